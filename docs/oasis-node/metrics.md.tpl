@@ -1,11 +1,14 @@
 # Metrics
 
 `oasis-node` can report a number of metrics to Prometheus server. By default,
-no metrics are collected and reported. There is one way to enable metrics
+no metrics are collected and reported. There are two ways to enable metrics
 reporting:
 
 * *Pull mode* listens on given address and waits for Prometheus to scrape the
   metrics.
+* *Push mode* periodically pushes the metrics to a Prometheus [Pushgateway][3].
+  This is useful for nodes that cannot be scraped directly, e.g., nodes behind
+  NAT.
 
 ## Configuring `oasis-node` in Pull Mode
 
@@ -28,6 +31,24 @@ Prometheus:
       - targets: ['localhost:3000']
 ```
 
+## Configuring `oasis-node` in Push Mode
+
+To run `oasis-node` in *push mode* set flag `--metrics.mode push` and provide
+the URL of the Pushgateway with `--metrics.address`. Metrics are pushed every
+`--metrics.interval` under the job name given by `--metrics.job_name` (default
+`oasis-node`) and grouped by the labels given by `--metrics.labels`. The
+`instance` label defaults to the host name of the node. For example
+
+```
+oasis-node \
+  --metrics.mode push \
+  --metrics.address http://pushgateway.example.com:9091 \
+  --metrics.interval 30s \
+  --metrics.labels instance=my-node
+```
+
+The last state of metrics is also pushed when the node is stopped.
+
 ## Metrics Reported by `oasis-node`
 
 `oasis-node` reports metrics starting with `oasis_`.
@@ -40,6 +61,26 @@ The following metrics are currently reported:
 
 <!-- markdownlint-enable line-length -->
 
+Additionally, internal metrics of the [BadgerDB][4] databases used by the node
+are reported as `oasis_badger_*`.
+
+## Tracing
+
+`oasis-node` can export [OpenTelemetry][5] traces to a collector via OTLP (over
+gRPC). To enable tracing, set `--tracing.otlp.endpoint` to the `host:port` of
+the collector, e.g.
+
+```
+oasis-node \
+  --tracing.otlp.endpoint otel-collector.example.com:4317 \
+  --tracing.sample_ratio 0.1
+```
+
+Pass `--tracing.otlp.insecure` in case the collector does not use TLS. The
+ratio of sampled traces is configured via `--tracing.sample_ratio` (default
+`1.0`). Currently, a span is recorded for each Runtime Host Protocol request
+made to a runtime.
+
 ## Consensus backends
 
 ### Metrics Reported by *Tendermint*
@@ -50,3 +91,6 @@ Tendermint metrics are also reported. Consult
 
 [1]: ../consensus/index.md#tendermint
 [2]: https://docs.tendermint.com/master/nodes/metrics.html
+[3]: https://github.com/prometheus/pushgateway
+[4]: https://github.com/dgraph-io/badger
+[5]: https://opentelemetry.io
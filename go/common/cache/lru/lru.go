@@ -5,6 +5,7 @@ import (
 	"container/list"
 	"errors"
 	"sync"
+	"time"
 )
 
 // ErrTooLarge is the error returned when a value is too large for the cache.
@@ -23,6 +24,35 @@ type Sizeable interface {
 // cache instance.
 type OnEvictFunc func(key, value interface{})
 
+// EvictReason is the reason for an entry being evicted from the cache.
+type EvictReason uint8
+
+const (
+	// EvictReasonCapacity means the entry was evicted to make room for another entry.
+	EvictReasonCapacity EvictReason = iota
+	// EvictReasonExpired means the entry was evicted because its TTL expired.
+	EvictReasonExpired
+)
+
+// String returns a string representation of the eviction reason.
+func (r EvictReason) String() string {
+	switch r {
+	case EvictReasonCapacity:
+		return "capacity"
+	case EvictReasonExpired:
+		return "expired"
+	default:
+		return "[unknown]"
+	}
+}
+
+// OnEvictReasonFunc is the function signature for the on-evict callback that
+// also receives the reason for the eviction.
+//
+// Note: The callback does not support calling routines on it's associated
+// cache instance.
+type OnEvictReasonFunc func(key, value interface{}, reason EvictReason)
+
 // Cache is an LRU cache instance.
 type Cache struct {
 	sync.Mutex
@@ -30,30 +60,50 @@ type Cache struct {
 	lru     *list.List
 	entries map[interface{}]*list.Element
 
-	onEvict OnEvictFunc
+	onEvict OnEvictReasonFunc
+	metrics *cacheMetrics
 
 	capacityInBytes bool
 	capacity        uint64
 	size            uint64
+
+	ttl time.Duration
+	now func() time.Time
 }
 
 type cacheEntry struct {
-	key   interface{}
-	value interface{}
+	key      interface{}
+	value    interface{}
+	expireAt time.Time
+}
+
+func (ent *cacheEntry) isExpired(now time.Time) bool {
+	return !ent.expireAt.IsZero() && !now.Before(ent.expireAt)
 }
 
 // Put inserts the key/value pair into the cache.  If the key is already present,
 // the value is updated, and the entry is moved to the most-recently-used position.
+//
+// The entry expires after the default TTL of the cache (if any).
 func (c *Cache) Put(key, value interface{}) error {
+	return c.PutWithTTL(key, value, c.ttl)
+}
+
+// PutWithTTL inserts the key/value pair into the cache like Put, but the entry
+// expires after the given TTL instead of the default one.  A zero TTL means
+// that the entry never expires.
+//
+// Expired entries are treated as not present and are evicted lazily (or by
+// calling PurgeExpired), so they are accounted for in the cache size until
+// then.
+func (c *Cache) PutWithTTL(key, value interface{}, ttl time.Duration) error {
 	c.Lock()
 	defer c.Unlock()
 
 	if elem, ok := c.entries[key]; ok {
 		// Key already present in cache.  Evict the existing entry, but do not
 		// call the callback.
-		c.lru.Remove(elem)
-		delete(c.entries, key)
-		c.size -= c.getValueSize(elem.Value.(*cacheEntry).value)
+		c.removeElement(elem)
 	}
 
 	// Sanity check that the value will fit.
@@ -71,12 +121,17 @@ func (c *Cache) Put(key, value interface{}) error {
 		c.evictEntries(valueSize)
 	}
 
-	elem := c.lru.PushFront(&cacheEntry{
+	ent := &cacheEntry{
 		key:   key,
 		value: value,
-	})
+	}
+	if ttl > 0 {
+		ent.expireAt = c.now().Add(ttl)
+	}
+	elem := c.lru.PushFront(ent)
 	c.entries[key] = elem
 	c.size += valueSize
+	c.metrics.resize(int64(valueSize))
 
 	return nil
 }
@@ -100,32 +155,55 @@ func (c *Cache) Remove(key interface{}) bool {
 
 	elem, ok := c.entries[key]
 	if ok {
-		c.lru.Remove(elem)
-		delete(c.entries, key)
-		c.size -= c.getValueSize(elem.Value.(*cacheEntry).value)
+		c.removeElement(elem)
 	}
 
 	return ok
 }
 
-// Keys returns the keys for every entry in the cache, from the least-recently-used
-// to the most-recently-used.
+// Keys returns the keys for every non-expired entry in the cache, from the
+// least-recently-used to the most-recently-used.
 func (c *Cache) Keys() []interface{} {
 	c.Lock()
 	defer c.Unlock()
 
+	now := c.now()
 	vec := make([]interface{}, 0, c.lru.Len())
 	for elem := c.lru.Back(); elem != nil; elem = elem.Prev() {
-		vec = append(vec, elem.Value.(*cacheEntry).key)
+		ent := elem.Value.(*cacheEntry)
+		if ent.isExpired(now) {
+			continue
+		}
+		vec = append(vec, ent.key)
 	}
 	return vec
 }
 
+// PurgeExpired evicts all expired entries from the cache and returns the number
+// of evicted entries.
+func (c *Cache) PurgeExpired() int {
+	c.Lock()
+	defer c.Unlock()
+
+	now := c.now()
+	var n int
+	for elem := c.lru.Back(); elem != nil; {
+		prev := elem.Prev()
+		if elem.Value.(*cacheEntry).isExpired(now) {
+			c.evictElement(elem, EvictReasonExpired)
+			n++
+		}
+		elem = prev
+	}
+	return n
+}
+
 // Clear empties the cache.
 func (c *Cache) Clear() {
 	c.Lock()
 	defer c.Unlock()
 
+	c.metrics.resize(-int64(c.size))
 	c.size = 0
 	c.lru = list.New()
 	c.entries = make(map[interface{}]*list.Element)
@@ -146,27 +224,51 @@ func (c *Cache) getEntry(key interface{}, isPeek bool) (interface{}, bool) {
 
 	elem, ok := c.entries[key]
 	if !ok {
+		c.metrics.miss()
+		return nil, false
+	}
+	ent := elem.Value.(*cacheEntry)
+	if ent.isExpired(c.now()) {
+		c.evictElement(elem, EvictReasonExpired)
+		c.metrics.miss()
 		return nil, false
 	}
 
 	if !isPeek {
 		c.lru.MoveToFront(elem)
 	}
-	return elem.Value.(*cacheEntry).value, true
+	c.metrics.hit()
+	return ent.value, true
 }
 
 func (c *Cache) evictEntries(targetCapacity uint64) {
 	for c.lru.Len() > 0 && c.capacity-c.size < targetCapacity {
 		elem := c.lru.Back()
-		c.lru.Remove(elem)
+		reason := EvictReasonCapacity
+		if elem.Value.(*cacheEntry).isExpired(c.now()) {
+			reason = EvictReasonExpired
+		}
+		c.evictElement(elem, reason)
+	}
+}
 
-		ent := elem.Value.(*cacheEntry)
-		delete(c.entries, ent.key)
-		c.size -= c.getValueSize(ent.value)
+func (c *Cache) removeElement(elem *list.Element) {
+	ent := elem.Value.(*cacheEntry)
+	c.lru.Remove(elem)
+	delete(c.entries, ent.key)
 
-		if c.onEvict != nil {
-			c.onEvict(ent.key, ent.value)
-		}
+	valueSize := c.getValueSize(ent.value)
+	c.size -= valueSize
+	c.metrics.resize(-int64(valueSize))
+}
+
+func (c *Cache) evictElement(elem *list.Element, reason EvictReason) {
+	c.removeElement(elem)
+	c.metrics.evict(reason)
+
+	if c.onEvict != nil {
+		ent := elem.Value.(*cacheEntry)
+		c.onEvict(ent.key, ent.value, reason)
 	}
 }
 
@@ -185,6 +287,7 @@ func New(options ...Option) (*Cache, error) {
 	c := &Cache{
 		lru:     list.New(),
 		entries: make(map[interface{}]*list.Element),
+		now:     time.Now,
 	}
 
 	for _, v := range options {
@@ -212,10 +315,48 @@ func Capacity(capacity uint64, inBytes bool) Option {
 	}
 }
 
-// OnEvict sets the on-evict callback.
+// OnEvict sets the on-evict callback, called for entries evicted due to
+// capacity constraints or expired TTLs.
 func OnEvict(fn OnEvictFunc) Option {
+	return func(c *Cache) error {
+		c.onEvict = func(key, value interface{}, _ EvictReason) {
+			fn(key, value)
+		}
+		return nil
+	}
+}
+
+// OnEvictReason sets the on-evict callback that also receives the reason for
+// the eviction.
+func OnEvictReason(fn OnEvictReasonFunc) Option {
 	return func(c *Cache) error {
 		c.onEvict = fn
 		return nil
 	}
 }
+
+// TTL sets the default time-to-live of the cache entries.  Entries expire once
+// their TTL passes and are then treated as not present.
+//
+// If no TTL is specified, the entries never expire.
+func TTL(ttl time.Duration) Option {
+	return func(c *Cache) error {
+		if ttl < 0 {
+			return errors.New("lru: TTL must not be negative")
+		}
+		c.ttl = ttl
+		return nil
+	}
+}
+
+// Metrics enables the Prometheus instrumentation of the cache, reported under
+// the given cache name.  Caches sharing the same name are reported together.
+func Metrics(name string) Option {
+	return func(c *Cache) error {
+		if name == "" {
+			return errors.New("lru: cache name must not be empty")
+		}
+		c.metrics = newCacheMetrics(name)
+		return nil
+	}
+}
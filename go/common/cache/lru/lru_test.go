@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"math/rand"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -149,6 +151,86 @@ func TestLRURemoval(t *testing.T) {
 	require.Equal(sizeBeforeRemoval-entries[0].Size(), sizeAfterRemoval, "Size - expected size to reduce by entry size after removal")
 }
 
+func TestLRUTTL(t *testing.T) {
+	require := require.New(t)
+
+	var evicted []interface{}
+	var reasons []EvictReason
+	cache, err := New(
+		Capacity(3, false),
+		TTL(time.Minute),
+		OnEvictReason(func(k, v interface{}, reason EvictReason) {
+			evicted = append(evicted, k)
+			reasons = append(reasons, reason)
+		}),
+	)
+	require.NoError(err, "New")
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	err = cache.Put("default", 1)
+	require.NoError(err, "Put")
+	err = cache.PutWithTTL("short", 2, time.Second)
+	require.NoError(err, "PutWithTTL")
+	err = cache.PutWithTTL("forever", 3, 0)
+	require.NoError(err, "PutWithTTL")
+
+	// Expire the short-lived entry.
+	now = now.Add(2 * time.Second)
+	require.Equal([]interface{}{"default", "forever"}, cache.Keys(), "Keys - expired entries skipped")
+	_, ok := cache.Get("short")
+	require.False(ok, "Get - expired entry")
+	require.Equal([]interface{}{"short"}, evicted, "OnEvict - expired entry")
+	require.Equal([]EvictReason{EvictReasonExpired}, reasons, "OnEvict - expired reason")
+	require.EqualValues(2, cache.Size(), "Size - expired entry evicted")
+
+	// Updating an entry refreshes its TTL.
+	now = now.Add(30 * time.Second)
+	err = cache.Put("default", 4)
+	require.NoError(err, "Put - update")
+	now = now.Add(45 * time.Second)
+	v, ok := cache.Peek("default")
+	require.True(ok, "Peek - refreshed entry")
+	require.Equal(4, v, "Peek - refreshed entry")
+
+	// Purge expired entries.
+	err = cache.Put("other", 5)
+	require.NoError(err, "Put")
+	now = now.Add(time.Hour)
+	require.Equal(2, cache.PurgeExpired(), "PurgeExpired")
+	require.Equal([]interface{}{"forever"}, cache.Keys(), "Keys - after purge")
+	require.Equal([]EvictReason{EvictReasonExpired, EvictReasonExpired, EvictReasonExpired}, reasons, "OnEvict - purged entries")
+
+	_, err = New(TTL(-time.Second))
+	require.Error(err, "New - negative TTL")
+}
+
+func TestLRUMetrics(t *testing.T) {
+	require := require.New(t)
+
+	cache, err := New(
+		Capacity(2, false),
+		Metrics("test"),
+	)
+	require.NoError(err, "New")
+
+	for i := 0; i < 3; i++ {
+		err = cache.Put(i, i)
+		require.NoError(err, "Put")
+	}
+	_, _ = cache.Get(2)
+	_, _ = cache.Get(0)
+
+	require.EqualValues(1, testutil.ToFloat64(cacheHits.WithLabelValues("test")), "hits")
+	require.EqualValues(1, testutil.ToFloat64(cacheMisses.WithLabelValues("test")), "misses")
+	require.EqualValues(1, testutil.ToFloat64(cacheEvictions.WithLabelValues("test", "capacity")), "evictions")
+	require.EqualValues(2, testutil.ToFloat64(cacheSize.WithLabelValues("test")), "size")
+
+	cache.Clear()
+	require.EqualValues(0, testutil.ToFloat64(cacheSize.WithLabelValues("test")), "size after clear")
+}
+
 type testEntry struct {
 	key   string
 	value []byte
@@ -0,0 +1,102 @@
+package lru
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	cacheHits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_lru_cache_hits",
+			Help: "Number of LRU cache lookups that found an entry.",
+		},
+		[]string{"cache"},
+	)
+	cacheMisses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_lru_cache_misses",
+			Help: "Number of LRU cache lookups that did not find an entry.",
+		},
+		[]string{"cache"},
+	)
+	cacheEvictions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_lru_cache_evictions",
+			Help: "Number of entries evicted from LRU caches.",
+		},
+		[]string{"cache", "reason"},
+	)
+	cacheSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_lru_cache_size",
+			Help: "Size of LRU caches (in entries or bytes, depending on the cache capacity).",
+		},
+		[]string{"cache"},
+	)
+
+	cacheCollectors = []prometheus.Collector{
+		cacheHits,
+		cacheMisses,
+		cacheEvictions,
+		cacheSize,
+	}
+
+	metricsOnce sync.Once
+)
+
+// cacheMetrics are the metrics of a single cache. A nil instance does not report anything.
+type cacheMetrics struct {
+	hits   prometheus.Counter
+	misses prometheus.Counter
+	size   prometheus.Gauge
+
+	evictions map[EvictReason]prometheus.Counter
+}
+
+func (m *cacheMetrics) hit() {
+	if m == nil {
+		return
+	}
+	m.hits.Inc()
+}
+
+func (m *cacheMetrics) miss() {
+	if m == nil {
+		return
+	}
+	m.misses.Inc()
+}
+
+func (m *cacheMetrics) evict(reason EvictReason) {
+	if m == nil {
+		return
+	}
+	m.evictions[reason].Inc()
+}
+
+func (m *cacheMetrics) resize(delta int64) {
+	if m == nil {
+		return
+	}
+	m.size.Add(float64(delta))
+}
+
+func newCacheMetrics(name string) *cacheMetrics {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(cacheCollectors...)
+	})
+
+	labels := prometheus.Labels{"cache": name}
+	m := &cacheMetrics{
+		hits:      cacheHits.With(labels),
+		misses:    cacheMisses.With(labels),
+		size:      cacheSize.With(labels),
+		evictions: make(map[EvictReason]prometheus.Counter),
+	}
+	for _, reason := range []EvictReason{EvictReasonCapacity, EvictReasonExpired} {
+		m.evictions[reason] = cacheEvictions.With(prometheus.Labels{"cache": name, "reason": reason.String()})
+	}
+	return m
+}
@@ -6,11 +6,88 @@
 package cbor
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"sync/atomic"
 
 	"github.com/fxamacker/cbor/v2"
 )
 
+// ErrTooLarge is the error returned when the input exceeds the configured maximum size.
+var ErrTooLarge = errors.New("common/cbor: input too large")
+
+// DecodeLimits are the limits enforced when decoding UNTRUSTED inputs received from network peers
+// (e.g., P2P messages and gRPC requests). They bound the amount of memory that decoding a malformed
+// or malicious input may allocate.
+//
+// The limits are node-local configuration and are thus never applied to inputs that must be
+// decoded identically by all nodes (e.g., consensus transactions), which always use the fixed
+// default limits.
+type DecodeLimits struct {
+	// MaxNestedLevels is the maximum nesting depth of arrays, maps and tags.
+	MaxNestedLevels int
+
+	// MaxArrayElements is the maximum number of elements in an array.
+	MaxArrayElements int
+
+	// MaxMapPairs is the maximum number of key-value pairs in a map.
+	MaxMapPairs int
+
+	// MaxSize is the maximum size (in bytes) of an input. Zero means no limit.
+	MaxSize int
+}
+
+// DefaultDecodeLimits are the default limits enforced when decoding UNTRUSTED inputs. They are
+// also the fixed limits used by Unmarshal.
+var DefaultDecodeLimits = DecodeLimits{
+	MaxNestedLevels:  32,
+	MaxArrayElements: 131072,
+	MaxMapPairs:      131072,
+	MaxSize:          0,
+}
+
+// decoder is a decoding mode together with the size limit of its inputs.
+type decoder struct {
+	mode   cbor.DecMode
+	limits DecodeLimits
+}
+
+func newDecoder(limits DecodeLimits) (*decoder, error) {
+	if limits.MaxSize < 0 {
+		return nil, fmt.Errorf("common/cbor: maximum input size must not be negative")
+	}
+
+	opts := decOptions
+	opts.MaxNestedLevels = limits.MaxNestedLevels
+	opts.MaxArrayElements = limits.MaxArrayElements
+	opts.MaxMapPairs = limits.MaxMapPairs
+	mode, err := opts.DecMode()
+	if err != nil {
+		return nil, fmt.Errorf("common/cbor: invalid decode limits: %w", err)
+	}
+
+	return &decoder{
+		mode:   mode,
+		limits: limits,
+	}, nil
+}
+
+// SetIngressDecodeLimits configures the limits enforced by UnmarshalIngress.
+func SetIngressDecodeLimits(limits DecodeLimits) error {
+	dec, err := newDecoder(limits)
+	if err != nil {
+		return err
+	}
+	ingressDecoder.Store(dec)
+	return nil
+}
+
+// GetIngressDecodeLimits returns the limits enforced by UnmarshalIngress.
+func GetIngressDecodeLimits() DecodeLimits {
+	return ingressDecoder.Load().(*decoder).limits
+}
+
 // RawMessage is a raw encoded CBOR value. It implements Marshaler and
 // Unmarshaler interfaces and can be used to delay CBOR decoding or
 // precompute a CBOR encoding.
@@ -53,6 +130,7 @@ var (
 
 	encMode        cbor.EncMode
 	decMode        cbor.DecMode
+	ingressDecoder atomic.Value
 	decModeTrusted cbor.DecMode
 )
 
@@ -61,9 +139,12 @@ func init() {
 	if encMode, err = encOptions.EncMode(); err != nil {
 		panic(err)
 	}
-	if decMode, err = decOptions.DecMode(); err != nil {
+	var dec *decoder
+	if dec, err = newDecoder(DefaultDecodeLimits); err != nil {
 		panic(err)
 	}
+	decMode = dec.mode
+	ingressDecoder.Store(dec)
 	if decModeTrusted, err = decOptionsTrusted.DecMode(); err != nil {
 		panic(err)
 	}
@@ -96,6 +177,22 @@ func Unmarshal(data []byte, dst interface{}) error {
 	return decMode.Unmarshal(data, dst)
 }
 
+// UnmarshalIngress deserializes a CBOR byte vector received from a network peer (e.g., a P2P
+// message or a gRPC request) into a given type, enforcing the configured ingress decode limits.
+//
+// This method MUST NOT be used for inputs that need to be decoded identically by all nodes.
+func UnmarshalIngress(data []byte, dst interface{}) error {
+	if data == nil {
+		return nil
+	}
+
+	dec := ingressDecoder.Load().(*decoder)
+	if dec.limits.MaxSize > 0 && len(data) > dec.limits.MaxSize {
+		return ErrTooLarge
+	}
+	return dec.mode.Unmarshal(data, dst)
+}
+
 // UnmarshalTrusted deserializes a CBOR byte vector into a given type.
 //
 // This method MUST ONLY BE USED FOR TRUSTED INPUTS as it relaxes some decoding restrictions.
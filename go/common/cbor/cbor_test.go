@@ -62,3 +62,61 @@ func TestDecodeUnknowField(t *testing.T) {
 	err = UnmarshalTrusted(raw, &dec)
 	require.NoError(err, "unknown fields from trusted sources should pass")
 }
+
+func TestDecodeLimits(t *testing.T) {
+	require := require.New(t)
+
+	defer func() {
+		err := SetIngressDecodeLimits(DefaultDecodeLimits)
+		require.NoError(err, "SetIngressDecodeLimits")
+	}()
+
+	require.EqualValues(DefaultDecodeLimits, GetIngressDecodeLimits())
+
+	err := SetIngressDecodeLimits(DecodeLimits{
+		MaxNestedLevels:  4,
+		MaxArrayElements: 16,
+		MaxMapPairs:      16,
+		MaxSize:          64,
+	})
+	require.NoError(err, "SetIngressDecodeLimits")
+
+	var v interface{}
+	err = UnmarshalIngress(Marshal([][][]int{{{1}}}), &v)
+	require.NoError(err, "nesting within limits should succeed")
+	err = UnmarshalIngress(Marshal([][][][][]int{{{{{1}}}}}), &v)
+	require.Error(err, "nesting over limits should fail")
+
+	err = UnmarshalIngress(Marshal(make([]int, 16)), &v)
+	require.NoError(err, "array within limits should succeed")
+	err = UnmarshalIngress(Marshal(make([]int, 17)), &v)
+	require.Error(err, "array over limits should fail")
+
+	m := make(map[int]int)
+	for i := 0; i < 17; i++ {
+		m[i] = i
+	}
+	err = UnmarshalIngress(Marshal(m), &v)
+	require.Error(err, "map over limits should fail")
+
+	err = UnmarshalIngress(Marshal(make([]byte, 64)), &v)
+	require.ErrorIs(err, ErrTooLarge, "input over size limit should fail")
+	err = UnmarshalTrusted(Marshal(make([]byte, 64)), &v)
+	require.NoError(err, "size limit should not apply to trusted inputs")
+
+	// Ingress limits must not affect decoding of inputs that must decode identically on all nodes.
+	err = Unmarshal(Marshal([][][][][]int{{{{{1}}}}}), &v)
+	require.NoError(err, "ingress nesting limit should not apply to Unmarshal")
+	err = Unmarshal(Marshal(make([]int, 17)), &v)
+	require.NoError(err, "ingress array limit should not apply to Unmarshal")
+	err = Unmarshal(Marshal(make([]byte, 64)), &v)
+	require.NoError(err, "ingress size limit should not apply to Unmarshal")
+	err = NewDecoder(bytes.NewReader(Marshal(make([]int, 17)))).Decode(&v)
+	require.NoError(err, "ingress array limit should not apply to NewDecoder")
+
+	err = SetIngressDecodeLimits(DecodeLimits{MaxSize: -1})
+	require.Error(err, "negative size limit should be rejected")
+	err = SetIngressDecodeLimits(DecodeLimits{MaxNestedLevels: 1})
+	require.Error(err, "too small nesting limit should be rejected")
+	require.EqualValues(64, GetIngressDecodeLimits().MaxSize, "invalid limits should not be applied")
+}
@@ -0,0 +1,55 @@
+//go:build gofuzz
+// +build gofuzz
+
+// Package fuzz provides fuzz targets for CBOR decoding of untrusted inputs.
+package fuzz
+
+import (
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/worker/common/p2p"
+)
+
+func init() {
+	// Use tight limits so the fuzzer explores them.
+	if err := cbor.SetIngressDecodeLimits(cbor.DecodeLimits{
+		MaxNestedLevels:  16,
+		MaxArrayElements: 1024,
+		MaxMapPairs:      1024,
+		MaxSize:          1 << 20,
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// FuzzValue fuzzes decoding of arbitrary CBOR values.
+func FuzzValue(data []byte) int {
+	var v interface{}
+	if err := cbor.UnmarshalIngress(data, &v); err != nil {
+		return 0
+	}
+	return 1
+}
+
+// FuzzTransaction fuzzes decoding of signed consensus transactions.
+func FuzzTransaction(data []byte) int {
+	var sigTx transaction.SignedTransaction
+	if err := cbor.Unmarshal(data, &sigTx); err != nil {
+		return 0
+	}
+
+	// Re-encoding a decoded transaction must not fail.
+	_ = cbor.Marshal(&sigTx)
+	return 1
+}
+
+// FuzzMessage fuzzes decoding of P2P messages.
+func FuzzMessage(data []byte) int {
+	var msg p2p.Message
+	if err := cbor.UnmarshalIngress(data, &msg); err != nil {
+		return 0
+	}
+
+	_ = cbor.Marshal(&msg)
+	return 1
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"sort"
 	"sync"
 	"time"
 
@@ -28,6 +29,10 @@ const (
 	// CfgDefaultCrashPointProbability is the default crash point probability.
 	CfgDefaultCrashPointProbability = "debug.crash.default"
 
+	// CfgCrashPoints configures crash points (including the number of times they are passed
+	// before being armed and the maximum number of crashes) from the config file.
+	CfgCrashPoints = "debug.crash.points"
+
 	// CrashDefaultExitCode is the exit code that will be used if program is exited
 	// due to a debug crash invocation.
 	CrashDefaultExitCode = 123
@@ -38,6 +43,52 @@ type RandomProvider interface {
 	Float64() float64
 }
 
+// PointConfig is the configuration of a crash point.
+type PointConfig struct {
+	// ID is the crash point identifier.
+	ID string `json:"id" mapstructure:"id"`
+
+	// Probability is the probability of crashing each time the armed crash point is reached.
+	// Zero disarms the crash point.
+	Probability float64 `json:"probability" mapstructure:"probability"`
+
+	// After is the number of times the crash point is passed before it is armed.
+	After uint64 `json:"after,omitempty" mapstructure:"after"`
+
+	// Count is the maximum number of crashes caused by the crash point (only relevant in case
+	// the crash method does not terminate the process). Zero means no limit.
+	Count uint64 `json:"count,omitempty" mapstructure:"count"`
+}
+
+// Validate validates the crash point configuration.
+func (pc *PointConfig) Validate() error {
+	if pc.Probability < 0 || pc.Probability > 1 {
+		return fmt.Errorf("crash: invalid probability of crash point '%s': %f", pc.ID, pc.Probability)
+	}
+	return nil
+}
+
+// PointStatus is the status of a crash point.
+type PointStatus struct {
+	PointConfig
+
+	// Hits is the number of times the crash point has been reached since it was configured.
+	Hits uint64 `json:"hits"`
+
+	// Crashes is the number of crashes caused by the crash point since it was configured.
+	Crashes uint64 `json:"crashes"`
+}
+
+// pointState is the arming state of a crash point.
+type pointState struct {
+	sync.Mutex
+
+	after   uint64
+	count   uint64
+	hits    uint64
+	crashes uint64
+}
+
 // Crasher is a crash controller.
 type Crasher struct {
 	CrashPointConfig *sync.Map
@@ -46,6 +97,9 @@ type Crasher struct {
 	Rand             RandomProvider
 	logger           *logging.Logger
 
+	// states are the arming states of crash points, keyed by the crash point identifier.
+	states sync.Map
+
 	// callerSkip is used by the global crasher instance to determine the caller
 	// of the package level `Here` function.
 	callerSkip int
@@ -169,20 +223,104 @@ func (c *Crasher) Here(crashPointID string) {
 	if !ok {
 		panic(fmt.Errorf("invalid crash point config: %d", cfg))
 	}
-	// Do nothing if the probability of crashing is set to a value 0 or less.
-	if crashPointProbability <= 0 {
+
+	st := c.state(crashPointID)
+	st.Lock()
+	st.hits++
+	// Do nothing if the crash point is not armed yet, the probability of crashing is set to a
+	// value 0 or less, or the crash point already caused the maximum number of crashes.
+	if st.hits <= st.after || crashPointProbability <= 0 || (st.count > 0 && st.crashes >= st.count) {
+		st.Unlock()
 		return
 	}
-	if c.Rand.Float64() <= crashPointProbability {
-		c.logger.Info("Crashing intentionally",
-			"crash_point_id", crashPointID,
-			"crash_point_probability", crashPointProbability,
-			"caller_information_is_correct", callerInformationIsCorrect,
-			"caller_filename", callerFilename,
-			"caller_line", callerLine,
+	if c.Rand.Float64() > crashPointProbability {
+		st.Unlock()
+		return
+	}
+	st.crashes++
+	st.Unlock()
+
+	c.logger.Info("Crashing intentionally",
+		"crash_point_id", crashPointID,
+		"crash_point_probability", crashPointProbability,
+		"caller_information_is_correct", callerInformationIsCorrect,
+		"caller_filename", callerFilename,
+		"caller_line", callerLine,
+	)
+	c.CrashMethod()
+}
+
+func (c *Crasher) state(crashPointID string) *pointState {
+	st, _ := c.states.LoadOrStore(crashPointID, &pointState{})
+	return st.(*pointState)
+}
+
+// SetPoints configures the given crash points of the global crasher.
+func SetPoints(points []PointConfig) error {
+	return crashGlobal.SetPoints(points)
+}
+
+// SetPoints configures the given crash points, resetting their hit and crash counters. Crash
+// points not included are left unchanged.
+func (c *Crasher) SetPoints(points []PointConfig) error {
+	for _, pc := range points {
+		if _, loaded := c.CrashPointConfig.Load(pc.ID); !loaded {
+			return fmt.Errorf("crash: attempted to configure unregistered crash point '%s'", pc.ID)
+		}
+		if err := pc.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, pc := range points {
+		st := c.state(pc.ID)
+		st.Lock()
+		c.CrashPointConfig.Store(pc.ID, pc.Probability)
+		st.after = pc.After
+		st.count = pc.Count
+		st.hits = 0
+		st.crashes = 0
+		st.Unlock()
+
+		c.logger.Info("configured crash point",
+			"crash_point_id", pc.ID,
+			"crash_point_probability", pc.Probability,
+			"after", pc.After,
+			"count", pc.Count,
 		)
-		c.CrashMethod()
 	}
+	return nil
+}
+
+// Points returns the status of all registered crash points of the global crasher.
+func Points() []PointStatus {
+	return crashGlobal.Points()
+}
+
+// Points returns the status of all registered crash points, sorted by their identifiers.
+func (c *Crasher) Points() []PointStatus {
+	var points []PointStatus
+	c.CrashPointConfig.Range(func(k, v interface{}) bool {
+		id := k.(string)
+		st := c.state(id)
+		st.Lock()
+		points = append(points, PointStatus{
+			PointConfig: PointConfig{
+				ID:          id,
+				Probability: v.(float64),
+				After:       st.after,
+				Count:       st.count,
+			},
+			Hits:    st.hits,
+			Crashes: st.crashes,
+		})
+		st.Unlock()
+		return true
+	})
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].ID < points[j].ID
+	})
+	return points
 }
 
 // Config configure the global crash point values.
@@ -226,18 +364,30 @@ func (c *Crasher) InitFlags() *flag.FlagSet {
 
 // LoadViperArgValues loads viper arg values into the crash point config of the
 // global crasher.
-func LoadViperArgValues() {
-	crashGlobal.LoadViperArgValues()
+func LoadViperArgValues() error {
+	return crashGlobal.LoadViperArgValues()
 }
 
 // LoadViperArgValues loads viper arg values into the crash point config.
-func (c *Crasher) LoadViperArgValues() {
+//
+// Crash points configured in the config file under CfgCrashPoints take
+// precedence over the per crash point flags.
+func (c *Crasher) LoadViperArgValues() error {
 	defaultProb := viper.GetFloat64(CfgDefaultCrashPointProbability)
-	for _, crashPointID := range ListRegisteredCrashPoints() {
+	for _, crashPointID := range c.ListRegisteredCrashPoints() {
 		argFlag := fmt.Sprintf("%s.%s", c.CLIPrefix, crashPointID)
 		c.CrashPointConfig.Store(crashPointID, defaultProb)
 		if viper.IsSet(argFlag) {
 			c.CrashPointConfig.Store(crashPointID, viper.GetFloat64(argFlag))
 		}
 	}
+
+	if !viper.IsSet(CfgCrashPoints) {
+		return nil
+	}
+	var points []PointConfig
+	if err := viper.UnmarshalKey(CfgCrashPoints, &points); err != nil {
+		return fmt.Errorf("crash: failed to parse crash point config: %w", err)
+	}
+	return c.SetPoints(points)
 }
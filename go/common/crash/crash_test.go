@@ -140,7 +140,8 @@ func TestGlobalCrashPointRegistrationViaFlags(t *testing.T) {
 	viper.Set(defaultCLIPrefix+"."+"test.global.point2", 0.5)
 
 	// Load values from flags.
-	LoadViperArgValues()
+	err := LoadViperArgValues()
+	assert.NoError(t, err, "should load values from flags")
 
 	p1, ok := crashGlobal.CrashPointConfig.Load("test.global.point")
 	assert.True(t, ok, "should set test point correctly")
@@ -150,3 +151,68 @@ func TestGlobalCrashPointRegistrationViaFlags(t *testing.T) {
 	assert.True(t, ok, "should set test point correctly")
 	assert.Equal(t, 0.5, p2, "should set configured point probability")
 }
+
+func TestCrashPointArming(t *testing.T) {
+	testForceEnable = true
+	defer func() {
+		testForceEnable = false
+	}()
+
+	var crashes int
+	crasher := New(CrasherOptions{
+		CrashMethod: func() { crashes++ },
+		Rand:        newDeterministicRandomProvider(0.4),
+	})
+	crasher.RegisterCrashPoints("point1", "point2")
+
+	err := crasher.SetPoints([]PointConfig{
+		{ID: "point1", Probability: 1.0, After: 2, Count: 3},
+	})
+	assert.NoError(t, err, "should configure registered crash point")
+
+	for i := 0; i < 10; i++ {
+		crasher.Here("point1")
+		crasher.Here("point2")
+	}
+	assert.Equal(t, 3, crashes, "should crash only after being armed and up to count times")
+
+	points := crasher.Points()
+	assert.Equal(t, []PointStatus{
+		{
+			PointConfig: PointConfig{ID: "point1", Probability: 1.0, After: 2, Count: 3},
+			Hits:        10,
+			Crashes:     3,
+		},
+		{
+			PointConfig: PointConfig{ID: "point2"},
+			Hits:        10,
+		},
+	}, points, "should report crash point status")
+
+	// Disarm the crash point.
+	err = crasher.SetPoints([]PointConfig{{ID: "point1"}})
+	assert.NoError(t, err, "should disarm crash point")
+	crasher.Here("point1")
+	assert.Equal(t, 3, crashes, "should not crash once disarmed")
+
+	err = crasher.SetPoints([]PointConfig{{ID: "point3", Probability: 1.0}})
+	assert.Error(t, err, "should fail to configure unregistered crash point")
+	err = crasher.SetPoints([]PointConfig{{ID: "point2", Probability: 2.0}})
+	assert.Error(t, err, "should fail to configure invalid probability")
+}
+
+func TestCrashPointConfigFile(t *testing.T) {
+	crasher := New(CrasherOptions{CLIPrefix: "test.crash"})
+	crasher.RegisterCrashPoints("test.file.point")
+
+	viper.Set(CfgCrashPoints, []map[string]interface{}{
+		{"id": "test.file.point", "probability": 0.5, "after": 7},
+	})
+	defer viper.Set(CfgCrashPoints, nil)
+
+	err := crasher.LoadViperArgValues()
+	assert.NoError(t, err, "should load crash points from config")
+	assert.Equal(t, []PointStatus{
+		{PointConfig: PointConfig{ID: "test.file.point", Probability: 0.5, After: 7}},
+	}, crasher.Points(), "should configure crash points from config")
+}
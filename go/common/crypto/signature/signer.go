@@ -284,6 +284,16 @@ type SignerFactory interface {
 	Load(role SignerRole) (Signer, error)
 }
 
+// HealthCheckedSignerFactory is a SignerFactory whose backend may become
+// unavailable (e.g., a remote signer) and that can report on its health.
+type HealthCheckedSignerFactory interface {
+	SignerFactory
+
+	// Health returns the error encountered during the last health check of
+	// the backend, if any.
+	Health() error
+}
+
 // Signer is an opaque interface for private keys that is capable of producing
 // signatures, in the spirit of `crypto.Signer`.
 type Signer interface {
@@ -3,6 +3,7 @@ package composite
 
 import (
 	"errors"
+	"fmt"
 	"io"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
@@ -14,6 +15,8 @@ const SignerName = "composite"
 // FactoryConfig is the composite factory configuration.
 type FactoryConfig map[signature.SignerRole]signature.SignerFactory
 
+var _ signature.HealthCheckedSignerFactory = (*SignerFactory)(nil)
+
 // SignerFactory is a SignerFactory that is a composite of multiple other
 // SignerFactory(s).
 type SignerFactory struct {
@@ -51,6 +54,36 @@ func (sf *SignerFactory) Load(role signature.SignerRole) (signature.Signer, erro
 	return factory.Load(role)
 }
 
+// Health returns the first error encountered during the last health check of
+// any of the inner SignerFactory(s) that support health checks, if any.
+func (sf *SignerFactory) Health() error {
+	for _, role := range signature.SignerRoles {
+		factory, ok := sf.inner[role].(signature.HealthCheckedSignerFactory)
+		if !ok {
+			continue
+		}
+		if err := factory.Health(); err != nil {
+			return fmt.Errorf("signature/signer/composite: %s signer: %w", role, err)
+		}
+	}
+	return nil
+}
+
+// Cleanup releases the resources held by the inner SignerFactory(s).
+func (sf *SignerFactory) Cleanup() {
+	seen := make(map[signature.SignerFactory]bool)
+	for _, role := range signature.SignerRoles {
+		factory, ok := sf.inner[role]
+		if !ok || seen[factory] {
+			continue
+		}
+		seen[factory] = true
+		if c, ok := factory.(interface{ Cleanup() }); ok {
+			c.Cleanup()
+		}
+	}
+}
+
 // NewFactory creates a new factory with the specified roles, with the
 // specified pre-created SignerFactory(s).
 func NewFactory(config interface{}, roles ...signature.SignerRole) (signature.SignerFactory, error) {
@@ -0,0 +1,203 @@
+package file
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/deoxysii"
+	"golang.org/x/crypto/argon2"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/pem"
+)
+
+const (
+	encryptedPrivateKeyPemType = "ENCRYPTED ED25519 PRIVATE KEY"
+
+	encryptedKeyVersion = 1
+
+	encryptedKeySaltSize   = 32
+	encryptedKeyKDFTime    = 3
+	encryptedKeyKDFMemory  = 64 * 1024
+	encryptedKeyKDFThreads = 4
+
+	// The KDF parameters are read from the (not yet authenticated) key header, so they are
+	// bounded to prevent a malicious key file from exhausting resources (or crashing argon2).
+	encryptedKeyMinSaltSize   = 16
+	encryptedKeyMaxKDFTime    = 16
+	encryptedKeyMaxKDFMemory  = 1024 * 1024
+	encryptedKeyMaxKDFThreads = 16
+)
+
+var (
+	// ErrPassphraseRequired is the error returned when loading an encrypted
+	// private key without a passphrase.
+	ErrPassphraseRequired = errors.New("signature/signer/file: private key is encrypted, passphrase required")
+
+	// ErrNotEncrypted is the error returned when loading an unencrypted
+	// private key while a passphrase is configured.
+	ErrNotEncrypted = errors.New("signature/signer/file: private key is not encrypted")
+
+	// ErrWrongPassphrase is the error returned when an encrypted private key
+	// cannot be decrypted with the given passphrase.
+	ErrWrongPassphrase = errors.New("signature/signer/file: failed to decrypt private key (wrong passphrase?)")
+)
+
+// encryptedKeyKDF are the Argon2id parameters used to derive the key
+// encryption key from the passphrase.
+type encryptedKeyKDF struct {
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+// encryptedKeyHeader is the unencrypted (but authenticated) header of an
+// encrypted private key.
+type encryptedKeyHeader struct {
+	cbor.Versioned
+
+	// KDF are the key derivation parameters.
+	KDF encryptedKeyKDF `json:"kdf"`
+	// Nonce is the Deoxys-II nonce.
+	Nonce []byte `json:"nonce"`
+}
+
+// encryptedKey is an encrypted private key.
+type encryptedKey struct {
+	// Header is the encrypted key header.
+	Header encryptedKeyHeader `json:"header"`
+	// Ciphertext is the encrypted private key.
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// validate checks that the key derivation parameters are within sane bounds.
+func (kdf *encryptedKeyKDF) validate() error {
+	if len(kdf.Salt) < encryptedKeyMinSaltSize {
+		return fmt.Errorf("invalid salt size: %d", len(kdf.Salt))
+	}
+	if kdf.Time < 1 || kdf.Time > encryptedKeyMaxKDFTime {
+		return fmt.Errorf("invalid time parameter: %d", kdf.Time)
+	}
+	if kdf.Threads < 1 || kdf.Threads > encryptedKeyMaxKDFThreads {
+		return fmt.Errorf("invalid threads parameter: %d", kdf.Threads)
+	}
+	// Argon2 requires at least 8 KiB of memory per thread.
+	if kdf.Memory < 8*uint32(kdf.Threads) || kdf.Memory > encryptedKeyMaxKDFMemory {
+		return fmt.Errorf("invalid memory parameter: %d", kdf.Memory)
+	}
+	return nil
+}
+
+func (h *encryptedKeyHeader) deriveKey(passphrase []byte) ([]byte, error) {
+	if err := h.KDF.validate(); err != nil {
+		return nil, fmt.Errorf("signature/signer/file: malformed encrypted private key: %w", err)
+	}
+	return argon2.IDKey(passphrase, h.KDF.Salt, h.KDF.Time, h.KDF.Memory, h.KDF.Threads, deoxysii.KeySize), nil
+}
+
+// IsEncryptedPEM returns true iff the given PEM data contains an encrypted
+// private key.
+func IsEncryptedPEM(data []byte) bool {
+	_, err := pem.Unmarshal(encryptedPrivateKeyPemType, data)
+	return err == nil
+}
+
+// EncryptPEM encrypts the given PEM encoded private key, as stored by the
+// factory, with a key derived from the passphrase.
+func EncryptPEM(data, passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("signature/signer/file: empty passphrase")
+	}
+
+	var signer Signer
+	if err := signer.unmarshalPEM(data); err != nil {
+		return nil, err
+	}
+	defer signer.Reset()
+
+	return signer.marshalEncryptedPEM(passphrase)
+}
+
+// DecryptPEM decrypts the given encrypted PEM encoded private key with a key
+// derived from the passphrase and returns the unencrypted PEM encoded key.
+func DecryptPEM(data, passphrase []byte) ([]byte, error) {
+	var signer Signer
+	if err := signer.unmarshalEncryptedPEM(data, passphrase); err != nil {
+		return nil, err
+	}
+	defer signer.Reset()
+
+	return signer.marshalPEM()
+}
+
+func (s *Signer) marshalEncryptedPEM(passphrase []byte) ([]byte, error) {
+	hdr := encryptedKeyHeader{
+		Versioned: cbor.NewVersioned(encryptedKeyVersion),
+		KDF: encryptedKeyKDF{
+			Salt:    make([]byte, encryptedKeySaltSize),
+			Time:    encryptedKeyKDFTime,
+			Memory:  encryptedKeyKDFMemory,
+			Threads: encryptedKeyKDFThreads,
+		},
+		Nonce: make([]byte, deoxysii.NonceSize),
+	}
+	if _, err := rand.Read(hdr.KDF.Salt); err != nil {
+		return nil, fmt.Errorf("signature/signer/file: failed to generate salt: %w", err)
+	}
+	if _, err := rand.Read(hdr.Nonce); err != nil {
+		return nil, fmt.Errorf("signature/signer/file: failed to generate nonce: %w", err)
+	}
+
+	key, err := hdr.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := deoxysii.New(key)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := aead.Seal(nil, hdr.Nonce, s.privateKey[:], cbor.Marshal(hdr))
+
+	return pem.Marshal(encryptedPrivateKeyPemType, cbor.Marshal(&encryptedKey{
+		Header:     hdr,
+		Ciphertext: ciphertext,
+	}))
+}
+
+func (s *Signer) unmarshalEncryptedPEM(data, passphrase []byte) error {
+	raw, err := pem.Unmarshal(encryptedPrivateKeyPemType, data)
+	if err != nil {
+		return err
+	}
+	if len(passphrase) == 0 {
+		return ErrPassphraseRequired
+	}
+
+	var ek encryptedKey
+	if err = cbor.Unmarshal(raw, &ek); err != nil {
+		return fmt.Errorf("signature/signer/file: malformed encrypted private key: %w", err)
+	}
+	if ek.Header.V != encryptedKeyVersion {
+		return fmt.Errorf("signature/signer/file: unsupported encrypted private key version: %d", ek.Header.V)
+	}
+	if len(ek.Header.Nonce) != deoxysii.NonceSize {
+		return fmt.Errorf("signature/signer/file: malformed encrypted private key: invalid nonce size")
+	}
+
+	key, err := ek.Header.deriveKey(passphrase)
+	if err != nil {
+		return err
+	}
+	aead, err := deoxysii.New(key)
+	if err != nil {
+		return err
+	}
+	plaintext, err := aead.Open(nil, ek.Header.Nonce, ek.Ciphertext, cbor.Marshal(ek.Header))
+	if err != nil {
+		return ErrWrongPassphrase
+	}
+
+	return s.unmarshalPrivateKey(plaintext)
+}
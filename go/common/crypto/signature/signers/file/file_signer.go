@@ -51,24 +51,41 @@ var (
 	}
 )
 
+// FactoryConfig is the file backed signer factory configuration.
+type FactoryConfig struct {
+	// Dir is the directory containing the private keys.
+	Dir string
+
+	// Passphrase is the passphrase used to encrypt the private keys at rest.
+	// If empty, the private keys are stored unencrypted.
+	Passphrase []byte
+}
+
 // NewFactory creates a new factory with the specified roles, with the
-// specified dataDir.
+// specified dataDir (or *FactoryConfig).
 func NewFactory(config interface{}, roles ...signature.SignerRole) (signature.SignerFactory, error) {
-	dataDir, ok := config.(string)
-	if !ok {
+	var cfg FactoryConfig
+	switch c := config.(type) {
+	case string:
+		cfg.Dir = c
+	case *FactoryConfig:
+		cfg = *c
+	default:
 		return nil, errors.New("signature/signer/file: invalid file signer configuration provided")
 	}
 
 	return &Factory{
-		roles:   append([]signature.SignerRole{}, roles...),
-		dataDir: dataDir,
+		roles:      append([]signature.SignerRole{}, roles...),
+		dataDir:    cfg.Dir,
+		passphrase: append([]byte{}, cfg.Passphrase...),
 	}, nil
 }
 
 // Factory is a PEM file backed SignerFactory.
 type Factory struct {
-	roles   []signature.SignerRole
-	dataDir string
+	roles      []signature.SignerRole
+	dataDir    string
+	passphrase []byte
 }
 
 // EnsureRole ensures that the SignerFactory is configured for the given
@@ -111,7 +128,13 @@ func (fac *Factory) Generate(role signature.SignerRole, rng io.Reader) (signatur
 		privateKey: privateKey,
 		role:       role,
 	}
-	buf, err := signer.marshalPEM()
+	var buf []byte
+	switch len(fac.passphrase) {
+	case 0:
+		buf, err = signer.marshalPEM()
+	default:
+		buf, err = signer.marshalEncryptedPEM(fac.passphrase)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -163,7 +186,45 @@ func (fac *Factory) doLoad(fn string, role signature.SignerRole) (signature.Sign
 	}
 
 	var signer Signer
-	if err = signer.unmarshalPEM(buf); err != nil {
+	switch {
+	case IsEncryptedPEM(buf):
+		err = signer.unmarshalEncryptedPEM(buf, fac.passphrase)
+	case len(fac.passphrase) > 0:
+		// Refuse to silently use unencrypted keys when encryption is configured.
+		err = fmt.Errorf("%w: %s", ErrNotEncrypted, fn)
+	default:
+		err = signer.unmarshalPEM(buf)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer.role = role
+
+	return &signer, nil
+}
+
+// NewSignerFromPEM creates a new Signer for the given role from a PEM encoded
+// private key, as stored by the factory.
+func NewSignerFromPEM(role signature.SignerRole, data []byte) (*Signer, error) {
+	var signer Signer
+	if err := signer.unmarshalPEM(data); err != nil {
+		return nil, err
+	}
+	signer.role = role
+
+	return &signer, nil
+}
+
+// NewSignerFromPEMWithPassphrase creates a new Signer for the given role from
+// a PEM encoded private key, as stored by the factory, decrypting it with the
+// given passphrase in case it is encrypted.
+func NewSignerFromPEMWithPassphrase(role signature.SignerRole, data, passphrase []byte) (*Signer, error) {
+	if !IsEncryptedPEM(data) {
+		return NewSignerFromPEM(role, data)
+	}
+
+	var signer Signer
+	if err := signer.unmarshalEncryptedPEM(data, passphrase); err != nil {
 		return nil, err
 	}
 	signer.role = role
@@ -224,6 +285,11 @@ func (s *Signer) unmarshalPEM(data []byte) error {
 	if err != nil {
 		return err
 	}
+
+	return s.unmarshalPrivateKey(data)
+}
+
+func (s *Signer) unmarshalPrivateKey(data []byte) error {
 	if len(data) != ed25519.PrivateKeySize {
 		return signature.ErrMalformedPrivateKey
 	}
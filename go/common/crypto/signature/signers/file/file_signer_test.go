@@ -3,12 +3,16 @@ package file
 import (
 	"crypto/rand"
 	"io/ioutil"
+	"math"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/pem"
 )
 
 func TestFileSigner(t *testing.T) {
@@ -51,3 +55,86 @@ func TestFileSigner(t *testing.T) {
 	require.NoError(err, "LoadPEM(fn, nil), exists")
 	require.Equal(signer, signer2, "Generated = Loaded")
 }
+
+func TestFileSignerEncrypted(t *testing.T) {
+	require := require.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "oasis-signature-test")
+	require.NoError(err, "TempDir()")
+	defer os.RemoveAll(tmpDir)
+
+	passphrase := []byte("correct horse battery staple")
+
+	rolePEMFiles[signature.SignerUnknown] = "unit_test.pem"
+	factory, err := NewFactory(&FactoryConfig{Dir: tmpDir, Passphrase: passphrase}, signature.SignerUnknown)
+	require.NoError(err, "NewFactory()")
+
+	// Generate an encrypted key.
+	signer, err := factory.Generate(signature.SignerUnknown, rand.Reader)
+	require.NoError(err, "Generate(SignerUnknown, rand.Reader)")
+
+	fn := filepath.Join(tmpDir, "unit_test.pem")
+	raw, err := ioutil.ReadFile(fn)
+	require.NoError(err, "ReadFile")
+	require.True(IsEncryptedPEM(raw), "generated key should be encrypted")
+
+	// Load with the correct passphrase.
+	signer2, err := factory.Load(signature.SignerUnknown)
+	require.NoError(err, "Load(), encrypted")
+	require.Equal(signer, signer2, "Generated = Loaded")
+
+	// Load without or with a wrong passphrase.
+	plainFactory, err := NewFactory(tmpDir, signature.SignerUnknown)
+	require.NoError(err, "NewFactory()")
+	_, err = plainFactory.Load(signature.SignerUnknown)
+	require.ErrorIs(err, ErrPassphraseRequired, "Load(), no passphrase")
+
+	wrongFactory, err := NewFactory(&FactoryConfig{Dir: tmpDir, Passphrase: []byte("wrong")}, signature.SignerUnknown)
+	require.NoError(err, "NewFactory()")
+	_, err = wrongFactory.Load(signature.SignerUnknown)
+	require.ErrorIs(err, ErrWrongPassphrase, "Load(), wrong passphrase")
+
+	// Decrypt and make sure unencrypted keys are rejected when a passphrase is configured.
+	plain, err := DecryptPEM(raw, passphrase)
+	require.NoError(err, "DecryptPEM")
+	require.False(IsEncryptedPEM(plain), "decrypted key should not be encrypted")
+	require.NoError(ioutil.WriteFile(fn, plain, filePerm), "WriteFile")
+
+	_, err = factory.Load(signature.SignerUnknown)
+	require.ErrorIs(err, ErrNotEncrypted, "Load(), unencrypted")
+	signer3, err := plainFactory.Load(signature.SignerUnknown)
+	require.NoError(err, "Load(), unencrypted")
+	require.Equal(signer.Public(), signer3.Public(), "Decrypted = Generated")
+
+	// Encrypt (migrate) the unencrypted key.
+	encrypted, err := EncryptPEM(plain, passphrase)
+	require.NoError(err, "EncryptPEM")
+	signer4, err := NewSignerFromPEMWithPassphrase(signature.SignerUnknown, encrypted, passphrase)
+	require.NoError(err, "NewSignerFromPEMWithPassphrase")
+	require.Equal(signer.Public(), signer4.Public(), "Encrypted = Generated")
+	_, err = NewSignerFromPEM(signature.SignerUnknown, encrypted)
+	require.Error(err, "NewSignerFromPEM should reject encrypted keys")
+
+	// Keys with out of bounds key derivation parameters should be rejected.
+	for _, tc := range []struct {
+		name   string
+		modify func(kdf *encryptedKeyKDF)
+	}{
+		{"ZeroThreads", func(kdf *encryptedKeyKDF) { kdf.Threads = 0 }},
+		{"HugeMemory", func(kdf *encryptedKeyKDF) { kdf.Memory = math.MaxUint32 }},
+		{"ZeroTime", func(kdf *encryptedKeyKDF) { kdf.Time = 0 }},
+		{"ShortSalt", func(kdf *encryptedKeyKDF) { kdf.Salt = kdf.Salt[:1] }},
+	} {
+		rawKey, perr := pem.Unmarshal(encryptedPrivateKeyPemType, encrypted)
+		require.NoError(perr, "pem.Unmarshal")
+		var ek encryptedKey
+		require.NoError(cbor.Unmarshal(rawKey, &ek), "cbor.Unmarshal")
+		tc.modify(&ek.Header.KDF)
+		malformed, perr := pem.Marshal(encryptedPrivateKeyPemType, cbor.Marshal(&ek))
+		require.NoError(perr, "pem.Marshal")
+
+		_, err = NewSignerFromPEMWithPassphrase(signature.SignerUnknown, malformed, passphrase)
+		require.Error(err, "NewSignerFromPEMWithPassphrase should reject invalid KDF parameters (%s)", tc.name)
+		require.NotErrorIs(err, ErrWrongPassphrase, "invalid KDF parameters should be rejected before decryption (%s)", tc.name)
+	}
+}
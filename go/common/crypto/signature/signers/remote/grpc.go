@@ -7,15 +7,34 @@ import (
 	"crypto/x509"
 	"fmt"
 	"io"
+	"strings"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
 )
 
-// SignerName is the name used to identify the remote signer.
-const SignerName = "remote"
+const (
+	// SignerName is the name used to identify the remote signer.
+	SignerName = "remote"
+
+	// UnixSocketPrefix is the address prefix used to connect to a remote signer
+	// listening on a local unix socket.
+	UnixSocketPrefix = "unix:"
+
+	healthCheckTimeout = 5 * time.Second
+)
+
+var (
+	_ signature.VRFSigner                  = (*remoteSigner)(nil)
+	_ signature.HealthCheckedSignerFactory = (*remoteFactory)(nil)
+
+	logger = logging.GetLogger("signature/signer/remote")
+)
 
 var (
 	serviceName = cmnGrpc.NewServiceName("RemoteSigner")
@@ -197,10 +216,91 @@ func RegisterService(server *grpc.Server, signerFactory signature.SignerFactory)
 }
 
 type remoteFactory struct {
+	sync.RWMutex
+
 	conn   *grpc.ClientConn
 	reqCtx context.Context
 
-	signers map[signature.SignerRole]*remoteSigner
+	// cancelFn and healthDoneCh are only set for factories created via NewFactory which own
+	// the connection and the health check worker.
+	cancelFn     context.CancelFunc
+	healthDoneCh chan struct{}
+
+	signers   map[signature.SignerRole]*remoteSigner
+	healthErr error
+}
+
+// Health returns the error encountered during the last health check of the remote signer, if any.
+func (rf *remoteFactory) Health() error {
+	rf.RLock()
+	defer rf.RUnlock()
+
+	return rf.healthErr
+}
+
+// checkHealth checks that the remote signer is reachable and still provides the same keys.
+func (rf *remoteFactory) checkHealth() error {
+	ctx, cancel := context.WithTimeout(rf.reqCtx, healthCheckTimeout)
+	defer cancel()
+
+	var rsp []PublicKey
+	if err := rf.conn.Invoke(ctx, methodPublicKeys.FullName(), nil, &rsp); err != nil {
+		return fmt.Errorf("signature/signer/remote: failed to query public keys: %w", err)
+	}
+
+	keys := make(map[signature.SignerRole]signature.PublicKey)
+	for _, v := range rsp {
+		keys[v.Role] = v.PublicKey
+	}
+	for role, signer := range rf.signers {
+		if pk, ok := keys[role]; !ok || !pk.Equal(signer.publicKey) {
+			return fmt.Errorf("signature/signer/remote: public key changed for role %s", role)
+		}
+	}
+	return nil
+}
+
+func (rf *remoteFactory) healthWorker(interval time.Duration) {
+	defer close(rf.healthDoneCh)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-rf.reqCtx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := rf.checkHealth()
+		switch err {
+		case nil:
+			signerUp.Set(1)
+		default:
+			logger.Warn("remote signer health check failed",
+				"err", err,
+			)
+			signerUp.Set(0)
+		}
+
+		rf.Lock()
+		rf.healthErr = err
+		rf.Unlock()
+	}
+}
+
+// Cleanup stops the health check worker and closes the connection to the remote signer in case
+// they are owned by the factory.
+func (rf *remoteFactory) Cleanup() {
+	if rf.cancelFn == nil {
+		return
+	}
+	rf.cancelFn()
+	if rf.healthDoneCh != nil {
+		<-rf.healthDoneCh
+	}
+	_ = rf.conn.Close()
 }
 
 func (rf *remoteFactory) EnsureRole(role signature.SignerRole) error {
@@ -247,7 +347,31 @@ func (rs *remoteSigner) ContextSign(context signature.Context, message []byte) (
 	}
 
 	var rsp []byte
-	if err := rs.factory.conn.Invoke(rs.factory.reqCtx, methodSign.FullName(), req, &rsp); err != nil {
+	start := time.Now()
+	err = rs.factory.conn.Invoke(rs.factory.reqCtx, methodSign.FullName(), req, &rsp)
+	observeRequest(rs.role, "sign", start, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+func (rs *remoteSigner) Prove(alphaString []byte) ([]byte, error) {
+	if rs.role != signature.SignerVRF {
+		return nil, signature.ErrInvalidRole
+	}
+
+	req := &ProveRequest{
+		Role:  rs.role,
+		Alpha: alphaString,
+	}
+
+	var rsp []byte
+	start := time.Now()
+	err := rs.factory.conn.Invoke(rs.factory.reqCtx, methodProve.FullName(), req, &rsp)
+	observeRequest(rs.role, "prove", start, err)
+	if err != nil {
 		return nil, err
 	}
 
@@ -264,24 +388,25 @@ func (rs *remoteSigner) Reset() {
 
 // FactoryConfig is the remote factory configuration.
 type FactoryConfig struct {
-	// Address is the remote factory gRPC address.
+	// Address is the remote factory gRPC address. Addresses prefixed with
+	// UnixSocketPrefix refer to a local unix socket.
 	Address string
-	// ServerCertificate is the server certificate.
+	// ServerCertificate is the server certificate. Not required for unix sockets.
 	ServerCertificate *tls.Certificate
-	// ClientCertificate is the client certificate.
+	// ClientCertificate is the client certificate. Not required for unix sockets.
 	ClientCertificate *tls.Certificate
+	// HealthCheckInterval is the interval at which the connection to the remote
+	// signer is checked. Zero disables health checks.
+	HealthCheckInterval time.Duration
 }
 
-// NewFactory creates a new factory with the specified roles.
-func NewFactory(config interface{}, roles ...signature.SignerRole) (signature.SignerFactory, error) {
-	cfg, ok := config.(*FactoryConfig)
-	if !ok {
-		return nil, fmt.Errorf("signature/signer/remote: invalid remote signer configuration provided")
-	}
-
+func dialTLS(cfg *FactoryConfig) (*grpc.ClientConn, error) {
 	if cfg.ServerCertificate == nil {
 		return nil, fmt.Errorf("signature/signer/remote: server certificate is required")
 	}
+	if cfg.ClientCertificate == nil {
+		return nil, fmt.Errorf("signature/signer/remote: client certificate is required")
+	}
 
 	serverCert, err := x509.ParseCertificate(cfg.ServerCertificate.Certificate[0])
 	if err != nil {
@@ -299,17 +424,56 @@ func NewFactory(config interface{}, roles ...signature.SignerRole) (signature.Si
 		return nil, err
 	}
 
-	conn, err := cmnGrpc.Dial(cfg.Address, grpc.WithTransportCredentials(creds))
+	return cmnGrpc.Dial(cfg.Address, grpc.WithTransportCredentials(creds))
+}
+
+// NewFactory creates a new factory with the specified roles.
+func NewFactory(config interface{}, roles ...signature.SignerRole) (signature.SignerFactory, error) {
+	cfg, ok := config.(*FactoryConfig)
+	if !ok {
+		return nil, fmt.Errorf("signature/signer/remote: invalid remote signer configuration provided")
+	}
+	if cfg.HealthCheckInterval < 0 {
+		return nil, fmt.Errorf("signature/signer/remote: health check interval must not be negative")
+	}
+
+	var (
+		conn *grpc.ClientConn
+		err  error
+	)
+	if strings.HasPrefix(cfg.Address, UnixSocketPrefix) {
+		// Access to local unix sockets is controlled by file system permissions.
+		conn, err = cmnGrpc.Dial(cfg.Address, grpc.WithInsecure())
+	} else {
+		conn, err = dialTLS(cfg)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("signature/signer/remote: failed to dial server: %w", err)
 	}
 
-	return NewRemoteFactory(context.Background(), conn)
+	ctx, cancel := context.WithCancel(context.Background())
+	sf, err := NewRemoteFactory(ctx, conn)
+	if err != nil {
+		cancel()
+		_ = conn.Close()
+		return nil, err
+	}
+	rf := sf.(*remoteFactory)
+	rf.cancelFn = cancel
+	if cfg.HealthCheckInterval > 0 {
+		signerUp.Set(1)
+		rf.healthDoneCh = make(chan struct{})
+		go rf.healthWorker(cfg.HealthCheckInterval)
+	}
+
+	return rf, nil
 }
 
 // NewRemoteFactory creates a new gRPC remote signer client service given an
 // existing grpc connection.
 func NewRemoteFactory(ctx context.Context, conn *grpc.ClientConn) (signature.SignerFactory, error) {
+	initMetrics()
+
 	// Enumerate the keys available, and cache them.
 	var rsp []PublicKey
 	if err := conn.Invoke(ctx, methodPublicKeys.FullName(), nil, &rsp); err != nil {
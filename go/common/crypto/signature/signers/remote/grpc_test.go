@@ -0,0 +1,116 @@
+package remote
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	compositeSigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/composite"
+	fileSigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/file"
+	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+)
+
+func TestRemoteSignerUnixSocket(t *testing.T) {
+	require := require.New(t)
+
+	signature.UnsafeAllowUnregisteredContexts()
+
+	dir, err := ioutil.TempDir("", "oasis-remote-signer-test_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	roles := []signature.SignerRole{signature.SignerEntity, signature.SignerVRF}
+	sf, err := fileSigner.NewFactory(dir, roles...)
+	require.NoError(err, "fileSigner.NewFactory")
+	for _, role := range roles {
+		_, err = sf.Generate(role, rand.Reader)
+		require.NoError(err, "Generate")
+	}
+
+	socketPath := filepath.Join(dir, "signer.sock")
+	svr, err := cmnGrpc.NewServer(&cmnGrpc.ServerConfig{
+		Name: "remote-signer-test",
+		Path: socketPath,
+	})
+	require.NoError(err, "NewServer")
+	RegisterService(svr.Server(), sf)
+	require.NoError(svr.Start(), "Start")
+	defer svr.Stop()
+
+	_, err = NewFactory(&FactoryConfig{
+		Address:             UnixSocketPrefix + socketPath,
+		HealthCheckInterval: -time.Second,
+	})
+	require.Error(err, "NewFactory should fail with a negative health check interval")
+
+	rf, err := NewFactory(&FactoryConfig{
+		Address:             UnixSocketPrefix + socketPath,
+		HealthCheckInterval: time.Hour,
+	})
+	require.NoError(err, "NewFactory")
+
+	_, err = rf.Load(signature.SignerNode)
+	require.Error(err, "Load should fail for roles without keys")
+
+	// Signing.
+	entitySigner, err := rf.Load(signature.SignerEntity)
+	require.NoError(err, "Load")
+	localSigner, err := sf.Load(signature.SignerEntity)
+	require.NoError(err, "Load")
+	require.Equal(localSigner.Public(), entitySigner.Public(), "remote public key should match")
+
+	ctx := signature.NewContext("oasis-core/remote-signer: test context")
+	message := []byte("test message")
+	sig, err := entitySigner.ContextSign(ctx, message)
+	require.NoError(err, "ContextSign")
+	require.True(entitySigner.Public().Verify(ctx, message, sig), "signature should verify")
+
+	// VRF proofs.
+	vrfSigner, err := rf.Load(signature.SignerVRF)
+	require.NoError(err, "Load")
+	proof, err := signature.Prove(vrfSigner, message)
+	require.NoError(err, "Prove")
+	ok, _ := proof.Verify(message)
+	require.True(ok, "VRF proof should verify")
+	_, err = entitySigner.(signature.VRFSigner).Prove(message)
+	require.Error(err, "Prove should fail for non-VRF roles")
+
+	// Health checks.
+	remote := rf.(*remoteFactory)
+	require.NoError(remote.checkHealth(), "checkHealth")
+	require.NoError(remote.Health(), "Health")
+	remote.signers[signature.SignerNode] = &remoteSigner{role: signature.SignerNode}
+	require.Error(remote.checkHealth(), "checkHealth should fail when keys change")
+	delete(remote.signers, signature.SignerNode)
+
+	// The health of the remote signer should propagate through composite factories.
+	csf, err := compositeSigner.NewFactory(compositeSigner.FactoryConfig{
+		signature.SignerEntity: rf,
+		signature.SignerVRF:    rf,
+	}, roles...)
+	require.NoError(err, "compositeSigner.NewFactory")
+	hc, ok := csf.(signature.HealthCheckedSignerFactory)
+	require.True(ok, "composite factory should support health checks")
+	require.NoError(hc.Health(), "Health")
+	remote.Lock()
+	remote.healthErr = fmt.Errorf("unhealthy")
+	remote.Unlock()
+	require.Error(hc.Health(), "Health should fail when the remote signer is unhealthy")
+
+	// Cleanup should stop the health check worker and close the connection.
+	csf.(*compositeSigner.SignerFactory).Cleanup()
+	select {
+	case <-remote.healthDoneCh:
+	default:
+		t.Fatalf("health check worker should have stopped")
+	}
+	_, err = entitySigner.ContextSign(ctx, message)
+	require.Error(err, "ContextSign should fail after Cleanup")
+}
@@ -0,0 +1,56 @@
+package remote
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+var (
+	signLatency = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name: "oasis_signer_remote_latency",
+			Help: "Remote signer request latency (seconds).",
+		},
+		[]string{"role", "method"},
+	)
+	signFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_signer_remote_failures",
+			Help: "Number of failed remote signer requests.",
+		},
+		[]string{"role", "method"},
+	)
+	signerUp = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "oasis_signer_remote_up",
+			Help: "Whether the last remote signer health check succeeded.",
+		},
+	)
+
+	remoteCollectors = []prometheus.Collector{
+		signLatency,
+		signFailures,
+		signerUp,
+	}
+
+	metricsOnce sync.Once
+)
+
+func initMetrics() {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(remoteCollectors...)
+	})
+}
+
+func observeRequest(role signature.SignerRole, method string, start time.Time, err error) {
+	labels := prometheus.Labels{"role": role.String(), "method": method}
+	if err != nil {
+		signFailures.With(labels).Inc()
+		return
+	}
+	signLatency.With(labels).Observe(time.Since(start).Seconds())
+}
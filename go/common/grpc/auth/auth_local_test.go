@@ -0,0 +1,116 @@
+package auth_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	commonGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/grpc/auth"
+	commonTesting "github.com/oasisprotocol/oasis-core/go/common/grpc/testing"
+)
+
+// serverAuthPingServer is a ping server which does not override the server-level authentication
+// function.
+type serverAuthPingServer struct {
+	commonTesting.PingServer
+}
+
+func startLocalServer(t *testing.T, policy *auth.LocalPolicy, serviceAuth bool) string {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "test.sock")
+	grpcServer, err := commonGrpc.NewServer(&commonGrpc.ServerConfig{
+		Name:          "test",
+		Path:          path,
+		AuthFunc:      policy.AuthFunc,
+		CustomOptions: []grpc.ServerOption{grpc.Creds(auth.NewLocalCredentials())},
+	})
+	require.NoError(err, "NewServer")
+	var pingServer commonTesting.PingServer = commonTesting.NewPingServer(policy.AuthFunc)
+	if !serviceAuth {
+		pingServer = &serverAuthPingServer{pingServer}
+	}
+	commonTesting.RegisterService(grpcServer.Server(), pingServer)
+	err = grpcServer.Start()
+	require.NoError(err, "Start")
+	t.Cleanup(func() {
+		grpcServer.Stop()
+		grpcServer.Cleanup()
+	})
+	return path
+}
+
+func TestLocalPolicy(t *testing.T) {
+	t.Run("ServiceAuthFunc", func(t *testing.T) {
+		testLocalPolicy(t, true)
+	})
+	t.Run("ServerAuthFunc", func(t *testing.T) {
+		testLocalPolicy(t, false)
+	})
+}
+
+func testLocalPolicy(t *testing.T, serviceAuth bool) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	var adminMethod uint32
+	tokens := map[string]auth.Capability{
+		"admin-token":     auth.CapabilityAdmin,
+		"read-only-token": auth.CapabilityReadOnly,
+	}
+	isAdminMethod := func(string) bool {
+		return atomic.LoadUint32(&adminMethod) == 1
+	}
+	path := startLocalServer(t, &auth.LocalPolicy{
+		UIDs: map[uint32]auth.Capability{
+			uint32(os.Getuid()): auth.CapabilityReadOnly,
+		},
+		Tokens:        tokens,
+		IsAdminMethod: isAdminMethod,
+	}, serviceAuth)
+	unknownPeerPath := startLocalServer(t, &auth.LocalPolicy{
+		Tokens:        tokens,
+		IsAdminMethod: isAdminMethod,
+	}, serviceAuth)
+
+	ping := func(path, token string) error {
+		opts := []grpc.DialOption{
+			grpc.WithInsecure(),
+			grpc.WithDefaultCallOptions(grpc.ForceCodec(&commonGrpc.CBORCodec{})),
+		}
+		if token != "" {
+			opts = append(opts, grpc.WithPerRPCCredentials(auth.NewTokenCredentials(token)))
+		}
+		conn, err := grpc.DialContext(ctx, "unix:"+path, opts...)
+		require.NoError(err, "DialContext")
+		defer conn.Close()
+
+		_, err = commonTesting.NewPingClient(conn).Ping(ctx, &commonTesting.PingQuery{})
+		return err
+	}
+	requireDenied := func(err error, msg string) {
+		require.Error(err, msg)
+		require.Equal(codes.PermissionDenied, status.Code(err), msg)
+	}
+
+	// Read-only methods.
+	require.NoError(ping(path, ""), "peer credentials should grant the read-only capability")
+	require.NoError(ping(path, "unknown-token"), "unknown tokens should not revoke capabilities")
+	requireDenied(ping(unknownPeerPath, ""), "unknown peers should not be granted any capabilities")
+	require.NoError(ping(unknownPeerPath, "read-only-token"), "read-only tokens should grant the read-only capability")
+
+	// Admin methods.
+	atomic.StoreUint32(&adminMethod, 1)
+	requireDenied(ping(path, ""), "peer credentials should not grant the admin capability")
+	requireDenied(ping(path, "read-only-token"), "read-only tokens should not grant the admin capability")
+	requireDenied(ping(path, "admin"), "token prefixes should not grant the admin capability")
+	require.NoError(ping(path, "admin-token"), "admin tokens should grant the admin capability")
+}
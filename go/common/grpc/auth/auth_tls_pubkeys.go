@@ -59,6 +59,20 @@ func (auth *PeerPubkeyAuthenticator) AllowPeerPublicKey(key signature.PublicKey)
 	auth.whitelist[key] = true
 }
 
+// SetPeerPublicKeys replaces the set of peer public keys that are allowed access.
+//
+// Established connections are not affected, but the keys are checked on each request.
+func (auth *PeerPubkeyAuthenticator) SetPeerPublicKeys(keys []signature.PublicKey) {
+	whitelist := make(map[signature.PublicKey]bool)
+	for _, key := range keys {
+		whitelist[key] = true
+	}
+
+	auth.Lock()
+	defer auth.Unlock()
+	auth.whitelist = whitelist
+}
+
 // NewPeerPubkeyAuthenticator creates a new (empty) PeerPubkeyAuthenticator.
 func NewPeerPubkeyAuthenticator() *PeerPubkeyAuthenticator {
 	return &PeerPubkeyAuthenticator{
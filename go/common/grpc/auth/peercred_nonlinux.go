@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package auth
+
+import "net"
+
+func getPeerCredentials(conn *net.UnixConn) (*PeerCredentials, error) {
+	// Peer credentials are only implemented for Linux, clients can still authenticate via tokens.
+	return nil, nil
+}
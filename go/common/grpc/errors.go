@@ -7,6 +7,7 @@ import (
 	spb "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
@@ -136,5 +137,22 @@ func clientStreamErrorMapper(
 	opts ...grpc.CallOption,
 ) (grpc.ClientStream, error) {
 	cs, err := streamer(ctx, desc, cc, method, opts...)
-	return cs, errorFromGrpc(err)
+	if err != nil {
+		return nil, errorFromGrpc(err)
+	}
+	return &errorMappingClientStream{cs}, nil
+}
+
+// errorMappingClientStream is a client stream which maps errors returned by the server.
+type errorMappingClientStream struct {
+	grpc.ClientStream
+}
+
+func (s *errorMappingClientStream) Header() (metadata.MD, error) {
+	md, err := s.ClientStream.Header()
+	return md, errorFromGrpc(err)
+}
+
+func (s *errorMappingClientStream) RecvMsg(m interface{}) error {
+	return errorFromGrpc(s.ClientStream.RecvMsg(m))
 }
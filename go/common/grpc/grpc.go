@@ -66,6 +66,13 @@ var (
 		},
 		[]string{"call"},
 	)
+	grpcServerRejectedCalls = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_grpc_server_rejected_calls",
+			Help: "Number of gRPC calls rejected due to method limits.",
+		},
+		[]string{"call", "reason"},
+	)
 	grpcClientCalls = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "oasis_grpc_client_calls",
@@ -95,6 +102,7 @@ var (
 		grpcServerCalls,
 		grpcServerLatency,
 		grpcServerStreamWrites,
+		grpcServerRejectedCalls,
 	}
 
 	serverKeepAliveParams = keepalive.ServerParameters{
@@ -440,6 +448,9 @@ type ServerConfig struct { // nolint: maligned
 	// ClientCommonName is the expected common name on client TLS certificates. If not specified,
 	// the default identity.CommonName will be used.
 	ClientCommonName string
+	// Limits are the resource limits enforced by the server. If not specified, only the default
+	// message size limits are enforced.
+	Limits *Limits
 	// CustomOptions is an array of extra options for the grpc server.
 	CustomOptions []grpc.ServerOption
 }
@@ -600,6 +611,21 @@ func NewServer(config *ServerConfig) (*Server, error) {
 		// Default to identity.CommonName.
 		config.ClientCommonName = identity.CommonName
 	}
+	limits := config.Limits
+	if limits == nil {
+		limits = &Limits{}
+	}
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
+	recvMsgSize, sendMsgSize := maxRecvMsgSize, maxSendMsgSize
+	if limits.MaxRecvMsgSize > 0 {
+		recvMsgSize = limits.MaxRecvMsgSize
+	}
+	if limits.MaxSendMsgSize > 0 {
+		sendMsgSize = limits.MaxSendMsgSize
+	}
+
 	var wrapper *grpcWrapper
 	unaryInterceptors := []grpc.UnaryServerInterceptor{
 		logAdapter.unaryLogger,
@@ -611,6 +637,14 @@ func NewServer(config *ServerConfig) (*Server, error) {
 		serverStreamErrorMapper,
 		auth.StreamServerInterceptor(config.AuthFunc),
 	}
+	limiter, err := newMethodLimiter(limits.Methods)
+	if err != nil {
+		return nil, err
+	}
+	if limiter != nil {
+		unaryInterceptors = append(unaryInterceptors, limiter.unaryInterceptor)
+		streamInterceptors = append(streamInterceptors, limiter.streamInterceptor)
+	}
 	if config.InstallWrapper {
 		wrapper = newWrapper()
 		unaryInterceptors = append(unaryInterceptors, wrapper.unaryInterceptor)
@@ -619,11 +653,14 @@ func NewServer(config *ServerConfig) (*Server, error) {
 	sOpts := []grpc.ServerOption{
 		grpc.ChainUnaryInterceptor(unaryInterceptors...),
 		grpc.ChainStreamInterceptor(streamInterceptors...),
-		grpc.MaxRecvMsgSize(maxRecvMsgSize),
-		grpc.MaxSendMsgSize(maxSendMsgSize),
+		grpc.MaxRecvMsgSize(recvMsgSize),
+		grpc.MaxSendMsgSize(sendMsgSize),
 		grpc.KeepaliveParams(serverKeepAliveParams),
 		grpc.ForceServerCodec(&CBORCodec{}),
 	}
+	if limits.MaxConcurrentStreams > 0 {
+		sOpts = append(sOpts, grpc.MaxConcurrentStreams(limits.MaxConcurrentStreams))
+	}
 	if config.Identity != nil && config.Identity.GetTLSCertificate() != nil {
 		tlsConfig := &tls.Config{
 			ClientAuth: clientAuthType,
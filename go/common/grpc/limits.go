@@ -0,0 +1,221 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	rejectReasonRateLimit       = "rate_limit"
+	rejectReasonConcurrentLimit = "concurrent_limit"
+)
+
+// MethodLimits are the resource limits applied to calls of a gRPC method.
+type MethodLimits struct {
+	// Rate is the maximum sustained rate of calls per second. Zero means no limit.
+	Rate float64 `mapstructure:"rate"`
+
+	// Burst is the maximum number of calls that may be made in excess of the sustained rate.
+	// Must be non-zero in case the rate is limited.
+	Burst uint64 `mapstructure:"burst"`
+
+	// MaxConcurrent is the maximum number of concurrent in-flight calls (or open streams). Calls
+	// over the limit are rejected. Zero means no limit.
+	MaxConcurrent uint64 `mapstructure:"max_concurrent"`
+}
+
+// Validate validates the method limits.
+func (ml *MethodLimits) Validate() error {
+	if ml.Rate < 0 {
+		return fmt.Errorf("rate must not be negative")
+	}
+	if ml.Rate > 0 && ml.Burst == 0 {
+		return fmt.Errorf("burst must be non-zero when rate is set")
+	}
+	return nil
+}
+
+// Limits are the resource limits enforced by a gRPC server.
+type Limits struct {
+	// MaxRecvMsgSize is the maximum size of a received message. Zero means the default size.
+	MaxRecvMsgSize int
+
+	// MaxSendMsgSize is the maximum size of a sent message. Zero means the default size.
+	MaxSendMsgSize int
+
+	// MaxConcurrentStreams is the maximum number of concurrent streams (including unary calls)
+	// of each client connection. Zero means no limit.
+	MaxConcurrentStreams uint32
+
+	// Methods are the per-method limits, keyed by the (case-insensitive) service name without
+	// the oasis-core prefix, optionally followed by a slash and the method name (e.g., "Registry"
+	// or "Registry/GetNode"). Limits configured for a service apply to each of its methods that
+	// do not have limits configured on their own.
+	Methods map[string]MethodLimits
+}
+
+// Validate validates the limits.
+func (l *Limits) Validate() error {
+	if l.MaxRecvMsgSize < 0 {
+		return fmt.Errorf("grpc: maximum received message size must not be negative")
+	}
+	if l.MaxSendMsgSize < 0 {
+		return fmt.Errorf("grpc: maximum sent message size must not be negative")
+	}
+	for name, ml := range l.Methods {
+		if _, err := methodLimitsKey(name); err != nil {
+			return err
+		}
+		if err := ml.Validate(); err != nil {
+			return fmt.Errorf("grpc: bad limits for '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
+// methodLimitsKey converts the configured service/method name into the lower-cased full service
+// or full method name.
+func methodLimitsKey(name string) (string, error) {
+	parts := strings.Split(name, "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		return strings.ToLower(ServicePrefix + parts[0]), nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return strings.ToLower(fmt.Sprintf("/%s%s/%s", ServicePrefix, parts[0], parts[1])), nil
+	default:
+		return "", fmt.Errorf("grpc: malformed service/method name: '%s'", name)
+	}
+}
+
+// methodState is the limiting state of a single gRPC method.
+type methodState struct {
+	tokens   float64
+	last     time.Time
+	inflight uint64
+}
+
+// methodLimiter limits the rate and the number of concurrent calls of each gRPC method.
+//
+// The rate of calls is limited using a token bucket per method. A nil limiter does not limit
+// anything.
+type methodLimiter struct {
+	sync.Mutex
+
+	limits map[string]*MethodLimits
+	states map[string]*methodState
+	now    func() time.Time
+}
+
+func (l *methodLimiter) limitsFor(method string) *MethodLimits {
+	method = strings.ToLower(method)
+	if ml, ok := l.limits[method]; ok {
+		return ml
+	}
+	return l.limits[string(ServiceNameFromMethod(method))]
+}
+
+// acquire accounts for a new call of the given method. In case the call is allowed, the returned
+// function must be called once the call completes, otherwise the reason for rejecting the call
+// is returned.
+func (l *methodLimiter) acquire(method string) (func(), string) {
+	if l == nil {
+		return func() {}, ""
+	}
+
+	ml := l.limitsFor(method)
+	if ml == nil {
+		return func() {}, ""
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	now := l.now()
+	ms := l.states[method]
+	if ms == nil {
+		ms = &methodState{
+			tokens: float64(ml.Burst),
+			last:   now,
+		}
+		l.states[method] = ms
+	}
+
+	if ml.MaxConcurrent > 0 && ms.inflight >= ml.MaxConcurrent {
+		return nil, rejectReasonConcurrentLimit
+	}
+	if ml.Rate > 0 {
+		ms.tokens += now.Sub(ms.last).Seconds() * ml.Rate
+		if ms.tokens > float64(ml.Burst) {
+			ms.tokens = float64(ml.Burst)
+		}
+		ms.last = now
+		if ms.tokens < 1 {
+			return nil, rejectReasonRateLimit
+		}
+		ms.tokens--
+	}
+	ms.inflight++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.Lock()
+			defer l.Unlock()
+			ms.inflight--
+		})
+	}, ""
+}
+
+func (l *methodLimiter) reject(method, reason string) error {
+	grpcServerRejectedCalls.With(prometheus.Labels{"call": method, "reason": reason}).Inc()
+	return status.Errorf(codes.ResourceExhausted, "method limit exceeded: %s", reason)
+}
+
+func (l *methodLimiter) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	release, reason := l.acquire(info.FullMethod)
+	if reason != "" {
+		return nil, l.reject(info.FullMethod, reason)
+	}
+	defer release()
+
+	return handler(ctx, req)
+}
+
+func (l *methodLimiter) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	release, reason := l.acquire(info.FullMethod)
+	if reason != "" {
+		return l.reject(info.FullMethod, reason)
+	}
+	defer release()
+
+	return handler(srv, ss)
+}
+
+func newMethodLimiter(methods map[string]MethodLimits) (*methodLimiter, error) {
+	if len(methods) == 0 {
+		return nil, nil
+	}
+
+	l := &methodLimiter{
+		limits: make(map[string]*MethodLimits),
+		states: make(map[string]*methodState),
+		now:    time.Now,
+	}
+	for name, ml := range methods {
+		key, err := methodLimitsKey(name)
+		if err != nil {
+			return nil, err
+		}
+		ml := ml
+		l.limits[key] = &ml
+	}
+	return l, nil
+}
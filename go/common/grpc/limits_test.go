@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLimitsValidate(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		limits Limits
+		valid  bool
+	}{
+		{Limits{}, true},
+		{Limits{MaxRecvMsgSize: -1}, false},
+		{Limits{MaxSendMsgSize: -1}, false},
+		{Limits{Methods: map[string]MethodLimits{"Registry": {Rate: 1, Burst: 1}}}, true},
+		{Limits{Methods: map[string]MethodLimits{"Registry/GetNode": {MaxConcurrent: 1}}}, true},
+		{Limits{Methods: map[string]MethodLimits{"Registry/GetNode": {Rate: -1}}}, false},
+		{Limits{Methods: map[string]MethodLimits{"Registry/GetNode": {Rate: 1}}}, false},
+		{Limits{Methods: map[string]MethodLimits{"": {}}}, false},
+		{Limits{Methods: map[string]MethodLimits{"Registry/": {}}}, false},
+		{Limits{Methods: map[string]MethodLimits{"Registry/GetNode/Extra": {}}}, false},
+	} {
+		err := tc.limits.Validate()
+		if tc.valid {
+			require.NoError(err, "Validate(%+v)", tc.limits)
+		} else {
+			require.Error(err, "Validate(%+v)", tc.limits)
+		}
+	}
+}
+
+func TestMethodLimiter(t *testing.T) {
+	require := require.New(t)
+
+	var nilLimiter *methodLimiter
+	release, reason := nilLimiter.acquire("/oasis-core.Registry/GetNode")
+	require.Empty(reason, "nil limiter should not limit anything")
+	release()
+
+	l, err := newMethodLimiter(map[string]MethodLimits{
+		"registry":             {Rate: 1, Burst: 2},
+		"Registry/GetNodes":    {MaxConcurrent: 1},
+		"Scheduler/GetVersion": {},
+	})
+	require.NoError(err, "newMethodLimiter")
+
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	// Service limits should apply to each method of the service.
+	for _, method := range []string{"/oasis-core.Registry/GetNode", "/oasis-core.Registry/GetEntity"} {
+		for i := 0; i < 2; i++ {
+			release, reason = l.acquire(method)
+			require.Empty(reason, "calls within the burst should be allowed")
+			release()
+		}
+		_, reason = l.acquire(method)
+		require.Equal(rejectReasonRateLimit, reason, "calls over the burst should be rejected")
+	}
+
+	now = now.Add(time.Second)
+	release, reason = l.acquire("/oasis-core.Registry/GetNode")
+	require.Empty(reason, "calls should be allowed after the bucket is refilled")
+	release()
+
+	// Method limits should take precedence over service limits.
+	release, reason = l.acquire("/oasis-core.Registry/GetNodes")
+	require.Empty(reason, "first concurrent call should be allowed")
+	_, reason = l.acquire("/oasis-core.Registry/GetNodes")
+	require.Equal(rejectReasonConcurrentLimit, reason, "calls over the concurrency limit should be rejected")
+	release()
+	release()
+	release, reason = l.acquire("/oasis-core.Registry/GetNodes")
+	require.Empty(reason, "calls should be allowed after a call completes")
+	release()
+
+	// Methods without limits should not be limited.
+	for i := 0; i < 10; i++ {
+		release, reason = l.acquire("/oasis-core.Staking/Account")
+		require.Empty(reason, "methods without limits should not be limited")
+		release()
+	}
+
+	// Rejected calls should fail with ResourceExhausted.
+	info := &grpc.UnaryServerInfo{FullMethod: "/oasis-core.Registry/GetNode"}
+	_, err = l.unaryInterceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	require.Error(err, "unaryInterceptor should fail over the limit")
+	require.Equal(codes.ResourceExhausted, status.Code(err))
+}
@@ -0,0 +1,108 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/grpc/auth"
+)
+
+// ExternalPolicy is the access policy of a restricted external gRPC API surface, e.g., one that
+// is exposed to the public by RPC providers.
+type ExternalPolicy struct {
+	// Methods are the allowed services or methods, given as service names without the oasis-core
+	// prefix, optionally followed by a slash and the method name (e.g., "Consensus" or
+	// "Registry/GetNode"). If empty, no methods are allowed.
+	//
+	// Methods requiring the admin capability are never allowed, even if listed.
+	Methods []string
+
+	// Runtimes are the runtimes that runtime-specific methods may be called for. Methods that are
+	// not runtime-specific are not affected. If empty, all runtimes are allowed.
+	Runtimes []common.Namespace
+
+	// ReadOnly specifies whether methods that modify state (e.g., submit transactions) are
+	// rejected.
+	ReadOnly bool
+}
+
+type externalPolicyChecker struct {
+	services map[string]bool
+	methods  map[string]bool
+	runtimes map[common.Namespace]bool
+	readOnly bool
+}
+
+func (c *externalPolicyChecker) isMethodAllowed(fullMethodName string) bool {
+	fullMethodName = strings.ToLower(fullMethodName)
+	return c.methods[fullMethodName] || c.services[string(grpc.ServiceNameFromMethod(fullMethodName))]
+}
+
+func (c *externalPolicyChecker) authFunc(ctx context.Context, fullMethodName string, req interface{}) error {
+	md, err := grpc.GetRegisteredMethod(fullMethodName)
+	if err != nil {
+		return status.Errorf(codes.PermissionDenied, "invalid request method")
+	}
+	// Administrative methods are never exposed externally.
+	if md.RequiresAdminCapability() {
+		return status.Errorf(codes.PermissionDenied, "method not allowed")
+	}
+	if !c.isMethodAllowed(fullMethodName) {
+		return status.Errorf(codes.PermissionDenied, "method not allowed")
+	}
+	if c.readOnly && md.RequiresWriteAccess() {
+		return status.Errorf(codes.PermissionDenied, "method not allowed in read-only mode")
+	}
+	if len(c.runtimes) == 0 || !md.HasNamespaceExtractor() {
+		return nil
+	}
+
+	// Proxied requests are not unmarshalled.
+	if raw, ok := req.(*cbor.RawMessage); ok {
+		if req, err = md.UnmarshalRawMessage(raw); err != nil {
+			return status.Errorf(codes.PermissionDenied, "invalid request")
+		}
+	}
+	namespace, err := md.ExtractNamespace(ctx, req)
+	if err != nil {
+		return status.Errorf(codes.PermissionDenied, "invalid request namespace")
+	}
+	if !c.runtimes[namespace] {
+		return status.Errorf(codes.PermissionDenied, "runtime not allowed")
+	}
+	return nil
+}
+
+// ExternalAuthenticationFunction returns a gRPC authentication function enforcing the given
+// external API policy.
+func ExternalAuthenticationFunction(policy *ExternalPolicy) (auth.AuthenticationFunction, error) {
+	c := &externalPolicyChecker{
+		services: make(map[string]bool),
+		methods:  make(map[string]bool),
+		runtimes: make(map[common.Namespace]bool),
+		readOnly: policy.ReadOnly,
+	}
+	for _, name := range policy.Methods {
+		parts := strings.Split(name, "/")
+		switch {
+		case len(parts) == 1 && parts[0] != "":
+			c.services[strings.ToLower(string(grpc.NewServiceName(parts[0])))] = true
+		case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+			fullMethodName := fmt.Sprintf("/%s/%s", grpc.NewServiceName(parts[0]), parts[1])
+			c.methods[strings.ToLower(fullMethodName)] = true
+		default:
+			return nil, fmt.Errorf("grpc/policy: malformed service/method name: '%s'", name)
+		}
+	}
+	for _, rt := range policy.Runtimes {
+		c.runtimes[rt] = true
+	}
+	return c.authFunc, nil
+}
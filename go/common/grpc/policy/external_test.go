@@ -0,0 +1,117 @@
+package policy_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/grpc/policy"
+	cmnTesting "github.com/oasisprotocol/oasis-core/go/common/grpc/testing"
+	// Register the node controller and sentry methods.
+	_ "github.com/oasisprotocol/oasis-core/go/control/api"
+	_ "github.com/oasisprotocol/oasis-core/go/sentry/api"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+)
+
+var (
+	externalTestServiceName = cmnGrpc.NewServiceName("ExternalPolicyTest")
+
+	methodExternalTestQuery  = externalTestServiceName.NewMethod("Query", nil)
+	methodExternalTestSubmit = externalTestServiceName.NewMethod("Submit", nil).WithWriteAccess()
+	methodExternalTestAdmin  = externalTestServiceName.NewMethod("Admin", nil).WithAdminCapability()
+)
+
+func TestExternalPolicy(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	otherNs := common.NewTestNamespaceFromSeed([]byte("oasis common grpc policy test other ns"), 0)
+
+	_, err := policy.ExternalAuthenticationFunction(&policy.ExternalPolicy{
+		Methods: []string{"ExternalPolicyTest/Query/Extra"},
+	})
+	require.Error(err, "ExternalAuthenticationFunction should fail with malformed method names")
+
+	requireAllowed := func(authFunc func(context.Context, string, interface{}) error, method string, req interface{}, allowed bool) {
+		err := authFunc(ctx, method, req)
+		if allowed {
+			require.NoError(err, "%s should be allowed", method)
+		} else {
+			require.Error(err, "%s should not be allowed", method)
+			require.Equal(codes.PermissionDenied, status.Code(err))
+		}
+	}
+
+	// Method whitelist.
+	authFunc, err := policy.ExternalAuthenticationFunction(&policy.ExternalPolicy{
+		Methods: []string{"externalpolicytest/query", "PingService"},
+	})
+	require.NoError(err, "ExternalAuthenticationFunction")
+	requireAllowed(authFunc, methodExternalTestQuery.FullName(), nil, true)
+	requireAllowed(authFunc, methodExternalTestSubmit.FullName(), nil, false)
+	requireAllowed(authFunc, cmnTesting.MethodPing.FullName(), &cmnTesting.PingQuery{}, true)
+	requireAllowed(authFunc, "/oasis-core.Unknown/Method", nil, false)
+
+	// No methods are allowed by default.
+	authFunc, err = policy.ExternalAuthenticationFunction(&policy.ExternalPolicy{})
+	require.NoError(err, "ExternalAuthenticationFunction")
+	requireAllowed(authFunc, methodExternalTestQuery.FullName(), nil, false)
+	requireAllowed(authFunc, cmnTesting.MethodPing.FullName(), &cmnTesting.PingQuery{}, false)
+
+	// Administrative methods are never allowed.
+	authFunc, err = policy.ExternalAuthenticationFunction(&policy.ExternalPolicy{
+		Methods: []string{"ExternalPolicyTest", "NodeController", "NodeController/RequestShutdown"},
+	})
+	require.NoError(err, "ExternalAuthenticationFunction")
+	requireAllowed(authFunc, methodExternalTestQuery.FullName(), nil, true)
+	requireAllowed(authFunc, methodExternalTestAdmin.FullName(), nil, false)
+	for _, method := range []string{"RequestShutdown", "Shutdown", "UpgradeBinary", "SetLogLevel"} {
+		requireAllowed(authFunc, fmt.Sprintf("/%s/%s", cmnGrpc.NewServiceName("NodeController"), method), nil, false)
+	}
+	requireAllowed(authFunc, fmt.Sprintf("/%s/GetStatus", cmnGrpc.NewServiceName("NodeController")), nil, true)
+
+	// Read-only mode.
+	authFunc, err = policy.ExternalAuthenticationFunction(&policy.ExternalPolicy{
+		Methods:  []string{"ExternalPolicyTest"},
+		ReadOnly: true,
+	})
+	require.NoError(err, "ExternalAuthenticationFunction")
+	requireAllowed(authFunc, methodExternalTestQuery.FullName(), nil, true)
+	requireAllowed(authFunc, methodExternalTestSubmit.FullName(), nil, false)
+	requireAllowed(authFunc, methodExternalTestAdmin.FullName(), nil, false)
+
+	authFunc, err = policy.ExternalAuthenticationFunction(&policy.ExternalPolicy{
+		Methods:  []string{"Storage", "Sentry"},
+		ReadOnly: true,
+	})
+	require.NoError(err, "ExternalAuthenticationFunction")
+	requireAllowed(authFunc, storage.MethodSyncGet.FullName(), nil, true)
+	requireAllowed(authFunc, storage.MethodApply.FullName(), nil, false)
+	requireAllowed(authFunc, storage.MethodApplyBatch.FullName(), nil, false)
+	for _, method := range []string{"SetUpstreamTLSPubKeys", "UpdatePolicies"} {
+		requireAllowed(authFunc, fmt.Sprintf("/%s/%s", cmnGrpc.NewServiceName("Sentry"), method), nil, false)
+	}
+
+	// Per-runtime access.
+	authFunc, err = policy.ExternalAuthenticationFunction(&policy.ExternalPolicy{
+		Methods:  []string{"ExternalPolicyTest", "PingService"},
+		Runtimes: []common.Namespace{testNs},
+	})
+	require.NoError(err, "ExternalAuthenticationFunction")
+	requireAllowed(authFunc, methodExternalTestQuery.FullName(), nil, true)
+	requireAllowed(authFunc, cmnTesting.MethodPing.FullName(), &cmnTesting.PingQuery{Namespace: testNs}, true)
+	requireAllowed(authFunc, cmnTesting.MethodPing.FullName(), &cmnTesting.PingQuery{Namespace: otherNs}, false)
+
+	// Proxied requests.
+	raw := cbor.RawMessage(cbor.Marshal(&cmnTesting.PingQuery{Namespace: testNs}))
+	requireAllowed(authFunc, cmnTesting.MethodPing.FullName(), &raw, true)
+	raw = cbor.RawMessage(cbor.Marshal(&cmnTesting.PingQuery{Namespace: otherNs}))
+	requireAllowed(authFunc, cmnTesting.MethodPing.FullName(), &raw, false)
+}
@@ -9,7 +9,6 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
@@ -20,29 +19,27 @@ import (
 
 // Dialer should return a gRPC ClientConn that will be used
 // to forward calls to.
+//
+// The dialer is invoked for each proxied call, so it can switch between
+// upstream servers. It is responsible for caching client connections.
 type Dialer func(ctx context.Context) (*grpc.ClientConn, error)
 
 // Handler returns a gRPC StreamHandler than can be used
 // to proxy requests to the client returned by the proxy dialer.
 func Handler(dialer Dialer) grpc.StreamHandler {
 	proxy := &proxy{
-		logger:       logging.GetLogger("grpc/proxy"),
-		dialer:       dialer,
-		upstreamConn: nil, // Will be dialed on-demand.
+		logger: logging.GetLogger("grpc/proxy"),
+		dialer: dialer,
 	}
 
 	return grpc.StreamHandler(proxy.handler)
 }
 
 type proxy struct {
-	// This is the dialer callback we use to make new connections to the
-	// upstream server if the connection drops, etc.
+	// This is the dialer callback we use to obtain the connection to the
+	// upstream server for each call.
 	dialer Dialer
 
-	// This is a cached client connection to the upstream server, so we
-	// don't have to re-dial it on every call.
-	upstreamConn *grpc.ClientConn
-
 	logger *logging.Logger
 
 	// XXX: Currently for each incoming stream two goroutines are spawned,
@@ -78,25 +75,16 @@ func (p *proxy) handler(srv interface{}, stream grpc.ServerStream) error {
 	// Pass subject header upstream.
 	upstreamCtx = metadata.AppendToOutgoingContext(upstreamCtx, policy.ForwardedSubjectMD, sub)
 
-	// Check if upstream connection was disconnected.
-	if p.upstreamConn != nil && p.upstreamConn.GetState() == connectivity.Shutdown {
-		// We need to redial if the connection was shut down.
-		p.upstreamConn = nil
-	}
-
-	// Dial upstream if necessary.
-	if p.upstreamConn == nil {
-		var grr error
-		p.upstreamConn, grr = p.dialer(stream.Context())
-		if grr != nil {
-			return grr
-		}
+	// Obtain the upstream connection.
+	upstreamConn, err := p.dialer(stream.Context())
+	if err != nil {
+		return err
 	}
 
 	upstreamStream, err := grpc.NewClientStream(
 		upstreamCtx,
 		desc,
-		p.upstreamConn,
+		upstreamConn,
 		method,
 	)
 	if err != nil {
@@ -78,11 +78,10 @@ func TestGRPCProxy(t *testing.T) {
 	require.NoError(err, "NewClientCreds")
 
 	// Create upstream dialer.
+	proxiedConn := connectToGrpcServer(ctx, t, fmt.Sprintf("%s:%d", host, port), clientTLSCreds)
+	defer proxiedConn.Close()
 	upstreamDialer := func(ctx context.Context) (*grpc.ClientConn, error) {
-		// Connect to gRPC server.
-		address := fmt.Sprintf("%s:%d", host, port)
-		conn := connectToGrpcServer(ctx, t, address, clientTLSCreds)
-		return conn, nil
+		return proxiedConn, nil
 	}
 
 	// Create a proxy gRPC server.
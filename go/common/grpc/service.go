@@ -58,6 +58,16 @@ func GetRegisteredMethod(name string) (*MethodDesc, error) {
 	return m, nil
 }
 
+// IsAdminMethod returns true iff the given method requires the admin capability. Methods which
+// are not registered are conservatively considered to require the admin capability.
+func IsAdminMethod(fullMethodName string) bool {
+	md, err := GetRegisteredMethod(fullMethodName)
+	if err != nil {
+		return true
+	}
+	return md.RequiresAdminCapability()
+}
+
 // NewMethod creates a new method name for the given service.
 func (sn ServiceName) NewMethod(name string, requestType interface{}) *MethodDesc {
 	if strings.Contains(name, "/") {
@@ -91,6 +101,20 @@ func (m *MethodDesc) WithAccessControl(f AccessControlFunc) *MethodDesc {
 	return m
 }
 
+// WithAdminCapability tells that the endpoint requires the admin capability on servers that
+// authorize clients based on capabilities (e.g., the node's internal socket).
+func (m *MethodDesc) WithAdminCapability() *MethodDesc {
+	m.adminCapability = true
+	return m
+}
+
+// WithWriteAccess tells that the endpoint modifies state (e.g., submits transactions) and is thus
+// not available on read-only API surfaces.
+func (m *MethodDesc) WithWriteAccess() *MethodDesc {
+	m.writeAccess = true
+	return m
+}
+
 // MethodDesc is a gRPC method descriptor.
 type MethodDesc struct {
 	short       string
@@ -99,6 +123,8 @@ type MethodDesc struct {
 
 	accessControl      AccessControlFunc
 	namespaceExtractor NamespaceExtractorFunc
+	adminCapability    bool
+	writeAccess        bool
 }
 
 // ShortName returns the short method name.
@@ -111,6 +137,17 @@ func (m *MethodDesc) FullName() string {
 	return m.full
 }
 
+// RequiresAdminCapability returns true iff the method requires the admin capability.
+func (m *MethodDesc) RequiresAdminCapability() bool {
+	return m.adminCapability
+}
+
+// RequiresWriteAccess returns true iff the method modifies state. Methods requiring the admin
+// capability are always considered to modify state.
+func (m *MethodDesc) RequiresWriteAccess() bool {
+	return m.writeAccess || m.adminCapability
+}
+
 // IsAccessControlled retruns if method is access controlled.
 func (m *MethodDesc) IsAccessControlled(ctx context.Context, req interface{}) (bool, error) {
 	if m.accessControl == nil {
@@ -122,7 +159,7 @@ func (m *MethodDesc) IsAccessControlled(ctx context.Context, req interface{}) (b
 // UnmarshalRawMessage unmarshals `cbor.RawMessage` request.
 func (m *MethodDesc) UnmarshalRawMessage(req *cbor.RawMessage) (interface{}, error) {
 	v := reflect.New(reflect.TypeOf(m.requestType)).Interface()
-	if err := cbor.Unmarshal(*req, v); err != nil {
+	if err := cbor.UnmarshalIngress(*req, v); err != nil {
 		return nil, fmt.Errorf("unmarshal error: %w", err)
 	}
 	return v, nil
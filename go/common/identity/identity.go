@@ -63,6 +63,16 @@ var (
 		signature.SignerConsensus,
 		signature.SignerVRF,
 	}
+
+	// PersistentTLSFilenames are the filenames of the TLS keys and
+	// certificates that may be persisted in the data directory. Ephemeral
+	// TLS keys are not included.
+	PersistentTLSFilenames = []string{
+		tlsKeyFilename,
+		tlsCertFilename,
+		tlsSentryClientKeyFilename,
+		tlsSentryClientCertFilename,
+	}
 )
 
 // Identity is a node identity.
@@ -13,6 +13,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
@@ -31,6 +32,22 @@ var (
 	_ pflag.Value = (*Format)(nil)
 )
 
+// Field names used in log entries. These are stable and may be relied upon
+// by log ingestion pipelines.
+const (
+	// KeyTimestamp is the field name of the entry timestamp (RFC 3339, UTC).
+	KeyTimestamp = "ts"
+	// KeyLevel is the field name of the entry log level (the same as level.Key
+	// so that go-kit level filters keep working).
+	KeyLevel = "level"
+	// KeyModule is the field name of the module that emitted the entry.
+	KeyModule = "module"
+	// KeyCaller is the field name of the source location that emitted the entry.
+	KeyCaller = "caller"
+	// KeyMessage is the field name of the entry message.
+	KeyMessage = "msg"
+)
+
 // Format is a logging format.
 type Format uint
 
@@ -86,21 +103,6 @@ const (
 	LevelError
 )
 
-func (l Level) toOption() level.Option {
-	switch l {
-	case LevelDebug:
-		return level.AllowDebug()
-	case LevelInfo:
-		return level.AllowInfo()
-	case LevelWarn:
-		return level.AllowWarn()
-	case LevelError:
-		return level.AllowError()
-	default:
-		panic("logging: unsupported log level")
-	}
-}
-
 // String returns the string representation of a Level.
 func (l *Level) String() string {
 	switch *l {
@@ -143,44 +145,60 @@ func (l *Level) Type() string {
 // Logger is a logger instance.
 type Logger struct {
 	logger log.Logger
-	level  Level
 	module string
+
+	// level is the cached log level of the logger, packed together with
+	// the backend level generation it was computed for.
+	level uint64
+}
+
+func (l *Logger) getLevel() Level {
+	gen := atomic.LoadUint64(&backend.generation)
+	packed := atomic.LoadUint64(&l.level)
+	if packed>>8 == gen {
+		return Level(packed & 0xff)
+	}
+
+	// Log levels were changed since the level was last computed.
+	lvl, gen := backend.getLevel(l.module)
+	atomic.StoreUint64(&l.level, gen<<8|uint64(lvl))
+	return lvl
 }
 
 // Debug logs the message and key value pairs at the Debug log level.
 func (l *Logger) Debug(msg string, keyvals ...interface{}) {
-	if l.level > LevelDebug {
+	if l.getLevel() > LevelDebug {
 		return
 	}
-	keyvals = append([]interface{}{"msg", msg}, keyvals...)
-	_ = level.Debug(l.logger).Log(keyvals...)
+	keyvals = append([]interface{}{KeyMessage, msg}, keyvals...)
+	_ = log.WithPrefix(l.logger, KeyLevel, level.DebugValue()).Log(keyvals...)
 }
 
 // Info logs the message and key value pairs at the Info log level.
 func (l *Logger) Info(msg string, keyvals ...interface{}) {
-	if l.level > LevelInfo {
+	if l.getLevel() > LevelInfo {
 		return
 	}
-	keyvals = append([]interface{}{"msg", msg}, keyvals...)
-	_ = level.Info(l.logger).Log(keyvals...)
+	keyvals = append([]interface{}{KeyMessage, msg}, keyvals...)
+	_ = log.WithPrefix(l.logger, KeyLevel, level.InfoValue()).Log(keyvals...)
 }
 
 // Warn logs the message and key value pairs at the Warn log level.
 func (l *Logger) Warn(msg string, keyvals ...interface{}) {
-	if l.level > LevelWarn {
+	if l.getLevel() > LevelWarn {
 		return
 	}
-	keyvals = append([]interface{}{"msg", msg}, keyvals...)
-	_ = level.Warn(l.logger).Log(keyvals...)
+	keyvals = append([]interface{}{KeyMessage, msg}, keyvals...)
+	_ = log.WithPrefix(l.logger, KeyLevel, level.WarnValue()).Log(keyvals...)
 }
 
 // Error logs the message and key value pairs at the Error log level.
 func (l *Logger) Error(msg string, keyvals ...interface{}) {
-	if l.level > LevelError {
+	if l.getLevel() > LevelError {
 		return
 	}
-	keyvals = append([]interface{}{"msg", msg}, keyvals...)
-	_ = level.Error(l.logger).Log(keyvals...)
+	keyvals = append([]interface{}{KeyMessage, msg}, keyvals...)
+	_ = log.WithPrefix(l.logger, KeyLevel, level.ErrorValue()).Log(keyvals...)
 }
 
 // With returns a clone of the logger with the provided key/value pairs
@@ -188,15 +206,77 @@ func (l *Logger) Error(msg string, keyvals ...interface{}) {
 func (l *Logger) With(keyvals ...interface{}) *Logger {
 	return &Logger{
 		logger: log.With(l.logger, keyvals...),
-		level:  l.level,
+		module: l.module,
+		level:  atomic.LoadUint64(&l.level),
 	}
 }
 
 // GetLevel returns the current global log level.
 func GetLevel() Level {
+	backend.Lock()
+	defer backend.Unlock()
+
 	return backend.defaultLevel
 }
 
+// SetLevels changes the default log level and the log levels specified for
+// each module of an already initialized logging backend. All existing
+// loggers pick up the new levels.
+func SetLevels(defaultLvl Level, moduleLvls map[string]Level) error {
+	backend.Lock()
+	defer backend.Unlock()
+
+	if !backend.initialized {
+		return fmt.Errorf("logging: not initialized")
+	}
+
+	backend.moduleLevels = moduleLvls
+	backend.defaultLevel = defaultLvl
+	atomic.AddUint64(&backend.generation, 1)
+
+	return nil
+}
+
+// SetModuleLevel overrides the log level of the given module (and of all
+// modules that it is a prefix of) of an already initialized logging backend.
+// Unlike the configured module levels, overrides can be below the default
+// level and persist across SetLevels calls. All existing loggers pick up the
+// new level.
+func SetModuleLevel(module string, lvl Level) error {
+	backend.Lock()
+	defer backend.Unlock()
+
+	if !backend.initialized {
+		return fmt.Errorf("logging: not initialized")
+	}
+	if module == "" {
+		return fmt.Errorf("logging: module not specified")
+	}
+
+	if backend.overrideLevels == nil {
+		backend.overrideLevels = make(map[string]Level)
+	}
+	backend.overrideLevels[module] = lvl
+	atomic.AddUint64(&backend.generation, 1)
+
+	return nil
+}
+
+// ResetModuleLevel removes the log level override of the given module.
+func ResetModuleLevel(module string) error {
+	backend.Lock()
+	defer backend.Unlock()
+
+	if !backend.initialized {
+		return fmt.Errorf("logging: not initialized")
+	}
+
+	delete(backend.overrideLevels, module)
+	atomic.AddUint64(&backend.generation, 1)
+
+	return nil
+}
+
 // GetLogger creates a new logger instance with the specified module.
 //
 // This may be called from any point, including before Initialize is
@@ -241,23 +321,18 @@ func Initialize(w io.Writer, format Format, defaultLvl Level, moduleLvls map[str
 		}
 	}
 
-	logger = level.NewFilter(logger, defaultLvl.toOption())
-	logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+	logger = log.With(logger, KeyTimestamp, log.DefaultTimestampUTC)
 
 	backend.baseLogger = logger
 	backend.moduleLevels = moduleLvls
 	backend.defaultLevel = defaultLvl
 	backend.initialized = true
+	// Force re-evaluation of the log level of all loggers.
+	atomic.AddUint64(&backend.generation, 1)
 
 	// Swap all the early loggers to the initialized backend.
 	for _, l := range backend.earlyLoggers {
 		l.swapLogger.Swap(backend.baseLogger)
-
-		// Re-evaluate log level.
-		// NOTE: This introduces a potential race condition if loggers are used
-		//       before the logging system is initialized. Protecting the log
-		//       level with a mutex just for this one case seems overkill.
-		backend.setupLogLevelLocked(l.logger)
 	}
 	backend.earlyLoggers = nil
 
@@ -275,7 +350,6 @@ func Initialize(w io.Writer, format Format, defaultLvl Level, moduleLvls map[str
 
 type earlyLogger struct {
 	swapLogger *log.SwapLogger
-	logger     *Logger
 }
 
 type logBackend struct {
@@ -285,29 +359,55 @@ type logBackend struct {
 	earlyLoggers []*earlyLogger
 	defaultLevel Level
 	moduleLevels map[string]Level
+	generation   uint64
+
+	// overrideLevels are the module levels overridden at runtime.
+	overrideLevels map[string]Level
 
 	initialized bool
 }
 
-func (b *logBackend) setupLogLevelLocked(l *Logger) {
+func (b *logBackend) getLevel(module string) (Level, uint64) {
+	b.Lock()
+	defer b.Unlock()
+
+	return b.getLevelLocked(module), atomic.LoadUint64(&b.generation)
+}
+
+func (b *logBackend) getLevelLocked(module string) Level {
+	// Levels overridden at runtime take precedence.
+	if lvl, ok := longestPrefixLevel(b.overrideLevels, module); ok {
+		return lvl
+	}
+
 	// Check, whether there is a specific logging level set for the module.
 	// The longest prefix match of the module name provided in the config file will be taken.
 	// Otherwise, fallback to level defined by "default" key.
-	modulePrefixes := make([]string, 0, len(b.moduleLevels))
-	for k := range b.moduleLevels {
+	lvl, ok := longestPrefixLevel(b.moduleLevels, module)
+	if !ok {
+		lvl = b.defaultLevel
+	}
+
+	// Messages below the default level are never emitted.
+	if lvl < b.defaultLevel {
+		lvl = b.defaultLevel
+	}
+	return lvl
+}
+
+func longestPrefixLevel(levels map[string]Level, module string) (Level, bool) {
+	modulePrefixes := make([]string, 0, len(levels))
+	for k := range levels {
 		modulePrefixes = append(modulePrefixes, k)
 	}
 	sort.Sort(sort.Reverse(sort.StringSlice(modulePrefixes)))
 
-	lvl := b.defaultLevel
 	for _, k := range modulePrefixes {
-		if strings.HasPrefix(l.module, k) {
-			lvl = b.moduleLevels[k]
-			break
+		if strings.HasPrefix(module, k) {
+			return levels[k], true
 		}
 	}
-
-	l.level = lvl
+	return 0, false
 }
 
 func (b *logBackend) getLogger(module string, extraUnwind int) *Logger {
@@ -327,25 +427,25 @@ func (b *logBackend) getLogger(module string, extraUnwind int) *Logger {
 	var keyvals []interface{}
 	if module != "" {
 		keyvals = append(keyvals, []interface{}{
-			"module",
+			KeyModule,
 			module,
 		}...)
 	}
 	keyvals = append(keyvals, []interface{}{
-		"caller",
+		KeyCaller,
 		log.Caller(defaultUnwind + extraUnwind),
 	}...)
 	l := &Logger{
 		logger: log.WithPrefix(logger, keyvals...),
 		module: module,
 	}
-	b.setupLogLevelLocked(l)
+	l.level = atomic.LoadUint64(&b.generation)<<8 | uint64(b.getLevelLocked(module))
 
 	if !b.initialized {
 		// Stash the logger so that it can be instantiated once logging
 		// is actually initialized.
 		sLog := logger.(*log.SwapLogger)
-		b.earlyLoggers = append(b.earlyLoggers, &earlyLogger{swapLogger: sLog, logger: l})
+		b.earlyLoggers = append(b.earlyLoggers, &earlyLogger{swapLogger: sLog})
 	}
 
 	return l
@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-kit/log/level"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetLevels(t *testing.T) {
+	require := require.New(t)
+
+	earlyLogger := GetLogger("test/early")
+
+	var buf bytes.Buffer
+	err := Initialize(&buf, FmtLogfmt, LevelInfo, map[string]Level{
+		"test/verbose": LevelDebug,
+		"test/quiet":   LevelError,
+	})
+	require.NoError(err, "Initialize")
+
+	err = Initialize(&buf, FmtLogfmt, LevelInfo, nil)
+	require.Error(err, "Initialize should fail when already initialized")
+
+	quietLogger := GetLogger("test/quiet")
+	verboseLogger := GetLogger("test/verbose")
+	withLogger := quietLogger.With("key", "value")
+
+	emits := func(fn func(string, ...interface{})) bool {
+		buf.Reset()
+		fn("message")
+		return buf.Len() > 0
+	}
+
+	require.True(emits(earlyLogger.Info), "early logger should use the default level")
+	require.False(emits(earlyLogger.Debug), "early logger should use the default level")
+	require.False(emits(quietLogger.Warn), "module level should be respected")
+	require.False(emits(withLogger.Warn), "derived logger should use the module level")
+	require.False(emits(verboseLogger.Debug), "module level should not go below the default level")
+	require.Equal(LevelInfo, GetLevel())
+
+	err = SetLevels(LevelDebug, map[string]Level{
+		"test/quiet": LevelWarn,
+	})
+	require.NoError(err, "SetLevels")
+
+	require.True(emits(earlyLogger.Debug), "existing loggers should use the new default level")
+	require.True(emits(quietLogger.Warn), "existing loggers should use the new module level")
+	require.False(emits(quietLogger.Info), "existing loggers should use the new module level")
+	require.True(emits(withLogger.Warn), "derived loggers should use the new module level")
+	require.True(emits(verboseLogger.Debug), "removed module levels should fall back to the default level")
+	require.Equal(LevelDebug, GetLevel())
+
+	err = SetLevels(LevelInfo, nil)
+	require.NoError(err, "SetLevels")
+	err = SetModuleLevel("", LevelDebug)
+	require.Error(err, "SetModuleLevel should fail without a module")
+	err = SetModuleLevel("test/verbose", LevelDebug)
+	require.NoError(err, "SetModuleLevel")
+	err = SetModuleLevel("test", LevelError)
+	require.NoError(err, "SetModuleLevel")
+
+	require.True(emits(verboseLogger.Debug), "overrides should be able to go below the default level")
+	require.False(emits(earlyLogger.Warn), "the longest override prefix should be used")
+
+	err = SetLevels(LevelWarn, nil)
+	require.NoError(err, "SetLevels")
+	require.True(emits(verboseLogger.Debug), "overrides should persist across SetLevels")
+
+	err = ResetModuleLevel("test/verbose")
+	require.NoError(err, "ResetModuleLevel")
+	require.False(emits(verboseLogger.Debug), "reset overrides should fall back to the configured levels")
+	require.False(emits(verboseLogger.Warn), "reset overrides should fall back to the remaining overrides")
+	err = ResetModuleLevel("test")
+	require.NoError(err, "ResetModuleLevel")
+	require.True(emits(verboseLogger.Warn), "reset overrides should fall back to the configured levels")
+
+	// Entries should use the stable field names.
+	require.Equal(level.Key(), KeyLevel)
+	buf.Reset()
+	verboseLogger.Warn("message")
+	for _, field := range []string{
+		KeyTimestamp + "=",
+		KeyLevel + "=warn",
+		KeyModule + "=test/verbose",
+		KeyCaller + "=logging_test.go:",
+		KeyMessage + "=message",
+	} {
+		require.Contains(buf.String(), field)
+	}
+}
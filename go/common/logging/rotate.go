@@ -0,0 +1,213 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatedSuffixFmt is the time format used as the suffix of rotated log files.
+const rotatedSuffixFmt = "20060102T150405.000000000Z"
+
+var _ io.WriteCloser = (*RotatingFile)(nil)
+
+// RotationConfig is the log file rotation configuration.
+type RotationConfig struct {
+	// MaxSize is the size (in bytes) after which the log file is rotated.
+	// Zero disables size-based rotation.
+	MaxSize int64
+
+	// Interval is the time after which the log file is rotated.
+	// Zero disables time-based rotation.
+	Interval time.Duration
+
+	// MaxBackups is the maximum number of rotated log files to keep.
+	// Zero keeps all rotated log files.
+	MaxBackups int
+}
+
+// Validate validates the log file rotation configuration.
+func (cfg *RotationConfig) Validate() error {
+	if cfg.MaxSize < 0 {
+		return fmt.Errorf("logging: log rotation size must not be negative")
+	}
+	if cfg.Interval < 0 {
+		return fmt.Errorf("logging: log rotation interval must not be negative")
+	}
+	if cfg.MaxBackups < 0 {
+		return fmt.Errorf("logging: number of rotated log files must not be negative")
+	}
+	return nil
+}
+
+// RotatingFile is a log file writer that rotates the underlying file based
+// on its size and age.
+//
+// Rotated files are renamed to the original file name suffixed with the
+// rotation timestamp.
+type RotatingFile struct {
+	sync.Mutex
+
+	path string
+	cfg  RotationConfig
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	now func() time.Time
+}
+
+// Write writes the given data to the log file, rotating it first if needed.
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.Lock()
+	defer rf.Unlock()
+
+	if rf.file == nil {
+		return 0, fmt.Errorf("logging: log file closed")
+	}
+
+	var rotateErr error
+	if rf.shouldRotate(len(p)) {
+		if rotateErr = rf.rotate(); rotateErr != nil && rf.file == nil {
+			return 0, rotateErr
+		}
+	}
+
+	// Even if rotation failed, the entry is still written as long as a log file is open.
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	if err != nil {
+		return n, err
+	}
+	return n, rotateErr
+}
+
+// Close closes the log file.
+func (rf *RotatingFile) Close() error {
+	rf.Lock()
+	defer rf.Unlock()
+
+	if rf.file == nil {
+		return nil
+	}
+	err := rf.file.Close()
+	rf.file = nil
+	return err
+}
+
+func (rf *RotatingFile) shouldRotate(n int) bool {
+	// Never rotate an empty file as that would only produce empty backups.
+	if rf.size == 0 {
+		return false
+	}
+	if rf.cfg.MaxSize > 0 && rf.size+int64(n) > rf.cfg.MaxSize {
+		return true
+	}
+	if rf.cfg.Interval > 0 && rf.now().Sub(rf.openedAt) >= rf.cfg.Interval {
+		return true
+	}
+	return false
+}
+
+func (rf *RotatingFile) open() error {
+	f, err := os.OpenFile(rf.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("logging: failed to open log file: %w", err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("logging: failed to stat log file: %w", err)
+	}
+
+	rf.file = f
+	rf.size = fi.Size()
+	rf.openedAt = rf.now()
+	return nil
+}
+
+func (rf *RotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return fmt.Errorf("logging: failed to close log file: %w", err)
+	}
+	rf.file = nil
+
+	rotatedPath := rf.path + "." + rf.now().UTC().Format(rotatedSuffixFmt)
+	if err := os.Rename(rf.path, rotatedPath); err != nil {
+		// Keep logging to the original file so that a failed rotation does not make all
+		// subsequent writes fail.
+		if openErr := rf.open(); openErr != nil {
+			return fmt.Errorf("logging: failed to rotate log file: %w (reopen failed: %s)", err, openErr)
+		}
+		return fmt.Errorf("logging: failed to rotate log file: %w", err)
+	}
+	if err := rf.open(); err != nil {
+		return err
+	}
+
+	return rf.pruneBackups()
+}
+
+func (rf *RotatingFile) pruneBackups() error {
+	if rf.cfg.MaxBackups == 0 {
+		return nil
+	}
+
+	candidates, err := filepath.Glob(rf.path + ".*")
+	if err != nil {
+		return fmt.Errorf("logging: failed to list rotated log files: %w", err)
+	}
+	// Only consider files with a valid rotation timestamp suffix so that unrelated files
+	// sharing the log file name prefix are never removed.
+	var backups []string
+	for _, path := range candidates {
+		if isRotatedSuffix(strings.TrimPrefix(path, rf.path+".")) {
+			backups = append(backups, path)
+		}
+	}
+	if len(backups) <= rf.cfg.MaxBackups {
+		return nil
+	}
+
+	// The timestamp suffix sorts lexicographically.
+	sort.Strings(backups)
+	for _, path := range backups[:len(backups)-rf.cfg.MaxBackups] {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("logging: failed to remove rotated log file: %w", err)
+		}
+	}
+	return nil
+}
+
+func isRotatedSuffix(suffix string) bool {
+	ts, err := time.Parse(rotatedSuffixFmt, suffix)
+	if err != nil {
+		return false
+	}
+	// Require the canonical encoding as otherwise the backups would not sort correctly.
+	return ts.UTC().Format(rotatedSuffixFmt) == suffix
+}
+
+// NewRotatingFile opens the log file at the given path (creating it if it
+// does not exist) that is rotated according to the given configuration.
+func NewRotatingFile(path string, cfg RotationConfig) (*RotatingFile, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	rf := &RotatingFile{
+		path: path,
+		cfg:  cfg,
+		now:  time.Now,
+	}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
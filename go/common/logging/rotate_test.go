@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "oasis-logging-test_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "node.log")
+
+	_, err = NewRotatingFile(path, RotationConfig{MaxSize: -1})
+	require.Error(err, "NewRotatingFile should fail with invalid configuration")
+
+	rf, err := NewRotatingFile(path, RotationConfig{
+		MaxSize:    16,
+		Interval:   time.Hour,
+		MaxBackups: 2,
+	})
+	require.NoError(err, "NewRotatingFile")
+	defer rf.Close()
+
+	now := time.Now()
+	rf.now = func() time.Time { return now }
+
+	backups := func() []string {
+		matches, grr := filepath.Glob(path + ".*")
+		require.NoError(grr, "Glob")
+		return matches
+	}
+
+	_, err = rf.Write([]byte("0123456789"))
+	require.NoError(err, "Write")
+	require.Empty(backups(), "log file should not be rotated below the size limit")
+
+	// Size-based rotation.
+	_, err = rf.Write([]byte("0123456789"))
+	require.NoError(err, "Write")
+	require.Len(backups(), 1, "log file should be rotated after reaching the size limit")
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(err, "ReadFile")
+	require.EqualValues("0123456789", data, "log file should only contain the new entry")
+
+	// Time-based rotation.
+	now = now.Add(time.Hour)
+	_, err = rf.Write([]byte("a"))
+	require.NoError(err, "Write")
+	require.Len(backups(), 2, "log file should be rotated after the rotation interval")
+
+	// Old backups should be pruned.
+	now = now.Add(time.Hour)
+	_, err = rf.Write([]byte("b"))
+	require.NoError(err, "Write")
+	require.Len(backups(), 2, "old rotated log files should be removed")
+
+	// Files not created by rotation should never be pruned.
+	unrelated := []string{path + ".keep", path + ".20210101T000000Z"}
+	for _, p := range unrelated {
+		err = ioutil.WriteFile(p, []byte("keep"), 0o600)
+		require.NoError(err, "WriteFile")
+	}
+	now = now.Add(time.Hour)
+	_, err = rf.Write([]byte("c"))
+	require.NoError(err, "Write")
+	require.Len(backups(), 4, "only rotated log files should be removed")
+	for _, p := range unrelated {
+		require.FileExists(p, "unrelated files should not be removed")
+	}
+
+	// Failed rotation should keep writing to the original log file.
+	now = now.Add(time.Hour)
+	blocker := path + "." + now.UTC().Format(rotatedSuffixFmt)
+	err = os.MkdirAll(filepath.Join(blocker, "x"), 0o700)
+	require.NoError(err, "MkdirAll")
+	_, err = rf.Write([]byte("d"))
+	require.Error(err, "Write should report the failed rotation")
+	err = os.RemoveAll(blocker)
+	require.NoError(err, "RemoveAll")
+	data, err = ioutil.ReadFile(path)
+	require.NoError(err, "ReadFile")
+	require.EqualValues("cd", data, "entry should be written to the original log file")
+	now = now.Add(time.Hour)
+	_, err = rf.Write([]byte("e"))
+	require.NoError(err, "Write should succeed after a failed rotation")
+
+	err = rf.Close()
+	require.NoError(err, "Close")
+	_, err = rf.Write([]byte("f"))
+	require.Error(err, "Write should fail after Close")
+}
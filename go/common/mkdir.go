@@ -6,11 +6,11 @@ import (
 	"syscall"
 )
 
+const permDir = os.FileMode(0o700)
+
 // Mkdir creates a directory iff it does not exist, and otherwise
 // ensures that the filesystem permissions are sufficiently restrictive.
 func Mkdir(d string) error {
-	const permDir = os.FileMode(0o700)
-
 	fi, err := os.Lstat(d)
 	if err != nil {
 		// Iff the directory does not exist, create it.
@@ -22,6 +22,21 @@ func Mkdir(d string) error {
 		return err
 	}
 
+	return checkDir(d, fi)
+}
+
+// CheckDir ensures that an existing directory has sufficiently restrictive
+// filesystem permissions, without modifying anything.
+func CheckDir(d string) error {
+	fi, err := os.Lstat(d)
+	if err != nil {
+		return err
+	}
+
+	return checkDir(d, fi)
+}
+
+func checkDir(d string, fi os.FileInfo) error {
 	// Ensure that the existing path is a directory, with sufficiently
 	// restrictive permissions.
 	fm := fi.Mode()
@@ -19,6 +19,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/pcs"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 )
 
@@ -440,9 +441,129 @@ type CapabilityTEE struct {
 	RAK signature.PublicKey `json:"rak"`
 
 	// Attestation.
+	//
+	// For Intel SGX this is either a CBOR-serialized SGXAttestation or, for backwards
+	// compatibility, a CBOR-serialized IAS AVR bundle.
 	Attestation []byte `json:"attestation"`
 }
 
+// LatestSGXAttestationVersion is the latest SGX attestation structure version that should be used
+// for all new attestations.
+const LatestSGXAttestationVersion = 1
+
+// SGXAttestation is an Intel SGX remote attestation.
+type SGXAttestation struct {
+	cbor.Versioned
+
+	// IAS is an EPID-based attestation verification report produced by the Intel Attestation
+	// Service, bundled with its signature.
+	IAS *ias.AVRBundle `json:"ias,omitempty"`
+
+	// PCS is a DCAP-based ECDSA quote, bundled with the collateral obtained from the Intel
+	// Provisioning Certification Service (or a compatible caching service).
+	PCS *pcs.QuoteBundle `json:"pcs,omitempty"`
+}
+
+// ValidateBasic performs basic structure validity checks.
+func (sa *SGXAttestation) ValidateBasic() error {
+	if sa.V != LatestSGXAttestationVersion {
+		return fmt.Errorf("node: unsupported SGX attestation version: %d", sa.V)
+	}
+	if (sa.IAS == nil) == (sa.PCS == nil) {
+		return fmt.Errorf("node: exactly one SGX attestation kind must be specified")
+	}
+	return nil
+}
+
+// TEEFeatures are the TEE features enabled by the consensus layer.
+type TEEFeatures struct {
+	// SGX contains the enabled Intel SGX features.
+	SGX TEEFeaturesSGX `json:"sgx"`
+}
+
+// TEEFeaturesSGX are the enabled Intel SGX features.
+type TEEFeaturesSGX struct {
+	// PCS is true iff DCAP-based (ECDSA) attestations verified against the collateral obtained
+	// from the Intel PCS are accepted.
+	//
+	// When disabled, only legacy IAS AVR bundles are accepted.
+	PCS bool `json:"pcs,omitempty"`
+
+	// Policy is true iff the network-wide and per-runtime Intel SGX attestation acceptance
+	// policies are supported.
+	//
+	// When disabled, no policies may be configured and debug enclaves are only restricted by
+	// the node-local configuration.
+	Policy bool `json:"policy,omitempty"`
+}
+
+// pcsEnabled returns true iff DCAP-based attestations are enabled.
+func (f *TEEFeatures) pcsEnabled() bool {
+	return f != nil && f.SGX.PCS
+}
+
+// policyEnabled returns true iff attestation acceptance policies are enabled.
+func (f *TEEFeatures) policyEnabled() bool {
+	return f != nil && f.SGX.Policy
+}
+
+// SGXAttestation decodes the Intel SGX attestation, accepting only the formats enabled by the
+// given TEE features.
+func (c *CapabilityTEE) SGXAttestation(teeFeatures *TEEFeatures) (*SGXAttestation, error) {
+	if c.Hardware != TEEHardwareIntelSGX {
+		return nil, ErrInvalidTEEHardware
+	}
+
+	if teeFeatures.pcsEnabled() {
+		var sa SGXAttestation
+		if err := cbor.Unmarshal(c.Attestation, &sa); err == nil {
+			if err = sa.ValidateBasic(); err != nil {
+				return nil, err
+			}
+			return &sa, nil
+		}
+	}
+
+	// Fall back to the legacy format which only supports IAS AVR bundles.
+	var avrBundle ias.AVRBundle
+	if err := cbor.Unmarshal(c.Attestation, &avrBundle); err != nil {
+		return nil, err
+	}
+	return &SGXAttestation{
+		Versioned: cbor.NewVersioned(LatestSGXAttestationVersion),
+		IAS:       &avrBundle,
+	}, nil
+}
+
+// SGXPolicy is the Intel SGX attestation acceptance policy.
+type SGXPolicy struct {
+	// AllowedQuoteStatuses are the allowed IAS quote statuses.
+	//
+	// Note: QuoteOK is ALWAYS allowed, and does not need to be specified.
+	AllowedQuoteStatuses []ias.ISVEnclaveQuoteStatus `json:"allowed_quote_statuses,omitempty"`
+
+	// AllowedTCBStatuses are the allowed TCB statuses of DCAP-based attestations.
+	//
+	// Note: TCBUpToDate is ALWAYS allowed, and does not need to be specified.
+	AllowedTCBStatuses []pcs.TCBStatus `json:"allowed_tcb_statuses,omitempty"`
+
+	// AllowDebugEnclaves specifies whether enclaves with the debug attribute set are allowed.
+	//
+	// Note: Debug enclaves are additionally only accepted when the node verifying the
+	// attestation is configured to allow debug enclaves.
+	AllowDebugEnclaves bool `json:"allow_debug_enclaves,omitempty"`
+}
+
+// ValidateBasic performs basic policy validity checks.
+func (p *SGXPolicy) ValidateBasic() error {
+	for _, status := range p.AllowedTCBStatuses {
+		if status == pcs.TCBRevoked {
+			return fmt.Errorf("node: revoked TCB status not allowed")
+		}
+	}
+	return nil
+}
+
 // SGXConstraints are the Intel SGX TEE constraints.
 type SGXConstraints struct {
 	// Enclaves is the allowed MRENCLAVE/MRSIGNER pairs.
@@ -453,9 +574,46 @@ type SGXConstraints struct {
 	//
 	// Note: QuoteOK is ALWAYS allowed, and does not need to be specified.
 	AllowedQuoteStatuses []ias.ISVEnclaveQuoteStatus `json:"allowed_quote_statuses,omitempty"`
+
+	// AllowedTCBStatuses are the allowed TCB statuses of DCAP-based
+	// attestations for the node to be scheduled as a compute worker.
+	//
+	// Note: TCBUpToDate is ALWAYS allowed, and does not need to be specified.
+	AllowedTCBStatuses []pcs.TCBStatus `json:"allowed_tcb_statuses,omitempty"`
+
+	// Policy is the runtime-specific attestation acceptance policy which overrides the
+	// network-wide default policy when set. It may only be set when attestation acceptance
+	// policies are enabled by the consensus layer.
+	//
+	// The statuses allowed by AllowedQuoteStatuses and AllowedTCBStatuses are accepted in
+	// addition to the ones allowed by the effective policy.
+	Policy *SGXPolicy `json:"policy,omitempty"`
 }
 
-func (constraints *SGXConstraints) quoteStatusAllowed(avr *ias.AttestationVerificationReport) bool {
+// ValidateBasic performs basic constraints validity checks.
+func (constraints *SGXConstraints) ValidateBasic() error {
+	for _, status := range constraints.AllowedTCBStatuses {
+		if status == pcs.TCBRevoked {
+			return fmt.Errorf("node: revoked TCB status not allowed")
+		}
+	}
+	if constraints.Policy != nil {
+		if err := constraints.Policy.ValidateBasic(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// effectivePolicy returns the policy that applies under the given network-wide default policy.
+func (constraints *SGXConstraints) effectivePolicy(defaultPolicy *SGXPolicy) *SGXPolicy {
+	if constraints.Policy != nil {
+		return constraints.Policy
+	}
+	return defaultPolicy
+}
+
+func (constraints *SGXConstraints) quoteStatusAllowed(policy *SGXPolicy, avr *ias.AttestationVerificationReport) bool {
 	status := avr.ISVEnclaveQuoteStatus
 
 	// Always allow "OK".
@@ -463,9 +621,36 @@ func (constraints *SGXConstraints) quoteStatusAllowed(avr *ias.AttestationVerifi
 		return true
 	}
 
-	// Search through the constraints to see if the AVR quote status is
-	// explicitly allowed.
-	for _, v := range constraints.AllowedQuoteStatuses {
+	// Search through the constraints and the policy to see if the AVR
+	// quote status is explicitly allowed.
+	allowed := constraints.AllowedQuoteStatuses
+	if policy != nil {
+		allowed = append(append([]ias.ISVEnclaveQuoteStatus{}, allowed...), policy.AllowedQuoteStatuses...)
+	}
+	for _, v := range allowed {
+		if v == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (constraints *SGXConstraints) tcbStatusAllowed(policy *SGXPolicy, quote *pcs.VerifiedQuote) bool {
+	status := quote.TCBStatus
+
+	// Always allow "UpToDate".
+	if status == pcs.TCBUpToDate {
+		return true
+	}
+
+	// Search through the constraints and the policy to see if the TCB
+	// status is explicitly allowed.
+	allowed := constraints.AllowedTCBStatuses
+	if policy != nil {
+		allowed = append(append([]pcs.TCBStatus{}, allowed...), policy.AllowedTCBStatuses...)
+	}
+	for _, v := range allowed {
 		if v == status {
 			return true
 		}
@@ -474,6 +659,14 @@ func (constraints *SGXConstraints) quoteStatusAllowed(avr *ias.AttestationVerifi
 	return false
 }
 
+func (constraints *SGXConstraints) debugAllowed(policy *SGXPolicy, report *ias.Report) bool {
+	// Debug enclaves are only allowed when explicitly permitted by the policy.
+	if policy != nil && policy.AllowDebugEnclaves {
+		return true
+	}
+	return !report.Attributes.Flags.Contains(sgx.AttributeDebug)
+}
+
 // RAKHash computes the expected AVR report hash bound to a given public RAK.
 func RAKHash(rak signature.PublicKey) hash.Hash {
 	hData := make([]byte, 0, len(teeHashContext)+signature.PublicKeySize)
@@ -483,38 +676,64 @@ func RAKHash(rak signature.PublicKey) hash.Hash {
 }
 
 // Verify verifies the node's TEE capabilities, at the provided timestamp.
-func (c *CapabilityTEE) Verify(ts time.Time, constraints []byte) error {
+//
+// The given TEE features are the features enabled by the consensus layer. The given SGX policy is
+// the network-wide default attestation acceptance policy which is used unless the constraints
+// specify their own policy. Policies are only enforced when enabled by the TEE features.
+func (c *CapabilityTEE) Verify(teeFeatures *TEEFeatures, ts time.Time, constraints []byte, sgxPolicy *SGXPolicy) error {
 	rakHash := RAKHash(c.RAK)
 
 	switch c.Hardware {
 	case TEEHardwareIntelSGX:
-		var avrBundle ias.AVRBundle
-		if err := cbor.Unmarshal(c.Attestation, &avrBundle); err != nil {
+		sa, err := c.SGXAttestation(teeFeatures)
+		if err != nil {
 			return err
 		}
 
-		avr, err := avrBundle.Open(ias.IntelTrustRoots, ts)
-		if err != nil {
-			return err
+		var cs SGXConstraints
+		if err = cbor.Unmarshal(constraints, &cs); err != nil {
+			return fmt.Errorf("node: malformed SGX constraints: %w", err)
+		}
+		var policy *SGXPolicy
+		if teeFeatures.policyEnabled() {
+			policy = cs.effectivePolicy(sgxPolicy)
 		}
 
-		// Extract the original ISV quote.
-		q, err := avr.Quote()
-		if err != nil {
-			return err
+		// Verify the attestation and extract the attested enclave report.
+		var (
+			report        *ias.Report
+			statusAllowed bool
+		)
+		switch {
+		case sa.IAS != nil:
+			avr, err := sa.IAS.Open(ias.IntelTrustRoots, ts)
+			if err != nil {
+				return err
+			}
+
+			// Extract the original ISV quote.
+			q, err := avr.Quote()
+			if err != nil {
+				return err
+			}
+			report = &q.Report
+			statusAllowed = cs.quoteStatusAllowed(policy, avr)
+		case sa.PCS != nil:
+			quote, err := sa.PCS.Verify(pcs.IntelTrustRoots, ts)
+			if err != nil {
+				return err
+			}
+			report = &quote.ISVReport
+			statusAllowed = cs.tcbStatusAllowed(policy, quote)
 		}
 
 		// Ensure that the MRENCLAVE/MRSIGNER match what is specified
 		// in the TEE-specific constraints field.
-		var cs SGXConstraints
-		if err := cbor.Unmarshal(constraints, &cs); err != nil {
-			return fmt.Errorf("node: malformed SGX constraints: %w", err)
-		}
 		var eidValid bool
 		for _, eid := range cs.Enclaves {
 			eidMrenclave := eid.MrEnclave
 			eidMrsigner := eid.MrSigner
-			if bytes.Equal(eidMrenclave[:], q.Report.MRENCLAVE[:]) && bytes.Equal(eidMrsigner[:], q.Report.MRSIGNER[:]) {
+			if bytes.Equal(eidMrenclave[:], report.MRENCLAVE[:]) && bytes.Equal(eidMrsigner[:], report.MRSIGNER[:]) {
 				eidValid = true
 				break
 			}
@@ -526,13 +745,20 @@ func (c *CapabilityTEE) Verify(ts time.Time, constraints []byte) error {
 		// Ensure that the ISV quote includes the hash of the node's
 		// RAK.
 		var avrRAKHash hash.Hash
-		_ = avrRAKHash.UnmarshalBinary(q.Report.ReportData[:hash.Size])
+		_ = avrRAKHash.UnmarshalBinary(report.ReportData[:hash.Size])
 		if !rakHash.Equal(&avrRAKHash) {
 			return ErrRAKHashMismatch
 		}
 
 		// Ensure that the quote status is acceptable.
-		if !cs.quoteStatusAllowed(avr) {
+		if !statusAllowed {
+			return ErrConstraintViolation
+		}
+
+		// Ensure that debug enclaves are only accepted if allowed. When policies are disabled
+		// this is governed by the node-local configuration which has already been enforced
+		// when verifying the report.
+		if teeFeatures.policyEnabled() && !cs.debugAllowed(policy, report) {
 			return ErrConstraintViolation
 		}
 
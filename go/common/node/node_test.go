@@ -7,6 +7,9 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/pcs"
 )
 
 func TestRolesMask(t *testing.T) {
@@ -114,3 +117,109 @@ func TestNodeDescriptor(t *testing.T) {
 	require.NoError(err, "deserialize descriptor")
 	require.EqualValues(n, n2, "s11n roundtrip")
 }
+
+func TestSGXAttestation(t *testing.T) {
+	require := require.New(t)
+
+	// Legacy IAS AVR bundle.
+	avrBundle := ias.AVRBundle{
+		Body:      []byte("body"),
+		Signature: []byte("signature"),
+	}
+	tee := CapabilityTEE{
+		Hardware:    TEEHardwareIntelSGX,
+		Attestation: cbor.Marshal(avrBundle),
+	}
+	pcsEnabled := &TEEFeatures{SGX: TEEFeaturesSGX{PCS: true}}
+	for _, teeFeatures := range []*TEEFeatures{nil, {}, pcsEnabled} {
+		sa, err := tee.SGXAttestation(teeFeatures)
+		require.NoError(err, "SGXAttestation should decode legacy AVR bundles")
+		require.EqualValues(LatestSGXAttestationVersion, sa.V)
+		require.Nil(sa.PCS)
+		require.EqualValues(&avrBundle, sa.IAS)
+	}
+
+	// Versioned PCS attestation.
+	pcsAttestation := SGXAttestation{
+		Versioned: cbor.NewVersioned(LatestSGXAttestationVersion),
+		PCS: &pcs.QuoteBundle{
+			Quote: []byte("quote"),
+		},
+	}
+	tee.Attestation = cbor.Marshal(pcsAttestation)
+	_, err := tee.SGXAttestation(nil)
+	require.Error(err, "SGXAttestation should fail for PCS attestations when not enabled")
+	_, err = tee.SGXAttestation(&TEEFeatures{})
+	require.Error(err, "SGXAttestation should fail for PCS attestations when not enabled")
+	sa, err := tee.SGXAttestation(pcsEnabled)
+	require.NoError(err, "SGXAttestation")
+	require.Nil(sa.IAS)
+	require.EqualValues([]byte("quote"), sa.PCS.Quote)
+
+	// Exactly one attestation kind must be specified.
+	tee.Attestation = cbor.Marshal(SGXAttestation{
+		Versioned: cbor.NewVersioned(LatestSGXAttestationVersion),
+	})
+	_, err = tee.SGXAttestation(pcsEnabled)
+	require.Error(err, "SGXAttestation should fail without any attestation")
+
+	// Unsupported versions should be rejected.
+	pcsAttestation.V = LatestSGXAttestationVersion + 1
+	tee.Attestation = cbor.Marshal(pcsAttestation)
+	_, err = tee.SGXAttestation(pcsEnabled)
+	require.Error(err, "SGXAttestation should fail with unsupported version")
+
+	// Non-SGX hardware.
+	tee.Hardware = TEEHardwareInvalid
+	_, err = tee.SGXAttestation(pcsEnabled)
+	require.ErrorIs(err, ErrInvalidTEEHardware)
+}
+
+func TestSGXPolicy(t *testing.T) {
+	require := require.New(t)
+
+	avr := &ias.AttestationVerificationReport{ISVEnclaveQuoteStatus: ias.QuoteSwHardeningNeeded}
+	quote := &pcs.VerifiedQuote{TCBStatus: pcs.TCBSwHardeningNeeded}
+	var debugReport ias.Report
+	debugReport.Attributes.Flags = sgx.AttributeDebug
+
+	// No constraints and no policy.
+	var cs SGXConstraints
+	policy := cs.effectivePolicy(nil)
+	require.Nil(policy)
+	require.False(cs.quoteStatusAllowed(policy, avr))
+	require.False(cs.tcbStatusAllowed(policy, quote))
+	require.False(cs.debugAllowed(policy, &debugReport), "debug enclaves should not be allowed without a policy")
+	require.True(cs.debugAllowed(policy, &ias.Report{}))
+
+	// Default policy.
+	defaultPolicy := &SGXPolicy{
+		AllowedQuoteStatuses: []ias.ISVEnclaveQuoteStatus{ias.QuoteSwHardeningNeeded},
+		AllowedTCBStatuses:   []pcs.TCBStatus{pcs.TCBSwHardeningNeeded},
+	}
+	policy = cs.effectivePolicy(defaultPolicy)
+	require.Equal(defaultPolicy, policy)
+	require.True(cs.quoteStatusAllowed(policy, avr))
+	require.True(cs.tcbStatusAllowed(policy, quote))
+	require.False(cs.debugAllowed(policy, &debugReport))
+	require.True(cs.debugAllowed(policy, &ias.Report{}))
+
+	// Per-runtime policy overrides the default policy.
+	cs.Policy = &SGXPolicy{AllowDebugEnclaves: true}
+	policy = cs.effectivePolicy(defaultPolicy)
+	require.Equal(cs.Policy, policy)
+	require.False(cs.quoteStatusAllowed(policy, avr))
+	require.False(cs.tcbStatusAllowed(policy, quote))
+	require.True(cs.debugAllowed(policy, &debugReport))
+
+	// Statuses allowed by the constraints are always allowed.
+	cs.AllowedQuoteStatuses = []ias.ISVEnclaveQuoteStatus{ias.QuoteSwHardeningNeeded}
+	cs.AllowedTCBStatuses = []pcs.TCBStatus{pcs.TCBSwHardeningNeeded}
+	require.True(cs.quoteStatusAllowed(policy, avr))
+	require.True(cs.tcbStatusAllowed(policy, quote))
+
+	// Revoked TCB statuses are never allowed.
+	require.NoError(cs.ValidateBasic())
+	cs.Policy.AllowedTCBStatuses = []pcs.TCBStatus{pcs.TCBRevoked}
+	require.Error(cs.ValidateBasic())
+}
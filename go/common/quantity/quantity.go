@@ -3,7 +3,9 @@ package quantity
 import (
 	"encoding"
 	"errors"
+	"fmt"
 	"math/big"
+	"strings"
 )
 
 var (
@@ -197,6 +199,72 @@ func (q Quantity) String() string {
 	return tmp.String()
 }
 
+// FormatDecimal returns the decimal representation of q divided by 10**exp,
+// e.g., the token amount corresponding to an amount in base units for the
+// given token's value base-10 exponent.
+//
+// The fractional part is always present, but without trailing zeros (e.g.,
+// "10.5" or "10.0").
+func (q Quantity) FormatDecimal(exp uint8) string {
+	denominator := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)
+
+	// NOTE: We use DivMod() and manual string construction to avoid conversion
+	// to other types and support arbitrarily large amounts.
+	quotient, remainder := new(big.Int).DivMod(q.ToBigInt(), denominator, new(big.Int))
+
+	// Prefix the remainder with the appropriate number of zeros and trim
+	// trailing zeros, ensuring the remainder is not empty.
+	remainderStr := strings.TrimRight(fmt.Sprintf("%0*s", exp, remainder), "0")
+	if remainderStr == "" {
+		remainderStr = "0"
+	}
+
+	return fmt.Sprintf("%s.%s", quotient, remainderStr)
+}
+
+// ParseDecimal parses the decimal representation of a value (e.g., "10.5")
+// and returns the value multiplied by 10**exp, e.g., the amount in base units
+// corresponding to a token amount for the given token's value base-10
+// exponent.
+//
+// An error is returned in case the value has more fractional digits than exp
+// as it cannot be represented exactly.
+func ParseDecimal(text string, exp uint8) (*Quantity, error) {
+	intPart, fracPart := text, ""
+	if idx := strings.IndexByte(text, '.'); idx >= 0 {
+		intPart, fracPart = text[:idx], text[idx+1:]
+	}
+	if intPart == "" && fracPart == "" {
+		return nil, fmt.Errorf("%w: malformed decimal value '%s'", ErrInvalidQuantity, text)
+	}
+	for _, part := range []string{intPart, fracPart} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return nil, fmt.Errorf("%w: malformed decimal value '%s'", ErrInvalidQuantity, text)
+			}
+		}
+	}
+
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > int(exp) {
+		return nil, fmt.Errorf("%w: decimal value '%s' has more than %d fractional digits", ErrInvalidQuantity, text, exp)
+	}
+
+	// Scale the value by concatenating the integer and the (zero-padded)
+	// fractional part.
+	digits := intPart + fracPart + strings.Repeat("0", int(exp)-len(fracPart))
+	var tmp big.Int
+	if _, ok := tmp.SetString(digits, 10); !ok {
+		return nil, fmt.Errorf("%w: malformed decimal value '%s'", ErrInvalidQuantity, text)
+	}
+
+	var q Quantity
+	if err := q.FromBigInt(&tmp); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
 // IsValid returns true iff the quantity is in the valid range.
 func (q *Quantity) IsValid() bool {
 	return isValid(&q.inner)
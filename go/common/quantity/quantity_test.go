@@ -279,3 +279,58 @@ func TestMoveUpTo(t *testing.T) {
 	require.True(src.eqInt(0), "MoveUpTo, oversized - src value")
 	require.True(moved.eqInt(225), "MoveUpTo, oversized - moved")
 }
+
+func TestQuantityDecimal(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		decimal string
+		value   uint64
+		exp     uint8
+	}{
+		{"10.5", 10500000000, 9},
+		{"10.0", 10000000000, 9},
+		{"0.000000001", 1, 9},
+		{"0.0", 0, 9},
+		{"42.0", 42, 0},
+		{"0.010000000000000000001", 10000000000000000001, 21},
+	} {
+		q := NewFromUint64(tc.value)
+		require.Equal(tc.decimal, q.FormatDecimal(tc.exp), "FormatDecimal(%d)", tc.exp)
+
+		parsed, err := ParseDecimal(tc.decimal, tc.exp)
+		require.NoError(err, "ParseDecimal(%s)", tc.decimal)
+		require.Zero(q.Cmp(parsed), "ParseDecimal(%s)", tc.decimal)
+	}
+
+	for _, tc := range []struct {
+		decimal string
+		value   uint64
+		exp     uint8
+	}{
+		{"10", 10000, 3},
+		{"10.", 10000, 3},
+		{".5", 500, 3},
+		{"1.500", 1500, 3},
+		{"007.25", 7250, 3},
+	} {
+		parsed, err := ParseDecimal(tc.decimal, tc.exp)
+		require.NoError(err, "ParseDecimal(%s)", tc.decimal)
+		require.True(parsed.eqInt(int(tc.value)), "ParseDecimal(%s)", tc.decimal)
+	}
+
+	for _, invalid := range []string{
+		"",
+		".",
+		"-1",
+		"+1",
+		"1.2.3",
+		"1,5",
+		"1e3",
+		"0.0001",
+		" 1",
+	} {
+		_, err := ParseDecimal(invalid, 3)
+		require.ErrorIs(err, ErrInvalidQuantity, "ParseDecimal(%s)", invalid)
+	}
+}
@@ -194,6 +194,23 @@ func (b *AVRBundle) Open(trustRoots *x509.CertPool, ts time.Time) (*AttestationV
 	return DecodeAVR(b.Body, b.Signature, b.CertificateChain, trustRoots, ts)
 }
 
+// NotAfter returns the time after which the AVR contained in the bundle can no
+// longer be verified as its signing certificate chain will have expired.
+func (b *AVRBundle) NotAfter() (time.Time, error) {
+	certs, err := decodeAVRCertChain(b.CertificateChain)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	notAfter := certs[0].NotAfter
+	for _, cert := range certs[1:] {
+		if cert.NotAfter.Before(notAfter) {
+			notAfter = cert.NotAfter
+		}
+	}
+	return notAfter, nil
+}
+
 // AttestationVerificationReport is a deserialized Attestation Verification
 // Report (AVR).
 type AttestationVerificationReport struct {
@@ -347,10 +364,10 @@ func DecodeAVR(data, encodedSignature, encodedCertChain []byte, trustRoots *x509
 	return a, nil
 }
 
-func validateAVRSignature(data, encodedSignature, encodedCertChain []byte, trustRoots *x509.CertPool, ts time.Time) error {
+func decodeAVRCertChain(encodedCertChain []byte) ([]*x509.Certificate, error) {
 	decoded, err := url.QueryUnescape(string(encodedCertChain))
 	if err != nil {
-		return fmt.Errorf("ias/avr: failed to decode certificate chain: %w", err)
+		return nil, fmt.Errorf("ias/avr: failed to decode certificate chain: %w", err)
 	}
 	pemCerts := []byte(decoded)
 
@@ -359,7 +376,7 @@ func validateAVRSignature(data, encodedSignature, encodedCertChain []byte, trust
 		var cert *x509.Certificate
 		cert, pemCerts, err = CertFromPEM(pemCerts)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if cert == nil {
 			break
@@ -367,7 +384,15 @@ func validateAVRSignature(data, encodedSignature, encodedCertChain []byte, trust
 		certs = append(certs, cert)
 	}
 	if len(certs) != 2 {
-		return fmt.Errorf("ias/avr: unexpected certificate chain length: %d", len(certs))
+		return nil, fmt.Errorf("ias/avr: unexpected certificate chain length: %d", len(certs))
+	}
+	return certs, nil
+}
+
+func validateAVRSignature(data, encodedSignature, encodedCertChain []byte, trustRoots *x509.CertPool, ts time.Time) error {
+	certs, err := decodeAVRCertChain(encodedCertChain)
+	if err != nil {
+		return err
 	}
 
 	signingCert, rootCert := certs[0], certs[1]
@@ -39,6 +39,14 @@ func testAVRv4(t *testing.T) {
 
 	require.Equal(t, avr.AdvisoryURL, "https://security-center.intel.com", "advisoryURL")
 	require.EqualValues(t, avr.AdvisoryIDs, []string{"INTEL-SA-00334"}, "advisoryIDs")
+
+	bundle := AVRBundle{Body: raw, CertificateChain: certs, Signature: sig}
+	notAfter, err := bundle.NotAfter()
+	require.NoError(t, err, "NotAfter")
+	_, err = bundle.Open(IntelTrustRoots, notAfter)
+	require.NoError(t, err, "Open should succeed at NotAfter")
+	_, err = bundle.Open(IntelTrustRoots, notAfter.Add(time.Second))
+	require.Error(t, err, "Open should fail after NotAfter")
 }
 
 func loadAVRv4(t *testing.T) (raw, sig, certs []byte) {
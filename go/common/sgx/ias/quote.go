@@ -157,20 +157,20 @@ type Quote struct {
 	Report Report
 }
 
-// Verify checks the quote for validity.
-func (q *Quote) Verify() error {
-	if mrSignerBlacklist[q.Report.MRSIGNER] {
+// Verify checks the report for validity.
+func (r *Report) Verify() error {
+	if mrSignerBlacklist[r.MRSIGNER] {
 		return fmt.Errorf("ias/quote: blacklisted MRSIGNER")
 	}
 
 	if !unsafeAllowDebugEnclaves {
 		// Disallow debug enclaves, if we are in production mode.
-		if q.Report.Attributes.Flags.Contains(sgx.AttributeDebug) {
+		if r.Attributes.Flags.Contains(sgx.AttributeDebug) {
 			return fmt.Errorf("ias/avr: disallowed debug enclave since we are in production mode")
 		}
 	} else {
 		// Disallow non-debug enclaves, if we are in debug mode.
-		if !q.Report.Attributes.Flags.Contains(sgx.AttributeDebug) {
+		if !r.Attributes.Flags.Contains(sgx.AttributeDebug) {
 			return fmt.Errorf("ias/avr: disallowed production enclave since we are in debug mode")
 		}
 	}
@@ -178,6 +178,11 @@ func (q *Quote) Verify() error {
 	return nil
 }
 
+// Verify checks the quote for validity.
+func (q *Quote) Verify() error {
+	return q.Report.Verify()
+}
+
 // MarshalBinary encodes an enclave quote.
 func (q *Quote) MarshalBinary() ([]byte, error) {
 	bQuote := []byte{}
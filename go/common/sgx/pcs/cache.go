@@ -0,0 +1,182 @@
+package pcs
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+// CacheConfig is the attestation collateral cache configuration.
+type CacheConfig struct {
+	// RefreshInterval is the interval after which cached collateral is refreshed from the
+	// upstream service.
+	RefreshInterval time.Duration
+
+	// OfflineGracePeriod is the period after the refresh interval during which the cached
+	// collateral is still served in case the upstream service is unavailable.
+	OfflineGracePeriod time.Duration
+}
+
+// Validate validates the cache configuration.
+func (cfg *CacheConfig) Validate() error {
+	if cfg.RefreshInterval <= 0 {
+		return fmt.Errorf("pcs/cache: refresh interval must be positive")
+	}
+	if cfg.OfflineGracePeriod < 0 {
+		return fmt.Errorf("pcs/cache: offline grace period must not be negative")
+	}
+	return nil
+}
+
+const cacheFetchTimeout = httpClientTimeout
+
+type cacheEntry struct {
+	value     interface{}
+	certs     []byte
+	fetchedAt time.Time
+}
+
+type cachingClient struct {
+	sync.Mutex
+
+	upstream Client
+	cfg      CacheConfig
+
+	// fetches deduplicates concurrent upstream fetches of the same collateral.
+	fetches singleflight.Group
+	entries map[string]*cacheEntry
+
+	now    func() time.Time
+	logger *logging.Logger
+}
+
+// get returns the cached entry under the given key in case it is fresh and otherwise fetches it
+// from the upstream service, falling back to the stale entry during the offline grace period.
+//
+// Concurrent fetches of the same key are coalesced into a single upstream request, which is
+// performed with a context detached from the callers so that a cancelled caller does not fail the
+// fetch for all the others.
+func (cc *cachingClient) get(ctx context.Context, key string, fetch func(context.Context) (interface{}, []byte, error)) (*cacheEntry, error) {
+	cc.Lock()
+	entry := cc.entries[key]
+	cc.Unlock()
+	if entry != nil && cc.now().Sub(entry.fetchedAt) < cc.cfg.RefreshInterval {
+		return entry, nil
+	}
+
+	ch := cc.fetches.DoChan(key, func() (interface{}, error) {
+		fetchCtx, cancel := context.WithTimeout(context.Background(), cacheFetchTimeout)
+		defer cancel()
+
+		value, certs, err := fetch(fetchCtx)
+		now := cc.now()
+
+		cc.Lock()
+		defer cc.Unlock()
+
+		entry := cc.entries[key]
+		if err == nil {
+			entry = &cacheEntry{
+				value:     value,
+				certs:     certs,
+				fetchedAt: now,
+			}
+			cc.entries[key] = entry
+			return entry, nil
+		}
+
+		if entry == nil || now.Sub(entry.fetchedAt) >= cc.cfg.RefreshInterval+cc.cfg.OfflineGracePeriod {
+			return nil, err
+		}
+
+		cc.logger.Warn("failed to refresh attestation collateral, serving cached collateral",
+			"err", err,
+			"key", key,
+			"fetched_at", entry.fetchedAt,
+		)
+		return entry, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*cacheEntry), nil
+	}
+}
+
+func (cc *cachingClient) GetTCBInfo(ctx context.Context, fmspc []byte) (*SignedTCBInfo, []byte, error) {
+	key := "tcb/" + hex.EncodeToString(fmspc)
+	entry, err := cc.get(ctx, key, func(ctx context.Context) (interface{}, []byte, error) {
+		return cc.upstream.GetTCBInfo(ctx, fmspc)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return entry.value.(*SignedTCBInfo), entry.certs, nil
+}
+
+func (cc *cachingClient) GetQEIdentity(ctx context.Context) (*SignedQEIdentity, []byte, error) {
+	entry, err := cc.get(ctx, "qe", func(ctx context.Context) (interface{}, []byte, error) {
+		return cc.upstream.GetQEIdentity(ctx)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return entry.value.(*SignedQEIdentity), entry.certs, nil
+}
+
+func (cc *cachingClient) GetPCKCertificate(ctx context.Context, query *PCKCertificateQuery) (*PCKCertificate, []byte, error) {
+	if err := query.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	key := "pckcert/" + strings.Join([]string{query.EncryptedPPID, query.CPUSVN, query.PCESVN, query.PCEID}, "/")
+	entry, err := cc.get(ctx, strings.ToLower(key), func(ctx context.Context) (interface{}, []byte, error) {
+		return cc.upstream.GetPCKCertificate(ctx, query)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return entry.value.(*PCKCertificate), entry.certs, nil
+}
+
+func (cc *cachingClient) GetPCKCRL(ctx context.Context, ca string) ([]byte, []byte, error) {
+	if err := ValidatePCKCA(ca); err != nil {
+		return nil, nil, err
+	}
+
+	entry, err := cc.get(ctx, "pckcrl/"+ca, func(ctx context.Context) (interface{}, []byte, error) {
+		return cc.upstream.GetPCKCRL(ctx, ca)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return entry.value.([]byte), entry.certs, nil
+}
+
+// NewCachingClient returns a new PCS client that caches the collateral retrieved from the given
+// upstream client.
+func NewCachingClient(upstream Client, cfg CacheConfig) (Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &cachingClient{
+		upstream: upstream,
+		cfg:      cfg,
+		entries:  make(map[string]*cacheEntry),
+		now:      time.Now,
+		logger:   logging.GetLogger("common/sgx/pcs/cache"),
+	}, nil
+}
@@ -0,0 +1,239 @@
+package pcs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testUpstream struct {
+	sync.Mutex
+
+	available bool
+	fetches   int
+	// blockCh, if set, blocks fetches until closed.
+	blockCh chan struct{}
+}
+
+func (tu *testUpstream) fetch(ctx context.Context) (int, error) {
+	if tu.blockCh != nil {
+		select {
+		case <-tu.blockCh:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	tu.Lock()
+	defer tu.Unlock()
+
+	if !tu.available {
+		return 0, fmt.Errorf("upstream unavailable")
+	}
+	tu.fetches++
+	return tu.fetches, nil
+}
+
+func (tu *testUpstream) GetTCBInfo(ctx context.Context, fmspc []byte) (*SignedTCBInfo, []byte, error) {
+	n, err := tu.fetch(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &SignedTCBInfo{Signature: fmt.Sprintf("%x/%d", fmspc, n)}, []byte("certs"), nil
+}
+
+func (tu *testUpstream) GetQEIdentity(ctx context.Context) (*SignedQEIdentity, []byte, error) {
+	n, err := tu.fetch(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &SignedQEIdentity{Signature: fmt.Sprintf("qe/%d", n)}, []byte("certs"), nil
+}
+
+func (tu *testUpstream) GetPCKCertificate(ctx context.Context, query *PCKCertificateQuery) (*PCKCertificate, []byte, error) {
+	n, err := tu.fetch(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &PCKCertificate{Certificate: []byte(fmt.Sprintf("pckcert/%d", n))}, []byte("certs"), nil
+}
+
+func (tu *testUpstream) GetPCKCRL(ctx context.Context, ca string) ([]byte, []byte, error) {
+	n, err := tu.fetch(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []byte(fmt.Sprintf("%s/%d", ca, n)), []byte("certs"), nil
+}
+
+func TestCachingClient(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewCachingClient(&testUpstream{}, CacheConfig{})
+	require.Error(err, "NewCachingClient should fail with invalid configuration")
+
+	upstream := &testUpstream{available: true}
+	client, err := NewCachingClient(upstream, CacheConfig{
+		RefreshInterval:    time.Hour,
+		OfflineGracePeriod: 2 * time.Hour,
+	})
+	require.NoError(err, "NewCachingClient")
+
+	now := time.Now()
+	cc := client.(*cachingClient)
+	cc.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	fmspc := []byte{0x00, 0x90, 0x6e, 0xa1, 0x00, 0x00}
+
+	tcbInfo, certs, err := client.GetTCBInfo(ctx, fmspc)
+	require.NoError(err, "GetTCBInfo")
+	require.Equal("00906ea10000/1", tcbInfo.Signature)
+	require.Equal([]byte("certs"), certs)
+
+	// Fresh collateral should be served from the cache.
+	now = now.Add(30 * time.Minute)
+	tcbInfo, _, err = client.GetTCBInfo(ctx, fmspc)
+	require.NoError(err, "GetTCBInfo")
+	require.Equal("00906ea10000/1", tcbInfo.Signature)
+	require.Equal(1, upstream.fetches)
+
+	// Stale collateral should be refreshed.
+	now = now.Add(time.Hour)
+	tcbInfo, _, err = client.GetTCBInfo(ctx, fmspc)
+	require.NoError(err, "GetTCBInfo")
+	require.Equal("00906ea10000/2", tcbInfo.Signature)
+
+	// Stale collateral should be served during the offline grace period.
+	upstream.available = false
+	now = now.Add(2 * time.Hour)
+	tcbInfo, _, err = client.GetTCBInfo(ctx, fmspc)
+	require.NoError(err, "GetTCBInfo during offline grace period")
+	require.Equal("00906ea10000/2", tcbInfo.Signature)
+
+	// After the offline grace period, an error should be returned.
+	now = now.Add(time.Hour)
+	_, _, err = client.GetTCBInfo(ctx, fmspc)
+	require.Error(err, "GetTCBInfo after offline grace period")
+
+	// Nothing cached.
+	_, _, err = client.GetQEIdentity(ctx)
+	require.Error(err, "GetQEIdentity with unavailable upstream")
+
+	upstream.available = true
+	qeIdentity, _, err := client.GetQEIdentity(ctx)
+	require.NoError(err, "GetQEIdentity")
+	require.Equal("qe/3", qeIdentity.Signature)
+
+	bundle, err := GetTCBBundle(ctx, client, fmspc)
+	require.NoError(err, "GetTCBBundle")
+	require.Equal("00906ea10000/4", bundle.TCBInfo.Signature)
+	require.Equal("qe/3", bundle.QEIdentity.Signature)
+}
+
+func TestCachingClientPCK(t *testing.T) {
+	require := require.New(t)
+
+	upstream := &testUpstream{available: true}
+	client, err := NewCachingClient(upstream, CacheConfig{
+		RefreshInterval: time.Hour,
+	})
+	require.NoError(err, "NewCachingClient")
+
+	ctx := context.Background()
+	query := &PCKCertificateQuery{
+		EncryptedPPID: strings.Repeat("aa", 384),
+		CPUSVN:        strings.Repeat("00", 16),
+		PCESVN:        "0b00",
+		PCEID:         "0000",
+	}
+
+	for i := 0; i < 2; i++ {
+		cert, _, err := client.GetPCKCertificate(ctx, query)
+		require.NoError(err, "GetPCKCertificate")
+		require.Equal([]byte("pckcert/1"), cert.Certificate, "PCK certificates should be cached")
+
+		crl, _, err := client.GetPCKCRL(ctx, PCKCAProcessor)
+		require.NoError(err, "GetPCKCRL")
+		require.Equal([]byte("processor/2"), crl, "PCK CRLs should be cached")
+	}
+
+	crl, _, err := client.GetPCKCRL(ctx, PCKCAPlatform)
+	require.NoError(err, "GetPCKCRL")
+	require.Equal([]byte("platform/3"), crl, "PCK CRLs should be cached per CA")
+
+	_, _, err = client.GetPCKCRL(ctx, "invalid")
+	require.Error(err, "GetPCKCRL should fail with an invalid CA")
+	_, _, err = client.GetPCKCertificate(ctx, &PCKCertificateQuery{})
+	require.Error(err, "GetPCKCertificate should fail with a malformed query")
+	require.Equal(3, upstream.fetches, "invalid requests should not reach upstream")
+}
+
+func TestCachingClientConcurrentFetches(t *testing.T) {
+	require := require.New(t)
+
+	upstream := &testUpstream{
+		available: true,
+		blockCh:   make(chan struct{}),
+	}
+	client, err := NewCachingClient(upstream, CacheConfig{
+		RefreshInterval: time.Hour,
+	})
+	require.NoError(err, "NewCachingClient")
+
+	fmspc := []byte{0x00, 0x90, 0x6e, 0xa1, 0x00, 0x00}
+
+	// A caller giving up should not fail the fetch for the other callers.
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancelledCh := make(chan error, 1)
+	go func() {
+		_, _, err := client.GetTCBInfo(cancelledCtx, fmspc)
+		cancelledCh <- err
+	}()
+
+	const numCallers = 10
+	var wg sync.WaitGroup
+	results := make(chan string, numCallers)
+	for i := 0; i < numCallers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tcbInfo, _, err := client.GetTCBInfo(context.Background(), fmspc)
+			if err != nil {
+				results <- err.Error()
+				return
+			}
+			results <- tcbInfo.Signature
+		}()
+	}
+
+	// Other collateral should not be blocked by the pending fetch.
+	qeCtx, qeCancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer qeCancel()
+	_, _, err = client.GetQEIdentity(qeCtx)
+	require.ErrorIs(err, context.DeadlineExceeded, "GetQEIdentity should only wait for its own fetch")
+
+	cancel()
+	require.ErrorIs(<-cancelledCh, context.Canceled, "cancelled caller should return")
+
+	close(upstream.blockCh)
+	wg.Wait()
+	close(results)
+	sigs := make(map[string]bool)
+	for sig := range results {
+		sigs[sig] = true
+	}
+	require.Len(sigs, 1, "all callers should get the result of a single fetch")
+
+	// The QE identity fetch should complete even though its only caller gave up.
+	_, _, err = client.GetQEIdentity(context.Background())
+	require.NoError(err, "GetQEIdentity")
+	upstream.Lock()
+	defer upstream.Unlock()
+	require.Equal(2, upstream.fetches, "concurrent fetches should be coalesced")
+}
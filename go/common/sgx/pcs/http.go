@@ -0,0 +1,306 @@
+package pcs
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPCSBaseURL is the base URL of the Intel Provisioning Certification Service.
+	DefaultPCSBaseURL = "https://api.trustedservices.intel.com"
+
+	// PathTCBInfo is the PCS API path for retrieving the TCB info.
+	PathTCBInfo = "/sgx/certification/v3/tcb"
+	// PathQEIdentity is the PCS API path for retrieving the QE identity.
+	PathQEIdentity = "/sgx/certification/v3/qe/identity"
+	// PathPCKCertificate is the PCS API path for retrieving a PCK certificate.
+	PathPCKCertificate = "/sgx/certification/v3/pckcert"
+	// PathPCKCRL is the PCS API path for retrieving a PCK certificate revocation list.
+	PathPCKCRL = "/sgx/certification/v3/pckcrl"
+
+	// HeaderTCBInfoIssuerChain is the response header containing the URL-encoded TCB info
+	// signing certificate chain.
+	HeaderTCBInfoIssuerChain = "SGX-TCB-Info-Issuer-Chain"
+	// HeaderQEIdentityIssuerChain is the response header containing the URL-encoded QE identity
+	// signing certificate chain.
+	HeaderQEIdentityIssuerChain = "SGX-Enclave-Identity-Issuer-Chain"
+	// HeaderPCKCertificateIssuerChain is the response header containing the URL-encoded PCK
+	// certificate signing certificate chain.
+	HeaderPCKCertificateIssuerChain = "SGX-PCK-Certificate-Issuer-Chain"
+	// HeaderPCKCRLIssuerChain is the response header containing the URL-encoded PCK CRL signing
+	// certificate chain.
+	HeaderPCKCRLIssuerChain = "SGX-PCK-CRL-Issuer-Chain"
+	// HeaderTCBm is the response header containing the hex-encoded TCBm of a PCK certificate.
+	HeaderTCBm = "SGX-TCBm"
+	// HeaderFMSPC is the response header containing the hex-encoded FMSPC of a PCK certificate.
+	HeaderFMSPC = "SGX-FMSPC"
+
+	// QueryFMSPC is the query parameter containing the hex-encoded FMSPC.
+	QueryFMSPC = "fmspc"
+	// QueryEncryptedPPID is the query parameter containing the hex-encoded encrypted PPID.
+	QueryEncryptedPPID = "encrypted_ppid"
+	// QueryCPUSVN is the query parameter containing the hex-encoded CPUSVN.
+	QueryCPUSVN = "cpusvn"
+	// QueryPCESVN is the query parameter containing the hex-encoded PCESVN.
+	QueryPCESVN = "pcesvn"
+	// QueryPCEID is the query parameter containing the hex-encoded PCE ID.
+	QueryPCEID = "pceid"
+	// QueryCA is the query parameter containing the PCK CA type.
+	QueryCA = "ca"
+
+	// PCKCAProcessor is the processor PCK CA.
+	PCKCAProcessor = "processor"
+	// PCKCAPlatform is the platform PCK CA.
+	PCKCAPlatform = "platform"
+
+	httpClientTimeout = 30 * time.Second
+	maxResponseSize   = 1 << 20
+)
+
+// Client is a client for retrieving attestation collateral from the Intel PCS or a compatible
+// caching service.
+type Client interface {
+	// GetTCBInfo retrieves the signed TCB info for the platform with the given FMSPC together
+	// with the PEM-encoded signing certificate chain.
+	GetTCBInfo(ctx context.Context, fmspc []byte) (*SignedTCBInfo, []byte, error)
+
+	// GetQEIdentity retrieves the signed QE identity together with the PEM-encoded signing
+	// certificate chain.
+	GetQEIdentity(ctx context.Context) (*SignedQEIdentity, []byte, error)
+
+	// GetPCKCertificate retrieves the PCK certificate for the given platform together with the
+	// PEM-encoded signing certificate chain.
+	GetPCKCertificate(ctx context.Context, query *PCKCertificateQuery) (*PCKCertificate, []byte, error)
+
+	// GetPCKCRL retrieves the certificate revocation list of the given PCK CA together with the
+	// PEM-encoded signing certificate chain.
+	GetPCKCRL(ctx context.Context, ca string) ([]byte, []byte, error)
+}
+
+// PCKCertificateQuery identifies the platform and TCB level to retrieve a PCK certificate for.
+//
+// All fields are hex-encoded.
+type PCKCertificateQuery struct {
+	// EncryptedPPID is the encrypted platform provisioning ID.
+	EncryptedPPID string
+	// CPUSVN is the CPU security version number.
+	CPUSVN string
+	// PCESVN is the PCE security version number.
+	PCESVN string
+	// PCEID is the PCE identifier.
+	PCEID string
+}
+
+// Validate validates the PCK certificate query.
+func (q *PCKCertificateQuery) Validate() error {
+	for _, f := range []struct {
+		name  string
+		value string
+		size  int
+	}{
+		{QueryEncryptedPPID, q.EncryptedPPID, 384},
+		{QueryCPUSVN, q.CPUSVN, 16},
+		{QueryPCESVN, q.PCESVN, 2},
+		{QueryPCEID, q.PCEID, 2},
+	} {
+		raw, err := hex.DecodeString(f.value)
+		if err != nil || len(raw) != f.size {
+			return fmt.Errorf("pcs: malformed %s", f.name)
+		}
+	}
+	return nil
+}
+
+func (q *PCKCertificateQuery) values() url.Values {
+	return url.Values{
+		QueryEncryptedPPID: []string{q.EncryptedPPID},
+		QueryCPUSVN:        []string{q.CPUSVN},
+		QueryPCESVN:        []string{q.PCESVN},
+		QueryPCEID:         []string{q.PCEID},
+	}
+}
+
+// PCKCertificate is a PCK certificate together with the TCB level it was issued for.
+type PCKCertificate struct {
+	// Certificate is the PEM-encoded PCK certificate.
+	Certificate []byte
+	// TCBm is the hex-encoded TCB level (CPUSVN and PCESVN) of the certificate.
+	TCBm string
+	// FMSPC is the hex-encoded FMSPC of the platform.
+	FMSPC string
+}
+
+// ValidatePCKCA validates the PCK CA type.
+func ValidatePCKCA(ca string) error {
+	switch ca {
+	case PCKCAProcessor, PCKCAPlatform:
+		return nil
+	default:
+		return fmt.Errorf("pcs: invalid PCK CA: '%s'", ca)
+	}
+}
+
+// GetTCBBundle retrieves the TCB collateral required to verify quotes produced by the platform
+// with the given FMSPC.
+func GetTCBBundle(ctx context.Context, client Client, fmspc []byte) (*TCBBundle, error) {
+	tcbInfo, certs, err := client.GetTCBInfo(ctx, fmspc)
+	if err != nil {
+		return nil, err
+	}
+	qeIdentity, _, err := client.GetQEIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TCBBundle{
+		TCBInfo:      *tcbInfo,
+		QEIdentity:   *qeIdentity,
+		Certificates: certs,
+	}, nil
+}
+
+type httpClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// getRaw performs a request and returns the response body, headers and the signing certificate
+// chain from the given header.
+func (hc *httpClient) getRaw(ctx context.Context, path string, query url.Values, chainHeader string) ([]byte, http.Header, []byte, error) {
+	u := hc.baseURL + path
+	if query != nil {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("pcs/http: failed to create request: %w", err)
+	}
+
+	resp, err := hc.client.Do(req)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("pcs/http: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, nil, fmt.Errorf("pcs/http: unexpected response status: %d", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("pcs/http: failed to read response: %w", err)
+	}
+
+	certs, err := url.QueryUnescape(resp.Header.Get(chainHeader))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("pcs/http: malformed certificate chain: %w", err)
+	}
+	if _, err = certChainFromPEM([]byte(certs)); err != nil {
+		return nil, nil, nil, fmt.Errorf("pcs/http: malformed certificate chain: %w", err)
+	}
+	return body, resp.Header, []byte(certs), nil
+}
+
+func (hc *httpClient) get(ctx context.Context, path string, query url.Values, chainHeader string, dst interface{}) ([]byte, error) {
+	body, _, certs, err := hc.getRaw(ctx, path, query, chainHeader)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(body, dst); err != nil {
+		return nil, fmt.Errorf("pcs/http: malformed response: %w", err)
+	}
+	return certs, nil
+}
+
+func (hc *httpClient) GetTCBInfo(ctx context.Context, fmspc []byte) (*SignedTCBInfo, []byte, error) {
+	if len(fmspc) != fmspcLen {
+		return nil, nil, fmt.Errorf("pcs/http: invalid FMSPC length")
+	}
+
+	var tcbInfo SignedTCBInfo
+	query := url.Values{QueryFMSPC: []string{hex.EncodeToString(fmspc)}}
+	certs, err := hc.get(ctx, PathTCBInfo, query, HeaderTCBInfoIssuerChain, &tcbInfo)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &tcbInfo, certs, nil
+}
+
+func (hc *httpClient) GetQEIdentity(ctx context.Context) (*SignedQEIdentity, []byte, error) {
+	var qeIdentity SignedQEIdentity
+	certs, err := hc.get(ctx, PathQEIdentity, nil, HeaderQEIdentityIssuerChain, &qeIdentity)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &qeIdentity, certs, nil
+}
+
+func (hc *httpClient) GetPCKCertificate(ctx context.Context, query *PCKCertificateQuery) (*PCKCertificate, []byte, error) {
+	if err := query.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	body, hdr, certs, err := hc.getRaw(ctx, PathPCKCertificate, query.values(), HeaderPCKCertificateIssuerChain)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err = certChainFromPEM(body); err != nil {
+		return nil, nil, fmt.Errorf("pcs/http: malformed PCK certificate: %w", err)
+	}
+	return &PCKCertificate{
+		Certificate: body,
+		TCBm:        hdr.Get(HeaderTCBm),
+		FMSPC:       hdr.Get(HeaderFMSPC),
+	}, certs, nil
+}
+
+func (hc *httpClient) GetPCKCRL(ctx context.Context, ca string) ([]byte, []byte, error) {
+	if err := ValidatePCKCA(ca); err != nil {
+		return nil, nil, err
+	}
+
+	body, _, certs, err := hc.getRaw(ctx, PathPCKCRL, url.Values{QueryCA: []string{ca}}, HeaderPCKCRLIssuerChain)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = validateCRL(body); err != nil {
+		return nil, nil, fmt.Errorf("pcs/http: malformed PCK CRL: %w", err)
+	}
+	return body, certs, nil
+}
+
+// validateCRL checks that the given PEM- or DER-encoded data is a certificate revocation list.
+func validateCRL(raw []byte) error {
+	if block, _ := pem.Decode(raw); block != nil {
+		raw = block.Bytes
+	}
+	_, err := x509.ParseCRL(raw) // nolint: staticcheck
+	return err
+}
+
+// NewHTTPClient returns a new PCS client that talks to the PCS-compatible HTTP API at the given
+// base URL.
+//
+// If the base URL is empty, the Intel PCS is used.
+func NewHTTPClient(baseURL string) Client {
+	if baseURL == "" {
+		baseURL = DefaultPCSBaseURL
+	}
+
+	return &httpClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client: &http.Client{
+			Timeout: httpClientTimeout,
+		},
+	}
+}
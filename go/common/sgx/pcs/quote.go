@@ -0,0 +1,334 @@
+// Package pcs provides routines for verifying Intel SGX DCAP (ECDSA) attestation quotes against
+// the collateral provided by the Intel Provisioning Certification Service (PCS) or a
+// Provisioning Certificate Caching Service (PCCS).
+package pcs
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
+)
+
+const (
+	// quoteHeaderLen is the length of the quote header in bytes.
+	quoteHeaderLen = 48
+
+	// reportBodyLen is the length of an enclave report body in bytes.
+	reportBodyLen = 384
+
+	// quoteSigSizeLen is the length of the quote signature data size field in bytes.
+	quoteSigSizeLen = 4
+
+	// quoteSigECDSAP256MinLen is the minimum length of the ECDSA-256-with-P-256 quote signature
+	// data in bytes.
+	quoteSigECDSAP256MinLen = 64 + 64 + reportBodyLen + 64 + 2 + 2 + 4
+
+	// ecdsaP256SignatureLen is the length of a raw ECDSA P-256 signature (r || s) in bytes.
+	ecdsaP256SignatureLen = 64
+
+	// ecdsaP256PublicKeyLen is the length of a raw ECDSA P-256 public key (x || y) in bytes.
+	ecdsaP256PublicKeyLen = 64
+
+	// quoteVersion is the supported quote version.
+	quoteVersion = 3
+)
+
+// QEVendorIDIntel is the Intel QE vendor ID.
+var QEVendorIDIntel = [16]byte{0x93, 0x9a, 0x72, 0x33, 0xf7, 0x9c, 0x4c, 0xa9, 0x94, 0x0a, 0x0d, 0xb3, 0x95, 0x7f, 0x06, 0x07}
+
+// AttestationKeyType is the type of the attestation key used to sign a quote.
+type AttestationKeyType uint16
+
+// Predefined attestation key types.
+const (
+	AttestationKeyECDSAP256 AttestationKeyType = 2
+)
+
+// CertificationDataType is the type of the QE certification data.
+type CertificationDataType uint16
+
+// Predefined QE certification data types.
+const (
+	CertificationDataPCKCertificateChain CertificationDataType = 5
+)
+
+// QuoteHeader is a quote header.
+type QuoteHeader struct {
+	Version            uint16
+	AttestationKeyType AttestationKeyType
+	QESVN              uint16
+	PCESVN             uint16
+	QEVendorID         [16]byte
+	UserData           [20]byte
+}
+
+// UnmarshalBinary decodes QuoteHeader from a byte array.
+func (qh *QuoteHeader) UnmarshalBinary(data []byte) error {
+	if len(data) < quoteHeaderLen {
+		return fmt.Errorf("pcs/quote: invalid header length")
+	}
+
+	qh.Version = binary.LittleEndian.Uint16(data[0:])
+	if qh.Version != quoteVersion {
+		return fmt.Errorf("pcs/quote: unsupported version: %d", qh.Version)
+	}
+	qh.AttestationKeyType = AttestationKeyType(binary.LittleEndian.Uint16(data[2:]))
+	if qh.AttestationKeyType != AttestationKeyECDSAP256 {
+		return fmt.Errorf("pcs/quote: unsupported attestation key type: %d", qh.AttestationKeyType)
+	}
+	qh.QESVN = binary.LittleEndian.Uint16(data[8:])
+	qh.PCESVN = binary.LittleEndian.Uint16(data[10:])
+	copy(qh.QEVendorID[:], data[12:28])
+	copy(qh.UserData[:], data[28:48])
+
+	return nil
+}
+
+// QuoteSignatureECDSAP256 is an ECDSA-256-with-P-256 quote signature.
+type QuoteSignatureECDSAP256 struct {
+	// Signature is the signature of the quote header and the ISV enclave report body made by
+	// the attestation key.
+	Signature [ecdsaP256SignatureLen]byte
+	// AttestationPublicKey is the raw attestation public key.
+	AttestationPublicKey [ecdsaP256PublicKeyLen]byte
+
+	// QEReport is the raw report body of the quoting enclave.
+	QEReport []byte
+	// QEReportSignature is the signature of the QE report body made by the PCK key.
+	QEReportSignature [ecdsaP256SignatureLen]byte
+	// AuthenticationData is the QE authentication data.
+	AuthenticationData []byte
+
+	// CertificationDataType is the type of the QE certification data.
+	CertificationDataType CertificationDataType
+	// CertificationData is the QE certification data.
+	CertificationData []byte
+}
+
+// UnmarshalBinary decodes QuoteSignatureECDSAP256 from a byte array.
+func (qs *QuoteSignatureECDSAP256) UnmarshalBinary(data []byte) error {
+	if len(data) < quoteSigECDSAP256MinLen {
+		return fmt.Errorf("pcs/quote: invalid signature length")
+	}
+
+	offset := 0
+	copy(qs.Signature[:], data[offset:offset+ecdsaP256SignatureLen])
+	offset += ecdsaP256SignatureLen
+	copy(qs.AttestationPublicKey[:], data[offset:offset+ecdsaP256PublicKeyLen])
+	offset += ecdsaP256PublicKeyLen
+	qs.QEReport = append([]byte{}, data[offset:offset+reportBodyLen]...)
+	offset += reportBodyLen
+	copy(qs.QEReportSignature[:], data[offset:offset+ecdsaP256SignatureLen])
+	offset += ecdsaP256SignatureLen
+
+	authDataLen := int(binary.LittleEndian.Uint16(data[offset:]))
+	offset += 2
+	if len(data[offset:]) < authDataLen+2+4 {
+		return fmt.Errorf("pcs/quote: invalid authentication data length")
+	}
+	qs.AuthenticationData = append([]byte{}, data[offset:offset+authDataLen]...)
+	offset += authDataLen
+
+	qs.CertificationDataType = CertificationDataType(binary.LittleEndian.Uint16(data[offset:]))
+	offset += 2
+	certDataLen := int(binary.LittleEndian.Uint32(data[offset:]))
+	offset += 4
+	if len(data[offset:]) != certDataLen {
+		return fmt.Errorf("pcs/quote: invalid certification data length")
+	}
+	qs.CertificationData = append([]byte{}, data[offset:]...)
+
+	return nil
+}
+
+// attestationPublicKey returns the attestation public key.
+func (qs *QuoteSignatureECDSAP256) attestationPublicKey() (*ecdsa.PublicKey, error) {
+	return ecdsaP256PublicKeyFromRaw(qs.AttestationPublicKey[:])
+}
+
+// verifyQEReportBinding verifies that the QE report binds the attestation public key.
+func (qs *QuoteSignatureECDSAP256) verifyQEReportBinding(qeReport *ias.Report) error {
+	h := sha256.New()
+	_, _ = h.Write(qs.AttestationPublicKey[:])
+	_, _ = h.Write(qs.AuthenticationData)
+	expected := h.Sum(nil)
+
+	if !bytes.Equal(qeReport.ReportData[:sha256.Size], expected) {
+		return fmt.Errorf("pcs/quote: QE report does not bind the attestation key")
+	}
+	if !bytes.Equal(qeReport.ReportData[sha256.Size:], make([]byte, len(qeReport.ReportData)-sha256.Size)) {
+		return fmt.Errorf("pcs/quote: malformed QE report data")
+	}
+	return nil
+}
+
+// Quote is an Intel SGX DCAP attestation quote.
+type Quote struct {
+	Header    QuoteHeader
+	ISVReport ias.Report
+	Signature QuoteSignatureECDSAP256
+
+	// signedData is the part of the quote covered by the attestation key signature.
+	signedData []byte
+}
+
+// UnmarshalBinary decodes Quote from a byte array.
+func (q *Quote) UnmarshalBinary(data []byte) error {
+	if len(data) < quoteHeaderLen+reportBodyLen+quoteSigSizeLen {
+		return fmt.Errorf("pcs/quote: invalid quote length")
+	}
+
+	if err := q.Header.UnmarshalBinary(data[:quoteHeaderLen]); err != nil {
+		return err
+	}
+	if err := q.ISVReport.UnmarshalBinary(data[quoteHeaderLen : quoteHeaderLen+reportBodyLen]); err != nil {
+		return err
+	}
+	q.signedData = append([]byte{}, data[:quoteHeaderLen+reportBodyLen]...)
+
+	offset := quoteHeaderLen + reportBodyLen
+	sigLen := int(binary.LittleEndian.Uint32(data[offset:]))
+	offset += quoteSigSizeLen
+	if len(data[offset:]) != sigLen {
+		return fmt.Errorf("pcs/quote: invalid signature data length")
+	}
+	return q.Signature.UnmarshalBinary(data[offset:])
+}
+
+// Verify verifies the quote against the given TCB collateral and trust roots at the given
+// timestamp.
+//
+// In case the quote is valid, information about the verified quote is returned.
+func (q *Quote) Verify(tcb *TCBBundle, trustRoots *x509.CertPool, ts time.Time) (*VerifiedQuote, error) {
+	if q.Header.QEVendorID != QEVendorIDIntel {
+		return nil, fmt.Errorf("pcs/quote: unsupported QE vendor")
+	}
+	if q.Signature.CertificationDataType != CertificationDataPCKCertificateChain {
+		return nil, fmt.Errorf("pcs/quote: unsupported certification data type: %d", q.Signature.CertificationDataType)
+	}
+	if err := q.ISVReport.Verify(); err != nil {
+		return nil, err
+	}
+
+	// Verify the PCK certificate chain.
+	pckCert, err := verifyCertChain(q.Signature.CertificationData, trustRoots, ts)
+	if err != nil {
+		return nil, fmt.Errorf("pcs/quote: invalid PCK certificate chain: %w", err)
+	}
+	pckPublicKey, ok := pckCert.PublicKey.(*ecdsa.PublicKey)
+	if !ok || pckPublicKey.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("pcs/quote: unsupported PCK public key type")
+	}
+	pckInfo, err := parsePCKExtensions(pckCert)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify the QE report and make sure it binds the attestation key.
+	if err = verifyECDSAP256Signature(pckPublicKey, q.Signature.QEReport, q.Signature.QEReportSignature[:]); err != nil {
+		return nil, fmt.Errorf("pcs/quote: invalid QE report signature: %w", err)
+	}
+	var qeReport ias.Report
+	if err = qeReport.UnmarshalBinary(q.Signature.QEReport); err != nil {
+		return nil, fmt.Errorf("pcs/quote: malformed QE report: %w", err)
+	}
+	if err = q.Signature.verifyQEReportBinding(&qeReport); err != nil {
+		return nil, err
+	}
+
+	// Verify the quote signature made by the attestation key.
+	attPublicKey, err := q.Signature.attestationPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	if err = verifyECDSAP256Signature(attPublicKey, q.signedData, q.Signature.Signature[:]); err != nil {
+		return nil, fmt.Errorf("pcs/quote: invalid quote signature: %w", err)
+	}
+
+	// Verify the TCB collateral and determine the TCB status.
+	tcbInfo, qeIdentity, err := tcb.open(trustRoots, ts)
+	if err != nil {
+		return nil, err
+	}
+	qeStatus, err := qeIdentity.verify(&qeReport)
+	if err != nil {
+		return nil, err
+	}
+	tcbLevel, err := tcbInfo.verify(pckInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VerifiedQuote{
+		ISVReport:   q.ISVReport,
+		TCBStatus:   combineTCBStatus(tcbLevel.Status, qeStatus),
+		AdvisoryIDs: tcbLevel.AdvisoryIDs,
+	}, nil
+}
+
+// VerifiedQuote is the information about a successfully verified quote.
+type VerifiedQuote struct {
+	// ISVReport is the report body of the attested enclave.
+	ISVReport ias.Report
+	// TCBStatus is the TCB status of the platform and the quoting enclave.
+	TCBStatus TCBStatus
+	// AdvisoryIDs are the identifiers of the Intel security advisories that apply to the TCB.
+	AdvisoryIDs []string
+}
+
+// QuoteBundle is an attestation quote together with the TCB collateral required for its
+// verification.
+type QuoteBundle struct {
+	// Quote is the raw attestation quote.
+	Quote []byte `json:"quote"`
+
+	// TCB is the TCB collateral required to verify the quote.
+	TCB TCBBundle `json:"tcb"`
+}
+
+// Verify verifies the quote bundle at the given timestamp using the given trust roots.
+func (bnd *QuoteBundle) Verify(trustRoots *x509.CertPool, ts time.Time) (*VerifiedQuote, error) {
+	var quote Quote
+	if err := quote.UnmarshalBinary(bnd.Quote); err != nil {
+		return nil, err
+	}
+	return quote.Verify(&bnd.TCB, trustRoots, ts)
+}
+
+func ecdsaP256PublicKeyFromRaw(raw []byte) (*ecdsa.PublicKey, error) {
+	if len(raw) != ecdsaP256PublicKeyLen {
+		return nil, fmt.Errorf("pcs: invalid public key length")
+	}
+
+	pk := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(raw[:32]),
+		Y:     new(big.Int).SetBytes(raw[32:]),
+	}
+	if !pk.Curve.IsOnCurve(pk.X, pk.Y) {
+		return nil, fmt.Errorf("pcs: invalid public key")
+	}
+	return pk, nil
+}
+
+func verifyECDSAP256Signature(pk *ecdsa.PublicKey, message, signature []byte) error {
+	if len(signature) != ecdsaP256SignatureLen {
+		return fmt.Errorf("invalid signature length")
+	}
+
+	h := sha256.Sum256(message)
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:])
+	if !ecdsa.Verify(pk, h[:], r, s) {
+		return fmt.Errorf("signature verification failed")
+	}
+	return nil
+}
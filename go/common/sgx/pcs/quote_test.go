@@ -0,0 +1,283 @@
+package pcs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
+)
+
+type testPKI struct {
+	roots *x509.CertPool
+
+	rootKey  *ecdsa.PrivateKey
+	rootCert *x509.Certificate
+
+	pckKey   *ecdsa.PrivateKey
+	pckChain []byte
+
+	tcbKey   *ecdsa.PrivateKey
+	tcbChain []byte
+}
+
+func mustMarshalASN1(t *testing.T, v interface{}) asn1.RawValue {
+	raw, err := asn1.Marshal(v)
+	require.NoError(t, err, "asn1.Marshal")
+	return asn1.RawValue{FullBytes: raw}
+}
+
+func pckExtension(t *testing.T, compSVN uint8, pceSVN int) pkix.Extension {
+	var tcbExts []sgxExtension
+	for i := 1; i <= tcbComponentCount; i++ {
+		tcbExts = append(tcbExts, sgxExtension{
+			ID:    append(append(asn1.ObjectIdentifier{}, oidSGXTCB...), i),
+			Value: mustMarshalASN1(t, int(compSVN)),
+		})
+	}
+	tcbExts = append(tcbExts,
+		sgxExtension{ID: oidSGXTCBPCESVN, Value: mustMarshalASN1(t, pceSVN)},
+		sgxExtension{
+			ID:    append(append(asn1.ObjectIdentifier{}, oidSGXTCB...), 18),
+			Value: mustMarshalASN1(t, make([]byte, 16)),
+		},
+	)
+
+	exts := []sgxExtension{
+		{ID: oidSGXTCB, Value: mustMarshalASN1(t, tcbExts)},
+		{ID: oidSGXPCEID, Value: mustMarshalASN1(t, []byte{0x00, 0x00})},
+		{ID: oidSGXFMSPC, Value: mustMarshalASN1(t, []byte{0x00, 0x90, 0x6e, 0xa1, 0x00, 0x00})},
+	}
+	raw, err := asn1.Marshal(exts)
+	require.NoError(t, err, "asn1.Marshal")
+
+	return pkix.Extension{Id: oidSGXExtensions, Value: raw}
+}
+
+func newTestCert(t *testing.T, cn string, key *ecdsa.PrivateKey, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, exts []pkix.Extension) *x509.Certificate {
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+		ExtraExtensions:       exts,
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err, "CreateCertificate")
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err, "ParseCertificate")
+	return cert
+}
+
+func certsToPEM(certs ...*x509.Certificate) []byte {
+	var out []byte
+	for _, cert := range certs {
+		out = append(out, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	return out
+}
+
+func newTestPKI(t *testing.T, pckCompSVN uint8) *testPKI {
+	var (
+		pki testPKI
+		err error
+	)
+	pki.rootKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "GenerateKey")
+	pki.pckKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "GenerateKey")
+	pki.tcbKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "GenerateKey")
+
+	pki.rootCert = newTestCert(t, "Test SGX Root CA", pki.rootKey, nil, nil, nil)
+	pckCert := newTestCert(t, "Test SGX PCK Certificate", pki.pckKey, pki.rootCert, pki.rootKey,
+		[]pkix.Extension{pckExtension(t, pckCompSVN, 11)},
+	)
+	tcbCert := newTestCert(t, "Test SGX TCB Signing", pki.tcbKey, pki.rootCert, pki.rootKey, nil)
+
+	pki.roots = x509.NewCertPool()
+	pki.roots.AddCert(pki.rootCert)
+	pki.pckChain = certsToPEM(pckCert, pki.rootCert)
+	pki.tcbChain = certsToPEM(tcbCert, pki.rootCert)
+
+	return &pki
+}
+
+func signRaw(t *testing.T, key *ecdsa.PrivateKey, data []byte) []byte {
+	h := sha256.Sum256(data)
+	r, s, err := ecdsa.Sign(rand.Reader, key, h[:])
+	require.NoError(t, err, "ecdsa.Sign")
+
+	sig := make([]byte, ecdsaP256SignatureLen)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return sig
+}
+
+func (pki *testPKI) newQuote(t *testing.T, isvReport *ias.Report) []byte {
+	attKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "GenerateKey")
+	attPub := make([]byte, ecdsaP256PublicKeyLen)
+	attKey.X.FillBytes(attPub[:32])
+	attKey.Y.FillBytes(attPub[32:])
+	authData := []byte("authentication data")
+
+	qeReport := ias.Report{
+		ISVProdID: 1,
+		ISVSVN:    6,
+	}
+	qeReport.MRSIGNER[0] = 0x8c
+	binding := sha256.Sum256(append(append([]byte{}, attPub...), authData...))
+	copy(qeReport.ReportData[:], binding[:])
+	rawQEReport, _ := qeReport.MarshalBinary()
+
+	header := make([]byte, quoteHeaderLen)
+	binary.LittleEndian.PutUint16(header[0:], quoteVersion)
+	binary.LittleEndian.PutUint16(header[2:], uint16(AttestationKeyECDSAP256))
+	copy(header[12:28], QEVendorIDIntel[:])
+	rawISVReport, _ := isvReport.MarshalBinary()
+	signedData := append(header, rawISVReport...)
+
+	var sigData []byte
+	sigData = append(sigData, signRaw(t, attKey, signedData)...)
+	sigData = append(sigData, attPub...)
+	sigData = append(sigData, rawQEReport...)
+	sigData = append(sigData, signRaw(t, pki.pckKey, rawQEReport)...)
+	sigData = appendUint16(sigData, uint16(len(authData)))
+	sigData = append(sigData, authData...)
+	sigData = appendUint16(sigData, uint16(CertificationDataPCKCertificateChain))
+	sigData = appendUint32(sigData, uint32(len(pki.pckChain)))
+	sigData = append(sigData, pki.pckChain...)
+
+	quote := append([]byte{}, signedData...)
+	quote = appendUint32(quote, uint32(len(sigData)))
+	return append(quote, sigData...)
+}
+
+func (pki *testPKI) newTCBBundle(t *testing.T, issued, nextUpdate time.Time) TCBBundle {
+	var tcbLevels []string
+	for _, level := range []struct {
+		svn    int
+		status string
+	}{
+		{4, "UpToDate"},
+		{2, "SWHardeningNeeded"},
+		{1, "OutOfDate"},
+	} {
+		var comps []string
+		for i := 0; i < tcbComponentCount; i++ {
+			comps = append(comps, fmt.Sprintf(`{"svn":%d}`, level.svn))
+		}
+		tcbLevels = append(tcbLevels, fmt.Sprintf(
+			`{"tcb":{"sgxtcbcomponents":[%s],"pcesvn":11},"tcbDate":"2022-01-01T00:00:00Z","tcbStatus":"%s"}`,
+			strings.Join(comps, ","), level.status,
+		))
+	}
+	tcbInfo := []byte(fmt.Sprintf(
+		`{"id":"SGX","version":3,"issueDate":"%s","nextUpdate":"%s","fmspc":"00906ea10000","pceId":"0000","tcbType":0,"tcbEvaluationDataNumber":12,"tcbLevels":[%s]}`,
+		issued.UTC().Format(time.RFC3339), nextUpdate.UTC().Format(time.RFC3339), strings.Join(tcbLevels, ","),
+	))
+	qeIdentity := []byte(fmt.Sprintf(
+		`{"id":"QE","version":2,"issueDate":"%s","nextUpdate":"%s","tcbEvaluationDataNumber":12,"miscselect":"00000000","miscselectMask":"FFFFFFFF","attributes":"00000000000000000000000000000000","attributesMask":"FBFFFFFFFFFFFFFF0000000000000000","mrsigner":"8C%s","isvprodid":1,"tcbLevels":[{"tcb":{"isvsvn":6},"tcbDate":"2022-01-01T00:00:00Z","tcbStatus":"UpToDate"}]}`,
+		issued.UTC().Format(time.RFC3339), nextUpdate.UTC().Format(time.RFC3339), hex.EncodeToString(make([]byte, 31)),
+	))
+
+	return TCBBundle{
+		TCBInfo: SignedTCBInfo{
+			TCBInfo:   json.RawMessage(tcbInfo),
+			Signature: hex.EncodeToString(signRaw(t, pki.tcbKey, tcbInfo)),
+		},
+		QEIdentity: SignedQEIdentity{
+			EnclaveIdentity: json.RawMessage(qeIdentity),
+			Signature:       hex.EncodeToString(signRaw(t, pki.tcbKey, qeIdentity)),
+		},
+		Certificates: pki.tcbChain,
+	}
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	var raw [2]byte
+	binary.LittleEndian.PutUint16(raw[:], v)
+	return append(b, raw[:]...)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var raw [4]byte
+	binary.LittleEndian.PutUint32(raw[:], v)
+	return append(b, raw[:]...)
+}
+
+func TestQuoteBundleVerify(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	var isvReport ias.Report
+	isvReport.ISVProdID = 42
+	copy(isvReport.ReportData[:], "report data")
+
+	pki := newTestPKI(t, 4)
+	bundle := QuoteBundle{
+		Quote: pki.newQuote(t, &isvReport),
+		TCB:   pki.newTCBBundle(t, now.Add(-time.Hour), now.Add(time.Hour)),
+	}
+	verified, err := bundle.Verify(pki.roots, now)
+	require.NoError(err, "Verify")
+	require.Equal(isvReport, verified.ISVReport, "verified quote should contain the ISV report")
+	require.Equal(TCBUpToDate, verified.TCBStatus, "TCB status should be up to date")
+
+	_, err = bundle.Verify(IntelTrustRoots, now)
+	require.Error(err, "Verify should fail with untrusted roots")
+
+	_, err = bundle.Verify(pki.roots, now.Add(2*time.Hour))
+	require.Error(err, "Verify should fail with expired collateral")
+
+	tampered := append([]byte{}, bundle.Quote...)
+	tampered[quoteHeaderLen+reportBodyLen-1] ^= 0xff
+	_, err = (&QuoteBundle{Quote: tampered, TCB: bundle.TCB}).Verify(pki.roots, now)
+	require.Error(err, "Verify should fail with a tampered quote")
+
+	tamperedTCB := bundle.TCB
+	tamperedTCB.TCBInfo.TCBInfo = append(json.RawMessage{}, bundle.TCB.TCBInfo.TCBInfo...)
+	tamperedTCB.TCBInfo.TCBInfo[len(tamperedTCB.TCBInfo.TCBInfo)-3] = ' '
+	_, err = (&QuoteBundle{Quote: bundle.Quote, TCB: tamperedTCB}).Verify(pki.roots, now)
+	require.Error(err, "Verify should fail with tampered collateral")
+
+	// Platforms with an older TCB should get a matching TCB status.
+	pki = newTestPKI(t, 3)
+	bundle = QuoteBundle{
+		Quote: pki.newQuote(t, &isvReport),
+		TCB:   pki.newTCBBundle(t, now.Add(-time.Hour), now.Add(time.Hour)),
+	}
+	verified, err = bundle.Verify(pki.roots, now)
+	require.NoError(err, "Verify")
+	require.Equal(TCBSwHardeningNeeded, verified.TCBStatus, "TCB status should match the TCB level")
+
+	pki = newTestPKI(t, 0)
+	bundle = QuoteBundle{
+		Quote: pki.newQuote(t, &isvReport),
+		TCB:   pki.newTCBBundle(t, now.Add(-time.Hour), now.Add(time.Hour)),
+	}
+	_, err = bundle.Verify(pki.roots, now)
+	require.Error(err, "Verify should fail without a matching TCB level")
+}
@@ -0,0 +1,499 @@
+package pcs
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/sgx"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
+)
+
+const (
+	// requiredTCBInfoVersion is the required TCB info version.
+	requiredTCBInfoVersion = 3
+
+	// requiredQEIdentityVersion is the required QE identity version.
+	requiredQEIdentityVersion = 2
+
+	tcbInfoID    = "SGX"
+	qeIdentityID = "QE"
+
+	// tcbComponentCount is the number of SGX TCB components.
+	tcbComponentCount = 16
+
+	// fmspcLen is the length of the FMSPC in bytes.
+	fmspcLen = 6
+
+	// pceIDLen is the length of the PCE ID in bytes.
+	pceIDLen = 2
+)
+
+var (
+	oidSGXExtensions = asn1.ObjectIdentifier{1, 2, 840, 113741, 1, 13, 1}
+	oidSGXTCB        = asn1.ObjectIdentifier{1, 2, 840, 113741, 1, 13, 1, 2}
+	oidSGXTCBPCESVN  = asn1.ObjectIdentifier{1, 2, 840, 113741, 1, 13, 1, 2, 17}
+	oidSGXPCEID      = asn1.ObjectIdentifier{1, 2, 840, 113741, 1, 13, 1, 3}
+	oidSGXFMSPC      = asn1.ObjectIdentifier{1, 2, 840, 113741, 1, 13, 1, 4}
+)
+
+// TCBStatus is the status of a TCB level.
+type TCBStatus int
+
+// Predefined TCB statuses, ordered from best to worst.
+const (
+	tcbStatusInvalid TCBStatus = iota
+	TCBUpToDate
+	TCBSwHardeningNeeded
+	TCBConfigurationNeeded
+	TCBConfigurationAndSwHardeningNeeded
+	TCBOutOfDate
+	TCBOutOfDateConfigurationNeeded
+	TCBRevoked
+)
+
+var (
+	tcbStatusFwdMap = map[string]TCBStatus{
+		"UpToDate":                          TCBUpToDate,
+		"SWHardeningNeeded":                 TCBSwHardeningNeeded,
+		"ConfigurationNeeded":               TCBConfigurationNeeded,
+		"ConfigurationAndSWHardeningNeeded": TCBConfigurationAndSwHardeningNeeded,
+		"OutOfDate":                         TCBOutOfDate,
+		"OutOfDateConfigurationNeeded":      TCBOutOfDateConfigurationNeeded,
+		"Revoked":                           TCBRevoked,
+	}
+	tcbStatusRevMap = make(map[TCBStatus]string)
+)
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (s *TCBStatus) UnmarshalText(text []byte) error {
+	var ok bool
+
+	*s, ok = tcbStatusFwdMap[string(text)]
+	if !ok {
+		return fmt.Errorf("pcs/tcb: invalid TCB status: '%v'", string(text))
+	}
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (s *TCBStatus) MarshalText() ([]byte, error) {
+	str, ok := tcbStatusRevMap[*s]
+	if !ok {
+		return nil, fmt.Errorf("pcs/tcb: invalid TCB status: '%v'", int(*s))
+	}
+
+	return []byte(str), nil
+}
+
+func (s TCBStatus) String() string {
+	return tcbStatusRevMap[s]
+}
+
+// combineTCBStatus combines the platform TCB status with the QE TCB status by picking the worse
+// of the two.
+func combineTCBStatus(platform, qe TCBStatus) TCBStatus {
+	if qe > platform {
+		return qe
+	}
+	return platform
+}
+
+// TCBBundle contains the TCB collateral required to verify a quote.
+type TCBBundle struct {
+	// TCBInfo is the signed TCB info of the platform.
+	TCBInfo SignedTCBInfo `json:"tcb_info"`
+	// QEIdentity is the signed identity of the quoting enclave.
+	QEIdentity SignedQEIdentity `json:"qe_id"`
+	// Certificates is the PEM-encoded TCB info and QE identity signing certificate chain.
+	Certificates []byte `json:"certs"`
+}
+
+// open verifies the signatures and validity of the TCB collateral and returns the TCB info and
+// QE identity.
+func (bnd *TCBBundle) open(trustRoots *x509.CertPool, ts time.Time) (*TCBInfo, *QEIdentity, error) {
+	signingCert, err := verifyCertChain(bnd.Certificates, trustRoots, ts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("pcs/tcb: invalid TCB signing certificate chain: %w", err)
+	}
+	pk, ok := signingCert.PublicKey.(*ecdsa.PublicKey)
+	if !ok || pk.Curve != elliptic.P256() {
+		return nil, nil, fmt.Errorf("pcs/tcb: unsupported TCB signing public key type")
+	}
+
+	tcbInfo, err := bnd.TCBInfo.open(pk, ts)
+	if err != nil {
+		return nil, nil, err
+	}
+	qeIdentity, err := bnd.QEIdentity.open(pk, ts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tcbInfo, qeIdentity, nil
+}
+
+// openSignedJSON verifies the hex-encoded signature over the raw JSON body and decodes it.
+func openSignedJSON(pk *ecdsa.PublicKey, body json.RawMessage, signature string, dst interface{}) error {
+	rawSig, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("malformed signature: %w", err)
+	}
+	if err = verifyECDSAP256Signature(pk, body, rawSig); err != nil {
+		return err
+	}
+	if err = json.Unmarshal(body, dst); err != nil {
+		return fmt.Errorf("malformed body: %w", err)
+	}
+	return nil
+}
+
+// checkValidity checks that the collateral is valid at the given timestamp.
+func checkValidity(issueDate, nextUpdate string, ts time.Time) error {
+	issued, err := time.Parse(time.RFC3339, issueDate)
+	if err != nil {
+		return fmt.Errorf("malformed issue date: %w", err)
+	}
+	expires, err := time.Parse(time.RFC3339, nextUpdate)
+	if err != nil {
+		return fmt.Errorf("malformed next update date: %w", err)
+	}
+	if ts.Before(issued) {
+		return fmt.Errorf("not yet valid")
+	}
+	if !ts.Before(expires) {
+		return fmt.Errorf("expired")
+	}
+	return nil
+}
+
+// SignedTCBInfo is the signed TCB info structure as returned by the PCS.
+type SignedTCBInfo struct {
+	// TCBInfo is the raw JSON-encoded TCB info.
+	TCBInfo json.RawMessage `json:"tcbInfo"`
+	// Signature is the hex-encoded signature of the raw TCB info.
+	Signature string `json:"signature"`
+}
+
+func (st *SignedTCBInfo) open(pk *ecdsa.PublicKey, ts time.Time) (*TCBInfo, error) {
+	var tcbInfo TCBInfo
+	if err := openSignedJSON(pk, st.TCBInfo, st.Signature, &tcbInfo); err != nil {
+		return nil, fmt.Errorf("pcs/tcb: invalid TCB info: %w", err)
+	}
+	if err := tcbInfo.validate(ts); err != nil {
+		return nil, err
+	}
+	return &tcbInfo, nil
+}
+
+// TCBInfo is the TCB info structure.
+type TCBInfo struct {
+	ID                      string     `json:"id"`
+	Version                 int        `json:"version"`
+	IssueDate               string     `json:"issueDate"`
+	NextUpdate              string     `json:"nextUpdate"`
+	FMSPC                   string     `json:"fmspc"`
+	PCEID                   string     `json:"pceId"`
+	TCBType                 int        `json:"tcbType"`
+	TCBEvaluationDataNumber uint32     `json:"tcbEvaluationDataNumber"`
+	TCBLevels               []TCBLevel `json:"tcbLevels"`
+}
+
+func (ti *TCBInfo) validate(ts time.Time) error {
+	if ti.ID != tcbInfoID {
+		return fmt.Errorf("pcs/tcb: unexpected TCB info identifier: %s", ti.ID)
+	}
+	if ti.Version != requiredTCBInfoVersion {
+		return fmt.Errorf("pcs/tcb: unexpected TCB info version, got: %d, required: %d", ti.Version, requiredTCBInfoVersion)
+	}
+	if err := checkValidity(ti.IssueDate, ti.NextUpdate, ts); err != nil {
+		return fmt.Errorf("pcs/tcb: invalid TCB info: %w", err)
+	}
+	for _, level := range ti.TCBLevels {
+		if len(level.TCB.SGXComponents) != tcbComponentCount {
+			return fmt.Errorf("pcs/tcb: malformed TCB level: invalid number of components")
+		}
+	}
+	return nil
+}
+
+// verify matches the PCK certificate TCB against the TCB info and returns the matching TCB level.
+func (ti *TCBInfo) verify(pck *pckInfo) (*TCBLevel, error) {
+	fmspc, err := hex.DecodeString(ti.FMSPC)
+	if err != nil || !bytes.Equal(fmspc, pck.FMSPC) {
+		return nil, fmt.Errorf("pcs/tcb: TCB info FMSPC mismatch")
+	}
+	pceID, err := hex.DecodeString(ti.PCEID)
+	if err != nil || !bytes.Equal(pceID, pck.PCEID) {
+		return nil, fmt.Errorf("pcs/tcb: TCB info PCE ID mismatch")
+	}
+
+	// TCB levels are sorted from the highest to the lowest, so the first level that the platform
+	// TCB is higher or equal to is the matching one.
+	for i := range ti.TCBLevels {
+		level := &ti.TCBLevels[i]
+		if !level.TCB.matches(pck) {
+			continue
+		}
+		if level.Status == TCBRevoked {
+			return nil, fmt.Errorf("pcs/tcb: TCB level revoked")
+		}
+		return level, nil
+	}
+	return nil, fmt.Errorf("pcs/tcb: no matching TCB level")
+}
+
+// TCBLevel is a platform TCB level.
+type TCBLevel struct {
+	TCB         TCBComponents `json:"tcb"`
+	TCBDate     string        `json:"tcbDate"`
+	Status      TCBStatus     `json:"tcbStatus"`
+	AdvisoryIDs []string      `json:"advisoryIDs,omitempty"`
+}
+
+// TCBComponents are the platform TCB components.
+type TCBComponents struct {
+	SGXComponents []TCBComponent `json:"sgxtcbcomponents"`
+	PCESVN        uint16         `json:"pcesvn"`
+}
+
+func (tc *TCBComponents) matches(pck *pckInfo) bool {
+	for i, comp := range tc.SGXComponents {
+		if pck.TCBComponents[i] < comp.SVN {
+			return false
+		}
+	}
+	return pck.PCESVN >= tc.PCESVN
+}
+
+// TCBComponent is a platform TCB component.
+type TCBComponent struct {
+	SVN      uint8  `json:"svn"`
+	Category string `json:"category,omitempty"`
+	Type     string `json:"type,omitempty"`
+}
+
+// SignedQEIdentity is the signed QE identity structure as returned by the PCS.
+type SignedQEIdentity struct {
+	// EnclaveIdentity is the raw JSON-encoded QE identity.
+	EnclaveIdentity json.RawMessage `json:"enclaveIdentity"`
+	// Signature is the hex-encoded signature of the raw QE identity.
+	Signature string `json:"signature"`
+}
+
+func (sq *SignedQEIdentity) open(pk *ecdsa.PublicKey, ts time.Time) (*QEIdentity, error) {
+	var qeIdentity QEIdentity
+	if err := openSignedJSON(pk, sq.EnclaveIdentity, sq.Signature, &qeIdentity); err != nil {
+		return nil, fmt.Errorf("pcs/tcb: invalid QE identity: %w", err)
+	}
+	if err := qeIdentity.validate(ts); err != nil {
+		return nil, err
+	}
+	return &qeIdentity, nil
+}
+
+// QEIdentity is the quoting enclave identity structure.
+type QEIdentity struct {
+	ID                      string            `json:"id"`
+	Version                 int               `json:"version"`
+	IssueDate               string            `json:"issueDate"`
+	NextUpdate              string            `json:"nextUpdate"`
+	TCBEvaluationDataNumber uint32            `json:"tcbEvaluationDataNumber"`
+	MiscSelect              string            `json:"miscselect"`
+	MiscSelectMask          string            `json:"miscselectMask"`
+	Attributes              string            `json:"attributes"`
+	AttributesMask          string            `json:"attributesMask"`
+	MRSIGNER                string            `json:"mrsigner"`
+	ISVProdID               uint16            `json:"isvprodid"`
+	TCBLevels               []EnclaveTCBLevel `json:"tcbLevels"`
+}
+
+func (qe *QEIdentity) validate(ts time.Time) error {
+	if qe.ID != qeIdentityID {
+		return fmt.Errorf("pcs/tcb: unexpected QE identity identifier: %s", qe.ID)
+	}
+	if qe.Version != requiredQEIdentityVersion {
+		return fmt.Errorf("pcs/tcb: unexpected QE identity version, got: %d, required: %d", qe.Version, requiredQEIdentityVersion)
+	}
+	if err := checkValidity(qe.IssueDate, qe.NextUpdate, ts); err != nil {
+		return fmt.Errorf("pcs/tcb: invalid QE identity: %w", err)
+	}
+	return nil
+}
+
+// verify verifies the QE report against the QE identity and returns the QE TCB status.
+func (qe *QEIdentity) verify(report *ias.Report) (TCBStatus, error) {
+	var (
+		miscSelect, miscSelectMask [4]byte
+		attributes, attributesMask [16]byte
+		mrSigner                   sgx.MrSigner
+	)
+	for _, f := range []struct {
+		name string
+		hex  string
+		dst  []byte
+	}{
+		{"miscselect", qe.MiscSelect, miscSelect[:]},
+		{"miscselectMask", qe.MiscSelectMask, miscSelectMask[:]},
+		{"attributes", qe.Attributes, attributes[:]},
+		{"attributesMask", qe.AttributesMask, attributesMask[:]},
+		{"mrsigner", qe.MRSIGNER, mrSigner[:]},
+	} {
+		raw, err := hex.DecodeString(f.hex)
+		if err != nil || len(raw) != len(f.dst) {
+			return tcbStatusInvalid, fmt.Errorf("pcs/tcb: malformed QE identity %s", f.name)
+		}
+		copy(f.dst, raw)
+	}
+
+	var reportMiscSelect [4]byte
+	binary.LittleEndian.PutUint32(reportMiscSelect[:], report.MiscSelect)
+	var reportAttributes [16]byte
+	binary.LittleEndian.PutUint64(reportAttributes[0:], uint64(report.Attributes.Flags))
+	binary.LittleEndian.PutUint64(reportAttributes[8:], report.Attributes.Xfrm)
+
+	for i := range miscSelect {
+		if reportMiscSelect[i]&miscSelectMask[i] != miscSelect[i] {
+			return tcbStatusInvalid, fmt.Errorf("pcs/tcb: QE MISCSELECT mismatch")
+		}
+	}
+	for i := range attributes {
+		if reportAttributes[i]&attributesMask[i] != attributes[i] {
+			return tcbStatusInvalid, fmt.Errorf("pcs/tcb: QE attributes mismatch")
+		}
+	}
+	if report.MRSIGNER != mrSigner {
+		return tcbStatusInvalid, fmt.Errorf("pcs/tcb: QE MRSIGNER mismatch")
+	}
+	if report.ISVProdID != qe.ISVProdID {
+		return tcbStatusInvalid, fmt.Errorf("pcs/tcb: QE ISVPRODID mismatch")
+	}
+
+	for _, level := range qe.TCBLevels {
+		if report.ISVSVN < level.TCB.ISVSVN {
+			continue
+		}
+		if level.Status == TCBRevoked {
+			return tcbStatusInvalid, fmt.Errorf("pcs/tcb: QE TCB level revoked")
+		}
+		return level.Status, nil
+	}
+	return tcbStatusInvalid, fmt.Errorf("pcs/tcb: no matching QE TCB level")
+}
+
+// EnclaveTCBLevel is an enclave TCB level.
+type EnclaveTCBLevel struct {
+	TCB struct {
+		ISVSVN uint16 `json:"isvsvn"`
+	} `json:"tcb"`
+	TCBDate string    `json:"tcbDate"`
+	Status  TCBStatus `json:"tcbStatus"`
+}
+
+// pckInfo is the platform information contained in the SGX extensions of a PCK certificate.
+type pckInfo struct {
+	FMSPC         []byte
+	PCEID         []byte
+	TCBComponents [tcbComponentCount]uint8
+	PCESVN        uint16
+}
+
+type sgxExtension struct {
+	ID    asn1.ObjectIdentifier
+	Value asn1.RawValue
+}
+
+// parsePCKExtensions extracts the platform information from the SGX extensions of a PCK
+// certificate.
+func parsePCKExtensions(cert *x509.Certificate) (*pckInfo, error) {
+	var raw []byte
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oidSGXExtensions) {
+			raw = ext.Value
+			break
+		}
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("pcs/pck: missing SGX extensions")
+	}
+
+	var exts []sgxExtension
+	if _, err := asn1.Unmarshal(raw, &exts); err != nil {
+		return nil, fmt.Errorf("pcs/pck: malformed SGX extensions: %w", err)
+	}
+
+	var (
+		info   pckInfo
+		hasTCB bool
+	)
+	for _, ext := range exts {
+		switch {
+		case ext.ID.Equal(oidSGXTCB):
+			var tcbExts []sgxExtension
+			if _, err := asn1.Unmarshal(ext.Value.FullBytes, &tcbExts); err != nil {
+				return nil, fmt.Errorf("pcs/pck: malformed SGX TCB extension: %w", err)
+			}
+			if err := info.parseTCB(tcbExts); err != nil {
+				return nil, err
+			}
+			hasTCB = true
+		case ext.ID.Equal(oidSGXPCEID):
+			if _, err := asn1.Unmarshal(ext.Value.FullBytes, &info.PCEID); err != nil || len(info.PCEID) != pceIDLen {
+				return nil, fmt.Errorf("pcs/pck: malformed PCE ID extension")
+			}
+		case ext.ID.Equal(oidSGXFMSPC):
+			if _, err := asn1.Unmarshal(ext.Value.FullBytes, &info.FMSPC); err != nil || len(info.FMSPC) != fmspcLen {
+				return nil, fmt.Errorf("pcs/pck: malformed FMSPC extension")
+			}
+		default:
+		}
+	}
+	if !hasTCB || info.PCEID == nil || info.FMSPC == nil {
+		return nil, fmt.Errorf("pcs/pck: incomplete SGX extensions")
+	}
+	return &info, nil
+}
+
+func (info *pckInfo) parseTCB(exts []sgxExtension) error {
+	var seen int
+	for _, ext := range exts {
+		switch {
+		case ext.ID.Equal(oidSGXTCBPCESVN):
+			var svn int
+			if _, err := asn1.Unmarshal(ext.Value.FullBytes, &svn); err != nil || svn < 0 || svn > 0xffff {
+				return fmt.Errorf("pcs/pck: malformed PCESVN")
+			}
+			info.PCESVN = uint16(svn)
+			seen++
+		case len(ext.ID) == len(oidSGXTCB)+1 && ext.ID[:len(oidSGXTCB)].Equal(oidSGXTCB):
+			idx := ext.ID[len(oidSGXTCB)] - 1
+			if idx < 0 || idx >= tcbComponentCount {
+				// Ignore other TCB fields (e.g., CPUSVN).
+				continue
+			}
+			var svn int
+			if _, err := asn1.Unmarshal(ext.Value.FullBytes, &svn); err != nil || svn < 0 || svn > 0xff {
+				return fmt.Errorf("pcs/pck: malformed TCB component SVN")
+			}
+			info.TCBComponents[idx] = uint8(svn)
+			seen++
+		default:
+		}
+	}
+	if seen != tcbComponentCount+1 {
+		return fmt.Errorf("pcs/pck: incomplete SGX TCB extension")
+	}
+	return nil
+}
+
+func init() {
+	for k, v := range tcbStatusFwdMap {
+		tcbStatusRevMap[v] = k
+	}
+}
@@ -125,13 +125,13 @@ var (
 	// checked in Oasis Core.
 	// It is converted to TendermintAppVersion whose compatibility is checked
 	// via Tendermint's version checks.
-	ConsensusProtocol = Version{Major: 4, Minor: 0, Patch: 0}
+	ConsensusProtocol = Version{Major: 5, Minor: 0, Patch: 0}
 
 	// RuntimeHostProtocol versions the protocol between the Oasis node(s) and
 	// the runtime.
 	//
 	// NOTE: This version must be synced with runtime/src/common/version.rs.
-	RuntimeHostProtocol = Version{Major: 4, Minor: 0, Patch: 0}
+	RuntimeHostProtocol = Version{Major: 4, Minor: 1, Patch: 0}
 
 	// RuntimeCommitteeProtocol versions the P2P protocol used by the runtime
 	// committee members.
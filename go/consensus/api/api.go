@@ -27,6 +27,7 @@ import (
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 	mkvsNode "github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
 const (
@@ -264,6 +265,11 @@ type Backend interface {
 	//
 	// This may be nil in case checkpoints are disabled.
 	Checkpointer() checkpoint.Checkpointer
+
+	// DryRunUpgrade executes the consensus upgrade handler of the given pending upgrade against a
+	// copy of the latest consensus state and reports the resulting state root, without committing
+	// any changes.
+	DryRunUpgrade(ctx context.Context, descriptor *upgrade.Descriptor) (*upgrade.DryRunResult, error)
 }
 
 // HaltHook is a function that gets called when consensus needs to halt for some reason.
@@ -25,7 +25,7 @@ var (
 	lightServiceName = cmnGrpc.NewServiceName("ConsensusLight")
 
 	// methodSubmitTx is the SubmitTx method.
-	methodSubmitTx = serviceName.NewMethod("SubmitTx", transaction.SignedTransaction{})
+	methodSubmitTx = serviceName.NewMethod("SubmitTx", transaction.SignedTransaction{}).WithWriteAccess()
 	// methodStateToGenesis is the StateToGenesis method.
 	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0))
 	// methodEstimateGas is the EstimateGas method.
@@ -63,9 +63,9 @@ var (
 	// methodStateSyncIterate is the StateSyncIterate method.
 	methodStateSyncIterate = lightServiceName.NewMethod("StateSyncIterate", syncer.IterateRequest{})
 	// methodSubmitTxNoWait is the SubmitTxNoWait method.
-	methodSubmitTxNoWait = lightServiceName.NewMethod("SubmitTxNoWait", transaction.SignedTransaction{})
+	methodSubmitTxNoWait = lightServiceName.NewMethod("SubmitTxNoWait", transaction.SignedTransaction{}).WithWriteAccess()
 	// methodSubmitEvidence is the SubmitEvidence method.
-	methodSubmitEvidence = lightServiceName.NewMethod("SubmitEvidence", &Evidence{})
+	methodSubmitEvidence = lightServiceName.NewMethod("SubmitEvidence", &Evidence{}).WithWriteAccess()
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -14,6 +14,10 @@ import (
 type Genesis struct {
 	Backend    string     `json:"backend"`
 	Parameters Parameters `json:"params"`
+
+	// ImportTransforms are the names of the transforms applied to the genesis state during
+	// genesis import, in the given order.
+	ImportTransforms []string `json:"import_transforms,omitempty"`
 }
 
 // Parameters are the consensus parameters.
@@ -80,5 +84,17 @@ func (g *Genesis) SanityCheck() error {
 		m[v] = true
 	}
 
+	// Check for empty and duplicate import transforms.
+	transforms := make(map[string]bool)
+	for _, name := range g.ImportTransforms {
+		if name == "" {
+			return fmt.Errorf("consensus: sanity check failed: empty import transform name")
+		}
+		if transforms[name] {
+			return fmt.Errorf("consensus: sanity check failed: redundant import transform: '%s'", name)
+		}
+		transforms[name] = true
+	}
+
 	return nil
 }
@@ -30,7 +30,9 @@ import (
 	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
 	abciState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/abci/state"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
 	storageApi "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
@@ -187,6 +189,12 @@ func (a *ApplicationServer) EstimateGas(caller signature.PublicKey, tx *transact
 	return a.mux.EstimateGas(caller, tx)
 }
 
+// DryRunUpgrade executes the consensus upgrade handler of the given pending upgrade against a
+// copy of the latest consensus state, without committing any changes.
+func (a *ApplicationServer) DryRunUpgrade(ctx context.Context, descriptor *upgrade.Descriptor) (*upgrade.DryRunResult, error) {
+	return a.mux.DryRunUpgrade(ctx, descriptor)
+}
+
 // State returns the application state.
 func (a *ApplicationServer) State() api.ApplicationQueryState {
 	return a.mux.state
@@ -222,6 +230,8 @@ type abciMux struct {
 	appsByLexOrder []api.Application
 	appBlessed     api.Application
 
+	importTransforms map[string]api.GenesisImportTransform
+
 	lastBeginBlock int64
 	currentTime    time.Time
 
@@ -310,6 +320,12 @@ func (mux *abciMux) InitChain(req types.RequestInitChain) types.ResponseInitChai
 		panic(err)
 	}
 
+	// Apply the enabled genesis import transforms and re-check the transformed document. Note that
+	// the chain context is derived from the genesis document as given.
+	if err = mux.applyGenesisImportTransforms(st); err != nil {
+		panic(fmt.Errorf("mux: failed to apply genesis import transforms: %w", err))
+	}
+
 	resp := mux.BaseApplication.InitChain(req)
 
 	// HACK: The state is only updated iff validators or consensus parameters
@@ -366,6 +382,41 @@ func (mux *abciMux) InitChain(req types.RequestInitChain) types.ResponseInitChai
 	return resp
 }
 
+func (mux *abciMux) applyGenesisImportTransforms(doc *genesis.Document) error {
+	for _, name := range doc.Consensus.ImportTransforms {
+		t, ok := mux.importTransforms[name]
+		if !ok {
+			return fmt.Errorf("unknown genesis import transform: %s", name)
+		}
+
+		changes, err := t.Apply(doc)
+		if err != nil {
+			return fmt.Errorf("genesis import transform '%s' failed: %w", name, err)
+		}
+
+		// Record an audit log of all changes.
+		for _, change := range changes {
+			mux.logger.Info("InitChain: genesis import transform changed state",
+				"transform", name,
+				"change", change,
+			)
+		}
+		mux.logger.Info("InitChain: applied genesis import transform",
+			"transform", name,
+			"num_changes", len(changes),
+		)
+	}
+
+	if len(doc.Consensus.ImportTransforms) == 0 {
+		return nil
+	}
+	// Make sure that the transforms did not produce an invalid document.
+	if err := doc.SanityCheck(); err != nil {
+		return fmt.Errorf("transformed genesis document is invalid: %w", err)
+	}
+	return nil
+}
+
 func (mux *abciMux) dispatchHaltHooks(blockHeight int64, currentEpoch beacon.EpochTime, err error) {
 	for _, hook := range mux.haltHooks {
 		hook(mux.state.ctx, blockHeight, currentEpoch, err)
@@ -658,6 +709,78 @@ func (mux *abciMux) EstimateGas(caller signature.PublicKey, tx *transaction.Tran
 	return ctx.Gas().GasUsed(), nil
 }
 
+func (mux *abciMux) DryRunUpgrade(ctx context.Context, descriptor *upgrade.Descriptor) (result *upgrade.DryRunResult, err error) {
+	if atomic.LoadInt64(&mux.lastBeginBlock) == blockHeightInvalid {
+		return nil, consensus.ErrNoCommittedBlocks
+	}
+	upgrader := mux.state.Upgrader()
+	if upgrader == nil {
+		return nil, consensus.ErrUnsupported
+	}
+
+	// Similar to simulation, the upgrade handler operates on a separate in-memory tree so that the
+	// dry run can run in parallel to any changes to the database and its changes are discarded.
+	tree, root, now := mux.state.newDryRunTree()
+	defer tree.Close()
+
+	result = &upgrade.DryRunResult{
+		Height: int64(root.Version),
+	}
+	defer func() {
+		// Make sure a broken upgrade handler does not take down the node.
+		if p := recover(); p != nil {
+			result.StateRoot = hash.Hash{}
+			result.Error = fmt.Sprintf("upgrade handler panicked: %v", p)
+			err = nil
+		}
+	}()
+
+	blockCtx := api.NewBlockContext()
+	blockCtx.Set(api.GasAccountantKey{}, api.NewNopGasAccountant())
+	for _, mode := range []api.ContextMode{api.ContextBeginBlock, api.ContextEndBlock} {
+		abciCtx := api.NewContext(
+			ctx,
+			mode,
+			now,
+			api.NewNopGasAccountant(),
+			mux.state,
+			tree,
+			int64(root.Version),
+			blockCtx,
+			mux.state.InitialHeight(),
+		)
+		err = upgrader.DryRunConsensusUpgrade(abciCtx, descriptor)
+		abciCtx.Close()
+		switch err {
+		case nil:
+		case upgrade.ErrUpgradeNotFound, upgrade.ErrBadDescriptor:
+			return nil, err
+		default:
+			mux.logger.Warn("upgrade dry run failed",
+				"handler", descriptor.Handler,
+				"height", result.Height,
+				"mode", mode,
+				"err", err,
+			)
+			result.Error = err.Error()
+			return result, nil
+		}
+	}
+
+	_, result.StateRoot, err = tree.Commit(ctx, root.Namespace, root.Version+1, mkvs.NoPersist())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute state root: %w", err)
+	}
+
+	mux.logger.Info("upgrade dry run completed",
+		"handler", descriptor.Handler,
+		"height", result.Height,
+		"state_root", result.StateRoot,
+	)
+
+	return result, nil
+}
+
 func (mux *abciMux) notifyInvalidatedCheckTx(txHash hash.Hash, err error) {
 	if item, exists := mux.invalidatedTxs.Load(txHash); exists {
 		// Notify subscriber.
@@ -1110,6 +1233,14 @@ func (mux *abciMux) doRegister(app api.Application) error {
 		}
 		mux.appsByMethod[m] = app
 	}
+	if importer, ok := app.(api.GenesisImporter); ok {
+		for _, t := range importer.GenesisImportTransforms() {
+			if _, exists := mux.importTransforms[t.Name]; exists {
+				return fmt.Errorf("mux: genesis import transform already registered: %s", t.Name)
+			}
+			mux.importTransforms[t.Name] = t
+		}
+	}
 	mux.rebuildAppLexOrdering() // Inefficient but not a lot of apps.
 
 	app.OnRegister(mux.state, &mux.md)
@@ -1174,11 +1305,12 @@ func newABCIMux(ctx context.Context, upgrader upgrade.Backend, cfg *ApplicationC
 	}
 
 	mux := &abciMux{
-		logger:         logging.GetLogger("abci-mux"),
-		state:          state,
-		appsByName:     make(map[string]api.Application),
-		appsByMethod:   make(map[transaction.MethodName]api.Application),
-		lastBeginBlock: blockHeightInvalid,
+		logger:           logging.GetLogger("abci-mux"),
+		state:            state,
+		appsByName:       make(map[string]api.Application),
+		appsByMethod:     make(map[transaction.MethodName]api.Application),
+		importTransforms: make(map[string]api.GenesisImportTransform),
+		lastBeginBlock:   blockHeightInvalid,
 	}
 
 	mux.logger.Debug("ABCI multiplexer initialized",
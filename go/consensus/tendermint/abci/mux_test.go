@@ -0,0 +1,65 @@
+package abci
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+)
+
+func TestApplyGenesisImportTransforms(t *testing.T) {
+	require := require.New(t)
+
+	var applied []string
+	mux := &abciMux{
+		logger: logging.GetLogger("abci-mux/test"),
+		importTransforms: map[string]api.GenesisImportTransform{
+			"test.noop": {
+				Name: "test.noop",
+				Apply: func(doc *genesis.Document) ([]string, error) {
+					applied = append(applied, "test.noop")
+					return nil, nil
+				},
+			},
+			"test.invalidate": {
+				Name: "test.invalidate",
+				Apply: func(doc *genesis.Document) ([]string, error) {
+					applied = append(applied, "test.invalidate")
+					doc.ChainID = ""
+					return []string{"cleared chain ID"}, nil
+				},
+			},
+			"test.fail": {
+				Name: "test.fail",
+				Apply: func(doc *genesis.Document) ([]string, error) {
+					return nil, fmt.Errorf("failed")
+				},
+			},
+		},
+	}
+
+	// Without any enabled transforms, the document should not be touched.
+	var doc genesis.Document
+	err := mux.applyGenesisImportTransforms(&doc)
+	require.NoError(err, "applyGenesisImportTransforms")
+	require.Empty(applied, "no transforms should be applied")
+
+	doc.Consensus.ImportTransforms = []string{"test.unknown"}
+	err = mux.applyGenesisImportTransforms(&doc)
+	require.Error(err, "unknown transforms should be rejected")
+
+	doc.Consensus.ImportTransforms = []string{"test.fail"}
+	err = mux.applyGenesisImportTransforms(&doc)
+	require.Error(err, "failing transforms should be reported")
+
+	// The transformed document should be sanity checked.
+	doc.Consensus.ImportTransforms = []string{"test.noop", "test.invalidate"}
+	err = mux.applyGenesisImportTransforms(&doc)
+	require.Error(err, "transforms producing an invalid document should be rejected")
+	require.Contains(err.Error(), "transformed genesis document is invalid")
+	require.Equal([]string{"test.noop", "test.invalidate"}, applied, "transforms should be applied in order")
+}
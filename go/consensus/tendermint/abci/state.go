@@ -31,8 +31,8 @@ import (
 
 var _ api.ApplicationState = (*applicationState)(nil)
 
-// appStateDir is the subdirectory which contains ABCI state.
-const appStateDir = "abci-state"
+// AppStateDir is the subdirectory which contains ABCI state.
+const AppStateDir = "abci-state"
 
 type applicationState struct { // nolint: maligned
 	logger *logging.Logger
@@ -112,6 +112,15 @@ func (s *applicationState) NewContext(mode api.ContextMode, now time.Time) *api.
 	)
 }
 
+// newDryRunTree creates a separate in-memory tree at the latest committed state that can be
+// modified in parallel to any changes to the database. The tree must be closed after use.
+func (s *applicationState) newDryRunTree() (mkvs.Tree, storage.Root, time.Time) {
+	s.blockLock.RLock()
+	defer s.blockLock.RUnlock()
+
+	return mkvs.NewWithRoot(nil, s.storage.NodeDB(), s.stateRoot, mkvs.WithoutWriteLog()), s.stateRoot, s.blockTime
+}
+
 func (s *applicationState) LastRetainedVersion() (int64, error) {
 	return int64(s.statePruner.GetLastRetainedVersion()), nil
 }
@@ -430,7 +439,7 @@ func (s *applicationState) pruneWorker() {
 
 // InitStateStorage initializes the internal ABCI state storage.
 func InitStateStorage(ctx context.Context, cfg *ApplicationConfig) (storage.LocalBackend, storage.NodeDB, *storage.Root, error) {
-	baseDir := filepath.Join(cfg.DataDir, appStateDir)
+	baseDir := filepath.Join(cfg.DataDir, AppStateDir)
 	switch cfg.ReadOnlyStorage {
 	case true:
 		// Note: I'm not sure what badger does when given a path that
@@ -33,6 +33,11 @@ const (
 	// LogEventPeerExchangeDisable is a log event that indicates that
 	// Tendermint's peer exchange has been disabled.
 	LogEventPeerExchangeDisabled = "tendermint/peer_exchange_disabled"
+
+	// LogEventStateSyncProviderFailed is a log event that indicates that the
+	// state sync state provider could not be created (e.g., because the
+	// configured trust root does not match the chain served by the peers).
+	LogEventStateSyncProviderFailed = "tendermint/state_sync_provider_failed"
 )
 
 // PublicKeyToValidatorUpdate converts an Oasis node public key to a
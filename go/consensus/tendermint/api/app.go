@@ -41,6 +41,26 @@ func (nd *NoopMessageDispatcher) Publish(*Context, interface{}, interface{}) err
 	return nil
 }
 
+// GenesisImportTransform is a transform of the genesis document applied during genesis import
+// when enabled in the consensus genesis state.
+type GenesisImportTransform struct {
+	// Name is the globally unique name of the transform.
+	Name string
+
+	// Apply applies the transform to the genesis document in place and returns a description
+	// of each change that was made.
+	//
+	// Note: Transforms must be deterministic as they affect the initial consensus state.
+	Apply func(doc *genesis.Document) ([]string, error)
+}
+
+// GenesisImporter is an optional interface implemented by applications that provide transforms
+// of the genesis document applied during genesis import.
+type GenesisImporter interface {
+	// GenesisImportTransforms returns the genesis import transforms provided by the application.
+	GenesisImportTransforms() []GenesisImportTransform
+}
+
 // Application is the interface implemented by multiplexed Oasis-specific
 // ABCI applications.
 type Application interface {
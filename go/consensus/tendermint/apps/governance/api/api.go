@@ -0,0 +1,11 @@
+// Package api defines the governance application API for other applications.
+package api
+
+type messageKind uint8
+
+var (
+	// MessageSetRoundTimeout is the message kind for passed executor round timeout override
+	// proposals. The message is the round timeout override proposal. Any errors returned from the
+	// handler will cause the proposal execution to fail.
+	MessageSetRoundTimeout = messageKind(0)
+)
@@ -10,6 +10,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	governanceApi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/governance/api"
 	governanceState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/governance/state"
 	registryapp "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
@@ -26,6 +27,7 @@ var _ api.Application = (*governanceApplication)(nil)
 
 type governanceApplication struct {
 	state api.ApplicationState
+	md    api.MessageDispatcher
 }
 
 func (app *governanceApplication) Name() string {
@@ -50,6 +52,7 @@ func (app *governanceApplication) Dependencies() []string {
 
 func (app *governanceApplication) OnRegister(state api.ApplicationState, md api.MessageDispatcher) {
 	app.state = state
+	app.md = md
 
 	// Subscribe to messages emitted by other apps.
 	md.Subscribe(api.MessageStateSyncCompleted, app)
@@ -260,6 +263,11 @@ func (app *governanceApplication) executeProposal(ctx *api.Context, state *gover
 				)
 			}
 		}
+	case proposal.Content.SetRoundTimeout != nil:
+		// Let the roothash application schedule the round timeout override.
+		if err := app.md.Publish(ctx, governanceApi.MessageSetRoundTimeout, proposal.Content.SetRoundTimeout); err != nil {
+			return fmt.Errorf("failed to set round timeout: %w", err)
+		}
 	default:
 		return governance.ErrInvalidArgument
 	}
@@ -7,10 +7,12 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	governanceState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/governance/state"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
+	roothashState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/roothash/state"
 	schedulerState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/scheduler/state"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking/state"
 	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
 	registryAPI "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothashAPI "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	stakingAPI "github.com/oasisprotocol/oasis-core/go/staking/api"
 	upgradeAPI "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
@@ -127,6 +129,33 @@ func (app *governanceApplication) submitProposal(
 		if upgrade.Descriptor.Epoch < params.UpgradeCancelMinEpochDiff+epoch {
 			return governance.ErrUpgradeTooSoon
 		}
+
+	case proposalContent.SetRoundTimeout != nil:
+		setRoundTimeout := proposalContent.SetRoundTimeout
+		// Ensure round timeout overrides are enabled and the round timeout is within bounds.
+		rhState := roothashState.NewMutableState(ctx.State())
+		var rhParams *roothashAPI.ConsensusParameters
+		rhParams, err = rhState.ConsensusParameters(ctx)
+		if err != nil {
+			return fmt.Errorf("governance: failed to fetch roothash consensus parameters: %w", err)
+		}
+		if err = rhParams.VerifyRoundTimeoutOverride(setRoundTimeout.RoundTimeout); err != nil {
+			ctx.Logger().Error("governance: round timeout override not allowed",
+				"runtime_id", setRoundTimeout.RuntimeID,
+				"round_timeout", setRoundTimeout.RoundTimeout,
+				"err", err,
+			)
+			return fmt.Errorf("%w: %v", governance.ErrInvalidArgument, err)
+		}
+
+		// Ensure the runtime exists.
+		if _, err = rhState.RuntimeState(ctx, setRoundTimeout.RuntimeID); err != nil {
+			ctx.Logger().Error("governance: round timeout override for a non existing runtime",
+				"runtime_id", setRoundTimeout.RuntimeID,
+				"err", err,
+			)
+			return fmt.Errorf("%w: %v", governance.ErrInvalidArgument, err)
+		}
 	}
 
 	// Deposit proposal funds.
@@ -8,14 +8,19 @@ import (
 	"github.com/stretchr/testify/require"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	governanceState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/governance/state"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
+	roothashState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/roothash/state"
 	schedulerState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/scheduler/state"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking/state"
 	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
@@ -44,6 +49,28 @@ func TestSubmitProposal(t *testing.T) {
 	})
 	require.NoError(err, "SetAccount")
 
+	// Setup roothash state.
+	rhState := roothashState.NewMutableState(ctx.State())
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("tendermint/apps/governance/transactions_test: runtime"), 0)
+	unknownRuntimeID := common.NewTestNamespaceFromSeed([]byte("tendermint/apps/governance/transactions_test: unknown runtime"), 0)
+	err = rhState.SetConsensusParameters(ctx, &roothash.ConsensusParameters{})
+	require.NoError(err, "SetConsensusParameters")
+	err = rhState.SetRuntimeState(ctx, &roothash.RuntimeState{
+		Runtime:      &registry.Runtime{ID: runtimeID},
+		GenesisBlock: block.NewGenesisBlock(runtimeID, 0),
+		CurrentBlock: block.NewGenesisBlock(runtimeID, 0),
+	})
+	require.NoError(err, "SetRuntimeState")
+	enableRoundTimeoutOverrides := func() {
+		err = rhState.SetConsensusParameters(ctx, &roothash.ConsensusParameters{
+			RoundTimeoutOverride: &roothash.RoundTimeoutOverrideParameters{
+				MinRoundTimeout: 5,
+				MaxRoundTimeout: 100,
+			},
+		})
+		require.NoError(err, "SetConsensusParameters")
+	}
+
 	// Setup governance state.
 	state := governanceState.NewMutableState(ctx.State())
 	app := &governanceApplication{
@@ -226,6 +253,50 @@ func TestSubmitProposal(t *testing.T) {
 			},
 			governance.ErrUpgradeAlreadyPending,
 		},
+		{
+			"should fail set round timeout proposal when round timeout overrides are not enabled",
+			baseConsParams,
+			pk1,
+			&governance.ProposalContent{SetRoundTimeout: &governance.SetRoundTimeoutProposal{
+				RuntimeID:    runtimeID,
+				RoundTimeout: 10,
+			}},
+			func() {},
+			governance.ErrInvalidArgument,
+		},
+		{
+			"should fail set round timeout proposal with round timeout out of bounds",
+			baseConsParams,
+			pk1,
+			&governance.ProposalContent{SetRoundTimeout: &governance.SetRoundTimeoutProposal{
+				RuntimeID:    runtimeID,
+				RoundTimeout: 1000,
+			}},
+			enableRoundTimeoutOverrides,
+			governance.ErrInvalidArgument,
+		},
+		{
+			"should fail set round timeout proposal for non-existing runtime",
+			baseConsParams,
+			pk1,
+			&governance.ProposalContent{SetRoundTimeout: &governance.SetRoundTimeoutProposal{
+				RuntimeID:    unknownRuntimeID,
+				RoundTimeout: 10,
+			}},
+			func() {},
+			governance.ErrInvalidArgument,
+		},
+		{
+			"should work with valid set round timeout proposal",
+			baseConsParams,
+			pk1,
+			&governance.ProposalContent{SetRoundTimeout: &governance.SetRoundTimeoutProposal{
+				RuntimeID:    runtimeID,
+				RoundTimeout: 10,
+			}},
+			func() {},
+			nil,
+		},
 	} {
 		err = state.SetConsensusParameters(ctx, tc.params)
 		require.NoError(err, "setting governance consensus parameters should not error")
@@ -11,6 +11,10 @@ const (
 
 	// AppName is the ABCI application name.
 	AppName string = "200_registry"
+
+	// ImportTransformPruneExpiredNodes is the name of the genesis import transform that removes
+	// nodes that are expired at the genesis epoch together with their statuses.
+	ImportTransformPruneExpiredNodes = "registry.prune_expired_nodes"
 )
 
 var (
@@ -47,6 +51,11 @@ var (
 	// become unfrozen (value is CBOR serialized node ID).
 	KeyNodeUnfrozen = []byte("nodes.unfrozen")
 
+	// KeyNodeAttestation is the ABCI event attribute for decisions on
+	// the TEE attestations of node runtimes (value is a CBOR serialized
+	// node attestation event).
+	KeyNodeAttestation = []byte("nodes.attestation")
+
 	// KeyRegistryNodeListEpoch is the ABCI event attribute for
 	// registry epochs.
 	KeyRegistryNodeListEpoch = []byte("nodes.epoch")
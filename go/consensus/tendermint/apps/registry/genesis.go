@@ -120,6 +120,47 @@ func (app *registryApplication) InitChain(ctx *abciAPI.Context, request types.Re
 	return nil
 }
 
+// Implements abciAPI.GenesisImporter.
+func (app *registryApplication) GenesisImportTransforms() []abciAPI.GenesisImportTransform {
+	return []abciAPI.GenesisImportTransform{
+		{
+			Name:  ImportTransformPruneExpiredNodes,
+			Apply: pruneExpiredNodes,
+		},
+	}
+}
+
+func pruneExpiredNodes(doc *genesis.Document) ([]string, error) {
+	epoch := uint64(doc.Beacon.Base)
+
+	var (
+		changes []string
+		nodes   []*node.MultiSignedNode
+	)
+	for i, sigNode := range doc.Registry.Nodes {
+		if sigNode == nil {
+			return nil, fmt.Errorf("registry: genesis node index %d is nil", i)
+		}
+		var n node.Node
+		if err := sigNode.Open(registry.RegisterGenesisNodeSignatureContext, &n); err != nil {
+			return nil, fmt.Errorf("registry: failed to open genesis node index %d: %w", i, err)
+		}
+		if !n.IsExpired(epoch) {
+			nodes = append(nodes, sigNode)
+			continue
+		}
+
+		changes = append(changes, fmt.Sprintf("removed node %s (expired at epoch %d)", n.ID, n.Expiration))
+		if _, ok := doc.Registry.NodeStatuses[n.ID]; ok {
+			delete(doc.Registry.NodeStatuses, n.ID)
+			changes = append(changes, fmt.Sprintf("removed status of node %s", n.ID))
+		}
+	}
+	doc.Registry.Nodes = nodes
+
+	return changes, nil
+}
+
 func (rq *registryQuerier) Genesis(ctx context.Context) (*registry.Genesis, error) {
 	// Fetch entities, runtimes, and nodes from state.
 	signedEntities, err := rq.state.SignedEntities(ctx)
@@ -0,0 +1,65 @@
+package registry
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+)
+
+func TestPruneExpiredNodes(t *testing.T) {
+	require := require.New(t)
+
+	var doc genesis.Document
+	doc.Beacon.Base = beacon.EpochTime(10)
+	doc.Registry.NodeStatuses = make(map[signature.PublicKey]*registry.NodeStatus)
+
+	var ids []signature.PublicKey
+	for i, expiration := range []uint64{5, 9, 10, 11} {
+		signer := memorySigner.NewTestSigner(fmt.Sprintf("prune expired nodes %d", i))
+		n := node.Node{
+			Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+			ID:         signer.Public(),
+			Expiration: expiration,
+		}
+		sigNode, err := node.MultiSignNode([]signature.Signer{signer}, registry.RegisterGenesisNodeSignatureContext, &n)
+		require.NoError(err, "MultiSignNode")
+
+		doc.Registry.Nodes = append(doc.Registry.Nodes, sigNode)
+		doc.Registry.NodeStatuses[n.ID] = &registry.NodeStatus{}
+		ids = append(ids, n.ID)
+	}
+
+	changes, err := pruneExpiredNodes(&doc)
+	require.NoError(err, "pruneExpiredNodes")
+	require.Len(changes, 4, "removal of each expired node and its status should be recorded")
+
+	require.Len(doc.Registry.Nodes, 2, "expired nodes should be removed")
+	for i, sigNode := range doc.Registry.Nodes {
+		var n node.Node
+		err = sigNode.Open(registry.RegisterGenesisNodeSignatureContext, &n)
+		require.NoError(err, "Open")
+		require.Equal(ids[i+2], n.ID, "non-expired nodes should be kept in order")
+	}
+	require.Len(doc.Registry.NodeStatuses, 2, "statuses of expired nodes should be removed")
+	require.Contains(doc.Registry.NodeStatuses, ids[2])
+	require.Contains(doc.Registry.NodeStatuses, ids[3])
+
+	// Applying the transform again should not change anything.
+	changes, err = pruneExpiredNodes(&doc)
+	require.NoError(err, "pruneExpiredNodes")
+	require.Empty(changes, "no changes should be recorded")
+
+	// Invalid nodes should be reported.
+	doc.Registry.Nodes = append(doc.Registry.Nodes, nil)
+	_, err = pruneExpiredNodes(&doc)
+	require.Error(err, "pruneExpiredNodes should fail for nil nodes")
+}
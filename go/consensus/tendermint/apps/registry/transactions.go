@@ -1,6 +1,7 @@
 package registry
 
 import (
+	"errors"
 	"fmt"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
@@ -215,6 +216,18 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 		state,
 	)
 	if err != nil {
+		// Record the rejected attestation so that operators can figure out why
+		// the node failed to register.
+		var attErr *registry.AttestationError
+		if errors.As(err, &attErr) {
+			ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyNodeAttestation, cbor.Marshal(&registry.NodeAttestationEvent{
+				NodeID:    untrustedNode.ID,
+				RuntimeID: attErr.RuntimeID,
+				Accepted:  false,
+				Reason:    attErr.Reason(),
+				Error:     attErr.Error(),
+			})))
+		}
 		return err
 	}
 
@@ -534,6 +547,16 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 	)
 
 	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyNodeRegistered, cbor.Marshal(newNode)))
+	for _, rt := range newNode.Runtimes {
+		if rt.Capabilities.TEE == nil {
+			continue
+		}
+		ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyNodeAttestation, cbor.Marshal(&registry.NodeAttestationEvent{
+			NodeID:    newNode.ID,
+			RuntimeID: rt.ID,
+			Accepted:  true,
+		})))
+	}
 
 	return nil
 }
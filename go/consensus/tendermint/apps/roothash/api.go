@@ -50,6 +50,9 @@ var (
 	// KeyMessage is an ABCI event attribute key for message result events
 	// (value is a CBOR serialized ValueMessage).
 	KeyMessage = []byte("message")
+	// KeyEvidence is an ABCI event attribute key for accepted evidence
+	// events (value is a CBOR serialized ValueEvidence).
+	KeyEvidence = []byte("evidence")
 )
 
 // QueryForRuntime returns a query for filtering transactions processed by the roothash application
@@ -87,3 +90,9 @@ type ValueMessage struct {
 	ID    common.Namespace      `json:"id"`
 	Event roothash.MessageEvent `json:"event"`
 }
+
+// ValueEvidence is the value component of a KeyEvidence.
+type ValueEvidence struct {
+	ID    common.Namespace       `json:"id"`
+	Event roothash.EvidenceEvent `json:"event"`
+}
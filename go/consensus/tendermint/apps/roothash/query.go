@@ -17,6 +17,7 @@ type Query interface {
 	RuntimeState(context.Context, common.Namespace) (*roothash.RuntimeState, error)
 	Genesis(context.Context) (*roothash.Genesis, error)
 	ConsensusParameters(context.Context) (*roothash.ConsensusParameters, error)
+	RetainedEvidence(context.Context, common.Namespace) ([]*roothash.RetainedEvidence, error)
 }
 
 // QueryFactory is the roothash query factory.
@@ -61,6 +62,10 @@ func (rq *rootHashQuerier) ConsensusParameters(ctx context.Context) (*roothash.C
 	return rq.state.ConsensusParameters(ctx)
 }
 
+func (rq *rootHashQuerier) RetainedEvidence(ctx context.Context, id common.Namespace) ([]*roothash.RetainedEvidence, error) {
+	return rq.state.RetainedEvidence(ctx, id)
+}
+
 func (app *rootHashApplication) QueryFactory() interface{} {
 	return &QueryFactory{app.state}
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	tmapi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	governanceApi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/governance/api"
 	registryApi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/api"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
 	roothashApi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/roothash/api"
@@ -21,6 +22,7 @@ import (
 	schedulerState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/scheduler/state"
 	stakingapp "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking/state"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
@@ -65,6 +67,7 @@ func (app *rootHashApplication) OnRegister(state tmapi.ApplicationState, md tmap
 	md.Subscribe(registryApi.MessageRuntimeUpdated, app)
 	md.Subscribe(registryApi.MessageRuntimeResumed, app)
 	md.Subscribe(roothashApi.RuntimeMessageNoop, app)
+	md.Subscribe(governanceApi.MessageSetRoundTimeout, app)
 }
 
 func (app *rootHashApplication) OnCleanup() {
@@ -118,6 +121,19 @@ func (app *rootHashApplication) onCommitteeChanged(ctx *tmapi.Context, state *ro
 			return fmt.Errorf("failed to fetch runtime state: %w", err)
 		}
 
+		// Apply any pending round timeout override.
+		if pending := rtState.PendingRoundTimeoutOverride; pending != nil {
+			ctx.Logger().Debug("applying round timeout override",
+				"runtime", rt.ID,
+				"round_timeout", *pending,
+			)
+			rtState.RoundTimeoutOverride = *pending
+			rtState.PendingRoundTimeoutOverride = nil
+		}
+		if rtState.RoundTimeoutOverride > 0 {
+			rt.Executor.RoundTimeout = rtState.RoundTimeoutOverride
+		}
+
 		// Expire past evidence of runtime node misbehaviour.
 		if rtState.CurrentBlock != nil {
 			if round := rtState.CurrentBlock.Header.Round; round > params.MaxEvidenceAge {
@@ -330,11 +346,39 @@ func (app *rootHashApplication) ExecuteMessage(ctx *tmapi.Context, kind, msg int
 	case roothashApi.RuntimeMessageNoop:
 		// Noop message always succeeds.
 		return nil
+	case governanceApi.MessageSetRoundTimeout:
+		// A round timeout override proposal has passed.
+		return app.onSetRoundTimeoutProposal(ctx, msg.(*governance.SetRoundTimeoutProposal))
 	default:
 		return roothash.ErrInvalidArgument
 	}
 }
 
+func (app *rootHashApplication) onSetRoundTimeoutProposal(ctx *tmapi.Context, proposal *governance.SetRoundTimeoutProposal) error {
+	state := roothashState.NewMutableState(ctx.State())
+
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get consensus parameters: %w", err)
+	}
+	// Parameters may have changed since the proposal has been submitted.
+	if err = params.VerifyRoundTimeoutOverride(proposal.RoundTimeout); err != nil {
+		return err
+	}
+
+	rtState, err := state.RuntimeState(ctx, proposal.RuntimeID)
+	if err != nil {
+		return err
+	}
+
+	ctx.Logger().Debug("scheduling round timeout override from governance",
+		"runtime", proposal.RuntimeID,
+		"round_timeout", proposal.RoundTimeout,
+	)
+
+	return scheduleRoundTimeoutOverride(ctx, state, rtState, proposal.RoundTimeout)
+}
+
 func (app *rootHashApplication) verifyRuntimeUpdate(ctx *tmapi.Context, rt *registry.Runtime) error {
 	state := roothashState.NewMutableState(ctx.State())
 
@@ -371,6 +415,13 @@ func (app *rootHashApplication) ExecuteTx(ctx *tmapi.Context, tx *transaction.Tr
 		}
 
 		return app.submitEvidence(ctx, state, &ev)
+	case roothash.MethodSetRoundTimeout:
+		var srt roothash.SetRoundTimeout
+		if err := cbor.Unmarshal(tx.Body, &srt); err != nil {
+			return err
+		}
+
+		return app.setRoundTimeout(ctx, state, &srt)
 	default:
 		return roothash.ErrInvalidArgument
 	}
@@ -189,6 +189,33 @@ func (s *ImmutableState) EvidenceHashExists(ctx context.Context, runtimeID commo
 	return data != nil, api.UnavailableStateError(err)
 }
 
+// RetainedEvidence returns the retained evidence hashes for the runtime, ordered by round.
+func (s *ImmutableState) RetainedEvidence(ctx context.Context, runtimeID common.Namespace) ([]*roothash.RetainedEvidence, error) {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	hID := hashedRuntimeID(runtimeID)
+
+	var evidence []*roothash.RetainedEvidence
+	for it.Seek(evidenceKeyFmt.Encode(&runtimeID)); it.Valid(); it.Next() {
+		var hRuntimeID keyformat.PreHashed
+		var ev roothash.RetainedEvidence
+		if !evidenceKeyFmt.Decode(it.Key(), &hRuntimeID, &ev.Round, &ev.Hash) || !hRuntimeID.Equal(&hID) {
+			break
+		}
+		evidence = append(evidence, &ev)
+	}
+	if it.Err() != nil {
+		return nil, api.UnavailableStateError(it.Err())
+	}
+	return evidence, nil
+}
+
+// hashedRuntimeID returns the runtime identifier as it is encoded in hashed key formats.
+func hashedRuntimeID(runtimeID common.Namespace) keyformat.PreHashed {
+	return keyformat.PreHashed(hash.NewFromBytes(runtimeID[:]))
+}
+
 // MutableState is the mutable roothash state wrapper.
 type MutableState struct {
 	*ImmutableState
@@ -260,12 +287,14 @@ func (s *MutableState) RemoveExpiredEvidence(ctx context.Context, runtimeID comm
 	it := s.is.NewIterator(ctx)
 	defer it.Close()
 
+	hID := hashedRuntimeID(runtimeID)
+
 	var toDelete [][]byte
 	for it.Seek(evidenceKeyFmt.Encode(&runtimeID)); it.Valid(); it.Next() {
-		var runtimeID keyformat.PreHashed
+		var hRuntimeID keyformat.PreHashed
 		var round uint64
 		var hash hash.Hash
-		if !evidenceKeyFmt.Decode(it.Key(), &runtimeID, &round, &hash) {
+		if !evidenceKeyFmt.Decode(it.Key(), &hRuntimeID, &round, &hash) || !hRuntimeID.Equal(&hID) {
 			break
 		}
 		if round > minRound {
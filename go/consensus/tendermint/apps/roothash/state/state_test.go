@@ -97,9 +97,26 @@ func TestEvidence(t *testing.T) {
 	require.NoError(err, "EvidenceHashExists")
 	require.True(b, "Evidence hash should exist")
 
+	retained, err := s.RetainedEvidence(ctx, rt1ID)
+	require.NoError(err, "RetainedEvidence")
+	require.Len(retained, 3, "all runtime evidence should be retained")
+	for i, round := range []uint64{0, 10, 20} {
+		require.EqualValues(round, retained[i].Round, "retained evidence should be ordered by round")
+	}
+	retained, err = s.RetainedEvidence(ctx, rt3ID)
+	require.NoError(err, "RetainedEvidence")
+	require.Empty(retained, "there should be no evidence for a runtime without evidence")
+
 	// Expire evidence.
 	err = s.RemoveExpiredEvidence(ctx, rt1ID, 10)
 	require.NoError(err, "RemoveExpiredEvidence")
+	retained, err = s.RetainedEvidence(ctx, rt1ID)
+	require.NoError(err, "RetainedEvidence")
+	require.Len(retained, 1, "expired evidence should be removed")
+	retained, err = s.RetainedEvidence(ctx, rt2ID)
+	require.NoError(err, "RetainedEvidence")
+	require.Len(retained, 3, "evidence of other runtimes should not be removed")
+
 	err = s.RemoveExpiredEvidence(ctx, rt2ID, 10)
 	require.NoError(err, "RemoveExpiredEvidence")
 	err = s.RemoveExpiredEvidence(ctx, rt3ID, 1)
@@ -206,13 +206,28 @@ func (app *rootHashApplication) executorCommit(
 		return err
 	}
 
+	if len(cc.AggregatedCommits) > 0 && !params.AllowAggregatedCommitments {
+		return fmt.Errorf("%w: aggregated commitments not enabled", roothash.ErrInvalidArgument)
+	}
+
 	rtState, sv, nl, err := app.getRuntimeState(ctx, state, cc.ID)
 	if err != nil {
 		return err
 	}
 
-	// Account for gas consumed by messages.
+	// Enforce the effective message limit (if enabled) and account for gas consumed by messages.
 	msgGasAccountant := func(msgs []message.Message) error {
+		if params.EnforceMaxRuntimeMessages {
+			if maxMessages := params.EffectiveMaxRuntimeMessages(rtState.Runtime); uint32(len(msgs)) > maxMessages {
+				ctx.Logger().Debug("ComputeCommit: too many runtime messages",
+					"runtime_id", rtState.Runtime.ID,
+					"num_messages", len(msgs),
+					"max_messages", maxMessages,
+				)
+				return commitment.ErrInvalidMessages
+			}
+		}
+
 		// Deliver messages in the simulation context to estimate gas.
 		msgCtx := ctx.WithSimulation()
 		defer msgCtx.Close()
@@ -236,6 +251,22 @@ func (app *rootHashApplication) executorCommit(
 			return err
 		}
 	}
+	for i := range cc.AggregatedCommits {
+		if err = rtState.ExecutorPool.AddAggregatedExecutorCommitment(
+			ctx,
+			rtState.CurrentBlock,
+			sv,
+			nl,
+			&cc.AggregatedCommits[i],
+			msgGasAccountant,
+		); err != nil {
+			ctx.Logger().Error("failed to add aggregated compute commitment to round",
+				"err", err,
+				"round", rtState.CurrentBlock.Header.Round,
+			)
+			return err
+		}
+	}
 
 	// Return early for simulation as we only need gas accounting.
 	if ctx.IsSimulation() {
@@ -256,7 +287,11 @@ func (app *rootHashApplication) executorCommit(
 	}
 
 	// Emit events for all accepted commits.
-	for _, commit := range cc.Commits {
+	commits := append([]commitment.ExecutorCommitment{}, cc.Commits...)
+	for i := range cc.AggregatedCommits {
+		commits = append(commits, cc.AggregatedCommits[i].Commitments()...)
+	}
+	for _, commit := range commits {
 		evV := ValueExecutorCommitted{
 			ID: cc.ID,
 			Event: roothash.ExecutorCommittedEvent{
@@ -354,7 +389,7 @@ func (app *rootHashApplication) submitEvidence(
 
 		if batchA.Header.Round+params.MaxEvidenceAge < rtState.CurrentBlock.Header.Round {
 			ctx.Logger().Error("Evidence: proposed batch equivocation evidence expired",
-				"evidence", evidence.EquivocationExecutor,
+				"evidence", evidence.EquivocationBatch,
 				"current_round", rtState.CurrentBlock.Header.Round,
 				"max_evidence_age", params.MaxEvidenceAge,
 			)
@@ -392,5 +427,99 @@ func (app *rootHashApplication) submitEvidence(
 		return fmt.Errorf("error slashing runtime node: %w", err)
 	}
 
+	evV := ValueEvidence{
+		ID: rtState.Runtime.ID,
+		Event: roothash.EvidenceEvent{
+			Round: round,
+			Hash:  evHash,
+			Node:  pk,
+		},
+	}
+	ctx.EmitEvent(
+		abciAPI.NewEventBuilder(app.Name()).
+			Attribute(KeyEvidence, cbor.Marshal(evV)).
+			Attribute(KeyRuntimeID, ValueRuntimeID(rtState.Runtime.ID)),
+	)
+
+	return nil
+}
+
+func (app *rootHashApplication) setRoundTimeout(
+	ctx *abciAPI.Context,
+	state *roothashState.MutableState,
+	srt *roothash.SetRoundTimeout,
+) error {
+	if err := srt.ValidateBasic(); err != nil {
+		ctx.Logger().Error("SetRoundTimeout: invalid round timeout",
+			"round_timeout", srt.RoundTimeout,
+			"err", err,
+		)
+		return fmt.Errorf("%w: %v", roothash.ErrInvalidArgument, err)
+	}
+
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error("SetRoundTimeout: failed to fetch consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+	if err = params.VerifyRoundTimeoutOverride(srt.RoundTimeout); err != nil {
+		ctx.Logger().Error("SetRoundTimeout: round timeout override not allowed",
+			"round_timeout", srt.RoundTimeout,
+			"err", err,
+		)
+		return err
+	}
+
+	// Charge gas for this transaction.
+	if err = ctx.Gas().UseGas(1, roothash.GasOpSetRoundTimeout, params.GasCosts); err != nil {
+		return err
+	}
+
+	// Return early for simulation as we only need gas accounting.
+	if ctx.IsSimulation() {
+		return nil
+	}
+
+	rtState, err := state.RuntimeState(ctx, srt.ID)
+	if err != nil {
+		return err
+	}
+
+	// Make sure the caller is the entity or runtime controlling the runtime. Runtimes using the
+	// consensus layer governance model can only be changed via the consensus layer.
+	expectedAddr := rtState.Runtime.StakingAddress()
+	if expectedAddr == nil || !ctx.CallerAddress().Equal(*expectedAddr) {
+		ctx.Logger().Error("SetRoundTimeout: caller does not control the runtime",
+			"runtime_id", srt.ID,
+			"caller", ctx.CallerAddress(),
+			"gov_model", rtState.Runtime.GovernanceModel,
+		)
+		return roothash.ErrForbidden
+	}
+
+	return scheduleRoundTimeoutOverride(ctx, state, rtState, srt.RoundTimeout)
+}
+
+// scheduleRoundTimeoutOverride schedules the executor round timeout override of the runtime.
+//
+// The override only takes effect at the next epoch transition so that the round timeout doesn't
+// change in the middle of an epoch.
+func scheduleRoundTimeoutOverride(
+	ctx *abciAPI.Context,
+	state *roothashState.MutableState,
+	rtState *roothash.RuntimeState,
+	roundTimeout int64,
+) error {
+	rtState.PendingRoundTimeoutOverride = &roundTimeout
+
+	if err := state.SetRuntimeState(ctx, rtState); err != nil {
+		return fmt.Errorf("failed to set runtime state: %w", err)
+	}
 	return nil
 }
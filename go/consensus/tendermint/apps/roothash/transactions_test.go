@@ -1,6 +1,7 @@
 package roothash
 
 import (
+	"bytes"
 	"crypto/rand"
 	"math"
 	"testing"
@@ -17,12 +18,14 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	governanceApi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/governance/api"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
 	roothashApi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/roothash/api"
 	roothashState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/roothash/state"
 	schedulerState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/scheduler/state"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking/state"
 	genesisTestHelpers "github.com/oasisprotocol/oasis-core/go/genesis/tests"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
@@ -238,11 +241,65 @@ func TestMessagesGasEstimation(t *testing.T) {
 		Commits: []commitment.ExecutorCommitment{*commit},
 	}
 
+	// Commitments exceeding the effective message limit should be rejected even if they are
+	// within the runtime's own limit (e.g., the global limit has been lowered).
+	err = roothashState.SetConsensusParameters(ctx, &roothash.ConsensusParameters{
+		MaxRuntimeMessages:        uint32(len(msgs) - 1),
+		EnforceMaxRuntimeMessages: true,
+	})
+	require.NoError(err, "SetConsensusParameters")
+	limitCtx := appState.NewContext(abciAPI.ContextEndBlock, now)
+	limitCtx.SetGasAccountant(abciAPI.NewGasAccountant(transaction.Gas(math.MaxUint64)))
+	err = app.executorCommit(limitCtx, roothashState, cc)
+	limitCtx.Close()
+	require.ErrorIs(err, commitment.ErrInvalidMessages, "ExecutorCommit should fail with too many messages")
+
+	// Without enforcement only the runtime's own limit applies.
+	err = roothashState.SetConsensusParameters(ctx, &roothash.ConsensusParameters{
+		MaxRuntimeMessages: uint32(len(msgs) - 1),
+	})
+	require.NoError(err, "SetConsensusParameters")
 	err = app.executorCommit(ctx, roothashState, cc)
 	require.NoError(err, "ExecutorCommit")
 	require.EqualValues(12000, ctx.Gas().GasUsed(), "gas amount should be correct")
 }
 
+func TestAggregatedCommitmentsGate(t *testing.T) {
+	require := require.New(t)
+	var err error
+
+	genesisTestHelpers.SetTestChainContext()
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock, now)
+	defer ctx.Close()
+	ctx.SetGasAccountant(abciAPI.NewGasAccountant(transaction.Gas(math.MaxUint64)))
+
+	var md testMsgDispatcher
+	app := rootHashApplication{appState, &md}
+
+	roothashState := roothashState.NewMutableState(ctx.State())
+	err = roothashState.SetConsensusParameters(ctx, &roothash.ConsensusParameters{
+		MaxRuntimeMessages: 32,
+	})
+	require.NoError(err, "SetConsensusParameters")
+
+	sk, err := memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner")
+	rtID := common.NewTestNamespaceFromSeed([]byte("aggregated commitments gate test"), 0)
+	commit, err := commitment.SignExecutorCommitment(sk, rtID, &commitment.ComputeBody{})
+	require.NoError(err, "SignExecutorCommitment")
+	aggregated, _ := commitment.AggregateExecutorCommitments([]commitment.ExecutorCommitment{*commit, *commit})
+
+	// Aggregated commitments should be rejected unless enabled.
+	err = app.executorCommit(ctx, roothashState, &roothash.ExecutorCommit{
+		ID:                rtID,
+		AggregatedCommits: aggregated,
+	})
+	require.ErrorIs(err, roothash.ErrInvalidArgument, "ExecutorCommit should fail with aggregated commitments disabled")
+}
+
 func TestEvidence(t *testing.T) {
 	require := require.New(t)
 	var err error
@@ -620,4 +677,220 @@ func TestEvidence(t *testing.T) {
 	entAcc, err := stakingState.Account(ctx, staking.NewAddress(nod.EntityID))
 	require.NoError(err, "Account()")
 	require.EqualValues(entityEscrow, &entAcc.Escrow.Active.Balance, "entity was slashed expected amount")
+
+	// Check that evidence events were emitted for accepted evidence.
+	var evidenceEvents []*ValueEvidence
+	for _, ev := range ctx.GetEvents() {
+		if ev.Type != EventType {
+			continue
+		}
+		for _, pair := range ev.Attributes {
+			if !bytes.Equal(pair.GetKey(), KeyEvidence) {
+				continue
+			}
+			var value ValueEvidence
+			err = cbor.Unmarshal(pair.GetValue(), &value)
+			require.NoError(err, "cbor.Unmarshal(ValueEvidence)")
+			evidenceEvents = append(evidenceEvents, &value)
+		}
+	}
+	require.Len(evidenceEvents, 2, "evidence events should be emitted for accepted evidence")
+	for _, ev := range evidenceEvents {
+		require.EqualValues(runtime.ID, ev.ID, "evidence event should have the right runtime")
+		require.EqualValues(sk.Public(), ev.Event.Node, "evidence event should have the misbehaving node")
+	}
+}
+
+func TestSetRoundTimeout(t *testing.T) {
+	require := require.New(t)
+	var err error
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock, now)
+	defer ctx.Close()
+
+	entitySigner := memorySigner.NewTestSigner("consensus/tendermint/apps/roothash: entity signer")
+	otherSigner := memorySigner.NewTestSigner("consensus/tendermint/apps/roothash: other signer")
+
+	// Initialize runtimes.
+	uninitializedRtID := common.NewTestNamespaceFromSeed([]byte("tendermint/apps/roothash/transaction_test: non existing runtime"), 0)
+	runtime := registry.Runtime{
+		ID:              common.NewTestNamespaceFromSeed([]byte("tendermint/apps/roothash/transaction_test: entity runtime"), 0),
+		EntityID:        entitySigner.Public(),
+		Kind:            registry.KindCompute,
+		GovernanceModel: registry.GovernanceEntity,
+		Executor: registry.ExecutorParameters{
+			RoundTimeout: 20,
+		},
+	}
+	runtimeConsensus := registry.Runtime{
+		ID:              common.NewTestNamespaceFromSeed([]byte("tendermint/apps/roothash/transaction_test: consensus runtime"), 0),
+		EntityID:        entitySigner.Public(),
+		Kind:            registry.KindCompute,
+		GovernanceModel: registry.GovernanceConsensus,
+		Executor: registry.ExecutorParameters{
+			RoundTimeout: 20,
+		},
+	}
+
+	// Initialize registry and roothash state.
+	regState := registryState.NewMutableState(ctx.State())
+	roothashState := roothashState.NewMutableState(ctx.State())
+	params := &roothash.ConsensusParameters{
+		DebugDoNotSuspendRuntimes: true,
+		DebugBypassStake:          true,
+	}
+	err = roothashState.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+	for _, rt := range []*registry.Runtime{&runtime, &runtimeConsensus} {
+		err = regState.SetRuntime(ctx, rt, false)
+		require.NoError(err, "SetRuntime")
+
+		blk := block.NewGenesisBlock(rt.ID, 0)
+		err = roothashState.SetRuntimeState(ctx, &roothash.RuntimeState{
+			Runtime:      rt,
+			GenesisBlock: blk,
+			CurrentBlock: blk,
+		})
+		require.NoError(err, "SetRuntimeState")
+	}
+
+	var md testMsgDispatcher
+	app := rootHashApplication{appState, &md}
+
+	setRoundTimeout := func(txSigner signature.PublicKey, srt *roothash.SetRoundTimeout) error {
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+		defer txCtx.Close()
+		txCtx.SetTxSigner(txSigner)
+
+		return app.setRoundTimeout(txCtx, roothashState, srt)
+	}
+	runtimeState := func(id common.Namespace) *roothash.RuntimeState {
+		rtState, rerr := roothashState.RuntimeState(ctx, id)
+		require.NoError(rerr, "RuntimeState")
+		return rtState
+	}
+
+	// Round timeout overrides should be rejected while they are not enabled.
+	err = setRoundTimeout(entitySigner.Public(), &roothash.SetRoundTimeout{ID: runtime.ID, RoundTimeout: 10})
+	require.ErrorIs(err, roothash.ErrInvalidArgument, "round timeout overrides not enabled")
+	err = app.ExecuteMessage(ctx, governanceApi.MessageSetRoundTimeout, &governance.SetRoundTimeoutProposal{
+		RuntimeID:    runtimeConsensus.ID,
+		RoundTimeout: 10,
+	})
+	require.ErrorIs(err, roothash.ErrInvalidArgument, "round timeout overrides not enabled")
+
+	params.RoundTimeoutOverride = &roothash.RoundTimeoutOverrideParameters{
+		MinRoundTimeout: 5,
+		MaxRoundTimeout: 100,
+	}
+	err = roothashState.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+
+	for _, tc := range []struct {
+		txSigner signature.PublicKey
+		srt      *roothash.SetRoundTimeout
+		err      error
+		msg      string
+	}{
+		{
+			entitySigner.Public(),
+			&roothash.SetRoundTimeout{ID: runtime.ID, RoundTimeout: -1},
+			roothash.ErrInvalidArgument,
+			"negative round timeout",
+		},
+		{
+			entitySigner.Public(),
+			&roothash.SetRoundTimeout{ID: runtime.ID, RoundTimeout: 1},
+			roothash.ErrInvalidArgument,
+			"round timeout below the minimum",
+		},
+		{
+			entitySigner.Public(),
+			&roothash.SetRoundTimeout{ID: runtime.ID, RoundTimeout: 1000},
+			roothash.ErrInvalidArgument,
+			"round timeout above the maximum",
+		},
+		{
+			entitySigner.Public(),
+			&roothash.SetRoundTimeout{ID: uninitializedRtID, RoundTimeout: 10},
+			roothash.ErrInvalidRuntime,
+			"round timeout for non-existing runtime",
+		},
+		{
+			otherSigner.Public(),
+			&roothash.SetRoundTimeout{ID: runtime.ID, RoundTimeout: 10},
+			roothash.ErrForbidden,
+			"round timeout not set by the controlling entity",
+		},
+		{
+			entitySigner.Public(),
+			&roothash.SetRoundTimeout{ID: runtimeConsensus.ID, RoundTimeout: 10},
+			roothash.ErrForbidden,
+			"round timeout for runtime with consensus governance",
+		},
+		{
+			entitySigner.Public(),
+			&roothash.SetRoundTimeout{ID: runtime.ID, RoundTimeout: 10},
+			nil,
+			"valid round timeout",
+		},
+	} {
+		err = setRoundTimeout(tc.txSigner, tc.srt)
+		require.ErrorIs(err, tc.err, tc.msg)
+	}
+
+	// Runtimes using the consensus layer governance model can be changed via governance.
+	err = app.ExecuteMessage(ctx, governanceApi.MessageSetRoundTimeout, &governance.SetRoundTimeoutProposal{
+		RuntimeID:    runtimeConsensus.ID,
+		RoundTimeout: 1000,
+	})
+	require.ErrorIs(err, roothash.ErrInvalidArgument, "round timeout above the maximum")
+	err = app.ExecuteMessage(ctx, governanceApi.MessageSetRoundTimeout, &governance.SetRoundTimeoutProposal{
+		RuntimeID:    runtimeConsensus.ID,
+		RoundTimeout: 15,
+	})
+	require.NoError(err, "round timeout override via governance")
+
+	// The override should only be pending until the next epoch transition.
+	rtState := runtimeState(runtime.ID)
+	require.NotNil(rtState.PendingRoundTimeoutOverride, "round timeout override should be pending")
+	require.EqualValues(10, *rtState.PendingRoundTimeoutOverride, "pending round timeout override should be correct")
+	require.EqualValues(0, rtState.RoundTimeoutOverride, "round timeout override should not be applied yet")
+	require.EqualValues(20, rtState.Runtime.Executor.RoundTimeout, "runtime descriptor should not change")
+
+	// The override should be applied at the epoch transition.
+	err = app.onCommitteeChanged(ctx, roothashState, 1)
+	require.NoError(err, "onCommitteeChanged")
+	for _, tc := range []struct {
+		id           common.Namespace
+		roundTimeout int64
+	}{
+		{runtime.ID, 10},
+		{runtimeConsensus.ID, 15},
+	} {
+		rtState = runtimeState(tc.id)
+		require.Nil(rtState.PendingRoundTimeoutOverride, "round timeout override should no longer be pending")
+		require.EqualValues(tc.roundTimeout, rtState.RoundTimeoutOverride, "round timeout override should be applied")
+		require.EqualValues(tc.roundTimeout, rtState.Runtime.Executor.RoundTimeout, "round timeout should be overridden")
+	}
+
+	// Setting the round timeout to zero should clear the override at the next epoch transition.
+	err = setRoundTimeout(entitySigner.Public(), &roothash.SetRoundTimeout{ID: runtime.ID, RoundTimeout: 0})
+	require.NoError(err, "clearing round timeout override")
+	rtState = runtimeState(runtime.ID)
+	require.EqualValues(10, rtState.Runtime.Executor.RoundTimeout, "round timeout should still be overridden")
+
+	err = app.onCommitteeChanged(ctx, roothashState, 2)
+	require.NoError(err, "onCommitteeChanged")
+	rtState = runtimeState(runtime.ID)
+	require.Nil(rtState.PendingRoundTimeoutOverride, "round timeout override should no longer be pending")
+	require.EqualValues(0, rtState.RoundTimeoutOverride, "round timeout override should be cleared")
+	require.EqualValues(20, rtState.Runtime.Executor.RoundTimeout, "round timeout from the descriptor should be used")
+
+	// Overrides of other runtimes should be retained.
+	rtState = runtimeState(runtimeConsensus.ID)
+	require.EqualValues(15, rtState.RoundTimeoutOverride, "round timeout override should be retained")
+	require.EqualValues(15, rtState.Runtime.Executor.RoundTimeout, "round timeout should be overridden")
 }
@@ -119,6 +119,10 @@ func (app *schedulerApplication) BeginBlock(ctx *api.Context, request types.Requ
 		filterCommitteeNodes := beaconParameters.Backend == beacon.BackendVRF && !params.DebugAllowWeakAlpha
 
 		regState := registryState.NewMutableState(ctx.State())
+		registryParameters, err := regState.ConsensusParameters(ctx)
+		if err != nil {
+			return fmt.Errorf("tendermint/scheduler: couldn't get registry parameters: %w", err)
+		}
 		runtimes, err := regState.Runtimes(ctx)
 		if err != nil {
 			return fmt.Errorf("tendermint/scheduler: couldn't get runtimes: %w", err)
@@ -197,6 +201,7 @@ func (app *schedulerApplication) BeginBlock(ctx *api.Context, request types.Requ
 				params,
 				beaconState,
 				beaconParameters,
+				registryParameters,
 				stakeAcc,
 				entitiesEligibleForReward,
 				validatorEntities,
@@ -310,7 +315,12 @@ func (app *schedulerApplication) EndBlock(ctx *api.Context, req types.RequestEnd
 	return resp, nil
 }
 
-func (app *schedulerApplication) isSuitableExecutorWorker(ctx *api.Context, n *node.Node, rt *registry.Runtime) bool {
+func (app *schedulerApplication) isSuitableExecutorWorker(
+	ctx *api.Context,
+	n *node.Node,
+	rt *registry.Runtime,
+	registryParameters *registry.ConsensusParameters,
+) bool {
 	if !n.HasRoles(node.RoleComputeWorker) {
 		return false
 	}
@@ -334,7 +344,12 @@ func (app *schedulerApplication) isSuitableExecutorWorker(ctx *api.Context, n *n
 			if nrt.Capabilities.TEE.Hardware != rt.TEEHardware {
 				return false
 			}
-			if err := nrt.Capabilities.TEE.Verify(ctx.Now(), rt.Version.TEE); err != nil {
+			if err := nrt.Capabilities.TEE.Verify(
+				registryParameters.TEEFeatures,
+				ctx.Now(),
+				rt.Version.TEE,
+				registryParameters.SGXPolicy,
+			); err != nil {
 				ctx.Logger().Warn("failed to verify node TEE attestaion",
 					"err", err,
 					"node", n,
@@ -349,7 +364,12 @@ func (app *schedulerApplication) isSuitableExecutorWorker(ctx *api.Context, n *n
 	return false
 }
 
-func (app *schedulerApplication) isSuitableStorageWorker(ctx *api.Context, n *node.Node, rt *registry.Runtime) bool {
+func (app *schedulerApplication) isSuitableStorageWorker(
+	ctx *api.Context,
+	n *node.Node,
+	rt *registry.Runtime,
+	registryParameters *registry.ConsensusParameters,
+) bool {
 	if !n.HasRoles(node.RoleStorageWorker) {
 		return false
 	}
@@ -379,6 +399,7 @@ func (app *schedulerApplication) electAllCommittees(
 	schedulerParameters *scheduler.ConsensusParameters,
 	beaconState *beaconState.MutableState,
 	beaconParameters *beacon.ConsensusParameters,
+	registryParameters *registry.ConsensusParameters,
 	stakeAcc *stakingState.StakeAccumulatorCache,
 	entitiesEligibleForReward map[staking.Address]bool,
 	validatorEntities map[staking.Address]bool,
@@ -393,6 +414,7 @@ func (app *schedulerApplication) electAllCommittees(
 			schedulerParameters,
 			beaconState,
 			beaconParameters,
+			registryParameters,
 			stakeAcc,
 			entitiesEligibleForReward,
 			validatorEntities,
@@ -147,6 +147,7 @@ func (app *schedulerApplication) electCommittee( //nolint: gocyclo
 	schedulerParameters *scheduler.ConsensusParameters,
 	beaconState *beaconState.MutableState,
 	beaconParameters *beacon.ConsensusParameters,
+	registryParameters *registry.ConsensusParameters,
 	stakeAcc *stakingState.StakeAccumulatorCache,
 	entitiesEligibleForReward map[staking.Address]bool,
 	validatorEntities map[staking.Address]bool,
@@ -194,7 +195,7 @@ func (app *schedulerApplication) electCommittee( //nolint: gocyclo
 	// Determine the committee size, and pre-filter the node-list based
 	// on eligibility, entity stake and other criteria.
 
-	var isSuitableFn func(*api.Context, *node.Node, *registry.Runtime) bool
+	var isSuitableFn func(*api.Context, *node.Node, *registry.Runtime, *registry.ConsensusParameters) bool
 	groupSizes := make(map[scheduler.Role]int)
 	switch kind {
 	case scheduler.KindComputeExecutor:
@@ -234,7 +235,7 @@ func (app *schedulerApplication) electCommittee( //nolint: gocyclo
 			}
 		}
 		// Check general node compatibility.
-		if !isSuitableFn(ctx, n, rt) {
+		if !isSuitableFn(ctx, n, rt, registryParameters) {
 			continue
 		}
 
@@ -8,6 +8,11 @@ import (
 const (
 	// AppID is the unique application identifier.
 	AppID uint8 = 0x05
+
+	// ImportTransformReleaseDebondingDelegations is the name of the genesis import transform
+	// that immediately releases all debonding delegations to the general balances of their
+	// delegators, clearing the debonding queues.
+	ImportTransformReleaseDebondingDelegations = "staking.release_debonding_delegations"
 )
 
 var (
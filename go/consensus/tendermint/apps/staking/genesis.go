@@ -1,8 +1,10 @@
 package staking
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/tendermint/tendermint/abci/types"
 
@@ -285,6 +287,87 @@ func (app *stakingApplication) initDebondingDelegations(ctx *abciAPI.Context, st
 	return nil
 }
 
+// Implements abciAPI.GenesisImporter.
+func (app *stakingApplication) GenesisImportTransforms() []abciAPI.GenesisImportTransform {
+	return []abciAPI.GenesisImportTransform{
+		{
+			Name:  ImportTransformReleaseDebondingDelegations,
+			Apply: releaseDebondingDelegations,
+		},
+	}
+}
+
+func releaseDebondingDelegations(doc *genesis.Document) ([]string, error) {
+	st := &doc.Staking
+
+	// Process accounts in a deterministic order as share to stake conversions round down.
+	escrowAddrs := make([]staking.Address, 0, len(st.DebondingDelegations))
+	for addr := range st.DebondingDelegations {
+		escrowAddrs = append(escrowAddrs, addr)
+	}
+	sortAddresses(escrowAddrs)
+
+	var changes []string
+	for _, escrowAddr := range escrowAddrs {
+		escrowAcct := st.Ledger[escrowAddr]
+		if escrowAcct == nil {
+			return nil, fmt.Errorf("tendermint/staking: debonding delegations to non-existent account %s", escrowAddr)
+		}
+
+		delegators := st.DebondingDelegations[escrowAddr]
+		delegatorAddrs := make([]staking.Address, 0, len(delegators))
+		for addr := range delegators {
+			delegatorAddrs = append(delegatorAddrs, addr)
+		}
+		sortAddresses(delegatorAddrs)
+
+		for _, delegatorAddr := range delegatorAddrs {
+			delegatorAcct := st.Ledger[delegatorAddr]
+			if delegatorAcct == nil {
+				return nil, fmt.Errorf("tendermint/staking: debonding delegation from non-existent account %s", delegatorAddr)
+			}
+
+			for idx, delegation := range delegators[delegatorAddr] {
+				if delegation == nil {
+					return nil, fmt.Errorf(
+						"tendermint/staking: genesis debonding delegation from %s to %s with index %d is nil",
+						delegatorAddr, escrowAddr, idx,
+					)
+				}
+
+				amount, err := escrowAcct.Escrow.Debonding.StakeForShares(&delegation.Shares)
+				if err != nil {
+					return nil, fmt.Errorf("tendermint/staking: failed to compute debonding delegation amount from %s to %s: %w",
+						delegatorAddr, escrowAddr, err,
+					)
+				}
+				if err = escrowAcct.Escrow.Debonding.Withdraw(&delegatorAcct.General.Balance, delegation.Shares.Clone(), &delegation.Shares); err != nil {
+					return nil, fmt.Errorf("tendermint/staking: failed to release debonding delegation from %s to %s: %w",
+						delegatorAddr, escrowAddr, err,
+					)
+				}
+				changes = append(changes, fmt.Sprintf("released debonding delegation of %s base units from %s to %s",
+					amount, escrowAddr, delegatorAddr,
+				))
+			}
+		}
+
+		// Any remainder due to rounding is not owned by anyone, move it to the common pool.
+		if !escrowAcct.Escrow.Debonding.Balance.IsZero() {
+			remainder := escrowAcct.Escrow.Debonding.Balance.Clone()
+			if err := quantity.Move(&st.CommonPool, &escrowAcct.Escrow.Debonding.Balance, remainder); err != nil {
+				return nil, fmt.Errorf("tendermint/staking: failed to move debonding remainder of %s: %w", escrowAddr, err)
+			}
+			changes = append(changes, fmt.Sprintf("moved debonding remainder of %s base units of %s to the common pool",
+				remainder, escrowAddr,
+			))
+		}
+	}
+	st.DebondingDelegations = nil
+
+	return changes, nil
+}
+
 // InitChain initializes the chain from genesis.
 func (app *stakingApplication) InitChain(ctx *abciAPI.Context, request types.RequestInitChain, doc *genesis.Document) error {
 	st := &doc.Staking
@@ -399,3 +482,9 @@ func (sq *stakingQuerier) Genesis(ctx context.Context) (*staking.Genesis, error)
 	}
 	return &gen, nil
 }
+
+func sortAddresses(addrs []staking.Address) {
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i][:], addrs[j][:]) < 0
+	})
+}
@@ -0,0 +1,84 @@
+package staking
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+func TestReleaseDebondingDelegations(t *testing.T) {
+	require := require.New(t)
+
+	escrowAddr := staking.NewAddress(memorySigner.NewTestSigner("escrow").Public())
+	delegator1Addr := staking.NewAddress(memorySigner.NewTestSigner("delegator 1").Public())
+	delegator2Addr := staking.NewAddress(memorySigner.NewTestSigner("delegator 2").Public())
+
+	var doc genesis.Document
+	doc.Staking.Ledger = map[staking.Address]*staking.Account{
+		escrowAddr: {
+			Escrow: staking.EscrowAccount{
+				Debonding: staking.SharePool{
+					Balance:     *quantity.NewFromUint64(100),
+					TotalShares: *quantity.NewFromUint64(30),
+				},
+			},
+		},
+		delegator1Addr: {
+			General: staking.GeneralAccount{
+				Balance: *quantity.NewFromUint64(5),
+			},
+		},
+		delegator2Addr: {},
+	}
+	doc.Staking.DebondingDelegations = map[staking.Address]map[staking.Address][]*staking.DebondingDelegation{
+		escrowAddr: {
+			delegator1Addr: {
+				{Shares: *quantity.NewFromUint64(10), DebondEndTime: 10},
+			},
+			delegator2Addr: {
+				{Shares: *quantity.NewFromUint64(5), DebondEndTime: 10},
+				{Shares: *quantity.NewFromUint64(15), DebondEndTime: 11},
+			},
+		},
+	}
+
+	changes, err := releaseDebondingDelegations(&doc)
+	require.NoError(err, "releaseDebondingDelegations")
+	require.Len(changes, 3, "each release should be recorded")
+	require.Nil(doc.Staking.DebondingDelegations, "debonding delegations should be cleared")
+
+	ledger := doc.Staking.Ledger
+	released := ledger[delegator1Addr].General.Balance.Clone()
+	require.NoError(released.Add(&ledger[delegator2Addr].General.Balance))
+	require.EqualValues(quantity.NewFromUint64(5+100), released, "whole debonding balance should be released")
+	require.True(ledger[escrowAddr].Escrow.Debonding.Balance.IsZero(), "debonding balance should be cleared")
+	require.True(ledger[escrowAddr].Escrow.Debonding.TotalShares.IsZero(), "debonding shares should be cleared")
+	require.True(doc.Staking.CommonPool.IsZero(), "nothing should be moved to the common pool")
+
+	// Debonding balance without any shares should be moved to the common pool.
+	ledger[escrowAddr].Escrow.Debonding.Balance = *quantity.NewFromUint64(7)
+	doc.Staking.DebondingDelegations = map[staking.Address]map[staking.Address][]*staking.DebondingDelegation{
+		escrowAddr: {},
+	}
+	changes, err = releaseDebondingDelegations(&doc)
+	require.NoError(err, "releaseDebondingDelegations")
+	require.Len(changes, 1, "moving the remainder should be recorded")
+	require.True(ledger[escrowAddr].Escrow.Debonding.Balance.IsZero(), "debonding balance should be cleared")
+	require.EqualValues(*quantity.NewFromUint64(7), doc.Staking.CommonPool, "remainder should be moved to the common pool")
+
+	// Missing accounts should be reported.
+	doc.Staking.DebondingDelegations = map[staking.Address]map[staking.Address][]*staking.DebondingDelegation{
+		escrowAddr: {
+			staking.NewAddress(memorySigner.NewTestSigner("missing").Public()): {
+				{Shares: *quantity.NewFromUint64(1)},
+			},
+		},
+	}
+	_, err = releaseDebondingDelegations(&doc)
+	require.Error(err, "releaseDebondingDelegations should fail for missing accounts")
+}
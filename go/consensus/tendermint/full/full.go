@@ -423,6 +423,11 @@ func (t *fullService) StateToGenesis(ctx context.Context, blockHeight int64) (*g
 		KeyManager: *keymanagerGenesis,
 		Scheduler:  *schedulerGenesis,
 		Consensus:  genesisDoc.Consensus,
+		Provenance: &genesisAPI.Provenance{
+			SourceChainID: genesisDoc.ChainID,
+			ExportHeight:  blockHeight,
+			ToolVersion:   version.SoftwareVersion,
+		},
 	}, nil
 }
 
@@ -543,6 +548,14 @@ func (t *fullService) EstimateGas(ctx context.Context, req *consensusAPI.Estimat
 	return t.mux.EstimateGas(req.Signer, req.Transaction)
 }
 
+// Implements consensusAPI.Backend.
+func (t *fullService) DryRunUpgrade(ctx context.Context, descriptor *upgradeAPI.Descriptor) (*upgradeAPI.DryRunResult, error) {
+	if err := t.ensureStarted(ctx); err != nil {
+		return nil, err
+	}
+	return t.mux.DryRunUpgrade(ctx, descriptor)
+}
+
 func (t *fullService) subscribe(subscriber string, query tmpubsub.Query) (tmtypes.Subscription, error) {
 	// Note: The tendermint documentation claims using SubscribeUnbuffered can
 	// freeze the server, however, the buffered Subscribe can drop events, and
@@ -1307,6 +1320,7 @@ func (t *fullService) lazyInit() error {
 		if stateProvider, err = newStateProvider(t.ctx, cfg); err != nil {
 			t.Logger.Error("failed to create state sync state provider",
 				"err", err,
+				logging.LogEvent, api.LogEventStateSyncProviderFailed,
 			)
 			return fmt.Errorf("failed to create state sync state provider: %w", err)
 		}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/eapache/channels"
 	"github.com/hashicorp/go-multierror"
@@ -26,6 +27,9 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/registry/api"
 )
 
+// maxNodeAttestations is the maximum number of recent attestation decisions kept per node.
+const maxNodeAttestations = 32
+
 // ServiceClient is the registry service client interface.
 type ServiceClient interface {
 	api.Backend
@@ -44,6 +48,9 @@ type serviceClient struct {
 	nodeNotifier     *pubsub.Broker
 	nodeListNotifier *pubsub.Broker
 	runtimeNotifier  *pubsub.Broker
+
+	attestationsLock sync.RWMutex
+	attestations     map[signature.PublicKey][]*api.Event
 }
 
 // NodeListEpochInternalEvent is the per-epoch node list event.
@@ -220,6 +227,25 @@ func (sc *serviceClient) GetEvents(ctx context.Context, height int64) ([]*api.Ev
 	return events, nil
 }
 
+func (sc *serviceClient) GetNodeAttestations(ctx context.Context, nodeID signature.PublicKey) ([]*api.Event, error) {
+	sc.attestationsLock.RLock()
+	defer sc.attestationsLock.RUnlock()
+
+	return append([]*api.Event{}, sc.attestations[nodeID]...), nil
+}
+
+func (sc *serviceClient) recordNodeAttestation(ev *api.Event) {
+	sc.attestationsLock.Lock()
+	defer sc.attestationsLock.Unlock()
+
+	nodeID := ev.NodeAttestationEvent.NodeID
+	evs := append(sc.attestations[nodeID], ev)
+	if len(evs) > maxNodeAttestations {
+		evs = evs[len(evs)-maxNodeAttestations:]
+	}
+	sc.attestations[nodeID] = evs
+}
+
 // Implements api.ServiceClient.
 func (sc *serviceClient) ServiceDescriptor() tmapi.ServiceDescriptor {
 	return tmapi.NewStaticServiceDescriptor(api.ModuleName, app.EventType, []tmpubsub.Query{app.QueryApp})
@@ -256,6 +282,9 @@ func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmty
 		if ev.RuntimeEvent != nil {
 			sc.runtimeNotifier.Broadcast(ev.RuntimeEvent.Runtime)
 		}
+		if ev.NodeAttestationEvent != nil {
+			sc.recordNodeAttestation(ev)
+		}
 	}
 
 	return nil
@@ -376,6 +405,19 @@ func EventsFromTendermint(
 					},
 				}
 				events = append(events, evt)
+			case bytes.Equal(key, app.KeyNodeAttestation):
+				// Node attestation event.
+				var nae api.NodeAttestationEvent
+				if err := cbor.Unmarshal(val, &nae); err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("registry: corrupt NodeAttestation event: %w", err))
+					continue
+				}
+				evt := &api.Event{
+					Height:               height,
+					TxHash:               txHash,
+					NodeAttestationEvent: &nae,
+				}
+				events = append(events, evt)
 			}
 		}
 	}
@@ -415,6 +457,7 @@ func New(ctx context.Context, backend tmapi.Backend) (ServiceClient, error) {
 		querier:        a.QueryFactory().(*app.QueryFactory),
 		entityNotifier: pubsub.NewBroker(false),
 		nodeNotifier:   pubsub.NewBroker(false),
+		attestations:   make(map[signature.PublicKey][]*api.Event),
 	}
 	sc.nodeListNotifier = pubsub.NewBrokerEx(func(ch channels.Channel) {
 		wr := ch.In()
@@ -4,6 +4,7 @@ package roothash
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"sync"
@@ -78,6 +79,10 @@ type serviceClient struct {
 	queryCh        chan tmpubsub.Query
 	cmdCh          chan interface{}
 	trackedRuntime map[common.Namespace]*trackedRuntime
+
+	// blockHistories are the block histories of tracked runtimes, used for serving historic
+	// blocks. Unlike trackedRuntime, access is protected by the service client lock.
+	blockHistories map[common.Namespace]api.BlockHistory
 }
 
 // Implements api.Backend.
@@ -133,6 +138,34 @@ func (sc *serviceClient) GetRuntimeState(ctx context.Context, request *api.Runti
 	return q.RuntimeState(ctx, request.RuntimeID)
 }
 
+// Implements api.Backend.
+func (sc *serviceClient) GetRoundResults(ctx context.Context, request *api.RuntimeRequest) (*api.LastRoundResults, error) {
+	state, err := sc.GetRuntimeState(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := sc.getRoundResults(ctx, request.RuntimeID, state.LastNormalHeight)
+	if err != nil {
+		return nil, err
+	}
+	return &api.LastRoundResults{
+		Round:   state.LastNormalRound,
+		Height:  state.LastNormalHeight,
+		Results: results,
+	}, nil
+}
+
+// Implements api.Backend.
+func (sc *serviceClient) GetRetainedEvidence(ctx context.Context, request *api.RuntimeRequest) ([]*api.RetainedEvidence, error) {
+	q, err := sc.querier.QueryAt(ctx, request.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.RetainedEvidence(ctx, request.RuntimeID)
+}
+
 // Implements api.Backend.
 func (sc *serviceClient) WatchBlocks(ctx context.Context, id common.Namespace) (<-chan *api.AnnotatedBlock, pubsub.ClosableSubscription, error) {
 	notifiers := sc.getRuntimeNotifiers(id)
@@ -182,6 +215,126 @@ func (sc *serviceClient) WatchBlocks(ctx context.Context, id common.Namespace) (
 	return monotonicCh, sub, nil
 }
 
+// Implements api.Backend.
+func (sc *serviceClient) WatchBlocksFrom(
+	ctx context.Context,
+	id common.Namespace,
+	startRound uint64,
+) (<-chan *api.AnnotatedBlock, pubsub.ClosableSubscription, error) {
+	sc.RLock()
+	history := sc.blockHistories[id]
+	sc.RUnlock()
+	if history == nil {
+		return nil, nil, api.ErrHistoryNotAvailable
+	}
+
+	earliest, err := history.GetEarliestBlock(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("roothash: failed to get earliest block: %w", err)
+	}
+	if startRound < earliest.Header.Round {
+		return nil, nil, api.ErrNotFound
+	}
+
+	// Subscribe to new blocks first so no blocks are missed while replaying history.
+	liveCh, liveSub, err := sc.WatchBlocks(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	subCtx, sub := pubsub.NewContextSubscription(ctx)
+
+	ch := make(chan *api.AnnotatedBlock)
+	go func() {
+		defer close(ch)
+		defer liveSub.Close()
+
+		if rerr := replayBlocksFrom(subCtx, history, startRound, liveCh, ch); rerr != nil {
+			sc.logger.Error("failed to replay blocks from history",
+				"err", rerr,
+				"runtime_id", id,
+			)
+		}
+	}()
+
+	return ch, sub, nil
+}
+
+// replayBlocksFrom emits blocks starting at startRound to ch, first replaying all blocks that are
+// already in history and then switching to the (monotonic) live block stream, filling any gaps
+// from history. Each round is emitted exactly once.
+func replayBlocksFrom(
+	ctx context.Context,
+	history api.BlockHistory,
+	startRound uint64,
+	liveCh <-chan *api.AnnotatedBlock,
+	ch chan<- *api.AnnotatedBlock,
+) error {
+	emit := func(blk *api.AnnotatedBlock) bool {
+		select {
+		case ch <- blk:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	replayUntil := func(nextRound, round uint64) (uint64, bool, error) {
+		for ; nextRound < round; nextRound++ {
+			blk, err := history.GetAnnotatedBlock(ctx, nextRound)
+			if err != nil {
+				return nextRound, false, fmt.Errorf("failed to get block for round %d: %w", nextRound, err)
+			}
+			if !emit(blk) {
+				return nextRound, false, nil
+			}
+		}
+		return nextRound, true, nil
+	}
+
+	// Replay what is already in history without waiting for the next live block.
+	nextRound := startRound
+	latest, err := history.GetAnnotatedBlock(ctx, api.RoundLatest)
+	switch {
+	case err == nil:
+		var ok bool
+		if nextRound, ok, err = replayUntil(nextRound, latest.Block.Header.Round+1); !ok {
+			return err
+		}
+	case errors.Is(err, api.ErrNotFound):
+	default:
+		return fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	for {
+		var live *api.AnnotatedBlock
+		select {
+		case blk, ok := <-liveCh:
+			if !ok {
+				return nil
+			}
+			live = blk
+		case <-ctx.Done():
+			return nil
+		}
+
+		// Replay any rounds committed since the initial replay that precede the live block. Blocks
+		// are committed to history before they are emitted, so all of them are available.
+		var ok bool
+		if nextRound, ok, err = replayUntil(nextRound, live.Block.Header.Round); !ok {
+			return err
+		}
+
+		// Skip live blocks that precede the start round.
+		if live.Block.Header.Round < nextRound {
+			continue
+		}
+		if !emit(live) {
+			return nil
+		}
+		nextRound = live.Block.Header.Round + 1
+	}
+}
+
 func (sc *serviceClient) WatchAllBlocks() (<-chan *block.Block, *pubsub.Subscription) {
 	sub := sc.allBlockNotifier.Subscribe()
 	ch := make(chan *block.Block)
@@ -208,6 +361,10 @@ func (sc *serviceClient) WatchEvents(ctx context.Context, id common.Namespace) (
 
 // Implements api.Backend.
 func (sc *serviceClient) TrackRuntime(ctx context.Context, history api.BlockHistory) error {
+	sc.Lock()
+	sc.blockHistories[history.RuntimeID()] = history
+	sc.Unlock()
+
 	return sc.trackRuntime(ctx, history.RuntimeID(), history)
 }
 
@@ -380,6 +537,15 @@ func (sc *serviceClient) GetEvents(ctx context.Context, height int64) ([]*api.Ev
 	return sc.getEvents(ctx, height, txns)
 }
 
+// Implements api.Backend.
+func (sc *serviceClient) GetEventsFiltered(ctx context.Context, height int64, filter *api.EventFilter) ([]*api.Event, error) {
+	events, err := sc.GetEvents(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return filter.Filter(events), nil
+}
+
 // Implements api.Backend.
 func (sc *serviceClient) Cleanup() {
 }
@@ -570,20 +736,16 @@ func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmty
 	}
 
 	for _, ev := range events {
-		// Notify non-finalized events.
-		if ev.Finalized == nil {
-			notifiers := sc.getRuntimeNotifiers(ev.RuntimeID)
-			notifiers.eventNotifier.Broadcast(ev)
-			continue
+		// Only process finalized events for tracked runtimes. Notify finalized events only after
+		// the block has been processed so that watchers can immediately query it.
+		if ev.Finalized != nil && sc.trackedRuntime[ev.RuntimeID] != nil {
+			if err = sc.processFinalizedEvent(ctx, height, ev.RuntimeID, &ev.Finalized.Round, true); err != nil {
+				return fmt.Errorf("roothash: failed to process finalized event: %w", err)
+			}
 		}
 
-		// Only process finalized events for tracked runtimes.
-		if sc.trackedRuntime[ev.RuntimeID] == nil {
-			continue
-		}
-		if err = sc.processFinalizedEvent(ctx, height, ev.RuntimeID, &ev.Finalized.Round, true); err != nil {
-			return fmt.Errorf("roothash: failed to process finalized event: %w", err)
-		}
+		notifiers := sc.getRuntimeNotifiers(ev.RuntimeID)
+		notifiers.eventNotifier.Broadcast(ev)
 	}
 
 	return nil
@@ -778,6 +940,16 @@ func EventsFromTendermint(
 
 				ev := &api.Event{RuntimeID: value.ID, Height: height, TxHash: txHash, Message: &value.Event}
 				events = append(events, ev)
+			case bytes.Equal(key, app.KeyEvidence):
+				// Evidence of node misbehaviour has been accepted.
+				var value app.ValueEvidence
+				if err := cbor.Unmarshal(val, &value); err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("roothash: corrupt evidence event: %w", err))
+					continue
+				}
+
+				ev := &api.Event{RuntimeID: value.ID, Height: height, TxHash: txHash, Evidence: &value.Event}
+				events = append(events, ev)
 			case bytes.Equal(key, app.KeyRuntimeID):
 				// Runtime ID attribute (Base64-encoded to allow queries).
 			default:
@@ -811,6 +983,7 @@ func New(
 		queryCh:          make(chan tmpubsub.Query, runtimeRegistry.MaxRuntimeCount),
 		cmdCh:            make(chan interface{}, runtimeRegistry.MaxRuntimeCount),
 		trackedRuntime:   make(map[common.Namespace]*trackedRuntime),
+		blockHistories:   make(map[common.Namespace]api.BlockHistory),
 	}, nil
 }
 
@@ -0,0 +1,197 @@
+package roothash
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	tmapi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	app "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/roothash"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+)
+
+type testBlockHistory struct {
+	api.BlockHistory
+
+	blocks map[uint64]*api.AnnotatedBlock
+}
+
+func (h *testBlockHistory) GetAnnotatedBlock(ctx context.Context, round uint64) (*api.AnnotatedBlock, error) {
+	if round == api.RoundLatest {
+		var latest *api.AnnotatedBlock
+		for _, blk := range h.blocks {
+			if latest == nil || blk.Block.Header.Round > latest.Block.Header.Round {
+				latest = blk
+			}
+		}
+		if latest == nil {
+			return nil, api.ErrNotFound
+		}
+		return latest, nil
+	}
+
+	blk, ok := h.blocks[round]
+	if !ok {
+		return nil, api.ErrNotFound
+	}
+	return blk, nil
+}
+
+func testAnnotatedBlock(round uint64) *api.AnnotatedBlock {
+	var blk block.Block
+	blk.Header.Round = round
+	return &api.AnnotatedBlock{
+		Height: int64(round) + 100,
+		Block:  &blk,
+	}
+}
+
+func TestReplayBlocksFrom(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		history    []uint64
+		startRound uint64
+		live       []uint64
+		expected   []uint64
+	}{
+		{"ReplayThenLive", []uint64{0, 1, 2, 3, 4, 5, 6}, 1, []uint64{4, 5, 6}, []uint64{1, 2, 3, 4, 5, 6}},
+		{"GapInLive", []uint64{0, 1, 2, 3, 4, 5, 6, 7}, 2, []uint64{4, 7}, []uint64{2, 3, 4, 5, 6, 7}},
+		{"StartAtLive", []uint64{0, 1, 2, 3, 4}, 4, []uint64{4, 5}, []uint64{4, 5}},
+		{"StartAfterLive", []uint64{0, 1, 2, 3, 4, 5, 6}, 6, []uint64{4, 5, 6, 7}, []uint64{6, 7}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			history := &testBlockHistory{blocks: make(map[uint64]*api.AnnotatedBlock)}
+			for _, round := range tc.history {
+				history.blocks[round] = testAnnotatedBlock(round)
+			}
+
+			liveCh := make(chan *api.AnnotatedBlock, len(tc.live))
+			for _, round := range tc.live {
+				liveCh <- testAnnotatedBlock(round)
+			}
+			close(liveCh)
+
+			ch := make(chan *api.AnnotatedBlock)
+			errCh := make(chan error, 1)
+			go func() {
+				defer close(ch)
+				errCh <- replayBlocksFrom(context.Background(), history, tc.startRound, liveCh, ch)
+			}()
+
+			var rounds []uint64
+			for blk := range ch {
+				require.EqualValues(int64(blk.Block.Header.Round)+100, blk.Height, "annotated block should be emitted")
+				rounds = append(rounds, blk.Block.Header.Round)
+			}
+			require.NoError(<-errCh, "replayBlocksFrom")
+			require.Equal(tc.expected, rounds, "each round should be emitted exactly once, in order")
+		})
+	}
+
+	t.Run("NoLiveBlocks", func(t *testing.T) {
+		require := require.New(t)
+
+		history := &testBlockHistory{blocks: make(map[uint64]*api.AnnotatedBlock)}
+		for round := uint64(0); round <= 5; round++ {
+			history.blocks[round] = testAnnotatedBlock(round)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ch := make(chan *api.AnnotatedBlock)
+		go func() {
+			_ = replayBlocksFrom(ctx, history, 2, make(chan *api.AnnotatedBlock), ch)
+		}()
+
+		for round := uint64(2); round <= 5; round++ {
+			select {
+			case blk := <-ch:
+				require.EqualValues(round, blk.Block.Header.Round, "historic blocks should be emitted in order")
+			case <-time.After(time.Second):
+				t.Fatalf("historic blocks should be emitted without waiting for a live block")
+			}
+		}
+	})
+
+	t.Run("MissingHistory", func(t *testing.T) {
+		require := require.New(t)
+
+		history := &testBlockHistory{blocks: map[uint64]*api.AnnotatedBlock{
+			0: testAnnotatedBlock(0),
+		}}
+		liveCh := make(chan *api.AnnotatedBlock, 1)
+		liveCh <- testAnnotatedBlock(3)
+
+		ch := make(chan *api.AnnotatedBlock, 4)
+		err := replayBlocksFrom(context.Background(), history, 0, liveCh, ch)
+		require.ErrorIs(err, api.ErrNotFound, "replay should fail when history is missing rounds")
+		require.Len(ch, 1, "only available rounds should be emitted")
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		require := require.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		liveCh := make(chan *api.AnnotatedBlock)
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- replayBlocksFrom(ctx, &testBlockHistory{}, 0, liveCh, make(chan *api.AnnotatedBlock))
+		}()
+		cancel()
+
+		select {
+		case err := <-errCh:
+			require.NoError(err, "replayBlocksFrom")
+		case <-time.After(time.Second):
+			t.Fatalf("replayBlocksFrom should terminate on cancellation")
+		}
+	})
+}
+
+func TestDeliverEventUntracked(t *testing.T) {
+	require := require.New(t)
+
+	sc := &serviceClient{
+		ctx:              context.Background(),
+		logger:           logging.GetLogger("roothash/tendermint/test"),
+		runtimeNotifiers: make(map[common.Namespace]*runtimeBrokers),
+		cmdCh:            make(chan interface{}, 1),
+		trackedRuntime:   make(map[common.Namespace]*trackedRuntime),
+	}
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("tendermint/roothash/roothash_test: untracked runtime"), 0)
+	ch, sub, err := sc.WatchEvents(context.Background(), runtimeID)
+	require.NoError(err, "WatchEvents")
+	defer sub.Close()
+
+	// The tracking request is never processed so the runtime remains untracked.
+	require.Nil(sc.trackedRuntime[runtimeID], "runtime should not be tracked")
+
+	ev := tmapi.NewEventBuilder(app.AppName).
+		Attribute(app.KeyFinalized, cbor.Marshal(app.ValueFinalized{
+			ID:    runtimeID,
+			Event: api.FinalizedEvent{Round: 42},
+		})).
+		Attribute(app.KeyRuntimeID, app.ValueRuntimeID(runtimeID)).
+		Event()
+	err = sc.DeliverEvent(context.Background(), 10, nil, &ev)
+	require.NoError(err, "DeliverEvent")
+
+	select {
+	case ev := <-ch:
+		require.EqualValues(10, ev.Height, "event should have the right height")
+		require.EqualValues(runtimeID, ev.RuntimeID, "event should have the right runtime")
+		require.NotNil(ev.Finalized, "event should be a finalized event")
+		require.EqualValues(42, ev.Finalized.Round, "finalized event should have the right round")
+	case <-time.After(time.Second):
+		t.Fatalf("failed to receive finalized event for untracked runtime")
+	}
+}
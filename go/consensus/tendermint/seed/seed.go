@@ -38,6 +38,7 @@ import (
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
+	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
 const (
@@ -148,6 +149,11 @@ func (srv *seedService) GetStatus(ctx context.Context) (*consensus.Status, error
 	return status, nil
 }
 
+// Implements Backend.
+func (srv *seedService) DryRunUpgrade(ctx context.Context, descriptor *upgrade.Descriptor) (*upgrade.DryRunResult, error) {
+	return nil, consensus.ErrUnsupported
+}
+
 // Implements Backend.
 func (srv *seedService) GetNextBlockState(ctx context.Context) (*consensus.NextBlockState, error) {
 	return nil, consensus.ErrUnsupported
@@ -96,8 +96,11 @@ func NewTestNodeGenesisProvider(identity *identity.Identity, ent *entity.Entity,
 		},
 		RootHash: roothash.Genesis{
 			Parameters: roothash.ConsensusParameters{
-				DebugDoNotSuspendRuntimes: true,
-				MaxRuntimeMessages:        32,
+				DebugDoNotSuspendRuntimes:  true,
+				MaxRuntimeMessages:         32,
+				EnforceMaxRuntimeMessages:  true,
+				AllowAggregatedCommitments: true,
+				MaxEvidenceAge:             100,
 			},
 		},
 		Consensus: consensus.Genesis{
@@ -7,17 +7,21 @@ import (
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	block "github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	sentry "github.com/oasisprotocol/oasis-core/go/sentry/api"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 	commonWorker "github.com/oasisprotocol/oasis-core/go/worker/common/api"
+	executorWorker "github.com/oasisprotocol/oasis-core/go/worker/compute/executor/api"
 	storageWorker "github.com/oasisprotocol/oasis-core/go/worker/storage/api"
 )
 
@@ -29,6 +33,13 @@ type NodeController interface {
 	// shutdown to complete.
 	RequestShutdown(ctx context.Context, wait bool) error
 
+	// Shutdown gracefully shuts the node down after draining it.
+	//
+	// The node (optionally) deregisters, stops accepting new runtime work and waits for the
+	// in-flight batches and storage applies to complete. In case draining does not complete
+	// within the request's timeout, the node shuts down anyway.
+	Shutdown(ctx context.Context, req *ShutdownRequest) error
+
 	// WaitSync waits for the node to finish syncing.
 	WaitSync(ctx context.Context) error
 
@@ -41,6 +52,14 @@ type NodeController interface {
 	// IsReady checks whether the node is ready to accept runtime work.
 	IsReady(ctx context.Context) (bool, error)
 
+	// CheckLiveness checks whether the node is alive, i.e. whether its services are responsive.
+	// A node that is not alive should be restarted.
+	CheckLiveness(ctx context.Context) (*HealthStatus, error)
+
+	// CheckReadiness checks whether the node is ready to serve, i.e. whether consensus is synced,
+	// all workers are initialized and all runtimes are provisioned.
+	CheckReadiness(ctx context.Context) (*HealthStatus, error)
+
 	// UpgradeBinary submits an upgrade descriptor to a running node.
 	// The node will wait for the appropriate epoch, then update its binaries
 	// and shut down.
@@ -49,8 +68,78 @@ type NodeController interface {
 	// CancelUpgrade cancels the specific pending upgrade, unless it is already in progress.
 	CancelUpgrade(ctx context.Context, descriptor *upgrade.Descriptor) error
 
+	// DryRunUpgrade executes the consensus upgrade handler of the specific pending upgrade against
+	// a copy of the latest consensus state and reports the resulting state root and any errors,
+	// without committing any changes.
+	DryRunUpgrade(ctx context.Context, descriptor *upgrade.Descriptor) (*upgrade.DryRunResult, error)
+
 	// GetStatus returns the current status overview of the node.
 	GetStatus(ctx context.Context) (*Status, error)
+
+	// GetP2PPeers returns the status of all connected, registered and banned P2P peers.
+	GetP2PPeers(ctx context.Context) ([]*P2PPeer, error)
+
+	// BanP2PPeer bans a P2P peer, disconnecting it and refusing any further connections until
+	// the ban expires or is lifted.
+	BanP2PPeer(ctx context.Context, req *BanP2PPeerRequest) error
+
+	// UnbanP2PPeer lifts the ban of a P2P peer.
+	UnbanP2PPeer(ctx context.Context, id signature.PublicKey) error
+
+	// SetLogLevel overrides the log level of a logging module (and of all modules that it is a
+	// prefix of) or removes the override. Unlike the configured module log levels, overrides can
+	// be below the default log level and persist across configuration reloads.
+	SetLogLevel(ctx context.Context, req *SetLogLevelRequest) error
+
+	// ReloadConfig reloads the subset of the node configuration that can be changed without
+	// restarting the node (log levels, client and sentry addresses, sentry upstream nodes and
+	// access configuration and transaction pool limits).
+	//
+	// In case the advertised addresses changed, the node re-registers.
+	ReloadConfig(ctx context.Context) error
+
+	// RotateCertificates rotates the node's TLS certificates and re-registers the node with the
+	// new certificates.
+	//
+	// The certificate that becomes current has already been registered as the node's next
+	// certificate, so the node remains reachable by peers that have not yet seen the new
+	// registration. Another rotation is only possible once the previous one has been registered
+	// and at least an epoch has passed since.
+	RotateCertificates(ctx context.Context) error
+
+	// GetTxPoolStatus returns the status of the given runtime's transaction pool.
+	GetTxPoolStatus(ctx context.Context, runtimeID common.Namespace) (*executorWorker.TxPoolStatus, error)
+
+	// GetTxPoolTransactions returns the transactions waiting to be scheduled in the given
+	// runtime's transaction pool, in scheduling order.
+	GetTxPoolTransactions(ctx context.Context, runtimeID common.Namespace) ([]*executorWorker.TxPoolTransaction, error)
+
+	// RemoveTxPoolTransactions removes the given transactions from the given runtime's transaction
+	// pool and returns the hashes of the transactions that were actually removed.
+	RemoveTxPoolTransactions(ctx context.Context, req *RemoveTxPoolTransactionsRequest) ([]hash.Hash, error)
+
+	// PauseRuntime pauses the given runtime's executor worker. While paused, the node does not
+	// check or schedule new transactions and does not accept new batches for the runtime, but the
+	// consensus layer and other runtimes keep running.
+	PauseRuntime(ctx context.Context, runtimeID common.Namespace) error
+
+	// ResumeRuntime resumes the given runtime's paused executor worker.
+	ResumeRuntime(ctx context.Context, runtimeID common.Namespace) error
+
+	// PruneRuntimeHistory prunes the given runtime's block history according to the configured
+	// retention policy, without waiting for the periodic background pruning.
+	PruneRuntimeHistory(ctx context.Context, runtimeID common.Namespace) error
+
+	// SetProfiling enables or disables the collection of a profile (CPU profile, execution trace,
+	// block or mutex profile) on the running node.
+	SetProfiling(ctx context.Context, req *SetProfilingRequest) error
+
+	// GetProfile returns a profile collected by the node, in the format expected by the go tool
+	// pprof (or go tool trace for execution traces).
+	//
+	// The CPU profile and the execution trace are only available after their collection has been
+	// disabled, other profiles reflect the state at the time of the call.
+	GetProfile(ctx context.Context, req *GetProfileRequest) ([]byte, error)
 }
 
 // Status is the current status overview.
@@ -72,6 +161,9 @@ type Status struct {
 
 	// PendingUpgrades are the node's pending upgrades.
 	PendingUpgrades []*upgrade.PendingUpgrade `json:"pending_upgrades"`
+
+	// Sentry is the sentry node status, in case the node is a gRPC sentry node.
+	Sentry *sentry.Status `json:"sentry,omitempty"`
 }
 
 // IdentityStatus is the current node identity status, listing all the public keys that identify
@@ -90,6 +182,39 @@ type IdentityStatus struct {
 	TLS []signature.PublicKey `json:"tls"`
 }
 
+// HealthCheck is the result of an individual health check.
+type HealthCheck struct {
+	// Name is the name of the check (e.g., consensus_synced).
+	Name string `json:"name"`
+
+	// Healthy is true iff the check passed.
+	Healthy bool `json:"healthy"`
+
+	// Reason is the reason why the check did not pass.
+	Reason string `json:"reason,omitempty"`
+}
+
+// HealthStatus is the result of a liveness or readiness check.
+type HealthStatus struct {
+	// Healthy is true iff all checks passed.
+	Healthy bool `json:"healthy"`
+
+	// Checks are the results of the individual checks.
+	Checks []HealthCheck `json:"checks"`
+}
+
+// NewHealthStatus creates a new health status from the results of the individual checks.
+func NewHealthStatus(checks []HealthCheck) *HealthStatus {
+	status := &HealthStatus{
+		Healthy: true,
+		Checks:  checks,
+	}
+	for _, check := range checks {
+		status.Healthy = status.Healthy && check.Healthy
+	}
+	return status
+}
+
 // RegistrationStatus is the node registration status.
 type RegistrationStatus struct {
 	// LastRegistration is the time of the last successful registration with the consensus registry
@@ -133,6 +258,134 @@ type RuntimeStatus struct {
 	Committee *commonWorker.Status `json:"committee"`
 	// Storage contains the storage worker status in case this node is a storage node.
 	Storage *storageWorker.Status `json:"storage"`
+	// Executor contains the executor worker status in case this node is a compute node.
+	Executor *executorWorker.Status `json:"executor,omitempty"`
+}
+
+// P2PPeer is the status of a P2P peer.
+type P2PPeer struct {
+	// ID is the P2P public key of the peer.
+	ID signature.PublicKey `json:"id"`
+
+	// PeerID is the libp2p peer identifier.
+	PeerID string `json:"peer_id"`
+
+	// Addresses are the known addresses of the peer.
+	Addresses []string `json:"addresses,omitempty"`
+
+	// Connected is true iff the peer is currently connected.
+	Connected bool `json:"connected"`
+
+	// Registered is true iff the peer belongs to a node in the registry.
+	Registered bool `json:"registered"`
+
+	// Score is the last known gossipsub score of the peer.
+	Score float64 `json:"score"`
+
+	// Ban is the ban status in case the peer is banned.
+	Ban *P2PPeerBan `json:"ban,omitempty"`
+}
+
+// P2PPeerBan is the ban status of a P2P peer.
+type P2PPeerBan struct {
+	// Until is the time when the ban expires.
+	Until time.Time `json:"until"`
+
+	// Reason is the reason for the ban.
+	Reason string `json:"reason"`
+}
+
+// BanP2PPeerRequest is a BanP2PPeer request.
+type BanP2PPeerRequest struct {
+	// ID is the P2P public key of the peer to ban.
+	ID signature.PublicKey `json:"id"`
+
+	// Duration is the duration of the ban. In case it is zero, the configured default ban duration
+	// is used.
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// Reason is an optional reason for the ban.
+	Reason string `json:"reason,omitempty"`
+}
+
+// SetLogLevelRequest is a SetLogLevel request.
+type SetLogLevelRequest struct {
+	// Module is the logging module (e.g., runtime/txpool).
+	Module string `json:"module"`
+
+	// Level is the new log level of the module. In case it is nil, the override is removed.
+	Level *logging.Level `json:"level,omitempty"`
+}
+
+const (
+	// ProfileCPU is the name of the CPU profile.
+	ProfileCPU = "cpu"
+	// ProfileTrace is the name of the execution trace.
+	ProfileTrace = "trace"
+	// ProfileBlock is the name of the blocking profile.
+	ProfileBlock = "block"
+	// ProfileMutex is the name of the mutex contention profile.
+	ProfileMutex = "mutex"
+)
+
+// SetProfilingRequest is a SetProfiling request.
+type SetProfilingRequest struct {
+	// Profile is the name of the profile (cpu, trace, block or mutex).
+	Profile string `json:"profile"`
+
+	// Enabled specifies whether collection of the profile should be enabled or disabled.
+	Enabled bool `json:"enabled"`
+
+	// Rate is the block profile rate (see runtime.SetBlockProfileRate) or the mutex profile
+	// fraction (see runtime.SetMutexProfileFraction). In case it is zero, all events are sampled.
+	Rate int `json:"rate,omitempty"`
+}
+
+// GetProfileRequest is a GetProfile request.
+type GetProfileRequest struct {
+	// Profile is the name of the profile (cpu, trace or any profile known to runtime/pprof, e.g.
+	// heap, goroutine, block or mutex).
+	Profile string `json:"profile"`
+}
+
+const (
+	// DefaultShutdownTimeout is the default time the node is given to drain before it shuts down.
+	DefaultShutdownTimeout = 5 * time.Minute
+
+	// DefaultDeregisterTimeout is the default time the node waits for its deregistration to take
+	// effect before it starts draining. As deregistration only takes effect at the next epoch
+	// transition, this is much longer than the drain timeout.
+	DefaultDeregisterTimeout = 2 * time.Hour
+)
+
+// ShutdownRequest is a Shutdown request.
+type ShutdownRequest struct {
+	// Wait specifies whether the method should also wait for the node to be drained and for the
+	// shutdown to start.
+	Wait bool `json:"wait,omitempty"`
+
+	// Deregister specifies whether the node should deregister (and wait for the deregistration
+	// to take effect) before draining.
+	Deregister bool `json:"deregister,omitempty"`
+
+	// Timeout is the time the node is given to drain before it shuts down. In case it is zero,
+	// DefaultShutdownTimeout is used.
+	//
+	// The drain deadline only starts once the deregistration (if any) takes effect or times out.
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	// DeregisterTimeout is the time the node waits for the deregistration to take effect before
+	// draining anyway. In case it is zero, DefaultDeregisterTimeout is used.
+	DeregisterTimeout time.Duration `json:"deregister_timeout,omitempty"`
+}
+
+// RemoveTxPoolTransactionsRequest is a RemoveTxPoolTransactions request.
+type RemoveTxPoolTransactionsRequest struct {
+	// RuntimeID is the identifier of the runtime.
+	RuntimeID common.Namespace `json:"runtime_id"`
+
+	// Hashes are the hashes of the transactions to remove.
+	Hashes []hash.Hash `json:"hashes"`
 }
 
 // ControlledNode is an internal interface that the controlled oasis-node must provide.
@@ -140,6 +393,10 @@ type ControlledNode interface {
 	// RequestShutdown is the method called by the control server to trigger node shutdown.
 	RequestShutdown() (<-chan struct{}, error)
 
+	// Shutdown is the method called by the control server to trigger a graceful node shutdown
+	// after draining. The returned channel is closed once the node starts shutting down.
+	Shutdown(req *ShutdownRequest) (<-chan struct{}, error)
+
 	// Ready returns a channel that is closed once node is ready.
 	Ready() <-chan struct{}
 
@@ -152,10 +409,107 @@ type ControlledNode interface {
 	// GetRuntimeStatus returns the node's current per-runtime status.
 	GetRuntimeStatus(ctx context.Context) (map[common.Namespace]RuntimeStatus, error)
 
+	// CheckRuntimesProvisioned checks whether each of the node's runtimes is provisioned.
+	CheckRuntimesProvisioned(ctx context.Context) []HealthCheck
+
+	// CheckSignerHealth checks whether the node's signer backend is healthy, in case the backend
+	// supports health checks.
+	CheckSignerHealth(ctx context.Context) []HealthCheck
+
 	// GetPendingUpgrade returns the node's pending upgrades.
 	GetPendingUpgrades(ctx context.Context) ([]*upgrade.PendingUpgrade, error)
+
+	// GetSentryStatus returns the node's sentry status, or nil in case the node is not a gRPC
+	// sentry node.
+	GetSentryStatus(ctx context.Context) (*sentry.Status, error)
+
+	// GetP2PPeers returns the status of the node's P2P peers.
+	GetP2PPeers(ctx context.Context) ([]*P2PPeer, error)
+
+	// BanP2PPeer bans a P2P peer.
+	BanP2PPeer(ctx context.Context, req *BanP2PPeerRequest) error
+
+	// UnbanP2PPeer lifts the ban of a P2P peer.
+	UnbanP2PPeer(ctx context.Context, id signature.PublicKey) error
+
+	// SetLogLevel overrides the log level of a logging module or removes the override.
+	SetLogLevel(ctx context.Context, req *SetLogLevelRequest) error
+
+	// ReloadConfig reloads the subset of the node configuration that can be changed at runtime.
+	ReloadConfig(ctx context.Context) error
+
+	// RotateCertificates rotates the node's TLS certificates and re-registers the node.
+	RotateCertificates(ctx context.Context) error
+
+	// GetTxPoolStatus returns the status of a runtime's transaction pool.
+	GetTxPoolStatus(ctx context.Context, runtimeID common.Namespace) (*executorWorker.TxPoolStatus, error)
+
+	// GetTxPoolTransactions returns the transactions waiting in a runtime's transaction pool.
+	GetTxPoolTransactions(ctx context.Context, runtimeID common.Namespace) ([]*executorWorker.TxPoolTransaction, error)
+
+	// RemoveTxPoolTransactions removes transactions from a runtime's transaction pool.
+	RemoveTxPoolTransactions(ctx context.Context, req *RemoveTxPoolTransactionsRequest) ([]hash.Hash, error)
+
+	// PauseRuntime pauses a runtime's executor worker.
+	PauseRuntime(ctx context.Context, runtimeID common.Namespace) error
+
+	// ResumeRuntime resumes a runtime's paused executor worker.
+	ResumeRuntime(ctx context.Context, runtimeID common.Namespace) error
+
+	// PruneRuntimeHistory prunes a runtime's block history.
+	PruneRuntimeHistory(ctx context.Context, runtimeID common.Namespace) error
+
+	// SetProfiling enables or disables the collection of a profile.
+	SetProfiling(ctx context.Context, req *SetProfilingRequest) error
+
+	// GetProfile returns a profile collected by the node.
+	GetProfile(ctx context.Context, req *GetProfileRequest) ([]byte, error)
 }
 
+// ModuleName is the module name for the node controller service.
+const ModuleName = "control"
+
+var (
+	// ErrP2PDisabled is the error returned when P2P operations are requested from a node that does
+	// not have the P2P worker enabled.
+	ErrP2PDisabled = errors.New(ModuleName, 1, "control: p2p not enabled")
+
+	// ErrP2PPeerNotBanned is the error returned when attempting to unban a P2P peer that is not
+	// banned.
+	ErrP2PPeerNotBanned = errors.New(ModuleName, 2, "control: p2p peer not banned")
+
+	// ErrTxPoolNotAvailable is the error returned when transaction pool operations are requested
+	// for a runtime for which the node does not maintain a transaction pool.
+	ErrTxPoolNotAvailable = errors.New(ModuleName, 3, "control: transaction pool not available")
+
+	// ErrExecutorNotAvailable is the error returned when executor worker operations are requested
+	// for a runtime for which the node is not running an executor worker.
+	ErrExecutorNotAvailable = errors.New(ModuleName, 4, "control: executor worker not available")
+
+	// ErrRegistrationNotAvailable is the error returned when registration operations are requested
+	// from a node that does not register itself.
+	ErrRegistrationNotAvailable = errors.New(ModuleName, 5, "control: node registration not available")
+
+	// ErrCertificateRotationPending is the error returned when a certificate rotation is requested
+	// before the previous rotation has been registered and seen by peers for at least an epoch.
+	ErrCertificateRotationPending = errors.New(ModuleName, 6, "control: previous certificate rotation still pending")
+
+	// ErrUnknownProfile is the error returned when an unknown profile is requested.
+	ErrUnknownProfile = errors.New(ModuleName, 7, "control: unknown profile")
+
+	// ErrProfilingInProgress is the error returned when the collection of a profile is enabled
+	// while already in progress, or the profile is requested before its collection is disabled.
+	ErrProfilingInProgress = errors.New(ModuleName, 8, "control: profile collection in progress")
+
+	// ErrProfileNotAvailable is the error returned when a profile is requested that has not been
+	// collected.
+	ErrProfileNotAvailable = errors.New(ModuleName, 9, "control: profile not available")
+
+	// ErrRuntimeNotAvailable is the error returned when runtime operations are requested for a
+	// runtime that is not supported by the node.
+	ErrRuntimeNotAvailable = errors.New(ModuleName, 10, "control: runtime not available")
+)
+
 // DebugModuleName is the module name for the debug controller service.
 const DebugModuleName = "control/debug"
 
@@ -173,4 +527,11 @@ type DebugController interface {
 
 	// WaitNodesRegistered waits for the given number of nodes to register.
 	WaitNodesRegistered(ctx context.Context, count int) error
+
+	// SetCrashPoints configures the given crash points, e.g., arms them with the given
+	// probability or disarms them by setting the probability to zero.
+	SetCrashPoints(ctx context.Context, points []crash.PointConfig) error
+
+	// GetCrashPoints returns the status of all registered crash points.
+	GetCrashPoints(ctx context.Context) ([]crash.PointStatus, error)
 }
@@ -5,8 +5,12 @@ import (
 
 	"google.golang.org/grpc"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
 	upgradeApi "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+	executorWorker "github.com/oasisprotocol/oasis-core/go/worker/compute/executor/api"
 )
 
 var (
@@ -14,7 +18,9 @@ var (
 	serviceName = cmnGrpc.NewServiceName("NodeController")
 
 	// methodRequestShutdown is the RequestShutdown method.
-	methodRequestShutdown = serviceName.NewMethod("RequestShutdown", false)
+	methodRequestShutdown = serviceName.NewMethod("RequestShutdown", false).WithAdminCapability()
+	// methodShutdown is the Shutdown method.
+	methodShutdown = serviceName.NewMethod("Shutdown", ShutdownRequest{}).WithAdminCapability()
 	// methodWaitSync is the WaitSync method.
 	methodWaitSync = serviceName.NewMethod("WaitSync", nil)
 	// methodIsSynced is the IsSynced method.
@@ -23,12 +29,46 @@ var (
 	methodWaitReady = serviceName.NewMethod("WaitReady", nil)
 	// methodIsReady is the IsReady method.
 	methodIsReady = serviceName.NewMethod("IsReady", nil)
+	// methodCheckLiveness is the CheckLiveness method.
+	methodCheckLiveness = serviceName.NewMethod("CheckLiveness", nil)
+	// methodCheckReadiness is the CheckReadiness method.
+	methodCheckReadiness = serviceName.NewMethod("CheckReadiness", nil)
 	// methodUpgradeBinary is the UpgradeBinary method.
-	methodUpgradeBinary = serviceName.NewMethod("UpgradeBinary", upgradeApi.Descriptor{})
+	methodUpgradeBinary = serviceName.NewMethod("UpgradeBinary", upgradeApi.Descriptor{}).WithAdminCapability()
 	// methodCancelUpgrade is the CancelUpgrade method.
-	methodCancelUpgrade = serviceName.NewMethod("CancelUpgrade", nil)
+	methodCancelUpgrade = serviceName.NewMethod("CancelUpgrade", nil).WithAdminCapability()
+	// methodDryRunUpgrade is the DryRunUpgrade method.
+	methodDryRunUpgrade = serviceName.NewMethod("DryRunUpgrade", upgradeApi.Descriptor{}).WithAdminCapability()
 	// methodGetStatus is the GetStatus method.
 	methodGetStatus = serviceName.NewMethod("GetStatus", nil)
+	// methodGetP2PPeers is the GetP2PPeers method.
+	methodGetP2PPeers = serviceName.NewMethod("GetP2PPeers", nil)
+	// methodBanP2PPeer is the BanP2PPeer method.
+	methodBanP2PPeer = serviceName.NewMethod("BanP2PPeer", BanP2PPeerRequest{}).WithAdminCapability()
+	// methodUnbanP2PPeer is the UnbanP2PPeer method.
+	methodUnbanP2PPeer = serviceName.NewMethod("UnbanP2PPeer", signature.PublicKey{}).WithAdminCapability()
+	// methodSetLogLevel is the SetLogLevel method.
+	methodSetLogLevel = serviceName.NewMethod("SetLogLevel", SetLogLevelRequest{}).WithAdminCapability()
+	// methodReloadConfig is the ReloadConfig method.
+	methodReloadConfig = serviceName.NewMethod("ReloadConfig", nil).WithAdminCapability()
+	// methodRotateCertificates is the RotateCertificates method.
+	methodRotateCertificates = serviceName.NewMethod("RotateCertificates", nil).WithAdminCapability()
+	// methodSetProfiling is the SetProfiling method.
+	methodSetProfiling = serviceName.NewMethod("SetProfiling", SetProfilingRequest{}).WithAdminCapability()
+	// methodGetProfile is the GetProfile method.
+	methodGetProfile = serviceName.NewMethod("GetProfile", GetProfileRequest{}).WithAdminCapability()
+	// methodGetTxPoolStatus is the GetTxPoolStatus method.
+	methodGetTxPoolStatus = serviceName.NewMethod("GetTxPoolStatus", common.Namespace{})
+	// methodGetTxPoolTransactions is the GetTxPoolTransactions method.
+	methodGetTxPoolTransactions = serviceName.NewMethod("GetTxPoolTransactions", common.Namespace{})
+	// methodRemoveTxPoolTransactions is the RemoveTxPoolTransactions method.
+	methodRemoveTxPoolTransactions = serviceName.NewMethod("RemoveTxPoolTransactions", RemoveTxPoolTransactionsRequest{}).WithAdminCapability()
+	// methodPauseRuntime is the PauseRuntime method.
+	methodPauseRuntime = serviceName.NewMethod("PauseRuntime", common.Namespace{}).WithAdminCapability()
+	// methodResumeRuntime is the ResumeRuntime method.
+	methodResumeRuntime = serviceName.NewMethod("ResumeRuntime", common.Namespace{}).WithAdminCapability()
+	// methodPruneRuntimeHistory is the PruneRuntimeHistory method.
+	methodPruneRuntimeHistory = serviceName.NewMethod("PruneRuntimeHistory", common.Namespace{}).WithAdminCapability()
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -39,6 +79,10 @@ var (
 				MethodName: methodRequestShutdown.ShortName(),
 				Handler:    handlerRequestShutdown,
 			},
+			{
+				MethodName: methodShutdown.ShortName(),
+				Handler:    handlerShutdown,
+			},
 			{
 				MethodName: methodWaitSync.ShortName(),
 				Handler:    handlerWaitSync,
@@ -55,6 +99,14 @@ var (
 				MethodName: methodIsReady.ShortName(),
 				Handler:    handlerIsReady,
 			},
+			{
+				MethodName: methodCheckLiveness.ShortName(),
+				Handler:    handlerCheckLiveness,
+			},
+			{
+				MethodName: methodCheckReadiness.ShortName(),
+				Handler:    handlerCheckReadiness,
+			},
 			{
 				MethodName: methodUpgradeBinary.ShortName(),
 				Handler:    handlerUpgradeBinary,
@@ -63,10 +115,70 @@ var (
 				MethodName: methodCancelUpgrade.ShortName(),
 				Handler:    handlerCancelUpgrade,
 			},
+			{
+				MethodName: methodDryRunUpgrade.ShortName(),
+				Handler:    handlerDryRunUpgrade,
+			},
 			{
 				MethodName: methodGetStatus.ShortName(),
 				Handler:    handlerGetStatus,
 			},
+			{
+				MethodName: methodGetP2PPeers.ShortName(),
+				Handler:    handlerGetP2PPeers,
+			},
+			{
+				MethodName: methodBanP2PPeer.ShortName(),
+				Handler:    handlerBanP2PPeer,
+			},
+			{
+				MethodName: methodUnbanP2PPeer.ShortName(),
+				Handler:    handlerUnbanP2PPeer,
+			},
+			{
+				MethodName: methodSetLogLevel.ShortName(),
+				Handler:    handlerSetLogLevel,
+			},
+			{
+				MethodName: methodReloadConfig.ShortName(),
+				Handler:    handlerReloadConfig,
+			},
+			{
+				MethodName: methodRotateCertificates.ShortName(),
+				Handler:    handlerRotateCertificates,
+			},
+			{
+				MethodName: methodSetProfiling.ShortName(),
+				Handler:    handlerSetProfiling,
+			},
+			{
+				MethodName: methodGetProfile.ShortName(),
+				Handler:    handlerGetProfile,
+			},
+			{
+				MethodName: methodGetTxPoolStatus.ShortName(),
+				Handler:    handlerGetTxPoolStatus,
+			},
+			{
+				MethodName: methodGetTxPoolTransactions.ShortName(),
+				Handler:    handlerGetTxPoolTransactions,
+			},
+			{
+				MethodName: methodRemoveTxPoolTransactions.ShortName(),
+				Handler:    handlerRemoveTxPoolTransactions,
+			},
+			{
+				MethodName: methodPauseRuntime.ShortName(),
+				Handler:    handlerPauseRuntime,
+			},
+			{
+				MethodName: methodResumeRuntime.ShortName(),
+				Handler:    handlerResumeRuntime,
+			},
+			{
+				MethodName: methodPruneRuntimeHistory.ShortName(),
+				Handler:    handlerPruneRuntimeHistory,
+			},
 		},
 		Streams: []grpc.StreamDesc{},
 	}
@@ -95,6 +207,29 @@ func handlerRequestShutdown( // nolint: golint
 	return interceptor(ctx, wait, info, handler)
 }
 
+func handlerShutdown( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var req ShutdownRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).Shutdown(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodShutdown.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(NodeController).Shutdown(ctx, req.(*ShutdownRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
 func handlerWaitSync( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -171,6 +306,44 @@ func handlerIsReady( // nolint: golint
 	return interceptor(ctx, nil, info, handler)
 }
 
+func handlerCheckLiveness( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return srv.(NodeController).CheckLiveness(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodCheckLiveness.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeController).CheckLiveness(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerCheckReadiness( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return srv.(NodeController).CheckReadiness(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodCheckReadiness.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeController).CheckReadiness(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
 func handlerUpgradeBinary( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -217,6 +390,29 @@ func handlerCancelUpgrade( // nolint: golint
 	return interceptor(ctx, &descriptor, info, handler)
 }
 
+func handlerDryRunUpgrade( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var descriptor upgradeApi.Descriptor
+	if err := dec(&descriptor); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeController).DryRunUpgrade(ctx, &descriptor)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodDryRunUpgrade.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeController).DryRunUpgrade(ctx, req.(*upgradeApi.Descriptor))
+	}
+	return interceptor(ctx, &descriptor, info, handler)
+}
+
 func handlerGetStatus( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -236,6 +432,247 @@ func handlerGetStatus( // nolint: golint
 	return interceptor(ctx, nil, info, handler)
 }
 
+func handlerGetP2PPeers( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return srv.(NodeController).GetP2PPeers(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetP2PPeers.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeController).GetP2PPeers(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerBanP2PPeer( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var req BanP2PPeerRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).BanP2PPeer(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodBanP2PPeer.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(NodeController).BanP2PPeer(ctx, req.(*BanP2PPeerRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
+func handlerUnbanP2PPeer( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var id signature.PublicKey
+	if err := dec(&id); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).UnbanP2PPeer(ctx, id)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodUnbanP2PPeer.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(NodeController).UnbanP2PPeer(ctx, req.(signature.PublicKey))
+	}
+	return interceptor(ctx, id, info, handler)
+}
+
+func handlerSetLogLevel( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var req SetLogLevelRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).SetLogLevel(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSetLogLevel.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(NodeController).SetLogLevel(ctx, req.(*SetLogLevelRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
+func handlerReloadConfig( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return nil, srv.(NodeController).ReloadConfig(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodReloadConfig.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(NodeController).ReloadConfig(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerRotateCertificates( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return nil, srv.(NodeController).RotateCertificates(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodRotateCertificates.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(NodeController).RotateCertificates(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerSetProfiling( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var req SetProfilingRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).SetProfiling(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSetProfiling.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(NodeController).SetProfiling(ctx, req.(*SetProfilingRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
+func handlerGetProfile( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var req GetProfileRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeController).GetProfile(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetProfile.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeController).GetProfile(ctx, req.(*GetProfileRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
+func handlerGetTxPoolStatus( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var runtimeID common.Namespace
+	if err := dec(&runtimeID); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeController).GetTxPoolStatus(ctx, runtimeID)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetTxPoolStatus.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeController).GetTxPoolStatus(ctx, req.(common.Namespace))
+	}
+	return interceptor(ctx, runtimeID, info, handler)
+}
+
+func handlerGetTxPoolTransactions( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var runtimeID common.Namespace
+	if err := dec(&runtimeID); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeController).GetTxPoolTransactions(ctx, runtimeID)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetTxPoolTransactions.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeController).GetTxPoolTransactions(ctx, req.(common.Namespace))
+	}
+	return interceptor(ctx, runtimeID, info, handler)
+}
+
+func handlerRemoveTxPoolTransactions( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var req RemoveTxPoolTransactionsRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeController).RemoveTxPoolTransactions(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodRemoveTxPoolTransactions.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeController).RemoveTxPoolTransactions(ctx, req.(*RemoveTxPoolTransactionsRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
 // RegisterService registers a new node controller service with the given gRPC server.
 func RegisterService(server *grpc.Server, service NodeController) {
 	server.RegisterService(&serviceDesc, service)
@@ -249,6 +686,10 @@ func (c *nodeControllerClient) RequestShutdown(ctx context.Context, wait bool) e
 	return c.conn.Invoke(ctx, methodRequestShutdown.FullName(), wait, nil)
 }
 
+func (c *nodeControllerClient) Shutdown(ctx context.Context, req *ShutdownRequest) error {
+	return c.conn.Invoke(ctx, methodShutdown.FullName(), req, nil)
+}
+
 func (c *nodeControllerClient) WaitSync(ctx context.Context) error {
 	return c.conn.Invoke(ctx, methodWaitSync.FullName(), nil, nil)
 }
@@ -273,6 +714,22 @@ func (c *nodeControllerClient) IsReady(ctx context.Context) (bool, error) {
 	return rsp, nil
 }
 
+func (c *nodeControllerClient) CheckLiveness(ctx context.Context) (*HealthStatus, error) {
+	var rsp HealthStatus
+	if err := c.conn.Invoke(ctx, methodCheckLiveness.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *nodeControllerClient) CheckReadiness(ctx context.Context) (*HealthStatus, error) {
+	var rsp HealthStatus
+	if err := c.conn.Invoke(ctx, methodCheckReadiness.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *nodeControllerClient) UpgradeBinary(ctx context.Context, descriptor *upgradeApi.Descriptor) error {
 	return c.conn.Invoke(ctx, methodUpgradeBinary.FullName(), descriptor, nil)
 }
@@ -281,6 +738,14 @@ func (c *nodeControllerClient) CancelUpgrade(ctx context.Context, descriptor *up
 	return c.conn.Invoke(ctx, methodCancelUpgrade.FullName(), descriptor, nil)
 }
 
+func (c *nodeControllerClient) DryRunUpgrade(ctx context.Context, descriptor *upgradeApi.Descriptor) (*upgradeApi.DryRunResult, error) {
+	var rsp upgradeApi.DryRunResult
+	if err := c.conn.Invoke(ctx, methodDryRunUpgrade.FullName(), descriptor, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *nodeControllerClient) GetStatus(ctx context.Context) (*Status, error) {
 	var rsp Status
 	if err := c.conn.Invoke(ctx, methodGetStatus.FullName(), nil, &rsp); err != nil {
@@ -289,6 +754,151 @@ func (c *nodeControllerClient) GetStatus(ctx context.Context) (*Status, error) {
 	return &rsp, nil
 }
 
+func (c *nodeControllerClient) GetP2PPeers(ctx context.Context) ([]*P2PPeer, error) {
+	var rsp []*P2PPeer
+	if err := c.conn.Invoke(ctx, methodGetP2PPeers.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *nodeControllerClient) BanP2PPeer(ctx context.Context, req *BanP2PPeerRequest) error {
+	return c.conn.Invoke(ctx, methodBanP2PPeer.FullName(), req, nil)
+}
+
+func (c *nodeControllerClient) UnbanP2PPeer(ctx context.Context, id signature.PublicKey) error {
+	return c.conn.Invoke(ctx, methodUnbanP2PPeer.FullName(), id, nil)
+}
+
+func (c *nodeControllerClient) SetLogLevel(ctx context.Context, req *SetLogLevelRequest) error {
+	return c.conn.Invoke(ctx, methodSetLogLevel.FullName(), req, nil)
+}
+
+func (c *nodeControllerClient) ReloadConfig(ctx context.Context) error {
+	return c.conn.Invoke(ctx, methodReloadConfig.FullName(), nil, nil)
+}
+
+func (c *nodeControllerClient) RotateCertificates(ctx context.Context) error {
+	return c.conn.Invoke(ctx, methodRotateCertificates.FullName(), nil, nil)
+}
+
+func (c *nodeControllerClient) SetProfiling(ctx context.Context, req *SetProfilingRequest) error {
+	return c.conn.Invoke(ctx, methodSetProfiling.FullName(), req, nil)
+}
+
+func (c *nodeControllerClient) GetProfile(ctx context.Context, req *GetProfileRequest) ([]byte, error) {
+	var rsp []byte
+	if err := c.conn.Invoke(ctx, methodGetProfile.FullName(), req, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *nodeControllerClient) GetTxPoolStatus(ctx context.Context, runtimeID common.Namespace) (*executorWorker.TxPoolStatus, error) {
+	var rsp executorWorker.TxPoolStatus
+	if err := c.conn.Invoke(ctx, methodGetTxPoolStatus.FullName(), runtimeID, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *nodeControllerClient) GetTxPoolTransactions(ctx context.Context, runtimeID common.Namespace) ([]*executorWorker.TxPoolTransaction, error) {
+	var rsp []*executorWorker.TxPoolTransaction
+	if err := c.conn.Invoke(ctx, methodGetTxPoolTransactions.FullName(), runtimeID, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func handlerPauseRuntime( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var runtimeID common.Namespace
+	if err := dec(&runtimeID); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).PauseRuntime(ctx, runtimeID)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodPauseRuntime.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(NodeController).PauseRuntime(ctx, req.(common.Namespace))
+	}
+	return interceptor(ctx, runtimeID, info, handler)
+}
+
+func handlerResumeRuntime( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var runtimeID common.Namespace
+	if err := dec(&runtimeID); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).ResumeRuntime(ctx, runtimeID)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodResumeRuntime.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(NodeController).ResumeRuntime(ctx, req.(common.Namespace))
+	}
+	return interceptor(ctx, runtimeID, info, handler)
+}
+
+func handlerPruneRuntimeHistory( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var runtimeID common.Namespace
+	if err := dec(&runtimeID); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).PruneRuntimeHistory(ctx, runtimeID)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodPruneRuntimeHistory.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(NodeController).PruneRuntimeHistory(ctx, req.(common.Namespace))
+	}
+	return interceptor(ctx, runtimeID, info, handler)
+}
+
+func (c *nodeControllerClient) RemoveTxPoolTransactions(ctx context.Context, req *RemoveTxPoolTransactionsRequest) ([]hash.Hash, error) {
+	var rsp []hash.Hash
+	if err := c.conn.Invoke(ctx, methodRemoveTxPoolTransactions.FullName(), req, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *nodeControllerClient) PauseRuntime(ctx context.Context, runtimeID common.Namespace) error {
+	return c.conn.Invoke(ctx, methodPauseRuntime.FullName(), runtimeID, nil)
+}
+
+func (c *nodeControllerClient) ResumeRuntime(ctx context.Context, runtimeID common.Namespace) error {
+	return c.conn.Invoke(ctx, methodResumeRuntime.FullName(), runtimeID, nil)
+}
+
+func (c *nodeControllerClient) PruneRuntimeHistory(ctx context.Context, runtimeID common.Namespace) error {
+	return c.conn.Invoke(ctx, methodPruneRuntimeHistory.FullName(), runtimeID, nil)
+}
+
 // NewNodeControllerClient creates a new gRPC node controller client service.
 func NewNodeControllerClient(c *grpc.ClientConn) NodeController {
 	return &nodeControllerClient{c}
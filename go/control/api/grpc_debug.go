@@ -6,6 +6,7 @@ import (
 	"google.golang.org/grpc"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/crash"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
 )
 
@@ -14,9 +15,13 @@ var (
 	debugServiceName = cmnGrpc.NewServiceName("DebugController")
 
 	// methodSetEpoch is the SetEpoch method.
-	methodSetEpoch = debugServiceName.NewMethod("SetEpoch", beacon.EpochTime(0))
+	methodSetEpoch = debugServiceName.NewMethod("SetEpoch", beacon.EpochTime(0)).WithAdminCapability()
 	// methodWaitNodesRegistered is the WaitNodesRegistered method.
-	methodWaitNodesRegistered = debugServiceName.NewMethod("WaitNodesRegistered", int(0))
+	methodWaitNodesRegistered = debugServiceName.NewMethod("WaitNodesRegistered", int(0)).WithAdminCapability()
+	// methodSetCrashPoints is the SetCrashPoints method.
+	methodSetCrashPoints = debugServiceName.NewMethod("SetCrashPoints", []crash.PointConfig{}).WithAdminCapability()
+	// methodGetCrashPoints is the GetCrashPoints method.
+	methodGetCrashPoints = debugServiceName.NewMethod("GetCrashPoints", nil).WithAdminCapability()
 
 	// debugServiceDesc is the gRPC service descriptor.
 	debugServiceDesc = grpc.ServiceDesc{
@@ -31,6 +36,14 @@ var (
 				MethodName: methodWaitNodesRegistered.ShortName(),
 				Handler:    handlerWaitNodesRegistered,
 			},
+			{
+				MethodName: methodSetCrashPoints.ShortName(),
+				Handler:    handlerSetCrashPoints,
+			},
+			{
+				MethodName: methodGetCrashPoints.ShortName(),
+				Handler:    handlerGetCrashPoints,
+			},
 		},
 		Streams: []grpc.StreamDesc{},
 	}
@@ -82,6 +95,48 @@ func handlerWaitNodesRegistered( // nolint: golint
 	return interceptor(ctx, count, info, handler)
 }
 
+func handlerSetCrashPoints( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var points []crash.PointConfig
+	if err := dec(&points); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(DebugController).SetCrashPoints(ctx, points)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSetCrashPoints.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(DebugController).SetCrashPoints(ctx, req.([]crash.PointConfig))
+	}
+	return interceptor(ctx, points, info, handler)
+}
+
+func handlerGetCrashPoints( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return srv.(DebugController).GetCrashPoints(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetCrashPoints.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DebugController).GetCrashPoints(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
 // RegisterDebugService registers a new debug controller service with the given gRPC server.
 func RegisterDebugService(server *grpc.Server, service DebugController) {
 	server.RegisterService(&debugServiceDesc, service)
@@ -99,6 +154,18 @@ func (c *debugControllerClient) WaitNodesRegistered(ctx context.Context, count i
 	return c.conn.Invoke(ctx, methodWaitNodesRegistered.FullName(), count, nil)
 }
 
+func (c *debugControllerClient) SetCrashPoints(ctx context.Context, points []crash.PointConfig) error {
+	return c.conn.Invoke(ctx, methodSetCrashPoints.FullName(), points, nil)
+}
+
+func (c *debugControllerClient) GetCrashPoints(ctx context.Context) ([]crash.PointStatus, error) {
+	var rsp []crash.PointStatus
+	if err := c.conn.Invoke(ctx, methodGetCrashPoints.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
 // NewDebugControllerClient creates a new gRPC debug controller client service.
 func NewDebugControllerClient(c *grpc.ClientConn) DebugController {
 	return &debugControllerClient{c}
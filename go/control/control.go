@@ -5,10 +5,14 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+	executorWorker "github.com/oasisprotocol/oasis-core/go/worker/compute/executor/api"
 )
 
 type nodeController struct {
@@ -33,6 +37,22 @@ func (c *nodeController) RequestShutdown(ctx context.Context, wait bool) error {
 	return nil
 }
 
+func (c *nodeController) Shutdown(ctx context.Context, req *control.ShutdownRequest) error {
+	ch, err := c.node.Shutdown(req)
+	if err != nil {
+		return err
+	}
+	if req.Wait {
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
 func (c *nodeController) WaitSync(ctx context.Context) error {
 	select {
 	case <-ctx.Done():
@@ -73,6 +93,40 @@ func (c *nodeController) IsReady(ctx context.Context) (bool, error) {
 	}
 }
 
+func (c *nodeController) CheckLiveness(ctx context.Context) (*control.HealthStatus, error) {
+	consensusCheck := control.HealthCheck{Name: "consensus", Healthy: true}
+	if _, err := c.consensus.GetStatus(ctx); err != nil {
+		consensusCheck.Healthy = false
+		consensusCheck.Reason = fmt.Sprintf("failed to get consensus status: %s", err)
+	}
+
+	return control.NewHealthStatus([]control.HealthCheck{consensusCheck}), nil
+}
+
+func (c *nodeController) CheckReadiness(ctx context.Context) (*control.HealthStatus, error) {
+	synced, err := c.IsSynced(ctx)
+	if err != nil {
+		return nil, err
+	}
+	syncedCheck := control.HealthCheck{Name: "consensus_synced", Healthy: synced}
+	if !synced {
+		syncedCheck.Reason = "consensus has not completed initial syncing"
+	}
+
+	ready, err := c.IsReady(ctx)
+	if err != nil {
+		return nil, err
+	}
+	readyCheck := control.HealthCheck{Name: "workers_initialized", Healthy: ready}
+	if !ready {
+		readyCheck.Reason = "workers are not yet initialized"
+	}
+
+	checks := append([]control.HealthCheck{syncedCheck, readyCheck}, c.node.CheckSignerHealth(ctx)...)
+	checks = append(checks, c.node.CheckRuntimesProvisioned(ctx)...)
+	return control.NewHealthStatus(checks), nil
+}
+
 func (c *nodeController) UpgradeBinary(ctx context.Context, descriptor *upgrade.Descriptor) error {
 	return c.upgrader.SubmitDescriptor(ctx, descriptor)
 }
@@ -81,6 +135,10 @@ func (c *nodeController) CancelUpgrade(ctx context.Context, descriptor *upgrade.
 	return c.upgrader.CancelUpgrade(ctx, descriptor)
 }
 
+func (c *nodeController) DryRunUpgrade(ctx context.Context, descriptor *upgrade.Descriptor) (*upgrade.DryRunResult, error) {
+	return c.consensus.DryRunUpgrade(ctx, descriptor)
+}
+
 func (c *nodeController) GetStatus(ctx context.Context) (*control.Status, error) {
 	cs, err := c.consensus.GetStatus(ctx)
 	if err != nil {
@@ -102,6 +160,11 @@ func (c *nodeController) GetStatus(ctx context.Context) (*control.Status, error)
 		return nil, fmt.Errorf("failed to get pending upgrades: %w", err)
 	}
 
+	sentryStatus, err := c.node.GetSentryStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sentry status: %w", err)
+	}
+
 	ident := c.node.GetIdentity()
 
 	return &control.Status{
@@ -116,9 +179,66 @@ func (c *nodeController) GetStatus(ctx context.Context) (*control.Status, error)
 		Runtimes:        runtimes,
 		Registration:    *rs,
 		PendingUpgrades: pendingUpgrades,
+		Sentry:          sentryStatus,
 	}, nil
 }
 
+func (c *nodeController) GetP2PPeers(ctx context.Context) ([]*control.P2PPeer, error) {
+	return c.node.GetP2PPeers(ctx)
+}
+
+func (c *nodeController) BanP2PPeer(ctx context.Context, req *control.BanP2PPeerRequest) error {
+	return c.node.BanP2PPeer(ctx, req)
+}
+
+func (c *nodeController) UnbanP2PPeer(ctx context.Context, id signature.PublicKey) error {
+	return c.node.UnbanP2PPeer(ctx, id)
+}
+
+func (c *nodeController) SetLogLevel(ctx context.Context, req *control.SetLogLevelRequest) error {
+	return c.node.SetLogLevel(ctx, req)
+}
+
+func (c *nodeController) ReloadConfig(ctx context.Context) error {
+	return c.node.ReloadConfig(ctx)
+}
+
+func (c *nodeController) RotateCertificates(ctx context.Context) error {
+	return c.node.RotateCertificates(ctx)
+}
+
+func (c *nodeController) SetProfiling(ctx context.Context, req *control.SetProfilingRequest) error {
+	return c.node.SetProfiling(ctx, req)
+}
+
+func (c *nodeController) GetProfile(ctx context.Context, req *control.GetProfileRequest) ([]byte, error) {
+	return c.node.GetProfile(ctx, req)
+}
+
+func (c *nodeController) GetTxPoolStatus(ctx context.Context, runtimeID common.Namespace) (*executorWorker.TxPoolStatus, error) {
+	return c.node.GetTxPoolStatus(ctx, runtimeID)
+}
+
+func (c *nodeController) GetTxPoolTransactions(ctx context.Context, runtimeID common.Namespace) ([]*executorWorker.TxPoolTransaction, error) {
+	return c.node.GetTxPoolTransactions(ctx, runtimeID)
+}
+
+func (c *nodeController) RemoveTxPoolTransactions(ctx context.Context, req *control.RemoveTxPoolTransactionsRequest) ([]hash.Hash, error) {
+	return c.node.RemoveTxPoolTransactions(ctx, req)
+}
+
+func (c *nodeController) PauseRuntime(ctx context.Context, runtimeID common.Namespace) error {
+	return c.node.PauseRuntime(ctx, runtimeID)
+}
+
+func (c *nodeController) ResumeRuntime(ctx context.Context, runtimeID common.Namespace) error {
+	return c.node.ResumeRuntime(ctx, runtimeID)
+}
+
+func (c *nodeController) PruneRuntimeHistory(ctx context.Context, runtimeID common.Namespace) error {
+	return c.node.PruneRuntimeHistory(ctx, runtimeID)
+}
+
 // New creates a new oasis-node controller.
 func New(node control.ControlledNode, consensus consensus.Backend, upgrader upgrade.Backend) control.NodeController {
 	return &nodeController{
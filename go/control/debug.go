@@ -4,6 +4,7 @@ import (
 	"context"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/crash"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/control/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
@@ -62,6 +63,14 @@ Loop:
 	return nil
 }
 
+func (c *debugController) SetCrashPoints(ctx context.Context, points []crash.PointConfig) error {
+	return crash.SetPoints(points)
+}
+
+func (c *debugController) GetCrashPoints(ctx context.Context) ([]crash.PointStatus, error) {
+	return crash.Points(), nil
+}
+
 // New creates a new oasis-node debug controller.
 func NewDebug(consensus consensus.Backend) api.DebugController {
 	return &debugController{
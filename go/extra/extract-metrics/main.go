@@ -222,6 +222,10 @@ func checkNewPrometheusMetric(f *token.FileSet, n ast.Node) (m Metric, ok bool)
 		}
 		return true
 	})
+	if m.Name == "" {
+		// Not a metric definition (e.g., a descriptor or a constant metric of a custom collector).
+		return m, false
+	}
 
 	// If labels are defined, extract them.
 	if len(c.Args) > 1 {
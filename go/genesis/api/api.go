@@ -3,6 +3,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"time"
@@ -21,6 +22,10 @@ import (
 
 const filePerm = 0o600
 
+// ErrHashMismatch is the error returned when the genesis document hash does
+// not match the expected hash.
+var ErrHashMismatch = errors.New("genesis: document hash mismatch")
+
 // Document is a genesis document.
 type Document struct {
 	// Height is the block height at which the document was generated.
@@ -51,11 +56,47 @@ type Document struct {
 	// Extra data is arbitrary extra data that is part of the
 	// genesis block but is otherwise ignored by the protocol.
 	ExtraData map[string][]byte `json:"extra_data"`
+	// Provenance is the optional metadata describing where the document
+	// was exported from.
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+// Provenance is the metadata describing the origin of an exported genesis
+// document.
+type Provenance struct {
+	// SourceChainID is the ID of the chain the state was exported from.
+	SourceChainID string `json:"source_chain_id"`
+	// ExportHeight is the block height at which the state was exported.
+	ExportHeight int64 `json:"export_height"`
+	// ToolVersion is the version of the software that exported the state.
+	//
+	// It is informational only and is not part of the genesis document hash.
+	ToolVersion string `json:"tool_version"`
 }
 
 // Hash returns the cryptographic hash of the encoded genesis document.
+//
+// The provenance tool version is not part of the hash, so that the same state exported by
+// different software versions results in the same hash.
 func (d *Document) Hash() hash.Hash {
-	return hash.NewFrom(d)
+	if d.Provenance == nil || d.Provenance.ToolVersion == "" {
+		return hash.NewFrom(d)
+	}
+
+	doc := *d
+	provenance := *d.Provenance
+	provenance.ToolVersion = ""
+	doc.Provenance = &provenance
+	return hash.NewFrom(&doc)
+}
+
+// VerifyHash checks that the cryptographic hash of the encoded genesis
+// document matches the expected hash.
+func (d *Document) VerifyHash(expected hash.Hash) error {
+	if h := d.Hash(); !h.Equal(&expected) {
+		return fmt.Errorf("%w (expected: %s actual: %s)", ErrHashMismatch, expected, h)
+	}
+	return nil
 }
 
 // ChainContext returns a string that can be used as a chain domain separation
@@ -1,10 +1,17 @@
 package api
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
 // SanityCheck does basic sanity checking on the contents of the genesis document.
@@ -53,5 +60,105 @@ func (d *Document) SanityCheck() error {
 		return fmt.Errorf("genesis: sanity check failed: halt epoch is in the past")
 	}
 
+	if d.Provenance != nil {
+		if err := d.Provenance.SanityCheck(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// SanityCheck does basic sanity checking on the provenance metadata.
+func (p *Provenance) SanityCheck() error {
+	if strings.TrimSpace(p.SourceChainID) == "" {
+		return fmt.Errorf("genesis: sanity check failed: provenance source chain ID must not be empty")
+	}
+	if p.ExportHeight < 1 {
+		return fmt.Errorf("genesis: sanity check failed: provenance export height must be >= 1")
+	}
+	return nil
+}
+
+// SanityCheckDeep performs the same checks as SanityCheck, but instead of stopping at the first
+// violation it runs all module sanity checks and additionally checks each registry entity and
+// runtime and each staking account individually, so that all violations are reported at once.
+//
+// In case the document passes all checks, an empty list is returned.
+func (d *Document) SanityCheckDeep() []error {
+	var errs []error
+	seen := make(map[string]bool)
+	report := func(err error) {
+		if err == nil || seen[err.Error()] {
+			return
+		}
+		seen[err.Error()] = true
+		errs = append(errs, err)
+	}
+
+	if d.Height < 1 {
+		report(fmt.Errorf("genesis: sanity check failed: height must be >= 1"))
+	}
+	if strings.TrimSpace(d.ChainID) == "" {
+		report(fmt.Errorf("genesis: sanity check failed: chain ID must not be empty"))
+	}
+
+	pkBlacklist := make(map[signature.PublicKey]bool)
+	for _, v := range d.Consensus.Parameters.PublicKeyBlacklist {
+		pkBlacklist[v] = true
+	}
+	epoch := d.Beacon.Base
+
+	report(d.Consensus.SanityCheck())
+	report(d.Beacon.SanityCheck())
+	report(d.Registry.SanityCheck(d.Time, epoch, d.Staking.Ledger, d.Staking.Parameters.Thresholds, pkBlacklist))
+	report(d.RootHash.SanityCheck())
+	report(d.Staking.SanityCheck(epoch))
+	report(d.KeyManager.SanityCheck())
+	report(d.Scheduler.SanityCheck(&d.Staking.TotalSupply))
+	report(d.Governance.SanityCheck(epoch, &d.Staking.GovernanceDeposits))
+
+	if d.HaltEpoch < epoch {
+		report(fmt.Errorf("genesis: sanity check failed: halt epoch is in the past"))
+	}
+	if d.Provenance != nil {
+		report(d.Provenance.SanityCheck())
+	}
+
+	// Module sanity checks stop at the first violation, so check individual items as well.
+	logger := logging.GetLogger("genesis/sanity-check")
+	for _, signedEnt := range d.Registry.Entities {
+		_, err := registry.SanityCheckEntities(logger, []*entity.SignedEntity{signedEnt})
+		report(err)
+	}
+	for _, rts := range [][]*registry.Runtime{d.Registry.Runtimes, d.Registry.SuspendedRuntimes} {
+		for _, rt := range rts {
+			if err := registry.VerifyRuntime(&d.Registry.Parameters, logger, rt, true, true); err != nil {
+				report(fmt.Errorf("runtime sanity check failed: %w", err))
+			}
+		}
+	}
+
+	addrs := make([]staking.Address, 0, len(d.Staking.Ledger))
+	for addr := range d.Staking.Ledger {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i][:], addrs[j][:]) < 0
+	})
+	for _, addr := range addrs {
+		acct := d.Staking.Ledger[addr]
+
+		var total quantity.Quantity
+		report(staking.SanityCheckAccount(&total, &d.Staking.Parameters, epoch, addr, acct))
+		if delegations, ok := d.Staking.Delegations[addr]; ok {
+			report(staking.SanityCheckDelegations(addr, acct, delegations))
+		}
+		if delegations, ok := d.Staking.DebondingDelegations[addr]; ok {
+			report(staking.SanityCheckDebondingDelegations(addr, acct, delegations))
+		}
+		report(staking.SanityCheckAccountShares(addr, acct, d.Staking.Delegations[addr], d.Staking.DebondingDelegations[addr]))
+	}
+
+	return errs
+}
@@ -0,0 +1,320 @@
+package api
+
+import (
+	"bufio"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// sections returns the top-level sections of the genesis document keyed by their JSON name.
+func (d *Document) sections() map[string]interface{} {
+	return map[string]interface{}{
+		"height":       &d.Height,
+		"genesis_time": &d.Time,
+		"chain_id":     &d.ChainID,
+		"registry":     &d.Registry,
+		"roothash":     &d.RootHash,
+		"staking":      &d.Staking,
+		"keymanager":   &d.KeyManager,
+		"scheduler":    &d.Scheduler,
+		"beacon":       &d.Beacon,
+		"governance":   &d.Governance,
+		"consensus":    &d.Consensus,
+		"halt_epoch":   &d.HaltEpoch,
+		"extra_data":   &d.ExtraData,
+		"provenance":   &d.Provenance,
+	}
+}
+
+// ReadDocument stream-parses a JSON encoded genesis document.
+//
+// Instead of buffering the whole (potentially very large) document in memory before decoding
+// it, the document is decoded directly from the reader. Objects and arrays (e.g., the staking
+// ledger or the list of registered nodes) are decoded element by element, so at most a single
+// leaf value (e.g., one account) is buffered at any time. Errors identify the section that failed
+// to decode. Unknown sections are rejected. The document is not sanity checked.
+func ReadDocument(r io.Reader) (*Document, error) {
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	var doc Document
+	sections := doc.sections()
+	seen := make(map[string]bool)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("genesis: malformed genesis document: %w", err)
+		}
+		name, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("genesis: malformed genesis document: unexpected token: %v", tok)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("genesis: malformed genesis document: duplicate section '%s'", name)
+		}
+		seen[name] = true
+
+		dst, ok := sections[name]
+		if !ok {
+			return nil, fmt.Errorf("genesis: malformed genesis document: unknown section '%s'", name)
+		}
+		if err = decodeStream(dec, reflect.ValueOf(dst).Elem()); err != nil {
+			return nil, fmt.Errorf("genesis: malformed genesis document: section '%s': %w", name, err)
+		}
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("genesis: malformed genesis document: trailing data after document")
+	}
+
+	return &doc, nil
+}
+
+// ReadDocumentFile stream-parses a JSON encoded genesis document from the given file.
+//
+// The document is not sanity checked.
+func ReadDocumentFile(filename string) (*Document, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadDocument(bufio.NewReader(f))
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("genesis: malformed genesis document: %w", err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("genesis: malformed genesis document: expected '%s', got: %v", delim, tok)
+	}
+	return nil
+}
+
+// decodeStream decodes the next JSON value from the decoder into v, following the semantics of
+// encoding/json. Structs, maps and slices that do not implement custom unmarshalling are decoded
+// element by element instead of buffering the whole value.
+func decodeStream(dec *json.Decoder, v reflect.Value) error {
+	if !isStreamable(v.Type()) {
+		return dec.Decode(v.Addr().Interface())
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		// Same as encoding/json, null sets pointers, maps and slices to nil and is a no-op
+		// for other types.
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Map, reflect.Slice:
+			v.Set(reflect.Zero(v.Type()))
+		}
+		return nil
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if tok != json.Delim('{') {
+			return fmt.Errorf("cannot decode %v into %s", tok, v.Type())
+		}
+		return decodeStreamStruct(dec, v)
+	case reflect.Map:
+		if tok != json.Delim('{') {
+			return fmt.Errorf("cannot decode %v into %s", tok, v.Type())
+		}
+		return decodeStreamMap(dec, v)
+	case reflect.Slice:
+		if tok != json.Delim('[') {
+			return fmt.Errorf("cannot decode %v into %s", tok, v.Type())
+		}
+		return decodeStreamSlice(dec, v)
+	default:
+		panic("genesis: unexpected streamable kind: " + v.Kind().String())
+	}
+}
+
+func decodeStreamStruct(dec *json.Decoder, v reflect.Value) error {
+	fields := make(map[string]int)
+	for i := 0; i < v.NumField(); i++ {
+		if name := jsonFieldName(v.Type().Field(i)); name != "" {
+			fields[name] = i
+		}
+	}
+
+	for dec.More() {
+		key, err := decodeKey(dec)
+		if err != nil {
+			return err
+		}
+
+		idx, ok := fields[key]
+		if !ok {
+			// Same as encoding/json, fall back to a case-insensitive match.
+			idx = -1
+			for name, i := range fields {
+				if strings.EqualFold(name, key) {
+					idx = i
+					break
+				}
+			}
+		}
+		if idx < 0 {
+			// Skip unknown fields, same as encoding/json does.
+			var skip json.RawMessage
+			if err = dec.Decode(&skip); err != nil {
+				return err
+			}
+			continue
+		}
+		if err = decodeStream(dec, v.Field(idx)); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	_, err := dec.Token()
+	return err
+}
+
+func decodeStreamMap(dec *json.Decoder, v reflect.Value) error {
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+
+	for dec.More() {
+		rawKey, err := decodeKey(dec)
+		if err != nil {
+			return err
+		}
+
+		key := reflect.New(v.Type().Key()).Elem()
+		switch {
+		case reflect.PtrTo(key.Type()).Implements(textUnmarshalerType):
+			err = key.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(rawKey))
+		case key.Kind() == reflect.String:
+			key.SetString(rawKey)
+		case key.Kind() >= reflect.Int && key.Kind() <= reflect.Int64:
+			var n int64
+			if n, err = strconv.ParseInt(rawKey, 10, key.Type().Bits()); err == nil {
+				key.SetInt(n)
+			}
+		default:
+			var n uint64
+			if n, err = strconv.ParseUint(rawKey, 10, key.Type().Bits()); err == nil {
+				key.SetUint(n)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("malformed key '%s': %w", rawKey, err)
+		}
+
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if err = decodeStream(dec, elem); err != nil {
+			return fmt.Errorf("%s: %w", rawKey, err)
+		}
+		v.SetMapIndex(key, elem)
+	}
+	_, err := dec.Token()
+	return err
+}
+
+func decodeStreamSlice(dec *json.Decoder, v reflect.Value) error {
+	v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+	for i := 0; dec.More(); i++ {
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if err := decodeStream(dec, elem); err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+		v.Set(reflect.Append(v, elem))
+	}
+	_, err := dec.Token()
+	return err
+}
+
+func decodeKey(dec *json.Decoder) (string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected token: %v", tok)
+	}
+	return key, nil
+}
+
+// isStreamable returns true iff values of the given type can be decoded element by element by
+// decodeStream.
+func isStreamable(t reflect.Type) bool {
+	if t.Implements(jsonUnmarshalerType) || reflect.PtrTo(t).Implements(jsonUnmarshalerType) ||
+		t.Implements(textUnmarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return t.Elem().Kind() != reflect.Ptr && isStreamable(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Anonymous || strings.Contains(f.Tag.Get("json"), ",string") {
+				// Promoted fields and string-encoded fields are left to encoding/json.
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		switch k := t.Key(); {
+		case reflect.PtrTo(k).Implements(textUnmarshalerType):
+			return true
+		default:
+			return k.Kind() == reflect.String || (k.Kind() >= reflect.Int && k.Kind() <= reflect.Uintptr)
+		}
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Uint8
+	default:
+		return false
+	}
+}
+
+// jsonFieldName returns the JSON name of the given struct field or an empty string in case the
+// field is not decoded.
+func jsonFieldName(f reflect.StructField) string {
+	if f.PkgPath != "" {
+		return ""
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name
+	}
+	return f.Name
+}
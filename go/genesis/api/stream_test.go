@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+)
+
+type testStreamKey string
+
+func (k *testStreamKey) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return fmt.Errorf("empty key")
+	}
+	*k = testStreamKey("key-" + string(text))
+	return nil
+}
+
+type testStreamEntry struct {
+	Amount quantity.Quantity `json:"amount"`
+	Tags   []string          `json:"tags,omitempty"`
+	Raw    []byte            `json:"raw,omitempty"`
+	Nested *testStreamEntry  `json:"nested,omitempty"`
+
+	unexported int
+}
+
+type testStreamSection struct {
+	Name     string `json:"name"`
+	Ignored  string `json:"-"`
+	NoTag    uint64
+	Entries  map[testStreamKey]*testStreamEntry `json:"entries"`
+	ByIndex  map[uint16]testStreamEntry         `json:"by_index"`
+	List     []*testStreamEntry                 `json:"list"`
+	Matrix   [][]int                            `json:"matrix"`
+	Params   map[string]map[string]json.Number  `json:"params"`
+	Optional *testStreamEntry                   `json:"optional"`
+	Raw      json.RawMessage                    `json:"raw"`
+	Any      interface{}                        `json:"any"`
+	Empty    map[testStreamKey]*testStreamEntry `json:"empty"`
+	Prefill  []int                              `json:"prefill"`
+}
+
+func TestDecodeStream(t *testing.T) {
+	require := require.New(t)
+
+	require.True(isStreamable(reflect.TypeOf(testStreamSection{})))
+	require.False(isStreamable(reflect.TypeOf(quantity.Quantity{})), "custom unmarshalling should not be streamed")
+	require.False(isStreamable(reflect.TypeOf([]byte{})), "byte slices should not be streamed")
+
+	for _, raw := range []string{
+		`{}`,
+		`null`,
+		`{"name": "test", "Ignored": "x", "notag": 42, "unknown": {"a": [1, {"b": null}]}}`,
+		`{"entries": {"a": {"amount": "10", "tags": ["x", "y"], "raw": "AQID", "nested": {"amount": "1"}}, "b": null}}`,
+		`{"by_index": {"1": {"amount": "1"}, "65535": {"amount": "2"}}}`,
+		`{"list": [{"amount": "1"}, null, {"amount": "3", "tags": []}], "matrix": [[1, 2], [], null]}`,
+		`{"params": {"a": {"x": 1.5}, "b": null}, "optional": null, "raw": {"x": [1]}, "any": [1, "a"]}`,
+		`{"empty": {}, "prefill": null, "NAME": "case-insensitive"}`,
+	} {
+		expected := testStreamSection{Prefill: []int{1}}
+		err := json.Unmarshal([]byte(raw), &expected)
+		require.NoError(err, "json.Unmarshal(%s)", raw)
+
+		actual := testStreamSection{Prefill: []int{1}}
+		dec := json.NewDecoder(strings.NewReader(raw))
+		err = decodeStream(dec, reflect.ValueOf(&actual).Elem())
+		require.NoError(err, "decodeStream(%s)", raw)
+		require.Equal(expected, actual, "decodeStream(%s) should match encoding/json", raw)
+	}
+
+	for _, raw := range []string{
+		`[]`,
+		`{"entries": []}`,
+		`{"entries": {"": {}}}`,
+		`{"by_index": {"65536": {}}}`,
+		`{"list": [{"amount": "-1"}]}`,
+		`{"matrix": [[1, "a"]]}`,
+		`{"list": [`,
+	} {
+		var actual testStreamSection
+		dec := json.NewDecoder(strings.NewReader(raw))
+		err := decodeStream(dec, reflect.ValueOf(&actual).Elem())
+		require.Error(err, "decodeStream(%s) should fail", raw)
+	}
+}
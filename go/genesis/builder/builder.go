@@ -0,0 +1,487 @@
+// Package builder implements a genesis document builder which composes a genesis document from
+// module-level inputs and validates the inputs against each other.
+package builder
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	keymanager "github.com/oasisprotocol/oasis-core/go/keymanager/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// ValidationError is the error returned when the composed genesis document fails validation.
+type ValidationError struct {
+	// Errors are all the validation errors.
+	Errors []error
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("genesis/builder: validation failed: %s", strings.Join(msgs, "; "))
+}
+
+type signedNode struct {
+	signed *node.MultiSignedNode
+	node   *node.Node
+}
+
+// Builder composes a genesis document from module-level inputs.
+//
+// The composed document only depends on the inputs and not on the order in which they were
+// added, so the same inputs always result in the same document.
+type Builder struct {
+	doc genesis.Document
+
+	entities      map[signature.PublicKey]*entity.SignedEntity
+	runtimes      map[common.Namespace]*registry.Runtime
+	nodes         map[signature.PublicKey]*signedNode
+	runtimeStates map[common.Namespace]*roothash.GenesisRuntimeState
+	kmStatuses    map[common.Namespace]*keymanager.Status
+}
+
+// SetHeight sets the initial block height.
+func (b *Builder) SetHeight(height int64) {
+	b.doc.Height = height
+}
+
+// SetHaltEpoch sets the epoch at which the network halts.
+func (b *Builder) SetHaltEpoch(epoch beacon.EpochTime) {
+	b.doc.HaltEpoch = epoch
+}
+
+// SetExtraData sets the extra data of the genesis document.
+func (b *Builder) SetExtraData(extraData map[string][]byte) {
+	b.doc.ExtraData = extraData
+}
+
+// SetConsensus sets the consensus backend and its parameters.
+func (b *Builder) SetConsensus(st consensusGenesis.Genesis) {
+	b.doc.Consensus = st
+}
+
+// SetBeacon sets the beacon genesis state.
+func (b *Builder) SetBeacon(st beacon.Genesis) {
+	b.doc.Beacon = st
+}
+
+// SetScheduler sets the scheduler genesis state.
+func (b *Builder) SetScheduler(st scheduler.Genesis) {
+	b.doc.Scheduler = st
+}
+
+// SetGovernance sets the governance genesis state.
+func (b *Builder) SetGovernance(st governance.Genesis) {
+	b.doc.Governance = st
+}
+
+// SetRegistryParameters sets the registry consensus parameters.
+func (b *Builder) SetRegistryParameters(params registry.ConsensusParameters) {
+	b.doc.Registry.Parameters = params
+}
+
+// SetRootHashParameters sets the roothash consensus parameters.
+func (b *Builder) SetRootHashParameters(params roothash.ConsensusParameters) {
+	b.doc.RootHash.Parameters = params
+}
+
+// SetStaking sets the staking genesis state.
+//
+// The total supply of the given state is ignored and is instead derived from the balances of all
+// accounts and pools when the document is built.
+func (b *Builder) SetStaking(st staking.Genesis) {
+	b.doc.Staking = st
+}
+
+// AddEntity adds a signed entity registration.
+func (b *Builder) AddEntity(sigEnt *entity.SignedEntity) error {
+	var ent entity.Entity
+	if err := sigEnt.Open(registry.RegisterGenesisEntitySignatureContext, &ent); err != nil {
+		return fmt.Errorf("genesis/builder: invalid entity registration: %w", err)
+	}
+	if _, ok := b.entities[ent.ID]; ok {
+		return fmt.Errorf("genesis/builder: duplicate entity %s", ent.ID)
+	}
+
+	b.entities[ent.ID] = sigEnt
+	return nil
+}
+
+// AddRuntime adds a runtime descriptor.
+func (b *Builder) AddRuntime(rt *registry.Runtime) error {
+	if _, ok := b.runtimes[rt.ID]; ok {
+		return fmt.Errorf("genesis/builder: duplicate runtime %s", rt.ID)
+	}
+
+	b.runtimes[rt.ID] = rt
+	return nil
+}
+
+// AddNode adds a signed node registration.
+func (b *Builder) AddNode(sigNode *node.MultiSignedNode) error {
+	var n node.Node
+	if err := sigNode.Open(registry.RegisterGenesisNodeSignatureContext, &n); err != nil {
+		return fmt.Errorf("genesis/builder: invalid node registration: %w", err)
+	}
+	if _, ok := b.nodes[n.ID]; ok {
+		return fmt.Errorf("genesis/builder: duplicate node %s", n.ID)
+	}
+
+	b.nodes[n.ID] = &signedNode{
+		signed: sigNode,
+		node:   &n,
+	}
+	return nil
+}
+
+// AddRuntimeState adds the initial roothash state of the given runtime.
+func (b *Builder) AddRuntimeState(id common.Namespace, st *roothash.GenesisRuntimeState) error {
+	if _, ok := b.runtimeStates[id]; ok {
+		return fmt.Errorf("genesis/builder: duplicate runtime state for runtime %s", id)
+	}
+
+	b.runtimeStates[id] = st
+	return nil
+}
+
+// AddKeyManagerStatus adds the initial status of a key manager.
+func (b *Builder) AddKeyManagerStatus(status *keymanager.Status) error {
+	if _, ok := b.kmStatuses[status.ID]; ok {
+		return fmt.Errorf("genesis/builder: duplicate key manager status for runtime %s", status.ID)
+	}
+
+	b.kmStatuses[status.ID] = status
+	return nil
+}
+
+// Build composes the genesis document and validates it.
+//
+// In addition to the genesis document sanity checks, the inputs are validated against each other
+// (e.g., node registrations against the stake of their entities). In case validation fails, a
+// *ValidationError listing all violations is returned.
+func (b *Builder) Build() (*genesis.Document, error) {
+	doc := b.compose()
+
+	if errs := validate(doc, b); len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
+	}
+	// Only run the sanity checks once the cross-module validation has passed as some of the
+	// sanity checks assume consistent inputs.
+	if errs := doc.SanityCheckDeep(); len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
+	}
+
+	return doc, nil
+}
+
+func (b *Builder) compose() *genesis.Document {
+	doc := b.doc
+
+	doc.Registry.Entities = nil
+	for _, id := range b.entityIDs() {
+		doc.Registry.Entities = append(doc.Registry.Entities, b.entities[id])
+	}
+	doc.Registry.Nodes = nil
+	for _, id := range b.nodeIDs() {
+		doc.Registry.Nodes = append(doc.Registry.Nodes, b.nodes[id].signed)
+	}
+	doc.Registry.Runtimes = nil
+	for _, id := range b.runtimeIDs() {
+		doc.Registry.Runtimes = append(doc.Registry.Runtimes, b.runtimes[id])
+	}
+
+	doc.RootHash.RuntimeStates = nil
+	if len(b.runtimeStates) > 0 {
+		doc.RootHash.RuntimeStates = make(map[common.Namespace]*roothash.GenesisRuntimeState)
+		for id, st := range b.runtimeStates {
+			doc.RootHash.RuntimeStates[id] = st
+		}
+	}
+
+	doc.KeyManager.Statuses = nil
+	for _, id := range b.kmStatusIDs() {
+		doc.KeyManager.Statuses = append(doc.KeyManager.Statuses, b.kmStatuses[id])
+	}
+
+	// Derive the total supply from all balances.
+	var total quantity.Quantity
+	for _, acct := range doc.Staking.Ledger {
+		_ = total.Add(&acct.General.Balance)
+		_ = total.Add(&acct.Escrow.Active.Balance)
+		_ = total.Add(&acct.Escrow.Debonding.Balance)
+	}
+	_ = total.Add(&doc.Staking.CommonPool)
+	_ = total.Add(&doc.Staking.LastBlockFees)
+	_ = total.Add(&doc.Staking.GovernanceDeposits)
+	doc.Staking.TotalSupply = total
+
+	return &doc
+}
+
+// validate validates the builder inputs against each other.
+func validate(doc *genesis.Document, b *Builder) []error {
+	var errs []error
+	errs = append(errs, validateReferences(b)...)
+	errs = append(errs, validateCommissionSchedules(doc)...)
+	if len(errs) > 0 {
+		// The remaining checks require consistent references.
+		return errs
+	}
+	errs = append(errs, validateStake(doc, b)...)
+	errs = append(errs, validateValidators(doc, b)...)
+	return errs
+}
+
+// validateReferences makes sure that all referenced entities, runtimes and nodes exist.
+func validateReferences(b *Builder) []error {
+	var errs []error
+	for _, id := range b.runtimeIDs() {
+		rt := b.runtimes[id]
+		if rt.GovernanceModel == registry.GovernanceEntity {
+			if _, ok := b.entities[rt.EntityID]; !ok {
+				errs = append(errs, fmt.Errorf("runtime %s: owner entity %s does not exist", rt.ID, rt.EntityID))
+			}
+		}
+		if rt.KeyManager != nil {
+			km, ok := b.runtimes[*rt.KeyManager]
+			if !ok || km.Kind != registry.KindKeyManager {
+				errs = append(errs, fmt.Errorf("runtime %s: key manager runtime %s does not exist", rt.ID, rt.KeyManager))
+			}
+		}
+	}
+	for _, id := range b.nodeIDs() {
+		n := b.nodes[id].node
+		if _, ok := b.entities[n.EntityID]; !ok {
+			errs = append(errs, fmt.Errorf("node %s: entity %s does not exist", n.ID, n.EntityID))
+		}
+		for _, rt := range n.Runtimes {
+			if _, ok := b.runtimes[rt.ID]; !ok {
+				errs = append(errs, fmt.Errorf("node %s: runtime %s does not exist", n.ID, rt.ID))
+			}
+		}
+	}
+	for _, id := range b.runtimeStateIDs() {
+		if _, ok := b.runtimes[id]; !ok {
+			errs = append(errs, fmt.Errorf("roothash: runtime state for runtime %s which does not exist", id))
+		}
+	}
+	for _, id := range b.kmStatusIDs() {
+		if rt, ok := b.runtimes[id]; !ok || rt.Kind != registry.KindKeyManager {
+			errs = append(errs, fmt.Errorf("keymanager: status for key manager runtime %s which does not exist", id))
+		}
+		for _, nodeID := range b.kmStatuses[id].Nodes {
+			if _, ok := b.nodes[nodeID]; !ok {
+				errs = append(errs, fmt.Errorf("keymanager: status for runtime %s references node %s which does not exist", id, nodeID))
+			}
+		}
+	}
+	return errs
+}
+
+// validateCommissionSchedules makes sure that the commission schedules of all accounts conform to
+// the commission schedule rules.
+func validateCommissionSchedules(doc *genesis.Document) []error {
+	var errs []error
+	rules := &doc.Staking.Parameters.CommissionScheduleRules
+	for _, addr := range sortedAddresses(doc.Staking.Ledger) {
+		cs := doc.Staking.Ledger[addr].Escrow.CommissionSchedule
+		if len(cs.Rates) == 0 && len(cs.Bounds) == 0 {
+			continue
+		}
+		if rules.RateChangeInterval == 0 {
+			errs = append(errs, fmt.Errorf("staking: account %s has a commission schedule, but the commission rate change interval is zero", addr))
+			continue
+		}
+		if err := cs.PruneAndValidateForGenesis(rules, doc.Beacon.Base); err != nil {
+			errs = append(errs, fmt.Errorf("staking: account %s commission schedule does not conform to the commission schedule rules: %w", addr, err))
+		}
+	}
+	return errs
+}
+
+// validateStake makes sure that all entities have enough stake in escrow to cover the stake
+// thresholds of the entity and of all its nodes and runtimes.
+func validateStake(doc *genesis.Document, b *Builder) []error {
+	if doc.Registry.Parameters.DebugBypassStake {
+		return nil
+	}
+
+	thresholds := doc.Staking.Parameters.Thresholds
+	escrows := make(map[signature.PublicKey]*staking.EscrowAccount)
+	for id := range b.entities {
+		escrow := &staking.EscrowAccount{}
+		if acct, ok := doc.Staking.Ledger[staking.NewAddress(id)]; ok {
+			escrow.Active.Balance = acct.Escrow.Active.Balance
+		}
+		escrow.StakeAccumulator.AddClaimUnchecked(registry.StakeClaimRegisterEntity, staking.GlobalStakeThresholds(staking.KindEntity))
+		escrows[id] = escrow
+	}
+	for _, sn := range b.nodes {
+		n := sn.node
+		rts := make([]*registry.Runtime, 0, len(n.Runtimes))
+		for _, rt := range n.Runtimes {
+			rts = append(rts, b.runtimes[rt.ID])
+		}
+		escrows[n.EntityID].StakeAccumulator.AddClaimUnchecked(registry.StakeClaimForNode(n.ID), registry.StakeThresholdsForNode(n, rts))
+	}
+	for _, rt := range b.runtimes {
+		if rt.GovernanceModel != registry.GovernanceEntity {
+			continue
+		}
+		if rt.Kind != registry.KindCompute && rt.Kind != registry.KindKeyManager {
+			// Invalid runtime kinds are reported by the sanity checks.
+			continue
+		}
+		escrows[rt.EntityID].StakeAccumulator.AddClaimUnchecked(registry.StakeClaimForRuntime(rt.ID), registry.StakeThresholdsForRuntime(rt))
+	}
+
+	var errs []error
+	for _, id := range b.entityIDs() {
+		escrow := escrows[id]
+		required, err := escrow.StakeAccumulator.TotalClaims(thresholds, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("staking: failed to compute stake claims of entity %s: %w", id, err))
+			continue
+		}
+		if escrow.Active.Balance.Cmp(required) < 0 {
+			errs = append(errs, fmt.Errorf("staking: entity %s has insufficient stake in escrow for itself and its %d node(s) and runtime(s) (required: %s available: %s)",
+				id,
+				len(escrow.StakeAccumulator.Claims)-1,
+				required,
+				escrow.Active.Balance,
+			))
+		}
+	}
+	return errs
+}
+
+// validateValidators makes sure that there are enough validator nodes for the scheduler to elect
+// the minimum number of validators.
+func validateValidators(doc *genesis.Document, b *Builder) []error {
+	params := &doc.Scheduler.Parameters
+	perEntity := make(map[signature.PublicKey]int)
+	for _, sn := range b.nodes {
+		if sn.node.HasRoles(node.RoleValidator) {
+			perEntity[sn.node.EntityID]++
+		}
+	}
+
+	var eligible int
+	for _, count := range perEntity {
+		if params.MaxValidatorsPerEntity > 0 && count > params.MaxValidatorsPerEntity {
+			count = params.MaxValidatorsPerEntity
+		}
+		eligible += count
+	}
+	if eligible < params.MinValidators {
+		return []error{fmt.Errorf("scheduler: not enough eligible validator nodes (required: %d available: %d)",
+			params.MinValidators,
+			eligible,
+		)}
+	}
+	return nil
+}
+
+func (b *Builder) entityIDs() []signature.PublicKey {
+	ids := make([]signature.PublicKey, 0, len(b.entities))
+	for id := range b.entities {
+		ids = append(ids, id)
+	}
+	return sortPublicKeys(ids)
+}
+
+func (b *Builder) nodeIDs() []signature.PublicKey {
+	ids := make([]signature.PublicKey, 0, len(b.nodes))
+	for id := range b.nodes {
+		ids = append(ids, id)
+	}
+	return sortPublicKeys(ids)
+}
+
+func (b *Builder) runtimeIDs() []common.Namespace {
+	ids := make([]common.Namespace, 0, len(b.runtimes))
+	for id := range b.runtimes {
+		ids = append(ids, id)
+	}
+	return sortNamespaces(ids)
+}
+
+func (b *Builder) runtimeStateIDs() []common.Namespace {
+	ids := make([]common.Namespace, 0, len(b.runtimeStates))
+	for id := range b.runtimeStates {
+		ids = append(ids, id)
+	}
+	return sortNamespaces(ids)
+}
+
+func (b *Builder) kmStatusIDs() []common.Namespace {
+	ids := make([]common.Namespace, 0, len(b.kmStatuses))
+	for id := range b.kmStatuses {
+		ids = append(ids, id)
+	}
+	return sortNamespaces(ids)
+}
+
+func sortPublicKeys(ids []signature.PublicKey) []signature.PublicKey {
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i][:], ids[j][:]) < 0
+	})
+	return ids
+}
+
+func sortNamespaces(ids []common.Namespace) []common.Namespace {
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i][:], ids[j][:]) < 0
+	})
+	return ids
+}
+
+func sortedAddresses(m map[staking.Address]*staking.Account) []staking.Address {
+	addrs := make([]staking.Address, 0, len(m))
+	for addr := range m {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i][:], addrs[j][:]) < 0
+	})
+	return addrs
+}
+
+// New creates a new genesis document builder for the given chain ID and genesis time.
+//
+// The initial height defaults to 1 and the halt epoch defaults to never halting.
+func New(chainID string, genesisTime time.Time) *Builder {
+	return &Builder{
+		doc: genesis.Document{
+			Height:    1,
+			ChainID:   chainID,
+			Time:      genesisTime,
+			HaltEpoch: beacon.EpochTime(math.MaxUint64),
+		},
+		entities:      make(map[signature.PublicKey]*entity.SignedEntity),
+		runtimes:      make(map[common.Namespace]*registry.Runtime),
+		nodes:         make(map[signature.PublicKey]*signedNode),
+		runtimeStates: make(map[common.Namespace]*roothash.GenesisRuntimeState),
+		kmStatuses:    make(map[common.Namespace]*keymanager.Status),
+	}
+}
@@ -0,0 +1,242 @@
+package builder
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
+	tendermint "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	genesisTestHelpers "github.com/oasisprotocol/oasis-core/go/genesis/tests"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	stakingTests "github.com/oasisprotocol/oasis-core/go/staking/tests"
+)
+
+type testValidator struct {
+	signedEntity *entity.SignedEntity
+	signedNode   *node.MultiSignedNode
+	address      staking.Address
+}
+
+func newTestValidator(t *testing.T, name string) *testValidator {
+	require := require.New(t)
+
+	entitySigner := memorySigner.NewTestSigner(name + " entity")
+	nodeSigner := memorySigner.NewTestSigner(name + " node")
+	consensusSigner := memorySigner.NewTestSigner(name + " node consensus")
+	p2pSigner := memorySigner.NewTestSigner(name + " node P2P")
+	tlsSigner := memorySigner.NewTestSigner(name + " node TLS")
+
+	var consensusAddress node.ConsensusAddress
+	require.NoError(consensusAddress.UnmarshalText([]byte("AAAAAAAAAAAAAAAAAAAABBBBBBBBBBBBBBBBBBBBBBA=@127.0.0.1:1234")))
+	var address node.Address
+	require.NoError(address.UnmarshalText([]byte("127.0.0.1:1234")))
+
+	ent := &entity.Entity{
+		Versioned: cbor.NewVersioned(entity.LatestDescriptorVersion),
+		ID:        entitySigner.Public(),
+		Nodes:     []signature.PublicKey{nodeSigner.Public()},
+	}
+	signedEntity, err := entity.SignEntity(entitySigner, registry.RegisterGenesisEntitySignatureContext, ent)
+	require.NoError(err, "SignEntity")
+
+	n := &node.Node{
+		Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+		ID:         nodeSigner.Public(),
+		EntityID:   ent.ID,
+		Expiration: 10,
+		Roles:      node.RoleValidator,
+		TLS: node.TLSInfo{
+			PubKey: tlsSigner.Public(),
+			Addresses: []node.TLSAddress{
+				{PubKey: tlsSigner.Public(), Address: address},
+			},
+		},
+		P2P: node.P2PInfo{
+			ID:        p2pSigner.Public(),
+			Addresses: []node.Address{address},
+		},
+		Consensus: node.ConsensusInfo{
+			ID:        consensusSigner.Public(),
+			Addresses: []node.ConsensusAddress{consensusAddress},
+		},
+	}
+	signedNode, err := node.MultiSignNode(
+		[]signature.Signer{nodeSigner, consensusSigner, p2pSigner, tlsSigner},
+		registry.RegisterGenesisNodeSignatureContext,
+		n,
+	)
+	require.NoError(err, "MultiSignNode")
+
+	return &testValidator{
+		signedEntity: signedEntity,
+		signedNode:   signedNode,
+		address:      staking.NewAddress(ent.ID),
+	}
+}
+
+func newTestBuilder(validators []*testValidator, escrow uint64) *Builder {
+	b := New(genesisTestHelpers.TestChainID, time.Unix(1574858284, 0))
+	b.SetRegistryParameters(registry.ConsensusParameters{
+		DebugAllowUnroutableAddresses: true,
+		EnableRuntimeGovernanceModels: map[registry.RuntimeGovernanceModel]bool{
+			registry.GovernanceEntity: true,
+		},
+	})
+	b.SetBeacon(beacon.Genesis{
+		Parameters: beacon.ConsensusParameters{
+			Backend:            beacon.BackendInsecure,
+			DebugMockBackend:   true,
+			InsecureParameters: &beacon.InsecureParameters{},
+		},
+	})
+	b.SetGovernance(governance.Genesis{
+		Parameters: governance.ConsensusParameters{
+			Quorum:                    90,
+			Threshold:                 90,
+			VotingPeriod:              100,
+			UpgradeCancelMinEpochDiff: 200,
+			UpgradeMinEpochDiff:       200,
+		},
+	})
+	b.SetScheduler(scheduler.Genesis{
+		Parameters: scheduler.ConsensusParameters{
+			MinValidators:          1,
+			MaxValidators:          100,
+			MaxValidatorsPerEntity: 1,
+		},
+	})
+	b.SetConsensus(consensus.Genesis{
+		Backend: tendermint.BackendName,
+		Parameters: consensus.Parameters{
+			TimeoutCommit:     1 * time.Millisecond,
+			SkipTimeoutCommit: true,
+		},
+	})
+
+	st := staking.Genesis{
+		Parameters:  stakingTests.GenesisState().Parameters,
+		TokenSymbol: genesisTestHelpers.TestStakingTokenSymbol,
+		Ledger:      make(map[staking.Address]*staking.Account),
+		Delegations: make(map[staking.Address]map[staking.Address]*staking.Delegation),
+	}
+	for _, v := range validators {
+		st.Ledger[v.address] = &staking.Account{
+			General: staking.GeneralAccount{
+				Balance: *quantity.NewFromUint64(1000),
+			},
+			Escrow: staking.EscrowAccount{
+				Active: staking.SharePool{
+					Balance:     *quantity.NewFromUint64(escrow),
+					TotalShares: *quantity.NewFromUint64(escrow),
+				},
+			},
+		}
+		st.Delegations[v.address] = map[staking.Address]*staking.Delegation{
+			v.address: {Shares: *quantity.NewFromUint64(escrow)},
+		}
+	}
+	b.SetStaking(st)
+
+	return b
+}
+
+func addTestValidators(t *testing.T, b *Builder, validators []*testValidator) {
+	for _, v := range validators {
+		require.NoError(t, b.AddEntity(v.signedEntity), "AddEntity")
+		require.NoError(t, b.AddNode(v.signedNode), "AddNode")
+	}
+}
+
+func requireValidationError(t *testing.T, err error, contains string) {
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr), "error should be a validation error")
+	require.NotEmpty(t, verr.Errors, "validation errors should be reported")
+	require.Contains(t, verr.Error(), contains)
+}
+
+func TestBuilder(t *testing.T) {
+	viper.Set(cmdFlags.CfgDebugDontBlameOasis, true)
+	require := require.New(t)
+
+	validators := []*testValidator{
+		newTestValidator(t, "builder test validator 1"),
+		newTestValidator(t, "builder test validator 2"),
+	}
+
+	// Entity (1) and validator node (2) thresholds should be covered.
+	b := newTestBuilder(validators, 3)
+	addTestValidators(t, b, validators)
+	doc, err := b.Build()
+	require.NoError(err, "Build")
+	require.Len(doc.Registry.Entities, 2)
+	require.Len(doc.Registry.Nodes, 2)
+	require.EqualValues(*quantity.NewFromUint64(2 * (1000 + 3)), doc.Staking.TotalSupply, "total supply should be derived")
+	require.NoError(doc.SanityCheck(), "built document should pass sanity checks")
+
+	// The document should not depend on the order of inputs.
+	b = newTestBuilder(validators, 3)
+	addTestValidators(t, b, []*testValidator{validators[1], validators[0]})
+	doc2, err := b.Build()
+	require.NoError(err, "Build")
+	raw, err := doc.CanonicalJSON()
+	require.NoError(err, "CanonicalJSON")
+	raw2, err := doc2.CanonicalJSON()
+	require.NoError(err, "CanonicalJSON")
+	require.Equal(raw, raw2, "built documents should be identical")
+
+	// Duplicate inputs should be rejected.
+	require.Error(b.AddEntity(validators[0].signedEntity), "duplicate entity should be rejected")
+	require.Error(b.AddNode(validators[0].signedNode), "duplicate node should be rejected")
+
+	// Insufficient stake should be reported for each entity.
+	b = newTestBuilder(validators, 2)
+	addTestValidators(t, b, validators)
+	_, err = b.Build()
+	requireValidationError(t, err, "insufficient stake")
+	require.Len(err.(*ValidationError).Errors, 2, "all entities should be reported")
+
+	// Nodes of missing entities should be reported.
+	b = newTestBuilder(validators, 3)
+	require.NoError(b.AddNode(validators[0].signedNode), "AddNode")
+	_, err = b.Build()
+	requireValidationError(t, err, "does not exist")
+
+	// Not enough validators should be reported.
+	b = newTestBuilder(validators, 3)
+	b.doc.Scheduler.Parameters.MinValidators = 3
+	addTestValidators(t, b, validators)
+	_, err = b.Build()
+	requireValidationError(t, err, "not enough eligible validator nodes")
+
+	// Commission schedules should conform to the commission schedule rules.
+	b = newTestBuilder(validators, 3)
+	b.doc.Staking.Ledger[validators[0].address].Escrow.CommissionSchedule = staking.CommissionSchedule{
+		Rates: []staking.CommissionRateStep{{Start: 10}},
+	}
+	addTestValidators(t, b, validators)
+	_, err = b.Build()
+	requireValidationError(t, err, "commission rate change interval is zero")
+
+	b.doc.Staking.Parameters.CommissionScheduleRules = staking.CommissionScheduleRules{
+		RateChangeInterval: 20,
+		MaxRateSteps:       1,
+		MaxBoundSteps:      1,
+	}
+	_, err = b.Build()
+	requireValidationError(t, err, "not aligned with commission rate change interval")
+}
@@ -2,10 +2,9 @@
 package file
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/genesis/api"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
@@ -23,31 +22,45 @@ func (p *fileProvider) GetGenesisDocument() (*api.Document, error) {
 
 // DefaultFileProvider creates a new local file genesis provider for the genesis
 // specified by the genesis flag.
+//
+// In case the expected genesis hash flag is set, the genesis document hash is
+// verified against it.
 func DefaultFileProvider() (api.Provider, error) {
 	filename := flags.GenesisFile()
-	return NewFileProvider(filename)
+	provider, err := NewFileProvider(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if expected := flags.GenesisExpectedHash(); expected != "" {
+		var h hash.Hash
+		if err = h.UnmarshalHex(expected); err != nil {
+			return nil, fmt.Errorf("genesis: malformed expected genesis hash: %w", err)
+		}
+		doc, _ := provider.GetGenesisDocument()
+		if err = doc.VerifyHash(h); err != nil {
+			return nil, err
+		}
+	}
+
+	return provider, nil
 }
 
 // NewFileProvider creates a new local file genesis provider.
 func NewFileProvider(filename string) (api.Provider, error) {
 	logger := logging.GetLogger("genesis/file").With("filename", filename)
 
-	raw, err := ioutil.ReadFile(filename)
+	doc, err := api.ReadDocumentFile(filename)
 	if err != nil {
-		logger.Warn("failed to open genesis document",
+		logger.Warn("failed to load genesis document",
 			"err", err,
 		)
 		return nil, err
 	}
 
-	var doc api.Document
-	if err = json.Unmarshal(raw, &doc); err != nil {
-		return nil, fmt.Errorf("genesis: malformed genesis file: %w", err)
-	}
-
 	if err = doc.SanityCheck(); err != nil {
 		return nil, fmt.Errorf("genesis: bad genesis file: %w", err)
 	}
 
-	return &fileProvider{document: &doc}, nil
+	return &fileProvider{document: doc}, nil
 }
@@ -1,9 +1,13 @@
 package genesis
 
 import (
+	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -142,6 +146,30 @@ func TestGenesisChainContext(t *testing.T) {
 	require.Equal(t, "7b6c3cd2cfb52d3bfa7a68ca0dfeb3cf43d4c5b4b3fcc0026436c110db125eba", stableDoc.ChainContext())
 }
 
+func TestGenesisVerifyHash(t *testing.T) {
+	require := require.New(t)
+
+	d := testDoc()
+	h := d.Hash()
+	require.NoError(d.VerifyHash(h), "VerifyHash should succeed on matching hash")
+
+	// Provenance metadata is part of the document hash.
+	d.Provenance = &genesis.Provenance{
+		SourceChainID: "oasis-test-chain",
+		ExportHeight:  1234,
+		ToolVersion:   "1.0.0",
+	}
+	err := d.VerifyHash(h)
+	require.ErrorIs(err, genesis.ErrHashMismatch, "VerifyHash should fail on mismatching hash")
+	h = d.Hash()
+	require.NoError(d.VerifyHash(h), "VerifyHash should succeed on matching hash")
+
+	// The tool version is not part of the document hash.
+	d.Provenance.ToolVersion = "2.0.0"
+	require.NoError(d.VerifyHash(h), "VerifyHash should ignore the provenance tool version")
+	require.Equal("2.0.0", d.Provenance.ToolVersion, "Hash should not modify the document")
+}
+
 func TestGenesisSanityCheck(t *testing.T) {
 	viper.Set(cmdFlags.CfgDebugDontBlameOasis, true)
 	require := require.New(t)
@@ -296,6 +324,14 @@ func TestGenesisSanityCheck(t *testing.T) {
 	d.Height = 0
 	require.Error(d.SanityCheck(), "height < 1 should be invalid")
 
+	d = testDoc()
+	d.Provenance = &genesis.Provenance{SourceChainID: "oasis-test-chain", ExportHeight: 1234}
+	require.NoError(d.SanityCheck(), "valid provenance should pass")
+	d.Provenance.SourceChainID = ""
+	require.Error(d.SanityCheck(), "provenance with empty source chain ID should be invalid")
+	d.Provenance = &genesis.Provenance{SourceChainID: "oasis-test-chain"}
+	require.Error(d.SanityCheck(), "provenance with export height < 1 should be invalid")
+
 	d = testDoc()
 	d.ChainID = "   \t"
 	require.Error(d.SanityCheck(), "empty chain ID should be invalid")
@@ -1004,3 +1040,73 @@ func TestGenesisSanityCheck(t *testing.T) {
 	})
 	require.Error(d.SanityCheck(), "pending upgrades not UpgradeMinEpochDiff apart")
 }
+
+func TestGenesisReadDocument(t *testing.T) {
+	viper.Set(cmdFlags.CfgDebugDontBlameOasis, true)
+	require := require.New(t)
+
+	doc := testDoc()
+	canonJSON, err := doc.CanonicalJSON()
+	require.NoError(err, "CanonicalJSON")
+
+	parsed, err := genesis.ReadDocument(bytes.NewReader(canonJSON))
+	require.NoError(err, "ReadDocument")
+	var expected genesis.Document
+	err = json.Unmarshal(canonJSON, &expected)
+	require.NoError(err, "json.Unmarshal")
+	require.Equal(&expected, parsed, "stream-parsed document should match encoding/json")
+	require.Equal(doc.ChainContext(), parsed.ChainContext(), "stream-parsed document should match")
+	require.NoError(parsed.SanityCheck(), "SanityCheck")
+	require.Empty(parsed.SanityCheckDeep(), "SanityCheckDeep")
+
+	for _, tc := range []struct {
+		raw string
+		msg string
+	}{
+		{`[]`, "non-object document"},
+		{`{"height": 1, "height": 2}`, "duplicate section"},
+		{`{"height": "one"}`, "malformed section"},
+		{`{"height": 1} {}`, "trailing data"},
+		{`{"height": 1`, "truncated document"},
+	} {
+		_, err = genesis.ReadDocument(strings.NewReader(tc.raw))
+		require.Error(err, tc.msg)
+	}
+
+	_, err = genesis.ReadDocument(strings.NewReader(`{"height": 1, "unknown": {"a": [1, 2]}}`))
+	require.Error(err, "unknown sections should be rejected")
+
+	// Writing and reading back a document should preserve its hash.
+	doc.Provenance = &genesis.Provenance{
+		SourceChainID: "oasis-test-chain",
+		ExportHeight:  1234,
+		ToolVersion:   "1.0.0",
+	}
+	filename := filepath.Join(t.TempDir(), "genesis.json")
+	err = doc.WriteFileJSON(filename)
+	require.NoError(err, "WriteFileJSON")
+	parsed, err = genesis.ReadDocumentFile(filename)
+	require.NoError(err, "ReadDocumentFile")
+	require.Equal(doc.Provenance, parsed.Provenance, "provenance should be preserved")
+	require.Equal(doc.Hash(), parsed.Hash(), "hash should be preserved")
+}
+
+func TestGenesisSanityCheckDeep(t *testing.T) {
+	viper.Set(cmdFlags.CfgDebugDontBlameOasis, true)
+	require := require.New(t)
+
+	doc := testDoc()
+	doc.Height = 0
+	doc.ChainID = ""
+	for _, acct := range doc.Staking.Ledger {
+		acct.General.Balance = quantity.Quantity{}
+		acct.General.Allowances = map[staking.Address]quantity.Quantity{
+			staking.CommonPoolAddress: *quantity.NewFromUint64(1),
+		}
+	}
+
+	errs := doc.SanityCheckDeep()
+	// Height, chain ID and per-account violations should all be reported.
+	require.Len(errs, 2+len(doc.Staking.Ledger), "all violations should be reported")
+	require.Error(doc.SanityCheck(), "SanityCheck should fail as well")
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
@@ -59,20 +60,39 @@ var (
 
 // ProposalContent is a consensus layer governance proposal content.
 type ProposalContent struct {
-	Upgrade       *UpgradeProposal       `json:"upgrade,omitempty"`
-	CancelUpgrade *CancelUpgradeProposal `json:"cancel_upgrade,omitempty"`
+	Upgrade         *UpgradeProposal         `json:"upgrade,omitempty"`
+	CancelUpgrade   *CancelUpgradeProposal   `json:"cancel_upgrade,omitempty"`
+	SetRoundTimeout *SetRoundTimeoutProposal `json:"set_round_timeout,omitempty"`
 }
 
 // ValidateBasic performs basic proposal content validity checks.
 func (p *ProposalContent) ValidateBasic() error {
+	var setFields uint8
+	if p.Upgrade != nil {
+		setFields++
+	}
+	if p.CancelUpgrade != nil {
+		setFields++
+	}
+	if p.SetRoundTimeout != nil {
+		setFields++
+	}
+
 	switch {
-	case p.Upgrade != nil && p.CancelUpgrade != nil:
+	case setFields > 1:
 		return fmt.Errorf("proposal content has multiple fields set")
 	case p.Upgrade != nil:
+		// Binary descriptions are only supported in upgrade descriptors submitted to the node,
+		// so the binary hash is never carried on-chain (see go/upgrade/fetcher).
+		if p.Upgrade.Binary != nil {
+			return fmt.Errorf("upgrade proposal must not describe the upgrade binary")
+		}
 		return p.Upgrade.ValidateBasic()
 	case p.CancelUpgrade != nil:
 		// No validation at this time.
 		return nil
+	case p.SetRoundTimeout != nil:
+		return p.SetRoundTimeout.ValidateBasic()
 	default:
 		return fmt.Errorf("proposal content has no fields set")
 	}
@@ -88,6 +108,8 @@ func (p *ProposalContent) Equals(other *ProposalContent) bool {
 		return p.CancelUpgrade.ProposalID == other.CancelUpgrade.ProposalID
 	case p.Upgrade != nil && other.Upgrade != nil:
 		return p.Upgrade.Descriptor.Equals(&other.Upgrade.Descriptor)
+	case p.SetRoundTimeout != nil && other.SetRoundTimeout != nil:
+		return *p.SetRoundTimeout == *other.SetRoundTimeout
 	default:
 		return false
 	}
@@ -97,12 +119,15 @@ func (p *ProposalContent) Equals(other *ProposalContent) bool {
 // given writer.
 func (p ProposalContent) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
 	switch {
-	case p.Upgrade != nil && p.CancelUpgrade == nil:
+	case p.Upgrade != nil && p.CancelUpgrade == nil && p.SetRoundTimeout == nil:
 		fmt.Fprintf(w, "%sUpgrade:\n", prefix)
 		p.Upgrade.PrettyPrint(ctx, prefix+"  ", w)
-	case p.CancelUpgrade != nil && p.Upgrade == nil:
+	case p.CancelUpgrade != nil && p.Upgrade == nil && p.SetRoundTimeout == nil:
 		fmt.Fprintf(w, "%sCancel Upgrade:\n", prefix)
 		p.CancelUpgrade.PrettyPrint(ctx, prefix+"  ", w)
+	case p.SetRoundTimeout != nil && p.Upgrade == nil && p.CancelUpgrade == nil:
+		fmt.Fprintf(w, "%sSet Round Timeout:\n", prefix)
+		p.SetRoundTimeout.PrettyPrint(ctx, prefix+"  ", w)
 	default:
 		fmt.Fprintf(w, "%s%s\n", prefix, ProposalContentInvalidText)
 	}
@@ -149,6 +174,36 @@ func (cu CancelUpgradeProposal) PrettyType() (interface{}, error) {
 	return cu, nil
 }
 
+// SetRoundTimeoutProposal is an executor round timeout override proposal.
+type SetRoundTimeoutProposal struct {
+	// RuntimeID is the identifier of the runtime.
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// RoundTimeout is the executor round timeout in consensus blocks which overrides the one in
+	// the runtime descriptor. Zero removes any existing override.
+	RoundTimeout int64 `json:"round_timeout"`
+}
+
+// ValidateBasic performs basic round timeout override proposal validity checks.
+func (srt *SetRoundTimeoutProposal) ValidateBasic() error {
+	if srt.RoundTimeout < 0 {
+		return fmt.Errorf("negative round timeout")
+	}
+	return nil
+}
+
+// PrettyPrint writes a pretty-printed representation of SetRoundTimeoutProposal
+// to the given writer.
+func (srt SetRoundTimeoutProposal) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
+	fmt.Fprintf(w, "%sRuntime ID:    %s\n", prefix, srt.RuntimeID)
+	fmt.Fprintf(w, "%sRound Timeout: %d\n", prefix, srt.RoundTimeout)
+}
+
+// PrettyType returns a representation of SetRoundTimeoutProposal that can be
+// used for pretty printing.
+func (srt SetRoundTimeoutProposal) PrettyType() (interface{}, error) {
+	return srt, nil
+}
+
 // ProposalVote is a vote for a proposal.
 type ProposalVote struct {
 	// ID is the unique identifier of a proposal.
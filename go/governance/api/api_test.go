@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
@@ -52,6 +53,24 @@ func TestValidateBasic(t *testing.T) {
 			},
 			shouldErr: false,
 		},
+		{
+			msg: "upgrade describing the upgrade binary should fail",
+			p: &ProposalContent{
+				Upgrade: &UpgradeProposal{
+					Descriptor: upgrade.Descriptor{
+						Versioned: cbor.NewVersioned(upgrade.LatestDescriptorVersion),
+						Handler:   "api_test_handler",
+						Target:    version.Versions,
+						Epoch:     42,
+						Binary: &upgrade.Binary{
+							Name: "oasis-node",
+							Hash: hash.NewFromBytes([]byte("oasis-node")),
+						},
+					},
+				},
+			},
+			shouldErr: true,
+		},
 		{
 			msg: "cancel upgrade proposal content should not fail",
 			p: &ProposalContent{
@@ -59,6 +78,28 @@ func TestValidateBasic(t *testing.T) {
 			},
 			shouldErr: false,
 		},
+		{
+			msg: "only one of CancelUpgrade/SetRoundTimeout fields should be set",
+			p: &ProposalContent{
+				CancelUpgrade:   &CancelUpgradeProposal{},
+				SetRoundTimeout: &SetRoundTimeoutProposal{},
+			},
+			shouldErr: true,
+		},
+		{
+			msg: "set round timeout with negative round timeout should fail",
+			p: &ProposalContent{
+				SetRoundTimeout: &SetRoundTimeoutProposal{RoundTimeout: -1},
+			},
+			shouldErr: true,
+		},
+		{
+			msg: "set round timeout proposal content should not fail",
+			p: &ProposalContent{
+				SetRoundTimeout: &SetRoundTimeoutProposal{RoundTimeout: 10},
+			},
+			shouldErr: false,
+		},
 	} {
 		err := tc.p.ValidateBasic()
 		if tc.shouldErr {
@@ -136,6 +177,26 @@ func TestProposalContentEquals(t *testing.T) {
 			},
 			equals: false,
 		},
+		{
+			msg: "set round timeout proposals should be equal",
+			p1: &ProposalContent{
+				SetRoundTimeout: &SetRoundTimeoutProposal{RoundTimeout: 10},
+			},
+			p2: &ProposalContent{
+				SetRoundTimeout: &SetRoundTimeoutProposal{RoundTimeout: 10},
+			},
+			equals: true,
+		},
+		{
+			msg: "set round timeout proposals should not be equal",
+			p1: &ProposalContent{
+				SetRoundTimeout: &SetRoundTimeoutProposal{RoundTimeout: 10},
+			},
+			p2: &ProposalContent{
+				SetRoundTimeout: &SetRoundTimeoutProposal{RoundTimeout: 0},
+			},
+			equals: false,
+		},
 	} {
 		require.Equal(t, tc.equals, tc.p1.Equals(tc.p2), tc.msg)
 	}
@@ -160,6 +221,12 @@ func TestProposalContentPrettyPrint(t *testing.T) {
 				CancelUpgrade: &CancelUpgradeProposal{ProposalID: 42},
 			},
 		},
+		{
+			expRegex: "^Set Round Timeout:",
+			p: &ProposalContent{
+				SetRoundTimeout: &SetRoundTimeoutProposal{RoundTimeout: 10},
+			},
+		},
 		{
 			expRegex: ProposalContentInvalidText,
 			p:        &ProposalContent{},
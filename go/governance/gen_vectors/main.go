@@ -129,7 +129,7 @@ func main() {
 						}),
 					} {
 						valid := valideCastVote(vote)
-						vectors = append(vectors, testvectors.MakeTestVector("SubmitProposal", tx, valid))
+						vectors = append(vectors, testvectors.MakeTestVector("CastVote", tx, valid))
 					}
 				}
 			}
@@ -8,6 +8,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/pcs"
 	"github.com/oasisprotocol/oasis-core/go/ias/api"
 	"github.com/oasisprotocol/oasis-core/go/ias/proxy/client"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
@@ -15,6 +16,7 @@ import (
 
 const (
 	CfgProxyAddress       = "ias.proxy.address"
+	CfgPCSURL             = "ias.pcs.url"
 	CfgDebugSkipVerify    = "ias.debug.skip_verify"
 	CfgAllowDebugEnclaves = "ias.debug.allow_debug_enclaves"
 )
@@ -44,8 +46,14 @@ func New(identity *identity.Identity) (api.Endpoint, error) {
 	)
 }
 
+// NewPCSClient creates a new client for retrieving DCAP attestation collateral.
+func NewPCSClient() pcs.Client {
+	return pcs.NewHTTPClient(viper.GetString(CfgPCSURL))
+}
+
 func init() {
 	Flags.StringSlice(CfgProxyAddress, []string{}, "IAS proxy address of the form ID@HOST:PORT")
+	Flags.String(CfgPCSURL, pcs.DefaultPCSBaseURL, "base URL of the PCS-compatible service used to retrieve DCAP attestation collateral")
 	Flags.Bool(CfgDebugSkipVerify, false, "skip IAS AVR signature verification (UNSAFE)")
 	Flags.Bool(CfgAllowDebugEnclaves, false, "allow enclaves compiled in debug mode (UNSAFE)")
 
@@ -0,0 +1,260 @@
+// Package pccs implements a caching proxy for Intel SGX DCAP attestation collateral.
+//
+// The proxy exposes a subset of the Intel Provisioning Certification Service (PCS) API so that
+// nodes can point at it instead of each node retrieving the collateral from Intel directly.
+package pccs
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/service"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/pcs"
+)
+
+const (
+	// CfgBind enables the attestation collateral caching proxy at the given address.
+	CfgBind = "ias.pccs.bind"
+	// CfgUpstreamURL is the base URL of the upstream PCS-compatible service.
+	CfgUpstreamURL = "ias.pccs.upstream_url"
+	// CfgRefreshInterval is the interval after which cached collateral is refreshed.
+	CfgRefreshInterval = "ias.pccs.refresh_interval"
+	// CfgOfflineGracePeriod is the period during which stale collateral is still served in case
+	// the upstream service is unavailable.
+	CfgOfflineGracePeriod = "ias.pccs.offline_grace_period"
+)
+
+// Flags has the flags used by the attestation collateral caching proxy.
+var Flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+type pccsService struct {
+	service.BaseBackgroundService
+
+	address string
+	client  pcs.Client
+
+	listener net.Listener
+	server   *http.Server
+
+	ctx   context.Context
+	errCh chan error
+}
+
+func (p *pccsService) writeResponse(w http.ResponseWriter, chainHeader string, certs []byte, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(chainHeader, url.QueryEscape(string(certs)))
+	_, _ = w.Write(data)
+}
+
+func (p *pccsService) handleTCBInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	fmspc, err := hex.DecodeString(r.URL.Query().Get(pcs.QueryFMSPC))
+	if err != nil || len(fmspc) == 0 {
+		http.Error(w, "malformed FMSPC", http.StatusBadRequest)
+		return
+	}
+
+	tcbInfo, certs, err := p.client.GetTCBInfo(r.Context(), fmspc)
+	if err != nil {
+		p.Logger.Error("failed to retrieve TCB info",
+			"err", err,
+			"fmspc", hex.EncodeToString(fmspc),
+		)
+		http.Error(w, "failed to retrieve TCB info", http.StatusBadGateway)
+		return
+	}
+	p.writeResponse(w, pcs.HeaderTCBInfoIssuerChain, certs, tcbInfo)
+}
+
+func (p *pccsService) handleQEIdentity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	qeIdentity, certs, err := p.client.GetQEIdentity(r.Context())
+	if err != nil {
+		p.Logger.Error("failed to retrieve QE identity",
+			"err", err,
+		)
+		http.Error(w, "failed to retrieve QE identity", http.StatusBadGateway)
+		return
+	}
+	p.writeResponse(w, pcs.HeaderQEIdentityIssuerChain, certs, qeIdentity)
+}
+
+func (p *pccsService) handlePCKCertificate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	query := &pcs.PCKCertificateQuery{
+		EncryptedPPID: q.Get(pcs.QueryEncryptedPPID),
+		CPUSVN:        q.Get(pcs.QueryCPUSVN),
+		PCESVN:        q.Get(pcs.QueryPCESVN),
+		PCEID:         q.Get(pcs.QueryPCEID),
+	}
+	if err := query.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cert, certs, err := p.client.GetPCKCertificate(r.Context(), query)
+	if err != nil {
+		p.Logger.Error("failed to retrieve PCK certificate",
+			"err", err,
+		)
+		http.Error(w, "failed to retrieve PCK certificate", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-pem-file")
+	w.Header().Set(pcs.HeaderPCKCertificateIssuerChain, url.QueryEscape(string(certs)))
+	w.Header().Set(pcs.HeaderTCBm, cert.TCBm)
+	w.Header().Set(pcs.HeaderFMSPC, cert.FMSPC)
+	_, _ = w.Write(cert.Certificate)
+}
+
+func (p *pccsService) handlePCKCRL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ca := r.URL.Query().Get(pcs.QueryCA)
+	if err := pcs.ValidatePCKCA(ca); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	crl, certs, err := p.client.GetPCKCRL(r.Context(), ca)
+	if err != nil {
+		p.Logger.Error("failed to retrieve PCK CRL",
+			"err", err,
+			"ca", ca,
+		)
+		http.Error(w, "failed to retrieve PCK CRL", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-pem-file")
+	w.Header().Set(pcs.HeaderPCKCRLIssuerChain, url.QueryEscape(string(certs)))
+	_, _ = w.Write(crl)
+}
+
+// handler returns the HTTP handler serving the attestation collateral.
+func (p *pccsService) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(pcs.PathTCBInfo, p.handleTCBInfo)
+	mux.HandleFunc(pcs.PathQEIdentity, p.handleQEIdentity)
+	mux.HandleFunc(pcs.PathPCKCertificate, p.handlePCKCertificate)
+	mux.HandleFunc(pcs.PathPCKCRL, p.handlePCKCRL)
+	return mux
+}
+
+func (p *pccsService) Start() error {
+	if p.address == "" {
+		return nil
+	}
+
+	p.Logger.Info("attestation collateral caching proxy is enabled",
+		"address", p.address,
+	)
+
+	listener, err := net.Listen("tcp", p.address)
+	if err != nil {
+		return err
+	}
+
+	p.listener = listener
+	p.server = &http.Server{Handler: p.handler()}
+
+	go func() {
+		if err := p.server.Serve(p.listener); err != nil {
+			p.BaseBackgroundService.Stop()
+			p.errCh <- err
+		}
+	}()
+
+	return nil
+}
+
+func (p *pccsService) Stop() {
+	if p.server != nil {
+		select {
+		case err := <-p.errCh:
+			if err != nil {
+				p.Logger.Error("attestation collateral caching proxy terminated uncleanly",
+					"err", err,
+				)
+			}
+		default:
+			_ = p.server.Shutdown(p.ctx)
+		}
+		p.server = nil
+	}
+}
+
+func (p *pccsService) Cleanup() {
+	if p.listener != nil {
+		_ = p.listener.Close()
+		p.listener = nil
+	}
+}
+
+// New constructs a new attestation collateral caching proxy service.
+func New(ctx context.Context) (service.BackgroundService, error) {
+	svc := &pccsService{
+		BaseBackgroundService: *service.NewBaseBackgroundService("ias/pccs"),
+		address:               viper.GetString(CfgBind),
+		ctx:                   ctx,
+		errCh:                 make(chan error),
+	}
+	if svc.address == "" {
+		return svc, nil
+	}
+
+	var err error
+	svc.client, err = pcs.NewCachingClient(
+		pcs.NewHTTPClient(viper.GetString(CfgUpstreamURL)),
+		pcs.CacheConfig{
+			RefreshInterval:    viper.GetDuration(CfgRefreshInterval),
+			OfflineGracePeriod: viper.GetDuration(CfgOfflineGracePeriod),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ias/pccs: failed to create collateral cache: %w", err)
+	}
+
+	return svc, nil
+}
+
+func init() {
+	Flags.String(CfgBind, "", "enable attestation collateral caching proxy at given address")
+	Flags.String(CfgUpstreamURL, pcs.DefaultPCSBaseURL, "base URL of the upstream PCS-compatible service")
+	Flags.Duration(CfgRefreshInterval, time.Hour, "attestation collateral refresh interval")
+	Flags.Duration(CfgOfflineGracePeriod, 24*time.Hour, "period during which stale attestation collateral is served while upstream is unavailable")
+
+	_ = viper.BindPFlags(Flags)
+}
@@ -0,0 +1,124 @@
+package pccs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/service"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/pcs"
+)
+
+type testClient struct {
+	certs []byte
+	crl   []byte
+}
+
+func (tc *testClient) GetTCBInfo(ctx context.Context, fmspc []byte) (*pcs.SignedTCBInfo, []byte, error) {
+	return &pcs.SignedTCBInfo{
+		TCBInfo:   json.RawMessage(fmt.Sprintf(`{"fmspc":"%x"}`, fmspc)),
+		Signature: "tcb",
+	}, tc.certs, nil
+}
+
+func (tc *testClient) GetQEIdentity(ctx context.Context) (*pcs.SignedQEIdentity, []byte, error) {
+	return &pcs.SignedQEIdentity{
+		EnclaveIdentity: json.RawMessage(`{"id":"QE"}`),
+		Signature:       "qe",
+	}, tc.certs, nil
+}
+
+func (tc *testClient) GetPCKCertificate(ctx context.Context, query *pcs.PCKCertificateQuery) (*pcs.PCKCertificate, []byte, error) {
+	return &pcs.PCKCertificate{
+		Certificate: tc.certs,
+		TCBm:        query.CPUSVN + query.PCESVN,
+		FMSPC:       "00906ea10000",
+	}, tc.certs, nil
+}
+
+func (tc *testClient) GetPCKCRL(ctx context.Context, ca string) ([]byte, []byte, error) {
+	return tc.crl, tc.certs, nil
+}
+
+func TestProxy(t *testing.T) {
+	require := require.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(err, "GenerateKey")
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(err, "CreateCertificate")
+	certs := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(err, "ParseCertificate")
+	crlDER, err := cert.CreateCRL(rand.Reader, key, nil, time.Now(), time.Now().Add(time.Hour)) // nolint: staticcheck
+	require.NoError(err, "CreateCRL")
+	crl := pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crlDER})
+
+	svc := &pccsService{
+		BaseBackgroundService: *service.NewBaseBackgroundService("ias/pccs"),
+		client:                &testClient{certs: certs, crl: crl},
+	}
+	srv := httptest.NewServer(svc.handler())
+	defer srv.Close()
+
+	ctx := context.Background()
+	client := pcs.NewHTTPClient(srv.URL)
+
+	tcbInfo, tcbCerts, err := client.GetTCBInfo(ctx, []byte{0x00, 0x90, 0x6e, 0xa1, 0x00, 0x00})
+	require.NoError(err, "GetTCBInfo")
+	require.JSONEq(`{"fmspc":"00906ea10000"}`, string(tcbInfo.TCBInfo))
+	require.Equal("tcb", tcbInfo.Signature)
+	require.Equal(certs, tcbCerts)
+
+	qeIdentity, qeCerts, err := client.GetQEIdentity(ctx)
+	require.NoError(err, "GetQEIdentity")
+	require.JSONEq(`{"id":"QE"}`, string(qeIdentity.EnclaveIdentity))
+	require.Equal("qe", qeIdentity.Signature)
+	require.Equal(certs, qeCerts)
+
+	_, _, err = client.GetTCBInfo(ctx, []byte{0x00})
+	require.Error(err, "GetTCBInfo should fail with malformed FMSPC")
+
+	query := &pcs.PCKCertificateQuery{
+		EncryptedPPID: strings.Repeat("aa", 384),
+		CPUSVN:        strings.Repeat("00", 16),
+		PCESVN:        "0b00",
+		PCEID:         "0000",
+	}
+	pckCert, pckCerts, err := client.GetPCKCertificate(ctx, query)
+	require.NoError(err, "GetPCKCertificate")
+	require.Equal(certs, pckCert.Certificate)
+	require.Equal(query.CPUSVN+query.PCESVN, pckCert.TCBm)
+	require.Equal("00906ea10000", pckCert.FMSPC)
+	require.Equal(certs, pckCerts)
+
+	pckCRL, crlCerts, err := client.GetPCKCRL(ctx, pcs.PCKCAPlatform)
+	require.NoError(err, "GetPCKCRL")
+	require.Equal(crl, pckCRL)
+	require.Equal(certs, crlCerts)
+
+	resp, err := http.Get(srv.URL + pcs.PathPCKCRL + "?ca=invalid")
+	require.NoError(err, "Get")
+	resp.Body.Close()
+	require.Equal(http.StatusBadRequest, resp.StatusCode, "invalid PCK CA should be rejected")
+}
@@ -24,6 +24,8 @@ const (
 	cfgLogFmt      = "log.format"
 	cfgLogLevel    = "log.level"
 	cfgLogNoStdout = "log.no_stdout"
+
+	cfgBundleDir = "bundle.dir"
 )
 
 var (
@@ -40,7 +42,14 @@ var (
 		Run:   doDumpFixture,
 	}
 
-	rootFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	deployBundleCmd = &cobra.Command{
+		Use:   "deploy-bundle",
+		Short: "provision configured fixture and write per-host deployment bundles",
+		RunE:  doDeployBundle,
+	}
+
+	rootFlags         = flag.NewFlagSet("", flag.ContinueOnError)
+	deployBundleFlags = flag.NewFlagSet("", flag.ContinueOnError)
 
 	cfgFile string
 )
@@ -166,6 +175,49 @@ func runRoot(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func doDeployBundle(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	bundleDir := viper.GetString(cfgBundleDir)
+	if bundleDir == "" {
+		return fmt.Errorf("deploy-bundle: bundle directory not configured")
+	}
+
+	// Initialize the base dir, logging, etc.
+	rootEnv, err := initRootEnv(cmd)
+	if err != nil {
+		return err
+	}
+	defer rootEnv.Cleanup()
+	logger := logging.GetLogger("net-runner")
+
+	childEnv, err := rootEnv.NewChild("net-runner", nil)
+	if err != nil {
+		return fmt.Errorf("deploy-bundle: failed to setup child environment: %w", err)
+	}
+
+	fixture, err := fixtures.GetFixture()
+	if err != nil {
+		return err
+	}
+	if fixture.Network.Deployment == nil {
+		return fmt.Errorf("deploy-bundle: fixture has no deployment configuration")
+	}
+
+	// Instantiate fixture.
+	logger.Debug("instantiating fixture")
+	net, err := fixture.Create(childEnv)
+	if err != nil {
+		return fmt.Errorf("deploy-bundle: failed to instantiate fixture: %w", err)
+	}
+
+	if err = net.WriteDeploymentBundles(bundleDir); err != nil {
+		return fmt.Errorf("deploy-bundle: failed to write deployment bundles: %w", err)
+	}
+
+	return nil
+}
+
 func doDumpFixture(cmd *cobra.Command, args []string) {
 	f, err := fixtures.GetFixture()
 	if err != nil {
@@ -198,8 +250,16 @@ func init() {
 	rootCmd.Flags().AddFlagSet(fixtures.FileFixtureFlags)
 
 	dumpFixtureCmd.Flags().AddFlagSet(fixtures.DefaultFixtureFlags)
+	dumpFixtureCmd.Flags().AddFlagSet(fixtures.FileFixtureFlags)
 	rootCmd.AddCommand(dumpFixtureCmd)
 
+	deployBundleFlags.String(cfgBundleDir, "", "directory to write per-host deployment bundles to")
+	_ = viper.BindPFlags(deployBundleFlags)
+	deployBundleCmd.Flags().AddFlagSet(deployBundleFlags)
+	deployBundleCmd.Flags().AddFlagSet(fixtures.DefaultFixtureFlags)
+	deployBundleCmd.Flags().AddFlagSet(fixtures.FileFixtureFlags)
+	rootCmd.AddCommand(deployBundleCmd)
+
 	cobra.OnInitialize(func() {
 		if cfgFile != "" {
 			viper.SetConfigFile(cfgFile)
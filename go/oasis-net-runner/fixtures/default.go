@@ -33,6 +33,8 @@ const (
 	cfgRuntimeProvisioner      = "fixture.default.runtime.provisioner"
 	cfgRuntimeGenesisState     = "fixture.default.runtime.genesis_state"
 	cfgRuntimeLoader           = "fixture.default.runtime.loader"
+	cfgRuntimeUpgradeBinary    = "fixture.default.runtime.upgrade.binary"
+	cfgRuntimeUpgradeEpoch     = "fixture.default.runtime.upgrade.epoch"
 	cfgSetupRuntimes           = "fixture.default.setup_runtimes"
 	cfgTEEHardware             = "fixture.default.tee_hardware"
 	cfgInitialHeight           = "fixture.default.initial_height"
@@ -214,6 +216,36 @@ func newDefaultFixture() (*oasis.NetworkFixture, error) {
 			}
 			fixture.Clients[0].Runtimes = append(fixture.Clients[0].Runtimes, i+1)
 		}
+
+		if upgradeBinary := viper.GetString(cfgRuntimeUpgradeBinary); upgradeBinary != "" {
+			if len(runtimes) == 0 {
+				cmdCommon.EarlyLogAndExit(fmt.Errorf("runtime upgrade requires a compute runtime"))
+			}
+
+			// The new version of the first compute runtime is registered and rolled out to the
+			// compute workers at the configured epoch.
+			var storageRuntimes []int
+			for i := range runtimes {
+				storageRuntimes = append(storageRuntimes, i+1)
+			}
+			for i := range fixture.StorageWorkers {
+				fixture.StorageWorkers[i].Runtimes = storageRuntimes
+			}
+
+			upgradedRuntime := fixture.Runtimes[1]
+			upgradedRuntime.Binaries = map[node.TEEHardware][]string{
+				tee: {upgradeBinary},
+			}
+			upgradedRuntime.ExcludeFromGenesis = true
+			fixture.Runtimes = append(fixture.Runtimes, upgradedRuntime)
+
+			fixture.RuntimeUpgrades = []oasis.RuntimeUpgradeFixture{
+				{
+					Runtime: len(fixture.Runtimes) - 1,
+					Epoch:   beacon.EpochTime(viper.GetUint64(cfgRuntimeUpgradeEpoch)),
+				},
+			}
+		}
 	}
 
 	return fixture, nil
@@ -233,6 +265,8 @@ func init() {
 	// []string{""} as default doesn't work and ends up as an empty slice.
 	DefaultFixtureFlags.StringSlice(cfgRuntimeGenesisState, []string{"", ""}, "path to the runtime genesis state")
 	DefaultFixtureFlags.String(cfgRuntimeLoader, "oasis-core-runtime-loader", "path to the runtime loader")
+	DefaultFixtureFlags.String(cfgRuntimeUpgradeBinary, "", "path to the upgraded runtime binary (enables runtime upgrade)")
+	DefaultFixtureFlags.Uint64(cfgRuntimeUpgradeEpoch, 5, "epoch at which the runtime is upgraded")
 	DefaultFixtureFlags.String(cfgTEEHardware, "", "TEE hardware to use")
 	DefaultFixtureFlags.Uint64(cfgHaltEpoch, math.MaxUint64, "halt epoch height")
 	DefaultFixtureFlags.Int64(cfgInitialHeight, 1, "initial block height")
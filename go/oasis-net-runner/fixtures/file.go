@@ -1,26 +1,54 @@
 package fixtures
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
 
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 
 	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
 )
 
 const (
 	cfgFile = "fixture.file"
+	cfgVars = "fixture.vars"
 )
 
-// newFixtureFromFile parses given JSON file and creates new fixture object from it.
+// newFixtureFromFile parses given JSON or YAML file and creates new fixture object from it.
 func newFixtureFromFile(path string) (*oasis.NetworkFixture, error) {
-	f := oasis.NetworkFixture{}
 	data, err := ioutil.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("newFixtureFromFile: failed to open fixture file: %w", err)
 	}
+
+	return parseFixture(filepath.Base(path), data, viper.GetStringMapString(cfgVars))
+}
+
+// parseFixture renders the given fixture template with the given variables and parses the result.
+//
+// The fixture is parsed as YAML in case the name has a .yaml or .yml extension and as JSON
+// otherwise.
+func parseFixture(name string, data []byte, vars map[string]string) (*oasis.NetworkFixture, error) {
+	data, err := renderFixture(name, data, vars)
+	if err != nil {
+		return nil, fmt.Errorf("newFixtureFromFile: failed to render fixture file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, fmt.Errorf("newFixtureFromFile: failed to unmarshal YAML from fixture file: %w", err)
+		}
+	}
+
+	f := oasis.NetworkFixture{}
 	if err = json.Unmarshal(data, &f); err != nil {
 		return nil, fmt.Errorf("newFixtureFromFile: failed to unmarshal JSON from fixture file: %w", err)
 	}
@@ -28,7 +56,114 @@ func newFixtureFromFile(path string) (*oasis.NetworkFixture, error) {
 	return &f, nil
 }
 
+// renderFixture renders the fixture as a text/template.
+//
+// Besides accessing the variables directly (e.g., {{ .name }}), the template can use the var
+// function to get a variable with an optional default value ({{ var "name" default }}), the seq
+// function to get the integers from 0 to n-1 ({{ range $i := seq n }}) and the add function to
+// add two integers.
+func renderFixture(name string, data []byte, vars map[string]string) ([]byte, error) {
+	funcs := template.FuncMap{
+		"var": func(name string, def ...interface{}) (interface{}, error) {
+			if v, ok := vars[name]; ok {
+				return v, nil
+			}
+			switch len(def) {
+			case 0:
+				return nil, fmt.Errorf("fixture variable '%s' is not set", name)
+			case 1:
+				return def[0], nil
+			default:
+				return nil, fmt.Errorf("fixture variable '%s' has more than one default", name)
+			}
+		},
+		"seq": func(n interface{}) ([]int, error) {
+			count, err := toInt(n)
+			if err != nil {
+				return nil, err
+			}
+			seq := make([]int, 0, count)
+			for i := 0; i < count; i++ {
+				seq = append(seq, i)
+			}
+			return seq, nil
+		},
+		"add": func(a, b interface{}) (int, error) {
+			x, err := toInt(a)
+			if err != nil {
+				return 0, err
+			}
+			y, err := toInt(b)
+			if err != nil {
+				return 0, err
+			}
+			return x + y, nil
+		},
+	}
+
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, vars); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func toInt(v interface{}) (int, error) {
+	switch n := v.(type) {
+	case int:
+		return n, nil
+	case string:
+		return strconv.Atoi(n)
+	default:
+		return 0, fmt.Errorf("not an integer: %v", v)
+	}
+}
+
+// yamlToJSON converts a YAML document to JSON so that the fixture can be decoded using the JSON
+// (un)marshalers of its fields.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = map[string]interface{}{}
+	}
+	return json.Marshal(normalizeYAML(doc))
+}
+
+// normalizeYAML converts YAML mappings with non-string keys into mappings with string keys as
+// required by the JSON encoder.
+func normalizeYAML(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = normalizeYAML(item)
+		}
+		return v
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			m[fmt.Sprintf("%v", k)] = normalizeYAML(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeYAML(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
 func init() {
-	FileFixtureFlags.String(cfgFile, "", "path to JSON-encoded fixture input file")
+	FileFixtureFlags.String(cfgFile, "", "path to JSON or YAML-encoded fixture input file")
+	FileFixtureFlags.StringToString(cfgVars, nil, "fixture file template variables (format: <name1>=<value1>,<name2>=<value2>)")
 	_ = viper.BindPFlags(FileFixtureFlags)
 }
@@ -4,10 +4,12 @@ import (
 	"io/ioutil"
 	"testing"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 )
 
 func TestDefaultFixture(t *testing.T) {
@@ -20,6 +22,33 @@ func TestDefaultFixture(t *testing.T) {
 	require.NotNil(t, data)
 }
 
+func TestDefaultFixtureRuntimeUpgrade(t *testing.T) {
+	require := require.New(t)
+
+	viper.Set(cfgRuntimeUpgradeBinary, "simple-keyvalue-upgrade")
+	viper.Set(cfgRuntimeUpgradeEpoch, 7)
+	defer viper.Set(cfgRuntimeUpgradeBinary, "")
+
+	f, err := newDefaultFixture()
+	require.NoError(err, "newDefaultFixture")
+	require.Len(f.Runtimes, 3, "upgraded runtime should be added")
+	require.Len(f.RuntimeUpgrades, 1, "runtime upgrade should be configured")
+
+	upgrade := f.RuntimeUpgrades[0]
+	require.EqualValues(2, upgrade.Runtime)
+	require.EqualValues(7, upgrade.Epoch)
+
+	upgraded := f.Runtimes[upgrade.Runtime]
+	require.Equal(f.Runtimes[1].ID, upgraded.ID, "upgraded runtime should have the same ID")
+	require.True(upgraded.ExcludeFromGenesis, "upgraded runtime should be registered later")
+	for _, binaries := range upgraded.Binaries {
+		require.Equal([]string{"simple-keyvalue-upgrade"}, binaries)
+	}
+	for _, sw := range f.StorageWorkers {
+		require.Equal([]int{1}, sw.Runtimes, "storage workers should only host the original runtime")
+	}
+}
+
 func TestCustomFixture(t *testing.T) {
 	f, _ := newDefaultFixture()
 	f.Network.NodeBinary = "myNodeBinary"
@@ -39,3 +68,61 @@ func TestCustomFixture(t *testing.T) {
 	require.Nil(t, err)
 	require.EqualValues(t, f, fs)
 }
+
+func TestYAMLFixture(t *testing.T) {
+	require := require.New(t)
+
+	raw := []byte(`
+network:
+  node_binary: {{ var "node_binary" "oasis-node" }}
+  beacon:
+    backend: insecure
+    insecure_parameters:
+      interval: {{ var "epoch_interval" 20 }}
+entities:
+  - IsDebugTestEntity: true
+  - {}
+validators:
+  - entity: 1
+runtimes:
+  - id: "8000000000000000000000000000000000000000000000000000000000000000"
+    kind: 1
+    entity: 0
+    keymanager: -1
+    governance_model: entity
+    executor:
+      group_size: {{ var "group_size" 2 }}
+compute_workers:
+{{- range $i := seq (var "compute_workers" 3) }}
+  - entity: 1
+    runtimes: [0]
+    node_name: compute-{{ add $i 1 }}
+{{- end }}
+`)
+
+	f, err := parseFixture("fixture.yaml", raw, map[string]string{
+		"compute_workers": "2",
+		"epoch_interval":  "10",
+	})
+	require.NoError(err, "parseFixture")
+	require.Equal("oasis-node", f.Network.NodeBinary, "defaults should be used for unset variables")
+	require.EqualValues(10, f.Network.Beacon.InsecureParameters.Interval, "variables should be substituted")
+	require.Len(f.Entities, 2)
+	require.True(f.Entities[0].IsDebugTestEntity)
+	require.Len(f.Runtimes, 1)
+	require.EqualValues(registry.KindCompute, f.Runtimes[0].Kind)
+	require.EqualValues(2, f.Runtimes[0].Executor.GroupSize)
+	require.Len(f.ComputeWorkers, 2, "number of compute workers should be configurable")
+	require.Equal("compute-2", f.ComputeWorkers[1].Name)
+
+	// JSON and YAML fixtures should be equivalent.
+	data, err := DumpFixture(f)
+	require.NoError(err, "DumpFixture")
+	fj, err := parseFixture("fixture.json", data, nil)
+	require.NoError(err, "parseFixture")
+	require.EqualValues(f, fj)
+
+	// Variables without defaults must be set.
+	_, err = parseFixture("fixture.yml", []byte(`network: {node_binary: {{ var "node_binary" }}}`), nil)
+	require.Error(err, "parseFixture should fail for unset variables")
+}
@@ -0,0 +1,147 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	// CfgAliases configures the user-defined command aliases, mapping alias
+	// names to the commands (with arguments) that they expand to.
+	CfgAliases = "aliases"
+
+	// envAliases is the environment variable that can be used to configure
+	// the command aliases, as a JSON object.
+	envAliases = "OASIS_NODE_ALIASES"
+)
+
+var aliasFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+// aliasesFromArgs returns the command aliases configured via the config
+// file, the environment or the command line flags preceding the
+// sub-command, before the command line is parsed.
+//
+// The configuration is resolved in the same way as the global
+// configuration (see InitConfig).
+func aliasesFromArgs(args []string) (map[string]string, error) {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.ParseErrorsWhitelist.UnknownFlags = true
+	fs.Usage = func() {}
+	fs.String(CfgConfigFile, "", "")
+	fs.StringToString(CfgAliases, nil, "")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	if err := v.BindPFlags(fs); err != nil {
+		return nil, err
+	}
+	fn, _ := fs.GetString(CfgConfigFile)
+	if err := initViper(v, fn); err != nil {
+		// The error will be reported once the config file is loaded.
+		return nil, nil
+	}
+	return v.GetStringMapString(CfgAliases), nil
+}
+
+// commandIndex returns the index of the first positional argument (the
+// sub-command name) in the given command line of the root command, or -1
+// if there is none.
+func commandIndex(rootCmd *cobra.Command, args []string) int {
+	lookup := func(name string, shorthand bool) *flag.Flag {
+		for _, fs := range []*flag.FlagSet{rootCmd.PersistentFlags(), rootCmd.Flags()} {
+			var f *flag.Flag
+			if shorthand {
+				f = fs.ShorthandLookup(name)
+			} else {
+				f = fs.Lookup(name)
+			}
+			if f != nil {
+				return f
+			}
+		}
+		return nil
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return -1
+		case strings.HasPrefix(arg, "--"):
+			name := strings.TrimPrefix(arg, "--")
+			if strings.Contains(name, "=") {
+				continue
+			}
+			if f := lookup(name, false); f != nil && f.NoOptDefVal == "" {
+				// Skip the flag value.
+				i++
+			}
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			if len(arg) != 2 {
+				continue
+			}
+			if f := lookup(arg[1:], true); f != nil && f.NoOptDefVal == "" {
+				// Skip the flag value.
+				i++
+			}
+		default:
+			return i
+		}
+	}
+	return -1
+}
+
+// ExpandAliases expands the user-defined command alias used in the given
+// command line of the root command (if any). Aliases can not shadow the
+// built-in commands.
+func ExpandAliases(rootCmd *cobra.Command, args []string) ([]string, error) {
+	idx := commandIndex(rootCmd, args)
+	if idx < 0 {
+		return args, nil
+	}
+	name := args[idx]
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return args, nil
+		}
+	}
+
+	aliases, err := aliasesFromArgs(args[:idx])
+	if err != nil {
+		return nil, err
+	}
+	var (
+		expansion string
+		ok        bool
+	)
+	for k, v := range aliases {
+		if strings.EqualFold(k, name) {
+			expansion, ok = v, true
+			break
+		}
+	}
+	if !ok {
+		return args, nil
+	}
+	expanded := strings.Fields(expansion)
+	if len(expanded) == 0 {
+		return nil, fmt.Errorf("empty command alias '%s'", name)
+	}
+
+	result := make([]string, 0, len(args)+len(expanded)-1)
+	result = append(result, args[:idx]...)
+	result = append(result, expanded...)
+	result = append(result, args[idx+1:]...)
+	return result, nil
+}
+
+func initAliasFlags() {
+	aliasFlags.StringToString(CfgAliases, nil, "command aliases (name=command)")
+	_ = viper.BindPFlags(aliasFlags)
+}
@@ -0,0 +1,71 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandAliases(t *testing.T) {
+	require := require.New(t)
+
+	fn := filepath.Join(t.TempDir(), "config.yml")
+	err := os.WriteFile(fn, []byte(`aliases:
+  bal: stake account info --stake.account.address
+  stake: control status
+  empty: ""
+`), 0o600)
+	require.NoError(err, "WriteFile")
+
+	rootCmd := &cobra.Command{Use: "oasis-node"}
+	rootCmd.PersistentFlags().String(CfgConfigFile, "", "")
+	rootCmd.PersistentFlags().Bool("verbose", false, "")
+	rootCmd.PersistentFlags().StringP("address", "a", "", "")
+	rootCmd.AddCommand(&cobra.Command{Use: "stake"})
+
+	for _, tc := range []struct {
+		args     []string
+		expanded []string
+	}{
+		// No config file.
+		{[]string{"bal", "addr"}, []string{"bal", "addr"}},
+		// Alias expansion, with flags preceding the alias.
+		{
+			[]string{"--config", fn, "bal", "addr"},
+			[]string{"--config", fn, "stake", "account", "info", "--stake.account.address", "addr"},
+		},
+		{
+			[]string{"--verbose", "-a", "bal", "--config=" + fn, "bal", "addr"},
+			[]string{"--verbose", "-a", "bal", "--config=" + fn, "stake", "account", "info", "--stake.account.address", "addr"},
+		},
+		// Built-in commands can not be shadowed.
+		{[]string{"--config", fn, "stake", "list"}, []string{"--config", fn, "stake", "list"}},
+		// Unknown aliases.
+		{[]string{"--config", fn, "unknown"}, []string{"--config", fn, "unknown"}},
+	} {
+		expanded, err := ExpandAliases(rootCmd, tc.args)
+		require.NoError(err, "ExpandAliases(%v)", tc.args)
+		require.Equal(tc.expanded, expanded, "ExpandAliases(%v)", tc.args)
+	}
+
+	_, err = ExpandAliases(rootCmd, []string{"--config", fn, "empty"})
+	require.Error(err, "ExpandAliases should fail for empty aliases")
+
+	// Aliases can also be configured via flags and the environment, which
+	// take precedence over the config file.
+	rootCmd.PersistentFlags().AddFlagSet(aliasFlags)
+	expanded, err := ExpandAliases(rootCmd, []string{"--aliases", "st=control status", "st"})
+	require.NoError(err, "ExpandAliases")
+	require.Equal([]string{"--aliases", "st=control status", "control", "status"}, expanded)
+
+	t.Setenv(envAliases, `{"bal": "stake account info"}`)
+	expanded, err = ExpandAliases(rootCmd, []string{"--config", fn, "bal", "addr"})
+	require.NoError(err, "ExpandAliases")
+	require.Equal([]string{"--config", fn, "stake", "account", "info", "addr"}, expanded)
+	expanded, err = ExpandAliases(rootCmd, []string{"--aliases", "bal=control status", "bal"})
+	require.NoError(err, "ExpandAliases")
+	require.Equal([]string{"--aliases", "bal=control status", "control", "status"}, expanded)
+}
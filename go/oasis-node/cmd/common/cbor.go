@@ -0,0 +1,34 @@
+package common
+
+import (
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+)
+
+const (
+	cfgCBORMaxNestedLevels  = "cbor.decode.max_nested_levels"
+	cfgCBORMaxArrayElements = "cbor.decode.max_array_elements"
+	cfgCBORMaxMapPairs      = "cbor.decode.max_map_pairs"
+	cfgCBORMaxSize          = "cbor.decode.max_size"
+)
+
+var cborFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+func initCBOR() error {
+	return cbor.SetIngressDecodeLimits(cbor.DecodeLimits{
+		MaxNestedLevels:  viper.GetInt(cfgCBORMaxNestedLevels),
+		MaxArrayElements: viper.GetInt(cfgCBORMaxArrayElements),
+		MaxMapPairs:      viper.GetInt(cfgCBORMaxMapPairs),
+		MaxSize:          int(viper.GetSizeInBytes(cfgCBORMaxSize)),
+	})
+}
+
+func initCBORFlags() {
+	cborFlags.Int(cfgCBORMaxNestedLevels, cbor.DefaultDecodeLimits.MaxNestedLevels, "maximum nesting depth of CBOR values received from peers")
+	cborFlags.Int(cfgCBORMaxArrayElements, cbor.DefaultDecodeLimits.MaxArrayElements, "maximum number of elements of CBOR arrays received from peers")
+	cborFlags.Int(cfgCBORMaxMapPairs, cbor.DefaultDecodeLimits.MaxMapPairs, "maximum number of pairs of CBOR maps received from peers")
+	cborFlags.String(cfgCBORMaxSize, "0", "maximum size of CBOR messages received from peers (0 = unlimited)")
+	_ = viper.BindPFlags(cborFlags)
+}
@@ -2,6 +2,7 @@
 package common
 
 import (
+	"bufio"
 	"fmt"
 	"io"
 	"os"
@@ -94,8 +95,11 @@ func Init() error {
 	initFns := []func() error{
 		initDataDir,
 		initLogging,
+		initCBOR,
 		initPublicKeyBlacklist,
 		initRlimit,
+		initOutputFormat,
+		initNetwork,
 	}
 
 	for _, fn := range initFns {
@@ -109,6 +113,32 @@ func Init() error {
 	return nil
 }
 
+// InitReadOnly initializes the subset of the common environment that does
+// not modify the node's environment, for commands that only inspect it.
+//
+// Unlike Init, it does not create the data directory, does not open the
+// log file (logging to standard error instead) and does not change the
+// resource limits.
+func InitReadOnly() error {
+	initFns := []func() error{
+		initConsoleLogging,
+		initCBOR,
+		initPublicKeyBlacklist,
+		initOutputFormat,
+		initNetwork,
+	}
+
+	for _, fn := range initFns {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+
+	rootLog.Debug("common read-only initialization complete")
+
+	return nil
+}
+
 // Logger returns the command logger.
 func Logger() *logging.Logger {
 	return rootLog
@@ -116,6 +146,13 @@ func Logger() *logging.Logger {
 
 func init() {
 	initLoggingFlags()
+	initOutputFormatFlags()
+	initNetworkFlags()
+	initCBORFlags()
+	initAliasFlags()
+
+	// The signer package can't depend on this package, so provide the passphrase prompt.
+	cmdSigner.PromptPassphrase = GetUserPassphrase
 
 	debugAllowTestKeysFlag.Bool(CfgDebugAllowTestKeys, false, "allow test keys (UNSAFE)")
 	_ = debugAllowTestKeysFlag.MarkHidden(CfgDebugAllowTestKeys)
@@ -133,6 +170,25 @@ func init() {
 	RootFlags.AddFlagSet(debugAllowTestKeysFlag)
 	RootFlags.AddFlagSet(debugRlimitFlag)
 	RootFlags.AddFlagSet(flags.DebugDontBlameOasisFlag)
+	RootFlags.AddFlagSet(outputFormatFlags)
+	RootFlags.AddFlagSet(networkFlags)
+	RootFlags.AddFlagSet(cborFlags)
+	RootFlags.AddFlagSet(aliasFlags)
+}
+
+// initViper initializes the given configuration from the config file (if
+// any) and the environment.
+func initViper(v *viper.Viper, fn string) error {
+	if fn != "" {
+		// Read the config file if one is provided, otherwise
+		// it is assumed that the combination of default values,
+		// command line flags and env vars is sufficient.
+		v.SetConfigFile(fn)
+		if err := v.ReadInConfig(); err != nil {
+			return err
+		}
+	}
+	return v.BindEnv(CfgAliases, envAliases)
 }
 
 // InitConfig initializes the command configuration.
@@ -140,14 +196,8 @@ func init() {
 // WARNING: This is exposed for the benefit of tests and the interface
 // is not guaranteed to be stable.
 func InitConfig() {
-	if cfgFile != "" {
-		// Read the config file if one is provided, otherwise
-		// it is assumed that the combination of default values,
-		// command line flags and env vars is sufficient.
-		viper.SetConfigFile(cfgFile)
-		if err := viper.ReadInConfig(); err != nil {
-			EarlyLogAndExit(err)
-		}
+	if err := initViper(viper.GetViper(), cfgFile); err != nil {
+		EarlyLogAndExit(err)
 	}
 
 	dataDir := viper.GetString(CfgDataDir)
@@ -171,6 +221,50 @@ func InitConfig() {
 	viper.Set(CfgDataDir, dataDir)
 }
 
+// ReloadConfig re-reads the configuration file (if any), applies the
+// subset of the common configuration that can be changed at runtime
+// (currently the log levels) and returns the reloaded configuration.
+//
+// The global configuration is never modified as it is read concurrently
+// by running services. Instead, the reloaded configuration only contains
+// the log levels and the given reloadable keys, which subsystems supporting
+// a configuration reload should re-read their configuration from. Their
+// values are resolved as on startup: flags explicitly given on the command
+// line (from fs or the root flags) take precedence over the configuration
+// file, which takes precedence over the flag defaults.
+func ReloadConfig(fs *flag.FlagSet, reloadable []string) (*viper.Viper, error) {
+	cfg, err := loadReloadableConfig(fs, append([]string{cfgLogLevel}, reloadable...))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = reloadLogging(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func loadReloadableConfig(fs *flag.FlagSet, reloadable []string) (*viper.Viper, error) {
+	v := viper.New()
+	for _, flags := range []*flag.FlagSet{RootFlags, fs} {
+		if err := v.BindPFlags(flags); err != nil {
+			return nil, fmt.Errorf("failed to bind flags: %w", err)
+		}
+	}
+	if cfgFile != "" {
+		v.SetConfigFile(cfgFile)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+
+	cfg := viper.New()
+	for _, key := range reloadable {
+		cfg.Set(key, v.Get(key))
+	}
+	return cfg, nil
+}
+
 func initDataDir() error {
 	dataDir := viper.GetString(CfgDataDir)
 	if dataDir == "" {
@@ -279,6 +373,29 @@ func ExportEntity(signerBackend, entityDir string) error {
 	return err
 }
 
+// GetUserInput displays the prompt and returns the line entered by the
+// user. In case the user enters an empty line, the default value is returned.
+//
+// Note: If standard input is not a tty, this will omit displaying the
+// prompt, and return the default value.
+func GetUserInput(prompt, defaultValue string) string {
+	if !Isatty(os.Stdin.Fd()) {
+		return defaultValue
+	}
+
+	fmt.Printf("%s [%s]: ", prompt, defaultValue)
+
+	var response string
+	if _, err := fmt.Scanln(&response); err != nil && err.Error() != "unexpected newline" {
+		rootLog.Error("Error reading from line", "err", err)
+		return defaultValue
+	}
+	if response = strings.TrimSpace(response); response == "" {
+		return defaultValue
+	}
+	return response
+}
+
 // GetUserConfirmation displays the prompt, and scans the input for
 // the user's confirmation, until the user either explicitly confirms
 // or rejects the prompt.
@@ -311,6 +428,30 @@ func GetUserConfirmation(prompt string) bool {
 	}
 }
 
+// GetUserPassphrase displays the prompt and returns the passphrase entered
+// by the user, without echoing it back to the terminal.
+//
+// Note: Unlike the other prompts, this fails if standard input is not a tty.
+func GetUserPassphrase(prompt string) (string, error) {
+	fd := os.Stdin.Fd()
+	if !Isatty(fd) {
+		return "", fmt.Errorf("standard input is not a terminal")
+	}
+
+	fmt.Printf("%s: ", prompt)
+	restore, err := disableEcho(fd)
+	if err != nil {
+		return "", fmt.Errorf("failed to disable terminal echo: %w", err)
+	}
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	restore()
+	fmt.Println()
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimRight(response, "\r\n"), nil
+}
+
 // SetBasicVersionTemplate sets a basic custom version template for the given
 // cobra command that shows the version of Oasis Core and the Go toolchain.
 func SetBasicVersionTemplate(cmd *cobra.Command) {
@@ -3,6 +3,7 @@ package consensus
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -10,7 +11,6 @@ import (
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
-	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	signerFile "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/file"
 	signerPlugin "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/plugin"
@@ -38,15 +38,55 @@ const (
 
 	// CfgTxUnsigned makes SaveTx save an unsigned transaction.
 	CfgTxUnsigned = "transaction.unsigned"
+
+	// CfgTxUnsignedFile configures the filename for the unsigned transaction.
+	CfgTxUnsignedFile = "transaction.unsigned_file"
 )
 
 var (
-	TxFlags     = flag.NewFlagSet("", flag.ContinueOnError)
-	TxFileFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	TxFlags             = flag.NewFlagSet("", flag.ContinueOnError)
+	TxFileFlags         = flag.NewFlagSet("", flag.ContinueOnError)
+	TxUnsignedFileFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	// genesisChainContext is the chain domain separation context of the genesis document loaded
+	// by InitGenesis.
+	genesisChainContext string
 
 	logger = logging.GetLogger("cmd/common/consensus")
 )
 
+// UnsignedTransaction is an unsigned transaction envelope.
+//
+// Besides the transaction itself (including the nonce and fee), it contains the chain domain
+// separation context so that the transaction can be signed on a machine that has access to
+// neither the network nor the genesis document.
+type UnsignedTransaction struct {
+	// ChainContext is the chain domain separation context used when signing the transaction.
+	ChainContext string `json:"chain_context"`
+	// Transaction is the unsigned transaction.
+	Transaction *transaction.Transaction `json:"transaction"`
+}
+
+// LoadUnsignedTx loads an unsigned transaction envelope from the given file.
+func LoadUnsignedTx(fn string) (*UnsignedTransaction, error) {
+	raw, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read unsigned transaction: %w", err)
+	}
+
+	var utx UnsignedTransaction
+	if err = json.Unmarshal(raw, &utx); err != nil {
+		return nil, fmt.Errorf("failed to parse unsigned transaction: %w", err)
+	}
+	if utx.ChainContext == "" {
+		return nil, fmt.Errorf("unsigned transaction is missing the chain context")
+	}
+	if utx.Transaction == nil {
+		return nil, fmt.Errorf("unsigned transaction is missing the transaction")
+	}
+	return &utx, nil
+}
+
 func AssertTxFileOK() {
 	f := viper.GetString(CfgTxFile)
 	if f == "" {
@@ -75,7 +115,17 @@ func InitGenesis() *genesisAPI.Document {
 		)
 		os.Exit(1)
 	}
+	if network := cmdCommon.Network(); network != nil {
+		if err = network.VerifyChainContext(genesisDoc.ChainContext()); err != nil {
+			logger.Error("genesis document does not match the selected network",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+	}
+
 	genesisDoc.SetChainContext()
+	genesisChainContext = genesisDoc.ChainContext()
 
 	return genesisDoc
 }
@@ -95,8 +145,21 @@ func GetTxNonceAndFee() (uint64, *transaction.Fee) {
 
 func SignAndSaveTx(ctx context.Context, tx *transaction.Transaction, signer signature.Signer) {
 	if viper.GetBool(CfgTxUnsigned) {
-		rawUnsignedTx := cbor.Marshal(tx)
-		if err := ioutil.WriteFile(viper.GetString(CfgTxFile), rawUnsignedTx, 0o600); err != nil {
+		if genesisChainContext == "" {
+			logger.Error("failed to determine chain context of unsigned transaction")
+			os.Exit(1)
+		}
+		prettyUnsignedTx, err := cmdCommon.PrettyJSONMarshal(&UnsignedTransaction{
+			ChainContext: genesisChainContext,
+			Transaction:  tx,
+		})
+		if err != nil {
+			logger.Error("failed to get pretty JSON of unsigned transaction",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		if err = ioutil.WriteFile(viper.GetString(CfgTxFile), prettyUnsignedTx, 0o600); err != nil {
 			logger.Error("failed to save unsigned transaction",
 				"err", err,
 			)
@@ -160,6 +223,9 @@ func init() {
 	TxFileFlags.String(CfgTxFile, "", "path to the transaction")
 	_ = viper.BindPFlags(TxFileFlags)
 
+	TxUnsignedFileFlags.String(CfgTxUnsignedFile, "", "path to the unsigned transaction")
+	_ = viper.BindPFlags(TxUnsignedFileFlags)
+
 	TxFlags.Uint64(CfgTxNonce, 0, "nonce of the signing account")
 	TxFlags.Uint64(CfgTxFeeAmount, 0, "transaction fee in base units")
 	TxFlags.String(CfgTxFeeGas, "0", "maximum transaction gas limit")
@@ -16,6 +16,9 @@ const (
 	CfgDebugTestEntity = "debug.test_entity"
 	// CfgGenesisFile is the flag used to specify a genesis file.
 	CfgGenesisFile = "genesis.file"
+	// CfgGenesisExpectedHash is the flag used to specify the expected genesis
+	// document hash.
+	CfgGenesisExpectedHash = "genesis.expected_hash"
 	// CfgConsensusValidator is the flag used to opt-in to being a validator.
 	CfgConsensusValidator = "consensus.validator"
 
@@ -41,6 +44,8 @@ var (
 
 	// GenesisFileFlags has the genesis file flag.
 	GenesisFileFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	// GenesisExpectedHashFlags has the expected genesis hash flag.
+	GenesisExpectedHashFlags = flag.NewFlagSet("", flag.ContinueOnError)
 
 	// ConsensusValidatorFlag has the consensus validator flag.
 	ConsensusValidatorFlag = flag.NewFlagSet("", flag.ContinueOnError)
@@ -80,6 +85,11 @@ func GenesisFile() string {
 	return viper.GetString(CfgGenesisFile)
 }
 
+// GenesisExpectedHash returns the set expected genesis document hash.
+func GenesisExpectedHash() string {
+	return viper.GetString(CfgGenesisExpectedHash)
+}
+
 // DebugDontBlameOasis returns true iff the "don't blame oasis" flag is set.
 func DebugDontBlameOasis() bool {
 	return viper.GetBool(CfgDebugDontBlameOasis)
@@ -107,6 +117,8 @@ func init() {
 
 	GenesisFileFlags.StringP(CfgGenesisFile, "g", "genesis.json", "path to genesis file")
 
+	GenesisExpectedHashFlags.String(CfgGenesisExpectedHash, "", "expected hash of the genesis document (hex-encoded)")
+
 	DebugDontBlameOasisFlag.Bool(CfgDebugDontBlameOasis, false, "Enable debug/unsafe/insecure options")
 	_ = DebugDontBlameOasisFlag.MarkHidden(CfgDebugDontBlameOasis)
 
@@ -119,6 +131,7 @@ func init() {
 		ForceFlags,
 		DebugTestEntityFlags,
 		GenesisFileFlags,
+		GenesisExpectedHashFlags,
 		ConsensusValidatorFlag,
 		DebugDontBlameOasisFlag,
 		DryRunFlag,
@@ -0,0 +1,111 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/grpc/policy"
+	"github.com/oasisprotocol/oasis-core/go/common/grpc/proxy"
+	"github.com/oasisprotocol/oasis-core/go/common/identity"
+)
+
+const (
+	// CfgExternalPort configures the port of the external gRPC API. The external API is disabled
+	// if the port is not set.
+	CfgExternalPort = "grpc.external.port"
+	// CfgExternalMethods configures the services and methods allowed on the external gRPC API.
+	CfgExternalMethods = "grpc.external.methods"
+	// CfgExternalRuntimes configures the runtimes allowed on the external gRPC API.
+	CfgExternalRuntimes = "grpc.external.runtimes"
+	// CfgExternalReadOnly configures the external gRPC API to reject methods that modify state.
+	CfgExternalReadOnly = "grpc.external.read_only"
+)
+
+// ServerExternalFlags has the flags used by the external gRPC API server.
+var ServerExternalFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+// localDialer lazily establishes the connection to the internal socket.
+type localDialer struct {
+	sync.Mutex
+
+	path string
+	conn *grpc.ClientConn
+}
+
+func (d *localDialer) dial(ctx context.Context) (*grpc.ClientConn, error) {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.conn != nil {
+		return d.conn, nil
+	}
+	conn, err := cmnGrpc.Dial("unix:"+d.path, grpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial internal socket: %w", err)
+	}
+	d.conn = conn
+	return conn, nil
+}
+
+// NewServerExternal constructs a new gRPC server service exposing a restricted subset of the
+// node's internal API on a TCP port, as configured by the external API flags. All calls allowed
+// by the configured policy are proxied to the internal socket. Methods requiring the admin
+// capability are never allowed.
+//
+// In case the external API is not enabled, nil is returned.
+func NewServerExternal(id *identity.Identity) (*cmnGrpc.Server, error) {
+	port := uint16(viper.GetUint(CfgExternalPort))
+	if port == 0 {
+		return nil, nil
+	}
+
+	var externalPolicy policy.ExternalPolicy
+	externalPolicy.Methods = viper.GetStringSlice(CfgExternalMethods)
+	if len(externalPolicy.Methods) == 0 {
+		return nil, fmt.Errorf("external API enabled but no services or methods allowed (%s)", CfgExternalMethods)
+	}
+	externalPolicy.ReadOnly = viper.GetBool(CfgExternalReadOnly)
+	for _, raw := range viper.GetStringSlice(CfgExternalRuntimes) {
+		var rt common.Namespace
+		if err := rt.UnmarshalHex(raw); err != nil {
+			return nil, fmt.Errorf("malformed external API runtime identifier '%s': %w", raw, err)
+		}
+		externalPolicy.Runtimes = append(externalPolicy.Runtimes, rt)
+	}
+	authFunc, err := policy.ExternalAuthenticationFunction(&externalPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure external API policy: %w", err)
+	}
+
+	path, err := localSocketPath()
+	if err != nil {
+		return nil, err
+	}
+	dialer := &localDialer{path: path}
+
+	return cmnGrpc.NewServer(&cmnGrpc.ServerConfig{
+		Name:     "external-api",
+		Port:     port,
+		Identity: id,
+		AuthFunc: authFunc,
+		CustomOptions: []grpc.ServerOption{
+			// All requests are proxied to the internal socket.
+			grpc.UnknownServiceHandler(proxy.Handler(dialer.dial)),
+		},
+	})
+}
+
+func init() {
+	ServerExternalFlags.Uint16(CfgExternalPort, 0, "port of the external gRPC API (0 disables)")
+	ServerExternalFlags.StringSlice(CfgExternalMethods, nil, "services and methods allowed on the external gRPC API (format: <service>[/<method>],...; required)")
+	ServerExternalFlags.StringSlice(CfgExternalRuntimes, nil, "runtimes allowed on the external gRPC API (default: all)")
+	ServerExternalFlags.Bool(CfgExternalReadOnly, false, "reject external gRPC API methods that modify state")
+	_ = viper.BindPFlags(ServerExternalFlags)
+}
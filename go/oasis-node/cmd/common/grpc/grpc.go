@@ -2,11 +2,13 @@
 package grpc
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
@@ -14,8 +16,10 @@ import (
 	"google.golang.org/grpc"
 
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/grpc/auth"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 )
@@ -29,6 +33,23 @@ const (
 	CfgWait = "wait"
 	// CfgDebugGrpcInternalSocketPath sets custom internal socket path.
 	CfgDebugGrpcInternalSocketPath = "debug.grpc.internal.socket_path"
+	// CfgTokenFile configures the file containing the token used to authenticate to the node.
+	CfgTokenFile = "token_file"
+
+	// CfgInternalAuthEnabled enables capability-based authorization on the internal socket.
+	CfgInternalAuthEnabled = "grpc.internal.auth.enabled"
+	// CfgInternalAuthAdminUIDs configures the user IDs granted the admin capability on the
+	// internal socket. If not set, the user running the node is granted the admin capability.
+	CfgInternalAuthAdminUIDs = "grpc.internal.auth.admin_uids"
+	// CfgInternalAuthReadOnlyUIDs configures the user IDs granted the read-only capability on
+	// the internal socket.
+	CfgInternalAuthReadOnlyUIDs = "grpc.internal.auth.read_only_uids"
+	// CfgInternalAuthAdminTokenFile configures the file containing the token granting the admin
+	// capability on the internal socket.
+	CfgInternalAuthAdminTokenFile = "grpc.internal.auth.admin_token_file"
+	// CfgInternalAuthReadOnlyTokenFile configures the file containing the token granting the
+	// read-only capability on the internal socket.
+	CfgInternalAuthReadOnlyTokenFile = "grpc.internal.auth.read_only_token_file"
 
 	// LocalSocketFilename is the filename of the unix socket in node datadir.
 	LocalSocketFilename = "internal.sock"
@@ -63,33 +84,119 @@ func NewServerTCP(cert *tls.Certificate, installWrapper bool) (*cmnGrpc.Server,
 	return cmnGrpc.NewServer(config)
 }
 
-// NewServerLocal constructs a new gRPC server service listening on
-// a specific AF_LOCAL socket using default arguments.
-//
-// This internally takes a snapshot of the current global tracer, so
-// make sure you initialize the global tracer before calling this.
-func NewServerLocal(installWrapper bool) (*cmnGrpc.Server, error) {
+// localSocketPath returns the path of the internal socket.
+func localSocketPath() (string, error) {
 	dataDir := common.DataDir()
 	if dataDir == "" {
-		return nil, errors.New("data directory must be set")
+		return "", errors.New("data directory must be set")
 	}
 	path := filepath.Join(dataDir, LocalSocketFilename)
 	if viper.IsSet(CfgDebugGrpcInternalSocketPath) && flags.DebugDontBlameOasis() {
 		logger.Info("overriding internal socket path", "path", viper.GetString(CfgDebugGrpcInternalSocketPath))
 		path = viper.GetString(CfgDebugGrpcInternalSocketPath)
 	}
+	return path, nil
+}
+
+// NewServerLocal constructs a new gRPC server service listening on
+// a specific AF_LOCAL socket using default arguments.
+//
+// This internally takes a snapshot of the current global tracer, so
+// make sure you initialize the global tracer before calling this.
+func NewServerLocal(installWrapper bool) (*cmnGrpc.Server, error) {
+	path, err := localSocketPath()
+	if err != nil {
+		return nil, err
+	}
 
 	config := &cmnGrpc.ServerConfig{
 		Name:           "internal",
 		Path:           path,
 		InstallWrapper: installWrapper,
 	}
+	if viper.GetBool(CfgInternalAuthEnabled) {
+		policy, err := newInternalAuthPolicy()
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure internal socket authorization: %w", err)
+		}
+		config.AuthFunc = policy.AuthFunc
+		config.CustomOptions = append(config.CustomOptions, grpc.Creds(auth.NewLocalCredentials()))
+	}
 
 	return cmnGrpc.NewServer(config)
 }
 
+func readToken(fn string) (string, error) {
+	raw, err := os.ReadFile(fn)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(raw))
+	if token == "" {
+		return "", fmt.Errorf("empty token in '%s'", fn)
+	}
+	return token, nil
+}
+
+func newInternalAuthPolicy() (*auth.LocalPolicy, error) {
+	policy := &auth.LocalPolicy{
+		UIDs:          make(map[uint32]auth.Capability),
+		Tokens:        make(map[string]auth.Capability),
+		IsAdminMethod: cmnGrpc.IsAdminMethod,
+	}
+	for _, v := range []struct {
+		uidsCfg      string
+		tokenFileCfg string
+		capability   auth.Capability
+	}{
+		{CfgInternalAuthReadOnlyUIDs, CfgInternalAuthReadOnlyTokenFile, auth.CapabilityReadOnly},
+		{CfgInternalAuthAdminUIDs, CfgInternalAuthAdminTokenFile, auth.CapabilityAdmin},
+	} {
+		for _, uid := range viper.GetIntSlice(v.uidsCfg) {
+			if uid < 0 {
+				return nil, fmt.Errorf("invalid user ID: %d", uid)
+			}
+			policy.UIDs[uint32(uid)] = v.capability
+		}
+		if fn := viper.GetString(v.tokenFileCfg); fn != "" {
+			token, err := readToken(fn)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := policy.Tokens[token]; ok {
+				return nil, fmt.Errorf("read-only and admin tokens must differ")
+			}
+			policy.Tokens[token] = v.capability
+		}
+	}
+
+	// Unless configured otherwise, the user running the node is an admin.
+	uid := uint32(os.Getuid())
+	if _, ok := policy.UIDs[uid]; !ok && len(viper.GetIntSlice(CfgInternalAuthAdminUIDs)) == 0 {
+		policy.UIDs[uid] = auth.CapabilityAdmin
+	}
+	return policy, nil
+}
+
+// clientAddress returns the address to connect to, given the configured
+// address, whether it has been explicitly configured and the selected network
+// profile (if any).
+func clientAddress(addr string, explicit bool, network *common.NetworkProfile) string {
+	if network != nil && network.Address != "" && !explicit {
+		return network.Address
+	}
+	return addr
+}
+
+// NewClient creates a new gRPC client connection to the configured address.
+//
+// In case a network profile is selected, its address is used unless an
+// address has been explicitly configured, and the node is verified to be
+// part of the profile's network.
 func NewClient(cmd *cobra.Command) (*grpc.ClientConn, error) {
 	addr, _ := cmd.Flags().GetString(CfgAddress)
+	network := common.Network()
+	addr = clientAddress(addr, cmd.Flags().Changed(CfgAddress), network)
 
 	if _, err := os.Stat(addr); err == nil {
 		logger.Warn(fmt.Sprintf("'%s' is a file name. Assuming 'unix:%s'.", addr, addr))
@@ -100,6 +207,13 @@ func NewClient(cmd *cobra.Command) (*grpc.ClientConn, error) {
 	if viper.GetBool(CfgWait) {
 		opts = append(opts, grpc.WithDefaultCallOptions(grpc.WaitForReady(true)))
 	}
+	if fn := viper.GetString(CfgTokenFile); fn != "" {
+		token, err := readToken(fn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read token: %w", err)
+		}
+		opts = append(opts, grpc.WithPerRPCCredentials(auth.NewTokenCredentials(token)))
+	}
 
 	conn, err := cmnGrpc.Dial(
 		addr,
@@ -109,6 +223,18 @@ func NewClient(cmd *cobra.Command) (*grpc.ClientConn, error) {
 		return nil, err
 	}
 
+	if network != nil && network.ChainContext != "" {
+		chainContext, err := consensus.NewConsensusClient(conn).GetChainContext(context.Background())
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to query chain context: %w", err)
+		}
+		if err = network.VerifyChainContext(chainContext); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
 	return conn, nil
 }
 
@@ -119,11 +245,17 @@ func init() {
 
 	ServerLocalFlags.String(CfgDebugGrpcInternalSocketPath, "", "use custom internal unix socket path")
 	_ = ServerLocalFlags.MarkHidden(CfgDebugGrpcInternalSocketPath)
+	ServerLocalFlags.Bool(CfgInternalAuthEnabled, false, "enable capability-based authorization of internal socket clients")
+	ServerLocalFlags.IntSlice(CfgInternalAuthAdminUIDs, nil, "user IDs granted the admin capability (default: user running the node)")
+	ServerLocalFlags.IntSlice(CfgInternalAuthReadOnlyUIDs, nil, "user IDs granted the read-only capability")
+	ServerLocalFlags.String(CfgInternalAuthAdminTokenFile, "", "file containing the token granting the admin capability")
+	ServerLocalFlags.String(CfgInternalAuthReadOnlyTokenFile, "", "file containing the token granting the read-only capability")
 	_ = viper.BindPFlags(ServerLocalFlags)
 	ServerLocalFlags.AddFlagSet(cmnGrpc.Flags)
 
 	ClientFlags.StringP(CfgAddress, "a", defaultAddress, "remote gRPC address")
 	ClientFlags.Bool(CfgWait, false, "wait for gRPC address to become available")
+	ClientFlags.String(CfgTokenFile, "", "file containing the token used to authenticate to the node")
 	ClientFlags.AddFlagSet(cmnGrpc.Flags)
 	_ = viper.BindPFlags(ClientFlags)
 }
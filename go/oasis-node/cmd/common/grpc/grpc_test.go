@@ -0,0 +1,31 @@
+package grpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+)
+
+func TestClientAddress(t *testing.T) {
+	require := require.New(t)
+
+	network := &common.NetworkProfile{
+		Name:    "testnet",
+		Address: "unix:/testnet-node/data/internal.sock",
+	}
+
+	require.Equal(defaultAddress, clientAddress(defaultAddress, false, nil),
+		"the configured address should be used without a network profile",
+	)
+	require.Equal(network.Address, clientAddress(defaultAddress, false, network),
+		"the network profile address should override the default address",
+	)
+	require.Equal("unix:/other/internal.sock", clientAddress("unix:/other/internal.sock", true, network),
+		"an explicitly configured address should override the network profile address",
+	)
+	require.Equal(defaultAddress, clientAddress(defaultAddress, false, &common.NetworkProfile{Name: "mainnet"}),
+		"profiles without an address should not override the configured address",
+	)
+}
@@ -0,0 +1,138 @@
+// Package health implements an HTTP service exposing the node's liveness and readiness checks.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/service"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+)
+
+const (
+	// CfgHealthBind enables the liveness and readiness HTTP endpoints at the given address.
+	CfgHealthBind = "health.bind"
+
+	// LivenessPath is the path of the liveness endpoint.
+	LivenessPath = "/livez"
+	// ReadinessPath is the path of the readiness endpoint.
+	ReadinessPath = "/readyz"
+
+	checkTimeout = 5 * time.Second
+)
+
+// Flags has the flags used by the health service.
+var Flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+type healthService struct {
+	service.BaseBackgroundService
+
+	address    string
+	controller control.NodeController
+
+	listener net.Listener
+	server   *http.Server
+
+	ctx   context.Context
+	errCh chan error
+}
+
+func (h *healthService) handler(check func(context.Context) (*control.HealthStatus, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		defer cancel()
+
+		status, err := check(ctx)
+		if err != nil {
+			h.Logger.Error("failed to check node health",
+				"err", err,
+				"path", r.URL.Path,
+			)
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if !status.Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(status)
+	}
+}
+
+func (h *healthService) Start() error {
+	if h.address == "" {
+		return nil
+	}
+
+	h.Logger.Info("health HTTP endpoint is enabled",
+		"address", h.address,
+	)
+
+	listener, err := net.Listen("tcp", h.address)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(LivenessPath, h.handler(h.controller.CheckLiveness))
+	mux.HandleFunc(ReadinessPath, h.handler(h.controller.CheckReadiness))
+
+	h.listener = listener
+	h.server = &http.Server{Handler: mux}
+
+	go func() {
+		if err := h.server.Serve(h.listener); err != nil && err != http.ErrServerClosed {
+			h.BaseBackgroundService.Stop()
+			h.errCh <- err
+		}
+	}()
+
+	return nil
+}
+
+func (h *healthService) Stop() {
+	if h.server != nil {
+		select {
+		case err := <-h.errCh:
+			if err != nil {
+				h.Logger.Error("health server terminated uncleanly",
+					"err", err,
+				)
+			}
+		default:
+			_ = h.server.Shutdown(h.ctx)
+		}
+		h.server = nil
+	}
+}
+
+func (h *healthService) Cleanup() {
+	if h.listener != nil {
+		_ = h.listener.Close()
+		h.listener = nil
+	}
+}
+
+// New constructs a new health service.
+func New(ctx context.Context, controller control.NodeController) (service.BackgroundService, error) {
+	return &healthService{
+		BaseBackgroundService: *service.NewBaseBackgroundService("health"),
+		address:               viper.GetString(CfgHealthBind),
+		controller:            controller,
+		ctx:                   ctx,
+		errCh:                 make(chan error),
+	}, nil
+}
+
+func init() {
+	Flags.String(CfgHealthBind, "", "enable liveness ("+LivenessPath+") and readiness ("+ReadinessPath+") HTTP endpoints at given address")
+
+	_ = viper.BindPFlags(Flags)
+}
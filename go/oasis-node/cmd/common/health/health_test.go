@@ -0,0 +1,56 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/service"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+)
+
+func TestHandler(t *testing.T) {
+	require := require.New(t)
+
+	h := &healthService{
+		BaseBackgroundService: *service.NewBaseBackgroundService("health"),
+	}
+
+	for _, tc := range []struct {
+		status *control.HealthStatus
+		err    error
+		code   int
+	}{
+		{
+			control.NewHealthStatus([]control.HealthCheck{{Name: "a", Healthy: true}, {Name: "b", Healthy: true}}),
+			nil,
+			http.StatusOK,
+		},
+		{
+			control.NewHealthStatus([]control.HealthCheck{{Name: "a", Healthy: true}, {Name: "b", Reason: "not yet"}}),
+			nil,
+			http.StatusServiceUnavailable,
+		},
+		{nil, fmt.Errorf("node unresponsive"), http.StatusServiceUnavailable},
+	} {
+		handler := h.handler(func(context.Context) (*control.HealthStatus, error) {
+			return tc.status, tc.err
+		})
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+		require.Equal(tc.code, rec.Code, "status code")
+
+		if tc.err != nil {
+			continue
+		}
+		var status control.HealthStatus
+		err := json.Unmarshal(rec.Body.Bytes(), &status)
+		require.NoError(err, "Unmarshal")
+		require.EqualValues(*tc.status, status, "health status")
+	}
+}
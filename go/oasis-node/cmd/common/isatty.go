@@ -31,3 +31,47 @@ func Isatty(fd uintptr) bool {
 
 	return errno == 0
 }
+
+// disableEcho disables echoing of the input characters on the terminal
+// referred to by the provided file descriptor, and returns a function that
+// restores the previous terminal state.
+func disableEcho(fd uintptr) (func(), error) {
+	var attrs syscall.Termios
+	if _, _, errno := syscall.Syscall6(
+		syscall.SYS_IOCTL,
+		fd,
+		cmnSyscall.IoctlTermiosGetAttr,
+		uintptr(unsafe.Pointer(&attrs)),
+		0,
+		0,
+		0,
+	); errno != 0 {
+		return nil, errno
+	}
+
+	noEcho := attrs
+	noEcho.Lflag &^= syscall.ECHO
+	if _, _, errno := syscall.Syscall6(
+		syscall.SYS_IOCTL,
+		fd,
+		cmnSyscall.IoctlTermiosSetAttr,
+		uintptr(unsafe.Pointer(&noEcho)),
+		0,
+		0,
+		0,
+	); errno != 0 {
+		return nil, errno
+	}
+
+	return func() {
+		_, _, _ = syscall.Syscall6(
+			syscall.SYS_IOCTL,
+			fd,
+			cmnSyscall.IoctlTermiosSetAttr,
+			uintptr(unsafe.Pointer(&attrs)),
+			0,
+			0,
+			0,
+		)
+	}, nil
+}
@@ -14,6 +14,10 @@ const (
 	cfgLogFile  = "log.file"
 	cfgLogFmt   = "log.format"
 	cfgLogLevel = "log.level"
+
+	cfgLogRotationMaxSize    = "log.rotation.max_size"
+	cfgLogRotationInterval   = "log.rotation.interval"
+	cfgLogRotationMaxBackups = "log.rotation.max_backups"
 	// Custom log levels for modules are not supported by cobra.
 	// Use the config file (parsed by viper) instead.
 )
@@ -21,28 +25,36 @@ const (
 // LoggingFlags has the logging flags.
 var loggingFlags = flag.NewFlagSet("", flag.ContinueOnError)
 
-func initLogging() error {
-	logFile := viper.GetString(cfgLogFile)
-
+func parseLogLevels(cfg *viper.Viper) (logging.Level, map[string]logging.Level, error) {
 	var logLevel logging.Level
 	moduleLevels := map[string]logging.Level{}
-	if err := logLevel.Set(viper.GetString(cfgLogLevel)); err != nil {
-		if errDefault := logLevel.Set(viper.GetString(cfgLogLevel + ".default")); errDefault != nil {
-			return errDefault
+	if err := logLevel.Set(cfg.GetString(cfgLogLevel)); err != nil {
+		if errDefault := logLevel.Set(cfg.GetString(cfgLogLevel + ".default")); errDefault != nil {
+			return logLevel, nil, errDefault
 		}
 
-		for k, v := range viper.GetStringMapString(cfgLogLevel) {
+		for k, v := range cfg.GetStringMapString(cfgLogLevel) {
 			if k == "default" {
 				continue
 			}
 
 			var lvl logging.Level
 			if err = lvl.Set(v); err != nil {
-				return err
+				return logLevel, nil, err
 			}
 			moduleLevels[k] = lvl
 		}
 	}
+	return logLevel, moduleLevels, nil
+}
+
+func initLogging() error {
+	logFile := viper.GetString(cfgLogFile)
+
+	logLevel, moduleLevels, err := parseLogLevels(viper.GetViper())
+	if err != nil {
+		return err
+	}
 
 	var logFmt logging.Format
 	if err := logFmt.Set(viper.GetString(cfgLogFmt)); err != nil {
@@ -53,8 +65,11 @@ func initLogging() error {
 	if logFile != "" {
 		logFile = normalizePath(logFile)
 
-		var err error
-		if w, err = os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600); err != nil {
+		if w, err = logging.NewRotatingFile(logFile, logging.RotationConfig{
+			MaxSize:    int64(viper.GetSizeInBytes(cfgLogRotationMaxSize)),
+			Interval:   viper.GetDuration(cfgLogRotationInterval),
+			MaxBackups: viper.GetInt(cfgLogRotationMaxBackups),
+		}); err != nil {
 			return err
 		}
 	}
@@ -62,6 +77,28 @@ func initLogging() error {
 	return logging.Initialize(w, logFmt, logLevel, moduleLevels)
 }
 
+func initConsoleLogging() error {
+	logLevel, moduleLevels, err := parseLogLevels(viper.GetViper())
+	if err != nil {
+		return err
+	}
+
+	var logFmt logging.Format
+	if err = logFmt.Set(viper.GetString(cfgLogFmt)); err != nil {
+		return err
+	}
+
+	return logging.Initialize(os.Stderr, logFmt, logLevel, moduleLevels)
+}
+
+func reloadLogging(cfg *viper.Viper) error {
+	logLevel, moduleLevels, err := parseLogLevels(cfg)
+	if err != nil {
+		return err
+	}
+	return logging.SetLevels(logLevel, moduleLevels)
+}
+
 func initLoggingFlags() {
 	logFmt := logging.FmtLogfmt
 	logLevel := logging.LevelWarn
@@ -69,6 +106,9 @@ func initLoggingFlags() {
 	loggingFlags.String(cfgLogFile, "", "log file")
 	loggingFlags.Var(&logFmt, cfgLogFmt, "log format")
 	loggingFlags.Var(&logLevel, cfgLogLevel, "log level")
+	loggingFlags.String(cfgLogRotationMaxSize, "0", "rotate log file after it reaches the given size (0 disables)")
+	loggingFlags.Duration(cfgLogRotationInterval, 0, "rotate log file after the given interval (0 disables)")
+	loggingFlags.Int(cfgLogRotationMaxBackups, 0, "maximum number of rotated log files to keep (0 keeps all)")
 
 	_ = viper.BindPFlags(loggingFlags)
 }
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+var (
+	// badgerCollector exports the internal metrics of all BadgerDB instances in the process, as
+	// published by BadgerDB via expvar.
+	badgerCollector = collectors.NewExpvarCollector(map[string]*prometheus.Desc{
+		"badger_v3_disk_reads_total": prometheus.NewDesc(
+			"oasis_badger_disk_reads_total", "Number of BadgerDB disk reads.", nil, nil,
+		),
+		"badger_v3_disk_writes_total": prometheus.NewDesc(
+			"oasis_badger_disk_writes_total", "Number of BadgerDB disk writes.", nil, nil,
+		),
+		"badger_v3_read_bytes": prometheus.NewDesc(
+			"oasis_badger_read_bytes", "Number of bytes read by BadgerDB.", nil, nil,
+		),
+		"badger_v3_written_bytes": prometheus.NewDesc(
+			"oasis_badger_written_bytes", "Number of bytes written by BadgerDB.", nil, nil,
+		),
+		"badger_v3_gets_total": prometheus.NewDesc(
+			"oasis_badger_gets_total", "Number of BadgerDB gets.", nil, nil,
+		),
+		"badger_v3_puts_total": prometheus.NewDesc(
+			"oasis_badger_puts_total", "Number of BadgerDB puts.", nil, nil,
+		),
+		"badger_v3_blocked_puts_total": prometheus.NewDesc(
+			"oasis_badger_blocked_puts_total", "Number of BadgerDB puts blocked on the write channel.", nil, nil,
+		),
+		"badger_v3_memtable_gets_total": prometheus.NewDesc(
+			"oasis_badger_memtable_gets_total", "Number of BadgerDB memtable gets.", nil, nil,
+		),
+		"badger_v3_compactions_current": prometheus.NewDesc(
+			"oasis_badger_compactions_current", "Number of BadgerDB tables being compacted.", nil, nil,
+		),
+		"badger_v3_lsm_size_bytes": prometheus.NewDesc(
+			"oasis_badger_lsm_size_bytes", "Size of the BadgerDB LSM tree (bytes).", []string{"dir"}, nil,
+		),
+		"badger_v3_vlog_size_bytes": prometheus.NewDesc(
+			"oasis_badger_vlog_size_bytes", "Size of the BadgerDB value log (bytes).", []string{"dir"}, nil,
+		),
+		"badger_v3_pending_writes_total": prometheus.NewDesc(
+			"oasis_badger_pending_writes_total", "Number of pending BadgerDB writes.", []string{"dir"}, nil,
+		),
+	})
+
+	collectorsOnce sync.Once
+)
+
+// registerCollectors registers the additional standard collectors that are not updated by the
+// resource service.
+func registerCollectors() {
+	collectorsOnce.Do(func() {
+		prometheus.MustRegister(badgerCollector)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -13,9 +14,10 @@ import (
 )
 
 const (
-	MetricDiskUsageBytes   = "oasis_node_disk_usage_bytes"
-	MetricDiskReadBytes    = "oasis_node_disk_read_bytes"
-	MetricDiskWrittenBytes = "oasis_node_disk_written_bytes"
+	MetricDiskUsageBytes    = "oasis_node_disk_usage_bytes"
+	MetricDataDirUsageBytes = "oasis_node_datadir_usage_bytes"
+	MetricDiskReadBytes     = "oasis_node_disk_read_bytes"
+	MetricDiskWrittenBytes  = "oasis_node_disk_written_bytes"
 )
 
 var (
@@ -26,6 +28,14 @@ var (
 		},
 	)
 
+	dataDirUsageGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MetricDataDirUsageBytes,
+			Help: "Size of top-level datadir entries of the worker (bytes).",
+		},
+		[]string{"dir"},
+	)
+
 	diskIOReadBytesGauge = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Name: MetricDiskReadBytes,
@@ -40,7 +50,12 @@ var (
 		},
 	)
 
-	diskCollectors  = []prometheus.Collector{diskUsageGauge, diskIOReadBytesGauge, diskIOWrittenBytesGauge}
+	diskCollectors = []prometheus.Collector{
+		diskUsageGauge,
+		dataDirUsageGauge,
+		diskIOReadBytesGauge,
+		diskIOWrittenBytesGauge,
+	}
 	diskServiceOnce sync.Once
 )
 
@@ -57,17 +72,23 @@ func (d *diskCollector) Name() string {
 func (d *diskCollector) Update() error {
 	// Compute disk usage of datadir.
 	var duBytes int64
+	dirBytes := make(map[string]int64)
 	err := filepath.Walk(d.dataDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return fmt.Errorf("disk usage metric: failed to access file %s: %w", path, err)
 		}
 		duBytes += info.Size()
+		dirBytes[dataDirEntry(d.dataDir, path, info)] += info.Size()
 		return nil
 	})
 	if err != nil {
 		return fmt.Errorf("disk usage metric: failed to walk directory %s: %w", d.dataDir, err)
 	}
 	diskUsageGauge.Set(float64(duBytes))
+	dataDirUsageGauge.Reset()
+	for dir, size := range dirBytes {
+		dataDirUsageGauge.WithLabelValues(dir).Set(float64(size))
+	}
 
 	// Obtain process I/O info.
 	proc, err := procfs.NewProc(d.pid)
@@ -79,15 +100,30 @@ func (d *diskCollector) Update() error {
 		return fmt.Errorf("disk I/O metric: failed to obtain procIO object %d: %w", d.pid, err)
 	}
 
-	diskIOWrittenBytesGauge.Set(float64(procIO.ReadBytes))
-	diskIOReadBytesGauge.Set(float64(procIO.WriteBytes))
+	diskIOReadBytesGauge.Set(float64(procIO.ReadBytes))
+	diskIOWrittenBytesGauge.Set(float64(procIO.WriteBytes))
 
 	return nil
 }
 
+// dataDirEntry returns the top-level datadir directory that the given path belongs to. Files
+// directly in the datadir (and the datadir itself) are accounted under ".".
+func dataDirEntry(dataDir, path string, info os.FileInfo) string {
+	rel, err := filepath.Rel(dataDir, path)
+	if err != nil || rel == "." {
+		return "."
+	}
+	parts := strings.SplitN(filepath.ToSlash(rel), "/", 2)
+	if len(parts) == 1 && !info.IsDir() {
+		return "."
+	}
+	return parts[0]
+}
+
 // NewDiskService constructs a new disk usage and I/O service.
 //
-// This service will regularly compute the size of datadir folder and read I/O
+// This service will regularly compute the size of datadir folder (in total and
+// per top-level directory) and read I/O
 // info of the process.
 func NewDiskService() ResourceCollector {
 	ds := &diskCollector{
@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"runtime/pprof"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MetricGoroutines = "oasis_node_goroutines"
+
+	// goroutineModulePrefix is the package path prefix of oasis-core packages.
+	goroutineModulePrefix = "github.com/oasisprotocol/oasis-core/go/"
+	// goroutineServiceExternal is the service label of goroutines created by external packages.
+	goroutineServiceExternal = "external"
+	// goroutineServiceRuntime is the service label of goroutines not created by other goroutines
+	// (e.g., the main goroutine) or created by the Go runtime.
+	goroutineServiceRuntime = "runtime"
+)
+
+var (
+	goroutinesGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MetricGoroutines,
+			Help: "Number of goroutines by the package of the function that created them.",
+		},
+		[]string{"service"},
+	)
+
+	goroutineCollectors  = []prometheus.Collector{goroutinesGauge}
+	goroutineServiceOnce sync.Once
+)
+
+type goroutineCollector struct{}
+
+func (g *goroutineCollector) Name() string {
+	return "goroutines"
+}
+
+func (g *goroutineCollector) Update() error {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+		return fmt.Errorf("goroutine metric: failed to obtain goroutine stacks: %w", err)
+	}
+
+	counts := countGoroutinesByService(&buf)
+	goroutinesGauge.Reset()
+	for svc, n := range counts {
+		goroutinesGauge.WithLabelValues(svc).Set(float64(n))
+	}
+	return nil
+}
+
+// countGoroutinesByService counts goroutines in a goroutine stack dump, grouped by the package of
+// the function that created them.
+func countGoroutinesByService(dump *bytes.Buffer) map[string]int {
+	counts := make(map[string]int)
+	scanner := bufio.NewScanner(dump)
+	scanner.Buffer(nil, 1024*1024)
+
+	var inGoroutine, created bool
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "goroutine "):
+			inGoroutine, created = true, false
+		case line == "":
+			if inGoroutine && !created {
+				counts[goroutineServiceRuntime]++
+			}
+			inGoroutine = false
+		case strings.HasPrefix(line, "created by "):
+			if !inGoroutine {
+				continue
+			}
+			created = true
+			counts[goroutineService(strings.TrimPrefix(line, "created by "))]++
+		}
+	}
+	if inGoroutine && !created {
+		counts[goroutineServiceRuntime]++
+	}
+	return counts
+}
+
+// goroutineService returns the service label for the given creator function name, i.e. the
+// oasis-core package path of the function.
+func goroutineService(fn string) string {
+	// Strip the creating goroutine, if any (e.g., "pkg.fn in goroutine 1").
+	if idx := strings.Index(fn, " in goroutine "); idx >= 0 {
+		fn = fn[:idx]
+	}
+	switch {
+	case strings.HasPrefix(fn, "runtime."):
+		return goroutineServiceRuntime
+	case !strings.HasPrefix(fn, goroutineModulePrefix):
+		return goroutineServiceExternal
+	}
+	fn = strings.TrimPrefix(fn, goroutineModulePrefix)
+
+	// The package path ends at the first dot after the last slash.
+	lastSlash := strings.LastIndex(fn, "/")
+	if idx := strings.Index(fn[lastSlash+1:], "."); idx >= 0 {
+		return fn[:lastSlash+1+idx]
+	}
+	return fn
+}
+
+// NewGoroutineCollector constructs a new goroutine count collector.
+//
+// This collector will regularly count the goroutines of the process, grouped by the oasis-core
+// package that created them.
+func NewGoroutineCollector() ResourceCollector {
+	// Goroutine metrics are singletons per process. Ensure to register them only once.
+	goroutineServiceOnce.Do(func() {
+		prometheus.MustRegister(goroutineCollectors...)
+	})
+
+	return &goroutineCollector{}
+}
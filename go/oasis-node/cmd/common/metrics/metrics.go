@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -19,7 +20,6 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common/service"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
-	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/env"
 )
 
@@ -33,6 +33,7 @@ const (
 	MetricUp = "oasis_up"
 
 	MetricsJobTestRunner = "oasis-test-runner"
+	MetricsJobNode       = "oasis-node"
 
 	MetricsLabelGitBranch       = "git_branch"
 	MetricsLabelInstance        = "instance"
@@ -191,6 +192,12 @@ func (s *pushService) worker() {
 	for {
 		select {
 		case <-s.Quit():
+			// Push the final state of metrics before stopping.
+			if err := s.pusher.Push(); err != nil {
+				s.Logger.Warn("Push: final push failed",
+					"err", err,
+				)
+			}
 			return
 		case <-t.C:
 		}
@@ -243,8 +250,17 @@ func newPushService() (service.BackgroundService, error) {
 	if svc.jobName == "" {
 		return nil, fmt.Errorf("metrics: %s required for push mode", CfgMetricsJobName)
 	}
-	if svc.labels["instance"] == "" {
-		return nil, fmt.Errorf("metrics: at least 'instance' key should be set for %s. Provided labels: %v", CfgMetricsLabels, svc.labels)
+	if svc.labels[MetricsLabelInstance] == "" {
+		// Default to the host name so that metrics of different nodes pushed to the same
+		// gateway do not overwrite each other.
+		hostname, err := os.Hostname()
+		if err != nil || hostname == "" {
+			return nil, fmt.Errorf("metrics: at least '%s' key should be set for %s. Provided labels: %v", MetricsLabelInstance, CfgMetricsLabels, svc.labels)
+		}
+		svc.labels[MetricsLabelInstance] = hostname
+	}
+	if svc.interval <= 0 {
+		return nil, fmt.Errorf("metrics: %s must be positive for push mode", CfgMetricsInterval)
 	}
 
 	svc.initPusher(false)
@@ -259,11 +275,12 @@ func New(ctx context.Context) (service.BackgroundService, error) {
 	case MetricsModeNone:
 		return newStubService()
 	case MetricsModePull:
+		registerCollectors()
 		return newPullService(ctx)
+	case MetricsModePush:
+		registerCollectors()
+		return newPushService()
 	default:
-		if mode == MetricsModePush && flags.DebugDontBlameOasis() {
-			return newPushService()
-		}
 		return nil, fmt.Errorf("metrics: unsupported mode: '%v'", mode)
 	}
 }
@@ -316,17 +333,11 @@ func GetDefaultPushLabels(ti *env.ScenarioInstanceInfo) map[string]string {
 }
 
 func init() {
-	Flags.String(CfgMetricsMode, MetricsModeNone, "metrics mode: none, pull")
-	Flags.String(CfgMetricsAddr, "127.0.0.1:3000", "metrics pull address")
-
-	// MetricsModePush is a debug only option that is not officially
-	// supported, so hide the related config options.
-	Flags.String(CfgMetricsJobName, "", "metrics push job name")
-	Flags.StringToString(CfgMetricsLabels, map[string]string{}, "metrics push instance label")
-	Flags.Duration(CfgMetricsInterval, 5*time.Second, "metrics push interval")
-	_ = Flags.MarkHidden(CfgMetricsJobName)
-	_ = Flags.MarkHidden(CfgMetricsLabels)
-	_ = Flags.MarkHidden(CfgMetricsInterval)
+	Flags.String(CfgMetricsMode, MetricsModeNone, "metrics mode: none, pull, push")
+	Flags.String(CfgMetricsAddr, "127.0.0.1:3000", "metrics pull address or push gateway URL")
+	Flags.String(CfgMetricsJobName, MetricsJobNode, "metrics push job name")
+	Flags.StringToString(CfgMetricsLabels, map[string]string{}, "metrics push grouping labels (instance defaults to the host name)")
+	Flags.Duration(CfgMetricsInterval, 5*time.Second, "metrics push and resource collection interval")
 
 	_ = viper.BindPFlags(Flags)
 }
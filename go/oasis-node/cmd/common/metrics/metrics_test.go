@@ -21,3 +21,21 @@ func TestEscapeLabelCharacters(t *testing.T) {
 		require.EqualValues(tc.expected, EscapeLabelCharacters(tc.input))
 	}
 }
+
+func TestGoroutineService(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		{"github.com/oasisprotocol/oasis-core/go/worker/common/p2p.(*p2p).Start in goroutine 1", "worker/common/p2p"},
+		{"github.com/oasisprotocol/oasis-core/go/common/service.(*BaseBackgroundService).Start", "common/service"},
+		{"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd.Run", "oasis-node/cmd"},
+		{"google.golang.org/grpc.(*Server).Serve in goroutine 42", "external"},
+		{"os/signal.Notify.func1.1", "external"},
+		{"runtime.gcenable", "runtime"},
+	} {
+		require.EqualValues(tc.expected, goroutineService(tc.input), tc.input)
+	}
+}
@@ -0,0 +1,141 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	// CfgNetwork selects the named network profile.
+	CfgNetwork = "network"
+
+	// CfgNetworkProfiles configures the path to the network profiles file.
+	CfgNetworkProfiles = "network_profiles"
+)
+
+var (
+	networkFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	networkProfile *NetworkProfile
+)
+
+// NetworkProfile is a named set of network-specific settings, so that the
+// network to interact with can be selected by name.
+type NetworkProfile struct {
+	// Name is the name of the profile.
+	Name string `mapstructure:"-" json:"name"`
+	// ChainContext is the chain domain separation context (genesis document
+	// hash) of the network.
+	ChainContext string `mapstructure:"chain_context" json:"chain_context,omitempty"`
+	// Address is the gRPC address of the node to connect to.
+	Address string `mapstructure:"address" json:"address,omitempty"`
+}
+
+// VerifyChainContext verifies that the given chain context matches the one
+// configured in the network profile (if any).
+func (p *NetworkProfile) VerifyChainContext(chainContext string) error {
+	if p.ChainContext == "" || p.ChainContext == chainContext {
+		return nil
+	}
+	return fmt.Errorf("chain context mismatch for network '%s' (expected: %s got: %s)",
+		p.Name, p.ChainContext, chainContext,
+	)
+}
+
+// DefaultNetworkProfilesPath returns the default path of the network
+// profiles file.
+func DefaultNetworkProfilesPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "oasis", "networks.yml")
+}
+
+// LoadNetworkProfiles loads the network profiles from the given file, which
+// maps profile names to profiles.
+func LoadNetworkProfiles(path string) (map[string]*NetworkProfile, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read network profiles: %w", err)
+	}
+
+	profiles := make(map[string]*NetworkProfile)
+	if err := v.Unmarshal(&profiles); err != nil {
+		return nil, fmt.Errorf("malformed network profiles: %w", err)
+	}
+	for name, p := range profiles {
+		if p == nil {
+			return nil, fmt.Errorf("malformed network profiles: empty profile '%s'", name)
+		}
+		p.Name = name
+	}
+	return profiles, nil
+}
+
+// Network returns the selected network profile or nil if no network
+// profile has been selected.
+func Network() *NetworkProfile {
+	return networkProfile
+}
+
+// selectNetwork loads the network profiles from the given file and returns
+// the profile with the given name.
+func selectNetwork(name, path string) (*NetworkProfile, error) {
+	profiles, err := LoadNetworkProfiles(path)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := profiles[name]
+	if !ok {
+		var names []string
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown network '%s' (available: %s)", name, strings.Join(names, ", "))
+	}
+	if p.ChainContext == "" && p.Address == "" {
+		return nil, fmt.Errorf("network profile '%s' has neither a chain context nor an address", name)
+	}
+	return p, nil
+}
+
+func initNetwork() error {
+	networkProfile = nil
+
+	name := viper.GetString(CfgNetwork)
+	if name == "" {
+		return nil
+	}
+
+	path := viper.GetString(CfgNetworkProfiles)
+	if path == "" {
+		path = DefaultNetworkProfilesPath()
+	}
+	p, err := selectNetwork(name, path)
+	if err != nil {
+		return err
+	}
+	networkProfile = p
+
+	rootLog.Debug("using network profile",
+		"network", name,
+		"profiles", path,
+	)
+
+	return nil
+}
+
+func initNetworkFlags() {
+	networkFlags.String(CfgNetwork, "", "named network profile to use")
+	networkFlags.String(CfgNetworkProfiles, "", "path to the network profiles file (default: "+DefaultNetworkProfilesPath()+")")
+	_ = viper.BindPFlags(networkFlags)
+}
@@ -0,0 +1,55 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkProfiles(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	fn := filepath.Join(dir, "networks.yml")
+	err := os.WriteFile(fn, []byte(`mainnet:
+  chain_context: 53852332637bacb61b91b6411ab4095168ba02a50be4c3f82448438826f23898
+  address: unix:/node/data/internal.sock
+testnet:
+  address: unix:/testnet-node/data/internal.sock
+empty:
+  address: ""
+`), 0o600)
+	require.NoError(err, "WriteFile")
+
+	profiles, err := LoadNetworkProfiles(fn)
+	require.NoError(err, "LoadNetworkProfiles")
+	require.Len(profiles, 3)
+	require.Equal(&NetworkProfile{
+		Name:         "mainnet",
+		ChainContext: "53852332637bacb61b91b6411ab4095168ba02a50be4c3f82448438826f23898",
+		Address:      "unix:/node/data/internal.sock",
+	}, profiles["mainnet"])
+
+	_, err = LoadNetworkProfiles(filepath.Join(dir, "missing.yml"))
+	require.Error(err, "LoadNetworkProfiles should fail for missing files")
+
+	p, err := selectNetwork("testnet", fn)
+	require.NoError(err, "selectNetwork")
+	require.Equal("testnet", p.Name)
+	require.Equal("unix:/testnet-node/data/internal.sock", p.Address)
+	require.NoError(p.VerifyChainContext("anything"), "profiles without a chain context should match any chain")
+
+	p, err = selectNetwork("mainnet", fn)
+	require.NoError(err, "selectNetwork")
+	require.NoError(p.VerifyChainContext(p.ChainContext), "VerifyChainContext")
+	require.Error(p.VerifyChainContext("50304f98ddb656620ea817cc1446c401752a05a249b36c9b90dba4616829977a"),
+		"VerifyChainContext should fail for other chains",
+	)
+
+	_, err = selectNetwork("devnet", fn)
+	require.Error(err, "selectNetwork should fail for unknown networks")
+	_, err = selectNetwork("empty", fn)
+	require.Error(err, "selectNetwork should fail for profiles without a chain context and address")
+}
@@ -0,0 +1,117 @@
+package common
+
+import (
+	"fmt"
+
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	// CfgOutputFormat configures the output format of query and status commands.
+	CfgOutputFormat = "format"
+
+	// FormatText is the human-readable output format.
+	FormatText = "text"
+	// FormatJSON is the machine-readable output format.
+	FormatJSON = "json"
+
+	// JSONOutputVersion is the version of the JSON output structures. It is incremented on any
+	// backwards-incompatible change of the structures emitted by any command.
+	JSONOutputVersion = 1
+)
+
+var (
+	outputFormatFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	// deprecatedFormatFlags are the per-command output format flags that were
+	// superseded by the global output format flag.
+	deprecatedFormatFlags []string
+)
+
+// JSONOutput is the versioned envelope of all JSON command outputs.
+type JSONOutput struct {
+	// Version is the version of the output structures.
+	Version uint16 `json:"version"`
+	// Result is the command-specific result.
+	Result interface{} `json:"result"`
+}
+
+// OutputFormat returns the configured output format.
+//
+// Deprecated per-command output format flags take precedence over the global
+// output format flag when explicitly set.
+func OutputFormat() string {
+	for _, name := range deprecatedFormatFlags {
+		if viper.IsSet(name) {
+			return viper.GetString(name)
+		}
+	}
+	return viper.GetString(CfgOutputFormat)
+}
+
+// AddDeprecatedFormatFlag adds a deprecated per-command output format flag to
+// the given flag set, which acts as an alias for the global output format flag.
+//
+// The flag set still needs to be bound to viper by the caller.
+func AddDeprecatedFormatFlag(fs *flag.FlagSet, name string) {
+	fs.String(name, FormatText, "output format (text, json)")
+	_ = fs.MarkDeprecated(name, fmt.Sprintf("use --%s instead", CfgOutputFormat))
+	deprecatedFormatFlags = append(deprecatedFormatFlags, name)
+}
+
+// IsJSONOutput returns true iff the JSON output format is configured.
+func IsJSONOutput() bool {
+	return OutputFormat() == FormatJSON
+}
+
+// PrintJSONOutput prints the given command result wrapped in a versioned
+// JSON envelope.
+func PrintJSONOutput(result interface{}) error {
+	pretty, err := PrettyJSONMarshal(&JSONOutput{
+		Version: JSONOutputVersion,
+		Result:  result,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(pretty))
+	return nil
+}
+
+// PrintResult prints the result of a query or status command.
+//
+// In the JSON output format, the result is wrapped in a versioned JSON
+// envelope. Otherwise printText is called to print the human-readable
+// representation of the result or, if printText is nil, the result is
+// printed as pretty JSON.
+func PrintResult(result interface{}, printText func()) error {
+	switch {
+	case IsJSONOutput():
+		return PrintJSONOutput(result)
+	case printText != nil:
+		printText()
+		return nil
+	default:
+		pretty, err := PrettyJSONMarshal(result)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(pretty))
+		return nil
+	}
+}
+
+func initOutputFormat() error {
+	switch format := OutputFormat(); format {
+	case FormatText, FormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: '%s'", format)
+	}
+}
+
+func initOutputFormatFlags() {
+	outputFormatFlags.String(CfgOutputFormat, FormatText, "output format of query and status commands (text, json)")
+	_ = viper.BindPFlags(outputFormatFlags)
+}
@@ -0,0 +1,73 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	flag "github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+func TestLoadReloadableConfig(t *testing.T) {
+	require := require.New(t)
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.String("test.flag", "default", "")
+	fs.String("test.file", "default", "")
+	fs.StringSlice("test.slice", []string{"default"}, "")
+	fs.String("test.static", "default", "")
+	require.NoError(fs.Set("test.flag", "flag"), "Set")
+
+	fn := filepath.Join(t.TempDir(), "config.yml")
+	oldCfgFile := cfgFile
+	cfgFile = fn
+	defer func() { cfgFile = oldCfgFile }()
+
+	reloadable := []string{cfgLogLevel, "test.flag", "test.file", "test.slice"}
+
+	err := os.WriteFile(fn, []byte(`log:
+  level:
+    default: debug
+    test/module: info
+test:
+  flag: file
+  file: file
+  slice:
+    - a
+    - b
+  static: file
+`), 0o600)
+	require.NoError(err, "WriteFile")
+
+	cfg, err := loadReloadableConfig(fs, reloadable)
+	require.NoError(err, "loadReloadableConfig")
+	require.Equal("flag", cfg.GetString("test.flag"), "explicitly set flags should take precedence")
+	require.Equal("file", cfg.GetString("test.file"), "the config file should take precedence over defaults")
+	require.Equal([]string{"a", "b"}, cfg.GetStringSlice("test.slice"))
+	require.False(cfg.IsSet("test.static"), "non-reloadable keys should not be reloaded")
+
+	logLevel, moduleLevels, err := parseLogLevels(cfg)
+	require.NoError(err, "parseLogLevels")
+	require.Equal(logging.LevelDebug, logLevel)
+	require.Equal(map[string]logging.Level{"test/module": logging.LevelInfo}, moduleLevels)
+
+	// Keys removed from the config file should revert to their defaults.
+	err = os.WriteFile(fn, []byte(`log:
+  level: error
+`), 0o600)
+	require.NoError(err, "WriteFile")
+
+	cfg, err = loadReloadableConfig(fs, reloadable)
+	require.NoError(err, "loadReloadableConfig")
+	require.Equal("flag", cfg.GetString("test.flag"))
+	require.Equal("default", cfg.GetString("test.file"))
+	require.Equal([]string{"default"}, cfg.GetStringSlice("test.slice"))
+
+	logLevel, moduleLevels, err = parseLogLevels(cfg)
+	require.NoError(err, "parseLogLevels")
+	require.Equal(logging.LevelError, logLevel)
+	require.Empty(moduleLevels)
+}
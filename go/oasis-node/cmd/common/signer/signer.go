@@ -2,10 +2,15 @@
 package signer
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"os/exec"
 	"strings"
+	"sync"
+	"time"
 
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -30,10 +35,21 @@ const (
 	// It also contains the private keys of a signer if using a file backend.
 	CfgCLISignerDir = "signer.dir"
 
-	cfgSignerRemoteAddress    = "signer.remote.address"
-	cfgSignerRemoteClientCert = "signer.remote.client.certificate"
-	cfgSignerRemoteClientKey  = "signer.remote.client.key"
-	cfgSignerRemoteServerCert = "signer.remote.server.certificate"
+	// CfgSignerFilePassphraseFile is the file containing the passphrase used to encrypt the file
+	// signer private keys.
+	CfgSignerFilePassphraseFile = "signer.file.passphrase_file"
+	// CfgSignerFilePassphraseCommand is the command that outputs the passphrase used to encrypt
+	// the file signer private keys (e.g., by fetching it from a KMS).
+	CfgSignerFilePassphraseCommand = "signer.file.passphrase_command"
+	// CfgSignerFilePassphrasePrompt configures prompting for the passphrase used to encrypt the
+	// file signer private keys.
+	CfgSignerFilePassphrasePrompt = "signer.file.passphrase_prompt"
+
+	cfgSignerRemoteAddress     = "signer.remote.address"
+	cfgSignerRemoteClientCert  = "signer.remote.client.certificate"
+	cfgSignerRemoteClientKey   = "signer.remote.client.key"
+	cfgSignerRemoteServerCert  = "signer.remote.server.certificate"
+	cfgSignerRemoteHealthCheck = "signer.remote.health_check_interval"
 
 	cfgSignerCompositeBackends = "signer.composite.backends"
 
@@ -46,10 +62,19 @@ var (
 	// Flags has the signer related flags.
 	Flags = flag.NewFlagSet("", flag.ContinueOnError)
 
+	// FileFlags has the file signer related flags.
+	FileFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
 	// CLIFlags has the oasis-node specific signer related flags.
 	CLIFlags = flag.NewFlagSet("", flag.ContinueOnError)
 
+	// PromptPassphrase prompts the user for a passphrase.
+	PromptPassphrase func(prompt string) (string, error)
+
 	testingAllowMemory bool
+
+	filePassphraseLock sync.Mutex
+	filePassphrase     []byte
 )
 
 // Backend returns the configured signer backend name.
@@ -57,6 +82,67 @@ func Backend() string {
 	return viper.GetString(CfgSigner)
 }
 
+// FilePassphrase returns the passphrase used to encrypt the file signer private keys, either from
+// the configured file, the output of the configured command or by prompting the user. In case no
+// passphrase source is configured, nil is returned and the keys are not encrypted.
+//
+// The passphrase is only obtained once per process.
+func FilePassphrase() ([]byte, error) {
+	filePassphraseLock.Lock()
+	defer filePassphraseLock.Unlock()
+
+	if filePassphrase != nil {
+		return filePassphrase, nil
+	}
+
+	var (
+		raw     []byte
+		err     error
+		sources int
+	)
+	if fn := viper.GetString(CfgSignerFilePassphraseFile); fn != "" {
+		sources++
+		if raw, err = ioutil.ReadFile(fn); err != nil {
+			return nil, fmt.Errorf("failed to read signer passphrase file: %w", err)
+		}
+	}
+	if command := viper.GetString(CfgSignerFilePassphraseCommand); command != "" {
+		sources++
+		cmd := exec.Command("/bin/sh", "-c", command) // nolint: gosec
+		cmd.Stderr = os.Stderr
+		if raw, err = cmd.Output(); err != nil {
+			return nil, fmt.Errorf("failed to run signer passphrase command: %w", err)
+		}
+	}
+	if viper.GetBool(CfgSignerFilePassphrasePrompt) {
+		sources++
+		if PromptPassphrase == nil {
+			return nil, fmt.Errorf("prompting for the signer passphrase is not supported")
+		}
+		var passphrase string
+		if passphrase, err = PromptPassphrase("Signer passphrase"); err != nil {
+			return nil, fmt.Errorf("failed to prompt for signer passphrase: %w", err)
+		}
+		raw = []byte(passphrase)
+	}
+	switch sources {
+	case 0:
+		return nil, nil
+	case 1:
+	default:
+		return nil, fmt.Errorf("only one of %s, %s and %s may be set",
+			CfgSignerFilePassphraseFile, CfgSignerFilePassphraseCommand, CfgSignerFilePassphrasePrompt,
+		)
+	}
+
+	passphrase := bytes.TrimRight(raw, "\r\n")
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("empty signer passphrase")
+	}
+	filePassphrase = passphrase
+	return filePassphrase, nil
+}
+
 // CLIDirOrPwd returns the directory with the entity files, (and the signer
 // keys for file-based signer).
 //
@@ -85,10 +171,23 @@ func NewFactory(signerBackend, signerDir string, roles ...signature.SignerRole)
 	return doNewComposite(signerDir, roles...)
 }
 
+// NewFileFactory returns a file backed SignerFactory for the given directory, with the private
+// key encryption configured by flags.
+func NewFileFactory(signerDir string, roles ...signature.SignerRole) (signature.SignerFactory, error) {
+	passphrase, err := FilePassphrase()
+	if err != nil {
+		return nil, err
+	}
+	return fileSigner.NewFactory(&fileSigner.FactoryConfig{
+		Dir:        signerDir,
+		Passphrase: passphrase,
+	}, roles...)
+}
+
 func doNewFactory(signerBackend, signerDir string, roles ...signature.SignerRole) (signature.SignerFactory, error) {
 	switch signerBackend {
 	case fileSigner.SignerName:
-		return fileSigner.NewFactory(signerDir, roles...)
+		return NewFileFactory(signerDir, roles...)
 	case memorySigner.SignerName:
 		if !testingAllowMemory {
 			return nil, fmt.Errorf("memory signer backend is only for testing")
@@ -96,8 +195,13 @@ func doNewFactory(signerBackend, signerDir string, roles ...signature.SignerRole
 		return memorySigner.NewFactory(), nil
 	case remoteSigner.SignerName:
 		config := &remoteSigner.FactoryConfig{
-			Address: viper.GetString(cfgSignerRemoteAddress),
+			Address:             viper.GetString(cfgSignerRemoteAddress),
+			HealthCheckInterval: viper.GetDuration(cfgSignerRemoteHealthCheck),
 		}
+		if strings.HasPrefix(config.Address, remoteSigner.UnixSocketPrefix) {
+			return remoteSigner.NewFactory(config, roles...)
+		}
+
 		clientCert, err := tls.Load(
 			viper.GetString(cfgSignerRemoteClientCert),
 			viper.GetString(cfgSignerRemoteClientKey),
@@ -177,10 +281,17 @@ func doNewComposite(signerDir string, roles ...signature.SignerRole) (signature.
 
 func init() {
 	Flags.StringP(CfgSigner, "s", "file", "signer backend [file, plugin, remote, composite]")
-	Flags.String(cfgSignerRemoteAddress, "", "remote signer server address")
+	FileFlags.String(CfgSignerFilePassphraseFile, "", "path to file containing the file signer private key passphrase (enables key encryption)")
+	FileFlags.String(CfgSignerFilePassphraseCommand, "", "command that outputs the file signer private key passphrase (enables key encryption)")
+	FileFlags.Bool(CfgSignerFilePassphrasePrompt, false, "prompt for the file signer private key passphrase (enables key encryption)")
+	_ = viper.BindPFlags(FileFlags)
+
+	Flags.AddFlagSet(FileFlags)
+	Flags.String(cfgSignerRemoteAddress, "", "remote signer server address (prefix with unix: for a local socket)")
 	Flags.String(cfgSignerRemoteClientCert, "", "remote signer client certificate path")
 	Flags.String(cfgSignerRemoteClientKey, "", "remote signer client certificate key path")
 	Flags.String(cfgSignerRemoteServerCert, "", "remote signer server certificate path")
+	Flags.Duration(cfgSignerRemoteHealthCheck, 30*time.Second, "remote signer health check interval (0 disables)")
 	Flags.String(cfgSignerCompositeBackends, "", "composite signer backends")
 	Flags.String(cfgSignerPluginName, "", "plugin signer backend name")
 	Flags.String(cfgSignerPluginPath, "", "plugin signer binary path")
@@ -0,0 +1,135 @@
+// Package tracing implements the OpenTelemetry tracing service.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+
+	"github.com/oasisprotocol/oasis-core/go/common/service"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+)
+
+const (
+	// CfgOTLPEndpoint configures the OTLP (gRPC) endpoint traces are exported to. Tracing is
+	// disabled if no endpoint is configured.
+	CfgOTLPEndpoint = "tracing.otlp.endpoint"
+	// CfgOTLPInsecure disables TLS for the connection to the OTLP endpoint.
+	CfgOTLPInsecure = "tracing.otlp.insecure"
+	// CfgSampleRatio configures the ratio of traces that are sampled.
+	CfgSampleRatio = "tracing.sample_ratio"
+
+	serviceName = "oasis-node"
+
+	shutdownTimeout = 5 * time.Second
+)
+
+// Flags has the flags used by the tracing service.
+var Flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+// Config is the tracing service configuration.
+type Config struct {
+	// OTLPEndpoint is the OTLP (gRPC) endpoint traces are exported to.
+	OTLPEndpoint string
+	// OTLPInsecure disables TLS for the connection to the OTLP endpoint.
+	OTLPInsecure bool
+	// SampleRatio is the ratio of traces that are sampled.
+	SampleRatio float64
+}
+
+type tracingService struct {
+	service.BaseBackgroundService
+
+	cfg Config
+
+	provider *sdktrace.TracerProvider
+}
+
+func (t *tracingService) Start() error {
+	if t.cfg.OTLPEndpoint == "" {
+		return nil
+	}
+
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(t.cfg.OTLPEndpoint),
+	}
+	if t.cfg.OTLPInsecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	// The exporter connects lazily, so this does not block on the endpoint being available.
+	exporter, err := otlptracegrpc.New(context.Background(), opts...)
+	if err != nil {
+		return fmt.Errorf("tracing: failed to create OTLP exporter: %w", err)
+	}
+
+	t.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(t.cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceVersionKey.String(version.SoftwareVersion),
+		)),
+	)
+	otel.SetTracerProvider(t.provider)
+
+	t.Logger.Info("exporting traces via OTLP",
+		"endpoint", t.cfg.OTLPEndpoint,
+		"sample_ratio", t.cfg.SampleRatio,
+	)
+
+	return nil
+}
+
+func (t *tracingService) Stop() {
+	if t.provider == nil {
+		return
+	}
+
+	// Flush any pending spans.
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := t.provider.Shutdown(ctx); err != nil {
+		t.Logger.Error("failed to shut down tracer provider",
+			"err", err,
+		)
+	}
+	t.provider = nil
+}
+
+// NewWithConfig constructs a new tracing service with the given configuration.
+func NewWithConfig(cfg Config) (service.BackgroundService, error) {
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return nil, fmt.Errorf("tracing: sample ratio must be between 0 and 1 (got %f)", cfg.SampleRatio)
+	}
+
+	return &tracingService{
+		BaseBackgroundService: *service.NewBaseBackgroundService("tracing"),
+		cfg:                   cfg,
+	}, nil
+}
+
+// New constructs a new tracing service.
+func New() (service.BackgroundService, error) {
+	return NewWithConfig(Config{
+		OTLPEndpoint: viper.GetString(CfgOTLPEndpoint),
+		OTLPInsecure: viper.GetBool(CfgOTLPInsecure),
+		SampleRatio:  viper.GetFloat64(CfgSampleRatio),
+	})
+}
+
+func init() {
+	Flags.String(CfgOTLPEndpoint, "", "OTLP (gRPC) endpoint to export traces to (host:port, tracing disabled if empty)")
+	Flags.Bool(CfgOTLPInsecure, false, "disable TLS for the connection to the OTLP endpoint")
+	Flags.Float64(CfgSampleRatio, 1.0, "ratio of traces that are sampled")
+
+	_ = viper.BindPFlags(Flags)
+}
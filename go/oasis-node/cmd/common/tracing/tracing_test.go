@@ -0,0 +1,37 @@
+package tracing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingService(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewWithConfig(Config{SampleRatio: -0.1})
+	require.Error(err, "negative sample ratio should be rejected")
+	_, err = NewWithConfig(Config{SampleRatio: 1.5})
+	require.Error(err, "sample ratio above one should be rejected")
+
+	// Tracing is disabled without an endpoint.
+	svc, err := NewWithConfig(Config{SampleRatio: 1})
+	require.NoError(err, "NewWithConfig")
+	require.NoError(svc.Start(), "Start")
+	require.Nil(svc.(*tracingService).provider, "tracing should be disabled without an endpoint")
+	svc.Stop()
+
+	// The exporter connects lazily, so the endpoint need not be available.
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	svc, err = NewWithConfig(Config{
+		OTLPEndpoint: "127.0.0.1:4317",
+		OTLPInsecure: true,
+		SampleRatio:  0.5,
+	})
+	require.NoError(err, "NewWithConfig")
+	require.NoError(svc.Start(), "Start")
+	require.Equal(svc.(*tracingService).provider, otel.GetTracerProvider(), "the global tracer provider should be set")
+	svc.Stop()
+}
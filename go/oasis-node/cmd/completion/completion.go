@@ -0,0 +1,132 @@
+// Package completion implements the shell completion sub-command and the
+// dynamic completion helpers.
+package completion
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	"github.com/oasisprotocol/oasis-core/go/runtime/registry"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// CfgAddresses configures the account addresses offered when completing
+// account address arguments.
+const CfgAddresses = "completion.addresses"
+
+var (
+	completionCmd = &cobra.Command{
+		Use:       "completion (bash|zsh|fish)",
+		Short:     "generate shell completion scripts",
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		Args:      cobra.ExactValidArgs(1),
+		Run:       doCompletion,
+	}
+
+	logger = logging.GetLogger("cmd/completion")
+)
+
+func doCompletion(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	var err error
+	rootCmd := cmd.Root()
+	switch args[0] {
+	case "bash":
+		err = rootCmd.GenBashCompletionV2(os.Stdout, true)
+	case "zsh":
+		err = rootCmd.GenZshCompletion(os.Stdout)
+	case "fish":
+		err = rootCmd.GenFishCompletion(os.Stdout, true)
+	}
+	if err != nil {
+		logger.Error("failed to generate completion script",
+			"err", err,
+			"shell", args[0],
+		)
+		os.Exit(1)
+	}
+}
+
+// loadConfig loads the config file given on the command line being
+// completed, as completion runs before the config file is loaded.
+func loadConfig(cmd *cobra.Command) *viper.Viper {
+	v := viper.New()
+	if fn, _ := cmd.Flags().GetString(cmdCommon.CfgConfigFile); fn != "" {
+		v.SetConfigFile(fn)
+		_ = v.ReadInConfig()
+	}
+	return v
+}
+
+func filterCompletions(candidates []string, toComplete string) []string {
+	var completions []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, toComplete) {
+			completions = append(completions, c)
+		}
+	}
+	sort.Strings(completions)
+	return completions
+}
+
+// RuntimeIDs completes runtime identifiers of the runtimes configured as
+// supported in the config file.
+func RuntimeIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	runtimes, err := registry.ParseRuntimeMap(loadConfig(cmd).GetStringSlice(registry.CfgSupported))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var ids []string
+	for id := range runtimes {
+		ids = append(ids, id.String())
+	}
+	return filterCompletions(ids, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// AccountAddresses completes account addresses configured in the config
+// file.
+func AccountAddresses(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var addrs []string
+	for _, s := range loadConfig(cmd).GetStringSlice(CfgAddresses) {
+		var addr staking.Address
+		if err := addr.UnmarshalText([]byte(s)); err != nil {
+			continue
+		}
+		addrs = append(addrs, addr.String())
+	}
+	return filterCompletions(addrs, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// FirstArg limits the given completion function to the first positional
+// argument.
+func FirstArg(fn func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective)) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return fn(cmd, args, toComplete)
+	}
+}
+
+// RegisterFlag registers the given completion function for the given flag
+// of the command.
+func RegisterFlag(cmd *cobra.Command, name string, fn func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective)) {
+	if err := cmd.RegisterFlagCompletionFunc(name, fn); err != nil {
+		panic(fmt.Sprintf("completion: failed to register flag '%s': %s", name, err))
+	}
+}
+
+// Register registers the completion sub-command.
+func Register(parentCmd *cobra.Command) {
+	parentCmd.AddCommand(completionCmd)
+}
@@ -13,6 +13,7 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
@@ -21,6 +22,7 @@ import (
 	cmdConsensus "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/consensus"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
 )
 
 const (
@@ -37,9 +39,17 @@ var (
 	}
 
 	submitTxCmd = &cobra.Command{
-		Use:   "submit_tx",
-		Short: "Submit a pre-signed transaction",
-		Run:   doSubmitTx,
+		Use:     "submit_tx",
+		Aliases: []string{"broadcast"},
+		Short:   "Submit a pre-signed transaction",
+		Run:     doSubmitTx,
+	}
+
+	signTxCmd = &cobra.Command{
+		Use:     "sign_tx",
+		Aliases: []string{"sign"},
+		Short:   "Sign an unsigned transaction (does not require network access)",
+		Run:     doSignTx,
 	}
 
 	showTxCmd = &cobra.Command{
@@ -95,23 +105,41 @@ func loadTx() *transaction.SignedTransaction {
 	return &tx
 }
 
-func loadUnsignedTx() *transaction.Transaction {
-	rawUnsignedTx, err := ioutil.ReadFile(viper.GetString(cmdConsensus.CfgTxFile))
+func loadUnsignedTx(fn string) *cmdConsensus.UnsignedTransaction {
+	utx, err := cmdConsensus.LoadUnsignedTx(fn)
 	if err != nil {
-		logger.Error("failed to read raw serialized unsigned transaction",
+		logger.Error("failed to load unsigned transaction",
 			"err", err,
 		)
 		os.Exit(1)
 	}
+	return utx
+}
 
+// loadUnsignedTxForEstimate loads an unsigned transaction from the given file, accepting both
+// the JSON envelope and the raw CBOR-serialized transaction format.
+func loadUnsignedTxForEstimate(fn string) *transaction.Transaction {
+	utx, err := cmdConsensus.LoadUnsignedTx(fn)
+	if err == nil {
+		return utx.Transaction
+	}
+
+	// Fall back to the raw CBOR-serialized transaction format.
+	rawUnsignedTx, rerr := ioutil.ReadFile(fn)
+	if rerr != nil {
+		logger.Error("failed to read raw serialized unsigned transaction",
+			"err", rerr,
+		)
+		os.Exit(1)
+	}
 	var tx transaction.Transaction
-	if err = cbor.Unmarshal(rawUnsignedTx, &tx); err != nil {
-		logger.Error("failed to parse serialized unsigned transaction",
+	if rerr = cbor.Unmarshal(rawUnsignedTx, &tx); rerr != nil {
+		logger.Error("failed to load unsigned transaction",
 			"err", err,
+			"cbor_err", rerr,
 		)
 		os.Exit(1)
 	}
-
 	return &tx
 }
 
@@ -133,6 +161,23 @@ func doSubmitTx(cmd *cobra.Command, args []string) {
 	}
 }
 
+func doSignTx(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	cmdConsensus.AssertTxFileOK()
+	utx := loadUnsignedTx(viper.GetString(cmdConsensus.CfgTxUnsignedFile))
+
+	// Use the chain context captured in the unsigned transaction instead of the genesis
+	// document, which may not be available on an offline machine.
+	signature.SetChainContext(utx.ChainContext)
+
+	ctx := context.Background()
+	fmt.Printf("Chain context: %s\n", utx.ChainContext)
+	cmdConsensus.SignAndSaveTx(ctx, utx.Transaction, nil)
+}
+
 func doShowTx(cmd *cobra.Command, args []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
@@ -149,6 +194,11 @@ func doShowTx(cmd *cobra.Command, args []string) {
 	sigTx.PrettyPrint(ctx, "", os.Stdout)
 }
 
+// gasEstimate is the result of the estimate_gas command.
+type gasEstimate struct {
+	Gas transaction.Gas `json:"gas"`
+}
+
 func doEstimateGas(cmd *cobra.Command, args []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
@@ -158,7 +208,7 @@ func doEstimateGas(cmd *cobra.Command, args []string) {
 	defer conn.Close()
 
 	req := consensus.EstimateGasRequest{
-		Transaction: loadUnsignedTx(),
+		Transaction: loadUnsignedTxForEstimate(viper.GetString(cmdConsensus.CfgTxFile)),
 	}
 	if err := req.Signer.UnmarshalText([]byte(signerPub)); err != nil {
 		logger.Error("failed to unmarshal signer public key",
@@ -174,7 +224,14 @@ func doEstimateGas(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(1)
 	}
-	fmt.Println(gas)
+	if err = cmdCommon.PrintResult(&gasEstimate{Gas: gas}, func() {
+		fmt.Println(gas)
+	}); err != nil {
+		logger.Error("failed to print gas estimate",
+			"err", err,
+		)
+		os.Exit(1)
+	}
 }
 
 func doNextBlockState(cmd *cobra.Command, args []string) {
@@ -193,20 +250,19 @@ func doNextBlockState(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(128)
 	}
-	prettyStatus, err := cmdCommon.PrettyJSONMarshal(state)
-	if err != nil {
-		logger.Error("failed to get pretty JSON of next block state status",
+	if err = cmdCommon.PrintResult(state, nil); err != nil {
+		logger.Error("failed to print next block state status",
 			"err", err,
 		)
 		os.Exit(1)
 	}
-	fmt.Println(string(prettyStatus))
 }
 
 // Register registers the consensus sub-command and all of it's children.
 func Register(parentCmd *cobra.Command) {
 	for _, v := range []*cobra.Command{
 		submitTxCmd,
+		signTxCmd,
 		showTxCmd,
 		estimateGasCmd,
 		nextBlockStateCmd,
@@ -217,6 +273,13 @@ func Register(parentCmd *cobra.Command) {
 	submitTxCmd.Flags().AddFlagSet(cmdConsensus.TxFileFlags)
 	submitTxCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
 
+	signTxCmd.Flags().AddFlagSet(cmdConsensus.TxUnsignedFileFlags)
+	signTxCmd.Flags().AddFlagSet(cmdConsensus.TxFileFlags)
+	signTxCmd.Flags().AddFlagSet(cmdFlags.DebugTestEntityFlags)
+	signTxCmd.Flags().AddFlagSet(cmdFlags.AssumeYesFlag)
+	signTxCmd.Flags().AddFlagSet(cmdSigner.Flags)
+	signTxCmd.Flags().AddFlagSet(cmdSigner.CLIFlags)
+
 	showTxCmd.Flags().AddFlagSet(cmdConsensus.TxFileFlags)
 	showTxCmd.Flags().AddFlagSet(cmdFlags.GenesisFileFlags)
 
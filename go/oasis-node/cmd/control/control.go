@@ -7,19 +7,36 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/completion"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
 var (
-	shutdownWait = false
+	shutdownWait              = false
+	shutdownDrain             bool
+	shutdownDeregister        bool
+	shutdownTimeout           time.Duration
+	shutdownDeregisterTimeout time.Duration
+
+	p2pBanDuration time.Duration
+	p2pBanReason   string
+
+	profileRate   int
+	profileOutput string
+
+	statusWatch         bool
+	statusWatchInterval time.Duration
 
 	controlCmd = &cobra.Command{
 		Use:   "control",
@@ -38,6 +55,18 @@ var (
 		Run:   doWaitSync,
 	}
 
+	controlLivenessCmd = &cobra.Command{
+		Use:   "liveness",
+		Short: "exit with 0 if the node is alive, 1 if not",
+		Run:   doLiveness,
+	}
+
+	controlReadinessCmd = &cobra.Command{
+		Use:   "readiness",
+		Short: "exit with 0 if the node is ready to serve, 1 if not",
+		Run:   doReadiness,
+	}
+
 	controlShutdownCmd = &cobra.Command{
 		Use:   "shutdown",
 		Short: "request node shutdown on next epoch transition",
@@ -57,12 +86,109 @@ var (
 		Run:   doCancelUpgrade,
 	}
 
+	controlUpgradeDryRunCmd = &cobra.Command{
+		Use:   "upgrade-dry-run <upgrade-descriptor>",
+		Short: "validate a pending upgrade against a copy of the consensus state",
+		Args:  cobra.ExactArgs(1),
+		Run:   doUpgradeDryRun,
+	}
+
 	controlStatusCmd = &cobra.Command{
 		Use:   "status",
 		Short: "show node status",
 		Run:   doStatus,
 	}
 
+	controlP2PPeersCmd = &cobra.Command{
+		Use:   "p2p-peers",
+		Short: "show the status of connected, registered and banned P2P peers",
+		Run:   doP2PPeers,
+	}
+
+	controlP2PBanCmd = &cobra.Command{
+		Use:   "p2p-ban <p2p-public-key>",
+		Short: "ban a P2P peer",
+		Args:  cobra.ExactArgs(1),
+		Run:   doP2PBan,
+	}
+
+	controlP2PUnbanCmd = &cobra.Command{
+		Use:   "p2p-unban <p2p-public-key>",
+		Short: "lift the ban of a P2P peer",
+		Args:  cobra.ExactArgs(1),
+		Run:   doP2PUnban,
+	}
+
+	controlRuntimePauseCmd = &cobra.Command{
+		Use:   "runtime-pause <runtime-id>",
+		Short: "stop accepting new transactions and batches for a runtime",
+		Args:  cobra.ExactArgs(1),
+		Run:   doRuntimePause,
+	}
+
+	controlRuntimeResumeCmd = &cobra.Command{
+		Use:   "runtime-resume <runtime-id>",
+		Short: "resume a paused runtime",
+		Args:  cobra.ExactArgs(1),
+		Run:   doRuntimeResume,
+	}
+
+	controlRuntimePruneHistoryCmd = &cobra.Command{
+		Use:   "runtime-prune-history <runtime-id>",
+		Short: "prune a runtime's block history according to the retention policy",
+		Args:  cobra.ExactArgs(1),
+		Run:   doRuntimePruneHistory,
+	}
+
+	controlSetLogLevelCmd = &cobra.Command{
+		Use:   "set-log-level <module> <level>",
+		Short: "override the log level of a logging module",
+		Args:  cobra.ExactArgs(2),
+		Run:   doSetLogLevel,
+	}
+
+	controlResetLogLevelCmd = &cobra.Command{
+		Use:   "reset-log-level <module>",
+		Short: "remove the log level override of a logging module",
+		Args:  cobra.ExactArgs(1),
+		Run:   doResetLogLevel,
+	}
+
+	controlRotateCertsCmd = &cobra.Command{
+		Use:   "rotate-certs",
+		Short: "rotate the node's TLS certificates and re-register",
+		Run:   doRotateCerts,
+	}
+
+	controlProfileEnableCmd = &cobra.Command{
+		Use:       "profile-enable <cpu|trace|block|mutex>",
+		Short:     "start collecting a profile",
+		ValidArgs: []string{control.ProfileCPU, control.ProfileTrace, control.ProfileBlock, control.ProfileMutex},
+		Args:      cobra.ExactValidArgs(1),
+		Run:       doProfileEnable,
+	}
+
+	controlProfileDisableCmd = &cobra.Command{
+		Use:       "profile-disable <cpu|trace|block|mutex>",
+		Short:     "stop collecting a profile",
+		ValidArgs: []string{control.ProfileCPU, control.ProfileTrace, control.ProfileBlock, control.ProfileMutex},
+		Args:      cobra.ExactValidArgs(1),
+		Run:       doProfileDisable,
+	}
+
+	controlProfileFetchCmd = &cobra.Command{
+		Use:   "profile-fetch <profile>",
+		Short: "fetch a profile collected by the node",
+		Args:  cobra.ExactArgs(1),
+		Run:   doProfileFetch,
+	}
+
+	controlReloadConfigCmd = &cobra.Command{
+		Use:   "reload-config",
+		Short: "reload the subset of the node configuration that can be changed at runtime",
+		Run:   doReloadConfig,
+	}
+
 	logger = logging.GetLogger("cmd/control")
 )
 
@@ -85,6 +211,12 @@ func DoConnect(cmd *cobra.Command) (*grpc.ClientConn, control.NodeController) {
 	return conn, client
 }
 
+// syncStatus is the result of the is-synced command.
+type syncStatus struct {
+	// Synced is true iff the node completed initial syncing.
+	Synced bool `json:"synced"`
+}
+
 func doIsSynced(cmd *cobra.Command, args []string) {
 	conn, client := DoConnect(cmd)
 	defer conn.Close()
@@ -99,11 +231,19 @@ func doIsSynced(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(128)
 	}
-	if synced {
-		fmt.Println("node completed initial syncing")
-		os.Exit(0)
-	} else {
-		fmt.Println("node has not completed initial syncing")
+	if err = cmdCommon.PrintResult(&syncStatus{Synced: synced}, func() {
+		if synced {
+			fmt.Println("node completed initial syncing")
+		} else {
+			fmt.Println("node has not completed initial syncing")
+		}
+	}); err != nil {
+		logger.Error("failed to print synced status",
+			"err", err,
+		)
+		os.Exit(128)
+	}
+	if !synced {
 		os.Exit(1)
 	}
 }
@@ -124,24 +264,75 @@ func doWaitSync(cmd *cobra.Command, args []string) {
 	}
 }
 
-func doShutdown(cmd *cobra.Command, args []string) {
+func doHealthCheck(cmd *cobra.Command, kind string, check func(control.NodeController) (*control.HealthStatus, error)) {
 	conn, client := DoConnect(cmd)
 	defer conn.Close()
 
-	err := client.RequestShutdown(context.Background(), shutdownWait)
+	logger.Debug("querying " + kind + " status")
+
+	status, err := check(client)
 	if err != nil {
-		logger.Error("failed to send shutdown request",
+		logger.Error("failed to query "+kind+" status",
 			"err", err,
 		)
+		os.Exit(128)
+	}
+	if err = cmdCommon.PrintResult(status, func() {
+		for _, c := range status.Checks {
+			if c.Healthy {
+				fmt.Printf("%s: ok\n", c.Name)
+			} else {
+				fmt.Printf("%s: FAILED (%s)\n", c.Name, c.Reason)
+			}
+		}
+	}); err != nil {
+		logger.Error("failed to print "+kind+" status",
+			"err", err,
+		)
+		os.Exit(128)
+	}
+	if !status.Healthy {
 		os.Exit(1)
 	}
 }
 
-func doUpgradeBinary(cmd *cobra.Command, args []string) {
+func doLiveness(cmd *cobra.Command, args []string) {
+	doHealthCheck(cmd, "liveness", func(client control.NodeController) (*control.HealthStatus, error) {
+		return client.CheckLiveness(context.Background())
+	})
+}
+
+func doReadiness(cmd *cobra.Command, args []string) {
+	doHealthCheck(cmd, "readiness", func(client control.NodeController) (*control.HealthStatus, error) {
+		return client.CheckReadiness(context.Background())
+	})
+}
+
+func doShutdown(cmd *cobra.Command, args []string) {
 	conn, client := DoConnect(cmd)
 	defer conn.Close()
 
-	descriptorBytes, err := ioutil.ReadFile(args[0])
+	var err error
+	if shutdownDrain {
+		err = client.Shutdown(context.Background(), &control.ShutdownRequest{
+			Wait:              shutdownWait,
+			Deregister:        shutdownDeregister,
+			Timeout:           shutdownTimeout,
+			DeregisterTimeout: shutdownDeregisterTimeout,
+		})
+	} else {
+		err = client.RequestShutdown(context.Background(), shutdownWait)
+	}
+	if err != nil {
+		logger.Error("failed to send shutdown request",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func loadUpgradeDescriptor(fn string) *upgrade.Descriptor {
+	descriptorBytes, err := ioutil.ReadFile(fn)
 	if err != nil {
 		logger.Error("failed to read upgrade descriptor",
 			"err", err,
@@ -156,15 +347,22 @@ func doUpgradeBinary(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(1)
 	}
+	return &desc
+}
 
-	if err = desc.ValidateBasic(); err != nil {
+func doUpgradeBinary(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	desc := loadUpgradeDescriptor(args[0])
+	if err := desc.ValidateBasic(); err != nil {
 		logger.Error("submitted upgrade descriptor is not valid",
 			"err", err,
 		)
 		os.Exit(1)
 	}
 
-	if err = client.UpgradeBinary(context.Background(), &desc); err != nil {
+	if err := client.UpgradeBinary(context.Background(), desc); err != nil {
 		logger.Error("error while sending upgrade descriptor to the node",
 			"err", err,
 		)
@@ -181,35 +379,61 @@ func doCancelUpgrade(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	descriptorBytes, err := ioutil.ReadFile(args[0])
-	if err != nil {
-		logger.Error("failed to read upgrade descriptor",
+	desc := loadUpgradeDescriptor(args[0])
+	if err := client.CancelUpgrade(context.Background(), desc); err != nil {
+		logger.Error("failed to send upgrade cancellation request",
 			"err", err,
 		)
 		os.Exit(1)
 	}
+}
 
-	var desc upgrade.Descriptor
-	if err = json.Unmarshal(descriptorBytes, &desc); err != nil {
-		logger.Error("can't parse upgrade descriptor",
+func doUpgradeDryRun(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	desc := loadUpgradeDescriptor(args[0])
+	result, err := client.DryRunUpgrade(context.Background(), desc)
+	if err != nil {
+		logger.Error("failed to perform upgrade dry run",
 			"err", err,
 		)
 		os.Exit(1)
 	}
 
-	err = client.CancelUpgrade(context.Background(), &desc)
-	if err != nil {
-		logger.Error("failed to send upgrade cancellation request",
+	if err = cmdCommon.PrintResult(result, func() {
+		fmt.Printf("Height:     %d\n", result.Height)
+		if result.Error != "" {
+			fmt.Printf("Error:      %s\n", result.Error)
+			return
+		}
+		fmt.Printf("State root: %s\n", result.StateRoot)
+	}); err != nil {
+		logger.Error("failed to print upgrade dry run result",
 			"err", err,
 		)
 		os.Exit(1)
 	}
+	if result.Error != "" {
+		os.Exit(1)
+	}
 }
 
 func doStatus(cmd *cobra.Command, args []string) {
+	if statusWatch {
+		if err := validateWatchInterval(statusWatchInterval); err != nil {
+			cmdCommon.EarlyLogAndExit(err)
+		}
+	}
+
 	conn, client := DoConnect(cmd)
 	defer conn.Close()
 
+	if statusWatch {
+		watchStatus(client)
+		return
+	}
+
 	logger.Debug("querying status")
 
 	// Use background context to block until the result comes in.
@@ -220,14 +444,242 @@ func doStatus(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(128)
 	}
-	prettyStatus, err := cmdCommon.PrettyJSONMarshal(status)
+	if err = cmdCommon.PrintResult(status, nil); err != nil {
+		logger.Error("failed to print node status",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doP2PPeers(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	peers, err := client.GetP2PPeers(context.Background())
+	if err != nil {
+		logger.Error("failed to query P2P peers",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err = cmdCommon.PrintResult(peers, nil); err != nil {
+		logger.Error("failed to print P2P peers",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func parseP2PPublicKey(raw string) signature.PublicKey {
+	var id signature.PublicKey
+	if err := id.UnmarshalText([]byte(raw)); err != nil {
+		logger.Error("malformed P2P public key",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	return id
+}
+
+func doP2PBan(cmd *cobra.Command, args []string) {
+	id := parseP2PPublicKey(args[0])
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	err := client.BanP2PPeer(context.Background(), &control.BanP2PPeerRequest{
+		ID:       id,
+		Duration: p2pBanDuration,
+		Reason:   p2pBanReason,
+	})
+	if err != nil {
+		logger.Error("failed to ban P2P peer",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doP2PUnban(cmd *cobra.Command, args []string) {
+	id := parseP2PPublicKey(args[0])
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err := client.UnbanP2PPeer(context.Background(), id); err != nil {
+		logger.Error("failed to unban P2P peer",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func parseRuntimeID(raw string) common.Namespace {
+	var runtimeID common.Namespace
+	if err := runtimeID.UnmarshalHex(raw); err != nil {
+		logger.Error("malformed runtime identifier",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	return runtimeID
+}
+
+func doRuntimePause(cmd *cobra.Command, args []string) {
+	runtimeID := parseRuntimeID(args[0])
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err := client.PauseRuntime(context.Background(), runtimeID); err != nil {
+		logger.Error("failed to pause runtime",
+			"err", err,
+			"runtime_id", runtimeID,
+		)
+		os.Exit(1)
+	}
+}
+
+func doRuntimeResume(cmd *cobra.Command, args []string) {
+	runtimeID := parseRuntimeID(args[0])
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err := client.ResumeRuntime(context.Background(), runtimeID); err != nil {
+		logger.Error("failed to resume runtime",
+			"err", err,
+			"runtime_id", runtimeID,
+		)
+		os.Exit(1)
+	}
+}
+
+func doRuntimePruneHistory(cmd *cobra.Command, args []string) {
+	runtimeID := parseRuntimeID(args[0])
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err := client.PruneRuntimeHistory(context.Background(), runtimeID); err != nil {
+		logger.Error("failed to prune runtime history",
+			"err", err,
+			"runtime_id", runtimeID,
+		)
+		os.Exit(1)
+	}
+}
+
+func doSetLogLevel(cmd *cobra.Command, args []string) {
+	var lvl logging.Level
+	if err := lvl.Set(args[1]); err != nil {
+		logger.Error("malformed log level",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err := client.SetLogLevel(context.Background(), &control.SetLogLevelRequest{
+		Module: args[0],
+		Level:  &lvl,
+	}); err != nil {
+		logger.Error("failed to set log level",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doResetLogLevel(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err := client.SetLogLevel(context.Background(), &control.SetLogLevelRequest{
+		Module: args[0],
+	}); err != nil {
+		logger.Error("failed to reset log level",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doRotateCerts(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err := client.RotateCertificates(context.Background()); err != nil {
+		logger.Error("failed to rotate TLS certificates",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doSetProfiling(cmd *cobra.Command, req *control.SetProfilingRequest) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err := client.SetProfiling(context.Background(), req); err != nil {
+		logger.Error("failed to configure profiling",
+			"err", err,
+			"profile", req.Profile,
+		)
+		os.Exit(1)
+	}
+}
+
+func doProfileEnable(cmd *cobra.Command, args []string) {
+	doSetProfiling(cmd, &control.SetProfilingRequest{
+		Profile: args[0],
+		Enabled: true,
+		Rate:    profileRate,
+	})
+}
+
+func doProfileDisable(cmd *cobra.Command, args []string) {
+	doSetProfiling(cmd, &control.SetProfilingRequest{
+		Profile: args[0],
+		Enabled: false,
+	})
+}
+
+func doProfileFetch(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	profile, err := client.GetProfile(context.Background(), &control.GetProfileRequest{Profile: args[0]})
 	if err != nil {
-		logger.Error("failed to get pretty JSON of node status",
+		logger.Error("failed to fetch profile",
+			"err", err,
+			"profile", args[0],
+		)
+		os.Exit(1)
+	}
+
+	if err = ioutil.WriteFile(profileOutput, profile, 0o600); err != nil {
+		logger.Error("failed to write profile",
+			"err", err,
+			"output", profileOutput,
+		)
+		os.Exit(1)
+	}
+}
+
+func doReloadConfig(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err := client.ReloadConfig(context.Background()); err != nil {
+		logger.Error("failed to reload node configuration",
 			"err", err,
 		)
 		os.Exit(1)
 	}
-	fmt.Println(string(prettyStatus))
 }
 
 // Register registers the client sub-command and all of it's children.
@@ -235,12 +687,46 @@ func Register(parentCmd *cobra.Command) {
 	controlCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
 
 	controlShutdownCmd.Flags().BoolVarP(&shutdownWait, "wait", "w", false, "wait for the node to finish shutdown")
+	controlShutdownCmd.Flags().BoolVar(&shutdownDrain, "drain", false, "finish in-flight runtime work and shut down without waiting for the next epoch")
+	controlShutdownCmd.Flags().BoolVar(&shutdownDeregister, "deregister", false, "deregister the node before draining (with --drain)")
+	controlShutdownCmd.Flags().DurationVar(&shutdownTimeout, "timeout", control.DefaultShutdownTimeout, "time to drain before shutting down anyway (with --drain)")
+	controlShutdownCmd.Flags().DurationVar(&shutdownDeregisterTimeout, "deregister-timeout", control.DefaultDeregisterTimeout, "time to wait for the deregistration before draining anyway (with --drain and --deregister)")
+	controlStatusCmd.Flags().BoolVar(&statusWatch, "watch", false, "continuously show node status changes until interrupted")
+	controlStatusCmd.Flags().DurationVar(&statusWatchInterval, "interval", time.Second, "status polling interval in watch mode")
+	controlRuntimeStatsCmd.Flags().Uint64Var(&runtimeStatsFromRound, "from-round", 0, "first round to examine (default: 100 rounds before the last round)")
+	controlRuntimeStatsCmd.Flags().Uint64Var(&runtimeStatsToRound, "to-round", 0, "last round to examine (default: latest round)")
+	controlRuntimeStatsCmd.ValidArgsFunction = completion.FirstArg(completion.RuntimeIDs)
+	controlRuntimePauseCmd.ValidArgsFunction = completion.FirstArg(completion.RuntimeIDs)
+	controlRuntimeResumeCmd.ValidArgsFunction = completion.FirstArg(completion.RuntimeIDs)
+	controlRuntimePruneHistoryCmd.ValidArgsFunction = completion.FirstArg(completion.RuntimeIDs)
+	controlProfileEnableCmd.Flags().IntVar(&profileRate, "rate", 0, "block profile rate or mutex profile fraction (default: sample all events)")
+	controlProfileFetchCmd.Flags().StringVarP(&profileOutput, "output", "o", "", "file to write the profile to")
+	_ = controlProfileFetchCmd.MarkFlagRequired("output")
+	controlP2PBanCmd.Flags().DurationVar(&p2pBanDuration, "duration", 0, "ban duration (if not set, the node's configured default is used)")
+	controlP2PBanCmd.Flags().StringVar(&p2pBanReason, "reason", "manual ban", "reason for the ban")
 
 	controlCmd.AddCommand(controlIsSyncedCmd)
 	controlCmd.AddCommand(controlWaitSyncCmd)
+	controlCmd.AddCommand(controlLivenessCmd)
+	controlCmd.AddCommand(controlReadinessCmd)
 	controlCmd.AddCommand(controlShutdownCmd)
 	controlCmd.AddCommand(controlUpgradeBinaryCmd)
 	controlCmd.AddCommand(controlCancelUpgradeCmd)
+	controlCmd.AddCommand(controlUpgradeDryRunCmd)
 	controlCmd.AddCommand(controlStatusCmd)
+	controlCmd.AddCommand(controlRuntimeStatsCmd)
+	controlCmd.AddCommand(controlRuntimePauseCmd)
+	controlCmd.AddCommand(controlRuntimeResumeCmd)
+	controlCmd.AddCommand(controlRuntimePruneHistoryCmd)
+	controlCmd.AddCommand(controlP2PPeersCmd)
+	controlCmd.AddCommand(controlP2PBanCmd)
+	controlCmd.AddCommand(controlP2PUnbanCmd)
+	controlCmd.AddCommand(controlSetLogLevelCmd)
+	controlCmd.AddCommand(controlResetLogLevelCmd)
+	controlCmd.AddCommand(controlReloadConfigCmd)
+	controlCmd.AddCommand(controlRotateCertsCmd)
+	controlCmd.AddCommand(controlProfileEnableCmd)
+	controlCmd.AddCommand(controlProfileDisableCmd)
+	controlCmd.AddCommand(controlProfileFetchCmd)
 	parentCmd.AddCommand(controlCmd)
 }
@@ -0,0 +1,455 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+)
+
+// defaultRuntimeStatsRounds is the number of rounds examined when no starting round is given.
+const defaultRuntimeStatsRounds = 100
+
+var (
+	runtimeStatsFromRound uint64
+	runtimeStatsToRound   uint64
+
+	controlRuntimeStatsCmd = &cobra.Command{
+		Use:   "runtime-stats <runtime-id>",
+		Short: "show runtime round and commitment statistics over a range of rounds",
+		Args:  cobra.ExactArgs(1),
+		Run:   doRuntimeStats,
+	}
+)
+
+// entityRuntimeStats are the per-entity commitment statistics.
+type entityRuntimeStats struct {
+	// RoundsElected is the number of finalized rounds in which the entity's nodes were primary
+	// executor workers.
+	RoundsElected uint64 `json:"rounds_elected"`
+	// RoundsBackup is the number of finalized rounds in which the entity's nodes were backup
+	// executor workers.
+	RoundsBackup uint64 `json:"rounds_backup"`
+	// RoundsGood is the number of commitments which contributed to finalized rounds.
+	RoundsGood uint64 `json:"rounds_good"`
+	// RoundsBad is the number of commitments which caused discrepancies.
+	RoundsBad uint64 `json:"rounds_bad"`
+	// RoundsMissed is the number of rounds in which a primary executor worker did not commit.
+	RoundsMissed uint64 `json:"rounds_missed"`
+}
+
+// runtimeStats are the runtime statistics over a range of rounds.
+type runtimeStats struct {
+	// RuntimeID is the runtime identifier.
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// FromRound is the first examined round.
+	FromRound uint64 `json:"from_round"`
+	// ToRound is the last examined round.
+	ToRound uint64 `json:"to_round"`
+	// FromHeight is the first examined consensus height.
+	FromHeight int64 `json:"from_height"`
+	// ToHeight is the last examined consensus height.
+	ToHeight int64 `json:"to_height"`
+
+	// Rounds is the number of examined rounds.
+	Rounds uint64 `json:"rounds"`
+	// FinalizedRounds is the number of rounds finalized normally.
+	FinalizedRounds uint64 `json:"finalized_rounds"`
+	// FailedRounds is the number of failed rounds.
+	FailedRounds uint64 `json:"failed_rounds"`
+	// EpochTransitionRounds is the number of epoch transition rounds.
+	EpochTransitionRounds uint64 `json:"epoch_transition_rounds"`
+	// SuspendedRounds is the number of rounds emitted due to runtime suspension.
+	SuspendedRounds uint64 `json:"suspended_rounds"`
+	// Discrepancies is the number of detected execution discrepancies.
+	Discrepancies uint64 `json:"discrepancies"`
+	// DiscrepancyTimeouts is the number of execution discrepancies caused by timeouts.
+	DiscrepancyTimeouts uint64 `json:"discrepancy_timeouts"`
+	// AverageRoundTime is the average time between two consecutive rounds.
+	AverageRoundTime time.Duration `json:"average_round_time"`
+
+	// Entities are the per-entity commitment statistics. Nodes which are no longer registered
+	// are accounted under their node identifier.
+	Entities map[signature.PublicKey]*entityRuntimeStats `json:"entities,omitempty"`
+}
+
+// runtimeStatsBackend provides the consensus state queried when computing runtime statistics.
+type runtimeStatsBackend interface {
+	// latestBlock returns the latest runtime block at the given consensus height.
+	latestBlock(ctx context.Context, height int64) (*block.Block, error)
+	// events returns the runtime's roothash events at the given consensus height.
+	events(ctx context.Context, height int64) ([]*roothash.Event, error)
+	// executorCommittee returns the runtime's executor committee at the given consensus height.
+	executorCommittee(ctx context.Context, height int64) (*scheduler.Committee, error)
+	// nodeEntity returns the entity owning the given node at the given consensus height.
+	nodeEntity(ctx context.Context, height int64, nodeID signature.PublicKey) (signature.PublicKey, error)
+}
+
+type grpcRuntimeStatsBackend struct {
+	runtimeID common.Namespace
+
+	roothash  roothash.Backend
+	scheduler scheduler.Backend
+	registry  registry.Backend
+
+	entities map[signature.PublicKey]signature.PublicKey
+}
+
+func (b *grpcRuntimeStatsBackend) latestBlock(ctx context.Context, height int64) (*block.Block, error) {
+	return b.roothash.GetLatestBlock(ctx, &roothash.RuntimeRequest{
+		RuntimeID: b.runtimeID,
+		Height:    height,
+	})
+}
+
+func (b *grpcRuntimeStatsBackend) events(ctx context.Context, height int64) ([]*roothash.Event, error) {
+	evs, err := b.roothash.GetEvents(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	var rtEvs []*roothash.Event
+	for _, ev := range evs {
+		if ev.RuntimeID.Equal(&b.runtimeID) {
+			rtEvs = append(rtEvs, ev)
+		}
+	}
+	return rtEvs, nil
+}
+
+func (b *grpcRuntimeStatsBackend) executorCommittee(ctx context.Context, height int64) (*scheduler.Committee, error) {
+	committees, err := b.scheduler.GetCommittees(ctx, &scheduler.GetCommitteesRequest{
+		Height:    height,
+		RuntimeID: b.runtimeID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range committees {
+		if c.Kind == scheduler.KindComputeExecutor {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("no executor committee at height %d", height)
+}
+
+func (b *grpcRuntimeStatsBackend) nodeEntity(ctx context.Context, height int64, nodeID signature.PublicKey) (signature.PublicKey, error) {
+	if id, ok := b.entities[nodeID]; ok {
+		return id, nil
+	}
+	n, err := b.registry.GetNode(ctx, &registry.IDQuery{
+		Height: height,
+		ID:     nodeID,
+	})
+	switch {
+	case err == nil:
+		b.entities[nodeID] = n.EntityID
+		return n.EntityID, nil
+	case errors.Is(err, registry.ErrNoSuchNode):
+		return nodeID, nil
+	default:
+		return signature.PublicKey{}, err
+	}
+}
+
+// roundHeight returns the lowest consensus height in [lowHeight, highHeight] at which the latest
+// runtime block has at least the given round.
+func roundHeight(ctx context.Context, backend runtimeStatsBackend, round uint64, lowHeight, highHeight int64) (int64, error) {
+	var searchErr error
+	idx := sort.Search(int(highHeight-lowHeight+1), func(i int) bool {
+		if searchErr != nil {
+			return true
+		}
+		blk, err := backend.latestBlock(ctx, lowHeight+int64(i))
+		switch {
+		case err == nil:
+			return blk.Header.Round >= round
+		case errors.Is(err, roothash.ErrInvalidRuntime):
+			// Runtime did not exist yet at this height.
+			return false
+		default:
+			searchErr = err
+			return true
+		}
+	})
+	if searchErr != nil {
+		return 0, searchErr
+	}
+	if idx > int(highHeight-lowHeight) {
+		return 0, fmt.Errorf("round %d not found", round)
+	}
+	return lowHeight + int64(idx), nil
+}
+
+// collectRuntimeStats computes the runtime statistics for rounds in [fromRound, toRound], using
+// consensus state in [lowHeight, highHeight].
+func collectRuntimeStats(
+	ctx context.Context,
+	backend runtimeStatsBackend,
+	runtimeID common.Namespace,
+	fromRound, toRound uint64,
+	lowHeight, highHeight int64,
+) (*runtimeStats, error) {
+	if fromRound == 0 || fromRound > toRound {
+		return nil, fmt.Errorf("invalid round range: [%d, %d]", fromRound, toRound)
+	}
+
+	// Start right after the height at which the round preceding the range was finalized, so
+	// that discrepancies detected during the first round are also accounted for.
+	startHeight, err := roundHeight(ctx, backend, fromRound-1, lowHeight, highHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find height of round %d: %w", fromRound-1, err)
+	}
+	prevBlk, err := backend.latestBlock(ctx, startHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query runtime block at height %d: %w", startHeight, err)
+	}
+
+	stats := &runtimeStats{
+		RuntimeID:  runtimeID,
+		FromRound:  fromRound,
+		ToRound:    toRound,
+		FromHeight: startHeight + 1,
+		Entities:   make(map[signature.PublicKey]*entityRuntimeStats),
+	}
+	entityStats := func(height int64, nodeID signature.PublicKey) (*entityRuntimeStats, error) {
+		entityID, err := backend.nodeEntity(ctx, height, nodeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query node %s: %w", nodeID, err)
+		}
+		es := stats.Entities[entityID]
+		if es == nil {
+			es = &entityRuntimeStats{}
+			stats.Entities[entityID] = es
+		}
+		return es, nil
+	}
+
+	var firstTime, lastTime time.Time
+	lastRound := prevBlk.Header.Round
+	for height := startHeight + 1; lastRound < toRound; height++ {
+		if height > highHeight {
+			return nil, fmt.Errorf("round %d not finalized by height %d", toRound, highHeight)
+		}
+
+		evs, err := backend.events(ctx, height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query roothash events at height %d: %w", height, err)
+		}
+
+		var blk *block.Block
+		for _, ev := range evs {
+			switch {
+			case ev.ExecutionDiscrepancyDetected != nil:
+				if lastRound+1 < fromRound || lastRound+1 > toRound {
+					continue
+				}
+				stats.Discrepancies++
+				if ev.ExecutionDiscrepancyDetected.Timeout {
+					stats.DiscrepancyTimeouts++
+				}
+			case ev.Finalized != nil:
+				round := ev.Finalized.Round
+				lastRound = round
+				if round < fromRound || round > toRound {
+					continue
+				}
+				if blk == nil {
+					if blk, err = backend.latestBlock(ctx, height); err != nil {
+						return nil, fmt.Errorf("failed to query runtime block at height %d: %w", height, err)
+					}
+				}
+
+				// All blocks finalized at the same height share the same timestamp, but only the
+				// latest one can be queried. Earlier blocks are either normal (with commitments)
+				// or failed rounds.
+				hdrType := blk.Header.HeaderType
+				if round != blk.Header.Round {
+					hdrType = block.RoundFailed
+					if len(ev.Finalized.GoodComputeNodes) > 0 || len(ev.Finalized.BadComputeNodes) > 0 {
+						hdrType = block.Normal
+					}
+				}
+
+				ts := time.Unix(int64(blk.Header.Timestamp), 0)
+				if stats.Rounds == 0 {
+					firstTime = ts
+				}
+				lastTime = ts
+				stats.Rounds++
+				stats.ToHeight = height
+
+				switch hdrType {
+				case block.Normal:
+					stats.FinalizedRounds++
+				case block.RoundFailed:
+					stats.FailedRounds++
+					continue
+				case block.EpochTransition:
+					stats.EpochTransitionRounds++
+					continue
+				case block.Suspended:
+					stats.SuspendedRounds++
+					continue
+				default:
+					continue
+				}
+
+				// The round was processed by the committee elected before this height.
+				committee, err := backend.executorCommittee(ctx, height-1)
+				if err != nil {
+					return nil, fmt.Errorf("failed to query executor committee at height %d: %w", height-1, err)
+				}
+				committed := make(map[signature.PublicKey]bool)
+				for _, id := range ev.Finalized.GoodComputeNodes {
+					committed[id] = true
+					es, err := entityStats(height-1, id)
+					if err != nil {
+						return nil, err
+					}
+					es.RoundsGood++
+				}
+				for _, id := range ev.Finalized.BadComputeNodes {
+					committed[id] = true
+					es, err := entityStats(height-1, id)
+					if err != nil {
+						return nil, err
+					}
+					es.RoundsBad++
+				}
+				for _, member := range committee.Members {
+					es, err := entityStats(height-1, member.PublicKey)
+					if err != nil {
+						return nil, err
+					}
+					switch member.Role {
+					case scheduler.RoleWorker:
+						es.RoundsElected++
+						if !committed[member.PublicKey] {
+							es.RoundsMissed++
+						}
+					case scheduler.RoleBackupWorker:
+						es.RoundsBackup++
+					}
+				}
+			}
+		}
+	}
+
+	if stats.Rounds > 1 {
+		stats.AverageRoundTime = lastTime.Sub(firstTime) / time.Duration(stats.Rounds-1)
+	}
+	return stats, nil
+}
+
+func printRuntimeStats(stats *runtimeStats) {
+	fmt.Printf("Runtime:          %s\n", stats.RuntimeID)
+	fmt.Printf("Rounds:           %d-%d (heights %d-%d)\n", stats.FromRound, stats.ToRound, stats.FromHeight, stats.ToHeight)
+	fmt.Printf("Finalized rounds: %d\n", stats.FinalizedRounds)
+	fmt.Printf("Failed rounds:    %d\n", stats.FailedRounds)
+	fmt.Printf("Epoch transition: %d\n", stats.EpochTransitionRounds)
+	fmt.Printf("Suspended rounds: %d\n", stats.SuspendedRounds)
+	fmt.Printf("Discrepancies:    %d (%d due to timeouts)\n", stats.Discrepancies, stats.DiscrepancyTimeouts)
+	fmt.Printf("Avg. round time:  %s\n", stats.AverageRoundTime)
+
+	if len(stats.Entities) == 0 {
+		return
+	}
+	ids := make([]signature.PublicKey, 0, len(stats.Entities))
+	for id := range stats.Entities {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+
+	fmt.Println()
+	fmt.Printf("%-44s %8s %8s %8s %8s %8s\n", "Entity", "Elected", "Backup", "Good", "Bad", "Missed")
+	for _, id := range ids {
+		es := stats.Entities[id]
+		fmt.Printf("%-44s %8d %8d %8d %8d %8d\n", id, es.RoundsElected, es.RoundsBackup, es.RoundsGood, es.RoundsBad, es.RoundsMissed)
+	}
+}
+
+func doRuntimeStats(cmd *cobra.Command, args []string) {
+	conn, _ := DoConnect(cmd)
+	defer conn.Close()
+
+	runtimeID := parseRuntimeID(args[0])
+
+	ctx := context.Background()
+	backend := &grpcRuntimeStatsBackend{
+		runtimeID: runtimeID,
+		roothash:  roothash.NewRootHashClient(conn),
+		scheduler: scheduler.NewSchedulerClient(conn),
+		registry:  registry.NewRegistryClient(conn),
+		entities:  make(map[signature.PublicKey]signature.PublicKey),
+	}
+
+	status, err := consensus.NewConsensusClient(conn).GetStatus(ctx)
+	if err != nil {
+		logger.Error("failed to query consensus status",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	latestBlk, err := backend.latestBlock(ctx, status.LatestHeight)
+	if err != nil {
+		logger.Error("failed to query latest runtime block",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	toRound := latestBlk.Header.Round
+	if cmd.Flags().Changed("to-round") {
+		if runtimeStatsToRound > toRound {
+			logger.Error("round not yet finalized",
+				"round", runtimeStatsToRound,
+				"latest_round", toRound,
+			)
+			os.Exit(1)
+		}
+		toRound = runtimeStatsToRound
+	}
+	fromRound := uint64(1)
+	if toRound > defaultRuntimeStatsRounds {
+		fromRound = toRound - defaultRuntimeStatsRounds + 1
+	}
+	if cmd.Flags().Changed("from-round") {
+		fromRound = runtimeStatsFromRound
+	}
+
+	lowHeight := status.LastRetainedHeight
+	if lowHeight < status.GenesisHeight {
+		lowHeight = status.GenesisHeight
+	}
+	stats, err := collectRuntimeStats(ctx, backend, runtimeID, fromRound, toRound, lowHeight, status.LatestHeight)
+	if err != nil {
+		logger.Error("failed to collect runtime statistics",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	if err = cmdCommon.PrintResult(stats, func() {
+		printRuntimeStats(stats)
+	}); err != nil {
+		logger.Error("failed to print runtime statistics",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
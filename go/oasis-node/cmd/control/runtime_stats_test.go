@@ -0,0 +1,163 @@
+package control
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+)
+
+type testRuntimeStatsHeight struct {
+	blk       *block.Block
+	events    []*roothash.Event
+	committee *scheduler.Committee
+}
+
+type testRuntimeStatsBackend struct {
+	heights map[int64]*testRuntimeStatsHeight
+	nodes   map[signature.PublicKey]signature.PublicKey
+}
+
+func (b *testRuntimeStatsBackend) latestBlock(ctx context.Context, height int64) (*block.Block, error) {
+	h, ok := b.heights[height]
+	if !ok {
+		return nil, roothash.ErrInvalidRuntime
+	}
+	return h.blk, nil
+}
+
+func (b *testRuntimeStatsBackend) events(ctx context.Context, height int64) ([]*roothash.Event, error) {
+	return b.heights[height].events, nil
+}
+
+func (b *testRuntimeStatsBackend) executorCommittee(ctx context.Context, height int64) (*scheduler.Committee, error) {
+	return b.heights[height].committee, nil
+}
+
+func (b *testRuntimeStatsBackend) nodeEntity(ctx context.Context, height int64, nodeID signature.PublicKey) (signature.PublicKey, error) {
+	return b.nodes[nodeID], nil
+}
+
+func TestCollectRuntimeStats(t *testing.T) {
+	require := require.New(t)
+
+	var runtimeID common.Namespace
+	entityA := memorySigner.NewTestSigner("runtime stats test: entity A").Public()
+	entityB := memorySigner.NewTestSigner("runtime stats test: entity B").Public()
+	node1 := memorySigner.NewTestSigner("runtime stats test: node 1").Public()
+	node2 := memorySigner.NewTestSigner("runtime stats test: node 2").Public()
+	node3 := memorySigner.NewTestSigner("runtime stats test: node 3").Public()
+	backend := &testRuntimeStatsBackend{
+		heights: make(map[int64]*testRuntimeStatsHeight),
+		nodes: map[signature.PublicKey]signature.PublicKey{
+			node1: entityA,
+			node2: entityA,
+			node3: entityB,
+		},
+	}
+	committee := &scheduler.Committee{
+		Kind: scheduler.KindComputeExecutor,
+		Members: []*scheduler.CommitteeNode{
+			{Role: scheduler.RoleWorker, PublicKey: node1},
+			{Role: scheduler.RoleWorker, PublicKey: node3},
+			{Role: scheduler.RoleBackupWorker, PublicKey: node2},
+		},
+	}
+
+	// Heights 10-19 finalize one round each, except for:
+	//  - height 13, which only detects a discrepancy,
+	//  - height 14, which resolves the discrepancy via the backup worker,
+	//  - height 16, which fails a round and emits an epoch transition block.
+	now := time.Now().Truncate(time.Second)
+	round := uint64(0)
+	for height := int64(10); height < 20; height++ {
+		var events []*roothash.Event
+		finalize := func(hdrType block.HeaderType, good, bad []signature.PublicKey) {
+			round++
+			events = append(events, &roothash.Event{
+				Height:    height,
+				RuntimeID: runtimeID,
+				Finalized: &roothash.FinalizedEvent{
+					Round:            round,
+					GoodComputeNodes: good,
+					BadComputeNodes:  bad,
+				},
+			})
+		}
+
+		var hdrType block.HeaderType
+		switch height {
+		case 10:
+			// Genesis block.
+			hdrType = block.Normal
+		case 13:
+			events = append(events, &roothash.Event{
+				Height:                       height,
+				RuntimeID:                    runtimeID,
+				ExecutionDiscrepancyDetected: &roothash.ExecutionDiscrepancyDetectedEvent{},
+			})
+		case 14:
+			hdrType = block.Normal
+			finalize(hdrType, []signature.PublicKey{node1, node2}, []signature.PublicKey{node3})
+		case 16:
+			finalize(block.RoundFailed, nil, nil)
+			hdrType = block.EpochTransition
+			finalize(hdrType, nil, nil)
+		default:
+			hdrType = block.Normal
+			finalize(hdrType, []signature.PublicKey{node1, node3}, nil)
+		}
+
+		blk := block.NewGenesisBlock(runtimeID, uint64(now.Add(time.Duration(height)*time.Second).Unix()))
+		blk.Header.Round = round
+		blk.Header.HeaderType = hdrType
+		backend.heights[height] = &testRuntimeStatsHeight{
+			blk:       blk,
+			events:    events,
+			committee: committee,
+		}
+	}
+	// Rounds: 1@11, 2@12, 3@14, 4@15, 5@16 (failed), 6@16 (epoch), 7@17, 8@18, 9@19.
+
+	ctx := context.Background()
+	height, err := roundHeight(ctx, backend, 3, 1, 19)
+	require.NoError(err, "roundHeight")
+	require.EqualValues(14, height)
+	_, err = roundHeight(ctx, backend, 10, 1, 19)
+	require.Error(err, "roundHeight should fail for rounds not yet finalized")
+
+	_, err = collectRuntimeStats(ctx, backend, runtimeID, 5, 4, 1, 19)
+	require.Error(err, "collectRuntimeStats should fail for an invalid range")
+
+	stats, err := collectRuntimeStats(ctx, backend, runtimeID, 2, 7, 1, 19)
+	require.NoError(err, "collectRuntimeStats")
+	require.EqualValues(12, stats.FromHeight)
+	require.EqualValues(17, stats.ToHeight)
+	require.EqualValues(6, stats.Rounds)
+	require.EqualValues(4, stats.FinalizedRounds)
+	require.EqualValues(1, stats.FailedRounds)
+	require.EqualValues(1, stats.EpochTransitionRounds)
+	require.EqualValues(1, stats.Discrepancies)
+	require.EqualValues(0, stats.DiscrepancyTimeouts)
+	require.Equal(time.Second, stats.AverageRoundTime)
+
+	require.Len(stats.Entities, 2)
+	require.Equal(&entityRuntimeStats{
+		RoundsElected: 4,
+		RoundsBackup:  4,
+		RoundsGood:    5,
+	}, stats.Entities[entityA])
+	require.Equal(&entityRuntimeStats{
+		RoundsElected: 4,
+		RoundsGood:    3,
+		RoundsBad:     1,
+	}, stats.Entities[entityB])
+}
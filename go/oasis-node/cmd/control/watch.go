@@ -0,0 +1,222 @@
+package control
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+)
+
+// statusSummary is the condensed node status shown in watch mode.
+type statusSummary struct {
+	// LatestHeight is the height of the latest consensus block.
+	LatestHeight int64 `json:"latest_height"`
+	// LatestTime is the timestamp of the latest consensus block.
+	LatestTime time.Time `json:"latest_time"`
+	// LatestEpoch is the epoch of the latest consensus block.
+	LatestEpoch beacon.EpochTime `json:"latest_epoch"`
+	// Peers is the number of consensus peers.
+	Peers int `json:"peers"`
+	// LastRegistration is the time of the last successful registration.
+	LastRegistration time.Time `json:"last_registration"`
+	// Runtimes are the per-runtime worker states.
+	Runtimes map[common.Namespace]*runtimeStatusSummary `json:"runtimes,omitempty"`
+}
+
+// runtimeStatusSummary is the condensed per-runtime status shown in watch mode.
+type runtimeStatusSummary struct {
+	// LatestRound is the round of the latest runtime block.
+	LatestRound uint64 `json:"latest_round"`
+	// ExecutorRoles are the node's roles in the executor committee.
+	ExecutorRoles []scheduler.Role `json:"executor_roles,omitempty"`
+	// StorageRoles are the node's roles in the storage committee.
+	StorageRoles []scheduler.Role `json:"storage_roles,omitempty"`
+	// IsTransactionScheduler is true iff the node is the transaction scheduler.
+	IsTransactionScheduler bool `json:"is_txn_scheduler"`
+	// Peers is the number of runtime committee peers.
+	Peers int `json:"peers"`
+	// LastFinalizedRound is the last round finalized by the storage worker (if any).
+	LastFinalizedRound *uint64 `json:"last_finalized_round,omitempty"`
+	// ExecutorState is the state of the executor worker (if any).
+	ExecutorState string `json:"executor_state,omitempty"`
+	// ExecutorPaused is true iff the executor worker is paused.
+	ExecutorPaused bool `json:"executor_paused,omitempty"`
+	// TxPoolSize is the number of transactions waiting to be scheduled (if known).
+	TxPoolSize *uint64 `json:"tx_pool_size,omitempty"`
+}
+
+func summarizeStatus(status *control.Status) *statusSummary {
+	summary := &statusSummary{
+		LatestHeight:     status.Consensus.LatestHeight,
+		LatestTime:       status.Consensus.LatestTime,
+		LatestEpoch:      status.Consensus.LatestEpoch,
+		Peers:            len(status.Consensus.NodePeers),
+		LastRegistration: status.Registration.LastRegistration,
+	}
+	if len(status.Runtimes) > 0 {
+		summary.Runtimes = make(map[common.Namespace]*runtimeStatusSummary)
+	}
+	for id, rs := range status.Runtimes {
+		rt := &runtimeStatusSummary{
+			LatestRound: rs.LatestRound,
+		}
+		if rs.Committee != nil {
+			rt.ExecutorRoles = rs.Committee.ExecutorRoles
+			rt.StorageRoles = rs.Committee.StorageRoles
+			rt.IsTransactionScheduler = rs.Committee.IsTransactionScheduler
+			rt.Peers = len(rs.Committee.Peers)
+		}
+		if rs.Storage != nil {
+			round := rs.Storage.LastFinalizedRound
+			rt.LastFinalizedRound = &round
+		}
+		if rs.Executor != nil {
+			rt.ExecutorState = rs.Executor.State
+			rt.ExecutorPaused = rs.Executor.Paused
+			if rs.Executor.TxPool != nil {
+				size := rs.Executor.TxPool.ScheduleQueueSize
+				rt.TxPoolSize = &size
+			}
+		}
+		summary.Runtimes[id] = rt
+	}
+	return summary
+}
+
+func formatRoles(roles []scheduler.Role) string {
+	if len(roles) == 0 {
+		return "none"
+	}
+	var s []string
+	for _, r := range roles {
+		s = append(s, r.String())
+	}
+	return strings.Join(s, ", ")
+}
+
+func printStatusSummary(now time.Time, summary *statusSummary) {
+	fmt.Println("Consensus:")
+	fmt.Printf("  Height:            %d (%s ago)\n", summary.LatestHeight, now.Sub(summary.LatestTime).Round(time.Second))
+	fmt.Printf("  Epoch:             %d\n", summary.LatestEpoch)
+	fmt.Printf("  Peers:             %d\n", summary.Peers)
+	switch summary.LastRegistration.IsZero() {
+	case true:
+		fmt.Println("  Last registration: never")
+	case false:
+		fmt.Printf("  Last registration: %s (%s ago)\n",
+			summary.LastRegistration.Format(time.RFC3339),
+			now.Sub(summary.LastRegistration).Round(time.Second),
+		)
+	}
+
+	if len(summary.Runtimes) == 0 {
+		return
+	}
+	ids := make([]common.Namespace, 0, len(summary.Runtimes))
+	for id := range summary.Runtimes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+
+	fmt.Println("Runtimes:")
+	for _, id := range ids {
+		rt := summary.Runtimes[id]
+		fmt.Printf("  %s:\n", id)
+		fmt.Printf("    Latest round:         %d\n", rt.LatestRound)
+		fmt.Printf("    Executor roles:       %s\n", formatRoles(rt.ExecutorRoles))
+		fmt.Printf("    Storage roles:        %s\n", formatRoles(rt.StorageRoles))
+		fmt.Printf("    Txn scheduler:        %t\n", rt.IsTransactionScheduler)
+		fmt.Printf("    Committee peers:      %d\n", rt.Peers)
+		if rt.LastFinalizedRound != nil {
+			fmt.Printf("    Last finalized round: %d\n", *rt.LastFinalizedRound)
+		}
+		if rt.ExecutorState != "" {
+			var paused string
+			if rt.ExecutorPaused {
+				paused = " (paused)"
+			}
+			fmt.Printf("    Executor state:       %s%s\n", rt.ExecutorState, paused)
+		}
+		if rt.TxPoolSize != nil {
+			fmt.Printf("    Tx pool size:         %d\n", *rt.TxPoolSize)
+		}
+	}
+}
+
+// validateWatchInterval checks that the status polling interval is usable.
+func validateWatchInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid status watch interval: %s (must be positive)", interval)
+	}
+	return nil
+}
+
+// watchStatus periodically queries the node status and prints a summary whenever it changes,
+// until interrupted.
+//
+// In case standard output is a terminal and the text output format is used, the screen is
+// refreshed on each change. Otherwise the summaries are streamed.
+func watchStatus(client control.NodeController) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	refresh := !cmdCommon.IsJSONOutput() && cmdCommon.Isatty(os.Stdout.Fd())
+
+	ticker := time.NewTicker(statusWatchInterval)
+	defer ticker.Stop()
+
+	var prev *statusSummary
+	for {
+		status, err := client.GetStatus(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			logger.Error("failed to query status",
+				"err", err,
+			)
+		default:
+			summary := summarizeStatus(status)
+			if !reflect.DeepEqual(prev, summary) {
+				now := time.Now()
+				switch {
+				case cmdCommon.IsJSONOutput():
+					if err = cmdCommon.PrintJSONOutput(summary); err != nil {
+						logger.Error("failed to print node status",
+							"err", err,
+						)
+						os.Exit(1)
+					}
+				case refresh:
+					// Clear the screen and move the cursor to the top left corner.
+					fmt.Print("\033[H\033[2J")
+					fmt.Printf("Every %s: node status at %s\n\n", statusWatchInterval, now.Format(time.RFC3339))
+					printStatusSummary(now, summary)
+				default:
+					fmt.Printf("--- %s\n", now.Format(time.RFC3339))
+					printStatusSummary(now, summary)
+				}
+				prev = summary
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
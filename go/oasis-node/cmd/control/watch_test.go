@@ -0,0 +1,76 @@
+package control
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	commonWorker "github.com/oasisprotocol/oasis-core/go/worker/common/api"
+	executorWorker "github.com/oasisprotocol/oasis-core/go/worker/compute/executor/api"
+	storageWorker "github.com/oasisprotocol/oasis-core/go/worker/storage/api"
+)
+
+func TestSummarizeStatus(t *testing.T) {
+	require := require.New(t)
+
+	rtID := common.NewTestNamespaceFromSeed([]byte("control watch test"), 0)
+	now := time.Now()
+	status := &control.Status{
+		Consensus: consensus.Status{
+			LatestHeight: 42,
+			LatestTime:   now,
+			LatestEpoch:  3,
+			NodePeers:    []string{"a", "b"},
+		},
+		Runtimes: map[common.Namespace]control.RuntimeStatus{
+			rtID: {
+				LatestRound: 10,
+				Committee: &commonWorker.Status{
+					ExecutorRoles: []scheduler.Role{scheduler.RoleWorker},
+					Peers:         []string{"c"},
+				},
+				Storage: &storageWorker.Status{LastFinalizedRound: 9},
+				Executor: &executorWorker.Status{
+					State:  "WaitingForBatch",
+					TxPool: &executorWorker.TxPoolStatus{ScheduleQueueSize: 5},
+				},
+			},
+		},
+	}
+
+	summary := summarizeStatus(status)
+	require.EqualValues(42, summary.LatestHeight)
+	require.EqualValues(3, summary.LatestEpoch)
+	require.Equal(2, summary.Peers)
+	require.Len(summary.Runtimes, 1)
+	rt := summary.Runtimes[rtID]
+	require.EqualValues(10, rt.LatestRound)
+	require.Equal([]scheduler.Role{scheduler.RoleWorker}, rt.ExecutorRoles)
+	require.Equal(1, rt.Peers)
+	require.NotNil(rt.LastFinalizedRound)
+	require.EqualValues(9, *rt.LastFinalizedRound)
+	require.Equal("WaitingForBatch", rt.ExecutorState)
+	require.NotNil(rt.TxPoolSize)
+	require.EqualValues(5, *rt.TxPoolSize)
+	require.False(rt.ExecutorPaused)
+
+	require.Equal(summary, summarizeStatus(status), "equal statuses should have equal summaries")
+	status.Consensus.LatestHeight++
+	require.NotEqual(summary, summarizeStatus(status), "status changes should be detected")
+	summary = summarizeStatus(status)
+	status.Runtimes[rtID].Executor.Paused = true
+	require.NotEqual(summary, summarizeStatus(status), "pausing should be detected")
+}
+
+func TestValidateWatchInterval(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(validateWatchInterval(time.Second))
+	require.Error(validateWatchInterval(0), "zero interval should be rejected")
+	require.Error(validateWatchInterval(-time.Second), "negative interval should be rejected")
+}
@@ -3,7 +3,6 @@ package beacon
 
 import (
 	"context"
-	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -71,14 +70,12 @@ func doBeaconStatus(cmd *cobra.Command, args []string) {
 		Beacon: b,
 	}
 
-	prettyJSON, err := cmdCommon.PrettyJSONMarshal(prettyOut)
-	if err != nil {
-		logger.Error("failed to get pretty JSON of beacon state",
+	if err = cmdCommon.PrintResult(prettyOut, nil); err != nil {
+		logger.Error("failed to print beacon state",
 			"err", err,
 		)
 		os.Exit(1)
 	}
-	fmt.Println(string(prettyJSON))
 }
 
 // Register registers the beacon sub-command and all of it's children.
@@ -309,6 +309,9 @@ func init() {
 	storageFlags.Uint64(CfgNumStorageFailApply, 0, "Number of Apply requests to fail")
 	storageFlags.Bool(CfgFailReadRequests, false, "Whether the storage node should fail read requests")
 	storageFlags.Bool(CfgCorruptGetDiff, false, "Whether the storage node should corrupt GetDiff responses")
+	storageFlags.Uint64(CfgNumStorageWrongReceiptApplyBatch, 0, "Number of ApplyBatch requests to return receipts for wrong roots for")
+	storageFlags.Bool(CfgWithholdGetDiff, false, "Whether the storage node should withhold GetDiff responses")
+	storageFlags.Bool(CfgCorruptSyncProofs, false, "Whether the storage node should corrupt Sync* proofs")
 	_ = viper.BindPFlags(storageFlags)
 	byzantineCmd.PersistentFlags().AddFlagSet(storageFlags)
 
@@ -6,7 +6,6 @@ import (
 	"fmt"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/balancer/roundrobin"
 	"google.golang.org/grpc/resolver"
 	"google.golang.org/grpc/resolver/manual"
 
@@ -49,7 +48,7 @@ func dialNode(node *node.Node, opts grpc.DialOption) (*grpc.ClientConn, error) {
 	manualResolver := manual.NewBuilderWithScheme("oasis-core-resolver")
 
 	conn, err := cmnGrpc.Dial("oasis-core-resolver:///", opts,
-		grpc.WithBalancerName(roundrobin.Name), // nolint: staticcheck
+		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"round_robin"}`),
 		grpc.WithResolvers(manualResolver),
 	)
 	if err != nil {
@@ -11,11 +11,13 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/database"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
 )
 
 const (
@@ -29,6 +31,15 @@ const (
 	CfgFailReadRequests = "fail_read_requests"
 	// CfgCorruptGetDiff configures whether the storage node should corrupt GetDiff responses.
 	CfgCorruptGetDiff = "corrupt_get_diff"
+	// CfgNumStorageWrongReceiptApplyBatch configures for how many apply-batch
+	// requests the storage node should return receipts for wrong roots.
+	CfgNumStorageWrongReceiptApplyBatch = "num_storage_wrong_receipt_apply_batch"
+	// CfgWithholdGetDiff configures whether the storage node should withhold
+	// GetDiff responses by returning empty diffs.
+	CfgWithholdGetDiff = "withhold_get_diff"
+	// CfgCorruptSyncProofs configures whether the storage node should corrupt
+	// proofs returned in Sync* responses.
+	CfgCorruptSyncProofs = "corrupt_sync_proofs"
 )
 
 var (
@@ -36,6 +47,8 @@ var (
 
 	errByzantine = fmt.Errorf("byzantine error")
 
+	wrongRoot = hash.NewFromBytes([]byte("byzantine wrong root"))
+
 	storageFlags = flag.NewFlagSet("", flag.ContinueOnError)
 )
 
@@ -50,6 +63,10 @@ type storageWorker struct {
 	numFailApplyBatch uint64
 	failReadRequests  bool
 	corruptGetDiff    bool
+
+	numWrongReceiptApplyBatch uint64
+	withholdGetDiff           bool
+	corruptSyncProofs         bool
 }
 
 func newStorageNode(id *identity.Identity, namespace common.Namespace, datadir string) (*storageWorker, error) {
@@ -70,13 +87,16 @@ func newStorageNode(id *identity.Identity, namespace common.Namespace, datadir s
 	}
 
 	return &storageWorker{
-		id:                id,
-		backend:           impl,
-		initCh:            initCh,
-		numFailApply:      viper.GetUint64(CfgNumStorageFailApply),
-		numFailApplyBatch: viper.GetUint64(CfgNumStorageFailApplyBatch),
-		failReadRequests:  viper.GetBool(CfgFailReadRequests),
-		corruptGetDiff:    viper.GetBool(CfgCorruptGetDiff),
+		id:                        id,
+		backend:                   impl,
+		initCh:                    initCh,
+		numFailApply:              viper.GetUint64(CfgNumStorageFailApply),
+		numFailApplyBatch:         viper.GetUint64(CfgNumStorageFailApplyBatch),
+		failReadRequests:          viper.GetBool(CfgFailReadRequests),
+		corruptGetDiff:            viper.GetBool(CfgCorruptGetDiff),
+		numWrongReceiptApplyBatch: viper.GetUint64(CfgNumStorageWrongReceiptApplyBatch),
+		withholdGetDiff:           viper.GetBool(CfgWithholdGetDiff),
+		corruptSyncProofs:         viper.GetBool(CfgCorruptSyncProofs),
 	}, nil
 }
 
@@ -85,7 +105,7 @@ func (w *storageWorker) SyncGet(ctx context.Context, request *syncer.GetRequest)
 		return nil, errByzantine
 	}
 
-	return w.backend.SyncGet(ctx, request)
+	return w.maybeCorruptProof(w.backend.SyncGet(ctx, request))
 }
 
 func (w *storageWorker) SyncGetPrefixes(ctx context.Context, request *syncer.GetPrefixesRequest) (*syncer.ProofResponse, error) {
@@ -93,7 +113,7 @@ func (w *storageWorker) SyncGetPrefixes(ctx context.Context, request *syncer.Get
 		return nil, errByzantine
 	}
 
-	return w.backend.SyncGetPrefixes(ctx, request)
+	return w.maybeCorruptProof(w.backend.SyncGetPrefixes(ctx, request))
 }
 
 func (w *storageWorker) SyncIterate(ctx context.Context, request *syncer.IterateRequest) (*syncer.ProofResponse, error) {
@@ -101,7 +121,27 @@ func (w *storageWorker) SyncIterate(ctx context.Context, request *syncer.Iterate
 		return nil, errByzantine
 	}
 
-	return w.backend.SyncIterate(ctx, request)
+	return w.maybeCorruptProof(w.backend.SyncIterate(ctx, request))
+}
+
+// maybeCorruptProof corrupts the proof in the given proof response if configured.
+func (w *storageWorker) maybeCorruptProof(rsp *syncer.ProofResponse, err error) (*syncer.ProofResponse, error) {
+	if err != nil || !w.corruptSyncProofs {
+		return rsp, err
+	}
+
+	// Corrupt the first proof entry so that the proof no longer verifies.
+	for i, entry := range rsp.Proof.Entries {
+		if len(entry) < 2 {
+			continue
+		}
+
+		corrupted := append([]byte{}, entry...)
+		corrupted[len(corrupted)-1] ^= 0xff
+		rsp.Proof.Entries[i] = corrupted
+		break
+	}
+	return rsp, nil
 }
 
 func (w *storageWorker) Apply(ctx context.Context, request *storage.ApplyRequest) ([]*storage.Receipt, error) {
@@ -125,7 +165,29 @@ func (w *storageWorker) ApplyBatch(ctx context.Context, request *storage.ApplyBa
 		return nil, errByzantine
 	}
 
-	return w.backend.ApplyBatch(ctx, request)
+	receipts, err := w.backend.ApplyBatch(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.numWrongReceiptApplyBatch > 0 {
+		w.numWrongReceiptApplyBatch--
+
+		// Certify storing a wrong root for every applied operation.
+		rootTypes := make([]storage.RootType, 0, len(request.Ops))
+		roots := make([]hash.Hash, 0, len(request.Ops))
+		for _, op := range request.Ops {
+			rootTypes = append(rootTypes, op.RootType)
+			roots = append(roots, wrongRoot)
+		}
+
+		receipt, err := storage.SignReceipt(w.id.NodeSigner, request.Namespace, request.DstRound, rootTypes, roots)
+		if err != nil {
+			return nil, err
+		}
+		return []*storage.Receipt{receipt}, nil
+	}
+	return receipts, nil
 }
 
 type corruptIterator struct {
@@ -157,6 +219,10 @@ func (w *storageWorker) GetDiff(ctx context.Context, request *storage.GetDiffReq
 	if w.failReadRequests {
 		return nil, errByzantine
 	}
+	if w.withholdGetDiff {
+		// Pretend that there are no changes between the roots.
+		return writelog.NewStaticIterator(nil), nil
+	}
 
 	wl, err := w.backend.GetDiff(ctx, request)
 	if err != nil {
@@ -0,0 +1,323 @@
+// Package consensus implements the consensus state introspection debug sub-commands.
+package consensus
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+)
+
+const (
+	cfgHeight = "height"
+	cfgApp    = "app"
+)
+
+var (
+	dumpHeight int64
+	dumpApp    string
+
+	consensusCmd = &cobra.Command{
+		Use:   "consensus",
+		Short: "debug the consensus layer",
+	}
+
+	consensusDumpStateCmd = &cobra.Command{
+		Use:   "dump-state",
+		Short: "dump a consensus application's state at the given height",
+		Run:   doDumpState,
+	}
+
+	logger = logging.GetLogger("cmd/debug/consensus")
+)
+
+// consensusApp describes the state keys of a consensus application.
+type consensusApp struct {
+	// prefix is the first key prefix used by the application. Each application uses the key
+	// prefixes in [prefix, prefix+0x10).
+	prefix byte
+	// kinds are the names of the application's key kinds by key prefix.
+	kinds map[byte]string
+}
+
+// consensusApps are the consensus applications whose state can be dumped.
+var consensusApps = map[string]*consensusApp{
+	"registry": {
+		prefix: 0x10,
+		kinds: map[byte]string{
+			0x10: "signed_entity",
+			0x11: "signed_node",
+			0x12: "signed_node_by_entity",
+			0x13: "runtime",
+			0x14: "node_by_cons_address",
+			0x15: "node_status",
+			0x16: "parameters",
+			0x17: "key_map",
+			0x18: "suspended_runtime",
+			0x19: "runtime_by_entity",
+		},
+	},
+	"roothash": {
+		prefix: 0x20,
+		kinds: map[byte]string{
+			0x20: "runtime",
+			0x21: "parameters",
+			0x22: "round_timeout_queue",
+			0x24: "evidence",
+			0x25: "state_root",
+			0x26: "io_root",
+		},
+	},
+	"beacon": {
+		prefix: 0x40,
+		kinds: map[byte]string{
+			0x40: "epoch_current",
+			0x41: "epoch_future",
+			0x42: "beacon",
+			0x43: "parameters",
+			0x45: "epoch_pending_mock",
+			0x46: "vrf_state",
+		},
+	},
+	"staking": {
+		prefix: 0x50,
+		kinds: map[byte]string{
+			0x50: "account",
+			0x51: "total_supply",
+			0x52: "common_pool",
+			0x53: "delegation",
+			0x54: "debonding_delegation",
+			0x55: "debonding_queue",
+			0x56: "parameters",
+			0x57: "last_block_fees",
+			0x58: "epoch_signing",
+			0x59: "governance_deposits",
+		},
+	},
+	"scheduler": {
+		prefix: 0x60,
+		kinds: map[byte]string{
+			0x60: "committee",
+			0x61: "validators_current",
+			0x62: "validators_pending",
+			0x63: "parameters",
+		},
+	},
+	"keymanager": {
+		prefix: 0x70,
+		kinds: map[byte]string{
+			0x70: "status",
+		},
+	},
+	"governance": {
+		prefix: 0x80,
+		kinds: map[byte]string{
+			0x80: "next_proposal_identifier",
+			0x81: "proposal",
+			0x82: "active_proposal",
+			0x83: "vote",
+			0x84: "pending_upgrade",
+			0x85: "parameters",
+		},
+	},
+}
+
+// stateEntry is a single consensus state entry.
+type stateEntry struct {
+	// Kind is the name of the key kind (if known).
+	Kind string `json:"kind,omitempty"`
+	// Key is the raw key.
+	Key []byte `json:"key"`
+	// Value is the raw value.
+	Value []byte `json:"value"`
+	// Decoded is the decoded value (if the value is valid CBOR).
+	Decoded interface{} `json:"decoded,omitempty"`
+}
+
+func appNames() []string {
+	var names []string
+	for name := range consensusApps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// decodeCBORValue decodes the given CBOR value into a representation that can be serialized
+// into JSON. Byte strings are hex-encoded, except for signed envelopes whose raw values are
+// decoded as well.
+func decodeCBORValue(raw []byte) (interface{}, bool) {
+	var v interface{}
+	if err := cbor.Unmarshal(raw, &v); err != nil {
+		return nil, false
+	}
+	return toJSONValue(v), true
+}
+
+func toJSONValue(v interface{}) interface{} {
+	switch vv := v.(type) {
+	case []byte:
+		return hex.EncodeToString(vv)
+	case []interface{}:
+		out := make([]interface{}, 0, len(vv))
+		for _, e := range vv {
+			out = append(out, toJSONValue(e))
+		}
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(vv))
+		for k, e := range vv {
+			var key string
+			switch kk := k.(type) {
+			case []byte:
+				key = hex.EncodeToString(kk)
+			default:
+				key = fmt.Sprintf("%v", kk)
+			}
+
+			// Signed envelopes contain the CBOR-encoded signed value.
+			if raw, ok := e.([]byte); ok && key == "untrusted_raw_value" {
+				if decoded, ok := decodeCBORValue(raw); ok {
+					out[key] = decoded
+					continue
+				}
+			}
+			out[key] = toJSONValue(e)
+		}
+		return out
+	default:
+		return vv
+	}
+}
+
+func dumpAppState(ctx context.Context, tree mkvs.KeyValueTree, app *consensusApp) ([]*stateEntry, error) {
+	it := tree.NewIterator(ctx, mkvs.IteratorPrefetch(1000))
+	defer it.Close()
+
+	var entries []*stateEntry
+	for it.Seek([]byte{app.prefix}); it.Valid(); it.Next() {
+		key := it.Key()
+		if len(key) == 0 || key[0] >= app.prefix+0x10 {
+			break
+		}
+		entry := &stateEntry{
+			Kind:  app.kinds[key[0]],
+			Key:   append([]byte{}, key...),
+			Value: append([]byte{}, it.Value()...),
+		}
+		if decoded, ok := decodeCBORValue(entry.Value); ok {
+			entry.Decoded = decoded
+		}
+		entries = append(entries, entry)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func doDumpState(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	app, ok := consensusApps[dumpApp]
+	if !ok {
+		logger.Error("unknown consensus application",
+			"app", dumpApp,
+			"supported", strings.Join(appNames(), ", "),
+		)
+		os.Exit(1)
+	}
+
+	conn, err := cmdGrpc.NewClient(cmd)
+	if err != nil {
+		logger.Error("failed to establish connection with node",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	client := consensus.NewConsensusClient(conn)
+	status, err := client.GetStatus(ctx)
+	if err != nil {
+		logger.Error("failed to query consensus status",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if dumpHeight != consensus.HeightLatest && dumpHeight < status.LastRetainedHeight {
+		logger.Error("height is no longer retained by the node",
+			"height", dumpHeight,
+			"last_retained_height", status.LastRetainedHeight,
+		)
+		os.Exit(1)
+	}
+
+	blk, err := client.GetBlock(ctx, dumpHeight)
+	if err != nil {
+		logger.Error("failed to query consensus block",
+			"err", err,
+			"height", dumpHeight,
+		)
+		os.Exit(1)
+	}
+
+	tree := mkvs.NewWithRoot(consensus.NewConsensusLightClient(conn).State(), nil, blk.StateRoot)
+	defer tree.Close()
+
+	entries, err := dumpAppState(ctx, tree, app)
+	if err != nil {
+		logger.Error("failed to dump consensus state",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	if err = cmdCommon.PrintResult(entries, func() {
+		fmt.Printf("# app: %s height: %d state root: %s entries: %d\n", dumpApp, blk.Height, blk.StateRoot.Hash, len(entries))
+		for _, e := range entries {
+			kind := e.Kind
+			if kind == "" {
+				kind = "unknown"
+			}
+			fmt.Printf("%s %s\n", kind, hex.EncodeToString(e.Key))
+			switch e.Decoded {
+			case nil:
+				fmt.Printf("  %s\n", hex.EncodeToString(e.Value))
+			default:
+				decoded, _ := json.Marshal(e.Decoded)
+				fmt.Printf("  %s\n", decoded)
+			}
+		}
+	}); err != nil {
+		logger.Error("failed to print consensus state",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+// Register registers the consensus sub-command and all of its children.
+func Register(parentCmd *cobra.Command) {
+	consensusCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
+	consensusDumpStateCmd.Flags().Int64Var(&dumpHeight, cfgHeight, consensus.HeightLatest, "consensus height (0 for the latest height)")
+	consensusDumpStateCmd.Flags().StringVar(&dumpApp, cfgApp, "", "consensus application ("+strings.Join(appNames(), ", ")+")")
+
+	consensusCmd.AddCommand(consensusDumpStateCmd)
+	parentCmd.AddCommand(consensusCmd)
+}
@@ -0,0 +1,52 @@
+package consensus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+func TestDumpAppState(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	tree := mkvs.New(nil, nil, node.RootTypeState)
+	defer tree.Close()
+
+	signed := signature.Signed{
+		Blob: cbor.Marshal(map[string]uint64{"nonce": 42}),
+	}
+	for _, kv := range []struct {
+		key   []byte
+		value []byte
+	}{
+		{[]byte{0x11, 0x01}, cbor.Marshal(&signed)},
+		{[]byte{0x16}, cbor.Marshal(map[string]bool{"debug_bypass_stake": true})},
+		{[]byte{0x1f, 0x02}, []byte{0xff}},
+		{[]byte{0x50, 0x01}, cbor.Marshal(uint64(1))},
+	} {
+		err := tree.Insert(ctx, kv.key, kv.value)
+		require.NoError(err, "Insert")
+	}
+
+	entries, err := dumpAppState(ctx, tree, consensusApps["registry"])
+	require.NoError(err, "dumpAppState")
+	require.Len(entries, 3, "only registry entries should be dumped")
+
+	require.Equal("signed_node", entries[0].Kind)
+	decoded := entries[0].Decoded.(map[string]interface{})
+	require.Equal(map[string]interface{}{"nonce": uint64(42)}, decoded["untrusted_raw_value"], "signed values should be decoded")
+
+	require.Equal("parameters", entries[1].Kind)
+	require.Equal(map[string]interface{}{"debug_bypass_stake": true}, entries[1].Decoded)
+
+	require.Equal("", entries[2].Kind, "unknown key kinds should have no name")
+	require.Nil(entries[2].Decoded, "invalid CBOR should not be decoded")
+	require.Equal([]byte{0xff}, entries[2].Value)
+}
@@ -3,12 +3,14 @@ package control
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/crash"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
@@ -20,6 +22,8 @@ var (
 	epoch uint64
 	nodes int
 
+	crashPoint crash.PointConfig
+
 	controlCmd = &cobra.Command{
 		Use:   "control",
 		Short: "debug control node during tests",
@@ -45,6 +49,22 @@ var (
 		Run: doWaitReady,
 	}
 
+	controlSetCrashPointCmd = &cobra.Command{
+		Use:   "set-crash-point <crash-point-id>",
+		Short: "arm or disarm a crash point",
+		Long: "Arm a crash point with the given probability or disarm it by setting the " +
+			"probability to zero. The crash point is only armed after it has been passed " +
+			"the given number of times.",
+		Args: cobra.ExactArgs(1),
+		Run:  doSetCrashPoint,
+	}
+
+	controlListCrashPointsCmd = &cobra.Command{
+		Use:   "list-crash-points",
+		Short: "list crash points and their status",
+		Run:   doListCrashPoints,
+	}
+
 	logger = logging.GetLogger("cmd/debug/control")
 )
 
@@ -115,14 +135,59 @@ func doWaitReady(cmd *cobra.Command, args []string) {
 	}
 }
 
+func doSetCrashPoint(cmd *cobra.Command, args []string) {
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	crashPoint.ID = args[0]
+
+	logger.Info("setting crash point",
+		"crash_point_id", crashPoint.ID,
+		"probability", crashPoint.Probability,
+		"after", crashPoint.After,
+		"count", crashPoint.Count,
+	)
+
+	if err := client.SetCrashPoints(context.Background(), []crash.PointConfig{crashPoint}); err != nil {
+		logger.Error("failed to set crash point",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doListCrashPoints(cmd *cobra.Command, args []string) {
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	points, err := client.GetCrashPoints(context.Background())
+	if err != nil {
+		logger.Error("failed to get crash points",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	for _, p := range points {
+		fmt.Printf("%s: probability=%f after=%d count=%d hits=%d crashes=%d\n",
+			p.ID, p.Probability, p.After, p.Count, p.Hits, p.Crashes,
+		)
+	}
+}
+
 // Register registers the dummy sub-command and all of its children.
 func Register(parentCmd *cobra.Command) {
 	controlCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
 	controlSetEpochCmd.Flags().Uint64VarP(&epoch, "epoch", "e", 0, "set epoch to given value")
 	controlWaitNodesCmd.Flags().IntVarP(&nodes, "nodes", "n", 1, "number of nodes to wait for")
+	controlSetCrashPointCmd.Flags().Float64Var(&crashPoint.Probability, "probability", 1.0, "crash probability (0 disarms the crash point)")
+	controlSetCrashPointCmd.Flags().Uint64Var(&crashPoint.After, "after", 0, "number of times the crash point is passed before it is armed")
+	controlSetCrashPointCmd.Flags().Uint64Var(&crashPoint.Count, "count", 0, "maximum number of crashes (0 = unlimited)")
 
 	controlCmd.AddCommand(controlSetEpochCmd)
 	controlCmd.AddCommand(controlWaitNodesCmd)
 	controlCmd.AddCommand(controlWaitReadyCmd)
+	controlCmd.AddCommand(controlSetCrashPointCmd)
+	controlCmd.AddCommand(controlListCrashPointsCmd)
 	parentCmd.AddCommand(controlCmd)
 }
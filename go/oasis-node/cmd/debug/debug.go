@@ -6,10 +6,13 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/beacon"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/byzantine"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/consensus"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/control"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/dumpdb"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/fixgenesis"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/replay"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/storage"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/txpool"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/txsource"
 )
 
@@ -27,6 +30,9 @@ func Register(parentCmd *cobra.Command) {
 	control.Register(debugCmd)
 	dumpdb.Register(debugCmd)
 	beacon.Register(debugCmd)
+	replay.Register(debugCmd)
+	txpool.Register(debugCmd)
+	consensus.Register(debugCmd)
 
 	parentCmd.AddCommand(debugCmd)
 }
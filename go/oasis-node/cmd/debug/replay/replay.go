@@ -0,0 +1,160 @@
+// Package replay implements the runtime round replay sub-command.
+package replay
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	runtimeClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/sandbox"
+	"github.com/oasisprotocol/oasis-core/go/runtime/replay"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+)
+
+const (
+	cfgRuntimeID         = "runtime.id"
+	cfgRuntimePath       = "runtime.path"
+	cfgRound             = "round"
+	cfgSandboxBinary     = "sandbox.binary"
+	cfgInsecureNoSandbox = "sandbox.insecure_disable"
+)
+
+var (
+	replayCmd = &cobra.Command{
+		Use:   "replay",
+		Short: "re-execute a historical runtime round and compare the results",
+		Long: "Fetches the inputs and context of a historical runtime round from a node, " +
+			"re-executes the batch against a local runtime binary and reports any differences " +
+			"between the produced roots and the ones finalized on-chain.",
+		Run: doReplay,
+	}
+
+	replayFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	logger = logging.GetLogger("cmd/debug/replay")
+)
+
+func doReplay(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	var runtimeID common.Namespace
+	if err := runtimeID.UnmarshalHex(viper.GetString(cfgRuntimeID)); err != nil {
+		logger.Error("malformed runtime identifier",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	runtimePath := viper.GetString(cfgRuntimePath)
+	if runtimePath == "" {
+		logger.Error("runtime path must be set")
+		os.Exit(1)
+	}
+
+	conn, err := cmdGrpc.NewClient(cmd)
+	if err != nil {
+		logger.Error("failed to establish connection with node",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	cs := consensus.NewConsensusClient(conn)
+
+	status, err := cs.GetStatus(ctx)
+	if err != nil {
+		logger.Error("failed to get consensus status",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	chainCtx, err := cs.GetChainContext(ctx)
+	if err != nil {
+		logger.Error("failed to get chain context",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	provisioner, err := sandbox.New(sandbox.Config{
+		HostInfo: &protocol.HostInfo{
+			ConsensusBackend:         status.Backend,
+			ConsensusProtocolVersion: status.Version,
+			ConsensusChainContext:    chainCtx,
+		},
+		SandboxBinaryPath: viper.GetString(cfgSandboxBinary),
+		InsecureNoSandbox: viper.GetBool(cfgInsecureNoSandbox),
+	})
+	if err != nil {
+		logger.Error("failed to create runtime provisioner",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	replayer, err := replay.New(replay.Config{
+		RuntimeID:   runtimeID,
+		RuntimePath: runtimePath,
+		Provisioner: provisioner,
+		Consensus:   cs,
+		Runtime:     runtimeClient.NewRuntimeClient(conn),
+		Storage:     storage.NewStorageClient(conn),
+	})
+	if err != nil {
+		logger.Error("failed to create replayer",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	res, err := replayer.Replay(ctx, viper.GetUint64(cfgRound))
+	if err != nil {
+		logger.Error("failed to replay round",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	pretty, err := cmdCommon.PrettyJSONMarshal(res)
+	if err != nil {
+		logger.Error("failed to marshal replay result",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(pretty))
+
+	if !res.IsDeterministic() {
+		os.Exit(2)
+	}
+}
+
+// Register registers the replay sub-command.
+func Register(parentCmd *cobra.Command) {
+	replayCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+	replayCmd.Flags().AddFlagSet(replayFlags)
+	parentCmd.AddCommand(replayCmd)
+}
+
+func init() {
+	replayFlags.String(cfgRuntimeID, "", "runtime identifier (hex-encoded)")
+	replayFlags.String(cfgRuntimePath, "", "path to the local runtime binary")
+	replayFlags.Uint64(cfgRound, 0, "runtime round to replay")
+	replayFlags.String(cfgSandboxBinary, "/usr/bin/bwrap", "path to the sandbox binary (bubblewrap)")
+	replayFlags.Bool(cfgInsecureNoSandbox, false, "run the runtime without a sandbox")
+	_ = viper.BindPFlags(replayFlags)
+}
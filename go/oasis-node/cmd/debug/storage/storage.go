@@ -13,6 +13,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/completion"
 	cmdControl "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/control"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	runtimeClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
@@ -216,6 +217,7 @@ func doCheckRoots(cmd *cobra.Command, args []string) {
 func Register(parentCmd *cobra.Command) {
 	storageCheckRootsCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
 	storageCheckRootsCmd.PersistentFlags().AddFlagSet(cmdFlags.DebugDontBlameOasisFlag)
+	storageCheckRootsCmd.ValidArgsFunction = completion.FirstArg(completion.RuntimeIDs)
 
 	storageExportCmd.Flags().AddFlagSet(storage.Flags)
 	storageExportCmd.Flags().AddFlagSet(cmdFlags.GenesisFileFlags)
@@ -224,8 +226,14 @@ func Register(parentCmd *cobra.Command) {
 
 	storageBenchmarkCmd.Flags().AddFlagSet(storageBenchmarkFlags)
 
+	storageVerifyCmd.Flags().StringVar(&verifyRuntimeID, cfgVerifyRuntimeID, "", "runtime identifier (hex)")
+	_ = storageVerifyCmd.MarkFlagRequired(cfgVerifyRuntimeID)
+	completion.RegisterFlag(storageVerifyCmd, cfgVerifyRuntimeID, completion.RuntimeIDs)
+	storageVerifyCmd.Flags().AddFlagSet(storage.Flags)
+
 	storageCmd.AddCommand(storageCheckRootsCmd)
 	storageCmd.AddCommand(storageExportCmd)
 	storageCmd.AddCommand(storageBenchmarkCmd)
+	storageCmd.AddCommand(storageVerifyCmd)
 	parentCmd.AddCommand(storageCmd)
 }
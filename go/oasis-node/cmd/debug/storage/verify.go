@@ -0,0 +1,366 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/runtime/history"
+	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
+	"github.com/oasisprotocol/oasis-core/go/storage/database"
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage"
+)
+
+const (
+	cfgVerifyRuntimeID = "runtime-id"
+
+	// maxVisitedNodes is the maximum number of visited node hashes remembered in order to avoid
+	// re-verifying subtrees shared between roots.
+	maxVisitedNodes = 3_000_000
+)
+
+// Kinds of storage verification problems.
+const (
+	problemMissingNode   = "missing_node"
+	problemCorruptedNode = "corrupted_node"
+	problemMissingRoot   = "missing_root"
+	problemUnknownRoot   = "unknown_root"
+)
+
+var (
+	verifyRuntimeID string
+
+	storageVerifyCmd = &cobra.Command{
+		Use:   "verify",
+		Short: "verify the integrity of a runtime's local storage (while the node is stopped)",
+		Run:   doVerify,
+	}
+)
+
+// verifyProblem is a storage integrity problem found during verification.
+type verifyProblem struct {
+	// Kind is the kind of the problem.
+	Kind string `json:"kind"`
+	// Root is the storage root affected by the problem.
+	Root node.Root `json:"root"`
+	// Hash is the hash of the affected node (if any).
+	Hash *hash.Hash `json:"hash,omitempty"`
+	// Error is the error that caused the problem (if any).
+	Error string `json:"error,omitempty"`
+}
+
+// verifyResult is the outcome of the storage verification.
+type verifyResult struct {
+	// RuntimeID is the runtime identifier.
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// EarliestVersion is the earliest retained version.
+	EarliestVersion uint64 `json:"earliest_version"`
+	// LatestVersion is the latest finalized version.
+	LatestVersion uint64 `json:"latest_version"`
+	// Roots is the number of verified roots.
+	Roots uint64 `json:"roots"`
+	// Nodes is the number of verified nodes.
+	Nodes uint64 `json:"nodes"`
+	// UnverifiedVersions is the number of versions without a block in the roothash history.
+	UnverifiedVersions uint64 `json:"unverified_versions"`
+	// Problems are the found problems.
+	Problems []*verifyProblem `json:"problems,omitempty"`
+}
+
+// blockHistory provides the runtime blocks against which the retained roots are verified.
+type blockHistory interface {
+	// GetBlock returns the block at a specific round.
+	GetBlock(ctx context.Context, round uint64) (*block.Block, error)
+}
+
+// lazyHistory is a block history that is only opened once a block is first requested, so that the
+// runtime history database is not opened (and locked) unless it is actually needed.
+type lazyHistory struct {
+	runtimeDir string
+	runtimeID  common.Namespace
+
+	hist history.History
+}
+
+// GetBlock implements blockHistory.
+func (h *lazyHistory) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	if h.hist == nil {
+		if _, err := os.Stat(filepath.Join(h.runtimeDir, history.DbFilename)); err != nil {
+			return nil, fmt.Errorf("runtime history not available: %w", err)
+		}
+		hist, err := history.New(h.runtimeDir, h.runtimeID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open runtime history: %w", err)
+		}
+		h.hist = hist
+	}
+	return h.hist.GetBlock(ctx, round)
+}
+
+// Close closes the runtime history database in case it has been opened.
+func (h *lazyHistory) Close() {
+	if h.hist != nil {
+		h.hist.Close()
+	}
+}
+
+// openNodeDB opens the node database of the configured storage backend in read-only mode.
+func openNodeDB(runtimeDir, backend string, runtimeID common.Namespace) (db.NodeDB, error) {
+	switch backend {
+	case database.BackendNameBadgerDB:
+	default:
+		return nil, fmt.Errorf("unsupported storage backend: %s", backend)
+	}
+
+	dbDir := storage.GetLocalBackendDBDir(runtimeDir, backend)
+	if _, err := os.Stat(dbDir); err != nil {
+		return nil, fmt.Errorf("node database not available: %w", err)
+	}
+	return badger.New(&db.Config{
+		DB:        dbDir,
+		Namespace: runtimeID,
+		ReadOnly:  true,
+	})
+}
+
+type storageVerifier struct {
+	ndb     db.NodeDB
+	history blockHistory
+
+	visited map[hash.Hash]bool
+	result  *verifyResult
+}
+
+func (v *storageVerifier) report(kind string, root node.Root, h *hash.Hash, err error) {
+	p := &verifyProblem{
+		Kind: kind,
+		Root: root,
+		Hash: h,
+	}
+	if err != nil {
+		p.Error = err.Error()
+	}
+	v.result.Problems = append(v.result.Problems, p)
+}
+
+func (v *storageVerifier) verifyNode(ctx context.Context, root node.Root, ptr *node.Pointer) error {
+	if ptr == nil || v.visited[ptr.Hash] {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Each node is only verified (and each problem reported) once, even if shared.
+	h := ptr.Hash
+	if len(v.visited) >= maxVisitedNodes {
+		v.visited = make(map[hash.Hash]bool)
+	}
+	v.visited[h] = true
+
+	nd := ptr.Node
+	if nd == nil {
+		var err error
+		nd, err = v.ndb.GetNode(root, ptr)
+		switch {
+		case err == nil:
+		case errors.Is(err, db.ErrNodeNotFound):
+			v.report(problemMissingNode, root, &h, nil)
+			return nil
+		default:
+			v.report(problemCorruptedNode, root, &h, err)
+			return nil
+		}
+	}
+
+	// Nodes are re-hashed when they are decoded.
+	if nodeHash := nd.GetHash(); !nodeHash.Equal(&h) {
+		v.report(problemCorruptedNode, root, &h, fmt.Errorf("hash mismatch (got: %s)", nodeHash))
+		return nil
+	}
+	v.result.Nodes++
+
+	if n, ok := nd.(*node.InternalNode); ok {
+		for _, child := range []*node.Pointer{n.LeafNode, n.Left, n.Right} {
+			if err := v.verifyNode(ctx, root, child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *storageVerifier) verifyVersion(ctx context.Context, version uint64) error {
+	roots, err := v.ndb.GetRootsForVersion(ctx, version)
+	if err != nil {
+		return fmt.Errorf("failed to get roots for version %d: %w", version, err)
+	}
+
+	for _, root := range roots {
+		if root.Hash.IsEmpty() {
+			continue
+		}
+		v.result.Roots++
+		if err = v.verifyNode(ctx, root, &node.Pointer{Clean: true, Hash: root.Hash}); err != nil {
+			return err
+		}
+	}
+
+	blk, err := v.history.GetBlock(ctx, version)
+	switch {
+	case err == nil:
+	case errors.Is(err, roothash.ErrNotFound):
+		v.result.UnverifiedVersions++
+		return nil
+	default:
+		return fmt.Errorf("failed to get block for round %d: %w", version, err)
+	}
+
+	blockRoots := blk.Header.StorageRoots()
+	for _, root := range blockRoots {
+		if !v.ndb.HasRoot(root) {
+			v.report(problemMissingRoot, root, nil, nil)
+		}
+	}
+	for _, root := range roots {
+		if root.Hash.IsEmpty() {
+			continue
+		}
+		var found bool
+		for i := range blockRoots {
+			if root.Equal(&blockRoots[i]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.report(problemUnknownRoot, root, nil, nil)
+		}
+	}
+	return nil
+}
+
+// verifyStorage verifies all retained roots of the given node database by walking and re-hashing
+// their nodes and comparing them against the roots of the runtime blocks in the history.
+func verifyStorage(ctx context.Context, ndb db.NodeDB, hist blockHistory, namespace common.Namespace) (*verifyResult, error) {
+	earliest, err := ndb.GetEarliestVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get earliest version: %w", err)
+	}
+	latest, err := ndb.GetLatestVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest version: %w", err)
+	}
+
+	v := &storageVerifier{
+		ndb:     ndb,
+		history: hist,
+		visited: make(map[hash.Hash]bool),
+		result: &verifyResult{
+			RuntimeID:       namespace,
+			EarliestVersion: earliest,
+			LatestVersion:   latest,
+		},
+	}
+	for version := earliest; version <= latest; version++ {
+		if err = v.verifyVersion(ctx, version); err != nil {
+			return nil, err
+		}
+	}
+	return v.result, nil
+}
+
+func printVerifyResult(result *verifyResult) {
+	fmt.Printf("Runtime:             %s\n", result.RuntimeID)
+	fmt.Printf("Versions:            %d-%d\n", result.EarliestVersion, result.LatestVersion)
+	fmt.Printf("Verified roots:      %d\n", result.Roots)
+	fmt.Printf("Verified nodes:      %d\n", result.Nodes)
+	fmt.Printf("Versions w/o blocks: %d\n", result.UnverifiedVersions)
+
+	if len(result.Problems) == 0 {
+		fmt.Println("No problems found.")
+		return
+	}
+	fmt.Printf("Problems:            %d\n", len(result.Problems))
+	for _, p := range result.Problems {
+		fmt.Printf("  %s: root %s", p.Kind, p.Root)
+		if p.Hash != nil {
+			fmt.Printf(" node %s", p.Hash)
+		}
+		if p.Error != "" {
+			fmt.Printf(" (%s)", p.Error)
+		}
+		fmt.Println()
+	}
+}
+
+func doVerify(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	dataDir := cmdCommon.DataDir()
+	if dataDir == "" {
+		logger.Error("data directory must be set")
+		os.Exit(1)
+	}
+
+	var runtimeID common.Namespace
+	if err := runtimeID.UnmarshalHex(verifyRuntimeID); err != nil {
+		logger.Error("malformed runtime identifier",
+			"err", err,
+			"runtime_id", verifyRuntimeID,
+		)
+		os.Exit(1)
+	}
+
+	runtimeDir := runtimeRegistry.GetRuntimeStateDir(dataDir, runtimeID)
+	ndb, err := openNodeDB(runtimeDir, strings.ToLower(viper.GetString(storage.CfgBackend)), runtimeID)
+	if err != nil {
+		logger.Error("failed to open node database (is the node still running?)",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer ndb.Close()
+
+	hist := &lazyHistory{
+		runtimeDir: runtimeDir,
+		runtimeID:  runtimeID,
+	}
+	defer hist.Close()
+
+	result, err := verifyStorage(context.Background(), ndb, hist, runtimeID)
+	if err != nil {
+		logger.Error("failed to verify storage",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	if err = cmdCommon.PrintResult(result, func() {
+		printVerifyResult(result)
+	}); err != nil {
+		logger.Error("failed to print verification result",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if len(result.Problems) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/runtime/history"
+	"github.com/oasisprotocol/oasis-core/go/storage/database"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage"
+)
+
+type testBlockHistory map[uint64]*block.Block
+
+func (h testBlockHistory) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	blk, ok := h[round]
+	if !ok {
+		return nil, roothash.ErrNotFound
+	}
+	return blk, nil
+}
+
+// corruptingNodeDB is a node database which returns a corrupted copy of a given node.
+type corruptingNodeDB struct {
+	db.NodeDB
+
+	corrupt hash.Hash
+}
+
+func (d *corruptingNodeDB) GetNode(root node.Root, ptr *node.Pointer) (node.Node, error) {
+	n, err := d.NodeDB.GetNode(root, ptr)
+	if err != nil || !ptr.Hash.Equal(&d.corrupt) {
+		return n, err
+	}
+	leaf := n.(*node.LeafNode)
+	corrupted := &node.LeafNode{
+		Clean: true,
+		Key:   leaf.Key,
+		Value: append([]byte("corrupted "), leaf.Value...),
+	}
+	corrupted.UpdateHash()
+	return corrupted, nil
+}
+
+func TestVerifyStorage(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	ns := common.NewTestNamespaceFromSeed([]byte("storage verify test ns"), 0)
+	ndb, err := badger.New(&db.Config{
+		Namespace:    ns,
+		MaxCacheSize: 16 * 1024 * 1024,
+		NoFsync:      true,
+		MemoryOnly:   true,
+	})
+	require.NoError(err, "badger.New")
+	defer ndb.Close()
+
+	// Commit and finalize a few versions of the state.
+	hist := make(testBlockHistory)
+	tree := mkvs.New(nil, ndb, node.RootTypeState)
+	for version := uint64(0); version < 3; version++ {
+		err = tree.Insert(ctx, []byte{byte(version)}, []byte("value"))
+		require.NoError(err, "Insert")
+		_, rootHash, cerr := tree.Commit(ctx, ns, version)
+		require.NoError(cerr, "Commit")
+
+		root := node.Root{
+			Namespace: ns,
+			Version:   version,
+			Type:      node.RootTypeState,
+			Hash:      rootHash,
+		}
+		err = ndb.Finalize(ctx, []node.Root{root})
+		require.NoError(err, "Finalize")
+
+		blk := block.NewGenesisBlock(ns, 0)
+		blk.Header.Round = version
+		blk.Header.StateRoot = rootHash
+		hist[version] = blk
+	}
+	leaf := &node.LeafNode{Key: []byte{0}, Value: []byte("value")}
+	leaf.UpdateHash()
+	leafHash := leaf.GetHash()
+
+	result, err := verifyStorage(ctx, ndb, hist, ns)
+	require.NoError(err, "verifyStorage")
+	require.EqualValues(0, result.EarliestVersion)
+	require.EqualValues(2, result.LatestVersion)
+	require.EqualValues(3, result.Roots)
+	require.NotZero(result.Nodes)
+	require.Empty(result.Problems)
+
+	// Corrupted nodes are detected by re-hashing.
+	result, err = verifyStorage(ctx, &corruptingNodeDB{NodeDB: ndb, corrupt: leafHash}, hist, ns)
+	require.NoError(err, "verifyStorage")
+	require.Len(result.Problems, 1)
+	require.Equal(problemCorruptedNode, result.Problems[0].Kind)
+	require.Equal(leafHash, *result.Problems[0].Hash)
+
+	// Roots are verified against the history.
+	hist[1].Header.StateRoot.FromBytes([]byte("bogus state root"))
+	delete(hist, 2)
+	result, err = verifyStorage(ctx, ndb, hist, ns)
+	require.NoError(err, "verifyStorage")
+	require.EqualValues(1, result.UnverifiedVersions)
+	require.Len(result.Problems, 2)
+	require.Equal(problemMissingRoot, result.Problems[0].Kind)
+	require.Equal(hist[1].Header.StateRoot, result.Problems[0].Root.Hash)
+	require.Equal(problemUnknownRoot, result.Problems[1].Kind)
+	require.EqualValues(1, result.Problems[1].Root.Version)
+}
+
+func TestVerifyOpenLazily(t *testing.T) {
+	require := require.New(t)
+
+	ns := common.NewTestNamespaceFromSeed([]byte("storage verify test ns"), 0)
+	dir := t.TempDir()
+
+	_, err := openNodeDB(dir, "unknown", ns)
+	require.Error(err, "openNodeDB should fail for unsupported backends")
+	_, err = openNodeDB(dir, database.BackendNameBadgerDB, ns)
+	require.Error(err, "openNodeDB should fail for missing databases")
+	_, err = os.Stat(storage.GetLocalBackendDBDir(dir, database.BackendNameBadgerDB))
+	require.True(os.IsNotExist(err), "openNodeDB should not create the node database")
+
+	// The history is only opened when a block is requested.
+	hist := &lazyHistory{runtimeDir: dir, runtimeID: ns}
+	defer hist.Close()
+	require.Nil(hist.hist)
+	_, err = hist.GetBlock(context.Background(), 0)
+	require.Error(err, "GetBlock should fail for missing histories")
+	_, err = os.Stat(filepath.Join(dir, history.DbFilename))
+	require.True(os.IsNotExist(err), "GetBlock should not create the history database")
+}
@@ -0,0 +1,139 @@
+// Package txpool implements the transaction pool debug sub-commands.
+package txpool
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/completion"
+	cmdControl "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/control"
+)
+
+const cfgRuntimeID = "runtime.id"
+
+var (
+	runtimeIDHex string
+
+	txPoolCmd = &cobra.Command{
+		Use:   "txpool",
+		Short: "inspect and manipulate a runtime's transaction pool on a running node",
+	}
+
+	txPoolStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "show the transaction pool status",
+		Run:   doStatus,
+	}
+
+	txPoolListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "list the transactions waiting to be scheduled",
+		Run:   doList,
+	}
+
+	txPoolRemoveCmd = &cobra.Command{
+		Use:   "remove <tx-hash>...",
+		Short: "remove transactions from the transaction pool",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   doRemove,
+	}
+
+	logger = logging.GetLogger("cmd/debug/txpool")
+)
+
+func parseRuntimeID() common.Namespace {
+	var runtimeID common.Namespace
+	if err := runtimeID.UnmarshalHex(runtimeIDHex); err != nil {
+		logger.Error("malformed runtime identifier",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	return runtimeID
+}
+
+func printJSON(what string, v interface{}) {
+	if err := cmdCommon.PrintResult(v, nil); err != nil {
+		logger.Error("failed to print "+what,
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doStatus(cmd *cobra.Command, args []string) {
+	conn, client := cmdControl.DoConnect(cmd)
+	defer conn.Close()
+
+	status, err := client.GetTxPoolStatus(context.Background(), parseRuntimeID())
+	if err != nil {
+		logger.Error("failed to query transaction pool status",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	printJSON("transaction pool status", status)
+}
+
+func doList(cmd *cobra.Command, args []string) {
+	conn, client := cmdControl.DoConnect(cmd)
+	defer conn.Close()
+
+	txs, err := client.GetTxPoolTransactions(context.Background(), parseRuntimeID())
+	if err != nil {
+		logger.Error("failed to query transaction pool transactions",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	printJSON("transaction pool transactions", txs)
+}
+
+func doRemove(cmd *cobra.Command, args []string) {
+	req := control.RemoveTxPoolTransactionsRequest{
+		RuntimeID: parseRuntimeID(),
+	}
+	for _, raw := range args {
+		var h hash.Hash
+		if err := h.UnmarshalHex(raw); err != nil {
+			logger.Error("malformed transaction hash",
+				"err", err,
+				"hash", raw,
+			)
+			os.Exit(1)
+		}
+		req.Hashes = append(req.Hashes, h)
+	}
+
+	conn, client := cmdControl.DoConnect(cmd)
+	defer conn.Close()
+
+	removed, err := client.RemoveTxPoolTransactions(context.Background(), &req)
+	if err != nil {
+		logger.Error("failed to remove transactions from the transaction pool",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	printJSON("removed transactions", removed)
+}
+
+// Register registers the txpool sub-command and all of its children.
+func Register(parentCmd *cobra.Command) {
+	txPoolCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
+	txPoolCmd.PersistentFlags().StringVar(&runtimeIDHex, cfgRuntimeID, "", "runtime identifier (hex-encoded)")
+	completion.RegisterFlag(txPoolCmd, cfgRuntimeID, completion.RuntimeIDs)
+
+	txPoolCmd.AddCommand(txPoolStatusCmd)
+	txPoolCmd.AddCommand(txPoolListCmd)
+	txPoolCmd.AddCommand(txPoolRemoveCmd)
+	parentCmd.AddCommand(txPoolCmd)
+}
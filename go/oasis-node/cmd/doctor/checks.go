@@ -0,0 +1,308 @@
+package doctor
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	tmCommon "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/common"
+	tmFull "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/full"
+	genesisFile "github.com/oasisprotocol/oasis-core/go/genesis/file"
+	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
+	workerCommon "github.com/oasisprotocol/oasis-core/go/worker/common"
+)
+
+const (
+	checkFileDescriptorsName = "file_descriptors"
+	checkDataDirName         = "data_dir"
+	checkRuntimeBundlesName  = "runtime_bundles"
+	checkReachabilityName    = "reachability"
+	checkClockSkewName       = "clock_skew"
+	checkGenesisName         = "genesis"
+
+	// minFileDescriptors is the recommended minimum limit of open file descriptors.
+	minFileDescriptors = 65536
+
+	// maxClockSkew is the maximum tolerated difference between the local clock and the timestamp
+	// of the latest consensus block.
+	maxClockSkew = 5 * time.Second
+	// maxBlockAge is the maximum tolerated age of the latest consensus block of a synced node.
+	maxBlockAge = 2 * time.Minute
+)
+
+func checkFileDescriptors() []*Finding {
+	var rlim syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rlim); err != nil {
+		return []*Finding{{
+			Check:    checkFileDescriptorsName,
+			Severity: SeveritySkipped,
+			Message:  fmt.Sprintf("failed to query file descriptor limit: %s", err),
+		}}
+	}
+	return []*Finding{evaluateFileDescriptorLimit(rlim.Cur)}
+}
+
+func evaluateFileDescriptorLimit(limit uint64) *Finding {
+	if limit < minFileDescriptors {
+		return &Finding{
+			Check:    checkFileDescriptorsName,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("open file descriptor limit is %d, below the recommended %d", limit, minFileDescriptors),
+			Action:   fmt.Sprintf("raise the limit (e.g., 'ulimit -n %d' or LimitNOFILE in the systemd unit)", minFileDescriptors),
+		}
+	}
+	return &Finding{
+		Check:    checkFileDescriptorsName,
+		Severity: SeverityOK,
+		Message:  fmt.Sprintf("open file descriptor limit is %d", limit),
+	}
+}
+
+func checkDataDir(dataDir string) []*Finding {
+	if dataDir == "" {
+		return []*Finding{{
+			Check:    checkDataDirName,
+			Severity: SeverityError,
+			Message:  "data directory is not configured",
+			Action:   "set the datadir configuration option",
+		}}
+	}
+
+	fi, err := os.Stat(dataDir)
+	switch {
+	case os.IsNotExist(err):
+		return []*Finding{{
+			Check:    checkDataDirName,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("data directory '%s' does not exist", dataDir),
+			Action:   "make sure the datadir configuration option points to the node's data directory",
+		}}
+	case err != nil:
+		return []*Finding{{
+			Check:    checkDataDirName,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("failed to access data directory '%s': %s", dataDir, err),
+			Action:   "make sure the data directory is accessible by the user running the node",
+		}}
+	case !fi.IsDir():
+		return []*Finding{{
+			Check:    checkDataDirName,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("data directory '%s' is not a directory", dataDir),
+		}}
+	}
+
+	// Reuse the checks performed when the node creates its data directory.
+	if err = common.CheckDir(dataDir); err != nil {
+		return []*Finding{{
+			Check:    checkDataDirName,
+			Severity: SeverityError,
+			Message:  err.Error(),
+			Action:   fmt.Sprintf("run 'chmod 700 %s' and make sure it is owned by the user running the node", dataDir),
+		}}
+	}
+	return []*Finding{{
+		Check:    checkDataDirName,
+		Severity: SeverityOK,
+		Message:  fmt.Sprintf("data directory '%s' has correct permissions", dataDir),
+	}}
+}
+
+func checkRuntimeBundles() []*Finding {
+	paths := viper.GetStringMapString(runtimeRegistry.CfgRuntimePaths)
+	supported := viper.GetStringSlice(runtimeRegistry.CfgSupported)
+	if len(paths) == 0 && len(supported) == 0 {
+		return []*Finding{{
+			Check:    checkRuntimeBundlesName,
+			Severity: SeverityOK,
+			Message:  "no runtimes configured",
+		}}
+	}
+
+	var findings []*Finding
+	for _, rawID := range supported {
+		var id common.Namespace
+		if err := id.UnmarshalHex(rawID); err != nil {
+			findings = append(findings, &Finding{
+				Check:    checkRuntimeBundlesName,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("malformed supported runtime identifier '%s': %s", rawID, err),
+			})
+			continue
+		}
+		if _, ok := paths[strings.ToLower(rawID)]; !ok {
+			findings = append(findings, &Finding{
+				Check:    checkRuntimeBundlesName,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("no runtime path configured for supported runtime %s", id),
+				Action:   fmt.Sprintf("add the runtime to %s", runtimeRegistry.CfgRuntimePaths),
+			})
+		}
+	}
+
+	ids := make([]string, 0, len(paths))
+	for id := range paths {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		path := paths[id]
+		fi, err := os.Stat(path)
+		switch {
+		case err != nil:
+			findings = append(findings, &Finding{
+				Check:    checkRuntimeBundlesName,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("runtime %s: failed to access '%s': %s", id, path, err),
+				Action:   "make sure the configured runtime path exists and is readable",
+			})
+		case !fi.Mode().IsRegular():
+			findings = append(findings, &Finding{
+				Check:    checkRuntimeBundlesName,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("runtime %s: '%s' is not a regular file", id, path),
+			})
+		default:
+			findings = append(findings, &Finding{
+				Check:    checkRuntimeBundlesName,
+				Severity: SeverityOK,
+				Message: fmt.Sprintf("runtime %s: '%s' (modified %s)",
+					id, path, fi.ModTime().UTC().Format(time.RFC3339),
+				),
+			})
+		}
+	}
+	return findings
+}
+
+func checkReachability(timeout time.Duration) []*Finding {
+	type peer struct {
+		kind string
+		addr string
+	}
+	var peers []peer
+	for _, addr := range viper.GetStringSlice(workerCommon.CfgSentryAddresses) {
+		peers = append(peers, peer{"sentry node", addr})
+	}
+	if addr := viper.GetString(tmFull.CfgSentryUpstreamAddress); addr != "" {
+		peers = append(peers, peer{"sentry upstream node", addr})
+	}
+	for _, addr := range viper.GetStringSlice(tmCommon.CfgP2PSeed) {
+		peers = append(peers, peer{"seed node", addr})
+	}
+	if len(peers) == 0 {
+		return []*Finding{{
+			Check:    checkReachabilityName,
+			Severity: SeverityOK,
+			Message:  "no sentry or seed nodes configured",
+		}}
+	}
+
+	var findings []*Finding
+	for _, p := range peers {
+		findings = append(findings, checkAddressReachable(p.kind, p.addr, timeout))
+	}
+	return findings
+}
+
+// checkAddressReachable checks whether a TCP connection can be established to the given address
+// which is in the <id>@<host>:<port> format.
+func checkAddressReachable(kind, addr string, timeout time.Duration) *Finding {
+	hostPort := addr
+	if idx := strings.LastIndex(addr, "@"); idx >= 0 {
+		hostPort = addr[idx+1:]
+	}
+
+	conn, err := net.DialTimeout("tcp", hostPort, timeout)
+	if err != nil {
+		return &Finding{
+			Check:    checkReachabilityName,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("%s %s is unreachable: %s", kind, hostPort, err),
+			Action:   "check the configured address and any firewalls between this node and the remote node",
+		}
+	}
+	_ = conn.Close()
+
+	return &Finding{
+		Check:    checkReachabilityName,
+		Severity: SeverityOK,
+		Message:  fmt.Sprintf("%s %s is reachable", kind, hostPort),
+	}
+}
+
+func checkClockSkew(now, latestBlockTime time.Time, synced bool) *Finding {
+	if skew := latestBlockTime.Sub(now); skew > maxClockSkew {
+		return &Finding{
+			Check:    checkClockSkewName,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("latest consensus block is %s in the future, the local clock is behind", skew.Round(time.Second)),
+			Action:   "synchronize the system clock (e.g., enable NTP)",
+		}
+	}
+	if age := now.Sub(latestBlockTime); synced && age > maxBlockAge {
+		return &Finding{
+			Check:    checkClockSkewName,
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf("node is synced but the latest consensus block is %s old, "+
+				"either the local clock is ahead or the network is stalled", age.Round(time.Second)),
+			Action: "synchronize the system clock (e.g., enable NTP) and check the network status",
+		}
+	}
+	return &Finding{
+		Check:    checkClockSkewName,
+		Severity: SeverityOK,
+		Message:  "local clock is consistent with the latest consensus block",
+	}
+}
+
+func checkGenesis(genesisPath, chainContext string) *Finding {
+	if genesisPath == "" {
+		return &Finding{
+			Check:    checkGenesisName,
+			Severity: SeveritySkipped,
+			Message:  "genesis file is not configured",
+		}
+	}
+
+	provider, err := genesisFile.NewFileProvider(genesisPath)
+	if err != nil {
+		return &Finding{
+			Check:    checkGenesisName,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("failed to load genesis file '%s': %s", genesisPath, err),
+			Action:   "make sure the configured genesis file exists and is valid",
+		}
+	}
+	doc, err := provider.GetGenesisDocument()
+	if err != nil {
+		return &Finding{
+			Check:    checkGenesisName,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("failed to load genesis document: %s", err),
+		}
+	}
+
+	if doc.ChainContext() != chainContext {
+		return &Finding{
+			Check:    checkGenesisName,
+			Severity: SeverityError,
+			Message: fmt.Sprintf("genesis file '%s' (chain context %s) does not match the running node (chain context %s)",
+				genesisPath, doc.ChainContext(), chainContext,
+			),
+			Action: "make sure the node is configured with the genesis file of the network it should join",
+		}
+	}
+	return &Finding{
+		Check:    checkGenesisName,
+		Severity: SeverityOK,
+		Message:  fmt.Sprintf("genesis file matches the running node (chain context %s)", chainContext),
+	}
+}
@@ -0,0 +1,169 @@
+// Package doctor implements the doctor sub-command.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
+	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+)
+
+const (
+	// CfgFormat configures the output format.
+	//
+	// Deprecated: Use the global output format flag instead.
+	CfgFormat = "doctor.format"
+	// CfgTimeout configures the timeout of network checks.
+	CfgTimeout = "doctor.timeout"
+)
+
+var (
+	doctorCmd = &cobra.Command{
+		Use:   "doctor",
+		Short: "check the node for common misconfigurations",
+		Long: "Checks the node configuration and environment for common misconfigurations " +
+			"(clock skew, file descriptor limits, data directory permissions, genesis document " +
+			"mismatch, unreachable sentry and seed nodes, missing runtime bundles) and prints " +
+			"actionable findings. Checks that require a running node are skipped in case the " +
+			"node's internal socket is not reachable.",
+		Run: doDoctor,
+	}
+
+	doctorFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	logger = logging.GetLogger("cmd/doctor")
+)
+
+// Severity is the severity of a finding.
+type Severity string
+
+const (
+	// SeverityOK indicates that the check passed.
+	SeverityOK Severity = "ok"
+	// SeverityWarning indicates a potential problem.
+	SeverityWarning Severity = "warning"
+	// SeverityError indicates a problem that will likely prevent the node from working correctly.
+	SeverityError Severity = "error"
+	// SeveritySkipped indicates that the check could not be performed.
+	SeveritySkipped Severity = "skipped"
+)
+
+// Finding is the result of a single check.
+type Finding struct {
+	// Check is the name of the check.
+	Check string `json:"check"`
+	// Severity is the severity of the finding.
+	Severity Severity `json:"severity"`
+	// Message describes the finding.
+	Message string `json:"message"`
+	// Action is the suggested action to resolve the problem.
+	Action string `json:"action,omitempty"`
+}
+
+func doDoctor(cmd *cobra.Command, args []string) {
+	// Make sure not to create or fix up the data directory (or raise the file descriptor limit)
+	// before it is checked.
+	if err := cmdCommon.InitReadOnly(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	timeout := viper.GetDuration(CfgTimeout)
+
+	var findings []*Finding
+	findings = append(findings, checkFileDescriptors()...)
+	findings = append(findings, checkDataDir(cmdCommon.DataDir())...)
+	findings = append(findings, checkRuntimeBundles()...)
+	findings = append(findings, checkReachability(timeout)...)
+	findings = append(findings, checkNode(cmd, timeout)...)
+
+	if err := cmdCommon.PrintResult(findings, func() {
+		for _, f := range findings {
+			fmt.Printf("%-9s %s: %s\n", "["+strings.ToUpper(string(f.Severity))+"]", f.Check, f.Message)
+			if f.Action != "" {
+				fmt.Printf("          -> %s\n", f.Action)
+			}
+		}
+	}); err != nil {
+		logger.Error("failed to print findings",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			os.Exit(1)
+		}
+	}
+}
+
+// checkNode runs the checks that require a running node.
+func checkNode(cmd *cobra.Command, timeout time.Duration) []*Finding {
+	nodeChecks := []string{checkClockSkewName, checkGenesisName}
+	skipAll := func(msg string) []*Finding {
+		var findings []*Finding
+		for _, name := range nodeChecks {
+			findings = append(findings, &Finding{
+				Check:    name,
+				Severity: SeveritySkipped,
+				Message:  msg,
+			})
+		}
+		return findings
+	}
+
+	if !cmd.Flags().Changed(cmdGrpc.CfgAddress) {
+		// Default to the internal socket in the node's data directory.
+		addr := "unix:" + filepath.Join(cmdCommon.DataDir(), cmdGrpc.LocalSocketFilename)
+		_ = cmd.Flags().Set(cmdGrpc.CfgAddress, addr)
+	}
+	conn, err := cmdGrpc.NewClient(cmd)
+	if err != nil {
+		return skipAll(fmt.Sprintf("failed to connect to node: %s", err))
+	}
+	defer conn.Close()
+	client := control.NewNodeControllerClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	status, err := client.GetStatus(ctx)
+	if err != nil {
+		return skipAll(fmt.Sprintf("failed to query node status: %s", err))
+	}
+	synced, err := client.IsSynced(ctx)
+	if err != nil {
+		return skipAll(fmt.Sprintf("failed to query node sync status: %s", err))
+	}
+
+	var findings []*Finding
+	findings = append(findings, checkClockSkew(time.Now(), status.Consensus.LatestTime, synced))
+	findings = append(findings, checkGenesis(cmdFlags.GenesisFile(), status.Consensus.ChainContext))
+	return findings
+}
+
+// Register registers the doctor sub-command.
+func Register(parentCmd *cobra.Command) {
+	doctorCmd.Flags().AddFlagSet(doctorFlags)
+	doctorCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+	doctorCmd.Flags().AddFlagSet(cmdFlags.GenesisFileFlags)
+	parentCmd.AddCommand(doctorCmd)
+}
+
+func init() {
+	cmdCommon.AddDeprecatedFormatFlag(doctorFlags, CfgFormat)
+	doctorFlags.Duration(CfgTimeout, 5*time.Second, "timeout of checks involving network requests")
+	_ = viper.BindPFlags(doctorFlags)
+}
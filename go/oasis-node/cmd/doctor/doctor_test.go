@@ -0,0 +1,73 @@
+package doctor
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileDescriptorLimit(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal(SeverityWarning, evaluateFileDescriptorLimit(1024).Severity)
+	require.Equal(SeverityOK, evaluateFileDescriptorLimit(minFileDescriptors).Severity)
+}
+
+func TestDataDir(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := os.MkdirTemp("", "oasis-doctor-test")
+	require.NoError(err, "MkdirTemp")
+	defer os.RemoveAll(dir)
+
+	dataDir := filepath.Join(dir, "data")
+	findings := checkDataDir(dataDir)
+	require.Len(findings, 1)
+	require.Equal(SeverityWarning, findings[0].Severity, "missing data directory")
+	_, err = os.Stat(dataDir)
+	require.True(os.IsNotExist(err), "check should not create the data directory")
+
+	err = os.Mkdir(dataDir, 0o755)
+	require.NoError(err, "Mkdir")
+	findings = checkDataDir(dataDir)
+	require.Len(findings, 1)
+	require.Equal(SeverityError, findings[0].Severity, "data directory with invalid permissions")
+	require.NotEmpty(findings[0].Action)
+
+	err = os.Chmod(dataDir, 0o700)
+	require.NoError(err, "Chmod")
+	findings = checkDataDir(dataDir)
+	require.Len(findings, 1)
+	require.Equal(SeverityOK, findings[0].Severity, "valid data directory")
+}
+
+func TestClockSkew(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	require.Equal(SeverityOK, checkClockSkew(now, now.Add(-time.Second), true).Severity)
+	require.Equal(SeverityError, checkClockSkew(now, now.Add(time.Minute), true).Severity)
+	require.Equal(SeverityWarning, checkClockSkew(now, now.Add(-time.Hour), true).Severity)
+	require.Equal(SeverityOK, checkClockSkew(now, now.Add(-time.Hour), false).Severity,
+		"old blocks are expected while syncing",
+	)
+}
+
+func TestAddressReachable(t *testing.T) {
+	require := require.New(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err, "Listen")
+	addr := ln.Addr().String()
+
+	f := checkAddressReachable("seed node", "0123456789abcdef@"+addr, time.Second)
+	require.Equal(SeverityOK, f.Severity)
+
+	_ = ln.Close()
+	f = checkAddressReachable("seed node", addr, time.Second)
+	require.Equal(SeverityError, f.Severity)
+}
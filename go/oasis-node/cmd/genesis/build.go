@@ -0,0 +1,208 @@
+package genesis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	"github.com/oasisprotocol/oasis-core/go/genesis/builder"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	keymanager "github.com/oasisprotocol/oasis-core/go/keymanager/api"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// buildSpec is the specification of a genesis document composed by the build command.
+//
+// Paths are relative to the directory containing the specification file.
+type buildSpec struct {
+	ChainID     string            `json:"chain_id"`
+	GenesisTime time.Time         `json:"genesis_time"`
+	Height      int64             `json:"height,omitempty"`
+	HaltEpoch   *beacon.EpochTime `json:"halt_epoch,omitempty"`
+
+	Registry struct {
+		Parameters registry.ConsensusParameters `json:"params"`
+		// Entities are paths to signed entity registrations.
+		Entities []string `json:"entities,omitempty"`
+		// Runtimes are paths to runtime descriptors.
+		Runtimes []string `json:"runtimes,omitempty"`
+		// Nodes are paths to signed node registrations.
+		Nodes []string `json:"nodes,omitempty"`
+	} `json:"registry"`
+
+	RootHash struct {
+		Parameters roothash.ConsensusParameters `json:"params"`
+		// RuntimeStates are paths to exported runtime states.
+		RuntimeStates []string `json:"runtime_states,omitempty"`
+	} `json:"roothash"`
+
+	// Staking is the path to the staking genesis state.
+	Staking string `json:"staking"`
+	// KeyManager are paths to key manager statuses.
+	KeyManager []string `json:"keymanager,omitempty"`
+
+	Scheduler  scheduler.Genesis        `json:"scheduler"`
+	Beacon     beacon.Genesis           `json:"beacon"`
+	Governance governance.Genesis       `json:"governance"`
+	Consensus  consensusGenesis.Genesis `json:"consensus"`
+}
+
+// buildGenesisDocument composes a genesis document from the given specification file.
+func buildGenesisDocument(specFn string) (*genesis.Document, error) {
+	var spec buildSpec
+	if err := loadJSONFile(specFn, &spec); err != nil {
+		return nil, fmt.Errorf("failed to load build specification: %w", err)
+	}
+	if spec.GenesisTime.IsZero() {
+		return nil, fmt.Errorf("genesis time missing from build specification")
+	}
+
+	baseDir := filepath.Dir(specFn)
+	path := func(fn string) string {
+		if filepath.IsAbs(fn) {
+			return fn
+		}
+		return filepath.Join(baseDir, fn)
+	}
+
+	b := builder.New(spec.ChainID, spec.GenesisTime)
+	if spec.Height != 0 {
+		b.SetHeight(spec.Height)
+	}
+	if spec.HaltEpoch != nil {
+		b.SetHaltEpoch(*spec.HaltEpoch)
+	}
+	b.SetRegistryParameters(spec.Registry.Parameters)
+	b.SetRootHashParameters(spec.RootHash.Parameters)
+	b.SetScheduler(spec.Scheduler)
+	b.SetBeacon(spec.Beacon)
+	b.SetGovernance(spec.Governance)
+	b.SetConsensus(spec.Consensus)
+
+	for _, fn := range spec.Registry.Entities {
+		var sigEnt entity.SignedEntity
+		if err := loadJSONFile(path(fn), &sigEnt); err != nil {
+			return nil, fmt.Errorf("failed to load entity: %w", err)
+		}
+		if err := b.AddEntity(&sigEnt); err != nil {
+			return nil, fmt.Errorf("%s: %w", fn, err)
+		}
+	}
+	for _, fn := range spec.Registry.Runtimes {
+		var rt registry.Runtime
+		if err := loadJSONFile(path(fn), &rt); err != nil {
+			return nil, fmt.Errorf("failed to load runtime: %w", err)
+		}
+		if err := b.AddRuntime(&rt); err != nil {
+			return nil, fmt.Errorf("%s: %w", fn, err)
+		}
+	}
+	for _, fn := range spec.Registry.Nodes {
+		var sigNode node.MultiSignedNode
+		if err := loadJSONFile(path(fn), &sigNode); err != nil {
+			return nil, fmt.Errorf("failed to load node: %w", err)
+		}
+		if err := b.AddNode(&sigNode); err != nil {
+			return nil, fmt.Errorf("%s: %w", fn, err)
+		}
+	}
+	for _, fn := range spec.RootHash.RuntimeStates {
+		var rtStates map[common.Namespace]*roothash.GenesisRuntimeState
+		if err := loadJSONFile(path(fn), &rtStates); err != nil {
+			return nil, fmt.Errorf("failed to load runtime states: %w", err)
+		}
+		for id, st := range rtStates {
+			if err := b.AddRuntimeState(id, st); err != nil {
+				return nil, fmt.Errorf("%s: %w", fn, err)
+			}
+		}
+	}
+	for _, fn := range spec.KeyManager {
+		var status keymanager.Status
+		if err := loadJSONFile(path(fn), &status); err != nil {
+			return nil, fmt.Errorf("failed to load key manager status: %w", err)
+		}
+		if err := b.AddKeyManagerStatus(&status); err != nil {
+			return nil, fmt.Errorf("%s: %w", fn, err)
+		}
+	}
+	if spec.Staking != "" {
+		var st staking.Genesis
+		if err := loadJSONFile(path(spec.Staking), &st); err != nil {
+			return nil, fmt.Errorf("failed to load staking state: %w", err)
+		}
+		b.SetStaking(st)
+	}
+
+	return b.Build()
+}
+
+func loadJSONFile(fn string, v interface{}) error {
+	b, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%s: %w", fn, err)
+	}
+	return nil
+}
+
+func doBuildGenesis(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	f := flags.GenesisFile()
+	if len(f) == 0 {
+		logger.Error("failed to determine output location")
+		os.Exit(1)
+	}
+
+	doc, err := buildGenesisDocument(args[0])
+	if err != nil {
+		var verr *builder.ValidationError
+		if errors.As(err, &verr) {
+			fmt.Fprintf(os.Stderr, "genesis document failed validation (%d violations):\n", len(verr.Errors))
+			for _, err := range verr.Errors {
+				fmt.Fprintf(os.Stderr, "  - %s\n", err)
+			}
+			os.Exit(1)
+		}
+		logger.Error("failed to build genesis document",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	canonJSON, err := doc.CanonicalJSON()
+	if err != nil {
+		logger.Error("failed to get canonical form of genesis file",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err = ioutil.WriteFile(f, canonJSON, 0o600); err != nil {
+		logger.Error("failed to write genesis file",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,342 @@
+package genesis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	keymanager "github.com/oasisprotocol/oasis-core/go/keymanager/api"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+)
+
+const (
+	// cfgDiffFormat is the deprecated per-command output format flag.
+	cfgDiffFormat = "diff.format"
+
+	// diffModuleGenesis is the pseudo-module containing the top-level genesis document fields.
+	diffModuleGenesis = "genesis"
+)
+
+// diffModules are the genesis document modules in the order in which they are diffed.
+var diffModules = []string{
+	diffModuleGenesis,
+	"registry",
+	"roothash",
+	"staking",
+	"keymanager",
+	"scheduler",
+	"beacon",
+	"governance",
+	"consensus",
+}
+
+// genesisChangeKind is the kind of a genesis document change.
+type genesisChangeKind string
+
+const (
+	genesisChangeAdded   genesisChangeKind = "added"
+	genesisChangeRemoved genesisChangeKind = "removed"
+	genesisChangeChanged genesisChangeKind = "changed"
+)
+
+// genesisChange is a single change between two genesis documents.
+type genesisChange struct {
+	// Kind is the kind of the change.
+	Kind genesisChangeKind `json:"kind"`
+	// Path is the dot-separated path of the changed field within the module.
+	Path string `json:"path"`
+	// Old is the old value (if any).
+	Old interface{} `json:"old,omitempty"`
+	// New is the new value (if any).
+	New interface{} `json:"new,omitempty"`
+}
+
+// genesisModuleDiff are the changes of a single genesis document module.
+type genesisModuleDiff struct {
+	// Module is the name of the module.
+	Module string `json:"module"`
+	// Changes are the changes of the module.
+	Changes []*genesisChange `json:"changes"`
+}
+
+func doDiffGenesis(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	var docs [2]*genesis.Document
+	for i, fn := range args {
+		doc, err := loadGenesisDocument(fn)
+		if err != nil {
+			logger.Error("failed to load genesis document",
+				"err", err,
+				"filename", fn,
+			)
+			os.Exit(1)
+		}
+		docs[i] = doc
+	}
+
+	diffs, err := diffGenesisDocuments(docs[0], docs[1])
+	if err != nil {
+		logger.Error("failed to diff genesis documents",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	if err = cmdCommon.PrintResult(diffs, func() {
+		if len(diffs) == 0 {
+			fmt.Println("genesis documents are equal")
+			return
+		}
+		fmt.Printf("chain context: %s -> %s\n", docs[0].ChainContext(), docs[1].ChainContext())
+		for _, md := range diffs {
+			fmt.Printf("\n%s (%d changes):\n", md.Module, len(md.Changes))
+			for _, c := range md.Changes {
+				switch c.Kind {
+				case genesisChangeAdded:
+					fmt.Printf("  + %s: %s\n", c.Path, formatDiffValue(c.New))
+				case genesisChangeRemoved:
+					fmt.Printf("  - %s: %s\n", c.Path, formatDiffValue(c.Old))
+				default:
+					fmt.Printf("  ~ %s: %s -> %s\n", c.Path, formatDiffValue(c.Old), formatDiffValue(c.New))
+				}
+			}
+		}
+	}); err != nil {
+		logger.Error("failed to print genesis diff",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+// loadGenesisDocument loads a genesis document without performing any sanity checks so that
+// documents produced by older versions can still be compared.
+func loadGenesisDocument(fn string) (*genesis.Document, error) {
+	return genesis.ReadDocumentFile(fn)
+}
+
+// diffGenesisDocuments computes the per-module differences between two genesis documents.
+//
+// Modules without any changes are omitted.
+func diffGenesisDocuments(oldDoc, newDoc *genesis.Document) ([]*genesisModuleDiff, error) {
+	oldView, err := genesisDiffView(oldDoc)
+	if err != nil {
+		return nil, fmt.Errorf("old document: %w", err)
+	}
+	newView, err := genesisDiffView(newDoc)
+	if err != nil {
+		return nil, fmt.Errorf("new document: %w", err)
+	}
+
+	var diffs []*genesisModuleDiff
+	for _, module := range diffModules {
+		changes := diffJSONValues("", oldView[module], newView[module])
+		if len(changes) == 0 {
+			continue
+		}
+		diffs = append(diffs, &genesisModuleDiff{
+			Module:  module,
+			Changes: changes,
+		})
+	}
+	return diffs, nil
+}
+
+// genesisDiffView converts a genesis document into its generic JSON representation, split by
+// module. Lists of descriptors are replaced by maps keyed by the descriptor identifiers and signed
+// descriptors are replaced by their (unverified) contents, so that individual descriptors can be
+// compared.
+func genesisDiffView(doc *genesis.Document) (map[string]interface{}, error) {
+	raw, err := toJSONValue(doc)
+	if err != nil {
+		return nil, err
+	}
+	view := raw.(map[string]interface{})
+
+	regView := struct {
+		Parameters        registry.ConsensusParameters                 `json:"params"`
+		Entities          map[signature.PublicKey]*entity.Entity       `json:"entities,omitempty"`
+		Runtimes          map[common.Namespace]*registry.Runtime       `json:"runtimes,omitempty"`
+		SuspendedRuntimes map[common.Namespace]*registry.Runtime       `json:"suspended_runtimes,omitempty"`
+		Nodes             map[signature.PublicKey]*node.Node           `json:"nodes,omitempty"`
+		NodeStatuses      map[signature.PublicKey]*registry.NodeStatus `json:"node_statuses,omitempty"`
+	}{
+		Parameters:        doc.Registry.Parameters,
+		Entities:          make(map[signature.PublicKey]*entity.Entity),
+		Runtimes:          make(map[common.Namespace]*registry.Runtime),
+		SuspendedRuntimes: make(map[common.Namespace]*registry.Runtime),
+		Nodes:             make(map[signature.PublicKey]*node.Node),
+		NodeStatuses:      doc.Registry.NodeStatuses,
+	}
+	for _, sigEnt := range doc.Registry.Entities {
+		var ent entity.Entity
+		if err = cbor.Unmarshal(sigEnt.Blob, &ent); err != nil {
+			return nil, fmt.Errorf("malformed entity descriptor: %w", err)
+		}
+		regView.Entities[ent.ID] = &ent
+	}
+	for _, rt := range doc.Registry.Runtimes {
+		regView.Runtimes[rt.ID] = rt
+	}
+	for _, rt := range doc.Registry.SuspendedRuntimes {
+		regView.SuspendedRuntimes[rt.ID] = rt
+	}
+	for _, sigNode := range doc.Registry.Nodes {
+		var n node.Node
+		if err = cbor.Unmarshal(sigNode.Blob, &n); err != nil {
+			return nil, fmt.Errorf("malformed node descriptor: %w", err)
+		}
+		regView.Nodes[n.ID] = &n
+	}
+	if view["registry"], err = toJSONValue(regView); err != nil {
+		return nil, err
+	}
+
+	kmView := struct {
+		Statuses map[common.Namespace]*keymanager.Status `json:"statuses,omitempty"`
+	}{
+		Statuses: make(map[common.Namespace]*keymanager.Status),
+	}
+	for _, st := range doc.KeyManager.Statuses {
+		kmView.Statuses[st.ID] = st
+	}
+	if view["keymanager"], err = toJSONValue(kmView); err != nil {
+		return nil, err
+	}
+
+	govView := struct {
+		Parameters  governance.ConsensusParameters     `json:"params"`
+		Proposals   map[uint64]*governance.Proposal    `json:"proposals,omitempty"`
+		VoteEntries map[uint64][]*governance.VoteEntry `json:"vote_entries,omitempty"`
+	}{
+		Parameters:  doc.Governance.Parameters,
+		Proposals:   make(map[uint64]*governance.Proposal),
+		VoteEntries: doc.Governance.VoteEntries,
+	}
+	for _, p := range doc.Governance.Proposals {
+		govView.Proposals[p.ID] = p
+	}
+	if view["governance"], err = toJSONValue(govView); err != nil {
+		return nil, err
+	}
+
+	// Move all top-level fields under the genesis pseudo-module.
+	topLevel := make(map[string]interface{})
+	for k, v := range view {
+		if !isDiffModule(k) {
+			topLevel[k] = v
+			delete(view, k)
+		}
+	}
+	view[diffModuleGenesis] = topLevel
+
+	return view, nil
+}
+
+func isDiffModule(name string) bool {
+	for _, m := range diffModules {
+		if m == name && m != diffModuleGenesis {
+			return true
+		}
+	}
+	return false
+}
+
+// toJSONValue converts the given value into its generic JSON representation.
+func toJSONValue(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var out interface{}
+	if err = dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// diffJSONValues computes the differences between two generic JSON values. Objects are compared
+// field by field, while all other values (including arrays) are compared as a whole.
+func diffJSONValues(path string, oldValue, newValue interface{}) []*genesisChange {
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if !oldIsMap || !newIsMap {
+		if reflect.DeepEqual(oldValue, newValue) {
+			return nil
+		}
+		return []*genesisChange{{
+			Kind: genesisChangeChanged,
+			Path: path,
+			Old:  oldValue,
+			New:  newValue,
+		}}
+	}
+
+	keys := make(map[string]bool)
+	for k := range oldMap {
+		keys[k] = true
+	}
+	for k := range newMap {
+		keys[k] = true
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for k := range keys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+
+	var changes []*genesisChange
+	for _, k := range sortedKeys {
+		subPath := k
+		if path != "" {
+			subPath = path + "." + k
+		}
+
+		oldSub, inOld := oldMap[k]
+		newSub, inNew := newMap[k]
+		switch {
+		case !inOld:
+			changes = append(changes, &genesisChange{
+				Kind: genesisChangeAdded,
+				Path: subPath,
+				New:  newSub,
+			})
+		case !inNew:
+			changes = append(changes, &genesisChange{
+				Kind: genesisChangeRemoved,
+				Path: subPath,
+				Old:  oldSub,
+			})
+		default:
+			changes = append(changes, diffJSONValues(subPath, oldSub, newSub)...)
+		}
+	}
+	return changes
+}
+
+func formatDiffValue(v interface{}) string {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return strings.TrimSpace(string(raw))
+}
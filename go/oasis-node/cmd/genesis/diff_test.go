@@ -0,0 +1,86 @@
+package genesis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+func TestDiffGenesisDocuments(t *testing.T) {
+	require := require.New(t)
+
+	signer := memorySigner.NewTestSigner("genesis diff test")
+	ent := &entity.Entity{
+		Versioned: cbor.NewVersioned(entity.LatestDescriptorVersion),
+		ID:        signer.Public(),
+	}
+	sigEnt, err := entity.SignEntity(signer, registry.RegisterEntitySignatureContext, ent)
+	require.NoError(err, "SignEntity")
+
+	addr := staking.NewAddress(signer.Public())
+	rtID := common.NewTestNamespaceFromSeed([]byte("genesis diff test"), 0)
+
+	newDocument := func(balance uint64) *genesis.Document {
+		return &genesis.Document{
+			ChainID: "test",
+			Registry: registry.Genesis{
+				Entities: []*entity.SignedEntity{sigEnt},
+			},
+			Staking: staking.Genesis{
+				Ledger: map[staking.Address]*staking.Account{
+					addr: {General: staking.GeneralAccount{Balance: *quantity.NewFromUint64(balance)}},
+				},
+			},
+		}
+	}
+
+	oldDoc := newDocument(100)
+	diffs, err := diffGenesisDocuments(oldDoc, newDocument(100))
+	require.NoError(err, "diffGenesisDocuments")
+	require.Empty(diffs, "equal documents should have no differences")
+
+	newDoc := newDocument(200)
+	newDoc.ChainID = "test-2"
+	newDoc.Registry.Parameters.DisableRuntimeRegistration = true
+	newDoc.Registry.Runtimes = []*registry.Runtime{{ID: rtID, Kind: registry.KindCompute}}
+	diffs, err = diffGenesisDocuments(oldDoc, newDoc)
+	require.NoError(err, "diffGenesisDocuments")
+	require.Len(diffs, 3)
+
+	require.Equal(diffModuleGenesis, diffs[0].Module)
+	require.Len(diffs[0].Changes, 1)
+	require.Equal(&genesisChange{
+		Kind: genesisChangeChanged,
+		Path: "chain_id",
+		Old:  "test",
+		New:  "test-2",
+	}, diffs[0].Changes[0])
+
+	require.Equal("registry", diffs[1].Module)
+	require.Len(diffs[1].Changes, 2)
+	require.Equal(genesisChangeAdded, diffs[1].Changes[0].Kind, "omitted fields are treated as missing")
+	require.Equal("params.disable_runtime_registration", diffs[1].Changes[0].Path)
+	require.Equal(genesisChangeAdded, diffs[1].Changes[1].Kind)
+	require.Equal("runtimes", diffs[1].Changes[1].Path, "omitted fields are treated as missing")
+
+	require.Equal("staking", diffs[2].Module)
+	require.Len(diffs[2].Changes, 1)
+	require.Equal(genesisChangeChanged, diffs[2].Changes[0].Kind)
+	require.Equal("ledger."+addr.String()+".general.balance", diffs[2].Changes[0].Path)
+
+	// Descriptors are matched by identifier.
+	oldDoc.Registry.Runtimes = []*registry.Runtime{{ID: rtID, Kind: registry.KindKeyManager}}
+	diffs, err = diffGenesisDocuments(oldDoc, newDoc)
+	require.NoError(err, "diffGenesisDocuments")
+	require.Equal("registry", diffs[1].Module)
+	require.Equal("runtimes."+rtID.String()+".kind", diffs[1].Changes[1].Path)
+}
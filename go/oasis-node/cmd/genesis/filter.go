@@ -0,0 +1,148 @@
+package genesis
+
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	keymanager "github.com/oasisprotocol/oasis-core/go/keymanager/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+)
+
+// filterModules are the genesis document modules whose state can be excluded from a dump. The
+// state of other modules only consists of consensus parameters which are always included.
+var filterModules = []string{
+	"registry",
+	"roothash",
+	"staking",
+	"keymanager",
+	"governance",
+}
+
+// filterGenesisDocument removes the state of all modules not in the given list and the state of
+// all runtimes not in the given list from the genesis document. The consensus parameters of all
+// modules are retained.
+//
+// An empty list of modules or runtimes retains all modules or runtimes respectively.
+func filterGenesisDocument(doc *genesis.Document, modules []string, runtimes []common.Namespace) error {
+	if len(modules) > 0 {
+		included := make(map[string]bool)
+		for _, m := range modules {
+			if !isFilterModule(m) {
+				return fmt.Errorf("unsupported module '%s' (supported: %v)", m, filterModules)
+			}
+			included[m] = true
+		}
+
+		if !included["registry"] {
+			doc.Registry = registry.Genesis{Parameters: doc.Registry.Parameters}
+		}
+		if !included["roothash"] {
+			doc.RootHash = roothash.Genesis{Parameters: doc.RootHash.Parameters}
+		}
+		if !included["keymanager"] {
+			doc.KeyManager = keymanager.Genesis{}
+		}
+		if !included["governance"] {
+			doc.Governance.Proposals = nil
+			doc.Governance.VoteEntries = nil
+
+			// Deposits of removed proposals are removed from the total supply.
+			if err := doc.Staking.TotalSupply.Sub(&doc.Staking.GovernanceDeposits); err != nil {
+				return fmt.Errorf("failed to remove governance deposits: %w", err)
+			}
+			doc.Staking.GovernanceDeposits = quantity.Quantity{}
+		}
+		if !included["staking"] {
+			doc.Staking.Ledger = nil
+			doc.Staking.Delegations = nil
+			doc.Staking.DebondingDelegations = nil
+
+			// Balances of removed accounts are removed from the total supply.
+			var total quantity.Quantity
+			_ = total.Add(&doc.Staking.CommonPool)
+			_ = total.Add(&doc.Staking.LastBlockFees)
+			_ = total.Add(&doc.Staking.GovernanceDeposits)
+			doc.Staking.TotalSupply = total
+		}
+	}
+
+	if len(runtimes) > 0 {
+		included := make(map[common.Namespace]bool)
+		for _, id := range runtimes {
+			included[id] = true
+		}
+		if err := filterGenesisRuntimes(doc, included); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func filterGenesisRuntimes(doc *genesis.Document, included map[common.Namespace]bool) error {
+	filterRuntimes := func(rts []*registry.Runtime) []*registry.Runtime {
+		var filtered []*registry.Runtime
+		for _, rt := range rts {
+			if included[rt.ID] {
+				filtered = append(filtered, rt)
+			}
+		}
+		return filtered
+	}
+	doc.Registry.Runtimes = filterRuntimes(doc.Registry.Runtimes)
+	doc.Registry.SuspendedRuntimes = filterRuntimes(doc.Registry.SuspendedRuntimes)
+
+	// Node descriptors are signed so they cannot be modified, remove nodes that are registered
+	// for any of the excluded runtimes instead.
+	var nodes []*node.MultiSignedNode
+	for _, sigNode := range doc.Registry.Nodes {
+		var n node.Node
+		if err := cbor.Unmarshal(sigNode.Blob, &n); err != nil {
+			return fmt.Errorf("malformed node descriptor: %w", err)
+		}
+
+		keep := true
+		for _, rt := range n.Runtimes {
+			if !included[rt.ID] {
+				keep = false
+				break
+			}
+		}
+		if !keep {
+			delete(doc.Registry.NodeStatuses, n.ID)
+			continue
+		}
+		nodes = append(nodes, sigNode)
+	}
+	doc.Registry.Nodes = nodes
+
+	for id := range doc.RootHash.RuntimeStates {
+		if !included[id] {
+			delete(doc.RootHash.RuntimeStates, id)
+		}
+	}
+
+	var statuses []*keymanager.Status
+	for _, st := range doc.KeyManager.Statuses {
+		if included[st.ID] {
+			statuses = append(statuses, st)
+		}
+	}
+	doc.KeyManager.Statuses = statuses
+
+	return nil
+}
+
+func isFilterModule(name string) bool {
+	for _, m := range filterModules {
+		if m == name {
+			return true
+		}
+	}
+	return false
+}
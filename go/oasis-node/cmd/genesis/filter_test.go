@@ -0,0 +1,112 @@
+package genesis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	keymanager "github.com/oasisprotocol/oasis-core/go/keymanager/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+func TestFilterGenesisDocument(t *testing.T) {
+	require := require.New(t)
+
+	signer := memorySigner.NewTestSigner("genesis filter test")
+	ent := &entity.Entity{
+		Versioned: cbor.NewVersioned(entity.LatestDescriptorVersion),
+		ID:        signer.Public(),
+	}
+	sigEnt, err := entity.SignEntity(signer, registry.RegisterEntitySignatureContext, ent)
+	require.NoError(err, "SignEntity")
+
+	addr := staking.NewAddress(signer.Public())
+	rtID1 := common.NewTestNamespaceFromSeed([]byte("genesis filter test 1"), 0)
+	rtID2 := common.NewTestNamespaceFromSeed([]byte("genesis filter test 2"), 0)
+	kmID := common.NewTestNamespaceFromSeed([]byte("genesis filter test km"), common.NamespaceKeyManager)
+
+	newDocument := func() *genesis.Document {
+		return &genesis.Document{
+			ChainID: "test",
+			Registry: registry.Genesis{
+				Parameters: registry.ConsensusParameters{MaxNodeExpiration: 5},
+				Entities:   []*entity.SignedEntity{sigEnt},
+				Runtimes: []*registry.Runtime{
+					{ID: rtID1, Kind: registry.KindCompute},
+					{ID: rtID2, Kind: registry.KindCompute},
+					{ID: kmID, Kind: registry.KindKeyManager},
+				},
+			},
+			RootHash: roothash.Genesis{
+				Parameters: roothash.ConsensusParameters{MaxRuntimeMessages: 10},
+				RuntimeStates: map[common.Namespace]*roothash.GenesisRuntimeState{
+					rtID1: {},
+					rtID2: {},
+				},
+			},
+			KeyManager: keymanager.Genesis{
+				Statuses: []*keymanager.Status{{ID: kmID}},
+			},
+			Governance: governance.Genesis{
+				Proposals: []*governance.Proposal{{ID: 1}},
+			},
+			Staking: staking.Genesis{
+				TotalSupply:        *quantity.NewFromUint64(1000),
+				CommonPool:         *quantity.NewFromUint64(300),
+				GovernanceDeposits: *quantity.NewFromUint64(100),
+				Ledger: map[staking.Address]*staking.Account{
+					addr: {General: staking.GeneralAccount{Balance: *quantity.NewFromUint64(600)}},
+				},
+			},
+		}
+	}
+
+	// No filters should keep everything.
+	doc := newDocument()
+	require.NoError(filterGenesisDocument(doc, nil, nil), "filterGenesisDocument")
+	require.Equal(newDocument(), doc)
+
+	// Unsupported modules should be rejected.
+	require.Error(filterGenesisDocument(newDocument(), []string{"beacon"}, nil), "unsupported module")
+
+	// Only staking and registry.
+	doc = newDocument()
+	require.NoError(filterGenesisDocument(doc, []string{"staking", "registry"}, nil), "filterGenesisDocument")
+	require.Len(doc.Registry.Entities, 1)
+	require.Len(doc.Registry.Runtimes, 3)
+	require.Len(doc.Staking.Ledger, 1)
+	require.Empty(doc.RootHash.RuntimeStates)
+	require.EqualValues(10, doc.RootHash.Parameters.MaxRuntimeMessages, "parameters should be retained")
+	require.Empty(doc.KeyManager.Statuses)
+	require.Empty(doc.Governance.Proposals)
+	require.True(doc.Staking.GovernanceDeposits.IsZero())
+	require.Equal(quantity.NewFromUint64(900), &doc.Staking.TotalSupply, "governance deposits should be removed from total supply")
+
+	// Only governance.
+	doc = newDocument()
+	require.NoError(filterGenesisDocument(doc, []string{"governance"}, nil), "filterGenesisDocument")
+	require.Empty(doc.Registry.Entities)
+	require.EqualValues(5, doc.Registry.Parameters.MaxNodeExpiration, "parameters should be retained")
+	require.Empty(doc.Staking.Ledger)
+	require.Len(doc.Governance.Proposals, 1)
+	require.Equal(quantity.NewFromUint64(400), &doc.Staking.TotalSupply, "account balances should be removed from total supply")
+
+	// Only some runtimes.
+	doc = newDocument()
+	require.NoError(filterGenesisDocument(doc, nil, []common.Namespace{rtID2, kmID}), "filterGenesisDocument")
+	require.Len(doc.Registry.Runtimes, 2)
+	require.Equal(rtID2, doc.Registry.Runtimes[0].ID)
+	require.Equal(kmID, doc.Registry.Runtimes[1].ID)
+	require.Len(doc.RootHash.RuntimeStates, 1)
+	require.Contains(doc.RootHash.RuntimeStates, rtID2)
+	require.Len(doc.KeyManager.Statuses, 1)
+}
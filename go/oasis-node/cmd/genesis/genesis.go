@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math"
 	"os"
@@ -21,6 +22,7 @@ import (
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/diff"
 	"github.com/oasisprotocol/oasis-core/go/common/entity"
@@ -32,7 +34,6 @@ import (
 	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
 	tendermint "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
-	genesisFile "github.com/oasisprotocol/oasis-core/go/genesis/file"
 	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
 	keymanager "github.com/oasisprotocol/oasis-core/go/keymanager/api"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
@@ -55,6 +56,9 @@ const (
 	cfgChainID       = "chain.id"
 	cfgHaltEpoch     = "halt.epoch"
 	cfgInitialHeight = "initial_height"
+	cfgCheckDeep     = "deep"
+	cfgDumpModules   = "dump.modules"
+	cfgDumpRuntimes  = "dump.runtimes"
 
 	// Registry config flags.
 	CfgRegistryMaxNodeExpiration             = "registry.max_node_expiration"
@@ -63,6 +67,8 @@ const (
 	CfgRegistryDebugAllowTestRuntimes        = "registry.debug.allow_test_runtimes"
 	cfgRegistryDebugBypassStake              = "registry.debug.bypass_stake" // nolint: gosec
 	cfgRegistryEnableRuntimeGovernanceModels = "registry.enable_runtime_governance_models"
+	cfgRegistryTEEFeaturesSGXPCS             = "registry.tee_features.sgx.pcs"
+	cfgRegistryTEEFeaturesSGXPolicy          = "registry.tee_features.sgx.policy"
 
 	// Scheduler config flags.
 	cfgSchedulerMinValidators          = "scheduler.min_validators"
@@ -92,6 +98,11 @@ const (
 	cfgRoothashDebugDoNotSuspendRuntimes = "roothash.debug.do_not_suspend_runtimes"
 	cfgRoothashDebugBypassStake          = "roothash.debug.bypass_stake" // nolint: gosec
 	cfgRoothashMaxRuntimeMessages        = "roothash.max_runtime_messages"
+	cfgRoothashEnforceMaxRuntimeMessages = "roothash.enforce_max_runtime_messages"
+	cfgRoothashAllowAggregatedCommits    = "roothash.allow_aggregated_commitments"
+	cfgRoothashMaxEvidenceAge            = "roothash.max_evidence_age"
+	cfgRoothashRoundTimeoutOverrideMin   = "roothash.round_timeout_override.min"
+	cfgRoothashRoundTimeoutOverrideMax   = "roothash.round_timeout_override.max"
 
 	// Staking config flags.
 	CfgStakingTokenSymbol        = "staking.token_symbol"
@@ -120,9 +131,11 @@ const (
 )
 
 var (
+	buildGenesisFlags = flag.NewFlagSet("", flag.ContinueOnError)
 	checkGenesisFlags = flag.NewFlagSet("", flag.ContinueOnError)
 	dumpGenesisFlags  = flag.NewFlagSet("", flag.ContinueOnError)
 	initGenesisFlags  = flag.NewFlagSet("", flag.ContinueOnError)
+	diffGenesisFlags  = flag.NewFlagSet("", flag.ContinueOnError)
 
 	genesisCmd = &cobra.Command{
 		Use:   "genesis",
@@ -135,6 +148,13 @@ var (
 		Run:   doInitGenesis,
 	}
 
+	buildGenesisCmd = &cobra.Command{
+		Use:   "build <spec.json>",
+		Short: "build the genesis file from a build specification",
+		Args:  cobra.ExactArgs(1),
+		Run:   doBuildGenesis,
+	}
+
 	dumpGenesisCmd = &cobra.Command{
 		Use:   "dump",
 		Short: "dump state into genesis file",
@@ -147,6 +167,13 @@ var (
 		Run:   doCheckGenesis,
 	}
 
+	diffGenesisCmd = &cobra.Command{
+		Use:   "diff <old.json> <new.json>",
+		Short: "show per-module differences between two genesis files",
+		Args:  cobra.ExactArgs(2),
+		Run:   doDiffGenesis,
+	}
+
 	logger = logging.GetLogger("cmd/genesis")
 )
 
@@ -400,6 +427,15 @@ func AppendRegistryState(doc *genesis.Document, entities, runtimes, nodes []stri
 		Nodes:    make([]*node.MultiSignedNode, 0, len(nodes)),
 	}
 
+	if viper.GetBool(cfgRegistryTEEFeaturesSGXPCS) || viper.GetBool(cfgRegistryTEEFeaturesSGXPolicy) {
+		regSt.Parameters.TEEFeatures = &node.TEEFeatures{
+			SGX: node.TEEFeaturesSGX{
+				PCS:    viper.GetBool(cfgRegistryTEEFeaturesSGXPCS),
+				Policy: viper.GetBool(cfgRegistryTEEFeaturesSGXPolicy),
+			},
+		}
+	}
+
 	for _, gmStr := range viper.GetStringSlice(cfgRegistryEnableRuntimeGovernanceModels) {
 		var gm registry.RuntimeGovernanceModel
 		if err := gm.UnmarshalText([]byte(strings.ToLower(gmStr))); err != nil {
@@ -539,14 +575,24 @@ func AppendRootHashState(doc *genesis.Document, exports []string, l *logging.Log
 		RuntimeStates: make(map[common.Namespace]*roothash.GenesisRuntimeState),
 
 		Parameters: roothash.ConsensusParameters{
-			DebugDoNotSuspendRuntimes: viper.GetBool(cfgRoothashDebugDoNotSuspendRuntimes),
-			DebugBypassStake:          viper.GetBool(cfgRoothashDebugBypassStake),
-			MaxRuntimeMessages:        viper.GetUint32(cfgRoothashMaxRuntimeMessages),
+			DebugDoNotSuspendRuntimes:  viper.GetBool(cfgRoothashDebugDoNotSuspendRuntimes),
+			DebugBypassStake:           viper.GetBool(cfgRoothashDebugBypassStake),
+			MaxRuntimeMessages:         viper.GetUint32(cfgRoothashMaxRuntimeMessages),
+			EnforceMaxRuntimeMessages:  viper.GetBool(cfgRoothashEnforceMaxRuntimeMessages),
+			AllowAggregatedCommitments: viper.GetBool(cfgRoothashAllowAggregatedCommits),
+			MaxEvidenceAge:             viper.GetUint64(cfgRoothashMaxEvidenceAge),
 			// TODO: Make these configurable.
 			GasCosts: roothash.DefaultGasCosts,
 		},
 	}
 
+	if viper.GetInt64(cfgRoothashRoundTimeoutOverrideMax) > 0 {
+		rootSt.Parameters.RoundTimeoutOverride = &roothash.RoundTimeoutOverrideParameters{
+			MinRoundTimeout: viper.GetInt64(cfgRoothashRoundTimeoutOverrideMin),
+			MaxRoundTimeout: viper.GetInt64(cfgRoothashRoundTimeoutOverrideMax),
+		}
+	}
+
 	for _, v := range exports {
 		b, err := ioutil.ReadFile(v)
 		if err != nil {
@@ -674,6 +720,19 @@ func doDumpGenesis(cmd *cobra.Command, args []string) {
 
 	client := consensus.NewConsensusClient(conn)
 
+	var runtimes []common.Namespace
+	for _, raw := range viper.GetStringSlice(cfgDumpRuntimes) {
+		var id common.Namespace
+		if err = id.UnmarshalHex(raw); err != nil {
+			logger.Error("malformed runtime identifier",
+				"err", err,
+				"runtime_id", raw,
+			)
+			os.Exit(1)
+		}
+		runtimes = append(runtimes, id)
+	}
+
 	doc, err := client.StateToGenesis(ctx, viper.GetInt64(cfgBlockHeight))
 	if err != nil {
 		logger.Error("failed to generate genesis document",
@@ -682,6 +741,13 @@ func doDumpGenesis(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if err = filterGenesisDocument(doc, viper.GetStringSlice(cfgDumpModules), runtimes); err != nil {
+		logger.Error("failed to filter genesis document",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
 	w, shouldClose, err := cmdCommon.GetOutputWriter(cmd, flags.CfgGenesisFile)
 	if err != nil {
 		logger.Error("failed to get writer for genesis file",
@@ -708,31 +774,56 @@ func doDumpGenesis(cmd *cobra.Command, args []string) {
 	}
 }
 
+// readGenesisFile stream-parses the given genesis file, writing its raw contents to w.
+func readGenesisFile(filename string, w io.Writer) (*genesis.Document, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return genesis.ReadDocument(io.TeeReader(f, w))
+}
+
 func doCheckGenesis(cmd *cobra.Command, args []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
 	}
 
 	filename := flags.GenesisFile()
-	provider, err := genesisFile.NewFileProvider(filename)
+	// Compute the file's checksum while parsing it, so that the (potentially very large) file
+	// only needs to be read once.
+	sha256Hasher := sha256.New()
+	doc, err := readGenesisFile(filename, sha256Hasher)
 	if err != nil {
 		logger.Error("failed to open genesis file", "err", err)
 		os.Exit(1)
 	}
-	// NOTE: The genesis document sanity checks are performed inside the
-	// NewFileProvider() function.
-	doc, err := provider.GetGenesisDocument()
-	if err != nil {
-		logger.Error("failed to get genesis document", "err", err)
+	if viper.GetBool(cfgCheckDeep) {
+		if errs := doc.SanityCheckDeep(); len(errs) > 0 {
+			fmt.Fprintf(os.Stderr, "genesis document failed sanity checks (%d violations):\n", len(errs))
+			for _, err := range errs {
+				fmt.Fprintf(os.Stderr, "  - %s\n", err)
+			}
+			os.Exit(1)
+		}
+	} else if err = doc.SanityCheck(); err != nil {
+		logger.Error("genesis document failed sanity check", "err", err)
 		os.Exit(1)
 	}
 
-	// Load genesis file to check if it is in the canonical form.
-	actualGenesis, err := ioutil.ReadFile(filename)
-	if err != nil {
-		logger.Error("failed to read genesis file:", "err", err)
-		os.Exit(1)
+	if expected := flags.GenesisExpectedHash(); expected != "" {
+		var h hash.Hash
+		if err := h.UnmarshalHex(expected); err != nil {
+			logger.Error("malformed expected genesis hash", "err", err)
+			os.Exit(1)
+		}
+		if err := doc.VerifyHash(h); err != nil {
+			logger.Error("genesis document hash mismatch", "err", err)
+			os.Exit(1)
+		}
 	}
+
 	// Get canonical form of the genesis document serialized into a file.
 	canonicalJSON, err := doc.CanonicalJSON()
 	if err != nil {
@@ -740,8 +831,16 @@ func doCheckGenesis(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 	// Actual genesis file should equal the canonical form.
-	if !bytes.Equal(actualGenesis, canonicalJSON) {
-		var err error
+	checksum := sha256Hasher.Sum(nil)
+	canonicalChecksum := sha256.Sum256(canonicalJSON)
+	if !bytes.Equal(checksum, canonicalChecksum[:]) {
+		// Only load the whole genesis file when needed to report the differences.
+		actualGenesis, err := ioutil.ReadFile(filename)
+		if err != nil {
+			logger.Error("failed to read genesis file:", "err", err)
+			os.Exit(1)
+		}
+
 		if len(strings.Split(strings.TrimSpace(string(actualGenesis)), "\n")) == 1 {
 			err = fmt.Errorf("genesis file has everything on a single line")
 		} else {
@@ -761,29 +860,30 @@ func doCheckGenesis(cmd *cobra.Command, args []string) {
 
 	fmt.Println("genesis file is valid and in canonical form")
 	fmt.Printf("genesis document's hash: %s\n", doc.ChainContext())
-	fmt.Printf("genesis file's SHA256 checksum: ")
+	fmt.Printf("genesis file's SHA256 checksum: %x\n", checksum)
 
-	sha256Hasher := sha256.New()
-	_, herr := sha256Hasher.Write(actualGenesis)
-	switch herr {
-	case nil:
-		fmt.Printf("%x\n", sha256Hasher.Sum(nil))
-	default:
-		fmt.Println("[unknown]")
+	if p := doc.Provenance; p != nil {
+		fmt.Printf("genesis document's provenance: chain ID %q at height %d (exported by %s)\n",
+			p.SourceChainID, p.ExportHeight, p.ToolVersion,
+		)
 	}
 }
 
 // Register registers the genesis sub-command and all of it's children.
 func Register(parentCmd *cobra.Command) {
 	initGenesisCmd.Flags().AddFlagSet(initGenesisFlags)
+	buildGenesisCmd.Flags().AddFlagSet(buildGenesisFlags)
 	dumpGenesisCmd.Flags().AddFlagSet(dumpGenesisFlags)
 	dumpGenesisCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
 	checkGenesisCmd.Flags().AddFlagSet(checkGenesisFlags)
+	diffGenesisCmd.Flags().AddFlagSet(diffGenesisFlags)
 
 	for _, v := range []*cobra.Command{
 		initGenesisCmd,
+		buildGenesisCmd,
 		dumpGenesisCmd,
 		checkGenesisCmd,
+		diffGenesisCmd,
 	} {
 		genesisCmd.AddCommand(v)
 	}
@@ -792,10 +892,19 @@ func Register(parentCmd *cobra.Command) {
 }
 
 func init() {
+	buildGenesisFlags.AddFlagSet(flags.GenesisFileFlags)
+
+	checkGenesisFlags.Bool(cfgCheckDeep, false, "run all sanity checks and report every violation")
 	_ = viper.BindPFlags(checkGenesisFlags)
 	checkGenesisFlags.AddFlagSet(flags.GenesisFileFlags)
+	checkGenesisFlags.AddFlagSet(flags.GenesisExpectedHashFlags)
+
+	cmdCommon.AddDeprecatedFormatFlag(diffGenesisFlags, cfgDiffFormat)
+	_ = viper.BindPFlags(diffGenesisFlags)
 
 	dumpGenesisFlags.Int64(cfgBlockHeight, consensus.HeightLatest, "block height at which to dump state")
+	dumpGenesisFlags.StringSlice(cfgDumpModules, nil, fmt.Sprintf("only include the state of the given modules (%s; default: all)", strings.Join(filterModules, ", ")))
+	dumpGenesisFlags.StringSlice(cfgDumpRuntimes, nil, "only include the state of the given runtimes (default: all)")
 	_ = viper.BindPFlags(dumpGenesisFlags)
 	dumpGenesisFlags.AddFlagSet(flags.GenesisFileFlags)
 
@@ -815,6 +924,8 @@ func init() {
 	initGenesisFlags.Bool(CfgRegistryDebugAllowTestRuntimes, false, "enable test runtime registration")
 	initGenesisFlags.Bool(cfgRegistryDebugBypassStake, false, "bypass all stake checks and operations (UNSAFE)")
 	initGenesisFlags.StringSlice(cfgRegistryEnableRuntimeGovernanceModels, []string{"entity"}, "set of enabled runtime governance models")
+	initGenesisFlags.Bool(cfgRegistryTEEFeaturesSGXPCS, false, "enable PCS-based (DCAP) Intel SGX attestations")
+	initGenesisFlags.Bool(cfgRegistryTEEFeaturesSGXPolicy, false, "enable Intel SGX attestation acceptance policies")
 	_ = initGenesisFlags.MarkHidden(cfgRegistryDebugAllowUnroutableAddresses)
 	_ = initGenesisFlags.MarkHidden(CfgRegistryDebugAllowTestRuntimes)
 	_ = initGenesisFlags.MarkHidden(cfgRegistryDebugBypassStake)
@@ -851,6 +962,11 @@ func init() {
 	initGenesisFlags.Bool(cfgRoothashDebugDoNotSuspendRuntimes, false, "do not suspend runtimes (UNSAFE)")
 	initGenesisFlags.Bool(cfgRoothashDebugBypassStake, false, "bypass all roothash stake checks and operations (UNSAFE)")
 	initGenesisFlags.Uint32(cfgRoothashMaxRuntimeMessages, 128, "maximum number of runtime messages submitted in a round")
+	initGenesisFlags.Bool(cfgRoothashEnforceMaxRuntimeMessages, false, "enforce the maximum number of runtime messages for runtimes declaring a higher limit")
+	initGenesisFlags.Bool(cfgRoothashAllowAggregatedCommits, false, "allow aggregated executor commitments in executor commit transactions")
+	initGenesisFlags.Uint64(cfgRoothashMaxEvidenceAge, 100, "maximum age of submitted evidence (in rounds)")
+	initGenesisFlags.Int64(cfgRoothashRoundTimeoutOverrideMin, 5, "minimum executor round timeout override (in blocks)")
+	initGenesisFlags.Int64(cfgRoothashRoundTimeoutOverrideMax, 0, "maximum executor round timeout override (in blocks, 0 disables overrides)")
 	_ = initGenesisFlags.MarkHidden(cfgRoothashDebugDoNotSuspendRuntimes)
 	_ = initGenesisFlags.MarkHidden(cfgRoothashDebugBypassStake)
 
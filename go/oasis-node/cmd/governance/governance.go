@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
@@ -27,8 +28,10 @@ import (
 )
 
 const (
-	cfgProposalCancelUpgradeID   = "proposal.cancel_upgrade.id"
-	cfgProposalUpgradeDescriptor = "proposal.upgrade.descriptor"
+	cfgProposalCancelUpgradeID             = "proposal.cancel_upgrade.id"
+	cfgProposalUpgradeDescriptor           = "proposal.upgrade.descriptor"
+	cfgProposalSetRoundTimeoutRuntimeID    = "proposal.set_round_timeout.runtime_id"
+	cfgProposalSetRoundTimeoutRoundTimeout = "proposal.set_round_timeout.round_timeout"
 
 	cfgVote           = "vote"
 	cfgVoteProposalID = "vote.proposal.id"
@@ -143,9 +146,24 @@ func doGenSubmitProposal(cmd *cobra.Command, args []string) {
 				ProposalID: viper.GetUint64(cfgProposalCancelUpgradeID),
 			},
 		})
+	case viper.GetString(cfgProposalSetRoundTimeoutRuntimeID) != "":
+		var runtimeID common.Namespace
+		if err := runtimeID.UnmarshalHex(viper.GetString(cfgProposalSetRoundTimeoutRuntimeID)); err != nil {
+			logger.Error("can't parse runtime ID",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+
+		tx = governance.NewSubmitProposalTx(nonce, fee, &governance.ProposalContent{
+			SetRoundTimeout: &governance.SetRoundTimeoutProposal{
+				RuntimeID:    runtimeID,
+				RoundTimeout: viper.GetInt64(cfgProposalSetRoundTimeoutRoundTimeout),
+			},
+		})
 	default:
-		logger.Error(fmt.Sprintf("missing required arguments: either '%v' or '%v' required",
-			cfgProposalUpgradeDescriptor, cfgProposalCancelUpgradeID,
+		logger.Error(fmt.Sprintf("missing required arguments: one of '%v', '%v' or '%v' required",
+			cfgProposalUpgradeDescriptor, cfgProposalCancelUpgradeID, cfgProposalSetRoundTimeoutRuntimeID,
 		))
 		os.Exit(1)
 	}
@@ -204,14 +222,12 @@ func doProposalInfo(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	prettyProposal, err := cmdCommon.PrettyJSONMarshal(proposal)
-	if err != nil {
-		logger.Error("failed to get pretty JSON of proposal",
+	if err = cmdCommon.PrintResult(proposal, nil); err != nil {
+		logger.Error("failed to print proposal",
 			"err", err,
 		)
 		os.Exit(1)
 	}
-	fmt.Println(string(prettyProposal))
 }
 
 func doProposalVotes(cmd *cobra.Command, args []string) {
@@ -235,14 +251,12 @@ func doProposalVotes(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	prettyVotes, err := cmdCommon.PrettyJSONMarshal(votes)
-	if err != nil {
-		logger.Error("failed to get pretty JSON of votes",
+	if err = cmdCommon.PrintResult(votes, nil); err != nil {
+		logger.Error("failed to print votes",
 			"err", err,
 		)
 		os.Exit(1)
 	}
-	fmt.Println(string(prettyVotes))
 }
 
 func doListProposals(cmd *cobra.Command, args []string) {
@@ -268,14 +282,12 @@ func doListProposals(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	prettyProposals, err := cmdCommon.PrettyJSONMarshal(proposals)
-	if err != nil {
-		logger.Error("failed to get pretty JSON of proposals",
+	if err = cmdCommon.PrintResult(proposals, nil); err != nil {
+		logger.Error("failed to print proposals",
 			"err", err,
 		)
 		os.Exit(1)
 	}
-	fmt.Println(string(prettyProposals))
 }
 
 // Register registers the governance sub-command and all of it's children.
@@ -312,6 +324,8 @@ func init() {
 
 	submitProposalFlags.String(cfgProposalUpgradeDescriptor, "", "Path to the proposal upgrade descriptor")
 	submitProposalFlags.Uint64(cfgProposalCancelUpgradeID, 0, "Cancel upgrade proposal ID")
+	submitProposalFlags.String(cfgProposalSetRoundTimeoutRuntimeID, "", "Set round timeout proposal runtime ID (hex)")
+	submitProposalFlags.Int64(cfgProposalSetRoundTimeoutRoundTimeout, 0, "Set round timeout proposal round timeout (in blocks, 0 removes the override)")
 	_ = viper.BindPFlags(submitProposalFlags)
 	submitProposalFlags.AddFlagSet(cmdConsensus.TxFlags)
 	submitProposalFlags.AddFlagSet(cmdFlags.AssumeYesFlag)
@@ -0,0 +1,574 @@
+package identity
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/oasisprotocol/deoxysii"
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/argon2"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	fileSigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/file"
+	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
+)
+
+const (
+	// CfgBackupEntityDir is the directory containing the entity key to include in (or restore
+	// from) the backup archive.
+	CfgBackupEntityDir = "identity.backup.entity_dir"
+
+	// CfgBackupPassphraseFile is the file containing the backup archive passphrase.
+	CfgBackupPassphraseFile = "identity.backup.passphrase_file"
+
+	// CfgBackupVerifyKeys are the public keys that must be present in the restored archive.
+	CfgBackupVerifyKeys = "identity.backup.verify_keys"
+
+	keyBackupVersion = 1
+
+	keyBackupSaltSize   = 32
+	keyBackupKDFTime    = 3
+	keyBackupKDFMemory  = 64 * 1024
+	keyBackupKDFThreads = 4
+
+	// The KDF parameters are read from the (not yet authenticated) archive header, so they are
+	// bounded to prevent a malicious archive from exhausting resources (or crashing argon2).
+	keyBackupMinSaltSize   = 16
+	keyBackupMaxKDFTime    = 16
+	keyBackupMaxKDFMemory  = 1024 * 1024
+	keyBackupMaxKDFThreads = 16
+
+	// keyBackupFileMode are the permissions of all restored files.
+	keyBackupFileMode = 0o600
+
+	minBackupPassphraseLength = 12
+)
+
+var (
+	identityBackupCmd = &cobra.Command{
+		Use:   "backup <archive>",
+		Short: "export node (and entity) keys into an encrypted backup archive",
+		Args:  cobra.ExactArgs(1),
+		Run:   doBackup,
+	}
+
+	identityRestoreCmd = &cobra.Command{
+		Use:   "restore <archive>",
+		Short: "restore node (and entity) keys from an encrypted backup archive",
+		Args:  cobra.ExactArgs(1),
+		Run:   doRestore,
+	}
+
+	backupFlags  = flag.NewFlagSet("", flag.ContinueOnError)
+	restoreFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	// nodeKeyFilenames are the node signing key filenames by role.
+	nodeKeyFilenames = map[signature.SignerRole]string{
+		signature.SignerNode:      fileSigner.FileIdentityKey,
+		signature.SignerP2P:       fileSigner.FileP2PKey,
+		signature.SignerConsensus: fileSigner.FileConsensusKey,
+		signature.SignerVRF:       fileSigner.FileVRFKey,
+	}
+)
+
+// keyBackupKDF are the Argon2id parameters used to derive the archive key from the passphrase.
+type keyBackupKDF struct {
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+// keyBackupHeader is the unencrypted (but authenticated) header of a key backup archive.
+type keyBackupHeader struct {
+	cbor.Versioned
+
+	// KDF are the key derivation parameters.
+	KDF keyBackupKDF `json:"kdf"`
+	// Nonce is the Deoxys-II nonce.
+	Nonce []byte `json:"nonce"`
+}
+
+// keyBackup is a key backup archive.
+type keyBackup struct {
+	// Header is the archive header.
+	Header keyBackupHeader `json:"header"`
+	// Ciphertext are the encrypted archive contents.
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// keyBackupFile is a single file stored in a key backup archive.
+type keyBackupFile struct {
+	// Name is the name of the file.
+	Name string `json:"name"`
+	// Role is the signer role of the key (if the file is a signing key).
+	Role signature.SignerRole `json:"role,omitempty"`
+	// PublicKey is the public key of the signing key (if the file is a signing key).
+	PublicKey *signature.PublicKey `json:"public_key,omitempty"`
+	// Mode are the file permissions at the time of the backup.
+	//
+	// Note: Restored files are always created with keyBackupFileMode.
+	Mode os.FileMode `json:"mode"`
+	// Data are the file contents.
+	Data []byte `json:"data"`
+}
+
+// keyBackupContents are the contents of a key backup archive.
+type keyBackupContents struct {
+	// Files are the backed up files.
+	Files []*keyBackupFile `json:"files"`
+}
+
+// validate checks that the key derivation parameters are within sane bounds.
+func (kdf *keyBackupKDF) validate() error {
+	if len(kdf.Salt) < keyBackupMinSaltSize {
+		return fmt.Errorf("invalid salt size: %d", len(kdf.Salt))
+	}
+	if kdf.Time < 1 || kdf.Time > keyBackupMaxKDFTime {
+		return fmt.Errorf("invalid time parameter: %d", kdf.Time)
+	}
+	if kdf.Threads < 1 || kdf.Threads > keyBackupMaxKDFThreads {
+		return fmt.Errorf("invalid threads parameter: %d", kdf.Threads)
+	}
+	// Argon2 requires at least 8 KiB of memory per thread.
+	if kdf.Memory < 8*uint32(kdf.Threads) || kdf.Memory > keyBackupMaxKDFMemory {
+		return fmt.Errorf("invalid memory parameter: %d", kdf.Memory)
+	}
+	return nil
+}
+
+func (h *keyBackupHeader) deriveKey(passphrase string) ([]byte, error) {
+	if err := h.KDF.validate(); err != nil {
+		return nil, fmt.Errorf("malformed backup archive: %w", err)
+	}
+	return argon2.IDKey([]byte(passphrase), h.KDF.Salt, h.KDF.Time, h.KDF.Memory, h.KDF.Threads, deoxysii.KeySize), nil
+}
+
+// sealKeyBackup encrypts the given contents with a key derived from the passphrase.
+func sealKeyBackup(contents *keyBackupContents, passphrase string) ([]byte, error) {
+	hdr := keyBackupHeader{
+		Versioned: cbor.NewVersioned(keyBackupVersion),
+		KDF: keyBackupKDF{
+			Salt:    make([]byte, keyBackupSaltSize),
+			Time:    keyBackupKDFTime,
+			Memory:  keyBackupKDFMemory,
+			Threads: keyBackupKDFThreads,
+		},
+		Nonce: make([]byte, deoxysii.NonceSize),
+	}
+	if _, err := rand.Read(hdr.KDF.Salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	if _, err := rand.Read(hdr.Nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	key, err := hdr.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := deoxysii.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext := cbor.Marshal(contents)
+	ciphertext := aead.Seal(nil, hdr.Nonce, plaintext, cbor.Marshal(hdr))
+	for i := range plaintext {
+		plaintext[i] = 0
+	}
+
+	return cbor.Marshal(&keyBackup{
+		Header:     hdr,
+		Ciphertext: ciphertext,
+	}), nil
+}
+
+// openKeyBackup decrypts the given key backup archive with a key derived from the passphrase.
+func openKeyBackup(raw []byte, passphrase string) (*keyBackupContents, error) {
+	var kb keyBackup
+	if err := cbor.Unmarshal(raw, &kb); err != nil {
+		return nil, fmt.Errorf("malformed backup archive: %w", err)
+	}
+	if kb.Header.V != keyBackupVersion {
+		return nil, fmt.Errorf("unsupported backup archive version: %d", kb.Header.V)
+	}
+	if len(kb.Header.Nonce) != deoxysii.NonceSize {
+		return nil, fmt.Errorf("malformed backup archive: invalid nonce size")
+	}
+
+	key, err := kb.Header.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := deoxysii.New(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, kb.Header.Nonce, kb.Ciphertext, cbor.Marshal(kb.Header))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt backup archive (wrong passphrase?)")
+	}
+
+	var contents keyBackupContents
+	if err = cbor.Unmarshal(plaintext, &contents); err != nil {
+		return nil, fmt.Errorf("malformed backup archive contents: %w", err)
+	}
+	return &contents, nil
+}
+
+// signerFromPEM creates a new signer from a PEM encoded private key, decrypting it with the
+// configured signer passphrase in case it is encrypted.
+func signerFromPEM(role signature.SignerRole, data []byte) (*fileSigner.Signer, error) {
+	var passphrase []byte
+	if fileSigner.IsEncryptedPEM(data) {
+		var err error
+		if passphrase, err = cmdSigner.FilePassphrase(); err != nil {
+			return nil, err
+		}
+	}
+	return fileSigner.NewSignerFromPEMWithPassphrase(role, data, passphrase)
+}
+
+// readBackupFile reads a file that should be included in the backup.
+func readBackupFile(dir, name string, role signature.SignerRole) (*keyBackupFile, error) {
+	fn := filepath.Join(dir, name)
+	fi, err := os.Stat(fn)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &keyBackupFile{
+		Name: name,
+		Role: role,
+		Mode: fi.Mode().Perm(),
+		Data: data,
+	}
+	if role != signature.SignerUnknown {
+		signer, err := signerFromPEM(role, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fn, err)
+		}
+		pk := signer.Public()
+		signer.Reset()
+		f.PublicKey = &pk
+	}
+	return f, nil
+}
+
+// collectBackupFiles collects the node keys and TLS identities from the data directory and the
+// entity key from the entity directory (if configured).
+func collectBackupFiles(dataDir, entityDir string) ([]*keyBackupFile, error) {
+	var files []*keyBackupFile
+	if entityDir != "" {
+		f, err := readBackupFile(entityDir, fileSigner.FileEntityKey, signature.SignerEntity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read entity key: %w", err)
+		}
+		files = append(files, f)
+	}
+	for _, role := range identity.RequiredSignerRoles {
+		f, err := readBackupFile(dataDir, nodeKeyFilenames[role], role)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s key: %w", role, err)
+		}
+		files = append(files, f)
+	}
+	for _, name := range identity.PersistentTLSFilenames {
+		f, err := readBackupFile(dataDir, name, signature.SignerUnknown)
+		switch {
+		case err == nil:
+			files = append(files, f)
+		case errors.Is(err, os.ErrNotExist):
+			// TLS identities are only persisted in some configurations.
+		default:
+			return nil, fmt.Errorf("failed to read TLS identity: %w", err)
+		}
+	}
+	return files, nil
+}
+
+// verifyBackupFiles verifies that all signing keys correspond to their recorded public keys and
+// that all of the expected public keys are present.
+func verifyBackupFiles(files []*keyBackupFile, expected []signature.PublicKey) error {
+	present := make(map[signature.PublicKey]bool)
+	for _, f := range files {
+		if f.Name != filepath.Base(f.Name) || f.Name == "." || f.Name == ".." {
+			return fmt.Errorf("invalid file name in backup archive: %s", f.Name)
+		}
+		if f.Role == signature.SignerUnknown {
+			continue
+		}
+		if f.PublicKey == nil {
+			return fmt.Errorf("missing public key for %s", f.Name)
+		}
+		signer, err := signerFromPEM(f.Role, f.Data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+		pk := signer.Public()
+		signer.Reset()
+		if !pk.Equal(*f.PublicKey) {
+			return fmt.Errorf("%s: public key mismatch (expected: %s actual: %s)", f.Name, f.PublicKey, pk)
+		}
+		present[pk] = true
+	}
+	for _, pk := range expected {
+		if !present[pk] {
+			return fmt.Errorf("expected public key not found in backup archive: %s", pk)
+		}
+	}
+	return nil
+}
+
+// restoreBackupFiles writes the backed up files into the data and entity directories. Existing
+// files are never overwritten and all files are created with keyBackupFileMode, regardless of the
+// permissions recorded in the archive.
+func restoreBackupFiles(files []*keyBackupFile, dataDir, entityDir string) error {
+	path := func(f *keyBackupFile) string {
+		if f.Role == signature.SignerEntity {
+			return filepath.Join(entityDir, f.Name)
+		}
+		return filepath.Join(dataDir, f.Name)
+	}
+
+	for _, f := range files {
+		if f.Role == signature.SignerEntity && entityDir == "" {
+			return fmt.Errorf("backup archive contains an entity key, %s must be set", CfgBackupEntityDir)
+		}
+		if _, err := os.Stat(path(f)); !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("refusing to overwrite existing file: %s", path(f))
+		}
+	}
+	for _, f := range files {
+		if err := writeBackupFile(path(f), f.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeBackupFile creates a new file with keyBackupFileMode permissions and writes the data.
+func writeBackupFile(fn string, data []byte) error {
+	f, err := os.OpenFile(fn, os.O_WRONLY|os.O_CREATE|os.O_EXCL, keyBackupFileMode)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func printBackupFingerprints(files []*keyBackupFile) {
+	fmt.Println("Key fingerprints:")
+	for _, f := range files {
+		if f.PublicKey == nil {
+			continue
+		}
+		fmt.Printf("  %-10s %s\n", f.Role.String()+":", f.PublicKey)
+	}
+}
+
+// getBackupPassphrase returns the backup archive passphrase, either from the configured file or by
+// prompting the user.
+//
+// When creating a new archive, the passphrase must be at least minBackupPassphraseLength
+// characters long and, if prompted for, is confirmed by the user.
+func getBackupPassphrase(create bool) (string, error) {
+	if fn := viper.GetString(CfgBackupPassphraseFile); fn != "" {
+		raw, err := ioutil.ReadFile(fn)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase file: %w", err)
+		}
+		passphrase := strings.TrimRight(string(raw), "\r\n")
+		if passphrase == "" {
+			return "", fmt.Errorf("empty passphrase")
+		}
+		if create {
+			if err = checkBackupPassphrase(passphrase); err != nil {
+				return "", err
+			}
+		}
+		return passphrase, nil
+	}
+
+	passphrase, err := cmdCommon.GetUserPassphrase("Passphrase")
+	if err != nil {
+		return "", err
+	}
+	if !create {
+		return passphrase, nil
+	}
+	if err = checkBackupPassphrase(passphrase); err != nil {
+		return "", err
+	}
+	again, err := cmdCommon.GetUserPassphrase("Repeat passphrase")
+	if err != nil {
+		return "", err
+	}
+	if again != passphrase {
+		return "", fmt.Errorf("passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+// checkBackupPassphrase checks that the passphrase used to create a new archive is long enough.
+func checkBackupPassphrase(passphrase string) error {
+	if len(passphrase) < minBackupPassphraseLength {
+		return fmt.Errorf("passphrase must be at least %d characters long", minBackupPassphraseLength)
+	}
+	return nil
+}
+
+func doBackup(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	dataDir := cmdCommon.DataDir()
+	if dataDir == "" {
+		logger.Error("data directory must be set")
+		os.Exit(1)
+	}
+
+	files, err := collectBackupFiles(dataDir, viper.GetString(CfgBackupEntityDir))
+	if err != nil {
+		logger.Error("failed to collect keys",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	passphrase, err := getBackupPassphrase(true)
+	if err != nil {
+		logger.Error("failed to obtain passphrase",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	raw, err := sealKeyBackup(&keyBackupContents{Files: files}, passphrase)
+	if err != nil {
+		logger.Error("failed to encrypt backup archive",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	f, err := os.OpenFile(args[0], os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		logger.Error("failed to create backup archive",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer f.Close()
+	if _, err = f.Write(raw); err != nil {
+		logger.Error("failed to write backup archive",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Wrote backup archive with %d files to: %s\n", len(files), args[0])
+	printBackupFingerprints(files)
+	fmt.Println()
+	fmt.Println("WARNING: The archive contains private keys. Store it (and the passphrase) securely.")
+	fmt.Println("WARNING: Never run more than one node with the same keys, this may lead to double signing.")
+}
+
+func doRestore(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	dataDir := cmdCommon.DataDir()
+	if dataDir == "" {
+		logger.Error("data directory must be set")
+		os.Exit(1)
+	}
+
+	var expected []signature.PublicKey
+	for _, s := range viper.GetStringSlice(CfgBackupVerifyKeys) {
+		var pk signature.PublicKey
+		if err := pk.UnmarshalText([]byte(s)); err != nil {
+			logger.Error("malformed public key to verify",
+				"err", err,
+				"public_key", s,
+			)
+			os.Exit(1)
+		}
+		expected = append(expected, pk)
+	}
+
+	raw, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		logger.Error("failed to read backup archive",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	passphrase, err := getBackupPassphrase(false)
+	if err != nil {
+		logger.Error("failed to obtain passphrase",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	contents, err := openKeyBackup(raw, passphrase)
+	if err != nil {
+		logger.Error("failed to open backup archive",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err = verifyBackupFiles(contents.Files, expected); err != nil {
+		logger.Error("failed to verify backup archive",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	printBackupFingerprints(contents.Files)
+	fmt.Println()
+	fmt.Println("WARNING: Never run more than one node with the same keys, this may lead to double signing.")
+	if !cmdCommon.GetUserConfirmation("Restore the above keys? (y)es/(n)o: ") {
+		os.Exit(1)
+	}
+
+	if err = restoreBackupFiles(contents.Files, dataDir, viper.GetString(CfgBackupEntityDir)); err != nil {
+		logger.Error("failed to restore keys",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Restored %d files\n", len(contents.Files))
+}
+
+func init() {
+	backupFlags.String(CfgBackupEntityDir, "", "path to directory containing the entity key (if not set, the entity key is not included)")
+	backupFlags.String(CfgBackupPassphraseFile, "", "path to file containing the archive passphrase (if not set, the passphrase is prompted for)")
+	_ = viper.BindPFlags(backupFlags)
+	backupFlags.AddFlagSet(cmdSigner.FileFlags)
+
+	restoreFlags.StringSlice(CfgBackupVerifyKeys, nil, "public keys that must be present in the backup archive")
+	_ = viper.BindPFlags(restoreFlags)
+	restoreFlags.AddFlagSet(backupFlags)
+}
@@ -0,0 +1,143 @@
+package identity
+
+import (
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	fileSigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/file"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/identity"
+)
+
+func TestKeyBackup(t *testing.T) {
+	require := require.New(t)
+
+	srcDir, err := ioutil.TempDir("", "oasis-node-test_identity_backup_src_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(srcDir)
+	dstDir, err := ioutil.TempDir("", "oasis-node-test_identity_backup_dst_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dstDir)
+
+	// Provision a node identity and an entity key.
+	nodeFactory, err := fileSigner.NewFactory(srcDir, identity.RequiredSignerRoles...)
+	require.NoError(err, "NewFactory")
+	ident, err := identity.LoadOrGenerate(srcDir, nodeFactory, true)
+	require.NoError(err, "LoadOrGenerate")
+	entityFactory, err := fileSigner.NewFactory(srcDir, signature.SignerEntity)
+	require.NoError(err, "NewFactory")
+	entitySigner, err := entityFactory.Generate(signature.SignerEntity, rand.Reader)
+	require.NoError(err, "Generate")
+
+	files, err := collectBackupFiles(srcDir, srcDir)
+	require.NoError(err, "collectBackupFiles")
+	require.NoError(verifyBackupFiles(files, nil), "verifyBackupFiles")
+
+	raw, err := sealKeyBackup(&keyBackupContents{Files: files}, "correct horse battery staple")
+	require.NoError(err, "sealKeyBackup")
+
+	_, err = openKeyBackup(raw, "incorrect horse battery staple")
+	require.Error(err, "openKeyBackup should fail with the wrong passphrase")
+
+	contents, err := openKeyBackup(raw, "correct horse battery staple")
+	require.NoError(err, "openKeyBackup")
+	require.Len(contents.Files, len(files))
+
+	expected := []signature.PublicKey{
+		entitySigner.Public(),
+		ident.NodeSigner.Public(),
+		ident.ConsensusSigner.Public(),
+	}
+	require.NoError(verifyBackupFiles(contents.Files, expected), "verifyBackupFiles")
+	unknownSigner := memorySigner.NewTestSigner("oasis-node identity backup test: unknown")
+	require.Error(verifyBackupFiles(contents.Files, []signature.PublicKey{unknownSigner.Public()}),
+		"verifyBackupFiles should fail with an unknown expected key",
+	)
+
+	// Tamper with a key, which should be detected.
+	tampered := *contents.Files[1]
+	tampered.PublicKey = contents.Files[0].PublicKey
+	require.Error(verifyBackupFiles([]*keyBackupFile{&tampered}, nil), "verifyBackupFiles should detect a mismatch")
+
+	// Restoring without an entity directory should fail.
+	require.Error(restoreBackupFiles(contents.Files, dstDir, ""), "restoreBackupFiles without entity dir")
+
+	// Restored files should never be more permissive than keyBackupFileMode.
+	for _, f := range contents.Files {
+		f.Mode = 0o644
+	}
+	require.NoError(restoreBackupFiles(contents.Files, dstDir, dstDir), "restoreBackupFiles")
+	dstFactory, err := fileSigner.NewFactory(dstDir, identity.RequiredSignerRoles...)
+	require.NoError(err, "NewFactory")
+	restored, err := identity.Load(dstDir, dstFactory)
+	require.NoError(err, "identity.Load")
+	require.Equal(ident.NodeSigner.Public(), restored.NodeSigner.Public())
+	require.Equal(ident.P2PSigner.Public(), restored.P2PSigner.Public())
+	require.Equal(ident.ConsensusSigner.Public(), restored.ConsensusSigner.Public())
+	require.Equal(ident.VRFSigner.Public(), restored.VRFSigner.Public())
+	require.Equal(ident.GetTLSSigner().Public(), restored.GetTLSSigner().Public())
+
+	for _, f := range contents.Files {
+		fi, err := os.Stat(filepath.Join(dstDir, f.Name))
+		require.NoError(err, "Stat")
+		require.EqualValues(0o600, fi.Mode().Perm(), "restored file mode (%s)", f.Name)
+	}
+
+	// Restoring again should refuse to overwrite the existing keys.
+	require.Error(restoreBackupFiles(contents.Files, dstDir, dstDir), "restoreBackupFiles should not overwrite")
+}
+
+func TestKeyBackupKDFBounds(t *testing.T) {
+	require := require.New(t)
+
+	raw, err := sealKeyBackup(&keyBackupContents{}, "correct horse battery staple")
+	require.NoError(err, "sealKeyBackup")
+
+	for _, tc := range []struct {
+		name   string
+		tamper func(kdf *keyBackupKDF)
+	}{
+		{"ZeroThreads", func(kdf *keyBackupKDF) { kdf.Threads = 0 }},
+		{"TooManyThreads", func(kdf *keyBackupKDF) { kdf.Threads = keyBackupMaxKDFThreads + 1 }},
+		{"ZeroTime", func(kdf *keyBackupKDF) { kdf.Time = 0 }},
+		{"TooMuchTime", func(kdf *keyBackupKDF) { kdf.Time = keyBackupMaxKDFTime + 1 }},
+		{"TooLittleMemory", func(kdf *keyBackupKDF) { kdf.Memory = 8*uint32(kdf.Threads) - 1 }},
+		{"TooMuchMemory", func(kdf *keyBackupKDF) { kdf.Memory = keyBackupMaxKDFMemory + 1 }},
+		{"ShortSalt", func(kdf *keyBackupKDF) { kdf.Salt = kdf.Salt[:keyBackupMinSaltSize-1] }},
+	} {
+		var kb keyBackup
+		require.NoError(cbor.Unmarshal(raw, &kb), "Unmarshal")
+		tc.tamper(&kb.Header.KDF)
+
+		_, err = openKeyBackup(cbor.Marshal(&kb), "correct horse battery staple")
+		require.Error(err, "openKeyBackup should reject out of bounds KDF parameters (%s)", tc.name)
+	}
+}
+
+func TestBackupPassphraseFile(t *testing.T) {
+	require := require.New(t)
+
+	fn := filepath.Join(t.TempDir(), "passphrase")
+	viper.Set(CfgBackupPassphraseFile, fn)
+	defer viper.Set(CfgBackupPassphraseFile, nil)
+
+	require.NoError(ioutil.WriteFile(fn, []byte("short\n"), 0o600), "WriteFile")
+	_, err := getBackupPassphrase(true)
+	require.Error(err, "short passphrases should be rejected when creating an archive")
+	passphrase, err := getBackupPassphrase(false)
+	require.NoError(err, "short passphrases should be accepted when opening an archive")
+	require.Equal("short", passphrase)
+
+	require.NoError(ioutil.WriteFile(fn, []byte("correct horse battery staple\n"), 0o600), "WriteFile")
+	passphrase, err = getBackupPassphrase(true)
+	require.NoError(err, "getBackupPassphrase")
+	require.Equal("correct horse battery staple", passphrase)
+}
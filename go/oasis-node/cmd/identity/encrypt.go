@@ -0,0 +1,148 @@
+package identity
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	fileSigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/file"
+	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
+)
+
+// CfgEncryptEntityDir is the directory containing the entity key to encrypt.
+const CfgEncryptEntityDir = "identity.encrypt.entity_dir"
+
+var (
+	identityEncryptKeysCmd = &cobra.Command{
+		Use:   "encrypt-keys",
+		Short: "encrypt unencrypted node (and entity) private keys with the signer passphrase",
+		Run:   doEncryptKeys,
+	}
+
+	encryptKeysFlags = flag.NewFlagSet("", flag.ContinueOnError)
+)
+
+// encryptKeyFile encrypts the private key in the given file in place. Already encrypted keys are
+// left untouched. Returns true iff the key was encrypted.
+func encryptKeyFile(fn string, role signature.SignerRole, passphrase []byte) (bool, error) {
+	data, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return false, err
+	}
+	if fileSigner.IsEncryptedPEM(data) {
+		// Make sure that the key can be decrypted with the passphrase.
+		signer, err := fileSigner.NewSignerFromPEMWithPassphrase(role, data, passphrase)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", fn, err)
+		}
+		signer.Reset()
+		return false, nil
+	}
+
+	signer, err := fileSigner.NewSignerFromPEM(role, data)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", fn, err)
+	}
+	pk := signer.Public()
+	signer.Reset()
+
+	encrypted, err := fileSigner.EncryptPEM(data, passphrase)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", fn, err)
+	}
+
+	// Verify the encrypted key before replacing the unencrypted one.
+	signer, err = fileSigner.NewSignerFromPEMWithPassphrase(role, encrypted, passphrase)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to verify encrypted key: %w", fn, err)
+	}
+	encPk := signer.Public()
+	signer.Reset()
+	if !encPk.Equal(pk) {
+		return false, fmt.Errorf("%s: encrypted key public key mismatch", fn)
+	}
+
+	// Atomically replace the key file.
+	tmpFn := fn + ".tmp"
+	if err = ioutil.WriteFile(tmpFn, encrypted, 0o600); err != nil {
+		return false, err
+	}
+	if err = os.Rename(tmpFn, fn); err != nil {
+		_ = os.Remove(tmpFn)
+		return false, err
+	}
+	return true, nil
+}
+
+func doEncryptKeys(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	dataDir := cmdCommon.DataDir()
+	if dataDir == "" {
+		logger.Error("data directory must be set")
+		os.Exit(1)
+	}
+
+	passphrase, err := cmdSigner.FilePassphrase()
+	if err != nil {
+		logger.Error("failed to obtain signer passphrase",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if passphrase == nil {
+		logger.Error("signer passphrase must be configured",
+			"passphrase_file", cmdSigner.CfgSignerFilePassphraseFile,
+			"passphrase_command", cmdSigner.CfgSignerFilePassphraseCommand,
+			"passphrase_prompt", cmdSigner.CfgSignerFilePassphrasePrompt,
+		)
+		os.Exit(1)
+	}
+
+	type keyFile struct {
+		path string
+		role signature.SignerRole
+	}
+	var keys []keyFile
+	if entityDir := viper.GetString(CfgEncryptEntityDir); entityDir != "" {
+		keys = append(keys, keyFile{filepath.Join(entityDir, fileSigner.FileEntityKey), signature.SignerEntity})
+	}
+	for _, role := range identity.RequiredSignerRoles {
+		keys = append(keys, keyFile{filepath.Join(dataDir, nodeKeyFilenames[role]), role})
+	}
+
+	for _, k := range keys {
+		encrypted, err := encryptKeyFile(k.path, k.role, passphrase)
+		if err != nil {
+			logger.Error("failed to encrypt private key",
+				"err", err,
+				"role", k.role,
+			)
+			os.Exit(1)
+		}
+		if encrypted {
+			fmt.Printf("Encrypted %s key: %s\n", k.role, k.path)
+		} else {
+			fmt.Printf("Already encrypted %s key: %s\n", k.role, k.path)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("NOTE: The node must now be started with the same signer passphrase configured.")
+}
+
+func init() {
+	encryptKeysFlags.String(CfgEncryptEntityDir, "", "path to directory containing the entity key (if not set, the entity key is not encrypted)")
+	_ = viper.BindPFlags(encryptKeysFlags)
+	encryptKeysFlags.AddFlagSet(cmdSigner.FileFlags)
+}
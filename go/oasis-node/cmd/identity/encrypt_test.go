@@ -0,0 +1,63 @@
+package identity
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	fileSigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/file"
+	"github.com/oasisprotocol/oasis-core/go/common/identity"
+)
+
+func TestEncryptKeys(t *testing.T) {
+	require := require.New(t)
+
+	dataDir, err := ioutil.TempDir("", "oasis-node-test_identity_encrypt_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dataDir)
+
+	factory, err := fileSigner.NewFactory(dataDir, identity.RequiredSignerRoles...)
+	require.NoError(err, "NewFactory")
+	ident, err := identity.LoadOrGenerate(dataDir, factory, true)
+	require.NoError(err, "LoadOrGenerate")
+
+	passphrase := []byte("correct horse battery staple")
+	for _, role := range identity.RequiredSignerRoles {
+		fn := filepath.Join(dataDir, nodeKeyFilenames[role])
+		encrypted, err := encryptKeyFile(fn, role, passphrase)
+		require.NoError(err, "encryptKeyFile")
+		require.True(encrypted, "key should be encrypted")
+
+		fi, err := os.Stat(fn)
+		require.NoError(err, "Stat")
+		require.EqualValues(0o600, fi.Mode().Perm())
+
+		// Encrypting again should be a no-op.
+		encrypted, err = encryptKeyFile(fn, role, passphrase)
+		require.NoError(err, "encryptKeyFile")
+		require.False(encrypted, "key should already be encrypted")
+
+		// A wrong passphrase should be detected.
+		_, err = encryptKeyFile(fn, role, []byte("wrong"))
+		require.ErrorIs(err, fileSigner.ErrWrongPassphrase)
+	}
+
+	// Loading without a passphrase should fail.
+	_, err = identity.Load(dataDir, factory)
+	require.Error(err, "identity.Load without passphrase")
+
+	encFactory, err := fileSigner.NewFactory(&fileSigner.FactoryConfig{
+		Dir:        dataDir,
+		Passphrase: passphrase,
+	}, identity.RequiredSignerRoles...)
+	require.NoError(err, "NewFactory")
+	loaded, err := identity.Load(dataDir, encFactory)
+	require.NoError(err, "identity.Load")
+	require.Equal(ident.NodeSigner.Public(), loaded.NodeSigner.Public())
+	require.Equal(ident.P2PSigner.Public(), loaded.P2PSigner.Public())
+	require.Equal(ident.ConsensusSigner.Public(), loaded.ConsensusSigner.Public())
+	require.Equal(ident.VRFSigner.Public(), loaded.VRFSigner.Public())
+}
@@ -10,11 +10,11 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
-	fileSigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/file"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
+	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/identity/tendermint"
 )
 
@@ -57,7 +57,7 @@ func doNodeInit(cmd *cobra.Command, args []string) {
 	}
 
 	// Provision the node identity.
-	nodeSignerFactory, err := fileSigner.NewFactory(dataDir, identity.RequiredSignerRoles...)
+	nodeSignerFactory, err := cmdSigner.NewFileFactory(dataDir, identity.RequiredSignerRoles...)
 	if err != nil {
 		logger.Error("failed to create identity signer factory",
 			"err", err,
@@ -74,6 +74,11 @@ func doNodeInit(cmd *cobra.Command, args []string) {
 	fmt.Printf("Generated identity files in: %s\n", dataDir)
 }
 
+// publicKey is the result of the show-tls-pubkey and show-sentry-client-pubkey commands.
+type publicKey struct {
+	PublicKey signature.PublicKey `json:"public_key"`
+}
+
 func doShowPubkey(cmd *cobra.Command, args []string, sentry bool) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
@@ -85,7 +90,7 @@ func doShowPubkey(cmd *cobra.Command, args []string, sentry bool) {
 		os.Exit(1)
 	}
 
-	nodeSignerFactory, err := fileSigner.NewFactory(dataDir, identity.RequiredSignerRoles...)
+	nodeSignerFactory, err := cmdSigner.NewFileFactory(dataDir, identity.RequiredSignerRoles...)
 	if err != nil {
 		logger.Error("failed to create node identity signer factory",
 			"err", err,
@@ -118,7 +123,11 @@ func doShowPubkey(cmd *cobra.Command, args []string, sentry bool) {
 	}
 	key, _ := pubKey.MarshalText()
 
-	fmt.Println(string(key))
+	if err = cmdCommon.PrintResult(&publicKey{PublicKey: pubKey}, func() {
+		fmt.Println(string(key))
+	}); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
 }
 
 func doShowTLSPubkey(cmd *cobra.Command, args []string) {
@@ -134,9 +143,19 @@ func Register(parentCmd *cobra.Command) {
 	tendermint.Register(identityCmd)
 
 	identityInitCmd.Flags().AddFlagSet(cmdFlags.VerboseFlags)
+	identityInitCmd.Flags().AddFlagSet(cmdSigner.FileFlags)
 	identityCmd.AddCommand(identityInitCmd)
+	identityShowSentryPubkeyCmd.Flags().AddFlagSet(cmdSigner.FileFlags)
 	identityCmd.AddCommand(identityShowSentryPubkeyCmd)
+	identityShowTLSPubkeyCmd.Flags().AddFlagSet(cmdSigner.FileFlags)
 	identityCmd.AddCommand(identityShowTLSPubkeyCmd)
 
+	identityBackupCmd.Flags().AddFlagSet(backupFlags)
+	identityCmd.AddCommand(identityBackupCmd)
+	identityRestoreCmd.Flags().AddFlagSet(restoreFlags)
+	identityCmd.AddCommand(identityRestoreCmd)
+	identityEncryptKeysCmd.Flags().AddFlagSet(encryptKeysFlags)
+	identityCmd.AddCommand(identityEncryptKeysCmd)
+
 	parentCmd.AddCommand(identityCmd)
 }
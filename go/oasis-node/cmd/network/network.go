@@ -0,0 +1,83 @@
+// Package network implements the network profile sub-commands.
+package network
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+)
+
+var (
+	networkCmd = &cobra.Command{
+		Use:   "network",
+		Short: "network profile utilities",
+	}
+
+	networkListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "list configured network profiles",
+		Run:   doList,
+	}
+
+	logger = logging.GetLogger("cmd/network")
+)
+
+func doList(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	path := viper.GetString(cmdCommon.CfgNetworkProfiles)
+	if path == "" {
+		path = cmdCommon.DefaultNetworkProfilesPath()
+	}
+	profiles, err := cmdCommon.LoadNetworkProfiles(path)
+	if err != nil {
+		logger.Error("failed to load network profiles",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	list := make([]*cmdCommon.NetworkProfile, 0, len(profiles))
+	for _, p := range profiles {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	if err = cmdCommon.PrintResult(list, func() {
+		selected := cmdCommon.Network()
+		for _, p := range list {
+			marker := " "
+			if selected != nil && selected.Name == p.Name {
+				marker = "*"
+			}
+			fmt.Printf("%s %s\n", marker, p.Name)
+			if p.ChainContext != "" {
+				fmt.Printf("    Chain context: %s\n", p.ChainContext)
+			}
+			if p.Address != "" {
+				fmt.Printf("    Address:       %s\n", p.Address)
+			}
+		}
+	}); err != nil {
+		logger.Error("failed to print network profiles",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+// Register registers the network sub-command and all of its children.
+func Register(parentCmd *cobra.Command) {
+	networkCmd.AddCommand(networkListCmd)
+	parentCmd.AddCommand(networkCmd)
+}
@@ -2,16 +2,30 @@ package node
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	sentryAPI "github.com/oasisprotocol/oasis-core/go/sentry/api"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+	workerCommon "github.com/oasisprotocol/oasis-core/go/worker/common"
+	"github.com/oasisprotocol/oasis-core/go/worker/common/p2p"
+	"github.com/oasisprotocol/oasis-core/go/worker/compute/executor"
+	executorWorker "github.com/oasisprotocol/oasis-core/go/worker/compute/executor/api"
+	executorCommittee "github.com/oasisprotocol/oasis-core/go/worker/compute/executor/committee"
 	"github.com/oasisprotocol/oasis-core/go/worker/registration"
+	workerSentry "github.com/oasisprotocol/oasis-core/go/worker/sentry"
 )
 
 var (
@@ -21,6 +35,14 @@ var (
 
 // Implements registration.Delegate.
 func (n *Node) RegistrationStopped() {
+	n.shutdownLock.Lock()
+	draining := n.shutdownCh != nil
+	n.shutdownLock.Unlock()
+	if draining {
+		// The node will be stopped once it is drained.
+		return
+	}
+
 	n.Stop()
 }
 
@@ -46,6 +68,185 @@ func (n *Node) RequestShutdown() (<-chan struct{}, error) {
 	return n.RegistrationWorker.Quit(), nil
 }
 
+// Implements control.ControlledNode.
+func (n *Node) Shutdown(req *control.ShutdownRequest) (<-chan struct{}, error) {
+	n.shutdownLock.Lock()
+	defer n.shutdownLock.Unlock()
+
+	if n.shutdownCh != nil {
+		// Shutdown already in progress.
+		return n.shutdownCh, nil
+	}
+
+	deregister := req.Deregister && n.RegistrationWorker != nil
+	if deregister {
+		if err := n.RegistrationWorker.RequestDeregistration(); err != nil {
+			return nil, err
+		}
+	}
+
+	drainTimeout := req.Timeout
+	if drainTimeout == 0 {
+		drainTimeout = control.DefaultShutdownTimeout
+	}
+	deregisterTimeout := req.DeregisterTimeout
+	if deregisterTimeout == 0 {
+		deregisterTimeout = control.DefaultDeregisterTimeout
+	}
+
+	n.logger.Info("graceful shutdown requested",
+		"deregister", deregister,
+		"deregister_timeout", deregisterTimeout,
+		"drain_timeout", drainTimeout,
+	)
+
+	var deregistered <-chan struct{}
+	if deregister {
+		deregistered = n.RegistrationWorker.Quit()
+	}
+
+	ch := make(chan struct{})
+	n.shutdownCh = ch
+	go func() {
+		n.shutdownPhases(deregistered, deregisterTimeout, drainTimeout, n.drain)
+
+		close(ch)
+		n.Stop()
+	}()
+	return ch, nil
+}
+
+// shutdownPhases waits for the deregistration to take effect (in case deregistered is not nil)
+// and then drains the node, giving each of the phases its own deadline so that a slow
+// deregistration does not cut the drain short.
+func (n *Node) shutdownPhases(
+	deregistered <-chan struct{},
+	deregisterTimeout time.Duration,
+	drainTimeout time.Duration,
+	drain func(context.Context),
+) {
+	if deregistered != nil {
+		timer := time.NewTimer(deregisterTimeout)
+		select {
+		case <-deregistered:
+		case <-timer.C:
+			n.logger.Warn("deregistration did not take effect in time, draining anyway",
+				"deregister_timeout", deregisterTimeout,
+			)
+		}
+		timer.Stop()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	drain(ctx)
+}
+
+// drain stops all runtimes from accepting new work and waits for the in-flight batches and
+// storage applies to complete.
+func (n *Node) drain(ctx context.Context) {
+	// Seed node doesn't have a runtime registry.
+	if n.RuntimeRegistry == nil {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, rt := range n.RuntimeRegistry.Runtimes() {
+		if executorNode := n.ExecutorWorker.GetRuntime(rt.ID()); executorNode != nil {
+			wg.Add(1)
+			go func(id common.Namespace) {
+				defer wg.Done()
+
+				if err := executorNode.Drain(ctx); err != nil {
+					n.logger.Warn("failed to drain executor worker",
+						"err", err,
+						"runtime_id", id,
+					)
+				}
+			}(rt.ID())
+		}
+	}
+	wg.Wait()
+
+	// Storage is drained after the executors, as finishing in-flight batches may still require
+	// the storage workers to apply new rounds.
+	for _, rt := range n.RuntimeRegistry.Runtimes() {
+		if storageNode := n.StorageWorker.GetRuntime(rt.ID()); storageNode != nil {
+			wg.Add(1)
+			go func(id common.Namespace) {
+				defer wg.Done()
+
+				if err := storageNode.Drain(ctx); err != nil {
+					n.logger.Warn("failed to drain storage worker",
+						"err", err,
+						"runtime_id", id,
+					)
+				}
+			}(rt.ID())
+		}
+	}
+	wg.Wait()
+}
+
+// Implements control.ControlledNode.
+func (n *Node) SetLogLevel(ctx context.Context, req *control.SetLogLevelRequest) error {
+	if req.Level == nil {
+		if err := logging.ResetModuleLevel(req.Module); err != nil {
+			return err
+		}
+		n.logger.Info("removed module log level override",
+			"module", req.Module,
+		)
+		return nil
+	}
+
+	if err := logging.SetModuleLevel(req.Module, *req.Level); err != nil {
+		return err
+	}
+	n.logger.Info("overrode module log level",
+		"module", req.Module,
+		"level", req.Level,
+	)
+	return nil
+}
+
+// Implements control.ControlledNode.
+func (n *Node) ReloadConfig(ctx context.Context) error {
+	n.reloadLock.Lock()
+	defer n.reloadLock.Unlock()
+
+	n.logger.Info("reloading configuration")
+
+	var reloadable []string
+	reloadable = append(reloadable, workerCommon.ReloadableConfig...)
+	reloadable = append(reloadable, executor.ReloadableConfig...)
+	reloadable = append(reloadable, workerSentry.ReloadableConfig...)
+
+	cfg, err := cmdCommon.ReloadConfig(Flags, reloadable)
+	if err != nil {
+		return err
+	}
+	if n.RegistrationWorker != nil {
+		if err = n.RegistrationWorker.ReloadConfig(cfg); err != nil {
+			return err
+		}
+	}
+	if n.ExecutorWorker != nil {
+		if err = n.ExecutorWorker.ReloadConfig(cfg); err != nil {
+			return err
+		}
+	}
+	if n.SentryWorker != nil {
+		if err = n.SentryWorker.ReloadConfig(cfg); err != nil {
+			return err
+		}
+	}
+
+	n.logger.Info("configuration reloaded")
+
+	return nil
+}
+
 // Implements control.ControlledNode.
 func (n *Node) Ready() <-chan struct{} {
 	return n.readyCh
@@ -64,6 +265,53 @@ func (n *Node) GetRegistrationStatus(ctx context.Context) (*control.Registration
 	return n.RegistrationWorker.GetRegistrationStatus(ctx)
 }
 
+// Implements control.ControlledNode.
+func (n *Node) CheckRuntimesProvisioned(ctx context.Context) []control.HealthCheck {
+	// Seed node doesn't have a runtime registry.
+	if n.RuntimeRegistry == nil {
+		return nil
+	}
+
+	var checks []control.HealthCheck
+	for _, rt := range n.RuntimeRegistry.Runtimes() {
+		check := control.HealthCheck{
+			Name:    "runtime/" + rt.ID().String(),
+			Healthy: true,
+		}
+
+		// The common committee node is initialized once the runtime is provisioned (in case it is
+		// hosted by this node) and all the runtime workers are initialized.
+		if rtNode := n.CommonWorker.GetRuntime(rt.ID()); rtNode != nil {
+			select {
+			case <-rtNode.Initialized():
+			default:
+				check.Healthy = false
+				check.Reason = "runtime is not yet provisioned"
+			}
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// Implements control.ControlledNode.
+func (n *Node) CheckSignerHealth(ctx context.Context) []control.HealthCheck {
+	sf, ok := n.signerFactory.(signature.HealthCheckedSignerFactory)
+	if !ok {
+		return nil
+	}
+
+	check := control.HealthCheck{
+		Name:    "signer",
+		Healthy: true,
+	}
+	if err := sf.Health(); err != nil {
+		check.Healthy = false
+		check.Reason = fmt.Sprintf("signer backend is unhealthy: %s", err)
+	}
+	return []control.HealthCheck{check}
+}
+
 // Implements control.ControlledNode.
 func (n *Node) GetRuntimeStatus(ctx context.Context) (map[common.Namespace]control.RuntimeStatus, error) {
 	runtimes := make(map[common.Namespace]control.RuntimeStatus)
@@ -156,6 +404,17 @@ func (n *Node) GetRuntimeStatus(ctx context.Context) (map[common.Namespace]contr
 			}
 		}
 
+		// Fetch executor worker status.
+		if executorNode := n.ExecutorWorker.GetRuntime(rt.ID()); executorNode != nil {
+			status.Executor, err = executorNode.GetStatus(ctx)
+			if err != nil {
+				n.logger.Error("failed to fetch executor worker status",
+					"err", err,
+					"runtime_id", rt.ID(),
+				)
+			}
+		}
+
 		// Fetch storage worker status.
 		if storageNode := n.StorageWorker.GetRuntime(rt.ID()); storageNode != nil {
 			status.Storage, err = storageNode.GetStatus(ctx)
@@ -176,3 +435,163 @@ func (n *Node) GetRuntimeStatus(ctx context.Context) (map[common.Namespace]contr
 func (n *Node) GetPendingUpgrades(ctx context.Context) ([]*upgrade.PendingUpgrade, error) {
 	return n.Upgrader.PendingUpgrades(ctx)
 }
+
+// Implements control.ControlledNode.
+func (n *Node) GetSentryStatus(ctx context.Context) (*sentryAPI.Status, error) {
+	if n.SentryWorker == nil {
+		return nil, nil
+	}
+	return n.SentryWorker.GetStatus(), nil
+}
+
+// Implements control.ControlledNode.
+func (n *Node) GetP2PPeers(ctx context.Context) ([]*control.P2PPeer, error) {
+	if n.P2P == nil {
+		return nil, control.ErrP2PDisabled
+	}
+
+	var peers []*control.P2PPeer
+	for _, ps := range n.P2P.PeerStatuses() {
+		peer := &control.P2PPeer{
+			ID:         ps.ID,
+			PeerID:     ps.PeerID.Pretty(),
+			Addresses:  ps.Addresses,
+			Connected:  ps.Connected,
+			Registered: ps.Registered,
+			Score:      ps.Score,
+		}
+		if ps.Ban != nil {
+			peer.Ban = &control.P2PPeerBan{
+				Until:  ps.Ban.Until,
+				Reason: ps.Ban.Reason,
+			}
+		}
+		peers = append(peers, peer)
+	}
+	return peers, nil
+}
+
+// Implements control.ControlledNode.
+func (n *Node) BanP2PPeer(ctx context.Context, req *control.BanP2PPeerRequest) error {
+	if n.P2P == nil {
+		return control.ErrP2PDisabled
+	}
+	return n.P2P.BanPeer(req.ID, req.Duration, req.Reason)
+}
+
+// Implements control.ControlledNode.
+func (n *Node) UnbanP2PPeer(ctx context.Context, id signature.PublicKey) error {
+	if n.P2P == nil {
+		return control.ErrP2PDisabled
+	}
+	err := n.P2P.UnbanPeer(id)
+	if errors.Is(err, p2p.ErrPeerNotBanned) {
+		return control.ErrP2PPeerNotBanned
+	}
+	return err
+}
+
+// Implements control.ControlledNode.
+func (n *Node) RotateCertificates(ctx context.Context) error {
+	if n.RegistrationWorker == nil {
+		return control.ErrRegistrationNotAvailable
+	}
+	return n.RegistrationWorker.RequestCertificateRotation(ctx)
+}
+
+// Implements control.ControlledNode.
+func (n *Node) SetProfiling(ctx context.Context, req *control.SetProfilingRequest) error {
+	if err := n.profiler.setProfiling(req); err != nil {
+		return err
+	}
+
+	n.logger.Info("profiling configuration changed",
+		"profile", req.Profile,
+		"enabled", req.Enabled,
+		"rate", req.Rate,
+	)
+	return nil
+}
+
+// Implements control.ControlledNode.
+func (n *Node) GetProfile(ctx context.Context, req *control.GetProfileRequest) ([]byte, error) {
+	return n.profiler.getProfile(req)
+}
+
+func (n *Node) getExecutorNode(runtimeID common.Namespace) (*executorCommittee.Node, error) {
+	rt := n.lookupExecutorNode(runtimeID)
+	if rt == nil {
+		return nil, control.ErrTxPoolNotAvailable
+	}
+	return rt, nil
+}
+
+func (n *Node) lookupExecutorNode(runtimeID common.Namespace) *executorCommittee.Node {
+	if n.ExecutorWorker == nil || !n.ExecutorWorker.Enabled() {
+		return nil
+	}
+	return n.ExecutorWorker.GetRuntime(runtimeID)
+}
+
+// Implements control.ControlledNode.
+func (n *Node) GetTxPoolStatus(ctx context.Context, runtimeID common.Namespace) (*executorWorker.TxPoolStatus, error) {
+	rt, err := n.getExecutorNode(runtimeID)
+	if err != nil {
+		return nil, err
+	}
+	return rt.GetTxPoolStatus()
+}
+
+// Implements control.ControlledNode.
+func (n *Node) GetTxPoolTransactions(ctx context.Context, runtimeID common.Namespace) ([]*executorWorker.TxPoolTransaction, error) {
+	rt, err := n.getExecutorNode(runtimeID)
+	if err != nil {
+		return nil, err
+	}
+	return rt.GetTxPoolTransactions()
+}
+
+// Implements control.ControlledNode.
+func (n *Node) RemoveTxPoolTransactions(ctx context.Context, req *control.RemoveTxPoolTransactionsRequest) ([]hash.Hash, error) {
+	rt, err := n.getExecutorNode(req.RuntimeID)
+	if err != nil {
+		return nil, err
+	}
+	return rt.RemoveTxPoolTransactions(req.Hashes)
+}
+
+// Implements control.ControlledNode.
+func (n *Node) PauseRuntime(ctx context.Context, runtimeID common.Namespace) error {
+	rt := n.lookupExecutorNode(runtimeID)
+	if rt == nil {
+		return control.ErrExecutorNotAvailable
+	}
+	rt.SetPaused(true)
+	return nil
+}
+
+// Implements control.ControlledNode.
+func (n *Node) ResumeRuntime(ctx context.Context, runtimeID common.Namespace) error {
+	rt := n.lookupExecutorNode(runtimeID)
+	if rt == nil {
+		return control.ErrExecutorNotAvailable
+	}
+	rt.SetPaused(false)
+	return nil
+}
+
+// Implements control.ControlledNode.
+func (n *Node) PruneRuntimeHistory(ctx context.Context, runtimeID common.Namespace) error {
+	if n.RuntimeRegistry == nil {
+		return control.ErrRuntimeNotAvailable
+	}
+	rt, err := n.RuntimeRegistry.GetRuntime(runtimeID)
+	if err != nil {
+		return control.ErrRuntimeNotAvailable
+	}
+
+	n.logger.Info("pruning runtime history",
+		"runtime_id", runtimeID,
+	)
+	return rt.History().Prune(ctx)
+}
@@ -0,0 +1,43 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+func TestShutdownPhases(t *testing.T) {
+	require := require.New(t)
+
+	n := &Node{logger: logging.GetLogger("oasis-node/test")}
+
+	drainDeadline := func(deregistered <-chan struct{}, deregisterTimeout, drainTimeout time.Duration) time.Duration {
+		var remaining time.Duration
+		n.shutdownPhases(deregistered, deregisterTimeout, drainTimeout, func(ctx context.Context) {
+			deadline, ok := ctx.Deadline()
+			require.True(ok, "drain context should have a deadline")
+			remaining = time.Until(deadline)
+		})
+		return remaining
+	}
+
+	// Without deregistration, the drain gets the whole drain timeout.
+	remaining := drainDeadline(nil, time.Hour, time.Minute)
+	require.InDelta(time.Minute, remaining, float64(time.Second))
+
+	// A completed deregistration does not affect the drain deadline.
+	deregistered := make(chan struct{})
+	close(deregistered)
+	remaining = drainDeadline(deregistered, time.Hour, time.Minute)
+	require.InDelta(time.Minute, remaining, float64(time.Second))
+
+	// A deregistration that times out does not cut the drain short.
+	start := time.Now()
+	remaining = drainDeadline(make(chan struct{}), 200*time.Millisecond, 300*time.Millisecond)
+	require.GreaterOrEqual(time.Since(start), 200*time.Millisecond, "deregistration should be waited for")
+	require.InDelta(300*time.Millisecond, remaining, float64(50*time.Millisecond))
+}
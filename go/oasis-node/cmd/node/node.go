@@ -6,8 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"sync"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
@@ -16,6 +18,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/badger"
 	"github.com/oasisprotocol/oasis-core/go/common/crash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
@@ -32,14 +35,17 @@ import (
 	governanceAPI "github.com/oasisprotocol/oasis-core/go/governance/api"
 	"github.com/oasisprotocol/oasis-core/go/ias"
 	iasAPI "github.com/oasisprotocol/oasis-core/go/ias/api"
+	"github.com/oasisprotocol/oasis-core/go/ias/pccs"
 	keymanagerAPI "github.com/oasisprotocol/oasis-core/go/keymanager/api"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/background"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/health"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/metrics"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/pprof"
 	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/tracing"
 	registryAPI "github.com/oasisprotocol/oasis-core/go/registry/api"
 	roothashAPI "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	runtimeClient "github.com/oasisprotocol/oasis-core/go/runtime/client"
@@ -52,6 +58,7 @@ import (
 	storageAPI "github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/upgrade"
 	upgradeAPI "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+	upgradeFetcher "github.com/oasisprotocol/oasis-core/go/upgrade/fetcher"
 	workerBeacon "github.com/oasisprotocol/oasis-core/go/worker/beacon"
 	workerCommon "github.com/oasisprotocol/oasis-core/go/worker/common"
 	"github.com/oasisprotocol/oasis-core/go/worker/common/p2p"
@@ -95,10 +102,16 @@ type Node struct {
 	svcMgr       *background.ServiceManager
 	grpcInternal *grpc.Server
 
-	stopOnce sync.Once
+	stopOnce   sync.Once
+	reloadLock sync.Mutex
+
+	shutdownLock sync.Mutex
+	shutdownCh   chan struct{}
 
 	commonStore *persistent.CommonStore
 
+	signerFactory signature.SignerFactory
+
 	NodeController  controlAPI.NodeController
 	DebugController controlAPI.DebugController
 
@@ -124,6 +137,8 @@ type Node struct {
 	BeaconWorker       *workerBeacon.Worker
 	readyCh            chan struct{}
 
+	profiler *profiler
+
 	logger *logging.Logger
 }
 
@@ -151,6 +166,27 @@ func (n *Node) Wait() {
 	n.svcMgr.Wait()
 }
 
+func (n *Node) reloadOnSignal() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-n.svcMgr.Ctx.Done():
+			return
+		case <-sigCh:
+		}
+
+		n.logger.Info("configuration reload requested via SIGHUP")
+		if err := n.ReloadConfig(n.svcMgr.Ctx); err != nil {
+			n.logger.Error("failed to reload configuration",
+				"err", err,
+			)
+		}
+	}
+}
+
 func (n *Node) waitReady() {
 	if n.NodeController == nil {
 		n.logger.Error("failed while waiting for node: node controller not initialized")
@@ -236,12 +272,16 @@ func (n *Node) startRuntimeServices() error {
 		n.Consensus,
 		n.RuntimeRegistry,
 		n.P2P,
+		n.localTxPool(),
 	)
 	if err != nil {
 		return err
 	}
 	n.svcMgr.Register(n.RuntimeClient)
 	runtimeClientAPI.RegisterService(n.grpcInternal.Server(), n.RuntimeClient)
+	if n.CommonWorker.GetConfig().ClientRuntimeClient {
+		runtimeClientAPI.RegisterService(n.CommonWorker.Grpc.Server(), n.RuntimeClient)
+	}
 
 	// Start workers (requires NodeController for checking, if nodes are synced).
 	if err = n.startRuntimeWorkers(); err != nil {
@@ -256,6 +296,14 @@ func (n *Node) startRuntimeServices() error {
 	return nil
 }
 
+// localTxPool returns the transaction pool of runtimes executed by the node (if any).
+func (n *Node) localTxPool() runtimeClient.LocalTxPool {
+	if n.ExecutorWorker == nil || !n.ExecutorWorker.Enabled() {
+		return nil
+	}
+	return n.ExecutorWorker
+}
+
 func (n *Node) initRuntimeWorkers() error {
 	dataDir := cmdCommon.DataDir()
 
@@ -277,11 +325,14 @@ func (n *Node) initRuntimeWorkers() error {
 		if genesisDoc.Registry.Parameters.DebugAllowUnroutableAddresses {
 			p2p.DebugForceAllowUnroutableAddresses()
 		}
-		n.P2P, err = p2p.New(p2pCtx, n.Identity, n.Consensus)
+		n.P2P, err = p2p.New(p2pCtx, n.Identity, n.Consensus, n.commonStore)
 		if err != nil {
 			return err
 		}
 		n.svcMgr.RegisterCleanupOnly(p2pSvc, "worker p2p")
+		// Must be registered after the context cleanup so that the peerstore is persisted
+		// before the common store is closed.
+		n.svcMgr.RegisterCleanupOnly(n.P2P, "worker p2p peerstore")
 	}
 
 	// Initialize the IAS proxy client.
@@ -333,7 +384,7 @@ func (n *Node) initRuntimeWorkers() error {
 		n.Identity,
 		n.Consensus,
 		n.P2P,
-		&workerCommonCfg,
+		workerCommonCfg,
 		n.commonStore,
 		n, // the delegate to be called on registration shutdown
 		n.RuntimeRegistry,
@@ -536,9 +587,10 @@ func NewNode() (node *Node, err error) { // nolint: gocyclo
 	logger := cmdCommon.Logger()
 
 	node = &Node{
-		svcMgr:  background.NewServiceManager(logger),
-		readyCh: make(chan struct{}),
-		logger:  logger,
+		svcMgr:   background.NewServiceManager(logger),
+		readyCh:  make(chan struct{}),
+		profiler: newProfiler(),
+		logger:   logger,
 	}
 
 	var startOk bool
@@ -572,7 +624,12 @@ func NewNode() (node *Node, err error) { // nolint: gocyclo
 	}
 
 	// Load configured values for all registered crash points.
-	crash.LoadViperArgValues()
+	if err = crash.LoadViperArgValues(); err != nil {
+		logger.Error("failed to configure crash points",
+			"err", err,
+		)
+		return nil, err
+	}
 
 	// Open the common node store.
 	node.commonStore, err = persistent.NewCommonStore(dataDir)
@@ -583,6 +640,42 @@ func NewNode() (node *Node, err error) { // nolint: gocyclo
 		return nil, err
 	}
 
+	// Initialize the metrics server. This is done before performing any startup upgrades so that
+	// the progress of long upgrades can be monitored.
+	metrics, err := metrics.New(node.svcMgr.Ctx)
+	if err != nil {
+		logger.Error("failed to initialize metrics server",
+			"err", err,
+		)
+		return nil, err
+	}
+	node.svcMgr.Register(metrics)
+
+	// Start the metrics reporting server.
+	if err = metrics.Start(); err != nil {
+		logger.Error("failed to start metrics reporting server",
+			"err", err,
+		)
+		return nil, err
+	}
+
+	// Initialize and start the tracing service.
+	tracing, err := tracing.New()
+	if err != nil {
+		logger.Error("failed to initialize tracing",
+			"err", err,
+		)
+		return nil, err
+	}
+	node.svcMgr.Register(tracing)
+
+	if err = tracing.Start(); err != nil {
+		logger.Error("failed to start tracing",
+			"err", err,
+		)
+		return nil, err
+	}
+
 	// Initialize upgrader backend and check if we can even launch.
 	node.Upgrader, err = upgrade.New(node.commonStore, cmdCommon.DataDir())
 	if err != nil {
@@ -598,15 +691,34 @@ func NewNode() (node *Node, err error) { // nolint: gocyclo
 		return nil, err
 	}
 
+	// Initialize and start the upgrade binary fetcher.
+	binaryFetcher, err := upgradeFetcher.New(dataDir, node.Upgrader)
+	if err != nil {
+		logger.Error("failed to initialize upgrade binary fetcher",
+			"err", err,
+		)
+		return nil, err
+	}
+	node.svcMgr.Register(binaryFetcher)
+	if err = binaryFetcher.Start(); err != nil {
+		logger.Error("failed to start upgrade binary fetcher",
+			"err", err,
+		)
+		return nil, err
+	}
+
 	// Generate/Load the node identity.
-	signerFactory, err := cmdSigner.NewFactory(cmdSigner.Backend(), dataDir, identity.RequiredSignerRoles...)
+	node.signerFactory, err = cmdSigner.NewFactory(cmdSigner.Backend(), dataDir, identity.RequiredSignerRoles...)
 	if err != nil {
 		logger.Error("failed to initialize signer backend",
 			"err", err,
 		)
 		return nil, err
 	}
-	node.Identity, err = identity.LoadOrGenerate(dataDir, signerFactory, false)
+	if cleanup, ok := node.signerFactory.(service.CleanupAble); ok {
+		node.svcMgr.RegisterCleanupOnly(cleanup, "signer backend")
+	}
+	node.Identity, err = identity.LoadOrGenerate(dataDir, node.signerFactory, false)
 	if err != nil {
 		logger.Error("failed to load/generate identity",
 			"err", err,
@@ -631,37 +743,35 @@ func NewNode() (node *Node, err error) { // nolint: gocyclo
 	}
 	node.svcMgr.Register(node.grpcInternal)
 
-	// Initialize the metrics server.
-	metrics, err := metrics.New(node.svcMgr.Ctx)
+	// Initialize the profiling server.
+	profiling, err := pprof.New(node.svcMgr.Ctx)
 	if err != nil {
-		logger.Error("failed to initialize metrics server",
+		logger.Error("failed to initialize pprof server",
 			"err", err,
 		)
 		return nil, err
 	}
-	node.svcMgr.Register(metrics)
+	node.svcMgr.Register(profiling)
 
-	// Start the metrics reporting server.
-	if err = metrics.Start(); err != nil {
-		logger.Error("failed to start metrics reporting server",
+	// Start the profiling server.
+	if err = profiling.Start(); err != nil {
+		logger.Error("failed to start pprof server",
 			"err", err,
 		)
 		return nil, err
 	}
 
-	// Initialize the profiling server.
-	profiling, err := pprof.New(node.svcMgr.Ctx)
+	// Initialize and start the attestation collateral caching proxy.
+	collateralProxy, err := pccs.New(node.svcMgr.Ctx)
 	if err != nil {
-		logger.Error("failed to initialize pprof server",
+		logger.Error("failed to initialize attestation collateral caching proxy",
 			"err", err,
 		)
 		return nil, err
 	}
-	node.svcMgr.Register(profiling)
-
-	// Start the profiling server.
-	if err = profiling.Start(); err != nil {
-		logger.Error("failed to start pprof server",
+	node.svcMgr.Register(collateralProxy)
+	if err = collateralProxy.Start(); err != nil {
+		logger.Error("failed to start attestation collateral caching proxy",
 			"err", err,
 		)
 		return nil, err
@@ -692,6 +802,22 @@ func NewNode() (node *Node, err error) { // nolint: gocyclo
 	node.NodeController = control.New(node, node.Consensus, node.Upgrader)
 	controlAPI.RegisterService(node.grpcInternal.Server(), node.NodeController)
 
+	// Initialize and start the health server.
+	healthSrv, err := health.New(node.svcMgr.Ctx, node.NodeController)
+	if err != nil {
+		logger.Error("failed to initialize health server",
+			"err", err,
+		)
+		return nil, err
+	}
+	node.svcMgr.Register(healthSrv)
+	if err = healthSrv.Start(); err != nil {
+		logger.Error("failed to start health server",
+			"err", err,
+		)
+		return nil, err
+	}
+
 	// If the consensus backend supports communicating with consensus services, we can also start
 	// all services required for runtime operation.
 	if node.Consensus.SupportedFeatures().Has(consensusAPI.FeatureServices) {
@@ -717,6 +843,24 @@ func NewNode() (node *Node, err error) { // nolint: gocyclo
 		return nil, err
 	}
 
+	// Initialize and start the external gRPC API server.
+	grpcExternal, err := cmdGrpc.NewServerExternal(node.Identity)
+	if err != nil {
+		logger.Error("failed to initialize external gRPC API server",
+			"err", err,
+		)
+		return nil, err
+	}
+	if grpcExternal != nil {
+		node.svcMgr.Register(grpcExternal)
+		if err = grpcExternal.Start(); err != nil {
+			logger.Error("failed to start external gRPC API server",
+				"err", err,
+			)
+			return nil, err
+		}
+	}
+
 	// Start the consensus backend service.
 	if err = node.Consensus.Start(); err != nil {
 		logger.Error("failed to start consensus backend service",
@@ -725,6 +869,9 @@ func NewNode() (node *Node, err error) { // nolint: gocyclo
 		return nil, err
 	}
 
+	// Reload the configuration on SIGHUP.
+	go node.reloadOnSignal()
+
 	logger.Info("initialization complete: ready to serve")
 	startOk = true
 
@@ -737,23 +884,38 @@ func Register(parentCmd *cobra.Command) {
 	unsafeResetCmd.Flags().AddFlagSet(flags.DryRunFlag)
 	unsafeResetCmd.Flags().AddFlagSet(unsafeResetFlags)
 
+	upgradeSignBinaryCmd.Flags().AddFlagSet(cmdSigner.Flags)
+	upgradeSignBinaryCmd.Flags().AddFlagSet(cmdSigner.CLIFlags)
+	upgradeSignBinaryCmd.Flags().AddFlagSet(flags.DebugTestEntityFlags)
+
+	upgradeCmd.AddCommand(upgradeRollbackCmd)
+	upgradeCmd.AddCommand(upgradeSignBinaryCmd)
+
 	parentCmd.AddCommand(unsafeResetCmd)
+	parentCmd.AddCommand(upgradeCmd)
 }
 
 func init() {
 	Flags.AddFlagSet(flags.DebugTestEntityFlags)
 	Flags.AddFlagSet(flags.ConsensusValidatorFlag)
 	Flags.AddFlagSet(flags.GenesisFileFlags)
+	Flags.AddFlagSet(flags.GenesisExpectedHashFlags)
 
 	// Backend initialization flags.
 	for _, v := range []*flag.FlagSet{
 		metrics.Flags,
 		cmdGrpc.ServerLocalFlags,
+		cmdGrpc.ServerExternalFlags,
 		cmdSigner.Flags,
 		pprof.Flags,
+		tracing.Flags,
+		health.Flags,
+		upgrade.Flags,
+		upgradeFetcher.Flags,
 		tendermint.Flags,
 		seed.Flags,
 		ias.Flags,
+		pccs.Flags,
 		workerKeymanager.Flags,
 		runtimeRegistry.Flags,
 		compute.Flags,
@@ -0,0 +1,142 @@
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"sync"
+
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+)
+
+// maxProfileSize is the maximum size of a collected CPU profile or execution trace.
+//
+// Collected profiles are buffered in memory until they are retrieved and are then returned in a
+// single gRPC message, so the size must stay below the maximum gRPC message size (100 MiB) with
+// enough headroom for the message framing. Within that bound it is chosen to limit the memory the
+// node uses while profiling: a CPU profile of a busy node grows by well under 1 MiB per minute,
+// while an execution trace grows by a few MiB per second, so 64 MiB is enough for tracing several
+// seconds of activity, which is what traces are typically collected for. Collections exceeding
+// the limit are discarded instead of being returned truncated.
+const maxProfileSize = 64 * 1024 * 1024
+
+// profileBuffer is a buffer for a profile being collected, which discards all writes once the
+// profile exceeds maxProfileSize.
+type profileBuffer struct {
+	bytes.Buffer
+
+	truncated bool
+}
+
+func (b *profileBuffer) Write(p []byte) (int, error) {
+	if b.truncated || b.Len()+len(p) > maxProfileSize {
+		b.truncated = true
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+// profileCollection is a profile that is collected between being enabled and disabled.
+type profileCollection struct {
+	start func(io.Writer) error
+	stop  func()
+
+	active bool
+	buf    *profileBuffer
+}
+
+// profiler controls the collection of profiles on a running node.
+type profiler struct {
+	sync.Mutex
+
+	collections map[string]*profileCollection
+}
+
+func (p *profiler) setProfiling(req *control.SetProfilingRequest) error {
+	p.Lock()
+	defer p.Unlock()
+
+	rate := 1
+	if req.Rate > 0 {
+		rate = req.Rate
+	}
+	if !req.Enabled {
+		rate = 0
+	}
+
+	switch req.Profile {
+	case control.ProfileBlock:
+		runtime.SetBlockProfileRate(rate)
+		return nil
+	case control.ProfileMutex:
+		runtime.SetMutexProfileFraction(rate)
+		return nil
+	}
+
+	c, ok := p.collections[req.Profile]
+	if !ok {
+		return control.ErrUnknownProfile
+	}
+	switch {
+	case req.Enabled && c.active:
+		return control.ErrProfilingInProgress
+	case req.Enabled:
+		buf := &profileBuffer{}
+		if err := c.start(buf); err != nil {
+			return fmt.Errorf("failed to start collecting %s profile: %w", req.Profile, err)
+		}
+		c.active = true
+		c.buf = buf
+	case c.active:
+		// Stopping only returns once all of the profile has been written.
+		c.stop()
+		c.active = false
+	}
+	return nil
+}
+
+func (p *profiler) getProfile(req *control.GetProfileRequest) ([]byte, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	if c, ok := p.collections[req.Profile]; ok {
+		switch {
+		case c.active:
+			return nil, control.ErrProfilingInProgress
+		case c.buf == nil:
+			return nil, control.ErrProfileNotAvailable
+		case c.buf.truncated:
+			return nil, fmt.Errorf("%w: profile exceeded %d bytes", control.ErrProfileNotAvailable, maxProfileSize)
+		default:
+			return c.buf.Bytes(), nil
+		}
+	}
+
+	prof := pprof.Lookup(req.Profile)
+	if prof == nil {
+		return nil, control.ErrUnknownProfile
+	}
+	var buf bytes.Buffer
+	if err := prof.WriteTo(&buf, 0); err != nil {
+		return nil, fmt.Errorf("failed to write %s profile: %w", req.Profile, err)
+	}
+	return buf.Bytes(), nil
+}
+
+func newProfiler() *profiler {
+	return &profiler{
+		collections: map[string]*profileCollection{
+			control.ProfileCPU: {
+				start: pprof.StartCPUProfile,
+				stop:  pprof.StopCPUProfile,
+			},
+			control.ProfileTrace: {
+				start: trace.Start,
+				stop:  trace.Stop,
+			},
+		},
+	}
+}
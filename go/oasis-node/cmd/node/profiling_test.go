@@ -0,0 +1,67 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+)
+
+func TestProfiler(t *testing.T) {
+	require := require.New(t)
+
+	p := newProfiler()
+
+	for _, profile := range []string{control.ProfileCPU, control.ProfileTrace} {
+		_, err := p.getProfile(&control.GetProfileRequest{Profile: profile})
+		require.ErrorIs(err, control.ErrProfileNotAvailable, "profile should not be available before collection")
+
+		err = p.setProfiling(&control.SetProfilingRequest{Profile: profile, Enabled: true})
+		require.NoError(err, "setProfiling(enabled)")
+		err = p.setProfiling(&control.SetProfilingRequest{Profile: profile, Enabled: true})
+		require.ErrorIs(err, control.ErrProfilingInProgress, "profile collection should not be started twice")
+		_, err = p.getProfile(&control.GetProfileRequest{Profile: profile})
+		require.ErrorIs(err, control.ErrProfilingInProgress, "profile should not be available during collection")
+
+		err = p.setProfiling(&control.SetProfilingRequest{Profile: profile, Enabled: false})
+		require.NoError(err, "setProfiling(disabled)")
+		data, err := p.getProfile(&control.GetProfileRequest{Profile: profile})
+		require.NoError(err, "getProfile")
+		require.NotEmpty(data, "collected profile should not be empty")
+	}
+
+	for _, profile := range []string{control.ProfileBlock, control.ProfileMutex} {
+		err := p.setProfiling(&control.SetProfilingRequest{Profile: profile, Enabled: true, Rate: 10})
+		require.NoError(err, "setProfiling(enabled)")
+		data, err := p.getProfile(&control.GetProfileRequest{Profile: profile})
+		require.NoError(err, "getProfile")
+		require.NotEmpty(data, "profile should not be empty")
+		err = p.setProfiling(&control.SetProfilingRequest{Profile: profile, Enabled: false})
+		require.NoError(err, "setProfiling(disabled)")
+	}
+
+	data, err := p.getProfile(&control.GetProfileRequest{Profile: "goroutine"})
+	require.NoError(err, "getProfile(goroutine)")
+	require.NotEmpty(data, "goroutine profile should not be empty")
+
+	err = p.setProfiling(&control.SetProfilingRequest{Profile: "goroutine", Enabled: true})
+	require.ErrorIs(err, control.ErrUnknownProfile, "setProfiling should fail for profiles without collection")
+	_, err = p.getProfile(&control.GetProfileRequest{Profile: "unknown"})
+	require.ErrorIs(err, control.ErrUnknownProfile, "getProfile should fail for unknown profiles")
+}
+
+func TestProfileBuffer(t *testing.T) {
+	require := require.New(t)
+
+	var buf profileBuffer
+	_, err := buf.Write(make([]byte, maxProfileSize))
+	require.NoError(err, "Write")
+	require.False(buf.truncated, "buffer should not be truncated at the maximum size")
+
+	n, err := buf.Write([]byte{0})
+	require.NoError(err, "Write")
+	require.Equal(1, n, "writes beyond the maximum size should be discarded silently")
+	require.True(buf.truncated, "buffer should be truncated beyond the maximum size")
+	require.Equal(maxProfileSize, buf.Len(), "writes beyond the maximum size should be discarded")
+}
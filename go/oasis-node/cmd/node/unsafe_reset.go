@@ -14,6 +14,7 @@ import (
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	"github.com/oasisprotocol/oasis-core/go/runtime/history"
 	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
+	"github.com/oasisprotocol/oasis-core/go/runtime/scheduling/journal"
 )
 
 const (
@@ -41,6 +42,7 @@ var (
 		"persistent-store.*.db",
 		tendermintCommon.StateDir,
 		filepath.Join(runtimesGlob, history.DbFilename),
+		filepath.Join(runtimesGlob, journal.DbFilename),
 	}
 
 	runtimeLocalStorageGlob = filepath.Join(runtimesGlob, "worker-local-storage.*.db")
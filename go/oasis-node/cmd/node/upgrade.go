@@ -0,0 +1,132 @@
+package node
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	"github.com/oasisprotocol/oasis-core/go/upgrade"
+	upgradeFetcher "github.com/oasisprotocol/oasis-core/go/upgrade/fetcher"
+)
+
+var (
+	upgradeCmd = &cobra.Command{
+		Use:   "upgrade",
+		Short: "node upgrade maintenance",
+	}
+
+	upgradeRollbackCmd = &cobra.Command{
+		Use:   "rollback",
+		Short: "roll back a failed upgrade by restoring the pre-upgrade state snapshot",
+		Run:   doUpgradeRollback,
+	}
+
+	upgradeSignBinaryCmd = &cobra.Command{
+		Use:   "sign-binary <binary>",
+		Short: "sign an upgrade binary with the entity key",
+		Args:  cobra.ExactArgs(1),
+		Run:   doUpgradeSignBinary,
+	}
+
+	upgradeLogger = logging.GetLogger("cmd/upgrade")
+)
+
+func doUpgradeRollback(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	dataDir := cmdCommon.DataDir()
+	if dataDir == "" {
+		upgradeLogger.Error("data directory must be set")
+		os.Exit(1)
+	}
+
+	store, err := persistent.NewCommonStore(dataDir)
+	if err != nil {
+		upgradeLogger.Error("failed to open persistent store",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	descriptor, err := upgrade.Rollback(store, dataDir)
+	if err != nil {
+		upgradeLogger.Error("failed to roll back upgrade",
+			"err", err,
+		)
+		store.Close()
+		os.Exit(1)
+	}
+
+	upgradeLogger.Info("upgrade rolled back",
+		"handler", descriptor.Handler,
+		"epoch", descriptor.Epoch,
+	)
+}
+
+func doUpgradeSignBinary(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	fn := args[0]
+	f, err := os.Open(fn)
+	if err != nil {
+		upgradeLogger.Error("failed to open binary",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	b := hash.NewBuilder()
+	if _, err = io.Copy(b, f); err != nil {
+		upgradeLogger.Error("failed to read binary",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	binaryHash := b.Build()
+
+	_, signer, err := cmdCommon.LoadEntitySigner()
+	if err != nil {
+		upgradeLogger.Error("failed to load entity and its signer",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer signer.Reset()
+
+	sig, err := signature.Sign(signer, upgradeFetcher.SignatureContext, binaryHash[:])
+	if err != nil {
+		upgradeLogger.Error("failed to sign binary",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	pem, err := sig.MarshalPEM()
+	if err != nil {
+		upgradeLogger.Error("failed to encode signature",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err = os.WriteFile(fn+upgradeFetcher.SignatureSuffix, pem, 0o644); err != nil { // nolint: gosec
+		upgradeLogger.Error("failed to write signature",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Hash: %s\n", binaryHash)
+	fmt.Printf("Signer: %s\n", sig.PublicKey)
+}
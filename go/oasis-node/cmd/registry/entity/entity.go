@@ -342,6 +342,24 @@ func doList(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if cmdCommon.IsJSONOutput() {
+		var result interface{} = entities
+		if !cmdFlags.Verbose() {
+			ids := make([]signature.PublicKey, 0, len(entities))
+			for _, ent := range entities {
+				ids = append(ids, ent.ID)
+			}
+			result = ids
+		}
+		if err = cmdCommon.PrintJSONOutput(result); err != nil {
+			logger.Error("failed to print entities",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		return
+	}
+
 	for _, ent := range entities {
 		var entString string
 		switch cmdFlags.Verbose() {
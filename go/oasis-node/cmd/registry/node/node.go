@@ -16,7 +16,6 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
-	fileSigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/file"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
@@ -309,6 +308,24 @@ func doList(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if cmdCommon.IsJSONOutput() {
+		var result interface{} = nodes
+		if !cmdFlags.Verbose() {
+			ids := make([]signature.PublicKey, 0, len(nodes))
+			for _, node := range nodes {
+				ids = append(ids, node.ID)
+			}
+			result = ids
+		}
+		if err = cmdCommon.PrintJSONOutput(result); err != nil {
+			logger.Error("failed to print nodes",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		return
+	}
+
 	for _, node := range nodes {
 		var nodeString string
 		switch cmdFlags.Verbose() {
@@ -331,6 +348,12 @@ func doList(cmd *cobra.Command, args []string) {
 	}
 }
 
+// registrationStatus is the result of the is-registered command.
+type registrationStatus struct {
+	// Registered is true iff the node is registered.
+	Registered bool `json:"registered"`
+}
+
 func doIsRegistered(cmd *cobra.Command, args []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
@@ -345,7 +368,7 @@ func doIsRegistered(cmd *cobra.Command, args []string) {
 	}
 
 	// Load node's identity.
-	nodeSignerFactory, err := fileSigner.NewFactory(dataDir, identity.RequiredSignerRoles...)
+	nodeSignerFactory, err := cmdSigner.NewFileFactory(dataDir, identity.RequiredSignerRoles...)
 	if err != nil {
 		logger.Error("failed to create node identity signer factory",
 			"err", err,
@@ -371,14 +394,28 @@ func doIsRegistered(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	var registered bool
 	for _, node := range nodes {
 		if node.ID.Equal(nodeIdentity.NodeSigner.Public()) {
+			registered = true
+			break
+		}
+	}
+	if err = cmdCommon.PrintResult(&registrationStatus{Registered: registered}, func() {
+		if registered {
 			fmt.Println("node is registered")
-			os.Exit(0)
+		} else {
+			fmt.Println("node is not registered")
 		}
+	}); err != nil {
+		logger.Error("failed to print registration status",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if !registered {
+		os.Exit(1)
 	}
-	fmt.Println("node is not registered")
-	os.Exit(1)
 }
 
 // Register registers the node sub-command and all of it's children.
@@ -392,6 +429,7 @@ func Register(parentCmd *cobra.Command) {
 	listCmd.Flags().AddFlagSet(cmdFlags.VerboseFlags)
 
 	isRegisteredCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+	isRegisteredCmd.Flags().AddFlagSet(cmdSigner.FileFlags)
 
 	for _, subCmd := range []*cobra.Command{
 		initCmd,
@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
@@ -132,6 +133,24 @@ func doList(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if cmdCommon.IsJSONOutput() {
+		var result interface{} = runtimes
+		if !cmdFlags.Verbose() {
+			ids := make([]common.Namespace, 0, len(runtimes))
+			for _, rt := range runtimes {
+				ids = append(ids, rt.ID)
+			}
+			result = ids
+		}
+		if err = cmdCommon.PrintJSONOutput(result); err != nil {
+			logger.Error("failed to print runtimes",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		return
+	}
+
 	for _, rt := range runtimes {
 		var rtString string
 		switch cmdFlags.Verbose() {
@@ -9,14 +9,17 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/completion"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/consensus"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/control"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/doctor"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/genesis"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/governance"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/ias"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/identity"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/keymanager"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/network"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/node"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/registry"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/signer"
@@ -43,7 +46,13 @@ func Execute() {
 	// anything created by the oasis-node binary.
 	syscall.Umask(0o077)
 
-	if err := rootCmd.Execute(); err != nil {
+	args, err := cmdCommon.ExpandAliases(rootCmd, os.Args[1:])
+	if err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+	rootCmd.SetArgs(args)
+
+	if err = rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
 }
@@ -73,13 +82,16 @@ func init() {
 
 	// Register all of the sub-commands.
 	for _, v := range []func(*cobra.Command){
+		completion.Register,
 		control.Register,
 		debug.Register,
+		doctor.Register,
 		genesis.Register,
 		governance.Register,
 		ias.Register,
 		identity.Register,
 		keymanager.Register,
+		network.Register,
 		registry.Register,
 		signer.Register,
 		stake.Register,
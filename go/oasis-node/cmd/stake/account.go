@@ -11,12 +11,15 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	genesisAPI "github.com/oasisprotocol/oasis-core/go/genesis/api"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	cmdConsensus "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/consensus"
-	cmdContext "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/context"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/completion"
 	"github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/staking/api/token"
 )
 
 const (
@@ -128,6 +131,21 @@ var (
 	}
 )
 
+// accountInfo is the result of the account info command.
+type accountInfo struct {
+	Address                      api.Address                                    `json:"address"`
+	Account                      *api.Account                                   `json:"account"`
+	OutgoingDelegations          map[api.Address]*api.DelegationInfo            `json:"outgoing_delegations,omitempty"`
+	IncomingDelegations          map[api.Address]*api.Delegation                `json:"incoming_delegations,omitempty"`
+	OutgoingDebondingDelegations map[api.Address][]*api.DebondingDelegationInfo `json:"outgoing_debonding_delegations,omitempty"`
+	IncomingDebondingDelegations map[api.Address][]*api.DebondingDelegation     `json:"incoming_debonding_delegations,omitempty"`
+}
+
+// accountNonce is the result of the account nonce command.
+type accountNonce struct {
+	Nonce uint64 `json:"nonce"`
+}
+
 func doAccountInfo(cmd *cobra.Command, args []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
@@ -150,6 +168,24 @@ func doAccountInfo(cmd *cobra.Command, args []string) {
 	incomingDelegations := getDelegationsTo(ctx, cmd, addr, client)
 	outgoingDebondingDelegationInfos := getDebondingDelegationInfosFor(ctx, cmd, addr, client)
 	incomingDebondingDelegations := getDebondingDelegationsTo(ctx, cmd, addr, client)
+
+	if cmdCommon.IsJSONOutput() {
+		if err := cmdCommon.PrintJSONOutput(&accountInfo{
+			Address:                      addr,
+			Account:                      acct,
+			OutgoingDelegations:          outgoingDelegationInfos,
+			IncomingDelegations:          incomingDelegations,
+			OutgoingDebondingDelegations: outgoingDebondingDelegationInfos,
+			IncomingDebondingDelegations: incomingDebondingDelegations,
+		}); err != nil {
+			logger.Error("failed to print account info",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		return
+	}
+
 	symbol := getTokenSymbol(ctx, cmd, client)
 	exp := getTokenValueExponent(ctx, cmd, client)
 	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenSymbol, symbol)
@@ -212,7 +248,14 @@ func doAccountNonce(cmd *cobra.Command, args []string) {
 
 	ctx := context.Background()
 	acct := getAccount(ctx, cmd, addr, client)
-	fmt.Println(acct.General.Nonce)
+	if err := cmdCommon.PrintResult(&accountNonce{Nonce: acct.General.Nonce}, func() {
+		fmt.Println(acct.General.Nonce)
+	}); err != nil {
+		logger.Error("failed to print account nonce",
+			"err", err,
+		)
+		os.Exit(1)
+	}
 }
 
 func doValidateAddress(cmd *cobra.Command, args []string) {
@@ -253,7 +296,7 @@ func doAccountTransfer(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(1)
 	}
-	if err := xfer.Amount.UnmarshalText([]byte(viper.GetString(CfgAmount))); err != nil {
+	if err := parseAmount(genesis, viper.GetString(CfgAmount), &xfer.Amount); err != nil {
 		logger.Error("failed to parse transfer amount",
 			"err", err,
 		)
@@ -263,7 +306,7 @@ func doAccountTransfer(cmd *cobra.Command, args []string) {
 	nonce, fee := cmdConsensus.GetTxNonceAndFee()
 	tx := api.NewTransferTx(nonce, fee, &xfer)
 
-	cmdConsensus.SignAndSaveTx(cmdContext.GetCtxWithGenesisInfo(genesis), tx, nil)
+	signAndSaveTx(cmd, genesis, tx)
 }
 
 func doAccountBurn(cmd *cobra.Command, args []string) {
@@ -275,7 +318,7 @@ func doAccountBurn(cmd *cobra.Command, args []string) {
 	cmdConsensus.AssertTxFileOK()
 
 	var burn api.Burn
-	if err := burn.Amount.UnmarshalText([]byte(viper.GetString(CfgAmount))); err != nil {
+	if err := parseAmount(genesis, viper.GetString(CfgAmount), &burn.Amount); err != nil {
 		logger.Error("failed to parse burn amount",
 			"err", err,
 		)
@@ -285,7 +328,7 @@ func doAccountBurn(cmd *cobra.Command, args []string) {
 	nonce, fee := cmdConsensus.GetTxNonceAndFee()
 	tx := api.NewBurnTx(nonce, fee, &burn)
 
-	cmdConsensus.SignAndSaveTx(cmdContext.GetCtxWithGenesisInfo(genesis), tx, nil)
+	signAndSaveTx(cmd, genesis, tx)
 }
 
 func doAccountEscrow(cmd *cobra.Command, args []string) {
@@ -303,7 +346,7 @@ func doAccountEscrow(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(1)
 	}
-	if err := escrow.Amount.UnmarshalText([]byte(viper.GetString(CfgAmount))); err != nil {
+	if err := parseAmount(genesis, viper.GetString(CfgAmount), &escrow.Amount); err != nil {
 		logger.Error("failed to parse escrow amount",
 			"err", err,
 		)
@@ -313,7 +356,7 @@ func doAccountEscrow(cmd *cobra.Command, args []string) {
 	nonce, fee := cmdConsensus.GetTxNonceAndFee()
 	tx := api.NewAddEscrowTx(nonce, fee, &escrow)
 
-	cmdConsensus.SignAndSaveTx(cmdContext.GetCtxWithGenesisInfo(genesis), tx, nil)
+	signAndSaveTx(cmd, genesis, tx)
 }
 
 func doAccountReclaimEscrow(cmd *cobra.Command, args []string) {
@@ -341,7 +384,7 @@ func doAccountReclaimEscrow(cmd *cobra.Command, args []string) {
 	nonce, fee := cmdConsensus.GetTxNonceAndFee()
 	tx := api.NewReclaimEscrowTx(nonce, fee, &reclaim)
 
-	cmdConsensus.SignAndSaveTx(cmdContext.GetCtxWithGenesisInfo(genesis), tx, nil)
+	signAndSaveTx(cmd, genesis, tx)
 }
 
 func scanRateStep(dst *api.CommissionRateStep, raw string) error {
@@ -419,7 +462,7 @@ func doAccountAmendCommissionSchedule(cmd *cobra.Command, args []string) {
 	nonce, fee := cmdConsensus.GetTxNonceAndFee()
 	tx := api.NewAmendCommissionScheduleTx(nonce, fee, &amendCommissionSchedule)
 
-	cmdConsensus.SignAndSaveTx(cmdContext.GetCtxWithGenesisInfo(genesis), tx, nil)
+	signAndSaveTx(cmd, genesis, tx)
 }
 
 func doAccountAllow(cmd *cobra.Command, args []string) {
@@ -446,7 +489,7 @@ func doAccountAllow(cmd *cobra.Command, args []string) {
 		allow.Negative = true
 		amountRaw = amountRaw[1:]
 	}
-	if err := allow.AmountChange.UnmarshalText([]byte(amountRaw)); err != nil {
+	if err := parseAmount(genesis, amountRaw, &allow.AmountChange); err != nil {
 		logger.Error("failed to parse allowance change amount",
 			"err", err,
 		)
@@ -456,7 +499,7 @@ func doAccountAllow(cmd *cobra.Command, args []string) {
 	nonce, fee := cmdConsensus.GetTxNonceAndFee()
 	tx := api.NewAllowTx(nonce, fee, &allow)
 
-	cmdConsensus.SignAndSaveTx(cmdContext.GetCtxWithGenesisInfo(genesis), tx, nil)
+	signAndSaveTx(cmd, genesis, tx)
 }
 
 func doAccountWithdraw(cmd *cobra.Command, args []string) {
@@ -474,7 +517,7 @@ func doAccountWithdraw(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(1)
 	}
-	if err := withdraw.Amount.UnmarshalText([]byte(viper.GetString(CfgAmount))); err != nil {
+	if err := parseAmount(genesis, viper.GetString(CfgAmount), &withdraw.Amount); err != nil {
 		logger.Error("failed to parse withdraw amount",
 			"err", err,
 		)
@@ -484,7 +527,18 @@ func doAccountWithdraw(cmd *cobra.Command, args []string) {
 	nonce, fee := cmdConsensus.GetTxNonceAndFee()
 	tx := api.NewWithdrawTx(nonce, fee, &withdraw)
 
-	cmdConsensus.SignAndSaveTx(cmdContext.GetCtxWithGenesisInfo(genesis), tx, nil)
+	signAndSaveTx(cmd, genesis, tx)
+}
+
+// parseAmount parses an amount given either in base units or in tokens followed by the token's
+// ticker symbol, as configured in the genesis document.
+func parseAmount(genesis *genesisAPI.Document, raw string, amount *quantity.Quantity) error {
+	parsed, err := token.ParseAmount(raw, genesis.Staking.TokenSymbol, genesis.Staking.TokenValueExponent)
+	if err != nil {
+		return err
+	}
+	*amount = *parsed
+	return nil
 }
 
 func registerAccountCmd() {
@@ -499,6 +553,7 @@ func registerAccountCmd() {
 		accountAmendCommissionScheduleCmd,
 		accountAllowCmd,
 		accountWithdrawCmd,
+		accountHistoryCmd,
 	} {
 		accountCmd.AddCommand(v)
 	}
@@ -516,6 +571,26 @@ func registerAccountCmd() {
 	accountAmendCommissionScheduleCmd.Flags().AddFlagSet(commissionScheduleFlags)
 	accountAllowCmd.Flags().AddFlagSet(accountAllowFlags)
 	accountWithdrawCmd.Flags().AddFlagSet(accountWithdrawFlags)
+	accountHistoryCmd.Flags().AddFlagSet(accountHistoryFlags)
+
+	accountHistoryCmd.ValidArgsFunction = completion.FirstArg(completion.AccountAddresses)
+	completion.RegisterFlag(accountInfoCmd, CfgAccountAddr, completion.AccountAddresses)
+	completion.RegisterFlag(accountTransferCmd, CfgTransferDestination, completion.AccountAddresses)
+	completion.RegisterFlag(accountEscrowCmd, CfgEscrowAccount, completion.AccountAddresses)
+	completion.RegisterFlag(accountAllowCmd, CfgAllowBeneficiary, completion.AccountAddresses)
+	completion.RegisterFlag(accountWithdrawCmd, CfgWithdrawSource, completion.AccountAddresses)
+
+	for _, v := range []*cobra.Command{
+		accountTransferCmd,
+		accountBurnCmd,
+		accountEscrowCmd,
+		accountReclaimEscrowCmd,
+		accountAmendCommissionScheduleCmd,
+		accountAllowCmd,
+		accountWithdrawCmd,
+	} {
+		v.Flags().AddFlagSet(interactiveFlags)
+	}
 }
 
 func init() {
@@ -523,7 +598,7 @@ func init() {
 	_ = viper.BindPFlags(commonAccountFlags)
 	commonAccountFlags.AddFlagSet(cmdGrpc.ClientFlags)
 
-	amountFlags.String(CfgAmount, "0", "amount of stake (in base units) for the transaction")
+	amountFlags.String(CfgAmount, "0", "amount of stake for the transaction (in base units or in tokens, e.g., '10.5 ROSE')")
 	_ = viper.BindPFlags(amountFlags)
 
 	sharesFlags.String(CfgShares, "0", "amount of shares for the transaction")
@@ -560,7 +635,7 @@ func init() {
 	commissionScheduleFlags.AddFlagSet(cmdFlags.AssumeYesFlag)
 
 	accountAllowFlags.String(CfgAllowBeneficiary, "", "allowance beneficiary address")
-	accountAllowFlags.String(CfgAllowAmountChange, "0", "allowance change amount (in base units)")
+	accountAllowFlags.String(CfgAllowAmountChange, "0", "allowance change amount (in base units or in tokens, e.g., '10.5 ROSE')")
 	_ = viper.BindPFlags(accountAllowFlags)
 	accountAllowFlags.AddFlagSet(cmdConsensus.TxFlags)
 	accountAllowFlags.AddFlagSet(cmdFlags.AssumeYesFlag)
@@ -0,0 +1,282 @@
+package stake
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/staking/api/token"
+)
+
+const (
+	// CfgHistoryFromHeight configures the first height of the account history.
+	CfgHistoryFromHeight = "stake.history.from_height"
+
+	// CfgHistoryToHeight configures the last height of the account history.
+	CfgHistoryToHeight = "stake.history.to_height"
+
+	// CfgHistoryCSV enables the CSV output of the account history.
+	CfgHistoryCSV = "stake.history.csv"
+
+	// defaultHistoryHeights is the number of heights examined when no first height is given.
+	defaultHistoryHeights = 1000
+
+	// maxHistoryHeights is the maximum number of heights examined by a single command invocation.
+	maxHistoryHeights = 10000
+
+	// historyKindReward is the kind of reward history entries.
+	historyKindReward = "reward"
+)
+
+var (
+	accountHistoryFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	accountHistoryCmd = &cobra.Command{
+		Use:   "history <address>",
+		Short: "show transfers, escrow operations and rewards affecting an account",
+		Args:  cobra.ExactArgs(1),
+		Run:   doAccountHistory,
+	}
+)
+
+// historyEntry is a single staking event affecting an account.
+type historyEntry struct {
+	// Height is the consensus height of the event.
+	Height int64 `json:"height"`
+	// TxHash is the hash of the transaction that caused the event (if any).
+	TxHash hash.Hash `json:"tx_hash"`
+	// Kind is the kind of the event.
+	Kind string `json:"kind"`
+	// From is the account the stake moved from (if any).
+	From *api.Address `json:"from,omitempty"`
+	// To is the account the stake moved to (if any).
+	To *api.Address `json:"to,omitempty"`
+	// Amount is the amount of stake.
+	Amount quantity.Quantity `json:"amount"`
+}
+
+// accountHistoryEntries returns the history entries for the given staking event, if it affects
+// the given account.
+func accountHistoryEntries(addr api.Address, ev *api.Event) []*historyEntry {
+	entry := &historyEntry{
+		Height: ev.Height,
+		TxHash: ev.TxHash,
+	}
+	switch {
+	case ev.Transfer != nil:
+		entry.Kind = ev.Transfer.EventKind()
+		entry.From = &ev.Transfer.From
+		entry.To = &ev.Transfer.To
+		entry.Amount = ev.Transfer.Amount
+	case ev.Burn != nil:
+		entry.Kind = ev.Burn.EventKind()
+		entry.From = &ev.Burn.Owner
+		entry.Amount = ev.Burn.Amount
+	case ev.Escrow != nil && ev.Escrow.Add != nil:
+		entry.Kind = ev.Escrow.Add.EventKind()
+		if ev.Escrow.Add.Owner.Equal(api.CommonPoolAddress) {
+			entry.Kind = historyKindReward
+		}
+		entry.From = &ev.Escrow.Add.Owner
+		entry.To = &ev.Escrow.Add.Escrow
+		entry.Amount = ev.Escrow.Add.Amount
+	case ev.Escrow != nil && ev.Escrow.Take != nil:
+		entry.Kind = ev.Escrow.Take.EventKind()
+		entry.From = &ev.Escrow.Take.Owner
+		entry.Amount = ev.Escrow.Take.Amount
+	case ev.Escrow != nil && ev.Escrow.DebondingStart != nil:
+		entry.Kind = ev.Escrow.DebondingStart.EventKind()
+		entry.From = &ev.Escrow.DebondingStart.Escrow
+		entry.To = &ev.Escrow.DebondingStart.Owner
+		entry.Amount = ev.Escrow.DebondingStart.Amount
+	case ev.Escrow != nil && ev.Escrow.Reclaim != nil:
+		entry.Kind = ev.Escrow.Reclaim.EventKind()
+		entry.From = &ev.Escrow.Reclaim.Escrow
+		entry.To = &ev.Escrow.Reclaim.Owner
+		entry.Amount = ev.Escrow.Reclaim.Amount
+	default:
+		return nil
+	}
+
+	if (entry.From != nil && entry.From.Equal(addr)) || (entry.To != nil && entry.To.Equal(addr)) {
+		return []*historyEntry{entry}
+	}
+	return nil
+}
+
+// collectAccountHistory collects the history entries affecting the given account in the given
+// (inclusive) height range.
+//
+// As there is no index of staking events by account, this performs a linear scan requesting the
+// events of each height in the range, which is why the range is bounded by maxHistoryHeights.
+func collectAccountHistory(ctx context.Context, client api.Backend, addr api.Address, fromHeight, toHeight int64) ([]*historyEntry, error) {
+	var entries []*historyEntry
+	for height := fromHeight; height <= toHeight; height++ {
+		evs, err := client.GetEvents(ctx, height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query staking events at height %d: %w", height, err)
+		}
+		for _, ev := range evs {
+			entries = append(entries, accountHistoryEntries(addr, ev)...)
+		}
+	}
+	return entries, nil
+}
+
+// historyHeightRange returns the (inclusive) height range to examine, given the configured first
+// and last heights (either of which may be unset).
+//
+// The range is clamped to the heights retained by the node and may span at most maxHistoryHeights
+// heights.
+func historyHeightRange(status *consensus.Status, fromHeight, toHeight int64) (int64, int64, error) {
+	if toHeight == consensus.HeightLatest {
+		toHeight = status.LatestHeight
+	}
+	if fromHeight == 0 {
+		fromHeight = toHeight - defaultHistoryHeights + 1
+	}
+	lowHeight := status.LastRetainedHeight
+	if lowHeight < status.GenesisHeight {
+		lowHeight = status.GenesisHeight
+	}
+	if fromHeight < lowHeight {
+		fromHeight = lowHeight
+	}
+	if fromHeight > toHeight || toHeight > status.LatestHeight {
+		return 0, 0, fmt.Errorf("invalid height range %d-%d (latest height: %d)", fromHeight, toHeight, status.LatestHeight)
+	}
+	if toHeight-fromHeight+1 > maxHistoryHeights {
+		return 0, 0, fmt.Errorf("height range %d-%d exceeds the maximum of %d heights", fromHeight, toHeight, maxHistoryHeights)
+	}
+	return fromHeight, toHeight, nil
+}
+
+func formatHistoryAddress(addr *api.Address) string {
+	if addr == nil {
+		return ""
+	}
+	return addr.String()
+}
+
+func printAccountHistoryCSV(entries []*historyEntry) error {
+	w := csv.NewWriter(os.Stdout)
+	if err := w.Write([]string{"height", "tx_hash", "kind", "from", "to", "amount"}); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := w.Write([]string{
+			strconv.FormatInt(e.Height, 10),
+			e.TxHash.String(),
+			e.Kind,
+			formatHistoryAddress(e.From),
+			formatHistoryAddress(e.To),
+			e.Amount.String(),
+		}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func printAccountHistory(ctx context.Context, entries []*historyEntry) {
+	if len(entries) == 0 {
+		fmt.Println("No events affecting the account.")
+		return
+	}
+	for _, e := range entries {
+		var amount bytes.Buffer
+		token.PrettyPrintAmount(ctx, e.Amount, &amount)
+
+		fmt.Printf("Height %d: %s of %s\n", e.Height, e.Kind, amount.String())
+		if e.From != nil {
+			fmt.Printf("  From:    %s\n", e.From)
+		}
+		if e.To != nil {
+			fmt.Printf("  To:      %s\n", e.To)
+		}
+		if !e.TxHash.IsEmpty() {
+			fmt.Printf("  Tx hash: %s\n", e.TxHash)
+		}
+	}
+}
+
+func doAccountHistory(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	var addr api.Address
+	if err := addr.UnmarshalText([]byte(args[0])); err != nil {
+		logger.Error("failed to parse account address",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	ctx := context.Background()
+	status, err := consensus.NewConsensusClient(conn).GetStatus(ctx)
+	if err != nil {
+		logger.Error("failed to query consensus status",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	fromHeight, toHeight, err := historyHeightRange(status, viper.GetInt64(CfgHistoryFromHeight), viper.GetInt64(CfgHistoryToHeight))
+	if err != nil {
+		logger.Error("invalid height range",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	entries, err := collectAccountHistory(ctx, client, addr, fromHeight, toHeight)
+	if err != nil {
+		logger.Error("failed to collect account history",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	if viper.GetBool(CfgHistoryCSV) {
+		err = printAccountHistoryCSV(entries)
+	} else {
+		ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenSymbol, getTokenSymbol(ctx, cmd, client))
+		ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenValueExponent, getTokenValueExponent(ctx, cmd, client))
+		err = cmdCommon.PrintResult(entries, func() {
+			printAccountHistory(ctx, entries)
+		})
+	}
+	if err != nil {
+		logger.Error("failed to print account history",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	accountHistoryFlags.Int64(CfgHistoryFromHeight, 0, fmt.Sprintf("first height to examine (default: %d heights before the last height)", defaultHistoryHeights))
+	accountHistoryFlags.Int64(CfgHistoryToHeight, consensus.HeightLatest, "last height to examine (default: latest height)")
+	accountHistoryFlags.Bool(CfgHistoryCSV, false, "output the history as CSV")
+	_ = viper.BindPFlags(accountHistoryFlags)
+	accountHistoryFlags.AddFlagSet(cmdGrpc.ClientFlags)
+}
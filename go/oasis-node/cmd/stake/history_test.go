@@ -0,0 +1,129 @@
+package stake
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// historyTestBackend is a staking backend that only serves events.
+type historyTestBackend struct {
+	api.Backend
+
+	events map[int64][]*api.Event
+}
+
+func (b *historyTestBackend) GetEvents(ctx context.Context, height int64) ([]*api.Event, error) {
+	if height > 100 {
+		return nil, fmt.Errorf("height %d not available", height)
+	}
+	return b.events[height], nil
+}
+
+func TestAccountHistory(t *testing.T) {
+	require := require.New(t)
+
+	addr := api.NewAddress(signature.NewPublicKey("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"))
+	other := api.NewAddress(signature.NewPublicKey("bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"))
+	amount := *quantity.NewFromUint64(10)
+
+	backend := &historyTestBackend{
+		events: map[int64][]*api.Event{
+			10: {
+				{Height: 10, Transfer: &api.TransferEvent{From: addr, To: other, Amount: amount}},
+				// Events not affecting the account should be skipped.
+				{Height: 10, Transfer: &api.TransferEvent{From: other, To: other, Amount: amount}},
+			},
+			11: {
+				{Height: 11, Escrow: &api.EscrowEvent{Add: &api.AddEscrowEvent{Owner: api.CommonPoolAddress, Escrow: addr, Amount: amount}}},
+				{Height: 11, Escrow: &api.EscrowEvent{Add: &api.AddEscrowEvent{Owner: addr, Escrow: other, Amount: amount}}},
+			},
+			12: {
+				{Height: 12, Burn: &api.BurnEvent{Owner: addr, Amount: amount}},
+				{Height: 12, Escrow: &api.EscrowEvent{Reclaim: &api.ReclaimEscrowEvent{Owner: addr, Escrow: other, Amount: amount}}},
+			},
+			// Events outside of the requested range should be ignored.
+			20: {
+				{Height: 20, Burn: &api.BurnEvent{Owner: addr, Amount: amount}},
+			},
+		},
+	}
+
+	entries, err := collectAccountHistory(context.Background(), backend, addr, 10, 12)
+	require.NoError(err, "collectAccountHistory")
+	require.Len(entries, 5)
+
+	var kinds []string
+	for _, e := range entries {
+		kinds = append(kinds, e.Kind)
+		require.Equal(amount, e.Amount)
+	}
+	require.Equal([]string{
+		(&api.TransferEvent{}).EventKind(),
+		historyKindReward,
+		(&api.AddEscrowEvent{}).EventKind(),
+		(&api.BurnEvent{}).EventKind(),
+		(&api.ReclaimEscrowEvent{}).EventKind(),
+	}, kinds)
+	require.EqualValues(addr, *entries[4].To, "reclaimed stake should move to the owner")
+
+	_, err = collectAccountHistory(context.Background(), backend, addr, 99, 101)
+	require.Error(err, "collectAccountHistory should fail for unavailable heights")
+}
+
+func TestHistoryHeightRange(t *testing.T) {
+	require := require.New(t)
+
+	status := &consensus.Status{
+		LatestHeight:       5000,
+		LastRetainedHeight: 3000,
+		GenesisHeight:      1,
+	}
+
+	for _, tc := range []struct {
+		from, to                 int64
+		expectedFrom, expectedTo int64
+		valid                    bool
+	}{
+		// Defaults to the last heights.
+		{0, consensus.HeightLatest, 5000 - defaultHistoryHeights + 1, 5000, true},
+		{0, 4000, 4000 - defaultHistoryHeights + 1, 4000, true},
+		{4500, consensus.HeightLatest, 4500, 5000, true},
+		// Clamped to the retained heights.
+		{1, 3500, 3000, 3500, true},
+		{0, 3100, 3000, 3100, true},
+		// Invalid ranges.
+		{4500, 4000, 0, 0, false},
+		{4500, 6000, 0, 0, false},
+		{0, 2000, 0, 0, false},
+	} {
+		from, to, err := historyHeightRange(status, tc.from, tc.to)
+		if !tc.valid {
+			require.Error(err, "historyHeightRange(%d, %d)", tc.from, tc.to)
+			continue
+		}
+		require.NoError(err, "historyHeightRange(%d, %d)", tc.from, tc.to)
+		require.Equal(tc.expectedFrom, from, "from height (%d, %d)", tc.from, tc.to)
+		require.Equal(tc.expectedTo, to, "to height (%d, %d)", tc.from, tc.to)
+	}
+
+	// Ranges are bounded.
+	status = &consensus.Status{
+		LatestHeight:       50000,
+		LastRetainedHeight: 1,
+		GenesisHeight:      1,
+	}
+	_, _, err := historyHeightRange(status, 1, consensus.HeightLatest)
+	require.Error(err, "ranges longer than the maximum should be rejected")
+	from, to, err := historyHeightRange(status, 50000-maxHistoryHeights+1, consensus.HeightLatest)
+	require.NoError(err, "historyHeightRange")
+	require.EqualValues(50000-maxHistoryHeights+1, from)
+	require.EqualValues(50000, to)
+}
@@ -0,0 +1,166 @@
+package stake
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdConsensus "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/consensus"
+	cmdContext "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/context"
+	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/staking/api/token"
+)
+
+// CfgInteractive enables the interactive transaction builder.
+const CfgInteractive = "stake.interactive"
+
+var (
+	interactiveFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	// methodGasOps maps staking transaction methods to their gas cost operations.
+	methodGasOps = map[transaction.MethodName]transaction.Op{
+		api.MethodTransfer:                api.GasOpTransfer,
+		api.MethodBurn:                    api.GasOpBurn,
+		api.MethodAddEscrow:               api.GasOpAddEscrow,
+		api.MethodReclaimEscrow:           api.GasOpReclaimEscrow,
+		api.MethodAmendCommissionSchedule: api.GasOpAmendCommissionSchedule,
+		api.MethodAllow:                   api.GasOpAllow,
+		api.MethodWithdraw:                api.GasOpWithdraw,
+	}
+)
+
+// signAndSaveTx signs and saves the given staking transaction.
+//
+// In interactive mode, the signer's account, the relevant gas costs and staking rules are first
+// queried from the node and the transaction's nonce and fee are populated unless they have been
+// explicitly configured.
+func signAndSaveTx(cmd *cobra.Command, genesis *genesis.Document, tx *transaction.Transaction) {
+	ctx := cmdContext.GetCtxWithGenesisInfo(genesis)
+	if !viper.GetBool(CfgInteractive) {
+		cmdConsensus.SignAndSaveTx(ctx, tx, nil)
+		return
+	}
+
+	_, signer, err := cmdCommon.LoadEntitySigner()
+	if err != nil {
+		logger.Error("failed to load signer",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer signer.Reset()
+
+	prepareInteractiveTx(ctx, cmd, tx, signer.Public())
+	cmdConsensus.SignAndSaveTx(ctx, tx, signer)
+}
+
+func prepareInteractiveTx(ctx context.Context, cmd *cobra.Command, tx *transaction.Transaction, signerPub signature.PublicKey) {
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+	consensusClient := consensus.NewConsensusClient(conn)
+
+	addr := api.NewAddress(signerPub)
+	acct := getAccount(ctx, cmd, addr, client)
+	params, err := client.ConsensusParameters(ctx, consensus.HeightLatest)
+	if err != nil {
+		logger.Error("failed to query staking consensus parameters",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	fmt.Println("Signer account:")
+	fmt.Printf("  Address: %s\n", addr)
+	fmt.Printf("  Balance: ")
+	token.PrettyPrintAmount(ctx, acct.General.Balance, os.Stdout)
+	fmt.Println()
+	fmt.Printf("  Nonce:   %d\n", acct.General.Nonce)
+	fmt.Println()
+
+	if op, ok := methodGasOps[tx.Method]; ok {
+		fmt.Printf("Gas cost of the %s operation: %d\n", op, params.GasCosts[op])
+		fmt.Println()
+	}
+
+	if tx.Method == api.MethodAmendCommissionSchedule {
+		rules := params.CommissionScheduleRules
+		fmt.Println("Commission schedule rules:")
+		fmt.Printf("  Rate change interval: %d epoch(s)\n", rules.RateChangeInterval)
+		fmt.Printf("  Rate bound lead:      %d epoch(s)\n", rules.RateBoundLead)
+		fmt.Printf("  Max rate steps:       %d\n", rules.MaxRateSteps)
+		fmt.Printf("  Max bound steps:      %d\n", rules.MaxBoundSteps)
+		fmt.Println()
+
+		cs := acct.Escrow.CommissionSchedule
+		if len(cs.Rates) > 0 || len(cs.Bounds) > 0 {
+			fmt.Println("Current commission schedule:")
+			cs.PrettyPrint(ctx, "  ", os.Stdout)
+			fmt.Println()
+		}
+	}
+
+	if !cmd.Flags().Changed(cmdConsensus.CfgTxNonce) {
+		tx.Nonce = acct.General.Nonce
+	}
+	if !cmd.Flags().Changed(cmdConsensus.CfgTxFeeGas) {
+		gas, err := consensusClient.EstimateGas(ctx, &consensus.EstimateGasRequest{
+			Signer:      signerPub,
+			Transaction: tx,
+		})
+		if err != nil {
+			logger.Error("failed to estimate gas",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		tx.Fee.Gas = gas
+	}
+	if !cmd.Flags().Changed(cmdConsensus.CfgTxFeeAmount) {
+		rawGasPrice := cmdCommon.GetUserInput("Gas price (in base units per gas unit)", "0")
+		var gasPrice quantity.Quantity
+		if err = gasPrice.UnmarshalText([]byte(rawGasPrice)); err != nil {
+			logger.Error("failed to parse gas price",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		amount := quantity.NewFromUint64(uint64(tx.Fee.Gas))
+		if err = amount.Mul(&gasPrice); err != nil {
+			logger.Error("failed to compute fee amount",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		tx.Fee.Amount = *amount
+	}
+
+	fmt.Printf("Estimated fee: ")
+	token.PrettyPrintAmount(ctx, tx.Fee.Amount, os.Stdout)
+	fmt.Printf(" (gas limit: %d)\n", tx.Fee.Gas)
+	if tx.Fee.Amount.Cmp(&acct.General.Balance) > 0 {
+		fmt.Println("WARNING: The fee exceeds the signer account's balance.")
+	}
+	fmt.Println()
+
+	if viper.GetBool(cmdConsensus.CfgTxUnsigned) {
+		fmt.Printf("Generated the following unsigned transaction:\n")
+		tx.PrettyPrint(ctx, "  ", os.Stdout)
+	}
+}
+
+func init() {
+	interactiveFlags.Bool(CfgInteractive, false, "query the account nonce, gas costs and staking rules from the node and estimate the fee")
+	_ = viper.BindPFlags(interactiveFlags)
+	interactiveFlags.AddFlagSet(cmdGrpc.ClientFlags)
+}
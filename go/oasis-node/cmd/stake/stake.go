@@ -15,6 +15,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
@@ -172,6 +173,17 @@ func getDebondingDelegationsTo(
 	return delegations
 }
 
+// stakingInfo is the result of the info command.
+type stakingInfo struct {
+	TokenSymbol        string                                  `json:"token_symbol"`
+	TokenValueExponent uint8                                   `json:"token_value_exponent"`
+	TotalSupply        quantity.Quantity                       `json:"total_supply"`
+	CommonPool         quantity.Quantity                       `json:"common_pool"`
+	LastBlockFees      quantity.Quantity                       `json:"last_block_fees"`
+	GovernanceDeposits quantity.Quantity                       `json:"governance_deposits"`
+	Thresholds         map[api.ThresholdKind]quantity.Quantity `json:"thresholds"`
+}
+
 func doInfo(cmd *cobra.Command, args []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
@@ -182,14 +194,13 @@ func doInfo(cmd *cobra.Command, args []string) {
 
 	ctx := context.Background()
 
-	symbol := getTokenSymbol(ctx, cmd, client)
-	fmt.Printf("Token's ticker symbol: %s\n", symbol)
-
-	exp := getTokenValueExponent(ctx, cmd, client)
-	fmt.Printf("Token's value base-10 exponent: %d\n", exp)
-
-	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenSymbol, symbol)
-	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenValueExponent, exp)
+	info := stakingInfo{
+		TokenSymbol:        getTokenSymbol(ctx, cmd, client),
+		TokenValueExponent: getTokenValueExponent(ctx, cmd, client),
+		Thresholds:         make(map[api.ThresholdKind]quantity.Quantity),
+	}
+	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenSymbol, info.TokenSymbol)
+	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenValueExponent, info.TokenValueExponent)
 
 	totalSupply, err := client.TotalSupply(ctx, consensus.HeightLatest)
 	if err != nil {
@@ -198,9 +209,7 @@ func doInfo(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(1)
 	}
-	fmt.Print("Total supply: ")
-	token.PrettyPrintAmount(ctx, *totalSupply, os.Stdout)
-	fmt.Println()
+	info.TotalSupply = *totalSupply
 
 	commonPool, err := client.CommonPool(ctx, consensus.HeightLatest)
 	if err != nil {
@@ -209,9 +218,7 @@ func doInfo(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(1)
 	}
-	fmt.Print("Common pool: ")
-	token.PrettyPrintAmount(ctx, *commonPool, os.Stdout)
-	fmt.Println()
+	info.CommonPool = *commonPool
 
 	lastBlockFees, err := client.LastBlockFees(ctx, consensus.HeightLatest)
 	if err != nil {
@@ -220,9 +227,7 @@ func doInfo(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(1)
 	}
-	fmt.Print("Last block fees: ")
-	token.PrettyPrintAmount(ctx, *lastBlockFees, os.Stdout)
-	fmt.Println()
+	info.LastBlockFees = *lastBlockFees
 
 	governanceDeposits, err := client.GovernanceDeposits(ctx, consensus.HeightLatest)
 	if err != nil {
@@ -231,9 +236,7 @@ func doInfo(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(1)
 	}
-	fmt.Print("Governance deposits: ")
-	token.PrettyPrintAmount(ctx, *governanceDeposits, os.Stdout)
-	fmt.Println()
+	info.GovernanceDeposits = *governanceDeposits
 
 	thresholdsToQuery := []api.ThresholdKind{
 		api.KindEntity,
@@ -256,9 +259,41 @@ func doInfo(cmd *cobra.Command, args []string) {
 			)
 			os.Exit(1)
 		}
-		fmt.Printf("Staking threshold (%s): ", kind)
-		token.PrettyPrintAmount(ctx, *thres, os.Stdout)
-		fmt.Println()
+		info.Thresholds[kind] = *thres
+	}
+
+	if err = cmdCommon.PrintResult(&info, func() {
+		fmt.Printf("Token's ticker symbol: %s\n", info.TokenSymbol)
+		fmt.Printf("Token's value base-10 exponent: %d\n", info.TokenValueExponent)
+
+		for _, v := range []struct {
+			name   string
+			amount quantity.Quantity
+		}{
+			{"Total supply", info.TotalSupply},
+			{"Common pool", info.CommonPool},
+			{"Last block fees", info.LastBlockFees},
+			{"Governance deposits", info.GovernanceDeposits},
+		} {
+			fmt.Printf("%s: ", v.name)
+			token.PrettyPrintAmount(ctx, v.amount, os.Stdout)
+			fmt.Println()
+		}
+
+		for _, kind := range thresholdsToQuery {
+			thres, ok := info.Thresholds[kind]
+			if !ok {
+				continue
+			}
+			fmt.Printf("Staking threshold (%s): ", kind)
+			token.PrettyPrintAmount(ctx, thres, os.Stdout)
+			fmt.Println()
+		}
+	}); err != nil {
+		logger.Error("failed to print staking info",
+			"err", err,
+		)
+		os.Exit(1)
 	}
 }
 
@@ -280,6 +315,24 @@ func doList(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if cmdCommon.IsJSONOutput() {
+		var result interface{} = addresses
+		if cmdFlags.Verbose() {
+			accounts := make(map[api.Address]*api.Account)
+			for _, addr := range addresses {
+				accounts[addr] = getAccount(ctx, cmd, addr, client)
+			}
+			result = accounts
+		}
+		if err = cmdCommon.PrintJSONOutput(result); err != nil {
+			logger.Error("failed to print accounts",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		return
+	}
+
 	for _, addr := range addresses {
 		var acctString string
 		switch cmdFlags.Verbose() {
@@ -306,6 +359,11 @@ func doList(cmd *cobra.Command, args []string) {
 	}
 }
 
+// accountAddress is the result of the pubkey2address command.
+type accountAddress struct {
+	Address api.Address `json:"address"`
+}
+
 func doPubkey2Address(cmd *cobra.Command, args []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
@@ -325,7 +383,15 @@ func doPubkey2Address(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	fmt.Printf("%v\n", api.NewAddress(pk))
+	addr := api.NewAddress(pk)
+	if err := cmdCommon.PrintResult(&accountAddress{Address: addr}, func() {
+		fmt.Printf("%v\n", addr)
+	}); err != nil {
+		logger.Error("failed to print account address",
+			"err", err,
+		)
+		os.Exit(1)
+	}
 }
 
 // Register registers the stake sub-command and all of it's children.
@@ -29,6 +29,7 @@ import (
 
 const (
 	cfgClientCertificate = "client.certificate"
+	cfgSocketPath        = "socket"
 
 	// clientCommonName is the common name on the client TLS certificates.
 	clientCommonName = "remote-signer-client"
@@ -147,16 +148,7 @@ func doClientInit(cmd *cobra.Command, args []string) {
 	}
 }
 
-func runRoot(cmd *cobra.Command, args []string) error {
-	// Initialize all of the server keys.
-	sf, cert, err := serverInit(false)
-	if err != nil {
-		logger.Error("failed to initialize server keys",
-			"err", err,
-		)
-		return err
-	}
-
+func newTCPServerConfig(cert *goTls.Certificate) (*grpc.ServerConfig, error) {
 	// Load the client certificate to be granted access.
 	clientCertPath := viper.GetString(cfgClientCertificate)
 	tlsCert, err := tls.LoadCertificate(clientCertPath)
@@ -164,18 +156,18 @@ func runRoot(cmd *cobra.Command, args []string) error {
 		logger.Error("failed to load client TLS certificate",
 			"err", err,
 		)
-		return err
+		return nil, err
 	}
 	clientCert, err := x509.ParseCertificate(tlsCert.Certificate[0])
 	if err != nil {
 		logger.Error("failed to parse client TLS certificate",
 			"err", err,
 		)
+		return nil, err
 	}
 	peerCertAuth := auth.NewPeerCertAuthenticator()
 	peerCertAuth.AllowPeerCertificate(clientCert)
 
-	// Initialize the gRPC server.
 	svrCfg := &grpc.ServerConfig{
 		Name:             "remote-signer",
 		Port:             uint16(viper.GetInt(cmdGrpc.CfgServerPort)),
@@ -184,6 +176,33 @@ func runRoot(cmd *cobra.Command, args []string) error {
 		ClientCommonName: clientCommonName,
 	}
 	svrCfg.Identity.SetTLSCertificate(cert)
+	return svrCfg, nil
+}
+
+func runRoot(cmd *cobra.Command, args []string) error {
+	// Initialize all of the server keys.
+	sf, cert, err := serverInit(false)
+	if err != nil {
+		logger.Error("failed to initialize server keys",
+			"err", err,
+		)
+		return err
+	}
+
+	// Initialize the gRPC server.
+	var svrCfg *grpc.ServerConfig
+	switch socketPath := viper.GetString(cfgSocketPath); socketPath {
+	case "":
+		if svrCfg, err = newTCPServerConfig(cert); err != nil {
+			return err
+		}
+	default:
+		// Access to the local socket is controlled by file system permissions.
+		svrCfg = &grpc.ServerConfig{
+			Name: "remote-signer",
+			Path: socketPath,
+		}
+	}
 	svr, err := grpc.NewServer(svrCfg)
 	if err != nil {
 		logger.Error("failed to instantiate gRPC server",
@@ -216,7 +235,8 @@ func init() {
 
 	_ = viper.BindPFlags(cmdCommon.RootFlags)
 
-	rootFlags.String(cfgClientCertificate, "client_cert.pem", "client TLS certificate (REQUIRED)")
+	rootFlags.String(cfgClientCertificate, "client_cert.pem", "client TLS certificate (REQUIRED unless listening on a local socket)")
+	rootFlags.String(cfgSocketPath, "", "listen on the given local unix socket instead of a TCP port")
 	_ = viper.BindPFlags(rootFlags)
 
 	rootCmd.PersistentFlags().AddFlagSet(cmdCommon.RootFlags)
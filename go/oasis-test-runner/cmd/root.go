@@ -2,6 +2,8 @@
 package cmd
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
@@ -37,6 +39,14 @@ const (
 	cfgNumRuns          = "num_runs"
 	cfgParallelJobCount = "parallel.job_count"
 	cfgParallelJobIndex = "parallel.job_index"
+	cfgSeed             = "seed"
+
+	cfgResourceReport                            = "resource_report"
+	cfgResourceReportBaseline                    = "resource_report.baseline"
+	cfgResourceReportThresholdCPUTime            = "resource_report.threshold.cpu_time"
+	cfgResourceReportThresholdMaxRSS             = "resource_report.threshold.max_rss"
+	cfgResourceReportThresholdDiskUsage          = "resource_report.threshold.disk_usage"
+	cfgResourceReportThresholdConsensusStateSize = "resource_report.threshold.consensus_state_size"
 )
 
 var (
@@ -365,6 +375,7 @@ func runRoot(cmd *cobra.Command, args []string) error { // nolint: gocyclo
 	}
 
 	// Run all requested scenarios.
+	var resourceReport oasis.ResourceReport
 	index := 0
 	for run := 0; run < numRuns; run++ {
 		// Iterate through toRun instead of toRunExploded to preserve scenario
@@ -398,8 +409,13 @@ func runRoot(cmd *cobra.Command, args []string) error { // nolint: gocyclo
 					continue
 				}
 
+				seed, err := scenarioSeed()
+				if err != nil {
+					return fmt.Errorf("root: failed to generate scenario seed: %w", err)
+				}
+
 				logger.Info("running scenario",
-					"scenario", name, "run_id", runID,
+					"scenario", name, "run_id", runID, "seed", seed,
 				)
 
 				childEnv, err := rootEnv.NewChild(n, &env.ScenarioInstanceInfo{
@@ -407,6 +423,7 @@ func runRoot(cmd *cobra.Command, args []string) error { // nolint: gocyclo
 					Instance:     filepath.Base(rootEnv.Dir()),
 					ParameterSet: v.Parameters(),
 					Run:          run,
+					Seed:         seed,
 				})
 				if err != nil {
 					logger.Error("failed to setup child environment",
@@ -435,6 +452,7 @@ func runRoot(cmd *cobra.Command, args []string) error { // nolint: gocyclo
 						"err", err,
 						"scenario", name,
 						"run_id", runID,
+						"seed", seed,
 					)
 					err = fmt.Errorf("root: failed to run scenario: %w", err)
 				}
@@ -458,14 +476,76 @@ func runRoot(cmd *cobra.Command, args []string) error { // nolint: gocyclo
 					"scenario", name, "run_id", runID,
 				)
 
+				usage, err := oasis.LoadResourceUsage(childEnv.Dir())
+				if err != nil {
+					return fmt.Errorf("root: failed to load scenario resource usage: %w", err)
+				}
+				if usage != nil {
+					resourceReport.AddScenario(n, usage)
+				}
+
 				index++
 			}
 		}
 	}
 
+	return checkResourceReport(logger, &resourceReport)
+}
+
+// checkResourceReport saves the resource usage report and compares it against the baseline
+// report, if configured.
+func checkResourceReport(logger *logging.Logger, report *oasis.ResourceReport) error {
+	if path := viper.GetString(cfgResourceReport); path != "" {
+		if err := report.Save(path); err != nil {
+			return fmt.Errorf("root: failed to save resource usage report: %w", err)
+		}
+	}
+
+	path := viper.GetString(cfgResourceReportBaseline)
+	if path == "" {
+		return nil
+	}
+	baseline, err := oasis.LoadResourceReport(path)
+	if err != nil {
+		return fmt.Errorf("root: failed to load baseline resource usage report: %w", err)
+	}
+
+	regressions := report.Compare(baseline, &oasis.ResourceThresholds{
+		CPUTime:            viper.GetFloat64(cfgResourceReportThresholdCPUTime),
+		MaxRSS:             viper.GetFloat64(cfgResourceReportThresholdMaxRSS),
+		DiskUsage:          viper.GetFloat64(cfgResourceReportThresholdDiskUsage),
+		ConsensusStateSize: viper.GetFloat64(cfgResourceReportThresholdConsensusStateSize),
+	})
+	for _, r := range regressions {
+		logger.Error("resource usage regression",
+			"scenario", r.Scenario,
+			"node", r.Node,
+			"resource", r.Resource,
+			"baseline", r.Baseline,
+			"current", r.Current,
+			"ratio", r.Ratio(),
+			"threshold", r.Threshold,
+		)
+	}
+	if len(regressions) > 0 {
+		return fmt.Errorf("root: %d resource usage regression(s) detected, first: %s", len(regressions), regressions[0])
+	}
 	return nil
 }
 
+// scenarioSeed returns the configured scenario seed or generates a new random one.
+func scenarioSeed() (string, error) {
+	if seed := viper.GetString(cfgSeed); seed != "" {
+		return seed, nil
+	}
+
+	var rawSeed [32]byte
+	if _, err := rand.Read(rawSeed[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(rawSeed[:]), nil
+}
+
 func doScenario(childEnv *env.Env, sc scenario.Scenario) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -609,6 +689,17 @@ func init() {
 	rootFlags.IntVarP(&numRuns, cfgNumRuns, "n", 1, "number of runs for given scenario(s)")
 	rootFlags.Int(cfgParallelJobCount, 1, "(for CI) number of overall parallel jobs")
 	rootFlags.Int(cfgParallelJobIndex, 0, "(for CI) index of this parallel job")
+	rootFlags.String(cfgSeed, "", "scenario seed used to derive all randomness (random if not set)")
+	rootFlags.String(cfgResourceReport, "", "path to write the per-node resource usage report to")
+	rootFlags.String(cfgResourceReportBaseline, "", "path to the baseline resource usage report to compare against")
+	rootFlags.Float64(cfgResourceReportThresholdCPUTime, 1.2, "maximum allowed CPU time ratio to baseline (0 to disable)")
+	rootFlags.Float64(cfgResourceReportThresholdMaxRSS, 1.2, "maximum allowed max RSS ratio to baseline (0 to disable)")
+	rootFlags.Float64(cfgResourceReportThresholdDiskUsage, 1.15, "maximum allowed disk usage ratio to baseline (0 to disable)")
+	rootFlags.Float64(
+		cfgResourceReportThresholdConsensusStateSize,
+		1.1,
+		"maximum allowed consensus state size ratio to baseline (0 to disable)",
+	)
 	_ = viper.BindPFlags(rootFlags)
 	rootCmd.Flags().AddFlagSet(rootFlags)
 	rootCmd.Flags().AddFlagSet(env.Flags)
@@ -3,9 +3,11 @@ package env
 
 import (
 	"container/list"
+	"crypto"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
+	"math/rand"
 	"os/exec"
 	"path/filepath"
 	"reflect"
@@ -15,6 +17,8 @@ import (
 
 	flag "github.com/spf13/pflag"
 
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/drbg"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/mathrand"
 	cmnSyscall "github.com/oasisprotocol/oasis-core/go/common/syscall"
 )
 
@@ -22,6 +26,8 @@ import (
 // sub-process terminates prior to the Cleanup.
 var ErrEarlyTerm = errors.New("env: sub-process exited early")
 
+var errScenarioSeedUnavailable = errors.New("env: scenario seed not available")
+
 // CmdAttrs is the SysProcAttr that will ensure graceful cleanup (on Linux).
 var CmdAttrs = cmnSyscall.CmdAttrs
 
@@ -49,6 +55,9 @@ type ScenarioInstanceInfo struct {
 
 	// Run is the number of the run.
 	Run int `json:"run"`
+
+	// Seed is the scenario seed from which all randomness used by the scenario is derived.
+	Seed string `json:"seed"`
 }
 
 // MarshalJSON outputs ParameterFlagSet as an ordinary JSON map.
@@ -131,6 +140,28 @@ func (env *Env) ScenarioInfo() *ScenarioInstanceInfo {
 	return env.scenarioInfo
 }
 
+// NewDrbg returns a new deterministic random bit generator derived from the scenario seed and
+// the given domain separator.
+func (env *Env) NewDrbg(domainSep string) (*drbg.Drbg, error) {
+	if env.scenarioInfo == nil || env.scenarioInfo.Seed == "" {
+		return nil, errScenarioSeedUnavailable
+	}
+
+	h := crypto.SHA512.New()
+	_, _ = h.Write([]byte(env.scenarioInfo.Seed))
+	return drbg.New(crypto.SHA512, h.Sum(nil), nil, []byte(domainSep))
+}
+
+// NewRand returns a new deterministic random number generator derived from the scenario seed
+// and the given domain separator.
+func (env *Env) NewRand(domainSep string) (*rand.Rand, error) {
+	src, err := env.NewDrbg(domainSep)
+	if err != nil {
+		return nil, err
+	}
+	return rand.New(mathrand.New(src)), nil // nolint: gosec
+}
+
 // AddOnCleanup adds a cleanup routine to be called during the environment's
 // cleanup.  Routines will be called in reverse order that they were
 // registered.
@@ -73,3 +73,27 @@ func TestParameterFlagSet_MarshalJSON(t *testing.T) {
 	require.Equal(t, "value1", fsNew["flag1"])
 	require.Equal(t, "defaultvalue2", fsNew["flag2"])
 }
+
+func TestEnv_NewRand(t *testing.T) {
+	require := require.New(t)
+
+	env := &Env{scenarioInfo: &ScenarioInstanceInfo{Seed: "seed"}}
+	rng1, err := env.NewRand("domain")
+	require.NoError(err, "NewRand")
+	rng2, err := env.NewRand("domain")
+	require.NoError(err, "NewRand")
+	rng3, err := env.NewRand("other domain")
+	require.NoError(err, "NewRand")
+
+	v1, v2, v3 := rng1.Uint64(), rng2.Uint64(), rng3.Uint64()
+	require.Equal(v1, v2, "same seed and domain should produce the same values")
+	require.NotEqual(v1, v3, "different domains should produce different values")
+
+	other := &Env{scenarioInfo: &ScenarioInstanceInfo{Seed: "other seed"}}
+	rng4, err := other.NewRand("domain")
+	require.NoError(err, "NewRand")
+	require.NotEqual(v1, rng4.Uint64(), "different seeds should produce different values")
+
+	_, err = (&Env{}).NewRand("domain")
+	require.Error(err, "NewRand should fail without a scenario seed")
+}
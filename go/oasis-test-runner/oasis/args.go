@@ -218,10 +218,10 @@ func (args *argBuilder) tendermintRecoverCorruptedWAL(enable bool) *argBuilder {
 	return args
 }
 
-func (args *argBuilder) tendermintCoreAddress(port uint16) *argBuilder {
+func (args *argBuilder) tendermintCoreAddress(host string, port uint16) *argBuilder {
 	args.vec = append(args.vec, []Argument{
 		{tendermintCommon.CfgCoreListenAddress, []string{"tcp://0.0.0.0:" + strconv.Itoa(int(port))}, false},
-		{tendermintCommon.CfgCoreExternalAddress, []string{"tcp://127.0.0.1:" + strconv.Itoa(int(port))}, false},
+		{tendermintCommon.CfgCoreExternalAddress, []string{"tcp://" + hostPort(host, port)}, false},
 	}...)
 	return args
 }
@@ -546,7 +546,7 @@ func (args *argBuilder) iasSPID(spid []byte) *argBuilder {
 func (args *argBuilder) addSentries(sentries []*Sentry) *argBuilder {
 	var addrs []string
 	for _, sentry := range sentries {
-		addrs = append(addrs, fmt.Sprintf("%s@127.0.0.1:%d", sentry.tlsPublicKey.String(), sentry.controlPort))
+		addrs = append(addrs, sentry.tlsPublicKey.String()+"@"+sentry.GetSentryControlAddress())
 	}
 	return args.workerCommonSentryAddresses(addrs)
 }
@@ -554,7 +554,7 @@ func (args *argBuilder) addSentries(sentries []*Sentry) *argBuilder {
 func (args *argBuilder) addValidatorsAsSentryUpstreams(validators []*Validator) *argBuilder {
 	var addrs, sentryPubKeys []string
 	for _, val := range validators {
-		addrs = append(addrs, fmt.Sprintf("%s@127.0.0.1:%d", val.tmAddress, val.consensusPort))
+		addrs = append(addrs, val.tmAddress+"@"+hostPort(val.hostAddress(), val.consensusPort))
 		key, _ := val.sentryPubKey.MarshalText()
 		sentryPubKeys = append(sentryPubKeys, string(key))
 	}
@@ -564,9 +564,9 @@ func (args *argBuilder) addValidatorsAsSentryUpstreams(validators []*Validator)
 func (args *argBuilder) addSentryStorageWorkers(storageWorkers []*Storage) *argBuilder {
 	var addrs, ids, tmAddrs, sentryPubKeys []string
 	for _, storageWorker := range storageWorkers {
-		addrs = append(addrs, fmt.Sprintf("127.0.0.1:%d", storageWorker.clientPort))
+		addrs = append(addrs, storageWorker.GetClientAddress())
 		ids = append(ids, storageWorker.NodeID.String())
-		tmAddrs = append(tmAddrs, fmt.Sprintf("%s@127.0.0.1:%d", storageWorker.tmAddress, storageWorker.consensusPort))
+		tmAddrs = append(tmAddrs, storageWorker.tmAddress+"@"+hostPort(storageWorker.hostAddress(), storageWorker.consensusPort))
 		key, _ := storageWorker.sentryPubKey.MarshalText()
 		sentryPubKeys = append(sentryPubKeys, string(key))
 	}
@@ -579,9 +579,9 @@ func (args *argBuilder) addSentryStorageWorkers(storageWorkers []*Storage) *argB
 func (args *argBuilder) addSentryKeymanagerWorkers(keymanagerWorkers []*Keymanager) *argBuilder {
 	var addrs, ids, tmAddrs, sentryPubKeys []string
 	for _, keymanager := range keymanagerWorkers {
-		addrs = append(addrs, fmt.Sprintf("127.0.0.1:%d", keymanager.workerClientPort))
+		addrs = append(addrs, hostPort(keymanager.hostAddress(), keymanager.workerClientPort))
 		ids = append(ids, keymanager.NodeID.String())
-		tmAddrs = append(tmAddrs, fmt.Sprintf("%s@127.0.0.1:%d", keymanager.tmAddress, keymanager.consensusPort))
+		tmAddrs = append(tmAddrs, keymanager.tmAddress+"@"+hostPort(keymanager.hostAddress(), keymanager.consensusPort))
 		key, _ := keymanager.sentryPubKey.MarshalText()
 		sentryPubKeys = append(sentryPubKeys, string(key))
 	}
@@ -595,7 +595,7 @@ func (args *argBuilder) appendSeedNodes(seeds []*Seed) *argBuilder {
 	for _, seed := range seeds {
 		args.vec = append(args.vec, Argument{
 			Name:        tendermintCommon.CfgP2PSeed,
-			Values:      []string{fmt.Sprintf("%s@127.0.0.1:%d", seed.tmAddress, seed.consensusPort)},
+			Values:      []string{seed.tmAddress + "@" + hostPort(seed.hostAddress(), seed.consensusPort)},
 			MultiValued: true,
 		})
 	}
@@ -686,7 +686,7 @@ func (args *argBuilder) appendEntity(ent *Entity) *argBuilder {
 func (args *argBuilder) appendIASProxy(iasProxy *iasProxy) *argBuilder {
 	if iasProxy != nil {
 		args.vec = append(args.vec, []Argument{
-			{ias.CfgProxyAddress, []string{fmt.Sprintf("%s@127.0.0.1:%d", iasProxy.tlsPublicKey, iasProxy.grpcPort)}, false},
+			{ias.CfgProxyAddress, []string{iasProxy.tlsPublicKey.String() + "@" + hostPort(iasProxy.hostAddress(), iasProxy.grpcPort)}, false},
 			{Name: ias.CfgAllowDebugEnclaves},
 		}...)
 		if iasProxy.mock {
@@ -45,7 +45,7 @@ func (worker *Byzantine) AddArgs(args *argBuilder) error {
 		debugSetRlimit().
 		debugEnableProfiling(worker.Node.pprofPort).
 		tendermintDebugAllowDuplicateIP().
-		tendermintCoreAddress(worker.consensusPort).
+		tendermintCoreAddress(worker.hostAddress(), worker.consensusPort).
 		tendermintDebugAddrBookLenient().
 		tendermintSubmissionGasPrice(worker.consensus.SubmissionGasPrice).
 		workerP2pPort(worker.p2pPort).
@@ -0,0 +1,538 @@
+package oasis
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	netPkg "net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	tendermintCommon "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/common"
+)
+
+const (
+	nodePortChaos = "chaos"
+
+	defaultChaosRetransmitTimeout = 200 * time.Millisecond
+
+	chaosChunkSize = 32 * 1024
+	chaosQueueSize = 64
+)
+
+// ChaosCfg is the network chaos injection configuration.
+//
+// When enabled, Tendermint P2P connections between nodes are routed through an in-process proxy
+// which applies the configured link conditions and partitions. Nodes listen on an internal port
+// while the proxy listens on the port advertised to other nodes.
+type ChaosCfg struct {
+	// Seed is the seed of the random number generator used for jitter and packet loss. If not
+	// set, the seed is derived from the network seed.
+	Seed int64 `json:"seed,omitempty"`
+
+	// RetransmitTimeout is the additional delay of data affected by packet loss, modelling
+	// the retransmission of lost packets. If not set, a default of 200ms is used.
+	RetransmitTimeout time.Duration `json:"retransmit_timeout,omitempty"`
+
+	// Links are the link conditions between nodes. For each direction of a connection the first
+	// matching link is used.
+	Links []ChaosLinkCfg `json:"links,omitempty"`
+
+	// Partitions are the temporary network partitions.
+	Partitions []ChaosPartitionCfg `json:"partitions,omitempty"`
+}
+
+// ChaosLinkCfg are the conditions of the link between two nodes.
+type ChaosLinkCfg struct {
+	// From is the name of the node sending the data. Empty matches any node.
+	From string `json:"from,omitempty"`
+	// To is the name of the node receiving the data. Empty matches any node.
+	To string `json:"to,omitempty"`
+
+	// Latency is the delay of all data sent over the link.
+	Latency time.Duration `json:"latency,omitempty"`
+	// Jitter is the maximum random deviation from the latency.
+	Jitter time.Duration `json:"jitter,omitempty"`
+	// PacketLoss is the probability that data sent over the link is lost and retransmitted.
+	PacketLoss float64 `json:"packet_loss,omitempty"`
+}
+
+func (l *ChaosLinkCfg) matches(from, to string) bool {
+	return (l.From == "" || l.From == from) && (l.To == "" || l.To == to)
+}
+
+// ChaosPartitionCfg is a temporary network partition.
+type ChaosPartitionCfg struct {
+	// Nodes are the names of the nodes on one side of the partition.
+	Nodes []string `json:"nodes"`
+	// Others are the names of the nodes on the other side of the partition. If empty, the nodes
+	// are isolated from all other nodes.
+	Others []string `json:"others,omitempty"`
+
+	// Start is the time after the network has been started at which the partition is created.
+	Start time.Duration `json:"start,omitempty"`
+	// Duration is the time after which the partition is healed. If zero, the partition is never
+	// healed.
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+func (cfg *ChaosCfg) validate() error {
+	if cfg.RetransmitTimeout < 0 {
+		return fmt.Errorf("retransmit timeout must not be negative")
+	}
+	for i, l := range cfg.Links {
+		if l.Latency < 0 || l.Jitter < 0 {
+			return fmt.Errorf("link %d: latency and jitter must not be negative", i)
+		}
+		if l.PacketLoss < 0 || l.PacketLoss > 1 {
+			return fmt.Errorf("link %d: packet loss must be between 0 and 1", i)
+		}
+	}
+	for i, p := range cfg.Partitions {
+		if len(p.Nodes) == 0 {
+			return fmt.Errorf("partition %d: no nodes", i)
+		}
+		if p.Start < 0 || p.Duration < 0 {
+			return fmt.Errorf("partition %d: start and duration must not be negative", i)
+		}
+	}
+	return nil
+}
+
+type chaosPartition struct {
+	nodes  map[string]bool
+	others map[string]bool
+}
+
+func (p *chaosPartition) separates(a, b string) bool {
+	side := func(name string) int {
+		switch {
+		case p.nodes[name]:
+			return 1
+		case len(p.others) == 0 || p.others[name]:
+			return 2
+		default:
+			return 0
+		}
+	}
+	sa, sb := side(a), side(b)
+	return sa != 0 && sb != 0 && sa != sb
+}
+
+type chaosConn struct {
+	src string
+	dst string
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+	conns     []netPkg.Conn
+}
+
+func (cc *chaosConn) close() {
+	cc.closeOnce.Do(func() {
+		close(cc.closeCh)
+		for _, conn := range cc.conns {
+			_ = conn.Close()
+		}
+	})
+}
+
+type chaosChunk struct {
+	data      []byte
+	deliverAt time.Time
+}
+
+// Chaos is the network chaos injector.
+type Chaos struct {
+	sync.Mutex
+
+	net    *Network
+	cfg    ChaosCfg
+	logger *logging.Logger
+	rng    *rand.Rand
+
+	listeners  map[uint16]netPkg.Listener
+	conns      map[*chaosConn]bool
+	partitions map[*chaosPartition]bool
+	timers     []*time.Timer
+	closed     bool
+}
+
+// Partition isolates the given nodes from the other nodes until the returned function is called.
+//
+// If others is empty, the nodes are isolated from all other nodes. Existing connections crossing
+// the partition are closed and new ones are refused.
+func (c *Chaos) Partition(nodes, others []string) (heal func()) {
+	p := &chaosPartition{
+		nodes:  make(map[string]bool),
+		others: make(map[string]bool),
+	}
+	for _, name := range nodes {
+		p.nodes[name] = true
+	}
+	for _, name := range others {
+		p.others[name] = true
+	}
+
+	c.Lock()
+	c.partitions[p] = true
+	var toClose []*chaosConn
+	for cc := range c.conns {
+		if p.separates(cc.src, cc.dst) {
+			toClose = append(toClose, cc)
+		}
+	}
+	c.Unlock()
+
+	c.logger.Info("creating network partition",
+		"nodes", strings.Join(nodes, ","),
+		"others", strings.Join(others, ","),
+		"closed_connections", len(toClose),
+	)
+	for _, cc := range toClose {
+		cc.close()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.Lock()
+			delete(c.partitions, p)
+			c.Unlock()
+
+			c.logger.Info("healing network partition",
+				"nodes", strings.Join(nodes, ","),
+				"others", strings.Join(others, ","),
+			)
+		})
+	}
+}
+
+func (c *Chaos) isPartitioned(a, b string) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	for p := range c.partitions {
+		if p.separates(a, b) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Chaos) link(from, to string) *ChaosLinkCfg {
+	for i := range c.cfg.Links {
+		if c.cfg.Links[i].matches(from, to) {
+			return &c.cfg.Links[i]
+		}
+	}
+	return nil
+}
+
+func (c *Chaos) delay(link *ChaosLinkCfg) time.Duration {
+	c.Lock()
+	defer c.Unlock()
+
+	d := link.Latency
+	if link.Jitter > 0 {
+		d += time.Duration((2*c.rng.Float64() - 1) * float64(link.Jitter))
+	}
+	if link.PacketLoss > 0 && c.rng.Float64() < link.PacketLoss {
+		d += c.cfg.RetransmitTimeout
+	}
+	if d < 0 {
+		d = 0
+	}
+	return d
+}
+
+// proxyNode routes the Tendermint P2P connections of the given node through the proxy by making
+// the node listen on an internal port instead of the advertised one.
+func (c *Chaos) proxyNode(node *Node, args *argBuilder) error {
+	for i, arg := range args.vec {
+		if arg.Name != tendermintCommon.CfgCoreListenAddress || len(arg.Values) != 1 {
+			continue
+		}
+
+		addr := arg.Values[0]
+		idx := strings.LastIndex(addr, ":")
+		port, err := strconv.ParseUint(addr[idx+1:], 10, 16)
+		if err != nil {
+			return fmt.Errorf("malformed listen address '%s': %w", addr, err)
+		}
+		internalPort := node.getProvisionedPort(fmt.Sprintf("%s-%d", nodePortChaos, port))
+		if err = c.listen(node.Name, uint16(port), internalPort); err != nil {
+			return err
+		}
+
+		args.vec[i] = Argument{
+			Name:   arg.Name,
+			Values: []string{addr[:idx+1] + strconv.Itoa(int(internalPort))},
+		}
+	}
+	return nil
+}
+
+func (c *Chaos) listen(dst string, port, internalPort uint16) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.listeners[port]; ok {
+		return nil
+	}
+	if c.closed {
+		return fmt.Errorf("chaos injector closed")
+	}
+
+	ln, err := netPkg.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return fmt.Errorf("failed to listen on proxied port %d: %w", port, err)
+	}
+	c.listeners[port] = ln
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go c.handleConn(dst, conn, fmt.Sprintf("127.0.0.1:%d", internalPort))
+		}
+	}()
+	return nil
+}
+
+func (c *Chaos) handleConn(dst string, conn netPkg.Conn, upstreamAddr string) {
+	src := c.identifyNode(conn)
+	if c.isPartitioned(src, dst) {
+		c.logger.Debug("refusing partitioned connection",
+			"src", src,
+			"dst", dst,
+		)
+		_ = conn.Close()
+		return
+	}
+
+	upstream, err := netPkg.Dial("tcp", upstreamAddr)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	cc := &chaosConn{
+		src:     src,
+		dst:     dst,
+		closeCh: make(chan struct{}),
+		conns:   []netPkg.Conn{conn, upstream},
+	}
+	c.Lock()
+	if c.closed {
+		c.Unlock()
+		cc.close()
+		return
+	}
+	c.conns[cc] = true
+	c.Unlock()
+
+	c.logger.Debug("proxying connection",
+		"src", src,
+		"dst", dst,
+	)
+
+	go c.pipe(cc, conn, upstream, c.link(src, dst))
+	c.pipe(cc, upstream, conn, c.link(dst, src))
+
+	c.Lock()
+	delete(c.conns, cc)
+	c.Unlock()
+}
+
+func (c *Chaos) pipe(cc *chaosConn, from, to netPkg.Conn, link *ChaosLinkCfg) {
+	defer cc.close()
+
+	if link == nil {
+		_, _ = io.Copy(to, from)
+		return
+	}
+
+	chunks := make(chan *chaosChunk, chaosQueueSize)
+	go func() {
+		defer close(chunks)
+
+		var last time.Time
+		for {
+			buf := make([]byte, chaosChunkSize)
+			n, err := from.Read(buf)
+			if n > 0 {
+				// Data must be delivered in order, even with jitter.
+				deliverAt := time.Now().Add(c.delay(link))
+				if deliverAt.Before(last) {
+					deliverAt = last
+				}
+				last = deliverAt
+
+				select {
+				case chunks <- &chaosChunk{data: buf[:n], deliverAt: deliverAt}:
+				case <-cc.closeCh:
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for chunk := range chunks {
+		select {
+		case <-time.After(time.Until(chunk.deliverAt)):
+		case <-cc.closeCh:
+			return
+		}
+		if _, err := to.Write(chunk.data); err != nil {
+			return
+		}
+	}
+}
+
+// identifyNode returns the name of the node which initiated the given proxied connection or
+// an empty string if the node cannot be identified.
+//
+// The node is identified by looking up the process owning the other end of the connection.
+func (c *Chaos) identifyNode(conn netPkg.Conn) string {
+	localAddr, ok1 := conn.LocalAddr().(*netPkg.TCPAddr)
+	remoteAddr, ok2 := conn.RemoteAddr().(*netPkg.TCPAddr)
+	if !ok1 || !ok2 {
+		return ""
+	}
+	if c.net == nil {
+		return ""
+	}
+	inode := findSocketInode(remoteAddr.Port, localAddr.Port)
+	if inode == "" {
+		return ""
+	}
+	socket := "socket:[" + inode + "]"
+
+	for _, node := range c.net.nodes {
+		pid := node.pid()
+		if pid == 0 {
+			continue
+		}
+		fdDir := fmt.Sprintf("/proc/%d/fd", pid)
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			if target, _ := os.Readlink(filepath.Join(fdDir, fd.Name())); target == socket {
+				return node.Name
+			}
+		}
+	}
+	return ""
+}
+
+// findSocketInode returns the inode of the TCP socket with the given local and remote ports.
+func findSocketInode(localPort, remotePort int) string {
+	parsePort := func(addr string) int {
+		port, err := strconv.ParseUint(addr[strings.LastIndex(addr, ":")+1:], 16, 16)
+		if err != nil {
+			return -1
+		}
+		return int(port)
+	}
+
+	for _, fn := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		data, err := os.ReadFile(fn)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n")[1:] {
+			fields := strings.Fields(line)
+			if len(fields) < 10 {
+				continue
+			}
+			if parsePort(fields[1]) == localPort && parsePort(fields[2]) == remotePort {
+				return fields[9]
+			}
+		}
+	}
+	return ""
+}
+
+func (c *Chaos) start() {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, p := range c.cfg.Partitions {
+		p := p
+		c.timers = append(c.timers, time.AfterFunc(p.Start, func() {
+			heal := c.Partition(p.Nodes, p.Others)
+			if p.Duration == 0 {
+				return
+			}
+
+			c.Lock()
+			defer c.Unlock()
+			if c.closed {
+				return
+			}
+			c.timers = append(c.timers, time.AfterFunc(p.Duration, heal))
+		}))
+	}
+}
+
+func (c *Chaos) cleanup() {
+	c.Lock()
+	c.closed = true
+	for _, t := range c.timers {
+		t.Stop()
+	}
+	for _, ln := range c.listeners {
+		_ = ln.Close()
+	}
+	conns := c.conns
+	c.conns = make(map[*chaosConn]bool)
+	c.Unlock()
+
+	for cc := range conns {
+		cc.close()
+	}
+}
+
+func newChaos(net *Network, cfg *ChaosCfg) (*Chaos, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("oasis/chaos: invalid configuration: %w", err)
+	}
+
+	seed := cfg.Seed
+	if seed == 0 && net != nil && net.cfg.Seed != "" {
+		h := hash.NewFromBytes([]byte(net.cfg.Seed), []byte("oasis/chaos"))
+		seed = int64(binary.LittleEndian.Uint64(h[:8]))
+	}
+
+	c := &Chaos{
+		net:        net,
+		cfg:        *cfg,
+		logger:     logging.GetLogger("oasis/chaos"),
+		rng:        rand.New(rand.NewSource(seed)), // nolint: gosec
+		listeners:  make(map[uint16]netPkg.Listener),
+		conns:      make(map[*chaosConn]bool),
+		partitions: make(map[*chaosPartition]bool),
+	}
+	if c.cfg.RetransmitTimeout == 0 {
+		c.cfg.RetransmitTimeout = defaultChaosRetransmitTimeout
+	}
+	if net != nil {
+		net.env.AddOnCleanup(c.cleanup)
+	}
+	return c, nil
+}
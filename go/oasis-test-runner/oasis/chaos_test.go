@@ -0,0 +1,109 @@
+package oasis
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func freeTCPPort(t *testing.T) uint16 {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err, "Listen")
+	defer ln.Close()
+	return uint16(ln.Addr().(*net.TCPAddr).Port)
+}
+
+func TestChaosPartition(t *testing.T) {
+	require := require.New(t)
+
+	p := &chaosPartition{
+		nodes:  map[string]bool{"a": true},
+		others: map[string]bool{},
+	}
+	require.True(p.separates("a", "b"), "isolated node should be separated from others")
+	require.True(p.separates("b", "a"), "partitions should be symmetric")
+	require.True(p.separates("a", ""), "isolated node should be separated from unknown nodes")
+	require.False(p.separates("b", "c"), "other nodes should not be separated")
+
+	p.others["b"] = true
+	require.True(p.separates("a", "b"))
+	require.False(p.separates("a", "c"), "nodes outside of the partition should not be separated")
+}
+
+func TestChaosProxy(t *testing.T) {
+	require := require.New(t)
+
+	// Upstream echo server.
+	upstream, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err, "Listen")
+	defer upstream.Close()
+	go func() {
+		for {
+			conn, aerr := upstream.Accept()
+			if aerr != nil {
+				return
+			}
+			go func() {
+				_, _ = io.Copy(conn, conn)
+				conn.Close()
+			}()
+		}
+	}()
+
+	latency := 100 * time.Millisecond
+	c, err := newChaos(nil, &ChaosCfg{
+		Links: []ChaosLinkCfg{
+			{To: "node", Latency: latency},
+		},
+	})
+	require.NoError(err, "newChaos")
+	defer c.cleanup()
+
+	port := freeTCPPort(t)
+	err = c.listen("node", port, uint16(upstream.Addr().(*net.TCPAddr).Port))
+	require.NoError(err, "listen")
+
+	conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	require.NoError(err, "Dial")
+	defer conn.Close()
+
+	// Data sent to the node should be delayed.
+	start := time.Now()
+	_, err = conn.Write([]byte("hello"))
+	require.NoError(err, "Write")
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(err, "ReadFull")
+	require.Equal([]byte("hello"), buf)
+	require.GreaterOrEqual(time.Since(start), latency, "data should be delayed by the link latency")
+
+	// Partitions should close existing connections.
+	heal := c.Partition([]string{"node"}, nil)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = conn.Read(buf)
+	require.Error(err, "partitioned connection should be closed")
+
+	// New connections should be refused until the partition is healed.
+	conn2, err := net.Dial("tcp", conn.RemoteAddr().String())
+	require.NoError(err, "Dial")
+	defer conn2.Close()
+	_ = conn2.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = conn2.Read(buf)
+	require.Error(err, "partitioned connection should be refused")
+
+	heal()
+	conn3, err := net.Dial("tcp", conn.RemoteAddr().String())
+	require.NoError(err, "Dial")
+	defer conn3.Close()
+	_, err = conn3.Write([]byte("world"))
+	require.NoError(err, "Write")
+	_, err = io.ReadFull(conn3, buf)
+	require.NoError(err, "ReadFull after healing")
+	require.Equal([]byte("world"), buf)
+
+	require.Error((&ChaosCfg{Links: []ChaosLinkCfg{{PacketLoss: 2}}}).validate(), "invalid packet loss should be rejected")
+}
@@ -32,6 +32,22 @@ func (c *ConsensusHelpers) SubmitTx(txPath string) error {
 	return nil
 }
 
+// SignTx is a wrapper for "consensus sign_tx" subcommand.
+func (c *ConsensusHelpers) SignTx(unsignedTxPath, txPath string, extraArgs ...string) error {
+	c.logger.Info("signing tx", consensus.CfgTxUnsignedFile, unsignedTxPath, consensus.CfgTxFile, txPath)
+
+	args := append([]string{
+		"consensus", "sign_tx",
+		"--" + consensus.CfgTxUnsignedFile, unsignedTxPath,
+		"--" + consensus.CfgTxFile, txPath,
+		"--" + common.CfgDebugAllowTestKeys,
+	}, extraArgs...)
+	if err := c.runSubCommand("consensus-sign_tx", args); err != nil {
+		return fmt.Errorf("failed to sign tx: %w", err)
+	}
+	return nil
+}
+
 // EstimateGas is a wrapper for "consensus estimate_gas" subcommand.
 func (c *ConsensusHelpers) EstimateGas(txPath string, signerPub signature.PublicKey) (transaction.Gas, error) {
 	c.logger.Info("estimating gas", consensus.CfgTxFile, txPath)
@@ -38,7 +38,7 @@ func (client *Client) AddArgs(args *argBuilder) error {
 		runtimeProvisioner(client.runtimeProvisioner).
 		tendermintPrune(client.consensus.PruneNumKept).
 		tendermintRecoverCorruptedWAL(client.consensus.TendermintRecoverCorruptedWAL).
-		tendermintCoreAddress(client.consensusPort).
+		tendermintCoreAddress(client.hostAddress(), client.consensusPort).
 		appendNetwork(client.net).
 		appendSeedNodes(client.net.seeds).
 		workerP2pPort(client.p2pPort).
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
 )
 
@@ -47,6 +48,54 @@ func (worker *Compute) UpdateRuntimes(runtimes []int) {
 	worker.runtimes = runtimes
 }
 
+// hostsRuntime returns true iff the worker node hosts a runtime with the given ID.
+func (worker *Compute) hostsRuntime(id common.Namespace) bool {
+	worker.RLock()
+	defer worker.RUnlock()
+
+	for _, idx := range worker.runtimes {
+		if worker.net.runtimes[idx].id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceRuntime replaces the worker node runtimes with the same ID as the given runtime with
+// the given runtime. The node needs to be restarted for the change to take effect.
+func (worker *Compute) replaceRuntime(rt *Runtime) {
+	worker.Lock()
+	defer worker.Unlock()
+
+	newIdx := -1
+	for idx, v := range worker.net.runtimes {
+		if v == rt {
+			newIdx = idx
+			break
+		}
+	}
+
+	runtimes := make([]int, 0, len(worker.runtimes))
+	var replaced bool
+	for _, idx := range worker.runtimes {
+		if worker.net.runtimes[idx].id != rt.id {
+			runtimes = append(runtimes, idx)
+			continue
+		}
+		if !replaced {
+			runtimes = append(runtimes, newIdx)
+			if cfg, ok := worker.runtimeConfig[idx]; ok {
+				worker.runtimeConfig[newIdx] = cfg
+			}
+			replaced = true
+		}
+	}
+	worker.runtimes = runtimes
+
+	// Make sure the new runtime is used when the node is restarted.
+	delete(worker.hostedRuntimes, rt.id)
+}
+
 // IdentityKeyPath returns the path to the node's identity key.
 func (worker *Compute) IdentityKeyPath() string {
 	return nodeIdentityKeyPath(worker.dir)
@@ -86,7 +135,7 @@ func (worker *Compute) AddArgs(args *argBuilder) error {
 		debugSetRlimit().
 		debugEnableProfiling(worker.Node.pprofPort).
 		workerCertificateRotation(true).
-		tendermintCoreAddress(worker.consensusPort).
+		tendermintCoreAddress(worker.hostAddress(), worker.consensusPort).
 		tendermintSubmissionGasPrice(worker.consensus.SubmissionGasPrice).
 		tendermintPrune(worker.consensus.PruneNumKept).
 		tendermintRecoverCorruptedWAL(worker.consensus.TendermintRecoverCorruptedWAL).
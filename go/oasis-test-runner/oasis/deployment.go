@@ -0,0 +1,317 @@
+package oasis
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	netPkg "net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+)
+
+const (
+	localhost = "127.0.0.1"
+
+	defaultDeploymentRemoteDir = "/srv/oasis"
+
+	deploymentBinDir     = "bin"
+	deploymentEtcDir     = "etc"
+	deploymentNetworkDir = "network"
+	deploymentSystemdDir = "systemd"
+)
+
+const systemdUnitTemplate = `[Unit]
+Description=Oasis node %s
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=simple
+WorkingDirectory=%s
+ExecStart=%s
+Restart=on-failure
+LimitNOFILE=%d
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// DeploymentCfg is the multi-host network deployment configuration.
+type DeploymentCfg struct {
+	// Hosts maps node names to the IP addresses of the hosts the nodes are deployed to. Nodes
+	// advertise these addresses to other nodes.
+	Hosts map[string]string `json:"hosts"`
+
+	// RemoteDir is the directory on the hosts where the deployment bundles are installed.
+	// If not set, /srv/oasis is used.
+	RemoteDir string `json:"remote_dir,omitempty"`
+}
+
+func (cfg *DeploymentCfg) validate() error {
+	for name, host := range cfg.Hosts {
+		if netPkg.ParseIP(host) == nil {
+			return fmt.Errorf("oasis/deployment: invalid IP address for node %s: %s", name, host)
+		}
+	}
+	if cfg.RemoteDir != "" && !filepath.IsAbs(cfg.RemoteDir) {
+		return fmt.Errorf("oasis/deployment: remote directory must be an absolute path")
+	}
+	return nil
+}
+
+// hostAddress returns the IP address of the host the node is deployed to.
+func (n *Node) hostAddress() string {
+	if cfg := n.net.cfg.Deployment; cfg != nil {
+		if host, ok := cfg.Hosts[n.Name]; ok {
+			return host
+		}
+	}
+	return localhost
+}
+
+func hostPort(host string, port uint16) string {
+	return netPkg.JoinHostPort(host, strconv.Itoa(int(port)))
+}
+
+// WriteDeploymentBundles provisions the network without starting any nodes and writes a
+// deployment bundle for each of the deployment hosts into the given directory.
+//
+// Each bundle is written into a sub-directory named after the host and contains the node binary,
+// any runtime binaries, the data directories of all nodes deployed to the host and a systemd unit
+// for each of the nodes. The bundle is expected to be installed into the configured remote
+// directory on the host.
+func (net *Network) WriteDeploymentBundles(dir string) error {
+	cfg := net.cfg.Deployment
+	if cfg == nil {
+		return fmt.Errorf("oasis/deployment: deployment not configured")
+	}
+	remoteDir := cfg.RemoteDir
+	if remoteDir == "" {
+		remoteDir = defaultDeploymentRemoteDir
+	}
+
+	if err := net.provision(); err != nil {
+		return err
+	}
+
+	bundles := make(map[string]*deploymentBundle)
+	for _, n := range net.nodes {
+		if n.noAutoStart {
+			net.logger.Debug("skipping non-autostartable node", "name", n.Name)
+			continue
+		}
+		host, ok := cfg.Hosts[n.Name]
+		if !ok {
+			return fmt.Errorf("oasis/deployment: no host configured for node %s", n.Name)
+		}
+
+		args, customStart, err := n.buildArgs()
+		if err != nil {
+			return err
+		}
+		if customStart != nil {
+			return fmt.Errorf("oasis/deployment: node %s has customized startup which is not supported", n.Name)
+		}
+		nodeArgs, err := net.nodeArgs(n, nil, args)
+		if err != nil {
+			return fmt.Errorf("oasis/deployment: failed to generate arguments for node %s: %w", n.Name, err)
+		}
+
+		b, ok := bundles[host]
+		if !ok {
+			b = &deploymentBundle{
+				dir:       filepath.Join(dir, host),
+				remoteDir: remoteDir,
+				baseDir:   net.baseDir.String(),
+				files:     make(map[string]string),
+			}
+			bundles[host] = b
+		}
+		if err = b.addNode(n.Name, net.cfg.NodeBinary, nodeArgs); err != nil {
+			return fmt.Errorf("oasis/deployment: failed to add node %s to bundle: %w", n.Name, err)
+		}
+
+		net.logger.Info("added node to deployment bundle",
+			"node", n.Name,
+			"host", host,
+		)
+	}
+
+	net.logger.Info("deployment bundles written",
+		"dir", dir,
+		"num_hosts", len(bundles),
+	)
+
+	return nil
+}
+
+// deploymentBundle is the deployment bundle for a single host.
+type deploymentBundle struct {
+	dir       string
+	remoteDir string
+	baseDir   string
+
+	// files maps paths of files copied from outside the network directory relative to the bundle
+	// directory to their source paths.
+	files map[string]string
+}
+
+func (b *deploymentBundle) addNode(name, nodeBinary string, args []string) error {
+	binary, err := b.addFile(nodeBinary)
+	if err != nil {
+		return err
+	}
+
+	cmd := []string{binary}
+	for _, arg := range args {
+		if arg, err = b.rewriteArg(arg); err != nil {
+			return err
+		}
+		cmd = append(cmd, arg)
+	}
+
+	unitDir := filepath.Join(b.dir, deploymentSystemdDir)
+	if err = os.MkdirAll(unitDir, 0o700); err != nil {
+		return err
+	}
+	unit := systemdUnit(name, b.remoteDir, cmd)
+	return ioutil.WriteFile(filepath.Join(unitDir, "oasis-"+name+".service"), []byte(unit), 0o600)
+}
+
+// rewriteArg rewrites any local path in the given argument to the corresponding path on the host
+// and copies the referenced files into the bundle.
+//
+// Paths may either be the whole argument or follow a prefix ending with '=' or ':' (e.g., runtime
+// paths and unix socket addresses).
+func (b *deploymentBundle) rewriteArg(arg string) (string, error) {
+	idx := strings.IndexByte(arg, '/')
+	switch {
+	case idx < 0:
+		return arg, nil
+	case idx > 0 && arg[idx-1] != '=' && arg[idx-1] != ':':
+		return arg, nil
+	}
+	prefix, path := arg[:idx], arg[idx:]
+
+	// Paths within the network directory are copied into the same location in the bundle.
+	if rel, err := filepath.Rel(b.baseDir, path); err == nil && rel != ".." && !strings.HasPrefix(rel, "../") {
+		if err = copyPath(path, filepath.Join(b.dir, deploymentNetworkDir, rel)); err != nil {
+			return "", err
+		}
+		return prefix + filepath.Join(b.remoteDir, deploymentNetworkDir, rel), nil
+	}
+
+	// Other existing files (e.g., runtime binaries or an existing genesis file) are copied into
+	// the bundle's binary or configuration directory.
+	fi, err := os.Stat(path)
+	if err != nil || !fi.Mode().IsRegular() {
+		return arg, nil
+	}
+	file, err := b.addFile(path)
+	if err != nil {
+		return "", err
+	}
+	return prefix + file, nil
+}
+
+// addFile copies the given file into the bundle and returns its path on the host. Executable
+// files are copied into the binary directory and other files into the configuration directory.
+func (b *deploymentBundle) addFile(path string) (string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	subDir := deploymentEtcDir
+	if fi.Mode().Perm()&0o111 != 0 {
+		subDir = deploymentBinDir
+	}
+	name := filepath.Join(subDir, filepath.Base(path))
+
+	if src, ok := b.files[name]; ok {
+		if src != path {
+			return "", fmt.Errorf("conflicting files named %s: %s and %s", name, src, path)
+		}
+	} else {
+		if err = copyPath(path, filepath.Join(b.dir, name)); err != nil {
+			return "", err
+		}
+		b.files[name] = path
+	}
+	return filepath.Join(b.remoteDir, name), nil
+}
+
+// systemdUnit returns a systemd unit running the given command.
+func systemdUnit(name, workDir string, cmd []string) string {
+	quoted := make([]string, 0, len(cmd))
+	for _, arg := range cmd {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'\\;") {
+			arg = strconv.Quote(arg)
+		}
+		quoted = append(quoted, strings.ReplaceAll(arg, "%", "%%"))
+	}
+
+	return fmt.Sprintf(systemdUnitTemplate, name, workDir, strings.Join(quoted, " "), cmdCommon.RequiredRlimit)
+}
+
+// copyPath recursively copies the src file or directory to dst preserving permissions. A missing
+// src is ignored and anything other than directories and regular files (e.g., sockets) is skipped.
+func copyPath(src, dst string) error {
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+		return err
+	}
+
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			if err = os.MkdirAll(target, info.Mode().Perm()); err != nil {
+				return err
+			}
+			// Make sure permissions are correct even if the directory already existed.
+			return os.Chmod(target, info.Mode().Perm())
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			return nil
+		}
+	})
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,71 @@
+package oasis
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentBundle(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "oasis-test-runner-deployment")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	baseDir := filepath.Join(dir, "network")
+	nodeDir := filepath.Join(baseDir, "validator-0")
+	require.NoError(os.MkdirAll(nodeDir, 0o700), "MkdirAll")
+	require.NoError(ioutil.WriteFile(filepath.Join(nodeDir, "identity.pem"), []byte("key"), 0o600), "WriteFile")
+	require.NoError(ioutil.WriteFile(filepath.Join(baseDir, "genesis.json"), []byte("{}"), 0o600), "WriteFile")
+	binary := filepath.Join(dir, "runtime")
+	require.NoError(ioutil.WriteFile(binary, []byte("binary"), 0o700), "WriteFile")
+
+	b := &deploymentBundle{
+		dir:       filepath.Join(dir, "bundle"),
+		remoteDir: "/srv/oasis",
+		baseDir:   baseDir,
+		files:     make(map[string]string),
+	}
+
+	for _, tc := range []struct {
+		arg      string
+		expected string
+	}{
+		{"--datadir", "--datadir"},
+		{"tcp://0.0.0.0:20000", "tcp://0.0.0.0:20000"},
+		{nodeDir, "/srv/oasis/network/validator-0"},
+		{filepath.Join(nodeDir, "node.log"), "/srv/oasis/network/validator-0/node.log"},
+		{filepath.Join(baseDir, "genesis.json"), "/srv/oasis/network/genesis.json"},
+		{"unix:" + filepath.Join(nodeDir, "internal.sock"), "unix:/srv/oasis/network/validator-0/internal.sock"},
+		{"8000000000000000000000000000000000000000000000000000000000000000=" + binary, "8000000000000000000000000000000000000000000000000000000000000000=/srv/oasis/bin/runtime"},
+		{filepath.Join(dir, "missing"), filepath.Join(dir, "missing")},
+	} {
+		arg, rerr := b.rewriteArg(tc.arg)
+		require.NoError(rerr, "rewriteArg(%s)", tc.arg)
+		require.Equal(tc.expected, arg, "rewriteArg(%s)", tc.arg)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(b.dir, "network", "validator-0", "identity.pem"))
+	require.NoError(err, "node data directory should be copied")
+	require.Equal("key", string(data))
+	_, err = os.Stat(filepath.Join(b.dir, "network", "genesis.json"))
+	require.NoError(err, "genesis file should be copied")
+	fi, err := os.Stat(filepath.Join(b.dir, "bin", "runtime"))
+	require.NoError(err, "runtime binary should be copied")
+	require.EqualValues(0o700, fi.Mode().Perm(), "runtime binary should remain executable")
+
+	// Different files with the same name should be rejected.
+	otherBinary := filepath.Join(dir, "other", "runtime")
+	require.NoError(os.MkdirAll(filepath.Dir(otherBinary), 0o700), "MkdirAll")
+	require.NoError(ioutil.WriteFile(otherBinary, []byte("other"), 0o700), "WriteFile")
+	_, err = b.rewriteArg(otherBinary)
+	require.Error(err, "conflicting files should be rejected")
+
+	unit := systemdUnit("validator-0", "/srv/oasis", []string{"/srv/oasis/bin/oasis-node", "--chain.id", "test: 100%"})
+	require.True(strings.Contains(unit, `ExecStart=/srv/oasis/bin/oasis-node --chain.id "test: 100%%"`), "arguments should be quoted")
+}
@@ -151,11 +151,16 @@ func (net *Network) NewEntity(cfg *EntityCfg) (*Entity, error) {
 		switch {
 		case cfg.Restore:
 			// Restore an existing entity.
-		case net.cfg.DeterministicIdentities:
-			// Generate a deterministic entity.
+		case net.cfg.DeterministicIdentities, net.cfg.Seed != "":
+			// Generate a deterministic entity, derived from the network seed unless deterministic
+			// identities are used.
+			seed := fmt.Sprintf(entityIdentitySeedTemplate, len(net.entities))
+			if !net.cfg.DeterministicIdentities {
+				seed = net.seededIdentitySeed(seed)
+			}
 			err = net.generateDeterministicIdentity(
 				entityDir,
-				fmt.Sprintf(entityIdentitySeedTemplate, len(net.entities)),
+				seed,
 				[]signature.SignerRole{signature.SignerEntity},
 			)
 			if err != nil {
@@ -28,6 +28,7 @@ type NetworkFixture struct {
 	KeymanagerPolicies []KeymanagerPolicyFixture `json:"keymanager_policies,omitempty"`
 	StorageWorkers     []StorageWorkerFixture    `json:"storage_workers,omitempty"`
 	ComputeWorkers     []ComputeWorkerFixture    `json:"compute_workers,omitempty"`
+	RuntimeUpgrades    []RuntimeUpgradeFixture   `json:"runtime_upgrades,omitempty"`
 	Sentries           []SentryFixture           `json:"sentries,omitempty"`
 	Clients            []ClientFixture           `json:"clients,omitempty"`
 	Seeds              []SeedFixture             `json:"seeds,omitempty"`
@@ -113,6 +114,13 @@ func (f *NetworkFixture) Create(env *env.Env) (*Network, error) {
 		}
 	}
 
+	// Provision runtime upgrades.
+	for _, fx := range f.RuntimeUpgrades {
+		if _, err = fx.Create(net); err != nil {
+			return nil, err
+		}
+	}
+
 	// Provision the client nodes.
 	for _, fx := range f.Clients {
 		if _, err = fx.Create(net); err != nil {
@@ -309,6 +317,38 @@ func (f *KeymanagerPolicyFixture) Create(net *Network) (*KeymanagerPolicy, error
 	})
 }
 
+// RuntimeUpgradeFixture is a runtime upgrade fixture.
+type RuntimeUpgradeFixture struct {
+	// Runtime is the index of the runtime containing the new runtime version. It must have the
+	// same ID as the runtime being upgraded and should be excluded from genesis.
+	Runtime int `json:"runtime"`
+
+	// Epoch is the epoch at which the new runtime version is registered and rolled out.
+	Epoch beacon.EpochTime `json:"epoch"`
+
+	// ComputeWorkers are the indices of the compute workers to roll out the new runtime bundle
+	// to. If empty, it is rolled out to all compute workers hosting the runtime.
+	ComputeWorkers []int `json:"compute_workers,omitempty"`
+}
+
+// Create instantiates the runtime upgrade described by the fixture.
+func (f *RuntimeUpgradeFixture) Create(net *Network) (*RuntimeUpgrade, error) {
+	runtime, err := resolveRuntimeOfKind(net, f.Runtime, registry.KindCompute)
+	if err != nil {
+		return nil, err
+	}
+	computeWorkers, err := resolveComputeWorkers(net, f.ComputeWorkers)
+	if err != nil {
+		return nil, err
+	}
+
+	return net.NewRuntimeUpgrade(&RuntimeUpgradeCfg{
+		Runtime:        runtime,
+		Epoch:          f.Epoch,
+		ComputeWorkers: computeWorkers,
+	})
+}
+
 // KeymanagerFixture is a key manager fixture.
 type KeymanagerFixture struct {
 	NodeFixture
@@ -668,6 +708,18 @@ func resolveStorageWorkers(net *Network, indices []int) ([]*Storage, error) {
 	return storageWorkers, nil
 }
 
+func resolveComputeWorkers(net *Network, indices []int) ([]*Compute, error) {
+	allComputeWorkers := net.ComputeWorkers()
+	var computeWorkers []*Compute
+	for _, index := range indices {
+		if index < 0 || index >= len(allComputeWorkers) {
+			return nil, fmt.Errorf("invalid compute index: %d", index)
+		}
+		computeWorkers = append(computeWorkers, allComputeWorkers[index])
+	}
+	return computeWorkers, nil
+}
+
 func resolveKeymanagerWorkers(net *Network, indices []int) ([]*Keymanager, error) {
 	allKeymanagerWorkers := net.Keymanagers()
 	var keymanagerWorkers []*Keymanager
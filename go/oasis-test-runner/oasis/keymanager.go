@@ -266,7 +266,7 @@ func (km *Keymanager) AddArgs(args *argBuilder) error {
 		debugSetRlimit().
 		debugEnableProfiling(km.Node.pprofPort).
 		workerCertificateRotation(true).
-		tendermintCoreAddress(km.consensusPort).
+		tendermintCoreAddress(km.hostAddress(), km.consensusPort).
 		tendermintSubmissionGasPrice(km.consensus.SubmissionGasPrice).
 		tendermintPrune(km.consensus.PruneNumKept).
 		tendermintRecoverCorruptedWAL(km.consensus.TendermintRecoverCorruptedWAL).
@@ -7,6 +7,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/log"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+	storageClient "github.com/oasisprotocol/oasis-core/go/storage/client"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 	workerStorage "github.com/oasisprotocol/oasis-core/go/worker/storage/committee"
 )
@@ -107,6 +108,18 @@ func LogEventABCIStateSyncComplete() log.WatcherHandlerFactory {
 	return LogAssertEvent(abci.LogEventABCIStateSyncComplete, "expected ABCI state sync to complete")
 }
 
+// LogAssertNoABCIStateSyncComplete returns a handler which checks that no ABCI state sync
+// completion was detected based on JSON log output.
+func LogAssertNoABCIStateSyncComplete() log.WatcherHandlerFactory {
+	return LogAssertNotEvent(abci.LogEventABCIStateSyncComplete, "ABCI state sync completed")
+}
+
+// LogAssertStateSyncProviderFailed returns a handler which checks whether creating the state sync
+// state provider failed based on JSON log output.
+func LogAssertStateSyncProviderFailed() log.WatcherHandlerFactory {
+	return LogAssertEvent(tendermint.LogEventStateSyncProviderFailed, "expected state sync state provider to fail")
+}
+
 // LogAssertRoothashRoothashReindexing returns a handler which checks whether roothash reindexing was
 // run based on JSON log output.
 func LogAssertRoothashRoothashReindexing() log.WatcherHandlerFactory {
@@ -125,3 +138,9 @@ func LogAssertDiscrepancyMajorityFailure() log.WatcherHandlerFactory {
 	return LogAssertEvent(commitment.LogEventDiscrepancyMajorityFailure,
 		"discrepancy resolution majority failure not detected")
 }
+
+// LogAssertStorageInvalidReceipt returns a handler which checks whether an invalid storage receipt
+// was rejected by the storage client based on JSON log output.
+func LogAssertStorageInvalidReceipt() log.WatcherHandlerFactory {
+	return LogAssertEvent(storageClient.LogEventInvalidReceipt, "invalid storage receipt not detected")
+}
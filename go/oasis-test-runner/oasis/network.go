@@ -1,6 +1,7 @@
 package oasis
 
 import (
+	"context"
 	"crypto"
 	"crypto/x509"
 	"encoding/json"
@@ -8,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
@@ -63,12 +65,15 @@ type Network struct { // nolint: maligned
 	seeds          []*Seed
 
 	keymanagerPolicies []*KeymanagerPolicy
+	runtimeUpgrades    []*RuntimeUpgrade
 
 	iasProxy *iasProxy
 
 	cfg          *NetworkCfg
 	nextNodePort uint16
 
+	chaos *Chaos
+
 	logWatchers []*log.Watcher
 
 	controller       *Controller
@@ -112,6 +117,11 @@ type NetworkCfg struct { // nolint: maligned
 	// RestoreIdentities is the restore identities flag.
 	RestoreIdentities bool `json:"restore_identities"`
 
+	// Seed is the seed from which node and entity identities are derived unless deterministic
+	// identities are used. If not set, the scenario seed is used when available and identities
+	// are generated randomly otherwise.
+	Seed string `json:"seed,omitempty"`
+
 	// FundEntities is the fund entities flag.
 	FundEntities bool `json:"fund_entities"`
 
@@ -128,6 +138,12 @@ type NetworkCfg struct { // nolint: maligned
 	// SchedulerWeakAlpkaOk is for disabling the VRF alpha entropy requirement.
 	SchedulerWeakAlphaOk bool `json:"scheduler_weak_alpha_ok,omitempty"`
 
+	// Chaos is the optional network chaos injection configuration.
+	Chaos *ChaosCfg `json:"chaos,omitempty"`
+
+	// Deployment is the optional multi-host deployment configuration.
+	Deployment *DeploymentCfg `json:"deployment,omitempty"`
+
 	// SchedulerForceElect are the rigged committee elections.
 	SchedulerForceElect map[common.Namespace]map[signature.PublicKey]*scheduler.ForceElectCommitteeRole `json:"scheduler_force_elect,omitempty"`
 
@@ -173,6 +189,11 @@ func (net *Network) Entities() []*Entity {
 	return net.entities
 }
 
+// Chaos returns the network chaos injector or nil if chaos injection is not enabled.
+func (net *Network) Chaos() *Chaos {
+	return net.chaos
+}
+
 // Validators returns the validators associated with the network.
 func (net *Network) Validators() []*Validator {
 	return net.validators
@@ -183,6 +204,11 @@ func (net *Network) Runtimes() []*Runtime {
 	return net.runtimes
 }
 
+// RuntimeUpgrades returns the runtime upgrades orchestrated by the network.
+func (net *Network) RuntimeUpgrades() []*RuntimeUpgrade {
+	return net.runtimeUpgrades
+}
+
 // Seeds returns the seed node associated with the network.
 func (net *Network) Seeds() []*Seed {
 	return net.seeds
@@ -385,33 +411,8 @@ func (net *Network) Start() error { // nolint: gocyclo
 
 	net.logger.Info("starting network")
 
-	// Figure out if the IAS proxy is needed by peeking at all the
-	// runtimes.
-	for _, v := range net.Runtimes() {
-		needIASProxy := v.teeHardware == node.TEEHardwareIntelSGX
-		if needIASProxy {
-			if _, err := net.newIASProxy(); err != nil {
-				net.logger.Error("failed to provision IAS proxy",
-					"err", err,
-				)
-				return err
-			}
-			break
-		}
-	}
-
-	if net.cfg.GenesisFile == "" {
-		net.logger.Debug("provisioning genesis doc")
-		if err := net.MakeGenesis(); err != nil {
-			net.logger.Error("failed to create genesis document",
-				"err", err,
-			)
-			return err
-		}
-	} else {
-		net.logger.Debug("using existing genesis doc",
-			"path", net.cfg.GenesisFile,
-		)
+	if err := net.provision(); err != nil {
+		return err
 	}
 
 	// Retrieve the genesis document and use it to configure the context for
@@ -508,12 +509,57 @@ func (net *Network) Start() error { // nolint: gocyclo
 		break
 	}
 
+	if net.chaos != nil {
+		net.chaos.start()
+	}
+
+	if len(net.runtimeUpgrades) > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		net.env.AddOnCleanup(func() { cancel() })
+		for _, u := range net.runtimeUpgrades {
+			u.start(ctx)
+		}
+	}
+
 	net.logger.Info("network started")
 	net.running = true
 
 	return nil
 }
 
+// provision provisions the IAS proxy (if needed) and the genesis document.
+func (net *Network) provision() error {
+	// Figure out if the IAS proxy is needed by peeking at all the
+	// runtimes.
+	for _, v := range net.Runtimes() {
+		needIASProxy := v.teeHardware == node.TEEHardwareIntelSGX
+		if needIASProxy {
+			if _, err := net.newIASProxy(); err != nil {
+				net.logger.Error("failed to provision IAS proxy",
+					"err", err,
+				)
+				return err
+			}
+			break
+		}
+	}
+
+	if net.cfg.GenesisFile == "" {
+		net.logger.Debug("provisioning genesis doc")
+		if err := net.MakeGenesis(); err != nil {
+			net.logger.Error("failed to create genesis document",
+				"err", err,
+			)
+			return err
+		}
+	} else {
+		net.logger.Debug("using existing genesis doc",
+			"path", net.cfg.GenesisFile,
+		)
+	}
+	return nil
+}
+
 // Stop stops the network.
 func (net *Network) Stop() {
 	net.env.Cleanup()
@@ -545,6 +591,11 @@ func (net *Network) generateDeterministicNodeIdentity(dir *env.Dir, rawSeed stri
 	return net.generateDeterministicIdentity(dir, rawSeed, identity.RequiredSignerRoles)
 }
 
+// seededIdentitySeed returns the identity seed derived from the network seed.
+func (net *Network) seededIdentitySeed(seed string) string {
+	return net.cfg.Seed + "/" + seed
+}
+
 // GenerateDeterministicNodeKeys generates and returns deterministic node keys.
 func GenerateDeterministicNodeKeys(dir *env.Dir, rawSeed string, roles []signature.SignerRole) ([]signature.PublicKey, error) {
 	h := crypto.SHA512.New()
@@ -594,17 +645,8 @@ func (net *Network) generateTempSocketPath() string {
 	return f.Name()
 }
 
-func (net *Network) startOasisNode(
-	node *Node,
-	subCmd []string,
-	extraArgs *argBuilder,
-) error {
-	node.Lock()
-	defer node.Unlock()
-
-	// Make a deep copy as we will be modifying the arguments.
-	initialExtraArgs := extraArgs.clone()
-
+// nodeArgs returns the node binary arguments for the given node.
+func (net *Network) nodeArgs(node *Node, subCmd []string, extraArgs *argBuilder) ([]string, error) {
 	baseArgs := []string{
 		"--" + cmdCommon.CfgDataDir, node.dir.String(),
 		"--log.level", "debug",
@@ -618,6 +660,12 @@ func (net *Network) startOasisNode(
 			tendermintDebugAddrBookLenient().
 			tendermintDebugAllowDuplicateIP().
 			tendermintUpgradeStopDelay(10 * time.Second)
+
+		if net.chaos != nil {
+			if err := net.chaos.proxyNode(node, extraArgs); err != nil {
+				return nil, fmt.Errorf("oasis: failed to proxy node connections: %w", err)
+			}
+		}
 	}
 	if net.cfg.UseShortGrpcSocketPaths {
 		// Keep the socket, if it was already generated!
@@ -641,6 +689,25 @@ func (net *Network) startOasisNode(
 	args = append(args, baseArgs...)
 	args = append(args, extraArgs.merge(node.dir.String())...)
 
+	return args, nil
+}
+
+func (net *Network) startOasisNode(
+	node *Node,
+	subCmd []string,
+	extraArgs *argBuilder,
+) error {
+	node.Lock()
+	defer node.Unlock()
+
+	// Make a deep copy as we will be modifying the arguments.
+	initialExtraArgs := extraArgs.clone()
+
+	args, err := net.nodeArgs(node, subCmd, extraArgs)
+	if err != nil {
+		return err
+	}
+
 	w, err := node.dir.NewLogWriter(logConsoleFile)
 	if err != nil {
 		return err
@@ -672,6 +739,7 @@ func (net *Network) startOasisNode(
 		net.logger.Debug("node terminated",
 			"err", cmdErr,
 		)
+		node.recordProcessUsage(cmd.ProcessState)
 
 		if cmdErr != nil {
 			exitCh <- cmdErr
@@ -873,10 +941,21 @@ func (net *Network) GetCLIConfig() cli.Config {
 }
 
 func (net *Network) provisionNodeIdentity(dataDir *env.Dir, seed string, persistTLS bool) (signature.PublicKey, signature.PublicKey, *x509.Certificate, error) {
-	if net.cfg.DeterministicIdentities && !net.cfg.RestoreIdentities {
+	switch {
+	case net.cfg.RestoreIdentities:
+	case net.cfg.DeterministicIdentities:
 		if err := net.generateDeterministicNodeIdentity(dataDir, seed); err != nil {
 			return signature.PublicKey{}, signature.PublicKey{}, nil, fmt.Errorf("oasis: failed to generate deterministic identity: %w", err)
 		}
+	case net.cfg.Seed != "":
+		// Derive the identity from the network seed, unless the node has already been provisioned
+		// (e.g., when the network is re-created using the same data directories).
+		if _, err := os.Stat(filepath.Join(dataDir.String(), fileSigner.FileIdentityKey)); err == nil {
+			break
+		}
+		if err := net.generateDeterministicNodeIdentity(dataDir, net.seededIdentitySeed(seed)); err != nil {
+			return signature.PublicKey{}, signature.PublicKey{}, nil, fmt.Errorf("oasis: failed to generate seeded identity: %w", err)
+		}
 	}
 
 	signerFactory, err := fileSigner.NewFactory(dataDir.String(), identity.RequiredSignerRoles...)
@@ -943,6 +1022,9 @@ func New(env *env.Env, cfg *NetworkCfg) (*Network, error) {
 	if cfgCopy.HaltEpoch == 0 {
 		cfgCopy.HaltEpoch = defaultHaltEpoch
 	}
+	if cfgCopy.Seed == "" && env.ScenarioInfo() != nil {
+		cfgCopy.Seed = env.ScenarioInfo().Seed
+	}
 
 	net := &Network{
 		logger:       logging.GetLogger("oasis/" + env.Name()),
@@ -953,6 +1035,24 @@ func New(env *env.Env, cfg *NetworkCfg) (*Network, error) {
 		errCh:        make(chan error, maxNodes),
 	}
 
+	if cfgCopy.Deployment != nil {
+		if cfgCopy.Chaos != nil {
+			return nil, fmt.Errorf("oasis: network chaos injection is not supported with deployment")
+		}
+		if err = cfgCopy.Deployment.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfgCopy.Chaos != nil {
+		if net.chaos, err = newChaos(net, cfgCopy.Chaos); err != nil {
+			return nil, err
+		}
+	}
+
+	// Record resource usage of all nodes once they are stopped.
+	env.AddOnCleanup(net.recordResourceUsage)
+
 	// Pre-provision node objects if they were listed in the top-level network fixture.
 	for _, nodeName := range cfg.Nodes {
 		_, err = net.GetNamedNode(nodeName, nil)
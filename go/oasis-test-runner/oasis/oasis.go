@@ -93,6 +93,10 @@ type Node struct { // nolint: maligned
 
 	exitCh chan error
 
+	cpuTime         time.Duration
+	reportedCPUTime time.Duration
+	maxRSS          uint64
+
 	termEarlyOk bool
 	termErrorOk bool
 	isStopping  bool
@@ -182,17 +186,18 @@ func (n *Node) LoadIdentity() (*identity.Identity, error) {
 	return identity.Load(n.dir.String(), factory)
 }
 
-// Start starts the node.
-func (n *Node) Start() error {
+// buildArgs builds the arguments of all the node's features and returns the feature with
+// customized startup, if any.
+func (n *Node) buildArgs() (*argBuilder, CustomStartFeature, error) {
 	args := newArgBuilder()
 	var customStart CustomStartFeature
 	for _, f := range n.features {
 		if err := f.AddArgs(args); err != nil {
-			return fmt.Errorf("oasis/node: failed to add arguments for feature on node %s: %w", n.Name, err)
+			return nil, nil, fmt.Errorf("oasis/node: failed to add arguments for feature on node %s: %w", n.Name, err)
 		}
 		if cf, ok := f.(CustomStartFeature); ok {
 			if customStart != nil {
-				return fmt.Errorf("oasis/node: multiple features with customized startup on node %s", n.Name)
+				return nil, nil, fmt.Errorf("oasis/node: multiple features with customized startup on node %s", n.Name)
 			}
 			customStart = cf
 		}
@@ -203,11 +208,21 @@ func (n *Node) Start() error {
 
 	args.extraArgs(n.extraArgs)
 
+	return args, customStart, nil
+}
+
+// Start starts the node.
+func (n *Node) Start() error {
+	args, customStart, err := n.buildArgs()
+	if err != nil {
+		return err
+	}
+
 	if customStart != nil {
 		return customStart.CustomStart(args)
 	}
 
-	if err := n.net.startOasisNode(n, nil, args); err != nil {
+	if err = n.net.startOasisNode(n, nil, args); err != nil {
 		return fmt.Errorf("oasis/node: failed to launch node %s: %w", n.Name, err)
 	}
 
@@ -267,6 +282,16 @@ func (n *Node) BinaryPath() string {
 	return fmt.Sprintf("/proc/%d/exe", n.cmd.Process.Pid)
 }
 
+func (n *Node) pid() int {
+	n.Lock()
+	defer n.Unlock()
+
+	if n.cmd == nil || n.cmd.Process == nil {
+		return 0
+	}
+	return n.cmd.Process.Pid
+}
+
 // WaitReady is a helper for creating a controller and calling node's WaitReady.
 func (n *Node) WaitReady(ctx context.Context) error {
 	nodeCtrl, err := NewController(n.SocketPath())
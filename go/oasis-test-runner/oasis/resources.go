@@ -0,0 +1,317 @@
+package oasis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/abci"
+	tmcommon "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/common"
+)
+
+// ResourceUsageFile is the name of the file in the scenario environment directory which contains
+// the resource usage of all of the network nodes.
+const ResourceUsageFile = "resource_usage.json"
+
+// NodeResourceUsage is the resource usage of a single node.
+type NodeResourceUsage struct {
+	// CPUTime is the total (user and system) CPU time used by the node processes.
+	CPUTime time.Duration `json:"cpu_time"`
+
+	// MaxRSS is the maximum resident set size of the node processes in bytes.
+	MaxRSS uint64 `json:"max_rss"`
+
+	// DiskUsage is the size of the node's data directory in bytes.
+	DiskUsage uint64 `json:"disk_usage"`
+
+	// ConsensusStateSize is the size of the node's consensus application state in bytes.
+	ConsensusStateSize uint64 `json:"consensus_state_size"`
+}
+
+// ResourceReport is a resource usage report of a set of scenarios.
+type ResourceReport struct {
+	// Scenarios is the per-node resource usage of each of the scenarios, indexed by scenario name
+	// and node name.
+	Scenarios map[string]map[string]*NodeResourceUsage `json:"scenarios"`
+}
+
+// AddScenario adds the per-node resource usage of a scenario to the report.
+func (r *ResourceReport) AddScenario(name string, usage map[string]*NodeResourceUsage) {
+	if r.Scenarios == nil {
+		r.Scenarios = make(map[string]map[string]*NodeResourceUsage)
+	}
+	r.Scenarios[name] = usage
+}
+
+// Save writes the report to the given file.
+func (r *ResourceReport) Save(path string) error {
+	return writeJSONFile(path, r)
+}
+
+// ResourceThresholds are the maximum allowed ratios between the current and the baseline
+// resource usage. A zero threshold disables checking of the given resource.
+type ResourceThresholds struct {
+	CPUTime            float64
+	MaxRSS             float64
+	DiskUsage          float64
+	ConsensusStateSize float64
+}
+
+// ResourceRegression is a resource usage regression of a node in a scenario.
+type ResourceRegression struct {
+	Scenario string
+	Node     string
+	Resource string
+
+	Baseline  uint64
+	Current   uint64
+	Threshold float64
+}
+
+// Ratio returns the ratio between the current and the baseline resource usage.
+func (r ResourceRegression) Ratio() float64 {
+	return float64(r.Current) / float64(r.Baseline)
+}
+
+// String returns a string representation of the regression.
+func (r ResourceRegression) String() string {
+	return fmt.Sprintf("%s/%s: %s regressed (baseline: %d current: %d ratio: %.3f threshold: %.3f)",
+		r.Scenario, r.Node, r.Resource, r.Baseline, r.Current, r.Ratio(), r.Threshold,
+	)
+}
+
+// Compare compares the report against a baseline report and returns all resource usage
+// regressions exceeding the given thresholds.
+//
+// Only scenarios and nodes present in both reports are compared.
+func (r *ResourceReport) Compare(baseline *ResourceReport, thresholds *ResourceThresholds) []ResourceRegression {
+	scNames := make([]string, 0, len(r.Scenarios))
+	for scName := range r.Scenarios {
+		scNames = append(scNames, scName)
+	}
+	sort.Strings(scNames)
+
+	var regressions []ResourceRegression
+	for _, scName := range scNames {
+		baseNodes, ok := baseline.Scenarios[scName]
+		if !ok {
+			continue
+		}
+		nodes := r.Scenarios[scName]
+		nodeNames := make([]string, 0, len(nodes))
+		for nodeName := range nodes {
+			nodeNames = append(nodeNames, nodeName)
+		}
+		sort.Strings(nodeNames)
+
+		for _, nodeName := range nodeNames {
+			base, ok := baseNodes[nodeName]
+			if !ok {
+				continue
+			}
+			cur := nodes[nodeName]
+
+			for _, res := range []struct {
+				name      string
+				baseline  uint64
+				current   uint64
+				threshold float64
+			}{
+				{"cpu_time", uint64(base.CPUTime), uint64(cur.CPUTime), thresholds.CPUTime},
+				{"max_rss", base.MaxRSS, cur.MaxRSS, thresholds.MaxRSS},
+				{"disk_usage", base.DiskUsage, cur.DiskUsage, thresholds.DiskUsage},
+				{"consensus_state_size", base.ConsensusStateSize, cur.ConsensusStateSize, thresholds.ConsensusStateSize},
+			} {
+				if res.threshold == 0 || res.baseline == 0 {
+					continue
+				}
+				if float64(res.current) <= float64(res.baseline)*res.threshold {
+					continue
+				}
+				regressions = append(regressions, ResourceRegression{
+					Scenario:  scName,
+					Node:      nodeName,
+					Resource:  res.name,
+					Baseline:  res.baseline,
+					Current:   res.current,
+					Threshold: res.threshold,
+				})
+			}
+		}
+	}
+	return regressions
+}
+
+// LoadResourceReport loads a resource usage report from the given file.
+func LoadResourceReport(path string) (*ResourceReport, error) {
+	var r ResourceReport
+	if err := readJSONFile(path, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// LoadResourceUsage loads the per-node resource usage recorded in the given scenario environment
+// directory. In case no resource usage has been recorded, nil is returned.
+func LoadResourceUsage(dir string) (map[string]*NodeResourceUsage, error) {
+	var usage map[string]*NodeResourceUsage
+	err := readJSONFile(filepath.Join(dir, ResourceUsageFile), &usage)
+	switch {
+	case err == nil:
+		return usage, nil
+	case errors.Is(err, os.ErrNotExist):
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// recordProcessUsage records the resource usage of an exited node process.
+func (n *Node) recordProcessUsage(state *os.ProcessState) {
+	if state == nil {
+		return
+	}
+
+	n.Lock()
+	defer n.Unlock()
+
+	n.cpuTime += state.UserTime() + state.SystemTime()
+	if ru, ok := state.SysUsage().(*syscall.Rusage); ok {
+		// Maxrss is in kilobytes.
+		if rss := uint64(ru.Maxrss) * 1024; rss > n.maxRSS {
+			n.maxRSS = rss
+		}
+	}
+}
+
+// resourceUsage returns the resource usage of the node since the last call.
+//
+// The CPU time only includes node processes that exited since the last call.
+func (n *Node) resourceUsage() (*NodeResourceUsage, error) {
+	// Wait for the exit of the last node process to be processed.
+	if exitCh := n.Exit(); exitCh != nil {
+		<-exitCh
+	}
+
+	n.Lock()
+	usage := &NodeResourceUsage{
+		CPUTime: n.cpuTime - n.reportedCPUTime,
+		MaxRSS:  n.maxRSS,
+	}
+	n.reportedCPUTime = n.cpuTime
+	n.Unlock()
+
+	var err error
+	if usage.DiskUsage, err = dirSize(n.dir.String()); err != nil {
+		return nil, fmt.Errorf("failed to compute disk usage: %w", err)
+	}
+	if usage.ConsensusStateSize, err = dirSize(filepath.Join(n.dir.String(), tmcommon.StateDir, abci.AppStateDir)); err != nil {
+		return nil, fmt.Errorf("failed to compute consensus state size: %w", err)
+	}
+	return usage, nil
+}
+
+// recordResourceUsage records the resource usage of all of the network nodes into the resource
+// usage file in the environment directory.
+//
+// The network may be stopped multiple times and there may be multiple networks in the same
+// environment so the resource usage is merged with any previously recorded resource usage.
+func (net *Network) recordResourceUsage() {
+	usage, err := LoadResourceUsage(net.env.Dir())
+	if err != nil {
+		net.logger.Error("failed to load recorded resource usage",
+			"err", err,
+		)
+		return
+	}
+	if usage == nil {
+		usage = make(map[string]*NodeResourceUsage)
+	}
+
+	for _, node := range net.nodes {
+		nodeUsage, err := node.resourceUsage()
+		if err != nil {
+			net.logger.Error("failed to get node resource usage",
+				"err", err,
+				"node", node.Name,
+			)
+			return
+		}
+
+		prev, ok := usage[node.Name]
+		if !ok {
+			usage[node.Name] = nodeUsage
+			continue
+		}
+		prev.CPUTime += nodeUsage.CPUTime
+		if nodeUsage.MaxRSS > prev.MaxRSS {
+			prev.MaxRSS = nodeUsage.MaxRSS
+		}
+		prev.DiskUsage = nodeUsage.DiskUsage
+		prev.ConsensusStateSize = nodeUsage.ConsensusStateSize
+	}
+
+	if err = writeJSONFile(filepath.Join(net.env.Dir(), ResourceUsageFile), usage); err != nil {
+		net.logger.Error("failed to record resource usage",
+			"err", err,
+		)
+	}
+}
+
+// dirSize returns the total disk space allocated to all files in the given directory. In case
+// the directory does not exist, zero is returned.
+//
+// Allocated space is used instead of file sizes as the databases preallocate large sparse files.
+func dirSize(dir string) (uint64, error) {
+	var size uint64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			// Files may be removed while walking the directory.
+			return nil
+		case err != nil:
+			return err
+		case !d.Type().IsRegular():
+			return nil
+		}
+
+		fi, err := d.Info()
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			return nil
+		case err != nil:
+			return err
+		}
+		if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+			// Blocks are always 512 bytes.
+			size += uint64(st.Blocks) * 512
+		} else {
+			size += uint64(fi.Size())
+		}
+		return nil
+	})
+	return size, err
+}
+
+func readJSONFile(path string, v interface{}) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func writeJSONFile(path string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0o600)
+}
@@ -0,0 +1,111 @@
+package oasis
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceReportCompare(t *testing.T) {
+	require := require.New(t)
+
+	baseline := &ResourceReport{}
+	baseline.AddScenario("sc-a", map[string]*NodeResourceUsage{
+		"validator-0": {
+			CPUTime:            10 * time.Second,
+			MaxRSS:             100,
+			DiskUsage:          1000,
+			ConsensusStateSize: 500,
+		},
+		"validator-1": {
+			CPUTime: 10 * time.Second,
+		},
+	})
+	baseline.AddScenario("sc-b", map[string]*NodeResourceUsage{
+		"validator-0": {
+			CPUTime: 10 * time.Second,
+		},
+	})
+
+	current := &ResourceReport{}
+	current.AddScenario("sc-a", map[string]*NodeResourceUsage{
+		"validator-0": {
+			CPUTime:            11 * time.Second, // Within threshold.
+			MaxRSS:             150,              // Exceeds threshold.
+			DiskUsage:          1000,
+			ConsensusStateSize: 600, // Exceeds threshold.
+		},
+		"validator-1": {
+			CPUTime: 20 * time.Second, // Exceeds threshold.
+			MaxRSS:  100,              // No baseline.
+		},
+		"validator-2": { // No baseline.
+			CPUTime: 20 * time.Second,
+		},
+	})
+	current.AddScenario("sc-c", map[string]*NodeResourceUsage{ // No baseline.
+		"validator-0": {
+			CPUTime: 20 * time.Second,
+		},
+	})
+
+	thresholds := &ResourceThresholds{
+		CPUTime:            1.2,
+		MaxRSS:             1.2,
+		DiskUsage:          1.2,
+		ConsensusStateSize: 1.1,
+	}
+	regressions := current.Compare(baseline, thresholds)
+	require.Len(regressions, 3, "all regressions should be detected")
+	require.Equal("sc-a", regressions[0].Scenario)
+	require.Equal("validator-0", regressions[0].Node)
+	require.Equal("max_rss", regressions[0].Resource)
+	require.EqualValues(1.5, regressions[0].Ratio())
+	require.Equal("consensus_state_size", regressions[1].Resource)
+	require.Equal("validator-1", regressions[2].Node)
+	require.Equal("cpu_time", regressions[2].Resource)
+
+	// Disabled thresholds should not be checked.
+	regressions = current.Compare(baseline, &ResourceThresholds{CPUTime: 1.2})
+	require.Len(regressions, 1, "only enabled thresholds should be checked")
+	require.Equal("cpu_time", regressions[0].Resource)
+
+	// Reports should survive a round trip.
+	dir, err := ioutil.TempDir("", "oasis-test-runner-resources")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "report.json")
+	require.NoError(current.Save(path), "Save")
+	loaded, err := LoadResourceReport(path)
+	require.NoError(err, "LoadResourceReport")
+	require.EqualValues(current, loaded, "loaded report should be equal")
+}
+
+func TestDirSize(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "oasis-test-runner-resources")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	require.NoError(os.MkdirAll(filepath.Join(dir, "a", "b"), 0o700), "MkdirAll")
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "a", "f1"), make([]byte, 8192), 0o600), "WriteFile")
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "a", "b", "f2"), make([]byte, 4096), 0o600), "WriteFile")
+
+	size, err := dirSize(dir)
+	require.NoError(err, "dirSize")
+	require.EqualValues(12288, size, "directory size should be correct")
+
+	size, err = dirSize(filepath.Join(dir, "missing"))
+	require.NoError(err, "dirSize of a missing directory")
+	require.EqualValues(0, size, "missing directory size should be zero")
+
+	usage, err := LoadResourceUsage(dir)
+	require.NoError(err, "LoadResourceUsage without recorded usage")
+	require.Nil(usage, "resource usage should be nil when not recorded")
+}
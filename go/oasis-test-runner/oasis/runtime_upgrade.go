@@ -0,0 +1,217 @@
+package oasis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// RuntimeUpgrade is a runtime upgrade orchestrated by the network.
+//
+// Once the configured epoch is reached, the descriptor of the new runtime version is registered
+// and the new runtime bundle is rolled out to the compute workers, one worker at a time.
+type RuntimeUpgrade struct {
+	sync.Mutex
+
+	net *Network
+
+	runtime        *Runtime
+	epoch          beacon.EpochTime
+	computeWorkers []*Compute
+
+	started bool
+	doneCh  chan struct{}
+	err     error
+}
+
+// RuntimeUpgradeCfg is the Oasis runtime upgrade configuration.
+type RuntimeUpgradeCfg struct {
+	// Runtime is the new runtime version. It must have the same ID as the runtime being upgraded
+	// and should be excluded from genesis.
+	Runtime *Runtime
+
+	// Epoch is the epoch at which the upgrade is performed.
+	Epoch beacon.EpochTime
+
+	// ComputeWorkers are the compute workers to roll out the new runtime bundle to. If empty,
+	// the new runtime bundle is rolled out to all compute workers hosting the runtime.
+	ComputeWorkers []*Compute
+}
+
+// Runtime returns the new runtime version.
+func (u *RuntimeUpgrade) Runtime() *Runtime {
+	return u.runtime
+}
+
+// Epoch returns the epoch at which the upgrade is performed.
+func (u *RuntimeUpgrade) Epoch() beacon.EpochTime {
+	return u.epoch
+}
+
+// Wait waits for the runtime upgrade to complete and returns the upgrade error, if any.
+func (u *RuntimeUpgrade) Wait(ctx context.Context) error {
+	select {
+	case <-u.doneCh:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	u.Lock()
+	defer u.Unlock()
+	return u.err
+}
+
+func (u *RuntimeUpgrade) start(ctx context.Context) {
+	u.Lock()
+	defer u.Unlock()
+
+	// The upgrade is only performed once, even if the network is restarted.
+	if u.started {
+		return
+	}
+	u.started = true
+
+	go func() {
+		err := u.run(ctx)
+
+		u.Lock()
+		u.err = err
+		u.Unlock()
+		close(u.doneCh)
+
+		if err != nil && ctx.Err() == nil {
+			u.net.errCh <- fmt.Errorf("oasis: runtime upgrade of %s failed: %w", u.runtime.ID(), err)
+		}
+	}()
+}
+
+func (u *RuntimeUpgrade) run(ctx context.Context) error {
+	logger := u.net.logger.With("runtime_id", u.runtime.ID(), "epoch", u.epoch)
+
+	logger.Info("waiting for runtime upgrade epoch")
+	if err := u.net.Controller().Beacon.WaitEpoch(ctx, u.epoch); err != nil {
+		return fmt.Errorf("failed to wait for epoch: %w", err)
+	}
+
+	logger.Info("registering new runtime version",
+		"version", u.runtime.descriptor.Version.Version,
+	)
+	if err := u.registerRuntime(ctx); err != nil {
+		return err
+	}
+
+	for _, worker := range u.rolloutWorkers() {
+		logger.Info("rolling out new runtime bundle",
+			"node", worker.Name,
+		)
+
+		if err := worker.Stop(); err != nil {
+			return fmt.Errorf("failed to stop compute worker %s: %w", worker.Name, err)
+		}
+		worker.replaceRuntime(u.runtime)
+		if err := worker.Start(); err != nil {
+			return fmt.Errorf("failed to start compute worker %s: %w", worker.Name, err)
+		}
+		if err := worker.WaitReady(ctx); err != nil {
+			return fmt.Errorf("failed to wait for compute worker %s: %w", worker.Name, err)
+		}
+	}
+
+	logger.Info("runtime upgrade completed")
+
+	return nil
+}
+
+// rolloutWorkers returns the compute workers the new runtime bundle should be rolled out to.
+func (u *RuntimeUpgrade) rolloutWorkers() []*Compute {
+	if len(u.computeWorkers) > 0 {
+		return u.computeWorkers
+	}
+
+	var workers []*Compute
+	for _, worker := range u.net.ComputeWorkers() {
+		if worker.noAutoStart || !worker.hostsRuntime(u.runtime.ID()) {
+			continue
+		}
+		workers = append(workers, worker)
+	}
+	return workers
+}
+
+func (u *RuntimeUpgrade) registerRuntime(ctx context.Context) error {
+	var entity *Entity
+	for _, ent := range u.net.Entities() {
+		if ent.ID().Equal(u.runtime.descriptor.EntityID) {
+			entity = ent
+			break
+		}
+	}
+	if entity == nil {
+		return fmt.Errorf("runtime entity %s not found", u.runtime.descriptor.EntityID)
+	}
+	signer := entity.Signer()
+
+	ctrl := u.net.Controller()
+	nonce, err := ctrl.Consensus.GetSignerNonce(ctx, &consensus.GetSignerNonceRequest{
+		AccountAddress: staking.NewAddress(signer.Public()),
+		Height:         consensus.HeightLatest,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query signer nonce: %w", err)
+	}
+
+	descriptor := u.runtime.ToRuntimeDescriptor()
+	tx := registry.NewRegisterRuntimeTx(nonce, &transaction.Fee{}, &descriptor)
+	gas, err := ctrl.Consensus.EstimateGas(ctx, &consensus.EstimateGasRequest{
+		Signer:      signer.Public(),
+		Transaction: tx,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	tx.Fee.Gas = gas
+
+	sigTx, err := transaction.Sign(signer, tx)
+	if err != nil {
+		return fmt.Errorf("failed to sign register runtime transaction: %w", err)
+	}
+	if err = ctrl.Consensus.SubmitTx(ctx, sigTx); err != nil {
+		return fmt.Errorf("failed to register runtime: %w", err)
+	}
+	return nil
+}
+
+// NewRuntimeUpgrade provisions a new runtime upgrade and adds it to the network.
+func (net *Network) NewRuntimeUpgrade(cfg *RuntimeUpgradeCfg) (*RuntimeUpgrade, error) {
+	if cfg.Runtime.kind != registry.KindCompute {
+		return nil, fmt.Errorf("oasis/runtime_upgrade: only compute runtimes can be upgraded")
+	}
+
+	var found bool
+	for _, rt := range net.runtimes {
+		if rt != cfg.Runtime && rt.ID() == cfg.Runtime.ID() {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("oasis/runtime_upgrade: no runtime to upgrade with ID %s", cfg.Runtime.ID())
+	}
+
+	upgrade := &RuntimeUpgrade{
+		net:            net,
+		runtime:        cfg.Runtime,
+		epoch:          cfg.Epoch,
+		computeWorkers: cfg.ComputeWorkers,
+		doneCh:         make(chan struct{}),
+	}
+	net.runtimeUpgrades = append(net.runtimeUpgrades, upgrade)
+
+	return upgrade, nil
+}
@@ -38,7 +38,7 @@ func (seed *Seed) AddArgs(args *argBuilder) error {
 		debugAllowTestKeys().
 		debugSetRlimit().
 		workerCertificateRotation(true).
-		tendermintCoreAddress(seed.consensusPort).
+		tendermintCoreAddress(seed.hostAddress(), seed.consensusPort).
 		appendSeedNodes(otherSeeds).
 		tendermintSeedMode()
 
@@ -46,12 +46,12 @@ func (sentry *Sentry) GetTLSPubKey() signature.PublicKey {
 
 // GetSentryAddress returns the sentry grpc endpoint address.
 func (sentry *Sentry) GetSentryAddress() string {
-	return fmt.Sprintf("127.0.0.1:%d", sentry.sentryPort)
+	return hostPort(sentry.hostAddress(), sentry.sentryPort)
 }
 
 // GetSentryControlAddress returns the sentry control endpoint address.
 func (sentry *Sentry) GetSentryControlAddress() string {
-	return fmt.Sprintf("127.0.0.1:%d", sentry.controlPort)
+	return hostPort(sentry.hostAddress(), sentry.controlPort)
 }
 
 func (sentry *Sentry) AddArgs(args *argBuilder) error {
@@ -77,7 +77,7 @@ func (sentry *Sentry) AddArgs(args *argBuilder) error {
 		workerCertificateRotation(false).
 		workerSentryEnabled().
 		workerSentryControlPort(sentry.controlPort).
-		tendermintCoreAddress(sentry.consensusPort).
+		tendermintCoreAddress(sentry.hostAddress(), sentry.consensusPort).
 		tendermintPrune(sentry.consensus.PruneNumKept).
 		tendermintRecoverCorruptedWAL(sentry.consensus.TendermintRecoverCorruptedWAL).
 		configureDebugCrashPoints(sentry.crashPointsProbability).
@@ -92,7 +92,7 @@ func (sentry *Sentry) AddArgs(args *argBuilder) error {
 
 	if len(storageWorkers) > 0 || len(keymanagerWorkers) > 0 {
 		args.workerGrpcSentryEnabled().
-			workerSentryGrpcClientAddress([]string{fmt.Sprintf("127.0.0.1:%d", sentry.sentryPort)}).
+			workerSentryGrpcClientAddress([]string{sentry.GetSentryAddress()}).
 			workerSentryGrpcClientPort(sentry.sentryPort)
 	}
 
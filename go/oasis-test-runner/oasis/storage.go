@@ -63,7 +63,7 @@ func (worker *Storage) IdentityKeyPath() string {
 
 // GetClientAddress returns the storage node endpoint address.
 func (worker *Storage) GetClientAddress() string {
-	return fmt.Sprintf("127.0.0.1:%d", worker.clientPort)
+	return hostPort(worker.hostAddress(), worker.clientPort)
 }
 
 // P2PKeyPath returns the path to the node's P2P key.
@@ -132,7 +132,7 @@ func (worker *Storage) AddArgs(args *argBuilder) error {
 		debugSetRlimit().
 		debugEnableProfiling(worker.Node.pprofPort).
 		workerCertificateRotation(!worker.disableCertRotation).
-		tendermintCoreAddress(worker.consensusPort).
+		tendermintCoreAddress(worker.hostAddress(), worker.consensusPort).
 		tendermintSubmissionGasPrice(worker.consensus.SubmissionGasPrice).
 		tendermintPrune(worker.consensus.PruneNumKept).
 		tendermintRecoverCorruptedWAL(worker.consensus.TendermintRecoverCorruptedWAL).
@@ -70,7 +70,7 @@ func (val *Validator) ExportsPath() string {
 
 // ExternalGRPCAddress returns the address of the node's external gRPC server.
 func (val *Validator) ExternalGRPCAddress() string {
-	return fmt.Sprintf("127.0.0.1:%d", val.clientPort)
+	return hostPort(val.hostAddress(), val.clientPort)
 }
 
 func (val *Validator) AddArgs(args *argBuilder) error {
@@ -80,7 +80,7 @@ func (val *Validator) AddArgs(args *argBuilder) error {
 		debugEnableProfiling(val.Node.pprofPort).
 		workerCertificateRotation(!val.disableCertRotation).
 		consensusValidator().
-		tendermintCoreAddress(val.consensusPort).
+		tendermintCoreAddress(val.hostAddress(), val.consensusPort).
 		tendermintMinGasPrice(val.consensus.MinGasPrice).
 		tendermintSubmissionGasPrice(val.consensus.SubmissionGasPrice).
 		tendermintPrune(val.consensus.PruneNumKept).
@@ -121,19 +121,18 @@ func (net *Network) NewValidator(cfg *ValidatorCfg) (*Validator, error) {
 	}
 
 	var consensusAddrs []interface{ String() string }
-	localhost := netPkg.ParseIP("127.0.0.1")
 	if len(val.sentries) > 0 {
 		for _, sentry := range val.sentries {
 			var consensusAddr node.ConsensusAddress
 			consensusAddr.ID = sentry.p2pPublicKey
-			if err = consensusAddr.Address.FromIP(localhost, sentry.consensusPort); err != nil {
+			if err = consensusAddr.Address.FromIP(netPkg.ParseIP(sentry.hostAddress()), sentry.consensusPort); err != nil {
 				return nil, fmt.Errorf("oasis/validator: failed to parse IP address: %w", err)
 			}
 			consensusAddrs = append(consensusAddrs, &consensusAddr)
 		}
 	} else {
 		var consensusAddr node.Address
-		if err = consensusAddr.FromIP(localhost, val.consensusPort); err != nil {
+		if err = consensusAddr.FromIP(netPkg.ParseIP(val.hostAddress()), val.consensusPort); err != nil {
 			return nil, fmt.Errorf("oasis/validator: failed to parse IP address: %w", err)
 		}
 		consensusAddrs = append(consensusAddrs, &consensusAddr)
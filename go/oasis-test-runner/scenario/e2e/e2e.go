@@ -428,6 +428,8 @@ func RegisterScenarios() error {
 		EarlyQueryInitHeight,
 		// Consensus state sync.
 		ConsensusStateSync,
+		// Long-range fork test.
+		LongRangeFork,
 		// Multiple seeds test.
 		MultipleSeeds,
 		// Seed API test.
@@ -0,0 +1,348 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	tmlight "github.com/tendermint/tendermint/light"
+
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	tendermint "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/light"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	genesisFile "github.com/oasisprotocol/oasis-core/go/genesis/file"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/env"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/log"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/scenario"
+)
+
+const (
+	// longRangeForkHeight is the height of the block from the abandoned chain that is used as the
+	// conflicting trust root.
+	longRangeForkHeight = 20
+
+	// longRangeForkTimeout is the time the nodes configured with a conflicting trust root have
+	// to terminate.
+	longRangeForkTimeout = 2 * time.Minute
+)
+
+// LongRangeFork is the long-range fork scenario.
+//
+// The scenario first runs the network for a while, then wipes all consensus state and restarts
+// the network from the same genesis document using the same validators. This results in a
+// conflicting chain signed by the same validator set. Afterwards, it makes sure that a light
+// client, a validator and a storage node which use a block from the abandoned chain as their
+// trust root refuse to follow the new chain.
+var LongRangeFork scenario.Scenario = &longRangeForkImpl{
+	E2E: *NewE2E("long-range-fork"),
+}
+
+type longRangeForkImpl struct {
+	E2E
+}
+
+func (sc *longRangeForkImpl) Clone() scenario.Scenario {
+	return &longRangeForkImpl{
+		E2E: sc.E2E.Clone(),
+	}
+}
+
+func (sc *longRangeForkImpl) Fixture() (*oasis.NetworkFixture, error) {
+	f, err := sc.E2E.Fixture()
+	if err != nil {
+		return nil, err
+	}
+
+	f.Network.SetInsecureBeacon()
+
+	// Enable checkpoints so that the nodes would be able to state sync if they accepted the chain.
+	f.Network.Consensus.Parameters.StateCheckpointInterval = 10
+	f.Network.Consensus.Parameters.StateCheckpointNumKept = 100
+	f.Network.Consensus.Parameters.StateCheckpointChunkSize = 1024 * 1024
+	// Make sure the TLS addresses of the validators remain valid for the duration of the test.
+	for i := range f.Validators {
+		f.Validators[i].DisableCertRotation = true
+	}
+
+	// Nodes that will try to state sync using a conflicting trust root. These are expected to
+	// terminate with an error.
+	rejectHandlers := []log.WatcherHandlerFactory{
+		oasis.LogAssertStateSyncProviderFailed(),
+		oasis.LogAssertNoABCIStateSyncComplete(),
+	}
+	f.Validators = append(f.Validators,
+		oasis.ValidatorFixture{
+			NodeFixture: oasis.NodeFixture{
+				NoAutoStart: true,
+			},
+			AllowErrorTermination:      true,
+			Entity:                     1,
+			Consensus:                  oasis.ConsensusFixture{EnableConsensusRPCWorker: true},
+			LogWatcherHandlerFactories: rejectHandlers,
+		},
+	)
+	f.StorageWorkers = append(f.StorageWorkers,
+		oasis.StorageWorkerFixture{
+			NodeFixture: oasis.NodeFixture{
+				NoAutoStart: true,
+			},
+			AllowErrorTermination:      true,
+			Entity:                     1,
+			Backend:                    "badger",
+			LogWatcherHandlerFactories: rejectHandlers,
+		},
+	)
+
+	return f, nil
+}
+
+func (sc *longRangeForkImpl) Run(childEnv *env.Env) error {
+	ctx := context.Background()
+
+	if err := sc.Net.Start(); err != nil {
+		return err
+	}
+
+	// The last validator is the one that will try to sync from the conflicting trust root.
+	numValidators := len(sc.Net.Validators()) - 1
+
+	sc.Logger.Info("waiting for network to come up")
+	if err := sc.Net.Controller().WaitNodesRegistered(ctx, numValidators); err != nil {
+		return err
+	}
+
+	// Remember a block from the original chain which will be abandoned.
+	forkBlk, err := sc.waitForBlock(ctx, longRangeForkHeight)
+	if err != nil {
+		return err
+	}
+	sc.Logger.Info("got block from the original chain",
+		"height", forkBlk.Height,
+		"hash", forkBlk.Hash.Hex(),
+	)
+
+	// Wipe all consensus state and restart the network from the same genesis document. Since
+	// the same validators sign the new chain, this results in a conflicting history.
+	sc.Logger.Info("stopping the network")
+	sc.Net.Stop()
+
+	genesisPath := sc.Net.GenesisPath()
+	if err = sc.ResetConsensusState(childEnv); err != nil {
+		return fmt.Errorf("failed to reset consensus state: %w", err)
+	}
+
+	fixture, err := sc.Fixture()
+	if err != nil {
+		return err
+	}
+	fixture.Network.GenesisFile = genesisPath
+	// Make sure to not overwrite entities.
+	for i, entity := range fixture.Entities {
+		if !entity.IsDebugTestEntity {
+			fixture.Entities[i].Restore = true
+		}
+	}
+	if sc.Net, err = fixture.Create(childEnv); err != nil {
+		return err
+	}
+	// If network is used, enable shorter per-node socket paths, because some e2e test datadir
+	// exceed maximum unix socket path length.
+	sc.Net.Config().UseShortGrpcSocketPaths = true
+
+	sc.Logger.Info("starting the forked network")
+	if err = sc.Net.Start(); err != nil {
+		return err
+	}
+	if err = sc.Net.Controller().WaitNodesRegistered(ctx, numValidators); err != nil {
+		return err
+	}
+	if _, err = sc.waitForBlock(ctx, longRangeForkHeight+10); err != nil {
+		return err
+	}
+
+	blk, err := sc.Net.Controller().Consensus.GetBlock(ctx, longRangeForkHeight)
+	if err != nil {
+		return fmt.Errorf("failed to get block from the forked chain: %w", err)
+	}
+	sc.Logger.Info("got block from the forked chain",
+		"height", blk.Height,
+		"hash", blk.Hash.Hex(),
+	)
+	if forkBlk.Hash.Equal(&blk.Hash) {
+		return fmt.Errorf("chains did not fork at height %d", longRangeForkHeight)
+	}
+
+	consensusNodes, err := sc.consensusNodes(ctx, sc.Net.Validators()[:numValidators])
+	if err != nil {
+		return err
+	}
+
+	// Light clients should only accept the chain when using a trust root from the same chain.
+	sc.Logger.Info("checking light client using a trust root from the forked chain")
+	if err = sc.verifyLightClient(ctx, consensusNodes, blk); err != nil {
+		return fmt.Errorf("light client rejected the canonical chain: %w", err)
+	}
+	sc.Logger.Info("checking light client using a trust root from the original chain")
+	if err = sc.verifyLightClient(ctx, consensusNodes, forkBlk); err == nil {
+		return fmt.Errorf("light client accepted a conflicting chain")
+	}
+	sc.Logger.Info("light client rejected the conflicting chain",
+		"err", err,
+	)
+
+	// Nodes that state sync using a trust root from the original chain should refuse to start.
+	var rawAddresses []string
+	for _, addr := range consensusNodes {
+		var rawAddr []byte
+		if rawAddr, err = addr.MarshalText(); err != nil {
+			return fmt.Errorf("failed to marshal TLS address: %w", err)
+		}
+		rawAddresses = append(rawAddresses, string(rawAddr))
+	}
+	stateSyncCfg := &oasis.ConsensusStateSyncCfg{
+		ConsensusNodes: rawAddresses,
+		TrustHeight:    uint64(forkBlk.Height),
+		TrustHash:      forkBlk.Hash.Hex(),
+	}
+
+	for _, n := range []*oasis.Node{
+		sc.Net.Validators()[numValidators].Node,
+		sc.Net.StorageWorkers()[0].Node,
+	} {
+		n.SetConsensusStateSync(stateSyncCfg)
+		if err = sc.ensureNodeRejects(n); err != nil {
+			return err
+		}
+	}
+
+	// Make sure the rest of the network keeps making progress.
+	if _, err = sc.waitForBlock(ctx, longRangeForkHeight+20); err != nil {
+		return err
+	}
+
+	return sc.Net.CheckLogWatchers()
+}
+
+// waitForBlock waits for the network to reach the given height and returns the block at that
+// height.
+func (sc *longRangeForkImpl) waitForBlock(ctx context.Context, height int64) (*consensus.Block, error) {
+	blockCh, blockSub, err := sc.Net.Controller().Consensus.WatchBlocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer blockSub.Close()
+
+	sc.Logger.Info("waiting for block",
+		"height", height,
+	)
+	for {
+		select {
+		case blk := <-blockCh:
+			if blk.Height < height {
+				continue
+			}
+			if blk.Height == height {
+				return blk, nil
+			}
+			// Make sure to return the block at the requested height.
+			return sc.Net.Controller().Consensus.GetBlock(ctx, height)
+		case <-time.After(60 * time.Second):
+			return nil, fmt.Errorf("timed out waiting for block at height %d", height)
+		}
+	}
+}
+
+// consensusNodes returns the TLS addresses of the public consensus RPC endpoints of the given
+// validators.
+func (sc *longRangeForkImpl) consensusNodes(ctx context.Context, validators []*oasis.Validator) ([]node.TLSAddress, error) {
+	var addresses []node.TLSAddress
+	for _, v := range validators {
+		ctrl, err := oasis.NewController(v.SocketPath())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create controller for validator %s: %w", v.Name, err)
+		}
+
+		var status *control.Status
+		status, err = ctrl.GetStatus(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get status for validator %s: %w", v.Name, err)
+		}
+
+		if status.Registration.Descriptor == nil {
+			return nil, fmt.Errorf("validator %s has not registered", v.Name)
+		}
+		if len(status.Registration.Descriptor.TLS.Addresses) == 0 {
+			return nil, fmt.Errorf("validator %s has no TLS addresses", v.Name)
+		}
+		addresses = append(addresses, status.Registration.Descriptor.TLS.Addresses[0])
+	}
+	return addresses, nil
+}
+
+// verifyLightClient creates a light client using the given block as the trust root and uses it to
+// verify the latest block served by the given consensus nodes.
+func (sc *longRangeForkImpl) verifyLightClient(ctx context.Context, consensusNodes []node.TLSAddress, trustBlk *consensus.Block) error {
+	genesisProvider, err := genesisFile.NewFileProvider(sc.Net.GenesisPath())
+	if err != nil {
+		return fmt.Errorf("failed to load genesis file: %w", err)
+	}
+	tmGenDoc, err := tendermint.GetTendermintGenesisDocument(genesisProvider)
+	if err != nil {
+		return fmt.Errorf("failed to get tendermint genesis document: %w", err)
+	}
+
+	lightCtx, cancel := context.WithTimeout(ctx, longRangeForkTimeout)
+	defer cancel()
+
+	lc, err := light.NewClient(lightCtx, light.ClientConfig{
+		GenesisDocument: tmGenDoc,
+		ConsensusNodes:  consensusNodes,
+		TrustOptions: tmlight.TrustOptions{
+			Period: 24 * time.Hour,
+			Height: trustBlk.Height,
+			Hash:   trustBlk.Hash[:],
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	latest, err := sc.Net.Controller().Consensus.GetBlock(ctx, consensus.HeightLatest)
+	if err != nil {
+		return fmt.Errorf("failed to get latest block: %w", err)
+	}
+	lb, err := lc.GetVerifiedLightBlock(lightCtx, latest.Height)
+	if err != nil {
+		return fmt.Errorf("failed to verify light block at height %d: %w", latest.Height, err)
+	}
+	if !bytes.Equal(lb.Hash(), latest.Hash[:]) {
+		return fmt.Errorf("verified light block hash mismatch (expected: %X got: %X)", latest.Hash, lb.Hash())
+	}
+	return nil
+}
+
+// ensureNodeRejects starts the given node and makes sure that it terminates. The reason for the
+// termination is verified by the node's log watchers.
+func (sc *longRangeForkImpl) ensureNodeRejects(n *oasis.Node) error {
+	sc.Logger.Info("starting node with a conflicting trust root",
+		"node", n.Name,
+	)
+	if err := n.Start(); err != nil {
+		return fmt.Errorf("failed to start node %s: %w", n.Name, err)
+	}
+
+	select {
+	case err := <-n.Exit():
+		sc.Logger.Info("node rejected the conflicting chain",
+			"node", n.Name,
+			"err", err,
+		)
+		return nil
+	case <-time.After(longRangeForkTimeout):
+		return fmt.Errorf("node %s did not reject the conflicting chain", n.Name)
+	}
+}
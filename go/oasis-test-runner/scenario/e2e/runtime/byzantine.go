@@ -34,6 +34,7 @@ var (
 		"executor-honest",
 		"executor",
 		nil,
+		nil,
 		oasis.ByzantineDefaultIdentitySeed,
 		false,
 		nil,
@@ -48,6 +49,7 @@ var (
 		"executor-scheduler-honest",
 		"executor",
 		nil,
+		nil,
 		oasis.ByzantineSlot1IdentitySeed,
 		false,
 		nil,
@@ -70,6 +72,7 @@ var (
 			oasis.LogAssertNoRoundFailures(),
 			oasis.LogAssertExecutionDiscrepancyDetected(),
 		},
+		nil,
 		oasis.ByzantineDefaultIdentitySeed,
 		false,
 		// Byzantine node entity should be slashed once for submitting incorrect commitment.
@@ -95,6 +98,7 @@ var (
 			oasis.LogAssertTimeouts(),
 			oasis.LogAssertExecutionDiscrepancyDetected(),
 		},
+		nil,
 		oasis.ByzantineSlot1IdentitySeed,
 		false,
 		nil,
@@ -118,6 +122,7 @@ var (
 			oasis.LogAssertNoRoundFailures(),
 			oasis.LogAssertExecutionDiscrepancyDetected(),
 		},
+		nil,
 		oasis.ByzantineDefaultIdentitySeed,
 		false,
 		nil,
@@ -140,6 +145,7 @@ var (
 			oasis.LogAssertTimeouts(),
 			oasis.LogAssertExecutionDiscrepancyDetected(),
 		},
+		nil,
 		oasis.ByzantineSlot1IdentitySeed,
 		false,
 		nil,
@@ -164,6 +170,7 @@ var (
 			oasis.LogAssertNoRoundFailures(),
 			oasis.LogAssertExecutionDiscrepancyDetected(),
 		},
+		nil,
 		oasis.ByzantineDefaultIdentitySeed,
 		false,
 		nil,
@@ -186,6 +193,7 @@ var (
 			oasis.LogAssertTimeouts(),
 			oasis.LogAssertExecutionDiscrepancyDetected(),
 		},
+		nil,
 		oasis.ByzantineSlot1IdentitySeed,
 		false,
 		nil,
@@ -204,6 +212,7 @@ var (
 		"storage-honest",
 		"storage",
 		nil,
+		nil,
 		oasis.ByzantineDefaultIdentitySeed,
 		false,
 		nil,
@@ -221,6 +230,7 @@ var (
 		// Failing first 5 apply requests should result in no round failures. As the proposer
 		// should keep retrying proposing a batch until it succeeds.
 		nil,
+		nil,
 		oasis.ByzantineDefaultIdentitySeed,
 		false,
 		nil,
@@ -244,6 +254,7 @@ var (
 			oasis.LogAssertDiscrepancyMajorityFailure(),
 			oasis.LogAssertRoundFailures(),
 		},
+		nil,
 		oasis.ByzantineDefaultIdentitySeed,
 		false,
 		nil,
@@ -262,6 +273,7 @@ var (
 		"storage",
 		// There should be no discrepancy or round failures.
 		nil,
+		nil,
 		oasis.ByzantineDefaultIdentitySeed,
 		// Hack to work around the way the storage client selects nodes.
 		// It can happen that for this test, the client will keep selecting the byzantine
@@ -286,6 +298,7 @@ var (
 		"storage",
 		// There should be no discrepancy or round failures.
 		nil,
+		nil,
 		oasis.ByzantineDefaultIdentitySeed,
 		false,
 		nil,
@@ -298,6 +311,77 @@ var (
 			Role: scheduler.RoleWorker,
 		},
 	)
+	// ByzantineStorageWrongReceiptApplyBatch is the byzantine storage scenario where storage node
+	// returns receipts for wrong roots for the first 3 ApplyBatch requests.
+	ByzantineStorageWrongReceiptApplyBatch scenario.Scenario = newByzantineImpl(
+		"storage-wrong-receipt-applybatch",
+		"storage",
+		[]log.WatcherHandlerFactory{
+			// Wrong receipts are rejected so the executors fail to obtain enough receipts. There
+			// should be a discrepancy and discrepancy resolution should fail with majority failure.
+			oasis.LogAssertExecutionDiscrepancyDetected(),
+			oasis.LogAssertDiscrepancyMajorityFailure(),
+			oasis.LogAssertRoundFailures(),
+		},
+		[]log.WatcherHandlerFactory{
+			// All executors (the 2 executor workers and 1 backup node) should reject the receipts.
+			oasis.LogAssertStorageInvalidReceipt(),
+		},
+		oasis.ByzantineDefaultIdentitySeed,
+		false,
+		nil,
+		[]oasis.Argument{
+			// Return wrong receipts for first 3 ApplyBatch requests - from the 2 executor workers
+			// and 1 backup node.
+			{Name: byzantine.CfgNumStorageWrongReceiptApplyBatch, Values: []string{strconv.Itoa(3)}},
+		},
+		scheduler.ForceElectCommitteeRole{
+			Kind: scheduler.KindStorage,
+			Role: scheduler.RoleWorker,
+		},
+	)
+	// ByzantineStorageWithholdGetDiff is the byzantine storage node scenario that withholds
+	// GetDiff responses by returning empty diffs.
+	ByzantineStorageWithholdGetDiff scenario.Scenario = newByzantineImpl(
+		"storage-withhold-getdiff",
+		"storage",
+		// There should be no discrepancy or round failures as syncing storage nodes detect the
+		// bogus diffs and fetch them from other storage nodes.
+		nil,
+		nil,
+		oasis.ByzantineDefaultIdentitySeed,
+		false,
+		nil,
+		[]oasis.Argument{
+			// Withhold all GetDiff responses.
+			{Name: byzantine.CfgWithholdGetDiff},
+		},
+		scheduler.ForceElectCommitteeRole{
+			Kind: scheduler.KindStorage,
+			Role: scheduler.RoleWorker,
+		},
+	)
+	// ByzantineStorageCorruptSyncProofs is the byzantine storage node scenario that corrupts
+	// proofs in Sync* responses.
+	ByzantineStorageCorruptSyncProofs scenario.Scenario = newByzantineImpl(
+		"storage-corrupt-sync-proofs",
+		"storage",
+		// There should be no discrepancy or round failures as storage clients reject the invalid
+		// proofs and read from other storage nodes.
+		nil,
+		nil,
+		oasis.ByzantineDefaultIdentitySeed,
+		false,
+		nil,
+		[]oasis.Argument{
+			// Corrupt all Sync* proofs.
+			{Name: byzantine.CfgCorruptSyncProofs},
+		},
+		scheduler.ForceElectCommitteeRole{
+			Kind: scheduler.KindStorage,
+			Role: scheduler.RoleWorker,
+		},
+	)
 )
 
 type byzantineImpl struct {
@@ -312,6 +396,10 @@ type byzantineImpl struct {
 	identitySeed               string
 	logWatcherHandlerFactories []log.WatcherHandlerFactory
 
+	// computeLogWatcherHandlerFactories are additional log watcher handler factories for all of
+	// the compute workers.
+	computeLogWatcherHandlerFactories []log.WatcherHandlerFactory
+
 	// expectedSlashes are the expected slashes of the byzantine entity. Value
 	// is the number of times the entity is expected to be slashed for the specific
 	// reason.
@@ -322,6 +410,7 @@ func newByzantineImpl(
 	name string,
 	script string,
 	logWatcherHandlerFactories []log.WatcherHandlerFactory,
+	computeLogWatcherHandlerFactories []log.WatcherHandlerFactory,
 	identitySeed string,
 	skipStorageWait bool,
 	expectedSlashes map[staking.SlashReason]uint64,
@@ -329,14 +418,15 @@ func newByzantineImpl(
 	schedParams scheduler.ForceElectCommitteeRole,
 ) scenario.Scenario {
 	sc := &byzantineImpl{
-		runtimeImpl:                *newRuntimeImpl("byzantine/"+name, nil),
-		script:                     script,
-		extraArgs:                  extraArgs,
-		skipStorageSyncWait:        skipStorageWait,
-		identitySeed:               identitySeed,
-		logWatcherHandlerFactories: logWatcherHandlerFactories,
-		expectedSlashes:            expectedSlashes,
-		schedParams:                schedParams,
+		runtimeImpl:                       *newRuntimeImpl("byzantine/"+name, nil),
+		script:                            script,
+		extraArgs:                         extraArgs,
+		skipStorageSyncWait:               skipStorageWait,
+		identitySeed:                      identitySeed,
+		logWatcherHandlerFactories:        logWatcherHandlerFactories,
+		computeLogWatcherHandlerFactories: computeLogWatcherHandlerFactories,
+		expectedSlashes:                   expectedSlashes,
+		schedParams:                       schedParams,
 	}
 
 	// The byzantine node code and our tests are extremely sensitive
@@ -349,14 +439,15 @@ func newByzantineImpl(
 
 func (sc *byzantineImpl) Clone() scenario.Scenario {
 	return &byzantineImpl{
-		runtimeImpl:                *sc.runtimeImpl.Clone().(*runtimeImpl),
-		script:                     sc.script,
-		extraArgs:                  sc.extraArgs,
-		skipStorageSyncWait:        sc.skipStorageSyncWait,
-		identitySeed:               sc.identitySeed,
-		logWatcherHandlerFactories: sc.logWatcherHandlerFactories,
-		expectedSlashes:            sc.expectedSlashes,
-		schedParams:                sc.schedParams,
+		runtimeImpl:                       *sc.runtimeImpl.Clone().(*runtimeImpl),
+		script:                            sc.script,
+		extraArgs:                         sc.extraArgs,
+		skipStorageSyncWait:               sc.skipStorageSyncWait,
+		identitySeed:                      sc.identitySeed,
+		logWatcherHandlerFactories:        sc.logWatcherHandlerFactories,
+		computeLogWatcherHandlerFactories: sc.computeLogWatcherHandlerFactories,
+		expectedSlashes:                   sc.expectedSlashes,
+		schedParams:                       sc.schedParams,
 	}
 }
 
@@ -413,6 +504,12 @@ func (sc *byzantineImpl) Fixture() (*oasis.NetworkFixture, error) {
 	if sc.logWatcherHandlerFactories != nil {
 		f.Network.DefaultLogWatcherHandlerFactories = sc.logWatcherHandlerFactories
 	}
+	for i := range f.ComputeWorkers {
+		f.ComputeWorkers[i].LogWatcherHandlerFactories = append(
+			f.ComputeWorkers[i].LogWatcherHandlerFactories,
+			sc.computeLogWatcherHandlerFactories...,
+		)
+	}
 	// Provision a Byzantine node.
 	schedParams := sc.schedParams // Copy
 	f.ByzantineNodes = []oasis.ByzantineFixture{
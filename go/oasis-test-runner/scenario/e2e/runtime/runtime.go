@@ -2,8 +2,8 @@ package runtime
 
 import (
 	"context"
-	"crypto/rand"
 	"fmt"
+	"math/rand"
 	"path/filepath"
 	"time"
 
@@ -94,6 +94,9 @@ type runtimeImpl struct {
 	// If your new test needs this, your test is bad, and you should go
 	// and rewrite it so that this option isn't set.
 	debugWeakAlphaOk bool
+
+	// rng is the random number generator derived from the scenario seed.
+	rng *rand.Rand
 }
 
 func newRuntimeImpl(name string, testClient TestClient) *runtimeImpl {
@@ -131,6 +134,10 @@ func (sc *runtimeImpl) Clone() scenario.Scenario {
 }
 
 func (sc *runtimeImpl) PreInit(childEnv *env.Env) error {
+	var err error
+	if sc.rng, err = childEnv.NewRand("runtime scenario"); err != nil {
+		return fmt.Errorf("failed to create random source: %w", err)
+	}
 	return nil
 }
 
@@ -639,9 +646,7 @@ func (sc *runtimeImpl) initialEpochTransitions(fixture *oasis.NetworkFixture) (b
 			//
 			// If this causes your test to fail, it is not this code that is
 			// wrong, it is the test that is wrong.
-			var randByte [1]byte
-			_, _ = rand.Read(randByte[:])
-			numSkips := (int)(randByte[0]&3) + 1
+			numSkips := sc.rng.Intn(4) + 1
 			sc.Logger.Info("advancing the epoch to prevent hardcoding time assumptions in tests",
 				"num_advances", numSkips,
 			)
@@ -686,6 +691,9 @@ func RegisterScenarios() error {
 		ByzantineStorageFailApplyBatch,
 		ByzantineStorageFailRead,
 		ByzantineStorageCorruptGetDiff,
+		ByzantineStorageWrongReceiptApplyBatch,
+		ByzantineStorageWithholdGetDiff,
+		ByzantineStorageCorruptSyncProofs,
 		// Storage sync test.
 		StorageSync,
 		StorageSyncFromRegistered,
@@ -747,6 +755,10 @@ func RegisterScenarios() error {
 		// it is identical to the txsource-multi-short, only using fewer nodes
 		// due to SGX CI instance resource constrains.
 		TxSourceMultiShortSGX,
+		// Upgrade path tests. Non-default, because they require node binaries
+		// of previous releases.
+		UpgradePathDumpRestore,
+		UpgradePathInPlace,
 	} {
 		if err := cmd.RegisterNondefault(s); err != nil {
 			return err
@@ -3,9 +3,9 @@ package runtime
 import (
 	"context"
 	"crypto"
-	cryptoRand "crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"math"
 	"math/rand"
 	"os"
@@ -196,17 +196,24 @@ type txSourceImpl struct { // nolint: maligned
 }
 
 func (sc *txSourceImpl) PreInit(childEnv *env.Env) error {
-	// Generate a new random seed and log it so we can reproduce the run.
+	if err := sc.runtimeImpl.PreInit(childEnv); err != nil {
+		return err
+	}
+
+	// Derive the seed from the scenario seed and log it so we can reproduce the run.
 	// Use existing seed, if it already exists.
 	if sc.seed == "" {
 		rawSeed := make([]byte, 16)
-		_, err := cryptoRand.Read(rawSeed)
+		src, err := childEnv.NewDrbg("txsource scenario seed")
 		if err != nil {
-			return fmt.Errorf("failed to generate random seed: %w", err)
+			return fmt.Errorf("failed to create random source: %w", err)
+		}
+		if _, err = io.ReadFull(src, rawSeed); err != nil {
+			return fmt.Errorf("failed to generate seed: %w", err)
 		}
 		sc.seed = hex.EncodeToString(rawSeed)
 
-		sc.Logger.Info("using random seed",
+		sc.Logger.Info("using seed",
 			"seed", sc.seed,
 		)
 	}
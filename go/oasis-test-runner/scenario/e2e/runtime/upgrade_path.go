@@ -0,0 +1,265 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/env"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/scenario"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/scenario/e2e"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+const (
+	// cfgPreUpgradeNodeBinary is the path to the oasis-node executable of a previous release
+	// which is used to run the network before the upgrade.
+	//
+	// Multiple comma-separated values can be given in order to test upgrade paths from
+	// multiple previous releases.
+	cfgPreUpgradeNodeBinary = "pre_upgrade.node.binary"
+
+	// upgradePathTransferAmount is the amount transferred before and after the upgrade.
+	upgradePathTransferAmount = 10
+)
+
+var (
+	// UpgradePathDumpRestore is the upgrade path scenario where the network running a previous
+	// release is upgraded to the current release via a dump and restore.
+	UpgradePathDumpRestore scenario.Scenario = newUpgradePathImpl("dump-restore", false)
+
+	// UpgradePathInPlace is the upgrade path scenario where the nodes of the network running
+	// a previous release are restarted with the current release, keeping all of their state.
+	UpgradePathInPlace scenario.Scenario = newUpgradePathImpl("in-place", true)
+)
+
+type upgradePathImpl struct {
+	runtimeImpl
+
+	inPlace bool
+}
+
+func newUpgradePathImpl(name string, inPlace bool) scenario.Scenario {
+	// Use -nomsg variant as the dump-restore variant cannot reconstruct the emitted messages
+	// from the state dump alone.
+	sc := &upgradePathImpl{
+		runtimeImpl: *newRuntimeImpl(
+			"upgrade-path/"+name,
+			NewLongTermTestClient().WithMode(ModePart1NoMsg),
+		),
+		inPlace: inPlace,
+	}
+	sc.Flags.String(cfgPreUpgradeNodeBinary, "", "path to the node binary used before the upgrade (defaults to node.binary)")
+
+	return sc
+}
+
+func (sc *upgradePathImpl) Clone() scenario.Scenario {
+	return &upgradePathImpl{
+		runtimeImpl: *sc.runtimeImpl.Clone().(*runtimeImpl),
+		inPlace:     sc.inPlace,
+	}
+}
+
+func (sc *upgradePathImpl) Fixture() (*oasis.NetworkFixture, error) {
+	// Start the network with the previous release.
+	return sc.fixture(true)
+}
+
+// fixture returns the network fixture using either the pre-upgrade or the current node binary.
+func (sc *upgradePathImpl) fixture(preUpgrade bool) (*oasis.NetworkFixture, error) {
+	f, err := sc.runtimeImpl.Fixture()
+	if err != nil {
+		return nil, err
+	}
+
+	if preUpgradeBinary, _ := sc.Flags.GetString(cfgPreUpgradeNodeBinary); preUpgrade && preUpgradeBinary != "" {
+		f.Network.NodeBinary = preUpgradeBinary
+	}
+
+	// Fund entity account so we'll be able to perform staking operations.
+	f.Network.DeterministicIdentities = true
+	f.Network.RestoreIdentities = true
+	f.Network.StakingGenesis = &staking.Genesis{
+		TotalSupply: *quantity.NewFromUint64(1000),
+		Ledger: map[staking.Address]*staking.Account{
+			e2e.DeterministicEntity1: {
+				General: staking.GeneralAccount{
+					Balance: *quantity.NewFromUint64(1000),
+				},
+			},
+		},
+	}
+
+	return f, nil
+}
+
+func (sc *upgradePathImpl) Run(childEnv *env.Env) error {
+	ctx := context.Background()
+	if err := sc.startNetworkAndTestClient(ctx, childEnv); err != nil {
+		return err
+	}
+
+	preUpgradeBinary := sc.Net.Config().NodeBinary
+	sc.Logger.Info("network started with the pre-upgrade node binary",
+		"node_binary", preUpgradeBinary,
+	)
+
+	// Perform a staking operation before the upgrade.
+	initialBalance, err := sc.testEntityBalance(ctx)
+	if err != nil {
+		return err
+	}
+	if err = sc.submitTransfer(ctx); err != nil {
+		return err
+	}
+	if err = sc.checkTransferred(ctx, initialBalance, 1); err != nil {
+		return err
+	}
+
+	// Wait for the client to exit.
+	if err = sc.waitTestClientOnly(); err != nil {
+		return err
+	}
+
+	// Upgrade the network to the current node binary.
+	fixture, err := sc.fixture(false)
+	if err != nil {
+		return err
+	}
+
+	sc.Logger.Info("upgrading the network",
+		"pre_upgrade_node_binary", preUpgradeBinary,
+		"node_binary", fixture.Network.NodeBinary,
+		"in_place", sc.inPlace,
+	)
+	switch sc.inPlace {
+	case true:
+		err = sc.upgradeInPlace(childEnv, fixture)
+	case false:
+		err = sc.DumpRestoreNetwork(childEnv, fixture, false, nil)
+	}
+	if err != nil {
+		return err
+	}
+	if err = sc.Net.Start(); err != nil {
+		return fmt.Errorf("failed to start upgraded network: %w", err)
+	}
+
+	// Wait for all storage and compute nodes to be ready.
+	sc.Logger.Info("waiting for all storage and compute nodes to be ready")
+	for _, n := range sc.Net.StorageWorkers() {
+		if err = n.WaitReady(ctx); err != nil {
+			return fmt.Errorf("failed to wait for a storage worker: %w", err)
+		}
+	}
+	for _, n := range sc.Net.ComputeWorkers() {
+		if err = n.WaitReady(ctx); err != nil {
+			return fmt.Errorf("failed to wait for a compute worker: %w", err)
+		}
+	}
+
+	// Ensure that staking operations continue to work after the upgrade.
+	if err = sc.submitTransfer(ctx); err != nil {
+		return err
+	}
+	if err = sc.checkTransferred(ctx, initialBalance, 2); err != nil {
+		return err
+	}
+
+	// Check that runtime rounds continue with the upgraded network.
+	newTestClient := sc.testClient.Clone().(*LongTermTestClient)
+	sc.runtimeImpl.testClient = newTestClient.WithMode(ModePart2).WithSeed("second_seed")
+	return sc.runtimeImpl.Run(childEnv)
+}
+
+// upgradeInPlace stops the network and recreates it with the given fixture, keeping all of the
+// node state and the original genesis document.
+func (sc *upgradePathImpl) upgradeInPlace(childEnv *env.Env, fixture *oasis.NetworkFixture) error {
+	sc.Logger.Info("stopping the network")
+	sc.Net.Stop()
+
+	fixture.Network.GenesisFile = sc.Net.GenesisPath()
+	// Make sure to not overwrite entities.
+	for i, entity := range fixture.Entities {
+		if !entity.IsDebugTestEntity {
+			fixture.Entities[i].Restore = true
+		}
+	}
+
+	var err error
+	if sc.Net, err = fixture.Create(childEnv); err != nil {
+		return fmt.Errorf("failed to recreate network: %w", err)
+	}
+
+	// If network is used, enable shorter per-node socket paths, because some e2e test datadir
+	// exceed maximum unix socket path length.
+	sc.Net.Config().UseShortGrpcSocketPaths = true
+
+	return nil
+}
+
+// submitTransfer transfers funds from the funded entity account to the test entity account.
+func (sc *upgradePathImpl) submitTransfer(ctx context.Context) error {
+	acct, err := sc.Net.Controller().Staking.Account(ctx,
+		&staking.OwnerQuery{
+			Height: consensus.HeightLatest,
+			Owner:  e2e.DeterministicEntity1,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed querying account: %w", err)
+	}
+
+	sc.Logger.Info("submitting transfer",
+		"from", e2e.DeterministicEntity1,
+		"to", e2e.TestEntityAccount,
+		"nonce", acct.General.Nonce,
+	)
+	transfer := staking.Transfer{
+		To:     e2e.TestEntityAccount,
+		Amount: *quantity.NewFromUint64(upgradePathTransferAmount),
+	}
+	tx := staking.NewTransferTx(acct.General.Nonce, &transaction.Fee{Gas: 2000}, &transfer)
+	sigTx, err := transaction.Sign(sc.Net.Entities()[0].Signer(), tx)
+	if err != nil {
+		return fmt.Errorf("failed signing transfer transaction: %w", err)
+	}
+	if err = sc.Net.Controller().Consensus.SubmitTx(ctx, sigTx); err != nil {
+		return fmt.Errorf("failed submitting transfer transaction: %w", err)
+	}
+	return nil
+}
+
+// testEntityBalance returns the general balance of the test entity account.
+func (sc *upgradePathImpl) testEntityBalance(ctx context.Context) (*quantity.Quantity, error) {
+	acct, err := sc.Net.Controller().Staking.Account(ctx,
+		&staking.OwnerQuery{
+			Height: consensus.HeightLatest,
+			Owner:  e2e.TestEntityAccount,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed querying account: %w", err)
+	}
+	return &acct.General.Balance, nil
+}
+
+// checkTransferred checks that the test entity account received the given number of transfers.
+func (sc *upgradePathImpl) checkTransferred(ctx context.Context, initialBalance *quantity.Quantity, numTransfers uint64) error {
+	balance, err := sc.testEntityBalance(ctx)
+	if err != nil {
+		return err
+	}
+	expected := initialBalance.Clone()
+	if err = expected.Add(quantity.NewFromUint64(numTransfers * upgradePathTransferAmount)); err != nil {
+		return err
+	}
+	if balance.Cmp(expected) != 0 {
+		return fmt.Errorf("unexpected test entity balance (expected: %s got: %s)", expected, balance)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -351,7 +352,7 @@ func (sc *stakeCLIImpl) testTransfer(childEnv *env.Env, cli *cli.Helpers, src, d
 	}
 	ctx := contextWithTokenInfo()
 
-	unsignedTransferTxPath := filepath.Join(childEnv.Dir(), "stake_transfer_unsigned.cbor")
+	unsignedTransferTxPath := filepath.Join(childEnv.Dir(), "stake_transfer_unsigned.json")
 	if err = sc.genUnsignedTransferTx(childEnv, transferAmount, srcNonce, dst, unsignedTransferTxPath); err != nil {
 		return fmt.Errorf("genUnsignedTransferTx: %w", err)
 	}
@@ -369,6 +370,22 @@ func (sc *stakeCLIImpl) testTransfer(childEnv *env.Env, cli *cli.Helpers, src, d
 		return fmt.Errorf("wrong gas estimate: expected %d, got %d", expectedGasEstimate, gas)
 	}
 
+	// The interactive transaction builder should populate the nonce and the gas limit.
+	interactiveTransferTxPath := filepath.Join(childEnv.Dir(), "stake_transfer_interactive.json")
+	if err = sc.genInteractiveUnsignedTransferTx(childEnv, transferAmount, dst, interactiveTransferTxPath); err != nil {
+		return fmt.Errorf("genInteractiveUnsignedTransferTx: %w", err)
+	}
+	interactiveTransferTx, err := consensus.LoadUnsignedTx(interactiveTransferTxPath)
+	if err != nil {
+		return fmt.Errorf("failed to load interactive transfer tx: %w", err)
+	}
+	if interactiveTransferTx.Transaction.Nonce != srcNonce {
+		return fmt.Errorf("wrong interactive transfer tx nonce: expected %d, got %d", srcNonce, interactiveTransferTx.Transaction.Nonce)
+	}
+	if interactiveTransferTx.Transaction.Fee.Gas != expectedGasEstimate {
+		return fmt.Errorf("wrong interactive transfer tx gas limit: expected %d, got %d", expectedGasEstimate, interactiveTransferTx.Transaction.Fee.Gas)
+	}
+
 	transferTxPath := filepath.Join(childEnv.Dir(), "stake_transfer.json")
 	if err = sc.genTransferTx(childEnv, transferAmount, srcNonce, dst, transferTxPath); err != nil {
 		return err
@@ -377,6 +394,29 @@ func (sc *stakeCLIImpl) testTransfer(childEnv *env.Env, cli *cli.Helpers, src, d
 		return err
 	}
 
+	// Signing the unsigned transaction offline should result in the same signed transaction.
+	offlineTransferTxPath := filepath.Join(childEnv.Dir(), "stake_transfer_offline.json")
+	if err = cli.Consensus.SignTx(
+		unsignedTransferTxPath,
+		offlineTransferTxPath,
+		"--"+flags.CfgDebugDontBlameOasis,
+		"--"+flags.CfgDebugTestEntity,
+		"--"+flags.CfgAssumeYes,
+	); err != nil {
+		return err
+	}
+	rawTransferTx, err := ioutil.ReadFile(transferTxPath)
+	if err != nil {
+		return fmt.Errorf("failed to read transfer tx: %w", err)
+	}
+	rawOfflineTransferTx, err := ioutil.ReadFile(offlineTransferTxPath)
+	if err != nil {
+		return fmt.Errorf("failed to read offline signed transfer tx: %w", err)
+	}
+	if !bytes.Equal(rawTransferTx, rawOfflineTransferTx) {
+		return fmt.Errorf("offline signed transfer tx differs from online signed transfer tx")
+	}
+
 	expectedSrcBalance := mustInitQuantity(initBalance)
 	if err = sc.checkGeneralAccount(ctx, childEnv, src, &api.GeneralAccount{
 		Balance: expectedSrcBalance, Nonce: srcNonce,
@@ -952,6 +992,29 @@ func (sc *stakeCLIImpl) genUnsignedTransferTx(childEnv *env.Env, amount int, non
 	return nil
 }
 
+func (sc *stakeCLIImpl) genInteractiveUnsignedTransferTx(childEnv *env.Env, amount int, dst api.Address, txPath string) error {
+	sc.Logger.Info("generating interactive unsigned stake transfer tx", stake.CfgTransferDestination, dst)
+
+	args := []string{
+		"stake", "account", "gen_transfer",
+		"--" + stake.CfgAmount, strconv.Itoa(amount),
+		"--" + consensus.CfgTxFile, txPath,
+		"--" + stake.CfgTransferDestination, dst.String(),
+		"--" + consensus.CfgTxFeeAmount, strconv.Itoa(feeAmount),
+		"--" + consensus.CfgTxUnsigned,
+		"--" + stake.CfgInteractive,
+		"--" + grpc.CfgAddress, "unix:" + sc.Net.Validators()[0].SocketPath(),
+		"--" + flags.CfgDebugDontBlameOasis,
+		"--" + flags.CfgDebugTestEntity,
+		"--" + common.CfgDebugAllowTestKeys,
+		"--" + flags.CfgGenesisFile, sc.Net.GenesisPath(),
+	}
+	if out, err := cli.RunSubCommandWithOutput(childEnv, sc.Logger, "gen_transfer", sc.Net.Config().NodeBinary, args); err != nil {
+		return fmt.Errorf("genInteractiveUnsignedTransferTx: failed to generate transfer tx: error: %w output: %s", err, out.String())
+	}
+	return nil
+}
+
 func (sc *stakeCLIImpl) genTransferTx(childEnv *env.Env, amount int, nonce uint64, dst api.Address, txPath string) error {
 	sc.Logger.Info("generating stake transfer tx", stake.CfgTransferDestination, dst)
 
@@ -418,6 +418,21 @@ func (sc *nodeUpgradeImpl) Run(childEnv *env.Env) error { // nolint: gocyclo
 			return fmt.Errorf("failed to submit upgrade descriptor to validator %d: %w", i, err)
 		}
 	}
+
+	// Validate the upgrade before the upgrade epoch is reached.
+	sc.Logger.Info("performing upgrade dry run")
+	dryRun, err := sc.controller.DryRunUpgrade(sc.ctx, &validDescriptor)
+	if err != nil {
+		return fmt.Errorf("failed to perform upgrade dry run: %w", err)
+	}
+	if dryRun.Error != "" {
+		return fmt.Errorf("upgrade dry run failed: %s", dryRun.Error)
+	}
+	sc.Logger.Info("upgrade dry run succeeded",
+		"height", dryRun.Height,
+		"state_root", dryRun.StateRoot,
+	)
+
 	if err = sc.nextEpoch(); err != nil {
 		return err
 	}
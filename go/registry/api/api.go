@@ -219,6 +219,13 @@ type Backend interface {
 	// GetEvents returns the events at specified block height.
 	GetEvents(ctx context.Context, height int64) ([]*Event, error)
 
+	// GetNodeAttestations returns the most recent TEE attestation decisions
+	// made for the given node, ordered from the oldest to the newest.
+	//
+	// Only decisions observed by the queried node since it was started are
+	// returned.
+	GetNodeAttestations(ctx context.Context, nodeID signature.PublicKey) ([]*Event, error)
+
 	// Cleanup cleans up the registry backend.
 	Cleanup()
 }
@@ -301,15 +308,83 @@ type NodeUnfrozenEvent struct {
 	NodeID signature.PublicKey `json:"node_id"`
 }
 
+// AttestationRejectionReason is the reason for rejecting a node's TEE attestation.
+type AttestationRejectionReason string
+
+const (
+	// AttestationRejectedHardwareMismatch is the rejection reason when the TEE hardware of the
+	// node's runtime does not match the TEE hardware of the registered runtime.
+	AttestationRejectedHardwareMismatch AttestationRejectionReason = "tee_hardware_mismatch"
+	// AttestationRejectedBadEnclaveIdentity is the rejection reason when the attested enclave
+	// identity (MRENCLAVE/MRSIGNER) is not allowed by the runtime.
+	AttestationRejectedBadEnclaveIdentity AttestationRejectionReason = "bad_enclave_identity"
+	// AttestationRejectedRAKHashMismatch is the rejection reason when the attestation does not
+	// bind the node's RAK.
+	AttestationRejectedRAKHashMismatch AttestationRejectionReason = "rak_hash_mismatch"
+	// AttestationRejectedPolicyViolation is the rejection reason when the quote or TCB status or
+	// the enclave attributes are not allowed by the attestation acceptance policy.
+	AttestationRejectedPolicyViolation AttestationRejectionReason = "policy_violation"
+	// AttestationRejectedInvalid is the rejection reason when the attestation itself is invalid,
+	// e.g., malformed, incorrectly signed or expired.
+	AttestationRejectedInvalid AttestationRejectionReason = "invalid_attestation"
+)
+
+// NodeAttestationEvent signifies a decision on the TEE attestation of a node's runtime.
+type NodeAttestationEvent struct {
+	NodeID    signature.PublicKey `json:"node_id"`
+	RuntimeID common.Namespace    `json:"runtime_id"`
+	Accepted  bool                `json:"accepted"`
+
+	// Reason is the reason for rejecting the attestation.
+	Reason AttestationRejectionReason `json:"reason,omitempty"`
+	// Error is the detailed attestation verification error.
+	Error string `json:"error,omitempty"`
+}
+
+// AttestationError is the error returned when a node's TEE attestation is rejected.
+type AttestationError struct {
+	// RuntimeID is the identifier of the runtime whose attestation was rejected.
+	RuntimeID common.Namespace
+	// Err is the attestation verification error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *AttestationError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the attestation verification error.
+func (e *AttestationError) Unwrap() error {
+	return e.Err
+}
+
+// Reason returns the attestation rejection reason.
+func (e *AttestationError) Reason() AttestationRejectionReason {
+	switch {
+	case errors.Is(e.Err, ErrTEEHardwareMismatch):
+		return AttestationRejectedHardwareMismatch
+	case errors.Is(e.Err, node.ErrBadEnclaveIdentity):
+		return AttestationRejectedBadEnclaveIdentity
+	case errors.Is(e.Err, node.ErrRAKHashMismatch):
+		return AttestationRejectedRAKHashMismatch
+	case errors.Is(e.Err, node.ErrConstraintViolation):
+		return AttestationRejectedPolicyViolation
+	default:
+		return AttestationRejectedInvalid
+	}
+}
+
 // Event is a registry event returned via GetEvents.
 type Event struct {
 	Height int64     `json:"height,omitempty"`
 	TxHash hash.Hash `json:"tx_hash,omitempty"`
 
-	RuntimeEvent      *RuntimeEvent      `json:"runtime,omitempty"`
-	EntityEvent       *EntityEvent       `json:"entity,omitempty"`
-	NodeEvent         *NodeEvent         `json:"node,omitempty"`
-	NodeUnfrozenEvent *NodeUnfrozenEvent `json:"node_unfrozen,omitempty"`
+	RuntimeEvent         *RuntimeEvent         `json:"runtime,omitempty"`
+	EntityEvent          *EntityEvent          `json:"entity,omitempty"`
+	NodeEvent            *NodeEvent            `json:"node,omitempty"`
+	NodeUnfrozenEvent    *NodeUnfrozenEvent    `json:"node_unfrozen,omitempty"`
+	NodeAttestationEvent *NodeAttestationEvent `json:"node_attestation,omitempty"`
 }
 
 // NodeList is a per-epoch immutable node list.
@@ -549,7 +624,7 @@ func VerifyRegisterNodeArgs( // nolint: gocyclo
 
 			// If the node indicates TEE support for any of it's runtimes,
 			// validate the attestation evidence.
-			if err := VerifyNodeRuntimeEnclaveIDs(logger, rt, regRt, now); err != nil {
+			if err := VerifyNodeRuntimeEnclaveIDs(logger, rt, regRt, now, params); err != nil {
 				return nil, nil, err
 			}
 
@@ -729,7 +804,7 @@ func VerifyRegisterNodeArgs( // nolint: gocyclo
 }
 
 // VerifyNodeRuntimeEnclaveIDs verifies TEE-specific attributes of the node's runtime.
-func VerifyNodeRuntimeEnclaveIDs(logger *logging.Logger, rt *node.Runtime, regRt *Runtime, ts time.Time) error {
+func VerifyNodeRuntimeEnclaveIDs(logger *logging.Logger, rt *node.Runtime, regRt *Runtime, ts time.Time, params *ConsensusParameters) error {
 	// If no TEE available, do nothing.
 	if rt.Capabilities.TEE == nil {
 		return nil
@@ -742,16 +817,16 @@ func VerifyNodeRuntimeEnclaveIDs(logger *logging.Logger, rt *node.Runtime, regRt
 			"tee_hardware", rt.Capabilities.TEE.Hardware,
 			"ts", ts,
 		)
-		return ErrTEEHardwareMismatch
+		return &AttestationError{RuntimeID: rt.ID, Err: ErrTEEHardwareMismatch}
 	}
 
-	if err := rt.Capabilities.TEE.Verify(ts, regRt.Version.TEE); err != nil {
+	if err := rt.Capabilities.TEE.Verify(params.TEEFeatures, ts, regRt.Version.TEE, params.SGXPolicy); err != nil {
 		logger.Error("VerifyNodeRuntimeEnclaveIDs: failed to validate attestation",
 			"runtime_id", rt.ID,
 			"ts", ts,
 			"err", err,
 		)
-		return err
+		return &AttestationError{RuntimeID: rt.ID, Err: err}
 	}
 
 	return nil
@@ -1005,6 +1080,15 @@ func VerifyRuntime( // nolint: gocyclo
 			if len(cs.Enclaves) == 0 {
 				return fmt.Errorf("%w: invalid SGX TEE constraints", ErrNoEnclaveForRuntime)
 			}
+			if err := cs.ValidateBasic(); err != nil {
+				return fmt.Errorf("%w: invalid SGX TEE constraints: %s", ErrInvalidArgument, err)
+			}
+			if len(cs.AllowedTCBStatuses) > 0 && (params.TEEFeatures == nil || !params.TEEFeatures.SGX.PCS) {
+				return fmt.Errorf("%w: invalid SGX TEE constraints: PCS-based attestation not enabled", ErrInvalidArgument)
+			}
+			if cs.Policy != nil && (params.TEEFeatures == nil || !params.TEEFeatures.SGX.Policy) {
+				return fmt.Errorf("%w: invalid SGX TEE constraints: attestation policies not enabled", ErrInvalidArgument)
+			}
 		}
 	}
 
@@ -1208,6 +1292,15 @@ type ConsensusParameters struct {
 
 	// EnableRuntimeGovernanceModels is a set of enabled runtime governance models.
 	EnableRuntimeGovernanceModels map[RuntimeGovernanceModel]bool `json:"enable_runtime_governance_models,omitempty"`
+
+	// TEEFeatures are the enabled TEE features.
+	TEEFeatures *node.TEEFeatures `json:"tee_features,omitempty"`
+
+	// SGXPolicy is the default Intel SGX attestation acceptance policy which applies to all
+	// runtimes that do not specify their own policy in their SGX constraints.
+	//
+	// It may only be set when attestation acceptance policies are enabled in TEEFeatures.
+	SGXPolicy *node.SGXPolicy `json:"sgx_policy,omitempty"`
 }
 
 const (
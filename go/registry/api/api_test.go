@@ -1,15 +1,18 @@
 package api
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx"
 )
 
 func TestVerifyNodeUpdate(t *testing.T) {
@@ -134,3 +137,59 @@ func TestVerifyNodeUpdate(t *testing.T) {
 		require.Equal(t, tc.err, err, tc.msg)
 	}
 }
+
+func TestAttestationErrorReason(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		err    error
+		reason AttestationRejectionReason
+	}{
+		{ErrTEEHardwareMismatch, AttestationRejectedHardwareMismatch},
+		{node.ErrBadEnclaveIdentity, AttestationRejectedBadEnclaveIdentity},
+		{node.ErrRAKHashMismatch, AttestationRejectedRAKHashMismatch},
+		{node.ErrConstraintViolation, AttestationRejectedPolicyViolation},
+		{fmt.Errorf("ias/avr: quote expired"), AttestationRejectedInvalid},
+	} {
+		attErr := &AttestationError{Err: tc.err}
+		require.Equal(tc.reason, attErr.Reason(), "Reason for %s", tc.err)
+		require.ErrorIs(attErr, tc.err)
+	}
+}
+
+func TestVerifyRuntimeSGXPolicy(t *testing.T) {
+	require := require.New(t)
+
+	logger := logging.GetLogger("registry/api/tests")
+
+	var cs node.SGXConstraints
+	cs.Enclaves = []sgx.EnclaveIdentity{{}}
+	cs.Policy = &node.SGXPolicy{AllowDebugEnclaves: true}
+	rt := &Runtime{
+		Versioned:       cbor.NewVersioned(LatestRuntimeDescriptorVersion),
+		ID:              common.NewTestNamespaceFromSeed([]byte("km runtime"), common.NamespaceKeyManager),
+		Kind:            KindKeyManager,
+		TEEHardware:     node.TEEHardwareIntelSGX,
+		GovernanceModel: GovernanceEntity,
+		Version: VersionInfo{
+			TEE: cbor.Marshal(cs),
+		},
+		AdmissionPolicy: RuntimeAdmissionPolicy{
+			AnyNode: &AnyNodeRuntimeAdmissionPolicy{},
+		},
+	}
+	rt.Genesis.StateRoot.Empty()
+	params := &ConsensusParameters{
+		DebugAllowTestRuntimes: true,
+		EnableRuntimeGovernanceModels: map[RuntimeGovernanceModel]bool{
+			GovernanceEntity: true,
+		},
+	}
+
+	err := VerifyRuntime(params, logger, rt, false, false)
+	require.ErrorIs(err, ErrInvalidArgument, "runtime SGX policy should be rejected when policies are disabled")
+
+	params.TEEFeatures = &node.TEEFeatures{SGX: node.TEEFeaturesSGX{Policy: true}}
+	err = VerifyRuntime(params, logger, rt, false, false)
+	require.NoError(err, "runtime SGX policy should be accepted when policies are enabled")
+}
@@ -5,6 +5,7 @@ import (
 
 	"google.golang.org/grpc"
 
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/entity"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
@@ -35,6 +36,8 @@ var (
 	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0))
 	// methodGetEvents is the GetEvents method.
 	methodGetEvents = serviceName.NewMethod("GetEvents", int64(0))
+	// methodGetNodeAttestations is the GetNodeAttestations method.
+	methodGetNodeAttestations = serviceName.NewMethod("GetNodeAttestations", signature.PublicKey{})
 
 	// methodWatchEntities is the WatchEntities method.
 	methodWatchEntities = serviceName.NewMethod("WatchEntities", nil)
@@ -90,6 +93,10 @@ var (
 				MethodName: methodGetEvents.ShortName(),
 				Handler:    handlerGetEvents,
 			},
+			{
+				MethodName: methodGetNodeAttestations.ShortName(),
+				Handler:    handlerGetNodeAttestations,
+			},
 		},
 		Streams: []grpc.StreamDesc{
 			{
@@ -346,6 +353,29 @@ func handlerGetEvents( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetNodeAttestations( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var nodeID signature.PublicKey
+	if err := dec(&nodeID); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetNodeAttestations(ctx, nodeID)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetNodeAttestations.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetNodeAttestations(ctx, req.(signature.PublicKey))
+	}
+	return interceptor(ctx, nodeID, info, handler)
+}
+
 func handlerWatchEntities(srv interface{}, stream grpc.ServerStream) error {
 	if err := stream.RecvMsg(nil); err != nil {
 		return err
@@ -687,6 +717,14 @@ func (c *registryClient) GetEvents(ctx context.Context, height int64) ([]*Event,
 	return rsp, nil
 }
 
+func (c *registryClient) GetNodeAttestations(ctx context.Context, nodeID signature.PublicKey) ([]*Event, error) {
+	var rsp []*Event
+	if err := c.conn.Invoke(ctx, methodGetNodeAttestations.FullName(), nodeID, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
 func (c *registryClient) Cleanup() {
 }
 
@@ -94,7 +94,7 @@ type ExecutorParameters struct {
 	RoundTimeout int64 `json:"round_timeout"`
 
 	// MaxMessages is the maximum number of messages that can be emitted by the runtime in a
-	// single round.
+	// single round. It must not exceed the global roothash MaxRuntimeMessages limit.
 	MaxMessages uint32 `json:"max_messages"`
 }
 
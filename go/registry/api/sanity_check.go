@@ -35,6 +35,15 @@ func (g *Genesis) SanityCheck(
 		}
 	}
 
+	if g.Parameters.SGXPolicy != nil {
+		if g.Parameters.TEEFeatures == nil || !g.Parameters.TEEFeatures.SGX.Policy {
+			return fmt.Errorf("registry: sanity check failed: SGX policy set but attestation policies not enabled")
+		}
+		if err := g.Parameters.SGXPolicy.ValidateBasic(); err != nil {
+			return fmt.Errorf("registry: sanity check failed: invalid SGX policy: %w", err)
+		}
+	}
+
 	// Check entities.
 	seenEntities, err := SanityCheckEntities(logger, g.Entities)
 	if err != nil {
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"time"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
@@ -25,6 +27,47 @@ func valideRegisterEntity(v uint16) bool {
 	return true
 }
 
+func newRuntime(entityID signature.PublicKey, kind registry.RuntimeKind, gm registry.RuntimeGovernanceModel) *registry.Runtime {
+	var id common.Namespace
+	if err := id.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000"); err != nil {
+		panic(err)
+	}
+
+	return &registry.Runtime{
+		Versioned: cbor.NewVersioned(registry.LatestRuntimeDescriptorVersion),
+		ID:        id,
+		EntityID:  entityID,
+		Kind:      kind,
+		Executor: registry.ExecutorParameters{
+			GroupSize:         3,
+			GroupBackupSize:   1,
+			AllowedStragglers: 1,
+			RoundTimeout:      20,
+			MaxMessages:       128,
+		},
+		TxnScheduler: registry.TxnSchedulerParameters{
+			Algorithm:         registry.TxnSchedulerSimple,
+			MaxBatchSize:      1000,
+			MaxBatchSizeBytes: 16 * 1024 * 1024,
+			BatchFlushTimeout: 1 * time.Second,
+			ProposerTimeout:   20,
+		},
+		Storage: registry.StorageParameters{
+			GroupSize:               3,
+			MinWriteReplication:     2,
+			MaxApplyWriteLogEntries: 100_000,
+			MaxApplyOps:             2,
+			CheckpointInterval:      10_000,
+			CheckpointNumKept:       2,
+			CheckpointChunkSize:     1024 * 1024,
+		},
+		AdmissionPolicy: registry.RuntimeAdmissionPolicy{
+			AnyNode: &registry.AnyNodeRuntimeAdmissionPolicy{},
+		},
+		GovernanceModel: gm,
+	}
+}
+
 func main() {
 	// Configure chain context for all signatures using chain domain separation.
 	var chainContext hash.Hash
@@ -75,6 +118,17 @@ func main() {
 				NodeID: nodeSigner.Public(),
 			})
 			vectors = append(vectors, testvectors.MakeTestVector("UnfreezeNode", tx, true))
+
+			// Generate register runtime transactions.
+			for _, kind := range []registry.RuntimeKind{registry.KindInvalid, registry.KindCompute} {
+				for _, gm := range []registry.RuntimeGovernanceModel{registry.GovernanceEntity, registry.GovernanceRuntime} {
+					runtimeSigner := memorySigner.NewTestSigner("oasis-core registry test vectors: RegisterRuntime signer")
+					rt := newRuntime(runtimeSigner.Public(), kind, gm)
+					tx = registry.NewRegisterRuntimeTx(nonce, fee, rt)
+					valid := rt.ValidateBasic(true) == nil
+					vectors = append(vectors, testvectors.MakeTestVectorWithSigner("RegisterRuntime", tx, valid, runtimeSigner))
+				}
+			}
 		}
 	}
 
@@ -74,6 +74,13 @@ var (
 	// ErrInvalidEvidence is the error return when an invalid evidence is submitted.
 	ErrInvalidEvidence = errors.New(ModuleName, 10, "roothash: invalid evidence")
 
+	// ErrHistoryNotAvailable is the error returned when historic blocks of a runtime are
+	// requested, but the runtime's block history is not available.
+	ErrHistoryNotAvailable = errors.New(ModuleName, 11, "roothash: block history not available")
+
+	// ErrForbidden is the error returned when an operation is forbidden.
+	ErrForbidden = errors.New(ModuleName, 12, "roothash: forbidden")
+
 	// MethodExecutorCommit is the method name for executor commit submission.
 	MethodExecutorCommit = transaction.NewMethodName(ModuleName, "ExecutorCommit", ExecutorCommit{})
 
@@ -83,11 +90,15 @@ var (
 	// MethodEvidence is the method name for submitting evidence of node misbehavior.
 	MethodEvidence = transaction.NewMethodName(ModuleName, "Evidence", Evidence{})
 
+	// MethodSetRoundTimeout is the method name for overriding the executor round timeout.
+	MethodSetRoundTimeout = transaction.NewMethodName(ModuleName, "SetRoundTimeout", SetRoundTimeout{})
+
 	// Methods is a list of all methods supported by the roothash backend.
 	Methods = []transaction.MethodName{
 		MethodExecutorCommit,
 		MethodExecutorProposerTimeout,
 		MethodEvidence,
+		MethodSetRoundTimeout,
 	}
 )
 
@@ -105,6 +116,14 @@ type Backend interface {
 	// GetRuntimeState returns the given runtime's state.
 	GetRuntimeState(ctx context.Context, request *RuntimeRequest) (*RuntimeState, error)
 
+	// GetRoundResults returns the results of the given runtime's last normal round as of the
+	// given height, including the results of executing the runtime messages emitted in the round.
+	GetRoundResults(ctx context.Context, request *RuntimeRequest) (*LastRoundResults, error)
+
+	// GetRetainedEvidence returns the evidence of the given runtime's node misbehaviour that is
+	// currently retained in order to reject duplicate submissions.
+	GetRetainedEvidence(ctx context.Context, request *RuntimeRequest) ([]*RetainedEvidence, error)
+
 	// WatchBlocks returns a channel that produces a stream of
 	// annotated blocks.
 	//
@@ -113,7 +132,18 @@ type Backend interface {
 	// confirmed.
 	WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<-chan *AnnotatedBlock, pubsub.ClosableSubscription, error)
 
-	// WatchEvents returns a stream of protocol events.
+	// WatchBlocksFrom returns a channel that produces a stream of
+	// annotated blocks, starting at the given round.
+	//
+	// Historic blocks are replayed from the runtime's block history
+	// (which must be tracked by the node) before switching to blocks
+	// as they are confirmed. Each round is emitted exactly once.
+	WatchBlocksFrom(ctx context.Context, runtimeID common.Namespace, startRound uint64) (<-chan *AnnotatedBlock, pubsub.ClosableSubscription, error)
+
+	// WatchEvents returns a stream of protocol events for the given runtime.
+	//
+	// Each event is annotated with the consensus height at which it was emitted. Finalized events
+	// are only emitted after the corresponding block is available via GetLatestBlock.
 	WatchEvents(ctx context.Context, runtimeID common.Namespace) (<-chan *Event, pubsub.ClosableSubscription, error)
 
 	// TrackRuntime adds a runtime the history of which should be tracked.
@@ -128,6 +158,9 @@ type Backend interface {
 	// GetEvents returns the events at specified block height.
 	GetEvents(ctx context.Context, height int64) ([]*Event, error)
 
+	// GetEventsFiltered returns the events at specified block height that match the given filter.
+	GetEventsFiltered(ctx context.Context, height int64, filter *EventFilter) ([]*Event, error)
+
 	// Cleanup cleans up the roothash backend.
 	Cleanup()
 }
@@ -138,10 +171,20 @@ type RuntimeRequest struct {
 	Height    int64            `json:"height"`
 }
 
+// WatchBlocksFromRequest is a WatchBlocksFrom request.
+type WatchBlocksFromRequest struct {
+	RuntimeID  common.Namespace `json:"runtime_id"`
+	StartRound uint64           `json:"start_round"`
+}
+
 // ExecutorCommit is the argument set for the ExecutorCommit method.
 type ExecutorCommit struct {
 	ID      common.Namespace                `json:"id"`
 	Commits []commitment.ExecutorCommitment `json:"commits"`
+
+	// AggregatedCommits are executor commitments with identical bodies made by multiple executor
+	// committee members. They are only accepted when enabled via consensus parameters.
+	AggregatedCommits []commitment.AggregatedExecutorCommitment `json:"aggregated_commits,omitempty"`
 }
 
 // NewExecutorCommitTx creates a new executor commit transaction.
@@ -152,6 +195,17 @@ func NewExecutorCommitTx(nonce uint64, fee *transaction.Fee, runtimeID common.Na
 	})
 }
 
+// NewAggregatedExecutorCommitTx creates a new executor commit transaction where commitments with
+// identical bodies are aggregated in order to reduce the transaction size.
+func NewAggregatedExecutorCommitTx(nonce uint64, fee *transaction.Fee, runtimeID common.Namespace, commits []commitment.ExecutorCommitment) *transaction.Transaction {
+	aggregated, rest := commitment.AggregateExecutorCommitments(commits)
+	return transaction.NewTransaction(nonce, fee, MethodExecutorCommit, &ExecutorCommit{
+		ID:                runtimeID,
+		Commits:           rest,
+		AggregatedCommits: aggregated,
+	})
+}
+
 // ExecutorProposerTimeoutRequest is an executor proposer timeout request.
 type ExecutorProposerTimeoutRequest struct {
 	ID    common.Namespace `json:"id"`
@@ -309,6 +363,39 @@ func NewEvidenceTx(nonce uint64, fee *transaction.Fee, evidence *Evidence) *tran
 	return transaction.NewTransaction(nonce, fee, MethodEvidence, evidence)
 }
 
+// SetRoundTimeout is the argument set for the SetRoundTimeout method.
+type SetRoundTimeout struct {
+	// ID is the identifier of the runtime.
+	ID common.Namespace `json:"id"`
+	// RoundTimeout is the executor round timeout in consensus blocks which overrides the one in
+	// the runtime descriptor. Zero removes any existing override.
+	RoundTimeout int64 `json:"round_timeout"`
+}
+
+// ValidateBasic performs basic round timeout override validity checks.
+func (srt *SetRoundTimeout) ValidateBasic() error {
+	if srt.RoundTimeout < 0 {
+		return fmt.Errorf("negative round timeout")
+	}
+	return nil
+}
+
+// NewSetRoundTimeoutTx creates a new set round timeout transaction.
+func NewSetRoundTimeoutTx(nonce uint64, fee *transaction.Fee, runtimeID common.Namespace, roundTimeout int64) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodSetRoundTimeout, &SetRoundTimeout{
+		ID:           runtimeID,
+		RoundTimeout: roundTimeout,
+	})
+}
+
+// RetainedEvidence is a record of submitted evidence that is retained until it expires.
+type RetainedEvidence struct {
+	// Round is the runtime round that the evidence refers to.
+	Round uint64 `json:"round"`
+	// Hash is the evidence hash.
+	Hash hash.Hash `json:"hash"`
+}
+
 // RuntimeState is the per-runtime state.
 type RuntimeState struct {
 	Runtime   *registry.Runtime `json:"runtime"`
@@ -325,6 +412,13 @@ type RuntimeState struct {
 	// LastNormalHeight is the consensus block height corresponding to LastNormalRound.
 	LastNormalHeight int64 `json:"last_normal_height"`
 
+	// RoundTimeoutOverride is the executor round timeout which overrides the one specified in the
+	// runtime descriptor. Zero means that the round timeout is not overridden.
+	RoundTimeoutOverride int64 `json:"round_timeout_override,omitempty"`
+	// PendingRoundTimeoutOverride is the round timeout override that will take effect at the next
+	// epoch transition.
+	PendingRoundTimeoutOverride *int64 `json:"pending_round_timeout_override,omitempty"`
+
 	ExecutorPool *commitment.Pool `json:"executor_pool"`
 }
 
@@ -364,6 +458,16 @@ type FinalizedEvent struct {
 	BadComputeNodes []signature.PublicKey `json:"bad_compute_nodes,omitempty"`
 }
 
+// EvidenceEvent is an event emitted each time evidence of node misbehaviour is accepted.
+type EvidenceEvent struct {
+	// Round is the runtime round that the evidence refers to.
+	Round uint64 `json:"round"`
+	// Hash is the evidence hash.
+	Hash hash.Hash `json:"hash"`
+	// Node is the public key of the misbehaving node.
+	Node signature.PublicKey `json:"node"`
+}
+
 // MessageEvent is a runtime message processed event.
 type MessageEvent struct {
 	Module string `json:"module,omitempty"`
@@ -387,6 +491,106 @@ type Event struct {
 	ExecutionDiscrepancyDetected *ExecutionDiscrepancyDetectedEvent `json:"execution_discrepancy,omitempty"`
 	Finalized                    *FinalizedEvent                    `json:"finalized,omitempty"`
 	Message                      *MessageEvent                      `json:"message,omitempty"`
+	Evidence                     *EvidenceEvent                     `json:"evidence,omitempty"`
+}
+
+// Kind returns the kind of the event.
+func (e *Event) Kind() EventKind {
+	switch {
+	case e.ExecutorCommitted != nil:
+		return EventKindExecutorCommitted
+	case e.ExecutionDiscrepancyDetected != nil:
+		return EventKindExecutionDiscrepancyDetected
+	case e.Finalized != nil:
+		return EventKindFinalized
+	case e.Message != nil:
+		return EventKindMessage
+	case e.Evidence != nil:
+		return EventKindEvidence
+	default:
+		return EventKindInvalid
+	}
+}
+
+// EventKind is the kind of a roothash event.
+type EventKind uint8
+
+// Roothash event kinds.
+const (
+	EventKindInvalid                      EventKind = 0
+	EventKindExecutorCommitted            EventKind = 1
+	EventKindExecutionDiscrepancyDetected EventKind = 2
+	EventKindFinalized                    EventKind = 3
+	EventKindMessage                      EventKind = 4
+	EventKindEvidence                     EventKind = 5
+)
+
+// String returns a string representation of the event kind.
+func (k EventKind) String() string {
+	switch k {
+	case EventKindExecutorCommitted:
+		return "executor_committed"
+	case EventKindExecutionDiscrepancyDetected:
+		return "execution_discrepancy"
+	case EventKindFinalized:
+		return "finalized"
+	case EventKindMessage:
+		return "message"
+	case EventKindEvidence:
+		return "evidence"
+	default:
+		return "[invalid event kind]"
+	}
+}
+
+// EventFilter is a filter for roothash events.
+type EventFilter struct {
+	// RuntimeID is the identifier of the runtime the events must relate to. If not set, events
+	// of all runtimes match.
+	RuntimeID *common.Namespace `json:"runtime_id,omitempty"`
+
+	// Kinds are the kinds of the events to match. If empty, events of all kinds match.
+	Kinds []EventKind `json:"kinds,omitempty"`
+}
+
+// Matches returns true iff the given event matches the filter.
+func (f *EventFilter) Matches(ev *Event) bool {
+	if f == nil {
+		return true
+	}
+	if f.RuntimeID != nil && !f.RuntimeID.Equal(&ev.RuntimeID) {
+		return false
+	}
+	if len(f.Kinds) == 0 {
+		return true
+	}
+	kind := ev.Kind()
+	for _, k := range f.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the events that match the filter.
+func (f *EventFilter) Filter(evs []*Event) []*Event {
+	if f == nil {
+		return evs
+	}
+	var filtered []*Event
+	for _, ev := range evs {
+		if f.Matches(ev) {
+			filtered = append(filtered, ev)
+		}
+	}
+	return filtered
+}
+
+// GetEventsFilteredRequest is a GetEventsFiltered request.
+type GetEventsFilteredRequest struct {
+	Height int64        `json:"height"`
+	Filter *EventFilter `json:"filter,omitempty"`
 }
 
 // MetricsMonitorable is the interface exposed by backends capable of
@@ -430,11 +634,73 @@ type ConsensusParameters struct {
 	DebugBypassStake bool `json:"debug_bypass_stake,omitempty"`
 
 	// MaxRuntimeMessages is the maximum number of allowed messages that can be emitted by a runtime
-	// in a single round.
+	// in a single round. Runtimes can declare a lower limit in their descriptors.
 	MaxRuntimeMessages uint32 `json:"max_runtime_messages"`
 
+	// EnforceMaxRuntimeMessages is true iff the MaxRuntimeMessages limit should also be enforced
+	// for runtimes whose descriptors declare a higher limit.
+	EnforceMaxRuntimeMessages bool `json:"enforce_max_runtime_messages,omitempty"`
+
+	// AllowAggregatedCommitments is true iff executor commit transactions can include aggregated
+	// executor commitments.
+	AllowAggregatedCommitments bool `json:"allow_aggregated_commitments,omitempty"`
+
 	// MaxEvidenceAge is the maximum age of submitted evidence in the number of rounds.
 	MaxEvidenceAge uint64 `json:"max_evidence_age"`
+
+	// RoundTimeoutOverride are the bounds of executor round timeout overrides. If not set, the
+	// round timeout of a runtime cannot be overridden.
+	RoundTimeoutOverride *RoundTimeoutOverrideParameters `json:"round_timeout_override,omitempty"`
+}
+
+// RoundTimeoutOverrideParameters are the bounds of executor round timeout overrides.
+type RoundTimeoutOverrideParameters struct {
+	// MinRoundTimeout is the minimum round timeout override in consensus blocks.
+	MinRoundTimeout int64 `json:"min_round_timeout"`
+	// MaxRoundTimeout is the maximum round timeout override in consensus blocks.
+	MaxRoundTimeout int64 `json:"max_round_timeout"`
+}
+
+// ValidateBasic performs basic round timeout override parameter validity checks.
+func (p *RoundTimeoutOverrideParameters) ValidateBasic() error {
+	if p.MinRoundTimeout <= 0 {
+		return fmt.Errorf("minimum round timeout must be positive")
+	}
+	if p.MaxRoundTimeout < p.MinRoundTimeout {
+		return fmt.Errorf("maximum round timeout must not be lower than the minimum round timeout")
+	}
+	return nil
+}
+
+// VerifyRoundTimeoutOverride verifies whether the given executor round timeout override is
+// allowed. Zero removes any existing override and is allowed whenever overrides are enabled.
+func (p *ConsensusParameters) VerifyRoundTimeoutOverride(roundTimeout int64) error {
+	bounds := p.RoundTimeoutOverride
+	switch {
+	case bounds == nil:
+		return fmt.Errorf("%w: round timeout overrides not enabled", ErrInvalidArgument)
+	case roundTimeout == 0:
+		return nil
+	case roundTimeout < bounds.MinRoundTimeout || roundTimeout > bounds.MaxRoundTimeout:
+		return fmt.Errorf("%w: round timeout %d outside of allowed range [%d, %d]",
+			ErrInvalidArgument, roundTimeout, bounds.MinRoundTimeout, bounds.MaxRoundTimeout,
+		)
+	default:
+		return nil
+	}
+}
+
+// EffectiveMaxRuntimeMessages returns the maximum number of messages that the given runtime can
+// emit in a single round.
+//
+// In case EnforceMaxRuntimeMessages is set, this is the lower of the runtime's own limit (as
+// declared in its descriptor) and the global limit, as the global limit may have been lowered
+// after the runtime has been registered. Otherwise this is the runtime's own limit.
+func (p *ConsensusParameters) EffectiveMaxRuntimeMessages(rt *registry.Runtime) uint32 {
+	if !p.EnforceMaxRuntimeMessages || rt.Executor.MaxMessages < p.MaxRuntimeMessages {
+		return rt.Executor.MaxMessages
+	}
+	return p.MaxRuntimeMessages
 }
 
 const (
@@ -446,6 +712,9 @@ const (
 
 	// GasOpEvidence is the gas operation identifier for evidence submission transaction cost.
 	GasOpEvidence transaction.Op = "evidence"
+
+	// GasOpSetRoundTimeout is the gas operation identifier for round timeout override cost.
+	GasOpSetRoundTimeout transaction.Op = "set_round_timeout"
 )
 
 // XXX: Define reasonable default gas costs.
@@ -455,6 +724,7 @@ var DefaultGasCosts = transaction.Costs{
 	GasOpComputeCommit:   1000,
 	GasOpProposerTimeout: 1000,
 	GasOpEvidence:        1000,
+	GasOpSetRoundTimeout: 1000,
 }
 
 // SanityCheckBlocks examines the blocks table.
@@ -475,6 +745,11 @@ func (g *Genesis) SanityCheck() error {
 	if unsafeFlags && !flags.DebugDontBlameOasis() {
 		return fmt.Errorf("roothash: sanity check failed: one or more unsafe debug flags set")
 	}
+	if bounds := g.Parameters.RoundTimeoutOverride; bounds != nil {
+		if err := bounds.ValidateBasic(); err != nil {
+			return fmt.Errorf("roothash: sanity check failed: invalid round timeout override parameters: %w", err)
+		}
+	}
 
 	// Check blocks.
 	for _, rtg := range g.RuntimeStates {
@@ -11,6 +11,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
 	genesisTestHelpers "github.com/oasisprotocol/oasis-core/go/genesis/tests"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
 )
@@ -589,3 +590,70 @@ func TestEquivocationExecutorEvidenceValidateBasic(t *testing.T) {
 		}
 	}
 }
+
+func TestEventFilter(t *testing.T) {
+	require := require.New(t)
+
+	var rt1, rt2 common.Namespace
+	_ = rt1.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+	_ = rt2.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000001")
+
+	evs := []*Event{
+		{RuntimeID: rt1, Finalized: &FinalizedEvent{Round: 1}},
+		{RuntimeID: rt1, ExecutorCommitted: &ExecutorCommittedEvent{}},
+		{RuntimeID: rt2, Finalized: &FinalizedEvent{Round: 2}},
+		{RuntimeID: rt2, Message: &MessageEvent{Index: 1}},
+		{RuntimeID: rt2, Evidence: &EvidenceEvent{Round: 1}},
+	}
+
+	var filter *EventFilter
+	require.Equal(evs, filter.Filter(evs), "nil filter should match all events")
+
+	filter = &EventFilter{RuntimeID: &rt1}
+	require.Equal(evs[:2], filter.Filter(evs), "runtime filter should match runtime events")
+
+	filter = &EventFilter{Kinds: []EventKind{EventKindFinalized, EventKindMessage}}
+	require.Equal([]*Event{evs[0], evs[2], evs[3]}, filter.Filter(evs), "kind filter should match events of given kinds")
+
+	filter = &EventFilter{RuntimeID: &rt2, Kinds: []EventKind{EventKindFinalized}}
+	require.Equal([]*Event{evs[2]}, filter.Filter(evs), "combined filter should match events of both")
+
+	filter = &EventFilter{Kinds: []EventKind{EventKindEvidence}}
+	require.Equal([]*Event{evs[4]}, filter.Filter(evs), "kind filter should match evidence events")
+
+	filter = &EventFilter{Kinds: []EventKind{EventKindExecutionDiscrepancyDetected}}
+	require.Empty(filter.Filter(evs), "filter should match no events")
+
+	require.Equal(EventKindMessage, evs[3].Kind())
+	require.Equal("executor_committed", evs[1].Kind().String())
+	require.Equal("evidence", evs[4].Kind().String())
+}
+
+func TestEffectiveMaxRuntimeMessages(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		enforce     bool
+		maxMessages uint32
+		expected    uint32
+	}{
+		{false, 0, 0},
+		{false, 16, 16},
+		{false, 64, 64},
+		{true, 0, 0},
+		{true, 16, 16},
+		{true, 32, 32},
+		{true, 64, 32},
+	} {
+		params := ConsensusParameters{
+			MaxRuntimeMessages:        32,
+			EnforceMaxRuntimeMessages: tc.enforce,
+		}
+		rt := registry.Runtime{
+			Executor: registry.ExecutorParameters{
+				MaxMessages: tc.maxMessages,
+			},
+		}
+		require.EqualValues(tc.expected, params.EffectiveMaxRuntimeMessages(&rt), "effective limit for runtime limit %d (enforce: %t)", tc.maxMessages, tc.enforce)
+	}
+}
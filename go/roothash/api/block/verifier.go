@@ -0,0 +1,122 @@
+package block
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+var (
+	// ErrHeaderNamespaceMismatch is the error returned when a header is for a different namespace
+	// than the trusted header.
+	ErrHeaderNamespaceMismatch = errors.New("roothash: header has unexpected namespace")
+
+	// ErrHeaderRoundMismatch is the error returned when a header does not directly follow the
+	// previous header in the chain.
+	ErrHeaderRoundMismatch = errors.New("roothash: header has unexpected round")
+
+	// ErrHeaderPreviousHashMismatch is the error returned when a header does not link to the
+	// previous header in the chain.
+	ErrHeaderPreviousHashMismatch = errors.New("roothash: header has unexpected previous hash")
+
+	// ErrHeaderInvalidStorageSignatures is the error returned when the storage receipt signatures
+	// of a header are invalid.
+	ErrHeaderInvalidStorageSignatures = errors.New("roothash: header has invalid storage signatures")
+)
+
+// HeaderChainVerifierOption is an option for the header chain verifier.
+type HeaderChainVerifierOption func(v *HeaderChainVerifier)
+
+// WithStorageSigners restricts the storage receipt signatures included in headers to the given
+// set of storage node signers and requires each normal header to carry at least one storage
+// receipt signature.
+func WithStorageSigners(signers []signature.PublicKey) HeaderChainVerifierOption {
+	return func(v *HeaderChainVerifier) {
+		v.storageSigners = make(map[signature.PublicKey]bool, len(signers))
+		for _, signer := range signers {
+			v.storageSigners[signer] = true
+		}
+	}
+}
+
+// HeaderChainVerifier verifies chains of block headers starting at a trusted header.
+//
+// This enables light clients to validate ranges of blocks fetched from untrusted peers as long as
+// they have obtained a single trusted header (e.g., the latest block from consensus).
+type HeaderChainVerifier struct {
+	trusted Header
+
+	storageSigners map[signature.PublicKey]bool
+}
+
+// Trusted returns the latest trusted header.
+func (v *HeaderChainVerifier) Trusted() *Header {
+	h := v.trusted
+	return &h
+}
+
+// Verify verifies a sequence of headers directly following the latest trusted header.
+//
+// Each header must have the same namespace as the trusted header, must be for the round directly
+// following the previous header and must link to the previous header via its previous hash. Any
+// storage receipt signatures included in the headers must be valid. In case storage signers are
+// configured, normal headers must also include at least one storage receipt signature.
+//
+// In case the whole sequence is valid, the last header becomes the latest trusted header. In case
+// verification fails, the latest trusted header is not changed.
+func (v *HeaderChainVerifier) Verify(headers []*Header) error {
+	prev := &v.trusted
+	for _, h := range headers {
+		if err := v.verifyNext(prev, h); err != nil {
+			return err
+		}
+		prev = h
+	}
+
+	v.trusted = *prev
+	return nil
+}
+
+func (v *HeaderChainVerifier) verifyNext(prev, h *Header) error {
+	if !h.Namespace.Equal(&prev.Namespace) {
+		return fmt.Errorf("%w (expected: %s got: %s)", ErrHeaderNamespaceMismatch, prev.Namespace, h.Namespace)
+	}
+	if h.Round != prev.Round+1 {
+		return fmt.Errorf("%w (expected: %d got: %d)", ErrHeaderRoundMismatch, prev.Round+1, h.Round)
+	}
+	if prevHash := prev.EncodedHash(); !h.PreviousHash.Equal(&prevHash) {
+		return fmt.Errorf("%w (round: %d expected: %s got: %s)", ErrHeaderPreviousHashMismatch, h.Round, prevHash, h.PreviousHash)
+	}
+
+	if len(h.StorageSignatures) == 0 {
+		// Only normal headers carry storage receipts, headers resulting from failed rounds, epoch
+		// transitions or suspensions do not.
+		if v.storageSigners != nil && h.HeaderType == Normal {
+			return fmt.Errorf("%w (round: %d): no storage signatures", ErrHeaderInvalidStorageSignatures, h.Round)
+		}
+		return nil
+	}
+	if v.storageSigners != nil {
+		for _, sig := range h.StorageSignatures {
+			if !v.storageSigners[sig.PublicKey] {
+				return fmt.Errorf("%w (round: %d unexpected signer: %s)", ErrHeaderInvalidStorageSignatures, h.Round, sig.PublicKey)
+			}
+		}
+	}
+	if err := h.VerifyStorageReceiptSignatures(); err != nil {
+		return fmt.Errorf("%w (round: %d): %v", ErrHeaderInvalidStorageSignatures, h.Round, err)
+	}
+	return nil
+}
+
+// NewHeaderChainVerifier creates a new header chain verifier starting at the given trusted header.
+func NewHeaderChainVerifier(trusted *Header, opts ...HeaderChainVerifierOption) *HeaderChainVerifier {
+	v := &HeaderChainVerifier{
+		trusted: *trusted,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
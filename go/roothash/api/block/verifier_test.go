@@ -0,0 +1,125 @@
+package block
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	genesisTestHelpers "github.com/oasisprotocol/oasis-core/go/genesis/tests"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+)
+
+func TestHeaderChainVerifier(t *testing.T) {
+	require := require.New(t)
+
+	genesisTestHelpers.SetTestChainContext()
+
+	ns := common.NewTestNamespaceFromSeed([]byte("header chain verifier test"), 0)
+	otherNs := common.NewTestNamespaceFromSeed([]byte("header chain verifier test"), 1)
+
+	storageSigner, err := memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner")
+	otherSigner, err := memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner")
+
+	signHeader := func(h *Header, signer signature.Signer) {
+		receipt, rerr := storage.SignReceipt(signer, h.Namespace, h.Round, h.RootTypesForStorageReceipt(), h.RootsForStorageReceipt())
+		require.NoError(rerr, "SignReceipt")
+		h.StorageSignatures = []signature.Signature{receipt.Signature}
+	}
+
+	// Build a chain of headers.
+	genesis := NewGenesisBlock(ns, 0)
+	blocks := []*Block{genesis}
+	for i := 0; i < 5; i++ {
+		blk := NewEmptyBlock(blocks[len(blocks)-1], uint64(i+1), Normal)
+		blk.Header.StateRoot = hash.NewFromBytes([]byte{byte(i)})
+		signHeader(&blk.Header, storageSigner)
+		blocks = append(blocks, blk)
+	}
+	var headers []*Header
+	for _, blk := range blocks[1:] {
+		h := blk.Header
+		headers = append(headers, &h)
+	}
+
+	// Valid chain.
+	v := NewHeaderChainVerifier(&genesis.Header, WithStorageSigners([]signature.PublicKey{storageSigner.Public()}))
+	err = v.Verify(headers[:2])
+	require.NoError(err, "Verify should succeed for a valid chain")
+	require.EqualValues(*headers[1], *v.Trusted(), "last verified header should become trusted")
+	err = v.Verify(headers[2:])
+	require.NoError(err, "Verify should succeed when continuing the chain")
+	require.EqualValues(*headers[4], *v.Trusted(), "last verified header should become trusted")
+	err = v.Verify(nil)
+	require.NoError(err, "Verify should succeed for an empty chain")
+	require.EqualValues(*headers[4], *v.Trusted(), "trusted header should not change for an empty chain")
+
+	// Invalid chains.
+	for _, tc := range []struct {
+		name        string
+		modify      func(h *Header)
+		expectedErr error
+	}{
+		{
+			"WrongNamespace",
+			func(h *Header) { h.Namespace = otherNs },
+			ErrHeaderNamespaceMismatch,
+		},
+		{
+			"WrongRound",
+			func(h *Header) { h.Round++ },
+			ErrHeaderRoundMismatch,
+		},
+		{
+			"WrongPreviousHash",
+			func(h *Header) { h.PreviousHash = hash.NewFromBytes([]byte("wrong")) },
+			ErrHeaderPreviousHashMismatch,
+		},
+		{
+			"WrongStorageSignatures",
+			func(h *Header) { h.StateRoot = hash.NewFromBytes([]byte("wrong")) },
+			ErrHeaderInvalidStorageSignatures,
+		},
+		{
+			"UnexpectedStorageSigner",
+			func(h *Header) { signHeader(h, otherSigner) },
+			ErrHeaderInvalidStorageSignatures,
+		},
+		{
+			"MissingStorageSignatures",
+			func(h *Header) { h.StorageSignatures = nil },
+			ErrHeaderInvalidStorageSignatures,
+		},
+	} {
+		modified := *headers[2]
+		tc.modify(&modified)
+		chain := []*Header{headers[0], headers[1], &modified, headers[3]}
+
+		v = NewHeaderChainVerifier(&genesis.Header, WithStorageSigners([]signature.PublicKey{storageSigner.Public()}))
+		err = v.Verify(chain)
+		require.Error(err, "Verify should fail for an invalid chain (%s)", tc.name)
+		require.True(errors.Is(err, tc.expectedErr), "Verify should fail with the expected error (%s got: %s)", tc.name, err)
+		require.EqualValues(genesis.Header, *v.Trusted(), "trusted header should not change on failure (%s)", tc.name)
+	}
+
+	// Without restricting storage signers, any valid storage signatures should be accepted.
+	modified := *headers[0]
+	signHeader(&modified, otherSigner)
+	v = NewHeaderChainVerifier(&genesis.Header)
+	err = v.Verify([]*Header{&modified})
+	require.NoError(err, "Verify should succeed with any valid storage signatures")
+
+	// Headers which do not carry storage receipts should be accepted even when storage signers
+	// are restricted.
+	epochBlk := NewEmptyBlock(genesis, 1, EpochTransition)
+	v = NewHeaderChainVerifier(&genesis.Header, WithStorageSigners([]signature.PublicKey{storageSigner.Public()}))
+	err = v.Verify([]*Header{&epochBlk.Header})
+	require.NoError(err, "Verify should succeed for headers without storage receipts")
+}
@@ -0,0 +1,123 @@
+package commitment
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+// AggregatedExecutorCommitment is an aggregate of executor commitments with identical bodies
+// made by multiple executor committee members.
+//
+// Instead of including the (potentially large) body once for each committee member, the body is
+// only included once together with the signatures of all committee members. The signatures are
+// verified together using batch verification.
+//
+// The signed content is ComputeBody.
+type AggregatedExecutorCommitment struct {
+	signature.MultiSigned
+}
+
+// ValidateBasic performs basic aggregated executor commitment validity checks.
+func (a *AggregatedExecutorCommitment) ValidateBasic() error {
+	if len(a.Signatures) == 0 {
+		return fmt.Errorf("roothash/commitment: aggregated commitment has no signatures")
+	}
+
+	signers := make(map[signature.PublicKey]bool, len(a.Signatures))
+	for _, sig := range a.Signatures {
+		if signers[sig.PublicKey] {
+			return fmt.Errorf("roothash/commitment: aggregated commitment has duplicate signer %s", sig.PublicKey)
+		}
+		signers[sig.PublicKey] = true
+	}
+	return nil
+}
+
+// Open validates all of the aggregated commitment signatures using batch verification, and
+// de-serializes the message into the individual open executor commitments.
+// This does not validate the RAK signature.
+func (a *AggregatedExecutorCommitment) Open(runtimeID common.Namespace) ([]*OpenExecutorCommitment, error) {
+	if err := a.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	sigCtx, err := ExecutorSignatureContext.WithSuffix(runtimeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("roothash/commitment: signature context error: %w", err)
+	}
+
+	var body ComputeBody
+	if err := a.MultiSigned.Open(sigCtx, &body); err != nil {
+		return nil, errors.New("roothash/commitment: aggregated commitment has invalid signature")
+	}
+
+	commits := a.Commitments()
+	openComs := make([]*OpenExecutorCommitment, 0, len(commits))
+	for _, commit := range commits {
+		commitBody := body
+		openComs = append(openComs, &OpenExecutorCommitment{
+			ExecutorCommitment: commit,
+			Body:               &commitBody,
+		})
+	}
+	return openComs, nil
+}
+
+// Commitments returns the individual executor commitments contained in the aggregate.
+//
+// Note: This does not verify the signatures.
+func (a *AggregatedExecutorCommitment) Commitments() []ExecutorCommitment {
+	commits := make([]ExecutorCommitment, 0, len(a.Signatures))
+	for _, sig := range a.Signatures {
+		commits = append(commits, ExecutorCommitment{
+			Signed: signature.Signed{
+				Blob:      a.Blob,
+				Signature: sig,
+			},
+		})
+	}
+	return commits
+}
+
+// AggregateExecutorCommitments aggregates executor commitments with identical bodies.
+//
+// Commitments with a body that is not shared with any other commitment are returned unaggregated
+// as aggregation would not result in any savings. The relative order of commitments is preserved.
+func AggregateExecutorCommitments(commits []ExecutorCommitment) ([]AggregatedExecutorCommitment, []ExecutorCommitment) {
+	var order []hash.Hash
+	groups := make(map[hash.Hash][]ExecutorCommitment)
+	for _, commit := range commits {
+		h := hash.NewFromBytes(commit.Blob)
+		if _, ok := groups[h]; !ok {
+			order = append(order, h)
+		}
+		groups[h] = append(groups[h], commit)
+	}
+
+	var (
+		aggregated []AggregatedExecutorCommitment
+		rest       []ExecutorCommitment
+	)
+	for _, h := range order {
+		group := groups[h]
+		if len(group) == 1 {
+			rest = append(rest, group[0])
+			continue
+		}
+
+		agg := AggregatedExecutorCommitment{
+			MultiSigned: signature.MultiSigned{
+				Blob: group[0].Blob,
+			},
+		}
+		for _, commit := range group {
+			agg.Signatures = append(agg.Signatures, commit.Signature)
+		}
+		aggregated = append(aggregated, agg)
+	}
+	return aggregated, rest
+}
@@ -0,0 +1,98 @@
+package commitment
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	genesisTestHelpers "github.com/oasisprotocol/oasis-core/go/genesis/tests"
+)
+
+func TestAggregateExecutorCommitments(t *testing.T) {
+	require := require.New(t)
+
+	genesisTestHelpers.SetTestChainContext()
+
+	rtID := common.NewTestNamespaceFromSeed([]byte("aggregated commitment test"), 0)
+	otherRtID := common.NewTestNamespaceFromSeed([]byte("aggregated commitment test"), 1)
+
+	var signers []signature.Signer
+	for i := 0; i < 4; i++ {
+		sk, err := memorySigner.NewSigner(rand.Reader)
+		require.NoError(err, "NewSigner")
+		signers = append(signers, sk)
+	}
+
+	body := ComputeBody{Header: ComputeResultsHeader{Round: 42}}
+	otherBody := ComputeBody{Header: ComputeResultsHeader{Round: 43}}
+
+	var commits []ExecutorCommitment
+	for i, sk := range signers {
+		b := &body
+		if i == 2 {
+			b = &otherBody
+		}
+		commit, err := SignExecutorCommitment(sk, rtID, b)
+		require.NoError(err, "SignExecutorCommitment")
+		commits = append(commits, *commit)
+	}
+
+	aggregated, rest := AggregateExecutorCommitments(commits)
+	require.Len(aggregated, 1, "commitments with identical bodies should be aggregated")
+	require.Len(rest, 1, "commitments with unique bodies should not be aggregated")
+	require.EqualValues(commits[2], rest[0], "unaggregated commitment should be unchanged")
+
+	agg := aggregated[0]
+	require.Len(agg.Signatures, 3, "aggregated commitment should contain all signatures")
+	require.True(len(cbor.Marshal(agg)) < len(cbor.Marshal([]ExecutorCommitment{commits[0], commits[1], commits[3]})),
+		"aggregated commitment should be smaller than individual commitments")
+
+	openComs, err := agg.Open(rtID)
+	require.NoError(err, "Open")
+	require.Len(openComs, 3, "all commitments should be opened")
+	for i, openCom := range openComs {
+		require.EqualValues(body, *openCom.Body, "opened body should be correct")
+		require.EqualValues(agg.Signatures[i], openCom.Signature, "opened commitment should have the right signature")
+	}
+
+	_, err = agg.Open(otherRtID)
+	require.Error(err, "Open should fail for a different runtime")
+
+	expanded := agg.Commitments()
+	require.EqualValues([]ExecutorCommitment{commits[0], commits[1], commits[3]}, expanded, "expanded commitments should match")
+	for _, commit := range expanded {
+		_, err = commit.Open(rtID)
+		require.NoError(err, "Open expanded commitment")
+	}
+
+	// Tampered signatures should fail verification.
+	tampered := AggregatedExecutorCommitment{
+		MultiSigned: signature.MultiSigned{
+			Blob:       agg.Blob,
+			Signatures: append([]signature.Signature{}, agg.Signatures...),
+		},
+	}
+	tampered.Signatures[1] = commits[2].Signature
+	_, err = tampered.Open(rtID)
+	require.Error(err, "Open should fail with an invalid signature")
+
+	// Duplicate signers should be rejected.
+	duplicate := AggregatedExecutorCommitment{
+		MultiSigned: signature.MultiSigned{
+			Blob:       agg.Blob,
+			Signatures: []signature.Signature{agg.Signatures[0], agg.Signatures[0]},
+		},
+	}
+	require.Error(duplicate.ValidateBasic(), "ValidateBasic should fail with duplicate signers")
+	_, err = duplicate.Open(rtID)
+	require.Error(err, "Open should fail with duplicate signers")
+
+	// Empty aggregates should be rejected.
+	var empty AggregatedExecutorCommitment
+	require.Error(empty.ValidateBasic(), "ValidateBasic should fail without signatures")
+}
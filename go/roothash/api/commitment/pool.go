@@ -406,6 +406,34 @@ func (p *Pool) AddExecutorCommitment(
 	return p.addOpenExecutorCommitment(ctx, blk, sv, nl, msgValidator, openCom)
 }
 
+// AddAggregatedExecutorCommitment verifies and adds all executor commitments contained in the
+// given aggregated commitment to the pool. The commitment signatures are verified together using
+// batch verification.
+func (p *Pool) AddAggregatedExecutorCommitment(
+	ctx context.Context,
+	blk *block.Block,
+	sv SignatureVerifier,
+	nl NodeLookup,
+	agg *AggregatedExecutorCommitment,
+	msgValidator MessageValidator,
+) error {
+	if p.Runtime == nil {
+		return ErrNoRuntime
+	}
+	// Check the commitment signatures and de-serialize into headers.
+	openComs, err := agg.Open(p.Runtime.ID)
+	if err != nil {
+		return p2pError.Permanent(err)
+	}
+
+	for _, openCom := range openComs {
+		if err = p.addOpenExecutorCommitment(ctx, blk, sv, nl, msgValidator, openCom); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ProcessCommitments performs a single round of commitment checks. If there are enough commitments
 // in the pool, it performs discrepancy detection or resolution.
 func (p *Pool) ProcessCommitments(didTimeout bool) (OpenCommitment, error) {
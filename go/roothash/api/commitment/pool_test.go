@@ -499,6 +499,50 @@ func TestPoolTwoCommitments(t *testing.T) {
 		require.EqualValues(t, &body.Header, &header, "DD should return the same header")
 	})
 
+	t.Run("Aggregated", func(t *testing.T) {
+		// Create a pool.
+		pool := Pool{
+			Runtime:   rt,
+			Committee: committee,
+			Round:     0,
+		}
+
+		// Generate an aggregated commitment.
+		childBlk, _, body := generateComputeBody(t, pool.Round)
+
+		commit1, err := SignExecutorCommitment(sk1, rt.ID, &body)
+		require.NoError(t, err, "SignExecutorCommitment")
+
+		commit2, err := SignExecutorCommitment(sk2, rt.ID, &body)
+		require.NoError(t, err, "SignExecutorCommitment")
+
+		aggregated, rest := AggregateExecutorCommitments([]ExecutorCommitment{*commit1, *commit2})
+		require.Len(t, aggregated, 1, "commitments should be aggregated")
+		require.Empty(t, rest, "all commitments should be aggregated")
+
+		// Adding an aggregated commitment with an invalid signature should fail.
+		tampered := aggregated[0]
+		tampered.Signatures = []signature.Signature{commit1.Signature, commit1.Signature}
+		tampered.Signatures[1].PublicKey = commit2.Signature.PublicKey
+		err = pool.AddAggregatedExecutorCommitment(context.Background(), childBlk, nopSV, nl, &tampered, nil)
+		require.Error(t, err, "AddAggregatedExecutorCommitment")
+
+		// Adding the aggregated commitment should succeed.
+		err = pool.AddAggregatedExecutorCommitment(context.Background(), childBlk, nopSV, nl, &aggregated[0], nil)
+		require.NoError(t, err, "AddAggregatedExecutorCommitment")
+
+		// There should be enough executor commitments and no discrepancy.
+		dc, err := pool.ProcessCommitments(false)
+		require.NoError(t, err, "ProcessCommitments")
+		require.Equal(t, false, pool.Discrepancy)
+		header := dc.ToDDResult().(*ComputeBody).Header
+		require.EqualValues(t, &body.Header, &header, "DD should return the same header")
+
+		// Adding the same commitments again should fail.
+		err = pool.AddExecutorCommitment(context.Background(), childBlk, nopSV, nl, commit1, nil)
+		require.Error(t, err, "AddExecutorCommitment(context.Background(), duplicate)")
+	})
+
 	t.Run("Discrepancy", func(t *testing.T) {
 		pool, childBlk, _, correctBody, _ := setupDiscrepancy(t, rt, sks, committee, nl, false)
 
@@ -2,8 +2,10 @@ package api
 
 import (
 	"context"
+	"fmt"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
@@ -11,6 +13,10 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 )
 
+// streamEstablishedHeader is the header sent by the server once a WatchBlocksFrom stream has been
+// successfully established.
+const streamEstablishedHeader = "x-oasis-stream-established"
+
 var (
 	// serviceName is the gRPC service name.
 	serviceName = cmnGrpc.NewServiceName("RootHash")
@@ -21,17 +27,25 @@ var (
 	methodGetLatestBlock = serviceName.NewMethod("GetLatestBlock", RuntimeRequest{})
 	// methodGetRuntimeState is the GetRuntimeState method.
 	methodGetRuntimeState = serviceName.NewMethod("GetRuntimeState", RuntimeRequest{})
+	// methodGetRoundResults is the GetRoundResults method.
+	methodGetRoundResults = serviceName.NewMethod("GetRoundResults", RuntimeRequest{})
+	// methodGetRetainedEvidence is the GetRetainedEvidence method.
+	methodGetRetainedEvidence = serviceName.NewMethod("GetRetainedEvidence", RuntimeRequest{})
 	// methodStateToGenesis is the StateToGenesis method.
 	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0))
 	// methodConsensusParameters is the ConsensusParameters method.
 	methodConsensusParameters = serviceName.NewMethod("ConsensusParameters", int64(0))
 	// methodGetEvents is the GetEvents method.
 	methodGetEvents = serviceName.NewMethod("GetEvents", int64(0))
+	// methodGetEventsFiltered is the GetEventsFiltered method.
+	methodGetEventsFiltered = serviceName.NewMethod("GetEventsFiltered", GetEventsFilteredRequest{})
 
 	// methodWatchBlocks is the WatchBlocks method.
 	methodWatchBlocks = serviceName.NewMethod("WatchBlocks", common.Namespace{})
 	// methodWatchEvents is the WatchEvents method.
 	methodWatchEvents = serviceName.NewMethod("WatchEvents", common.Namespace{})
+	// methodWatchBlocksFrom is the WatchBlocksFrom method.
+	methodWatchBlocksFrom = serviceName.NewMethod("WatchBlocksFrom", WatchBlocksFromRequest{})
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -50,6 +64,14 @@ var (
 				MethodName: methodGetRuntimeState.ShortName(),
 				Handler:    handlerGetRuntimeState,
 			},
+			{
+				MethodName: methodGetRoundResults.ShortName(),
+				Handler:    handlerGetRoundResults,
+			},
+			{
+				MethodName: methodGetRetainedEvidence.ShortName(),
+				Handler:    handlerGetRetainedEvidence,
+			},
 			{
 				MethodName: methodStateToGenesis.ShortName(),
 				Handler:    handlerStateToGenesis,
@@ -62,6 +84,10 @@ var (
 				MethodName: methodGetEvents.ShortName(),
 				Handler:    handlerGetEvents,
 			},
+			{
+				MethodName: methodGetEventsFiltered.ShortName(),
+				Handler:    handlerGetEventsFiltered,
+			},
 		},
 		Streams: []grpc.StreamDesc{
 			{
@@ -74,6 +100,11 @@ var (
 				Handler:       handlerWatchEvents,
 				ServerStreams: true,
 			},
+			{
+				StreamName:    methodWatchBlocksFrom.ShortName(),
+				Handler:       handlerWatchBlocksFrom,
+				ServerStreams: true,
+			},
 		},
 	}
 )
@@ -147,6 +178,52 @@ func handlerGetRuntimeState( // nolint: golint
 	return interceptor(ctx, &rq, info, handler)
 }
 
+func handlerGetRoundResults( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var rq RuntimeRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetRoundResults(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetRoundResults.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetRoundResults(ctx, req.(*RuntimeRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
+func handlerGetRetainedEvidence( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var rq RuntimeRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetRetainedEvidence(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetRetainedEvidence.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetRetainedEvidence(ctx, req.(*RuntimeRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
 func handlerStateToGenesis( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -216,6 +293,30 @@ func handlerGetEvents( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetEventsFiltered( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var rq GetEventsFilteredRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetEventsFiltered(ctx, rq.Height, rq.Filter)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetEventsFiltered.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(*GetEventsFilteredRequest)
+		return srv.(Backend).GetEventsFiltered(ctx, r.Height, r.Filter)
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
 func handlerWatchBlocks(srv interface{}, stream grpc.ServerStream) error {
 	var runtimeID common.Namespace
 	if err := stream.RecvMsg(&runtimeID); err != nil {
@@ -274,6 +375,41 @@ func handlerWatchEvents(srv interface{}, stream grpc.ServerStream) error {
 	}
 }
 
+func handlerWatchBlocksFrom(srv interface{}, stream grpc.ServerStream) error {
+	var rq WatchBlocksFromRequest
+	if err := stream.RecvMsg(&rq); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(Backend).WatchBlocksFrom(ctx, rq.RuntimeID, rq.StartRound)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	// Send headers immediately so that the client can distinguish a successfully established
+	// stream from an error before any blocks are available.
+	if err = stream.SendHeader(metadata.Pairs(streamEstablishedHeader, "true")); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case blk, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(blk); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // RegisterService registers a new roothash service with the given gRPC server.
 func RegisterService(server *grpc.Server, service Backend) {
 	server.RegisterService(&serviceDesc, service)
@@ -307,6 +443,22 @@ func (c *roothashClient) GetRuntimeState(ctx context.Context, request *RuntimeRe
 	return &rsp, nil
 }
 
+func (c *roothashClient) GetRoundResults(ctx context.Context, request *RuntimeRequest) (*LastRoundResults, error) {
+	var rsp LastRoundResults
+	if err := c.conn.Invoke(ctx, methodGetRoundResults.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *roothashClient) GetRetainedEvidence(ctx context.Context, request *RuntimeRequest) ([]*RetainedEvidence, error) {
+	var rsp []*RetainedEvidence
+	if err := c.conn.Invoke(ctx, methodGetRetainedEvidence.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
 func (c *roothashClient) TrackRuntime(ctx context.Context, history BlockHistory) error {
 	return ErrInvalidArgument
 }
@@ -335,6 +487,18 @@ func (c *roothashClient) GetEvents(ctx context.Context, height int64) ([]*Event,
 	return rsp, nil
 }
 
+func (c *roothashClient) GetEventsFiltered(ctx context.Context, height int64, filter *EventFilter) ([]*Event, error) {
+	var rsp []*Event
+	rq := &GetEventsFilteredRequest{
+		Height: height,
+		Filter: filter,
+	}
+	if err := c.conn.Invoke(ctx, methodGetEventsFiltered.FullName(), rq, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
 func (c *roothashClient) Cleanup() {
 }
 
@@ -373,6 +537,58 @@ func (c *roothashClient) WatchBlocks(ctx context.Context, runtimeID common.Names
 	return ch, sub, nil
 }
 
+func (c *roothashClient) WatchBlocksFrom(ctx context.Context, runtimeID common.Namespace, startRound uint64) (<-chan *AnnotatedBlock, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[2], methodWatchBlocksFrom.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	rq := &WatchBlocksFromRequest{
+		RuntimeID:  runtimeID,
+		StartRound: startRound,
+	}
+	if err = stream.SendMsg(rq); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+	// Wait for the stream to be established so that errors (e.g., ErrHistoryNotAvailable) are
+	// returned to the caller instead of just closing the channel.
+	md, err := stream.Header()
+	if err == nil && len(md.Get(streamEstablishedHeader)) == 0 {
+		// The server terminated the stream without establishing it, retrieve the error.
+		if err = stream.RecvMsg(&AnnotatedBlock{}); err == nil {
+			err = fmt.Errorf("roothash: unexpected message before stream was established")
+		}
+	}
+	if err != nil {
+		sub.Close()
+		return nil, nil, err
+	}
+
+	ch := make(chan *AnnotatedBlock)
+	go func() {
+		defer close(ch)
+
+		for {
+			var blk AnnotatedBlock
+			if serr := stream.RecvMsg(&blk); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &blk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
 func (c *roothashClient) WatchEvents(ctx context.Context, runtimeID common.Namespace) (<-chan *Event, pubsub.ClosableSubscription, error) {
 	ctx, sub := pubsub.NewContextSubscription(ctx)
 
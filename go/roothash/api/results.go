@@ -15,3 +15,14 @@ type RoundResults struct {
 	// negatively contributed to the round by causing discrepancies.
 	BadComputeEntities []signature.PublicKey `json:"bad_compute_entities,omitempty"`
 }
+
+// LastRoundResults contains the results of a runtime's last normal round.
+type LastRoundResults struct {
+	// Round is the runtime round the results are for.
+	Round uint64 `json:"round"`
+	// Height is the consensus height at which the round was finalized.
+	Height int64 `json:"height"`
+
+	// Results are the results of the round.
+	Results *RoundResults `json:"results"`
+}
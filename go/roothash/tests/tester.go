@@ -24,6 +24,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/message"
 	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
@@ -87,6 +88,14 @@ func RootHashImplementationTests(t *testing.T, backend api.Backend, consensus co
 		testConsensusParameters(t, backend)
 	})
 
+	t.Run("EffectiveMaxRuntimeMessages", func(t *testing.T) {
+		testEffectiveMaxRuntimeMessages(t, backend, rtStates[0])
+	})
+
+	t.Run("WatchBlocksFrom", func(t *testing.T) {
+		testWatchBlocksFromUntracked(t, backend, rtStates[0])
+	})
+
 	// Run the various tests. (Ordering matters)
 	for _, v := range rtStates {
 		t.Run("GenesisBlock/"+v.id, func(t *testing.T) {
@@ -104,7 +113,11 @@ func RootHashImplementationTests(t *testing.T, backend api.Backend, consensus co
 	// EpochTransitionBlock was successful. Otherwise this may leave the
 	// committees set to nil and cause a crash.
 	t.Run("SuccessfulRound", func(t *testing.T) {
-		testSuccessfulRound(t, backend, consensus, identity, rtStates)
+		testSuccessfulRound(t, backend, consensus, identity, rtStates, false)
+	})
+
+	t.Run("SuccessfulRoundAggregated", func(t *testing.T) {
+		testSuccessfulRound(t, backend, consensus, identity, rtStates, true)
 	})
 
 	t.Run("RoundTimeout", func(t *testing.T) {
@@ -130,6 +143,27 @@ func testConsensusParameters(t *testing.T, backend api.Backend) {
 	params, err := backend.ConsensusParameters(ctx, consensusAPI.HeightLatest)
 	require.NoError(t, err, "ConsensusParameters")
 	require.EqualValues(t, 32, params.MaxRuntimeMessages, "expected max runtime messages value")
+	require.True(t, params.EnforceMaxRuntimeMessages, "max runtime messages should be enforced")
+}
+
+func testEffectiveMaxRuntimeMessages(t *testing.T, backend api.Backend, state *runtimeState) {
+	ctx := context.Background()
+
+	params, err := backend.ConsensusParameters(ctx, consensusAPI.HeightLatest)
+	require.NoError(t, err, "ConsensusParameters")
+	rs, err := backend.GetRuntimeState(ctx, &api.RuntimeRequest{
+		RuntimeID: state.rt.Runtime.ID,
+		Height:    consensusAPI.HeightLatest,
+	})
+	require.NoError(t, err, "GetRuntimeState")
+	maxMessages := params.EffectiveMaxRuntimeMessages(rs.Runtime)
+	require.EqualValues(t, state.rt.Runtime.Executor.MaxMessages, maxMessages, "expected effective max runtime messages value")
+}
+
+func testWatchBlocksFromUntracked(t *testing.T, backend api.Backend, state *runtimeState) {
+	// The block history of test runtimes is not tracked by the node.
+	_, _, err := backend.WatchBlocksFrom(context.Background(), state.rt.Runtime.ID, 0)
+	require.ErrorIs(t, err, api.ErrHistoryNotAvailable, "WatchBlocksFrom should fail without block history")
 }
 
 func testGenesisBlock(t *testing.T, backend api.Backend, state *runtimeState) {
@@ -260,13 +294,13 @@ func (s *runtimeState) testEpochTransitionBlock(t *testing.T, consensus consensu
 	}
 }
 
-func testSuccessfulRound(t *testing.T, backend api.Backend, consensus consensusAPI.Backend, identity *identity.Identity, states []*runtimeState) {
+func testSuccessfulRound(t *testing.T, backend api.Backend, consensus consensusAPI.Backend, identity *identity.Identity, states []*runtimeState, aggregated bool) {
 	for _, state := range states {
-		state.testSuccessfulRound(t, backend, consensus, identity)
+		state.testSuccessfulRound(t, backend, consensus, identity, aggregated)
 	}
 }
 
-func (s *runtimeState) generateExecutorCommitments(t *testing.T, consensus consensusAPI.Backend, identity *identity.Identity, child *block.Block) (
+func (s *runtimeState) generateExecutorCommitments(t *testing.T, consensus consensusAPI.Backend, identity *identity.Identity, child *block.Block, msgs []message.Message) (
 	parent *block.Block,
 	executorCommits []commitment.ExecutorCommitment,
 	executorNodes []*registryTests.TestNode,
@@ -333,8 +367,8 @@ func (s *runtimeState) generateExecutorCommitments(t *testing.T, consensus conse
 		},
 	)
 
-	var msgsHash hash.Hash
-	msgsHash.Empty()
+	msgsHash := message.MessagesHash(msgs)
+	parent.Header.MessagesHash = msgsHash
 
 	// Generate all the executor commitments.
 	executorNodes = append([]*registryTests.TestNode{}, executorCommittee.workers...)
@@ -374,6 +408,11 @@ func (s *runtimeState) generateExecutorCommitments(t *testing.T, consensus conse
 		}
 		require.NotNil(schedulerNode, "TransactionScheduler missing in test nodes")
 
+		// Only the transaction scheduler can include messages.
+		if node == schedulerNode {
+			commitBody.Messages = msgs
+		}
+
 		var signedDispatch *commitment.SignedProposedBatch
 		signedDispatch, err = commitment.SignProposedBatch(schedulerNode.Signer, s.rt.Runtime.ID, dispatch)
 		require.NoError(err, "SignProposedBatch")
@@ -388,7 +427,7 @@ func (s *runtimeState) generateExecutorCommitments(t *testing.T, consensus conse
 	return
 }
 
-func (s *runtimeState) testSuccessfulRound(t *testing.T, backend api.Backend, consensus consensusAPI.Backend, identity *identity.Identity) {
+func (s *runtimeState) testSuccessfulRound(t *testing.T, backend api.Backend, consensus consensusAPI.Backend, identity *identity.Identity, aggregated bool) {
 	require := require.New(t)
 
 	child, err := backend.GetLatestBlock(context.Background(), &api.RuntimeRequest{
@@ -401,12 +440,30 @@ func (s *runtimeState) testSuccessfulRound(t *testing.T, backend api.Backend, co
 	require.NoError(err, "WatchBlocks")
 	defer sub.Close()
 
+	evCh, evSub, err := backend.WatchEvents(context.Background(), s.rt.Runtime.ID)
+	require.NoError(err, "WatchEvents")
+	defer evSub.Close()
+
 	ctx, cancel := context.WithTimeout(context.Background(), recvTimeout)
 	defer cancel()
 
+	// Emit a runtime message so that its result is included in the round results.
+	msgs := []message.Message{
+		{
+			Staking: &message.StakingMessage{
+				Transfer: &staking.Transfer{
+					To: staking.NewRuntimeAddress(s.rt.Runtime.ID),
+				},
+			},
+		},
+	}
+
 	// Generate and submit all executor commitments.
-	parent, executorCommits, executorNodes := s.generateExecutorCommitments(t, consensus, identity, child)
+	parent, executorCommits, executorNodes := s.generateExecutorCommitments(t, consensus, identity, child, msgs)
 	tx := api.NewExecutorCommitTx(0, nil, s.rt.Runtime.ID, executorCommits)
+	if aggregated {
+		tx = api.NewAggregatedExecutorCommitTx(0, nil, s.rt.Runtime.ID, executorCommits)
+	}
 	err = consensusAPI.SignAndSubmitTx(ctx, consensus, executorNodes[0].Signer, tx)
 	require.NoError(err, "ExecutorCommit")
 
@@ -433,27 +490,63 @@ func (s *runtimeState) testSuccessfulRound(t *testing.T, backend api.Backend, co
 			require.EqualValues(parent.Header.PreviousHash, header.PreviousHash, "block previous hash")
 			require.EqualValues(parent.Header.IORoot, header.IORoot, "block I/O root")
 			require.EqualValues(parent.Header.StateRoot, header.StateRoot, "block root hash")
-
-			// There should be merge commitment events for all commitments.
-			evts, err := backend.GetEvents(ctx, blk.Height)
-			require.NoError(err, "GetEvents")
-			// Executor commit event + Finalized event.
-			require.Len(evts, len(executorCommits)+1, "should have all events")
-			// First event is Finalized.
-			fev := evts[0].Finalized
-			require.EqualValues(header.Round, fev.Round, "finalized event should have the right round")
-			require.Empty(fev.BadComputeNodes, "there should be no bad compute nodes")
-			require.Len(fev.GoodComputeNodes, len(executorNodes), "all nodes should be good (round %d)", fev.Round)
-			for i, ev := range evts[1:] {
+			require.EqualValues(parent.Header.MessagesHash, header.MessagesHash, "block messages hash")
+
+			// There should be executor commitment events for all commitments.
+			// Executor commit events + Message events + Finalized event.
+			evts := s.recvEvents(t, evCh, blk.Height, len(executorCommits)+len(msgs)+1)
+			require.Len(evts, len(executorCommits)+len(msgs)+1, "should have all events")
+			var (
+				fev          *api.FinalizedEvent
+				msgEvts      []*api.MessageEvent
+				executorEvts []commitment.ExecutorCommitment
+			)
+			for _, ev := range evts {
 				switch {
+				case ev.Finalized != nil:
+					require.Nil(fev, "there should be a single finalized event")
+					fev = ev.Finalized
+				case ev.Message != nil:
+					msgEvts = append(msgEvts, ev.Message)
 				case ev.ExecutorCommitted != nil:
-					// Executor commitment event.
-					require.EqualValues(executorCommits[i], ev.ExecutorCommitted.Commit, "executor commitment event should have the right commitment")
+					executorEvts = append(executorEvts, ev.ExecutorCommitted.Commit)
 				default:
 					// There should be no other event types.
 					t.Fatalf("unexpected event: %+v", ev)
 				}
 			}
+			require.NotNil(fev, "there should be a finalized event")
+			require.EqualValues(header.Round, fev.Round, "finalized event should have the right round")
+			require.Empty(fev.BadComputeNodes, "there should be no bad compute nodes")
+			require.Len(fev.GoodComputeNodes, len(executorNodes), "all nodes should be good (round %d)", fev.Round)
+			require.ElementsMatch(executorCommits, executorEvts, "executor commitment events should have the right commitments")
+			require.Len(msgEvts, len(msgs), "there should be a message event for each message")
+
+			// Streamed events should match the events at the given height.
+			heightEvts, err := backend.GetEvents(ctx, blk.Height)
+			require.NoError(err, "GetEvents")
+			require.EqualValues(heightEvts, evts, "streamed events should match events at height")
+
+			// Filtered events should only include the requested kinds.
+			evts, err = backend.GetEventsFiltered(ctx, blk.Height, &api.EventFilter{
+				RuntimeID: &s.rt.Runtime.ID,
+				Kinds:     []api.EventKind{api.EventKindFinalized},
+			})
+			require.NoError(err, "GetEventsFiltered")
+			require.Len(evts, 1, "should only have the finalized event")
+			require.EqualValues(header.Round, evts[0].Finalized.Round, "finalized event should have the right round")
+
+			// Round results should be available for the last normal round.
+			rr, err := backend.GetRoundResults(ctx, &api.RuntimeRequest{
+				RuntimeID: s.rt.Runtime.ID,
+				Height:    blk.Height,
+			})
+			require.NoError(err, "GetRoundResults")
+			require.EqualValues(header.Round, rr.Round, "round results should be for the finalized round")
+			require.EqualValues(blk.Height, rr.Height, "round results should be for the finalization height")
+			require.EqualValues(msgEvts, rr.Results.Messages, "round results should include the message results")
+			require.NotEmpty(rr.Results.GoodComputeEntities, "there should be good compute entities")
+			require.Empty(rr.Results.BadComputeEntities, "there should be no bad compute entities")
 
 			// Nothing more to do after the block was received.
 			return
@@ -463,6 +556,23 @@ func (s *runtimeState) testSuccessfulRound(t *testing.T, backend api.Backend, co
 	}
 }
 
+func (s *runtimeState) recvEvents(t *testing.T, ch <-chan *api.Event, height int64, n int) []*api.Event {
+	var evts []*api.Event
+	for len(evts) < n {
+		select {
+		case ev := <-ch:
+			require.EqualValues(t, s.rt.Runtime.ID, ev.RuntimeID, "streamed event should have the right runtime")
+			if ev.Height != height {
+				continue
+			}
+			evts = append(evts, ev)
+		case <-time.After(recvTimeout):
+			t.Fatalf("failed to receive events at height %d", height)
+		}
+	}
+	return evts
+}
+
 func testRoundTimeout(t *testing.T, backend api.Backend, consensus consensusAPI.Backend, identity *identity.Identity, states []*runtimeState) {
 	for _, state := range states {
 		state.testRoundTimeout(t, backend, consensus, identity)
@@ -486,7 +596,7 @@ func (s *runtimeState) testRoundTimeout(t *testing.T, backend api.Backend, conse
 	defer cancel()
 
 	// Only submit a single commitment to cause a timeout.
-	_, executorCommits, executorNodes := s.generateExecutorCommitments(t, consensus, identity, child)
+	_, executorCommits, executorNodes := s.generateExecutorCommitments(t, consensus, identity, child, nil)
 	tx := api.NewExecutorCommitTx(0, nil, s.rt.Runtime.ID, executorCommits[:1])
 	err = consensusAPI.SignAndSubmitTx(ctx, consensus, executorNodes[0].Signer, tx)
 	require.NoError(err, "ExecutorCommit")
@@ -563,7 +673,7 @@ func (s *runtimeState) testRoundTimeoutWithEpochTransition(t *testing.T, backend
 	defer cancel()
 
 	// Only submit a single commitment to cause a timeout.
-	_, executorCommits, executorNodes := s.generateExecutorCommitments(t, consensus, identity, child)
+	_, executorCommits, executorNodes := s.generateExecutorCommitments(t, consensus, identity, child, nil)
 	tx := api.NewExecutorCommitTx(0, nil, s.rt.Runtime.ID, executorCommits[:1])
 	err = consensusAPI.SignAndSubmitTx(ctx, consensus, executorNodes[0].Signer, tx)
 	require.NoError(err, "ExecutorCommit")
@@ -913,13 +1023,14 @@ func testSubmitEquivocationEvidence(t *testing.T, backend api.Backend, consensus
 	require.NoError(err, "AddEscrow")
 
 	// Submit evidence of executor equivocation.
-	tx = api.NewEvidenceTx(0, nil, &api.Evidence{
+	evidence := &api.Evidence{
 		ID: s.rt.Runtime.ID,
 		EquivocationBatch: &api.EquivocationBatchEvidence{
 			BatchA: *signedBatch1,
 			BatchB: *signedBatch2,
 		},
-	})
+	}
+	tx = api.NewEvidenceTx(0, nil, evidence)
 	submitter := s.executorCommittee.workers[1]
 	err = consensusAPI.SignAndSubmitTx(ctx, consensus, submitter.Signer, tx)
 	require.NoError(err, "SignAndSubmitTx(EvidenceTx)")
@@ -951,4 +1062,21 @@ WaitLoop:
 	})
 	require.NoError(err, "staking.Account(runtimeAddr)")
 	require.EqualValues(escrow.Amount, runtimeAcc.General.Balance, "Runtime account expected salshed balance")
+
+	// Ensure the evidence is retained.
+	evHash, err := evidence.Hash()
+	require.NoError(err, "evidence.Hash")
+	retained, err := backend.GetRetainedEvidence(ctx, &api.RuntimeRequest{
+		RuntimeID: s.rt.Runtime.ID,
+		Height:    consensusAPI.HeightLatest,
+	})
+	require.NoError(err, "GetRetainedEvidence")
+	require.Len(retained, 1, "submitted evidence should be retained")
+	require.EqualValues(child.Header.Round, retained[0].Round, "retained evidence should have the right round")
+	require.EqualValues(evHash, retained[0].Hash, "retained evidence should have the right hash")
+
+	// Ensure duplicate evidence is rejected.
+	tx = api.NewEvidenceTx(0, nil, evidence)
+	err = consensusAPI.SignAndSubmitTx(ctx, consensus, submitter.Signer, tx)
+	require.ErrorIs(err, api.ErrDuplicateEvidence, "duplicate evidence should be rejected")
 }
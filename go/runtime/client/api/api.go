@@ -19,6 +19,9 @@ const (
 
 	// RoundLatest is a special round number always referring to the latest round.
 	RoundLatest = roothash.RoundLatest
+
+	// MaxSubmitTxBatchSize is the maximum number of transactions in a SubmitTxBatch request.
+	MaxSubmitTxBatchSize = 1024
 )
 
 var (
@@ -35,6 +38,9 @@ var (
 	ErrCheckTxFailed = errors.New(ModuleName, 5, "client: transaction check failed")
 	// ErrNoHostedRuntime is returned when the hosted runtime is not available locally.
 	ErrNoHostedRuntime = errors.New(ModuleName, 6, "client: no hosted runtime is available")
+	// ErrMalformedBatch is an error returned when a submitted transaction batch is empty, too
+	// large or contains duplicate transactions.
+	ErrMalformedBatch = errors.New(ModuleName, 7, "client: malformed transaction batch")
 )
 
 // RuntimeClient is the runtime client interface.
@@ -54,6 +60,17 @@ type RuntimeClient interface {
 	// not wait for transaction execution.
 	SubmitTxNoWait(ctx context.Context, request *SubmitTxRequest) error
 
+	// SubmitTxBatch submits a batch of transactions to the runtime transaction scheduler but
+	// does not wait for transaction execution.
+	//
+	// In case a hosted runtime is available, the whole batch is checked in a single local
+	// transaction check and only transactions that pass the check are submitted. The response
+	// contains a result for each transaction in the order in which they were given.
+	//
+	// The batch must contain at most MaxSubmitTxBatchSize transactions and must not contain
+	// duplicate transactions.
+	SubmitTxBatch(ctx context.Context, request *SubmitTxBatchRequest) (*SubmitTxBatchResponse, error)
+
 	// CheckTx asks the local runtime to check the specified transaction.
 	CheckTx(ctx context.Context, request *CheckTxRequest) error
 
@@ -108,6 +125,24 @@ type SubmitTxMetaResponse struct {
 	CheckTxError *protocol.Error `json:"check_tx_error,omitempty"`
 }
 
+// SubmitTxBatchRequest is a SubmitTxBatch request.
+type SubmitTxBatchRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	Data      [][]byte         `json:"data"`
+}
+
+// SubmitTxBatchResponse is the SubmitTxBatch response.
+type SubmitTxBatchResponse struct {
+	// Results are the per-transaction results in the order of submitted transactions.
+	Results []*SubmitTxBatchResult `json:"results"`
+}
+
+// SubmitTxBatchResult is the result of submitting a single transaction of a batch.
+type SubmitTxBatchResult struct {
+	// CheckTxError is the CheckTx error in case transaction failed the transaction check.
+	CheckTxError *protocol.Error `json:"check_tx_error,omitempty"`
+}
+
 // CheckTxRequest is a CheckTx request.
 type CheckTxRequest struct {
 	RuntimeID common.Namespace `json:"runtime_id"`
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -16,34 +17,55 @@ import (
 )
 
 var (
+	errInvalidRequestType = fmt.Errorf("invalid request type")
+
 	// serviceName is the gRPC service name.
 	serviceName = cmnGrpc.NewServiceName("RuntimeClient")
 
 	// methodSubmitTx is the SubmitTx method.
-	methodSubmitTx = serviceName.NewMethod("SubmitTx", SubmitTxRequest{})
+	methodSubmitTx = serviceName.NewMethod("SubmitTx", SubmitTxRequest{}).
+			WithNamespaceExtractor(extractRuntimeID).
+			WithWriteAccess()
 	// methodSubmitTxMeta is the SubmitTxMeta method.
-	methodSubmitTxMeta = serviceName.NewMethod("SubmitTxMeta", SubmitTxRequest{})
+	methodSubmitTxMeta = serviceName.NewMethod("SubmitTxMeta", SubmitTxRequest{}).
+				WithNamespaceExtractor(extractRuntimeID).
+				WithWriteAccess()
 	// methodSubmitTxNoWait is the SubmitTxNoWait method.
-	methodSubmitTxNoWait = serviceName.NewMethod("SubmitTxNoWait", SubmitTxRequest{})
+	methodSubmitTxNoWait = serviceName.NewMethod("SubmitTxNoWait", SubmitTxRequest{}).
+				WithNamespaceExtractor(extractRuntimeID).
+				WithWriteAccess()
+	// methodSubmitTxBatch is the SubmitTxBatch method.
+	methodSubmitTxBatch = serviceName.NewMethod("SubmitTxBatch", SubmitTxBatchRequest{}).
+				WithNamespaceExtractor(extractRuntimeID).
+				WithWriteAccess()
 	// methodCheckTx is the CheckTx method.
-	methodCheckTx = serviceName.NewMethod("CheckTx", CheckTxRequest{})
+	methodCheckTx = serviceName.NewMethod("CheckTx", CheckTxRequest{}).
+			WithNamespaceExtractor(extractRuntimeID)
 	// methodGetGenesisBlock is the GetGenesisBlock method.
-	methodGetGenesisBlock = serviceName.NewMethod("GetGenesisBlock", common.Namespace{})
+	methodGetGenesisBlock = serviceName.NewMethod("GetGenesisBlock", common.Namespace{}).
+				WithNamespaceExtractor(extractRuntimeID)
 	// methodGetBlock is the GetBlock method.
-	methodGetBlock = serviceName.NewMethod("GetBlock", GetBlockRequest{})
+	methodGetBlock = serviceName.NewMethod("GetBlock", GetBlockRequest{}).
+			WithNamespaceExtractor(extractRuntimeID)
 	// methodGetLastRetainedBlock is the GetLastRetainedBlock method.
-	methodGetLastRetainedBlock = serviceName.NewMethod("GetLastRetainedBlock", common.Namespace{})
+	methodGetLastRetainedBlock = serviceName.NewMethod("GetLastRetainedBlock", common.Namespace{}).
+					WithNamespaceExtractor(extractRuntimeID)
 	// methodGetTransactions is the GetTransactions method.
-	methodGetTransactions = serviceName.NewMethod("GetTransactions", GetTransactionsRequest{})
+	methodGetTransactions = serviceName.NewMethod("GetTransactions", GetTransactionsRequest{}).
+				WithNamespaceExtractor(extractRuntimeID)
 	// methodGetTransactionsWithResults is the GetTransactionsWithResults method.
-	methodGetTransactionsWithResults = serviceName.NewMethod("GetTransactionsWithResults", GetTransactionsRequest{})
+	methodGetTransactionsWithResults = serviceName.NewMethod("GetTransactionsWithResults", GetTransactionsRequest{}).
+						WithNamespaceExtractor(extractRuntimeID)
 	// methodGetEvents is the GetEvents method.
-	methodGetEvents = serviceName.NewMethod("GetEvents", GetEventsRequest{})
+	methodGetEvents = serviceName.NewMethod("GetEvents", GetEventsRequest{}).
+			WithNamespaceExtractor(extractRuntimeID)
 	// methodQuery is the Query method.
-	methodQuery = serviceName.NewMethod("Query", QueryRequest{})
+	methodQuery = serviceName.NewMethod("Query", QueryRequest{}).
+			WithNamespaceExtractor(extractRuntimeID)
 
 	// methodWatchBlocks is the WatchBlocks method.
-	methodWatchBlocks = serviceName.NewMethod("WatchBlocks", common.Namespace{})
+	methodWatchBlocks = serviceName.NewMethod("WatchBlocks", common.Namespace{}).
+				WithNamespaceExtractor(extractRuntimeID)
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -62,6 +84,10 @@ var (
 				MethodName: methodSubmitTxNoWait.ShortName(),
 				Handler:    handlerSubmitTxNoWait,
 			},
+			{
+				MethodName: methodSubmitTxBatch.ShortName(),
+				Handler:    handlerSubmitTxBatch,
+			},
 			{
 				MethodName: methodCheckTx.ShortName(),
 				Handler:    handlerCheckTx,
@@ -105,6 +131,30 @@ var (
 	}
 )
 
+// extractRuntimeID extracts the runtime identifier from a runtime client request.
+func extractRuntimeID(ctx context.Context, req interface{}) (common.Namespace, error) {
+	switch r := req.(type) {
+	case *SubmitTxRequest:
+		return r.RuntimeID, nil
+	case *SubmitTxBatchRequest:
+		return r.RuntimeID, nil
+	case *CheckTxRequest:
+		return r.RuntimeID, nil
+	case *GetBlockRequest:
+		return r.RuntimeID, nil
+	case *GetTransactionsRequest:
+		return r.RuntimeID, nil
+	case *GetEventsRequest:
+		return r.RuntimeID, nil
+	case *QueryRequest:
+		return r.RuntimeID, nil
+	case *common.Namespace:
+		return *r, nil
+	default:
+		return common.Namespace{}, errInvalidRequestType
+	}
+}
+
 func handlerSubmitTx( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -174,6 +224,29 @@ func handlerSubmitTxNoWait( // nolint: golint
 	return interceptor(ctx, &rq, info, handler)
 }
 
+func handlerSubmitTxBatch( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var rq SubmitTxBatchRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeClient).SubmitTxBatch(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSubmitTxBatch.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeClient).SubmitTxBatch(ctx, req.(*SubmitTxBatchRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
 func handlerCheckTx( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -451,6 +524,14 @@ func (c *runtimeClient) SubmitTxNoWait(ctx context.Context, request *SubmitTxReq
 	return c.conn.Invoke(ctx, methodSubmitTxNoWait.FullName(), request, nil)
 }
 
+func (c *runtimeClient) SubmitTxBatch(ctx context.Context, request *SubmitTxBatchRequest) (*SubmitTxBatchResponse, error) {
+	var rsp SubmitTxBatchResponse
+	if err := c.conn.Invoke(ctx, methodSubmitTxBatch.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *runtimeClient) CheckTx(ctx context.Context, request *CheckTxRequest) error {
 	return c.conn.Invoke(ctx, methodCheckTx.FullName(), request, nil)
 }
@@ -10,6 +10,7 @@ import (
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
@@ -47,12 +48,21 @@ var (
 	Flags = flag.NewFlagSet("", flag.ContinueOnError)
 )
 
+// LocalTxPool is the transaction pool of runtimes executed by the local node.
+type LocalTxPool interface {
+	// SubmitLocalTx queues a transaction submitted by a local client for checks in the
+	// transaction pool of the given runtime.
+	SubmitLocalTx(runtimeID common.Namespace, tx []byte) error
+}
+
 type clientCommon struct {
 	storage         storage.Backend
 	consensus       consensus.Backend
 	runtimeRegistry runtimeRegistry.Registry
 	// p2p may be nil.
 	p2p *p2p.P2P
+	// localTxPool may be nil.
+	localTxPool LocalTxPool
 
 	ctx context.Context
 }
@@ -68,6 +78,12 @@ type runtimeClient struct {
 
 	maxTransactionAge int64
 
+	// paramsLock protects the cached roothash consensus parameters.
+	paramsLock sync.Mutex
+	// params are the roothash consensus parameters cached for paramsEpoch.
+	params      *roothash.ConsensusParameters
+	paramsEpoch beacon.EpochTime
+
 	logger *logging.Logger
 }
 
@@ -86,13 +102,48 @@ func (c *runtimeClient) getHostedRuntime(ctx context.Context, runtimeID common.N
 }
 
 func (c *runtimeClient) submitTx(ctx context.Context, request *api.SubmitTxRequest) (<-chan *txResult, *protocol.Error, error) {
+	respChs, checkTxErrs, err := c.submitTxBatch(ctx, request.RuntimeID, [][]byte{request.Data})
+	if err != nil {
+		return nil, nil, err
+	}
+	return respChs[0], checkTxErrs[0], nil
+}
+
+// submitTxBatch checks and submits a batch of transactions. For each transaction it returns
+// either the channel on which the result will be delivered or the CheckTx error.
+func (c *runtimeClient) submitTxBatch(
+	ctx context.Context,
+	runtimeID common.Namespace,
+	batch [][]byte,
+) ([]<-chan *txResult, []*protocol.Error, error) {
 	if c.common.p2p == nil {
 		return nil, nil, fmt.Errorf("client: cannot submit transaction, p2p disabled")
 	}
 
+	// Bound the amount of work performed by a single local transaction check and make sure each
+	// transaction is only tracked once as otherwise only one of the callers would get a result.
+	switch {
+	case len(batch) == 0:
+		return nil, nil, errors.WithContext(api.ErrMalformedBatch, "empty batch")
+	case len(batch) > api.MaxSubmitTxBatchSize:
+		return nil, nil, errors.WithContext(api.ErrMalformedBatch,
+			fmt.Sprintf("batch too large (%d > %d)", len(batch), api.MaxSubmitTxBatchSize),
+		)
+	}
+	seen := make(map[hash.Hash]int, len(batch))
+	for i, data := range batch {
+		h := hash.NewFromBytes(data)
+		if j, ok := seen[h]; ok {
+			return nil, nil, errors.WithContext(api.ErrMalformedBatch,
+				fmt.Sprintf("transaction %d is a duplicate of transaction %d", i, j),
+			)
+		}
+		seen[h] = i
+	}
+
 	// Make sure that the runtime is actually among the supported runtimes for this node as
 	// otherwise we will not be able to actually get any results back.
-	if _, err := c.common.runtimeRegistry.GetRuntime(request.RuntimeID); err != nil {
+	if _, err := c.common.runtimeRegistry.GetRuntime(runtimeID); err != nil {
 		return nil, nil, fmt.Errorf("client: cannot resolve runtime: %w", err)
 	}
 
@@ -106,37 +157,71 @@ func (c *runtimeClient) submitTx(ctx context.Context, request *api.SubmitTxReque
 	}
 
 	// Perform a local transaction check when a hosted runtime is available.
-	if _, ok := c.hosts[request.RuntimeID]; ok {
-		resp, err := c.checkTx(ctx, &api.CheckTxRequest{
-			RuntimeID: request.RuntimeID,
-			Data:      request.Data,
-		})
+	checkTxErrs := make([]*protocol.Error, len(batch))
+	if _, ok := c.hosts[runtimeID]; ok {
+		results, err := c.checkTxBatch(ctx, runtimeID, batch)
 		if err != nil {
 			return nil, nil, err
 		}
-		if !resp.IsSuccess() {
-			return nil, &resp.Error, nil
+		for i := range results {
+			if !results[i].IsSuccess() {
+				checkTxErrs[i] = &results[i].Error
+			}
 		}
 	}
 
 	var submitter *txSubmitter
 	var ok bool
 	c.Lock()
-	if submitter, ok = c.txSubmitters[request.RuntimeID]; !ok {
-		submitter = newTxSubmitter(c.common, request.RuntimeID, c.common.p2p, c.maxTransactionAge)
+	if submitter, ok = c.txSubmitters[runtimeID]; !ok {
+		submitter = newTxSubmitter(c.common, runtimeID, c.common.p2p, c.maxTransactionAge)
 		submitter.Start()
-		c.txSubmitters[request.RuntimeID] = submitter
+		c.txSubmitters[runtimeID] = submitter
 	}
 	c.Unlock()
 
-	// Send a request for watching a new runtime transaction.
-	respCh := make(chan *txResult, 1)
-	req := &txRequest{
-		ctx:    ctx,
-		respCh: respCh,
-		req:    request,
+	// Queue transactions directly into the local transaction pool (if any) so that they are
+	// treated as local transactions. They are still published to peers below.
+	if c.common.localTxPool != nil {
+		for i, data := range batch {
+			if checkTxErrs[i] != nil {
+				continue
+			}
+			if err := c.common.localTxPool.SubmitLocalTx(runtimeID, data); err != nil {
+				c.logger.Debug("failed to queue transaction in local transaction pool",
+					"err", err,
+					"runtime_id", runtimeID,
+				)
+			}
+		}
+	}
+
+	// Prepare requests for watching new runtime transactions.
+	respChs := make([]<-chan *txResult, len(batch))
+	var reqs []*txRequest
+	for i, data := range batch {
+		if checkTxErrs[i] != nil {
+			continue
+		}
+
+		respCh := make(chan *txResult, 1)
+		req := &txRequest{
+			ctx:    ctx,
+			respCh: respCh,
+			req: &api.SubmitTxRequest{
+				RuntimeID: runtimeID,
+				Data:      data,
+			},
+		}
+		req.id.FromBytes(data)
+		reqs = append(reqs, req)
+		respChs[i] = respCh
 	}
-	req.id.FromBytes(request.Data)
+	if len(reqs) == 0 {
+		return respChs, checkTxErrs, nil
+	}
+
+	// Send the requests to the submitter all at once.
 	select {
 	case <-ctx.Done():
 		// The context we're working in was canceled, abort.
@@ -144,10 +229,10 @@ func (c *runtimeClient) submitTx(ctx context.Context, request *api.SubmitTxReque
 	case <-c.common.ctx.Done():
 		// Client is shutting down.
 		return nil, nil, fmt.Errorf("client: shutting down")
-	case submitter.newCh <- req:
+	case submitter.newCh <- reqs:
 	}
 
-	return respCh, nil, nil
+	return respChs, checkTxErrs, nil
 }
 
 // Implements api.RuntimeClient.
@@ -207,19 +292,67 @@ func (c *runtimeClient) SubmitTxNoWait(ctx context.Context, request *api.SubmitT
 	return nil
 }
 
+// Implements api.RuntimeClient.
+func (c *runtimeClient) SubmitTxBatch(ctx context.Context, request *api.SubmitTxBatchRequest) (*api.SubmitTxBatchResponse, error) {
+	_, checkTxErrs, err := c.submitTxBatch(ctx, request.RuntimeID, request.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &api.SubmitTxBatchResponse{
+		Results: make([]*api.SubmitTxBatchResult, len(checkTxErrs)),
+	}
+	for i, checkTxErr := range checkTxErrs {
+		rsp.Results[i] = &api.SubmitTxBatchResult{
+			CheckTxError: checkTxErr,
+		}
+	}
+	return rsp, nil
+}
+
 func (c *runtimeClient) checkTx(ctx context.Context, request *api.CheckTxRequest) (*protocol.CheckTxResult, error) {
-	rt, err := c.getHostedRuntime(ctx, request.RuntimeID)
+	resp, err := c.checkTxBatch(ctx, request.RuntimeID, transaction.RawBatch{request.Data})
+	if err != nil {
+		return nil, err
+	}
+	return &resp[0], nil
+}
+
+// getRoothashParameters returns the roothash consensus parameters at the given height. The
+// parameters are only fetched once per epoch.
+func (c *runtimeClient) getRoothashParameters(ctx context.Context, epoch beacon.EpochTime, height int64) (*roothash.ConsensusParameters, error) {
+	c.paramsLock.Lock()
+	params, paramsEpoch := c.params, c.paramsEpoch
+	c.paramsLock.Unlock()
+	if params != nil && paramsEpoch == epoch {
+		return params, nil
+	}
+
+	params, err := c.common.consensus.RootHash().ConsensusParameters(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("client: failed to get roothash consensus parameters at height %d: %w", height, err)
+	}
+
+	c.paramsLock.Lock()
+	c.params, c.paramsEpoch = params, epoch
+	c.paramsLock.Unlock()
+	return params, nil
+}
+
+// checkTxBatch asks the local runtime to check the given batch of transactions in a single call.
+func (c *runtimeClient) checkTxBatch(ctx context.Context, runtimeID common.Namespace, batch transaction.RawBatch) ([]protocol.CheckTxResult, error) {
+	rt, err := c.getHostedRuntime(ctx, runtimeID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get current blocks.
 	rs, err := c.common.consensus.RootHash().GetRuntimeState(ctx, &roothash.RuntimeRequest{
-		RuntimeID: request.RuntimeID,
+		RuntimeID: runtimeID,
 		Height:    consensus.HeightLatest,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("client: failed to get runtime %s state: %w", request.RuntimeID, err)
+		return nil, fmt.Errorf("client: failed to get runtime %s state: %w", runtimeID, err)
 	}
 	lb, err := c.common.consensus.GetLightBlock(ctx, rs.CurrentBlockHeight)
 	if err != nil {
@@ -229,13 +362,20 @@ func (c *runtimeClient) checkTx(ctx context.Context, request *api.CheckTxRequest
 	if err != nil {
 		return nil, fmt.Errorf("client: failed to get current epoch: %w", err)
 	}
-	maxMessages := rs.Runtime.Executor.MaxMessages
+	params, err := c.getRoothashParameters(ctx, epoch, consensus.HeightLatest)
+	if err != nil {
+		return nil, err
+	}
+	maxMessages := params.EffectiveMaxRuntimeMessages(rs.Runtime)
 
-	resp, err := rt.CheckTx(ctx, rs.CurrentBlock, lb, epoch, maxMessages, transaction.RawBatch{request.Data})
+	resp, err := rt.CheckTx(ctx, rs.CurrentBlock, lb, epoch, maxMessages, batch)
 	if err != nil {
 		return nil, fmt.Errorf("client: local transaction check failed: %w", err)
 	}
-	return &resp[0], nil
+	if len(resp) != len(batch) {
+		return nil, fmt.Errorf("client: local transaction check returned %d results for %d transactions", len(resp), len(batch))
+	}
+	return resp, nil
 }
 
 // Implements api.RuntimeClient.
@@ -432,7 +572,11 @@ func (c *runtimeClient) Query(ctx context.Context, request *api.QueryRequest) (*
 	if err != nil {
 		return nil, fmt.Errorf("client: failed to get active runtime descriptor: %w", err)
 	}
-	maxMessages := rtDsc.Executor.MaxMessages
+	params, err := c.getRoothashParameters(ctx, epoch, annBlk.Height)
+	if err != nil {
+		return nil, err
+	}
+	maxMessages := params.EffectiveMaxRuntimeMessages(rtDsc)
 
 	data, err := hrt.Query(ctx, annBlk.Block, lb, epoch, maxMessages, request.Method, request.Args)
 	if err != nil {
@@ -498,6 +642,7 @@ func New(
 	consensus consensus.Backend,
 	runtimeRegistry runtimeRegistry.Registry,
 	p2p *p2p.P2P,
+	localTxPool LocalTxPool,
 ) (api.RuntimeClientService, error) {
 	maxTransactionAge := viper.GetInt64(CfgMaxTransactionAge)
 	if maxTransactionAge < minMaxTransactionAge && !cmdFlags.DebugDontBlameOasis() {
@@ -511,6 +656,7 @@ func New(
 			runtimeRegistry: runtimeRegistry,
 			ctx:             ctx,
 			p2p:             p2p,
+			localTxPool:     localTxPool,
 		},
 		quitCh:            make(chan struct{}),
 		hosts:             make(map[common.Namespace]*clientHost),
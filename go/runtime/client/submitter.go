@@ -47,7 +47,7 @@ type txSubmitter struct {
 	id     common.Namespace
 
 	transactions map[hash.Hash]*txRequest
-	newCh        chan *txRequest
+	newCh        chan []*txRequest
 
 	maxTransactionAge int64
 	toBeChecked       []*block.Block
@@ -262,10 +262,12 @@ func (w *txSubmitter) start() {
 				close(req.respCh)
 				delete(w.transactions, key)
 			}
-		case newRequest := <-w.newCh:
-			w.transactions[newRequest.id] = newRequest
-			newRequest.height = latestHeight
-			w.publishTx(newRequest, latestGroupVersion)
+		case newRequests := <-w.newCh:
+			for _, newRequest := range newRequests {
+				w.transactions[newRequest.id] = newRequest
+				newRequest.height = latestHeight
+				w.publishTx(newRequest, latestGroupVersion)
+			}
 		case <-w.stopCh:
 			w.logger.Info("stop requested, aborting watcher")
 			return
@@ -298,7 +300,7 @@ func newTxSubmitter(common *clientCommon, id common.Namespace, p2pSvc *p2p.P2P,
 		id:                id,
 		maxTransactionAge: maxTransactionAge,
 		transactions:      make(map[hash.Hash]*txRequest),
-		newCh:             make(chan *txRequest),
+		newCh:             make(chan []*txRequest),
 		stopCh:            make(chan struct{}),
 		quitCh:            make(chan struct{}),
 	}
@@ -46,6 +46,13 @@ func ClientImplementationTests(
 		testSubmitTransactionNoWait(ctx, t, runtimeID, client, noWaitInput)
 	})
 
+	batchInput := "cuttlefish at: " + time.Now().String()
+	t.Run("SubmitTxBatch", func(t *testing.T) {
+		ctx, cancelFunc := context.WithTimeout(context.Background(), timeout)
+		defer cancelFunc()
+		testSubmitTransactionBatch(ctx, t, runtimeID, client, batchInput)
+	})
+
 	t.Run("FailSubmitTx", func(t *testing.T) {
 		ctx, cancelFunc := context.WithTimeout(context.Background(), timeout)
 		defer cancelFunc()
@@ -252,3 +259,38 @@ func testSubmitTransactionNoWait(
 	// Check if everything is in order.
 	require.NoError(t, err, "SubmitTxNoWait")
 }
+
+func testSubmitTransactionBatch(
+	ctx context.Context,
+	t *testing.T,
+	runtimeID common.Namespace,
+	c api.RuntimeClient,
+	input string,
+) {
+	// Submit a batch with a transaction that passes and one that fails the transaction check.
+	resp, err := c.SubmitTxBatch(ctx, &api.SubmitTxBatchRequest{
+		RuntimeID: runtimeID,
+		Data:      [][]byte{[]byte(input), mock.CheckTxFailInput},
+	})
+	require.NoError(t, err, "SubmitTxBatch")
+	require.Len(t, resp.Results, 2, "SubmitTxBatch should return a result for each transaction")
+	require.Nil(t, resp.Results[0].CheckTxError, "SubmitTxBatch check tx error")
+	require.EqualValues(t, &protocol.Error{
+		Module: "mock",
+		Code:   1,
+	}, resp.Results[1].CheckTxError, "SubmitTxBatch should fail check tx")
+
+	// Malformed batches should be rejected as a whole.
+	_, err = c.SubmitTxBatch(ctx, &api.SubmitTxBatchRequest{
+		RuntimeID: runtimeID,
+		Data:      [][]byte{[]byte(input + "-dup"), []byte(input + "-dup")},
+	})
+	require.ErrorIs(t, err, api.ErrMalformedBatch, "SubmitTxBatch should reject duplicate transactions")
+	_, err = c.SubmitTxBatch(ctx, &api.SubmitTxBatchRequest{
+		RuntimeID: runtimeID,
+		Data:      make([][]byte, api.MaxSubmitTxBatchSize+1),
+	})
+	require.ErrorIs(t, err, api.ErrMalformedBatch, "SubmitTxBatch should reject too large batches")
+	_, err = c.SubmitTxBatch(ctx, &api.SubmitTxBatchRequest{RuntimeID: runtimeID})
+	require.ErrorIs(t, err, api.ErrMalformedBatch, "SubmitTxBatch should reject empty batches")
+}
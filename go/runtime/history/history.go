@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/eapache/channels"
@@ -48,6 +49,10 @@ type History interface {
 	// Pruner returns the history pruner.
 	Pruner() Pruner
 
+	// Prune prunes the history according to the configured pruner without waiting for the
+	// background pruner, continuing until there is nothing left to prune.
+	Prune(ctx context.Context) error
+
 	// Close closes the history keeper.
 	Close()
 }
@@ -93,6 +98,10 @@ func (h *nopHistory) Pruner() Pruner {
 	return pruner
 }
 
+func (h *nopHistory) Prune(ctx context.Context) error {
+	return errNopHistory
+}
+
 func (h *nopHistory) Close() {
 }
 
@@ -111,6 +120,7 @@ type runtimeHistory struct {
 
 	db *DB
 
+	pruneLock     sync.Mutex
 	pruner        Pruner
 	pruneInterval time.Duration
 	pruneCh       *channels.RingChannel
@@ -206,6 +216,44 @@ func (h *runtimeHistory) Pruner() Pruner {
 	return h.pruner
 }
 
+func (h *runtimeHistory) Prune(ctx context.Context) error {
+	meta, err := h.db.metadata()
+	if err != nil {
+		return err
+	}
+
+	h.pruneLock.Lock()
+	defer h.pruneLock.Unlock()
+
+	// Each pass prunes a bounded number of rounds, so keep going until the earliest retained
+	// round stops changing.
+	earliestRound := roothash.RoundInvalid
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err = h.pruner.Prune(ctx, meta.LastRound); err != nil {
+			return err
+		}
+
+		var annBlk *roothash.AnnotatedBlock
+		annBlk, err = h.db.getEarliestBlock()
+		switch err {
+		case nil:
+		case roothash.ErrNotFound:
+			// Everything has been pruned.
+			return nil
+		default:
+			return err
+		}
+		if annBlk.Block.Header.Round == earliestRound {
+			return nil
+		}
+		earliestRound = annBlk.Block.Header.Round
+	}
+}
+
 func (h *runtimeHistory) Close() {
 	h.cancelCtx()
 	close(h.stopCh)
@@ -235,7 +283,10 @@ func (h *runtimeHistory) pruneWorker() {
 				"round", round.(uint64),
 			)
 
-			if err := h.pruner.Prune(h.ctx, round.(uint64)); err != nil {
+			h.pruneLock.Lock()
+			err := h.pruner.Prune(h.ctx, round.(uint64))
+			h.pruneLock.Unlock()
+			if err != nil {
 				h.logger.Error("failed to prune",
 					"err", err,
 				)
@@ -263,6 +314,7 @@ func New(dataDir string, runtimeID common.Namespace, cfg *Config) (History, erro
 	}
 	pruner, err := cfg.Pruner(db)
 	if err != nil {
+		db.close()
 		return nil, err
 	}
 
@@ -315,3 +315,78 @@ func TestHistoryPruneError(t *testing.T) {
 		require.NoError(err, "GetBlock(%d)", i)
 	}
 }
+
+func TestHistoryPruneKeepLastEpochs(t *testing.T) {
+	require := require.New(t)
+
+	// Create a new random temporary directory under /tmp.
+	dataDir, err := ioutil.TempDir("", "oasis-runtime-history-test_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dataDir)
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("history prune epochs test ns"), 0)
+
+	_, err = New(dataDir, runtimeID, &Config{
+		Pruner:        NewKeepLastEpochsPruner(0),
+		PruneInterval: time.Hour,
+	})
+	require.Error(err, "New should fail when keeping zero epochs")
+
+	// Use a long prune interval so that only explicit pruning takes place.
+	history, err := New(dataDir, runtimeID, &Config{
+		Pruner:        NewKeepLastEpochsPruner(2),
+		PruneInterval: time.Hour,
+	})
+	require.NoError(err, "New")
+	defer history.Close()
+
+	ph := testPruneHandler{
+		doneCh:     make(chan struct{}),
+		waitRounds: 180,
+	}
+	history.Pruner().RegisterHandler(&ph)
+
+	// Create some blocks with an epoch transition every 20 rounds.
+	commitBlocks := func(from, to int) {
+		for i := from; i <= to; i++ {
+			blk := roothash.AnnotatedBlock{
+				Height: int64(i),
+				Block:  block.NewGenesisBlock(runtimeID, 0),
+			}
+			blk.Block.Header.Round = uint64(i)
+			if i%20 == 0 {
+				blk.Block.Header.HeaderType = block.EpochTransition
+			}
+
+			err = history.Commit(&blk, &roothash.RoundResults{})
+			require.NoError(err, "Commit")
+		}
+	}
+	commitBlocks(0, 199)
+
+	err = history.Prune(context.Background())
+	require.NoError(err, "Prune")
+
+	// Only the last two epochs (starting at round 160) should be kept.
+	blk, err := history.GetEarliestBlock(context.Background())
+	require.NoError(err, "GetEarliestBlock")
+	require.EqualValues(160, blk.Header.Round, "rounds of the last two epochs should be kept")
+	require.Len(ph.prunedRounds, 160)
+	require.Equal([]int{64, 64, 32}, ph.batches, "pruning should be done in batches")
+
+	// Pruning again should be a no-op.
+	err = history.Prune(context.Background())
+	require.NoError(err, "Prune")
+	require.Len(ph.prunedRounds, 160)
+
+	// Once a new epoch starts, the oldest kept epoch should be pruned.
+	commitBlocks(200, 210)
+	err = history.Prune(context.Background())
+	require.NoError(err, "Prune")
+	blk, err = history.GetEarliestBlock(context.Background())
+	require.NoError(err, "GetEarliestBlock")
+	require.EqualValues(180, blk.Header.Round, "rounds of the last two epochs should be kept")
+
+	nop := NewNop(runtimeID)
+	require.Error(nop.Prune(context.Background()), "Prune should fail for no-op history")
+}
@@ -7,7 +7,10 @@ import (
 
 	"github.com/dgraph-io/badger/v3"
 
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 )
 
 const (
@@ -15,6 +18,8 @@ const (
 	PrunerStrategyNone = "none"
 	// PrunerStrategyKeepLast is the name of the keep last pruner strategy.
 	PrunerStrategyKeepLast = "keep_last"
+	// PrunerStrategyKeepLastEpochs is the name of the keep last epochs pruner strategy.
+	PrunerStrategyKeepLastEpochs = "keep_last_epochs"
 
 	// maxBatchSize is the maximum number of rounds to prune in one pass.
 	maxBatchSize = 64
@@ -59,6 +64,76 @@ func (p *prunerBase) RegisterHandler(handler PruneHandler) {
 	p.handlers = append(p.handlers, handler)
 }
 
+// pruneUntil prunes (at most maxBatchSize) rounds up to and including the given round and returns
+// the rounds that have been pruned.
+//
+// The caller must hold the read lock.
+func (p *prunerBase) pruneUntil(ctx context.Context, logger *logging.Logger, db *DB, lastPrunedRound uint64) ([]uint64, error) {
+	var pruned []uint64
+	err := db.db.Update(func(tx *badger.Txn) error {
+		// NOTE: Do not prefetch values as we are only looking at keys.
+		it := tx.NewIterator(badger.IteratorOptions{
+			Prefix: blockKeyFmt.Encode(),
+		})
+		defer it.Close()
+
+		// Start with the smallest round and proceed forward.
+		pruned = nil
+		for it.Rewind(); it.Valid() && len(pruned) < maxBatchSize; it.Next() {
+			item := it.Item()
+
+			var round uint64
+			if !blockKeyFmt.Decode(item.Key(), &round) {
+				// This should not happen as the Badger iterator should take care of it.
+				panic("runtime/history: bad iterator")
+			}
+
+			if round > lastPrunedRound {
+				break
+			}
+
+			if err := tx.Delete(roundResultsKeyFmt.Encode(round)); err != nil {
+				if err == badger.ErrTxnTooBig {
+					// We can't prune any more rounds in this transaction.
+					break
+				}
+				return err
+			}
+
+			if err := tx.Delete(item.KeyCopy(nil)); err != nil {
+				return err
+			}
+
+			pruned = append(pruned, round)
+		}
+
+		// If there is nothing to prune, do not call any handlers.
+		if len(pruned) == 0 {
+			return nil
+		}
+
+		// Before pruning anything, run all prune handlers. If any of them
+		// fails we abort the prune.
+		for _, ph := range p.handlers {
+			if err := ph.Prune(ctx, pruned); err != nil {
+				logger.Error("prune handler failed, aborting prune",
+					"err", err,
+					"round_count", len(pruned),
+					"round_min", pruned[0],
+					"round_max", pruned[len(pruned)-1],
+				)
+				return fmt.Errorf("runtime/history: prune handler failed: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pruned, nil
+}
+
 func newPrunerBase() prunerBase {
 	return prunerBase{}
 }
@@ -96,18 +171,51 @@ func (p *keepLastPruner) Prune(ctx context.Context, latestRound uint64) error {
 	p.prunerBase.RLock()
 	defer p.prunerBase.RUnlock()
 
-	lastPrunedRound := latestRound - p.numKept
+	_, err := p.prunerBase.pruneUntil(ctx, p.logger, p.db, latestRound-p.numKept)
+	return err
+}
 
-	return p.db.db.Update(func(tx *badger.Txn) error {
-		// NOTE: Do not prefetch values as we are only looking at keys.
+// NewKeepLastPruner creates a pruner that keeps the last configured
+// number of rounds.
+func NewKeepLastPruner(numKept uint64) PrunerFactory {
+	return func(db *DB) (Pruner, error) {
+		return &keepLastPruner{
+			prunerBase: newPrunerBase(),
+			logger:     logging.GetLogger("history/prune/keep_last"),
+			db:         db,
+			numKept:    numKept,
+		}, nil
+	}
+}
+
+type keepLastEpochsPruner struct {
+	prunerBase
+
+	logger *logging.Logger
+	db     *DB
+
+	numKept uint64
+
+	scanLock sync.Mutex
+	// epochRounds are the rounds of the retained epoch transition blocks in ascending order.
+	epochRounds []uint64
+	// nextScanRound is the first round that has not yet been scanned for epoch transitions.
+	nextScanRound uint64
+}
+
+// scan updates the known epoch transition rounds with blocks up to and including the given round.
+func (p *keepLastEpochsPruner) scan(latestRound uint64) error {
+	if latestRound < p.nextScanRound {
+		return nil
+	}
+
+	err := p.db.db.View(func(tx *badger.Txn) error {
 		it := tx.NewIterator(badger.IteratorOptions{
 			Prefix: blockKeyFmt.Encode(),
 		})
 		defer it.Close()
 
-		// Start with the smallest round and proceed forward.
-		var pruned []uint64
-		for it.Rewind(); it.Valid() && len(pruned) < maxBatchSize; it.Next() {
+		for it.Seek(blockKeyFmt.Encode(p.nextScanRound)); it.Valid(); it.Next() {
 			item := it.Item()
 
 			var round uint64
@@ -116,55 +224,74 @@ func (p *keepLastPruner) Prune(ctx context.Context, latestRound uint64) error {
 				panic("runtime/history: bad iterator")
 			}
 
-			if round > lastPrunedRound {
+			if round > latestRound {
 				break
 			}
 
-			if err := tx.Delete(roundResultsKeyFmt.Encode(round)); err != nil {
-				if err == badger.ErrTxnTooBig {
-					// We can't prune any more rounds in this transaction.
-					break
-				}
+			var blk roothash.AnnotatedBlock
+			if err := item.Value(func(val []byte) error {
+				return cbor.UnmarshalTrusted(val, &blk)
+			}); err != nil {
 				return err
 			}
 
-			if err := tx.Delete(item.KeyCopy(nil)); err != nil {
-				return err
+			if blk.Block.Header.HeaderType == block.EpochTransition {
+				p.epochRounds = append(p.epochRounds, round)
 			}
-
-			pruned = append(pruned, round)
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
-		// If there is nothing to prune, do not call any handlers.
-		if len(pruned) == 0 {
-			return nil
-		}
+	p.nextScanRound = latestRound + 1
 
-		// Before pruning anything, run all prune handlers. If any of them
-		// fails we abort the prune.
-		for _, ph := range p.prunerBase.handlers {
-			if err := ph.Prune(ctx, pruned); err != nil {
-				p.logger.Error("prune handler failed, aborting prune",
-					"err", err,
-					"round_count", len(pruned),
-					"round_min", pruned[0],
-					"round_max", pruned[len(pruned)-1],
-				)
-				return fmt.Errorf("runtime/history: prune handler failed: %w", err)
-			}
-		}
+	return nil
+}
 
+func (p *keepLastEpochsPruner) Prune(ctx context.Context, latestRound uint64) error {
+	p.scanLock.Lock()
+	defer p.scanLock.Unlock()
+
+	if err := p.scan(latestRound); err != nil {
+		return fmt.Errorf("runtime/history: failed to scan for epoch transitions: %w", err)
+	}
+	if uint64(len(p.epochRounds)) <= p.numKept {
 		return nil
-	})
+	}
+
+	// Keep everything starting with the epoch transition block of the oldest kept epoch.
+	firstKeptRound := p.epochRounds[uint64(len(p.epochRounds))-p.numKept]
+
+	p.prunerBase.RLock()
+	defer p.prunerBase.RUnlock()
+
+	pruned, err := p.prunerBase.pruneUntil(ctx, p.logger, p.db, firstKeptRound-1)
+	if err != nil || len(pruned) == 0 {
+		return err
+	}
+
+	// Forget about epoch transitions that have been pruned.
+	lastPrunedRound := pruned[len(pruned)-1]
+	for len(p.epochRounds) > 0 && p.epochRounds[0] <= lastPrunedRound {
+		p.epochRounds = p.epochRounds[1:]
+	}
+
+	return nil
 }
 
-// NewKeepLastPruner creates a pruner that keeps the last configured
-// number of rounds.
-func NewKeepLastPruner(numKept uint64) PrunerFactory {
+// NewKeepLastEpochsPruner creates a pruner that keeps the rounds of the last configured number of
+// epochs, based on the epoch transition blocks in history.
+func NewKeepLastEpochsPruner(numKept uint64) PrunerFactory {
 	return func(db *DB) (Pruner, error) {
-		return &keepLastPruner{
+		if numKept == 0 {
+			return nil, fmt.Errorf("runtime/history: number of kept epochs must be at least one")
+		}
+
+		return &keepLastEpochsPruner{
 			prunerBase: newPrunerBase(),
-			logger:     logging.GetLogger("history/prune/keep_last"),
+			logger:     logging.GetLogger("history/prune/keep_last_epochs"),
 			db:         db,
 			numKept:    numKept,
 		}, nil
@@ -27,6 +27,10 @@ type Config struct {
 	// MessageHandler is the message handler for the Runtime Host Protocol messages.
 	MessageHandler protocol.Handler
 
+	// Middleware is an optional list of middleware applied to all Runtime Host Protocol requests
+	// made to the runtime.
+	Middleware []protocol.Middleware
+
 	// LocalConfig is the node-local runtime configuration.
 	LocalConfig map[string]interface{}
 }
@@ -85,6 +89,9 @@ type StartedEvent struct {
 	// CapabilityTEE is the newly started runtime's CapabilityTEE. It may be nil in case the runtime
 	// is not running inside a TEE.
 	CapabilityTEE *node.CapabilityTEE
+
+	// Features is the set of Runtime Host Protocol features negotiated with the runtime.
+	Features protocol.FeatureSet
 }
 
 // FailedToStartEvent is a failed to start runtime event.
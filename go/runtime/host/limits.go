@@ -0,0 +1,155 @@
+package host
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/metrics"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+)
+
+var (
+	rhpQueueLength = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_rhp_queue_length",
+			Help: "Number of Runtime Host calls waiting for a free concurrency slot.",
+		},
+		[]string{"runtime", "call"},
+	)
+	rhpInFlight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_rhp_in_flight",
+			Help: "Number of in-flight Runtime Host calls.",
+		},
+		[]string{"runtime", "call"},
+	)
+
+	limitsCollectors = []prometheus.Collector{
+		rhpQueueLength,
+		rhpInFlight,
+	}
+
+	limitsMetricsOnce sync.Once
+)
+
+// CallLimit are the limits applied to a specific kind of Runtime Host Protocol call.
+type CallLimit struct {
+	// Timeout is the maximum amount of time a call may take. Zero means no timeout.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// MaxConcurrent is the maximum number of concurrent in-flight calls. Calls over the limit are
+	// queued until a slot frees up. Zero means no limit.
+	MaxConcurrent int `mapstructure:"max_concurrent"`
+}
+
+// validate checks that the call limit is not negative.
+func (l *CallLimit) validate() error {
+	if l.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative (got %s)", l.Timeout)
+	}
+	if l.MaxConcurrent < 0 {
+		return fmt.Errorf("concurrency limit must not be negative (got %d)", l.MaxConcurrent)
+	}
+	return nil
+}
+
+// CallLimits are the limits applied to Runtime Host Protocol calls made to a runtime.
+//
+// Since each kind of call has its own limits, a slow query workload cannot starve batch execution
+// on the same runtime.
+type CallLimits struct {
+	// CheckTx are the limits applied to transaction check calls.
+	CheckTx CallLimit `mapstructure:"check_tx"`
+
+	// Query are the limits applied to runtime query calls.
+	Query CallLimit `mapstructure:"query"`
+
+	// Execute are the limits applied to batch execution calls.
+	Execute CallLimit `mapstructure:"execute"`
+}
+
+func (l *CallLimits) forCall(body *protocol.Body) (string, *CallLimit) {
+	switch {
+	case body.RuntimeCheckTxBatchRequest != nil:
+		return "check_tx", &l.CheckTx
+	case body.RuntimeQueryRequest != nil:
+		return "query", &l.Query
+	case body.RuntimeExecuteTxBatchRequest != nil:
+		return "execute", &l.Execute
+	default:
+		return "", nil
+	}
+}
+
+// Middleware returns a Runtime Host Protocol middleware that enforces the call limits.
+//
+// The limits must not be negative.
+func (l CallLimits) Middleware(runtimeID common.Namespace) (protocol.Middleware, error) {
+	// Prepare concurrency slots for each kind of call.
+	slots := make(map[string]chan struct{})
+	for _, call := range []protocol.Body{
+		{RuntimeCheckTxBatchRequest: &protocol.RuntimeCheckTxBatchRequest{}},
+		{RuntimeQueryRequest: &protocol.RuntimeQueryRequest{}},
+		{RuntimeExecuteTxBatchRequest: &protocol.RuntimeExecuteTxBatchRequest{}},
+	} {
+		kind, limit := l.forCall(&call)
+		if err := limit.validate(); err != nil {
+			return nil, fmt.Errorf("host: bad %s call limits: %w", kind, err)
+		}
+		if limit.MaxConcurrent > 0 {
+			slots[kind] = make(chan struct{}, limit.MaxConcurrent)
+		}
+	}
+	runtime := runtimeID.String()
+
+	limitsMetricsOnce.Do(func() {
+		prometheus.MustRegister(limitsCollectors...)
+	})
+
+	return func(next protocol.CallFunc) protocol.CallFunc {
+		return func(ctx context.Context, body *protocol.Body) (*protocol.Body, error) {
+			kind, limit := l.forCall(body)
+			if limit == nil {
+				return next(ctx, body)
+			}
+			labels := prometheus.Labels{"runtime": runtime, "call": kind}
+
+			if limit.Timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, limit.Timeout)
+				defer cancel()
+			}
+
+			if ch := slots[kind]; ch != nil {
+				if metrics.Enabled() {
+					rhpQueueLength.With(labels).Inc()
+				}
+				var err error
+				select {
+				case ch <- struct{}{}:
+				case <-ctx.Done():
+					err = ctx.Err()
+				}
+				if metrics.Enabled() {
+					rhpQueueLength.With(labels).Dec()
+				}
+				if err != nil {
+					return nil, err
+				}
+				defer func() { <-ch }()
+			}
+
+			if metrics.Enabled() {
+				rhpInFlight.With(labels).Inc()
+				defer rhpInFlight.With(labels).Dec()
+			}
+
+			return next(ctx, body)
+		}
+	}, nil
+}
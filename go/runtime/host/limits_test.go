@@ -0,0 +1,83 @@
+package host
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+)
+
+func TestCallLimits(t *testing.T) {
+	require := require.New(t)
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("host limits test"), 0)
+
+	limits := CallLimits{
+		Query: CallLimit{
+			Timeout:       50 * time.Millisecond,
+			MaxConcurrent: 1,
+		},
+	}
+
+	for _, invalid := range []CallLimits{
+		{CheckTx: CallLimit{MaxConcurrent: -1}},
+		{Query: CallLimit{Timeout: -time.Second}},
+		{Execute: CallLimit{MaxConcurrent: -5}},
+	} {
+		_, err := invalid.Middleware(runtimeID)
+		require.Error(err, "negative limits should be rejected (%+v)", invalid)
+	}
+
+	mw, err := limits.Middleware(runtimeID)
+	require.NoError(err, "Middleware")
+
+	var inFlight, maxInFlight int32
+	blockCh := make(chan struct{})
+	fn := mw(func(ctx context.Context, body *protocol.Body) (*protocol.Body, error) {
+		if body.RuntimeQueryRequest != nil {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				old := atomic.LoadInt32(&maxInFlight)
+				if n <= old || atomic.CompareAndSwapInt32(&maxInFlight, old, n) {
+					break
+				}
+			}
+
+			select {
+			case <-blockCh:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		return body, nil
+	})
+
+	// Queries should time out and never run concurrently.
+	var wg sync.WaitGroup
+	errCh := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, qerr := fn(context.Background(), &protocol.Body{RuntimeQueryRequest: &protocol.RuntimeQueryRequest{}})
+			errCh <- qerr
+		}()
+	}
+
+	// Batch execution should not be affected by slow queries.
+	_, err = fn(context.Background(), &protocol.Body{RuntimeExecuteTxBatchRequest: &protocol.RuntimeExecuteTxBatchRequest{}})
+	require.NoError(err, "execute should not be limited")
+
+	wg.Wait()
+	close(errCh)
+	for qerr := range errCh {
+		require.ErrorIs(qerr, context.DeadlineExceeded, "query should time out")
+	}
+	require.EqualValues(1, atomic.LoadInt32(&maxInFlight), "at most one query should be in flight")
+}
@@ -15,7 +15,6 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
-	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/metrics"
 )
 
 const (
@@ -108,6 +107,12 @@ type Connection interface {
 	//
 	// Only one of InitHost/InitGuest can be called otherwise the method may panic.
 	InitGuest(ctx context.Context, conn net.Conn) error
+
+	// Features returns the set of protocol features negotiated during initialization.
+	//
+	// Requests requiring features that are not part of the set are rejected with
+	// ErrFeatureNotSupported.
+	Features() FeatureSet
 }
 
 // HostInfo contains the information about the host environment that is sent to the runtime during
@@ -195,6 +200,8 @@ type connection struct { // nolint: maligned
 
 	runtimeID common.Namespace
 	handler   Handler
+	callFn    CallFunc
+	features  FeatureSet
 
 	state           state
 	pendingRequests map[uint64]chan *Body
@@ -259,24 +266,22 @@ func (c *connection) Call(ctx context.Context, body *Body) (*Body, error) {
 	if c.getState() != stateReady {
 		return nil, ErrNotReady
 	}
+	if !c.Features().Supports(body) {
+		return nil, ErrFeatureNotSupported
+	}
 
-	b, err := c.call(ctx, body)
+	b, err := c.callFn(ctx, body)
 	return b, err
 }
 
-func (c *connection) call(ctx context.Context, body *Body) (result *Body, err error) {
-	start := time.Now()
-	defer func() {
-		if metrics.Enabled() {
-			rhpLatency.With(prometheus.Labels{"call": body.Type()}).Observe(time.Since(start).Seconds())
-			if err != nil {
-				rhpCallFailures.With(prometheus.Labels{"call": body.Type()}).Inc()
-			} else {
-				rhpCallSuccesses.With(prometheus.Labels{"call": body.Type()}).Inc()
-			}
-		}
-	}()
+// Implements Connection.
+func (c *connection) Features() FeatureSet {
+	c.RLock()
+	defer c.RUnlock()
+	return c.features
+}
 
+func (c *connection) call(ctx context.Context, body *Body) (*Body, error) {
 	respCh, err := c.makeRequest(ctx, body)
 	if err != nil {
 		return nil, err
@@ -290,8 +295,7 @@ func (c *connection) call(ctx context.Context, body *Body) (result *Body, err er
 
 		if resp.Error != nil {
 			// Decode error.
-			err = errors.FromCode(resp.Error.Module, resp.Error.Code, resp.Error.Message)
-			return nil, err
+			return nil, errors.FromCode(resp.Error.Module, resp.Error.Code, resp.Error.Message)
 		}
 
 		return resp, nil
@@ -504,6 +508,7 @@ func (c *connection) InitGuest(ctx context.Context, conn net.Conn) error {
 
 	// Transition the protocol state to Ready.
 	c.Lock()
+	c.features = AllFeatures()
 	c.setStateLocked(stateReady)
 	c.Unlock()
 
@@ -515,7 +520,7 @@ func (c *connection) InitHost(ctx context.Context, conn net.Conn, hi *HostInfo)
 	c.initConn(conn)
 
 	// Check Runtime Host Protocol version.
-	rsp, err := c.call(ctx, &Body{RuntimeInfoRequest: &RuntimeInfoRequest{
+	rsp, err := c.callFn(ctx, &Body{RuntimeInfoRequest: &RuntimeInfoRequest{
 		RuntimeID:                c.runtimeID,
 		ConsensusBackend:         hi.ConsensusBackend,
 		ConsensusProtocolVersion: hi.ConsensusProtocolVersion,
@@ -534,22 +539,32 @@ func (c *connection) InitHost(ctx context.Context, conn net.Conn, hi *HostInfo)
 	}
 
 	info := rsp.RuntimeInfoResponse
-	if info.ProtocolVersion.Major != version.RuntimeHostProtocol.Major {
+	features, err := NegotiateFeatures(info)
+	if err != nil {
 		c.logger.Error("runtime has incompatible protocol version",
 			"version", info.ProtocolVersion,
 			"expected_version", version.RuntimeHostProtocol,
+			"min_supported_version", MinSupportedRuntimeHostProtocol,
 		)
-		return nil, fmt.Errorf("rhp: incompatible protocol version (expected: %s got: %s)",
-			version.RuntimeHostProtocol,
-			info.ProtocolVersion,
-		)
+		return nil, err
 	}
 
 	rtVersion := info.RuntimeVersion
-	c.logger.Info("runtime host protocol initialized", "runtime_version", rtVersion)
+	c.logger.Info("runtime host protocol initialized",
+		"runtime_version", rtVersion,
+		"protocol_version", info.ProtocolVersion,
+		"features", features.List(),
+	)
+	if !versionAtLeast(info.ProtocolVersion, version.RuntimeHostProtocol) {
+		c.logger.Warn("runtime uses an older protocol version, some features may be unavailable",
+			"version", info.ProtocolVersion,
+			"expected_version", version.RuntimeHostProtocol,
+		)
+	}
 
 	// Transition the protocol state to Ready.
 	c.Lock()
+	c.features = features
 	c.setStateLocked(stateReady)
 	c.Unlock()
 
@@ -557,7 +572,10 @@ func (c *connection) InitHost(ctx context.Context, conn net.Conn, hi *HostInfo)
 }
 
 // NewConnection creates a new uninitialized RHP connection.
-func NewConnection(logger *logging.Logger, runtimeID common.Namespace, handler Handler) (Connection, error) {
+//
+// Any passed middleware is applied to all outgoing requests, with the first middleware being the
+// outermost one.
+func NewConnection(logger *logging.Logger, runtimeID common.Namespace, handler Handler, mws ...Middleware) (Connection, error) {
 	metricsOnce.Do(func() {
 		prometheus.MustRegister(rhpCollectors...)
 	})
@@ -571,6 +589,7 @@ func NewConnection(logger *logging.Logger, runtimeID common.Namespace, handler H
 		closeCh:         make(chan struct{}),
 		logger:          logger,
 	}
+	c.callFn = Chain(append([]Middleware{MetricsMiddleware(), TracingMiddleware()}, mws...)...)(c.call)
 
 	return c, nil
 }
@@ -0,0 +1,140 @@
+package protocol
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+)
+
+// ErrFeatureNotSupported is the error reported when a request requires a protocol feature that
+// the remote side does not support.
+var ErrFeatureNotSupported = errors.New(moduleName, 2, "rhp: feature not supported by runtime")
+
+// MinSupportedRuntimeHostProtocol is the minimum Runtime Host Protocol version a runtime must
+// support in order for the host to talk to it. Runtimes between this version and the current
+// version are supported with graceful degradation of features they don't know about.
+var MinSupportedRuntimeHostProtocol = version.Version{Major: 4, Minor: 0, Patch: 0}
+
+// Feature is an optional Runtime Host Protocol capability.
+type Feature string
+
+const (
+	// FeatureQuery is the capability to answer runtime queries.
+	FeatureQuery Feature = "query"
+	// FeatureLocalRPC is the capability to answer local RPC calls.
+	FeatureLocalRPC Feature = "local_rpc"
+	// FeatureAbort is the capability to abort batch processing on request.
+	FeatureAbort Feature = "abort"
+	// FeatureConsensusSync is the capability to process consensus layer sync notifications.
+	FeatureConsensusSync Feature = "consensus_sync"
+	// FeatureKeyManagerPolicyUpdate is the capability to process key manager policy updates.
+	FeatureKeyManagerPolicyUpdate Feature = "km_policy_update"
+)
+
+// featureVersions maps each known feature to the protocol version that introduced it.
+//
+// When adding a new feature, make sure to also bump the minor RuntimeHostProtocol version in
+// go/common/version so that older runtimes are correctly detected as not supporting it.
+var featureVersions = map[Feature]version.Version{
+	FeatureQuery:                  {Major: 4, Minor: 0, Patch: 0},
+	FeatureLocalRPC:               {Major: 4, Minor: 0, Patch: 0},
+	FeatureAbort:                  {Major: 4, Minor: 0, Patch: 0},
+	FeatureConsensusSync:          {Major: 4, Minor: 0, Patch: 0},
+	FeatureKeyManagerPolicyUpdate: {Major: 4, Minor: 0, Patch: 0},
+}
+
+// RequiredFeature returns the protocol feature required for the remote side to be able to handle
+// the given request body. Returns false in case the request is part of the base protocol.
+func (body Body) RequiredFeature() (Feature, bool) {
+	switch {
+	case body.RuntimeQueryRequest != nil:
+		return FeatureQuery, true
+	case body.RuntimeLocalRPCCallRequest != nil:
+		return FeatureLocalRPC, true
+	case body.RuntimeAbortRequest != nil:
+		return FeatureAbort, true
+	case body.RuntimeConsensusSyncRequest != nil:
+		return FeatureConsensusSync, true
+	case body.RuntimeKeyManagerPolicyUpdateRequest != nil:
+		return FeatureKeyManagerPolicyUpdate, true
+	default:
+		return "", false
+	}
+}
+
+// FeatureSet is a set of negotiated protocol features.
+type FeatureSet map[Feature]bool
+
+// Has returns true iff the given feature is part of the feature set.
+func (fs FeatureSet) Has(f Feature) bool {
+	return fs[f]
+}
+
+// Supports returns true iff the remote side is able to handle the given request body.
+func (fs FeatureSet) Supports(body *Body) bool {
+	f, ok := body.RequiredFeature()
+	return !ok || fs.Has(f)
+}
+
+// List returns a sorted list of features in the feature set.
+func (fs FeatureSet) List() []Feature {
+	list := make([]Feature, 0, len(fs))
+	for f, ok := range fs {
+		if ok {
+			list = append(list, f)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
+
+// AllFeatures returns the set of all features known to the host.
+func AllFeatures() FeatureSet {
+	fs := make(FeatureSet)
+	for f := range featureVersions {
+		fs[f] = true
+	}
+	return fs
+}
+
+func versionAtLeast(v, min version.Version) bool {
+	if v.Major != min.Major {
+		return v.Major > min.Major
+	}
+	return v.Minor >= min.Minor
+}
+
+// NegotiateFeatures checks whether the runtime's protocol version is supported by the host and
+// returns the set of features that can be used when talking to it.
+//
+// In case the runtime explicitly advertises its features, only the advertised features known to
+// the host are enabled. Otherwise features are derived from the runtime's protocol version.
+func NegotiateFeatures(info *RuntimeInfoResponse) (FeatureSet, error) {
+	rtVersion := info.ProtocolVersion
+	if !versionAtLeast(rtVersion, MinSupportedRuntimeHostProtocol) || rtVersion.Major > version.RuntimeHostProtocol.Major {
+		return nil, fmt.Errorf("rhp: incompatible protocol version (expected: %s to %s got: %s)",
+			MinSupportedRuntimeHostProtocol.MaskNonMajor(),
+			version.RuntimeHostProtocol,
+			rtVersion,
+		)
+	}
+
+	fs := make(FeatureSet)
+	if len(info.Features) > 0 {
+		for _, f := range info.Features {
+			if _, known := featureVersions[f]; known {
+				fs[f] = true
+			}
+		}
+		return fs, nil
+	}
+
+	for f, introduced := range featureVersions {
+		if versionAtLeast(rtVersion, introduced) {
+			fs[f] = true
+		}
+	}
+	return fs, nil
+}
@@ -0,0 +1,103 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+)
+
+type featureTestHandler struct {
+	info  RuntimeInfoResponse
+	calls int
+}
+
+func (h *featureTestHandler) Handle(ctx context.Context, body *Body) (*Body, error) {
+	if body.RuntimeInfoRequest != nil {
+		return &Body{RuntimeInfoResponse: &h.info}, nil
+	}
+
+	h.calls++
+	return body, nil
+}
+
+func TestNegotiateFeatures(t *testing.T) {
+	require := require.New(t)
+
+	// Incompatible versions.
+	_, err := NegotiateFeatures(&RuntimeInfoResponse{
+		ProtocolVersion: version.Version{Major: MinSupportedRuntimeHostProtocol.Major - 1},
+	})
+	require.Error(err, "NegotiateFeatures should fail for too old versions")
+	_, err = NegotiateFeatures(&RuntimeInfoResponse{
+		ProtocolVersion: version.Version{Major: version.RuntimeHostProtocol.Major + 1},
+	})
+	require.Error(err, "NegotiateFeatures should fail for newer major versions")
+
+	// Features derived from the protocol version.
+	fs, err := NegotiateFeatures(&RuntimeInfoResponse{
+		ProtocolVersion: version.RuntimeHostProtocol,
+	})
+	require.NoError(err, "NegotiateFeatures")
+	require.EqualValues(AllFeatures(), fs)
+
+	// Explicitly advertised features.
+	fs, err = NegotiateFeatures(&RuntimeInfoResponse{
+		ProtocolVersion: version.RuntimeHostProtocol,
+		Features:        []Feature{FeatureQuery, "unknown_feature"},
+	})
+	require.NoError(err, "NegotiateFeatures")
+	require.EqualValues([]Feature{FeatureQuery}, fs.List())
+	require.True(fs.Supports(&Body{RuntimeQueryRequest: &RuntimeQueryRequest{}}))
+	require.True(fs.Supports(&Body{RuntimeCheckTxBatchRequest: &RuntimeCheckTxBatchRequest{}}))
+	require.False(fs.Supports(&Body{RuntimeAbortRequest: &Empty{}}))
+}
+
+func TestFeatureDegradation(t *testing.T) {
+	require := require.New(t)
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("test conn"), 0)
+	logger := logging.GetLogger("test")
+
+	// An older runtime which only supports queries.
+	handler := &featureTestHandler{
+		info: RuntimeInfoResponse{
+			ProtocolVersion: MinSupportedRuntimeHostProtocol,
+			Features:        []Feature{FeatureQuery},
+		},
+	}
+
+	connA, connB := net.Pipe()
+	protoA, err := NewConnection(logger, runtimeID, handler)
+	require.NoError(err, "A.New()")
+	defer protoA.Close()
+	protoB, err := NewConnection(logger, runtimeID, &testHandler{})
+	require.NoError(err, "B.New()")
+	defer protoB.Close()
+
+	err = protoA.InitGuest(context.Background(), connA)
+	require.NoError(err, "A.InitGuest()")
+	_, err = protoB.InitHost(context.Background(), connB, &HostInfo{})
+	require.NoError(err, "B.InitHost() should accept older runtimes")
+	require.EqualValues([]Feature{FeatureQuery}, protoB.Features().List())
+
+	_, err = protoB.Call(context.Background(), &Body{RuntimeQueryRequest: &RuntimeQueryRequest{}})
+	require.NoError(err, "supported requests should be sent to the runtime")
+	require.EqualValues(1, handler.calls)
+
+	_, err = protoB.Call(context.Background(), &Body{RuntimeAbortRequest: &Empty{}})
+	require.True(errors.Is(err, ErrFeatureNotSupported), "unsupported requests should be rejected")
+	_, err = protoB.Call(context.Background(), &Body{RuntimeConsensusSyncRequest: &RuntimeConsensusSyncRequest{}})
+	require.True(errors.Is(err, ErrFeatureNotSupported), "unsupported requests should be rejected")
+	require.EqualValues(1, handler.calls, "unsupported requests should not be sent to the runtime")
+
+	// Base protocol requests do not require any features.
+	_, err = protoB.Call(context.Background(), &Body{Empty: &Empty{}})
+	require.NoError(err, "base protocol requests should be sent to the runtime")
+	require.EqualValues(2, handler.calls)
+}
@@ -0,0 +1,139 @@
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/metrics"
+)
+
+// CallFunc is a function that sends a request to the other side of a Runtime Host Protocol
+// connection and waits for the response.
+type CallFunc func(ctx context.Context, body *Body) (*Body, error)
+
+// Middleware wraps a CallFunc in order to inject additional behavior (e.g., logging, rate
+// limiting, latency injection) around outgoing Runtime Host Protocol requests.
+type Middleware func(next CallFunc) CallFunc
+
+// Chain composes the given middleware into a single middleware. The first middleware in the list
+// is the outermost one, meaning that it is the first to see the request and the last to see the
+// response.
+func Chain(mws ...Middleware) Middleware {
+	return func(next CallFunc) CallFunc {
+		for i := len(mws) - 1; i >= 0; i-- {
+			if mws[i] == nil {
+				continue
+			}
+			next = mws[i](next)
+		}
+		return next
+	}
+}
+
+// MetricsMiddleware returns a middleware that records per-method call latency, successes and
+// failures.
+func MetricsMiddleware() Middleware {
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, body *Body) (rsp *Body, err error) {
+			if !metrics.Enabled() {
+				return next(ctx, body)
+			}
+
+			start := time.Now()
+			rsp, err = next(ctx, body)
+
+			call := body.Type()
+			rhpLatency.With(prometheus.Labels{"call": call}).Observe(time.Since(start).Seconds())
+			if err != nil {
+				rhpCallFailures.With(prometheus.Labels{"call": call}).Inc()
+			} else {
+				rhpCallSuccesses.With(prometheus.Labels{"call": call}).Inc()
+			}
+			return
+		}
+	}
+}
+
+// TracingMiddleware returns a middleware that records a span for each outgoing request.
+//
+// Spans are only exported in case tracing has been configured.
+func TracingMiddleware() Middleware {
+	tracer := otel.Tracer("oasis-core/runtime/host/protocol")
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, body *Body) (*Body, error) {
+			ctx, span := tracer.Start(ctx, "rhp."+body.Type())
+			defer span.End()
+
+			rsp, err := next(ctx, body)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return rsp, err
+		}
+	}
+}
+
+// LoggingMiddleware returns a middleware that logs all outgoing requests at debug level.
+func LoggingMiddleware(logger *logging.Logger) Middleware {
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, body *Body) (*Body, error) {
+			call := body.Type()
+			start := time.Now()
+			rsp, err := next(ctx, body)
+			logger.Debug("runtime host call",
+				"call", call,
+				"duration", time.Since(start),
+				"err", err,
+			)
+			return rsp, err
+		}
+	}
+}
+
+// ConcurrencyLimitMiddleware returns a middleware that limits the number of concurrent in-flight
+// requests. Requests over the limit block until a slot frees up or the context is cancelled.
+//
+// The limit must be positive.
+func ConcurrencyLimitMiddleware(limit int) (Middleware, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("protocol: concurrency limit must be positive (got %d)", limit)
+	}
+
+	slots := make(chan struct{}, limit)
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, body *Body) (*Body, error) {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			defer func() { <-slots }()
+
+			return next(ctx, body)
+		}
+	}, nil
+}
+
+// DelayMiddleware returns a middleware that delays each outgoing request by the duration
+// returned by the given function. This is mostly useful for latency injection in tests.
+func DelayMiddleware(delayFn func(body *Body) time.Duration) Middleware {
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, body *Body) (*Body, error) {
+			if d := delayFn(body); d > 0 {
+				select {
+				case <-time.After(d):
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+			return next(ctx, body)
+		}
+	}
+}
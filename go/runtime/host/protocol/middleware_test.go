@@ -0,0 +1,112 @@
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+func TestMiddlewareChain(t *testing.T) {
+	require := require.New(t)
+
+	var order []string
+	mw := func(name string) Middleware {
+		return func(next CallFunc) CallFunc {
+			return func(ctx context.Context, body *Body) (*Body, error) {
+				order = append(order, name)
+				return next(ctx, body)
+			}
+		}
+	}
+	fn := Chain(mw("a"), nil, mw("b"), mw("c"))(func(ctx context.Context, body *Body) (*Body, error) {
+		order = append(order, "call")
+		return body, nil
+	})
+
+	_, err := fn(context.Background(), &Body{Empty: &Empty{}})
+	require.NoError(err, "call")
+	require.EqualValues([]string{"a", "b", "c", "call"}, order, "middleware should be applied in order")
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	require := require.New(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	var callErr error
+	fn := TracingMiddleware()(func(ctx context.Context, body *Body) (*Body, error) {
+		require.True(trace.SpanContextFromContext(ctx).IsValid(), "span context should be propagated")
+		return body, callErr
+	})
+
+	_, err := fn(context.Background(), &Body{RuntimePingRequest: &Empty{}})
+	require.NoError(err, "call")
+	callErr = fmt.Errorf("call failed")
+	_, err = fn(context.Background(), &Body{RuntimePingRequest: &Empty{}})
+	require.Error(err, "call")
+
+	spans := recorder.Ended()
+	require.Len(spans, 2)
+	require.Equal("rhp.RuntimePingRequest", spans[0].Name())
+	require.Equal(codes.Unset, spans[0].Status().Code)
+	require.Equal(codes.Error, spans[1].Status().Code, "failed calls should be marked as errors")
+}
+
+func TestMiddlewareConnection(t *testing.T) {
+	require := require.New(t)
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("test conn"), 0)
+	logger := logging.GetLogger("test")
+
+	var calls []string
+	recorder := func(next CallFunc) CallFunc {
+		return func(ctx context.Context, body *Body) (*Body, error) {
+			calls = append(calls, body.Type())
+			return next(ctx, body)
+		}
+	}
+
+	_, err := ConcurrencyLimitMiddleware(0)
+	require.Error(err, "zero concurrency limit should be rejected")
+	_, err = ConcurrencyLimitMiddleware(-1)
+	require.Error(err, "negative concurrency limit should be rejected")
+	limiter, err := ConcurrencyLimitMiddleware(1)
+	require.NoError(err, "ConcurrencyLimitMiddleware")
+
+	connA, connB := net.Pipe()
+	protoA, err := NewConnection(logger, runtimeID, &testHandler{})
+	require.NoError(err, "A.New()")
+	protoB, err := NewConnection(logger, runtimeID, &testHandler{},
+		recorder,
+		DelayMiddleware(func(*Body) time.Duration { return 10 * time.Millisecond }),
+		limiter,
+	)
+	require.NoError(err, "B.New()")
+
+	err = protoA.InitGuest(context.Background(), connA)
+	require.NoError(err, "A.InitGuest()")
+	_, err = protoB.InitHost(context.Background(), connB, &HostInfo{})
+	require.NoError(err, "B.InitHost()")
+
+	start := time.Now()
+	_, err = protoB.Call(context.Background(), &Body{Empty: &Empty{}})
+	require.NoError(err, "B.Call()")
+	require.True(time.Since(start) >= 10*time.Millisecond, "delay middleware should delay the call")
+	require.EqualValues([]string{"RuntimeInfoRequest", "Empty"}, calls, "middleware should see all calls")
+
+	protoA.Close()
+	protoB.Close()
+}
@@ -158,6 +158,11 @@ type RuntimeInfoResponse struct {
 
 	// RuntimeVersion is the version of the runtime.
 	RuntimeVersion version.Version `json:"runtime_version"`
+
+	// Features is an optional list of protocol features supported by the runtime. In case it is
+	// empty (e.g., for runtimes older than protocol version 4.1.0), the features are derived from
+	// the runtime's protocol version.
+	Features []Feature `json:"features,omitempty"`
 }
 
 // RuntimeCapabilityTEERakInitRequest is a worker RFC 0009 CapabilityTEE
@@ -236,6 +241,13 @@ type CheckTxMetadata struct {
 
 	// Weight are runtime specific transaction weights.
 	Weights map[transaction.Weight]uint64 `json:"weights,omitempty"`
+
+	// Sender is the opaque transaction sender identifier. Transactions of the
+	// same sender with the same sequence number replace each other in the
+	// transaction pool.
+	Sender []byte `json:"sender,omitempty"`
+	// SenderSeq is the per-sender sequence number (nonce) of the transaction.
+	SenderSeq uint64 `json:"sender_seq,omitempty"`
 }
 
 // IsSuccess returns true if transaction execution was successful.
@@ -247,9 +259,11 @@ func (r *CheckTxResult) IsSuccess() bool {
 //
 // Assumes a successful result.
 func (r *CheckTxResult) ToCheckedTransaction(rawTx []byte) *transaction.CheckedTransaction {
-	switch r.Meta {
-	case nil:
+	switch {
+	case r.Meta == nil:
 		return transaction.NewCheckedTransaction(rawTx, 0, nil)
+	case len(r.Meta.Sender) > 0:
+		return transaction.NewCheckedTransactionWithSender(rawTx, r.Meta.Priority, r.Meta.Weights, r.Meta.Sender, r.Meta.SenderSeq)
 	default:
 		return transaction.NewCheckedTransaction(rawTx, r.Meta.Priority, r.Meta.Weights)
 	}
@@ -295,7 +295,7 @@ func (r *sandboxedRuntime) startProcess() (err error) {
 		"pid", p.GetPID(),
 	)
 
-	pc, err := protocol.NewConnection(r.logger, r.rtCfg.RuntimeID, r.rtCfg.MessageHandler)
+	pc, err := protocol.NewConnection(r.logger, r.rtCfg.RuntimeID, r.rtCfg.MessageHandler, r.rtCfg.Middleware...)
 	if err != nil {
 		return fmt.Errorf("failed to create connection: %w", err)
 	}
@@ -342,6 +342,7 @@ func (r *sandboxedRuntime) startProcess() (err error) {
 	r.conn = pc
 
 	// Notify subscribers that a runtime has been started.
+	ev.Features = pc.Features()
 	r.notifier.Broadcast(&host.Event{Started: ev})
 
 	return nil
@@ -0,0 +1,44 @@
+package sgx
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	attestationAge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_runtime_sgx_attestation_age_seconds",
+			Help: "Time since the last successful runtime attestation.",
+		},
+		[]string{"runtime"},
+	)
+	attestationCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_runtime_sgx_attestations",
+			Help: "Number of runtime attestation attempts.",
+		},
+		[]string{"runtime", "result"},
+	)
+
+	sgxCollectors = []prometheus.Collector{
+		attestationAge,
+		attestationCount,
+	}
+
+	metricsOnce sync.Once
+)
+
+func initMetrics() {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(sgxCollectors...)
+	})
+}
+
+func attestationResult(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
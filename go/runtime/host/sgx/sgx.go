@@ -4,17 +4,23 @@ package sgx
 import (
 	"bytes"
 	"context"
+	cryptorand "crypto/rand"
 	"encoding/binary"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/oasisprotocol/oasis-core/go/common"
+	cmnBackoff "github.com/oasisprotocol/oasis-core/go/common/backoff"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/mathrand"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx"
@@ -24,6 +30,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	ias "github.com/oasisprotocol/oasis-core/go/ias/api"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/metrics"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/sandbox"
@@ -44,6 +51,14 @@ const (
 	runtimeRAKTimeout = 60 * time.Second
 	// Runtime attest interval.
 	defaultRuntimeAttestInterval = 1 * time.Hour
+	// Runtime attest jitter.
+	defaultRuntimeAttestJitter = 0.1
+	// Maximum interval between retries of failed runtime attestations.
+	maxRuntimeAttestRetryInterval = 5 * time.Minute
+	// Minimum interval between runtime attestations when bounded by the attestation validity.
+	minRuntimeAttestInterval = 1 * time.Minute
+	// Interval for updating the attestation age metric.
+	attestationAgeUpdateInterval = 15 * time.Second
 )
 
 // Config contains SGX-specific provisioner configuration options.
@@ -58,9 +73,15 @@ type Config struct {
 	IAS ias.Endpoint
 
 	// RuntimeAttestInterval is the interval for periodic runtime re-attestation. If not specified
-	// a default will be used.
+	// a default will be used. The interval is additionally bounded so that re-attestation always
+	// happens before the current attestation stops being valid.
 	RuntimeAttestInterval time.Duration
 
+	// RuntimeAttestJitter is the fraction of the re-attestation interval by which each
+	// re-attestation is randomly brought forward so that runtimes sharing the same host do not
+	// all attest at the same time. If not specified a default will be used, zero disables jitter.
+	RuntimeAttestJitter *float64
+
 	// SandboxBinaryPath is the path to the sandbox support binary.
 	SandboxBinaryPath string
 
@@ -85,6 +106,10 @@ type teeState struct {
 	epidGID   uint32
 	spid      cmnIAS.SPID
 	quoteType *cmnIAS.SignatureType
+
+	// attestationNotAfter is the time after which the last attestation is no longer valid. The
+	// zero value means that the validity is not bounded.
+	attestationNotAfter time.Time
 }
 
 type sgxProvisioner struct {
@@ -96,6 +121,8 @@ type sgxProvisioner struct {
 	ias     ias.Endpoint
 	aesm    *aesm.Client
 
+	rng *rand.Rand
+
 	logger *logging.Logger
 }
 
@@ -334,6 +361,14 @@ func (s *sgxProvisioner) updateCapabilityTEE(ctx context.Context, ts *teeState,
 		return nil, fmt.Errorf("error while configuring AVR: %w", err)
 	}
 
+	// Mock AVRs are not signed and their validity is not bounded.
+	ts.attestationNotAfter = time.Time{}
+	if len(avrBundle.CertificateChain) > 0 {
+		if ts.attestationNotAfter, err = avrBundle.NotAfter(); err != nil {
+			return nil, fmt.Errorf("error while determining attestation validity: %w", err)
+		}
+	}
+
 	attestation := cbor.Marshal(avrBundle)
 	capabilityTEE := &node.CapabilityTEE{
 		Hardware:    node.TEEHardwareIntelSGX,
@@ -344,33 +379,87 @@ func (s *sgxProvisioner) updateCapabilityTEE(ctx context.Context, ts *teeState,
 	return capabilityTEE, nil
 }
 
-func (s *sgxProvisioner) attestationWorker(ts *teeState, p process.Process, conn protocol.Connection) {
-	t := time.NewTicker(s.cfg.RuntimeAttestInterval)
-	defer t.Stop()
+// nextAttestationDelay returns the delay until the next periodic re-attestation.
+//
+// The delay is bounded to half of the remaining attestation validity so that there is enough
+// time for retries before the attestation expires.
+func (s *sgxProvisioner) nextAttestationDelay(notAfter time.Time) time.Duration {
+	s.Lock()
+	jitter := s.rng.Float64() * *s.cfg.RuntimeAttestJitter
+	s.Unlock()
+
+	interval := s.cfg.RuntimeAttestInterval
+	if validity := time.Until(notAfter) / 2; !notAfter.IsZero() && validity < interval {
+		interval = validity
+		if interval < minRuntimeAttestInterval {
+			interval = minRuntimeAttestInterval
+		}
+	}
+	return interval - time.Duration(jitter*float64(interval))
+}
 
+func (s *sgxProvisioner) attestationWorker(ts *teeState, p process.Process, conn protocol.Connection) {
 	logger := s.logger.With("runtime_id", ts.runtimeID)
 
+	// The initial attestation has just been performed by the host initializer.
+	lastAttestation := time.Now()
+	labels := prometheus.Labels{"runtime": ts.runtimeID.String()}
+	updateAgeMetric := func() {
+		if metrics.Enabled() {
+			attestationAge.With(labels).Set(time.Since(lastAttestation).Seconds())
+		}
+	}
+	updateAgeMetric()
+	defer attestationAge.Delete(labels)
+
+	ageTicker := time.NewTicker(attestationAgeUpdateInterval)
+	defer ageTicker.Stop()
+
+	boff := cmnBackoff.NewExponentialBackOff()
+	boff.MaxInterval = maxRuntimeAttestRetryInterval
+
+	timer := time.NewTimer(s.nextAttestationDelay(ts.attestationNotAfter))
+	defer timer.Stop()
+
 	for {
 		select {
 		case <-p.Wait():
 			// Process has terminated.
 			return
-		case <-t.C:
+		case <-ageTicker.C:
+			updateAgeMetric()
+		case <-timer.C:
 			// Update CapabilityTEE.
 			logger.Info("regenerating CapabilityTEE")
 
 			capabilityTEE, err := s.updateCapabilityTEE(context.Background(), ts, conn)
+			if metrics.Enabled() {
+				attestationCount.With(prometheus.Labels{
+					"runtime": ts.runtimeID.String(),
+					"result":  attestationResult(err),
+				}).Inc()
+			}
 			if err != nil {
+				retryDelay := boff.NextBackOff()
 				logger.Error("failed to regenerate CapabilityTEE",
 					"err", err,
+					"attestation_age", time.Since(lastAttestation),
+					"attestation_not_after", ts.attestationNotAfter,
+					"retry_in", retryDelay,
 				)
+				timer.Reset(retryDelay)
 				continue
 			}
+			boff.Reset()
+			lastAttestation = time.Now()
+			updateAgeMetric()
 
 			// Emit event about the updated CapabilityTEE.
 			ts.eventEmitter.EmitEvent(&host.Event{Updated: &host.UpdatedEvent{
 				CapabilityTEE: capabilityTEE,
 			}})
+
+			timer.Reset(s.nextAttestationDelay(ts.attestationNotAfter))
 		}
 	}
 }
@@ -386,11 +475,25 @@ func New(cfg Config) (host.Provisioner, error) {
 	if cfg.RuntimeAttestInterval == 0 {
 		cfg.RuntimeAttestInterval = defaultRuntimeAttestInterval
 	}
+	if cfg.RuntimeAttestInterval < 0 {
+		return nil, fmt.Errorf("runtime/host/sgx: attestation interval must be positive")
+	}
+	// Use a default RuntimeAttestJitter if none was provided.
+	if cfg.RuntimeAttestJitter == nil {
+		jitter := defaultRuntimeAttestJitter
+		cfg.RuntimeAttestJitter = &jitter
+	}
+	if *cfg.RuntimeAttestJitter < 0 || *cfg.RuntimeAttestJitter >= 1 {
+		return nil, fmt.Errorf("runtime/host/sgx: attestation jitter must be in range [0, 1)")
+	}
+
+	initMetrics()
 
 	s := &sgxProvisioner{
 		cfg:    cfg,
 		ias:    cfg.IAS,
 		aesm:   aesm.NewClient(aesmdSocketPath),
+		rng:    rand.New(mathrand.New(cryptorand.Reader)),
 		logger: logging.GetLogger("runtime/host/sgx"),
 	}
 	p, err := sandbox.New(sandbox.Config{
@@ -2,6 +2,7 @@ package sgx
 
 import (
 	"context"
+	"math/rand"
 	"os"
 	"testing"
 	"time"
@@ -93,6 +94,33 @@ func TestProvisionerSGX(t *testing.T) {
 	})
 }
 
+func TestNextAttestationDelay(t *testing.T) {
+	require := require.New(t)
+
+	noJitter := 0.0
+	s := &sgxProvisioner{
+		cfg: Config{
+			RuntimeAttestInterval: time.Hour,
+			RuntimeAttestJitter:   &noJitter,
+		},
+		rng: rand.New(rand.NewSource(1)), // nolint: gosec
+	}
+	require.Equal(time.Hour, s.nextAttestationDelay(time.Time{}), "zero jitter should disable jitter")
+	require.Equal(time.Hour, s.nextAttestationDelay(time.Now().Add(24*time.Hour)), "distant expiry should not bound the delay")
+
+	delay := s.nextAttestationDelay(time.Now().Add(time.Hour))
+	require.True(delay <= 30*time.Minute, "delay should be bounded by the attestation validity")
+	require.True(delay > 29*time.Minute, "delay should be bounded by the attestation validity")
+	require.Equal(minRuntimeAttestInterval, s.nextAttestationDelay(time.Now().Add(-time.Hour)), "delay should not go below the minimum")
+
+	jitter := 0.5
+	s.cfg.RuntimeAttestJitter = &jitter
+	for i := 0; i < 100; i++ {
+		delay = s.nextAttestationDelay(time.Time{})
+		require.True(delay > 30*time.Minute && delay <= time.Hour, "delay should include jitter")
+	}
+}
+
 func testAttestationWorker(t *testing.T, cfg host.Config, p host.Provisioner) {
 	require := require.New(t)
 
@@ -46,17 +46,51 @@ const (
 	//
 	// The value should be a map of runtime IDs to corresponding resource paths.
 	CfgRuntimeSGXSignatures = "runtime.sgx.signatures"
+	// CfgRuntimeSGXAttestInterval configures the interval for periodic re-attestation of SGX
+	// runtimes.
+	//
+	// The interval should be shorter than the maximum age of attestations accepted by the
+	// registry so that nodes are never left with a stale attestation.
+	CfgRuntimeSGXAttestInterval = "runtime.sgx.attestation.interval"
+	// CfgRuntimeSGXAttestJitter configures the fraction of the re-attestation interval by which
+	// each periodic re-attestation is randomly brought forward. Zero disables jitter.
+	CfgRuntimeSGXAttestJitter = "runtime.sgx.attestation.jitter"
 
 	// CfgRuntimeConfig configures node-local runtime configuration.
 	CfgRuntimeConfig = "runtime.config"
 
+	// CfgCallCheckTxTimeout configures the default timeout for runtime CheckTx calls.
+	CfgCallCheckTxTimeout = "runtime.call.check_tx.timeout"
+	// CfgCallCheckTxMaxConcurrent configures the default maximum number of concurrent runtime
+	// CheckTx calls.
+	CfgCallCheckTxMaxConcurrent = "runtime.call.check_tx.max_concurrent"
+	// CfgCallQueryTimeout configures the default timeout for runtime Query calls.
+	CfgCallQueryTimeout = "runtime.call.query.timeout"
+	// CfgCallQueryMaxConcurrent configures the default maximum number of concurrent runtime Query
+	// calls.
+	CfgCallQueryMaxConcurrent = "runtime.call.query.max_concurrent"
+	// CfgCallExecuteTimeout configures the default timeout for runtime batch execution calls.
+	CfgCallExecuteTimeout = "runtime.call.execute.timeout"
+	// CfgCallExecuteMaxConcurrent configures the default maximum number of concurrent runtime
+	// batch execution calls.
+	CfgCallExecuteMaxConcurrent = "runtime.call.execute.max_concurrent"
+	// CfgRuntimeCallLimits configures per-runtime overrides of the runtime call limits.
+	//
+	// The value should be a map of runtime IDs to call limits.
+	CfgRuntimeCallLimits = "runtime.call_limits"
+
 	// CfgHistoryPrunerStrategy configures the history pruner strategy.
 	CfgHistoryPrunerStrategy = "runtime.history.pruner.strategy"
 	// CfgHistoryPrunerInterval configures the history pruner interval.
 	CfgHistoryPrunerInterval = "runtime.history.pruner.interval"
 	// CfgHistoryPrunerKeepLastNum configures the number of last kept
-	// rounds when using the "keep last" pruner strategy.
+	// rounds (or epochs) when using the "keep last" (or "keep last epochs")
+	// pruner strategy.
 	CfgHistoryPrunerKeepLastNum = "runtime.history.pruner.num_kept"
+	// CfgHistoryPrunerRuntimes configures per-runtime overrides of the history pruner strategy.
+	//
+	// The value should be a map of runtime IDs to pruner configuration (strategy and num_kept).
+	CfgHistoryPrunerRuntimes = "runtime.history.pruner.runtimes"
 )
 
 // Flags has the configuration flags.
@@ -85,6 +119,39 @@ type RuntimeConfig struct {
 
 	// History configures the runtime history keeper.
 	History history.Config
+
+	// RuntimeHistory contains per-runtime overrides of the runtime history keeper configuration.
+	RuntimeHistory map[common.Namespace]*history.Config
+}
+
+// historyConfig returns the runtime history keeper configuration for the given runtime.
+func (cfg *RuntimeConfig) historyConfig(runtimeID common.Namespace) *history.Config {
+	if hc, ok := cfg.RuntimeHistory[runtimeID]; ok {
+		return hc
+	}
+	return &cfg.History
+}
+
+// historyPrunerConfig is the (per-runtime) history pruner configuration.
+type historyPrunerConfig struct {
+	// Strategy is the history pruner strategy.
+	Strategy string `mapstructure:"strategy"`
+
+	// NumKept is the number of last kept rounds (or epochs).
+	NumKept uint64 `mapstructure:"num_kept"`
+}
+
+func newHistoryPruner(cfg *historyPrunerConfig) (history.PrunerFactory, error) {
+	switch strings.ToLower(cfg.Strategy) {
+	case history.PrunerStrategyNone:
+		return history.NewNonePruner(), nil
+	case history.PrunerStrategyKeepLast:
+		return history.NewKeepLastPruner(cfg.NumKept), nil
+	case history.PrunerStrategyKeepLastEpochs:
+		return history.NewKeepLastEpochsPruner(cfg.NumKept), nil
+	default:
+		return nil, fmt.Errorf("runtime/registry: unknown history pruner strategy: %s", cfg.Strategy)
+	}
 }
 
 // RuntimeHostConfig is configuration for a node that hosts runtimes.
@@ -170,12 +237,15 @@ func newConfig(consensus consensus.Backend, ias ias.Endpoint) (*RuntimeConfig, e
 				}
 			default:
 				// Configure the provided SGX loader.
+				attestJitter := viper.GetFloat64(CfgRuntimeSGXAttestJitter)
 				rh.Provisioners[node.TEEHardwareIntelSGX], err = hostSgx.New(hostSgx.Config{
-					HostInfo:          hostInfo,
-					LoaderPath:        sgxLoader,
-					IAS:               ias,
-					SandboxBinaryPath: sandboxBinary,
-					InsecureNoSandbox: insecureNoSandbox,
+					HostInfo:              hostInfo,
+					LoaderPath:            sgxLoader,
+					IAS:                   ias,
+					RuntimeAttestInterval: viper.GetDuration(CfgRuntimeSGXAttestInterval),
+					RuntimeAttestJitter:   &attestJitter,
+					SandboxBinaryPath:     sandboxBinary,
+					InsecureNoSandbox:     insecureNoSandbox,
 				})
 				if err != nil {
 					return nil, fmt.Errorf("failed to create SGX runtime provisioner: %w", err)
@@ -185,6 +255,22 @@ func newConfig(consensus consensus.Backend, ias ias.Endpoint) (*RuntimeConfig, e
 			return nil, fmt.Errorf("unsupported runtime provisioner: %s", p)
 		}
 
+		// Configure default runtime call limits.
+		defaultCallLimits := runtimeHost.CallLimits{
+			CheckTx: runtimeHost.CallLimit{
+				Timeout:       viper.GetDuration(CfgCallCheckTxTimeout),
+				MaxConcurrent: viper.GetInt(CfgCallCheckTxMaxConcurrent),
+			},
+			Query: runtimeHost.CallLimit{
+				Timeout:       viper.GetDuration(CfgCallQueryTimeout),
+				MaxConcurrent: viper.GetInt(CfgCallQueryMaxConcurrent),
+			},
+			Execute: runtimeHost.CallLimit{
+				Timeout:       viper.GetDuration(CfgCallExecuteTimeout),
+				MaxConcurrent: viper.GetInt(CfgCallExecuteMaxConcurrent),
+			},
+		}
+
 		// Configure runtimes.
 		runtimeSGXSignatures := viper.GetStringMapString(CfgRuntimeSGXSignatures)
 		rh.Runtimes = make(map[common.Namespace]*runtimeHost.Config)
@@ -202,10 +288,26 @@ func newConfig(consensus consensus.Backend, ias ias.Endpoint) (*RuntimeConfig, e
 				}
 			}
 
+			// Unmarshal any per-runtime call limit overrides.
+			callLimits := defaultCallLimits
+			if sub := viper.Sub(CfgRuntimeCallLimits); sub != nil {
+				if err := sub.UnmarshalKey(runtimeID, &callLimits); err != nil {
+					return nil, fmt.Errorf("bad runtime call limits: %w", err)
+				}
+			}
+
+			callLimitsMiddleware, err := callLimits.Middleware(id)
+			if err != nil {
+				return nil, fmt.Errorf("bad runtime call limits: %w", err)
+			}
+
 			runtimeHostCfg := &runtimeHost.Config{
 				RuntimeID:   id,
 				Path:        path,
 				LocalConfig: localConfig,
+				Middleware: []hostProtocol.Middleware{
+					callLimitsMiddleware,
+				},
 			}
 
 			// This config is SGX specific, but that's all that's supported
@@ -231,15 +333,13 @@ func newConfig(consensus consensus.Backend, ias ias.Endpoint) (*RuntimeConfig, e
 		cfg.Host = &rh
 	}
 
-	strategy := viper.GetString(CfgHistoryPrunerStrategy)
-	switch strings.ToLower(strategy) {
-	case history.PrunerStrategyNone:
-		cfg.History.Pruner = history.NewNonePruner()
-	case history.PrunerStrategyKeepLast:
-		numKept := viper.GetUint64(CfgHistoryPrunerKeepLastNum)
-		cfg.History.Pruner = history.NewKeepLastPruner(numKept)
-	default:
-		return nil, fmt.Errorf("runtime/registry: unknown history pruner strategy: %s", strategy)
+	defaultPrunerCfg := historyPrunerConfig{
+		Strategy: viper.GetString(CfgHistoryPrunerStrategy),
+		NumKept:  viper.GetUint64(CfgHistoryPrunerKeepLastNum),
+	}
+	var err error
+	if cfg.History.Pruner, err = newHistoryPruner(&defaultPrunerCfg); err != nil {
+		return nil, err
 	}
 
 	cfg.History.PruneInterval = viper.GetDuration(CfgHistoryPrunerInterval)
@@ -247,6 +347,28 @@ func newConfig(consensus consensus.Backend, ias ias.Endpoint) (*RuntimeConfig, e
 		return nil, fmt.Errorf("runtime/registry: history prune interval must be >= 1s (got %s)", cfg.History.PruneInterval)
 	}
 
+	// Configure any per-runtime history pruner overrides.
+	if sub := viper.Sub(CfgHistoryPrunerRuntimes); sub != nil {
+		cfg.RuntimeHistory = make(map[common.Namespace]*history.Config)
+		for runtimeID := range sub.AllSettings() {
+			var id common.Namespace
+			if err = id.UnmarshalHex(runtimeID); err != nil {
+				return nil, fmt.Errorf("bad runtime identifier '%s': %w", runtimeID, err)
+			}
+
+			prunerCfg := defaultPrunerCfg
+			if err = sub.UnmarshalKey(runtimeID, &prunerCfg); err != nil {
+				return nil, fmt.Errorf("bad runtime history pruner configuration: %w", err)
+			}
+
+			historyCfg := cfg.History
+			if historyCfg.Pruner, err = newHistoryPruner(&prunerCfg); err != nil {
+				return nil, err
+			}
+			cfg.RuntimeHistory[id] = &historyCfg
+		}
+	}
+
 	return &cfg, nil
 }
 
@@ -258,10 +380,19 @@ func init() {
 	Flags.String(CfgSandboxBinary, "/usr/bin/bwrap", "Path to the sandbox binary (bubblewrap)")
 	Flags.String(CfgRuntimeSGXLoader, "", "(for SGX runtimes) Path to SGXS runtime loader binary")
 	Flags.StringToString(CfgRuntimeSGXSignatures, nil, "(for SGX runtimes) Paths to signatures (format: <rt1-ID>=<path>,<rt2-ID>=<path>")
+	Flags.Duration(CfgRuntimeSGXAttestInterval, time.Hour, "(for SGX runtimes) Periodic re-attestation interval")
+	Flags.Float64(CfgRuntimeSGXAttestJitter, 0.1, "(for SGX runtimes) Fraction of the re-attestation interval used as random jitter (0 = no jitter)")
+
+	Flags.Duration(CfgCallCheckTxTimeout, 0, "Default runtime CheckTx call timeout (0 = no timeout)")
+	Flags.Int(CfgCallCheckTxMaxConcurrent, 0, "Default maximum number of concurrent runtime CheckTx calls (0 = no limit)")
+	Flags.Duration(CfgCallQueryTimeout, 0, "Default runtime Query call timeout (0 = no timeout)")
+	Flags.Int(CfgCallQueryMaxConcurrent, 0, "Default maximum number of concurrent runtime Query calls (0 = no limit)")
+	Flags.Duration(CfgCallExecuteTimeout, 0, "Default runtime batch execution call timeout (0 = no timeout)")
+	Flags.Int(CfgCallExecuteMaxConcurrent, 0, "Default maximum number of concurrent runtime batch execution calls (0 = no limit)")
 
 	Flags.String(CfgHistoryPrunerStrategy, history.PrunerStrategyNone, "History pruner strategy")
 	Flags.Duration(CfgHistoryPrunerInterval, 2*time.Minute, "History pruning interval")
-	Flags.Uint64(CfgHistoryPrunerKeepLastNum, 600, "Keep last history pruner: number of last rounds to keep")
+	Flags.Uint64(CfgHistoryPrunerKeepLastNum, 600, "Keep last (epochs) history pruner: number of last rounds (epochs) to keep")
 
 	_ = viper.BindPFlags(Flags)
 }
@@ -299,6 +299,10 @@ func (n *runtimeHostNotifier) watchPolicyUpdates() {
 		ctx, cancel := context.WithTimeout(n.ctx, notifyTimeout)
 		response, err := n.host.Call(ctx, req)
 		cancel()
+		if errors.Is(err, protocol.ErrFeatureNotSupported) {
+			n.logger.Debug("runtime does not support key manager policy updates, skipping")
+			continue
+		}
 		if err != nil {
 			n.logger.Error("failed dispatching key manager policy update to runtime",
 				"err", err,
@@ -352,6 +356,12 @@ func (n *runtimeHostNotifier) watchConsensusLightBlocks() {
 			ctx, cancel := context.WithTimeout(n.ctx, notifyTimeout)
 			_, err = n.host.Call(ctx, req)
 			cancel()
+			if errors.Is(err, protocol.ErrFeatureNotSupported) {
+				n.logger.Debug("runtime does not support consensus layer sync, skipping",
+					"height", blk.Height,
+				)
+				continue
+			}
 			if err != nil {
 				n.logger.Error("failed to notify runtime of a new consensus layer block",
 					"err", err,
@@ -511,7 +511,7 @@ func (r *runtimeRegistry) addSupportedRuntime(ctx context.Context, id common.Nam
 	rt.managed = true
 
 	// Create runtime history keeper.
-	history, err := history.New(path, id, &r.cfg.History)
+	history, err := history.New(path, id, r.cfg.historyConfig(id))
 	if err != nil {
 		return fmt.Errorf("runtime/registry: cannot create block history for runtime %s: %w", id, err)
 	}
@@ -0,0 +1,102 @@
+package replay
+
+import (
+	"context"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
+)
+
+const moduleName = "runtime/replay"
+
+var (
+	errMethodNotSupported   = errors.New(moduleName, 1, "replay: method not supported")
+	errEndpointNotSupported = errors.New(moduleName, 2, "replay: endpoint not supported")
+)
+
+var _ protocol.Handler = (*hostHandler)(nil)
+
+// hostHandler is a runtime host handler used during replay. It serves storage reads from the
+// configured (remote) backends and keeps runtime local storage in memory so that the replay does
+// not have any side effects.
+type hostHandler struct {
+	sync.Mutex
+
+	consensus consensus.ClientBackend
+	storage   syncer.ReadSyncer
+
+	localStorage map[string][]byte
+}
+
+// Implements protocol.Handler.
+func (h *hostHandler) Handle(ctx context.Context, body *protocol.Body) (*protocol.Body, error) {
+	// Storage.
+	if body.HostStorageSyncRequest != nil {
+		rq := body.HostStorageSyncRequest
+
+		var rs syncer.ReadSyncer
+		switch rq.Endpoint {
+		case protocol.HostStorageEndpointRuntime:
+			rs = h.storage
+		case protocol.HostStorageEndpointConsensus:
+			rs = h.consensus.State()
+		default:
+			return nil, errEndpointNotSupported
+		}
+
+		var rsp *storage.ProofResponse
+		var err error
+		switch {
+		case rq.SyncGet != nil:
+			rsp, err = rs.SyncGet(ctx, rq.SyncGet)
+		case rq.SyncGetPrefixes != nil:
+			rsp, err = rs.SyncGetPrefixes(ctx, rq.SyncGetPrefixes)
+		case rq.SyncIterate != nil:
+			rsp, err = rs.SyncIterate(ctx, rq.SyncIterate)
+		default:
+			return nil, errMethodNotSupported
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return &protocol.Body{HostStorageSyncResponse: &protocol.HostStorageSyncResponse{ProofResponse: rsp}}, nil
+	}
+	// Local storage.
+	if body.HostLocalStorageGetRequest != nil {
+		h.Lock()
+		value := h.localStorage[string(body.HostLocalStorageGetRequest.Key)]
+		h.Unlock()
+		return &protocol.Body{HostLocalStorageGetResponse: &protocol.HostLocalStorageGetResponse{Value: value}}, nil
+	}
+	if body.HostLocalStorageSetRequest != nil {
+		h.Lock()
+		h.localStorage[string(body.HostLocalStorageSetRequest.Key)] = body.HostLocalStorageSetRequest.Value
+		h.Unlock()
+		return &protocol.Body{HostLocalStorageSetResponse: &protocol.Empty{}}, nil
+	}
+	// Consensus light client.
+	if body.HostFetchConsensusBlockRequest != nil {
+		lb, err := h.consensus.GetLightBlock(ctx, int64(body.HostFetchConsensusBlockRequest.Height))
+		if err != nil {
+			return nil, err
+		}
+		return &protocol.Body{HostFetchConsensusBlockResponse: &protocol.HostFetchConsensusBlockResponse{
+			Block: *lb,
+		}}, nil
+	}
+
+	return nil, errMethodNotSupported
+}
+
+func newHostHandler(consensus consensus.ClientBackend, storage syncer.ReadSyncer) *hostHandler {
+	return &hostHandler{
+		consensus:    consensus,
+		storage:      storage,
+		localStorage: make(map[string][]byte),
+	}
+}
@@ -0,0 +1,427 @@
+// Package replay implements deterministic replay of historical runtime rounds.
+//
+// The replayer fetches the inputs of a historical runtime round together with all of the context
+// that was available to the executor nodes at that time (parent block, consensus light block,
+// round results, epoch), re-executes the batch against a locally provisioned runtime and compares
+// the produced roots against the ones that were finalized on-chain. This is useful for debugging
+// runtime determinism violations.
+package replay
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+	runtimeClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
+)
+
+// Config is the replayer configuration.
+type Config struct {
+	// RuntimeID is the identifier of the runtime being replayed.
+	RuntimeID common.Namespace
+
+	// RuntimePath is the path to the local runtime binary that should be used for re-execution.
+	RuntimePath string
+
+	// LocalConfig is the optional node-local runtime configuration.
+	LocalConfig map[string]interface{}
+
+	// Provisioner is the runtime provisioner used to provision the local runtime.
+	Provisioner host.Provisioner
+
+	// Consensus is the consensus backend used to fetch historical consensus state.
+	Consensus consensus.ClientBackend
+
+	// Runtime is the runtime client used to fetch historical runtime blocks and inputs.
+	Runtime runtimeClient.RuntimeClient
+
+	// Storage is the read syncer used for serving runtime state reads during re-execution.
+	Storage syncer.ReadSyncer
+}
+
+// Mismatch describes a difference between the on-chain and the re-computed results.
+type Mismatch struct {
+	// Field is the name of the header field that differs.
+	Field string `json:"field"`
+	// Expected is the value finalized on-chain.
+	Expected hash.Hash `json:"expected"`
+	// Computed is the value produced by the local runtime (if any).
+	Computed *hash.Hash `json:"computed,omitempty"`
+}
+
+// Result is the result of replaying a single runtime round.
+type Result struct {
+	// Round is the replayed runtime round.
+	Round uint64 `json:"round"`
+	// Height is the consensus height at which the parent block was finalized.
+	Height int64 `json:"height"`
+	// NumInputs is the number of inputs in the replayed batch.
+	NumInputs int `json:"num_inputs"`
+
+	// Expected is the block header that was finalized on-chain.
+	Expected block.Header `json:"expected"`
+	// Computed is the compute results header produced by the local runtime.
+	Computed commitment.ComputeResultsHeader `json:"computed"`
+
+	// Mismatches is a list of differences between the expected and computed results.
+	Mismatches []Mismatch `json:"mismatches,omitempty"`
+}
+
+// IsDeterministic returns true iff the re-computed results match the on-chain results.
+func (r *Result) IsDeterministic() bool {
+	return len(r.Mismatches) == 0
+}
+
+// Replayer replays historical runtime rounds.
+type Replayer struct {
+	cfg Config
+
+	logger *logging.Logger
+}
+
+// Replay re-executes the given runtime round and compares the results against the ones that were
+// finalized on-chain.
+func (r *Replayer) Replay(ctx context.Context, round uint64) (*Result, error) {
+	rq, blk, height, err := r.prepareRequest(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Info("re-executing runtime round",
+		"round", round,
+		"height", height,
+		"num_inputs", len(rq.Inputs),
+	)
+
+	rsp, err := r.execute(ctx, rq)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{
+		Round:     round,
+		Height:    height,
+		NumInputs: len(rq.Inputs),
+		Expected:  blk.Header,
+		Computed:  rsp.Batch.Header,
+	}
+	res.Mismatches = diffHeaders(&res.Expected, &res.Computed)
+
+	return res, nil
+}
+
+func (r *Replayer) prepareRequest(ctx context.Context, round uint64) (*protocol.RuntimeExecuteTxBatchRequest, *block.Block, int64, error) {
+	if round == 0 {
+		return nil, nil, 0, fmt.Errorf("replay: cannot replay the genesis round")
+	}
+
+	blk, err := r.cfg.Runtime.GetBlock(ctx, &runtimeClient.GetBlockRequest{
+		RuntimeID: r.cfg.RuntimeID,
+		Round:     round,
+	})
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("replay: failed to fetch block for round %d: %w", round, err)
+	}
+	if blk.Header.HeaderType != block.Normal {
+		return nil, nil, 0, fmt.Errorf("replay: round %d is not a normal round (type: %d)", round, blk.Header.HeaderType)
+	}
+
+	parent, err := r.cfg.Runtime.GetBlock(ctx, &runtimeClient.GetBlockRequest{
+		RuntimeID: r.cfg.RuntimeID,
+		Round:     round - 1,
+	})
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("replay: failed to fetch parent block for round %d: %w", round, err)
+	}
+
+	inputs, err := r.cfg.Runtime.GetTransactions(ctx, &runtimeClient.GetTransactionsRequest{
+		RuntimeID: r.cfg.RuntimeID,
+		Round:     round,
+	})
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("replay: failed to fetch inputs for round %d: %w", round, err)
+	}
+
+	height, err := r.findParentHeight(ctx, parent.Header.Round)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	state, err := r.cfg.Consensus.RootHash().GetRuntimeState(ctx, &roothash.RuntimeRequest{
+		RuntimeID: r.cfg.RuntimeID,
+		Height:    height,
+	})
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("replay: failed to fetch runtime state at height %d: %w", height, err)
+	}
+
+	roundResults, err := r.getRoundResults(ctx, state)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	consensusBlk, err := r.cfg.Consensus.GetLightBlock(ctx, height)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("replay: failed to fetch consensus light block at height %d: %w", height, err)
+	}
+
+	epoch, err := r.cfg.Consensus.Beacon().GetEpoch(ctx, height)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("replay: failed to fetch epoch at height %d: %w", height, err)
+	}
+
+	params, err := r.cfg.Consensus.RootHash().ConsensusParameters(ctx, height)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("replay: failed to fetch roothash consensus parameters at height %d: %w", height, err)
+	}
+
+	ioRoot, err := computeInputRoot(ctx, parent, inputs)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return &protocol.RuntimeExecuteTxBatchRequest{
+		ConsensusBlock: *consensusBlk,
+		RoundResults:   roundResults,
+		IORoot:         ioRoot,
+		Inputs:         inputs,
+		Block:          *parent,
+		Epoch:          epoch,
+		MaxMessages:    params.EffectiveMaxRuntimeMessages(state.Runtime),
+	}, blk, height, nil
+}
+
+// findParentHeight finds the consensus height at which the given runtime round was finalized.
+func (r *Replayer) findParentHeight(ctx context.Context, round uint64) (int64, error) {
+	status, err := r.cfg.Consensus.GetStatus(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("replay: failed to fetch consensus status: %w", err)
+	}
+
+	latestRound := func(height int64) (uint64, error) {
+		blk, err := r.cfg.Consensus.RootHash().GetLatestBlock(ctx, &roothash.RuntimeRequest{
+			RuntimeID: r.cfg.RuntimeID,
+			Height:    height,
+		})
+		switch err {
+		case nil:
+			return blk.Header.Round, nil
+		case roothash.ErrInvalidRuntime:
+			// Runtime was not yet registered at the given height.
+			return 0, nil
+		default:
+			return 0, fmt.Errorf("replay: failed to fetch latest block at height %d: %w", height, err)
+		}
+	}
+
+	// Find the lowest height at which the latest runtime block is at least the given round.
+	lo, hi := status.LastRetainedHeight, status.LatestHeight
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		rnd, err := latestRound(mid)
+		if err != nil {
+			return 0, err
+		}
+		if rnd >= round {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	rnd, err := latestRound(lo)
+	if err != nil {
+		return 0, err
+	}
+	if rnd != round {
+		return 0, fmt.Errorf("replay: round %d not available in retained consensus state (last retained height: %d)",
+			round, status.LastRetainedHeight,
+		)
+	}
+	return lo, nil
+}
+
+// getRoundResults reconstructs the results of the last normal round as seen by the executors.
+func (r *Replayer) getRoundResults(ctx context.Context, state *roothash.RuntimeState) (*roothash.RoundResults, error) {
+	results := new(roothash.RoundResults)
+	if state.LastNormalRound == state.GenesisBlock.Header.Round {
+		return results, nil
+	}
+
+	evs, err := r.cfg.Consensus.RootHash().GetEvents(ctx, state.LastNormalHeight)
+	if err != nil {
+		return nil, fmt.Errorf("replay: failed to fetch roothash events at height %d: %w", state.LastNormalHeight, err)
+	}
+
+	for _, ev := range evs {
+		switch {
+		case !ev.RuntimeID.Equal(&r.cfg.RuntimeID):
+			continue
+		case ev.Message != nil:
+			results.Messages = append(results.Messages, ev.Message)
+		case ev.Finalized != nil:
+			results.GoodComputeEntities, err = r.getNodeEntities(ctx, state.LastNormalHeight, ev.Finalized.GoodComputeNodes)
+			if err != nil {
+				return nil, err
+			}
+			results.BadComputeEntities, err = r.getNodeEntities(ctx, state.LastNormalHeight, ev.Finalized.BadComputeNodes)
+			if err != nil {
+				return nil, err
+			}
+		default:
+		}
+	}
+	return results, nil
+}
+
+func (r *Replayer) getNodeEntities(ctx context.Context, height int64, nodes []signature.PublicKey) ([]signature.PublicKey, error) {
+	var entities []signature.PublicKey
+	seen := make(map[signature.PublicKey]bool)
+	for _, id := range nodes {
+		n, err := r.cfg.Consensus.Registry().GetNode(ctx, &registry.IDQuery{
+			Height: height,
+			ID:     id,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("replay: failed to fetch node %s: %w", id, err)
+		}
+
+		if seen[n.EntityID] {
+			continue
+		}
+		seen[n.EntityID] = true
+		entities = append(entities, n.EntityID)
+	}
+	return entities, nil
+}
+
+func (r *Replayer) execute(ctx context.Context, rq *protocol.RuntimeExecuteTxBatchRequest) (*protocol.RuntimeExecuteTxBatchResponse, error) {
+	rt, err := r.cfg.Provisioner.NewRuntime(ctx, host.Config{
+		RuntimeID:      r.cfg.RuntimeID,
+		Path:           r.cfg.RuntimePath,
+		MessageHandler: newHostHandler(r.cfg.Consensus, r.cfg.Storage),
+		LocalConfig:    r.cfg.LocalConfig,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("replay: failed to provision runtime: %w", err)
+	}
+
+	evCh, sub, err := rt.WatchEvents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("replay: failed to watch runtime events: %w", err)
+	}
+	defer sub.Close()
+
+	if err = rt.Start(); err != nil {
+		return nil, fmt.Errorf("replay: failed to start runtime: %w", err)
+	}
+	defer rt.Stop()
+
+	// Wait for the runtime to start.
+WaitLoop:
+	for {
+		select {
+		case ev := <-evCh:
+			switch {
+			case ev.Started != nil:
+				r.logger.Info("runtime started",
+					"version", ev.Started.Version,
+				)
+				break WaitLoop
+			case ev.FailedToStart != nil:
+				return nil, fmt.Errorf("replay: runtime failed to start: %w", ev.FailedToStart.Error)
+			default:
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	rsp, err := rt.Call(ctx, &protocol.Body{RuntimeExecuteTxBatchRequest: rq})
+	if err != nil {
+		return nil, fmt.Errorf("replay: failed to execute batch: %w", err)
+	}
+	if rsp.RuntimeExecuteTxBatchResponse == nil {
+		return nil, fmt.Errorf("replay: malformed runtime response")
+	}
+	return rsp.RuntimeExecuteTxBatchResponse, nil
+}
+
+// computeInputRoot computes the I/O root containing only the batch inputs, the same way as the
+// transaction scheduler does when proposing a batch.
+func computeInputRoot(ctx context.Context, parent *block.Block, inputs transaction.RawBatch) (hash.Hash, error) {
+	emptyRoot := storage.Root{
+		Namespace: parent.Header.Namespace,
+		Version:   parent.Header.Round + 1,
+		Type:      storage.RootTypeIO,
+	}
+	emptyRoot.Hash.Empty()
+
+	tree := transaction.NewTree(nil, emptyRoot)
+	defer tree.Close()
+
+	for idx, tx := range inputs {
+		if err := tree.AddTransaction(ctx, transaction.Transaction{Input: tx, BatchOrder: uint32(idx)}, nil); err != nil {
+			return hash.Hash{}, fmt.Errorf("replay: failed to create I/O tree: %w", err)
+		}
+	}
+
+	_, ioRoot, err := tree.Commit(ctx)
+	if err != nil {
+		return hash.Hash{}, fmt.Errorf("replay: failed to create I/O tree: %w", err)
+	}
+	return ioRoot, nil
+}
+
+func diffHeaders(expected *block.Header, computed *commitment.ComputeResultsHeader) []Mismatch {
+	var mismatches []Mismatch
+	check := func(field string, exp hash.Hash, cmp *hash.Hash) {
+		if cmp == nil || !exp.Equal(cmp) {
+			mismatches = append(mismatches, Mismatch{
+				Field:    field,
+				Expected: exp,
+				Computed: cmp,
+			})
+		}
+	}
+
+	prevHash := computed.PreviousHash
+	check("previous_hash", expected.PreviousHash, &prevHash)
+	check("io_root", expected.IORoot, computed.IORoot)
+	check("state_root", expected.StateRoot, computed.StateRoot)
+	check("messages_hash", expected.MessagesHash, computed.MessagesHash)
+
+	return mismatches
+}
+
+// New creates a new runtime round replayer.
+func New(cfg Config) (*Replayer, error) {
+	switch {
+	case cfg.Provisioner == nil:
+		return nil, fmt.Errorf("replay: no runtime provisioner configured")
+	case cfg.Consensus == nil:
+		return nil, fmt.Errorf("replay: no consensus backend configured")
+	case cfg.Runtime == nil:
+		return nil, fmt.Errorf("replay: no runtime client configured")
+	case cfg.Storage == nil:
+		return nil, fmt.Errorf("replay: no storage backend configured")
+	}
+
+	return &Replayer{
+		cfg:    cfg,
+		logger: logging.GetLogger("runtime/replay").With("runtime_id", cfg.RuntimeID),
+	}, nil
+}
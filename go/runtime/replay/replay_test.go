@@ -0,0 +1,46 @@
+package replay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+)
+
+func TestDiffHeaders(t *testing.T) {
+	require := require.New(t)
+
+	var expected block.Header
+	expected.Round = 2
+	expected.PreviousHash.FromBytes([]byte("previous"))
+	expected.IORoot.FromBytes([]byte("io"))
+	expected.StateRoot.FromBytes([]byte("state"))
+	expected.MessagesHash.Empty()
+
+	ioRoot := expected.IORoot
+	stateRoot := expected.StateRoot
+	msgsHash := expected.MessagesHash
+	computed := commitment.ComputeResultsHeader{
+		Round:        expected.Round,
+		PreviousHash: expected.PreviousHash,
+		IORoot:       &ioRoot,
+		StateRoot:    &stateRoot,
+		MessagesHash: &msgsHash,
+	}
+	require.Empty(diffHeaders(&expected, &computed), "identical headers should not differ")
+
+	var badStateRoot hash.Hash
+	badStateRoot.FromBytes([]byte("bad state"))
+	computed.StateRoot = &badStateRoot
+	computed.MessagesHash = nil
+
+	mismatches := diffHeaders(&expected, &computed)
+	require.Len(mismatches, 2, "state root and messages hash should differ")
+	require.Equal("state_root", mismatches[0].Field)
+	require.EqualValues(&badStateRoot, mismatches[0].Computed)
+	require.Equal("messages_hash", mismatches[1].Field)
+	require.Nil(mismatches[1].Computed)
+}
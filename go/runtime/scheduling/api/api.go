@@ -2,6 +2,8 @@
 package api
 
 import (
+	"time"
+
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
 )
@@ -20,6 +22,13 @@ type Scheduler interface {
 	// GetBatch returns a batch of scheduled transactions (if any is available).
 	GetBatch(force bool) []*transaction.CheckedTransaction
 
+	// GetTransactions returns all unscheduled transactions in scheduling order.
+	GetTransactions() []*transaction.CheckedTransaction
+
+	// RemoveExpired removes and returns all unscheduled transactions that have expired at the
+	// given round and time.
+	RemoveExpired(round uint64, now time.Time) []*transaction.CheckedTransaction
+
 	// UnscheduledSize returns number of unscheduled items.
 	UnscheduledSize() uint64
 
@@ -29,6 +38,12 @@ type Scheduler interface {
 	// UpdateParameters updates the scheduling parameters.
 	UpdateParameters(algo string, weightLimits map[transaction.Weight]uint64) error
 
+	// UpdateMaxPoolSize updates the maximum transaction pool size.
+	//
+	// In case the pool currently holds more transactions, no new transactions are accepted until
+	// enough transactions have been removed.
+	UpdateMaxPoolSize(maxPoolSize uint64) error
+
 	// Clear clears the transaction queue.
 	Clear()
 }
@@ -5,15 +5,27 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/runtime/scheduling/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/scheduling/simple"
+	txpool "github.com/oasisprotocol/oasis-core/go/runtime/scheduling/simple/txpool/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/scheduling/simple/txpool/priorityqueue"
 	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
 )
 
 // New creates a new scheduler.
-func New(maxTxPoolSize uint64, algo string, weightLimits map[transaction.Weight]uint64) (api.Scheduler, error) {
+//
+// Transactions of equal priority are ordered according to the given tie-breaking rule and
+// unscheduled transactions expire according to the given expiry. Local transactions are queued
+// in a separate lane bounded by maxLocalTxPoolSize (zero disables the local lane).
+func New(
+	maxTxPoolSize uint64,
+	maxLocalTxPoolSize uint64,
+	tieBreak txpool.TieBreak,
+	expiry txpool.Expiry,
+	algo string,
+	weightLimits map[transaction.Weight]uint64,
+) (api.Scheduler, error) {
 	switch algo {
 	case simple.Name:
-		return simple.New(priorityqueue.Name, maxTxPoolSize, algo, weightLimits)
+		return simple.New(priorityqueue.Name, maxTxPoolSize, maxLocalTxPoolSize, tieBreak, expiry, algo, weightLimits)
 	default:
 		return nil, fmt.Errorf("invalid transaction scheduler algorithm: %s", algo)
 	}
@@ -0,0 +1,199 @@
+// Package journal implements a persistent journal of checked transactions which allows the
+// transaction pool to survive node restarts.
+package journal
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/dgraph-io/badger/v3/options"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	cmnBadger "github.com/oasisprotocol/oasis-core/go/common/badger"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/keyformat"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+const (
+	// DbFilename is the filename of the transaction pool journal database.
+	DbFilename = "txpool.db"
+
+	dbVersion = 1
+)
+
+var (
+	// metadataKeyFmt is the metadata key format.
+	//
+	// Value is CBOR-serialized dbMetadata.
+	metadataKeyFmt = keyformat.New(0x01)
+	// txKeyFmt is the transaction key format.
+	//
+	// Value is the raw transaction.
+	txKeyFmt = keyformat.New(0x02, &hash.Hash{})
+)
+
+type dbMetadata struct {
+	// RuntimeID is the runtime ID this database is for.
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// Version is the database schema version.
+	Version uint64 `json:"version"`
+}
+
+// Journal is a persistent journal of checked transactions.
+type Journal struct {
+	logger *logging.Logger
+
+	db *badger.DB
+	gc *cmnBadger.GCWorker
+}
+
+// Add journals the given raw transactions.
+func (j *Journal) Add(txs [][]byte) error {
+	if len(txs) == 0 {
+		return nil
+	}
+
+	wb := j.db.NewWriteBatch()
+	defer wb.Cancel()
+
+	for _, tx := range txs {
+		txHash := hash.NewFromBytes(tx)
+		if err := wb.Set(txKeyFmt.Encode(&txHash), tx); err != nil {
+			return fmt.Errorf("runtime/scheduling/journal: failed to add transaction: %w", err)
+		}
+	}
+	if err := wb.Flush(); err != nil {
+		return fmt.Errorf("runtime/scheduling/journal: failed to add transactions: %w", err)
+	}
+	return nil
+}
+
+// Remove removes the transactions with the given hashes from the journal. Transactions that are
+// not journaled are ignored.
+func (j *Journal) Remove(hashes []hash.Hash) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	wb := j.db.NewWriteBatch()
+	defer wb.Cancel()
+
+	for i := range hashes {
+		if err := wb.Delete(txKeyFmt.Encode(&hashes[i])); err != nil {
+			return fmt.Errorf("runtime/scheduling/journal: failed to remove transaction: %w", err)
+		}
+	}
+	if err := wb.Flush(); err != nil {
+		return fmt.Errorf("runtime/scheduling/journal: failed to remove transactions: %w", err)
+	}
+	return nil
+}
+
+// Clear removes all transactions from the journal.
+func (j *Journal) Clear() error {
+	if err := j.db.DropPrefix(txKeyFmt.Encode()); err != nil {
+		return fmt.Errorf("runtime/scheduling/journal: failed to clear transactions: %w", err)
+	}
+	return nil
+}
+
+// Load returns all journaled raw transactions.
+func (j *Journal) Load() ([][]byte, error) {
+	var txs [][]byte
+	err := j.db.View(func(tx *badger.Txn) error {
+		it := tx.NewIterator(badger.IteratorOptions{Prefix: txKeyFmt.Encode()})
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			raw, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			txs = append(txs, raw)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("runtime/scheduling/journal: failed to load transactions: %w", err)
+	}
+	return txs, nil
+}
+
+// Close closes the journal.
+func (j *Journal) Close() {
+	j.gc.Close()
+	j.db.Close()
+}
+
+func (j *Journal) ensureMetadata(runtimeID common.Namespace) error {
+	return j.db.Update(func(tx *badger.Txn) error {
+		item, err := tx.Get(metadataKeyFmt.Encode())
+		switch err {
+		case nil:
+		case badger.ErrKeyNotFound:
+			// Create new metadata section.
+			meta := dbMetadata{
+				RuntimeID: runtimeID,
+				Version:   dbVersion,
+			}
+			return tx.Set(metadataKeyFmt.Encode(), cbor.Marshal(meta))
+		default:
+			return err
+		}
+
+		var meta dbMetadata
+		if err = item.Value(func(val []byte) error {
+			return cbor.Unmarshal(val, &meta)
+		}); err != nil {
+			return err
+		}
+
+		// Verify metadata section.
+		if meta.Version != dbVersion {
+			return fmt.Errorf("runtime/scheduling/journal: unsupported database version (expected: %d got: %d)",
+				dbVersion,
+				meta.Version,
+			)
+		}
+		if !meta.RuntimeID.Equal(&runtimeID) {
+			return fmt.Errorf("runtime/scheduling/journal: database for different runtime (expected: %s got: %s)",
+				runtimeID,
+				meta.RuntimeID,
+			)
+		}
+		return nil
+	})
+}
+
+// New opens (or creates) the transaction pool journal of the given runtime in the given
+// directory.
+func New(dataDir string, runtimeID common.Namespace) (*Journal, error) {
+	fn := filepath.Join(dataDir, DbFilename)
+	logger := logging.GetLogger("runtime/scheduling/journal").With("path", fn)
+
+	opts := badger.DefaultOptions(fn)
+	opts = opts.WithLogger(cmnBadger.NewLogAdapter(logger))
+	opts = opts.WithSyncWrites(true)
+	opts = opts.WithCompression(options.None)
+
+	db, err := cmnBadger.Open(opts)
+	if err != nil {
+		return nil, fmt.Errorf("runtime/scheduling/journal: failed to open database: %w", err)
+	}
+
+	j := &Journal{
+		logger: logger,
+		db:     db,
+		gc:     cmnBadger.NewGCWorker(logger, db),
+	}
+
+	if err = j.ensureMetadata(runtimeID); err != nil {
+		j.Close()
+		return nil, err
+	}
+
+	return j, nil
+}
@@ -0,0 +1,52 @@
+package journal
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+)
+
+func TestJournal(t *testing.T) {
+	require := require.New(t)
+
+	dataDir, err := ioutil.TempDir("", "oasis-runtime-scheduling-journal-test_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dataDir)
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("journal test ns 1"), 0)
+	runtimeID2 := common.NewTestNamespaceFromSeed([]byte("journal test ns 2"), 0)
+
+	j, err := New(dataDir, runtimeID)
+	require.NoError(err, "New")
+
+	txs, err := j.Load()
+	require.NoError(err, "Load")
+	require.Empty(txs, "journal should be empty")
+
+	tx1, tx2, tx3 := []byte("tx 1"), []byte("tx 2"), []byte("tx 3")
+	require.NoError(j.Add([][]byte{tx1, tx2, tx3}), "Add")
+	require.NoError(j.Add([][]byte{tx1}), "Add duplicate")
+	require.NoError(j.Remove([]hash.Hash{hash.NewFromBytes(tx2), hash.NewFromBytes([]byte("unknown"))}), "Remove")
+
+	// Reopen the journal.
+	j.Close()
+	_, err = New(dataDir, runtimeID2)
+	require.Error(err, "New should fail for a different runtime")
+	j, err = New(dataDir, runtimeID)
+	require.NoError(err, "New")
+	defer j.Close()
+
+	txs, err = j.Load()
+	require.NoError(err, "Load")
+	require.ElementsMatch([][]byte{tx1, tx3}, txs)
+
+	require.NoError(j.Clear(), "Clear")
+	txs, err = j.Load()
+	require.NoError(err, "Load")
+	require.Empty(txs, "journal should be empty after clear")
+}
@@ -15,6 +15,7 @@ import (
 type pair struct {
 	Key   hash.Hash
 	Value []byte
+	Local bool
 
 	element *list.Element
 }
@@ -26,8 +27,12 @@ type OrderedMap struct {
 	transactions map[hash.Hash]*pair
 	queue        *list.List
 
-	maxTxPoolSize uint64
-	maxBatchSize  uint64
+	maxTxPoolSize      uint64
+	maxLocalTxPoolSize uint64
+	maxBatchSize       uint64
+
+	// numLocal is the number of queued local transactions.
+	numLocal uint64
 }
 
 // Add adds transaction into the queue.
@@ -38,7 +43,31 @@ func (q *OrderedMap) Add(tx []byte) error {
 	defer q.Unlock()
 
 	// Check if there is room in the queue.
-	if uint64(q.queue.Len()) >= q.maxTxPoolSize {
+	if q.remoteSizeLocked() >= q.maxTxPoolSize {
+		return api.ErrFull
+	}
+
+	if err := q.checkTxLocked(tx, txHash); err != nil {
+		return err
+	}
+
+	q.addTxLocked(tx, txHash, false)
+
+	return nil
+}
+
+// AddLocal adds a transaction submitted by a local client into the queue.
+//
+// Local transactions are bounded separately so that remote transactions cannot prevent them
+// from being queued.
+func (q *OrderedMap) AddLocal(tx []byte) error {
+	txHash := hash.NewFromBytes(tx)
+
+	q.Lock()
+	defer q.Unlock()
+
+	// Check if there is room for local transactions in the queue.
+	if q.numLocal >= q.maxLocalTxPoolSize {
 		return api.ErrFull
 	}
 
@@ -46,7 +75,7 @@ func (q *OrderedMap) Add(tx []byte) error {
 		return err
 	}
 
-	q.addTxLocked(tx, txHash)
+	q.addTxLocked(tx, txHash, true)
 
 	return nil
 }
@@ -71,13 +100,13 @@ func (q *OrderedMap) AddBatch(batch [][]byte) error {
 		}
 
 		// Check if there is room in the queue.
-		if uint64(q.queue.Len()) >= q.maxTxPoolSize {
+		if q.remoteSizeLocked() >= q.maxTxPoolSize {
 			errs = multierror.Append(errs, fmt.Errorf("failed inserting tx: %d, error: %w", i, api.ErrFull))
 			return errs
 		}
 
 		// Add the tx if checks passed.
-		q.addTxLocked(tx, txHashes[i])
+		q.addTxLocked(tx, txHashes[i], false)
 	}
 
 	if len(q.transactions) != q.queue.Len() {
@@ -122,6 +151,9 @@ func (q *OrderedMap) RemoveBatch(batch [][]byte) {
 		if pair, ok := q.transactions[txHash]; ok {
 			q.queue.Remove(pair.element)
 			delete(q.transactions, pair.Key)
+			if pair.Local {
+				q.numLocal--
+			}
 		}
 	}
 	if len(q.transactions) != q.queue.Len() {
@@ -137,6 +169,15 @@ func (q *OrderedMap) IsQueued(txHash hash.Hash) bool {
 	return q.isQueuedLocked(txHash)
 }
 
+// IsLocal checks if a queued transaction was submitted by a local client.
+func (q *OrderedMap) IsLocal(txHash hash.Hash) bool {
+	q.Lock()
+	defer q.Unlock()
+
+	pair, ok := q.transactions[txHash]
+	return ok && pair.Local
+}
+
 // Size returns size of the queue.
 func (q *OrderedMap) Size() uint64 {
 	q.Lock()
@@ -145,6 +186,22 @@ func (q *OrderedMap) Size() uint64 {
 	return uint64(q.queue.Len())
 }
 
+// SetMaxPoolSize sets the maximum size of the queue.
+func (q *OrderedMap) SetMaxPoolSize(maxPoolSize uint64) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.maxTxPoolSize = maxPoolSize
+}
+
+// SetMaxLocalPoolSize sets the maximum number of queued local transactions.
+func (q *OrderedMap) SetMaxLocalPoolSize(maxLocalPoolSize uint64) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.maxLocalTxPoolSize = maxLocalPoolSize
+}
+
 // Clear empties the queue.
 func (q *OrderedMap) Clear() {
 	q.Lock()
@@ -152,6 +209,12 @@ func (q *OrderedMap) Clear() {
 
 	q.queue = list.New()
 	q.transactions = make(map[hash.Hash]*pair)
+	q.numLocal = 0
+}
+
+// NOTE: Assumes lock is held.
+func (q *OrderedMap) remoteSizeLocked() uint64 {
+	return uint64(q.queue.Len()) - q.numLocal
 }
 
 // NOTE: Assumes lock is held.
@@ -170,7 +233,7 @@ func (q *OrderedMap) checkTxLocked(tx []byte, txHash hash.Hash) error {
 }
 
 // NOTE: Assumes lock is held and that checkTxLocked has been called.
-func (q *OrderedMap) addTxLocked(tx []byte, txHash hash.Hash) {
+func (q *OrderedMap) addTxLocked(tx []byte, txHash hash.Hash, local bool) {
 	// Assuming checkTxLocked has been called before, this can happen if
 	// duplicate transactions are in the same batch -- just ignore them.
 	if _, exists := q.transactions[txHash]; exists {
@@ -179,9 +242,13 @@ func (q *OrderedMap) addTxLocked(tx []byte, txHash hash.Hash) {
 	p := &pair{
 		Key:   txHash,
 		Value: tx,
+		Local: local,
 	}
 	p.element = q.queue.PushFront(p)
 	q.transactions[txHash] = p
+	if local {
+		q.numLocal++
+	}
 }
 
 // New returns a new incoming queue.
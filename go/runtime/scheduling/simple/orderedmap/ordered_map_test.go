@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 )
 
 func TestOrderedMapBasic(t *testing.T) {
@@ -94,3 +96,37 @@ func TestOrderedMapRemoveBatch(t *testing.T) {
 	})
 	require.EqualValues(t, 2, queue.Size(), "Size")
 }
+
+func TestOrderedMapSetMaxPoolSize(t *testing.T) {
+	queue := New(2, 10)
+
+	require.NoError(t, queue.Add([]byte("one")), "Add")
+	require.NoError(t, queue.Add([]byte("two")), "Add")
+	require.Error(t, queue.Add([]byte("three")), "Add error on queue full")
+
+	queue.SetMaxPoolSize(3)
+	require.NoError(t, queue.Add([]byte("three")), "Add")
+	require.EqualValues(t, 3, queue.Size(), "Size")
+
+	queue.SetMaxPoolSize(1)
+	require.Error(t, queue.Add([]byte("four")), "Add error on queue full")
+	require.EqualValues(t, 3, queue.Size(), "Size")
+}
+
+func TestOrderedMapAddLocal(t *testing.T) {
+	queue := New(1, 10)
+	queue.SetMaxLocalPoolSize(1)
+
+	require.NoError(t, queue.Add([]byte("remote")), "Add")
+	require.Error(t, queue.Add([]byte("remote 2")), "Add error on queue full")
+	require.NoError(t, queue.AddLocal([]byte("local")), "AddLocal should not be limited by remote transactions")
+	require.Error(t, queue.AddLocal([]byte("local 2")), "AddLocal error on queue full")
+	require.EqualValues(t, 2, queue.Size(), "Size")
+
+	require.True(t, queue.IsLocal(hash.NewFromBytes([]byte("local"))), "IsLocal")
+	require.False(t, queue.IsLocal(hash.NewFromBytes([]byte("remote"))), "IsLocal")
+
+	queue.RemoveBatch([][]byte{[]byte("local")})
+	require.False(t, queue.IsLocal(hash.NewFromBytes([]byte("local"))), "IsLocal after removal")
+	require.NoError(t, queue.AddLocal([]byte("local 2")), "AddLocal")
+}
@@ -3,6 +3,7 @@ package simple
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
@@ -21,8 +22,12 @@ const (
 type scheduler struct {
 	logger *logging.Logger
 
-	txPool        txpool.TxPool
-	maxTxPoolSize uint64
+	txPool             txpool.TxPool
+	maxTxPoolSize      uint64
+	maxLocalTxPoolSize uint64
+	weightLimits       map[transaction.Weight]uint64
+	tieBreak           txpool.TieBreak
+	expiry             txpool.Expiry
 }
 
 func (s *scheduler) QueueTx(tx *transaction.CheckedTransaction) error {
@@ -49,6 +54,14 @@ func (s *scheduler) GetBatch(force bool) []*transaction.CheckedTransaction {
 	return s.txPool.GetBatch(force)
 }
 
+func (s *scheduler) GetTransactions() []*transaction.CheckedTransaction {
+	return s.txPool.GetTransactions()
+}
+
+func (s *scheduler) RemoveExpired(round uint64, now time.Time) []*transaction.CheckedTransaction {
+	return s.txPool.RemoveExpired(round, now)
+}
+
 func (s *scheduler) UnscheduledSize() uint64 {
 	return s.txPool.Size()
 }
@@ -67,11 +80,29 @@ func (s *scheduler) UpdateParameters(algo string, weightLimits map[transaction.W
 	}
 
 	if err := s.txPool.UpdateConfig(txpool.Config{
-		MaxPoolSize:  s.maxTxPoolSize,
-		WeightLimits: weightLimits,
+		MaxPoolSize:      s.maxTxPoolSize,
+		MaxLocalPoolSize: s.maxLocalTxPoolSize,
+		WeightLimits:     weightLimits,
+		TieBreak:         s.tieBreak,
+		Expiry:           s.expiry,
 	}); err != nil {
 		return fmt.Errorf("error updating parameters: %w", err)
 	}
+	s.weightLimits = weightLimits
+	return nil
+}
+
+func (s *scheduler) UpdateMaxPoolSize(maxPoolSize uint64) error {
+	if err := s.txPool.UpdateConfig(txpool.Config{
+		MaxPoolSize:      maxPoolSize,
+		MaxLocalPoolSize: s.maxLocalTxPoolSize,
+		WeightLimits:     s.weightLimits,
+		TieBreak:         s.tieBreak,
+		Expiry:           s.expiry,
+	}); err != nil {
+		return fmt.Errorf("error updating max pool size: %w", err)
+	}
+	s.maxTxPoolSize = maxPoolSize
 	return nil
 }
 
@@ -80,14 +111,29 @@ func (s *scheduler) Name() string {
 }
 
 // New creates a new simple scheduler.
-func New(txPoolImpl string, maxTxPoolSize uint64, algo string, weightLimits map[transaction.Weight]uint64) (api.Scheduler, error) {
+//
+// Transactions of equal priority are ordered according to the given tie-breaking rule and
+// unscheduled transactions expire according to the given expiry. Local transactions are queued
+// in a separate lane bounded by maxLocalTxPoolSize (zero disables the local lane).
+func New(
+	txPoolImpl string,
+	maxTxPoolSize uint64,
+	maxLocalTxPoolSize uint64,
+	tieBreak txpool.TieBreak,
+	expiry txpool.Expiry,
+	algo string,
+	weightLimits map[transaction.Weight]uint64,
+) (api.Scheduler, error) {
 	if algo != Name {
 		return nil, fmt.Errorf("unexpected transaction scheduling algorithm: %s", algo)
 	}
 
 	poolCfg := txpool.Config{
-		MaxPoolSize:  maxTxPoolSize,
-		WeightLimits: weightLimits,
+		MaxPoolSize:      maxTxPoolSize,
+		MaxLocalPoolSize: maxLocalTxPoolSize,
+		WeightLimits:     weightLimits,
+		TieBreak:         tieBreak,
+		Expiry:           expiry,
 	}
 	var pool txpool.TxPool
 	switch txPoolImpl {
@@ -98,9 +144,13 @@ func New(txPoolImpl string, maxTxPoolSize uint64, algo string, weightLimits map[
 	}
 
 	scheduler := &scheduler{
-		maxTxPoolSize: maxTxPoolSize,
-		txPool:        pool,
-		logger:        logging.GetLogger("runtime/scheduling").With("scheduler", "simple"),
+		maxTxPoolSize:      maxTxPoolSize,
+		maxLocalTxPoolSize: maxLocalTxPoolSize,
+		weightLimits:       weightLimits,
+		tieBreak:           tieBreak,
+		expiry:             expiry,
+		txPool:             pool,
+		logger:             logging.GetLogger("runtime/scheduling").With("scheduler", "simple"),
 	}
 
 	return scheduler, nil
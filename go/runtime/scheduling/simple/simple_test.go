@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	txpool "github.com/oasisprotocol/oasis-core/go/runtime/scheduling/simple/txpool/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/scheduling/simple/txpool/priorityqueue"
 	"github.com/oasisprotocol/oasis-core/go/runtime/scheduling/tests"
 	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
@@ -16,7 +17,7 @@ func TestSimpleSchedulerPriorityQueue(t *testing.T) {
 		transaction.WeightSizeBytes: 16 * 1024 * 1024,
 	}
 
-	algo, err := New(priorityqueue.Name, 100, Name, weightLimits)
+	algo, err := New(priorityqueue.Name, 100, 0, txpool.TieBreakHash, txpool.Expiry{}, Name, weightLimits)
 	require.NoError(t, err, "New()")
 	tests.SchedulerImplementationTests(t, algo)
 }
@@ -27,7 +28,7 @@ func BenchmarkSimpleSchedulerPriorityQueue(b *testing.B) {
 		transaction.WeightSizeBytes: 16 * 1024 * 1024,
 	}
 
-	algo, err := New(priorityqueue.Name, 1000000, Name, weightLimits)
+	algo, err := New(priorityqueue.Name, 1000000, 0, txpool.TieBreakHash, txpool.Expiry{}, Name, weightLimits)
 	require.NoError(b, err, "New()")
 	tests.SchedulerImplementationBenchmarks(b, algo)
 }
@@ -3,6 +3,7 @@ package api
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
@@ -13,13 +14,93 @@ var (
 	ErrCallAlreadyExists = fmt.Errorf("call already exists in pool")
 	ErrFull              = fmt.Errorf("pool is full")
 	ErrCallTooLarge      = p2pError.Permanent(fmt.Errorf("call too large"))
+	// ErrReplacementUnderpriced is the error returned when a transaction would replace a queued
+	// transaction of the same sender and sender sequence number without having a higher priority.
+	ErrReplacementUnderpriced = p2pError.Permanent(fmt.Errorf("replacement transaction underpriced"))
+	// ErrLocalReplacement is the error returned when a remote transaction would replace a queued
+	// local transaction of the same sender and sender sequence number.
+	ErrLocalReplacement = p2pError.Permanent(fmt.Errorf("remote transaction cannot replace local transaction"))
 )
 
+// TieBreak is the ordering of transactions with equal priority.
+type TieBreak uint8
+
+const (
+	// TieBreakHash orders transactions with equal priority by their hash.
+	TieBreakHash TieBreak = 0
+	// TieBreakArrival orders transactions with equal priority by their arrival time, scheduling
+	// transactions that were added to the pool first.
+	TieBreakArrival TieBreak = 1
+
+	tbHash    = "hash"
+	tbArrival = "arrival"
+)
+
+// String returns a string representation of a tie-breaking rule.
+func (tb TieBreak) String() string {
+	text, err := tb.MarshalText()
+	if err != nil {
+		return "[unsupported tie-breaking rule]"
+	}
+	return string(text)
+}
+
+// MarshalText encodes a tie-breaking rule into text form.
+func (tb TieBreak) MarshalText() ([]byte, error) {
+	switch tb {
+	case TieBreakHash:
+		return []byte(tbHash), nil
+	case TieBreakArrival:
+		return []byte(tbArrival), nil
+	default:
+		return nil, fmt.Errorf("unsupported tie-breaking rule: %d", tb)
+	}
+}
+
+// UnmarshalText decodes a text slice into a tie-breaking rule.
+func (tb *TieBreak) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case tbHash:
+		*tb = TieBreakHash
+	case tbArrival:
+		*tb = TieBreakArrival
+	default:
+		return fmt.Errorf("unsupported tie-breaking rule: '%s'", string(text))
+	}
+	return nil
+}
+
+// Expiry is the configuration of transaction expiry.
+type Expiry struct {
+	// MaxAgeRounds is the number of rounds after which unscheduled transactions expire. Zero
+	// means that transactions do not expire based on the number of rounds.
+	MaxAgeRounds uint64
+	// MaxAge is the duration after which unscheduled transactions expire. Zero means that
+	// transactions do not expire based on the time spent in the pool.
+	MaxAge time.Duration
+}
+
+// Enabled returns true iff transactions can expire.
+func (e Expiry) Enabled() bool {
+	return e.MaxAgeRounds > 0 || e.MaxAge > 0
+}
+
 // Config is a transaction pool configuration.
 type Config struct {
 	MaxPoolSize uint64
 
+	// MaxLocalPoolSize is the maximum number of queued local transactions. Local transactions
+	// do not count towards MaxPoolSize. Zero means that local transactions are treated the same
+	// as remote transactions.
+	MaxLocalPoolSize uint64
+
 	WeightLimits map[transaction.Weight]uint64
+
+	// TieBreak is the ordering of transactions with equal priority.
+	TieBreak TieBreak
+
+	// Expiry is the configuration of transaction expiry.
+	Expiry Expiry
 }
 
 // TxPool is the transaction pool interface.
@@ -28,14 +109,32 @@ type TxPool interface {
 	Name() string
 
 	// Add adds a single transaction into the transaction pool.
+	//
+	// In case the transaction specifies a sender and a transaction with the same sender and sender
+	// sequence number is already queued, the queued transaction is replaced if the new transaction
+	// has a higher priority. Otherwise ErrReplacementUnderpriced is returned. Local transactions
+	// are never replaced by remote transactions.
 	Add(tx *transaction.CheckedTransaction) error
 
 	// GetBatch gets a transaction batch from the transaction pool.
+	//
+	// Transactions are returned in scheduling order, local transactions first and then highest
+	// priority first, with transactions of equal priority ordered according to the configured
+	// tie-breaking rule.
 	GetBatch(force bool) []*transaction.CheckedTransaction
 
 	// RemoveBatch removes a batch from the transaction pool.
 	RemoveBatch(batch []hash.Hash) error
 
+	// GetTransactions returns all transactions in the transaction pool in scheduling order.
+	GetTransactions() []*transaction.CheckedTransaction
+
+	// RemoveExpired removes and returns all transactions that have expired at the given round
+	// and time according to the configured expiry.
+	//
+	// The round of a transaction is the last round passed to RemoveExpired before it was added.
+	RemoveExpired(round uint64, now time.Time) []*transaction.CheckedTransaction
+
 	// IsQueued returns whether a transaction is in the queue already.
 	IsQueued(txHash hash.Hash) bool
 
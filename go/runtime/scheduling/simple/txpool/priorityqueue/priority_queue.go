@@ -4,7 +4,9 @@ package priorityqueue
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/google/btree"
 
@@ -18,26 +20,62 @@ const Name = "priority-queue"
 
 type item struct {
 	tx *transaction.CheckedTransaction
+
+	// seq is the arrival sequence number of the transaction.
+	seq uint64
+	// round is the round at which the transaction was added.
+	round uint64
+	// added is the time at which the transaction was added.
+	added time.Time
+	// tieBreak is the ordering of transactions with equal priority.
+	tieBreak api.TieBreak
+	// local is true iff the transaction is in the local lane.
+	local bool
 }
 
 func (i item) Less(other btree.Item) bool {
 	i2 := other.(*item)
+	// Local transactions are always scheduled first.
+	if i.local != i2.local {
+		return i.local
+	}
 	if p1, p2 := i.tx.Priority(), i2.tx.Priority(); p1 != p2 {
 		return p1 > p2
 	}
-	// If transactions have same priority, sort arbitrary.
+	// If transactions have same priority, sort according to the tie-breaking rule.
+	if i.tieBreak == api.TieBreakArrival && i.seq != i2.seq {
+		return i.seq < i2.seq
+	}
 	h1 := i.tx.Hash()
 	h2 := i2.tx.Hash()
 	return bytes.Compare(h1[:], h2[:]) < 0
 }
 
+// senderKey identifies a transaction by its sender and sender sequence number.
+type senderKey struct {
+	sender string
+	seq    uint64
+}
+
 type priorityQueue struct {
 	sync.Mutex
 
 	priorityIndex *btree.BTree
 	transactions  map[hash.Hash]*item
+	senderIndex   map[senderKey]*item
+
+	maxTxPoolSize      uint64
+	maxLocalTxPoolSize uint64
+	tieBreak           api.TieBreak
+	expiry             api.Expiry
+	nextSeq            uint64
 
-	maxTxPoolSize uint64
+	// numLocal is the number of transactions in the local lane.
+	numLocal uint64
+
+	// round is the last round seen by RemoveExpired.
+	round    uint64
+	hasRound bool
 
 	poolWeights  map[transaction.Weight]uint64
 	weightLimits map[transaction.Weight]uint64
@@ -53,21 +91,59 @@ func (q *priorityQueue) Add(tx *transaction.CheckedTransaction) error {
 	q.Lock()
 	defer q.Unlock()
 
-	// Check if there is room in the queue.
-	if q.poolWeights[transaction.WeightCount] >= q.maxTxPoolSize {
-		return api.ErrFull
+	// Local transactions get a separate lane, if enabled.
+	local := tx.Meta().Local && q.maxLocalTxPoolSize > 0
+
+	// Check if the transaction replaces a queued transaction of the same sender.
+	var replaced *item
+	if tx.Sender() != "" {
+		replaced = q.senderIndex[senderKey{tx.Sender(), tx.SenderSeq()}]
+	}
+
+	// Check if there is room in the lane (replacements within the same lane don't need any).
+	if replaced == nil || replaced.local != local {
+		if local && q.numLocal >= q.maxLocalTxPoolSize {
+			return api.ErrFull
+		}
+		if !local && q.poolWeights[transaction.WeightCount]-q.numLocal >= q.maxTxPoolSize {
+			return api.ErrFull
+		}
 	}
 
 	if err := q.checkTxLocked(tx); err != nil {
 		return err
 	}
 
-	item := &item{tx: tx}
+	if replaced != nil {
+		if replaced.local && !local {
+			return api.ErrLocalReplacement
+		}
+		if tx.Priority() <= replaced.tx.Priority() {
+			return api.ErrReplacementUnderpriced
+		}
+		q.removeLocked(replaced)
+	}
+
+	item := &item{
+		tx:       tx,
+		seq:      q.nextSeq,
+		round:    q.round,
+		added:    time.Now(),
+		tieBreak: q.tieBreak,
+		local:    local,
+	}
+	q.nextSeq++
 	q.priorityIndex.ReplaceOrInsert(item)
 	q.transactions[tx.Hash()] = item
+	if tx.Sender() != "" {
+		q.senderIndex[senderKey{tx.Sender(), tx.SenderSeq()}] = item
+	}
 	for k, v := range tx.Weights() {
 		q.poolWeights[k] += v
 	}
+	if local {
+		q.numLocal++
+	}
 
 	if mlen, qlen := len(q.transactions), q.priorityIndex.Len(); mlen != qlen {
 		panic(fmt.Errorf("inconsistent sizes of the underlying index (%v) and map (%v) after Add", mlen, qlen))
@@ -139,11 +215,7 @@ func (q *priorityQueue) GetBatch(force bool) []*transaction.CheckedTransaction {
 	// This can happen if weight limits changed after the transaction was
 	// already set to be scheduled.
 	for _, item := range toRemove {
-		delete(q.transactions, item.tx.Hash())
-		q.priorityIndex.Delete(item)
-		for k, v := range item.tx.Weights() {
-			q.poolWeights[k] -= v
-		}
+		q.removeLocked(item)
 	}
 
 	return batch
@@ -156,11 +228,7 @@ func (q *priorityQueue) RemoveBatch(batch []hash.Hash) error {
 
 	for _, txHash := range batch {
 		if item, ok := q.transactions[txHash]; ok {
-			q.priorityIndex.Delete(item)
-			delete(q.transactions, txHash)
-			for k, v := range item.tx.Weights() {
-				q.poolWeights[k] -= v
-			}
+			q.removeLocked(item)
 		}
 	}
 	if mlen, qlen := len(q.transactions), q.priorityIndex.Len(); mlen != qlen {
@@ -173,6 +241,58 @@ func (q *priorityQueue) RemoveBatch(batch []hash.Hash) error {
 	return nil
 }
 
+// Implements api.TxPool.
+func (q *priorityQueue) GetTransactions() []*transaction.CheckedTransaction {
+	q.Lock()
+	defer q.Unlock()
+
+	txs := make([]*transaction.CheckedTransaction, 0, q.priorityIndex.Len())
+	q.priorityIndex.Ascend(func(i btree.Item) bool {
+		txs = append(txs, i.(*item).tx)
+		return true
+	})
+	return txs
+}
+
+// Implements api.TxPool.
+func (q *priorityQueue) RemoveExpired(round uint64, now time.Time) []*transaction.CheckedTransaction {
+	q.Lock()
+	defer q.Unlock()
+
+	if !q.hasRound {
+		// The round at which existing transactions were added is unknown, start counting now.
+		for _, item := range q.transactions {
+			item.round = round
+		}
+		q.hasRound = true
+	}
+	q.round = round
+
+	if !q.expiry.Enabled() {
+		return nil
+	}
+
+	var expired []*item
+	for _, item := range q.transactions {
+		roundsExpired := q.expiry.MaxAgeRounds > 0 && round >= item.round+q.expiry.MaxAgeRounds
+		timeExpired := q.expiry.MaxAge > 0 && now.Sub(item.added) >= q.expiry.MaxAge
+		if roundsExpired || timeExpired {
+			expired = append(expired, item)
+		}
+	}
+	// Return expired transactions in arrival order.
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].seq < expired[j].seq
+	})
+
+	txs := make([]*transaction.CheckedTransaction, 0, len(expired))
+	for _, item := range expired {
+		q.removeLocked(item)
+		txs = append(txs, item.tx)
+	}
+	return txs
+}
+
 // Implements api.TxPool.
 func (q *priorityQueue) IsQueued(txHash hash.Hash) bool {
 	q.Lock()
@@ -195,9 +315,23 @@ func (q *priorityQueue) UpdateConfig(cfg api.Config) error {
 	defer q.Unlock()
 
 	q.maxTxPoolSize = cfg.MaxPoolSize
+	q.maxLocalTxPoolSize = cfg.MaxLocalPoolSize
 	q.weightLimits = cfg.WeightLimits
+	q.expiry = cfg.Expiry
 
 	// Any transaction not within the new limits will get removed during GetBatch iteration.
+	// Queued transactions remain in their lanes even if the local lane gets disabled.
+
+	if cfg.TieBreak != q.tieBreak {
+		q.tieBreak = cfg.TieBreak
+
+		// The ordering changed, rebuild the index.
+		q.priorityIndex.Clear(false)
+		for _, item := range q.transactions {
+			item.tieBreak = cfg.TieBreak
+			q.priorityIndex.ReplaceOrInsert(item)
+		}
+	}
 
 	return nil
 }
@@ -209,7 +343,9 @@ func (q *priorityQueue) Clear() {
 
 	q.priorityIndex.Clear(true)
 	q.transactions = make(map[hash.Hash]*item)
+	q.senderIndex = make(map[senderKey]*item)
 	q.poolWeights = make(map[transaction.Weight]uint64)
+	q.numLocal = 0
 }
 
 // NOTE: Assumes lock is held.
@@ -229,6 +365,24 @@ func (q *priorityQueue) checkTxLocked(tx *transaction.CheckedTransaction) error
 	return nil
 }
 
+// NOTE: Assumes lock is held.
+func (q *priorityQueue) removeLocked(item *item) {
+	q.priorityIndex.Delete(item)
+	delete(q.transactions, item.tx.Hash())
+	if sender := item.tx.Sender(); sender != "" {
+		key := senderKey{sender, item.tx.SenderSeq()}
+		if q.senderIndex[key] == item {
+			delete(q.senderIndex, key)
+		}
+	}
+	for k, v := range item.tx.Weights() {
+		q.poolWeights[k] -= v
+	}
+	if item.local {
+		q.numLocal--
+	}
+}
+
 // NOTE: Assumes lock is held.
 func (q *priorityQueue) isQueuedLocked(txHash hash.Hash) bool {
 	_, ok := q.transactions[txHash]
@@ -238,10 +392,14 @@ func (q *priorityQueue) isQueuedLocked(txHash hash.Hash) bool {
 // New returns a new TxPool.
 func New(cfg api.Config) api.TxPool {
 	return &priorityQueue{
-		transactions:  make(map[hash.Hash]*item),
-		poolWeights:   make(map[transaction.Weight]uint64),
-		priorityIndex: btree.New(2),
-		maxTxPoolSize: cfg.MaxPoolSize,
-		weightLimits:  cfg.WeightLimits,
+		transactions:       make(map[hash.Hash]*item),
+		senderIndex:        make(map[senderKey]*item),
+		poolWeights:        make(map[transaction.Weight]uint64),
+		priorityIndex:      btree.New(2),
+		maxTxPoolSize:      cfg.MaxPoolSize,
+		maxLocalTxPoolSize: cfg.MaxLocalPoolSize,
+		tieBreak:           cfg.TieBreak,
+		expiry:             cfg.Expiry,
+		weightLimits:       cfg.WeightLimits,
 	}
 }
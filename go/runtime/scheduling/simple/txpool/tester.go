@@ -2,10 +2,13 @@
 package tests
 
 import (
+	"bytes"
 	"crypto"
 	"fmt"
 	"math/rand"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -45,6 +48,22 @@ func TxPoolImplementationTests(
 	t.Run("TestPriority", func(t *testing.T) {
 		testPriority(t, pool)
 	})
+
+	t.Run("TestTieBreak", func(t *testing.T) {
+		testTieBreak(t, pool)
+	})
+
+	t.Run("TestSenderReplacement", func(t *testing.T) {
+		testSenderReplacement(t, pool)
+	})
+
+	t.Run("TestRemoveExpired", func(t *testing.T) {
+		testRemoveExpired(t, pool)
+	})
+
+	t.Run("TestLocalLane", func(t *testing.T) {
+		testLocalLane(t, pool)
+	})
 }
 
 func testBasic(t *testing.T, pool api.TxPool) {
@@ -360,6 +379,244 @@ func testPriority(t *testing.T, pool api.TxPool) {
 		batch,
 		"elements should be returned by priority",
 	)
+	require.EqualValues(t, batch, pool.GetTransactions(), "GetTransactions should return elements by priority")
+
+	err = pool.RemoveBatch([]hash.Hash{txs[0].Hash()})
+	require.NoError(t, err, "RemoveBatch")
+	require.EqualValues(
+		t,
+		[]*transaction.CheckedTransaction{
+			txs[2], // 20
+			txs[1], // 5
+		},
+		pool.GetTransactions(),
+		"GetTransactions should not return removed elements",
+	)
+}
+
+func testTieBreak(t *testing.T, pool api.TxPool) {
+	pool.Clear()
+
+	cfg := api.Config{
+		MaxPoolSize: 50,
+		WeightLimits: map[transaction.Weight]uint64{
+			transaction.WeightCount:     10,
+			transaction.WeightSizeBytes: 1000,
+		},
+		TieBreak: api.TieBreakArrival,
+	}
+	err := pool.UpdateConfig(cfg)
+	require.NoError(t, err, "UpdateConfig")
+
+	var txs []*transaction.CheckedTransaction
+	for i := 0; i < 5; i++ {
+		txs = append(txs, transaction.NewCheckedTransaction(
+			[]byte(fmt.Sprintf("tie break %d", i)),
+			10,
+			nil,
+		))
+	}
+	high := transaction.NewCheckedTransaction([]byte("tie break high"), 20, nil)
+	for _, tx := range txs {
+		require.NoError(t, pool.Add(tx), "Add")
+	}
+	require.NoError(t, pool.Add(high), "Add")
+
+	require.EqualValues(
+		t,
+		append([]*transaction.CheckedTransaction{high}, txs...),
+		pool.GetBatch(true),
+		"transactions of equal priority should be returned in arrival order",
+	)
+
+	// Switching to hash ordering should reorder the pool.
+	cfg.TieBreak = api.TieBreakHash
+	err = pool.UpdateConfig(cfg)
+	require.NoError(t, err, "UpdateConfig")
+
+	byHash := append([]*transaction.CheckedTransaction{}, txs...)
+	sort.Slice(byHash, func(i, j int) bool {
+		hi, hj := byHash[i].Hash(), byHash[j].Hash()
+		return bytes.Compare(hi[:], hj[:]) < 0
+	})
+	require.EqualValues(
+		t,
+		append([]*transaction.CheckedTransaction{high}, byHash...),
+		pool.GetTransactions(),
+		"transactions of equal priority should be returned in hash order",
+	)
+}
+
+func testSenderReplacement(t *testing.T, pool api.TxPool) {
+	pool.Clear()
+
+	err := pool.UpdateConfig(api.Config{
+		MaxPoolSize: 2,
+		WeightLimits: map[transaction.Weight]uint64{
+			transaction.WeightCount:     10,
+			transaction.WeightSizeBytes: 1000,
+		},
+	})
+	require.NoError(t, err, "UpdateConfig")
+
+	sender := []byte("sender")
+	tx1 := transaction.NewCheckedTransactionWithSender([]byte("sender tx 1"), 10, nil, sender, 1)
+	tx2 := transaction.NewCheckedTransactionWithSender([]byte("sender tx 2"), 5, nil, sender, 2)
+	require.NoError(t, pool.Add(tx1), "Add")
+	require.NoError(t, pool.Add(tx2), "Add")
+
+	// Pool is full, transactions that are not replacements should be rejected.
+	err = pool.Add(transaction.NewCheckedTransactionWithSender([]byte("other sender tx"), 100, nil, []byte("other"), 1))
+	require.ErrorIs(t, err, api.ErrFull, "Add should fail on full pool")
+
+	// Duplicates should still be detected.
+	err = pool.Add(tx1)
+	require.ErrorIs(t, err, api.ErrCallAlreadyExists, "Add should fail on duplicates")
+
+	// Replacement with a lower or equal priority should fail.
+	tx1Low := transaction.NewCheckedTransactionWithSender([]byte("sender tx 1 low"), 10, nil, sender, 1)
+	err = pool.Add(tx1Low)
+	require.ErrorIs(t, err, api.ErrReplacementUnderpriced, "Add should fail on underpriced replacement")
+	require.False(t, pool.IsQueued(tx1Low.Hash()), "underpriced replacement should not be queued")
+	require.True(t, pool.IsQueued(tx1.Hash()), "original transaction should remain queued")
+
+	// Replacement with a higher priority should replace the transaction.
+	tx1High := transaction.NewCheckedTransactionWithSender([]byte("sender tx 1 high"), 20, nil, sender, 1)
+	require.NoError(t, pool.Add(tx1High), "Add should succeed on replacement")
+	require.False(t, pool.IsQueued(tx1.Hash()), "replaced transaction should be removed")
+	require.True(t, pool.IsQueued(tx1High.Hash()), "replacement should be queued")
+	require.EqualValues(t, 2, pool.Size(), "replacement should not change the pool size")
+	require.EqualValues(
+		t,
+		[]*transaction.CheckedTransaction{tx1High, tx2},
+		pool.GetBatch(true),
+		"replacement should be scheduled instead of the replaced transaction",
+	)
+
+	// Once removed, the sender sequence number can be reused.
+	err = pool.RemoveBatch([]hash.Hash{tx1High.Hash()})
+	require.NoError(t, err, "RemoveBatch")
+	require.NoError(t, pool.Add(tx1Low), "Add after removal")
+}
+
+func testRemoveExpired(t *testing.T, pool api.TxPool) {
+	pool.Clear()
+
+	cfg := api.Config{
+		MaxPoolSize: 50,
+		WeightLimits: map[transaction.Weight]uint64{
+			transaction.WeightCount:     10,
+			transaction.WeightSizeBytes: 1000,
+		},
+	}
+	err := pool.UpdateConfig(cfg)
+	require.NoError(t, err, "UpdateConfig")
+
+	now := time.Now()
+	tx1 := transaction.RawCheckedTransaction([]byte("expiry tx 1"))
+	require.NoError(t, pool.Add(tx1), "Add")
+
+	// Nothing should expire while expiry is disabled.
+	require.Empty(t, pool.RemoveExpired(100, now.Add(time.Hour)), "RemoveExpired should not remove transactions when disabled")
+	require.EqualValues(t, 1, pool.Size())
+
+	cfg.Expiry = api.Expiry{MaxAgeRounds: 2}
+	err = pool.UpdateConfig(cfg)
+	require.NoError(t, err, "UpdateConfig")
+
+	tx2 := transaction.RawCheckedTransaction([]byte("expiry tx 2"))
+	require.NoError(t, pool.Add(tx2), "Add")
+	require.Empty(t, pool.RemoveExpired(101, now), "RemoveExpired")
+	tx3 := transaction.RawCheckedTransaction([]byte("expiry tx 3"))
+	require.NoError(t, pool.Add(tx3), "Add")
+
+	require.EqualValues(
+		t,
+		[]*transaction.CheckedTransaction{tx1, tx2},
+		pool.RemoveExpired(102, now),
+		"transactions should expire after the maximum number of rounds in arrival order",
+	)
+	require.False(t, pool.IsQueued(tx1.Hash()), "expired transaction should be removed")
+	require.False(t, pool.IsQueued(tx2.Hash()), "expired transaction should be removed")
+	require.True(t, pool.IsQueued(tx3.Hash()), "non-expired transaction should remain queued")
+
+	cfg.Expiry = api.Expiry{MaxAge: time.Minute}
+	err = pool.UpdateConfig(cfg)
+	require.NoError(t, err, "UpdateConfig")
+
+	require.Empty(t, pool.RemoveExpired(200, time.Now()), "RemoveExpired")
+	require.EqualValues(
+		t,
+		[]*transaction.CheckedTransaction{tx3},
+		pool.RemoveExpired(200, time.Now().Add(2*time.Minute)),
+		"transactions should expire after the maximum age",
+	)
+	require.EqualValues(t, 0, pool.Size())
+}
+
+func testLocalLane(t *testing.T, pool api.TxPool) {
+	pool.Clear()
+
+	err := pool.UpdateConfig(api.Config{
+		MaxPoolSize:      2,
+		MaxLocalPoolSize: 2,
+		WeightLimits: map[transaction.Weight]uint64{
+			transaction.WeightCount:     10,
+			transaction.WeightSizeBytes: 1000,
+		},
+	})
+	require.NoError(t, err, "UpdateConfig")
+
+	newLocalTx := func(raw []byte, priority uint64, sender []byte, senderSeq uint64) *transaction.CheckedTransaction {
+		tx := transaction.NewCheckedTransactionWithSender(raw, priority, nil, sender, senderSeq)
+		tx.SetMeta(transaction.TransactionMeta{Local: true})
+		return tx
+	}
+
+	remote1 := transaction.NewCheckedTransaction([]byte("remote tx 1"), 100, nil)
+	remote2 := transaction.NewCheckedTransaction([]byte("remote tx 2"), 50, nil)
+	require.NoError(t, pool.Add(remote1), "Add")
+	require.NoError(t, pool.Add(remote2), "Add")
+	err = pool.Add(transaction.NewCheckedTransaction([]byte("remote tx 3"), 100, nil))
+	require.ErrorIs(t, err, api.ErrFull, "Add should fail on full remote lane")
+
+	// Local transactions should not be affected by a full remote lane.
+	local1 := newLocalTx([]byte("local tx 1"), 1, []byte("sender"), 1)
+	local2 := newLocalTx([]byte("local tx 2"), 10, []byte("other"), 1)
+	require.NoError(t, pool.Add(local1), "Add local")
+	require.NoError(t, pool.Add(local2), "Add local")
+	err = pool.Add(newLocalTx([]byte("local tx 3"), 10, nil, 0))
+	require.ErrorIs(t, err, api.ErrFull, "Add should fail on full local lane")
+	require.EqualValues(t, 4, pool.Size(), "Size should include both lanes")
+
+	// Remote transactions should not replace local transactions.
+	err = pool.RemoveBatch([]hash.Hash{remote2.Hash()})
+	require.NoError(t, err, "RemoveBatch")
+	err = pool.Add(transaction.NewCheckedTransactionWithSender([]byte("remote replacement"), 1000, nil, []byte("sender"), 1))
+	require.ErrorIs(t, err, api.ErrLocalReplacement, "Add should fail on remote replacement of a local transaction")
+	require.True(t, pool.IsQueued(local1.Hash()), "local transaction should remain queued")
+
+	// Local transactions should be scheduled first, regardless of priority.
+	require.EqualValues(
+		t,
+		[]*transaction.CheckedTransaction{local2, local1, remote1},
+		pool.GetBatch(true),
+		"local transactions should be scheduled first",
+	)
+
+	// Without a local lane, local transactions should be treated as remote.
+	pool.Clear()
+	err = pool.UpdateConfig(api.Config{
+		MaxPoolSize: 1,
+		WeightLimits: map[transaction.Weight]uint64{
+			transaction.WeightCount:     10,
+			transaction.WeightSizeBytes: 1000,
+		},
+	})
+	require.NoError(t, err, "UpdateConfig")
+	require.NoError(t, pool.Add(remote1), "Add")
+	err = pool.Add(local1)
+	require.ErrorIs(t, err, api.ErrFull, "Add local should fail on full pool without a local lane")
 }
 
 // TxPoolImplementationBenchmarks runs the tx pool implementation benchmarks.
@@ -163,6 +163,18 @@ func testScheduleTransactions(t *testing.T, scheduler api.Scheduler) {
 	}
 	require.ElementsMatch(t, txs, returned, "all transactions should be returned")
 	require.IsDecreasing(t, prios, "transactions should be sorted by priority")
+
+	// Test max pool size update.
+	err = scheduler.UpdateMaxPoolSize(1)
+	require.NoError(t, err, "UpdateMaxPoolSize")
+	require.NoError(t, scheduler.QueueTx(txs[0]))
+	err = scheduler.QueueTx(txs[1])
+	require.Error(t, err, "QueueTx should fail when the pool is full")
+	err = scheduler.UpdateMaxPoolSize(2)
+	require.NoError(t, err, "UpdateMaxPoolSize")
+	require.NoError(t, scheduler.QueueTx(txs[1]))
+	require.EqualValues(t, 2, scheduler.UnscheduledSize(), "two transactions queued")
+	scheduler.Clear()
 }
 
 type benchmarkingDispatcher interface {
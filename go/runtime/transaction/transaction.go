@@ -171,6 +171,12 @@ func (w Weight) IsCustom() bool {
 	}
 }
 
+// TransactionMeta is the local node metadata of a checked transaction.
+type TransactionMeta struct {
+	// Local is true iff the transaction was submitted by a local client.
+	Local bool
+}
+
 // CheckedTransaction is a checked transaction to be scheduled.
 type CheckedTransaction struct {
 	// tx represents the raw binary transaction data.
@@ -183,11 +189,24 @@ type CheckedTransaction struct {
 	// in the CheckTx response.
 	weights map[Weight]uint64
 
+	// sender is the opaque transaction sender identifier as specified by
+	// the runtime in the CheckTx response. Empty if not specified.
+	sender string
+	// senderSeq is the per-sender sequence number (nonce) of the transaction
+	// as specified by the runtime in the CheckTx response.
+	senderSeq uint64
+
+	// meta is the local node metadata of the transaction.
+	meta TransactionMeta
+
 	hash hash.Hash
 }
 
 // String returns string representation of the raw transaction data.
 func (t *CheckedTransaction) String() string {
+	if t.sender != "" {
+		return fmt.Sprintf("CheckedTransaction{hash: %v, priority: %v, weights: %v, sender: %X, sender_seq: %v}", t.hash, t.priority, t.weights, t.sender, t.senderSeq)
+	}
 	return fmt.Sprintf("CheckedTransaction{hash: %v, priority: %v, weights: %v}", t.hash, t.priority, t.weights)
 }
 
@@ -214,6 +233,24 @@ func NewCheckedTransaction(tx []byte, priority uint64, weights map[Weight]uint64
 	return checkedTx
 }
 
+// NewCheckedTransactionWithSender creates a new CheckedTransactions from the
+// provided bytes, priority, weights and sender metadata.
+//
+// Transactions with the same sender and sender sequence number replace each
+// other in the transaction pool.
+func NewCheckedTransactionWithSender(
+	tx []byte,
+	priority uint64,
+	weights map[Weight]uint64,
+	sender []byte,
+	senderSeq uint64,
+) *CheckedTransaction {
+	checkedTx := NewCheckedTransaction(tx, priority, weights)
+	checkedTx.sender = string(sender)
+	checkedTx.senderSeq = senderSeq
+	return checkedTx
+}
+
 // Priority returns the transaction priority.
 func (t *CheckedTransaction) Priority() uint64 {
 	return t.priority
@@ -232,6 +269,30 @@ func (t *CheckedTransaction) Weights() map[Weight]uint64 {
 	return t.weights
 }
 
+// Sender returns the opaque transaction sender identifier.
+//
+// An empty sender means that the runtime did not specify the sender.
+func (t *CheckedTransaction) Sender() string {
+	return t.sender
+}
+
+// SenderSeq returns the per-sender sequence number (nonce) of the transaction.
+func (t *CheckedTransaction) SenderSeq() uint64 {
+	return t.senderSeq
+}
+
+// Meta returns the local node metadata of the transaction.
+func (t *CheckedTransaction) Meta() TransactionMeta {
+	return t.meta
+}
+
+// SetMeta sets the local node metadata of the transaction.
+//
+// The metadata must be set before the transaction is added to a transaction pool.
+func (t *CheckedTransaction) SetMeta(meta TransactionMeta) {
+	t.meta = meta
+}
+
 // Hash returns the hash of the transaction binary data.
 func (t *CheckedTransaction) Hash() hash.Hash {
 	return t.hash
@@ -3,6 +3,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/accessctl"
@@ -12,10 +13,11 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 )
 
-// SentryAddresses contains sentry node consensus and TLS addresses.
+// SentryAddresses contains sentry node consensus, TLS and P2P addresses.
 type SentryAddresses struct {
 	Consensus []node.ConsensusAddress `json:"consensus"`
 	TLS       []node.TLSAddress       `json:"tls"`
+	P2P       []node.Address          `json:"p2p,omitempty"`
 }
 
 // ServicePolicies contains policies for a GRPC service.
@@ -24,16 +26,47 @@ type ServicePolicies struct {
 	AccessPolicies map[common.Namespace]accessctl.Policy `json:"access_policies"`
 }
 
+// UpstreamStatus is the status of an upstream node protected by the sentry node.
+type UpstreamStatus struct {
+	// Address is the gRPC address of the upstream node.
+	Address string `json:"address"`
+
+	// ID is the node ID of the upstream node.
+	ID signature.PublicKey `json:"id"`
+
+	// State is the connectivity state of the connection to the upstream node.
+	State string `json:"state"`
+
+	// Healthy is true iff the connection to the upstream node is ready.
+	Healthy bool `json:"healthy"`
+
+	// Active is true iff requests are currently proxied to the upstream node.
+	Active bool `json:"active"`
+
+	// LastHealthy is the time when the upstream node was last seen healthy.
+	LastHealthy time.Time `json:"last_healthy"`
+}
+
+// Status is the sentry node status.
+type Status struct {
+	// Upstreams is the status of the upstream nodes, in order of preference.
+	Upstreams []UpstreamStatus `json:"upstreams"`
+
+	// UpstreamTLSPubKeys are the currently accepted TLS public keys of the upstream nodes,
+	// including the keys which are still accepted during the grace period after a rotation.
+	UpstreamTLSPubKeys []signature.PublicKey `json:"upstream_tls_pub_keys"`
+}
+
 // Backend is a sentry backend implementation.
 type Backend interface {
-	// Get addresses returns the list of consensus and TLS addresses of the sentry node.
+	// Get addresses returns the list of consensus, TLS and P2P addresses of the sentry node.
 	GetAddresses(context.Context) (*SentryAddresses, error)
 
-	// SetUpstreamTLSPubKeys notifies the sentry node of the new TLS public keys used by its
+	// SetUpstreamTLSPubKeys notifies the sentry node of the new TLS public keys used by the calling
 	// upstream node.
 	SetUpstreamTLSPubKeys(context.Context, []signature.PublicKey) error
 
-	// GetUpstreamTLSPubKeys returns the TLS public keys of the sentry node's upstream node.
+	// GetUpstreamTLSPubKeys returns the TLS public keys of all of the sentry node's upstream nodes.
 	GetUpstreamTLSPubKeys(context.Context) ([]signature.PublicKey, error)
 
 	// UpdatePolicies notifies the sentry node of policy changes.
@@ -44,6 +77,10 @@ type Backend interface {
 type LocalBackend interface {
 	Backend
 
+	// GetUpstreamNodeTLSPubKeys returns the TLS public keys of the given upstream node, as
+	// registered for the node and as pushed by the node itself.
+	GetUpstreamNodeTLSPubKeys(context.Context, signature.PublicKey) ([]signature.PublicKey, error)
+
 	// GetPolicyChecker returns the current access policy checker for the given service.
 	GetPolicyChecker(context.Context, grpc.ServiceName) (*policy.DynamicRuntimePolicyChecker, error)
 }
@@ -17,13 +17,13 @@ var (
 	methodGetAddresses = serviceName.NewMethod("GetAddresses", nil)
 
 	// methodSetUpstreamTLSPubKeys is the SetUpstreamTLSPubKeys method.
-	methodSetUpstreamTLSPubKeys = serviceName.NewMethod("SetUpstreamTLSPubKeys", []signature.PublicKey{})
+	methodSetUpstreamTLSPubKeys = serviceName.NewMethod("SetUpstreamTLSPubKeys", []signature.PublicKey{}).WithAdminCapability()
 
 	// methodGetUpstreamTLSPubKeys is the GetUpstreamTLSPubKeys method.
 	methodGetUpstreamTLSPubKeys = serviceName.NewMethod("GetUpstreamTLSPubKeys", nil)
 
 	// methodUpdatePolicies is the UpdatePolicies method.
-	methodUpdatePolicies = serviceName.NewMethod("UpdatePolicies", ServicePolicies{})
+	methodUpdatePolicies = serviceName.NewMethod("UpdatePolicies", ServicePolicies{}).WithAdminCapability()
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
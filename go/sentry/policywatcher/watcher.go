@@ -25,9 +25,9 @@ type policyWatcher struct {
 
 	ctx context.Context
 
-	sentryAddrs   []node.TLSAddress
+	sentryAddrs   func() []node.TLSAddress
 	identity      *identity.Identity
-	sentryClients []*sentryClient.Client
+	sentryClients map[string]*sentryClient.Client
 
 	logger *logging.Logger
 }
@@ -38,20 +38,32 @@ func (c *policyWatcher) PolicyUpdated(service grpc.ServiceName, accessPolicies m
 		c.Lock()
 		defer c.Unlock()
 
+		sentryAddrs := c.sentryAddrs()
+
+		// Close clients of sentry nodes that are no longer configured.
+		current := make(map[string]bool)
+		for _, addr := range sentryAddrs {
+			current[addr.String()] = true
+		}
+		for key, client := range c.sentryClients {
+			if !current[key] {
+				client.Close()
+				delete(c.sentryClients, key)
+			}
+		}
+
 		// Notify the sentry nodes of the new policy.
-		for idx, addr := range c.sentryAddrs {
+		for _, addr := range sentryAddrs {
+			key := addr.String()
 			pushPolicies := func() error {
-				var client *sentryClient.Client
-				var err error
-
-				if c.sentryClients[idx] != nil {
-					client = c.sentryClients[idx]
-				} else {
+				client, ok := c.sentryClients[key]
+				if !ok {
+					var err error
 					client, err = sentryClient.New(addr, c.identity)
 					if err != nil {
 						return err
 					}
-					c.sentryClients[idx] = client
+					c.sentryClients[key] = client
 				}
 
 				policies := sentry.ServicePolicies{
@@ -59,11 +71,10 @@ func (c *policyWatcher) PolicyUpdated(service grpc.ServiceName, accessPolicies m
 					AccessPolicies: accessPolicies,
 				}
 
-				err = client.UpdatePolicies(c.ctx, policies)
-				if err != nil {
+				if err := client.UpdatePolicies(c.ctx, policies); err != nil {
 					// Try to reconnect on next try in case our certs have rotated.
-					c.sentryClients[idx].Close()
-					c.sentryClients[idx] = nil
+					client.Close()
+					delete(c.sentryClients, key)
 					return err
 				}
 				return nil
@@ -82,12 +93,15 @@ func (c *policyWatcher) PolicyUpdated(service grpc.ServiceName, accessPolicies m
 }
 
 // New retruns a new policy watcher.
-func New(ctx context.Context, sentryAddrs []node.TLSAddress, id *identity.Identity) api.PolicyWatcher {
+//
+// The sentryAddrs function is called on each policy update to obtain the addresses of the sentry
+// nodes that should be notified.
+func New(ctx context.Context, sentryAddrs func() []node.TLSAddress, id *identity.Identity) api.PolicyWatcher {
 	return &policyWatcher{
 		ctx:           ctx,
 		sentryAddrs:   sentryAddrs,
 		identity:      id,
-		sentryClients: make([]*sentryClient.Client, len(sentryAddrs)),
+		sentryClients: make(map[string]*sentryClient.Client),
 		logger:        logging.GetLogger("sentry/policywatcher"),
 	}
 }
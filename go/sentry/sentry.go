@@ -3,8 +3,14 @@ package sentry
 
 import (
 	"context"
+	"crypto/ed25519"
 	"fmt"
 	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
@@ -13,8 +19,10 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	"github.com/oasisprotocol/oasis-core/go/sentry/api"
 	grpcSentry "github.com/oasisprotocol/oasis-core/go/worker/sentry/grpc"
+	p2pSentry "github.com/oasisprotocol/oasis-core/go/worker/sentry/p2p"
 )
 
 var _ api.Backend = (*backend)(nil)
@@ -27,7 +35,14 @@ type backend struct {
 	consensus consensus.Backend
 	identity  *identity.Identity
 
-	upstreamTLSPubKeys []signature.PublicKey
+	// upstreamTLSPubKeys are the TLS public keys of the upstream nodes, keyed by the TLS public
+	// key that the upstream node used when pushing them.
+	upstreamTLSPubKeys map[signature.PublicKey][]signature.PublicKey
+	// retiredUpstreamTLSPubKeys are the TLS public keys that are no longer pushed by upstream
+	// nodes, together with the time until which they are still accepted, keyed by the TLS public
+	// key that the upstream node used when pushing them.
+	retiredUpstreamTLSPubKeys     map[signature.PublicKey]map[signature.PublicKey]time.Time
+	upstreamTLSPubKeysGracePeriod time.Duration
 
 	grpcPolicyCheckers map[cmnGrpc.ServiceName]*policy.DynamicRuntimePolicyChecker
 }
@@ -64,26 +79,147 @@ func (b *backend) GetAddresses(ctx context.Context) (*api.SentryAddresses, error
 		}
 	}
 
+	// P2P addresses -- only available if P2P sentry is enabled.
+	p2pAddrs, err := p2pSentry.GetNodeAddresses()
+	if err != nil {
+		return nil, fmt.Errorf("sentry: error obtaining sentry P2P worker addresses: %w", err)
+	}
+
 	return &api.SentryAddresses{
 		Consensus: consensusAddrs,
 		TLS:       tlsAddresses,
+		P2P:       p2pAddrs,
 	}, nil
 }
 
+// callerTLSPubKey returns the TLS public key of the caller, or the zero key in case the caller
+// did not authenticate via TLS (e.g., local calls).
+func callerTLSPubKey(ctx context.Context) signature.PublicKey {
+	var pk signature.PublicKey
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return pk
+	}
+	tlsAuth, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsAuth.State.PeerCertificates) != 1 {
+		return pk
+	}
+	raw, ok := tlsAuth.State.PeerCertificates[0].PublicKey.(ed25519.PublicKey)
+	if !ok {
+		return pk
+	}
+	_ = pk.UnmarshalBinary(raw[:])
+	return pk
+}
+
 func (b *backend) SetUpstreamTLSPubKeys(ctx context.Context, pubKeys []signature.PublicKey) error {
+	// Upstream nodes authenticate to the sentry control endpoint using their (persistent) sentry
+	// client certificate, so the pushed keys of each upstream node are tracked separately.
+	caller := callerTLSPubKey(ctx)
+
 	b.Lock()
 	defer b.Unlock()
 
-	b.upstreamTLSPubKeys = pubKeys
+	now := time.Now()
+	retired := b.retiredUpstreamTLSPubKeys[caller]
+	if retired == nil {
+		retired = make(map[signature.PublicKey]time.Time)
+		b.retiredUpstreamTLSPubKeys[caller] = retired
+	}
+	pushed := make(map[signature.PublicKey]bool)
+	for _, pk := range pubKeys {
+		pushed[pk] = true
+		delete(retired, pk)
+	}
+
+	// Keep accepting keys that are no longer pushed for a grace period, so that connections to
+	// the upstream node are not disrupted in case it rotates its certificates before the sentry
+	// node observes the new certificate being used.
+	for _, pk := range b.upstreamTLSPubKeys[caller] {
+		if pushed[pk] {
+			continue
+		}
+		retired[pk] = now.Add(b.upstreamTLSPubKeysGracePeriod)
+
+		b.logger.Info("retiring upstream TLS public key",
+			"pub_key", pk,
+			"grace_period", b.upstreamTLSPubKeysGracePeriod,
+		)
+	}
+	b.upstreamTLSPubKeys[caller] = pubKeys
 
 	return nil
 }
 
+// NOTE: Assumes lock is held.
+func (b *backend) callerTLSPubKeysLocked(caller signature.PublicKey, now time.Time) []signature.PublicKey {
+	pubKeys := append([]signature.PublicKey{}, b.upstreamTLSPubKeys[caller]...)
+	for pk, expiry := range b.retiredUpstreamTLSPubKeys[caller] {
+		if !now.Before(expiry) {
+			delete(b.retiredUpstreamTLSPubKeys[caller], pk)
+			continue
+		}
+		pubKeys = append(pubKeys, pk)
+	}
+	return pubKeys
+}
+
 func (b *backend) GetUpstreamTLSPubKeys(ctx context.Context) ([]signature.PublicKey, error) {
-	b.RLock()
-	defer b.RUnlock()
+	b.Lock()
+	defer b.Unlock()
 
-	return b.upstreamTLSPubKeys, nil
+	var pubKeys []signature.PublicKey
+	now := time.Now()
+	for caller := range b.upstreamTLSPubKeys {
+		pubKeys = append(pubKeys, b.callerTLSPubKeysLocked(caller, now)...)
+	}
+	return pubKeys, nil
+}
+
+func (b *backend) GetUpstreamNodeTLSPubKeys(ctx context.Context, nodeID signature.PublicKey) ([]signature.PublicKey, error) {
+	// Only accept the TLS public keys registered for the given node, together with any keys
+	// pushed by the same upstream node, so that the keys of one upstream node can not be used to
+	// impersonate another.
+	n, err := b.consensus.Registry().GetNode(ctx, &registry.IDQuery{
+		ID:     nodeID,
+		Height: consensus.HeightLatest,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sentry: failed to get upstream node %s: %w", nodeID, err)
+	}
+	registered := map[signature.PublicKey]bool{
+		n.TLS.PubKey: true,
+	}
+	if n.TLS.NextPubKey.IsValid() && n.TLS.NextPubKey != (signature.PublicKey{}) {
+		registered[n.TLS.NextPubKey] = true
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	pubKeys := make([]signature.PublicKey, 0, len(registered))
+	for pk := range registered {
+		pubKeys = append(pubKeys, pk)
+	}
+	now := time.Now()
+	for caller, pushed := range b.upstreamTLSPubKeys {
+		var ownedByNode bool
+		for _, pk := range pushed {
+			if registered[pk] {
+				ownedByNode = true
+				break
+			}
+		}
+		if !ownedByNode {
+			continue
+		}
+		for _, pk := range b.callerTLSPubKeysLocked(caller, now) {
+			if !registered[pk] {
+				pubKeys = append(pubKeys, pk)
+			}
+		}
+	}
+	return pubKeys, nil
 }
 
 func (b *backend) UpdatePolicies(ctx context.Context, p api.ServicePolicies) error {
@@ -120,10 +256,13 @@ func New(
 	}
 
 	b := &backend{
-		logger:             logging.GetLogger("sentry"),
-		consensus:          consensusBackend,
-		identity:           identity,
-		grpcPolicyCheckers: make(map[cmnGrpc.ServiceName]*policy.DynamicRuntimePolicyChecker),
+		logger:                        logging.GetLogger("sentry"),
+		consensus:                     consensusBackend,
+		identity:                      identity,
+		upstreamTLSPubKeys:            make(map[signature.PublicKey][]signature.PublicKey),
+		retiredUpstreamTLSPubKeys:     make(map[signature.PublicKey]map[signature.PublicKey]time.Time),
+		upstreamTLSPubKeysGracePeriod: viper.GetDuration(grpcSentry.CfgUpstreamTLSKeyGracePeriod),
+		grpcPolicyCheckers:            make(map[cmnGrpc.ServiceName]*policy.DynamicRuntimePolicyChecker),
 	}
 
 	return b, nil
@@ -0,0 +1,116 @@
+package sentry
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+)
+
+type testRegistry struct {
+	registry.Backend
+
+	nodes map[signature.PublicKey]*node.Node
+}
+
+func (r *testRegistry) GetNode(ctx context.Context, query *registry.IDQuery) (*node.Node, error) {
+	n, ok := r.nodes[query.ID]
+	if !ok {
+		return nil, registry.ErrNoSuchNode
+	}
+	return n, nil
+}
+
+type testConsensus struct {
+	consensus.Backend
+
+	registry *testRegistry
+}
+
+func (c *testConsensus) Registry() registry.Backend {
+	return c.registry
+}
+
+func testPubKey(b byte) signature.PublicKey {
+	var pk signature.PublicKey
+	pk[0] = b
+	pk[31] = 1
+	return pk
+}
+
+func callerContext(caller signature.PublicKey) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{
+					{PublicKey: ed25519.PublicKey(caller[:])},
+				},
+			},
+		},
+	})
+}
+
+func TestUpstreamNodeTLSPubKeys(t *testing.T) {
+	require := require.New(t)
+
+	nodeA, nodeB, unknown := testPubKey(1), testPubKey(2), testPubKey(3)
+	tlsA, nextTLSA, tlsB := testPubKey(10), testPubKey(11), testPubKey(20)
+	pushedA, pushedB := testPubKey(12), testPubKey(21)
+	callerA, callerB := testPubKey(100), testPubKey(101)
+
+	b := &backend{
+		logger: logging.GetLogger("sentry/test"),
+		consensus: &testConsensus{
+			registry: &testRegistry{
+				nodes: map[signature.PublicKey]*node.Node{
+					nodeA: {ID: nodeA, TLS: node.TLSInfo{PubKey: tlsA, NextPubKey: nextTLSA}},
+					nodeB: {ID: nodeB, TLS: node.TLSInfo{PubKey: tlsB}},
+				},
+			},
+		},
+		upstreamTLSPubKeys:            make(map[signature.PublicKey][]signature.PublicKey),
+		retiredUpstreamTLSPubKeys:     make(map[signature.PublicKey]map[signature.PublicKey]time.Time),
+		upstreamTLSPubKeysGracePeriod: time.Hour,
+	}
+
+	err := b.SetUpstreamTLSPubKeys(callerContext(callerA), []signature.PublicKey{nextTLSA, pushedA})
+	require.NoError(err, "SetUpstreamTLSPubKeys")
+	err = b.SetUpstreamTLSPubKeys(callerContext(callerB), []signature.PublicKey{tlsB, pushedB})
+	require.NoError(err, "SetUpstreamTLSPubKeys")
+
+	keys, err := b.GetUpstreamNodeTLSPubKeys(context.Background(), nodeA)
+	require.NoError(err, "GetUpstreamNodeTLSPubKeys")
+	require.ElementsMatch([]signature.PublicKey{tlsA, nextTLSA, pushedA}, keys,
+		"only keys registered or pushed by the upstream node should be accepted")
+
+	keys, err = b.GetUpstreamNodeTLSPubKeys(context.Background(), nodeB)
+	require.NoError(err, "GetUpstreamNodeTLSPubKeys")
+	require.ElementsMatch([]signature.PublicKey{tlsB, pushedB}, keys,
+		"only keys registered or pushed by the upstream node should be accepted")
+
+	_, err = b.GetUpstreamNodeTLSPubKeys(context.Background(), unknown)
+	require.ErrorIs(err, registry.ErrNoSuchNode, "keys of unregistered nodes should not be accepted")
+
+	// Retired keys should still be accepted for the node that pushed them.
+	err = b.SetUpstreamTLSPubKeys(callerContext(callerA), []signature.PublicKey{nextTLSA})
+	require.NoError(err, "SetUpstreamTLSPubKeys")
+	keys, err = b.GetUpstreamNodeTLSPubKeys(context.Background(), nodeA)
+	require.NoError(err, "GetUpstreamNodeTLSPubKeys")
+	require.ElementsMatch([]signature.PublicKey{tlsA, nextTLSA, pushedA}, keys,
+		"retired keys should be accepted during the grace period")
+	keys, err = b.GetUpstreamNodeTLSPubKeys(context.Background(), nodeB)
+	require.NoError(err, "GetUpstreamNodeTLSPubKeys")
+	require.NotContains(keys, pushedA, "retired keys should not be accepted for other nodes")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
@@ -20,7 +21,43 @@ func ConvertToTokenAmount(amount quantity.Quantity, tokenValueExponent uint8) (s
 		return "", ErrInvalidTokenValueExponent
 	}
 
-	return prettyprint.QuantityFrac(amount, tokenValueExponent), nil
+	return amount.FormatDecimal(tokenValueExponent), nil
+}
+
+// ConvertToBaseUnits returns the given token amount (e.g., "10.5") in base
+// units according to the given token's value base-10 exponent.
+func ConvertToBaseUnits(tokenAmount string, tokenValueExponent uint8) (*quantity.Quantity, error) {
+	if tokenValueExponent > TokenValueExponentMaxValue {
+		return nil, ErrInvalidTokenValueExponent
+	}
+
+	return quantity.ParseDecimal(tokenAmount, tokenValueExponent)
+}
+
+// ParseAmount parses an amount given either in base units (e.g.,
+// "10500000000") or in tokens followed by the token's ticker symbol (e.g.,
+// "10.5 ROSE") and returns the amount in base units.
+func ParseAmount(text, symbol string, tokenValueExponent uint8) (*quantity.Quantity, error) {
+	fields := strings.Fields(text)
+	switch len(fields) {
+	case 1:
+		var amount quantity.Quantity
+		if err := amount.UnmarshalText([]byte(fields[0])); err != nil {
+			return nil, fmt.Errorf("staking/token: malformed base unit amount '%s': %w", text, err)
+		}
+		return &amount, nil
+	case 2:
+		if symbol == "" || fields[1] != symbol {
+			return nil, fmt.Errorf("staking/token: unexpected token symbol '%s'", fields[1])
+		}
+		amount, err := ConvertToBaseUnits(fields[0], tokenValueExponent)
+		if err != nil {
+			return nil, fmt.Errorf("staking/token: malformed token amount '%s': %w", text, err)
+		}
+		return amount, nil
+	default:
+		return nil, fmt.Errorf("staking/token: malformed amount '%s'", text)
+	}
 }
 
 // PrettyPrintAmount writes a pretty-printed representation of the given amount
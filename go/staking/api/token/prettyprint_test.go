@@ -112,3 +112,39 @@ func TestPrettyPrintAmount(t *testing.T) {
 
 	}
 }
+
+func TestParseAmount(t *testing.T) {
+	require := require.New(t)
+
+	for _, t := range []struct {
+		text   string
+		amount *quantity.Quantity
+		valid  bool
+	}{
+		{"10500000000", quantity.NewFromUint64(10500000000), true},
+		{"10.5 ROSE", quantity.NewFromUint64(10500000000), true},
+		{"10 ROSE", quantity.NewFromUint64(10000000000), true},
+		{"0.000000001 ROSE", quantity.NewFromUint64(1), true},
+		{"  42  ", quantity.NewFromUint64(42), true},
+		// Base unit amounts must be integers.
+		{"10.5", nil, false},
+		// Too many fractional digits.
+		{"0.0000000001 ROSE", nil, false},
+		// Wrong symbol.
+		{"10.5 TEST", nil, false},
+		{"-10 ROSE", nil, false},
+		{"10.5 ROSE extra", nil, false},
+		{"", nil, false},
+	} {
+		amount, err := ParseAmount(t.text, "ROSE", 9)
+		if !t.valid {
+			require.Error(err, "parsing '%s' should fail", t.text)
+			continue
+		}
+		require.NoError(err, "parsing '%s' shouldn't fail", t.text)
+		require.Zero(t.amount.Cmp(amount), "parsing '%s' didn't return the expected amount", t.text)
+	}
+
+	_, err := ConvertToBaseUnits("1.0", TokenValueExponentMaxValue+1)
+	require.ErrorIs(err, ErrInvalidTokenValueExponent, "too large token's value exponent")
+}
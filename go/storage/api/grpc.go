@@ -59,7 +59,8 @@ var (
 			}
 			return r.Namespace, nil
 		}).
-		WithAccessControl(cmnGrpc.AccessControlAlways)
+		WithAccessControl(cmnGrpc.AccessControlAlways).
+		WithWriteAccess()
 
 	// MethodApplyBatch is the ApplyBatch method.
 	MethodApplyBatch = ServiceName.NewMethod("ApplyBatch", ApplyBatchRequest{}).
@@ -70,7 +71,8 @@ var (
 			}
 			return r.Namespace, nil
 		}).
-		WithAccessControl(cmnGrpc.AccessControlAlways)
+		WithAccessControl(cmnGrpc.AccessControlAlways).
+		WithWriteAccess()
 
 	// MethodGetDiff is the GetDiff method.
 	MethodGetDiff = ServiceName.NewMethod("GetDiff", GetDiffRequest{})
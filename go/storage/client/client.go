@@ -25,6 +25,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/runtime/nodes/grpc"
 	"github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
 )
 
@@ -36,7 +37,16 @@ var (
 // ErrStorageNotAvailable is the error returned when no storage node is available.
 var ErrStorageNotAvailable = errors.New("storage/client: storage not available")
 
+var errInvalidProof = errors.New("storage/client: invalid proof")
+
 const (
+	// LogEventInvalidReceipt is a log event value that signals a storage node returned
+	// an invalid storage receipt.
+	LogEventInvalidReceipt = "storage/client/invalid_receipt"
+	// LogEventInvalidProof is a log event value that signals a storage node returned
+	// an invalid proof.
+	LogEventInvalidProof = "storage/client/invalid_proof"
+
 	retryInterval = 1 * time.Second
 	maxRetries    = 15
 )
@@ -247,6 +257,7 @@ func (b *storageClientBackend) writeWithClient( // nolint: gocyclo
 			b.logger.Error("failed to open receipt for a storage node",
 				"node", response.node,
 				"err", err,
+				logging.LogEvent, LogEventInvalidReceipt,
 			)
 			continue
 		}
@@ -278,6 +289,7 @@ func (b *storageClientBackend) writeWithClient( // nolint: gocyclo
 				"obtainedRoots", receiptBody.Roots,
 				"expectedNewRootTypes", expectedNewRootTypes,
 				"expectedNewRoots", expectedNewRoots,
+				logging.LogEvent, LogEventInvalidReceipt,
 			)
 			continue
 		}
@@ -409,6 +421,15 @@ func (b *storageClientBackend) readWithClient(
 			if ctx.Err() != nil {
 				return backoff.Permanent(ctx.Err())
 			}
+			if errors.Is(err, errInvalidProof) {
+				b.logger.Error("got invalid proof from a storage node",
+					"node", conn.Node,
+					"err", err,
+					"runtime_id", ns,
+					logging.LogEvent, LogEventInvalidProof,
+				)
+				continue
+			}
 			if err != nil {
 				b.logger.Error("failed to get response from a storage node",
 					"node", conn.Node,
@@ -431,12 +452,35 @@ func (b *storageClientBackend) readWithClient(
 	return resp, err
 }
 
+// verifyProofResponse verifies that the proof response contains a valid proof for the given tree
+// so that an invalid proof returned by one storage node does not prevent reading from others.
+func verifyProofResponse(ctx context.Context, tree *api.TreeID, rsp *api.ProofResponse) error {
+	// The proof can either be for the requested position or for the tree root.
+	root := rsp.Proof.UntrustedRoot
+	if !root.Equal(&tree.Position) && !root.Equal(&tree.Root.Hash) {
+		return fmt.Errorf("%w: proof for unexpected root (%s)", errInvalidProof, root)
+	}
+
+	var pv syncer.ProofVerifier
+	if _, err := pv.VerifyProof(ctx, root, &rsp.Proof); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidProof, err)
+	}
+	return nil
+}
+
 func (b *storageClientBackend) SyncGet(ctx context.Context, request *api.GetRequest) (*api.ProofResponse, error) {
 	rsp, err := b.readWithClient(
 		ctx,
 		request.Tree.Root.Namespace,
 		func(ctx context.Context, c api.Backend) (interface{}, error) {
-			return c.SyncGet(ctx, request)
+			rsp, err := c.SyncGet(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			if err = verifyProofResponse(ctx, &request.Tree, rsp); err != nil {
+				return nil, err
+			}
+			return rsp, nil
 		},
 	)
 	if err != nil {
@@ -450,7 +494,14 @@ func (b *storageClientBackend) SyncGetPrefixes(ctx context.Context, request *api
 		ctx,
 		request.Tree.Root.Namespace,
 		func(ctx context.Context, c api.Backend) (interface{}, error) {
-			return c.SyncGetPrefixes(ctx, request)
+			rsp, err := c.SyncGetPrefixes(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			if err = verifyProofResponse(ctx, &request.Tree, rsp); err != nil {
+				return nil, err
+			}
+			return rsp, nil
 		},
 	)
 	if err != nil {
@@ -464,7 +515,14 @@ func (b *storageClientBackend) SyncIterate(ctx context.Context, request *api.Ite
 		ctx,
 		request.Tree.Root.Namespace,
 		func(ctx context.Context, c api.Backend) (interface{}, error) {
-			return c.SyncIterate(ctx, request)
+			rsp, err := c.SyncIterate(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			if err = verifyProofResponse(ctx, &request.Tree, rsp); err != nil {
+				return nil, err
+			}
+			return rsp, nil
 		},
 	)
 	if err != nil {
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+)
+
+func TestVerifyProofResponse(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	var ns common.Namespace
+
+	tree := mkvs.New(nil, nil, api.RootTypeState)
+	defer tree.Close()
+	for _, key := range []string{"foo", "bar", "moo", "boo"} {
+		err := tree.Insert(ctx, []byte(key), []byte("value of "+key))
+		require.NoError(err, "Insert")
+	}
+	_, rootHash, err := tree.Commit(ctx, ns, 0)
+	require.NoError(err, "Commit")
+
+	treeID := api.TreeID{
+		Root: api.Root{
+			Namespace: ns,
+			Version:   0,
+			Type:      api.RootTypeState,
+			Hash:      rootHash,
+		},
+		Position: rootHash,
+	}
+	rsp, err := tree.SyncGet(ctx, &api.GetRequest{Tree: treeID, Key: []byte("foo")})
+	require.NoError(err, "SyncGet")
+
+	err = verifyProofResponse(ctx, &treeID, rsp)
+	require.NoError(err, "verifyProofResponse should succeed for a valid proof")
+
+	// Proofs for unexpected roots should be rejected.
+	otherTreeID := treeID
+	otherTreeID.Root.Hash = hash.NewFromBytes([]byte("other root"))
+	otherTreeID.Position = otherTreeID.Root.Hash
+	err = verifyProofResponse(ctx, &otherTreeID, rsp)
+	require.Error(err, "verifyProofResponse should fail for a proof for an unexpected root")
+	require.True(errors.Is(err, errInvalidProof), "error should be errInvalidProof")
+
+	// Corrupted proofs should be rejected.
+	corrupted := &api.ProofResponse{
+		Proof: api.Proof{
+			UntrustedRoot: rsp.Proof.UntrustedRoot,
+		},
+	}
+	for _, entry := range rsp.Proof.Entries {
+		corrupted.Proof.Entries = append(corrupted.Proof.Entries, append([]byte{}, entry...))
+	}
+	entry := corrupted.Proof.Entries[0]
+	entry[len(entry)-1] ^= 0xff
+	err = verifyProofResponse(ctx, &treeID, corrupted)
+	require.Error(err, "verifyProofResponse should fail for a corrupted proof")
+	require.True(errors.Is(err, errInvalidProof), "error should be errInvalidProof")
+}
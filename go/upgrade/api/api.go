@@ -5,9 +5,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
@@ -83,6 +85,10 @@ var (
 	// ErrBadDescriptor is the error returned when the provided descriptor is bad.
 	ErrBadDescriptor = errors.New(ModuleName, 8, "upgrade: bad descriptor")
 
+	// ErrNoSnapshot is the error returned when rolling back an upgrade for which no state snapshot
+	// is available.
+	ErrNoSnapshot = errors.New(ModuleName, 9, "upgrade: no state snapshot available")
+
 	_ prettyprint.PrettyPrinter = (*Descriptor)(nil)
 )
 
@@ -112,9 +118,33 @@ type Descriptor struct { // nolint: maligned
 	Target version.ProtocolVersions `json:"target"`
 	// Epoch is the epoch at which the upgrade should happen.
 	Epoch beacon.EpochTime `json:"epoch"`
+	// Binary optionally describes the binary that performs the upgrade.
+	Binary *Binary `json:"binary,omitempty"`
+}
+
+// Binary describes the binary that performs an upgrade.
+type Binary struct {
+	// Name is the file name under which the binary is published.
+	Name string `json:"name"`
+	// Hash is the SHA-512/256 hash of the binary.
+	Hash hash.Hash `json:"hash"`
+}
+
+// ValidateBasic does basic validation checks of the upgrade binary description.
+func (b *Binary) ValidateBasic() error {
+	if b.Name == "" || b.Name == "." || b.Name == ".." || strings.ContainsAny(b.Name, `/\`) {
+		return fmt.Errorf("invalid name: '%s'", b.Name)
+	}
+	if b.Hash == (hash.Hash{}) {
+		return fmt.Errorf("missing hash")
+	}
+	return nil
 }
 
 // Equals compares descriptors for equality.
+//
+// The binary description is not taken into account as it is only available in descriptors
+// submitted locally, which must still match the same upgrade submitted via governance.
 func (d *Descriptor) Equals(other *Descriptor) bool {
 	if d.V != other.V {
 		return false
@@ -154,6 +184,11 @@ func (d Descriptor) ValidateBasic() error {
 			MaxUpgradeEpoch,
 		)
 	}
+	if d.Binary != nil {
+		if err := d.Binary.ValidateBasic(); err != nil {
+			return fmt.Errorf("invalid upgrade descriptor binary: %w", err)
+		}
+	}
 
 	return nil
 }
@@ -161,7 +196,13 @@ func (d Descriptor) ValidateBasic() error {
 // EnsureCompatible checks if currently running binary is compatible with
 // the upgrade descriptor.
 func (d *Descriptor) EnsureCompatible() error {
-	ownConsensus := version.Versions.ConsensusProtocol
+	return d.EnsureCompatibleWith(version.Versions)
+}
+
+// EnsureCompatibleWith checks if a binary with the given protocol versions is compatible with
+// the upgrade.
+func (d *Descriptor) EnsureCompatibleWith(own version.ProtocolVersions) error {
+	ownConsensus := own.ConsensusProtocol
 	targetConsensus := d.Target.ConsensusProtocol
 	if ownConsensus.MaskNonMajor() != targetConsensus.MaskNonMajor() {
 		return fmt.Errorf("binary consensus version not compatible: own: %s, required: %s", ownConsensus, targetConsensus)
@@ -176,6 +217,11 @@ func (d Descriptor) PrettyPrint(ctx context.Context, prefix string, w io.Writer)
 	fmt.Fprintf(w, "%sTarget Version:\n", prefix)
 	d.Target.PrettyPrint(ctx, prefix+"  ", w)
 	fmt.Fprintf(w, "%sEpoch: %d\n", prefix, d.Epoch)
+	if d.Binary != nil {
+		fmt.Fprintf(w, "%sBinary:\n", prefix)
+		fmt.Fprintf(w, "%s  Name: %s\n", prefix, d.Binary.Name)
+		fmt.Fprintf(w, "%s  Hash: %s\n", prefix, d.Binary.Hash)
+	}
 }
 
 // PrettyType returns a representation of Descriptor that can be used for pretty
@@ -198,6 +244,46 @@ type PendingUpgrade struct {
 
 	// LastCompletedStage is the last upgrade stage that was successfully completed.
 	LastCompletedStage UpgradeStage `json:"last_completed_stage"`
+
+	// Progress is the progress of the upgrade stage that is currently being performed in multiple
+	// steps (if any).
+	Progress *UpgradeProgress `json:"progress,omitempty"`
+
+	// Preflight are the results of the latest preflight checks, performed while waiting for the
+	// upgrade epoch. The results are only reported by the node and are never persisted.
+	Preflight []PreflightCheck `json:"preflight,omitempty"`
+}
+
+// PreflightCheck is the result of an upgrade preflight check.
+type PreflightCheck struct {
+	// Name is the name of the check.
+	Name string `json:"name"`
+
+	// Passed is true iff the check passed.
+	Passed bool `json:"passed"`
+
+	// Reason describes why the check failed.
+	Reason string `json:"reason,omitempty"`
+}
+
+// UpgradeProgress is the progress of an upgrade stage that is performed in multiple steps.
+type UpgradeProgress struct {
+	// Stage is the upgrade stage being performed.
+	Stage UpgradeStage `json:"stage"`
+
+	// CompletedSteps is the number of completed steps.
+	CompletedSteps uint64 `json:"completed_steps"`
+
+	// TotalSteps is the total number of steps.
+	TotalSteps uint64 `json:"total_steps"`
+}
+
+// Percentage returns the percentage of completed steps.
+func (p UpgradeProgress) Percentage() float64 {
+	if p.TotalSteps == 0 {
+		return 100
+	}
+	return 100 * float64(p.CompletedSteps) / float64(p.TotalSteps)
 }
 
 // IsCompleted checks if all upgrade stages were already completed.
@@ -221,6 +307,22 @@ func (pu *PendingUpgrade) PushStage(stage UpgradeStage) {
 		panic("upgrade: out of order upgrade stage execution")
 	}
 	pu.LastCompletedStage = stage
+	if pu.Progress != nil && pu.Progress.Stage == stage {
+		pu.Progress = nil
+	}
+}
+
+// DryRunResult is the result of an upgrade dry run.
+type DryRunResult struct {
+	// Height is the height of the consensus state that the upgrade was executed against.
+	Height int64 `json:"height"`
+
+	// StateRoot is the consensus state root after the upgrade. In case the upgrade failed, it is
+	// the zero hash.
+	StateRoot hash.Hash `json:"state_root"`
+
+	// Error is the error returned by the upgrade handler, if any.
+	Error string `json:"error,omitempty"`
 }
 
 // Backend defines the interface for upgrade managers.
@@ -254,6 +356,11 @@ type Backend interface {
 	// It is idempotent with respect to the current upgrade descriptor.
 	ConsensusUpgrade(interface{}, beacon.EpochTime, int64) error
 
+	// DryRunConsensusUpgrade performs the consensus portion of the given pending upgrade, without
+	// updating the state of the upgrade. The consensus backend is responsible for discarding any
+	// changes made to the consensus state.
+	DryRunConsensusUpgrade(interface{}, *Descriptor) error
+
 	// Close cleans up any upgrader state and database handles.
 	Close()
 }
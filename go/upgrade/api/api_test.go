@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 )
 
@@ -123,6 +124,33 @@ func TestValidateBasic(t *testing.T) {
 			},
 			shouldErr: true,
 		},
+		{
+			msg: "binary with invalid name should fail",
+			d: &Descriptor{
+				Versioned: cbor.NewVersioned(LatestDescriptorVersion),
+				Handler:   "TestHandler",
+				Target:    version.Versions,
+				Epoch:     42,
+				Binary: &Binary{
+					Name: "../oasis-node",
+					Hash: hash.NewFromBytes([]byte("oasis-node")),
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			msg: "binary without hash should fail",
+			d: &Descriptor{
+				Versioned: cbor.NewVersioned(LatestDescriptorVersion),
+				Handler:   "TestHandler",
+				Target:    version.Versions,
+				Epoch:     42,
+				Binary: &Binary{
+					Name: "oasis-node",
+				},
+			},
+			shouldErr: true,
+		},
 		{
 			msg: "valid descriptor should not fail",
 			d: &Descriptor{
@@ -133,6 +161,20 @@ func TestValidateBasic(t *testing.T) {
 			},
 			shouldErr: false,
 		},
+		{
+			msg: "valid descriptor with binary should not fail",
+			d: &Descriptor{
+				Versioned: cbor.NewVersioned(LatestDescriptorVersion),
+				Handler:   "TestHandler",
+				Target:    version.Versions,
+				Epoch:     42,
+				Binary: &Binary{
+					Name: "oasis-node",
+					Hash: hash.NewFromBytes([]byte("oasis-node")),
+				},
+			},
+			shouldErr: false,
+		},
 	} {
 		err := tc.d.ValidateBasic()
 		if tc.shouldErr {
@@ -194,6 +236,16 @@ func TestEquals(t *testing.T) {
 			},
 			equals: false,
 		},
+		{
+			msg: "different binary should be equal",
+			d1: &Descriptor{
+				Binary: &Binary{
+					Name: "oasis-node",
+				},
+			},
+			d2:     &Descriptor{},
+			equals: true,
+		},
 		{
 			msg: "same descriptors should be equal",
 			d1: &Descriptor{
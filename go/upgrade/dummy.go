@@ -39,6 +39,10 @@ func (u *dummyUpgradeManager) ConsensusUpgrade(privateCtx interface{}, currentEp
 	return nil
 }
 
+func (u *dummyUpgradeManager) DryRunConsensusUpgrade(privateCtx interface{}, descriptor *api.Descriptor) error {
+	return api.ErrUpgradeNotFound
+}
+
 func (u *dummyUpgradeManager) Close() {
 }
 
@@ -0,0 +1,381 @@
+// Package fetcher implements fetching and staging of upgrade binaries.
+//
+// For pending upgrades which describe the upgrade binary, the fetcher downloads the binary and
+// its detached signature from the configured mirrors, verifies that the binary matches the hash
+// from the upgrade descriptor and that it is signed by one of the trusted signers, and stages it
+// so that the operator (or a supervisor) can switch to it.
+//
+// Note that binary descriptions are never part of governance proposals, so the hash of the
+// binary is not carried on-chain. It is taken from the upgrade descriptor submitted to the node
+// by its operator, and the detached signature made by one of the trusted signers is what ties the
+// fetched binary to the announced upgrade.
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/service"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/api"
+)
+
+const (
+	// CfgMirrors configures the mirrors from which upgrade binaries are fetched.
+	CfgMirrors = "upgrade.fetch.mirrors"
+	// CfgSigners configures the public keys of the trusted upgrade binary signers.
+	CfgSigners = "upgrade.fetch.signers"
+	// CfgInterval configures the interval at which pending upgrades are checked.
+	CfgInterval = "upgrade.fetch.interval"
+	// CfgMaxBinarySize configures the maximum size of a fetched upgrade binary.
+	CfgMaxBinarySize = "upgrade.fetch.max_binary_size"
+
+	// StagingDir is the subdirectory of the node's data directory which contains the staged
+	// upgrade binaries.
+	StagingDir = "upgrade-binaries"
+
+	// SignatureSuffix is the suffix of the file name of the detached (PEM-encoded) binary
+	// signature.
+	SignatureSuffix = ".sig"
+
+	// maxSignatureSize is the maximum size of the detached binary signature.
+	maxSignatureSize = 16 * 1024
+	// fetchTimeout is the timeout for fetching a binary from a single mirror.
+	fetchTimeout = 10 * time.Minute
+)
+
+// SignatureContext is the signature context used for signing upgrade binaries.
+var SignatureContext = signature.NewContext("oasis-core/upgrade: binary")
+
+// Flags has the configuration flags.
+var Flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+// Config is the upgrade binary fetcher configuration.
+type Config struct {
+	// Mirrors are the base URLs of the mirrors from which binaries are fetched.
+	Mirrors []*url.URL
+	// Signers are the public keys of the trusted binary signers.
+	Signers []signature.PublicKey
+	// StagingDir is the directory where verified binaries are staged.
+	StagingDir string
+	// Interval is the interval at which pending upgrades are checked.
+	Interval time.Duration
+	// MaxBinarySize is the maximum size of a fetched binary (in bytes).
+	MaxBinarySize int64
+}
+
+// Fetcher is the upgrade binary fetcher.
+type Fetcher struct {
+	service.BaseBackgroundService
+
+	cfg      Config
+	upgrader api.Backend
+	client   *http.Client
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	quitCh chan struct{}
+}
+
+// StagedPath returns the path where the binary of the given upgrade is staged.
+func (f *Fetcher) StagedPath(descriptor *api.Descriptor) string {
+	return filepath.Join(f.cfg.StagingDir, string(descriptor.Handler), descriptor.Binary.Name)
+}
+
+// isStaged checks whether a binary matching the given description has already been staged.
+func isStaged(fn string, binary *api.Binary) bool {
+	f, err := os.Open(fn)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	h, err := hashReader(f)
+	if err != nil {
+		return false
+	}
+	return h.Equal(&binary.Hash)
+}
+
+func hashReader(r io.Reader) (hash.Hash, error) {
+	b := hash.NewBuilder()
+	if _, err := io.Copy(b, r); err != nil {
+		return hash.Hash{}, err
+	}
+	return b.Build(), nil
+}
+
+func (f *Fetcher) get(ctx context.Context, u *url.URL) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func (f *Fetcher) verifySignature(sigPEM []byte, binaryHash hash.Hash) error {
+	var sig signature.Signature
+	if err := sig.UnmarshalPEM(sigPEM); err != nil {
+		return fmt.Errorf("malformed signature: %w", err)
+	}
+
+	var trusted bool
+	for _, pk := range f.cfg.Signers {
+		if pk.Equal(sig.PublicKey) {
+			trusted = true
+			break
+		}
+	}
+	if !trusted {
+		return fmt.Errorf("binary signed by untrusted signer: %s", sig.PublicKey)
+	}
+	if !sig.Verify(SignatureContext, binaryHash[:]) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// fetchFromMirror fetches the binary of the given upgrade from a mirror and stages it.
+func (f *Fetcher) fetchFromMirror(mirror *url.URL, descriptor *api.Descriptor) error {
+	ctx, cancel := context.WithTimeout(f.ctx, fetchTimeout)
+	defer cancel()
+
+	binaryURL := *mirror
+	binaryURL.Path = path.Join(binaryURL.Path, descriptor.Binary.Name)
+	sigURL := binaryURL
+	sigURL.Path += SignatureSuffix
+
+	// Fetch the detached signature.
+	body, err := f.get(ctx, &sigURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch signature: %w", err)
+	}
+	sigPEM, err := io.ReadAll(io.LimitReader(body, maxSignatureSize))
+	body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to fetch signature: %w", err)
+	}
+
+	// Fetch the binary into a temporary file next to its staged location.
+	stagedPath := f.StagedPath(descriptor)
+	if err = common.Mkdir(filepath.Dir(stagedPath)); err != nil {
+		return err
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(stagedPath), descriptor.Binary.Name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	if body, err = f.get(ctx, &binaryURL); err != nil {
+		return fmt.Errorf("failed to fetch binary: %w", err)
+	}
+	// Read at most one byte more than allowed in order to detect oversized binaries.
+	lr := &io.LimitedReader{R: body, N: f.cfg.MaxBinarySize + 1}
+	binaryHash, err := hashReader(io.TeeReader(lr, tmpFile))
+	body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to fetch binary: %w", err)
+	}
+	if lr.N == 0 {
+		return fmt.Errorf("binary exceeds the maximum size of %d bytes", f.cfg.MaxBinarySize)
+	}
+
+	// Verify the binary before staging it.
+	if !binaryHash.Equal(&descriptor.Binary.Hash) {
+		return fmt.Errorf("binary hash mismatch (expected: %s got: %s)", descriptor.Binary.Hash, binaryHash)
+	}
+	if err = f.verifySignature(sigPEM, binaryHash); err != nil {
+		return err
+	}
+
+	if err = tmpFile.Chmod(0o755); err != nil {
+		return err
+	}
+	if err = tmpFile.Sync(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), stagedPath)
+}
+
+func (f *Fetcher) fetch(descriptor *api.Descriptor) {
+	// The handler name is used as the staging subdirectory.
+	if handler := string(descriptor.Handler); handler == "." || handler == ".." || strings.ContainsAny(handler, `/\`) {
+		f.Logger.Error("refusing to fetch upgrade binary for handler with unsafe name",
+			"handler", descriptor.Handler,
+		)
+		return
+	}
+
+	stagedPath := f.StagedPath(descriptor)
+	if isStaged(stagedPath, descriptor.Binary) {
+		return
+	}
+
+	for _, mirror := range f.cfg.Mirrors {
+		err := f.fetchFromMirror(mirror, descriptor)
+		if err != nil {
+			f.Logger.Warn("failed to fetch upgrade binary",
+				"handler", descriptor.Handler,
+				"mirror", mirror,
+				"err", err,
+			)
+			continue
+		}
+
+		f.Logger.Info("upgrade binary verified and staged",
+			"handler", descriptor.Handler,
+			"mirror", mirror,
+			"path", stagedPath,
+		)
+		return
+	}
+
+	f.Logger.Error("failed to fetch upgrade binary from any mirror",
+		"handler", descriptor.Handler,
+	)
+}
+
+func (f *Fetcher) fetchPending() {
+	pending, err := f.upgrader.PendingUpgrades(f.ctx)
+	if err != nil {
+		f.Logger.Error("failed to get pending upgrades",
+			"err", err,
+		)
+		return
+	}
+
+	for _, pu := range pending {
+		if pu.Descriptor.Binary == nil {
+			continue
+		}
+		f.fetch(pu.Descriptor)
+	}
+}
+
+func (f *Fetcher) worker() {
+	defer close(f.quitCh)
+
+	ticker := time.NewTicker(f.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		f.fetchPending()
+
+		select {
+		case <-f.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Start starts the fetcher.
+func (f *Fetcher) Start() error {
+	if len(f.cfg.Mirrors) == 0 {
+		close(f.quitCh)
+		return nil
+	}
+
+	f.Logger.Info("upgrade binary fetcher is enabled",
+		"mirrors", f.cfg.Mirrors,
+		"staging_dir", f.cfg.StagingDir,
+	)
+
+	go f.worker()
+	return nil
+}
+
+// Stop halts the fetcher.
+func (f *Fetcher) Stop() {
+	f.cancel()
+}
+
+// Quit returns a channel that will be closed when the fetcher terminates.
+func (f *Fetcher) Quit() <-chan struct{} {
+	return f.quitCh
+}
+
+// NewWithConfig creates a new upgrade binary fetcher with the given configuration.
+func NewWithConfig(upgrader api.Backend, cfg Config) (*Fetcher, error) {
+	if len(cfg.Mirrors) > 0 && len(cfg.Signers) == 0 {
+		return nil, fmt.Errorf("upgrade/fetcher: at least one trusted signer must be configured")
+	}
+	if cfg.Interval <= 0 {
+		return nil, fmt.Errorf("upgrade/fetcher: interval must be positive")
+	}
+	if cfg.MaxBinarySize <= 0 {
+		return nil, fmt.Errorf("upgrade/fetcher: maximum binary size must be positive")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Fetcher{
+		BaseBackgroundService: *service.NewBaseBackgroundService("upgrade/fetcher"),
+		cfg:                   cfg,
+		upgrader:              upgrader,
+		client:                &http.Client{},
+		ctx:                   ctx,
+		cancel:                cancel,
+		quitCh:                make(chan struct{}),
+	}, nil
+}
+
+// New creates a new upgrade binary fetcher, configured via flags.
+func New(dataDir string, upgrader api.Backend) (*Fetcher, error) {
+	cfg := Config{
+		StagingDir:    filepath.Join(dataDir, StagingDir),
+		Interval:      viper.GetDuration(CfgInterval),
+		MaxBinarySize: int64(viper.GetSizeInBytes(CfgMaxBinarySize)),
+	}
+	for _, v := range viper.GetStringSlice(CfgMirrors) {
+		mirror, err := url.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("upgrade/fetcher: malformed mirror URL '%s': %w", v, err)
+		}
+		if mirror.Scheme != "https" && mirror.Scheme != "http" {
+			return nil, fmt.Errorf("upgrade/fetcher: unsupported mirror URL scheme: '%s'", mirror.Scheme)
+		}
+		cfg.Mirrors = append(cfg.Mirrors, mirror)
+	}
+	for _, v := range viper.GetStringSlice(CfgSigners) {
+		var pk signature.PublicKey
+		if err := pk.UnmarshalText([]byte(strings.TrimSpace(v))); err != nil {
+			return nil, fmt.Errorf("upgrade/fetcher: malformed signer public key '%s': %w", v, err)
+		}
+		cfg.Signers = append(cfg.Signers, pk)
+	}
+	return NewWithConfig(upgrader, cfg)
+}
+
+func init() {
+	Flags.StringSlice(CfgMirrors, nil, "base URLs of the mirrors from which upgrade binaries are fetched")
+	Flags.StringSlice(CfgSigners, nil, "public keys of the trusted upgrade binary signers")
+	Flags.Duration(CfgInterval, 10*time.Minute, "interval at which pending upgrades are checked for binaries to fetch")
+	Flags.String(CfgMaxBinarySize, "512mb", "maximum size of a fetched upgrade binary")
+
+	_ = viper.BindPFlags(Flags)
+}
@@ -0,0 +1,120 @@
+package fetcher
+
+import (
+	"context"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/upgrade"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/api"
+)
+
+func TestFetcher(t *testing.T) {
+	require := require.New(t)
+
+	binary := []byte("#!/bin/sh\necho upgraded\n")
+	binaryHash := hash.NewFromBytes(binary)
+
+	signer, err := memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner")
+	untrustedSigner, err := memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner")
+	sign := func(signer signature.Signer, message []byte) []byte {
+		sig, serr := signature.Sign(signer, SignatureContext, message)
+		require.NoError(serr, "Sign")
+		pem, serr := sig.MarshalPEM()
+		require.NoError(serr, "MarshalPEM")
+		return pem
+	}
+
+	// Mirrors serving a corrupted binary, a binary signed by an untrusted signer, an oversized
+	// binary and a valid binary.
+	newMirror := func(binary, sig []byte) *url.URL {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/releases/oasis-node", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(binary)
+		})
+		mux.HandleFunc("/releases/oasis-node"+SignatureSuffix, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(sig)
+		})
+		srv := httptest.NewServer(mux)
+		t.Cleanup(srv.Close)
+
+		u, perr := url.Parse(srv.URL + "/releases")
+		require.NoError(perr, "url.Parse")
+		return u
+	}
+	corruptedBinary := append([]byte{}, binary...)
+	corruptedBinary[0] ^= 0xff
+	oversizedBinary := append(append([]byte{}, binary...), make([]byte, len(binary))...)
+	mirrors := []*url.URL{
+		newMirror(corruptedBinary, sign(signer, binaryHash[:])),
+		newMirror(binary, sign(untrustedSigner, binaryHash[:])),
+		newMirror(oversizedBinary, sign(signer, binaryHash[:])),
+		newMirror(binary, sign(signer, binaryHash[:])),
+	}
+
+	dataDir := t.TempDir()
+	store, err := persistent.NewCommonStore(dataDir)
+	require.NoError(err, "NewCommonStore")
+	defer store.Close()
+	upgrader, err := upgrade.New(store, dataDir)
+	require.NoError(err, "upgrade.New")
+	defer upgrader.Close()
+
+	desc := &api.Descriptor{
+		Versioned: cbor.NewVersioned(api.LatestDescriptorVersion),
+		Handler:   "test-fetcher",
+		Target:    version.Versions,
+		Epoch:     42,
+		Binary: &api.Binary{
+			Name: "oasis-node",
+			Hash: binaryHash,
+		},
+	}
+	err = upgrader.SubmitDescriptor(context.Background(), desc)
+	require.NoError(err, "SubmitDescriptor")
+
+	f, err := NewWithConfig(upgrader, Config{
+		Mirrors:       mirrors,
+		Signers:       []signature.PublicKey{signer.Public()},
+		StagingDir:    filepath.Join(dataDir, StagingDir),
+		Interval:      time.Hour,
+		MaxBinarySize: int64(len(binary)),
+	})
+	require.NoError(err, "NewWithConfig")
+
+	stagedPath := f.StagedPath(desc)
+	for _, mirror := range mirrors[:3] {
+		err = f.fetchFromMirror(mirror, desc)
+		require.Error(err, "fetching an invalid binary should fail")
+		_, err = os.Stat(stagedPath)
+		require.True(os.IsNotExist(err), "invalid binaries should not be staged")
+	}
+
+	f.fetchPending()
+	staged, err := os.ReadFile(stagedPath)
+	require.NoError(err, "ReadFile")
+	require.Equal(binary, staged, "the verified binary should be staged")
+	fi, err := os.Stat(stagedPath)
+	require.NoError(err, "Stat")
+	require.EqualValues(0o755, fi.Mode().Perm(), "the staged binary should be executable")
+
+	entries, err := os.ReadDir(filepath.Dir(stagedPath))
+	require.NoError(err, "ReadDir")
+	require.Len(entries, 1, "temporary files should be removed")
+}
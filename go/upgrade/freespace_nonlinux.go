@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package upgrade
+
+import "fmt"
+
+func freeSpace(path string) (uint64, error) {
+	return 0, fmt.Errorf("free space check not supported on this platform")
+}
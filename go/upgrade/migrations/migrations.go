@@ -3,9 +3,12 @@ package migrations
 
 import (
 	"fmt"
+	"path/filepath"
 	"sync"
 
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/abci"
+	tmcommon "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/common"
 	upgradeApi "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
@@ -17,6 +20,12 @@ const (
 var (
 	registeredHandlers sync.Map
 
+	// ConsensusStatePaths are the paths (relative to the node's data directory) of the consensus
+	// application state, for use by handlers implementing SnapshotHandler.
+	ConsensusStatePaths = []string{
+		filepath.Join(tmcommon.StateDir, abci.AppStateDir),
+	}
+
 	// ErrMissingMigrationHandler is error returned when a migration handler is not registered.
 	ErrMissingMigrationHandler = fmt.Errorf("missing migration handler")
 )
@@ -37,6 +46,37 @@ type Handler interface {
 	ConsensusUpgrade(*Context, interface{}) error
 }
 
+// StepHandler is the interface implemented by migration handlers whose startup upgrade is broken
+// into resumable steps. All steps are performed before the StartupUpgrade method is called.
+type StepHandler interface {
+	Handler
+
+	// StartupSteps returns the number of steps of the startup upgrade. It must return the same
+	// number when called again after the node is restarted.
+	StartupSteps(*Context) (uint64, error)
+
+	// StartupStep performs the given step (starting at zero) of the startup upgrade.
+	//
+	// Steps are performed in order and the progress is persisted after each step, so in case the
+	// node is restarted, the upgrade resumes with the first step that did not complete. As the
+	// node may be restarted after a step completed but before its progress was persisted, steps
+	// must be idempotent.
+	StartupStep(*Context, uint64) error
+}
+
+// SnapshotHandler is the interface implemented by migration handlers that need a snapshot of
+// (parts of) the node's data directory to be taken before the startup upgrade is performed, so
+// that a failed upgrade can be rolled back.
+//
+// As taking a snapshot copies all of the snapshotted state, handlers should only request one in
+// case a failed upgrade can not be recovered from otherwise.
+type SnapshotHandler interface {
+	Handler
+
+	// SnapshotPaths returns the paths (relative to the node's data directory) to snapshot.
+	SnapshotPaths(*Context) []string
+}
+
 // Context defines the common context used by migration handlers.
 type Context struct {
 	// Upgrade is the currently pending upgrade structure.
@@ -0,0 +1,310 @@
+package upgrade
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/api"
+)
+
+const (
+	// CfgPreflightBinary configures the path to the binary that will perform the upgrade.
+	CfgPreflightBinary = "upgrade.preflight.binary"
+	// CfgPreflightMinFreeSpace configures the minimum free space in the data directory required
+	// to perform the upgrade.
+	CfgPreflightMinFreeSpace = "upgrade.preflight.min_free_space"
+	// CfgPreflightRuntimePaths configures the paths to the runtimes required to perform the
+	// upgrade.
+	CfgPreflightRuntimePaths = "upgrade.preflight.runtime_paths"
+	// CfgPreflightRuntimeHashes configures the expected hashes of the runtimes required to perform
+	// the upgrade.
+	CfgPreflightRuntimeHashes = "upgrade.preflight.runtime_hashes"
+	// CfgPreflightInterval configures the interval at which the preflight checks are performed.
+	CfgPreflightInterval = "upgrade.preflight.interval"
+
+	preflightBinaryTimeout = 10 * time.Second
+)
+
+// Flags has the configuration flags.
+var Flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+var upgradePreflightFailedChecks = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "oasis_upgrade_preflight_failed_checks",
+		Help: "Number of failed preflight checks of a pending upgrade.",
+	},
+	[]string{"handler"},
+)
+
+type preflightConfig struct {
+	binary        string
+	minFreeSpace  uint64
+	runtimePaths  map[common.Namespace]string
+	runtimeHashes map[common.Namespace]hash.Hash
+	interval      time.Duration
+}
+
+func (cfg *preflightConfig) enabled() bool {
+	return cfg.binary != "" || cfg.minFreeSpace > 0 || len(cfg.runtimePaths) > 0
+}
+
+func newPreflightConfig() (*preflightConfig, error) {
+	cfg := &preflightConfig{
+		binary:        viper.GetString(CfgPreflightBinary),
+		minFreeSpace:  uint64(viper.GetSizeInBytes(CfgPreflightMinFreeSpace)),
+		runtimePaths:  make(map[common.Namespace]string),
+		runtimeHashes: make(map[common.Namespace]hash.Hash),
+		interval:      viper.GetDuration(CfgPreflightInterval),
+	}
+	for runtimeID, path := range viper.GetStringMapString(CfgPreflightRuntimePaths) {
+		var id common.Namespace
+		if err := id.UnmarshalHex(runtimeID); err != nil {
+			return nil, fmt.Errorf("malformed runtime identifier '%s': %w", runtimeID, err)
+		}
+		cfg.runtimePaths[id] = path
+	}
+	for runtimeID, rawHash := range viper.GetStringMapString(CfgPreflightRuntimeHashes) {
+		var id common.Namespace
+		if err := id.UnmarshalHex(runtimeID); err != nil {
+			return nil, fmt.Errorf("malformed runtime identifier '%s': %w", runtimeID, err)
+		}
+		if _, ok := cfg.runtimePaths[id]; !ok {
+			return nil, fmt.Errorf("runtime hash configured for runtime without path: %s", id)
+		}
+		var h hash.Hash
+		if err := h.UnmarshalHex(rawHash); err != nil {
+			return nil, fmt.Errorf("malformed hash for runtime '%s': %w", id, err)
+		}
+		cfg.runtimeHashes[id] = h
+	}
+	if cfg.enabled() && cfg.interval <= 0 {
+		return nil, fmt.Errorf("preflight check interval must be positive")
+	}
+	return cfg, nil
+}
+
+// binaryVersions runs the given binary and parses the protocol versions it reports.
+func binaryVersions(binary string) (*version.ProtocolVersions, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), preflightBinaryTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, binary, "--version").Output() // nolint: gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to run binary: %w", err)
+	}
+
+	var (
+		versions version.ProtocolVersions
+		found    int
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		parts := strings.SplitN(strings.TrimSpace(scanner.Text()), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name, value := parts[0], parts[1]
+		var dst *version.Version
+		switch name {
+		case "Consensus protocol version":
+			dst = &versions.ConsensusProtocol
+		case "Host protocol version":
+			dst = &versions.RuntimeHostProtocol
+		case "Committee protocol version":
+			dst = &versions.RuntimeCommitteeProtocol
+		default:
+			continue
+		}
+		if *dst, err = version.FromString(strings.TrimSpace(value)); err != nil {
+			return nil, fmt.Errorf("malformed %s: %w", strings.ToLower(name), err)
+		}
+		found++
+	}
+	if found != 3 {
+		return nil, fmt.Errorf("binary did not report its protocol versions")
+	}
+	return &versions, nil
+}
+
+// fileHash returns the hash of the given file's contents.
+func fileHash(fn string) (hash.Hash, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return hash.Hash{}, err
+	}
+	defer f.Close()
+
+	b := hash.NewBuilder()
+	if _, err = io.Copy(b, f); err != nil {
+		return hash.Hash{}, err
+	}
+	return b.Build(), nil
+}
+
+// checkFileHash checks that the given file has the expected hash.
+func checkFileHash(fn string, expected hash.Hash) error {
+	h, err := fileHash(fn)
+	if err != nil {
+		return err
+	}
+	if !h.Equal(&expected) {
+		return fmt.Errorf("hash mismatch (expected: %s got: %s)", expected, h)
+	}
+	return nil
+}
+
+// preflightChecks performs the preflight checks for the given upgrade. The checks are always
+// performed against the current state of the configured files, their results are never cached.
+func (u *upgradeManager) preflightChecks(descriptor *api.Descriptor) []api.PreflightCheck {
+	var checks []api.PreflightCheck
+	check := func(name string, err error) {
+		c := api.PreflightCheck{
+			Name:   name,
+			Passed: err == nil,
+		}
+		if err != nil {
+			c.Reason = err.Error()
+		}
+		checks = append(checks, c)
+	}
+
+	if u.preflight.binary != "" {
+		var err error
+		if descriptor.Binary != nil {
+			err = checkFileHash(u.preflight.binary, descriptor.Binary.Hash)
+		}
+		if err == nil {
+			var versions *version.ProtocolVersions
+			if versions, err = binaryVersions(u.preflight.binary); err == nil {
+				err = descriptor.EnsureCompatibleWith(*versions)
+			}
+		}
+		check("binary", err)
+	}
+
+	if u.preflight.minFreeSpace > 0 {
+		free, err := freeSpace(u.dataDir)
+		if err == nil && free < u.preflight.minFreeSpace {
+			err = fmt.Errorf("insufficient free space: %d bytes (required: %d bytes)", free, u.preflight.minFreeSpace)
+		}
+		check("disk_space", err)
+	}
+
+	for id, path := range u.preflight.runtimePaths {
+		fi, err := os.Stat(path)
+		if err == nil && !fi.Mode().IsRegular() {
+			err = fmt.Errorf("not a regular file: %s", path)
+		}
+		if expected, ok := u.preflight.runtimeHashes[id]; ok && err == nil {
+			err = checkFileHash(path, expected)
+		}
+		check("runtime/"+id.String(), err)
+	}
+
+	return checks
+}
+
+// reportPreflightChecks logs the failed preflight checks and updates the metrics, returning the
+// number of failed checks.
+func (u *upgradeManager) reportPreflightChecks(descriptor *api.Descriptor, checks []api.PreflightCheck, level func(string, ...interface{})) int {
+	var failed int
+	for _, c := range checks {
+		if c.Passed {
+			continue
+		}
+		failed++
+		level("upgrade preflight check failed",
+			"handler", descriptor.Handler,
+			"check", c.Name,
+			"reason", c.Reason,
+		)
+	}
+	upgradePreflightFailedChecks.With(prometheus.Labels{"handler": string(descriptor.Handler)}).Set(float64(failed))
+	return failed
+}
+
+// runPreflightChecks performs the preflight checks for all upgrades for which the upgrade epoch
+// has not yet been reached.
+//
+// The results are only kept in memory for reporting, as the checks are performed again once
+// the upgrade epoch is reached.
+func (u *upgradeManager) runPreflightChecks() {
+	var pending []*api.PendingUpgrade
+	u.Lock()
+	for _, pu := range u.pending {
+		if pu.UpgradeHeight == api.InvalidUpgradeHeight {
+			pending = append(pending, pu)
+		}
+	}
+	u.Unlock()
+
+	results := make(map[*api.PendingUpgrade][]api.PreflightCheck)
+	for _, pu := range pending {
+		checks := u.preflightChecks(pu.Descriptor)
+		u.reportPreflightChecks(pu.Descriptor, checks, u.logger.Warn)
+		results[pu] = checks
+	}
+
+	u.Lock()
+	for pu := range results {
+		if pu.UpgradeHeight != api.InvalidUpgradeHeight {
+			delete(results, pu)
+		}
+	}
+	u.preflightResults = results
+	u.Unlock()
+}
+
+// NOTE: Assumes lock is held.
+func (u *upgradeManager) upgradePreflightChecksLocked(pu *api.PendingUpgrade) {
+	if !u.preflight.enabled() {
+		return
+	}
+
+	checks := u.preflightChecks(pu.Descriptor)
+	if u.reportPreflightChecks(pu.Descriptor, checks, u.logger.Error) > 0 {
+		u.logger.Error("upgrade epoch reached but upgrade preflight checks failed",
+			"handler", pu.Descriptor.Handler,
+		)
+	}
+	delete(u.preflightResults, pu)
+}
+
+func (u *upgradeManager) preflightWorker() {
+	ticker := time.NewTicker(u.preflight.interval)
+	defer ticker.Stop()
+
+	for {
+		u.runPreflightChecks()
+
+		select {
+		case <-u.quitCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func init() {
+	Flags.String(CfgPreflightBinary, "", "path to the binary that will perform the upgrade (checked before the upgrade epoch)")
+	Flags.String(CfgPreflightMinFreeSpace, "0", "minimum free space in the data directory required to perform the upgrade (0 = disabled)")
+	Flags.StringToString(CfgPreflightRuntimePaths, nil, "paths to the runtimes required to perform the upgrade (format: <rt1-ID>=<path>,<rt2-ID>=<path>)")
+	Flags.StringToString(CfgPreflightRuntimeHashes, nil, "expected SHA-512/256 hashes of the runtimes required to perform the upgrade (format: <rt1-ID>=<hex>,<rt2-ID>=<hex>)")
+	Flags.Duration(CfgPreflightInterval, 5*time.Minute, "interval at which the upgrade preflight checks are performed")
+
+	_ = viper.BindPFlags(Flags)
+}
@@ -0,0 +1,260 @@
+package upgrade
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/api"
+)
+
+const (
+	// SnapshotDir is the subdirectory of the node's data directory which contains the snapshot of
+	// the state taken before performing an upgrade whose handler requested one.
+	SnapshotDir = "upgrade-snapshot"
+
+	snapshotMetadataFile = "metadata.cbor"
+)
+
+// snapshotMetadata describes a pre-upgrade snapshot.
+type snapshotMetadata struct {
+	// Descriptor is the descriptor of the upgrade the snapshot was taken for.
+	Descriptor *api.Descriptor `json:"descriptor"`
+	// Paths are the snapshotted paths (relative to the node's data directory).
+	Paths []string `json:"paths"`
+}
+
+// readSnapshotMetadata returns the metadata of the snapshot in the given data directory.
+func readSnapshotMetadata(dataDir string) (*snapshotMetadata, error) {
+	raw, err := os.ReadFile(filepath.Join(dataDir, SnapshotDir, snapshotMetadataFile))
+	switch {
+	case err == nil:
+	case os.IsNotExist(err):
+		return nil, api.ErrNoSnapshot
+	default:
+		return nil, err
+	}
+
+	var meta snapshotMetadata
+	if err = cbor.Unmarshal(raw, &meta); err != nil {
+		return nil, fmt.Errorf("malformed snapshot metadata: %w", err)
+	}
+	if meta.Descriptor == nil {
+		return nil, fmt.Errorf("malformed snapshot metadata: missing descriptor")
+	}
+	for _, p := range meta.Paths {
+		if !isLocalPath(p) {
+			return nil, fmt.Errorf("malformed snapshot metadata: invalid path: %s", p)
+		}
+	}
+	return &meta, nil
+}
+
+// NOTE: Assumes lock is held.
+func (u *upgradeManager) snapshotLocked(descriptor *api.Descriptor, paths []string) error {
+	for _, p := range paths {
+		if !isLocalPath(p) {
+			return fmt.Errorf("invalid snapshot path: %s", p)
+		}
+	}
+
+	existing, err := readSnapshotMetadata(u.dataDir)
+	switch err {
+	case nil:
+		if existing.Descriptor.Equals(descriptor) {
+			// The snapshot must not be overwritten when the startup stage is retried as the
+			// state may have already been modified by the failed attempt.
+			u.logger.Info("pre-upgrade state snapshot already exists",
+				"handler", descriptor.Handler,
+			)
+			return nil
+		}
+	case api.ErrNoSnapshot:
+	default:
+		return fmt.Errorf("failed to read existing state snapshot: %w", err)
+	}
+
+	u.logger.Info("taking pre-upgrade state snapshot",
+		"handler", descriptor.Handler,
+	)
+
+	// Build the snapshot in a temporary directory so that an interrupted snapshot is never
+	// mistaken for a complete one.
+	snapshotDir := filepath.Join(u.dataDir, SnapshotDir)
+	tmpDir := snapshotDir + ".tmp"
+	if err = os.RemoveAll(tmpDir); err != nil {
+		return fmt.Errorf("failed to remove stale snapshot: %w", err)
+	}
+	for _, p := range paths {
+		if err = copyDir(filepath.Join(u.dataDir, p), filepath.Join(tmpDir, p)); err != nil {
+			return fmt.Errorf("failed to snapshot %s: %w", p, err)
+		}
+	}
+	if err = os.MkdirAll(tmpDir, 0o700); err != nil {
+		return err
+	}
+	meta := &snapshotMetadata{
+		Descriptor: descriptor,
+		Paths:      paths,
+	}
+	if err = os.WriteFile(filepath.Join(tmpDir, snapshotMetadataFile), cbor.Marshal(meta), 0o600); err != nil {
+		return fmt.Errorf("failed to write snapshot metadata: %w", err)
+	}
+
+	if err = os.RemoveAll(snapshotDir); err != nil {
+		return fmt.Errorf("failed to remove previous snapshot: %w", err)
+	}
+	return os.Rename(tmpDir, snapshotDir)
+}
+
+// NOTE: Assumes lock is held.
+func (u *upgradeManager) removeSnapshotLocked(descriptor *api.Descriptor) {
+	existing, err := readSnapshotMetadata(u.dataDir)
+	if err != nil || !existing.Descriptor.Equals(descriptor) {
+		return
+	}
+	if err = os.RemoveAll(filepath.Join(u.dataDir, SnapshotDir)); err != nil {
+		u.logger.Warn("failed to remove pre-upgrade state snapshot",
+			"handler", descriptor.Handler,
+			"err", err,
+		)
+	}
+}
+
+// Rollback restores the state from the snapshot taken before the upgrade was performed and
+// removes the pending upgrade, returning its descriptor.
+//
+// The node must not be running while its state is being rolled back.
+func Rollback(store *persistent.CommonStore, dataDir string) (*api.Descriptor, error) {
+	logger := logging.GetLogger(api.ModuleName)
+
+	meta, err := readSnapshotMetadata(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	descriptor := meta.Descriptor
+	svcStore, err := store.GetServiceStore(api.ModuleName)
+	if err != nil {
+		return nil, err
+	}
+	defer svcStore.Close()
+
+	// Restore the snapshot. Paths that are missing from the snapshot have already been restored
+	// by an interrupted rollback.
+	snapshotDir := filepath.Join(dataDir, SnapshotDir)
+	for _, p := range meta.Paths {
+		src := filepath.Join(snapshotDir, p)
+		if _, err = os.Stat(src); os.IsNotExist(err) {
+			continue
+		}
+		dst := filepath.Join(dataDir, p)
+		if err = os.RemoveAll(dst); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", p, err)
+		}
+		if err = os.Rename(src, dst); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", p, err)
+		}
+		logger.Info("restored state from pre-upgrade snapshot",
+			"path", p,
+		)
+	}
+
+	// Clear the pending upgrade.
+	var pending, remaining []*api.PendingUpgrade
+	switch err = svcStore.GetCBOR(metadataStoreKey, &pending); err {
+	case nil, persistent.ErrNotFound:
+	default:
+		return nil, fmt.Errorf("can't decode stored upgrade descriptors: %w", err)
+	}
+	for _, pu := range pending {
+		if pu.Descriptor.Equals(descriptor) {
+			continue
+		}
+		remaining = append(remaining, pu)
+	}
+	if len(remaining) == 0 {
+		err = svcStore.Delete(metadataStoreKey)
+		if err == persistent.ErrNotFound {
+			err = nil
+		}
+	} else {
+		err = svcStore.PutCBOR(metadataStoreKey, remaining)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to clear pending upgrade: %w", err)
+	}
+
+	if err = os.RemoveAll(snapshotDir); err != nil {
+		return nil, fmt.Errorf("failed to remove snapshot: %w", err)
+	}
+	return descriptor, nil
+}
+
+// isLocalPath checks whether the given path is relative and stays within the directory it is
+// joined to.
+func isLocalPath(p string) bool {
+	if p == "" || filepath.IsAbs(p) {
+		return false
+	}
+	clean := filepath.Clean(p)
+	return clean != ".." && !strings.HasPrefix(clean, ".."+string(filepath.Separator))
+}
+
+// copyDir recursively copies the src directory to dst. A missing src directory is ignored.
+func copyDir(src, dst string) error {
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return nil
+	}
+
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			return fmt.Errorf("unsupported file type: %s", path)
+		}
+	})
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err = out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -4,6 +4,11 @@
 // running or be restarted up to the point when the consensus layer reaches
 // the upgrade epoch. The new node may not be started until the old node has
 // reached the upgrade epoch.
+//
+// Before the first upgrade stage is performed, a snapshot of the state requested
+// by the upgrade handler (if any) is taken. In case the upgrade handler fails
+// halfway, the snapshot can be restored via Rollback, which also clears the
+// pending upgrade.
 package upgrade
 
 import (
@@ -11,6 +16,8 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
@@ -23,6 +30,21 @@ var (
 	_ api.Backend = (*upgradeManager)(nil)
 
 	metadataStoreKey = []byte("descriptors")
+
+	upgradeProgress = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_upgrade_progress_percent",
+			Help: "Progress of the upgrade stage being performed in multiple steps (percent).",
+		},
+		[]string{"handler"},
+	)
+
+	upgradeCollectors = []prometheus.Collector{
+		upgradeProgress,
+		upgradePreflightFailedChecks,
+	}
+
+	metricsOnce sync.Once
 )
 
 type upgradeManager struct {
@@ -33,6 +55,12 @@ type upgradeManager struct {
 
 	dataDir string
 
+	preflight *preflightConfig
+	// preflightResults are the results of the latest periodic preflight checks. They are only
+	// used for reporting and are never persisted.
+	preflightResults map[*api.PendingUpgrade][]api.PreflightCheck
+	quitCh           chan struct{}
+
 	logger *logging.Logger
 }
 
@@ -70,7 +98,14 @@ func (u *upgradeManager) PendingUpgrades(ctx context.Context) ([]*api.PendingUpg
 	u.Lock()
 	defer u.Unlock()
 
-	return append([]*api.PendingUpgrade{}, u.pending...), nil
+	// Return copies as the preflight check results are not part of the stored pending upgrades.
+	pending := make([]*api.PendingUpgrade, 0, len(u.pending))
+	for _, pu := range u.pending {
+		cpu := *pu
+		cpu.Preflight = u.preflightResults[pu]
+		pending = append(pending, &cpu)
+	}
+	return pending, nil
 }
 
 // Implements api.Backend.
@@ -157,6 +192,9 @@ func (u *upgradeManager) checkStatus() error {
 	}
 
 	for _, pu := range u.pending {
+		// Preflight check results were persisted by previous versions, but may be stale.
+		pu.Preflight = nil
+
 		if pu.IsCompleted() {
 			continue
 		}
@@ -214,6 +252,7 @@ func (u *upgradeManager) flushDescriptorLocked() error {
 			u.logger.Info("upgrade completed, removing state",
 				"handler", pu.Descriptor.Handler,
 			)
+			u.removeSnapshotLocked(pu.Descriptor)
 			continue
 		}
 		pending = append(pending, pu)
@@ -238,15 +277,30 @@ func (u *upgradeManager) StartupUpgrade() error {
 			continue
 		}
 
+		migrationCtx := migrations.NewContext(pu, u.dataDir)
+		handler, err := migrations.GetHandler(pu.Descriptor.Handler)
+		if err != nil {
+			return err
+		}
+
+		// Snapshot the state requested by the handler so that a failed upgrade can be rolled back.
+		if snapshotHandler, ok := handler.(migrations.SnapshotHandler); ok {
+			if paths := snapshotHandler.SnapshotPaths(migrationCtx); len(paths) > 0 {
+				if err = u.snapshotLocked(pu.Descriptor, paths); err != nil {
+					return fmt.Errorf("failed to snapshot state before upgrade: %w", err)
+				}
+			}
+		}
+
 		// Execute the statup stage.
 		u.logger.Warn("performing startup upgrade",
 			"handler", pu.Descriptor.Handler,
 			logging.LogEvent, api.LogEventStartupUpgrade,
 		)
-		migrationCtx := migrations.NewContext(pu, u.dataDir)
-		handler, err := migrations.GetHandler(pu.Descriptor.Handler)
-		if err != nil {
-			return err
+		if stepHandler, ok := handler.(migrations.StepHandler); ok {
+			if err = u.startupStepsLocked(migrationCtx, pu, stepHandler); err != nil {
+				return err
+			}
 		}
 		if err := handler.StartupUpgrade(migrationCtx); err != nil {
 			return err
@@ -257,6 +311,53 @@ func (u *upgradeManager) StartupUpgrade() error {
 	return u.flushDescriptorLocked()
 }
 
+// NOTE: Assumes lock is held.
+func (u *upgradeManager) startupStepsLocked(ctx *migrations.Context, pu *api.PendingUpgrade, handler migrations.StepHandler) error {
+	totalSteps, err := handler.StartupSteps(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the number of startup upgrade steps: %w", err)
+	}
+
+	switch {
+	case pu.Progress == nil || pu.Progress.Stage != api.UpgradeStageStartup:
+		pu.Progress = &api.UpgradeProgress{
+			Stage:      api.UpgradeStageStartup,
+			TotalSteps: totalSteps,
+		}
+	case pu.Progress.TotalSteps != totalSteps:
+		return fmt.Errorf("number of startup upgrade steps changed (from %d to %d)", pu.Progress.TotalSteps, totalSteps)
+	default:
+		u.logger.Warn("resuming startup upgrade",
+			"handler", pu.Descriptor.Handler,
+			"completed_steps", pu.Progress.CompletedSteps,
+			"total_steps", totalSteps,
+		)
+	}
+
+	labels := prometheus.Labels{"handler": string(pu.Descriptor.Handler)}
+	upgradeProgress.With(labels).Set(pu.Progress.Percentage())
+	for pu.Progress.CompletedSteps < totalSteps {
+		step := pu.Progress.CompletedSteps
+		if err = handler.StartupStep(ctx, step); err != nil {
+			return fmt.Errorf("startup upgrade step %d failed: %w", step, err)
+		}
+
+		pu.Progress.CompletedSteps++
+		if err = u.flushDescriptorLocked(); err != nil {
+			return err
+		}
+		upgradeProgress.With(labels).Set(pu.Progress.Percentage())
+
+		u.logger.Info("startup upgrade step completed",
+			"handler", pu.Descriptor.Handler,
+			"step", step,
+			"total_steps", totalSteps,
+			"progress", fmt.Sprintf("%.1f%%", pu.Progress.Percentage()),
+		)
+	}
+	return nil
+}
+
 // Implements api.Backend.
 func (u *upgradeManager) ConsensusUpgrade(privateCtx interface{}, currentEpoch beacon.EpochTime, currentHeight int64) error {
 	u.Lock()
@@ -273,6 +374,9 @@ func (u *upgradeManager) ConsensusUpgrade(privateCtx interface{}, currentEpoch b
 			if err := u.flushDescriptorLocked(); err != nil {
 				return err
 			}
+			// Verify the prerequisites once more, as they may have changed since the last
+			// periodic preflight check.
+			u.upgradePreflightChecksLocked(pu)
 			return api.ErrStopForUpgrade
 		}
 
@@ -306,8 +410,43 @@ func (u *upgradeManager) ConsensusUpgrade(privateCtx interface{}, currentEpoch b
 	return u.flushDescriptorLocked()
 }
 
+// Implements api.Backend.
+func (u *upgradeManager) DryRunConsensusUpgrade(privateCtx interface{}, descriptor *api.Descriptor) error {
+	if descriptor == nil {
+		return api.ErrBadDescriptor
+	}
+
+	// Make a copy of the pending upgrade so the handler can not modify its state.
+	var pu *api.PendingUpgrade
+	u.Lock()
+	for _, p := range u.pending {
+		if p.Descriptor.Equals(descriptor) {
+			pu = &api.PendingUpgrade{}
+			*pu = *p
+			break
+		}
+	}
+	u.Unlock()
+	if pu == nil {
+		return api.ErrUpgradeNotFound
+	}
+
+	u.logger.Info("performing consensus upgrade dry run",
+		"handler", pu.Descriptor.Handler,
+	)
+
+	migrationCtx := migrations.NewContext(pu, u.dataDir)
+	handler, err := migrations.GetHandler(pu.Descriptor.Handler)
+	if err != nil {
+		return err
+	}
+	return handler.ConsensusUpgrade(migrationCtx, privateCtx)
+}
+
 // Implements api.Backend.
 func (u *upgradeManager) Close() {
+	close(u.quitCh)
+
 	u.Lock()
 	defer u.Unlock()
 	_ = u.flushDescriptorLocked()
@@ -318,19 +457,33 @@ func (u *upgradeManager) Close() {
 // pending upgrade descriptors; if this node is not the one intended to be run according
 // to the loaded descriptor, New will return an error.
 func New(store *persistent.CommonStore, dataDir string) (api.Backend, error) {
+	preflight, err := newPreflightConfig()
+	if err != nil {
+		return nil, err
+	}
 	svcStore, err := store.GetServiceStore(api.ModuleName)
 	if err != nil {
 		return nil, err
 	}
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(upgradeCollectors...)
+	})
+
 	upgrader := &upgradeManager{
-		store:   svcStore,
-		dataDir: dataDir,
-		logger:  logging.GetLogger(api.ModuleName),
+		store:     svcStore,
+		dataDir:   dataDir,
+		preflight: preflight,
+		quitCh:    make(chan struct{}),
+		logger:    logging.GetLogger(api.ModuleName),
 	}
 
 	if err := upgrader.checkStatus(); err != nil {
 		return nil, err
 	}
 
+	if preflight.enabled() {
+		go upgrader.preflightWorker()
+	}
+
 	return upgrader, nil
 }
@@ -0,0 +1,382 @@
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
+)
+
+const testStepHandlerName = "__test-steps"
+
+type testStepHandler struct {
+	totalSteps uint64
+	failAt     uint64
+	steps      []uint64
+	startup    int
+}
+
+func (h *testStepHandler) StartupSteps(ctx *migrations.Context) (uint64, error) {
+	return h.totalSteps, nil
+}
+
+func (h *testStepHandler) StartupStep(ctx *migrations.Context, step uint64) error {
+	if step == h.failAt {
+		return fmt.Errorf("step %d failed", step)
+	}
+	h.steps = append(h.steps, step)
+	return nil
+}
+
+func (h *testStepHandler) StartupUpgrade(ctx *migrations.Context) error {
+	h.startup++
+	return nil
+}
+
+func (h *testStepHandler) ConsensusUpgrade(ctx *migrations.Context, privateCtx interface{}) error {
+	return nil
+}
+
+func TestSubmitDescriptorBinary(t *testing.T) {
+	require := require.New(t)
+
+	dataDir := t.TempDir()
+	store, err := persistent.NewCommonStore(dataDir)
+	require.NoError(err, "NewCommonStore")
+	defer store.Close()
+	upgrader, err := New(store, dataDir)
+	require.NoError(err, "New")
+	defer upgrader.Close()
+
+	// A locally submitted descriptor describing the binary.
+	desc := &api.Descriptor{
+		Versioned: cbor.NewVersioned(api.LatestDescriptorVersion),
+		Handler:   testStepHandlerName,
+		Target:    version.Versions,
+		Epoch:     1,
+		Binary: &api.Binary{
+			Name: "oasis-node",
+			Hash: hash.NewFromBytes([]byte("binary")),
+		},
+	}
+	err = upgrader.SubmitDescriptor(context.Background(), desc)
+	require.NoError(err, "SubmitDescriptor")
+
+	// The same upgrade submitted via governance can not describe the binary.
+	govDesc := *desc
+	govDesc.Binary = nil
+	err = upgrader.SubmitDescriptor(context.Background(), &govDesc)
+	require.ErrorIs(err, api.ErrAlreadyPending, "the same upgrade should already be pending")
+
+	pending, err := upgrader.PendingUpgrades(context.Background())
+	require.NoError(err, "PendingUpgrades")
+	require.Len(pending, 1, "there should be a single pending upgrade")
+	require.Equal(desc.Binary, pending[0].Descriptor.Binary, "binary description should be kept")
+}
+
+func TestStartupUpgradeSteps(t *testing.T) {
+	require := require.New(t)
+
+	handler := &testStepHandler{
+		totalSteps: 5,
+		failAt:     3,
+	}
+	migrations.Register(testStepHandlerName, handler)
+
+	dataDir := t.TempDir()
+	newUpgrader := func() (*upgradeManager, func()) {
+		store, err := persistent.NewCommonStore(dataDir)
+		require.NoError(err, "NewCommonStore")
+		upgrader, err := New(store, dataDir)
+		require.NoError(err, "New")
+		return upgrader.(*upgradeManager), func() {
+			upgrader.Close()
+			store.Close()
+		}
+	}
+
+	// Submit an upgrade and pretend the upgrade epoch has been reached.
+	upgrader, closeFn := newUpgrader()
+	desc := &api.Descriptor{
+		Versioned: cbor.NewVersioned(api.LatestDescriptorVersion),
+		Handler:   testStepHandlerName,
+		Target:    version.Versions,
+		Epoch:     1,
+	}
+	err := upgrader.SubmitDescriptor(context.Background(), desc)
+	require.NoError(err, "SubmitDescriptor")
+	upgrader.pending[0].UpgradeHeight = 10
+	closeFn()
+
+	// The first startup upgrade should fail in the middle.
+	upgrader, closeFn = newUpgrader()
+	err = upgrader.StartupUpgrade()
+	require.Error(err, "StartupUpgrade should fail when a step fails")
+	require.EqualValues([]uint64{0, 1, 2}, handler.steps, "steps before the failed step should be performed")
+	require.Equal(0, handler.startup, "StartupUpgrade should not be called before all steps complete")
+	closeFn()
+
+	// After a restart, the startup upgrade should resume with the failed step.
+	handler.failAt = handler.totalSteps
+	upgrader, closeFn = newUpgrader()
+	defer closeFn()
+	pu, err := upgrader.GetUpgrade(context.Background(), desc)
+	require.NoError(err, "GetUpgrade")
+	require.EqualValues(&api.UpgradeProgress{
+		Stage:          api.UpgradeStageStartup,
+		CompletedSteps: 3,
+		TotalSteps:     5,
+	}, pu.Progress, "progress should be persisted")
+	require.EqualValues(60, pu.Progress.Percentage(), "progress percentage")
+
+	err = upgrader.StartupUpgrade()
+	require.NoError(err, "StartupUpgrade")
+	require.EqualValues([]uint64{0, 1, 2, 3, 4}, handler.steps, "all steps should be performed exactly once")
+	require.Equal(1, handler.startup, "StartupUpgrade should be called once all steps complete")
+	require.True(pu.HasStage(api.UpgradeStageStartup), "startup stage should be completed")
+	require.Nil(pu.Progress, "progress should be cleared once the stage is completed")
+}
+
+const (
+	testRollbackHandlerName   = "__test-rollback"
+	testNoSnapshotHandlerName = "__test-no-snapshot"
+)
+
+type testNoSnapshotHandler struct {
+	stateFile string
+}
+
+func (h *testNoSnapshotHandler) StartupUpgrade(ctx *migrations.Context) error {
+	// Modify the state and fail halfway.
+	if err := os.WriteFile(h.stateFile, []byte("modified"), 0o600); err != nil {
+		return err
+	}
+	return fmt.Errorf("upgrade failed")
+}
+
+func (h *testNoSnapshotHandler) ConsensusUpgrade(ctx *migrations.Context, privateCtx interface{}) error {
+	return nil
+}
+
+type testRollbackHandler struct {
+	testNoSnapshotHandler
+}
+
+func (h *testRollbackHandler) SnapshotPaths(ctx *migrations.Context) []string {
+	return migrations.ConsensusStatePaths
+}
+
+func TestRollback(t *testing.T) {
+	require := require.New(t)
+
+	dataDir := t.TempDir()
+	stateDir := filepath.Join(dataDir, migrations.ConsensusStatePaths[0])
+	err := os.MkdirAll(stateDir, 0o700)
+	require.NoError(err, "MkdirAll")
+	stateFile := filepath.Join(stateDir, "state")
+	err = os.WriteFile(stateFile, []byte("original"), 0o600)
+	require.NoError(err, "WriteFile")
+
+	migrations.Register(testRollbackHandlerName, &testRollbackHandler{
+		testNoSnapshotHandler{stateFile: stateFile},
+	})
+	migrations.Register(testNoSnapshotHandlerName, &testNoSnapshotHandler{stateFile: stateFile})
+
+	store, err := persistent.NewCommonStore(dataDir)
+	require.NoError(err, "NewCommonStore")
+	defer store.Close()
+
+	_, err = Rollback(store, dataDir)
+	require.ErrorIs(err, api.ErrNoSnapshot, "Rollback should fail without a snapshot")
+
+	// Handlers that don't request a snapshot should not get one.
+	upgrader, err := New(store, dataDir)
+	require.NoError(err, "New")
+	err = upgrader.SubmitDescriptor(context.Background(), &api.Descriptor{
+		Versioned: cbor.NewVersioned(api.LatestDescriptorVersion),
+		Handler:   testNoSnapshotHandlerName,
+		Target:    version.Versions,
+		Epoch:     1,
+	})
+	require.NoError(err, "SubmitDescriptor")
+	upgrader.(*upgradeManager).pending[0].UpgradeHeight = 10
+	err = upgrader.StartupUpgrade()
+	require.Error(err, "StartupUpgrade should fail")
+	_, err = os.Stat(filepath.Join(dataDir, SnapshotDir))
+	require.True(os.IsNotExist(err), "no snapshot should be taken")
+	upgrader.(*upgradeManager).pending[0].UpgradeHeight = api.InvalidUpgradeHeight
+	err = upgrader.CancelUpgrade(context.Background(), upgrader.(*upgradeManager).pending[0].Descriptor)
+	require.NoError(err, "CancelUpgrade")
+	upgrader.Close()
+	err = os.WriteFile(stateFile, []byte("original"), 0o600)
+	require.NoError(err, "WriteFile")
+
+	// Submit an upgrade, pretend the upgrade epoch has been reached and fail the upgrade.
+	upgrader, err = New(store, dataDir)
+	require.NoError(err, "New")
+	desc := &api.Descriptor{
+		Versioned: cbor.NewVersioned(api.LatestDescriptorVersion),
+		Handler:   testRollbackHandlerName,
+		Target:    version.Versions,
+		Epoch:     1,
+	}
+	err = upgrader.SubmitDescriptor(context.Background(), desc)
+	require.NoError(err, "SubmitDescriptor")
+	upgrader.(*upgradeManager).pending[0].UpgradeHeight = 10
+	err = upgrader.StartupUpgrade()
+	require.Error(err, "StartupUpgrade should fail")
+
+	// Retrying the upgrade must not overwrite the snapshot.
+	err = upgrader.StartupUpgrade()
+	require.Error(err, "StartupUpgrade should fail")
+	upgrader.Close()
+
+	rolledBack, err := Rollback(store, dataDir)
+	require.NoError(err, "Rollback")
+	require.True(desc.Equals(rolledBack), "Rollback should return the rolled back upgrade")
+
+	state, err := os.ReadFile(stateFile)
+	require.NoError(err, "ReadFile")
+	require.Equal("original", string(state), "state should be restored from the snapshot")
+	_, err = os.Stat(filepath.Join(dataDir, SnapshotDir))
+	require.True(os.IsNotExist(err), "snapshot should be removed")
+
+	upgrader, err = New(store, dataDir)
+	require.NoError(err, "New")
+	defer upgrader.Close()
+	pending, err := upgrader.PendingUpgrades(context.Background())
+	require.NoError(err, "PendingUpgrades")
+	require.Empty(pending, "pending upgrade should be cleared")
+}
+
+func TestPreflightChecks(t *testing.T) {
+	require := require.New(t)
+
+	dataDir := t.TempDir()
+	binary := filepath.Join(dataDir, "oasis-node")
+	err := os.WriteFile(binary, []byte(fmt.Sprintf(`#!/bin/sh
+echo "Software version: 0.0.0"
+echo "Consensus:"
+echo "  Consensus protocol version: %s"
+echo "Runtime:"
+echo "  Host protocol version:      %s"
+echo "  Committee protocol version: %s"
+`,
+		version.Versions.ConsensusProtocol,
+		version.Versions.RuntimeHostProtocol,
+		version.Versions.RuntimeCommitteeProtocol,
+	)), 0o700)
+	require.NoError(err, "WriteFile")
+
+	store, err := persistent.NewCommonStore(dataDir)
+	require.NoError(err, "NewCommonStore")
+	defer store.Close()
+	upgrader, err := New(store, dataDir)
+	require.NoError(err, "New")
+	defer upgrader.Close()
+	u := upgrader.(*upgradeManager)
+
+	var runtimeID, otherRuntimeID common.Namespace
+	otherRuntimeID[0] = 1
+	runtime := filepath.Join(dataDir, "runtime")
+	err = os.WriteFile(runtime, []byte("runtime"), 0o600)
+	require.NoError(err, "WriteFile")
+	u.preflight = &preflightConfig{
+		binary:       binary,
+		minFreeSpace: math.MaxUint64,
+		runtimePaths: map[common.Namespace]string{
+			runtimeID:      filepath.Join(dataDir, "missing-runtime"),
+			otherRuntimeID: runtime,
+		},
+		runtimeHashes: map[common.Namespace]hash.Hash{
+			otherRuntimeID: hash.NewFromBytes([]byte("runtime")),
+		},
+	}
+
+	desc := &api.Descriptor{
+		Versioned: cbor.NewVersioned(api.LatestDescriptorVersion),
+		Handler:   testStepHandlerName,
+		Target:    version.Versions,
+		Epoch:     1,
+	}
+	err = upgrader.SubmitDescriptor(context.Background(), desc)
+	require.NoError(err, "SubmitDescriptor")
+
+	u.runPreflightChecks()
+	pending, err := upgrader.PendingUpgrades(context.Background())
+	require.NoError(err, "PendingUpgrades")
+	require.Len(pending, 1)
+
+	checks := make(map[string]api.PreflightCheck)
+	for _, c := range pending[0].Preflight {
+		checks[c.Name] = c
+	}
+	require.Len(checks, 4, "all configured checks should be performed")
+	require.True(checks["binary"].Passed, "binary check should pass: %s", checks["binary"].Reason)
+	require.False(checks["disk_space"].Passed, "disk space check should fail")
+	require.False(checks["runtime/"+runtimeID.String()].Passed, "runtime check should fail")
+	require.True(checks["runtime/"+otherRuntimeID.String()].Passed, "runtime check should pass: %s", checks["runtime/"+otherRuntimeID.String()].Reason)
+
+	// Modified files should fail the checks, the results must not be cached.
+	err = os.WriteFile(runtime, []byte("modified"), 0o600)
+	require.NoError(err, "WriteFile")
+	checks = make(map[string]api.PreflightCheck)
+	for _, c := range u.preflightChecks(desc) {
+		checks[c.Name] = c
+	}
+	require.False(checks["runtime/"+otherRuntimeID.String()].Passed, "runtime check should fail for modified runtimes")
+
+	// A binary not matching the described hash should fail the check.
+	binaryDesc := *desc
+	binaryDesc.Binary = &api.Binary{
+		Name: "oasis-node",
+		Hash: hash.NewFromBytes([]byte("other binary")),
+	}
+	binaryChecks := u.preflightChecks(&binaryDesc)
+	require.Equal("binary", binaryChecks[0].Name)
+	require.False(binaryChecks[0].Passed, "binary check should fail for binaries with a different hash")
+	require.Contains(binaryChecks[0].Reason, "hash mismatch")
+
+	// The results must not be persisted.
+	var stored []*api.PendingUpgrade
+	err = u.store.GetCBOR(metadataStoreKey, &stored)
+	require.NoError(err, "GetCBOR")
+	require.Len(stored, 1)
+	require.Empty(stored[0].Preflight, "preflight check results should not be persisted")
+
+	// An incompatible binary should fail the check (the descriptor is shared with the manager).
+	desc.Target.ConsensusProtocol.Major++
+	u.runPreflightChecks()
+	require.True(pending[0].Preflight[0].Passed, "returned pending upgrades should not be modified")
+	pending, err = upgrader.PendingUpgrades(context.Background())
+	require.NoError(err, "PendingUpgrades")
+	require.False(pending[0].Preflight[0].Passed, "binary check should fail for incompatible binaries")
+
+	// Reaching the upgrade epoch should perform the checks again.
+	desc.Target.ConsensusProtocol.Major--
+	err = os.WriteFile(runtime, []byte("runtime"), 0o600)
+	require.NoError(err, "WriteFile")
+	u.preflight.minFreeSpace = 0
+	u.preflight.runtimePaths = map[common.Namespace]string{otherRuntimeID: runtime}
+	err = upgrader.ConsensusUpgrade(nil, desc.Epoch, 10)
+	require.ErrorIs(err, api.ErrStopForUpgrade, "ConsensusUpgrade")
+	labels := prometheus.Labels{"handler": string(desc.Handler)}
+	require.EqualValues(0, testutil.ToFloat64(upgradePreflightFailedChecks.With(labels)), "checks should pass at the upgrade epoch")
+	pending, err = upgrader.PendingUpgrades(context.Background())
+	require.NoError(err, "PendingUpgrades")
+	require.Empty(pending[0].Preflight, "periodic check results should be cleared at the upgrade epoch")
+}
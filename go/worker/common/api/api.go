@@ -1,6 +1,7 @@
 package api
 
 import (
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 )
 
@@ -13,6 +14,8 @@ type Status struct {
 
 	// LastCommitteeUpdateHeight is the consensus layer height of the last committee update.
 	LastCommitteeUpdateHeight int64 `json:"last_committee_update_height"`
+	// Epoch is the epoch of the current committees.
+	Epoch beacon.EpochTime `json:"epoch"`
 
 	// ExecutorRoles are the node's roles in the executor committee.
 	ExecutorRoles []scheduler.Role `json:"executor_roles"`
@@ -2,11 +2,13 @@ package committee
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
@@ -16,6 +18,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
 	"github.com/oasisprotocol/oasis-core/go/runtime/nodes"
 	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	storageClient "github.com/oasisprotocol/oasis-core/go/storage/client"
@@ -278,6 +281,15 @@ func (g *Group) Suspend(ctx context.Context) {
 	g.Lock()
 	defer g.Unlock()
 
+	// Stop gossiping messages of the suspended runtime as there is no committee.
+	if g.p2p != nil {
+		if err := g.p2p.LeaveTopic(g.runtime.ID()); err != nil && !errors.Is(err, p2p.ErrUnknownTopic) {
+			g.logger.Error("failed to leave runtime topic",
+				"err", err,
+			)
+		}
+	}
+
 	if g.activeEpoch == nil {
 		return
 	}
@@ -405,6 +417,22 @@ func (g *Group) EpochTransition(ctx context.Context, height int64) error {
 		runtime:           runtime,
 	}
 
+	// Only allow executor committee members to publish committee messages.
+	if g.p2p != nil {
+		// Resume gossiping in case the topic was left when the runtime was suspended.
+		if err = g.p2p.JoinTopic(g.runtime.ID()); err != nil && !errors.Is(err, p2p.ErrUnknownTopic) {
+			return fmt.Errorf("group: failed to join runtime topic: %w", err)
+		}
+
+		var p2pIDs []signature.PublicKey
+		for _, member := range executorCommittee.Committee.Members {
+			if n := g.nodes.Lookup(member.PublicKey); n != nil {
+				p2pIDs = append(p2pIDs, n.P2P.ID)
+			}
+		}
+		g.p2p.SetTopicAllowlist(g.runtime.ID(), groupVersion, p2pIDs)
+	}
+
 	g.logger.Info("epoch transition complete",
 		"group_version", groupVersion,
 		"executor_roles", executorCommittee.Roles,
@@ -540,6 +568,33 @@ func (g *Group) Peers() []string {
 	return g.p2p.Peers(g.runtime.ID())
 }
 
+// ProvideBatches registers a provider that serves proposed batches to peers.
+func (g *Group) ProvideBatches(provider p2p.BatchProvider) {
+	if g.p2p == nil {
+		return
+	}
+	g.p2p.RegisterBatchProvider(g.runtime.ID(), provider)
+}
+
+// FetchProposedBatch fetches the transactions of a proposed batch directly from the proposer or
+// other peers advertising them.
+func (g *Group) FetchProposedBatch(
+	ctx context.Context,
+	ioRoot hash.Hash,
+	proposer signature.PublicKey,
+	verify func(transaction.RawBatch) error,
+) (transaction.RawBatch, error) {
+	if g.p2p == nil {
+		return nil, fmt.Errorf("group: P2P not available")
+	}
+
+	var peers []signature.PublicKey
+	if n := g.nodes.Lookup(proposer); n != nil {
+		peers = append(peers, n.P2P.ID)
+	}
+	return g.p2p.FetchBatch(ctx, g.runtime.ID(), ioRoot, peers, verify)
+}
+
 // Storage returns the storage client backend that talks to the runtime group.
 func (g *Group) Storage() storage.Backend {
 	return g.storage
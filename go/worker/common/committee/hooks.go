@@ -0,0 +1,127 @@
+package committee
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/runtime/nodes"
+	"github.com/oasisprotocol/oasis-core/go/worker/common/p2p"
+)
+
+var (
+	_ NodeHooks = (*BaseNodeHooks)(nil)
+
+	closedCh = func() chan struct{} {
+		ch := make(chan struct{})
+		close(ch)
+		return ch
+	}()
+)
+
+// NodeHooksFactory creates additional node hooks for the given committee node.
+//
+// In case the hooks should not be attached to the given node (e.g., because they are only
+// interested in specific runtimes), the factory should return nil hooks and no error.
+type NodeHooksFactory func(n *Node) (NodeHooks, error)
+
+type namedNodeHooksFactory struct {
+	name    string
+	factory NodeHooksFactory
+}
+
+// NodeHooksRegistry is a registry of additional node hooks (e.g., metrics, tracing or external
+// notifications on state transitions) that are attached to committee nodes.
+type NodeHooksRegistry struct {
+	sync.Mutex
+
+	names     map[string]bool
+	factories []namedNodeHooksFactory
+}
+
+// Register registers a factory for additional node hooks that are attached to each committee
+// node using this registry when the node is started.
+//
+// Hooks are attached in registration order, after any hooks of the runtime workers, and are
+// called from the committee node's worker like all other hooks. Hook implementations that are
+// only interested in some of the events should embed BaseNodeHooks.
+func (r *NodeHooksRegistry) Register(name string, factory NodeHooksFactory) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.names[name] {
+		return fmt.Errorf("worker/common/committee: node hooks already registered: %s", name)
+	}
+	r.names[name] = true
+	r.factories = append(r.factories, namedNodeHooksFactory{
+		name:    name,
+		factory: factory,
+	})
+	return nil
+}
+
+// attach creates and attaches all registered node hooks to the given node.
+func (r *NodeHooksRegistry) attach(n *Node) error {
+	r.Lock()
+	factories := append([]namedNodeHooksFactory{}, r.factories...)
+	r.Unlock()
+
+	for _, f := range factories {
+		hooks, err := f.factory(n)
+		if err != nil {
+			return fmt.Errorf("worker/common/committee: failed to create node hooks '%s': %w", f.name, err)
+		}
+		if hooks == nil {
+			continue
+		}
+
+		n.logger.Debug("attaching node hooks",
+			"name", f.name,
+		)
+		n.AddHooks(hooks)
+	}
+	return nil
+}
+
+// NewNodeHooksRegistry creates a new, empty, node hooks registry.
+func NewNodeHooksRegistry() *NodeHooksRegistry {
+	return &NodeHooksRegistry{
+		names: make(map[string]bool),
+	}
+}
+
+// BaseNodeHooks is a NodeHooks implementation that ignores all events. It is meant to be embedded
+// in hook implementations that are only interested in some of the events.
+type BaseNodeHooks struct{}
+
+// HandlePeerMessage implements NodeHooks.
+func (h *BaseNodeHooks) HandlePeerMessage(context.Context, *p2p.Message, bool) (bool, error) {
+	return false, nil
+}
+
+// HandleEpochTransitionLocked implements NodeHooks.
+func (h *BaseNodeHooks) HandleEpochTransitionLocked(*EpochSnapshot) {
+}
+
+// HandleNewBlockEarlyLocked implements NodeHooks.
+func (h *BaseNodeHooks) HandleNewBlockEarlyLocked(*block.Block) {
+}
+
+// HandleNewBlockLocked implements NodeHooks.
+func (h *BaseNodeHooks) HandleNewBlockLocked(*block.Block) {
+}
+
+// HandleNewEventLocked implements NodeHooks.
+func (h *BaseNodeHooks) HandleNewEventLocked(*roothash.Event) {
+}
+
+// HandleNodeUpdateLocked implements NodeHooks.
+func (h *BaseNodeHooks) HandleNodeUpdateLocked(*nodes.NodeUpdate, *EpochSnapshot) {
+}
+
+// Initialized implements NodeHooks.
+func (h *BaseNodeHooks) Initialized() <-chan struct{} {
+	return closedCh
+}
@@ -0,0 +1,70 @@
+package committee
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+)
+
+type testNodeHooks struct {
+	BaseNodeHooks
+
+	blocks int
+}
+
+func (h *testNodeHooks) HandleNewBlockLocked(*block.Block) {
+	h.blocks++
+}
+
+func TestRegisterNodeHooks(t *testing.T) {
+	require := require.New(t)
+
+	registry := NewNodeHooksRegistry()
+	hooks := &testNodeHooks{}
+	err := registry.Register("test/attached", func(n *Node) (NodeHooks, error) {
+		return hooks, nil
+	})
+	require.NoError(err, "Register")
+	err = registry.Register("test/skipped", func(n *Node) (NodeHooks, error) {
+		return nil, nil
+	})
+	require.NoError(err, "Register")
+	err = registry.Register("test/attached", func(n *Node) (NodeHooks, error) {
+		return nil, nil
+	})
+	require.Error(err, "registering hooks with a duplicate name should fail")
+
+	// Registries should be independent.
+	require.NoError(NewNodeHooksRegistry().Register("test/attached", func(n *Node) (NodeHooks, error) {
+		return nil, nil
+	}), "Register")
+
+	n := &Node{logger: logging.GetLogger("worker/common/committee/test")}
+	err = registry.attach(n)
+	require.NoError(err, "attach")
+	require.Len(n.hooks, 1, "only non-nil hooks should be attached")
+
+	select {
+	case <-n.hooks[0].Initialized():
+	default:
+		require.Fail("base hooks should be initialized")
+	}
+	handled, err := n.hooks[0].HandlePeerMessage(context.Background(), nil, false)
+	require.NoError(err, "HandlePeerMessage")
+	require.False(handled, "base hooks should not handle peer messages")
+
+	n.hooks[0].HandleNewBlockLocked(nil)
+	require.Equal(1, hooks.blocks, "overridden hook should be called")
+
+	err = registry.Register("test/failing", func(n *Node) (NodeHooks, error) {
+		return nil, fmt.Errorf("failed")
+	})
+	require.NoError(err, "Register")
+	err = registry.attach(&Node{logger: n.logger})
+	require.Error(err, "attach should fail if a factory fails")
+}
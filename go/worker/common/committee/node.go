@@ -72,6 +72,8 @@ var (
 
 // NodeHooks defines a worker's duties at common events.
 // These are called from the runtime's common node's worker.
+//
+// Additional hooks can be attached to committee nodes via a NodeHooksRegistry.
 type NodeHooks interface {
 	HandlePeerMessage(context.Context, *p2p.Message, bool) (bool, error)
 	// Guarded by CrossNode.
@@ -109,7 +111,8 @@ type Node struct {
 	quitCh    chan struct{}
 	initCh    chan struct{}
 
-	hooks []NodeHooks
+	hooks         []NodeHooks
+	hooksRegistry *NodeHooksRegistry
 
 	// Mutable and shared between nodes' workers.
 	// Guarded by .CrossNode.
@@ -129,6 +132,12 @@ func (n *Node) Name() string {
 
 // Start starts the service.
 func (n *Node) Start() error {
+	if n.hooksRegistry != nil {
+		if err := n.hooksRegistry.attach(n); err != nil {
+			return err
+		}
+	}
+
 	if err := n.Group.Start(); err != nil {
 		return fmt.Errorf("failed to start group services: %w", err)
 	}
@@ -176,6 +185,7 @@ func (n *Node) GetStatus(ctx context.Context) (*api.Status, error) {
 
 	epoch := n.Group.GetEpochSnapshot()
 	status.LastCommitteeUpdateHeight = epoch.GetGroupVersion()
+	status.Epoch = epoch.GetEpochNumber()
 	if cmte := epoch.GetExecutorCommittee(); cmte != nil {
 		status.ExecutorRoles = cmte.Roles
 	}
@@ -491,6 +501,7 @@ func NewNode(
 	keymanager keymanagerApi.Backend,
 	consensus consensus.Backend,
 	p2p *p2p.P2P,
+	hooksRegistry *NodeHooksRegistry,
 ) (*Node, error) {
 	metricsOnce.Do(func() {
 		prometheus.MustRegister(nodeCollectors...)
@@ -499,17 +510,18 @@ func NewNode(
 	ctx, cancel := context.WithCancel(context.Background())
 
 	n := &Node{
-		HostNode:   hostNode,
-		Runtime:    runtime,
-		Identity:   identity,
-		KeyManager: keymanager,
-		Consensus:  consensus,
-		ctx:        ctx,
-		cancelCtx:  cancel,
-		stopCh:     make(chan struct{}),
-		quitCh:     make(chan struct{}),
-		initCh:     make(chan struct{}),
-		logger:     logging.GetLogger("worker/common/committee").With("runtime_id", runtime.ID()),
+		HostNode:      hostNode,
+		Runtime:       runtime,
+		Identity:      identity,
+		KeyManager:    keymanager,
+		Consensus:     consensus,
+		hooksRegistry: hooksRegistry,
+		ctx:           ctx,
+		cancelCtx:     cancel,
+		stopCh:        make(chan struct{}),
+		quitCh:        make(chan struct{}),
+		initCh:        make(chan struct{}),
+		logger:        logging.GetLogger("worker/common/committee").With("runtime_id", runtime.ID()),
 	}
 
 	group, err := NewGroup(ctx, identity, runtime, n, consensus, p2p)
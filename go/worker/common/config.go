@@ -2,12 +2,15 @@ package common
 
 import (
 	"fmt"
+	"reflect"
+	"sync"
 	"time"
 
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/worker/common/configparser"
@@ -19,33 +22,105 @@ var (
 
 	cfgClientAddresses = "worker.client.addresses"
 
+	cfgClientRuntimeClient = "worker.client.runtime_client"
+
+	cfgClientMaxRecvMsgSize       = "worker.client.limits.max_recv_message_size"
+	cfgClientMaxConcurrentStreams = "worker.client.limits.max_concurrent_streams"
+	// Per-method limits are not supported by cobra, use the config file instead.
+	cfgClientMethodLimits = "worker.client.limits.methods"
+
 	// CfgSentryAddresses configures addresses and public keys of sentry nodes the worker should
 	// connect to.
 	CfgSentryAddresses = "worker.sentry.address"
 
 	cfgStorageCommitTimeout = "worker.storage_commit_timeout"
 
+	cfgTxPoolRepublishInterval         = "worker.tx_pool.republish_interval"
+	cfgTxPoolMaxRepublishBatchSize     = "worker.tx_pool.max_republish_batch_size"
+	cfgTxPoolRepublishDebounceInterval = "worker.tx_pool.republish_debounce_interval"
+
 	// Flags has the configuration flags.
 	Flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	// ReloadableConfig are the configuration keys that can be changed at runtime via Reload.
+	ReloadableConfig = []string{cfgClientAddresses, CfgSentryAddresses}
 )
 
 // Config contains common worker config.
+//
+// The client and sentry addresses may be changed at runtime via Reload and
+// should be accessed via GetNodeAddresses and GetSentryAddresses.
 type Config struct { // nolint: maligned
+	sync.RWMutex
+
 	ClientPort      uint16
 	ClientAddresses []node.Address
 	SentryAddresses []node.TLSAddress
 
+	// ClientLimits are the resource limits of the externally-accessible gRPC server.
+	ClientLimits *grpc.Limits
+	// ClientRuntimeClient specifies whether the runtime client service should be exposed on the
+	// externally-accessible gRPC server (e.g., so that it can be reached via sentry nodes).
+	ClientRuntimeClient bool
+
 	StorageCommitTimeout time.Duration
 
+	// TxPool is the transaction pool configuration.
+	TxPool TxPoolConfig
+
 	logger *logging.Logger
 }
 
+// TxPoolConfig is the transaction pool configuration.
+type TxPoolConfig struct {
+	// RepublishInterval is the interval after which transactions in the pool are republished to
+	// peers. Zero disables republishing.
+	RepublishInterval time.Duration
+	// MaxRepublishBatchSize is the maximum number of transactions republished at once.
+	MaxRepublishBatchSize uint64
+	// RepublishDebounceInterval is the minimum interval between consecutive republishes when
+	// more transactions are due than fit into a single batch.
+	RepublishDebounceInterval time.Duration
+}
+
+// Validate validates the transaction pool configuration.
+func (c *TxPoolConfig) Validate() error {
+	if c.RepublishInterval < 0 {
+		return fmt.Errorf("republish interval must not be negative")
+	}
+	if c.RepublishInterval == 0 {
+		return nil
+	}
+	if c.MaxRepublishBatchSize == 0 {
+		return fmt.Errorf("max republish batch size must be greater than zero")
+	}
+	if c.RepublishDebounceInterval < 0 {
+		return fmt.Errorf("republish debounce interval must not be negative")
+	}
+	if c.RepublishDebounceInterval > c.RepublishInterval {
+		return fmt.Errorf("republish debounce interval must not exceed the republish interval")
+	}
+	return nil
+}
+
+// GetSentryAddresses returns the addresses of the sentry nodes the worker should connect to.
+func (c *Config) GetSentryAddresses() []node.TLSAddress {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.SentryAddresses
+}
+
 // GetNodeAddresses returns worker node addresses.
 func (c *Config) GetNodeAddresses() ([]node.Address, error) {
+	c.RLock()
+	clientAddresses := c.ClientAddresses
+	c.RUnlock()
+
 	var addresses []node.Address
 
-	if len(c.ClientAddresses) > 0 {
-		addresses = c.ClientAddresses
+	if len(clientAddresses) > 0 {
+		addresses = clientAddresses
 	} else {
 		// Use all non-loopback addresses of this node.
 		addrs, err := common.FindAllAddresses()
@@ -65,29 +140,87 @@ func (c *Config) GetNodeAddresses() ([]node.Address, error) {
 	return addresses, nil
 }
 
-// NewConfig creates a new worker config.
-func NewConfig() (*Config, error) {
+// Reload re-reads the client and sentry addresses from the configuration and returns true iff
+// any of them changed.
+func (c *Config) Reload(cfg *viper.Viper) (bool, error) {
+	clientAddresses, sentryAddresses, err := parseAddresses(cfg)
+	if err != nil {
+		return false, err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	changed := !reflect.DeepEqual(clientAddresses, c.ClientAddresses) ||
+		!reflect.DeepEqual(sentryAddresses, c.SentryAddresses)
+	if changed {
+		c.logger.Info("worker addresses changed",
+			"client_addresses", clientAddresses,
+			"sentry_addresses", sentryAddresses,
+		)
+	}
+
+	c.ClientAddresses = clientAddresses
+	c.SentryAddresses = sentryAddresses
+
+	return changed, nil
+}
+
+func parseAddresses(cfg *viper.Viper) ([]node.Address, []node.TLSAddress, error) {
 	// Parse register address overrides.
-	clientAddresses, err := configparser.ParseAddressList(viper.GetStringSlice(cfgClientAddresses))
+	clientAddresses, err := configparser.ParseAddressList(cfg.GetStringSlice(cfgClientAddresses))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Parse sentry configuration.
 	var sentryAddresses []node.TLSAddress
-	for _, v := range viper.GetStringSlice(CfgSentryAddresses) {
+	for _, v := range cfg.GetStringSlice(CfgSentryAddresses) {
 		var tlsAddr node.TLSAddress
 		if err = tlsAddr.UnmarshalText([]byte(v)); err != nil {
-			return nil, fmt.Errorf("worker: bad sentry address (%s): %w", v, err)
+			return nil, nil, fmt.Errorf("worker: bad sentry address (%s): %w", v, err)
 		}
 		sentryAddresses = append(sentryAddresses, tlsAddr)
 	}
 
+	return clientAddresses, sentryAddresses, nil
+}
+
+// NewConfig creates a new worker config.
+func NewConfig() (*Config, error) {
+	clientAddresses, sentryAddresses, err := parseAddresses(viper.GetViper())
+	if err != nil {
+		return nil, err
+	}
+
+	clientLimits := &grpc.Limits{
+		MaxRecvMsgSize:       int(viper.GetSizeInBytes(cfgClientMaxRecvMsgSize)),
+		MaxConcurrentStreams: viper.GetUint32(cfgClientMaxConcurrentStreams),
+	}
+	if err = viper.UnmarshalKey(cfgClientMethodLimits, &clientLimits.Methods); err != nil {
+		return nil, fmt.Errorf("worker: bad client method limits: %w", err)
+	}
+	if err = clientLimits.Validate(); err != nil {
+		return nil, fmt.Errorf("worker: bad client limits: %w", err)
+	}
+
+	txPool := TxPoolConfig{
+		RepublishInterval:         viper.GetDuration(cfgTxPoolRepublishInterval),
+		MaxRepublishBatchSize:     viper.GetUint64(cfgTxPoolMaxRepublishBatchSize),
+		RepublishDebounceInterval: viper.GetDuration(cfgTxPoolRepublishDebounceInterval),
+	}
+	if err = txPool.Validate(); err != nil {
+		return nil, fmt.Errorf("worker: bad transaction pool configuration: %w", err)
+	}
+
 	cfg := Config{
 		ClientPort:           uint16(viper.GetInt(CfgClientPort)),
 		ClientAddresses:      clientAddresses,
 		SentryAddresses:      sentryAddresses,
+		ClientLimits:         clientLimits,
+		ClientRuntimeClient:  viper.GetBool(cfgClientRuntimeClient),
 		StorageCommitTimeout: viper.GetDuration(cfgStorageCommitTimeout),
+		TxPool:               txPool,
 		logger:               logging.GetLogger("worker/config"),
 	}
 
@@ -96,10 +229,17 @@ func NewConfig() (*Config, error) {
 
 func init() {
 	Flags.Uint16(CfgClientPort, 9100, "Port to use for incoming gRPC client connections")
-	Flags.StringSlice(cfgClientAddresses, []string{}, "Address/port(s) to use for client connections when registering this node (if not set, all non-loopback local interfaces will be used)")
+	Flags.StringSlice(cfgClientAddresses, []string{}, "Address/port(s) (IPv4 or [IPv6]) to use for client connections when registering this node (if not set, all non-loopback local interfaces will be used)")
+	Flags.String(cfgClientMaxRecvMsgSize, "100mb", "Maximum size of a message received via incoming gRPC client connections")
+	Flags.Uint32(cfgClientMaxConcurrentStreams, 0, "Maximum number of concurrent gRPC streams per client connection (0 means no limit)")
+	Flags.Bool(cfgClientRuntimeClient, false, "Expose the runtime client service on the worker client endpoint (e.g., so that it can be proxied by sentry nodes)")
 	Flags.StringSlice(CfgSentryAddresses, []string{}, "Address(es) of sentry node(s) to connect to of the form [PubKey@]ip:port (where PubKey@ part represents base64 encoded node TLS public key)")
 
 	Flags.Duration(cfgStorageCommitTimeout, 10*time.Second, "Storage commit timeout")
 
+	Flags.Duration(cfgTxPoolRepublishInterval, 0, "Interval after which pending transactions are republished to peers (0 disables republishing)")
+	Flags.Uint64(cfgTxPoolMaxRepublishBatchSize, 128, "Maximum number of transactions republished at once")
+	Flags.Duration(cfgTxPoolRepublishDebounceInterval, 5*time.Second, "Minimum interval between consecutive transaction republishes")
+
 	_ = viper.BindPFlags(Flags)
 }
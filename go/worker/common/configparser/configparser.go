@@ -4,12 +4,16 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 )
 
 // ParseAddressList parses addresses.
+//
+// Each address must be of the form ip:port where ip is either an IPv4 address or an IPv6 address
+// enclosed in square brackets.
 func ParseAddressList(addresses []string) ([]node.Address, error) {
 	var output []node.Address
 	for _, rawAddress := range addresses {
@@ -23,6 +27,9 @@ func ParseAddressList(addresses []string) ([]node.Address, error) {
 			return nil, fmt.Errorf("malformed port: %s", rawPort)
 		}
 
+		if strings.Contains(rawIP, "%") {
+			return nil, fmt.Errorf("malformed ip address: zoned addresses are not supported: %s", rawIP)
+		}
 		ip := net.ParseIP(rawIP)
 		if ip == nil {
 			return nil, fmt.Errorf("malformed ip address: %s", rawIP)
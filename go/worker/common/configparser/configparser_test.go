@@ -0,0 +1,32 @@
+package configparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddressList(t *testing.T) {
+	require := require.New(t)
+
+	addrs, err := ParseAddressList([]string{
+		"192.0.2.10:9100",
+		"[2001:db8::10]:9200",
+	})
+	require.NoError(err, "ParseAddressList")
+	require.Len(addrs, 2)
+	require.Equal("192.0.2.10:9100", addrs[0].String())
+	require.Equal("[2001:db8::10]:9200", addrs[1].String())
+
+	for _, invalid := range []string{
+		"192.0.2.10",
+		"192.0.2.10:port",
+		"2001:db8::10:9200",
+		"[fe80::1%eth0]:9200",
+		"node.example.com:9100",
+		":9100",
+	} {
+		_, err = ParseAddressList([]string{invalid})
+		require.Error(err, "ParseAddressList should fail for: %s", invalid)
+	}
+}
@@ -0,0 +1,118 @@
+package p2p
+
+import (
+	"fmt"
+	"sync"
+
+	core "github.com/libp2p/go-libp2p-core"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+// topicAllowlist is the set of peers allowed to publish committee messages on a runtime topic.
+type topicAllowlist struct {
+	// groupVersion is the group version of the committee the allowlist was derived from.
+	groupVersion int64
+	peers        map[core.PeerID]bool
+}
+
+// allowlists keeps track of per-runtime publisher allowlists.
+type allowlists struct {
+	sync.RWMutex
+
+	enabled  bool
+	operator map[core.PeerID]bool
+	runtimes map[common.Namespace]*topicAllowlist
+}
+
+// set replaces the committee-derived allowlist for the given runtime.
+func (al *allowlists) set(runtimeID common.Namespace, groupVersion int64, peers []signature.PublicKey) {
+	tal := &topicAllowlist{
+		groupVersion: groupVersion,
+		peers:        make(map[core.PeerID]bool),
+	}
+	for _, id := range peers {
+		peerID, err := publicKeyToPeerID(id)
+		if err != nil {
+			continue
+		}
+		tal.peers[peerID] = true
+	}
+
+	al.Lock()
+	defer al.Unlock()
+
+	al.runtimes[runtimeID] = tal
+}
+
+// isAllowed checks whether the given peer is allowed to publish the given message on the topic
+// of the given runtime.
+func (al *allowlists) isAllowed(runtimeID common.Namespace, peerID core.PeerID, msg *Message) bool {
+	if !al.enabled {
+		return true
+	}
+	// Transactions may be submitted by any peer (e.g., client nodes).
+	if msg.ProposedBatch == nil && msg.ExecutorCommit == nil {
+		return true
+	}
+	if al.operator[peerID] {
+		return true
+	}
+
+	al.RLock()
+	defer al.RUnlock()
+
+	tal := al.runtimes[runtimeID]
+	if tal == nil {
+		// Committee not yet known, defer to peer authentication by the handlers.
+		return true
+	}
+	// Messages claiming a newer group version are not exempt as the group version is set by the
+	// publisher and the handlers keep such messages around until the committee is known.
+	return tal.peers[peerID]
+}
+
+// committeePeers returns the set of committee members of all runtimes.
+func (al *allowlists) committeePeers() map[core.PeerID]bool {
+	al.RLock()
+	defer al.RUnlock()
+
+	peers := make(map[core.PeerID]bool)
+	for _, tal := range al.runtimes {
+		for peerID := range tal.peers {
+			peers[peerID] = true
+		}
+	}
+	return peers
+}
+
+func newAllowlists() (*allowlists, error) {
+	al := &allowlists{
+		enabled:  viper.GetBool(CfgP2PAllowlistEnabled),
+		operator: make(map[core.PeerID]bool),
+		runtimes: make(map[common.Namespace]*topicAllowlist),
+	}
+	for _, rawID := range viper.GetStringSlice(CfgP2PAllowlistPeers) {
+		var id signature.PublicKey
+		if err := id.UnmarshalText([]byte(rawID)); err != nil {
+			return nil, fmt.Errorf("worker/common/p2p: malformed allowlisted peer '%s': %w", rawID, err)
+		}
+		peerID, err := publicKeyToPeerID(id)
+		if err != nil {
+			return nil, fmt.Errorf("worker/common/p2p: malformed allowlisted peer '%s': %w", rawID, err)
+		}
+		al.operator[peerID] = true
+	}
+	return al, nil
+}
+
+// SetTopicAllowlist sets the P2P public keys of the committee members that are allowed to publish
+// committee messages on the topic of the given runtime, as of the given group version.
+//
+// Peers configured in the operator allowlist are always allowed to publish. The allowlist is
+// only enforced in case it is enabled in the configuration.
+func (p *P2P) SetTopicAllowlist(runtimeID common.Namespace, groupVersion int64, peers []signature.PublicKey) {
+	p.allowlists.set(runtimeID, groupVersion, peers)
+}
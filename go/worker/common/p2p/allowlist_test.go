@@ -0,0 +1,54 @@
+package p2p
+
+import (
+	"math"
+	"testing"
+
+	core "github.com/libp2p/go-libp2p-core"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+	executor "github.com/oasisprotocol/oasis-core/go/worker/compute/executor/api"
+)
+
+func TestAllowlists(t *testing.T) {
+	require := require.New(t)
+
+	var runtimeID common.Namespace
+	member := memorySigner.NewTestSigner("allowlist test: member").Public()
+	operator := memorySigner.NewTestSigner("allowlist test: operator").Public()
+	other := memorySigner.NewTestSigner("allowlist test: other").Public()
+
+	toPeerID := func(id signature.PublicKey) core.PeerID {
+		peerID, err := publicKeyToPeerID(id)
+		require.NoError(err, "publicKeyToPeerID")
+		return peerID
+	}
+	operatorPeerID := toPeerID(operator)
+
+	al := &allowlists{
+		enabled:  true,
+		operator: map[core.PeerID]bool{operatorPeerID: true},
+		runtimes: make(map[common.Namespace]*topicAllowlist),
+	}
+
+	commitMsg := &Message{GroupVersion: 10, ExecutorCommit: &commitment.ExecutorCommitment{}}
+	txMsg := &Message{GroupVersion: 10, Tx: &executor.Tx{}}
+
+	require.True(al.isAllowed(runtimeID, toPeerID(other), commitMsg), "unknown committee should defer to handlers")
+
+	al.set(runtimeID, 10, []signature.PublicKey{member})
+	require.True(al.isAllowed(runtimeID, toPeerID(member), commitMsg), "committee members should be allowed")
+	require.True(al.isAllowed(runtimeID, operatorPeerID, commitMsg), "operator allowlisted peers should be allowed")
+	require.False(al.isAllowed(runtimeID, toPeerID(other), commitMsg), "other peers should be rejected")
+	require.True(al.isAllowed(runtimeID, toPeerID(other), txMsg), "transactions should be allowed from any peer")
+	require.False(al.isAllowed(runtimeID, toPeerID(other), &Message{GroupVersion: math.MaxInt64, ExecutorCommit: &commitment.ExecutorCommitment{}}),
+		"other peers should be rejected for future group versions",
+	)
+
+	al.enabled = false
+	require.True(al.isAllowed(runtimeID, toPeerID(other), commitMsg), "disabled allowlists should allow all peers")
+}
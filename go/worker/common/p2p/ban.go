@@ -0,0 +1,364 @@
+package p2p
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	core "github.com/libp2p/go-libp2p-core"
+	"github.com/libp2p/go-libp2p-core/connmgr"
+	"github.com/libp2p/go-libp2p-core/control"
+	"github.com/libp2p/go-libp2p-core/network"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+// ErrPeerNotBanned is the error returned when attempting to unban a peer that is not banned.
+var ErrPeerNotBanned = errors.New("worker/common/p2p: peer not banned")
+
+var (
+	_ pubsub.Blacklist        = (*banManager)(nil)
+	_ connmgr.ConnectionGater = (*banManager)(nil)
+)
+
+// BanInfo contains information about a banned peer.
+type BanInfo struct {
+	// Until is the time when the ban expires.
+	Until time.Time `json:"until"`
+
+	// Reason is the reason for the ban.
+	Reason string `json:"reason"`
+}
+
+type invalidMessages struct {
+	count uint64
+	since time.Time
+}
+
+// banManager keeps track of banned peers and of misbehaving peers that should be banned.
+//
+// It is used both as the gossipsub blacklist and as the libp2p connection gater so that banned
+// peers can neither connect to us nor exchange any messages with us.
+type banManager struct {
+	sync.Mutex
+
+	host core.Host
+
+	bans    map[core.PeerID]*BanInfo
+	invalid map[core.PeerID]*invalidMessages
+	scores  map[core.PeerID]float64
+
+	invalidThreshold uint64
+	invalidWindow    time.Duration
+	scoreThreshold   float64
+	banDuration      time.Duration
+
+	logger *logging.Logger
+}
+
+// ban bans the given peer for the given duration and disconnects it.
+func (bm *banManager) ban(peerID core.PeerID, duration time.Duration, reason string) {
+	bm.Lock()
+	bm.bans[peerID] = &BanInfo{
+		Until:  time.Now().Add(duration),
+		Reason: reason,
+	}
+	delete(bm.invalid, peerID)
+	host := bm.host
+	bm.Unlock()
+
+	bm.logger.Warn("banned peer",
+		"peer_id", peerID,
+		"duration", duration,
+		"reason", reason,
+	)
+
+	if host != nil {
+		_ = host.Network().ClosePeer(peerID)
+	}
+}
+
+// unban removes the ban for the given peer and returns true iff the peer was banned.
+func (bm *banManager) unban(peerID core.PeerID) bool {
+	bm.Lock()
+	defer bm.Unlock()
+
+	_, banned := bm.bans[peerID]
+	delete(bm.bans, peerID)
+	delete(bm.invalid, peerID)
+
+	if banned {
+		bm.logger.Info("unbanned peer",
+			"peer_id", peerID,
+		)
+	}
+	return banned
+}
+
+// banInfo returns the ban information for the given peer or nil in case the peer is not banned.
+func (bm *banManager) banInfo(peerID core.PeerID) *BanInfo {
+	bm.Lock()
+	defer bm.Unlock()
+
+	return bm.banInfoLocked(peerID)
+}
+
+func (bm *banManager) banInfoLocked(peerID core.PeerID) *BanInfo {
+	bi := bm.bans[peerID]
+	if bi == nil {
+		return nil
+	}
+	if time.Now().After(bi.Until) {
+		delete(bm.bans, peerID)
+		return nil
+	}
+	return bi
+}
+
+// banned returns a list of all currently banned peers.
+func (bm *banManager) banned() []core.PeerID {
+	bm.Lock()
+	defer bm.Unlock()
+
+	var peers []core.PeerID
+	for peerID := range bm.bans {
+		if bm.banInfoLocked(peerID) != nil {
+			peers = append(peers, peerID)
+		}
+	}
+	return peers
+}
+
+// score returns the last known gossipsub score of the given peer.
+func (bm *banManager) score(peerID core.PeerID) float64 {
+	bm.Lock()
+	defer bm.Unlock()
+
+	return bm.scores[peerID]
+}
+
+// recordInvalidMessage records that an invalid message has been received from the given peer
+// and bans the peer in case it sent too many invalid messages within the ban window.
+func (bm *banManager) recordInvalidMessage(peerID core.PeerID) {
+	if bm.invalidThreshold == 0 {
+		return
+	}
+
+	bm.Lock()
+	now := time.Now()
+	im := bm.invalid[peerID]
+	if im == nil || now.Sub(im.since) > bm.invalidWindow {
+		im = &invalidMessages{since: now}
+		bm.invalid[peerID] = im
+	}
+	im.count++
+	shouldBan := im.count >= bm.invalidThreshold
+	bm.Unlock()
+
+	if shouldBan {
+		bm.ban(peerID, bm.banDuration, "too many invalid messages")
+	}
+}
+
+// inspectScores is the gossipsub peer score inspector that records peer scores and bans peers
+// whose score dropped below the ban threshold.
+func (bm *banManager) inspectScores(scores map[core.PeerID]float64) {
+	bm.Lock()
+	bm.scores = scores
+	var toBan []core.PeerID
+	for peerID, score := range scores {
+		if score < bm.scoreThreshold && bm.banInfoLocked(peerID) == nil {
+			toBan = append(toBan, peerID)
+		}
+	}
+	bm.Unlock()
+
+	for _, peerID := range toBan {
+		bm.ban(peerID, bm.banDuration, "peer score below ban threshold")
+	}
+}
+
+// Implements pubsub.Blacklist.
+func (bm *banManager) Add(peerID core.PeerID) bool {
+	bm.ban(peerID, bm.banDuration, "blacklisted by gossipsub")
+	return true
+}
+
+// Implements pubsub.Blacklist.
+func (bm *banManager) Contains(peerID core.PeerID) bool {
+	return bm.banInfo(peerID) != nil
+}
+
+// Implements connmgr.ConnectionGater.
+func (bm *banManager) InterceptPeerDial(peerID core.PeerID) bool {
+	return !bm.Contains(peerID)
+}
+
+// Implements connmgr.ConnectionGater.
+func (bm *banManager) InterceptAddrDial(peerID core.PeerID, _ multiaddr.Multiaddr) bool {
+	return !bm.Contains(peerID)
+}
+
+// Implements connmgr.ConnectionGater.
+func (bm *banManager) InterceptAccept(network.ConnMultiaddrs) bool {
+	// Peer identity is not yet known at this point.
+	return true
+}
+
+// Implements connmgr.ConnectionGater.
+func (bm *banManager) InterceptSecured(_ network.Direction, peerID core.PeerID, _ network.ConnMultiaddrs) bool {
+	return !bm.Contains(peerID)
+}
+
+// Implements connmgr.ConnectionGater.
+func (bm *banManager) InterceptUpgraded(network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
+
+type banConfig struct {
+	invalidThreshold uint64
+	invalidWindow    time.Duration
+	scoreThreshold   float64
+	banDuration      time.Duration
+}
+
+func newBanConfig() *banConfig {
+	return &banConfig{
+		invalidThreshold: viper.GetUint64(CfgP2PBanInvalidMessages),
+		invalidWindow:    viper.GetDuration(CfgP2PBanWindow),
+		scoreThreshold:   viper.GetFloat64(CfgP2PBanScoreThreshold),
+		banDuration:      viper.GetDuration(CfgP2PBanDuration),
+	}
+}
+
+func newBanManager(cfg *banConfig) (*banManager, error) {
+	if cfg.banDuration <= 0 {
+		return nil, fmt.Errorf("worker/common/p2p: ban duration must be positive (got %s)", cfg.banDuration)
+	}
+	if cfg.invalidWindow <= 0 {
+		return nil, fmt.Errorf("worker/common/p2p: ban window must be positive (got %s)", cfg.invalidWindow)
+	}
+
+	return &banManager{
+		bans:             make(map[core.PeerID]*BanInfo),
+		invalid:          make(map[core.PeerID]*invalidMessages),
+		scores:           make(map[core.PeerID]float64),
+		invalidThreshold: cfg.invalidThreshold,
+		invalidWindow:    cfg.invalidWindow,
+		scoreThreshold:   cfg.scoreThreshold,
+		banDuration:      cfg.banDuration,
+		logger:           logging.GetLogger("worker/common/p2p/bans"),
+	}, nil
+}
+
+// PeerStatus is the status of a P2P peer.
+type PeerStatus struct {
+	// ID is the P2P public key of the peer.
+	ID signature.PublicKey
+
+	// PeerID is the libp2p peer identifier.
+	PeerID core.PeerID
+
+	// Addresses are the known addresses of the peer.
+	Addresses []string
+
+	// Connected is true iff the peer is currently connected.
+	Connected bool
+
+	// Registered is true iff the peer belongs to a node in the registry.
+	Registered bool
+
+	// Score is the last known gossipsub score of the peer. It is always zero in case peer scoring
+	// is disabled.
+	Score float64
+
+	// Ban is the ban information in case the peer is banned.
+	Ban *BanInfo
+}
+
+// PeerStatuses returns the status of all connected, registered and banned peers.
+func (p *P2P) PeerStatuses() []*PeerStatus {
+	peers := make(map[core.PeerID]*PeerStatus)
+	addPeer := func(peerID core.PeerID) *PeerStatus {
+		if ps := peers[peerID]; ps != nil {
+			return ps
+		}
+		id, err := peerIDToPublicKey(peerID)
+		if err != nil {
+			return nil
+		}
+		ps := &PeerStatus{
+			ID:        id,
+			PeerID:    peerID,
+			Connected: p.host.Network().Connectedness(peerID) == network.Connected,
+			Score:     p.bans.score(peerID),
+			Ban:       p.bans.banInfo(peerID),
+		}
+		for _, addr := range p.host.Peerstore().Addrs(peerID) {
+			ps.Addresses = append(ps.Addresses, addr.String())
+		}
+		peers[peerID] = ps
+		return ps
+	}
+
+	for _, peerID := range p.host.Network().Peers() {
+		addPeer(peerID)
+	}
+	for _, peerID := range p.KnownPeers() {
+		if ps := addPeer(peerID); ps != nil {
+			ps.Registered = true
+		}
+	}
+	for _, peerID := range p.bans.banned() {
+		addPeer(peerID)
+	}
+
+	statuses := make([]*PeerStatus, 0, len(peers))
+	for _, ps := range peers {
+		statuses = append(statuses, ps)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].PeerID < statuses[j].PeerID
+	})
+	return statuses
+}
+
+// BanPeer bans the peer with the given P2P public key for the given duration. Banned peers are
+// disconnected and are not allowed to connect until the ban expires or is lifted.
+//
+// In case the duration is zero, the configured default ban duration is used.
+func (p *P2P) BanPeer(id signature.PublicKey, duration time.Duration, reason string) error {
+	if duration < 0 {
+		return fmt.Errorf("worker/common/p2p: invalid ban duration: %s", duration)
+	}
+	peerID, err := publicKeyToPeerID(id)
+	if err != nil {
+		return fmt.Errorf("worker/common/p2p: failed to get peer ID from public key: %w", err)
+	}
+	if peerID == p.host.ID() {
+		return fmt.Errorf("worker/common/p2p: refusing to ban own peer")
+	}
+	if duration == 0 {
+		duration = p.bans.banDuration
+	}
+	p.bans.ban(peerID, duration, reason)
+	return nil
+}
+
+// UnbanPeer lifts the ban of the peer with the given P2P public key.
+func (p *P2P) UnbanPeer(id signature.PublicKey) error {
+	peerID, err := publicKeyToPeerID(id)
+	if err != nil {
+		return fmt.Errorf("worker/common/p2p: failed to get peer ID from public key: %w", err)
+	}
+	if !p.bans.unban(peerID) {
+		return ErrPeerNotBanned
+	}
+	return nil
+}
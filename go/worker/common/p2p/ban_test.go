@@ -0,0 +1,105 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	core "github.com/libp2p/go-libp2p-core"
+	"github.com/stretchr/testify/require"
+
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+func newTestBanManager() *banManager {
+	return &banManager{
+		bans:             make(map[core.PeerID]*BanInfo),
+		invalid:          make(map[core.PeerID]*invalidMessages),
+		scores:           make(map[core.PeerID]float64),
+		invalidThreshold: 3,
+		invalidWindow:    time.Minute,
+		scoreThreshold:   -100,
+		banDuration:      time.Hour,
+		logger:           logging.GetLogger("worker/common/p2p/bans/test"),
+	}
+}
+
+func TestBanManager(t *testing.T) {
+	require := require.New(t)
+
+	bm := newTestBanManager()
+	peerA := core.PeerID("peer-a")
+	peerB := core.PeerID("peer-b")
+
+	// Invalid messages.
+	bm.recordInvalidMessage(peerA)
+	bm.recordInvalidMessage(peerA)
+	require.False(bm.Contains(peerA), "peer should not be banned below threshold")
+	require.True(bm.InterceptPeerDial(peerA))
+	bm.recordInvalidMessage(peerA)
+	require.True(bm.Contains(peerA), "peer should be banned after reaching threshold")
+	require.False(bm.InterceptPeerDial(peerA))
+	require.False(bm.InterceptSecured(0, peerA, nil))
+	require.EqualValues([]core.PeerID{peerA}, bm.banned())
+
+	// Unban.
+	require.True(bm.unban(peerA), "unban should succeed for banned peer")
+	require.False(bm.unban(peerA), "unban should fail for non-banned peer")
+	require.False(bm.Contains(peerA))
+
+	// Scores.
+	bm.inspectScores(map[core.PeerID]float64{peerA: 10, peerB: -200})
+	require.False(bm.Contains(peerA))
+	require.True(bm.Contains(peerB), "peer with low score should be banned")
+	require.EqualValues(10, bm.score(peerA))
+
+	// Expiry.
+	bm.ban(peerA, -time.Second, "expired")
+	require.False(bm.Contains(peerA), "expired ban should not apply")
+	require.Nil(bm.banInfo(peerA))
+}
+
+func TestBanPeer(t *testing.T) {
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := newTestP2P(ctx, t, libp2p.NoListenAddrs)
+	id := memorySigner.NewTestSigner("ban test: peer").Public()
+	peerID, err := publicKeyToPeerID(id)
+	require.NoError(err, "publicKeyToPeerID")
+
+	err = p.BanPeer(id, -time.Second, "negative")
+	require.Error(err, "BanPeer should reject negative durations")
+	require.False(p.bans.Contains(peerID))
+
+	err = p.BanPeer(id, 0, "default")
+	require.NoError(err, "BanPeer")
+	info := p.bans.banInfo(peerID)
+	require.NotNil(info, "peer should be banned")
+	require.WithinDuration(time.Now().Add(p.bans.banDuration), info.Until, time.Minute, "default ban duration should be used")
+}
+
+func TestBanManagerConfig(t *testing.T) {
+	require := require.New(t)
+
+	cfg := &banConfig{
+		invalidThreshold: 3,
+		invalidWindow:    time.Minute,
+		scoreThreshold:   -100,
+		banDuration:      time.Hour,
+	}
+	bm, err := newBanManager(cfg)
+	require.NoError(err, "newBanManager")
+	require.Equal(time.Hour, bm.banDuration)
+	require.Equal(time.Minute, bm.invalidWindow)
+
+	_, err = newBanManager(&banConfig{invalidWindow: time.Minute})
+	require.Error(err, "zero ban duration should be rejected")
+
+	_, err = newBanManager(&banConfig{invalidWindow: -time.Minute, banDuration: time.Hour})
+	require.Error(err, "negative ban window should be rejected")
+}
@@ -0,0 +1,192 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	core "github.com/libp2p/go-libp2p-core"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/protocol"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
+)
+
+const (
+	// batchProtocolID is the libp2p protocol identifier of the proposed batch retrieval protocol.
+	batchProtocolID = protocol.ID("/oasis/p2p/batch/1.0.0")
+
+	// batchStreamTimeout is the maximum duration of a single batch retrieval request.
+	batchStreamTimeout = 5 * time.Second
+	// batchMaxExtraPeers is the maximum number of peers, other than the given ones, that are asked
+	// for a batch.
+	batchMaxExtraPeers = 3
+
+	batchCodecModule = "worker/common/p2p/batch"
+)
+
+// ErrBatchNotFound is the error returned when no peer was able to provide a proposed batch.
+var ErrBatchNotFound = errors.New("worker/common/p2p: batch not found")
+
+// BatchProvider provides the transactions of proposed batches to peers.
+type BatchProvider interface {
+	// GetProposedBatch returns the transactions of the proposed batch with the given I/O root.
+	GetProposedBatch(ioRoot hash.Hash) (transaction.RawBatch, bool)
+}
+
+type batchRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	IORoot    hash.Hash        `json:"io_root"`
+}
+
+type batchResponse struct {
+	Batch transaction.RawBatch `json:"batch,omitempty"`
+}
+
+// RegisterBatchProvider registers a provider of proposed batches of the given runtime.
+func (p *P2P) RegisterBatchProvider(runtimeID common.Namespace, provider BatchProvider) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.batchProviders[runtimeID] = provider
+}
+
+func (p *P2P) handleBatchStream(s network.Stream) {
+	defer s.Close()
+	_ = s.SetDeadline(time.Now().Add(batchStreamTimeout))
+
+	codec := cbor.NewMessageCodec(s, batchCodecModule)
+
+	var rq batchRequest
+	if err := codec.Read(&rq); err != nil {
+		p.logger.Debug("failed to read batch request",
+			"err", err,
+			"peer_id", s.Conn().RemotePeer(),
+		)
+		_ = s.Reset()
+		return
+	}
+
+	p.RLock()
+	provider := p.batchProviders[rq.RuntimeID]
+	p.RUnlock()
+
+	var rsp batchResponse
+	if provider != nil {
+		rsp.Batch, _ = provider.GetProposedBatch(rq.IORoot)
+	}
+	if err := codec.Write(&rsp); err != nil {
+		p.logger.Debug("failed to write batch response",
+			"err", err,
+			"peer_id", s.Conn().RemotePeer(),
+		)
+		_ = s.Reset()
+	}
+}
+
+func (p *P2P) requestBatch(ctx context.Context, peerID core.PeerID, rq *batchRequest) (transaction.RawBatch, error) {
+	ctx, cancel := context.WithTimeout(ctx, batchStreamTimeout)
+	defer cancel()
+
+	s, err := p.host.NewStream(ctx, peerID, batchProtocolID)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.SetDeadline(deadline)
+	}
+
+	codec := cbor.NewMessageCodec(s, batchCodecModule)
+	if err = codec.Write(rq); err != nil {
+		_ = s.Reset()
+		return nil, err
+	}
+	var rsp batchResponse
+	if err = codec.Read(&rsp); err != nil {
+		_ = s.Reset()
+		return nil, err
+	}
+	if rsp.Batch == nil {
+		return nil, ErrBatchNotFound
+	}
+	return rsp.Batch, nil
+}
+
+// FetchBatch fetches the transactions of a proposed batch of the given runtime directly from peers.
+//
+// The given peers (e.g., the proposer) are asked first, followed by a few other peers on the
+// runtime's topic that support the batch retrieval protocol. Each received batch is checked using
+// the given verification function and the first valid batch is returned.
+func (p *P2P) FetchBatch(
+	ctx context.Context,
+	runtimeID common.Namespace,
+	ioRoot hash.Hash,
+	peers []signature.PublicKey,
+	verify func(transaction.RawBatch) error,
+) (transaction.RawBatch, error) {
+	var candidates []core.PeerID
+	seen := make(map[core.PeerID]bool)
+	for _, id := range peers {
+		peerID, err := publicKeyToPeerID(id)
+		if err != nil || seen[peerID] {
+			continue
+		}
+		seen[peerID] = true
+		candidates = append(candidates, peerID)
+	}
+	var extra int
+	for _, peerID := range p.pubsub.ListPeers(p.topicIDForRuntime(runtimeID)) {
+		if extra >= batchMaxExtraPeers {
+			break
+		}
+		if seen[peerID] {
+			continue
+		}
+		if supported, _ := p.host.Peerstore().SupportsProtocols(peerID, string(batchProtocolID)); len(supported) == 0 {
+			continue
+		}
+		seen[peerID] = true
+		candidates = append(candidates, peerID)
+		extra++
+	}
+
+	rq := &batchRequest{
+		RuntimeID: runtimeID,
+		IORoot:    ioRoot,
+	}
+	for _, peerID := range candidates {
+		if peerID == p.host.ID() {
+			continue
+		}
+
+		batch, err := p.requestBatch(ctx, peerID, rq)
+		if err != nil {
+			p.logger.Debug("failed to fetch batch from peer",
+				"err", err,
+				"peer_id", peerID,
+				"io_root", ioRoot,
+			)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+		if err = verify(batch); err != nil {
+			p.logger.Warn("received invalid batch from peer",
+				"err", err,
+				"peer_id", peerID,
+				"io_root", ioRoot,
+			)
+			p.bans.recordInvalidMessage(peerID)
+			continue
+		}
+		return batch, nil
+	}
+	return nil, fmt.Errorf("%w (asked %d peers)", ErrBatchNotFound, len(candidates))
+}
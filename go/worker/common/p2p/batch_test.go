@@ -0,0 +1,62 @@
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
+)
+
+type testBatchProvider struct {
+	batches map[hash.Hash]transaction.RawBatch
+}
+
+func (bp *testBatchProvider) GetProposedBatch(ioRoot hash.Hash) (transaction.RawBatch, bool) {
+	batch, ok := bp.batches[ioRoot]
+	return batch, ok
+}
+
+func TestFetchBatch(t *testing.T) {
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listenAddr := libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0")
+	proposerSigner := memorySigner.NewTestSigner("batch test: proposer")
+	proposer := newTestP2P(ctx, t, listenAddr, libp2p.Identity(signerToPrivKey(proposerSigner)))
+	executor := newTestP2P(ctx, t, listenAddr, libp2p.Identity(signerToPrivKey(memorySigner.NewTestSigner("batch test: executor"))))
+	proposer.host.SetStreamHandler(batchProtocolID, proposer.handleBatchStream)
+
+	var runtimeID common.Namespace
+	ioRoot := hash.NewFromBytes([]byte("io root"))
+	batch := transaction.RawBatch{[]byte("tx 1"), []byte("tx 2")}
+	proposer.RegisterBatchProvider(runtimeID, &testBatchProvider{
+		batches: map[hash.Hash]transaction.RawBatch{ioRoot: batch},
+	})
+
+	err := executor.host.Connect(ctx, peer.AddrInfo{ID: proposer.host.ID(), Addrs: proposer.host.Addrs()})
+	require.NoError(err, "Connect")
+	proposerID := proposerSigner.Public()
+
+	acceptAll := func(transaction.RawBatch) error { return nil }
+	fetched, err := executor.FetchBatch(ctx, runtimeID, ioRoot, []signature.PublicKey{proposerID}, acceptAll)
+	require.NoError(err, "FetchBatch")
+	require.EqualValues(batch, fetched, "fetched batch should match the proposed batch")
+
+	_, err = executor.FetchBatch(ctx, runtimeID, hash.NewFromBytes([]byte("unknown")), []signature.PublicKey{proposerID}, acceptAll)
+	require.ErrorIs(err, ErrBatchNotFound, "unknown batches should not be found")
+
+	rejectAll := func(transaction.RawBatch) error { return fmt.Errorf("invalid batch") }
+	_, err = executor.FetchBatch(ctx, runtimeID, ioRoot, []signature.PublicKey{proposerID}, rejectAll)
+	require.ErrorIs(err, ErrBatchNotFound, "invalid batches should be rejected")
+}
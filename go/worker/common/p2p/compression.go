@@ -0,0 +1,182 @@
+package p2p
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	core "github.com/libp2p/go-libp2p-core"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+)
+
+const (
+	// compressedMessageMarker is the first byte of a compressed message. Since it is the CBOR
+	// "break" code, it cannot appear at the start of a valid uncompressed CBOR message.
+	compressedMessageMarker = 0xff
+
+	// compressedTopicSuffix is the suffix of the topic on which compressed messages are published.
+	compressedTopicSuffix = "compressed"
+
+	// maxDecompressedMessageSize is the maximum size of a decompressed message.
+	maxDecompressedMessageSize = 64 * 1024 * 1024
+)
+
+var (
+	compressionRatio = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "oasis_worker_p2p_compression_ratio",
+			Help:    "Ratio of compressed to uncompressed size of published P2P messages.",
+			Buckets: []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1.0, 1.5},
+		},
+		[]string{"algorithm"},
+	)
+	compressionFallbackCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_p2p_compression_fallback_count",
+			Help: "Number of P2P messages published uncompressed because a topic peer does not support compression.",
+		},
+		[]string{"algorithm"},
+	)
+
+	compressionCollectors = []prometheus.Collector{
+		compressionRatio,
+		compressionFallbackCount,
+	}
+
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecompressedMessageSize))
+)
+
+// Compression is a P2P message compression algorithm.
+type Compression uint8
+
+const (
+	// CompressionNone disables compression.
+	CompressionNone Compression = 0
+	// CompressionSnappy is the snappy compression algorithm.
+	CompressionSnappy Compression = 1
+	// CompressionZstd is the zstd compression algorithm.
+	CompressionZstd Compression = 2
+)
+
+// String returns a string representation of the compression algorithm.
+func (c Compression) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionSnappy:
+		return "snappy"
+	case CompressionZstd:
+		return "zstd"
+	default:
+		return fmt.Sprintf("[unknown compression: %d]", uint8(c))
+	}
+}
+
+// UnmarshalText decodes a text marshaled compression algorithm.
+func (c *Compression) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "", "none":
+		*c = CompressionNone
+	case "snappy":
+		*c = CompressionSnappy
+	case "zstd":
+		*c = CompressionZstd
+	default:
+		return fmt.Errorf("worker/common/p2p: unsupported compression algorithm: '%s'", string(text))
+	}
+	return nil
+}
+
+// supportedCompressions are the algorithms that the node is able to decompress.
+var supportedCompressions = []Compression{CompressionSnappy, CompressionZstd}
+
+// compressMessage compresses the given raw message.
+func compressMessage(c Compression, rawMsg []byte) []byte {
+	var compressed []byte
+	switch c {
+	case CompressionSnappy:
+		compressed = snappy.Encode(nil, rawMsg)
+	case CompressionZstd:
+		compressed = zstdEncoder.EncodeAll(rawMsg, nil)
+	default:
+		return rawMsg
+	}
+
+	return append([]byte{compressedMessageMarker, byte(c)}, compressed...)
+}
+
+// isCompressed returns true iff the given raw message is marked as compressed.
+func isCompressed(data []byte) bool {
+	return len(data) > 0 && data[0] == compressedMessageMarker
+}
+
+// decompressMessage decompresses the given compressed message.
+func decompressMessage(data []byte) ([]byte, error) {
+	if !isCompressed(data) || len(data) < 2 {
+		return nil, fmt.Errorf("worker/common/p2p: malformed compressed message")
+	}
+
+	compressed := data[2:]
+	switch c := Compression(data[1]); c {
+	case CompressionSnappy:
+		n, err := snappy.DecodedLen(compressed)
+		if err != nil {
+			return nil, fmt.Errorf("worker/common/p2p: malformed snappy message: %w", err)
+		}
+		if n > maxDecompressedMessageSize {
+			return nil, fmt.Errorf("worker/common/p2p: decompressed message too large (%d bytes)", n)
+		}
+		return snappy.Decode(nil, compressed)
+	case CompressionZstd:
+		return zstdDecoder.DecodeAll(compressed, nil)
+	default:
+		return nil, fmt.Errorf("worker/common/p2p: unsupported message compression: %s", c)
+	}
+}
+
+// messageID returns the gossipsub message identifier of the given message.
+//
+// The identifier is derived from the message as received, without decompressing it, since it is
+// computed for every received message (including duplicates) before any validation. Compressed
+// and uncompressed copies of the same message are published on different topics.
+func messageID(pmsg *pb.Message) string {
+	h := hash.NewFromBytes(pmsg.Data)
+	return string(h[:])
+}
+
+// maybeCompress compresses the given raw message using the configured compression algorithm in
+// case the message is large enough and all of the topic peers support decompressing it. It returns
+// the topic the (possibly compressed) message should be published on.
+//
+// Compressed messages are only ever published on the compressed topic which is only joined by
+// nodes able to decompress them, so that they are never relayed to peers that do not support
+// compression.
+func (h *topicHandler) maybeCompress(rawMsg []byte) (*pubsub.Topic, []byte) {
+	c := h.p2p.compression
+	if c == CompressionNone || len(rawMsg) < h.p2p.compressionMinSize {
+		return h.topic, rawMsg
+	}
+
+	compressedPeers := make(map[core.PeerID]bool)
+	for _, peerID := range h.compressedTopic.ListPeers() {
+		compressedPeers[peerID] = true
+	}
+	for _, peerID := range h.topic.ListPeers() {
+		if !compressedPeers[peerID] {
+			compressionFallbackCount.With(prometheus.Labels{"algorithm": c.String()}).Inc()
+			return h.topic, rawMsg
+		}
+	}
+
+	compressed := compressMessage(c, rawMsg)
+	compressionRatio.With(prometheus.Labels{"algorithm": c.String()}).Observe(
+		float64(len(compressed)) / float64(len(rawMsg)),
+	)
+	return h.compressedTopic, compressed
+}
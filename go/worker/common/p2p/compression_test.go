@@ -0,0 +1,165 @@
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	executor "github.com/oasisprotocol/oasis-core/go/worker/compute/executor/api"
+)
+
+func TestCompression(t *testing.T) {
+	require := require.New(t)
+
+	rawMsg := cbor.Marshal(&Message{GroupVersion: 42})
+	large := bytes.Repeat([]byte("oasis"), 1024)
+
+	_, err := decompressMessage(rawMsg)
+	require.Error(err, "decompressMessage should fail for uncompressed messages")
+
+	for _, c := range supportedCompressions {
+		compressed := compressMessage(c, large)
+		require.True(isCompressed(compressed), "compressed message should be marked")
+		require.True(len(compressed) < len(large), "compressed message should be smaller")
+
+		data, err := decompressMessage(compressed)
+		require.NoError(err, "decompressMessage(%s)", c)
+		require.EqualValues(large, data)
+
+		// Message identifiers should be derived from the wire bytes.
+		h := hash.NewFromBytes(compressed)
+		require.Equal(string(h[:]), messageID(&pb.Message{Data: compressed}),
+			"message identifiers should not require decompression (%s)", c)
+	}
+
+	_, err = decompressMessage([]byte{compressedMessageMarker, 42, 1, 2, 3})
+	require.Error(err, "decompressMessage should fail for unknown algorithms")
+
+	var c Compression
+	require.NoError(c.UnmarshalText([]byte("zstd")))
+	require.Equal(CompressionZstd, c)
+	require.Error(c.UnmarshalText([]byte("lz4")))
+}
+
+type recordingHandler struct {
+	BaseHandler
+
+	sync.Mutex
+	msgs []*Message
+}
+
+func (h *recordingHandler) HandlePeerMessage(peerID signature.PublicKey, msg *Message, isOwn bool) error {
+	h.Lock()
+	defer h.Unlock()
+	h.msgs = append(h.msgs, msg)
+	return nil
+}
+
+func (h *recordingHandler) received() int {
+	h.Lock()
+	defer h.Unlock()
+	return len(h.msgs)
+}
+
+func hasPeer(topic *pubsub.Topic, peerID peer.ID) bool {
+	for _, p := range topic.ListPeers() {
+		if p == peerID {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCompressionTopics(t *testing.T) {
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var runtimeID common.Namespace
+	listenAddr := libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0")
+	msg := &Message{Tx: &executor.Tx{Data: bytes.Repeat([]byte("oasis"), 1024)}}
+	rawMsg := cbor.Marshal(msg)
+
+	publisher := newTestP2P(ctx, t, listenAddr, libp2p.Identity(signerToPrivKey(memorySigner.NewTestSigner("compression test: publisher"))))
+	publisher.compression = CompressionZstd
+	publisher.RegisterHandler(runtimeID, &BaseHandler{})
+	receiver := newTestP2P(ctx, t, listenAddr, libp2p.Identity(signerToPrivKey(memorySigner.NewTestSigner("compression test: receiver"))))
+	handler := &recordingHandler{}
+	receiver.RegisterHandler(runtimeID, handler)
+
+	err := receiver.host.Connect(ctx, peer.AddrInfo{ID: publisher.host.ID(), Addrs: publisher.host.Addrs()})
+	require.NoError(err, "Connect")
+
+	h := publisher.topics[runtimeID]
+	require.Eventually(func() bool {
+		return hasPeer(h.topic, receiver.host.ID()) && hasPeer(h.compressedTopic, receiver.host.ID())
+	}, 10*time.Second, 10*time.Millisecond, "receiver should join both topics")
+
+	// All peers support compression, so the compressed topic should be used.
+	topic, data := h.maybeCompress(rawMsg)
+	require.Equal(h.compressedTopic, topic, "compressed topic should be used")
+	require.True(isCompressed(data), "message should be compressed")
+
+	err = h.publish(rawMsg)
+	require.NoError(err, "publish")
+	require.Eventually(func() bool { return handler.received() == 1 }, 10*time.Second, 10*time.Millisecond,
+		"compressed message should be received")
+
+	// An older peer that only joined the uncompressed topic.
+	oldHost, err := libp2p.New(ctx, listenAddr)
+	require.NoError(err, "libp2p.New")
+	defer oldHost.Close()
+	oldPubsub, err := pubsub.NewGossipSub(ctx, oldHost, pubsub.WithMessageIdFn(messageID))
+	require.NoError(err, "NewGossipSub")
+	oldTopic, err := oldPubsub.Join(publisher.topicIDForRuntime(runtimeID))
+	require.NoError(err, "Join")
+	sub, err := oldTopic.Subscribe()
+	require.NoError(err, "Subscribe")
+	defer sub.Cancel()
+
+	err = oldHost.Connect(ctx, peer.AddrInfo{ID: publisher.host.ID(), Addrs: publisher.host.Addrs()})
+	require.NoError(err, "Connect")
+	require.Eventually(func() bool {
+		return hasPeer(h.topic, oldHost.ID())
+	}, 10*time.Second, 10*time.Millisecond, "old peer should join the uncompressed topic")
+
+	// Since not all peers support compression, the uncompressed topic should be used.
+	topic, data = h.maybeCompress(rawMsg)
+	require.Equal(h.topic, topic, "uncompressed topic should be used")
+	require.EqualValues(rawMsg, data, "message should not be compressed")
+
+	msg.GroupVersion = 1
+	rawMsg = cbor.Marshal(msg)
+	err = h.publish(rawMsg)
+	require.NoError(err, "publish")
+
+	recvCtx, recvCancel := context.WithTimeout(ctx, 10*time.Second)
+	defer recvCancel()
+	for {
+		var envelope *pubsub.Message
+		envelope, err = sub.Next(recvCtx)
+		require.NoError(err, "old peer should receive the message")
+		require.False(isCompressed(envelope.Data), "old peer should never receive compressed messages")
+		var decoded Message
+		require.NoError(cbor.Unmarshal(envelope.Data, &decoded), "old peer should be able to decode the message")
+		if decoded.GroupVersion == 1 {
+			break
+		}
+	}
+	require.Eventually(func() bool { return handler.received() == 2 }, 10*time.Second, 10*time.Millisecond,
+		"uncompressed message should be received")
+}
@@ -0,0 +1,153 @@
+package p2p
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cache/lru"
+)
+
+var (
+	duplicateMessageCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_p2p_duplicate_message_count",
+			Help: "Number of received P2P messages dropped as already seen.",
+		},
+		[]string{"runtime"},
+	)
+	undeliverableMessageCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_p2p_undeliverable_message_count",
+			Help: "Number of P2P messages dropped because the local subscriber was too slow.",
+		},
+		[]string{"runtime"},
+	)
+
+	dedupCollectors = []prometheus.Collector{
+		duplicateMessageCount,
+		undeliverableMessageCount,
+	}
+
+	_ pubsub.RawTracer = (*dedupTracer)(nil)
+)
+
+// runtimeForTopic returns the runtime identifier part of the given topic identifier.
+func runtimeForTopic(topic string) string {
+	topic = strings.TrimSuffix(topic, "/"+compressedTopicSuffix)
+	return topic[strings.LastIndex(topic, "/")+1:]
+}
+
+// dedupTracer is a gossipsub tracer exporting duplicate suppression statistics.
+type dedupTracer struct{}
+
+// Implements pubsub.RawTracer.
+func (t *dedupTracer) DuplicateMessage(msg *pubsub.Message) {
+	duplicateMessageCount.WithLabelValues(runtimeForTopic(msg.GetTopic())).Inc()
+}
+
+// Implements pubsub.RawTracer.
+func (t *dedupTracer) UndeliverableMessage(msg *pubsub.Message) {
+	undeliverableMessageCount.WithLabelValues(runtimeForTopic(msg.GetTopic())).Inc()
+}
+
+// Implements pubsub.RawTracer.
+func (t *dedupTracer) AddPeer(peer.ID, protocol.ID) {}
+
+// Implements pubsub.RawTracer.
+func (t *dedupTracer) RemovePeer(peer.ID) {}
+
+// Implements pubsub.RawTracer.
+func (t *dedupTracer) Join(string) {}
+
+// Implements pubsub.RawTracer.
+func (t *dedupTracer) Leave(string) {}
+
+// Implements pubsub.RawTracer.
+func (t *dedupTracer) Graft(peer.ID, string) {}
+
+// Implements pubsub.RawTracer.
+func (t *dedupTracer) Prune(peer.ID, string) {}
+
+// Implements pubsub.RawTracer.
+func (t *dedupTracer) ValidateMessage(*pubsub.Message) {}
+
+// Implements pubsub.RawTracer.
+func (t *dedupTracer) DeliverMessage(*pubsub.Message) {}
+
+// Implements pubsub.RawTracer.
+func (t *dedupTracer) RejectMessage(*pubsub.Message, string) {}
+
+// Implements pubsub.RawTracer.
+func (t *dedupTracer) ThrottlePeer(peer.ID) {}
+
+// Implements pubsub.RawTracer.
+func (t *dedupTracer) RecvRPC(*pubsub.RPC) {}
+
+// Implements pubsub.RawTracer.
+func (t *dedupTracer) SendRPC(*pubsub.RPC, peer.ID) {}
+
+// Implements pubsub.RawTracer.
+func (t *dedupTracer) DropRPC(*pubsub.RPC, peer.ID) {}
+
+// gossipSubParams returns the gossipsub router parameters for the given message history length
+// and gossip window (both in heartbeat intervals).
+func gossipSubParams(historyLength, historyGossip int) (pubsub.GossipSubParams, error) {
+	params := pubsub.DefaultGossipSubParams()
+	params.HistoryLength = historyLength
+	params.HistoryGossip = historyGossip
+
+	if params.HistoryLength <= 0 {
+		return params, fmt.Errorf("worker/common/p2p: gossip history length must be positive")
+	}
+	if params.HistoryGossip <= 0 || params.HistoryGossip > params.HistoryLength {
+		return params, fmt.Errorf("worker/common/p2p: gossip history window must be positive and at most the history length")
+	}
+	return params, nil
+}
+
+// seenMessages is a bounded cache of identifiers of messages that passed validation, used to
+// suppress duplicates.
+//
+// Note: the gossipsub router additionally remembers all message identifiers for a fixed window
+// (pubsub.TimeCacheDuration) which can only be configured process-wide, so the cache only affects
+// duplicates that arrive after that window.
+type seenMessages struct {
+	sync.Mutex
+
+	cache *lru.Cache
+}
+
+// checkAndMark returns true iff the message with the given identifier has already been seen and
+// otherwise marks it as seen.
+func (s *seenMessages) checkAndMark(id string) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.cache.Get(id); ok {
+		return true
+	}
+	_ = s.cache.Put(id, struct{}{})
+	return false
+}
+
+func newSeenMessages(ttl time.Duration, size uint64) (*seenMessages, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("worker/common/p2p: seen messages TTL must be positive")
+	}
+	if size == 0 {
+		return nil, fmt.Errorf("worker/common/p2p: seen messages cache size must be positive")
+	}
+
+	cache, err := lru.New(lru.Capacity(size, false), lru.TTL(ttl))
+	if err != nil {
+		return nil, fmt.Errorf("worker/common/p2p: failed to create seen messages cache: %w", err)
+	}
+	return &seenMessages{cache: cache}, nil
+}
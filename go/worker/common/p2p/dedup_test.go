@@ -0,0 +1,59 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntimeForTopic(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal("8000000000000000000000000000000000000000000000000000000000000000",
+		runtimeForTopic("chain-context/4/8000000000000000000000000000000000000000000000000000000000000000"),
+	)
+	require.Equal("8000000000000000000000000000000000000000000000000000000000000000",
+		runtimeForTopic("chain-context/4/8000000000000000000000000000000000000000000000000000000000000000/compressed"),
+		"compressed topics should be attributed to their runtime",
+	)
+	require.Equal("foo", runtimeForTopic("foo"))
+}
+
+func TestSeenMessages(t *testing.T) {
+	require := require.New(t)
+
+	_, err := newSeenMessages(0, 10)
+	require.Error(err, "zero TTL should be rejected")
+	_, err = newSeenMessages(time.Minute, 0)
+	require.Error(err, "zero size should be rejected")
+
+	seen, err := newSeenMessages(time.Minute, 2)
+	require.NoError(err, "newSeenMessages")
+	require.False(seen.checkAndMark("a"), "first message should not be a duplicate")
+	require.True(seen.checkAndMark("a"), "repeated message should be a duplicate")
+	require.False(seen.checkAndMark("b"))
+	require.False(seen.checkAndMark("c"))
+	require.False(seen.checkAndMark("a"), "cache size should be bounded")
+
+	seen, err = newSeenMessages(10*time.Millisecond, 10)
+	require.NoError(err, "newSeenMessages")
+	require.False(seen.checkAndMark("a"))
+	time.Sleep(20 * time.Millisecond)
+	require.False(seen.checkAndMark("a"), "seen messages should expire")
+}
+
+func TestGossipSubParams(t *testing.T) {
+	require := require.New(t)
+
+	params, err := gossipSubParams(10, 4)
+	require.NoError(err, "gossipSubParams")
+	require.Equal(10, params.HistoryLength)
+	require.Equal(4, params.HistoryGossip)
+
+	_, err = gossipSubParams(10, 11)
+	require.Error(err, "gossip window larger than history length should be rejected")
+
+	_, err = gossipSubParams(0, 4)
+	require.Error(err, "zero history length should be rejected")
+}
@@ -63,7 +63,8 @@ func (h *BaseHandler) HandlePeerMessage(peerID signature.PublicKey, msg *Message
 type topicHandler struct {
 	handlersLock sync.RWMutex
 
-	ctx context.Context
+	ctx      context.Context
+	cancelFn context.CancelFunc
 
 	p2p *P2P
 
@@ -72,10 +73,21 @@ type topicHandler struct {
 	cancelRelay pubsub.RelayCancelFunc
 	handlers    []Handler
 
+	compressedTopic       *pubsub.Topic
+	cancelCompressedRelay pubsub.RelayCancelFunc
+
+	runtimeID common.Namespace
+
 	numWorkers uint64
 
 	pendingQueue chan *rawMessage
 
+	// limiter limits the rate at which messages are published. In case it is set, messages are
+	// published asynchronously via publishQueue so that callers (which may be holding locks) are
+	// never blocked by throttling.
+	limiter      *bandwidthLimiter
+	publishQueue chan []byte
+
 	logger *logging.Logger
 }
 
@@ -85,7 +97,28 @@ type queuedMsg struct {
 	msg    *Message
 }
 
-func (h *topicHandler) topicMessageValidator(ctx context.Context, unused core.PeerID, envelope *pubsub.Message) bool {
+func (h *topicHandler) topicMessageValidator(ctx context.Context, unused core.PeerID, envelope *pubsub.Message) pubsub.ValidationResult {
+	return h.dedupAndValidateMessage(envelope, false)
+}
+
+func (h *topicHandler) compressedTopicMessageValidator(ctx context.Context, unused core.PeerID, envelope *pubsub.Message) pubsub.ValidationResult {
+	return h.dedupAndValidateMessage(envelope, true)
+}
+
+func (h *topicHandler) dedupAndValidateMessage(envelope *pubsub.Message, compressed bool) pubsub.ValidationResult {
+	// Ignore (and do not relay) messages that have already been seen so that they are not
+	// dispatched again.
+	if h.p2p.seenMessages.checkAndMark(messageID(envelope.Message)) {
+		duplicateMessageCount.WithLabelValues(h.runtimeID.String()).Inc()
+		return pubsub.ValidationIgnore
+	}
+	if !h.validateMessage(envelope, compressed) {
+		return pubsub.ValidationReject
+	}
+	return pubsub.ValidationAccept
+}
+
+func (h *topicHandler) validateMessage(envelope *pubsub.Message, compressed bool) bool {
 	// Tease apart the pubsub message envelope and convert it to
 	// the expected format.
 
@@ -94,6 +127,10 @@ func (h *topicHandler) topicMessageValidator(ctx context.Context, unused core.Pe
 		"peer_id", peerID,
 		"received_from", envelope.ReceivedFrom,
 	)
+	if envelope.ReceivedFrom != h.p2p.host.ID() {
+		receivedMessageCount.WithLabelValues(h.runtimeID.String()).Inc()
+		messageSize.WithLabelValues(h.runtimeID.String(), directionIn).Observe(float64(len(envelope.GetData())))
+	}
 
 	id, err := peerIDToPublicKey(peerID)
 	if err != nil {
@@ -101,15 +138,39 @@ func (h *topicHandler) topicMessageValidator(ctx context.Context, unused core.Pe
 			"err", err,
 			"peer_id", peerID,
 		)
+		h.recordValidationFailure(envelope.ReceivedFrom, validationFailureMalformedPeerID)
 		return false
 	}
 
+	// Compressed messages are only allowed on the compressed topic so that they are never relayed
+	// to peers that are unable to decompress them.
+	data := envelope.GetData()
+	if compressed {
+		if data, err = decompressMessage(data); err != nil {
+			h.logger.Error("error while decompressing message from peer",
+				"err", err,
+				"peer_id", peerID,
+			)
+			h.recordValidationFailure(envelope.ReceivedFrom, validationFailureDecompression)
+			return false
+		}
+	}
+
 	var msg Message
-	if err = cbor.Unmarshal(envelope.GetData(), &msg); err != nil {
+	if err = cbor.UnmarshalIngress(data, &msg); err != nil {
 		h.logger.Error("error while parsing message from peer",
 			"err", err,
 			"peer_id", peerID,
 		)
+		h.recordValidationFailure(envelope.ReceivedFrom, validationFailureDecoding)
+		return false
+	}
+
+	if peerID != h.p2p.host.ID() && !h.p2p.allowlists.isAllowed(h.runtimeID, peerID, &msg) {
+		h.logger.Debug("rejecting committee message from peer not in allowlist",
+			"peer_id", peerID,
+		)
+		validationFailureCount.WithLabelValues(h.runtimeID.String(), validationFailureNotAllowed).Inc()
 		return false
 	}
 
@@ -125,6 +186,7 @@ func (h *topicHandler) topicMessageValidator(ctx context.Context, unused core.Pe
 
 	// If the message will never become valid, do not relay.
 	if err = h.dispatchMessage(peerID, m, true); !p2pError.ShouldRelay(err) {
+		validationFailureCount.WithLabelValues(h.runtimeID.String(), validationFailureDispatch).Inc()
 		return false
 	}
 
@@ -134,6 +196,13 @@ func (h *topicHandler) topicMessageValidator(ctx context.Context, unused core.Pe
 	return true
 }
 
+// recordValidationFailure records a message validation failure caused by a malformed message that
+// has been received from the given peer.
+func (h *topicHandler) recordValidationFailure(receivedFrom core.PeerID, reason string) {
+	validationFailureCount.WithLabelValues(h.runtimeID.String(), reason).Inc()
+	h.p2p.bans.recordInvalidMessage(receivedFrom)
+}
+
 func (h *topicHandler) dispatchMessage(peerID core.PeerID, m *queuedMsg, isInitial bool) (retErr error) {
 	defer func() {
 		if retErr == nil || !isInitial {
@@ -259,7 +328,52 @@ func (h *topicHandler) tryPublishing(rawMsg []byte) error {
 		}
 	}
 
-	return h.topic.Publish(h.ctx, rawMsg)
+	return h.publish(rawMsg)
+}
+
+func (h *topicHandler) publish(rawMsg []byte) error {
+	if h.limiter == nil {
+		return h.publishNow(rawMsg)
+	}
+
+	select {
+	case h.publishQueue <- rawMsg:
+		return nil
+	default:
+		return fmt.Errorf("worker/common/p2p: outbound message queue overflow")
+	}
+}
+
+func (h *topicHandler) publishNow(rawMsg []byte) error {
+	topic, data := h.maybeCompress(rawMsg)
+	if err := h.limiter.wait(h.ctx, len(data)); err != nil {
+		return fmt.Errorf("worker/common/p2p: failed to wait for topic bandwidth: %w", err)
+	}
+	if err := topic.Publish(h.ctx, data); err != nil {
+		return err
+	}
+
+	publishedMessageCount.WithLabelValues(h.runtimeID.String()).Inc()
+	messageSize.WithLabelValues(h.runtimeID.String(), directionOut).Observe(float64(len(data)))
+	return nil
+}
+
+// publishWorker publishes throttled messages in order.
+func (h *topicHandler) publishWorker() {
+	for {
+		var rawMsg []byte
+		select {
+		case <-h.ctx.Done():
+			return
+		case rawMsg = <-h.publishQueue:
+		}
+
+		if err := h.publishNow(rawMsg); err != nil {
+			h.logger.Error("failed to publish message to the network",
+				"err", err,
+			)
+		}
+	}
 }
 
 // pendingMessagesWorker handles retrying for P2P messages when there are no connected peers.
@@ -288,7 +402,7 @@ func (h *topicHandler) pendingMessagesWorker() {
 			}
 		}
 
-		if err := h.topic.Publish(h.ctx, msg.msg); err != nil {
+		if err := h.publish(msg.msg); err != nil {
 			h.logger.Error("failed to publish message to the network",
 				"err", err,
 			)
@@ -296,35 +410,82 @@ func (h *topicHandler) pendingMessagesWorker() {
 	}
 }
 
-func newTopicHandler(p *P2P, runtimeID common.Namespace, handlers []Handler) (string, *topicHandler, error) {
+// close stops relaying the topics, cancels any pending work and closes the topics.
+func (h *topicHandler) close() error {
+	h.cancelRelay()
+	h.cancelCompressedRelay()
+	h.cancelFn()
+	if err := h.compressedTopic.Close(); err != nil {
+		return err
+	}
+	return h.topic.Close()
+}
+
+func newTopicHandler(p *P2P, runtimeID common.Namespace, handlers []Handler) (*topicHandler, error) {
 	topicID := p.topicIDForRuntime(runtimeID)
 	topic, err := p.pubsub.Join(topicID) // Note: Disallows duplicates.
 	if err != nil {
-		return "", nil, fmt.Errorf("worker/common/p2p: failed to join topic '%s': %w", topicID, err)
+		return nil, fmt.Errorf("worker/common/p2p: failed to join topic '%s': %w", topicID, err)
+	}
+	compressedTopicID := p.compressedTopicIDForRuntime(runtimeID)
+	compressedTopic, err := p.pubsub.Join(compressedTopicID)
+	if err != nil {
+		_ = topic.Close()
+		return nil, fmt.Errorf("worker/common/p2p: failed to join topic '%s': %w", compressedTopicID, err)
 	}
 
 	h := &topicHandler{
-		ctx:          p.ctx, // TODO: Should this support individual cancelation?
-		p2p:          p,
-		topic:        topic,
-		host:         p.host,
-		handlers:     handlers,
-		pendingQueue: make(chan *rawMessage, rawMsgQueueSize),
-		logger:       logging.GetLogger("worker/common/p2p/" + topicID),
+		p2p:             p,
+		topic:           topic,
+		compressedTopic: compressedTopic,
+		host:            p.host,
+		handlers:        handlers,
+		runtimeID:       runtimeID,
+		pendingQueue:    make(chan *rawMessage, rawMsgQueueSize),
+		limiter:         p.throttle.newTopicLimiter(),
+		publishQueue:    make(chan []byte, rawMsgQueueSize),
+		logger:          logging.GetLogger("worker/common/p2p/" + topicID),
+	}
+	h.ctx, h.cancelFn = context.WithCancel(p.ctx)
+	closeTopics := func() {
+		h.cancelFn()
+		_ = compressedTopic.Close()
+		_ = topic.Close()
+	}
+
+	for _, t := range []*pubsub.Topic{topic, compressedTopic} {
+		if peerScoringEnabled() {
+			if err = t.SetScoreParams(topicScoreParams()); err != nil {
+				closeTopics()
+				return nil, fmt.Errorf("worker/common/p2p: failed to set score parameters for topic '%s': %w", t, err)
+			}
+		}
 	}
-	if h.cancelRelay, err = h.topic.Relay(); err != nil {
+	if h.cancelRelay, err = topic.Relay(); err != nil {
 		// Well, ok, fine.  This should NEVER happen, but try to back out
 		// the topic subscription we just did.
 		h.logger.Error("failed to enable topic relaying",
 			"err", err,
 		)
-		_ = topic.Close()
+		closeTopics()
+
+		return nil, fmt.Errorf("worker/common/p2p: failed to relay topic '%s': %w", topicID, err)
+	}
+	if h.cancelCompressedRelay, err = compressedTopic.Relay(); err != nil {
+		h.logger.Error("failed to enable compressed topic relaying",
+			"err", err,
+		)
+		h.cancelRelay()
+		closeTopics()
 
-		return "", nil, fmt.Errorf("worker/common/p2p: failed to relay topic '%s': %w", topicID, err)
+		return nil, fmt.Errorf("worker/common/p2p: failed to relay topic '%s': %w", compressedTopicID, err)
 	}
 
 	go h.pendingMessagesWorker()
-	return topicID, h, nil
+	if h.limiter != nil {
+		go h.publishWorker()
+	}
+	return h, nil
 }
 
 func peerIDToPublicKey(peerID core.PeerID) (signature.PublicKey, error) {
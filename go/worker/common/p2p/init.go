@@ -1,6 +1,8 @@
 package p2p
 
 import (
+	"time"
+
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
@@ -14,6 +16,22 @@ const (
 
 	cfgP2pAddresses = "worker.p2p.addresses"
 
+	// CfgP2PQUICEnabled enables the QUIC transport in addition to TCP.
+	CfgP2PQUICEnabled = "worker.p2p.quic.enabled"
+
+	// CfgP2PThrottlePeerRate sets the maximum outbound gossip bandwidth towards each peer in bytes
+	// per second (0 disables).
+	CfgP2PThrottlePeerRate = "worker.p2p.throttle.peer_rate"
+	// CfgP2PThrottlePeerBurst sets the maximum number of bytes that can be sent to a peer in a
+	// burst when per-peer throttling is enabled.
+	CfgP2PThrottlePeerBurst = "worker.p2p.throttle.peer_burst"
+	// CfgP2PThrottleTopicRate sets the maximum rate at which messages are published on each
+	// runtime topic in bytes per second (0 disables).
+	CfgP2PThrottleTopicRate = "worker.p2p.throttle.topic_rate"
+	// CfgP2PThrottleTopicBurst sets the maximum number of bytes that can be published on a topic
+	// in a burst when per-topic throttling is enabled.
+	CfgP2PThrottleTopicBurst = "worker.p2p.throttle.topic_burst"
+
 	// CfgP2PPeerOutboundQueueSize sets the libp2p gossipsub buffer size for outbound messages.
 	CfgP2PPeerOutboundQueueSize = "worker.p2p.peer_outbound_queue_size"
 	// CfgP2PValidateQueueSize sets the libp2p gossipsub buffer size of the validate queue.
@@ -27,6 +45,70 @@ const (
 	// CfgP2PConnectednessLowWater sets the ratio of connected to unconnected peers at which
 	// the peer manager will try to reconnect to disconnected nodes.
 	CfgP2PConnectednessLowWater = "worker.p2p.connectedness_low_water"
+
+	// CfgP2PCompression sets the compression algorithm used for published messages.
+	CfgP2PCompression = "worker.p2p.compression"
+	// CfgP2PCompressionMinSize sets the minimum size of a message to be compressed.
+	CfgP2PCompressionMinSize = "worker.p2p.compression_min_size"
+
+	// CfgP2PSeenMessagesTTL sets how long identifiers of seen messages are remembered in order to
+	// suppress duplicates.
+	CfgP2PSeenMessagesTTL = "worker.p2p.seen_messages_ttl"
+	// CfgP2PSeenMessagesSize sets the maximum number of remembered identifiers of seen messages.
+	CfgP2PSeenMessagesSize = "worker.p2p.seen_messages_size"
+	// CfgP2PGossipHistoryLength sets the number of heartbeat intervals for which published messages
+	// are kept in the message cache.
+	CfgP2PGossipHistoryLength = "worker.p2p.gossip.history_length"
+	// CfgP2PGossipHistoryGossip sets the number of heartbeat intervals of the message cache that
+	// are advertised to peers via gossip.
+	CfgP2PGossipHistoryGossip = "worker.p2p.gossip.history_gossip"
+
+	// CfgP2PNATPortMap enables NAT port mapping via UPnP/NAT-PMP.
+	CfgP2PNATPortMap = "worker.p2p.nat.port_map"
+	// CfgP2PNATService enables the AutoNAT service which helps other peers determine whether they
+	// are behind a NAT.
+	CfgP2PNATService = "worker.p2p.nat.service"
+	// CfgP2PRelayAddresses configures the circuit relay nodes to use (as multiaddrs including the
+	// relay's peer ID) in case the node is not publicly reachable.
+	CfgP2PRelayAddresses = "worker.p2p.relay.addresses"
+	// CfgP2PRelayHop enables relaying connections for other peers.
+	CfgP2PRelayHop = "worker.p2p.relay.hop"
+
+	// CfgP2PAllowlistEnabled enables restricting the publishers of committee messages on runtime
+	// topics to the runtime's executor committee members and the operator allowlist.
+	CfgP2PAllowlistEnabled = "worker.p2p.allowlist.enabled"
+	// CfgP2PAllowlistPeers configures the P2P public keys of peers that are always allowed to
+	// publish committee messages on runtime topics.
+	CfgP2PAllowlistPeers = "worker.p2p.allowlist.peers"
+
+	// CfgP2PPeerstoreMaxPeers sets the maximum number of peers persisted across restarts.
+	CfgP2PPeerstoreMaxPeers = "worker.p2p.peerstore.max_peers"
+
+	// CfgP2PPeerScoringEnabled enables gossipsub peer scoring.
+	CfgP2PPeerScoringEnabled = "worker.p2p.peer_scoring.enabled"
+	// CfgP2PPeerScoringInvalidMessageWeight sets the (negative) score weight applied to each
+	// invalid message delivered by a peer.
+	CfgP2PPeerScoringInvalidMessageWeight = "worker.p2p.peer_scoring.invalid_message_weight"
+	// CfgP2PPeerScoringGossipThreshold sets the score below which gossip is no longer exchanged
+	// with a peer.
+	CfgP2PPeerScoringGossipThreshold = "worker.p2p.peer_scoring.gossip_threshold"
+	// CfgP2PPeerScoringPublishThreshold sets the score below which own messages are no longer
+	// published to a peer.
+	CfgP2PPeerScoringPublishThreshold = "worker.p2p.peer_scoring.publish_threshold"
+	// CfgP2PPeerScoringGraylistThreshold sets the score below which all messages from a peer are
+	// ignored.
+	CfgP2PPeerScoringGraylistThreshold = "worker.p2p.peer_scoring.graylist_threshold"
+
+	// CfgP2PBanScoreThreshold sets the peer score below which a peer is automatically banned.
+	// Only used when peer scoring is enabled.
+	CfgP2PBanScoreThreshold = "worker.p2p.ban.score_threshold"
+	// CfgP2PBanInvalidMessages sets the number of invalid messages received from a peer within
+	// the ban window after which the peer is automatically banned (0 disables).
+	CfgP2PBanInvalidMessages = "worker.p2p.ban.invalid_messages"
+	// CfgP2PBanWindow sets the window in which invalid messages are counted.
+	CfgP2PBanWindow = "worker.p2p.ban.window"
+	// CfgP2PBanDuration sets the duration of automatic peer bans.
+	CfgP2PBanDuration = "worker.p2p.ban.duration"
 )
 
 // Enabled reads our enabled flag from viper.
@@ -40,12 +122,39 @@ var Flags = flag.NewFlagSet("", flag.ContinueOnError)
 func init() {
 	Flags.Bool(CfgP2PEnabled, false, "Enable P2P worker (automatically enabled if compute worker enabled)")
 	Flags.Uint16(CfgP2pPort, 9200, "Port to use for incoming P2P connections")
-	Flags.StringSlice(cfgP2pAddresses, []string{}, "Address/port(s) to use for P2P connections when registering this node (if not set, all non-loopback local interfaces will be used)")
+	Flags.StringSlice(cfgP2pAddresses, []string{}, "Address/port(s) (IPv4 or [IPv6]) to use for P2P connections when registering this node (if not set, all non-loopback local interfaces will be used)")
+	Flags.Bool(CfgP2PQUICEnabled, false, "Enable the QUIC transport (on the P2P port) in addition to TCP")
+	Flags.Uint64(CfgP2PThrottlePeerRate, 0, "Maximum outbound gossip bandwidth towards each peer in bytes per second (0 disables)")
+	Flags.Uint64(CfgP2PThrottlePeerBurst, 4*1024*1024, "Maximum burst size in bytes of outbound gossip traffic towards each peer")
+	Flags.Uint64(CfgP2PThrottleTopicRate, 0, "Maximum publish bandwidth on each runtime topic in bytes per second (0 disables)")
+	Flags.Uint64(CfgP2PThrottleTopicBurst, 4*1024*1024, "Maximum burst size in bytes of messages published on each runtime topic")
 	Flags.Int64(CfgP2PPeerOutboundQueueSize, 32, "Set libp2p gossipsub buffer size for outbound messages")
 	Flags.Int64(CfgP2PValidateQueueSize, 32, "Set libp2p gossipsub buffer size of the validate queue")
 	Flags.Int64(CfgP2PValidateConcurrency, 1024, "Set libp2p gossipsub per topic validator concurrency limit")
 	Flags.Int64(CfgP2PValidateThrottle, 8192, "Set libp2p gossipsub validator concurrency limit")
 	Flags.Float64(CfgP2PConnectednessLowWater, 0.2, "Set the low water mark at which the peer manager will try to reconnect to peers")
+	Flags.String(CfgP2PCompression, "none", "Compression algorithm for published messages (none, snappy, zstd)")
+	Flags.Int(CfgP2PCompressionMinSize, 1024, "Minimum size in bytes of a published message to be compressed")
+	Flags.Duration(CfgP2PSeenMessagesTTL, 10*time.Minute, "Duration for which seen message identifiers are remembered for duplicate suppression")
+	Flags.Int(CfgP2PSeenMessagesSize, 65536, "Maximum number of seen message identifiers remembered for duplicate suppression")
+	Flags.Int(CfgP2PGossipHistoryLength, 5, "Number of heartbeat intervals for which messages are kept in the gossip message cache")
+	Flags.Int(CfgP2PGossipHistoryGossip, 3, "Number of heartbeat intervals of the gossip message cache advertised to peers")
+	Flags.Bool(CfgP2PNATPortMap, false, "Enable NAT port mapping via UPnP/NAT-PMP")
+	Flags.Bool(CfgP2PNATService, false, "Enable the AutoNAT service to help other peers determine their reachability")
+	Flags.StringSlice(CfgP2PRelayAddresses, []string{}, "Circuit relay node multiaddrs (including the /p2p/<peer-id> component) to use when not publicly reachable")
+	Flags.Bool(CfgP2PRelayHop, false, "Relay connections for other peers (requires the node to be publicly reachable)")
+	Flags.Bool(CfgP2PAllowlistEnabled, false, "Only accept committee messages on runtime topics from committee members and allowlisted peers")
+	Flags.StringSlice(CfgP2PAllowlistPeers, []string{}, "P2P public keys of peers always allowed to publish committee messages on runtime topics")
+	Flags.Int(CfgP2PPeerstoreMaxPeers, 200, "Maximum number of peers persisted across restarts (0 disables persistence)")
+	Flags.Bool(CfgP2PPeerScoringEnabled, false, "Enable libp2p gossipsub peer scoring")
+	Flags.Float64(CfgP2PPeerScoringInvalidMessageWeight, -100, "Set libp2p gossipsub peer score weight of invalid messages")
+	Flags.Float64(CfgP2PPeerScoringGossipThreshold, -100, "Set libp2p gossipsub peer score gossip threshold")
+	Flags.Float64(CfgP2PPeerScoringPublishThreshold, -200, "Set libp2p gossipsub peer score publish threshold")
+	Flags.Float64(CfgP2PPeerScoringGraylistThreshold, -400, "Set libp2p gossipsub peer score graylist threshold")
+	Flags.Float64(CfgP2PBanScoreThreshold, -800, "Peer score below which a peer is automatically banned")
+	Flags.Uint64(CfgP2PBanInvalidMessages, 100, "Number of invalid messages within the ban window after which a peer is automatically banned (0 disables)")
+	Flags.Duration(CfgP2PBanWindow, 1*time.Minute, "Window in which invalid peer messages are counted")
+	Flags.Duration(CfgP2PBanDuration, 1*time.Hour, "Duration of automatic peer bans")
 
 	_ = viper.BindPFlags(Flags)
 }
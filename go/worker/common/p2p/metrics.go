@@ -0,0 +1,157 @@
+package p2p
+
+import (
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// bandwidthRetention is the amount of time bandwidth statistics of idle peers and protocols are
+// retained by the bandwidth counter.
+const bandwidthRetention = 1 * time.Hour
+
+var (
+	publishedMessageCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_p2p_published_message_count",
+			Help: "Number of P2P messages published.",
+		},
+		[]string{"runtime"},
+	)
+	receivedMessageCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_p2p_received_message_count",
+			Help: "Number of P2P messages received from peers.",
+		},
+		[]string{"runtime"},
+	)
+	messageSize = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name: "oasis_worker_p2p_message_size",
+			Help: "Size of published and received P2P messages (bytes).",
+		},
+		[]string{"runtime", "direction"},
+	)
+	validationFailureCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_p2p_validation_failure_count",
+			Help: "Number of received P2P messages that failed validation.",
+		},
+		[]string{"runtime", "reason"},
+	)
+	throttleDelay = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_p2p_throttle_delay_seconds",
+			Help: "Total time outbound P2P traffic was delayed by bandwidth throttling.",
+		},
+		[]string{"scope"},
+	)
+
+	p2pCollectors = []prometheus.Collector{
+		publishedMessageCount,
+		receivedMessageCount,
+		messageSize,
+		validationFailureCount,
+		throttleDelay,
+	}
+
+	metricsOnce sync.Once
+)
+
+const (
+	directionIn  = "in"
+	directionOut = "out"
+
+	validationFailureMalformedPeerID = "malformed_peer_id"
+	validationFailureDecompression   = "decompression"
+	validationFailureDecoding        = "decoding"
+	validationFailureDispatch        = "dispatch"
+	validationFailureNotAllowed      = "not_allowed"
+)
+
+// bandwidthCollector is a Prometheus collector exporting P2P bandwidth statistics.
+//
+// Statistics are aggregated per protocol. Since the set of peers is unbounded, per-peer statistics
+// are only exported for the (bounded) set of committee members.
+type bandwidthCollector struct {
+	bwc        *metrics.BandwidthCounter
+	allowlists *allowlists
+
+	bytesDesc     *prometheus.Desc
+	rateDesc      *prometheus.Desc
+	peerBytesDesc *prometheus.Desc
+	peerRateDesc  *prometheus.Desc
+}
+
+// Implements prometheus.Collector.
+func (c *bandwidthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.bytesDesc
+	ch <- c.rateDesc
+	ch <- c.peerBytesDesc
+	ch <- c.peerRateDesc
+}
+
+// Implements prometheus.Collector.
+func (c *bandwidthCollector) Collect(ch chan<- prometheus.Metric) {
+	c.bwc.TrimIdle(time.Now().Add(-bandwidthRetention))
+
+	for protocolID, stats := range c.bwc.GetBandwidthByProtocol() {
+		protocol := string(protocolID)
+		ch <- prometheus.MustNewConstMetric(c.bytesDesc, prometheus.CounterValue, float64(stats.TotalIn), protocol, directionIn)
+		ch <- prometheus.MustNewConstMetric(c.bytesDesc, prometheus.CounterValue, float64(stats.TotalOut), protocol, directionOut)
+		ch <- prometheus.MustNewConstMetric(c.rateDesc, prometheus.GaugeValue, stats.RateIn, protocol, directionIn)
+		ch <- prometheus.MustNewConstMetric(c.rateDesc, prometheus.GaugeValue, stats.RateOut, protocol, directionOut)
+	}
+
+	for peerID := range c.allowlists.committeePeers() {
+		stats := c.bwc.GetBandwidthForPeer(peerID)
+		peer := peerID.String()
+		ch <- prometheus.MustNewConstMetric(c.peerBytesDesc, prometheus.CounterValue, float64(stats.TotalIn), peer, directionIn)
+		ch <- prometheus.MustNewConstMetric(c.peerBytesDesc, prometheus.CounterValue, float64(stats.TotalOut), peer, directionOut)
+		ch <- prometheus.MustNewConstMetric(c.peerRateDesc, prometheus.GaugeValue, stats.RateIn, peer, directionIn)
+		ch <- prometheus.MustNewConstMetric(c.peerRateDesc, prometheus.GaugeValue, stats.RateOut, peer, directionOut)
+	}
+}
+
+func newBandwidthCollector(bwc *metrics.BandwidthCounter, allowlists *allowlists) *bandwidthCollector {
+	return &bandwidthCollector{
+		bwc:        bwc,
+		allowlists: allowlists,
+		bytesDesc: prometheus.NewDesc(
+			"oasis_worker_p2p_bandwidth_bytes",
+			"Total P2P traffic exchanged with peers (bytes).",
+			[]string{"protocol", "direction"},
+			nil,
+		),
+		rateDesc: prometheus.NewDesc(
+			"oasis_worker_p2p_bandwidth_rate",
+			"Current P2P traffic rate with peers (bytes/second).",
+			[]string{"protocol", "direction"},
+			nil,
+		),
+		peerBytesDesc: prometheus.NewDesc(
+			"oasis_worker_p2p_peer_bandwidth_bytes",
+			"Total P2P traffic exchanged with committee peers (bytes).",
+			[]string{"peer", "direction"},
+			nil,
+		),
+		peerRateDesc: prometheus.NewDesc(
+			"oasis_worker_p2p_peer_bandwidth_rate",
+			"Current P2P traffic rate with committee peers (bytes/second).",
+			[]string{"peer", "direction"},
+			nil,
+		),
+	}
+}
+
+// registerMetrics registers the P2P metrics collectors.
+func registerMetrics(bwc *metrics.BandwidthCounter, allowlists *allowlists) {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(p2pCollectors...)
+		prometheus.MustRegister(compressionCollectors...)
+		prometheus.MustRegister(dedupCollectors...)
+		prometheus.MustRegister(newBandwidthCollector(bwc, allowlists))
+	})
+}
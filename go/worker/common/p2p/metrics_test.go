@@ -0,0 +1,47 @@
+package p2p
+
+import (
+	"testing"
+
+	core "github.com/libp2p/go-libp2p-core"
+	"github.com/libp2p/go-libp2p-core/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+)
+
+func TestBandwidthCollector(t *testing.T) {
+	require := require.New(t)
+
+	member := memorySigner.NewTestSigner("bandwidth test: member").Public()
+	other := memorySigner.NewTestSigner("bandwidth test: other").Public()
+	toPeerID := func(id signature.PublicKey) core.PeerID {
+		peerID, err := publicKeyToPeerID(id)
+		require.NoError(err, "publicKeyToPeerID")
+		return peerID
+	}
+
+	bwc := metrics.NewBandwidthCounter()
+	bwc.LogRecvMessageStream(100, "/test/1.0.0", toPeerID(member))
+	bwc.LogSentMessageStream(200, "/test/1.0.0", toPeerID(other))
+
+	al := &allowlists{
+		operator: make(map[core.PeerID]bool),
+		runtimes: make(map[common.Namespace]*topicAllowlist),
+	}
+	c := newBandwidthCollector(bwc, al)
+	require.Equal(4, testutil.CollectAndCount(c, "oasis_worker_p2p_bandwidth_bytes", "oasis_worker_p2p_bandwidth_rate"),
+		"statistics should be exported per protocol and direction",
+	)
+	require.Zero(testutil.CollectAndCount(c, "oasis_worker_p2p_peer_bandwidth_bytes"),
+		"per-peer statistics should not be exported without committees",
+	)
+
+	al.set(common.Namespace{}, 1, []signature.PublicKey{member})
+	require.Equal(2, testutil.CollectAndCount(c, "oasis_worker_p2p_peer_bandwidth_bytes"),
+		"per-peer statistics should only be exported for committee members",
+	)
+}
@@ -3,6 +3,7 @@ package p2p
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"sync"
@@ -10,20 +11,20 @@ import (
 
 	"github.com/libp2p/go-libp2p"
 	core "github.com/libp2p/go-libp2p-core"
+	"github.com/libp2p/go-libp2p-core/metrics"
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/transport"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
-	pb "github.com/libp2p/go-libp2p-pubsub/pb"
 	"github.com/multiformats/go-multiaddr"
 	manet "github.com/multiformats/go-multiaddr/net"
 	"github.com/spf13/viper"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
-	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	registryAPI "github.com/oasisprotocol/oasis-core/go/registry/api"
@@ -32,6 +33,9 @@ import (
 
 var allowUnroutableAddresses bool
 
+// ErrUnknownTopic is the error returned when attempting to join or leave an unknown runtime topic.
+var ErrUnknownTopic = errors.New("worker/common/p2p: unknown runtime topic")
+
 // DebugForceAllowUnroutableAddresses allows unroutable addresses.
 func DebugForceAllowUnroutableAddresses() {
 	allowUnroutableAddresses = true
@@ -48,13 +52,33 @@ type P2P struct {
 
 	host   core.Host
 	pubsub *pubsub.PubSub
+	bans   *banManager
+
+	allowlists *allowlists
+
+	compression        Compression
+	compressionMinSize int
+
+	throttle     *throttleConfig
+	seenMessages *seenMessages
 
 	registerAddresses []multiaddr.Multiaddr
 	topics            map[common.Namespace]*topicHandler
+	suspended         map[common.Namespace][]Handler
+	batchProviders    map[common.Namespace]BatchProvider
+
+	peerstore *peerstore
 
 	logger *logging.Logger
 }
 
+// Cleanup waits for the peerstore to be persisted after the P2P context has been canceled.
+func (p *P2P) Cleanup() {
+	if p.peerstore != nil {
+		<-p.peerstore.quitCh
+	}
+}
+
 // Addresses returns the P2P addresses of the node.
 func (p *P2P) Addresses() []node.Address {
 	if p == nil {
@@ -74,9 +98,13 @@ func (p *P2P) Addresses() []node.Address {
 	for _, v := range addrs {
 		netAddr, err := manet.ToNetAddr(v)
 		if err != nil {
-			panic(err)
+			// Skip addresses that cannot be registered (e.g., circuit relay addresses).
+			continue
+		}
+		tcpAddr, ok := (netAddr).(*net.TCPAddr)
+		if !ok {
+			continue
 		}
-		tcpAddr := (netAddr).(*net.TCPAddr)
 		nodeAddr := node.Address{TCPAddr: *tcpAddr}
 		if err := registryAPI.VerifyAddress(nodeAddr, allowUnroutable); err != nil {
 			continue
@@ -140,9 +168,16 @@ func (p *P2P) Publish(ctx context.Context, runtimeID common.Namespace, msg *Mess
 	rawMsg := cbor.Marshal(msg)
 
 	p.RLock()
-	defer p.RUnlock()
-
 	h := p.topics[runtimeID]
+	_, suspended := p.suspended[runtimeID]
+	p.RUnlock()
+
+	if h == nil && suspended {
+		p.logger.Debug("dropping message for runtime with left topic",
+			"runtime_id", runtimeID,
+		)
+		return
+	}
 	if h == nil {
 		p.logger.Error("attempted to publish message for unknown runtime ID",
 			"runtime_id", runtimeID,
@@ -160,25 +195,26 @@ func (p *P2P) Publish(ctx context.Context, runtimeID common.Namespace, msg *Mess
 // RegisterHandler registers a message handler for the specified runtime.
 // If multiple handlers are registered for the same runtime, each of the
 // handlers will get invoked.
+//
+// In case the runtime's topic has been left via LeaveTopic, the handler is
+// only registered once the topic is joined again.
 func (p *P2P) RegisterHandler(runtimeID common.Namespace, handler Handler) {
 	p.Lock()
 	defer p.Unlock()
 
+	if handlers, ok := p.suspended[runtimeID]; ok {
+		p.suspended[runtimeID] = append(handlers, handler)
+		return
+	}
+
 	topic := p.topics[runtimeID]
 
 	switch topic {
 	case nil:
 		// New topic.
-		topicID, h, err := newTopicHandler(p, runtimeID, []Handler{handler})
-		if err != nil {
+		if err := p.joinTopicLocked(runtimeID, []Handler{handler}); err != nil {
 			panic(fmt.Sprintf("worker/common/p2p: failed to initialize topic handler: %s", err))
 		}
-		p.topics[runtimeID] = h
-		_ = p.pubsub.RegisterTopicValidator(
-			topicID,
-			h.topicMessageValidator,
-			pubsub.WithValidatorConcurrency(viper.GetInt(CfgP2PValidateConcurrency)),
-		)
 	default:
 		topic.handlersLock.Lock()
 		defer topic.handlersLock.Unlock()
@@ -187,7 +223,95 @@ func (p *P2P) RegisterHandler(runtimeID common.Namespace, handler Handler) {
 	}
 }
 
+// Topics returns the runtimes whose topics the node is currently subscribed to.
+func (p *P2P) Topics() []common.Namespace {
+	p.RLock()
+	defer p.RUnlock()
+
+	runtimes := make([]common.Namespace, 0, len(p.topics))
+	for runtimeID := range p.topics {
+		runtimes = append(runtimes, runtimeID)
+	}
+	return runtimes
+}
+
+// LeaveTopic stops gossiping messages of the specified runtime.
+//
+// The registered handlers are retained and the topic can be joined again via
+// JoinTopic without having to re-register them.
+func (p *P2P) LeaveTopic(runtimeID common.Namespace) error {
+	p.Lock()
+	defer p.Unlock()
+
+	h := p.topics[runtimeID]
+	if h == nil {
+		return ErrUnknownTopic
+	}
+
+	_ = p.pubsub.UnregisterTopicValidator(p.topicIDForRuntime(runtimeID))
+	_ = p.pubsub.UnregisterTopicValidator(p.compressedTopicIDForRuntime(runtimeID))
+	if err := h.close(); err != nil {
+		return fmt.Errorf("worker/common/p2p: failed to leave topic: %w", err)
+	}
+
+	h.handlersLock.RLock()
+	p.suspended[runtimeID] = append([]Handler{}, h.handlers...)
+	h.handlersLock.RUnlock()
+	delete(p.topics, runtimeID)
+
+	p.logger.Info("left runtime topic",
+		"runtime_id", runtimeID,
+	)
+
+	return nil
+}
+
+// JoinTopic resumes gossiping messages of the specified runtime after the
+// topic has been left via LeaveTopic.
+func (p *P2P) JoinTopic(runtimeID common.Namespace) error {
+	p.Lock()
+	defer p.Unlock()
+
+	handlers, ok := p.suspended[runtimeID]
+	if !ok {
+		return ErrUnknownTopic
+	}
+	if err := p.joinTopicLocked(runtimeID, handlers); err != nil {
+		return err
+	}
+	delete(p.suspended, runtimeID)
+
+	p.logger.Info("joined runtime topic",
+		"runtime_id", runtimeID,
+	)
+
+	return nil
+}
+
+func (p *P2P) joinTopicLocked(runtimeID common.Namespace, handlers []Handler) error {
+	h, err := newTopicHandler(p, runtimeID, handlers)
+	if err != nil {
+		return err
+	}
+	p.topics[runtimeID] = h
+	_ = p.pubsub.RegisterTopicValidator(
+		h.topic.String(),
+		h.topicMessageValidator,
+		pubsub.WithValidatorConcurrency(viper.GetInt(CfgP2PValidateConcurrency)),
+	)
+	_ = p.pubsub.RegisterTopicValidator(
+		h.compressedTopic.String(),
+		h.compressedTopicMessageValidator,
+		pubsub.WithValidatorConcurrency(viper.GetInt(CfgP2PValidateConcurrency)),
+	)
+	return nil
+}
+
 func (p *P2P) handleConnection(conn core.Conn) {
+	if isRelayedConn(conn) {
+		go p.holePunch(conn)
+	}
+
 	if conn.Stat().Direction != network.DirInbound {
 		return
 	}
@@ -197,6 +321,24 @@ func (p *P2P) handleConnection(conn core.Conn) {
 	)
 }
 
+// parseRegisterAddresses parses the configured P2P addresses.
+func parseRegisterAddresses(rawAddresses []string) ([]multiaddr.Multiaddr, error) {
+	addresses, err := configparser.ParseAddressList(rawAddresses)
+	if err != nil {
+		return nil, err
+	}
+
+	var mAddrs []multiaddr.Multiaddr
+	for _, addr := range addresses {
+		mAddr, err := manet.FromNetAddr(&addr.TCPAddr)
+		if err != nil {
+			return nil, err
+		}
+		mAddrs = append(mAddrs, mAddr)
+	}
+	return mAddrs, nil
+}
+
 func (p *P2P) topicIDForRuntime(runtimeID common.Namespace) string {
 	return fmt.Sprintf("%s/%d/%s",
 		p.chainContext,
@@ -205,57 +347,107 @@ func (p *P2P) topicIDForRuntime(runtimeID common.Namespace) string {
 	)
 }
 
+func (p *P2P) compressedTopicIDForRuntime(runtimeID common.Namespace) string {
+	return p.topicIDForRuntime(runtimeID) + "/" + compressedTopicSuffix
+}
+
 // New creates a new P2P node.
-func New(ctx context.Context, identity *identity.Identity, consensus consensus.Backend) (*P2P, error) {
+//
+// In case a common store is given, known peers are persisted in it across restarts.
+func New(
+	ctx context.Context,
+	identity *identity.Identity,
+	consensus consensus.Backend,
+	commonStore *persistent.CommonStore,
+) (*P2P, error) {
 	// Instantiate the libp2p host.
-	addresses, err := configparser.ParseAddressList(viper.GetStringSlice(cfgP2pAddresses))
+	registerAddresses, err := parseRegisterAddresses(viper.GetStringSlice(cfgP2pAddresses))
 	if err != nil {
 		return nil, err
 	}
 	port := uint16(viper.GetInt(CfgP2pPort))
 
-	var registerAddresses []multiaddr.Multiaddr
-	for _, addr := range addresses {
-		var mAddr multiaddr.Multiaddr
-		mAddr, err = manet.FromNetAddr(&addr.TCPAddr)
-		if err != nil {
-			return nil, err
-		}
-		registerAddresses = append(registerAddresses, mAddr)
+	var compression Compression
+	if err = compression.UnmarshalText([]byte(viper.GetString(CfgP2PCompression))); err != nil {
+		return nil, err
 	}
-
-	sourceMultiAddr, _ := multiaddr.NewMultiaddr(
-		fmt.Sprintf("/ip4/0.0.0.0/tcp/%d", port),
+	seenMessages, err := newSeenMessages(
+		viper.GetDuration(CfgP2PSeenMessagesTTL),
+		uint64(viper.GetInt(CfgP2PSeenMessagesSize)),
 	)
+	if err != nil {
+		return nil, err
+	}
+	gossipParams, err := gossipSubParams(
+		viper.GetInt(CfgP2PGossipHistoryLength),
+		viper.GetInt(CfgP2PGossipHistoryGossip),
+	)
+	if err != nil {
+		return nil, err
+	}
+	allowlists, err := newAllowlists()
+	if err != nil {
+		return nil, err
+	}
+	throttle, err := newThrottleConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	quicEnabled := viper.GetBool(CfgP2PQUICEnabled)
+	transportOpts, sourceMultiAddrs, err := transportOptions(port, quicEnabled)
+	if err != nil {
+		return nil, err
+	}
 
 	// Oh hey, they finally got around to fixing the NAT traversal code,
 	// so if people feel brave enough to want to interact with the
 	// mountain of terrible uPNP/NAT-PMP implementations out there,
 	// they can.
+	natOpts, relays, err := natOptions(newNATConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	bans, err := newBanManager(newBanConfig())
+	if err != nil {
+		return nil, err
+	}
+	bwc := metrics.NewBandwidthCounter()
 	host, err := libp2p.New(
 		ctx,
-		libp2p.ListenAddrs(sourceMultiAddr),
-		libp2p.Identity(signerToPrivKey(identity.P2PSigner)),
+		append([]libp2p.Option{
+			libp2p.ListenAddrs(sourceMultiAddrs...),
+			libp2p.Identity(signerToPrivKey(identity.P2PSigner)),
+			libp2p.ConnectionGater(bans),
+			libp2p.BandwidthReporter(bwc),
+		}, append(transportOpts, natOpts...)...)...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("worker/common/p2p: failed to initialize libp2p host: %w", err)
 	}
+	bans.host = host
 
 	// Initialize the gossipsub router.
-	pubsub, err := pubsub.NewGossipSub(
-		ctx,
-		host,
+	pubsubOpts := []pubsub.Option{
+		pubsub.WithGossipSubParams(gossipParams),
+		pubsub.WithRawTracer(&dedupTracer{}),
 		pubsub.WithMessageSigning(true),
 		pubsub.WithStrictSignatureVerification(true),
 		pubsub.WithFloodPublish(true),
 		pubsub.WithPeerOutboundQueueSize(viper.GetInt(CfgP2PPeerOutboundQueueSize)),
 		pubsub.WithValidateQueueSize(viper.GetInt(CfgP2PValidateQueueSize)),
 		pubsub.WithValidateThrottle(viper.GetInt(CfgP2PValidateThrottle)),
-		pubsub.WithMessageIdFn(func(pmsg *pb.Message) string {
-			h := hash.NewFromBytes(pmsg.Data)
-			return string(h[:])
-		}),
-	)
+		pubsub.WithMessageIdFn(messageID),
+		pubsub.WithBlacklist(bans),
+	}
+	if peerScoringEnabled() {
+		pubsubOpts = append(pubsubOpts,
+			pubsub.WithPeerScore(peerScoreParams(), peerScoreThresholds()),
+			pubsub.WithPeerScoreInspect(pubsub.PeerScoreInspectFn(bans.inspectScores), peerScoreInspectInterval),
+		)
+	}
+	pubsub, err := pubsub.NewGossipSub(ctx, newThrottledHost(ctx, host, throttle), pubsubOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("worker/common/p2p: failed to initialize libp2p gossipsub: %w", err)
 	}
@@ -266,19 +458,39 @@ func New(ctx context.Context, identity *identity.Identity, consensus consensus.B
 	}
 
 	p := &P2P{
-		PeerManager:       newPeerManager(ctx, host, consensus),
-		ctx:               ctx,
-		chainContext:      doc.ChainContext(),
-		host:              host,
-		pubsub:            pubsub,
-		registerAddresses: registerAddresses,
-		topics:            make(map[common.Namespace]*topicHandler),
-		logger:            logging.GetLogger("worker/common/p2p"),
+		PeerManager:        newPeerManager(ctx, host, consensus, relays, quicEnabled),
+		ctx:                ctx,
+		chainContext:       doc.ChainContext(),
+		host:               host,
+		pubsub:             pubsub,
+		bans:               bans,
+		allowlists:         allowlists,
+		compression:        compression,
+		compressionMinSize: viper.GetInt(CfgP2PCompressionMinSize),
+		throttle:           throttle,
+		seenMessages:       seenMessages,
+		registerAddresses:  registerAddresses,
+		topics:             make(map[common.Namespace]*topicHandler),
+		suspended:          make(map[common.Namespace][]Handler),
+		batchProviders:     make(map[common.Namespace]BatchProvider),
+		logger:             logging.GetLogger("worker/common/p2p"),
 	}
 	p.host.Network().SetConnHandler(p.handleConnection)
+	p.host.SetStreamHandler(batchProtocolID, p.handleBatchStream)
+
+	registerMetrics(bwc, allowlists)
+
+	if commonStore != nil && viper.GetInt(CfgP2PPeerstoreMaxPeers) > 0 {
+		if p.peerstore, err = newPeerstore(p, commonStore); err != nil {
+			return nil, fmt.Errorf("worker/common/p2p: failed to open peerstore: %w", err)
+		}
+		go p.peerstore.worker(ctx)
+	}
 
 	p.logger.Info("p2p host initialized",
 		"address", fmt.Sprintf("%+v", host.Addrs()),
+		"compression", compression,
+		"relays", relays,
 	)
 
 	return p, nil
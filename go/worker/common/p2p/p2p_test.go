@@ -0,0 +1,75 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+func newTestP2P(ctx context.Context, t *testing.T, opts ...libp2p.Option) *P2P {
+	require := require.New(t)
+
+	host, err := libp2p.New(ctx, opts...)
+	require.NoError(err, "libp2p.New")
+	t.Cleanup(func() { _ = host.Close() })
+
+	ps, err := pubsub.NewGossipSub(ctx, host,
+		pubsub.WithMessageIdFn(messageID),
+		pubsub.WithFloodPublish(true),
+	)
+	require.NoError(err, "NewGossipSub")
+	seenMessages, err := newSeenMessages(time.Minute, 1024)
+	require.NoError(err, "newSeenMessages")
+
+	return &P2P{
+		PeerManager:    newPeerManager(ctx, host, nil, nil, false),
+		ctx:            ctx,
+		chainContext:   "test",
+		host:           host,
+		pubsub:         ps,
+		bans:           newTestBanManager(),
+		allowlists:     &allowlists{},
+		throttle:       &throttleConfig{},
+		seenMessages:   seenMessages,
+		topics:         make(map[common.Namespace]*topicHandler),
+		suspended:      make(map[common.Namespace][]Handler),
+		batchProviders: make(map[common.Namespace]BatchProvider),
+		logger:         logging.GetLogger("worker/common/p2p/test"),
+	}
+}
+
+func TestTopicManagement(t *testing.T) {
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := newTestP2P(ctx, t, libp2p.NoListenAddrs)
+
+	var runtimeID common.Namespace
+	require.Error(p.LeaveTopic(runtimeID), "LeaveTopic should fail for unknown topics")
+	require.Error(p.JoinTopic(runtimeID), "JoinTopic should fail for unknown topics")
+
+	p.RegisterHandler(runtimeID, &BaseHandler{})
+	require.EqualValues([]common.Namespace{runtimeID}, p.Topics())
+
+	err := p.LeaveTopic(runtimeID)
+	require.NoError(err, "LeaveTopic")
+	require.Empty(p.Topics())
+
+	// Handlers registered while the topic is left should be retained.
+	p.RegisterHandler(runtimeID, &BaseHandler{})
+	require.Empty(p.Topics())
+
+	err = p.JoinTopic(runtimeID)
+	require.NoError(err, "JoinTopic")
+	require.EqualValues([]common.Namespace{runtimeID}, p.Topics())
+	require.Len(p.topics[runtimeID].handlers, 2)
+}
@@ -35,8 +35,10 @@ type PeerManager struct {
 
 	ctx context.Context
 
-	host  core.Host
-	peers map[core.PeerID]*p2pPeer
+	host   core.Host
+	peers  map[core.PeerID]*p2pPeer
+	relays []peer.AddrInfo
+	quic   bool
 
 	initCh   chan struct{}
 	initOnce sync.Once
@@ -276,11 +278,19 @@ func (mgr *PeerManager) watchRegistryNodes(consensus consensus.Backend) {
 	}
 }
 
-func newPeerManager(ctx context.Context, host core.Host, consensus consensus.Backend) *PeerManager {
+func newPeerManager(
+	ctx context.Context,
+	host core.Host,
+	consensus consensus.Backend,
+	relays []peer.AddrInfo,
+	quic bool,
+) *PeerManager {
 	mgr := &PeerManager{
 		ctx:    ctx,
 		host:   host,
 		peers:  make(map[core.PeerID]*p2pPeer),
+		relays: relays,
+		quic:   quic,
 		initCh: make(chan struct{}),
 		logger: logging.GetLogger("worker/common/p2p/peermgr"),
 	}
@@ -295,7 +305,7 @@ func (p *p2pPeer) connectWorker(mgr *PeerManager, peerID core.PeerID) {
 		close(p.doneCh)
 	}()
 
-	ai, err := nodeToAddrInfo(p.node)
+	ai, err := nodeToAddrInfo(p.node, mgr.quic)
 	if err != nil {
 		mgr.logger.Error("failed to get node addresses, not retrying",
 			"err", err,
@@ -303,6 +313,8 @@ func (p *p2pPeer) connectWorker(mgr *PeerManager, peerID core.PeerID) {
 		)
 		return
 	}
+	// In case the peer is not directly reachable, try to reach it via the configured relays.
+	ai.Addrs = append(ai.Addrs, relayAddrs(mgr.relays)...)
 
 	mgr.logger.Debug("updating libp2p gossipsub peer",
 		"node_id", p.node.ID,
@@ -350,7 +362,12 @@ func publicKeyToPeerID(pk signature.PublicKey) (core.PeerID, error) {
 	return id, nil
 }
 
-func nodeToAddrInfo(node *node.Node) (*peer.AddrInfo, error) {
+// nodeToAddrInfo converts the node's registered P2P addresses to libp2p peer address information.
+//
+// In case quic is true, a QUIC address on the same port is added for each registered address. As
+// libp2p prefers dialing QUIC addresses, the TCP addresses are only used in case the peer does not
+// support QUIC.
+func nodeToAddrInfo(node *node.Node, quic bool) (*peer.AddrInfo, error) {
 	var (
 		ai  peer.AddrInfo
 		err error
@@ -364,6 +381,13 @@ func nodeToAddrInfo(node *node.Node) (*peer.AddrInfo, error) {
 			return nil, fmt.Errorf("failed to convert address to libp2p format: %w", err)
 		}
 		ai.Addrs = append(ai.Addrs, addr)
+
+		if quic {
+			if addr, err = quicAddress(nodeAddr); err != nil {
+				return nil, fmt.Errorf("failed to convert address to libp2p format: %w", err)
+			}
+			ai.Addrs = append(ai.Addrs, addr)
+		}
 	}
 
 	return &ai, nil
@@ -0,0 +1,222 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	core "github.com/libp2p/go-libp2p-core"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+)
+
+const (
+	peerstoreBucketName = "worker/p2p/peerstore"
+
+	// peerstoreSaveInterval is the interval at which the peerstore is persisted.
+	peerstoreSaveInterval = 1 * time.Minute
+	// peerstoreAddrTTL is the TTL of addresses restored from the persisted peerstore.
+	peerstoreAddrTTL = 1 * time.Hour
+	// peerstoreReconnectTimeout is the timeout for reconnecting to a persisted peer.
+	peerstoreReconnectTimeout = 10 * time.Second
+)
+
+var peerstoreKey = []byte("peers")
+
+// persistedPeer is a peer as stored in the persisted peerstore.
+type persistedPeer struct {
+	// ID is the P2P public key of the peer.
+	ID signature.PublicKey `json:"id"`
+
+	// Addresses are the last known addresses of the peer.
+	Addresses []string `json:"addresses"`
+
+	// Score is the last known gossipsub score of the peer.
+	Score float64 `json:"score"`
+
+	// LastSeen is the time when the peer was last seen connected.
+	LastSeen time.Time `json:"last_seen"`
+
+	// Ban is the ban information in case the peer was banned.
+	Ban *BanInfo `json:"ban,omitempty"`
+}
+
+// persistedPeers is the persisted peerstore.
+type persistedPeers struct {
+	Peers []*persistedPeer `json:"peers"`
+}
+
+// peerstore persists known peer addresses, scores and bans across restarts.
+type peerstore struct {
+	p2p   *P2P
+	store *persistent.ServiceStore
+
+	maxPeers int
+
+	peers map[core.PeerID]*persistedPeer
+
+	quitCh chan struct{}
+}
+
+// load restores the persisted peers and bans and returns the peers that should be reconnected to.
+func (ps *peerstore) load() ([]peer.AddrInfo, error) {
+	var persisted persistedPeers
+	switch err := ps.store.GetCBOR(peerstoreKey, &persisted); {
+	case err == nil:
+	case errors.Is(err, persistent.ErrNotFound):
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	var good []peer.AddrInfo
+	for _, pp := range persisted.Peers {
+		peerID, err := publicKeyToPeerID(pp.ID)
+		if err != nil {
+			continue
+		}
+		ps.peers[peerID] = pp
+
+		if pp.Ban != nil && time.Now().Before(pp.Ban.Until) {
+			ps.p2p.bans.ban(peerID, time.Until(pp.Ban.Until), pp.Ban.Reason)
+			continue
+		}
+
+		ai := peer.AddrInfo{ID: peerID}
+		for _, rawAddr := range pp.Addresses {
+			addr, err := multiaddr.NewMultiaddr(rawAddr)
+			if err != nil {
+				continue
+			}
+			ai.Addrs = append(ai.Addrs, addr)
+		}
+		if len(ai.Addrs) == 0 {
+			continue
+		}
+		ps.p2p.host.Peerstore().AddAddrs(peerID, ai.Addrs, peerstoreAddrTTL)
+
+		// Only reconnect to peers that were behaving well.
+		if pp.Score >= 0 {
+			good = append(good, ai)
+		}
+	}
+	return good, nil
+}
+
+// save persists the currently connected peers together with the previously persisted ones.
+func (ps *peerstore) save() error {
+	now := time.Now()
+	for _, peerID := range ps.p2p.host.Network().Peers() {
+		id, err := peerIDToPublicKey(peerID)
+		if err != nil {
+			continue
+		}
+		pp := &persistedPeer{
+			ID:       id,
+			Score:    ps.p2p.bans.score(peerID),
+			LastSeen: now,
+		}
+		for _, addr := range ps.p2p.host.Peerstore().Addrs(peerID) {
+			pp.Addresses = append(pp.Addresses, addr.String())
+		}
+		ps.peers[peerID] = pp
+	}
+	for peerID, pp := range ps.peers {
+		pp.Ban = ps.p2p.bans.banInfo(peerID)
+	}
+
+	var persisted persistedPeers
+	for _, pp := range ps.peers {
+		persisted.Peers = append(persisted.Peers, pp)
+	}
+	// Keep only the most recently seen peers.
+	sort.Slice(persisted.Peers, func(i, j int) bool {
+		return persisted.Peers[i].LastSeen.After(persisted.Peers[j].LastSeen)
+	})
+	if len(persisted.Peers) > ps.maxPeers {
+		for _, pp := range persisted.Peers[ps.maxPeers:] {
+			if peerID, err := publicKeyToPeerID(pp.ID); err == nil {
+				delete(ps.peers, peerID)
+			}
+		}
+		persisted.Peers = persisted.Peers[:ps.maxPeers]
+	}
+
+	return ps.store.PutCBOR(peerstoreKey, &persisted)
+}
+
+// reconnect attempts to reconnect to the given previously known peers.
+func (ps *peerstore) reconnect(ctx context.Context, peers []peer.AddrInfo) {
+	for _, ai := range peers {
+		go func(ai peer.AddrInfo) {
+			connCtx, cancel := context.WithTimeout(ctx, peerstoreReconnectTimeout)
+			defer cancel()
+
+			if err := ps.p2p.host.Connect(connCtx, ai); err != nil {
+				ps.p2p.logger.Debug("failed to reconnect to persisted peer",
+					"err", err,
+					"peer_id", ai.ID,
+				)
+			}
+		}(ai)
+	}
+}
+
+// worker restores the persisted peers, reconnects to them and periodically persists known peers.
+//
+// Known peers are also persisted when the context is canceled, before the worker terminates.
+func (ps *peerstore) worker(ctx context.Context) {
+	defer close(ps.quitCh)
+
+	peers, err := ps.load()
+	if err != nil {
+		ps.p2p.logger.Error("failed to load persisted peerstore",
+			"err", err,
+		)
+	}
+	ps.p2p.logger.Info("reconnecting to persisted peers",
+		"num_peers", len(peers),
+	)
+	ps.reconnect(ctx, peers)
+
+	ticker := time.NewTicker(peerstoreSaveInterval)
+	defer ticker.Stop()
+
+	for {
+		var done bool
+		select {
+		case <-ctx.Done():
+			done = true
+		case <-ticker.C:
+		}
+
+		if err := ps.save(); err != nil {
+			ps.p2p.logger.Error("failed to persist peerstore",
+				"err", err,
+			)
+		}
+		if done {
+			return
+		}
+	}
+}
+
+func newPeerstore(p *P2P, commonStore *persistent.CommonStore) (*peerstore, error) {
+	store, err := commonStore.GetServiceStore(peerstoreBucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &peerstore{
+		p2p:      p,
+		store:    store,
+		maxPeers: viper.GetInt(CfgP2PPeerstoreMaxPeers),
+		peers:    make(map[core.PeerID]*persistedPeer),
+		quitCh:   make(chan struct{}),
+	}, nil
+}
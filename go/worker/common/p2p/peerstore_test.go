@@ -0,0 +1,106 @@
+package p2p
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+)
+
+func TestPeerstore(t *testing.T) {
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "oasis-worker-p2p-peerstore-test")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	commonStore, err := persistent.NewCommonStore(dir)
+	require.NoError(err, "NewCommonStore")
+	defer commonStore.Close()
+
+	host, err := libp2p.New(ctx, libp2p.NoListenAddrs)
+	require.NoError(err, "libp2p.New")
+	defer host.Close()
+
+	p := &P2P{
+		host:   host,
+		bans:   newTestBanManager(),
+		logger: logging.GetLogger("worker/common/p2p/test"),
+	}
+	ps, err := newPeerstore(p, commonStore)
+	require.NoError(err, "newPeerstore")
+	ps.maxPeers = 2
+
+	// Nothing persisted yet.
+	good, err := ps.load()
+	require.NoError(err, "load")
+	require.Empty(good)
+
+	newPeer := func(score float64, lastSeen time.Time) signature.PublicKey {
+		signer := memorySigner.NewTestSigner(lastSeen.String())
+		id := signer.Public()
+		peerID, perr := publicKeyToPeerID(id)
+		require.NoError(perr, "publicKeyToPeerID")
+		ps.peers[peerID] = &persistedPeer{
+			ID:        id,
+			Addresses: []string{"/ip4/127.0.0.1/tcp/9200"},
+			Score:     score,
+			LastSeen:  lastSeen,
+		}
+		return id
+	}
+	now := time.Now()
+	goodPeer := newPeer(10, now)
+	badPeer := newPeer(-10, now.Add(-time.Minute))
+	_ = newPeer(10, now.Add(-time.Hour)) // Should be pruned.
+
+	badPeerID, err := publicKeyToPeerID(badPeer)
+	require.NoError(err, "publicKeyToPeerID")
+	p.bans.ban(badPeerID, time.Hour, "test")
+
+	err = ps.save()
+	require.NoError(err, "save")
+	require.Len(ps.peers, 2, "peerstore should be pruned to max peers")
+
+	// Restore into a fresh peerstore.
+	p.bans = newTestBanManager()
+	ps, err = newPeerstore(p, commonStore)
+	require.NoError(err, "newPeerstore")
+	good, err = ps.load()
+	require.NoError(err, "load")
+	require.Len(good, 1, "only well-behaved peers should be reconnected to")
+
+	goodPeerID, err := publicKeyToPeerID(goodPeer)
+	require.NoError(err, "publicKeyToPeerID")
+	require.Equal(goodPeerID, good[0].ID)
+	require.True(p.bans.Contains(badPeerID), "bans should be restored")
+
+	// Peers should be persisted when the worker terminates.
+	ps, err = newPeerstore(p, commonStore)
+	require.NoError(err, "newPeerstore")
+	newPeer(10, now.Add(time.Minute))
+	p.peerstore = ps
+
+	workerCtx, workerCancel := context.WithCancel(ctx)
+	go ps.worker(workerCtx)
+	workerCancel()
+	p.Cleanup()
+
+	ps, err = newPeerstore(p, commonStore)
+	require.NoError(err, "newPeerstore")
+	good, err = ps.load()
+	require.NoError(err, "load")
+	require.Len(good, 2, "peers should be persisted on termination")
+}
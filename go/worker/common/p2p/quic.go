@@ -0,0 +1,16 @@
+//go:build !go1.18
+// +build !go1.18
+
+package p2p
+
+import (
+	"github.com/libp2p/go-libp2p"
+	libp2pquic "github.com/libp2p/go-libp2p-quic-transport"
+)
+
+// quicSupported is true iff the QUIC transport is available in this build.
+const quicSupported = true
+
+func quicTransport() (libp2p.Option, error) {
+	return libp2p.Transport(libp2pquic.NewTransport), nil
+}
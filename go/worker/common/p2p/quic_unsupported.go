@@ -0,0 +1,19 @@
+//go:build go1.18
+// +build go1.18
+
+package p2p
+
+import (
+	"fmt"
+
+	"github.com/libp2p/go-libp2p"
+)
+
+// quicSupported is true iff the QUIC transport is available in this build.
+//
+// The QUIC implementation used by libp2p does not support Go 1.18 or later.
+const quicSupported = false
+
+func quicTransport() (libp2p.Option, error) {
+	return nil, fmt.Errorf("worker/common/p2p: QUIC transport is not supported by this build")
+}
@@ -0,0 +1,142 @@
+package p2p
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	circuit "github.com/libp2p/go-libp2p-circuit"
+	core "github.com/libp2p/go-libp2p-core"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/spf13/viper"
+)
+
+type natConfig struct {
+	relayAddresses []string
+	relayHop       bool
+	portMap        bool
+	service        bool
+}
+
+func newNATConfig() *natConfig {
+	return &natConfig{
+		relayAddresses: viper.GetStringSlice(CfgP2PRelayAddresses),
+		relayHop:       viper.GetBool(CfgP2PRelayHop),
+		portMap:        viper.GetBool(CfgP2PNATPortMap),
+		service:        viper.GetBool(CfgP2PNATService),
+	}
+}
+
+// natOptions returns the libp2p host options for NAT traversal and circuit relaying, together with
+// the list of configured relay nodes.
+//
+// Note that the used libp2p version does not implement the DCUtR hole punching protocol, so relayed
+// connections are upgraded to direct ones on a best-effort basis (see holePunch).
+func natOptions(cfg *natConfig) ([]libp2p.Option, []peer.AddrInfo, error) {
+	var relays []peer.AddrInfo
+	for _, rawAddr := range cfg.relayAddresses {
+		addr, err := multiaddr.NewMultiaddr(rawAddr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("worker/common/p2p: malformed relay address '%s': %w", rawAddr, err)
+		}
+		ai, err := peer.AddrInfoFromP2pAddr(addr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("worker/common/p2p: malformed relay address '%s': %w", rawAddr, err)
+		}
+		relays = append(relays, *ai)
+	}
+
+	hop := cfg.relayHop
+	if hop && len(relays) > 0 {
+		return nil, nil, fmt.Errorf("worker/common/p2p: relay hop and relay addresses are mutually exclusive")
+	}
+
+	var opts []libp2p.Option
+	if cfg.portMap {
+		opts = append(opts, libp2p.NATPortMap())
+	}
+	if cfg.service {
+		opts = append(opts, libp2p.EnableNATService())
+	}
+	switch {
+	case hop:
+		opts = append(opts, libp2p.EnableRelay(circuit.OptHop))
+	case len(relays) > 0:
+		opts = append(opts,
+			libp2p.EnableRelay(),
+			libp2p.EnableAutoRelay(),
+			libp2p.StaticRelays(relays),
+		)
+	}
+
+	return opts, relays, nil
+}
+
+const (
+	// holePunchAttempts is the number of direct dial attempts made after a relayed connection has
+	// been established.
+	holePunchAttempts = 3
+	// holePunchRetryInterval is the interval between direct dial attempts. The first attempt is
+	// also delayed by this interval so that identify can learn the peer's direct addresses.
+	holePunchRetryInterval = 1 * time.Second
+)
+
+// isRelayedConn returns true iff the given connection goes through a circuit relay.
+func isRelayedConn(conn core.Conn) bool {
+	_, err := conn.RemoteMultiaddr().ValueForProtocol(multiaddr.P_CIRCUIT)
+	return err == nil
+}
+
+// holePunch tries to replace a relayed connection with a direct one.
+//
+// Both peers of a relayed connection start dialing each other directly as soon as the connection
+// is established, which opens NAT mappings on both sides at roughly the same time. This makes it
+// possible to traverse NATs with endpoint-independent mappings when using QUIC, and to connect
+// whenever at least one of the peers is publicly reachable. Once a direct connection is
+// established, the relayed connection is closed.
+func (p *P2P) holePunch(conn core.Conn) {
+	peerID := conn.RemotePeer()
+	ctx := network.WithForceDirectDial(p.ctx, "hole-punch")
+
+	for attempt := 1; attempt <= holePunchAttempts; attempt++ {
+		select {
+		case <-time.After(holePunchRetryInterval):
+		case <-p.ctx.Done():
+			return
+		}
+		if p.host.Network().Connectedness(peerID) != network.Connected {
+			// Peer has disconnected in the meantime.
+			return
+		}
+
+		if _, err := p.host.Network().DialPeer(ctx, peerID); err != nil {
+			p.logger.Debug("failed to establish direct connection to relayed peer",
+				"err", err,
+				"peer_id", peerID,
+				"attempt", attempt,
+			)
+			continue
+		}
+
+		p.logger.Debug("established direct connection to relayed peer",
+			"peer_id", peerID,
+		)
+		_ = conn.Close()
+		return
+	}
+}
+
+// relayAddrs returns the circuit relay addresses through which the given peer may be reachable.
+func relayAddrs(relays []peer.AddrInfo) []multiaddr.Multiaddr {
+	addrs := make([]multiaddr.Multiaddr, 0, len(relays))
+	for _, relay := range relays {
+		addr, err := multiaddr.NewMultiaddr(fmt.Sprintf("/p2p/%s/p2p-circuit", relay.ID.Pretty()))
+		if err != nil {
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
@@ -0,0 +1,79 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	circuit "github.com/libp2p/go-libp2p-circuit"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNATOptions(t *testing.T) {
+	require := require.New(t)
+
+	const relay = "/ip4/127.0.0.1/tcp/9200/p2p/12D3KooWDpJ7As7BWAwRMfu1VU2WCqNjvq387JEYKDBj4kx6nXTN"
+
+	_, relays, err := natOptions(&natConfig{})
+	require.NoError(err, "natOptions")
+	require.Empty(relays)
+
+	cfg := &natConfig{relayAddresses: []string{relay}}
+	opts, relays, err := natOptions(cfg)
+	require.NoError(err, "natOptions")
+	require.Len(relays, 1)
+	require.NotEmpty(opts)
+	require.Len(relayAddrs(relays), 1)
+	require.Equal("/p2p/"+relays[0].ID.Pretty()+"/p2p-circuit", relayAddrs(relays)[0].String())
+
+	cfg.relayHop = true
+	_, _, err = natOptions(cfg)
+	require.Error(err, "relay hop and relay addresses should be mutually exclusive")
+
+	cfg = &natConfig{relayAddresses: []string{"/ip4/127.0.0.1/tcp/9200"}}
+	_, _, err = natOptions(cfg)
+	require.Error(err, "relay addresses without peer IDs should be rejected")
+}
+
+func TestHolePunch(t *testing.T) {
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listenAddr := libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0")
+
+	relay, err := libp2p.New(ctx, listenAddr, libp2p.EnableRelay(circuit.OptHop))
+	require.NoError(err, "libp2p.New")
+	defer relay.Close()
+	relayInfo := peer.AddrInfo{ID: relay.ID(), Addrs: relay.Addrs()}
+
+	p := newTestP2P(ctx, t, listenAddr, libp2p.EnableRelay())
+	p.host.Network().SetConnHandler(p.handleConnection)
+	err = p.host.Connect(ctx, relayInfo)
+	require.NoError(err, "Connect")
+
+	other, err := libp2p.New(ctx, listenAddr, libp2p.EnableRelay())
+	require.NoError(err, "libp2p.New")
+	defer other.Close()
+	err = other.Connect(ctx, relayInfo)
+	require.NoError(err, "Connect")
+
+	// Connect through the relay only.
+	err = other.Connect(ctx, peer.AddrInfo{ID: p.host.ID(), Addrs: relayAddrs([]peer.AddrInfo{relayInfo})})
+	require.NoError(err, "Connect via relay")
+	conns := p.host.Network().ConnsToPeer(other.ID())
+	require.Len(conns, 1)
+	require.True(isRelayedConn(conns[0]), "connection should be relayed")
+
+	// The relayed connection should be replaced by a direct one.
+	require.Eventually(func() bool {
+		conns = p.host.Network().ConnsToPeer(other.ID())
+		return len(conns) == 1 && !isRelayedConn(conns[0])
+	}, 10*time.Second, 50*time.Millisecond, "relayed connection should be upgraded")
+	_, err = conns[0].RemoteMultiaddr().ValueForProtocol(multiaddr.P_TCP)
+	require.NoError(err, "direct connection should use TCP")
+}
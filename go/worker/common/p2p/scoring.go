@@ -0,0 +1,60 @@
+package p2p
+
+import (
+	"time"
+
+	core "github.com/libp2p/go-libp2p-core"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/spf13/viper"
+)
+
+const (
+	// peerScoreInspectInterval is the interval at which peer scores are inspected.
+	peerScoreInspectInterval = 10 * time.Second
+	// peerScoreRetention is the amount of time the score of a disconnected peer is retained so
+	// that misbehaving peers cannot reset their score by reconnecting.
+	peerScoreRetention = 1 * time.Hour
+)
+
+// peerScoringEnabled returns true iff gossipsub peer scoring is enabled.
+func peerScoringEnabled() bool {
+	return viper.GetBool(CfgP2PPeerScoringEnabled)
+}
+
+// peerScoreParams returns the global gossipsub peer score parameters.
+func peerScoreParams() *pubsub.PeerScoreParams {
+	return &pubsub.PeerScoreParams{
+		Topics: make(map[string]*pubsub.TopicScoreParams),
+		AppSpecificScore: func(core.PeerID) float64 {
+			return 0
+		},
+		BehaviourPenaltyWeight:    -10,
+		BehaviourPenaltyThreshold: 6,
+		BehaviourPenaltyDecay:     pubsub.ScoreParameterDecay(10 * time.Minute),
+		DecayInterval:             pubsub.DefaultDecayInterval,
+		DecayToZero:               pubsub.DefaultDecayToZero,
+		RetainScore:               peerScoreRetention,
+	}
+}
+
+// peerScoreThresholds returns the gossipsub peer score thresholds.
+func peerScoreThresholds() *pubsub.PeerScoreThresholds {
+	return &pubsub.PeerScoreThresholds{
+		GossipThreshold:   viper.GetFloat64(CfgP2PPeerScoringGossipThreshold),
+		PublishThreshold:  viper.GetFloat64(CfgP2PPeerScoringPublishThreshold),
+		GraylistThreshold: viper.GetFloat64(CfgP2PPeerScoringGraylistThreshold),
+	}
+}
+
+// topicScoreParams returns the gossipsub peer score parameters for runtime topics.
+//
+// Only invalid message deliveries are penalized as the message rate of runtime topics varies too
+// much for mesh delivery-based scoring to be reliable.
+func topicScoreParams() *pubsub.TopicScoreParams {
+	return &pubsub.TopicScoreParams{
+		TopicWeight:                    1,
+		TimeInMeshQuantum:              time.Second,
+		InvalidMessageDeliveriesWeight: viper.GetFloat64(CfgP2PPeerScoringInvalidMessageWeight),
+		InvalidMessageDeliveriesDecay:  pubsub.ScoreParameterDecay(time.Hour),
+	}
+}
@@ -0,0 +1,208 @@
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	core "github.com/libp2p/go-libp2p-core"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/spf13/viper"
+)
+
+const (
+	throttleScopePeer  = "peer"
+	throttleScopeTopic = "topic"
+)
+
+// bandwidthLimiter is a token bucket limiting the rate at which bytes are sent.
+//
+// A nil limiter does not limit anything.
+type bandwidthLimiter struct {
+	sync.Mutex
+
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	scope string
+	now   func() time.Time
+}
+
+// reserve takes n tokens from the bucket and returns the duration the caller needs to wait before
+// sending the corresponding bytes.
+//
+// Reservations larger than the burst size are allowed and simply put the bucket into debt which
+// delays all subsequent reservations.
+func (l *bandwidthLimiter) reserve(n int) time.Duration {
+	l.Lock()
+	defer l.Unlock()
+
+	now := l.now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// wait blocks until n bytes may be sent or the context is canceled.
+func (l *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	if l == nil || n <= 0 {
+		return nil
+	}
+
+	delay := l.reserve(n)
+	if delay == 0 {
+		return nil
+	}
+	throttleDelay.WithLabelValues(l.scope).Add(delay.Seconds())
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func newBandwidthLimiter(scope string, rate, burst uint64) *bandwidthLimiter {
+	if rate == 0 {
+		return nil
+	}
+	return &bandwidthLimiter{
+		rate:   float64(rate),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		scope:  scope,
+		now:    time.Now,
+	}
+}
+
+// throttleConfig is the outbound bandwidth throttling configuration.
+type throttleConfig struct {
+	peerRate  uint64
+	peerBurst uint64
+
+	topicRate  uint64
+	topicBurst uint64
+}
+
+func (cfg *throttleConfig) newTopicLimiter() *bandwidthLimiter {
+	return newBandwidthLimiter(throttleScopeTopic, cfg.topicRate, cfg.topicBurst)
+}
+
+func newThrottleConfig() (*throttleConfig, error) {
+	cfg := &throttleConfig{
+		peerRate:   viper.GetUint64(CfgP2PThrottlePeerRate),
+		peerBurst:  viper.GetUint64(CfgP2PThrottlePeerBurst),
+		topicRate:  viper.GetUint64(CfgP2PThrottleTopicRate),
+		topicBurst: viper.GetUint64(CfgP2PThrottleTopicBurst),
+	}
+	if cfg.peerRate > 0 && cfg.peerBurst == 0 {
+		return nil, fmt.Errorf("worker/common/p2p: %s must be non-zero when %s is set", CfgP2PThrottlePeerBurst, CfgP2PThrottlePeerRate)
+	}
+	if cfg.topicRate > 0 && cfg.topicBurst == 0 {
+		return nil, fmt.Errorf("worker/common/p2p: %s must be non-zero when %s is set", CfgP2PThrottleTopicBurst, CfgP2PThrottleTopicRate)
+	}
+	return cfg, nil
+}
+
+// throttledHost is a libp2p host which limits the outbound bandwidth of streams it opens on a
+// per-peer basis.
+//
+// It is only used by the gossipsub router, so that direct protocol streams are not affected.
+type throttledHost struct {
+	core.Host
+
+	ctx   context.Context
+	rate  uint64
+	burst uint64
+
+	sync.Mutex
+	limiters map[peer.ID]*bandwidthLimiter
+}
+
+// Implements core.Host.
+func (h *throttledHost) NewStream(ctx context.Context, p peer.ID, pids ...protocol.ID) (network.Stream, error) {
+	s, err := h.Host.NewStream(ctx, p, pids...)
+	if err != nil {
+		return nil, err
+	}
+	return &throttledStream{
+		Stream:  s,
+		ctx:     h.ctx,
+		limiter: h.peerLimiter(p),
+	}, nil
+}
+
+func (h *throttledHost) peerLimiter(p peer.ID) *bandwidthLimiter {
+	h.Lock()
+	defer h.Unlock()
+
+	l := h.limiters[p]
+	if l == nil {
+		l = newBandwidthLimiter(throttleScopePeer, h.rate, h.burst)
+		h.limiters[p] = l
+	}
+	return l
+}
+
+func (h *throttledHost) disconnected(_ network.Network, conn network.Conn) {
+	p := conn.RemotePeer()
+	if len(h.Network().ConnsToPeer(p)) > 0 {
+		return
+	}
+
+	h.Lock()
+	defer h.Unlock()
+	delete(h.limiters, p)
+}
+
+// newThrottledHost wraps the given host so that streams opened through it are throttled using the
+// per-peer limits. In case per-peer throttling is disabled, the host is returned unchanged.
+func newThrottledHost(ctx context.Context, host core.Host, cfg *throttleConfig) core.Host {
+	if cfg.peerRate == 0 {
+		return host
+	}
+
+	h := &throttledHost{
+		Host:     host,
+		ctx:      ctx,
+		rate:     cfg.peerRate,
+		burst:    cfg.peerBurst,
+		limiters: make(map[peer.ID]*bandwidthLimiter),
+	}
+	host.Network().Notify(&network.NotifyBundle{
+		DisconnectedF: h.disconnected,
+	})
+	return h
+}
+
+// throttledStream is a stream whose writes are limited by a bandwidth limiter.
+type throttledStream struct {
+	network.Stream
+
+	ctx     context.Context
+	limiter *bandwidthLimiter
+}
+
+// Implements network.Stream.
+func (s *throttledStream) Write(b []byte) (int, error) {
+	if err := s.limiter.wait(s.ctx, len(b)); err != nil {
+		return 0, err
+	}
+	return s.Stream.Write(b)
+}
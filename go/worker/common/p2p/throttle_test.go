@@ -0,0 +1,75 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+)
+
+func TestBandwidthLimiter(t *testing.T) {
+	require := require.New(t)
+
+	require.Nil(newBandwidthLimiter(throttleScopePeer, 0, 1000), "zero rate should disable limiting")
+	var nilLimiter *bandwidthLimiter
+	require.NoError(nilLimiter.wait(context.Background(), 1000), "nil limiter should not limit")
+
+	now := time.Unix(1000, 0)
+	l := newBandwidthLimiter(throttleScopeTopic, 1000, 2000)
+	l.last = now
+	l.now = func() time.Time { return now }
+
+	require.EqualValues(0, l.reserve(1500), "reservation within burst should not be delayed")
+	require.EqualValues(500*time.Millisecond, l.reserve(1000), "reservation exceeding available tokens should be delayed")
+	require.EqualValues(1500*time.Millisecond, l.reserve(1000), "debt should delay subsequent reservations")
+
+	now = now.Add(10 * time.Second)
+	require.EqualValues(0, l.reserve(2000), "tokens should be refilled up to burst")
+	require.EqualValues(2*time.Second, l.reserve(2000), "reservation larger than available tokens should be delayed")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Error(l.wait(ctx, 1000), "wait should fail when context is canceled")
+}
+
+func TestThrottleConfig(t *testing.T) {
+	require := require.New(t)
+
+	cfg, err := newThrottleConfig()
+	require.NoError(err, "newThrottleConfig")
+	require.Nil(cfg.newTopicLimiter(), "topic throttling should be disabled by default")
+
+	cfg = &throttleConfig{topicRate: 1000, topicBurst: 1000}
+	require.NotNil(cfg.newTopicLimiter(), "topic throttling should be enabled")
+}
+
+func TestThrottledPublish(t *testing.T) {
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := newTestP2P(ctx, t, libp2p.NoListenAddrs)
+	p.throttle = &throttleConfig{topicRate: 1, topicBurst: 1}
+
+	var runtimeID common.Namespace
+	p.RegisterHandler(runtimeID, &BaseHandler{})
+	h := p.topics[runtimeID]
+
+	// Throttled messages should be published asynchronously, without blocking the caller.
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(h.publish(cbor.Marshal(&Message{GroupVersion: int64(i)})), "publish")
+	}
+	require.True(time.Since(start) < time.Second, "publish should not block due to throttling")
+
+	for i := 0; i < rawMsgQueueSize; i++ {
+		_ = h.publish(cbor.Marshal(&Message{GroupVersion: int64(i)}))
+	}
+	require.Error(h.publish(cbor.Marshal(&Message{})), "publish should fail when the queue is full")
+}
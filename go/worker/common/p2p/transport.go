@@ -0,0 +1,56 @@
+package p2p
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+)
+
+var quicComponent = multiaddr.StringCast("/quic")
+
+// transportOptions returns the libp2p transport options and listen addresses for the given port,
+// additionally enabling the QUIC transport if requested.
+//
+// TCP is always enabled as it is the transport used for the addresses published in the registry.
+func transportOptions(port uint16, quicEnabled bool) ([]libp2p.Option, []multiaddr.Multiaddr, error) {
+	listenAddrs := []multiaddr.Multiaddr{
+		multiaddr.StringCast(fmt.Sprintf("/ip4/0.0.0.0/tcp/%d", port)),
+		multiaddr.StringCast(fmt.Sprintf("/ip6/::/tcp/%d", port)),
+	}
+	if !quicEnabled {
+		return nil, listenAddrs, nil
+	}
+	quicOpt, err := quicTransport()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	listenAddrs = append(listenAddrs,
+		multiaddr.StringCast(fmt.Sprintf("/ip4/0.0.0.0/udp/%d/quic", port)),
+		multiaddr.StringCast(fmt.Sprintf("/ip6/::/udp/%d/quic", port)),
+	)
+	opts := []libp2p.Option{
+		libp2p.DefaultTransports,
+		quicOpt,
+	}
+	return opts, listenAddrs, nil
+}
+
+// quicAddress returns the QUIC multiaddr corresponding to the given node address, assuming that
+// the peer listens for QUIC connections on the same port number as for TCP connections.
+func quicAddress(addr node.Address) (multiaddr.Multiaddr, error) {
+	udpAddr, err := manet.FromNetAddr(&net.UDPAddr{
+		IP:   addr.IP,
+		Port: addr.Port,
+		Zone: addr.Zone,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return udpAddr.Encapsulate(quicComponent), nil
+}
@@ -0,0 +1,59 @@
+package p2p
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+)
+
+func TestTransportOptions(t *testing.T) {
+	require := require.New(t)
+
+	opts, listenAddrs, err := transportOptions(9200, false)
+	require.NoError(err, "transportOptions")
+	require.Empty(opts, "default transports should be used")
+	require.Len(listenAddrs, 2)
+	require.Equal("/ip4/0.0.0.0/tcp/9200", listenAddrs[0].String())
+	require.Equal("/ip6/::/tcp/9200", listenAddrs[1].String())
+
+	opts, listenAddrs, err = transportOptions(9200, true)
+	if !quicSupported {
+		require.Error(err, "enabling QUIC should fail when not supported")
+		return
+	}
+	require.NoError(err, "transportOptions")
+	require.Len(opts, 2, "TCP and QUIC transports should be configured")
+	require.Len(listenAddrs, 4)
+	require.Equal("/ip4/0.0.0.0/udp/9200/quic", listenAddrs[2].String())
+	require.Equal("/ip6/::/udp/9200/quic", listenAddrs[3].String())
+}
+
+func TestNodeToAddrInfo(t *testing.T) {
+	require := require.New(t)
+
+	signer := memorySigner.NewTestSigner("worker/common/p2p: node to addr info test")
+	var n node.Node
+	n.P2P.ID = signer.Public()
+	n.P2P.Addresses = []node.Address{
+		{TCPAddr: net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 9200}},
+		{TCPAddr: net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 9200}},
+	}
+
+	ai, err := nodeToAddrInfo(&n, false)
+	require.NoError(err, "nodeToAddrInfo")
+	require.Len(ai.Addrs, 2)
+	require.Equal("/ip4/192.0.2.1/tcp/9200", ai.Addrs[0].String())
+	require.Equal("/ip6/2001:db8::1/tcp/9200", ai.Addrs[1].String())
+
+	ai, err = nodeToAddrInfo(&n, true)
+	require.NoError(err, "nodeToAddrInfo")
+	require.Len(ai.Addrs, 4, "QUIC addresses should be added")
+	require.Equal("/ip4/192.0.2.1/tcp/9200", ai.Addrs[0].String())
+	require.Equal("/ip4/192.0.2.1/udp/9200/quic", ai.Addrs[1].String())
+	require.Equal("/ip6/2001:db8::1/tcp/9200", ai.Addrs[2].String())
+	require.Equal("/ip6/2001:db8::1/udp/9200/quic", ai.Addrs[3].String())
+}
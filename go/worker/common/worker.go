@@ -23,7 +23,7 @@ import (
 // Worker is a garbage bag with lower level services and common runtime objects.
 type Worker struct {
 	enabled bool
-	cfg     Config
+	cfg     *Config
 
 	HostNode          control.ControlledNode
 	DataDir           string
@@ -37,6 +37,10 @@ type Worker struct {
 	RuntimeRegistry   runtimeRegistry.Registry
 	GenesisDoc        *genesis.Document
 
+	// NodeHooks is the registry of additional hooks attached to the committee nodes of this worker
+	// when they are started.
+	NodeHooks *committee.NodeHooksRegistry
+
 	runtimes map[common.Namespace]*committee.Node
 
 	ctx       context.Context
@@ -146,7 +150,7 @@ func (w *Worker) Initialized() <-chan struct{} {
 }
 
 // GetConfig returns the worker's configuration.
-func (w *Worker) GetConfig() Config {
+func (w *Worker) GetConfig() *Config {
 	return w.cfg
 }
 
@@ -189,6 +193,7 @@ func (w *Worker) NewUnmanagedCommitteeNode(runtime runtimeRegistry.Runtime, enab
 		w.KeyManager,
 		w.Consensus,
 		p2p,
+		w.NodeHooks,
 	)
 }
 
@@ -225,7 +230,7 @@ func newWorker(
 	ias ias.Endpoint,
 	keyManager keymanagerApi.Backend,
 	runtimeRegistry runtimeRegistry.Registry,
-	cfg Config,
+	cfg *Config,
 	genesisDoc *genesis.Document,
 ) (*Worker, error) {
 	w := &Worker{
@@ -242,6 +247,7 @@ func newWorker(
 		KeyManager:        keyManager,
 		RuntimeRegistry:   runtimeRegistry,
 		GenesisDoc:        genesisDoc,
+		NodeHooks:         committee.NewNodeHooksRegistry(),
 		runtimes:          make(map[common.Namespace]*committee.Node),
 		ctx:               ctx,
 		cancelCtx:         cancelCtx,
@@ -285,6 +291,7 @@ func New(
 		Name:     "external",
 		Port:     cfg.ClientPort,
 		Identity: identity,
+		Limits:   cfg.ClientLimits,
 	}
 	grpc, err := grpc.NewServer(serverConfig)
 	if err != nil {
@@ -292,7 +299,7 @@ func New(
 	}
 
 	ctx, cancelCtx := context.WithCancel(context.Background())
-	grpcPolicyWatcher := policywatcher.New(ctx, cfg.SentryAddresses, identity)
+	grpcPolicyWatcher := policywatcher.New(ctx, cfg.GetSentryAddresses, identity)
 
 	return newWorker(
 		ctx,
@@ -308,7 +315,7 @@ func New(
 		ias,
 		keyManager,
 		runtimeRegistry,
-		*cfg,
+		cfg,
 		genesisDoc,
 	)
 }
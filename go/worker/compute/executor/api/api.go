@@ -1,6 +1,147 @@
 package api
 
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
+)
+
 // Tx is a runtime transaction being sent to the executor node.
 type Tx struct {
 	Data []byte `json:"data"`
 }
+
+// Status is the executor worker status.
+type Status struct {
+	// State is the name of the executor worker's current state.
+	State string `json:"state"`
+
+	// Paused is true if the executor worker is paused by the operator.
+	Paused bool `json:"paused,omitempty"`
+
+	// TxPool is the status of the transaction pool. In case the transaction pool is not yet
+	// available, it will be nil.
+	TxPool *TxPoolStatus `json:"tx_pool,omitempty"`
+}
+
+// TxPoolStatus is the status of a runtime's transaction pool.
+type TxPoolStatus struct {
+	// Algorithm is the transaction scheduling algorithm.
+	Algorithm string `json:"algorithm"`
+
+	// ScheduleQueueSize is the number of checked transactions waiting to be scheduled.
+	ScheduleQueueSize uint64 `json:"schedule_queue_size"`
+	// CheckQueueSize is the number of transactions waiting to be checked.
+	CheckQueueSize uint64 `json:"check_queue_size"`
+	// MaxPoolSize is the maximum number of transactions in the transaction pool.
+	MaxPoolSize uint64 `json:"max_pool_size"`
+	// MaxLocalPoolSize is the maximum number of local transactions in the transaction pool.
+	MaxLocalPoolSize uint64 `json:"max_local_pool_size,omitempty"`
+
+	// WeightLimits are the per-round batch weight limits.
+	WeightLimits map[transaction.Weight]uint64 `json:"weight_limits"`
+}
+
+// TxPoolTransaction is a checked transaction waiting to be scheduled.
+type TxPoolTransaction struct {
+	// Hash is the transaction hash.
+	Hash hash.Hash `json:"hash"`
+
+	// Priority is the transaction priority.
+	Priority uint64 `json:"priority"`
+
+	// Weights are the transaction weights.
+	Weights map[transaction.Weight]uint64 `json:"weights"`
+}
+
+// TxEventKind is the kind of a transaction lifecycle event.
+type TxEventKind uint8
+
+const (
+	// TxEventQueuedForCheck is emitted when a transaction is queued for checks.
+	TxEventQueuedForCheck TxEventKind = 1
+	// TxEventChecked is emitted when a transaction passed checks and was queued for scheduling.
+	TxEventChecked TxEventKind = 2
+	// TxEventCheckFailed is emitted when a transaction failed (re)checks and was dropped.
+	TxEventCheckFailed TxEventKind = 3
+	// TxEventScheduled is emitted when a transaction was included in a proposed batch.
+	TxEventScheduled TxEventKind = 4
+	// TxEventRemoved is emitted when a transaction was removed from the transaction pool.
+	TxEventRemoved TxEventKind = 5
+	// TxEventExpired is emitted when a transaction expired before being scheduled and was dropped.
+	TxEventExpired TxEventKind = 6
+
+	txEventQueuedForCheck = "queued_for_check"
+	txEventChecked        = "checked"
+	txEventCheckFailed    = "check_failed"
+	txEventScheduled      = "scheduled"
+	txEventRemoved        = "removed"
+	txEventExpired        = "expired"
+)
+
+// String returns a string representation of a transaction event kind.
+func (k TxEventKind) String() string {
+	text, err := k.MarshalText()
+	if err != nil {
+		return "[unsupported transaction event kind]"
+	}
+	return string(text)
+}
+
+// MarshalText encodes a transaction event kind into text form.
+func (k TxEventKind) MarshalText() ([]byte, error) {
+	switch k {
+	case TxEventQueuedForCheck:
+		return []byte(txEventQueuedForCheck), nil
+	case TxEventChecked:
+		return []byte(txEventChecked), nil
+	case TxEventCheckFailed:
+		return []byte(txEventCheckFailed), nil
+	case TxEventScheduled:
+		return []byte(txEventScheduled), nil
+	case TxEventRemoved:
+		return []byte(txEventRemoved), nil
+	case TxEventExpired:
+		return []byte(txEventExpired), nil
+	default:
+		return nil, fmt.Errorf("unsupported transaction event kind: %d", k)
+	}
+}
+
+// UnmarshalText decodes a text slice into a transaction event kind.
+func (k *TxEventKind) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case txEventQueuedForCheck:
+		*k = TxEventQueuedForCheck
+	case txEventChecked:
+		*k = TxEventChecked
+	case txEventCheckFailed:
+		*k = TxEventCheckFailed
+	case txEventScheduled:
+		*k = TxEventScheduled
+	case txEventRemoved:
+		*k = TxEventRemoved
+	case txEventExpired:
+		*k = TxEventExpired
+	default:
+		return fmt.Errorf("unsupported transaction event kind: '%s'", string(text))
+	}
+	return nil
+}
+
+// TxEvent is a transaction lifecycle event.
+type TxEvent struct {
+	// Hash is the transaction hash.
+	Hash hash.Hash `json:"hash"`
+
+	// Kind is the event kind.
+	Kind TxEventKind `json:"kind"`
+
+	// Round is the round the transaction was scheduled for (only set for TxEventScheduled).
+	Round uint64 `json:"round,omitempty"`
+
+	// Reason is the human-readable reason why a transaction failed checks or was removed. It is
+	// empty for transactions that were removed after being processed.
+	Reason string `json:"reason,omitempty"`
+}
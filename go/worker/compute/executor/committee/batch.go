@@ -3,12 +3,30 @@ package committee
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 )
 
+// batchFetchTimeout is the maximum amount of time spent fetching a batch directly from peers
+// before falling back to storage.
+const batchFetchTimeout = 2 * time.Second
+
+// batchFetcher fetches proposed batches directly from peers.
+type batchFetcher interface {
+	// FetchProposedBatch fetches the transactions of a proposed batch directly from the proposer
+	// or other peers advertising them.
+	FetchProposedBatch(
+		ctx context.Context,
+		ioRoot hash.Hash,
+		proposer signature.PublicKey,
+		verify func(transaction.RawBatch) error,
+	) (transaction.RawBatch, error)
+}
+
 // unresolvedBatch is a batch that may still need to be resolved (fetched from storage).
 type unresolvedBatch struct {
 	// ioRoot is the I/O root from the transaction scheduler containing the inputs.
@@ -28,12 +46,59 @@ func (ub *unresolvedBatch) String() string {
 	return fmt.Sprintf("UnresolvedBatch{ioRoot: %s}", ub.ioRoot)
 }
 
-func (ub *unresolvedBatch) resolve(ctx context.Context, sb storage.Backend) (transaction.RawBatch, error) {
+// verify checks that the given batch corresponds to the batch's I/O root.
+func (ub *unresolvedBatch) verify(ctx context.Context, batch transaction.RawBatch) error {
+	if ub.maxBatchSize > 0 && uint64(len(batch)) > ub.maxBatchSize {
+		return fmt.Errorf("batch too large (%d transactions)", len(batch))
+	}
+	var batchSizeBytes uint64
+	for _, tx := range batch {
+		batchSizeBytes += uint64(len(tx))
+	}
+	if ub.maxBatchSizeBytes > 0 && batchSizeBytes > ub.maxBatchSizeBytes {
+		return fmt.Errorf("batch too large (%d bytes)", batchSizeBytes)
+	}
+
+	emptyRoot := ub.ioRoot
+	emptyRoot.Hash.Empty()
+
+	ioTree := transaction.NewTree(nil, emptyRoot)
+	defer ioTree.Close()
+
+	for idx, tx := range batch {
+		if err := ioTree.AddTransaction(ctx, transaction.Transaction{Input: tx, BatchOrder: uint32(idx)}, nil); err != nil {
+			return fmt.Errorf("failed to create I/O tree: %w", err)
+		}
+	}
+	_, ioRoot, err := ioTree.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create I/O tree: %w", err)
+	}
+	if !ioRoot.Equal(&ub.ioRoot.Hash) {
+		return fmt.Errorf("I/O root mismatch (expected: %s got: %s)", ub.ioRoot.Hash, ioRoot)
+	}
+	return nil
+}
+
+func (ub *unresolvedBatch) resolve(ctx context.Context, sb storage.Backend, bf batchFetcher) (transaction.RawBatch, error) {
 	if ub.batch != nil {
 		// In case we already have a resolved batch, just return it.
 		return ub.batch, nil
 	}
 
+	// Try fetching the batch directly from the proposer first.
+	if bf != nil {
+		fetchCtx, cancel := context.WithTimeout(ctx, batchFetchTimeout)
+		batch, err := bf.FetchProposedBatch(fetchCtx, ub.ioRoot.Hash, ub.txnSchedSignature.PublicKey, func(batch transaction.RawBatch) error {
+			return ub.verify(ctx, batch)
+		})
+		cancel()
+		if err == nil {
+			ub.batch = batch
+			return batch, nil
+		}
+	}
+
 	// Prioritize nodes that signed the storage receipt.
 	ctx = storage.WithNodePriorityHintFromSignatures(ctx, ub.storageSignatures)
 
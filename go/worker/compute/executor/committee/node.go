@@ -22,6 +22,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
@@ -31,7 +32,9 @@ import (
 	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
 	"github.com/oasisprotocol/oasis-core/go/runtime/scheduling"
 	schedulingAPI "github.com/oasisprotocol/oasis-core/go/runtime/scheduling/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/scheduling/journal"
 	"github.com/oasisprotocol/oasis-core/go/runtime/scheduling/simple/orderedmap"
+	txpool "github.com/oasisprotocol/oasis-core/go/runtime/scheduling/simple/txpool/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
@@ -39,6 +42,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/worker/common/committee"
 	"github.com/oasisprotocol/oasis-core/go/worker/common/p2p"
 	p2pError "github.com/oasisprotocol/oasis-core/go/worker/common/p2p/error"
+	executorAPI "github.com/oasisprotocol/oasis-core/go/worker/compute/executor/api"
 	"github.com/oasisprotocol/oasis-core/go/worker/registration"
 )
 
@@ -54,11 +58,16 @@ var (
 	// Transaction scheduling errors.
 	errNoBlocks        = fmt.Errorf("executor: no blocks")
 	errNotTxnScheduler = fmt.Errorf("executor: not transaction scheduler in this round")
+	errNoScheduler     = fmt.Errorf("executor: scheduler not available yet")
+	errDraining        = fmt.Errorf("executor: node is draining")
+	errPaused          = fmt.Errorf("executor: node is paused")
 
 	// proposeTimeoutDelay is the duration to wait before submitting the propose timeout request.
 	proposeTimeoutDelay = 2 * time.Second
 	// abortTimeout is the duration to wait for the runtime to abort.
 	abortTimeout = 5 * time.Second
+	// proposedBatchCacheSize is the number of recent batches served to peers.
+	proposedBatchCacheSize uint64 = 8
 )
 
 var (
@@ -140,12 +149,19 @@ type Node struct { // nolint: maligned
 	runtimeVersion       version.Version
 	runtimeCapabilityTEE *node.CapabilityTEE
 
-	lastScheduledCache    *lru.Cache
-	scheduleMaxTxPoolSize uint64
+	lastScheduledCache *lru.Cache
+
+	// proposedBatches are the recently proposed or resolved batches served to peers.
+	proposedBatches *lru.Cache
 
 	checkTxCh    *channels.RingChannel
 	checkTxQueue *orderedmap.OrderedMap
 
+	// txWatchers are the subscribers to transaction lifecycle events.
+	txWatchers *txWatchers
+	// txJournal is the optional persistent journal of checked transactions.
+	txJournal *journal.Journal
+
 	// The scheduler mutex is here to protect the initialization
 	// of the scheduler variable and updates to scheduler parameters.
 	schedulerMutex sync.RWMutex
@@ -153,17 +169,37 @@ type Node struct { // nolint: maligned
 	// Guarded by schedulerMutex.
 	// roundWeightLimits are the per round batch weight limits.
 	roundWeightLimits map[transaction.Weight]uint64
+	// Guarded by schedulerMutex.
+	scheduleMaxTxPoolSize uint64
+	// scheduleMaxLocalTxPoolSize is the maximum number of local transactions in the pool.
+	scheduleMaxLocalTxPoolSize uint64
+	// scheduleTieBreak is the ordering of scheduled transactions with equal priority.
+	scheduleTieBreak txpool.TieBreak
+	// txPoolExpiry is the expiry configuration of unscheduled transactions.
+	txPoolExpiry txpool.Expiry
 	// limitsLastUpdate is the round of the last update of the round weight limits.
 	limitsLastUpdate uint64
 	// schedulerAlgorithm is the scheduler algorithm.
 	schedulerAlgorithm string
 
+	// roothashParamsLock protects the cached roothash consensus parameters.
+	roothashParamsLock sync.Mutex
+	// roothashParams are the roothash consensus parameters cached for roothashParamsEpoch.
+	roothashParams      *roothash.ConsensusParameters
+	roothashParamsEpoch beacon.EpochTime
+
 	// Guarded by .commonNode.CrossNode.
 	proposingTimeout bool
 	prevEpochWorker  bool
+	// Guarded by .commonNode.CrossNode.
+	// draining is set once the node stops accepting new transactions and batches.
+	draining bool
+	// Guarded by .commonNode.CrossNode.
+	// paused is set while the node is paused by the operator.
+	paused bool
 
 	commonNode   *committee.Node
-	commonCfg    commonWorker.Config
+	commonCfg    *commonWorker.Config
 	roleProvider registration.RoleProvider
 
 	ctx       context.Context
@@ -195,7 +231,9 @@ func (n *Node) Name() string {
 
 // Start starts the service.
 func (n *Node) Start() error {
+	n.loadJournaledTxs()
 	go n.worker()
+	go n.republishWorker()
 	return nil
 }
 
@@ -211,6 +249,9 @@ func (n *Node) Quit() <-chan struct{} {
 
 // Cleanup performs the service specific post-termination cleanup.
 func (n *Node) Cleanup() {
+	if n.txJournal != nil {
+		n.txJournal.Close()
+	}
 }
 
 // Initialized returns a channel that will be closed when the node is
@@ -228,6 +269,13 @@ func (n *Node) WatchStateTransitions() (<-chan NodeState, *pubsub.Subscription)
 	return ch, sub
 }
 
+// WatchTransaction subscribes to lifecycle events of the given transaction in the transaction
+// pool. Events are only emitted for changes that happen after the subscription was made.
+func (n *Node) WatchTransaction(txHash hash.Hash) (<-chan *executorAPI.TxEvent, pubsub.ClosableSubscription, error) {
+	ch, sub := n.txWatchers.watch(txHash)
+	return ch, sub, nil
+}
+
 func (n *Node) getMetricLabels() prometheus.Labels {
 	return prometheus.Labels{
 		"runtime": n.commonNode.Runtime.ID().String(),
@@ -236,57 +284,117 @@ func (n *Node) getMetricLabels() prometheus.Labels {
 
 // Assumes scheduler is initialized.
 func (n *Node) clearQueuedTxs() {
+	txs := n.scheduler.GetTransactions()
 	n.scheduler.Clear()
+	if len(txs) > 0 {
+		hashes := make([]hash.Hash, 0, len(txs))
+		for _, tx := range txs {
+			hashes = append(hashes, tx.Hash())
+		}
+		n.txWatchers.notifyBatch(hashes, executorAPI.TxEventRemoved, 0, "transaction pool cleared")
+	}
+	if n.txJournal != nil {
+		if err := n.txJournal.Clear(); err != nil {
+			n.logger.Warn("failed to clear transaction journal",
+				"err", err,
+			)
+		}
+	}
 	if n.lastScheduledCache != nil {
 		n.lastScheduledCache.Clear()
 	}
 	incomingQueueSize.With(n.getMetricLabels()).Set(0)
 }
 
+// SubmitLocalTx queues a transaction submitted by a local client for checks.
+//
+// Local transactions are queued separately from transactions received from peers so that remote
+// traffic cannot evict them.
+func (n *Node) SubmitLocalTx(rawTx []byte) error {
+	n.commonNode.CrossNode.Lock()
+	acceptErr := n.acceptingWorkLocked()
+	n.commonNode.CrossNode.Unlock()
+	if acceptErr != nil {
+		return acceptErr
+	}
+
+	return n.queueTx(rawTx, true)
+}
+
+func (n *Node) queueTx(rawTx []byte, local bool) error {
+	// Note: if an epoch transition is just about to happen we can be out of
+	// the committee by the time we queue the transaction, but this is fine
+	// as scheduling is aware of this.
+	if es := n.commonNode.Group.GetEpochSnapshot(); !es.IsExecutorWorker() {
+		n.logger.Debug("unable to handle transaction, not execution worker",
+			"current_epoch", es.GetEpochNumber(),
+		)
+		return nil
+	}
+
+	// Skip recently scheduled transactions.
+	if n.lastScheduledCache != nil {
+		if _, b := n.lastScheduledCache.Get(hash.NewFromBytes(rawTx)); b {
+			n.logger.Debug("not scheduling duplicate transaction", "tx", rawTx)
+			return nil
+		}
+	}
+	// Queue transaction for checks.
+	n.logger.Debug("queuing transaction for check",
+		"tx", rawTx,
+		"local", local,
+	)
+	addTx := n.checkTxQueue.Add
+	if local {
+		addTx = n.checkTxQueue.AddLocal
+	}
+	if err := addTx(rawTx); err != nil {
+		n.logger.Error("unable to queue transaction",
+			"tx", rawTx,
+			"err", err,
+		)
+		return err
+	}
+	n.txWatchers.notify(&executorAPI.TxEvent{
+		Hash: hash.NewFromBytes(rawTx),
+		Kind: executorAPI.TxEventQueuedForCheck,
+	})
+	n.checkTxCh.In() <- struct{}{}
+	return nil
+}
+
 // HandlePeerMessage implements NodeHooks.
 func (n *Node) HandlePeerMessage(ctx context.Context, message *p2p.Message, isOwn bool) (bool, error) {
 	n.logger.Debug("received peer message", "message", message, "is_own", isOwn)
 
+	n.commonNode.CrossNode.Lock()
+	acceptErr := n.acceptingWorkLocked()
+	n.commonNode.CrossNode.Unlock()
+
 	switch {
 	case message.Tx != nil:
-		rawTx := message.Tx.Data
-
-		// Note: if an epoch transition is just about to happen we can be out of
-		// the committee by the time we queue the transaction, but this is fine
-		// as scheduling is aware of this.
-		if es := n.commonNode.Group.GetEpochSnapshot(); !es.IsExecutorWorker() {
-			n.logger.Debug("unable to handle transaction message, not execution worker",
-				"current_epoch", es.GetEpochNumber(),
+		if acceptErr != nil {
+			n.logger.Debug("unable to handle transaction message",
+				"err", acceptErr,
 			)
 			return true, nil
 		}
 
-		// Skip recently scheduled transactions.
-		if n.lastScheduledCache != nil {
-			if _, b := n.lastScheduledCache.Get(hash.NewFromBytes(rawTx)); b {
-				n.logger.Debug("not scheduling duplicate transaction", "tx", rawTx)
-				return true, nil
-			}
-		}
-		// Queue transaction for checks.
-		n.logger.Debug("queuing transaction for check",
-			"tx", rawTx,
-		)
-		if err := n.checkTxQueue.Add(rawTx); err != nil {
-			n.logger.Error("unable to queue transaction",
-				"tx", rawTx,
-				"err", err,
-			)
-			return true, err
-		}
-		n.checkTxCh.In() <- struct{}{}
-		return true, nil
+		// Transactions received via gossip (including our own) are never treated as local as
+		// local transactions are submitted via SubmitLocalTx.
+		return true, n.queueTx(message.Tx.Data, false)
 
 	case message.ProposedBatch != nil:
 		// Ignore own messages as those are handled via handleInternalBatchLocked.
 		if isOwn {
 			return true, nil
 		}
+		if acceptErr != nil {
+			n.logger.Debug("unable to handle proposed batch message",
+				"err", acceptErr,
+			)
+			return true, nil
+		}
 		crash.Here(crashPointBatchReceiveAfter)
 
 		sbd := message.ProposedBatch
@@ -414,6 +522,222 @@ func (n *Node) handleInternalBatchLocked(
 	)
 }
 
+// cacheProposedBatch caches the given batch so that it can be served to peers.
+func (n *Node) cacheProposedBatch(ioRoot hash.Hash, batch transaction.RawBatch) {
+	if err := n.proposedBatches.Put(ioRoot, batch); err != nil {
+		n.logger.Warn("failed to cache proposed batch",
+			"err", err,
+			"io_root", ioRoot,
+		)
+	}
+}
+
+// GetProposedBatch returns the transactions of the proposed batch with the given I/O root.
+//
+// Implements p2p.BatchProvider.
+func (n *Node) GetProposedBatch(ioRoot hash.Hash) (transaction.RawBatch, bool) {
+	batch, ok := n.proposedBatches.Get(ioRoot)
+	if !ok {
+		return nil, false
+	}
+	return batch.(transaction.RawBatch), true
+}
+
+// SetMaxTxPoolSize updates the maximum size of the transaction pool.
+func (n *Node) SetMaxTxPoolSize(maxTxPoolSize uint64) error {
+	n.schedulerMutex.Lock()
+	defer n.schedulerMutex.Unlock()
+
+	if maxTxPoolSize == n.scheduleMaxTxPoolSize {
+		return nil
+	}
+
+	// The scheduler is not available until the runtime is initialized, in which case it will be
+	// created with the updated size.
+	if n.scheduler != nil {
+		if err := n.scheduler.UpdateMaxPoolSize(maxTxPoolSize); err != nil {
+			return fmt.Errorf("updating scheduler max pool size: %w", err)
+		}
+	}
+	n.checkTxQueue.SetMaxPoolSize(maxTxPoolSize)
+	n.scheduleMaxTxPoolSize = maxTxPoolSize
+
+	n.logger.Info("updated maximum transaction pool size",
+		"max_tx_pool_size", maxTxPoolSize,
+	)
+
+	return nil
+}
+
+// GetStatus returns the executor worker status.
+func (n *Node) GetStatus(ctx context.Context) (*executorAPI.Status, error) {
+	n.commonNode.CrossNode.Lock()
+	state := n.state
+	paused := n.paused
+	n.commonNode.CrossNode.Unlock()
+
+	status := &executorAPI.Status{
+		State:  string(state.Name()),
+		Paused: paused,
+	}
+	txPool, err := n.GetTxPoolStatus()
+	switch err {
+	case nil:
+		status.TxPool = txPool
+	case errNoScheduler:
+		// The transaction pool is not available until the runtime is initialized.
+	default:
+		return nil, err
+	}
+	return status, nil
+}
+
+// Guarded by n.commonNode.CrossNode.
+func (n *Node) acceptingWorkLocked() error {
+	switch {
+	case n.draining:
+		return errDraining
+	case n.paused:
+		return errPaused
+	default:
+		return nil
+	}
+}
+
+// SetPaused pauses or resumes the node. While paused, the node does not check or schedule new
+// transactions and does not accept new batches, but batches already being processed are
+// completed.
+func (n *Node) SetPaused(paused bool) {
+	n.commonNode.CrossNode.Lock()
+	defer n.commonNode.CrossNode.Unlock()
+
+	if paused == n.paused {
+		return
+	}
+	n.paused = paused
+
+	n.logger.Info("updated paused status",
+		"paused", paused,
+	)
+
+	if !paused {
+		// Check any transactions queued while paused.
+		n.checkTxCh.In() <- struct{}{}
+	}
+}
+
+// Drain stops the node from accepting new transactions and batches and waits for the batch
+// currently being processed (if any) to be finalized.
+func (n *Node) Drain(ctx context.Context) error {
+	ch, sub := n.WatchStateTransitions()
+	defer sub.Close()
+
+	n.commonNode.CrossNode.Lock()
+	n.draining = true
+	state := n.state
+	n.commonNode.CrossNode.Unlock()
+
+	n.logger.Info("draining",
+		"state", state,
+	)
+
+	for {
+		switch state.Name() {
+		case NotReady, WaitingForBatch:
+			n.logger.Info("drained")
+			return nil
+		default:
+		}
+
+		select {
+		case state = <-ch:
+		case <-n.stopCh:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// GetTxPoolStatus returns the status of the transaction pool.
+func (n *Node) GetTxPoolStatus() (*executorAPI.TxPoolStatus, error) {
+	n.schedulerMutex.RLock()
+	defer n.schedulerMutex.RUnlock()
+
+	if n.scheduler == nil {
+		return nil, errNoScheduler
+	}
+
+	weightLimits := make(map[transaction.Weight]uint64, len(n.roundWeightLimits))
+	for w, l := range n.roundWeightLimits {
+		weightLimits[w] = l
+	}
+	return &executorAPI.TxPoolStatus{
+		Algorithm:         n.scheduler.Name(),
+		ScheduleQueueSize: n.scheduler.UnscheduledSize(),
+		CheckQueueSize:    n.checkTxQueue.Size(),
+		MaxPoolSize:       n.scheduleMaxTxPoolSize,
+		MaxLocalPoolSize:  n.scheduleMaxLocalTxPoolSize,
+		WeightLimits:      weightLimits,
+	}, nil
+}
+
+// GetTxPoolTransactions returns the checked transactions waiting to be scheduled in scheduling
+// order.
+func (n *Node) GetTxPoolTransactions() ([]*executorAPI.TxPoolTransaction, error) {
+	n.schedulerMutex.RLock()
+	defer n.schedulerMutex.RUnlock()
+
+	if n.scheduler == nil {
+		return nil, errNoScheduler
+	}
+
+	txs := n.scheduler.GetTransactions()
+	result := make([]*executorAPI.TxPoolTransaction, 0, len(txs))
+	for _, tx := range txs {
+		weights := make(map[transaction.Weight]uint64, len(tx.Weights()))
+		for w, v := range tx.Weights() {
+			weights[w] = v
+		}
+		result = append(result, &executorAPI.TxPoolTransaction{
+			Hash:     tx.Hash(),
+			Priority: tx.Priority(),
+			Weights:  weights,
+		})
+	}
+	return result, nil
+}
+
+// RemoveTxPoolTransactions removes the given transactions from the transaction pool and returns
+// the hashes of the transactions that were actually removed.
+func (n *Node) RemoveTxPoolTransactions(hashes []hash.Hash) ([]hash.Hash, error) {
+	n.schedulerMutex.RLock()
+	defer n.schedulerMutex.RUnlock()
+
+	if n.scheduler == nil {
+		return nil, errNoScheduler
+	}
+
+	var removed []hash.Hash
+	for _, h := range hashes {
+		if n.scheduler.IsQueued(h) {
+			removed = append(removed, h)
+		}
+	}
+	if err := n.scheduler.RemoveTxBatch(removed); err != nil {
+		return nil, err
+	}
+	incomingQueueSize.With(n.getMetricLabels()).Set(float64(n.scheduler.UnscheduledSize()))
+	n.txWatchers.notifyBatch(removed, executorAPI.TxEventRemoved, 0, "removed by operator")
+	n.unjournalTxs(removed)
+
+	n.logger.Warn("removed transactions from the transaction pool",
+		"removed", removed,
+	)
+
+	return removed, nil
+}
+
 func (n *Node) bumpReselect() {
 	select {
 	case n.reselect <- struct{}{}:
@@ -571,6 +895,9 @@ func (n *Node) HandleNewBlockLocked(blk *block.Block) {
 		}()
 	}
 
+	// Drop transactions that have been waiting for scheduling for too long.
+	n.removeExpiredTxs(header.Round)
+
 	// Clear the potentially set "is proposing timeout" flag from the previous round.
 	n.proposingTimeout = false
 
@@ -595,6 +922,13 @@ func (n *Node) HandleNewBlockLocked(blk *block.Block) {
 // checkTxBatch requests the runtime to check the validity of a transaction batch.
 // Transactions that pass the check are queued for scheduling.
 func (n *Node) checkTxBatch() {
+	n.commonNode.CrossNode.Lock()
+	paused := n.paused
+	n.commonNode.CrossNode.Unlock()
+	if paused {
+		return
+	}
+
 	batch := n.checkTxQueue.GetBatch()
 	if len(batch) == 0 {
 		return
@@ -619,7 +953,13 @@ func (n *Node) checkTxBatch() {
 		)
 		return
 	}
-	currentMaxMessages := rtDsc.Executor.MaxMessages
+	currentMaxMessages, err := n.getMaxMessages(n.ctx, rtDsc, currentEpoch, consensus.HeightLatest)
+	if err != nil {
+		n.logger.Error("failed to get runtime message limit",
+			"err", err,
+		)
+		return
+	}
 
 	// Check transaction batch.
 	results, err := rt.CheckTx(n.ctx, currentBlock, currentConsensusBlock, currentEpoch, currentMaxMessages, batch)
@@ -629,17 +969,30 @@ func (n *Node) checkTxBatch() {
 	}
 
 	txs := make([]*transaction.CheckedTransaction, 0, len(results))
+	var failed []hash.Hash
 	for i, res := range results {
 		if !res.IsSuccess() {
 			n.logger.Warn("check tx failed", "tx", batch[i], "result", res)
+			failed = append(failed, hash.NewFromBytes(batch[i]))
+			n.txWatchers.notify(&executorAPI.TxEvent{
+				Hash:   hash.NewFromBytes(batch[i]),
+				Kind:   executorAPI.TxEventCheckFailed,
+				Reason: res.Error.String(),
+			})
 			continue
 		}
 
-		txs = append(txs, res.ToCheckedTransaction(batch[i]))
+		tx := res.ToCheckedTransaction(batch[i])
+		tx.SetMeta(transaction.TransactionMeta{
+			Local: n.checkTxQueue.IsLocal(tx.Hash()),
+		})
+		txs = append(txs, tx)
 	}
 
 	// Remove the checked transaction batch.
 	n.checkTxQueue.RemoveBatch(batch)
+	// Transactions reloaded from the journal may no longer be valid.
+	n.unjournalTxs(failed)
 
 	// Queue checked transactions for scheduling.
 	n.queueTxBatch(txs)
@@ -647,11 +1000,27 @@ func (n *Node) checkTxBatch() {
 
 // queueTxBatch queues a runtime transaction batch for scheduling.
 func (n *Node) queueTxBatch(txs []*transaction.CheckedTransaction) {
+	var queued [][]byte
+	var dropped []hash.Hash
 	for _, tx := range txs {
 		if err := n.scheduler.QueueTx(tx); err != nil {
-			n.logger.Error("unable to schedule transaction", "tx", tx)
+			n.logger.Error("unable to schedule transaction",
+				"tx", tx,
+				"err", err,
+			)
+			n.txWatchers.notify(&executorAPI.TxEvent{
+				Hash:   tx.Hash(),
+				Kind:   executorAPI.TxEventRemoved,
+				Reason: err.Error(),
+			})
+			dropped = append(dropped, tx.Hash())
 			continue
 		}
+		queued = append(queued, tx.Raw())
+		n.txWatchers.notify(&executorAPI.TxEvent{
+			Hash: tx.Hash(),
+			Kind: executorAPI.TxEventChecked,
+		})
 		if n.lastScheduledCache != nil {
 			if err := n.lastScheduledCache.Put(tx.Hash(), true); err != nil {
 				// cache.Put can only error if capacity in bytes is used and the
@@ -664,6 +1033,66 @@ func (n *Node) queueTxBatch(txs []*transaction.CheckedTransaction) {
 	}
 
 	incomingQueueSize.With(n.getMetricLabels()).Set(float64(n.scheduler.UnscheduledSize()))
+
+	n.journalTxs(queued)
+	n.unjournalTxs(dropped)
+}
+
+// loadJournaledTxs queues all transactions from the transaction journal (if enabled) for checks.
+func (n *Node) loadJournaledTxs() {
+	if n.txJournal == nil {
+		return
+	}
+
+	txs, err := n.txJournal.Load()
+	if err != nil {
+		n.logger.Error("failed to load journaled transactions",
+			"err", err,
+		)
+		return
+	}
+	if len(txs) == 0 {
+		return
+	}
+
+	var dropped []hash.Hash
+	for _, tx := range txs {
+		if err = n.checkTxQueue.Add(tx); err != nil {
+			dropped = append(dropped, hash.NewFromBytes(tx))
+		}
+	}
+	n.unjournalTxs(dropped)
+
+	n.logger.Info("loaded journaled transactions",
+		"num_txs", len(txs),
+		"num_dropped", len(dropped),
+	)
+
+	n.checkTxCh.In() <- struct{}{}
+}
+
+// journalTxs adds the given checked transactions to the transaction journal (if enabled).
+func (n *Node) journalTxs(txs [][]byte) {
+	if n.txJournal == nil {
+		return
+	}
+	if err := n.txJournal.Add(txs); err != nil {
+		n.logger.Warn("failed to journal transactions",
+			"err", err,
+		)
+	}
+}
+
+// unjournalTxs removes the given transactions from the transaction journal (if enabled).
+func (n *Node) unjournalTxs(hashes []hash.Hash) {
+	if n.txJournal == nil {
+		return
+	}
+	if err := n.txJournal.Remove(hashes); err != nil {
+		n.logger.Warn("failed to remove transactions from journal",
+			"err", err,
+		)
+	}
 }
 
 // removeTxBatch removes a batch from scheduling queue.
@@ -679,10 +1108,45 @@ func (n *Node) removeTxBatch(batch transaction.RawBatch) error {
 	}
 
 	incomingQueueSize.With(n.getMetricLabels()).Set(float64(n.scheduler.UnscheduledSize()))
+	n.txWatchers.notifyBatch(hashes, executorAPI.TxEventRemoved, 0, "")
+	n.unjournalTxs(hashes)
 
 	return nil
 }
 
+// removeExpiredTxs removes transactions that expired before being scheduled from the
+// scheduling queue.
+func (n *Node) removeExpiredTxs(round uint64) {
+	if !n.txPoolExpiry.Enabled() {
+		return
+	}
+
+	n.schedulerMutex.RLock()
+	scheduler := n.scheduler
+	n.schedulerMutex.RUnlock()
+	if scheduler == nil {
+		return
+	}
+
+	expired := scheduler.RemoveExpired(round, time.Now())
+	if len(expired) == 0 {
+		return
+	}
+
+	n.logger.Debug("removed expired transactions from queue",
+		"num_txs", len(expired),
+		"round", round,
+	)
+
+	hashes := make([]hash.Hash, len(expired))
+	for i, tx := range expired {
+		hashes[i] = tx.Hash()
+	}
+	incomingQueueSize.With(n.getMetricLabels()).Set(float64(scheduler.UnscheduledSize()))
+	n.txWatchers.notifyBatch(hashes, executorAPI.TxEventExpired, round, "")
+	n.unjournalTxs(hashes)
+}
+
 // Assumes n.schedulerMutex lock is held.
 func (n *Node) updateRoundWeightLimitsLocked(newBatchLimits map[transaction.Weight]uint64, round uint64) error {
 	// Remove batch custom weight limits that don't exist anymore.
@@ -864,6 +1328,27 @@ func (n *Node) getRtStateAndRoundResults(ctx context.Context, height int64) (*ro
 	return state, roundResults, nil
 }
 
+// getMaxMessages returns the maximum number of messages that the runtime can emit in a single
+// round at the given consensus height. The roothash consensus parameters are only fetched once
+// per epoch.
+func (n *Node) getMaxMessages(ctx context.Context, rt *registry.Runtime, epoch beacon.EpochTime, height int64) (uint32, error) {
+	n.roothashParamsLock.Lock()
+	params, paramsEpoch := n.roothashParams, n.roothashParamsEpoch
+	n.roothashParamsLock.Unlock()
+
+	if params == nil || paramsEpoch != epoch {
+		var err error
+		if params, err = n.commonNode.Consensus.RootHash().ConsensusParameters(ctx, height); err != nil {
+			return 0, fmt.Errorf("failed to query roothash consensus parameters: %w", err)
+		}
+
+		n.roothashParamsLock.Lock()
+		n.roothashParams, n.roothashParamsEpoch = params, epoch
+		n.roothashParamsLock.Unlock()
+	}
+	return params.EffectiveMaxRuntimeMessages(rt), nil
+}
+
 func (n *Node) handleScheduleBatch(force bool) {
 	roundCtx, epoch, rtState, roundResults, blk, lb, err := func() (
 		context.Context,
@@ -882,6 +1367,9 @@ func (n *Node) handleScheduleBatch(force bool) {
 		if _, ok := n.state.(StateWaitingForBatch); !ok {
 			return roundCtx, nil, nil, nil, nil, nil, errIncorrectState
 		}
+		if err := n.acceptingWorkLocked(); err != nil {
+			return roundCtx, nil, nil, nil, nil, nil, err
+		}
 		if n.commonNode.CurrentBlock == nil {
 			return roundCtx, nil, nil, nil, nil, nil, errNoBlocks
 		}
@@ -999,6 +1487,7 @@ func (n *Node) handleScheduleBatch(force bool) {
 		)
 		return
 	}
+	n.cacheProposedBatch(ioRoot, rawBatch)
 
 	// Commit I/O tree to storage and obtain receipts.
 
@@ -1068,6 +1557,12 @@ func (n *Node) handleScheduleBatch(force bool) {
 	}
 	crash.Here(crashPointBatchPublishAfter)
 
+	scheduled := make([]hash.Hash, 0, len(batch))
+	for _, tx := range batch {
+		scheduled = append(scheduled, tx.Hash())
+	}
+	n.txWatchers.notifyBatch(scheduled, executorAPI.TxEventScheduled, blk.Header.Round+1, "")
+
 	// Also process the batch locally.
 	n.handleInternalBatchLocked(
 		ioRoot,
@@ -1265,9 +1760,18 @@ func (n *Node) startProcessingBatchLocked(batch *unresolvedBatch) {
 			return
 		}
 
+		maxMessages, err := n.getMaxMessages(ctx, state.Runtime, epoch.GetEpochNumber(), height)
+		if err != nil {
+			n.logger.Error("failed to get runtime message limit",
+				"err", err,
+				"height", height,
+			)
+			return
+		}
+
 		// Resolve the batch and dispatch it to the runtime.
 		readStartTime := time.Now()
-		resolvedBatch, err := batch.resolve(ctx, n.commonNode.Group.Storage())
+		resolvedBatch, err := batch.resolve(ctx, n.commonNode.Group.Storage(), n.commonNode.Group)
 		if err != nil {
 			n.logger.Error("failed to resolve batch",
 				"err", err,
@@ -1275,6 +1779,7 @@ func (n *Node) startProcessingBatchLocked(batch *unresolvedBatch) {
 			)
 			return
 		}
+		n.cacheProposedBatch(batch.ioRoot.Hash, resolvedBatch)
 
 		// Optionally start local storage replication in parallel to batch dispatch.
 		replicateCh := n.startLocalStorageReplication(ctx, blk, batch.ioRoot.Hash, resolvedBatch)
@@ -1287,7 +1792,7 @@ func (n *Node) startProcessingBatchLocked(batch *unresolvedBatch) {
 				Inputs:         resolvedBatch,
 				Block:          *blk,
 				Epoch:          epoch.GetEpochNumber(),
-				MaxMessages:    state.Runtime.Executor.MaxMessages,
+				MaxMessages:    maxMessages,
 			},
 		}
 		batchReadTime.With(n.getMetricLabels()).Observe(time.Since(readStartTime).Seconds())
@@ -1817,6 +2322,9 @@ func (n *Node) worker() {
 	n.schedulerAlgorithm = runtime.TxnScheduler.Algorithm
 	scheduler, err := scheduling.New(
 		n.scheduleMaxTxPoolSize,
+		n.scheduleMaxLocalTxPoolSize,
+		n.scheduleTieBreak,
+		n.txPoolExpiry,
 		n.schedulerAlgorithm,
 		n.roundWeightLimits,
 	)
@@ -1879,8 +2387,19 @@ func (n *Node) worker() {
 			// variable never gets updated.
 
 			// Update per round weight limits.
+			n.commonNode.CrossNode.Lock()
+			currentEpoch := n.commonNode.Group.GetEpochSnapshot().GetEpochNumber()
+			n.commonNode.CrossNode.Unlock()
+
+			var maxMessages uint32
+			if maxMessages, err = n.getMaxMessages(n.ctx, runtime, currentEpoch, consensus.HeightLatest); err != nil {
+				n.logger.Error("failed to get runtime message limit",
+					"err", err,
+				)
+				return
+			}
 			n.schedulerMutex.Lock()
-			n.roundWeightLimits[transaction.WeightConsensusMessages] = uint64(runtime.Executor.MaxMessages)
+			n.roundWeightLimits[transaction.WeightConsensusMessages] = uint64(maxMessages)
 			n.roundWeightLimits[transaction.WeightSizeBytes] = runtime.TxnScheduler.MaxBatchSizeBytes
 			n.roundWeightLimits[transaction.WeightCount] = runtime.TxnScheduler.MaxBatchSize
 			n.schedulerAlgorithm = runtime.TxnScheduler.Algorithm
@@ -1911,11 +2430,15 @@ func (n *Node) worker() {
 // NewNode initializes a new executor node.
 func NewNode(
 	commonNode *committee.Node,
-	commonCfg commonWorker.Config,
+	commonCfg *commonWorker.Config,
 	roleProvider registration.RoleProvider,
 	scheduleMaxTxPoolSize uint64,
+	scheduleMaxLocalTxPoolSize uint64,
+	scheduleTieBreak txpool.TieBreak,
+	txPoolExpiry txpool.Expiry,
 	lastScheduledCacheSize uint64,
 	checkTxMaxBatchSize uint64,
+	txJournal *journal.Journal,
 ) (*Node, error) {
 	metricsOnce.Do(func() {
 		prometheus.MustRegister(nodeCollectors...)
@@ -1929,37 +2452,57 @@ func NewNode(
 
 	var cache *lru.Cache
 	if lastScheduledCacheSize > 0 {
-		cache, err = lru.New(lru.Capacity(lastScheduledCacheSize, false))
+		cache, err = lru.New(
+			lru.Capacity(lastScheduledCacheSize, false),
+			lru.Metrics("executor_last_scheduled"),
+		)
 		if err != nil {
 			return nil, fmt.Errorf("error creating cache: %w", err)
 		}
 	}
 
+	proposedBatches, err := lru.New(
+		lru.Capacity(proposedBatchCacheSize, false),
+		lru.Metrics("executor_proposed_batches"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cache: %w", err)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	n := &Node{
-		RuntimeHostNode:       rhn,
-		commonNode:            commonNode,
-		commonCfg:             commonCfg,
-		roleProvider:          roleProvider,
-		scheduleMaxTxPoolSize: scheduleMaxTxPoolSize,
-		lastScheduledCache:    cache,
-		checkTxQueue:          orderedmap.New(scheduleMaxTxPoolSize, checkTxMaxBatchSize),
-		roundWeightLimits:     make(map[transaction.Weight]uint64),
-		checkTxCh:             channels.NewRingChannel(1),
-		ctx:                   ctx,
-		cancelCtx:             cancel,
-		stopCh:                make(chan struct{}),
-		quitCh:                make(chan struct{}),
-		initCh:                make(chan struct{}),
-		state:                 StateNotReady{},
-		stateTransitions:      pubsub.NewBroker(false),
-		reselect:              make(chan struct{}, 1),
-		logger:                logging.GetLogger("worker/executor/committee").With("runtime_id", commonNode.Runtime.ID()),
-	}
+		RuntimeHostNode:            rhn,
+		commonNode:                 commonNode,
+		commonCfg:                  commonCfg,
+		roleProvider:               roleProvider,
+		scheduleMaxTxPoolSize:      scheduleMaxTxPoolSize,
+		scheduleMaxLocalTxPoolSize: scheduleMaxLocalTxPoolSize,
+		scheduleTieBreak:           scheduleTieBreak,
+		txPoolExpiry:               txPoolExpiry,
+		lastScheduledCache:         cache,
+		proposedBatches:            proposedBatches,
+		checkTxQueue:               orderedmap.New(scheduleMaxTxPoolSize, checkTxMaxBatchSize),
+		txWatchers:                 newTxWatchers(),
+		txJournal:                  txJournal,
+		roundWeightLimits:          make(map[transaction.Weight]uint64),
+		checkTxCh:                  channels.NewRingChannel(1),
+		ctx:                        ctx,
+		cancelCtx:                  cancel,
+		stopCh:                     make(chan struct{}),
+		quitCh:                     make(chan struct{}),
+		initCh:                     make(chan struct{}),
+		state:                      StateNotReady{},
+		stateTransitions:           pubsub.NewBroker(false),
+		reselect:                   make(chan struct{}, 1),
+		logger:                     logging.GetLogger("worker/executor/committee").With("runtime_id", commonNode.Runtime.ID()),
+	}
+	n.checkTxQueue.SetMaxLocalPoolSize(scheduleMaxLocalTxPoolSize)
 
 	// Register prune handler.
 	commonNode.Runtime.History().Pruner().RegisterHandler(&pruneHandler{commonNode: commonNode})
+	// Serve proposed batches to peers.
+	commonNode.Group.ProvideBatches(n)
 
 	return n, nil
 }
@@ -0,0 +1,139 @@
+package committee
+
+import (
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
+	"github.com/oasisprotocol/oasis-core/go/worker/common/p2p"
+	executorAPI "github.com/oasisprotocol/oasis-core/go/worker/compute/executor/api"
+)
+
+// txRepublisher keeps track of when transactions in the pool were last published.
+type txRepublisher struct {
+	interval     time.Duration
+	maxBatchSize uint64
+
+	lastPublished map[hash.Hash]time.Time
+}
+
+// selectTxs returns the transactions from the given pool contents that are due for republishing
+// and marks them as published. Transactions seen for the first time are considered published
+// at the given time as they have just been received from peers.
+//
+// Returns true iff there are more transactions due than fit into a single batch.
+func (r *txRepublisher) selectTxs(now time.Time, txs []*transaction.CheckedTransaction) ([][]byte, bool) {
+	var (
+		due  [][]byte
+		more bool
+	)
+	pool := make(map[hash.Hash]struct{}, len(txs))
+	for _, tx := range txs {
+		txHash := tx.Hash()
+		pool[txHash] = struct{}{}
+
+		published, ok := r.lastPublished[txHash]
+		if !ok {
+			r.lastPublished[txHash] = now
+			continue
+		}
+		if now.Sub(published) < r.interval {
+			continue
+		}
+		if uint64(len(due)) >= r.maxBatchSize {
+			more = true
+			continue
+		}
+		due = append(due, tx.Raw())
+		r.lastPublished[txHash] = now
+	}
+
+	// Forget about transactions that are no longer in the pool.
+	for txHash := range r.lastPublished {
+		if _, ok := pool[txHash]; !ok {
+			delete(r.lastPublished, txHash)
+		}
+	}
+
+	return due, more
+}
+
+func newTxRepublisher(interval time.Duration, maxBatchSize uint64) *txRepublisher {
+	return &txRepublisher{
+		interval:      interval,
+		maxBatchSize:  maxBatchSize,
+		lastPublished: make(map[hash.Hash]time.Time),
+	}
+}
+
+// republishTxs republishes pool transactions that are due. Returns true iff more transactions
+// are due than have been republished.
+func (n *Node) republishTxs(r *txRepublisher) bool {
+	n.schedulerMutex.RLock()
+	scheduler := n.scheduler
+	n.schedulerMutex.RUnlock()
+	if scheduler == nil {
+		return false
+	}
+
+	txs, more := r.selectTxs(time.Now(), scheduler.GetTransactions())
+	if len(txs) == 0 {
+		return more
+	}
+
+	n.logger.Debug("republishing transactions",
+		"num_txs", len(txs),
+	)
+	for _, tx := range txs {
+		if err := n.commonNode.Group.Publish(&p2p.Message{
+			Tx: &executorAPI.Tx{
+				Data: tx,
+			},
+		}); err != nil {
+			n.logger.Debug("failed to republish transactions",
+				"err", err,
+			)
+			break
+		}
+	}
+	return more
+}
+
+// republishWorker periodically republishes transactions in the pool to peers so that peers
+// which missed the original gossip can still schedule them.
+func (n *Node) republishWorker() {
+	cfg := n.commonCfg.TxPool
+	if cfg.RepublishInterval == 0 {
+		return
+	}
+
+	// Wait for the scheduler to be initialized.
+	select {
+	case <-n.initCh:
+	case <-n.stopCh:
+		return
+	}
+
+	r := newTxRepublisher(cfg.RepublishInterval, cfg.MaxRepublishBatchSize)
+	ticker := time.NewTicker(cfg.RepublishInterval)
+	defer ticker.Stop()
+
+	var debounceCh <-chan time.Time
+	for {
+		select {
+		case <-n.stopCh:
+			return
+		case <-ticker.C:
+			if debounceCh != nil {
+				// Already republishing the remaining due transactions.
+				continue
+			}
+		case <-debounceCh:
+		}
+
+		debounceCh = nil
+		if n.republishTxs(r) {
+			debounceCh = time.After(cfg.RepublishDebounceInterval)
+		}
+	}
+}
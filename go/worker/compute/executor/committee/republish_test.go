@@ -0,0 +1,48 @@
+package committee
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
+)
+
+func TestTxRepublisher(t *testing.T) {
+	require := require.New(t)
+
+	r := newTxRepublisher(time.Minute, 2)
+	txs := []*transaction.CheckedTransaction{
+		transaction.RawCheckedTransaction([]byte("tx a")),
+		transaction.RawCheckedTransaction([]byte("tx b")),
+		transaction.RawCheckedTransaction([]byte("tx c")),
+	}
+	now := time.Now()
+
+	// Newly seen transactions should not be republished immediately.
+	due, more := r.selectTxs(now, txs)
+	require.Empty(due, "new transactions should not be due")
+	require.False(more)
+
+	due, more = r.selectTxs(now.Add(30*time.Second), txs)
+	require.Empty(due, "transactions should not be due before the interval")
+	require.False(more)
+
+	// After the interval, transactions should be republished in batches.
+	now = now.Add(time.Minute)
+	due, more = r.selectTxs(now, txs)
+	require.Len(due, 2, "batch should be limited to max batch size")
+	require.True(more, "remaining transactions should be reported")
+
+	due, more = r.selectTxs(now, txs)
+	require.EqualValues([][]byte{txs[2].Raw()}, due, "remaining transaction should be due")
+	require.False(more)
+
+	due, _ = r.selectTxs(now, txs)
+	require.Empty(due, "republished transactions should not be due again")
+
+	// Transactions removed from the pool should be forgotten.
+	_, _ = r.selectTxs(now, txs[:1])
+	require.Len(r.lastPublished, 1)
+}
@@ -0,0 +1,100 @@
+package committee
+
+import (
+	"sync"
+
+	"github.com/eapache/channels"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	executorAPI "github.com/oasisprotocol/oasis-core/go/worker/compute/executor/api"
+)
+
+// txWatchers tracks subscriptions to lifecycle events of individual transactions.
+type txWatchers struct {
+	sync.Mutex
+
+	watchers map[hash.Hash]map[*txWatcher]struct{}
+}
+
+type txWatcher struct {
+	tw     *txWatchers
+	txHash hash.Hash
+	ch     *channels.InfiniteChannel
+
+	closeOnce sync.Once
+}
+
+// Close implements pubsub.ClosableSubscription.
+func (w *txWatcher) Close() {
+	w.closeOnce.Do(func() {
+		w.tw.Lock()
+		defer w.tw.Unlock()
+
+		watchers := w.tw.watchers[w.txHash]
+		delete(watchers, w)
+		if len(watchers) == 0 {
+			delete(w.tw.watchers, w.txHash)
+		}
+		w.ch.Close()
+	})
+}
+
+// watch subscribes to lifecycle events of the given transaction.
+func (tw *txWatchers) watch(txHash hash.Hash) (<-chan *executorAPI.TxEvent, pubsub.ClosableSubscription) {
+	w := &txWatcher{
+		tw:     tw,
+		txHash: txHash,
+		ch:     channels.NewInfiniteChannel(),
+	}
+	ch := make(chan *executorAPI.TxEvent)
+	channels.Unwrap(w.ch, ch)
+
+	tw.Lock()
+	defer tw.Unlock()
+
+	watchers := tw.watchers[txHash]
+	if watchers == nil {
+		watchers = make(map[*txWatcher]struct{})
+		tw.watchers[txHash] = watchers
+	}
+	watchers[w] = struct{}{}
+
+	return ch, w
+}
+
+// notify emits the given event to all watchers of the event's transaction.
+func (tw *txWatchers) notify(ev *executorAPI.TxEvent) {
+	tw.Lock()
+	defer tw.Unlock()
+
+	for w := range tw.watchers[ev.Hash] {
+		w.ch.In() <- ev
+	}
+}
+
+// notifyBatch emits an event of the given kind for each of the given transactions.
+func (tw *txWatchers) notifyBatch(hashes []hash.Hash, kind executorAPI.TxEventKind, round uint64, reason string) {
+	tw.Lock()
+	defer tw.Unlock()
+
+	if len(tw.watchers) == 0 {
+		return
+	}
+	for _, h := range hashes {
+		for w := range tw.watchers[h] {
+			w.ch.In() <- &executorAPI.TxEvent{
+				Hash:   h,
+				Kind:   kind,
+				Round:  round,
+				Reason: reason,
+			}
+		}
+	}
+}
+
+func newTxWatchers() *txWatchers {
+	return &txWatchers{
+		watchers: make(map[hash.Hash]map[*txWatcher]struct{}),
+	}
+}
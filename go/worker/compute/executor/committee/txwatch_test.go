@@ -0,0 +1,56 @@
+package committee
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	executorAPI "github.com/oasisprotocol/oasis-core/go/worker/compute/executor/api"
+)
+
+func TestTxWatchers(t *testing.T) {
+	require := require.New(t)
+
+	tw := newTxWatchers()
+	txA := hash.NewFromBytes([]byte("tx a"))
+	txB := hash.NewFromBytes([]byte("tx b"))
+
+	chA, subA := tw.watch(txA)
+	defer subA.Close()
+
+	recv := func(ch <-chan *executorAPI.TxEvent) *executorAPI.TxEvent {
+		select {
+		case ev := <-ch:
+			return ev
+		case <-time.After(time.Second):
+			require.FailNow("timed out waiting for event")
+			return nil
+		}
+	}
+
+	tw.notify(&executorAPI.TxEvent{Hash: txB, Kind: executorAPI.TxEventQueuedForCheck})
+	tw.notify(&executorAPI.TxEvent{Hash: txA, Kind: executorAPI.TxEventQueuedForCheck})
+	ev := recv(chA)
+	require.Equal(txA, ev.Hash)
+	require.Equal(executorAPI.TxEventQueuedForCheck, ev.Kind)
+
+	tw.notifyBatch([]hash.Hash{txB, txA}, executorAPI.TxEventScheduled, 42, "")
+	ev = recv(chA)
+	require.Equal(executorAPI.TxEventScheduled, ev.Kind)
+	require.EqualValues(42, ev.Round)
+
+	chB, subB := tw.watch(txB)
+	subB.Close()
+	subB.Close()
+	_, ok := <-chB
+	require.False(ok, "channel should be closed after unsubscribing")
+	require.Len(tw.watchers, 1)
+
+	text, err := executorAPI.TxEventCheckFailed.MarshalText()
+	require.NoError(err, "MarshalText")
+	var kind executorAPI.TxEventKind
+	require.NoError(kind.UnmarshalText(text), "UnmarshalText")
+	require.Equal(executorAPI.TxEventCheckFailed, kind)
+}
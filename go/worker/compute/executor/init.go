@@ -1,9 +1,12 @@
 package executor
 
 import (
+	"fmt"
+
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
+	txpool "github.com/oasisprotocol/oasis-core/go/runtime/scheduling/simple/txpool/api"
 	workerCommon "github.com/oasisprotocol/oasis-core/go/worker/common"
 	"github.com/oasisprotocol/oasis-core/go/worker/compute"
 	"github.com/oasisprotocol/oasis-core/go/worker/registration"
@@ -11,34 +14,63 @@ import (
 
 const (
 	cfgMaxTxPoolSize       = "worker.executor.schedule_max_tx_pool_size"
+	cfgMaxLocalTxPoolSize  = "worker.tx_pool.max_local_pool_size"
+	cfgScheduleTieBreak    = "worker.executor.schedule_tie_break"
 	cfgScheduleTxCacheSize = "worker.executor.schedule_tx_cache_size"
 	cfgCheckTxMaxBatchSize = "worker.executor.check_tx_max_batch_size"
+	cfgTxPoolPersist       = "worker.tx_pool.persist"
+	cfgTxPoolMaxAgeRounds  = "worker.tx_pool.max_age_rounds"
+	cfgTxPoolMaxAge        = "worker.tx_pool.max_age"
 )
 
 // Flags has the configuration flags.
 var Flags = flag.NewFlagSet("", flag.ContinueOnError)
 
+// ReloadableConfig are the configuration keys that can be changed at runtime via
+// Worker.ReloadConfig.
+var ReloadableConfig = []string{cfgMaxTxPoolSize}
+
 // New creates a new executor worker.
 func New(
 	dataDir string,
 	commonWorker *workerCommon.Worker,
 	registration *registration.Worker,
 ) (*Worker, error) {
+	var tieBreak txpool.TieBreak
+	if err := tieBreak.UnmarshalText([]byte(viper.GetString(cfgScheduleTieBreak))); err != nil {
+		return nil, fmt.Errorf("worker/executor: invalid %s: %w", cfgScheduleTieBreak, err)
+	}
+	if viper.GetDuration(cfgTxPoolMaxAge) < 0 {
+		return nil, fmt.Errorf("worker/executor: %s must not be negative", cfgTxPoolMaxAge)
+	}
+
 	return newWorker(
 		dataDir,
 		compute.Enabled(),
 		commonWorker,
 		registration,
 		viper.GetUint64(cfgMaxTxPoolSize),
+		viper.GetUint64(cfgMaxLocalTxPoolSize),
+		tieBreak,
+		txpool.Expiry{
+			MaxAgeRounds: viper.GetUint64(cfgTxPoolMaxAgeRounds),
+			MaxAge:       viper.GetDuration(cfgTxPoolMaxAge),
+		},
 		viper.GetUint64(cfgScheduleTxCacheSize),
 		viper.GetUint64(cfgCheckTxMaxBatchSize),
+		viper.GetBool(cfgTxPoolPersist),
 	)
 }
 
 func init() {
 	Flags.Uint64(cfgMaxTxPoolSize, 10_000, "Maximum size of the scheduling transaction pool")
+	Flags.String(cfgScheduleTieBreak, txpool.TieBreakHash.String(), "Ordering of scheduled transactions with equal priority (hash, arrival)")
 	Flags.Uint64(cfgScheduleTxCacheSize, 10_000, "Cache size of recently scheduled transactions to prevent re-scheduling")
 	Flags.Uint64(cfgCheckTxMaxBatchSize, 10_000, "Maximum check tx batch size")
+	Flags.Bool(cfgTxPoolPersist, false, "Persist checked transactions and recheck them after a restart")
+	Flags.Uint64(cfgTxPoolMaxAgeRounds, 0, "Maximum number of rounds a transaction may wait for scheduling (0 disables)")
+	Flags.Duration(cfgTxPoolMaxAge, 0, "Maximum time a transaction may wait for scheduling (0 disables)")
+	Flags.Uint64(cfgMaxLocalTxPoolSize, 1_000, "Maximum number of locally submitted transactions queued separately from remote ones (0 disables)")
 
 	_ = viper.BindPFlags(Flags)
 }
@@ -4,9 +4,14 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/spf13/viper"
+
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
+	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
+	"github.com/oasisprotocol/oasis-core/go/runtime/scheduling/journal"
+	txpool "github.com/oasisprotocol/oasis-core/go/runtime/scheduling/simple/txpool/api"
 	workerCommon "github.com/oasisprotocol/oasis-core/go/worker/common"
 	committeeCommon "github.com/oasisprotocol/oasis-core/go/worker/common/committee"
 	"github.com/oasisprotocol/oasis-core/go/worker/compute/executor/committee"
@@ -17,9 +22,15 @@ import (
 type Worker struct {
 	enabled bool
 
-	scheduleMaxTxPoolSize uint64
-	scheduleTxCacheSize   uint64
-	checkTxMaxBatchSize   uint64
+	dataDir string
+
+	scheduleMaxTxPoolSize      uint64
+	scheduleMaxLocalTxPoolSize uint64
+	scheduleTieBreak           txpool.TieBreak
+	txPoolExpiry               txpool.Expiry
+	scheduleTxCacheSize        uint64
+	checkTxMaxBatchSize        uint64
+	txPoolPersist              bool
 
 	commonWorker *workerCommon.Worker
 	registration *registration.Worker
@@ -137,6 +148,34 @@ func (w *Worker) GetRuntime(id common.Namespace) *committee.Node {
 	return w.runtimes[id]
 }
 
+// SubmitLocalTx queues a transaction submitted by a local client for checks in the transaction
+// pool of the given runtime.
+//
+// Implements runtimeClient.LocalTxPool.
+func (w *Worker) SubmitLocalTx(runtimeID common.Namespace, tx []byte) error {
+	rt := w.runtimes[runtimeID]
+	if rt == nil {
+		return fmt.Errorf("worker/executor: runtime %s not registered", runtimeID)
+	}
+	return rt.SubmitLocalTx(tx)
+}
+
+// ReloadConfig re-reads the reloadable executor configuration (the maximum transaction pool size)
+// from the given configuration and applies it to all runtimes.
+func (w *Worker) ReloadConfig(cfg *viper.Viper) error {
+	if !w.enabled {
+		return nil
+	}
+
+	maxTxPoolSize := cfg.GetUint64(cfgMaxTxPoolSize)
+	for id, rt := range w.runtimes {
+		if err := rt.SetMaxTxPoolSize(maxTxPoolSize); err != nil {
+			return fmt.Errorf("worker/executor: failed to update runtime %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
 func (w *Worker) registerRuntime(commonNode *committeeCommon.Node) error {
 	id := commonNode.Runtime.ID()
 	w.logger.Info("registering new runtime",
@@ -148,16 +187,35 @@ func (w *Worker) registerRuntime(commonNode *committeeCommon.Node) error {
 		return fmt.Errorf("failed to create role provider: %w", err)
 	}
 
+	// Open the transaction pool journal when persistence is enabled.
+	var txJournal *journal.Journal
+	if w.txPoolPersist {
+		path, err := runtimeRegistry.EnsureRuntimeStateDir(w.dataDir, id)
+		if err != nil {
+			return fmt.Errorf("failed to create runtime state directory: %w", err)
+		}
+		if txJournal, err = journal.New(path, id); err != nil {
+			return fmt.Errorf("failed to open transaction pool journal: %w", err)
+		}
+	}
+
 	// Create committee node for the given runtime.
 	node, err := committee.NewNode(
 		commonNode,
 		w.commonWorker.GetConfig(),
 		rp,
 		w.scheduleMaxTxPoolSize,
+		w.scheduleMaxLocalTxPoolSize,
+		w.scheduleTieBreak,
+		w.txPoolExpiry,
 		w.scheduleTxCacheSize,
 		w.checkTxMaxBatchSize,
+		txJournal,
 	)
 	if err != nil {
+		if txJournal != nil {
+			txJournal.Close()
+		}
 		return err
 	}
 
@@ -177,24 +235,33 @@ func newWorker(
 	commonWorker *workerCommon.Worker,
 	registration *registration.Worker,
 	scheduleMaxTxPoolSize uint64,
+	scheduleMaxLocalTxPoolSize uint64,
+	scheduleTieBreak txpool.TieBreak,
+	txPoolExpiry txpool.Expiry,
 	scheduleTxCacheSize uint64,
 	checkTxMaxBatchSize uint64,
+	txPoolPersist bool,
 ) (*Worker, error) {
 	ctx, cancelCtx := context.WithCancel(context.Background())
 
 	w := &Worker{
-		enabled:               enabled,
-		commonWorker:          commonWorker,
-		scheduleMaxTxPoolSize: scheduleMaxTxPoolSize,
-		scheduleTxCacheSize:   scheduleTxCacheSize,
-		checkTxMaxBatchSize:   checkTxMaxBatchSize,
-		registration:          registration,
-		runtimes:              make(map[common.Namespace]*committee.Node),
-		ctx:                   ctx,
-		cancelCtx:             cancelCtx,
-		quitCh:                make(chan struct{}),
-		initCh:                make(chan struct{}),
-		logger:                logging.GetLogger("worker/executor"),
+		enabled:                    enabled,
+		dataDir:                    dataDir,
+		commonWorker:               commonWorker,
+		scheduleMaxTxPoolSize:      scheduleMaxTxPoolSize,
+		scheduleMaxLocalTxPoolSize: scheduleMaxLocalTxPoolSize,
+		scheduleTieBreak:           scheduleTieBreak,
+		txPoolExpiry:               txPoolExpiry,
+		scheduleTxCacheSize:        scheduleTxCacheSize,
+		checkTxMaxBatchSize:        checkTxMaxBatchSize,
+		txPoolPersist:              txPoolPersist,
+		registration:               registration,
+		runtimes:                   make(map[common.Namespace]*committee.Node),
+		ctx:                        ctx,
+		cancelCtx:                  cancelCtx,
+		quitCh:                     make(chan struct{}),
+		initCh:                     make(chan struct{}),
+		logger:                     logging.GetLogger("worker/executor"),
 	}
 
 	if enabled {
@@ -74,7 +74,7 @@ func (knw *kmNodeWatcher) watchNodes() {
 		// Rebuild the access policy, something has changed.
 		policy := accessctl.NewPolicy()
 
-		for _, addr := range knw.w.commonWorker.GetConfig().SentryAddresses {
+		for _, addr := range knw.w.commonWorker.GetConfig().GetSentryAddresses() {
 			sentryNodesPolicy.AddPublicKeyPolicy(&policy, addr.PubKey)
 		}
 
@@ -665,7 +665,7 @@ func (crw *clientRuntimeWatcher) updateExternalServicePolicyLocked(snapshot *com
 	}
 
 	// Apply rules for configured sentry nodes.
-	for _, addr := range crw.w.commonWorker.GetConfig().SentryAddresses {
+	for _, addr := range crw.w.commonWorker.GetConfig().GetSentryAddresses() {
 		sentryNodesPolicy.AddPublicKeyPolicy(&policy, addr.PubKey)
 	}
 
@@ -161,6 +161,9 @@ type Worker struct { // nolint: maligned
 	entityID           signature.PublicKey
 	registrationSigner signature.Signer
 
+	// sentryAddresses are the sentry addresses used for the current registration. They are only
+	// accessed from the registration loop and refreshed from the configuration on each
+	// registration.
 	sentryAddresses []node.TLSAddress
 
 	runtimeRegistry runtimeRegistry.Registry
@@ -182,6 +185,7 @@ type Worker struct { // nolint: maligned
 
 	roleProviders []*roleProvider
 	registerCh    chan struct{}
+	rotateCh      chan chan error
 
 	status control.RegistrationStatus
 }
@@ -202,17 +206,7 @@ func (w *Worker) registrationLoop() { // nolint: gocyclo
 					"retries", pushRetries,
 				)
 				pushRetries++
-				client, err := sentryClient.New(sentryAddr, w.identity)
-				if err != nil {
-					return err
-				}
-				defer client.Close()
-
-				err = client.SetUpstreamTLSPubKeys(w.ctx, pubKeys)
-				if err != nil {
-					return err
-				}
-				return nil
+				return w.pushTLSPubKeys(sentryAddr, pubKeys)
 			}
 
 			sched := backoff.WithMaxRetries(backoff.NewConstantBackOff(1*time.Second), 60)
@@ -319,26 +313,28 @@ Loop:
 				// TODO: Make this time-based instead.
 				rotateTLSCertsPer := beacon.EpochTime(viper.GetUint64(CfgRegistrationRotateCerts))
 				if rotateTLSCertsPer != 0 && (epoch-lastTLSRotationEpoch) >= rotateTLSCertsPer {
-					// Rotate node TLS certificates.
-					err := w.identity.RotateCertificates()
-					if err != nil {
-						w.logger.Error("node TLS certificate rotation failed",
-							"new_epoch", epoch,
-							"err", err,
-						)
-					} else {
-						pub1 := w.identity.GetTLSSigner().Public()
-						pub2 := w.identity.GetNextTLSSigner().Public()
+					if err := w.rotateCertificates(epoch); err == nil {
 						tlsRotationPending = true
-
-						w.logger.Info("node TLS certificates have been rotated",
-							"new_epoch", epoch,
-							"new_pub1", accessctl.SubjectFromPublicKey(pub1),
-							"new_pub2", accessctl.SubjectFromPublicKey(pub2),
-						)
 					}
 				}
 			}
+		case respCh := <-w.rotateCh:
+			// Manual TLS certificate rotation requested.
+			//
+			// The certificate that becomes current has been advertised as the next certificate
+			// in the registered descriptor, so the node remains reachable by peers which have
+			// not yet seen the new descriptor. To keep this true, do not rotate again before
+			// the previous rotation has been registered and seen for at least an epoch.
+			if tlsRotationPending || epoch <= lastTLSRotationEpoch {
+				respCh <- control.ErrCertificateRotationPending
+				continue Loop
+			}
+			if err := w.rotateCertificates(epoch); err != nil {
+				respCh <- err
+				continue Loop
+			}
+			tlsRotationPending = true
+			respCh <- nil
 		case ev := <-entityCh:
 			// Entity registration update.
 			if !ev.IsRegistration || !ev.Entity.ID.Equal(w.entityID) {
@@ -728,6 +724,42 @@ func (w *Worker) gatherTLSAddresses(sentryTLSAddrs []node.TLSAddress) ([]node.TL
 	return validatedAddrs, nil
 }
 
+func (w *Worker) gatherP2PAddresses(sentryP2PAddrs []node.Address) ([]node.Address, error) {
+	// If sentry nodes are used, only advertise sentry addresses so that the node's own P2P
+	// addresses remain hidden. Never fall back to the node's own addresses.
+	if len(w.sentryAddresses) > 0 {
+		ownAddrs := w.p2p.Addresses()
+
+		var validatedAddrs []node.Address
+	SentryAddrs:
+		for _, addr := range sentryP2PAddrs {
+			if err := registry.VerifyAddress(addr, allowUnroutableAddresses); err != nil {
+				w.logger.Error("worker/registration: skipping sentry P2P address due to invalid address",
+					"addr", addr,
+					"err", err,
+				)
+				continue
+			}
+			// Never advertise the node's own IP as that is what sentries are supposed to hide.
+			for _, ownAddr := range ownAddrs {
+				if addr.IP.Equal(ownAddr.IP) {
+					w.logger.Error("worker/registration: skipping sentry P2P address as it reveals the node's own address",
+						"addr", addr,
+					)
+					continue SentryAddrs
+				}
+			}
+			validatedAddrs = append(validatedAddrs, addr)
+		}
+		if len(validatedAddrs) == 0 {
+			return nil, fmt.Errorf("worker/registration: no sentry node provides usable P2P addresses")
+		}
+		return validatedAddrs, nil
+	}
+
+	return w.p2p.Addresses(), nil
+}
+
 func (w *Worker) registerNode(epoch beacon.EpochTime, hook RegisterNodeHook) error {
 	identityPublic := w.identity.NodeSigner.Public()
 	w.logger.Info("performing node (re-)registration",
@@ -735,6 +767,9 @@ func (w *Worker) registerNode(epoch beacon.EpochTime, hook RegisterNodeHook) err
 		"node_id", identityPublic.String(),
 	)
 
+	// Pick up any sentry address changes due to a configuration reload.
+	w.sentryAddresses = w.workerCommonCfg.GetSentryAddresses()
+
 	var nextPubKey signature.PublicKey
 	if s := w.identity.GetNextTLSSigner(); s != nil {
 		nextPubKey = s.Public()
@@ -776,8 +811,9 @@ func (w *Worker) registerNode(epoch beacon.EpochTime, hook RegisterNodeHook) err
 
 	var sentryConsensusAddrs []node.ConsensusAddress
 	var sentryTLSAddrs []node.TLSAddress
+	var sentryP2PAddrs []node.Address
 	if len(w.sentryAddresses) > 0 {
-		sentryConsensusAddrs, sentryTLSAddrs = w.querySentries()
+		sentryConsensusAddrs, sentryTLSAddrs, sentryP2PAddrs = w.querySentries()
 	}
 
 	// Add Consensus Addresses if required.
@@ -800,7 +836,11 @@ func (w *Worker) registerNode(epoch beacon.EpochTime, hook RegisterNodeHook) err
 
 	// Add P2P Addresses if required.
 	if nodeDesc.HasRoles(registry.P2PAddressRequiredRoles) {
-		nodeDesc.P2P.Addresses = w.p2p.Addresses()
+		addrs, err := w.gatherP2PAddresses(sentryP2PAddrs)
+		if err != nil {
+			return fmt.Errorf("error gathering P2P addresses: %w", err)
+		}
+		nodeDesc.P2P.Addresses = addrs
 	}
 
 	nodeSigners := []signature.Signer{
@@ -843,9 +883,10 @@ func (w *Worker) registerNode(epoch beacon.EpochTime, hook RegisterNodeHook) err
 	return nil
 }
 
-func (w *Worker) querySentries() ([]node.ConsensusAddress, []node.TLSAddress) {
+func (w *Worker) querySentries() ([]node.ConsensusAddress, []node.TLSAddress, []node.Address) {
 	var consensusAddrs []node.ConsensusAddress
 	var tlsAddrs []node.TLSAddress
+	var p2pAddrs []node.Address
 	var err error
 
 	pubKeys := w.identity.GetTLSPubKeys()
@@ -882,6 +923,7 @@ func (w *Worker) querySentries() ([]node.ConsensusAddress, []node.TLSAddress) {
 
 		consensusAddrs = append(consensusAddrs, sentryAddresses.Consensus...)
 		tlsAddrs = append(tlsAddrs, sentryAddresses.TLS...)
+		p2pAddrs = append(p2pAddrs, sentryAddresses.P2P...)
 	}
 
 	if len(consensusAddrs) == 0 {
@@ -895,7 +937,7 @@ func (w *Worker) querySentries() ([]node.ConsensusAddress, []node.TLSAddress) {
 		)
 	}
 
-	return consensusAddrs, tlsAddrs
+	return consensusAddrs, tlsAddrs, p2pAddrs
 }
 
 // RequestDeregistration requests that the node not register itself in the next epoch.
@@ -918,6 +960,95 @@ func (w *Worker) RequestDeregistration() error {
 	return nil
 }
 
+// rotateCertificates rotates the node's TLS certificates.
+func (w *Worker) rotateCertificates(epoch beacon.EpochTime) error {
+	if err := w.identity.RotateCertificates(); err != nil {
+		w.logger.Error("node TLS certificate rotation failed",
+			"new_epoch", epoch,
+			"err", err,
+		)
+		return err
+	}
+
+	pub1 := w.identity.GetTLSSigner().Public()
+	pub2 := w.identity.GetNextTLSSigner().Public()
+	w.logger.Info("node TLS certificates have been rotated",
+		"new_epoch", epoch,
+		"new_pub1", accessctl.SubjectFromPublicKey(pub1),
+		"new_pub2", accessctl.SubjectFromPublicKey(pub2),
+	)
+
+	// Let the sentry nodes know about the new next certificate right away instead of waiting
+	// for the re-registration, so that they accept it well ahead of the next rotation. The
+	// sentry nodes keep accepting the retired certificate for a grace period.
+	pubKeys := w.identity.GetTLSPubKeys()
+	for _, sentryAddr := range w.sentryAddresses {
+		if err := w.pushTLSPubKeys(sentryAddr, pubKeys); err != nil {
+			w.logger.Warn("failed to push rotated TLS certificates to sentry node",
+				"err", err,
+				"sentry_address", sentryAddr,
+			)
+		}
+	}
+	return nil
+}
+
+// pushTLSPubKeys notifies the given sentry node of the node's TLS public keys.
+func (w *Worker) pushTLSPubKeys(sentryAddr node.TLSAddress, pubKeys []signature.PublicKey) error {
+	client, err := sentryClient.New(sentryAddr, w.identity)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return client.SetUpstreamTLSPubKeys(w.ctx, pubKeys)
+}
+
+// RequestCertificateRotation requests the node's TLS certificates to be rotated immediately and
+// the node to re-register with the new certificates.
+func (w *Worker) RequestCertificateRotation(ctx context.Context) error {
+	if w.identity.DoNotRotateTLS {
+		return identity.ErrCertificateRotationForbidden
+	}
+
+	respCh := make(chan error, 1)
+	select {
+	case w.rotateCh <- respCh:
+	case <-w.quitCh:
+		return context.Canceled
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-respCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ReloadConfig reloads the client and sentry addresses from the given configuration and triggers
+// a re-registration in case the advertised addresses changed.
+func (w *Worker) ReloadConfig(cfg *viper.Viper) error {
+	changed, err := w.workerCommonCfg.Reload(cfg)
+	if err != nil {
+		return fmt.Errorf("worker/registration: failed to reload configuration: %w", err)
+	}
+	if !changed {
+		return nil
+	}
+
+	w.logger.Info("advertised addresses changed, triggering re-registration")
+
+	select {
+	case w.registerCh <- struct{}{}:
+	default:
+		// A re-registration is already pending.
+	}
+	return nil
+}
+
 // GetRegistrationSigner loads the signing credentials as configured by this package's flags.
 func GetRegistrationSigner(logger *logging.Logger, dataDir string, identity *identity.Identity) (signature.PublicKey, signature.Signer, error) {
 	var defaultPk signature.PublicKey
@@ -1016,6 +1147,7 @@ func New(
 		consensus:          consensus,
 		p2p:                p2p,
 		registerCh:         make(chan struct{}, 64),
+		rotateCh:           make(chan chan error),
 	}
 
 	if flags.ConsensusValidator() {
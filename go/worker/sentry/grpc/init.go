@@ -3,16 +3,14 @@ package grpc
 
 import (
 	"context"
-	tlsPkg "crypto/tls"
 	"fmt"
+	"strings"
+	"time"
 
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/resolver"
-	"google.golang.org/grpc/resolver/manual"
 
-	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/common/grpc/proxy"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
@@ -27,20 +25,76 @@ const (
 	// CfgEnabled enables the sentry grpc worker.
 	CfgEnabled = "worker.sentry.grpc.enabled"
 
-	// CfgUpstreamAddress is the grpc address of the upstream node.
+	// CfgUpstreamAddress are the grpc addresses of the upstream nodes, in order of preference.
 	CfgUpstreamAddress = "worker.sentry.grpc.upstream.address"
-	// CfgUpstreamID is the node ID of the upstream node.
+	// CfgUpstreamID are the node IDs of the upstream nodes, in the same order as the addresses.
 	CfgUpstreamID = "worker.sentry.grpc.upstream.id"
+	// CfgUpstreamHealthCheckInterval is the interval at which the connectivity to the upstream
+	// nodes is checked.
+	CfgUpstreamHealthCheckInterval = "worker.sentry.grpc.upstream.health_check_interval"
+
+	// CfgUpstreamTLSKeyGracePeriod is the period during which TLS public keys that are no longer
+	// pushed by an upstream node are still accepted.
+	CfgUpstreamTLSKeyGracePeriod = "worker.sentry.grpc.upstream.tls_key_grace_period"
 
 	// CfgClientAddresses are addresses on which the gRPC endpoint is reachable.
 	CfgClientAddresses = "worker.sentry.grpc.client.address"
 	// CfgClientPort is the sentry node's client port.
 	CfgClientPort = "worker.sentry.grpc.client.port"
+
+	// CfgClientRate is the maximum rate of new proxied gRPC streams per client (in streams per
+	// second).
+	CfgClientRate = "worker.sentry.grpc.client.rate"
+	// CfgClientBurst is the maximum burst of new proxied gRPC streams per client.
+	CfgClientBurst = "worker.sentry.grpc.client.burst"
+	// CfgClientMaxStreams is the maximum number of concurrent proxied gRPC streams per client.
+	CfgClientMaxStreams = "worker.sentry.grpc.client.max_streams"
+
+	// CfgServicePolicies configures the per-service policies for proxied gRPC services.
+	CfgServicePolicies = "worker.sentry.grpc.policies"
 )
 
+// ServicePolicy is the policy of the sentry node for a proxied gRPC service.
+type ServicePolicy string
+
+const (
+	// ServicePolicyDynamic enforces the access policies pushed by the upstream node. This is the
+	// default policy for services without a configured policy.
+	ServicePolicyDynamic ServicePolicy = "dynamic"
+	// ServicePolicyPublic proxies requests to methods without access control even if the upstream
+	// node did not push any access policies for the service. Access controlled methods still
+	// enforce the access policies pushed by the upstream node.
+	ServicePolicyPublic ServicePolicy = "public"
+	// ServicePolicyDeny rejects all requests.
+	ServicePolicyDeny ServicePolicy = "deny"
+)
+
+// parseServicePolicies parses the configured per-service policies, keyed by the lower-cased full
+// service name.
+func parseServicePolicies(cfg *viper.Viper) (map[string]ServicePolicy, error) {
+	policies := make(map[string]ServicePolicy)
+	for name, raw := range cfg.GetStringMapString(CfgServicePolicies) {
+		if name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("malformed service name: '%s'", name)
+		}
+		p := ServicePolicy(strings.ToLower(raw))
+		switch p {
+		case ServicePolicyDynamic, ServicePolicyPublic, ServicePolicyDeny:
+		default:
+			return nil, fmt.Errorf("unknown policy for service %s: '%s'", name, raw)
+		}
+		policies[strings.ToLower(string(cmnGrpc.NewServiceName(name)))] = p
+	}
+	return policies, nil
+}
+
 // Flags has the configuration flags.
 var Flags = flag.NewFlagSet("", flag.ContinueOnError)
 
+// ReloadableConfig are the configuration keys that can be changed at runtime via
+// Worker.ReloadConfig.
+var ReloadableConfig = []string{CfgUpstreamAddress, CfgUpstreamID, CfgServicePolicies}
+
 // GetNodeAddresses returns configured sentry node addresses.
 func GetNodeAddresses() ([]node.Address, error) {
 	clientAddresses, err := configparser.ParseAddressList(viper.GetStringSlice(CfgClientAddresses))
@@ -50,75 +104,6 @@ func GetNodeAddresses() ([]node.Address, error) {
 	return clientAddresses, nil
 }
 
-func initConnection(ctx context.Context, logger *logging.Logger, ident *identity.Identity, backend sentry.LocalBackend) (*grpc.ClientConn, error) {
-	var err error
-
-	addr := viper.GetString(CfgUpstreamAddress)
-
-	upstreamAddrs, err := configparser.ParseAddressList([]string{addr})
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse address: %s: %w", addr, err)
-	}
-
-	upstreamNodeIDRaw := viper.GetString(CfgUpstreamID)
-	var upstreamNodeID signature.PublicKey
-	err = upstreamNodeID.UnmarshalText([]byte(upstreamNodeIDRaw))
-	if err != nil {
-		return nil, fmt.Errorf("malformed upstream node ID: %s: %w", upstreamNodeIDRaw, err)
-	}
-
-	logger.Info("upstream node ID is valid",
-		"upstream_node_id", upstreamNodeIDRaw,
-	)
-
-	// Get upstream node's TLS public keys.
-	upstreamPubKeys, err := backend.GetUpstreamTLSPubKeys(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get upstream node's TLS public keys: %w", err)
-	}
-	if len(upstreamPubKeys) == 0 {
-		return nil, fmt.Errorf("upstream node has no defined TLS public keys")
-	}
-	logger.Info("found public keys for upstream node",
-		"num_keys", len(upstreamPubKeys),
-	)
-
-	pubKeys := make(map[signature.PublicKey]bool)
-	for _, pk := range upstreamPubKeys {
-		pubKeys[pk] = true
-	}
-	creds, err := cmnGrpc.NewClientCreds(&cmnGrpc.ClientOptions{
-		CommonName:    identity.CommonName,
-		ServerPubKeys: pubKeys,
-		GetClientCertificate: func(cri *tlsPkg.CertificateRequestInfo) (*tlsPkg.Certificate, error) {
-			return ident.GetTLSCertificate(), nil
-		},
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create TLS credentials: %w", err)
-	}
-
-	// Dial node
-	manualResolver := manual.NewBuilderWithScheme("oasis-core-resolver")
-	conn, err := cmnGrpc.Dial("oasis-core-resolver:///",
-		grpc.WithTransportCredentials(creds),
-		// https://github.com/grpc/grpc-go/issues/3003
-		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"round_robin"}`),
-		grpc.WithDefaultCallOptions(grpc.WaitForReady(true)),
-		grpc.WithResolvers(manualResolver),
-	) //nolint: staticcheck
-	if err != nil {
-		return nil, fmt.Errorf("error dialing node: %w", err)
-	}
-	var resolverState resolver.State
-	for _, addr := range upstreamAddrs {
-		resolverState.Addresses = append(resolverState.Addresses, resolver.Address{Addr: addr.String()})
-	}
-	manualResolver.UpdateState(resolverState)
-
-	return conn, nil
-}
-
 // New creates a new sentry grpc worker.
 func New(backend sentry.LocalBackend, identity *identity.Identity) (*Worker, error) {
 	logger := logging.GetLogger("sentry/grpc/worker")
@@ -142,12 +127,28 @@ func New(backend sentry.LocalBackend, identity *identity.Identity) (*Worker, err
 	if g.enabled {
 		logger.Info("Initializing gRPC sentry worker")
 
-		upstreamDialer := func(ctx context.Context) (*grpc.ClientConn, error) {
-			upstreamConn, err := initConnection(ctx, logger, identity, backend)
-			if err != nil {
-				return nil, fmt.Errorf("gRPC sentry worker initializing upstream connection failure: %w", err)
-			}
-			return upstreamConn, nil
+		var err error
+		if g.policies, err = parseServicePolicies(viper.GetViper()); err != nil {
+			return nil, fmt.Errorf("gRPC sentry worker: %w", err)
+		}
+
+		g.healthCheckInterval = viper.GetDuration(CfgUpstreamHealthCheckInterval)
+		if g.healthCheckInterval <= 0 {
+			return nil, fmt.Errorf("gRPC sentry worker: upstream health check interval must be positive")
+		}
+
+		if g.limiter, err = newClientLimiter(); err != nil {
+			return nil, fmt.Errorf("gRPC sentry worker: %w", err)
+		}
+		registerMetrics()
+
+		upstreams, err := parseUpstreams(viper.GetViper())
+		if err != nil {
+			return nil, fmt.Errorf("gRPC sentry worker: %w", err)
+		}
+		g.upstreams = newUpstreamManager(logger, identity, backend)
+		if err = g.upstreams.setUpstreams(upstreams); err != nil {
+			return nil, fmt.Errorf("gRPC sentry worker: %w", err)
 		}
 
 		// Create externally-accessible proxy gRPC server.
@@ -158,7 +159,7 @@ func New(backend sentry.LocalBackend, identity *identity.Identity) (*Worker, err
 			AuthFunc: g.authFunction(),
 			CustomOptions: []grpc.ServerOption{
 				// All unknown requests will be proxied to the upstream grpc server.
-				grpc.UnknownServiceHandler(proxy.Handler(upstreamDialer)),
+				grpc.UnknownServiceHandler(g.limiter.streamHandler(proxy.Handler(g.upstreams.dialer))),
 			},
 		}
 		grpcServer, err := cmnGrpc.NewServer(serverConfig)
@@ -173,10 +174,16 @@ func New(backend sentry.LocalBackend, identity *identity.Identity) (*Worker, err
 
 func init() {
 	Flags.Bool(CfgEnabled, false, "Enable Sentry gRPC worker (NOTE: This should only be enabled on gRPC Sentry nodes.)")
-	Flags.String(CfgUpstreamAddress, "", "Address of the upstream node")
-	Flags.String(CfgUpstreamID, "", "ID of the upstream node")
+	Flags.StringSlice(CfgUpstreamAddress, []string{}, "Addresses of the upstream nodes, in order of preference")
+	Flags.StringSlice(CfgUpstreamID, []string{}, "IDs of the upstream nodes, in the same order as the addresses")
+	Flags.Duration(CfgUpstreamHealthCheckInterval, 5*time.Second, "Interval at which the connectivity to the upstream nodes is checked")
+	Flags.Duration(CfgUpstreamTLSKeyGracePeriod, time.Hour, "Period during which TLS public keys that are no longer pushed by an upstream node are still accepted")
 	Flags.StringSlice(CfgClientAddresses, []string{}, "Address/port(s) to use for client connections for accessing this node")
 	Flags.Uint16(CfgClientPort, 9100, "Port to use for incoming gRPC client connections")
+	Flags.Float64(CfgClientRate, 0, "Maximum rate of new proxied gRPC streams per client in streams per second (0 disables)")
+	Flags.Uint64(CfgClientBurst, 100, "Maximum burst of new proxied gRPC streams per client")
+	Flags.Uint64(CfgClientMaxStreams, 0, "Maximum number of concurrent proxied gRPC streams per client (0 disables)")
+	Flags.StringToString(CfgServicePolicies, nil, "Policies for proxied gRPC services (format: <service>=<dynamic|public|deny>,...)")
 
 	_ = viper.BindPFlags(Flags)
 	Flags.AddFlagSet(cmdGrpc.ClientFlags)
@@ -0,0 +1,67 @@
+package grpc
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServicePolicies(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		policies map[string]string
+		expected map[string]ServicePolicy
+		valid    bool
+	}{
+		{
+			name:     "Empty",
+			policies: nil,
+			expected: map[string]ServicePolicy{},
+			valid:    true,
+		},
+		{
+			name: "Valid",
+			policies: map[string]string{
+				"ConsensusLight": "public",
+				"Storage":        "Dynamic",
+				"RuntimeClient":  "DENY",
+			},
+			expected: map[string]ServicePolicy{
+				"oasis-core.consensuslight": ServicePolicyPublic,
+				"oasis-core.storage":        ServicePolicyDynamic,
+				"oasis-core.runtimeclient":  ServicePolicyDeny,
+			},
+			valid: true,
+		},
+		{
+			name:     "UnknownPolicy",
+			policies: map[string]string{"Storage": "allow"},
+		},
+		{
+			name:     "EmptyPolicy",
+			policies: map[string]string{"Storage": ""},
+		},
+		{
+			name:     "MethodName",
+			policies: map[string]string{"Storage/SyncGet": "public"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			cfg := viper.New()
+			if tc.policies != nil {
+				cfg.Set(CfgServicePolicies, tc.policies)
+			}
+
+			policies, err := parseServicePolicies(cfg)
+			if !tc.valid {
+				require.Error(err, "parseServicePolicies should fail")
+				return
+			}
+			require.NoError(err, "parseServicePolicies")
+			require.Equal(tc.expected, policies)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// clientState is the rate limiting state of a single client.
+type clientState struct {
+	tokens  float64
+	last    time.Time
+	streams uint64
+}
+
+// clientLimiter limits the rate of new streams and the number of concurrent streams of each
+// client.
+//
+// The rate of new streams is limited using a token bucket per client. A nil limiter does not
+// limit anything.
+type clientLimiter struct {
+	sync.Mutex
+
+	rate       float64
+	burst      float64
+	maxStreams uint64
+
+	clients map[string]*clientState
+	now     func() time.Time
+}
+
+// acquire accounts for a new stream of the given client. In case the stream is allowed, the
+// returned function must be called once the stream terminates, otherwise the reason for
+// rejecting the stream is returned.
+func (l *clientLimiter) acquire(client string) (func(), string) {
+	if l == nil {
+		return func() {}, ""
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	now := l.now()
+	cs := l.clients[client]
+	if cs == nil {
+		cs = &clientState{
+			tokens: l.burst,
+			last:   now,
+		}
+		l.clients[client] = cs
+	}
+
+	if l.maxStreams > 0 && cs.streams >= l.maxStreams {
+		return nil, rejectReasonStreamLimit
+	}
+	if l.rate > 0 {
+		cs.tokens += now.Sub(cs.last).Seconds() * l.rate
+		if cs.tokens > l.burst {
+			cs.tokens = l.burst
+		}
+		cs.last = now
+		if cs.tokens < 1 {
+			return nil, rejectReasonRateLimit
+		}
+		cs.tokens--
+	}
+	cs.streams++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.Lock()
+			defer l.Unlock()
+			cs.streams--
+		})
+	}, ""
+}
+
+// prune removes the state of clients without active streams whose token buckets are full, as
+// such state is equivalent to the state of a new client.
+func (l *clientLimiter) prune() {
+	if l == nil {
+		return
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	now := l.now()
+	for client, cs := range l.clients {
+		if cs.streams > 0 {
+			continue
+		}
+		if l.rate > 0 && cs.tokens+now.Sub(cs.last).Seconds()*l.rate < l.burst {
+			continue
+		}
+		delete(l.clients, client)
+	}
+}
+
+// streamHandler wraps the given stream handler so that the client limits are enforced before
+// streams are passed to it.
+func (l *clientLimiter) streamHandler(handler grpc.StreamHandler) grpc.StreamHandler {
+	return func(srv interface{}, stream grpc.ServerStream) error {
+		release, reason := l.acquire(clientFromContext(stream.Context()))
+		if reason != "" {
+			rejectedStreamCount.With(prometheus.Labels{"reason": reason}).Inc()
+			return status.Errorf(codes.ResourceExhausted, "client limit exceeded: %s", reason)
+		}
+		defer release()
+
+		activeStreams.Inc()
+		defer activeStreams.Dec()
+
+		return handler(srv, stream)
+	}
+}
+
+// clientFromContext identifies the client of a stream by its IP address.
+//
+// TLS client certificates are not used to identify clients as they can be generated at will.
+func clientFromContext(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+func newClientLimiter() (*clientLimiter, error) {
+	rate := viper.GetFloat64(CfgClientRate)
+	burst := viper.GetUint64(CfgClientBurst)
+	maxStreams := viper.GetUint64(CfgClientMaxStreams)
+	if rate < 0 {
+		return nil, fmt.Errorf("%s must not be negative", CfgClientRate)
+	}
+	if rate > 0 && burst == 0 {
+		return nil, fmt.Errorf("%s must be non-zero when %s is set", CfgClientBurst, CfgClientRate)
+	}
+	if rate == 0 && maxStreams == 0 {
+		return nil, nil
+	}
+
+	return &clientLimiter{
+		rate:       rate,
+		burst:      float64(burst),
+		maxStreams: maxStreams,
+		clients:    make(map[string]*clientState),
+		now:        time.Now,
+	}, nil
+}
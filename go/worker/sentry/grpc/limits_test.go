@@ -0,0 +1,54 @@
+package grpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientLimiter(t *testing.T) {
+	require := require.New(t)
+
+	var nilLimiter *clientLimiter
+	release, reason := nilLimiter.acquire("client")
+	require.Empty(reason, "nil limiter should not limit")
+	release()
+
+	now := time.Unix(1000, 0)
+	l := &clientLimiter{
+		rate:       1,
+		burst:      2,
+		maxStreams: 2,
+		clients:    make(map[string]*clientState),
+		now:        func() time.Time { return now },
+	}
+
+	release1, reason := l.acquire("a")
+	require.Empty(reason, "stream within burst should be allowed")
+	release2, reason := l.acquire("a")
+	require.Empty(reason, "stream within burst should be allowed")
+	_, reason = l.acquire("a")
+	require.Equal(rejectReasonStreamLimit, reason, "streams exceeding the concurrency limit should be rejected")
+	releaseB, reason := l.acquire("b")
+	require.Empty(reason, "clients should be limited independently")
+	releaseB()
+
+	release1()
+	release1()
+	_, reason = l.acquire("a")
+	require.Equal(rejectReasonRateLimit, reason, "streams exceeding the rate limit should be rejected")
+
+	now = now.Add(time.Second)
+	release3, reason := l.acquire("a")
+	require.Empty(reason, "tokens should be refilled")
+	release2()
+	release3()
+
+	l.prune()
+	require.Len(l.clients, 1, "only clients with non-full buckets should be kept")
+	require.Contains(l.clients, "a", "clients with non-full buckets should be kept")
+	now = now.Add(10 * time.Second)
+	l.prune()
+	require.Empty(l.clients, "idle clients should be pruned")
+}
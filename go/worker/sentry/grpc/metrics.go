@@ -0,0 +1,53 @@
+package grpc
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	requestCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_sentry_grpc_requests",
+			Help: "Number of proxied gRPC requests accepted or rejected by the sentry node.",
+		},
+		[]string{"policy", "result"},
+	)
+	rejectedStreamCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_sentry_grpc_rejected_streams",
+			Help: "Number of proxied gRPC streams rejected by the sentry node due to client limits.",
+		},
+		[]string{"reason"},
+	)
+	activeStreams = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_sentry_grpc_active_streams",
+			Help: "Number of active proxied gRPC streams.",
+		},
+	)
+
+	sentryCollectors = []prometheus.Collector{
+		requestCount,
+		rejectedStreamCount,
+		activeStreams,
+	}
+
+	metricsOnce sync.Once
+)
+
+const (
+	requestResultAccepted = "accepted"
+	requestResultRejected = "rejected"
+
+	rejectReasonRateLimit   = "rate_limit"
+	rejectReasonStreamLimit = "stream_limit"
+)
+
+// registerMetrics registers the gRPC sentry metrics collectors.
+func registerMetrics() {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(sentryCollectors...)
+	})
+}
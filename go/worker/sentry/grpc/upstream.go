@@ -0,0 +1,317 @@
+package grpc
+
+import (
+	"context"
+	tlsPkg "crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	sentry "github.com/oasisprotocol/oasis-core/go/sentry/api"
+	"github.com/oasisprotocol/oasis-core/go/worker/common/configparser"
+)
+
+// Upstream is an upstream node protected by the sentry node.
+type Upstream struct {
+	// Address is the gRPC address of the upstream node.
+	Address node.Address
+	// ID is the node ID of the upstream node.
+	ID signature.PublicKey
+}
+
+// String returns a string representation of the upstream node.
+func (u Upstream) String() string {
+	return u.ID.String() + "@" + u.Address.String()
+}
+
+// parseUpstreams parses the configured upstream nodes, in order of preference.
+func parseUpstreams(cfg *viper.Viper) ([]Upstream, error) {
+	rawAddrs := cfg.GetStringSlice(CfgUpstreamAddress)
+	rawIDs := cfg.GetStringSlice(CfgUpstreamID)
+	if len(rawAddrs) == 0 {
+		return nil, fmt.Errorf("no upstream nodes configured")
+	}
+	if len(rawAddrs) != len(rawIDs) {
+		return nil, fmt.Errorf("number of upstream addresses (%d) does not match the number of upstream IDs (%d)",
+			len(rawAddrs), len(rawIDs),
+		)
+	}
+
+	addrs, err := configparser.ParseAddressList(rawAddrs)
+	if err != nil {
+		return nil, fmt.Errorf("malformed upstream address: %w", err)
+	}
+
+	upstreams := make([]Upstream, 0, len(addrs))
+	for i, addr := range addrs {
+		var id signature.PublicKey
+		if err = id.UnmarshalText([]byte(rawIDs[i])); err != nil {
+			return nil, fmt.Errorf("malformed upstream node ID: %s: %w", rawIDs[i], err)
+		}
+		upstreams = append(upstreams, Upstream{
+			Address: addr,
+			ID:      id,
+		})
+	}
+	return upstreams, nil
+}
+
+type upstreamConn struct {
+	Upstream
+
+	conn *grpc.ClientConn
+
+	state       connectivity.State
+	lastHealthy time.Time
+}
+
+func (uc *upstreamConn) healthy() bool {
+	return uc.state == connectivity.Ready
+}
+
+// upstreamManager maintains connections to the upstream nodes and selects the upstream node that
+// requests are proxied to.
+//
+// Requests are proxied to the first healthy upstream node, so in case the preferred upstream
+// node becomes unreachable, requests fail over to the next one and switch back once the
+// preferred upstream node is reachable again.
+type upstreamManager struct {
+	sync.RWMutex
+
+	logger   *logging.Logger
+	identity *identity.Identity
+	backend  sentry.LocalBackend
+
+	upstreams []*upstreamConn
+	active    *upstreamConn
+}
+
+func (m *upstreamManager) dial(upstream Upstream) (*grpc.ClientConn, error) {
+	creds, err := cmnGrpc.NewClientCreds(&cmnGrpc.ClientOptions{
+		CommonName: identity.CommonName,
+		// Upstream TLS public keys are pinned to the configured upstream node ID. As they may
+		// change at any time, they are looked up on every handshake.
+		GetServerPubKeys: func() (map[signature.PublicKey]bool, error) {
+			upstreamPubKeys, err := m.backend.GetUpstreamNodeTLSPubKeys(context.Background(), upstream.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get upstream node's TLS public keys: %w", err)
+			}
+			if len(upstreamPubKeys) == 0 {
+				return nil, fmt.Errorf("upstream node has no defined TLS public keys")
+			}
+			pubKeys := make(map[signature.PublicKey]bool)
+			for _, pk := range upstreamPubKeys {
+				pubKeys[pk] = true
+			}
+			return pubKeys, nil
+		},
+		GetClientCertificate: func(cri *tlsPkg.CertificateRequestInfo) (*tlsPkg.Certificate, error) {
+			return m.identity.GetTLSCertificate(), nil
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TLS credentials: %w", err)
+	}
+
+	conn, err := cmnGrpc.Dial(upstream.Address.String(),
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.WaitForReady(true)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error dialing upstream node: %w", err)
+	}
+	return conn, nil
+}
+
+// setUpstreams replaces the upstream nodes. Connections to upstream nodes that remain
+// configured are kept.
+func (m *upstreamManager) setUpstreams(upstreams []Upstream) error {
+	if len(upstreams) == 0 {
+		return fmt.Errorf("no upstream nodes configured")
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	existing := make(map[string]*upstreamConn)
+	for _, uc := range m.upstreams {
+		existing[uc.String()] = uc
+	}
+
+	var (
+		newUpstreams []*upstreamConn
+		dialed       []*grpc.ClientConn
+	)
+	for _, upstream := range upstreams {
+		if uc, ok := existing[upstream.String()]; ok {
+			delete(existing, upstream.String())
+			newUpstreams = append(newUpstreams, uc)
+			continue
+		}
+
+		conn, err := m.dial(upstream)
+		if err != nil {
+			for _, c := range dialed {
+				_ = c.Close()
+			}
+			return err
+		}
+		dialed = append(dialed, conn)
+		newUpstreams = append(newUpstreams, &upstreamConn{
+			Upstream: upstream,
+			conn:     conn,
+			state:    conn.GetState(),
+		})
+	}
+
+	for _, uc := range newUpstreams {
+		m.logger.Info("upstream node configured",
+			"address", uc.Address,
+			"id", uc.ID,
+		)
+	}
+
+	// Close connections to upstream nodes that are no longer configured.
+	for _, uc := range existing {
+		_ = uc.conn.Close()
+
+		m.logger.Info("removed upstream node",
+			"address", uc.Address,
+			"id", uc.ID,
+		)
+	}
+
+	m.upstreams = newUpstreams
+	m.checkHealthLocked()
+
+	return nil
+}
+
+// checkHealthLocked refreshes the connectivity state of all upstream nodes and selects the active
+// upstream node.
+func (m *upstreamManager) checkHealthLocked() {
+	var active *upstreamConn
+	for _, uc := range m.upstreams {
+		uc.state = uc.conn.GetState()
+		switch uc.state {
+		case connectivity.Ready:
+			uc.lastHealthy = time.Now()
+		case connectivity.Idle:
+			// Make sure that idle connections get (re)established so that they can be failed
+			// over to.
+			uc.conn.Connect()
+		default:
+		}
+
+		if active == nil && uc.healthy() {
+			active = uc
+		}
+	}
+
+	if active == m.active {
+		return
+	}
+	switch {
+	case active == nil:
+		m.logger.Warn("no healthy upstream nodes")
+	case m.active == nil:
+		m.logger.Info("proxying to upstream node",
+			"address", active.Address,
+			"id", active.ID,
+		)
+	default:
+		m.logger.Warn("switching to another upstream node",
+			"address", active.Address,
+			"id", active.ID,
+			"previous_address", m.active.Address,
+			"previous_id", m.active.ID,
+		)
+	}
+	m.active = active
+}
+
+// checkHealth refreshes the connectivity state of all upstream nodes and selects the active
+// upstream node.
+func (m *upstreamManager) checkHealth() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.checkHealthLocked()
+}
+
+// dialer returns the connection to the active upstream node.
+//
+// In case no upstream node is healthy, the connection to the most preferred upstream node is
+// returned so that requests wait for it to become ready.
+func (m *upstreamManager) dialer(ctx context.Context) (*grpc.ClientConn, error) {
+	m.RLock()
+	active := m.active
+	if active != nil && active.conn.GetState() == connectivity.Ready {
+		m.RUnlock()
+		return active.conn, nil
+	}
+	m.RUnlock()
+
+	// The active upstream node is no longer healthy, fail over immediately instead of waiting for
+	// the next periodic health check.
+	m.Lock()
+	defer m.Unlock()
+
+	m.checkHealthLocked()
+	switch {
+	case m.active != nil:
+		return m.active.conn, nil
+	case len(m.upstreams) > 0:
+		return m.upstreams[0].conn, nil
+	default:
+		return nil, fmt.Errorf("no upstream nodes configured")
+	}
+}
+
+// status returns the status of the upstream nodes.
+func (m *upstreamManager) status() []sentry.UpstreamStatus {
+	m.RLock()
+	defer m.RUnlock()
+
+	status := make([]sentry.UpstreamStatus, 0, len(m.upstreams))
+	for _, uc := range m.upstreams {
+		status = append(status, sentry.UpstreamStatus{
+			Address:     uc.Address.String(),
+			ID:          uc.ID,
+			State:       uc.state.String(),
+			Healthy:     uc.healthy(),
+			Active:      uc == m.active,
+			LastHealthy: uc.lastHealthy,
+		})
+	}
+	return status
+}
+
+// close closes the connections to all upstream nodes.
+func (m *upstreamManager) close() {
+	m.Lock()
+	defer m.Unlock()
+
+	for _, uc := range m.upstreams {
+		_ = uc.conn.Close()
+	}
+	m.upstreams = nil
+	m.active = nil
+}
+
+func newUpstreamManager(logger *logging.Logger, identity *identity.Identity, backend sentry.LocalBackend) *upstreamManager {
+	return &upstreamManager{
+		logger:   logger,
+		identity: identity,
+		backend:  backend,
+	}
+}
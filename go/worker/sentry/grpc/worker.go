@@ -3,8 +3,12 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/viper"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -12,6 +16,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/common/grpc/auth"
+	grpcPolicy "github.com/oasisprotocol/oasis-core/go/common/grpc/policy"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/service"
@@ -38,94 +43,148 @@ type Worker struct { // nolint: maligned
 
 	grpc     *cmnGrpc.Server
 	identity *identity.Identity
+
+	policies map[string]ServicePolicy
+
+	upstreams           *upstreamManager
+	healthCheckInterval time.Duration
+
+	limiter *clientLimiter
 }
 
 func (g *Worker) authFunction() auth.AuthenticationFunction {
 	return func(ctx context.Context,
 		fullMethodName string,
 		req interface{}) error {
-		serviceName := cmnGrpc.ServiceNameFromMethod(fullMethodName)
-		if serviceName == "" {
-			g.logger.Error("error getting service name from method",
-				"method_name", fullMethodName,
-			)
-			return status.Errorf(codes.PermissionDenied, fmt.Sprintf("invalid service in method: %s", fullMethodName))
-		}
+		policy, err := g.authorize(ctx, fullMethodName, req)
 
-		// Get method request type.
-		methodDesc, err := cmnGrpc.GetRegisteredMethod(fullMethodName)
+		result := requestResultAccepted
 		if err != nil {
-			g.logger.Error("error getting registered gRPC method",
-				"method_name", fullMethodName,
-				"err", err,
-			)
-			return status.Errorf(codes.PermissionDenied, fmt.Sprintf("unknown method: %s", fullMethodName))
+			result = requestResultRejected
 		}
+		requestCount.With(prometheus.Labels{"policy": string(policy), "result": result}).Inc()
+
+		return err
+	}
+}
+
+// authorize checks whether the given proxied request is allowed and returns the policy that was
+// applied to the request.
+func (g *Worker) authorize(ctx context.Context, fullMethodName string, req interface{}) (ServicePolicy, error) {
+	policy := ServicePolicyDynamic
 
-		g.RLock()
-		defer g.RUnlock()
+	serviceName := cmnGrpc.ServiceNameFromMethod(fullMethodName)
+	if serviceName == "" {
+		g.logger.Error("error getting service name from method",
+			"method_name", fullMethodName,
+		)
+		return policy, status.Errorf(codes.PermissionDenied, fmt.Sprintf("invalid service in method: %s", fullMethodName))
+	}
+
+	// Get method request type.
+	methodDesc, err := cmnGrpc.GetRegisteredMethod(fullMethodName)
+	if err != nil {
+		g.logger.Error("error getting registered gRPC method",
+			"method_name", fullMethodName,
+			"err", err,
+		)
+		return policy, status.Errorf(codes.PermissionDenied, fmt.Sprintf("unknown method: %s", fullMethodName))
+	}
+
+	// Administrative methods are never proxied.
+	if methodDesc.RequiresAdminCapability() {
+		return policy, status.Errorf(codes.PermissionDenied, "not allowed")
+	}
 
+	g.RLock()
+	if p, ok := g.policies[strings.ToLower(string(serviceName))]; ok {
+		policy = p
+	}
+	g.RUnlock()
+
+	var policyChecker *grpcPolicy.DynamicRuntimePolicyChecker
+	switch policy {
+	case ServicePolicyPublic:
+		// Methods without access control are proxied even if the upstream did not provide
+		// a policy checker for the service (e.g., for the public consensus light client
+		// service).
+	case ServicePolicyDeny:
+		return policy, status.Errorf(codes.PermissionDenied, "not allowed")
+	default:
 		// Ensure policy checker for the service exists. This needs to be done
 		// before checking if method is access controlled as otherwise the
 		// proxy would allow and propagate request for all registered methods
 		// without acesss control (even those not implemented by the upstream).
-		// XXX: This means that the proxy will reject requests to upstream
-		// services that do not provide at least a single policy checker.
-		// This is not the case in either of currently supported upstreams
-		// (storage and keymanager).
-		policyChecker, err := g.backend.GetPolicyChecker(ctx, serviceName)
-		if err != nil {
-			g.logger.Error("no policy checker defined for service",
-				"service_name", serviceName,
-			)
-			return status.Errorf(codes.PermissionDenied, "not allowed")
+		// This means that the proxy will reject requests to upstream services
+		// that do not provide at least a single policy checker.
+		if policyChecker, err = g.getPolicyChecker(ctx, serviceName); err != nil {
+			return policy, err
 		}
+	}
 
-		// Proxy defers unmarshalling.
-		rawCBOR, ok := req.(*cbor.RawMessage)
-		if !ok {
-			g.logger.Error("invalid proxy request type, expected *cbor.RawMessage",
-				"request", req,
-				"request_type", fmt.Sprintf("%T", req),
-			)
-			return status.Errorf(codes.PermissionDenied, "invalid request")
-		}
+	// Proxy defers unmarshalling.
+	rawCBOR, ok := req.(*cbor.RawMessage)
+	if !ok {
+		g.logger.Error("invalid proxy request type, expected *cbor.RawMessage",
+			"request", req,
+			"request_type", fmt.Sprintf("%T", req),
+		)
+		return policy, status.Errorf(codes.PermissionDenied, "invalid request")
+	}
 
-		// Unmarshal into correct type.
-		request, err := methodDesc.UnmarshalRawMessage(rawCBOR)
-		if err != nil {
-			g.logger.Error("error unamrshaling raw request",
-				"err", err,
-				"raw", rawCBOR,
-			)
-			return status.Errorf(codes.PermissionDenied, "invalid request")
-		}
+	// Unmarshal into correct type.
+	request, err := methodDesc.UnmarshalRawMessage(rawCBOR)
+	if err != nil {
+		g.logger.Error("error unamrshaling raw request",
+			"err", err,
+			"raw", rawCBOR,
+		)
+		return policy, status.Errorf(codes.PermissionDenied, "invalid request")
+	}
 
-		// Check whether access control must be done for this request.
-		ac, err := methodDesc.IsAccessControlled(ctx, request)
-		if err != nil {
-			g.logger.Error("failed to check if request is access controlled",
-				"err", err,
-			)
-			return status.Errorf(codes.PermissionDenied, "internal error")
-		}
-		if !ac {
-			// No access control, allow.
-			return nil
-		}
+	// Check whether access control must be done for this request.
+	ac, err := methodDesc.IsAccessControlled(ctx, request)
+	if err != nil {
+		g.logger.Error("failed to check if request is access controlled",
+			"err", err,
+		)
+		return policy, status.Errorf(codes.PermissionDenied, "internal error")
+	}
+	if !ac {
+		// No access control, allow.
+		return policy, nil
+	}
 
-		// Extract namespace.
-		namespace, err := methodDesc.ExtractNamespace(ctx, request)
-		if err != nil {
-			g.logger.Error("error extracting namespace from request",
-				"err", err,
-				"request", request,
-			)
-			return status.Errorf(codes.PermissionDenied, "invalid request")
+	// Access controlled methods are always subject to the policies pushed by the upstream node,
+	// even for public services.
+	if policyChecker == nil {
+		if policyChecker, err = g.getPolicyChecker(ctx, serviceName); err != nil {
+			return policy, err
 		}
+	}
 
-		return policyChecker.CheckAccessAllowed(ctx, accessctl.Action(fullMethodName), namespace)
+	// Extract namespace.
+	namespace, err := methodDesc.ExtractNamespace(ctx, request)
+	if err != nil {
+		g.logger.Error("error extracting namespace from request",
+			"err", err,
+			"request", request,
+		)
+		return policy, status.Errorf(codes.PermissionDenied, "invalid request")
 	}
+
+	return policy, policyChecker.CheckAccessAllowed(ctx, accessctl.Action(fullMethodName), namespace)
+}
+
+func (g *Worker) getPolicyChecker(ctx context.Context, serviceName cmnGrpc.ServiceName) (*grpcPolicy.DynamicRuntimePolicyChecker, error) {
+	policyChecker, err := g.backend.GetPolicyChecker(ctx, serviceName)
+	if err != nil {
+		g.logger.Error("no policy checker defined for service",
+			"service_name", serviceName,
+		)
+		return nil, status.Errorf(codes.PermissionDenied, "not allowed")
+	}
+	return policyChecker, nil
 }
 
 func (g *Worker) worker() {
@@ -135,16 +194,69 @@ func (g *Worker) worker() {
 	// Initialization complete.
 	close(g.initCh)
 
+	ticker := time.NewTicker(g.healthCheckInterval)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-g.stopCh:
 			return
 		case <-g.grpc.Quit():
 			return
+		case <-ticker.C:
+			g.upstreams.checkHealth()
+			g.limiter.prune()
 		}
 	}
 }
 
+// SetUpstreams replaces the upstream nodes, in order of preference, without restarting the
+// worker. Connections to upstream nodes that remain configured are kept.
+func (g *Worker) SetUpstreams(upstreams []Upstream) error {
+	if !g.enabled {
+		return fmt.Errorf("gRPC sentry worker is disabled")
+	}
+	return g.upstreams.setUpstreams(upstreams)
+}
+
+// ReloadConfig reloads the upstream nodes and the service policies from the given configuration.
+func (g *Worker) ReloadConfig(cfg *viper.Viper) error {
+	if !g.enabled {
+		return nil
+	}
+
+	policies, err := parseServicePolicies(cfg)
+	if err != nil {
+		return fmt.Errorf("gRPC sentry worker: failed to reload configuration: %w", err)
+	}
+	upstreams, err := parseUpstreams(cfg)
+	if err != nil {
+		return fmt.Errorf("gRPC sentry worker: failed to reload configuration: %w", err)
+	}
+	if err = g.SetUpstreams(upstreams); err != nil {
+		return err
+	}
+
+	g.Lock()
+	g.policies = policies
+	g.Unlock()
+
+	return nil
+}
+
+// GetStatus returns the status of the upstream nodes.
+func (g *Worker) GetStatus() *sentry.Status {
+	if !g.enabled {
+		return nil
+	}
+	// The local backend does not fail.
+	pubKeys, _ := g.backend.GetUpstreamTLSPubKeys(context.Background())
+	return &sentry.Status{
+		Upstreams:          g.upstreams.status(),
+		UpstreamTLSPubKeys: pubKeys,
+	}
+}
+
 // Initialized returns a channel that will be closed when the worker initializes.
 func (g *Worker) Initialized() <-chan struct{} {
 	return g.initCh
@@ -196,6 +308,7 @@ func (g *Worker) Cleanup() {
 		return
 	}
 	g.grpc.Cleanup()
+	g.upstreams.close()
 }
 
 // Quit returns a channel that will be closed when the service terminates.
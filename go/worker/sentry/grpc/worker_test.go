@@ -0,0 +1,211 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/accessctl"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	grpcPolicy "github.com/oasisprotocol/oasis-core/go/common/grpc/policy"
+	cmnTesting "github.com/oasisprotocol/oasis-core/go/common/grpc/testing"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	sentry "github.com/oasisprotocol/oasis-core/go/sentry/api"
+)
+
+var (
+	authTestServiceName = cmnGrpc.NewServiceName("SentryAuthTest")
+
+	authTestNs = common.NewTestNamespaceFromSeed([]byte("oasis sentry grpc auth test ns"), 0)
+
+	methodAuthTestOpen       = authTestServiceName.NewMethod("Open", authTestRequest{})
+	methodAuthTestControlled = authTestServiceName.NewMethod("Controlled", authTestRequest{}).
+					WithNamespaceExtractor(func(ctx context.Context, req interface{}) (common.Namespace, error) {
+			r, ok := req.(*authTestRequest)
+			if !ok {
+				return common.Namespace{}, fmt.Errorf("invalid request type")
+			}
+			return r.Namespace, nil
+		}).
+		WithAccessControl(cmnGrpc.AccessControlAlways)
+	methodAuthTestAdmin = authTestServiceName.NewMethod("Admin", authTestRequest{}).WithAdminCapability()
+)
+
+type authTestRequest struct {
+	Namespace common.Namespace `json:"namespace"`
+}
+
+type authTestBackend struct {
+	sentry.LocalBackend
+
+	policyCheckers map[cmnGrpc.ServiceName]*grpcPolicy.DynamicRuntimePolicyChecker
+}
+
+func (b *authTestBackend) GetPolicyChecker(ctx context.Context, service cmnGrpc.ServiceName) (*grpcPolicy.DynamicRuntimePolicyChecker, error) {
+	p, ok := b.policyCheckers[service]
+	if !ok {
+		return nil, fmt.Errorf("no policy checker defined for given service")
+	}
+	return p, nil
+}
+
+func TestAuthFunction(t *testing.T) {
+	_, allowedCert := cmnTesting.CreateCertificate(t)
+	_, otherCert := cmnTesting.CreateCertificate(t)
+
+	peerCtx := func(cert *x509.Certificate) context.Context {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{
+			AuthInfo: credentials.TLSInfo{
+				State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+			},
+		})
+		return metadata.NewIncomingContext(ctx, metadata.MD{})
+	}
+
+	policy := accessctl.NewPolicy()
+	policy.Allow(accessctl.SubjectFromX509Certificate(allowedCert), accessctl.Action(methodAuthTestControlled.FullName()))
+	policyChecker := grpcPolicy.NewDynamicRuntimePolicyChecker(authTestServiceName, nil)
+	policyChecker.SetAccessPolicy(policy, authTestNs)
+
+	rawRequest := cbor.RawMessage(cbor.Marshal(&authTestRequest{Namespace: authTestNs}))
+	serviceKey := strings.ToLower(string(authTestServiceName))
+
+	for _, tc := range []struct {
+		name           string
+		policy         ServicePolicy
+		policyChecker  bool
+		method         *cmnGrpc.MethodDesc
+		fullMethodName string
+		cert           *x509.Certificate
+		allowed        bool
+	}{
+		{
+			name:           "UnknownMethod",
+			fullMethodName: "/oasis-core.SentryAuthTest/Unknown",
+			policyChecker:  true,
+		},
+		{
+			name:          "DynamicOpen",
+			method:        methodAuthTestOpen,
+			policyChecker: true,
+			allowed:       true,
+		},
+		{
+			name:   "DynamicOpenNoPolicyChecker",
+			method: methodAuthTestOpen,
+		},
+		{
+			name:          "DynamicControlledAllowed",
+			method:        methodAuthTestControlled,
+			policyChecker: true,
+			cert:          allowedCert,
+			allowed:       true,
+		},
+		{
+			name:          "DynamicControlledForbidden",
+			method:        methodAuthTestControlled,
+			policyChecker: true,
+			cert:          otherCert,
+		},
+		{
+			name:    "PublicOpenNoPolicyChecker",
+			policy:  ServicePolicyPublic,
+			method:  methodAuthTestOpen,
+			allowed: true,
+		},
+		{
+			name:   "PublicControlledNoPolicyChecker",
+			policy: ServicePolicyPublic,
+			method: methodAuthTestControlled,
+			cert:   allowedCert,
+		},
+		{
+			name:          "PublicControlledAllowed",
+			policy:        ServicePolicyPublic,
+			method:        methodAuthTestControlled,
+			policyChecker: true,
+			cert:          allowedCert,
+			allowed:       true,
+		},
+		{
+			name:          "PublicControlledForbidden",
+			policy:        ServicePolicyPublic,
+			method:        methodAuthTestControlled,
+			policyChecker: true,
+			cert:          otherCert,
+		},
+		{
+			name:          "PublicAdmin",
+			policy:        ServicePolicyPublic,
+			method:        methodAuthTestAdmin,
+			policyChecker: true,
+		},
+		{
+			name:          "DynamicAdmin",
+			method:        methodAuthTestAdmin,
+			policyChecker: true,
+		},
+		{
+			name:          "DenyOpen",
+			policy:        ServicePolicyDeny,
+			method:        methodAuthTestOpen,
+			policyChecker: true,
+		},
+		{
+			name:          "DenyControlled",
+			policy:        ServicePolicyDeny,
+			method:        methodAuthTestControlled,
+			policyChecker: true,
+			cert:          allowedCert,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			backend := &authTestBackend{
+				policyCheckers: make(map[cmnGrpc.ServiceName]*grpcPolicy.DynamicRuntimePolicyChecker),
+			}
+			if tc.policyChecker {
+				backend.policyCheckers[authTestServiceName] = policyChecker
+			}
+			g := &Worker{
+				backend:  backend,
+				policies: make(map[string]ServicePolicy),
+				logger:   logging.GetLogger("worker/sentry/grpc/test"),
+			}
+			if tc.policy != "" {
+				g.policies[serviceKey] = tc.policy
+			}
+
+			fullMethodName := tc.fullMethodName
+			if tc.method != nil {
+				fullMethodName = tc.method.FullName()
+			}
+			ctx := context.Background()
+			if tc.cert != nil {
+				ctx = peerCtx(tc.cert)
+			}
+
+			raw := rawRequest
+			err := g.authFunction()(ctx, fullMethodName, &raw)
+			if tc.allowed {
+				require.NoError(err, "request should be allowed")
+				return
+			}
+			require.Error(err, "request should be rejected")
+			require.Equal(codes.PermissionDenied, status.Code(err))
+		})
+	}
+}
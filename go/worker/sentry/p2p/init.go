@@ -0,0 +1,48 @@
+// Package p2p implements a P2P sentry worker.
+package p2p
+
+import (
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/worker/common/configparser"
+)
+
+const (
+	// CfgEnabled enables the sentry P2P worker.
+	CfgEnabled = "worker.sentry.p2p.enabled"
+
+	// CfgUpstreamAddress is the P2P address of the upstream node.
+	CfgUpstreamAddress = "worker.sentry.p2p.upstream.address"
+
+	// CfgClientAddresses are addresses on which the P2P endpoint is reachable.
+	CfgClientAddresses = "worker.sentry.p2p.client.address"
+	// CfgClientPort is the sentry node's P2P port.
+	CfgClientPort = "worker.sentry.p2p.client.port"
+)
+
+// Flags has the configuration flags.
+var Flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+// GetNodeAddresses returns configured sentry node P2P addresses.
+func GetNodeAddresses() ([]node.Address, error) {
+	if !viper.GetBool(CfgEnabled) {
+		return nil, nil
+	}
+
+	clientAddresses, err := configparser.ParseAddressList(viper.GetStringSlice(CfgClientAddresses))
+	if err != nil {
+		return nil, err
+	}
+	return clientAddresses, nil
+}
+
+func init() {
+	Flags.Bool(CfgEnabled, false, "Enable Sentry P2P worker (NOTE: This should only be enabled on P2P Sentry nodes.)")
+	Flags.String(CfgUpstreamAddress, "", "P2P address of the upstream node")
+	Flags.StringSlice(CfgClientAddresses, []string{}, "Address/port(s) to use for P2P connections for accessing the upstream node")
+	Flags.Uint16(CfgClientPort, 9201, "Port to use for incoming P2P connections")
+
+	_ = viper.BindPFlags(Flags)
+}
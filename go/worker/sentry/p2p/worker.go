@@ -0,0 +1,199 @@
+package p2p
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/service"
+	"github.com/oasisprotocol/oasis-core/go/worker/common/configparser"
+)
+
+// upstreamDialTimeout is the timeout for connecting to the upstream node.
+const upstreamDialTimeout = 10 * time.Second
+
+var _ service.BackgroundService = (*Worker)(nil)
+
+// Worker is a P2P sentry node worker relaying P2P connections to the upstream node.
+//
+// Since P2P connections are authenticated end-to-end, the sentry forwards them without inspecting
+// the relayed traffic.
+type Worker struct {
+	sync.Mutex
+
+	enabled bool
+
+	listener     net.Listener
+	upstreamAddr string
+	port         uint16
+
+	conns map[net.Conn]bool
+
+	wg     sync.WaitGroup
+	stopCh chan struct{}
+	quitCh chan struct{}
+
+	logger *logging.Logger
+}
+
+func (w *Worker) trackConn(conn net.Conn) bool {
+	w.Lock()
+	defer w.Unlock()
+
+	select {
+	case <-w.stopCh:
+		return false
+	default:
+	}
+	w.conns[conn] = true
+	return true
+}
+
+func (w *Worker) untrackConn(conn net.Conn) {
+	w.Lock()
+	defer w.Unlock()
+
+	delete(w.conns, conn)
+}
+
+func (w *Worker) handleConn(conn net.Conn) {
+	defer w.wg.Done()
+	defer conn.Close()
+
+	// Track the connection before dialing upstream so that it is closed in case the worker is
+	// stopped while dialing.
+	if !w.trackConn(conn) {
+		return
+	}
+	defer w.untrackConn(conn)
+
+	upstream, err := net.DialTimeout("tcp", w.upstreamAddr, upstreamDialTimeout)
+	if err != nil {
+		w.logger.Error("failed to connect to upstream node",
+			"err", err,
+			"remote_addr", conn.RemoteAddr(),
+		)
+		return
+	}
+	defer upstream.Close()
+
+	if !w.trackConn(upstream) {
+		return
+	}
+	defer w.untrackConn(upstream)
+
+	// Relay traffic in both directions until either side closes the connection.
+	done := make(chan struct{}, 2)
+	relay := func(dst, src net.Conn) {
+		_, _ = io.Copy(dst, src)
+		done <- struct{}{}
+	}
+	go relay(upstream, conn)
+	go relay(conn, upstream)
+	<-done
+}
+
+func (w *Worker) worker() {
+	defer close(w.quitCh)
+
+	for {
+		conn, err := w.listener.Accept()
+		if err != nil {
+			select {
+			case <-w.stopCh:
+			default:
+				w.logger.Error("failed to accept P2P connection",
+					"err", err,
+				)
+			}
+			break
+		}
+
+		w.wg.Add(1)
+		go w.handleConn(conn)
+	}
+
+	w.wg.Wait()
+}
+
+// Start starts the worker.
+func (w *Worker) Start() error {
+	if !w.enabled {
+		w.logger.Info("not starting P2P sentry worker as it is disabled")
+		return nil
+	}
+
+	w.logger.Info("starting P2P sentry worker",
+		"port", w.port,
+		"upstream_address", w.upstreamAddr,
+	)
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", w.port))
+	if err != nil {
+		return fmt.Errorf("worker/sentry/p2p: failed to listen: %w", err)
+	}
+	w.listener = listener
+
+	go w.worker()
+
+	return nil
+}
+
+// Name returns the service name.
+func (w *Worker) Name() string {
+	return "P2P sentry worker"
+}
+
+// Stop halts the worker.
+func (w *Worker) Stop() {
+	w.Lock()
+	defer w.Unlock()
+
+	close(w.stopCh)
+	if !w.enabled || w.listener == nil {
+		close(w.quitCh)
+		return
+	}
+
+	_ = w.listener.Close()
+	for conn := range w.conns {
+		_ = conn.Close()
+	}
+}
+
+// Cleanup performs the service specific post-termination cleanup.
+func (w *Worker) Cleanup() {
+}
+
+// Quit returns a channel that will be closed when the service terminates.
+func (w *Worker) Quit() <-chan struct{} {
+	return w.quitCh
+}
+
+// New creates a new sentry P2P worker.
+func New() (*Worker, error) {
+	w := &Worker{
+		enabled: viper.GetBool(CfgEnabled),
+		port:    uint16(viper.GetInt(CfgClientPort)),
+		conns:   make(map[net.Conn]bool),
+		stopCh:  make(chan struct{}),
+		quitCh:  make(chan struct{}),
+		logger:  logging.GetLogger("sentry/p2p/worker"),
+	}
+
+	if w.enabled {
+		addr := viper.GetString(CfgUpstreamAddress)
+		upstreamAddrs, err := configparser.ParseAddressList([]string{addr})
+		if err != nil {
+			return nil, fmt.Errorf("worker/sentry/p2p: failed to parse upstream address: %s: %w", addr, err)
+		}
+		w.upstreamAddr = upstreamAddrs[0].String()
+	}
+
+	return w, nil
+}
@@ -0,0 +1,84 @@
+package p2p
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerRelay(t *testing.T) {
+	require := require.New(t)
+
+	// Start an upstream echo server.
+	upstream, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err, "Listen")
+	defer upstream.Close()
+	go func() {
+		for {
+			conn, err := upstream.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	viper.Set(CfgEnabled, true)
+	viper.Set(CfgUpstreamAddress, upstream.Addr().String())
+	viper.Set(CfgClientPort, 0)
+	defer func() {
+		viper.Set(CfgEnabled, false)
+		viper.Set(CfgUpstreamAddress, "")
+		viper.Set(CfgClientPort, 9201)
+	}()
+
+	w, err := New()
+	require.NoError(err, "New")
+	err = w.Start()
+	require.NoError(err, "Start")
+
+	conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", w.listener.Addr().(*net.TCPAddr).Port))
+	require.NoError(err, "Dial")
+	defer conn.Close()
+
+	msg := []byte("hello upstream")
+	_, err = conn.Write(msg)
+	require.NoError(err, "Write")
+	rsp := make([]byte, len(msg))
+	_, err = io.ReadFull(conn, rsp)
+	require.NoError(err, "ReadFull")
+	require.Equal(msg, rsp, "traffic should be relayed to the upstream node")
+
+	w.Stop()
+	<-w.Quit()
+	require.Empty(w.conns, "all connections should be untracked")
+
+	// Connections should not be leaked when the upstream node is unreachable.
+	viper.Set(CfgUpstreamAddress, upstream.Addr().String())
+	w, err = New()
+	require.NoError(err, "New")
+	err = upstream.Close()
+	require.NoError(err, "Close")
+	err = w.Start()
+	require.NoError(err, "Start")
+
+	conn, err = net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", w.listener.Addr().(*net.TCPAddr).Port))
+	require.NoError(err, "Dial")
+	defer conn.Close()
+	_, err = conn.Read(rsp)
+	require.Error(err, "connection should be closed when upstream is unreachable")
+
+	w.Lock()
+	require.Empty(w.conns, "all connections should be untracked")
+	w.Unlock()
+
+	w.Stop()
+	<-w.Quit()
+}
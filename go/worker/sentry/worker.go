@@ -13,6 +13,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/sentry/api"
 	workerGrpcSentry "github.com/oasisprotocol/oasis-core/go/worker/sentry/grpc"
+	workerP2PSentry "github.com/oasisprotocol/oasis-core/go/worker/sentry/p2p"
 )
 
 const (
@@ -28,6 +29,10 @@ const (
 // Flags has the configuration flags.
 var Flags = flag.NewFlagSet("", flag.ContinueOnError)
 
+// ReloadableConfig are the configuration keys that can be changed at runtime via
+// Worker.ReloadConfig.
+var ReloadableConfig = append([]string{CfgAuthorizedControlPubkeys}, workerGrpcSentry.ReloadableConfig...)
+
 // Enabled returns true if Sentry worker is enabled.
 func Enabled() bool {
 	return viper.GetBool(CfgEnabled)
@@ -39,10 +44,12 @@ type Worker struct {
 	enabled bool
 
 	grpcWorker *workerGrpcSentry.Worker
+	p2pWorker  *workerP2PSentry.Worker
 
 	backend api.LocalBackend
 
-	grpcServer *grpc.Server
+	grpcServer  *grpc.Server
+	controlAuth *auth.PeerPubkeyAuthenticator
 
 	quitCh chan struct{}
 
@@ -75,12 +82,18 @@ func (w *Worker) Start() error {
 		return err
 	}
 
-	// Stop the gRPC server when the worker quits.
+	// Start the sentry P2P worker.
+	if err := w.p2pWorker.Start(); err != nil {
+		return err
+	}
+
+	// Stop the gRPC server when the workers quit.
 	go func() {
 		defer close(w.quitCh)
 
 		<-w.grpcWorker.Quit()
-		w.logger.Debug("sentry gRPC worker quit, stopping sentry gRPC server")
+		<-w.p2pWorker.Quit()
+		w.logger.Debug("sentry workers quit, stopping sentry gRPC server")
 		w.grpcServer.Stop()
 	}()
 
@@ -95,7 +108,8 @@ func (w *Worker) Stop() {
 	}
 
 	w.grpcWorker.Stop()
-	// The gRPC server will terminate once the worker quits.
+	w.p2pWorker.Stop()
+	// The gRPC server will terminate once the workers quit.
 }
 
 // Enabled returns true if worker is enabled.
@@ -115,9 +129,55 @@ func (w *Worker) Cleanup() {
 	}
 
 	w.grpcWorker.Cleanup()
+	w.p2pWorker.Cleanup()
 	w.grpcServer.Cleanup()
 }
 
+// GetStatus returns the sentry node status or nil in case the gRPC sentry worker is disabled.
+func (w *Worker) GetStatus() *api.Status {
+	if !w.enabled {
+		return nil
+	}
+	return w.grpcWorker.GetStatus()
+}
+
+// ReloadConfig reloads the subset of the sentry configuration that can be changed without
+// restarting the node (the public keys of the upstream nodes authorized to use the control
+// endpoint, the gRPC upstream nodes and the gRPC service policies).
+//
+// Existing client connections are kept.
+func (w *Worker) ReloadConfig(cfg *viper.Viper) error {
+	if !w.enabled {
+		return nil
+	}
+
+	pubKeys, err := parseAuthorizedControlPubkeys(cfg)
+	if err != nil {
+		return err
+	}
+	if err = w.grpcWorker.ReloadConfig(cfg); err != nil {
+		return err
+	}
+	w.controlAuth.SetPeerPublicKeys(pubKeys)
+
+	w.logger.Info("sentry configuration reloaded",
+		"num_authorized_control_pubkeys", len(pubKeys),
+	)
+	return nil
+}
+
+func parseAuthorizedControlPubkeys(cfg *viper.Viper) ([]signature.PublicKey, error) {
+	var pubKeys []signature.PublicKey
+	for _, pubkey := range cfg.GetStringSlice(CfgAuthorizedControlPubkeys) {
+		var pk signature.PublicKey
+		if err := pk.UnmarshalText([]byte(pubkey)); err != nil {
+			return nil, fmt.Errorf("worker/sentry: failed unmarshalling upstream public key: %s: %w", pubkey, err)
+		}
+		pubKeys = append(pubKeys, pk)
+	}
+	return pubKeys, nil
+}
+
 // New creates a new sentry worker.
 func New(backend api.LocalBackend, identity *identity.Identity) (*Worker, error) {
 	w := &Worker{
@@ -128,19 +188,17 @@ func New(backend api.LocalBackend, identity *identity.Identity) (*Worker, error)
 	}
 
 	if w.enabled {
-		peerPubkeyAuth := auth.NewPeerPubkeyAuthenticator()
-		for _, pubkey := range viper.GetStringSlice(CfgAuthorizedControlPubkeys) {
-			var pk signature.PublicKey
-			if err := pk.UnmarshalText([]byte(pubkey)); err != nil {
-				return nil, fmt.Errorf("worker/sentry: failed unmarshalling upstream public key: %s: %w", pubkey, err)
-			}
-			peerPubkeyAuth.AllowPeerPublicKey(pk)
+		pubKeys, err := parseAuthorizedControlPubkeys(viper.GetViper())
+		if err != nil {
+			return nil, err
 		}
+		w.controlAuth = auth.NewPeerPubkeyAuthenticator()
+		w.controlAuth.SetPeerPublicKeys(pubKeys)
 		grpcServer, err := grpc.NewServer(&grpc.ServerConfig{
 			Name:     "sentry",
 			Port:     uint16(viper.GetInt(CfgControlPort)),
 			Identity: identity,
-			AuthFunc: peerPubkeyAuth.AuthFunc,
+			AuthFunc: w.controlAuth.AuthFunc,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("worker/sentry: failed to create a new gRPC server: %w", err)
@@ -157,6 +215,13 @@ func New(backend api.LocalBackend, identity *identity.Identity) (*Worker, error)
 	}
 	w.grpcWorker = sentryGrpcWorker
 
+	// Initialize the sentry P2P worker.
+	sentryP2PWorker, err := workerP2PSentry.New()
+	if err != nil {
+		return nil, fmt.Errorf("worker/sentry: failed to create a new sentry P2P worker: %w", err)
+	}
+	w.p2pWorker = sentryP2PWorker
+
 	return w, nil
 }
 
@@ -165,6 +230,7 @@ func init() {
 	Flags.Uint16(CfgControlPort, 9009, "Sentry worker's gRPC server port (NOTE: This should only be enabled on Sentry nodes.)")
 	Flags.StringSlice(CfgAuthorizedControlPubkeys, []string{}, "Public keys of upstream nodes that are allowed to connect to sentry control endpoint.")
 	Flags.AddFlagSet(workerGrpcSentry.Flags)
+	Flags.AddFlagSet(workerP2PSentry.Flags)
 
 	_ = viper.BindPFlags(Flags)
 }
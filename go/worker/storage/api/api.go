@@ -67,4 +67,7 @@ type PauseCheckpointerRequest struct {
 type Status struct {
 	// LastFinalizedRound is the last synced and finalized round.
 	LastFinalizedRound uint64 `json:"last_finalized_round"`
+	// LatestRound is the latest runtime round seen by the storage worker, which it needs to
+	// sync up to.
+	LatestRound uint64 `json:"latest_round"`
 }
@@ -17,7 +17,7 @@ var (
 	// methodWaitForRound is the WaitForRound method.
 	methodWaitForRound = serviceName.NewMethod("WaitForRound", &WaitForRoundRequest{})
 	// methodPauseCheckpointer is the PauseCheckpointer method.
-	methodPauseCheckpointer = serviceName.NewMethod("PauseCheckpointer", &PauseCheckpointerRequest{})
+	methodPauseCheckpointer = serviceName.NewMethod("PauseCheckpointer", &PauseCheckpointerRequest{}).WithAdminCapability()
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
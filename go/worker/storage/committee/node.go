@@ -185,7 +185,7 @@ type Node struct { // nolint: maligned
 
 	stateStore *persistent.ServiceStore
 
-	workerCommonCfg workerCommon.Config
+	workerCommonCfg *workerCommon.Config
 
 	checkpointer           checkpoint.Checkpointer
 	checkpointSyncDisabled bool
@@ -216,7 +216,7 @@ func NewNode(
 	store *persistent.ServiceStore,
 	roleProvider registration.RoleProvider,
 	rpcRoleProvider registration.RoleProvider,
-	workerCommonCfg workerCommon.Config,
+	workerCommonCfg *workerCommon.Config,
 	localStorage storageApi.LocalBackend,
 	checkpointerCfg *checkpoint.CheckpointerConfig,
 	checkpointSyncDisabled bool,
@@ -395,12 +395,48 @@ func (n *Node) Initialized() <-chan struct{} {
 
 // GetStatus returns the storage committee node status.
 func (n *Node) GetStatus(ctx context.Context) (*api.Status, error) {
+	var status api.Status
+
+	n.commonNode.CrossNode.Lock()
+	if n.commonNode.CurrentBlock != nil {
+		status.LatestRound = n.commonNode.CurrentBlock.Header.Round
+	}
+	n.commonNode.CrossNode.Unlock()
+
 	n.syncedLock.RLock()
 	defer n.syncedLock.RUnlock()
 
-	return &api.Status{
-		LastFinalizedRound: n.syncedState.LastBlock.Round,
-	}, nil
+	status.LastFinalizedRound = n.syncedState.LastBlock.Round
+
+	return &status, nil
+}
+
+// Drain waits for all the rounds seen so far to be synced and finalized.
+func (n *Node) Drain(ctx context.Context) error {
+	status, err := n.GetStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	n.logger.Info("draining",
+		"latest_round", status.LatestRound,
+		"last_finalized_round", status.LastFinalizedRound,
+	)
+
+	ch, err := n.WaitForRound(status.LatestRound, nil)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-ch:
+	case <-n.ctx.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	n.logger.Info("drained")
+	return nil
 }
 
 func (n *Node) getMetricLabels() prometheus.Labels {
@@ -743,7 +779,7 @@ func (n *Node) updateExternalServicePolicy(rtComputeNodes nodes.NodeDescriptorLo
 	policy := accessctl.NewPolicy()
 
 	// Add policy for configured sentry nodes.
-	for _, addr := range n.workerCommonCfg.SentryAddresses {
+	for _, addr := range n.workerCommonCfg.GetSentryAddresses() {
 		sentryNodesPolicy.AddPublicKeyPolicy(&policy, addr.PubKey)
 	}
 
@@ -33,6 +33,18 @@ pub type Stream = ::std::net::TcpStream;
 /// Maximum message size.
 const MAX_MESSAGE_SIZE: usize = 16 * 1024 * 1024; // 16MiB
 
+/// Optional protocol features supported by the runtime. They are advertised to the host during
+/// initialization so that the host never sends requests the runtime is unable to handle.
+///
+/// NOTE: This should be kept in sync with go/runtime/host/protocol/features.go.
+const FEATURES: &[&str] = &[
+    "query",
+    "local_rpc",
+    "abort",
+    "consensus_sync",
+    "km_policy_update",
+];
+
 #[derive(Error, Debug)]
 pub enum ProtocolError {
     #[error("message too large")]
@@ -464,6 +476,7 @@ impl Protocol {
         Ok(RuntimeInfoResponse {
             protocol_version: BUILD_INFO.protocol_version,
             runtime_version: self.config.version,
+            features: FEATURES.iter().map(|f| f.to_string()).collect(),
         })
     }
 
@@ -277,6 +277,12 @@ pub struct RuntimeInfoRequest {
 pub struct RuntimeInfoResponse {
     pub protocol_version: Version,
     pub runtime_version: Version,
+
+    /// Optional Runtime Host Protocol features supported by the runtime.
+    #[cbor(optional)]
+    #[cbor(default)]
+    #[cbor(skip_serializing_if = "Vec::is_empty")]
+    pub features: Vec<String>,
 }
 
 /// Result of a CheckTx operation.
@@ -296,6 +302,19 @@ pub struct CheckTxMetadata {
 
     #[cbor(optional)]
     pub weights: Option<BTreeMap<TransactionWeight, u64>>,
+
+    /// Opaque transaction sender identifier. Transactions of the same sender with the same
+    /// sequence number replace each other in the transaction pool.
+    #[cbor(optional)]
+    #[cbor(default)]
+    #[cbor(skip_serializing_if = "Vec::is_empty")]
+    pub sender: Vec<u8>,
+
+    /// Per-sender sequence number (nonce) of the transaction.
+    #[cbor(optional)]
+    #[cbor(default)]
+    #[cbor(skip_serializing_if = "num_traits::Zero::is_zero")]
+    pub sender_seq: u64,
 }
 
 /// Transaction weight kind.